@@ -104,3 +104,28 @@ func TestConstants(t *testing.T) {
 		t.Errorf("DepRelated = %q, want %q", beads.DepRelated, "related")
 	}
 }
+
+func TestNewFixtureStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.jsonl")
+	data := `{"id":"fx-1","title":"Epic","status":"open","priority":1,"issue_type":"epic","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}
+{"id":"fx-2","title":"Task","status":"open","priority":2,"issue_type":"task","created_at":"2025-01-01T00:01:00Z","updated_at":"2025-01-01T00:01:00Z","labels":["ui"],"dependencies":[{"issue_id":"fx-2","depends_on_id":"fx-1","type":"parent-child","created_at":"2025-01-01T00:01:00Z"}]}
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store := beads.NewFixtureStorage(t, path)
+	ctx := context.Background()
+	issue, err := store.GetIssue(ctx, "fx-2")
+	if err != nil || issue == nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	labels, _ := store.GetLabels(ctx, "fx-2")
+	if len(labels) != 1 || labels[0] != "ui" {
+		t.Errorf("labels = %v", labels)
+	}
+	deps, _ := store.GetDependencyRecords(ctx, "fx-2")
+	if len(deps) != 1 || deps[0].DependsOnID != "fx-1" {
+		t.Errorf("deps = %+v", deps)
+	}
+}
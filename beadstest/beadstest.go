@@ -0,0 +1,207 @@
+// Package beadstest provides a test harness for bd commands.
+//
+// A Project is a throwaway workspace: a temporary directory (in memory where
+// the OS allows) with a git repository and an initialized .beads database.
+// Commands run through a Runner, which executes bd in-process instead of
+// building the binary and spawning it for every case:
+//
+//	p := beadstest.New(t, run)
+//	var issue map[string]interface{}
+//	p.RunJSON(&issue, "create", "Fix login", "-p", "1")
+//	out := p.Run("show", issue["id"].(string))
+//
+// bd's own tests supply a Runner that drives its cobra command tree. Code
+// outside bd can use ExecRunner to run an installed binary.
+package beadstest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads"
+	"github.com/steveyegge/beads/internal/testutil"
+)
+
+// Runner executes one bd invocation with the given arguments in dir,
+// writing the command's output to stdout and stderr. A command that exits
+// with a non-zero status should be reported as an *ExitError.
+type Runner func(dir string, args []string, stdout, stderr io.Writer) error
+
+// ExitError reports that a command exited with a non-zero status.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExecRunner returns a Runner that spawns the bd binary at path.
+func ExecRunner(path string) Runner {
+	return func(dir string, args []string, stdout, stderr io.Writer) error {
+		cmd := exec.Command(path, args...) // #nosec G204 -- test harness runs the given binary
+		cmd.Dir = dir
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		cmd.Env = append(os.Environ(), "BEADS_NO_DAEMON=1")
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &ExitError{Code: exitErr.ExitCode()}
+		}
+		return err
+	}
+}
+
+// Result is the outcome of one command.
+type Result struct {
+	Stdout string
+	Stderr string
+	Err    error
+}
+
+// ExitCode returns the command's exit status: 0 on success, the code from an
+// *ExitError, or 1 for any other error.
+func (r Result) ExitCode() int {
+	if r.Err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(r.Err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
+// Project is an initialized bd workspace for a single test.
+type Project struct {
+	Dir      string // Workspace root (the git repository)
+	BeadsDir string // Dir/.beads
+	DBPath   string // The SQLite database
+	Prefix   string // Issue ID prefix
+
+	tb    testing.TB
+	run   Runner
+	store beads.Storage
+}
+
+type options struct {
+	prefix string
+	config map[string]string
+}
+
+// Option customizes New.
+type Option func(*options)
+
+// WithPrefix sets the issue prefix passed to bd init (default "test").
+func WithPrefix(prefix string) Option {
+	return func(o *options) { o.prefix = prefix }
+}
+
+// WithConfig sets a config value with 'bd config set' after init.
+func WithConfig(key, value string) Option {
+	return func(o *options) {
+		if o.config == nil {
+			o.config = make(map[string]string)
+		}
+		o.config[key] = value
+	}
+}
+
+// New creates a Project and runs 'bd init' in it. Everything is removed
+// when the test ends.
+func New(tb testing.TB, run Runner, opts ...Option) *Project {
+	tb.Helper()
+	o := options{prefix: "test"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	dir := testutil.TempDirInMemory(tb)
+	p := &Project{
+		Dir:      dir,
+		BeadsDir: filepath.Join(dir, ".beads"),
+		DBPath:   filepath.Join(dir, ".beads", "beads.db"),
+		Prefix:   o.prefix,
+		tb:       tb,
+		run:      run,
+	}
+	tb.Cleanup(func() {
+		if p.store != nil {
+			_ = p.store.Close()
+		}
+	})
+
+	p.git("init", "-q")
+	p.git("config", "user.email", "test@example.com")
+	p.git("config", "user.name", "beadstest")
+	// Without a remote bd assumes a contributor and routes new issues to
+	// ~/.beads-planning; keep everything in the project.
+	p.git("config", "beads.role", "maintainer")
+	p.Run("init", "--prefix", o.prefix, "--quiet")
+	for key, value := range o.config {
+		p.Run("config", "set", key, value)
+	}
+	return p
+}
+
+func (p *Project) git(args ...string) {
+	p.tb.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = p.Dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		p.tb.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// RunCommand runs bd with args in the project and returns its output.
+// Failures are returned, not reported to the test.
+func (p *Project) RunCommand(args ...string) Result {
+	p.tb.Helper()
+	var stdout, stderr bytes.Buffer
+	err := p.run(p.Dir, args, &stdout, &stderr)
+	return Result{Stdout: stdout.String(), Stderr: stderr.String(), Err: err}
+}
+
+// Run runs bd with args and returns stdout, failing the test if the command
+// fails.
+func (p *Project) Run(args ...string) string {
+	p.tb.Helper()
+	res := p.RunCommand(args...)
+	if res.Err != nil {
+		p.tb.Fatalf("bd %s: %v\nstdout:\n%s\nstderr:\n%s", strings.Join(args, " "), res.Err, res.Stdout, res.Stderr)
+	}
+	return res.Stdout
+}
+
+// RunJSON runs bd with args plus --json and decodes stdout into v.
+func (p *Project) RunJSON(v interface{}, args ...string) {
+	p.tb.Helper()
+	out := p.Run(append(args, "--json")...)
+	if err := json.Unmarshal([]byte(out), v); err != nil {
+		p.tb.Fatalf("bd %s: decoding JSON: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// Store opens the project database for direct assertions. The store is
+// shared across calls and closed when the test ends.
+func (p *Project) Store() beads.Storage {
+	p.tb.Helper()
+	if p.store == nil {
+		s, err := beads.NewSQLiteStorage(context.Background(), p.DBPath)
+		if err != nil {
+			p.tb.Fatalf("opening %s: %v", p.DBPath, err)
+		}
+		p.store = s
+	}
+	return p.store
+}
@@ -0,0 +1,68 @@
+package beadstest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestProjectRunCommand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	var calls []string
+	fake := func(dir string, args []string, stdout, stderr io.Writer) error {
+		calls = append(calls, strings.Join(args, " "))
+		switch args[0] {
+		case "fail":
+			fmt.Fprintln(stderr, "Error: boom")
+			return &ExitError{Code: 3}
+		case "json":
+			fmt.Fprintln(stdout, `{"id":"x-1"}`)
+		default:
+			fmt.Fprintln(stdout, "ok")
+		}
+		return nil
+	}
+
+	p := New(t, fake, WithPrefix("x"), WithConfig("sync.branch", "beads-sync"))
+	if p.Prefix != "x" || len(calls) != 2 || calls[0] != "init --prefix x --quiet" || calls[1] != "config set sync.branch beads-sync" {
+		t.Fatalf("unexpected setup: prefix=%q calls=%q", p.Prefix, calls)
+	}
+
+	if out := p.Run("list"); out != "ok\n" {
+		t.Errorf("Run = %q", out)
+	}
+
+	res := p.RunCommand("fail")
+	if res.ExitCode() != 3 || !strings.Contains(res.Stderr, "boom") {
+		t.Errorf("RunCommand = %+v (exit %d)", res, res.ExitCode())
+	}
+
+	var got map[string]string
+	p.RunJSON(&got, "json")
+	if got["id"] != "x-1" || calls[len(calls)-1] != "json --json" {
+		t.Errorf("RunJSON = %v, last call %q", got, calls[len(calls)-1])
+	}
+}
+
+func TestResultExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{&ExitError{Code: 2}, 2},
+		{fmt.Errorf("wrapped: %w", &ExitError{Code: 4}), 4},
+		{errors.New("other"), 1},
+	}
+	for _, tt := range tests {
+		if got := (Result{Err: tt.err}).ExitCode(); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// accessibleMode is set by --accessible (or the accessible setting). The
+// output helpers check ui.IsAccessible, which follows it.
+var accessibleMode bool
+
+// accessibleIssueLine describes an issue in one screen-reader-friendly
+// line: the ID and title, then each attribute as a short sentence, e.g.
+//
+//	bd-12: Fix login. Status open. Priority P1. Type bug. Assignee alice. Labels auth, ui.
+func accessibleIssueLine(issue *types.Issue, labels []string) string {
+	parts := []string{
+		fmt.Sprintf("%s: %s.", issue.ID, strings.TrimRight(issue.Title, ".")),
+		fmt.Sprintf("Status %s.", strings.ReplaceAll(string(issue.Status), "_", " ")),
+		fmt.Sprintf("Priority P%d.", issue.Priority),
+		fmt.Sprintf("Type %s.", issue.IssueType),
+	}
+	if issue.Assignee != "" {
+		parts = append(parts, fmt.Sprintf("Assignee %s.", issue.Assignee))
+	}
+	if len(labels) > 0 {
+		parts = append(parts, fmt.Sprintf("Labels %s.", strings.Join(labels, ", ")))
+	}
+	if issue.Pinned {
+		parts = append(parts, "Pinned.")
+	}
+	return strings.Join(parts, " ")
+}
+
+// accessibleRelationLine describes a related issue in one line, prefixed
+// by the relation ("Depends on", "Child", ...).
+func accessibleRelationLine(relation, id, title string, status types.Status, priority int) string {
+	return fmt.Sprintf("%s %s: %s. Status %s. Priority P%d.",
+		relation, id, strings.TrimRight(title, "."), strings.ReplaceAll(string(status), "_", " "), priority)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+func TestAccessibleIssueLine(t *testing.T) {
+	issue := &types.Issue{ID: "bd-12", Title: "Fix login.", Status: types.StatusInProgress, Priority: 1, IssueType: types.TypeBug, Assignee: "alice", Pinned: true}
+	got := accessibleIssueLine(issue, []string{"auth", "ui"})
+	want := "bd-12: Fix login. Status in progress. Priority P1. Type bug. Assignee alice. Labels auth, ui. Pinned."
+	if got != want {
+		t.Errorf("accessibleIssueLine =\n  %q\nwant\n  %q", got, want)
+	}
+}
+
+func TestFormatIssueCompactAccessible(t *testing.T) {
+	ui.SetAccessible(true)
+	defer ui.SetAccessible(false)
+
+	var buf strings.Builder
+	formatIssueCompact(&buf, &types.Issue{ID: "bd-1", Title: "T", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}, nil)
+	if got := buf.String(); got != "bd-1: T. Status open. Priority P2. Type task.\n" {
+		t.Errorf("compact line = %q", got)
+	}
+	if strings.ContainsAny(formatDependencyLine("←", &types.IssueWithDependencyMetadata{Issue: types.Issue{ID: "bd-2", Title: "U", Status: types.StatusClosed}}), "←○●✓") {
+		t.Error("dependency line still has symbols")
+	}
+}
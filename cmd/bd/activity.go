@@ -418,17 +418,19 @@ func parseDurationString(s string) (time.Duration, error) {
 		return d, nil
 	}
 
-	// Handle custom formats like "2d" for days
-	re := regexp.MustCompile(`^(\d+)([dhms])$`)
+	// Handle custom formats like "2d" for days and "1w" for weeks
+	re := regexp.MustCompile(`^(\d+)([wdhms])$`)
 	matches := re.FindStringSubmatch(strings.ToLower(s))
 	if len(matches) != 3 {
-		return 0, fmt.Errorf("invalid duration format: %s (use 5m, 1h, 30s, or 2d)", s)
+		return 0, fmt.Errorf("invalid duration format: %s (use 5m, 1h, 30s, 2d, or 1w)", s)
 	}
 
 	value, _ := strconv.Atoi(matches[1])
 	unit := matches[2]
 
 	switch unit {
+	case "w":
+		return time.Duration(value) * 7 * 24 * time.Hour, nil
 	case "d":
 		return time.Duration(value) * 24 * time.Hour, nil
 	case "h":
@@ -25,6 +25,7 @@ func TestParseDurationString(t *testing.T) {
 		{"2 days", "2d", 2 * 24 * time.Hour, false},
 		{"1 day", "1d", 24 * time.Hour, false},
 		{"7 days", "7d", 7 * 24 * time.Hour, false},
+		{"1 week", "1w", 7 * 24 * time.Hour, false},
 
 		// Case insensitivity for custom formats
 		{"uppercase D", "3D", 3 * 24 * time.Hour, false},
@@ -200,3 +200,28 @@ func TestGetActorWithGit_PriorityOrder(t *testing.T) {
 		t.Errorf("Expected BEADS_ACTOR to be used, got %q", result)
 	}
 }
+
+// TestGetActorWithoutGit verifies the git-free lookup used by read-only commands
+// stops at the env vars instead of falling back to git or $USER.
+func TestGetActorWithoutGit(t *testing.T) {
+	origActor := actor
+	defer func() { actor = origActor }()
+	t.Setenv("BD_ACTOR", "")
+	t.Setenv("BEADS_ACTOR", "")
+	t.Setenv("USER", "system-user")
+
+	actor = ""
+	if got := getActorWithoutGit(); got != "" {
+		t.Errorf("getActorWithoutGit() = %q, want empty", got)
+	}
+
+	t.Setenv("BEADS_ACTOR", "beads-actor")
+	if got := getActorWithoutGit(); got != "beads-actor" {
+		t.Errorf("getActorWithoutGit() = %q, want beads-actor", got)
+	}
+
+	actor = "flag-actor"
+	if got := getActorWithoutGit(); got != "flag-actor" {
+		t.Errorf("getActorWithoutGit() = %q, want flag-actor", got)
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/steveyegge/beads/internal/attachments"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+var attachmentCmd = &cobra.Command{
+	Use:     "attachment",
+	GroupID: "issues",
+	Short:   "Attach files to issues, within size limits",
+	Long: `Attach files to issues. Attachments live in .beads/attachments/<issue-id>/
+and are committed with the rest of the issue data.
+
+To keep the repository small, attachments.max-size caps a single attachment
+and attachments.quota caps them all together. With attachments.store set to
+an s3://, gs:// or file:// URL, attachments over the limits are offloaded
+there and only a small <name>.offloaded.json pointer stays in the
+repository; without it, they are refused.
+
+Examples:
+  bd attachment add bd-123 trace.log screenshot.png
+  bd attachment list bd-123
+  bd attachment list                  # All attachments, with quota usage
+  bd attachment get bd-123 trace.log -o /tmp/trace.log
+  bd attachment offload               # Move attachments over the limits to the store`,
+}
+
+var attachmentAddCmd = &cobra.Command{
+	Use:   "add <issue-id> <file>...",
+	Short: "Attach files to an issue",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("attachment add")
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+		issueID := resolveAttachmentIssue(args[0])
+		opts, err := attachmentOptions()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		beadsDir := attachmentsBeadsDir()
+
+		var saved []*attachments.Attachment
+		for _, path := range args[1:] {
+			// #nosec G304 - user-specified file to attach
+			f, err := os.Open(path)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			a, err := attachments.Save(ctx, beadsDir, issueID, filepath.Base(path), f, opts)
+			_ = f.Close()
+			if err != nil {
+				FatalErrorRespectJSON("attaching %s: %v", path, attachmentErrorHint(err))
+			}
+			saved = append(saved, a)
+		}
+
+		text := "Attached:\n- " + strings.Join(describeAttachments(saved), "\n- ")
+		if _, err := store.AddIssueComment(ctx, issueID, actor, text); err != nil {
+			WarnError("failed to record attachments: %v", err)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(saved)
+			return
+		}
+		for _, a := range saved {
+			fmt.Printf("%s Attached %s to %s (%s)\n", ui.RenderPass("✓"), a.Name, ui.RenderID(issueID), humanize.Bytes(uint64(a.Size)))
+			if a.Offloaded() {
+				fmt.Printf("  %s\n", ui.RenderMuted("offloaded to "+a.URL))
+			}
+		}
+	},
+}
+
+var attachmentListCmd = &cobra.Command{
+	Use:   "list [issue-id]",
+	Short: "List an issue's attachments, or all attachments",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		beadsDir := attachmentsBeadsDir()
+		var list []*attachments.Attachment
+		var err error
+		if len(args) == 1 {
+			if err := ensureStoreActive(); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			list, err = attachments.List(beadsDir, resolveAttachmentIssue(args[0]))
+		} else {
+			list, err = attachments.ListAll(beadsDir)
+		}
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		used, err := attachments.Usage(beadsDir)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		quota := config.GetSize("attachments.quota")
+
+		if jsonOutput {
+			if list == nil {
+				list = []*attachments.Attachment{}
+			}
+			outputJSON(struct {
+				Attachments []*attachments.Attachment `json:"attachments"`
+				Used        int64                     `json:"used"`
+				Quota       int64                     `json:"quota,omitempty"`
+			}{list, used, quota})
+			return
+		}
+		if len(list) == 0 {
+			fmt.Println("No attachments")
+		}
+		for _, a := range list {
+			where := ""
+			if a.Offloaded() {
+				where = ui.RenderMuted("  → " + a.URL)
+			}
+			fmt.Printf("%s  %-30s %10s%s\n", ui.RenderID(a.IssueID), a.Name, humanize.Bytes(uint64(a.Size)), where)
+		}
+		if len(args) == 0 {
+			usage := fmt.Sprintf("\n%s kept in the repository", humanize.Bytes(uint64(used)))
+			if quota > 0 {
+				usage += fmt.Sprintf(" (quota %s)", humanize.Bytes(uint64(quota)))
+			}
+			fmt.Println(usage)
+		}
+	},
+}
+
+var attachmentGetCmd = &cobra.Command{
+	Use:   "get <issue-id> <name>",
+	Short: "Write an attachment's content, fetching it from the store if offloaded",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		issueID := resolveAttachmentIssue(args[0])
+		opts, err := attachmentOptions()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		rc, _, err := attachments.Open(rootCtx, attachmentsBeadsDir(), issueID, args[1], opts.Store)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		defer func() { _ = rc.Close() }()
+
+		var w io.Writer = os.Stdout
+		if output != "" && output != "-" {
+			// #nosec G304 - user-specified output file
+			f, err := os.Create(output)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			defer func() { _ = f.Close() }()
+			w = f
+		}
+		if _, err := io.Copy(w, rc); err != nil {
+			if output != "" && output != "-" {
+				_ = os.Remove(output)
+			}
+			FatalErrorRespectJSON("reading %s: %v", args[1], err)
+		}
+	},
+}
+
+var attachmentOffloadCmd = &cobra.Command{
+	Use:   "offload [issue-id...]",
+	Short: "Move attachments over the size limits to the attachment store",
+	Long: `Move attachments kept in the repository that are over attachments.max-size
+to attachments.store, then the largest remaining ones until the repository
+is within attachments.quota. Use this after lowering the limits or setting
+up a store. Limited to the given issues if any.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("attachment offload")
+		var ids []string
+		if len(args) > 0 {
+			if err := ensureStoreActive(); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			for _, arg := range args {
+				ids = append(ids, resolveAttachmentIssue(arg))
+			}
+		}
+		opts, err := attachmentOptions()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if opts.Store == nil {
+			FatalErrorCode(ErrCodeUsage, "no attachment store configured (set attachments.store in .beads/config.yaml)")
+		}
+		moved, err := attachments.Offload(rootCtx, attachmentsBeadsDir(), ids, opts)
+		if err != nil {
+			FatalErrorRespectJSON("%v (%d offloaded before the error)", err, len(moved))
+		}
+
+		if jsonOutput {
+			if moved == nil {
+				moved = []*attachments.Attachment{}
+			}
+			outputJSON(moved)
+			return
+		}
+		if len(moved) == 0 {
+			fmt.Printf("%s All attachments are within the limits\n", ui.RenderPass("✓"))
+			return
+		}
+		for _, a := range moved {
+			fmt.Printf("%s Offloaded %s/%s (%s) to %s\n", ui.RenderPass("✓"), a.IssueID, a.Name, humanize.Bytes(uint64(a.Size)), a.URL)
+		}
+	},
+}
+
+// attachmentOptions returns the configured attachment limits and store.
+func attachmentOptions() (attachments.Options, error) {
+	opts := attachments.Options{
+		MaxSize: config.GetSize("attachments.max-size"),
+		Quota:   config.GetSize("attachments.quota"),
+	}
+	if storeURL := config.GetString("attachments.store"); storeURL != "" {
+		blobs, err := attachments.OpenStore(storeURL, attachments.StoreOptions{
+			S3Region:   config.GetString("attachments.s3-region"),
+			S3Endpoint: config.GetString("attachments.s3-endpoint"),
+		})
+		if err != nil {
+			return opts, err
+		}
+		opts.Store = blobs
+	}
+	return opts, nil
+}
+
+// attachmentErrorHint points a refused attachment at the settings that
+// would let it in.
+func attachmentErrorHint(err error) error {
+	if errors.Is(err, attachments.ErrTooLarge) {
+		return fmt.Errorf("%w\nSet attachments.store to offload large attachments, or raise attachments.max-size/attachments.quota", err)
+	}
+	return err
+}
+
+// describeAttachments lists attachments by path, noting where offloaded
+// ones went.
+func describeAttachments(list []*attachments.Attachment) []string {
+	var lines []string
+	for _, a := range list {
+		if a.Offloaded() {
+			lines = append(lines, fmt.Sprintf("%s (offloaded to %s)", a.Path, a.URL))
+		} else {
+			lines = append(lines, a.Path)
+		}
+	}
+	return lines
+}
+
+func attachmentsBeadsDir() string {
+	beadsDir := beads.FindBeadsDir()
+	if beadsDir == "" {
+		FatalErrorRespectJSON("no .beads directory found")
+	}
+	return beadsDir
+}
+
+// resolveAttachmentIssue resolves a partial ID to an existing issue.
+func resolveAttachmentIssue(id string) string {
+	fullID, err := utils.ResolvePartialID(rootCtx, store, id)
+	if err != nil {
+		FatalErrorRespectJSON("resolving %s: %v", id, err)
+	}
+	return fullID
+}
+
+func init() {
+	attachmentGetCmd.Flags().StringP("output", "o", "", "Write to this file instead of stdout")
+	for _, c := range []*cobra.Command{attachmentAddCmd, attachmentListCmd, attachmentGetCmd, attachmentOffloadCmd} {
+		c.ValidArgsFunction = issueIDCompletion
+		attachmentCmd.AddCommand(c)
+	}
+	rootCmd.AddCommand(attachmentCmd)
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/steveyegge/beads/internal/credentials"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// authProvider describes one connector secret.
+type authProvider struct {
+	Name   string // Display name
+	Key    string // Credential key, also the legacy project config key
+	Env    string // Environment variable read before the credentials store
+	Prompt string // What to paste
+}
+
+var authProviders = map[string]authProvider{
+	"ado":    {Name: "Azure DevOps", Key: "ado.pat", Env: "AZURE_DEVOPS_EXT_PAT", Prompt: "personal access token (Work Items: Read)"},
+	"github": {Name: "GitHub", Key: "github.token", Env: "GITHUB_TOKEN", Prompt: "token"},
+	"gitlab": {Name: "GitLab", Key: "gitlab.token", Env: "GITLAB_TOKEN", Prompt: "access token (api scope)"},
+	"imap":   {Name: "IMAP", Key: "email.imap.password", Env: "BD_IMAP_PASSWORD", Prompt: "password (or app password)"},
+	"jira":   {Name: "Jira", Key: "jira.api_token", Env: "JIRA_API_TOKEN", Prompt: "API token"},
+	"linear": {Name: "Linear", Key: "linear.api_key", Env: "LINEAR_API_KEY", Prompt: "API key"},
+	"notion": {Name: "Notion", Key: "notion.token", Env: "NOTION_TOKEN", Prompt: "integration token"},
+}
+
+func authProviderNames() []string {
+	names := make([]string, 0, len(authProviders))
+	for name := range authProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isSecretConfigKey reports whether key holds a connector secret that
+// belongs in the credentials store rather than the project config.
+func isSecretConfigKey(key string) (provider string, ok bool) {
+	for name, p := range authProviders {
+		if p.Key == key {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// lookupCredential returns a connector secret from the credentials store,
+// with a description of where it came from. Callers check the project
+// config and environment first, so existing setups keep working.
+func lookupCredential(key string) (value, source string) {
+	secret, from, err := credentials.Get(key)
+	if err != nil {
+		if !errors.Is(err, credentials.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "Warning: reading %s from the credentials store: %v\n", key, err)
+		}
+		return "", ""
+	}
+	return secret, "credentials store (" + from + ")"
+}
+
+// lookupSecret resolves a connector secret: project config (legacy), then
+// its environment variable, then the credentials store.
+func lookupSecret(ctx context.Context, key string) (value, source string) {
+	if store != nil {
+		if value, _ = store.GetConfig(ctx, key); value != "" {
+			return value, "project config (bd config)"
+		}
+	}
+	if provider, ok := isSecretConfigKey(key); ok {
+		env := authProviders[provider].Env
+		if value = os.Getenv(env); value != "" {
+			return value, fmt.Sprintf("environment variable (%s)", env)
+		}
+	}
+	return lookupCredential(key)
+}
+
+var authCmd = &cobra.Command{
+	Use:     "auth",
+	GroupID: "setup",
+	Short:   "Manage connector credentials",
+	Long: `Store API tokens for connectors outside the project config.
+
+Tokens saved with 'bd auth login' go to the OS keychain (macOS Keychain,
+the Secret Service on Linux, Windows Credential Manager), or to an
+encrypted file in the user config directory where no keychain is
+available. They are never written to the database, config.yaml or any
+exported file, so they cannot be committed by accident.
+
+Connectors look for a token in the project config (legacy), then the
+environment variable, then the credentials store.
+
+Providers: ado, github, gitlab, jira, linear, notion
+
+Examples:
+  bd auth login gitlab                   # Prompt for the token
+  echo "$TOKEN" | bd auth login jira --with-token
+  bd auth status
+  bd auth logout linear`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:       "login <provider>",
+	Short:     "Save a connector token in the credentials store",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: authProviderNames(),
+	Run: func(cmd *cobra.Command, args []string) {
+		provider, p := mustAuthProvider(args[0])
+		withToken, _ := cmd.Flags().GetBool("with-token")
+
+		token, err := readToken(p, withToken)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		backend, err := credentials.Set(p.Key, token)
+		if err != nil {
+			FatalErrorRespectJSON("saving %s token: %v", p.Name, err)
+		}
+
+		// Move a token out of the project config, where it would be shared
+		movedFromConfig := false
+		if ensureStoreActive() == nil && store != nil {
+			if old, _ := store.GetConfig(rootCtx, p.Key); old != "" {
+				if err := store.DeleteConfig(rootCtx, p.Key); err != nil {
+					WarnError("could not remove %s from the project config: %v", p.Key, err)
+				} else {
+					movedFromConfig = true
+				}
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"provider":            provider,
+				"backend":             backend,
+				"removed_from_config": movedFromConfig,
+			})
+			return
+		}
+		fmt.Printf("%s Saved %s %s in the %s\n", ui.RenderPass("✓"), p.Name, p.Prompt, backendDescription(backend))
+		if movedFromConfig {
+			fmt.Printf("  Removed %s from the project config\n", p.Key)
+		}
+		if os.Getenv(p.Env) != "" {
+			fmt.Printf("  Note: %s is set and takes precedence over the saved token\n", p.Env)
+		}
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:       "logout <provider>",
+	Short:     "Remove a connector token from the credentials store",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: authProviderNames(),
+	Run: func(cmd *cobra.Command, args []string) {
+		provider, p := mustAuthProvider(args[0])
+		err := credentials.Delete(p.Key)
+		if err != nil && !errors.Is(err, credentials.ErrNotFound) {
+			FatalErrorRespectJSON("removing %s token: %v", p.Name, err)
+		}
+		removed := err == nil
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"provider": provider, "removed": removed})
+			return
+		}
+		if removed {
+			fmt.Printf("%s Removed %s token\n", ui.RenderPass("✓"), p.Name)
+		} else {
+			fmt.Printf("No %s token was saved\n", p.Name)
+		}
+	},
+}
+
+// authStatus is one provider's row in bd auth status.
+type authStatus struct {
+	Provider string `json:"provider"`
+	Source   string `json:"source,omitempty"`
+	Token    string `json:"token,omitempty"` // Masked
+	InConfig bool   `json:"in_config"`
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show where each connector token comes from",
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = ensureStoreActive() // Without a database, the project config is skipped
+		var rows []authStatus
+		for _, provider := range authProviderNames() {
+			p := authProviders[provider]
+			value, source := lookupSecret(rootCtx, p.Key)
+			row := authStatus{Provider: provider, Source: source}
+			if value != "" {
+				row.Token = maskAPIKey(value)
+				row.InConfig = strings.HasPrefix(source, "project config")
+			}
+			rows = append(rows, row)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"backend":   credentials.Available(),
+				"providers": rows,
+			})
+			return
+		}
+		fmt.Printf("Credentials store: %s\n\n", backendDescription(credentials.Available()))
+		for _, row := range rows {
+			switch {
+			case row.Source == "":
+				fmt.Printf("  %-7s %s not logged in\n", row.Provider, ui.RenderMuted("-"))
+			case row.InConfig:
+				fmt.Printf("  %-7s %s %s in %s (run 'bd auth login %s' to move it out)\n",
+					row.Provider, ui.RenderWarn("!"), row.Token, row.Source, row.Provider)
+			default:
+				fmt.Printf("  %-7s %s %s from %s\n", row.Provider, ui.RenderPass("✓"), row.Token, row.Source)
+			}
+		}
+	},
+}
+
+func mustAuthProvider(name string) (string, authProvider) {
+	name = strings.ToLower(name)
+	p, ok := authProviders[name]
+	if !ok {
+		FatalErrorWithHint(fmt.Sprintf("unknown provider %q", name),
+			"choose one of: "+strings.Join(authProviderNames(), ", "))
+	}
+	return name, p
+}
+
+// readToken prompts for the token without echoing it, or reads the first
+// line of stdin with --with-token (or when stdin is not a terminal).
+func readToken(p authProvider, fromStdin bool) (string, error) {
+	var token string
+	if !fromStdin && term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintf(os.Stderr, "Paste your %s %s: ", p.Name, p.Prompt)
+		raw, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		token = string(raw)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("reading token from stdin: %w", err)
+		}
+		token = line
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", errors.New("no token given")
+	}
+	return token, nil
+}
+
+func backendDescription(backend string) string {
+	if backend == credentials.BackendFile {
+		dir, _ := credentials.Dir()
+		return "encrypted file in " + dir
+	}
+	return "OS keychain"
+}
+
+func init() {
+	authLoginCmd.Flags().Bool("with-token", false, "Read the token from stdin instead of prompting")
+	authCmd.AddCommand(authLoginCmd, authLogoutCmd, authStatusCmd)
+	rootCmd.AddCommand(authCmd)
+}
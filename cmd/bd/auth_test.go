@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/credentials"
+)
+
+func TestLookupSecretPrecedence(t *testing.T) {
+	testStore, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(credentials.BackendEnv, credentials.BackendFile)
+	t.Setenv("GITLAB_TOKEN", "")
+
+	origStore := store
+	store = testStore
+	t.Cleanup(func() { store = origStore })
+
+	if value, source := lookupSecret(ctx, "gitlab.token"); value != "" || source != "" {
+		t.Fatalf("nothing configured: got %q from %q", value, source)
+	}
+
+	if _, err := credentials.Set("gitlab.token", "from-store"); err != nil {
+		t.Fatal(err)
+	}
+	if value, source := lookupSecret(ctx, "gitlab.token"); value != "from-store" || !strings.HasPrefix(source, "credentials store") {
+		t.Errorf("got %q from %q, want the credentials store", value, source)
+	}
+	if value, _ := getGitLabConfig(ctx, "gitlab.token"); value != "from-store" {
+		t.Errorf("getGitLabConfig = %q, want the stored token", value)
+	}
+
+	t.Setenv("GITLAB_TOKEN", "from-env")
+	if value, _ := lookupSecret(ctx, "gitlab.token"); value != "from-env" {
+		t.Errorf("got %q, want the environment to override the store", value)
+	}
+
+	if err := testStore.SetConfig(ctx, "gitlab.token", "from-config"); err != nil {
+		t.Fatal(err)
+	}
+	if value, source := lookupSecret(ctx, "gitlab.token"); value != "from-config" || !strings.HasPrefix(source, "project config") {
+		t.Errorf("got %q from %q, want the legacy project config first", value, source)
+	}
+}
+
+func TestIsSecretConfigKey(t *testing.T) {
+	for key, want := range map[string]string{"jira.api_token": "jira", "linear.api_key": "linear", "gitlab.url": ""} {
+		if got, _ := isSecretConfigKey(key); got != want {
+			t.Errorf("isSecretConfigKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -9,20 +9,30 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/steveyegge/beads/internal/advisory"
+	"github.com/steveyegge/beads/internal/atomicfile"
 	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/cc"
 	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/debug"
+	"github.com/steveyegge/beads/internal/goals"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/repro"
 	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/subset"
 	"github.com/steveyegge/beads/internal/syncbranch"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/ui"
 	"github.com/steveyegge/beads/internal/utils"
+	"github.com/steveyegge/beads/internal/visibility"
+	"github.com/steveyegge/beads/internal/votes"
 )
 
 // outputJSON outputs data as pretty-printed JSON
@@ -246,6 +256,28 @@ func autoImportIfNewer() {
 
 	debug.Logf("auto-import triggered (hash changed)")
 
+	// Another bd process may be flushing right now; wait for it and re-read
+	// so we neither import a file that is about to change nor import twice.
+	unlock, err := lockJSONL(ctx, jsonlPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: auto-import skipped: %v\n", err)
+		return
+	}
+	defer unlock()
+	recoverInterruptedExport(jsonlPath)
+	jsonlData, err = os.ReadFile(jsonlPath)
+	if err != nil {
+		debug.Logf("auto-import skipped, JSONL not readable: %v", err)
+		return
+	}
+	hasher.Reset()
+	hasher.Write(jsonlData)
+	currentHash = hex.EncodeToString(hasher.Sum(nil))
+	if latest, err := store.GetMetadata(ctx, "jsonl_content_hash"); err == nil && latest == currentHash {
+		debug.Logf("auto-import skipped, JSONL imported by another process")
+		return
+	}
+
 	// Check if database needs initialization (GH#b09 - cold-start bootstrap)
 	// If issue_prefix is not set, the DB is uninitialized and import will fail.
 	// Auto-detect and set the prefix to enable seamless cold-start recovery.
@@ -347,11 +379,13 @@ func autoImportIfNewer() {
 		SkipPrefixValidation: true, // Auto-import is lenient about prefixes
 	}
 
+	recordPendingOpLog(ctx, store, jsonlPath)
 	result, err := importIssuesCore(ctx, dbPath, store, allIssues, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Auto-import failed: %v\n", err)
 		return
 	}
+	applyOpLog(ctx, store, jsonlPath)
 
 	// Show collision remapping notification if any occurred
 	if len(result.IDMapping) > 0 {
@@ -540,49 +574,22 @@ func writeJSONLAtomic(jsonlPath string, issues []*types.Issue) ([]string, error)
 		return cmp.Compare(a.ID, b.ID)
 	})
 
-	// Create temp file with PID suffix to avoid collisions
-	tempPath := fmt.Sprintf("%s.tmp.%d", jsonlPath, os.Getpid())
-	f, err := os.Create(tempPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create temp file: %w", err)
-	}
-
-	// Ensure cleanup on failure
-	defer func() {
-		if f != nil {
-			_ = f.Close()
-			_ = os.Remove(tempPath)
-		}
-	}()
-
-	// Write all issues as JSONL (timestamp-only deduplication DISABLED)
-	encoder := json.NewEncoder(f)
-	skippedCount := 0
+	// Write all issues as JSONL (timestamp-only deduplication DISABLED).
+	// atomicfile fsyncs and journals the swap so a crash can't leave a
+	// truncated JSONL behind.
 	exportedIDs := make([]string, 0, len(issues))
-	
-	for _, issue := range issues {
-		if err := encoder.Encode(issue); err != nil {
-		 return nil, fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
+	_, err := atomicfile.Write(jsonlPath, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		for _, issue := range issues {
+			if err := encoder.Encode(issue); err != nil {
+				return fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
+			}
+			exportedIDs = append(exportedIDs, issue.ID)
 		}
-		
-		exportedIDs = append(exportedIDs, issue.ID)
-	}
-	
-	// Report skipped issues if any (helps debugging)
-	if skippedCount > 0 {
-		debug.Logf("auto-flush skipped %d issue(s) with timestamp-only changes", skippedCount)
-	}
-
-	// Close temp file before renaming
-	if err := f.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close temp file: %w", err)
-	}
-	f = nil // Prevent defer cleanup
-
-	// Atomic rename
-	if err := os.Rename(tempPath, jsonlPath); err != nil {
-		_ = os.Remove(tempPath) // Clean up on rename failure
-		return nil, fmt.Errorf("failed to rename file: %w", err)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Set appropriate file permissions (0644: rw-r--r--)
@@ -836,6 +843,16 @@ func flushToJSONLWithState(state flushState) {
 
 	ctx := rootCtx
 
+	// Hold the JSONL lock from reading the file to recording its hash, so a
+	// concurrent flush from another bd process can't drop our merge (or we
+	// theirs).
+	unlock, err := lockJSONL(ctx, jsonlPath)
+	if err != nil {
+		recordFlushFailure(err)
+		return
+	}
+	defer unlock()
+
 	// Validate JSONL integrity BEFORE checking isDirty
 	// This detects if JSONL and export_hashes are out of sync (e.g., after git operations)
 	integrityNeedsFullExport, err := validateJSONLIntegrity(ctx, jsonlPath)
@@ -891,12 +908,55 @@ func flushToJSONLWithState(state flushState) {
 	// Filter by prefix in multi-repo mode
 	issues = filterByMultiRepoPrefix(ctx, store, issues)
 
+	// Populate typed external refs
+	if err := refs.Populate(ctx, store, issues); err != nil {
+		recordFlushFailure(err)
+		return
+	}
+
+	// Populate security advisories
+	if err := advisory.Populate(ctx, store, issues); err != nil {
+		recordFlushFailure(err)
+		return
+	}
+	if err := repro.Populate(ctx, store, issues); err != nil {
+		recordFlushFailure(err)
+		return
+	}
+	if err := votes.Populate(ctx, store, issues); err != nil {
+		recordFlushFailure(err)
+		return
+	}
+	if err := cc.Populate(ctx, store, issues); err != nil {
+		recordFlushFailure(err)
+		return
+	}
+	if err := goals.Populate(ctx, store, issues); err != nil {
+		recordFlushFailure(err)
+		return
+	}
+	// Private issues never reach the committed JSONL
+	issues, err = visibility.WithholdPrivate(ctx, store, issues)
+	if err != nil {
+		recordFlushFailure(err)
+		return
+	}
+
+	// Write subset stubs back in full
+	issues, err = subset.PreserveStubs(ctx, store, jsonlPath, issues)
+	if err != nil {
+		recordFlushFailure(err)
+		return
+	}
+
 	// Write atomically
 	exportedIDs, err := writeJSONLAtomic(jsonlPath, issues)
 	if err != nil {
 		recordFlushFailure(err)
 		return
 	}
+	recordOpLog(ctx, store, jsonlPath, dirtyIDs)
+	recordStatusSnapshot(ctx, store)
 
 	// Clear dirty issues that were exported
 	if len(exportedIDs) > 0 {
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/automation"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/query"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// automationActor is recorded as the actor of changes made by rules.
+const automationActor = "automation"
+
+var automationCmd = &cobra.Command{
+	Use:     "automation",
+	GroupID: "advanced",
+	Short:   "Inspect and test \"when X then Y\" automation rules",
+	Long: `Automation rules change issues when they match a condition. The daemon
+evaluates them after every mutation; restart it after editing the rules.
+
+Rules live in config.yaml. "when" is a query expression (see bd query) and
+"then" is one action or a list of them:
+
+  automation:
+    rules:
+      - name: release-tracking
+        when: status=closed and label=needs-release
+        then: add-label pending-release
+      - name: triage-security
+        when: label=security and status=open
+        then:
+          - set-priority 0
+          - assign secteam
+
+Actions:
+  add-label <label>       remove-label <label>
+  set-status <status>     set-priority <0-4>
+  assign <name|none>
+
+Actions that wouldn't change the issue are skipped, so a rule fires once
+per change rather than on every mutation. When one rule's actions make
+another match, rules run again (up to 5 passes).`,
+}
+
+var automationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the configured rules and any errors in them",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+		rules, errs := compileAutomationRules(ctx)
+		if jsonOutput {
+			type ruleJSON struct {
+				Name string              `json:"name"`
+				When string              `json:"when"`
+				Then []automation.Action `json:"then"`
+			}
+			out := struct {
+				Rules  []ruleJSON `json:"rules"`
+				Errors []string   `json:"errors,omitempty"`
+			}{Rules: []ruleJSON{}}
+			for _, r := range rules {
+				out.Rules = append(out.Rules, ruleJSON{Name: r.Name, When: r.When.String(), Then: r.Then})
+			}
+			for _, err := range errs {
+				out.Errors = append(out.Errors, err.Error())
+			}
+			outputJSON(out)
+			return
+		}
+		if len(rules) == 0 && len(errs) == 0 {
+			fmt.Println("No automation rules configured (see bd automation --help).")
+			return
+		}
+		for _, r := range rules {
+			fmt.Printf("%s\n  when: %s\n", ui.RenderAccent(r.Name), r.When.String())
+			for _, a := range r.Then {
+				fmt.Printf("  then: %s\n", a)
+			}
+		}
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s %v\n", ui.RenderFail("✗"), err)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var automationTestCmd = &cobra.Command{
+	Use:   "test <id>...",
+	Short: "Show which rules match issues and what they would change",
+	Long: `Evaluate the rules against issues without changing them. With --apply,
+run the actions too, as the daemon would.`,
+	Example: `  bd automation test bd-42
+  bd automation test bd-42 bd-43 --apply`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apply, _ := cmd.Flags().GetBool("apply")
+		if apply {
+			CheckReadonly("automation test --apply")
+		}
+		if err := ensureDirectMode("automation test requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		rules, errs := compileAutomationRules(ctx)
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s %v\n", ui.RenderWarn("⚠"), err)
+		}
+
+		var all []automation.Firing
+		for _, arg := range args {
+			id, err := utils.ResolvePartialID(ctx, store, arg)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			fired, err := automation.Run(ctx, store, rules, id, automationActor, !apply)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			all = append(all, fired...)
+			if jsonOutput {
+				continue
+			}
+			if len(fired) == 0 {
+				fmt.Printf("%s: no rules would change it\n", id)
+				continue
+			}
+			for _, f := range fired {
+				actions := make([]string, len(f.Actions))
+				for i, a := range f.Actions {
+					actions[i] = a.String()
+				}
+				verb := "would"
+				if apply {
+					verb = "did"
+				}
+				fmt.Printf("%s: %s %s %s\n", id, ui.RenderAccent(f.Rule), verb, strings.Join(actions, ", "))
+			}
+		}
+		if apply && len(all) > 0 {
+			markDirtyAndScheduleFlush()
+		}
+		if jsonOutput {
+			if all == nil {
+				all = []automation.Firing{}
+			}
+			outputJSON(all)
+		}
+	},
+}
+
+// compileAutomationRules compiles automation.rules, resolving @saved
+// filters in conditions.
+func compileAutomationRules(ctx context.Context) ([]*automation.Rule, []error) {
+	return automation.Compile(config.GetAutomationRules(), query.Options{Saved: savedQueryLookup(ctx)})
+}
+
+// runDaemonAutomation evaluates the rules against an issue the daemon just
+// saw change. Rule changes are written straight to the store, so they don't
+// produce further mutation events.
+func runDaemonAutomation(ctx context.Context, s storage.Storage, event rpc.MutationEvent, log daemonLogger) {
+	if event.IssueID == "" || event.Type == rpc.MutationDelete || event.Type == rpc.MutationBurned {
+		return
+	}
+	cfg := config.GetAutomationRules()
+	if len(cfg) == 0 {
+		return
+	}
+	lookup := query.ConfigLookup(func(key string) (string, error) { return s.GetConfig(ctx, key) })
+	rules, errs := automation.Compile(cfg, query.Options{Saved: lookup})
+	for _, err := range errs {
+		log.Warn("automation rule skipped", "error", err)
+	}
+	fired, err := automation.Run(ctx, s, rules, event.IssueID, automationActor, false)
+	for _, f := range fired {
+		log.Info("automation rule fired", "rule", f.Rule, "issue", f.IssueID, "actions", len(f.Actions))
+	}
+	if err != nil {
+		log.Error("automation failed", "issue", event.IssueID, "error", err)
+	}
+}
+
+func init() {
+	automationTestCmd.Flags().Bool("apply", false, "Apply the actions instead of only showing them")
+	automationCmd.AddCommand(automationListCmd, automationTestCmd)
+	rootCmd.AddCommand(automationCmd)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/calendar"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var calendarCmd = &cobra.Command{
+	Use:     "calendar",
+	GroupID: "views",
+	Short:   "Calendar views of due dates, milestones, and sprints",
+}
+
+var calendarExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export due dates, milestones, and sprints as an iCalendar feed",
+	Long: `Export an iCalendar (.ics) feed for subscription in Google Calendar,
+Outlook, or any calendar app that accepts ICS files.
+
+The feed contains:
+  - Due dates of open issues (all-day events)
+  - Milestones: for each milestone:<name> label, an event on the latest
+    due date among its issues
+  - Sprint boundaries, when sprint.start (and optionally sprint.length-days)
+    is set in config.yaml
+
+Events use stable UIDs, so re-exporting to the same URL updates the
+subscribed calendar in place. With feed.listen set, the daemon also serves
+the feed at /calendar.ics.
+
+Examples:
+  bd calendar export --ics                      # Write to stdout
+  bd calendar export --ics -o beads.ics
+  bd calendar export --ics --all                # Include closed issues`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ics, _ := cmd.Flags().GetBool("ics")
+		output, _ := cmd.Flags().GetString("output")
+		includeClosed, _ := cmd.Flags().GetBool("all")
+
+		if !ics {
+			FatalErrorWithHint("no output format selected", "use --ics to export an iCalendar feed")
+		}
+		if err := ensureDirectMode("calendar export requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+
+		events, err := calendar.Collect(rootCtx, store, calendar.Options{IncludeClosed: includeClosed})
+		if err != nil {
+			FatalError("%v", err)
+		}
+		if sprint := sprintCalendarEvent(); sprint != nil {
+			events = append(events, sprint)
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			f, err := os.Create(output) // #nosec G304 -- user-specified output path
+			if err != nil {
+				FatalError("failed to create %s: %v", output, err)
+			}
+			defer func() { _ = f.Close() }()
+			w = f
+		}
+		if err := calendar.WriteICS(w, events, time.Now()); err != nil {
+			FatalError("failed to write calendar: %v", err)
+		}
+		if output != "" {
+			fmt.Fprintf(os.Stderr, "%s Exported %d event(s) to %s\n", ui.RenderPass("✓"), len(events), output)
+		}
+	},
+}
+
+// sprintCalendarEvent returns the recurring sprint-start event configured by
+// sprint.start and sprint.length-days, or nil if there is none.
+func sprintCalendarEvent() *calendar.Event {
+	sprint, err := calendar.SprintEvent(config.GetString("sprint.start"), config.GetInt("sprint.length-days"))
+	if err != nil {
+		WarnError("ignoring %v", err)
+	}
+	return sprint
+}
+
+func init() {
+	calendarExportCmd.Flags().Bool("ics", false, "Export in iCalendar (.ics) format")
+	calendarExportCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	calendarExportCmd.Flags().Bool("all", false, "Include closed issues")
+	calendarCmd.AddCommand(calendarExportCmd)
+	rootCmd.AddCommand(calendarCmd)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+)
+
+// newRootContext returns the context every command runs under.
+//
+// The first Ctrl-C or SIGTERM cancels it, so imports, syncs and daemon
+// requests stop at the next check and report what they finished. Default
+// signal handling is restored at that point: a second Ctrl-C kills commands
+// that never look at the context.
+//
+// --timeout (or the timeout setting) adds a deadline. The daemon and bd web
+// run indefinitely, so they only honour an explicit --timeout flag.
+func newRootContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	if !cmd.Flags().Changed("timeout") {
+		commandTimeout = 0
+		if !isLongRunningCommand(cmd) {
+			commandTimeout = config.GetDuration("timeout")
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+	if commandTimeout <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, commandTimeout, fmt.Errorf("timed out after %s", commandTimeout))
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// isLongRunningCommand reports whether cmd serves until stopped: "bd web",
+// or "bd daemon" and its subcommands.
+func isLongRunningCommand(cmd *cobra.Command) bool {
+	fields := strings.Fields(cmd.CommandPath())
+	return len(fields) > 1 && (fields[1] == "daemon" || fields[1] == "web")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+)
+
+func TestNewRootContextTimeout(t *testing.T) {
+	if err := config.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	saved := commandTimeout
+	t.Cleanup(func() { commandTimeout = saved })
+
+	root := &cobra.Command{Use: "bd"}
+	root.PersistentFlags().DurationVar(&commandTimeout, "timeout", 0, "")
+	list := &cobra.Command{Use: "list"}
+	daemon := &cobra.Command{Use: "daemon"}
+	start := &cobra.Command{Use: "start"}
+	daemon.AddCommand(start)
+	root.AddCommand(list, daemon)
+
+	config.Set("timeout", "20ms")
+	t.Cleanup(func() { config.Set("timeout", "0s") })
+
+	ctx, cancel := newRootContext(list)
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatal("configured timeout did not set a deadline")
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not cancelled by the timeout")
+	}
+	if cause := context.Cause(ctx); cause == nil || cause.Error() != "timed out after 20ms" {
+		t.Errorf("cause = %v", cause)
+	}
+
+	// The daemon runs indefinitely and ignores the configured timeout
+	ctx, cancel = newRootContext(start)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("daemon command got a deadline from config")
+	}
+}
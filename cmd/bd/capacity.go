@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// CapacityStatus describes an assignee's work-in-progress against their limit.
+type CapacityStatus struct {
+	Assignee   string `json:"assignee"`
+	InProgress int    `json:"in_progress"`
+	Limit      int    `json:"limit,omitempty"` // 0 = unlimited
+}
+
+// AtLimit returns true if the assignee cannot take on new work.
+func (c CapacityStatus) AtLimit() bool {
+	return c.Limit > 0 && c.InProgress >= c.Limit
+}
+
+// Overloaded returns true if the assignee has more work in progress than their limit.
+func (c CapacityStatus) Overloaded() bool {
+	return c.Limit > 0 && c.InProgress > c.Limit
+}
+
+// getCapacityStatus counts the assignee's in-progress issues and pairs the
+// count with their configured WIP limit (capacity.<assignee> or capacity.default).
+func getCapacityStatus(ctx context.Context, assignee string) (CapacityStatus, error) {
+	status := CapacityStatus{
+		Assignee: assignee,
+		Limit:    config.GetCapacity(assignee),
+	}
+
+	if daemonClient != nil {
+		resp, err := daemonClient.List(&rpc.ListArgs{
+			Status:   string(types.StatusInProgress),
+			Assignee: assignee,
+		})
+		if err != nil {
+			return status, err
+		}
+		var issues []*types.IssueWithCounts
+		if err := json.Unmarshal(resp.Data, &issues); err != nil {
+			return status, fmt.Errorf("parsing response: %w", err)
+		}
+		status.InProgress = len(issues)
+		return status, nil
+	}
+
+	inProgress := types.StatusInProgress
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{
+		Status:   &inProgress,
+		Assignee: &assignee,
+	})
+	if err != nil {
+		return status, err
+	}
+	status.InProgress = len(issues)
+	return status, nil
+}
+
+// filterReadyByCapacity drops work that hasn't been started when the assignee
+// is at their WIP limit. In-progress issues are kept so the assignee can
+// continue what they already have on their plate.
+func filterReadyByCapacity(issues []*types.Issue, capacity CapacityStatus) []*types.Issue {
+	if !capacity.AtLimit() {
+		return issues
+	}
+	filtered := make([]*types.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Status == types.StatusInProgress {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// applyReadyCapacity enforces the WIP limit for `bd ready --for <assignee>`.
+// Returns nil when no assignee was requested. Capacity lookup failures are
+// reported as warnings and leave the ready list untouched.
+func applyReadyCapacity(assignee string, issues *[]*types.Issue) *CapacityStatus {
+	if assignee == "" {
+		return nil
+	}
+	capacity, err := getCapacityStatus(rootCtx, assignee)
+	if err != nil {
+		WarnError("failed to check capacity for %s: %v", assignee, err)
+		return nil
+	}
+	*issues = filterReadyByCapacity(*issues, capacity)
+	return &capacity
+}
+
+// printCapacityNotice tells the user when new work was withheld because the
+// assignee is at (or over) their WIP limit.
+func printCapacityNotice(capacity *CapacityStatus) {
+	if capacity == nil || !capacity.AtLimit() {
+		return
+	}
+	label := "at WIP limit"
+	if capacity.Overloaded() {
+		label = "over WIP limit"
+	}
+	fmt.Fprintf(os.Stderr, "\n%s %s is %s (%d/%d in progress): showing in-progress work only\n",
+		ui.RenderWarn("⚠"), capacity.Assignee, label, capacity.InProgress, capacity.Limit)
+}
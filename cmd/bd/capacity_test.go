@@ -0,0 +1,126 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestCapacityStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     CapacityStatus
+		atLimit    bool
+		overloaded bool
+	}{
+		{"unlimited", CapacityStatus{InProgress: 10, Limit: 0}, false, false},
+		{"under limit", CapacityStatus{InProgress: 1, Limit: 2}, false, false},
+		{"at limit", CapacityStatus{InProgress: 2, Limit: 2}, true, false},
+		{"over limit", CapacityStatus{InProgress: 3, Limit: 2}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.AtLimit(); got != tt.atLimit {
+				t.Errorf("AtLimit() = %v, want %v", got, tt.atLimit)
+			}
+			if got := tt.status.Overloaded(); got != tt.overloaded {
+				t.Errorf("Overloaded() = %v, want %v", got, tt.overloaded)
+			}
+		})
+	}
+}
+
+func TestFilterReadyByCapacity(t *testing.T) {
+	issues := []*types.Issue{
+		{ID: "bd-1", Status: types.StatusOpen},
+		{ID: "bd-2", Status: types.StatusInProgress},
+		{ID: "bd-3", Status: types.StatusOpen},
+	}
+
+	under := filterReadyByCapacity(issues, CapacityStatus{InProgress: 1, Limit: 3})
+	if len(under) != 3 {
+		t.Fatalf("expected all issues under limit, got %d", len(under))
+	}
+
+	at := filterReadyByCapacity(issues, CapacityStatus{InProgress: 1, Limit: 1})
+	if len(at) != 1 || at[0].ID != "bd-2" {
+		t.Fatalf("expected only in-progress issue at limit, got %v", at)
+	}
+}
+
+func TestBuildWorkload(t *testing.T) {
+	issues := []*types.Issue{
+		{ID: "bd-1", Assignee: "alice", Status: types.StatusInProgress},
+		{ID: "bd-2", Assignee: "alice", Status: types.StatusInProgress},
+		{ID: "bd-3", Assignee: "alice", Status: types.StatusInProgress},
+		{ID: "bd-4", Assignee: "alice", Status: types.StatusOpen},
+		{ID: "bd-5", Assignee: "bob", Status: types.StatusBlocked},
+		{ID: "bd-6", Assignee: "bob", Status: types.StatusInProgress},
+		{ID: "bd-7", Status: types.StatusOpen},
+		{ID: "bd-8", Assignee: "bob", Status: types.StatusClosed},
+	}
+	limits := map[string]int{"alice": 2, "bob": 1}
+
+	entries := buildWorkload(issues, func(name string) int { return limits[name] }, time.Now())
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	alice := entries[0]
+	if alice.Assignee != "alice" || alice.InProgress != 3 || alice.Open != 1 {
+		t.Errorf("unexpected alice entry: %+v", alice)
+	}
+	if !alice.Overloaded || !alice.AtLimit {
+		t.Errorf("expected alice to be overloaded: %+v", alice)
+	}
+
+	bob := entries[1]
+	if bob.Assignee != "bob" || bob.InProgress != 1 || bob.Blocked != 1 {
+		t.Errorf("unexpected bob entry: %+v", bob)
+	}
+	if !bob.AtLimit || bob.Overloaded {
+		t.Errorf("expected bob at limit but not overloaded: %+v", bob)
+	}
+
+	unassigned := entries[2]
+	if unassigned.Assignee != "" || unassigned.Open != 1 || unassigned.Limit != 0 {
+		t.Errorf("unexpected unassigned entry: %+v", unassigned)
+	}
+}
+
+func TestBuildWorkloadBreakdown(t *testing.T) {
+	now := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(d int) time.Time { return now.Add(-time.Duration(d) * 24 * time.Hour) }
+	issues := []*types.Issue{
+		{ID: "bd-1", Assignee: "alice", Status: types.StatusOpen, Priority: 0, CreatedAt: daysAgo(1)},
+		{ID: "bd-2", Assignee: "alice", Status: types.StatusOpen, Priority: 2, CreatedAt: daysAgo(10)},
+		{ID: "bd-3", Assignee: "alice", Status: types.StatusBlocked, Priority: 2, CreatedAt: daysAgo(45)},
+		{ID: "bd-4", Assignee: "alice", Status: types.StatusOpen, Priority: 4, CreatedAt: daysAgo(120)},
+		{ID: "bd-5", Assignee: "bob", Status: types.StatusInProgress, Priority: 1, CreatedAt: daysAgo(3)},
+	}
+
+	entries := buildWorkload(issues, nil, now)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	bob, alice := entries[0], entries[1]
+	if bob.Assignee != "bob" || bob.Idle {
+		t.Errorf("expected bob first and not idle: %+v", bob)
+	}
+	if alice.Total != 4 || !alice.Idle {
+		t.Errorf("expected alice with 4 issues and idle: %+v", alice)
+	}
+	if alice.ByPriority["P0"] != 1 || alice.ByPriority["P2"] != 2 || alice.ByPriority["P4"] != 1 {
+		t.Errorf("unexpected priority breakdown: %v", alice.ByPriority)
+	}
+	for _, bucket := range []string{"0-7d", "7-30d", "30-90d", "90d+"} {
+		if alice.ByAge[bucket] != 1 {
+			t.Errorf("expected 1 issue in %s bucket, got %d", bucket, alice.ByAge[bucket])
+		}
+	}
+	if alice.OldestDays != 120 {
+		t.Errorf("OldestDays = %d, want 120", alice.OldestDays)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/hooks"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/timeparsing"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// capturePriorityRe matches priority tokens: !p1, !P1, or !1.
+var capturePriorityRe = regexp.MustCompile(`^![pP]?([0-4])$`)
+
+// captureSpec is an issue parsed from a quick-capture string.
+type captureSpec struct {
+	Title      string     `json:"title"`
+	Priority   int        `json:"priority"`
+	IssueType  string     `json:"issue_type"`
+	Assignee   string     `json:"assignee,omitempty"`
+	Labels     []string   `json:"labels,omitempty"`
+	DueAt      *time.Time `json:"due_at,omitempty"`
+	DeferUntil *time.Time `json:"defer_until,omitempty"`
+}
+
+var captureCmd = &cobra.Command{
+	Use:     "capture <text>",
+	GroupID: "issues",
+	Short:   "Quick-add an issue using inline shorthand",
+	Long: `Quickly create an issue from a single line of text with inline shorthand.
+
+Tokens anywhere in the text set fields; everything else is the title:
+  !p1, !1          Priority (0-4)
+  @alice           Assignee
+  #daemon          Label (repeatable)
+  due:friday       Due date (same formats as --due; use - for spaces: due:next-monday)
+  defer:+2d        Defer until
+  type:bug         Issue type
+
+Prefix a token with a backslash to keep it in the title (e.g. \#123).
+
+Use - to read one capture per line from stdin.
+
+Examples:
+  bd capture "Fix daemon race !p1 @alice #daemon due:friday"
+  bd capture Update docs for sync #docs type:chore
+  bd capture --dry-run "Refactor parser !0 due:+3d"
+  printf 'Idea one #ideas\nIdea two #ideas\n' | bd capture -`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if !dryRun {
+			CheckReadonly("capture")
+		}
+
+		var lines []string
+		if len(args) == 1 && args[0] == "-" {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				if line := strings.TrimSpace(scanner.Text()); line != "" {
+					lines = append(lines, line)
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				FatalErrorRespectJSON("reading stdin: %v", err)
+			}
+		} else {
+			lines = []string{strings.Join(args, " ")}
+		}
+
+		now := time.Now()
+		specs := make([]*captureSpec, 0, len(lines))
+		for _, line := range lines {
+			spec, err := parseCapture(line, now)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			specs = append(specs, spec)
+		}
+
+		if dryRun {
+			if jsonOutput {
+				outputJSON(specs)
+				return
+			}
+			for _, spec := range specs {
+				printCaptureSpec(spec)
+			}
+			return
+		}
+
+		created := make([]*types.Issue, 0, len(specs))
+		for _, spec := range specs {
+			issue, err := createCapturedIssue(spec)
+			if err != nil {
+				FatalErrorRespectJSON("creating %q: %v", spec.Title, err)
+			}
+			created = append(created, issue)
+		}
+
+		if jsonOutput {
+			if len(created) == 1 {
+				outputJSON(created[0])
+			} else {
+				outputJSON(created)
+			}
+			return
+		}
+		for _, issue := range created {
+			fmt.Printf("%s Captured %s: %s\n", ui.RenderPass("✓"), ui.RenderID(issue.ID), issue.Title)
+		}
+		if len(created) > 0 {
+			SetLastTouchedID(created[len(created)-1].ID)
+		}
+	},
+}
+
+// parseCapture splits quick-capture text into a title and field tokens.
+func parseCapture(text string, now time.Time) (*captureSpec, error) {
+	spec := &captureSpec{Priority: 2, IssueType: string(types.TypeTask)}
+	var title []string
+
+	for _, tok := range strings.Fields(text) {
+		if strings.HasPrefix(tok, `\`) && len(tok) > 1 {
+			title = append(title, tok[1:])
+			continue
+		}
+		if m := capturePriorityRe.FindStringSubmatch(tok); m != nil {
+			spec.Priority, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if len(tok) > 1 && tok[0] == '@' {
+			spec.Assignee = tok[1:]
+			continue
+		}
+		if len(tok) > 1 && tok[0] == '#' {
+			spec.Labels = append(spec.Labels, tok[1:])
+			continue
+		}
+		if key, value, ok := strings.Cut(tok, ":"); ok && value != "" {
+			switch strings.ToLower(key) {
+			case "due":
+				t, err := parseCaptureTime(value, now)
+				if err != nil {
+					return nil, fmt.Errorf("invalid due date %q: %w", value, err)
+				}
+				spec.DueAt = &t
+				continue
+			case "defer":
+				t, err := parseCaptureTime(value, now)
+				if err != nil {
+					return nil, fmt.Errorf("invalid defer date %q: %w", value, err)
+				}
+				spec.DeferUntil = &t
+				continue
+			case "type", "t":
+				spec.IssueType = string(types.IssueType(value).Normalize())
+				continue
+			}
+		}
+		title = append(title, tok)
+	}
+
+	spec.Title = strings.Join(title, " ")
+	if spec.Title == "" {
+		return nil, fmt.Errorf("no title in %q", text)
+	}
+	return spec, nil
+}
+
+// parseCaptureTime parses a due/defer value, allowing - or _ in place of
+// spaces for multi-word expressions (next-monday).
+func parseCaptureTime(value string, now time.Time) (time.Time, error) {
+	t, err := timeparsing.ParseRelativeTime(value, now)
+	if err == nil {
+		return t, nil
+	}
+	spaced := strings.NewReplacer("-", " ", "_", " ").Replace(value)
+	if spaced != value {
+		if t, err2 := timeparsing.ParseRelativeTime(spaced, now); err2 == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// createCapturedIssue creates an issue from a capture spec via the daemon or
+// direct storage, running the create hook either way.
+func createCapturedIssue(spec *captureSpec) (*types.Issue, error) {
+	var issue *types.Issue
+	if daemonClient != nil {
+		resp, err := daemonClient.Create(&rpc.CreateArgs{
+			Title:      spec.Title,
+			IssueType:  spec.IssueType,
+			Priority:   spec.Priority,
+			Assignee:   spec.Assignee,
+			Labels:     spec.Labels,
+			CreatedBy:  getActorWithGit(),
+			Owner:      getOwner(),
+			DueAt:      formatTimeForRPC(spec.DueAt),
+			DeferUntil: formatTimeForRPC(spec.DeferUntil),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(resp.Data, &issue); err != nil {
+			return nil, fmt.Errorf("parsing response: %w", err)
+		}
+	} else {
+		ctx := rootCtx
+		issue = &types.Issue{
+			Title:      spec.Title,
+			Status:     types.StatusOpen,
+			Priority:   spec.Priority,
+			IssueType:  types.IssueType(spec.IssueType),
+			Assignee:   spec.Assignee,
+			Labels:     spec.Labels, // Added below; set so the validation rules see them
+			CreatedBy:  getActorWithGit(),
+			Owner:      getOwner(),
+			DueAt:      spec.DueAt,
+			DeferUntil: spec.DeferUntil,
+		}
+		if err := store.CreateIssue(ctx, issue, actor); err != nil {
+			return nil, err
+		}
+		for _, label := range spec.Labels {
+			if err := store.AddLabel(ctx, issue.ID, label, actor); err != nil {
+				WarnError("failed to add label %s: %v", label, err)
+			}
+		}
+		markDirtyAndScheduleFlush()
+	}
+
+	if hookRunner != nil {
+		hookRunner.Run(hooks.EventCreate, issue)
+	}
+	return issue, nil
+}
+
+// printCaptureSpec shows how a capture string was interpreted (--dry-run).
+func printCaptureSpec(spec *captureSpec) {
+	fmt.Printf("%s %s\n", ui.RenderAccent("○"), spec.Title)
+	fmt.Printf("  Priority: P%d  Type: %s\n", spec.Priority, spec.IssueType)
+	if spec.Assignee != "" {
+		fmt.Printf("  Assignee: %s\n", spec.Assignee)
+	}
+	if len(spec.Labels) > 0 {
+		fmt.Printf("  Labels:   %s\n", strings.Join(spec.Labels, ", "))
+	}
+	if spec.DueAt != nil {
+		fmt.Printf("  Due:      %s\n", spec.DueAt.Format("2006-01-02 15:04"))
+	}
+	if spec.DeferUntil != nil {
+		fmt.Printf("  Defer:    %s\n", spec.DeferUntil.Format("2006-01-02 15:04"))
+	}
+}
+
+func init() {
+	captureCmd.Flags().Bool("dry-run", false, "Show how the text would be parsed without creating anything")
+	rootCmd.AddCommand(captureCmd)
+}
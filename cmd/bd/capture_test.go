@@ -0,0 +1,75 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseCapture(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC) // Wednesday
+
+	spec, err := parseCapture("Fix daemon race !p1 @alice #daemon #sync due:+2d", now)
+	if err != nil {
+		t.Fatalf("parseCapture: %v", err)
+	}
+	if spec.Title != "Fix daemon race" {
+		t.Errorf("Title = %q", spec.Title)
+	}
+	if spec.Priority != 1 || spec.Assignee != "alice" || spec.IssueType != "task" {
+		t.Errorf("unexpected fields: %+v", spec)
+	}
+	if !reflect.DeepEqual(spec.Labels, []string{"daemon", "sync"}) {
+		t.Errorf("Labels = %v", spec.Labels)
+	}
+	if spec.DueAt == nil || !spec.DueAt.Equal(now.AddDate(0, 0, 2)) {
+		t.Errorf("DueAt = %v", spec.DueAt)
+	}
+}
+
+func TestParseCaptureTokens(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in       string
+		title    string
+		priority int
+		itype    string
+	}{
+		{"Crash on start !0 type:bug", "Crash on start", 0, "bug"},
+		{"Bump deps !P4 t:chore", "Bump deps", 4, "chore"},
+		{`Close \#123 and \@mention`, "Close #123 and @mention", 2, "task"},
+		{"See http://example.com/x ! # @", "See http://example.com/x ! # @", 2, "task"},
+		{"Ratio 3:1 !5", "Ratio 3:1 !5", 2, "task"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			spec, err := parseCapture(tt.in, now)
+			if err != nil {
+				t.Fatalf("parseCapture: %v", err)
+			}
+			if spec.Title != tt.title || spec.Priority != tt.priority || spec.IssueType != tt.itype {
+				t.Errorf("got %q P%d %s, want %q P%d %s", spec.Title, spec.Priority, spec.IssueType, tt.title, tt.priority, tt.itype)
+			}
+		})
+	}
+}
+
+func TestParseCaptureErrors(t *testing.T) {
+	now := time.Now()
+	for _, in := range []string{"!p1 @alice #x", "Ship due:notadate"} {
+		if _, err := parseCapture(in, now); err == nil {
+			t.Errorf("expected error for %q", in)
+		}
+	}
+}
+
+func TestParseCaptureTimeMultiWord(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC) // Wednesday
+	got, err := parseCaptureTime("next-monday", now)
+	if err != nil {
+		t.Fatalf("parseCaptureTime: %v", err)
+	}
+	if got.Weekday() != time.Monday || !got.After(now) {
+		t.Errorf("got %v, want a Monday after %v", got, now)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/beads/internal/cc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Stakeholders cc'd on an issue (--cc on create and update) stay informed
+// without being the assignee: bd show lists them, bd list --cc finds their
+// issues, bd digest names them (and copies them on --format email), and hook
+// payloads carry them.
+
+// createCC reads and validates bd create's --cc flag, before the issue is
+// created.
+func createCC(flags *pflag.FlagSet) []string {
+	entries, _ := flags.GetStringSlice("cc")
+	list, err := cc.Normalize(entries)
+	if err != nil {
+		FatalErrorCode(ErrCodeUsage, "%v", err)
+	}
+	return list
+}
+
+// setCreatedCC stores the cc list given to bd create on the new issue.
+func setCreatedCC(id string, list []string) {
+	if len(list) == 0 {
+		return
+	}
+	if err := ensureStoreActive(); err != nil {
+		WarnError("failed to set cc on %s: %v", id, err)
+		return
+	}
+	if err := applyCCUpdates(rootCtx, store, id, map[string]interface{}{"set_cc": list}); err != nil {
+		WarnError("failed to set cc on %s: %v", id, err)
+		return
+	}
+	markDirtyAndScheduleFlush()
+}
+
+// addCCUpdates reads bd update's --cc, --add-cc and --remove-cc flags into
+// updates as set_cc, add_cc and remove_cc.
+func addCCUpdates(flags *pflag.FlagSet, updates map[string]interface{}) {
+	for _, f := range []struct{ flag, key string }{{"cc", "set_cc"}, {"add-cc", "add_cc"}, {"remove-cc", "remove_cc"}} {
+		if !flags.Changed(f.flag) {
+			continue
+		}
+		entries, _ := flags.GetStringSlice(f.flag)
+		list, err := cc.Normalize(entries)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		updates[f.key] = list
+	}
+}
+
+// isCCUpdate reports whether an updates key is one of addCCUpdates'.
+func isCCUpdate(key string) bool {
+	return key == "set_cc" || key == "add_cc" || key == "remove_cc"
+}
+
+// applyCCUpdates applies the cc changes in updates to an issue.
+func applyCCUpdates(ctx context.Context, s storage.Storage, id string, updates map[string]interface{}) error {
+	set, hasSet := updates["set_cc"].([]string)
+	add, _ := updates["add_cc"].([]string)
+	remove, _ := updates["remove_cc"].([]string)
+	if !hasSet && len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	cs, err := cc.For(s)
+	if err != nil {
+		return err
+	}
+	list := set
+	if !hasSet {
+		if list, err = cs.GetCC(ctx, id); err != nil {
+			return err
+		}
+	}
+	return cs.SetCC(ctx, id, cc.Apply(list, add, remove), actor)
+}
+
+// updateCCDirect applies bd update's cc changes in daemon mode, where the
+// daemon's update RPC doesn't carry them.
+func updateCCDirect(ctx context.Context, id string, updates map[string]interface{}) error {
+	for key := range updates {
+		if !isCCUpdate(key) {
+			continue
+		}
+		if err := ensureStoreActive(); err != nil {
+			return err
+		}
+		return applyCCUpdates(ctx, store, id, updates)
+	}
+	return nil
+}
+
+// printCC prints an issue's stakeholders in bd show. issue.CC must already
+// be populated.
+func printCC(issue *types.Issue) {
+	if len(issue.CC) == 0 {
+		return
+	}
+	fmt.Printf("CC: %s\n", strings.Join(issue.CC, ", "))
+}
+
+// ccStore returns the store to read cc lists from outside direct mode: a
+// read-only connection, closed with the returned function.
+func ccStore(ctx context.Context) (storage.Storage, func(), error) {
+	if store != nil {
+		return store, func() {}, nil
+	}
+	if dbPath == "" {
+		return nil, nil, cc.ErrUnsupported
+	}
+	roStore, err := sqlite.NewReadOnlyWithTimeout(ctx, dbPath, lockTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return roStore, func() { _ = roStore.Close() }, nil
+}
+
+// ccFilterIDs returns the IDs of the issues a person is cc'd on, for
+// bd list --cc.
+func ccFilterIDs(ctx context.Context, person string) ([]string, error) {
+	s, done, err := ccStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	cs, err := cc.For(s)
+	if err != nil {
+		return nil, err
+	}
+	return cs.GetCCIssueIDs(ctx, strings.TrimPrefix(strings.TrimSpace(person), "@"))
+}
+
+// prepareHookIssue completes an issue before it's sent to a hook, so hook
+// payloads include its cc list.
+func prepareHookIssue(issue *types.Issue) {
+	s, done, err := ccStore(rootCtx)
+	if err != nil {
+		return
+	}
+	defer done()
+	_ = cc.Populate(rootCtx, s, []*types.Issue{issue})
+}
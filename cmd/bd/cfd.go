@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/cfd"
+	"github.com/steveyegge/beads/internal/debug"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var statusCfdCmd = &cobra.Command{
+	Use:   "cfd",
+	Short: "Daily issue counts per status for cumulative flow diagrams",
+	Long: `Show how many issues were in each status at the end of each day, the data
+behind a cumulative flow diagram.
+
+Every export records a snapshot of the day's counts. Days without one are
+rebuilt from the status changes in the event history, and today is always
+counted live. Backfilled days are marked in the output. Issues imported
+from another clone have no history here, so they count as open from
+creation until they closed.`,
+	Example: `  bd stats cfd                    # Last 90 days as a table
+  bd stats cfd --since 30d --format csv > cfd.csv
+  bd stats cfd --since 2026-01-01 --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "csv" {
+			FatalErrorCode(ErrCodeUsage, "invalid --format %q (must be text or csv)", format)
+		}
+		now := time.Now()
+		since, err := parsePastSince(sinceFlag, now)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if err := ensureDirectMode("stats cfd requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		diagram, err := cfd.Build(rootCtx, store, since, now)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		switch {
+		case jsonOutput:
+			outputJSON(diagram)
+		case format == "csv":
+			var buf bytes.Buffer
+			if err := writeCFDCSV(&buf, diagram); err != nil {
+				FatalError("%v", err)
+			}
+			_, _ = os.Stdout.Write(buf.Bytes())
+		default:
+			displayCFD(diagram)
+		}
+	},
+}
+
+// recordStatusSnapshot updates today's status snapshot after an export.
+// Storage backends without snapshots are skipped.
+func recordStatusSnapshot(ctx context.Context, s storage.Storage) {
+	if err := cfd.Record(ctx, s, time.Now()); err != nil && !errors.Is(err, cfd.ErrUnsupported) {
+		debug.Logf("status snapshot failed: %v", err)
+	}
+}
+
+func displayCFD(d *cfd.Diagram) {
+	fmt.Printf("\n%s Issues per status since %s:\n\n", ui.RenderAccent("📈"), d.Since)
+	fmt.Printf("  %-10s", "DATE")
+	for _, status := range d.Statuses {
+		fmt.Printf("  %11s", strings.ToUpper(status))
+	}
+	fmt.Println()
+	for _, day := range d.Days {
+		fmt.Printf("  %-10s", day.Date)
+		for _, status := range d.Statuses {
+			fmt.Printf("  %11d", day.Counts[status])
+		}
+		if day.Backfilled {
+			fmt.Printf("  %s", ui.RenderMuted("backfilled"))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+// writeCFDCSV writes one row per day with a header row.
+func writeCFDCSV(buf *bytes.Buffer, d *cfd.Diagram) error {
+	w := csv.NewWriter(buf)
+	_ = w.Write(append(append([]string{"date"}, d.Statuses...), "backfilled"))
+	for _, day := range d.Days {
+		record := []string{day.Date}
+		for _, status := range d.Statuses {
+			record = append(record, strconv.Itoa(day.Counts[status]))
+		}
+		_ = w.Write(append(record, strconv.FormatBool(day.Backfilled)))
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func init() {
+	statusCfdCmd.Flags().String("since", "90d", "First day: a duration such as 90d, or a date")
+	statusCfdCmd.Flags().String("format", "text", "Output format (text, csv)")
+	statusCmd.AddCommand(statusCfdCmd)
+}
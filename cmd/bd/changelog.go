@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/changelog"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// changelogLabelPrefix marks issues that belong in the changelog; the value
+// is the section, e.g. changelog:fixed.
+const changelogLabelPrefix = "changelog:"
+
+var changelogCmd = &cobra.Command{
+	Use:     "changelog",
+	GroupID: "maint",
+	Short:   "Maintain a Keep a Changelog CHANGELOG.md from closed issues",
+	Long: `Keep CHANGELOG.md current from the issues you close.
+
+Label an issue changelog:<section> to have it listed once it's closed:
+  added, changed, deprecated, removed, fixed or security
+Any other value (e.g. changelog:yes) picks the section from the issue
+type: bugs are Fixed, features Added, everything else Changed.
+
+bd changelog update adds each such issue under "## [Unreleased]" as
+"- <title> (<id>)", creating the section and subsections as needed. Issues
+whose ID already appears in the file are skipped, so entries can be
+reworded freely. Set changelog.file in config.yaml to have the daemon do
+this after every export:
+  changelog:
+    file: CHANGELOG.md`,
+}
+
+var changelogUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Add closed changelog:* issues under Unreleased",
+	Example: `  bd changelog update
+  bd changelog update --dry-run
+  bd changelog update --file docs/CHANGES.md`,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if err := ensureDirectMode("changelog update requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		path := changelogPath(file)
+
+		added, err := updateChangelogFile(ctx, store, path, dryRun)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"file": path, "added": added, "dry_run": dryRun})
+			return
+		}
+		if len(added) == 0 {
+			fmt.Printf("%s is up to date\n", path)
+			return
+		}
+		verb := "Added"
+		if dryRun {
+			verb = "Would add"
+		}
+		fmt.Printf("%s %s %d entr%s to %s\n", ui.RenderPass("✓"), verb, len(added), pluralY(len(added)), path)
+		for _, e := range added {
+			fmt.Printf("  %-10s %s\n", e.Section, e.Text)
+		}
+	},
+}
+
+var changelogReleaseCmd = &cobra.Command{
+	Use:   "release <version>",
+	Short: "Turn the Unreleased section into a version",
+	Long: `Rename "## [Unreleased]" to "## [<version>] - <date>" and start a new, empty
+Unreleased section. A leading "v" is dropped from the version.`,
+	Example: `  bd changelog release 1.4.0
+  bd changelog release v1.4.0 --date 2026-03-01`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+		dateStr, _ := cmd.Flags().GetString("date")
+		date := time.Now()
+		if dateStr != "" {
+			d, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
+			if err != nil {
+				FatalError("invalid --date %q: use YYYY-MM-DD", dateStr)
+			}
+			date = d
+		}
+		path := changelogPath(file)
+		content, err := os.ReadFile(path) // #nosec G304 -- user-configured changelog path
+		if err != nil {
+			FatalError("%v", err)
+		}
+		released, err := changelog.Release(string(content), args[0], date)
+		if err != nil {
+			FatalError("%s: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(released), 0644); err != nil { // #nosec G306 -- changelog is a public repo file
+			FatalError("%v", err)
+		}
+		fmt.Printf("%s Released %s in %s\n", ui.RenderPass("✓"), strings.TrimPrefix(args[0], "v"), path)
+	},
+}
+
+func pluralY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
+
+// changelogPath resolves the changelog file: the flag, else changelog.file,
+// else CHANGELOG.md, relative to the repository root.
+func changelogPath(file string) string {
+	if file == "" {
+		file = config.GetString("changelog.file")
+	}
+	if file == "" {
+		file = "CHANGELOG.md"
+	}
+	if filepath.IsAbs(file) || dbPath == "" {
+		return file
+	}
+	return filepath.Join(filepath.Dir(filepath.Dir(dbPath)), file)
+}
+
+// updateChangelogFile adds the missing entries to the changelog at path,
+// creating the file if needed, and returns them.
+func updateChangelogFile(ctx context.Context, s storage.Storage, path string, dryRun bool) ([]changelog.Entry, error) {
+	content, err := os.ReadFile(path) // #nosec G304 -- user-configured changelog path
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	entries, err := changelogEntries(ctx, s, string(content))
+	if err != nil || len(entries) == 0 || dryRun {
+		return entries, err
+	}
+	updated := changelog.Insert(string(content), entries)
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil { // #nosec G306 -- changelog is a public repo file
+		return nil, err
+	}
+	return entries, nil
+}
+
+// changelogEntries returns entries for closed changelog:* issues that the
+// changelog doesn't mention yet, in the order they were closed.
+func changelogEntries(ctx context.Context, s storage.Storage, content string) ([]changelog.Entry, error) {
+	closed := types.StatusClosed
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{
+		Status:     &closed,
+		LabelGlobs: []string{changelogLabelPrefix + "*"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	issues = slices.DeleteFunc(issues, func(issue *types.Issue) bool { return mentionsIssue(content, issue.ID) })
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	slices.SortStableFunc(issues, func(a, b *types.Issue) int {
+		if a.ClosedAt == nil || b.ClosedAt == nil {
+			return strings.Compare(a.ID, b.ID)
+		}
+		return a.ClosedAt.Compare(*b.ClosedAt)
+	})
+
+	var entries []changelog.Entry
+	for _, issue := range issues {
+		for _, label := range labels[issue.ID] {
+			if value, ok := strings.CutPrefix(label, changelogLabelPrefix); ok {
+				entries = append(entries, changelog.Entry{
+					Section: changelogSection(value, issue.IssueType),
+					Text:    fmt.Sprintf("%s (%s)", issue.Title, issue.ID),
+				})
+				break
+			}
+		}
+	}
+	return entries, nil
+}
+
+// changelogSection maps a changelog:<value> label to a section, falling back
+// on the issue type.
+func changelogSection(value string, issueType types.IssueType) string {
+	if section, ok := changelog.Section(value); ok {
+		return section
+	}
+	switch issueType {
+	case types.TypeBug:
+		return "Fixed"
+	case types.TypeFeature:
+		return "Added"
+	}
+	return "Changed"
+}
+
+// mentionsIssue reports whether content mentions id as a whole word, so
+// that bd-1 isn't found in bd-12 or bd-1.3.
+func mentionsIssue(content, id string) bool {
+	re := regexp.MustCompile(`(^|[^\w.-])` + regexp.QuoteMeta(id) + `($|[^\w.-]|\.(\s|$))`)
+	return re.MatchString(content)
+}
+
+// syncChangelog updates changelog.file after a daemon export. It does
+// nothing when no changelog is configured.
+func syncChangelog(ctx context.Context, s storage.Storage, beadsDir string, log daemonLogger) {
+	file := config.GetString("changelog.file")
+	if file == "" {
+		return
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(filepath.Dir(beadsDir), file)
+	}
+	added, err := updateChangelogFile(ctx, s, file, false)
+	if err != nil {
+		log.log("Changelog update failed: %v", err)
+		return
+	}
+	if len(added) > 0 {
+		log.log("Changelog: %d entr%s added to %s", len(added), pluralY(len(added)), file)
+	}
+}
+
+func init() {
+	changelogUpdateCmd.Flags().String("file", "", "Changelog file (default: changelog.file or CHANGELOG.md)")
+	changelogUpdateCmd.Flags().Bool("dry-run", false, "Show the entries without writing them")
+	changelogReleaseCmd.Flags().String("file", "", "Changelog file (default: changelog.file or CHANGELOG.md)")
+	changelogReleaseCmd.Flags().String("date", "", "Release date, YYYY-MM-DD (default: today)")
+	changelogCmd.AddCommand(changelogUpdateCmd, changelogReleaseCmd)
+	rootCmd.AddCommand(changelogCmd)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestChangelogEntries(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	for _, tc := range []struct {
+		issue *types.Issue
+		label string
+		close bool
+	}{
+		{&types.Issue{ID: "test-1", Title: "Login loop", IssueType: types.TypeBug}, "changelog:yes", true},
+		{&types.Issue{ID: "test-2", Title: "Dark mode", IssueType: types.TypeTask}, "changelog:added", true},
+		{&types.Issue{ID: "test-3", Title: "Still open", IssueType: types.TypeTask}, "changelog:fixed", false},
+		{&types.Issue{ID: "test-4", Title: "Internal", IssueType: types.TypeTask}, "refactor", true},
+		{&types.Issue{ID: "test-12", Title: "Listed", IssueType: types.TypeTask}, "changelog:changed", true},
+	} {
+		tc.issue.Status = types.StatusOpen
+		tc.issue.Priority = 2
+		if err := s.CreateIssue(ctx, tc.issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddLabel(ctx, tc.issue.ID, tc.label, "tester"); err != nil {
+			t.Fatal(err)
+		}
+		if tc.close {
+			if err := s.CloseIssue(ctx, tc.issue.ID, "done", "tester", ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	entries, err := changelogEntries(ctx, s, "## [Unreleased]\n\n### Changed\n\n- Listed (test-12)\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, e := range entries {
+		got[e.Text] = e.Section
+	}
+	want := map[string]string{"Login loop (test-1)": "Fixed", "Dark mode (test-2)": "Added"}
+	if len(got) != len(want) {
+		t.Fatalf("entries = %v, want %v", entries, want)
+	}
+	for text, section := range want {
+		if got[text] != section {
+			t.Errorf("%q in %q, want %q", text, got[text], section)
+		}
+	}
+}
+
+func TestMentionsIssue(t *testing.T) {
+	content := "- Fix (bd-12)\n- Parent work bd-3.1\n- Ends with bd-7.\n"
+	for id, want := range map[string]bool{"bd-12": true, "bd-1": false, "bd-3": false, "bd-3.1": true, "bd-7": true} {
+		if got := mentionsIssue(content, id); got != want {
+			t.Errorf("mentionsIssue(%s) = %v, want %v", id, got, want)
+		}
+	}
+}
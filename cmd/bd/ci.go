@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// ciBrokenLabel marks issues whose last reported build failed.
+const ciBrokenLabel = "ci-broken"
+
+// CI build statuses
+const (
+	ciPassed   = "passed"
+	ciFailed   = "failed"
+	ciError    = "error"
+	ciCanceled = "canceled"
+	ciRunning  = "running"
+)
+
+// ciStatusAliases maps the status names CI systems use onto bd's.
+var ciStatusAliases = map[string]string{
+	"passed": ciPassed, "pass": ciPassed, "success": ciPassed, "succeeded": ciPassed, "ok": ciPassed,
+	"failed": ciFailed, "fail": ciFailed, "failure": ciFailed,
+	"error": ciError, "errored": ciError,
+	"canceled": ciCanceled, "cancelled": ciCanceled, "aborted": ciCanceled,
+	"running": ciRunning, "pending": ciRunning, "started": ciRunning, "queued": ciRunning,
+}
+
+// ciReportLine matches the first line of a CI report comment.
+var ciReportLine = regexp.MustCompile(`^CI (passed|failed|error|canceled|running)(?:: (\S+))?$`)
+
+// CIReport is one build outcome reported on an issue. Reports are stored as
+// comments, so they sync with the issue:
+//
+//	CI failed: https://ci.example.com/builds/123
+//	Job: test-linux
+//	Commit: 4f2a9c1
+type CIReport struct {
+	IssueID  string    `json:"issue_id"`
+	Status   string    `json:"status"`
+	URL      string    `json:"url,omitempty"`
+	Job      string    `json:"job,omitempty"`
+	Commit   string    `json:"commit,omitempty"`
+	At       time.Time `json:"at"`
+	Reporter string    `json:"reporter,omitempty"`
+	Reopened bool      `json:"reopened,omitempty"` // The report reopened the closed issue
+}
+
+// CIStatusReport is the output of bd ci status.
+type CIStatusReport struct {
+	IssueID string      `json:"issue_id"`
+	Broken  bool        `json:"broken"` // Labeled ci-broken
+	Last    *CIReport   `json:"last,omitempty"`
+	History []*CIReport `json:"history"` // Newest first
+}
+
+var ciCmd = &cobra.Command{
+	Use:     "ci",
+	GroupID: "sync",
+	Short:   "Record CI build outcomes on issues",
+	Long: `Record CI build outcomes on the issues they verify, from pipelines.
+
+Each report is a "CI <status>: <url>" comment on the issue, so build
+history travels with the issue through git. A failed or errored build
+labels the issue ci-broken and a passing one removes the label, so
+'bd list --label ci-broken' shows what CI currently flags.`,
+}
+
+var ciReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report a build outcome for one or more issues",
+	Long: `Report a build outcome for one or more issues. Meant to be called from a
+pipeline step after the build, e.g. with the issue IDs parsed from the
+branch name or commit message.
+
+Statuses: passed, failed, error, canceled, running (common aliases such as
+success, failure and cancelled are accepted).
+
+With --reopen, a failed or errored build reopens issues that are closed:
+the work they tracked has regressed.`,
+	Example: `  bd ci report --issue bd-12 --status failed --url "$CI_JOB_URL"
+  bd ci report --issue bd-12,bd-13 --status passed --url "$BUILD_URL" --job test-linux --commit "$GIT_COMMIT"
+  bd ci report --issue bd-12 --status failure --url "$BUILD_URL" --reopen`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ids, _ := cmd.Flags().GetStringSlice("issue")
+		statusFlag, _ := cmd.Flags().GetString("status")
+		url, _ := cmd.Flags().GetString("url")
+		job, _ := cmd.Flags().GetString("job")
+		commit, _ := cmd.Flags().GetString("commit")
+		reopen, _ := cmd.Flags().GetBool("reopen")
+		if len(ids) == 0 {
+			FatalErrorCode(ErrCodeUsage, "--issue is required")
+		}
+		status, ok := ciStatusAliases[strings.ToLower(statusFlag)]
+		if !ok {
+			FatalErrorCode(ErrCodeUsage, "invalid --status %q (valid: passed, failed, error, canceled, running)", statusFlag)
+		}
+		if strings.ContainsAny(url, " \n") || strings.Contains(job, "\n") || strings.ContainsAny(commit, " \n") {
+			FatalErrorCode(ErrCodeUsage, "--url and --commit can't contain whitespace, --job can't contain newlines")
+		}
+		CheckReadonly("ci report")
+		if err := ensureDirectMode("ci report requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		reporter := getActorWithGit()
+		broken := status == ciFailed || status == ciError
+
+		var reports []*CIReport
+		for _, arg := range ids {
+			id, err := utils.ResolvePartialID(ctx, store, arg)
+			if err != nil {
+				FatalErrorRespectJSON("resolving %s: %v", arg, err)
+			}
+			issue, err := store.GetIssue(ctx, id)
+			if err != nil || issue == nil {
+				FatalErrorRespectJSON("issue %s not found", id)
+			}
+			report := &CIReport{IssueID: id, Status: status, URL: url, Job: job, Commit: commit, At: time.Now().UTC(), Reporter: reporter}
+			if _, err := store.AddIssueComment(ctx, id, reporter, formatCIReport(report)); err != nil {
+				FatalErrorRespectJSON("recording build on %s: %v", id, err)
+			}
+
+			labels, err := store.GetLabels(ctx, id)
+			if err != nil {
+				FatalErrorRespectJSON("failed to get labels of %s: %v", id, err)
+			}
+			labeled := containsString(labels, ciBrokenLabel)
+			switch {
+			case broken && !labeled:
+				err = store.AddLabel(ctx, id, ciBrokenLabel, reporter)
+			case status == ciPassed && labeled:
+				err = store.RemoveLabel(ctx, id, ciBrokenLabel, reporter)
+			}
+			if err != nil {
+				FatalErrorRespectJSON("updating %s on %s: %v", ciBrokenLabel, id, err)
+			}
+
+			if reopen && broken && issue.Status == types.StatusClosed {
+				if err := store.UpdateIssue(ctx, id, map[string]interface{}{"status": string(types.StatusOpen)}, reporter); err != nil {
+					FatalErrorRespectJSON("reopening %s: %v", id, err)
+				}
+				report.Reopened = true
+			}
+			reports = append(reports, report)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(reports)
+			return
+		}
+		for _, r := range reports {
+			fmt.Printf("%s Recorded CI %s on %s\n", ciStatusIcon(r.Status), r.Status, r.IssueID)
+			if r.Reopened {
+				fmt.Printf("  %s Reopened %s: its build regressed\n", ui.RenderAccent("↻"), r.IssueID)
+			}
+		}
+	},
+}
+
+var ciStatusCmd = &cobra.Command{
+	Use:   "status <issue-id>",
+	Short: "Show an issue's reported builds",
+	Example: `  bd ci status bd-12
+  bd ci status bd-12 --json
+  bd list --label ci-broken        # Every issue CI currently flags`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+		id, err := utils.ResolvePartialID(ctx, store, args[0])
+		if err != nil {
+			FatalErrorRespectJSON("resolving %s: %v", args[0], err)
+		}
+		comments, err := store.GetIssueComments(ctx, id)
+		if err != nil {
+			FatalErrorRespectJSON("getting comments: %v", err)
+		}
+		labels, err := store.GetLabels(ctx, id)
+		if err != nil {
+			FatalErrorRespectJSON("failed to get labels of %s: %v", id, err)
+		}
+
+		report := &CIStatusReport{IssueID: id, Broken: containsString(labels, ciBrokenLabel), History: []*CIReport{}}
+		for i := len(comments) - 1; i >= 0; i-- {
+			if r := parseCIReport(comments[i]); r != nil {
+				report.History = append(report.History, r)
+			}
+		}
+		if len(report.History) > 0 {
+			report.Last = report.History[0]
+		}
+
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		if report.Last == nil {
+			fmt.Printf("No builds reported for %s\n", id)
+			return
+		}
+		state := ui.RenderPass("passing")
+		if report.Broken {
+			state = ui.RenderFail("broken")
+		}
+		fmt.Printf("\n%s CI for %s: %s\n\n", ui.RenderAccent("🔧"), id, state)
+		for _, r := range report.History {
+			line := fmt.Sprintf("  %s %-8s %s", ciStatusIcon(r.Status), r.Status, r.At.Local().Format("2006-01-02 15:04"))
+			if r.Job != "" {
+				line += "  " + r.Job
+			}
+			if r.Commit != "" {
+				line += "  @" + r.Commit
+			}
+			if r.URL != "" {
+				line += "  " + ui.RenderMuted(r.URL)
+			}
+			fmt.Println(line)
+		}
+		fmt.Println()
+	},
+}
+
+// formatCIReport renders a report as the comment that stores it.
+func formatCIReport(r *CIReport) string {
+	text := "CI " + r.Status
+	if r.URL != "" {
+		text += ": " + r.URL
+	}
+	if r.Job != "" {
+		text += "\nJob: " + r.Job
+	}
+	if r.Commit != "" {
+		text += "\nCommit: " + r.Commit
+	}
+	return text
+}
+
+// parseCIReport reads a report back from its comment, or returns nil for
+// other comments.
+func parseCIReport(c *types.Comment) *CIReport {
+	lines := strings.Split(c.Text, "\n")
+	m := ciReportLine.FindStringSubmatch(lines[0])
+	if m == nil {
+		return nil
+	}
+	r := &CIReport{IssueID: c.IssueID, Status: m[1], URL: m[2], At: c.CreatedAt, Reporter: c.Author}
+	for _, line := range lines[1:] {
+		key, value, _ := strings.Cut(line, ": ")
+		switch key {
+		case "Job":
+			r.Job = value
+		case "Commit":
+			r.Commit = value
+		}
+	}
+	return r
+}
+
+// ciStatusIcon is the status glyph of a build outcome.
+func ciStatusIcon(status string) string {
+	switch status {
+	case ciPassed:
+		return ui.RenderPass("✓")
+	case ciFailed, ciError:
+		return ui.RenderFail("✗")
+	case ciRunning:
+		return ui.RenderWarn("●")
+	}
+	return ui.RenderMuted("○")
+}
+
+func init() {
+	ciReportCmd.Flags().StringSlice("issue", nil, "Issue(s) the build verifies (repeatable or comma-separated)")
+	ciReportCmd.Flags().String("status", "", "Build status: passed, failed, error, canceled, running")
+	ciReportCmd.Flags().String("url", "", "Link to the build")
+	ciReportCmd.Flags().String("job", "", "Job or pipeline name")
+	ciReportCmd.Flags().String("commit", "", "Commit the build ran on")
+	ciReportCmd.Flags().Bool("reopen", false, "Reopen closed issues whose build failed")
+	ciStatusCmd.ValidArgsFunction = issueIDCompletion
+	ciCmd.AddCommand(ciReportCmd)
+	ciCmd.AddCommand(ciStatusCmd)
+	rootCmd.AddCommand(ciCmd)
+}
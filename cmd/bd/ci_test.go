@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestCIReportRoundTrip(t *testing.T) {
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, r := range []*CIReport{
+		{Status: ciFailed, URL: "https://ci.example.com/b/1", Job: "test linux", Commit: "4f2a9c1"},
+		{Status: ciPassed},
+		{Status: ciRunning, URL: "https://ci.example.com/b/2"},
+	} {
+		c := &types.Comment{IssueID: "bd-1", Author: "ci", Text: formatCIReport(r), CreatedAt: at}
+		got := parseCIReport(c)
+		if got == nil || got.Status != r.Status || got.URL != r.URL || got.Job != r.Job || got.Commit != r.Commit ||
+			got.IssueID != "bd-1" || got.Reporter != "ci" || !got.At.Equal(at) {
+			t.Errorf("round trip of %q = %+v", c.Text, got)
+		}
+	}
+	for _, text := range []string{"CI is flaky again", "CI exploded: x", "Locked: release"} {
+		if r := parseCIReport(&types.Comment{Text: text}); r != nil {
+			t.Errorf("parseCIReport(%q) = %+v, want nil", text, r)
+		}
+	}
+}
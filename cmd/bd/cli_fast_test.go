@@ -11,7 +11,6 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
-	"sync"
 	"testing"
 	"time"
 )
@@ -25,10 +24,6 @@ import (
 //   - After: in-process tests take <1 second each, ~10x faster
 //   - End-to-end test (TestCLI_EndToEnd) still validates binary with exec.Command
 
-var (
-	inProcessMutex sync.Mutex // Protects concurrent access to rootCmd and global state
-)
-
 // setupCLITestDB creates a fresh initialized bd database for CLI tests
 func setupCLITestDB(t *testing.T) string {
 	t.Helper()
@@ -70,101 +65,12 @@ func createTempDirWithCleanup(t *testing.T) string {
 // This is ~10-20x faster than exec.Command because it avoids process spawn overhead
 func runBDInProcess(t *testing.T, dir string, args ...string) string {
 	t.Helper()
-	
-	// Serialize all in-process test execution to avoid race conditions
-	// rootCmd, cobra state, and viper are not thread-safe
-	inProcessMutex.Lock()
-	defer inProcessMutex.Unlock()
-	
-	// Add --no-daemon to all commands except init
-	if len(args) > 0 && args[0] != "init" {
-		args = append([]string{"--no-daemon"}, args...)
-	}
-	
-	// Save original state
-	oldStdout := os.Stdout
-	oldStderr := os.Stderr
-	oldDir, _ := os.Getwd()
-	oldArgs := os.Args
-	
-	// Change to test directory
-	if err := os.Chdir(dir); err != nil {
-		t.Fatalf("Failed to chdir to %s: %v", dir, err)
-	}
-	
-	// Capture stdout/stderr
-	rOut, wOut, _ := os.Pipe()
-	rErr, wErr, _ := os.Pipe()
-	os.Stdout = wOut
-	os.Stderr = wErr
-	
-	// Set args for rootCmd
-	rootCmd.SetArgs(args)
-	os.Args = append([]string{"bd"}, args...)
-	
-	// Set environment
-	os.Setenv("BEADS_NO_DAEMON", "1")
-	defer os.Unsetenv("BEADS_NO_DAEMON")
-	
-	// Execute command
-	err := rootCmd.Execute()
-	
-	// Close and clean up all global state to prevent contamination between tests
-	if store != nil {
-		store.Close()
-		store = nil
-	}
-	if daemonClient != nil {
-		daemonClient.Close()
-		daemonClient = nil
-	}
-	
-	// Reset all global flags and state
-	dbPath = ""
-	actor = ""
-	jsonOutput = false
-	noDaemon = false
-	noAutoFlush = false
-	noAutoImport = false
-	sandboxMode = false
-	noDb = false
-	autoFlushEnabled = true
-	storeActive = false
-	flushFailureCount = 0
-	lastFlushError = nil
-	// Shutdown any existing FlushManager
-	if flushManager != nil {
-		_ = flushManager.Shutdown()
-		flushManager = nil
-	}
-	// Reset context state
-	rootCtx = nil
-	rootCancel = nil
-	
-	// Give SQLite time to release file locks before cleanup
-	time.Sleep(10 * time.Millisecond)
-	
-	// Close writers and restore
-	wOut.Close()
-	wErr.Close()
-	os.Stdout = oldStdout
-	os.Stderr = oldStderr
-	os.Chdir(oldDir)
-	os.Args = oldArgs
-	rootCmd.SetArgs(nil)
-	
-	// Read output (keep stdout and stderr separate)
-	var outBuf, errBuf bytes.Buffer
-	outBuf.ReadFrom(rOut)
-	errBuf.ReadFrom(rErr)
-	
-	stdout := outBuf.String()
-	stderr := errBuf.String()
-	
+
+	stdout, stderr, err := runBDInProcessAllowError(t, dir, args...)
 	if err != nil {
 		t.Fatalf("bd %v failed: %v\nStdout: %s\nStderr: %s", args, err, stdout, stderr)
 	}
-	
+
 	// Return only stdout (stderr contains warnings that break JSON parsing)
 	return stdout
 }
@@ -698,78 +604,9 @@ func TestCLI_Reopen(t *testing.T) {
 func runBDInProcessAllowError(t *testing.T, dir string, args ...string) (string, string, error) {
 	t.Helper()
 
-	inProcessMutex.Lock()
-	defer inProcessMutex.Unlock()
-
-	if len(args) > 0 && args[0] != "init" {
-		args = append([]string{"--no-daemon"}, args...)
-	}
-
-	oldStdout := os.Stdout
-	oldStderr := os.Stderr
-	oldDir, _ := os.Getwd()
-	oldArgs := os.Args
-
-	if err := os.Chdir(dir); err != nil {
-		t.Fatalf("Failed to chdir to %s: %v", dir, err)
-	}
-
-	rOut, wOut, _ := os.Pipe()
-	rErr, wErr, _ := os.Pipe()
-	os.Stdout = wOut
-	os.Stderr = wErr
-
-	rootCmd.SetArgs(args)
-	os.Args = append([]string{"bd"}, args...)
-
-	os.Setenv("BEADS_NO_DAEMON", "1")
-	defer os.Unsetenv("BEADS_NO_DAEMON")
-
-	cmdErr := rootCmd.Execute()
-
-	if store != nil {
-		store.Close()
-		store = nil
-	}
-	if daemonClient != nil {
-		daemonClient.Close()
-		daemonClient = nil
-	}
-
-	dbPath = ""
-	actor = ""
-	jsonOutput = false
-	noDaemon = false
-	noAutoFlush = false
-	noAutoImport = false
-	sandboxMode = false
-	noDb = false
-	autoFlushEnabled = true
-	storeActive = false
-	flushFailureCount = 0
-	lastFlushError = nil
-	if flushManager != nil {
-		_ = flushManager.Shutdown()
-		flushManager = nil
-	}
-	rootCtx = nil
-	rootCancel = nil
-
-	time.Sleep(10 * time.Millisecond)
-
-	wOut.Close()
-	wErr.Close()
-	os.Stdout = oldStdout
-	os.Stderr = oldStderr
-	os.Chdir(oldDir)
-	os.Args = oldArgs
-	rootCmd.SetArgs(nil)
-
 	var outBuf, errBuf bytes.Buffer
-	outBuf.ReadFrom(rOut)
-	errBuf.ReadFrom(rErr)
-
-	return outBuf.String(), errBuf.String(), cmdErr
+	err := runInProcess(dir, args, &outBuf, &errBuf)
+	return outBuf.String(), errBuf.String(), err
 }
 
 // TestCLI_CreateDryRun tests the --dry-run flag for bd create command (bd-nib2)
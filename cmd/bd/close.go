@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/beads/internal/hooks"
+	"github.com/steveyegge/beads/internal/i18n"
 	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/ui"
@@ -25,6 +26,12 @@ create, update, show, or close operation).`,
 	Run: func(cmd *cobra.Command, args []string) {
 		CheckReadonly("close")
 
+		// Bulk selection by query expression (bd query)
+		args, ok := appendQueryMatches(cmd, args, "status!=closed")
+		if !ok {
+			return
+		}
+
 		// If no IDs provided, use last touched issue
 		if len(args) == 0 {
 			lastTouched := GetLastTouchedID()
@@ -148,7 +155,7 @@ create, update, show, or close operation).`,
 							}
 						}
 						if !jsonOutput {
-							fmt.Printf("%s Closed %s: %s\n", ui.RenderPass("✓"), id, reason)
+							fmt.Printf("%s %s\n", ui.RenderPass("✓"), i18n.T("Closed %s: %s", id, reason))
 							// Display newly unblocked issues
 							if len(result.Unblocked) > 0 {
 								fmt.Printf("\nNewly unblocked:\n")
@@ -170,7 +177,7 @@ create, update, show, or close operation).`,
 						}
 					}
 					if !jsonOutput {
-						fmt.Printf("%s Closed %s: %s\n", ui.RenderPass("✓"), id, reason)
+						fmt.Printf("%s %s\n", ui.RenderPass("✓"), i18n.T("Closed %s: %s", id, reason))
 					}
 				}
 			}
@@ -228,7 +235,7 @@ create, update, show, or close operation).`,
 						closedIssues = append(closedIssues, closedIssue)
 					}
 				} else {
-					fmt.Printf("%s Closed %s: %s\n", ui.RenderPass("✓"), result.ResolvedID, reason)
+					fmt.Printf("%s %s\n", ui.RenderPass("✓"), i18n.T("Closed %s: %s", result.ResolvedID, reason))
 				}
 				result.Close()
 			}
@@ -291,7 +298,7 @@ create, update, show, or close operation).`,
 					closedIssues = append(closedIssues, closedIssue)
 				}
 			} else {
-				fmt.Printf("%s Closed %s: %s\n", ui.RenderPass("✓"), id, reason)
+				fmt.Printf("%s %s\n", ui.RenderPass("✓"), i18n.T("Closed %s: %s", id, reason))
 			}
 		}
 
@@ -350,7 +357,7 @@ create, update, show, or close operation).`,
 					closedIssues = append(closedIssues, closedIssue)
 				}
 			} else {
-				fmt.Printf("%s Closed %s: %s\n", ui.RenderPass("✓"), result.ResolvedID, reason)
+				fmt.Printf("%s %s\n", ui.RenderPass("✓"), i18n.T("Closed %s: %s", result.ResolvedID, reason))
 			}
 			result.Close()
 		}
@@ -414,6 +421,7 @@ func init() {
 	closeCmd.Flags().Bool("no-auto", false, "With --continue, show next step but don't claim it")
 	closeCmd.Flags().Bool("suggest-next", false, "Show newly unblocked issues after closing")
 	closeCmd.Flags().String("session", "", "Claude Code session ID (or set CLAUDE_SESSION_ID env var)")
+	closeCmd.Flags().String("query", "", "Also close every open issue matching this query expression (see 'bd query --help')")
 	closeCmd.ValidArgsFunction = issueIDCompletion
 	rootCmd.AddCommand(closeCmd)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/beads/internal/beads"
 	"github.com/steveyegge/beads/internal/compact"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/ui"
 )
 
 var (
@@ -359,6 +360,15 @@ func runCompactSingle(ctx context.Context, compactor *compact.Compactor, store *
 	markDirtyAndScheduleFlush()
 }
 
+// compactProgress reports batch compaction on progress. The compactor's
+// total only counts eligible issues, so it replaces the candidate count.
+func compactProgress(progress *ui.Progress) func(done, total int) {
+	return func(done, total int) {
+		progress.SetTotal(total)
+		progress.Set(done)
+	}
+}
+
 func runCompactAll(ctx context.Context, compactor *compact.Compactor, store *sqlite.SQLiteStorage) {
 	start := time.Now()
 
@@ -429,7 +439,10 @@ func runCompactAll(ctx context.Context, compactor *compact.Compactor, store *sql
 		fmt.Printf("Compacting %d issues (Tier %d)...\n\n", len(candidates), compactTier)
 	}
 
+	progress := newProgress("Compacting", len(candidates))
+	compactor.SetProgress(compactProgress(progress))
 	results, err := compactor.CompactTier1Batch(ctx, candidates)
+	progress.Finish()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: batch compaction failed: %v\n", err)
 		os.Exit(1)
@@ -440,11 +453,7 @@ func runCompactAll(ctx context.Context, compactor *compact.Compactor, store *sql
 	totalSaved := 0
 	totalOriginal := 0
 
-	for i, result := range results {
-		if !jsonOutput {
-			fmt.Printf("[%s] %d/%d\r", progressBar(i+1, len(results)), i+1, len(results))
-		}
-
+	for _, result := range results {
 		if result.Err != nil {
 			failCount++
 		} else {
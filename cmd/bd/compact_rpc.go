@@ -7,24 +7,6 @@ import (
 	"os"
 )
 
-func progressBar(current, total int) string {
-	const width = 40
-	if total == 0 {
-		return "[" + string(make([]byte, width)) + "]"
-	}
-	filled := (current * width) / total
-	bar := ""
-	for i := 0; i < width; i++ {
-		if i < filled {
-			bar += "█"
-		} else {
-			bar += " "
-		}
-	}
-	return "[" + bar + "]"
-}
-
-//nolint:unparam // ctx may be used in future for cancellation
 func runCompactRPC(_ context.Context) {
 	if compactID != "" && compactAll {
 		fmt.Fprintf(os.Stderr, "Error: cannot use --id and --all together\n")
@@ -1,15 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
 )
 
 func TestCompactSuite(t *testing.T) {
@@ -307,21 +310,20 @@ func TestCompactValidation(t *testing.T) {
 	}
 }
 
-func TestCompactProgressBar(t *testing.T) {
-	// Test progress bar formatting
-	pb := progressBar(50, 100)
-	if len(pb) == 0 {
-		t.Error("Progress bar should not be empty")
-	}
+func TestCompactProgress(t *testing.T) {
+	var buf bytes.Buffer
+	progress := ui.NewProgress(&buf, "Compacting", 5, true)
+	report := compactProgress(progress)
 
-	pb = progressBar(100, 100)
-	if len(pb) == 0 {
-		t.Error("Full progress bar should not be empty")
+	// Only 2 of the 5 candidates were eligible
+	report(1, 2)
+	report(2, 2)
+	if !strings.HasSuffix(buf.String(), "Compacting [████████████████████████] 2/2 100%") {
+		t.Errorf("progress output = %q", buf.String())
 	}
-
-	pb = progressBar(0, 100)
-	if len(pb) == 0 {
-		t.Error("Zero progress bar should not be empty")
+	progress.Finish()
+	if !strings.HasSuffix(buf.String(), "\r\033[K") {
+		t.Errorf("Finish did not clear the line: %q", buf.String())
 	}
 }
 
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// Components are sub-projects inside one database. An issue's component is
+// the label component:<name>, following the <dimension>:<value> convention
+// of bd state, so every label filter and query already understands it.
+const componentLabelPrefix = "component:"
+
+var componentNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// componentLabel returns the label that puts an issue in component name.
+func componentLabel(name string) string {
+	return componentLabelPrefix + name
+}
+
+// validateComponentName rejects names that would make awkward labels.
+func validateComponentName(name string) error {
+	if !componentNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid component %q (use lowercase letters, digits, '.', '_' and '-')", name)
+	}
+	return nil
+}
+
+// issueComponents returns the components among labels.
+func issueComponents(labels []string) []string {
+	var names []string
+	for _, label := range labels {
+		if name, ok := strings.CutPrefix(label, componentLabelPrefix); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// applyComponent puts a new issue in component name and fills in the
+// component's defaults from config.yaml:
+//
+//	components:
+//	  api:
+//	    assignee: alice
+//	    labels: [backend]
+//
+// An explicit assignee wins over the default; default labels are added to
+// the explicit ones.
+func applyComponent(name, assignee string, labels []string) (string, []string) {
+	if name == "" {
+		return assignee, labels
+	}
+	if assignee == "" {
+		assignee = config.GetString("components." + name + ".assignee")
+	}
+	have := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		have[label] = true
+	}
+	for _, label := range append(config.GetStringSlice("components."+name+".labels"), componentLabel(name)) {
+		if !have[label] {
+			labels = append(labels, label)
+			have[label] = true
+		}
+	}
+	return assignee, labels
+}
+
+// configuredComponents returns the component names declared in config.yaml.
+func configuredComponents() []string {
+	components, _ := config.AllSettings()["components"].(map[string]interface{})
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	return names
+}
+
+// ComponentSummary is one row of bd component list.
+type ComponentSummary struct {
+	Name            string   `json:"name"`
+	Total           int      `json:"total"`
+	Open            int      `json:"open"`
+	InProgress      int      `json:"in_progress"`
+	Blocked         int      `json:"blocked"`
+	Closed          int      `json:"closed"`
+	DefaultAssignee string   `json:"default_assignee,omitempty"`
+	DefaultLabels   []string `json:"default_labels,omitempty"`
+}
+
+var componentCmd = &cobra.Command{
+	Use:     "component",
+	GroupID: "issues",
+	Short:   "Group issues into components (sub-projects) within one database",
+	Long: `Components split one database into sub-projects without separate databases.
+
+An issue's component is stored as the label component:<name>, so it syncs,
+filters and queries like any other label.
+
+Scoped commands:
+  bd create "Fix login" --component api     # New issue in component api
+  bd list --component api                   # Issues in a component
+  bd ready --component api                  # Ready work in a component
+  bd status --component api                 # Counts for a component
+
+Per-component defaults for new issues live in .beads/config.yaml:
+
+  components:
+    api:
+      assignee: alice          # used when --assignee isn't given
+      labels: [backend]        # added to every new issue
+
+Examples:
+  bd component list                 # Components with issue counts
+  bd component set web bd-12 bd-13  # Move issues into component web
+  bd component set --clear bd-12    # Take an issue out of its component`,
+}
+
+var componentListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List components with issue counts and defaults",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+		var issues []*types.Issue
+		if daemonClient != nil {
+			resp, err := daemonClient.List(&rpc.ListArgs{Expr: "label~" + componentLabelPrefix})
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if err := json.Unmarshal(resp.Data, &issues); err != nil {
+				FatalErrorRespectJSON("parsing response: %v", err)
+			}
+		} else {
+			var err error
+			issues, err = store.SearchIssues(ctx, "", types.IssueFilter{Query: parseQueryFlag("label~" + componentLabelPrefix)})
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			ids := make([]string, len(issues))
+			for i, issue := range issues {
+				ids[i] = issue.ID
+			}
+			labels, err := store.GetLabelsForIssues(ctx, ids)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			for _, issue := range issues {
+				issue.Labels = labels[issue.ID]
+			}
+		}
+
+		summaries := make(map[string]*ComponentSummary)
+		summary := func(name string) *ComponentSummary {
+			s := summaries[name]
+			if s == nil {
+				s = &ComponentSummary{
+					Name:            name,
+					DefaultAssignee: config.GetString("components." + name + ".assignee"),
+					DefaultLabels:   config.GetStringSlice("components." + name + ".labels"),
+				}
+				summaries[name] = s
+			}
+			return s
+		}
+		for _, name := range configuredComponents() {
+			summary(name)
+		}
+		for _, issue := range issues {
+			for _, name := range issueComponents(issue.Labels) {
+				s := summary(name)
+				s.Total++
+				switch issue.Status {
+				case types.StatusOpen:
+					s.Open++
+				case types.StatusInProgress:
+					s.InProgress++
+				case types.StatusBlocked:
+					s.Blocked++
+				case types.StatusClosed:
+					s.Closed++
+				}
+			}
+		}
+
+		result := make([]*ComponentSummary, 0, len(summaries))
+		for _, s := range summaries {
+			result = append(result, s)
+		}
+		sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		if len(result) == 0 {
+			fmt.Println("\nNo components found (use 'bd create --component <name>' to start one)")
+			return
+		}
+		fmt.Printf("\n%s Components (%d):\n", ui.RenderAccent("📦"), len(result))
+		maxLen := 0
+		for _, s := range result {
+			maxLen = max(maxLen, len(s.Name))
+		}
+		for _, s := range result {
+			line := fmt.Sprintf("  %-*s  %3d open  %3d in progress  %3d blocked  %3d closed",
+				maxLen, s.Name, s.Open, s.InProgress, s.Blocked, s.Closed)
+			if s.DefaultAssignee != "" {
+				line += ui.RenderMuted("  → " + s.DefaultAssignee)
+			}
+			fmt.Println(line)
+		}
+		fmt.Println()
+	},
+}
+
+var componentSetCmd = &cobra.Command{
+	Use:   "set [component] <id...>",
+	Short: "Move issues into a component (or out of it with --clear)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("component set")
+		ctx := rootCtx
+		clear, _ := cmd.Flags().GetBool("clear")
+
+		var name string
+		if !clear {
+			if len(args) < 2 {
+				FatalErrorRespectJSON("usage: bd component set <component> <id...> (or --clear <id...>)")
+			}
+			name, args = args[0], args[1:]
+			if err := validateComponentName(name); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
+
+		type change struct {
+			ID        string   `json:"id"`
+			Component string   `json:"component,omitempty"`
+			Previous  []string `json:"previous,omitempty"`
+		}
+		var changes []change
+		for _, id := range args {
+			var fullID string
+			var labels []string
+			if daemonClient != nil {
+				resp, err := daemonClient.ResolveID(&rpc.ResolveIDArgs{ID: id})
+				if err != nil {
+					FatalErrorRespectJSON("resolving %s: %v", id, err)
+				}
+				if err := json.Unmarshal(resp.Data, &fullID); err != nil {
+					FatalErrorRespectJSON("unmarshaling resolved ID: %v", err)
+				}
+				resp, err = daemonClient.Show(&rpc.ShowArgs{ID: fullID})
+				if err != nil {
+					FatalErrorRespectJSON("%v", err)
+				}
+				var issue types.Issue
+				if err := json.Unmarshal(resp.Data, &issue); err != nil {
+					FatalErrorRespectJSON("parsing response: %v", err)
+				}
+				labels = issue.Labels
+			} else {
+				var err error
+				fullID, err = utils.ResolvePartialID(ctx, store, id)
+				if err != nil {
+					FatalErrorRespectJSON("resolving %s: %v", id, err)
+				}
+				labels, err = store.GetLabels(ctx, fullID)
+				if err != nil {
+					FatalErrorRespectJSON("%v", err)
+				}
+			}
+
+			previous := issueComponents(labels)
+			for _, old := range previous {
+				if old == name {
+					continue
+				}
+				if err := removeIssueLabel(fullID, componentLabel(old)); err != nil {
+					FatalErrorRespectJSON("removing %s from %s: %v", componentLabel(old), fullID, err)
+				}
+			}
+			if name != "" && !containsString(previous, name) {
+				if err := addIssueLabel(fullID, componentLabel(name)); err != nil {
+					FatalErrorRespectJSON("adding %s to %s: %v", componentLabel(name), fullID, err)
+				}
+			}
+			changes = append(changes, change{ID: fullID, Component: name, Previous: previous})
+		}
+
+		if daemonClient == nil {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(changes)
+			return
+		}
+		for _, c := range changes {
+			if name == "" {
+				fmt.Printf("%s Cleared component on %s\n", ui.RenderPass("✓"), c.ID)
+			} else {
+				fmt.Printf("%s %s → component %s\n", ui.RenderPass("✓"), c.ID, name)
+			}
+		}
+	},
+}
+
+// addIssueLabel adds a label through the daemon or the store.
+func addIssueLabel(id, label string) error {
+	if daemonClient != nil {
+		_, err := daemonClient.AddLabel(&rpc.LabelAddArgs{ID: id, Label: label})
+		return err
+	}
+	return store.AddLabel(rootCtx, id, label, actor)
+}
+
+// removeIssueLabel removes a label through the daemon or the store.
+func removeIssueLabel(id, label string) error {
+	if daemonClient != nil {
+		_, err := daemonClient.RemoveLabel(&rpc.LabelRemoveArgs{ID: id, Label: label})
+		return err
+	}
+	return store.RemoveLabel(rootCtx, id, label, actor)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	componentSetCmd.Flags().Bool("clear", false, "Remove the issues from their component")
+	componentSetCmd.ValidArgsFunction = issueIDCompletion
+	componentCmd.AddCommand(componentListCmd)
+	componentCmd.AddCommand(componentSetCmd)
+	rootCmd.AddCommand(componentCmd)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/config"
+)
+
+func TestApplyComponentDefaults(t *testing.T) {
+	if err := config.Initialize(); err != nil {
+		t.Fatalf("config.Initialize: %v", err)
+	}
+	config.Set("components.api.assignee", "alice")
+	config.Set("components.api.labels", []string{"backend"})
+	defer config.Set("components", nil)
+
+	assignee, labels := applyComponent("api", "", []string{"urgent", "backend"})
+	if assignee != "alice" {
+		t.Errorf("assignee = %q, want the component default", assignee)
+	}
+	if want := []string{"urgent", "backend", "component:api"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("labels = %v, want %v", labels, want)
+	}
+
+	assignee, labels = applyComponent("web", "bob", nil)
+	if assignee != "bob" || !reflect.DeepEqual(labels, []string{"component:web"}) {
+		t.Errorf("unconfigured component: assignee=%q labels=%v", assignee, labels)
+	}
+
+	if got := issueComponents([]string{"backend", "component:api", "state:x"}); !reflect.DeepEqual(got, []string{"api"}) {
+		t.Errorf("issueComponents = %v, want [api]", got)
+	}
+}
+
+func TestValidateComponentName(t *testing.T) {
+	for _, name := range []string{"api", "web-ui", "svc.v2", "a_b"} {
+		if err := validateComponentName(name); err != nil {
+			t.Errorf("validateComponentName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "API", "has space", "-lead", "x:y"} {
+		if err := validateComponentName(name); err == nil {
+			t.Errorf("validateComponentName(%q) should fail", name)
+		}
+	}
+}
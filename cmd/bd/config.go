@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/syncbranch"
+	"github.com/steveyegge/beads/internal/ui"
 )
 
 var configCmd = &cobra.Command{
@@ -48,19 +49,46 @@ Examples:
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "Set a configuration value",
-	Args:  cobra.ExactArgs(2),
-	Run: func(_ *cobra.Command, args []string) {
+	Long: `Set a configuration value.
+
+Keys and values are checked against the config schema: unknown keys (with
+a suggestion for likely typos), enum values, durations, integers and URLs
+are rejected. Setting a deprecated key sets its replacement instead.
+
+Examples:
+  bd config set jira.url "https://company.atlassian.net"
+  bd config set validation.on-create warn
+  bd config set custom.my_tool.flag on          # custom.* is free-form
+  bd config set experimental.thing 1 --force    # Skip validation`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
 		key := args[0]
 		value := args[1]
 
+		if r, ok := config.LookupRename(key); ok {
+			fmt.Fprintf(os.Stderr, "Warning: %s is deprecated; setting %s instead\n", key, r.New)
+			key = r.New
+		}
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			if err := config.ValidateValue(key, value); err != nil {
+				FatalErrorWithHint(err.Error(), "run 'bd config lint' to check existing settings, or use --force to set it anyway")
+			}
+		}
+
 		// Check if this is a yaml-only key (startup settings like no-db, no-daemon, etc.)
 		// These must be written to config.yaml, not SQLite, because they're read
 		// before the database is opened. (GH#536)
 		if config.IsYamlOnlyKey(key) {
+			if isLockConfigKey(key) {
+				requireLockAdmin("changing " + key)
+			}
 			if err := config.SetYamlConfig(key, value); err != nil {
 				fmt.Fprintf(os.Stderr, "Error setting config: %v\n", err)
 				os.Exit(1)
 			}
+			if isLockConfigKey(key) {
+				stopDaemonForLockConfig()
+			}
 
 			if jsonOutput {
 				outputJSON(map[string]interface{}{
@@ -103,6 +131,9 @@ var configSetCmd = &cobra.Command{
 		} else {
 			fmt.Printf("Set %s = %s\n", key, value)
 		}
+		if provider, ok := isSecretConfigKey(key); ok {
+			fmt.Fprintf(os.Stderr, "Warning: %s is a secret stored in the project database; 'bd auth login %s' keeps it out of the project instead\n", key, provider)
+		}
 	},
 }
 
@@ -230,6 +261,18 @@ func showConfigYAMLOverrides(dbConfig map[string]string) {
 		}
 	}
 
+	// Any other key can be overridden by a flag or BD_/BEADS_ env var
+	keys := make([]string, 0, len(dbConfig))
+	for k := range dbConfig {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if value, source, ok := config.Override(k); ok && value != dbConfig[k] {
+			overrides = append(overrides, fmt.Sprintf("  %s: database has '%s' but effective value is '%s' (from %s)", k, dbConfig[k], value, source))
+		}
+	}
+
 	if len(overrides) > 0 {
 		fmt.Println("\n⚠️  Config overrides (higher priority sources):")
 		for _, o := range overrides {
@@ -239,6 +282,269 @@ func showConfigYAMLOverrides(dbConfig map[string]string) {
 	}
 }
 
+var configExplainCmd = &cobra.Command{
+	Use:   "explain <key>",
+	Short: "Show which config layer each value of a key comes from",
+	Long: `Show every place a setting is defined, highest precedence first.
+
+Settings are layered: command-line flags, environment variables, the
+selected profile (--config-profile or BD_CONFIG_PROFILE), directory-local
+.bd.yaml files, the project .beads/config.yaml, the user config, the
+system config, the project database (bd config set) and finally the
+built-in default. The first entry is the value bd uses.
+
+Examples:
+  bd config explain actor
+  bd --config-profile work config explain jira.url`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key := args[0]
+		origins := explainConfigKey(cmd, key)
+
+		if jsonOutput {
+			result := map[string]interface{}{
+				"key":     key,
+				"profile": config.ActiveProfile(),
+				"layers":  origins,
+			}
+			if len(origins) > 0 {
+				result["value"] = origins[0].Value
+				result["source"] = origins[0].Layer
+			}
+			outputJSON(result)
+			return
+		}
+
+		if len(origins) == 0 {
+			fmt.Printf("%s is not set in any layer\n", key)
+			return
+		}
+		fmt.Printf("%s = %v\n", key, origins[0].Value)
+		if profile := config.ActiveProfile(); profile != "" {
+			fmt.Printf("Profile: %s\n", profile)
+		}
+		fmt.Println()
+		for i, o := range origins {
+			value := fmt.Sprint(o.Value)
+			if value == "" {
+				value = `""`
+			}
+			line := fmt.Sprintf("%-9s %-40s %s", o.Layer, o.Source, value)
+			if i == 0 {
+				fmt.Printf("  %s %s\n", ui.RenderPass("→"), line)
+			} else {
+				fmt.Printf("    %s\n", ui.RenderMuted(line+"  (overridden)"))
+			}
+		}
+	},
+}
+
+// explainConfigKey adds the layers the config package can't see, a
+// command-line flag and the project database, to config.Explain.
+func explainConfigKey(cmd *cobra.Command, key string) []config.Origin {
+	var origins []config.Origin
+	// Generated config flags are reported by config.Explain itself
+	if f := cmd.Flag(key); f != nil && f.Changed && configFlags[f] == "" {
+		origins = append(origins, config.Origin{Layer: "flag", Source: "--" + key, Value: f.Value.String()})
+	}
+	fileOrigins := config.Explain(key)
+	defaultOrigin := -1
+	if n := len(fileOrigins); n > 0 && fileOrigins[n-1].Layer == config.LayerDefault {
+		defaultOrigin = n - 1
+	}
+	for i, o := range fileOrigins {
+		if i != defaultOrigin {
+			origins = append(origins, o)
+		}
+	}
+
+	// Database config ranks below config.yaml and the environment. Read
+	// it raw: GetConfig already applies flag and environment overrides.
+	if !config.IsYamlOnlyKey(key) && ensureStoreActive() == nil && store != nil {
+		if all, err := store.GetAllConfig(rootCtx); err == nil && all[key] != "" {
+			value := all[key]
+			origins = append(origins, config.Origin{Layer: "database", Source: "bd config set", Value: value})
+		}
+	}
+	if defaultOrigin >= 0 {
+		origins = append(origins, fileOrigins[defaultOrigin])
+	}
+	return origins
+}
+
+var configLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check config files and project config against the schema",
+	Long: `Check every config file (system, user, project, .bd.yaml and their
+profiles) and the project database config for unknown keys, invalid
+values and deprecated keys.
+
+--fix migrates deprecated keys in the project database to their
+replacements. Deprecated keys in config files are reported with the key
+to rename them to; their values keep working meanwhile.
+
+Exits with status 1 if any problem remains.
+
+Examples:
+  bd config lint
+  bd config lint --fix
+  bd config lint --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fix, _ := cmd.Flags().GetBool("fix")
+		issues := config.LintFiles()
+
+		var dbConfig map[string]string
+		if ensureStoreActive() == nil && store != nil {
+			all, err := store.GetAllConfig(rootCtx)
+			if err != nil {
+				FatalErrorRespectJSON("reading project config: %v", err)
+			}
+			dbConfig = all
+		}
+		var migrated []string
+		if fix && dbConfig != nil {
+			CheckReadonly("config lint --fix")
+			var err error
+			if migrated, err = migrateRenamedConfig(dbConfig); err != nil {
+				FatalErrorRespectJSON("migrating deprecated keys: %v", err)
+			}
+		}
+		issues = append(issues, config.LintValues("database", dbConfig)...)
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"issues":   issues,
+				"migrated": migrated,
+			})
+		} else {
+			for _, m := range migrated {
+				fmt.Printf("%s Migrated %s\n", ui.RenderPass("✓"), m)
+			}
+			if len(issues) == 0 {
+				fmt.Printf("%s Config is valid\n", ui.RenderPass("✓"))
+				return
+			}
+			for _, issue := range issues {
+				fmt.Printf("%s %s: %s\n", ui.RenderWarn("!"), issue.Source, issue.Message)
+			}
+			fmt.Printf("\n%d problem(s)\n", len(issues))
+		}
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var configKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "List config keys with their types, environment variables and flags",
+	Long: `List every config key bd reads, with its type and the environment
+variables and global flag that override it.
+
+Precedence, highest first: flag, BD_<KEY>, BEADS_<KEY>, named profile,
+.bd.yaml, project config.yaml, user config, system config, project
+database (bd config set), default.
+
+Keys marked internal are written by bd itself. issue_prefix and
+allowed_prefixes identify the project's data and can't be overridden.
+
+Examples:
+  bd config keys
+  bd config keys --markdown   # the table in docs/CONFIG.md
+  BEADS_JIRA_URL=https://jira.example.com bd jira sync
+  bd --sync.mode=realtime sync`,
+	Run: func(cmd *cobra.Command, args []string) {
+		markdown, _ := cmd.Flags().GetBool("markdown")
+
+		type keyInfo struct {
+			Key         string   `json:"key"`
+			Type        string   `json:"type"`
+			Values      []string `json:"values,omitempty"`
+			Env         []string `json:"env,omitempty"`
+			Flag        string   `json:"flag,omitempty"`
+			Description string   `json:"description,omitempty"`
+		}
+		var keys []keyInfo
+		for _, spec := range config.Schema {
+			if spec.Internal {
+				continue
+			}
+			info := keyInfo{Key: spec.Key, Type: string(spec.Type), Values: spec.Values, Description: spec.Description}
+			if config.Overridable(spec.Key) && !strings.Contains(spec.Key, "*") {
+				info.Env = config.EnvNames(spec.Key)
+				info.Flag = "--" + config.FlagName(spec.Key)
+			}
+			keys = append(keys, info)
+		}
+
+		if jsonOutput {
+			outputJSON(keys)
+			return
+		}
+		if markdown {
+			fmt.Println("| Key | Type | Environment | Flag | Description |")
+			fmt.Println("|-----|------|-------------|------|-------------|")
+			for _, k := range keys {
+				typ := k.Type
+				if len(k.Values) > 0 {
+					typ = strings.Join(k.Values, " \\| ")
+				}
+				env, flag := "-", "-"
+				if len(k.Env) > 0 {
+					env = "`" + strings.Join(k.Env, "`, `") + "`"
+					flag = "`" + k.Flag + "`"
+				}
+				fmt.Printf("| `%s` | %s | %s | %s | %s |\n", k.Key, typ, env, flag, k.Description)
+			}
+			return
+		}
+		for _, k := range keys {
+			typ := k.Type
+			if len(k.Values) > 0 {
+				typ = strings.Join(k.Values, "|")
+			}
+			fmt.Printf("%s %s\n", k.Key, ui.RenderMuted("("+typ+")"))
+			if k.Description != "" {
+				fmt.Printf("  %s\n", k.Description)
+			}
+			if len(k.Env) > 0 {
+				fmt.Printf("  %s\n", ui.RenderMuted(strings.Join(append([]string{k.Flag}, k.Env...), ", ")))
+			}
+		}
+	},
+}
+
+// migrateRenamedConfig moves deprecated keys in the project database to
+// their replacements, removing them from all. It returns a description of
+// each migration.
+func migrateRenamedConfig(all map[string]string) ([]string, error) {
+	var migrated []string
+	for _, r := range config.Renames {
+		old, ok := all[r.Old]
+		if !ok {
+			continue
+		}
+		current, exists := all[r.New]
+		value := old
+		if exists {
+			value = current
+			if r.AnyTrue && old == "true" {
+				value = "true"
+			}
+		}
+		if err := store.SetConfig(rootCtx, r.New, value); err != nil {
+			return migrated, err
+		}
+		if err := store.DeleteConfig(rootCtx, r.Old); err != nil {
+			return migrated, err
+		}
+		all[r.New] = value
+		delete(all, r.Old)
+		migrated = append(migrated, fmt.Sprintf("%s -> %s = %s", r.Old, r.New, value))
+	}
+	return migrated, nil
+}
+
 var configUnsetCmd = &cobra.Command{
 	Use:   "unset <key>",
 	Short: "Delete a configuration value",
@@ -273,5 +579,11 @@ func init() {
 	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configListCmd)
 	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configExplainCmd)
+	configCmd.AddCommand(configLintCmd)
+	configCmd.AddCommand(configKeysCmd)
+	configSetCmd.Flags().Bool("force", false, "Skip schema validation")
+	configLintCmd.Flags().Bool("fix", false, "Migrate deprecated keys in the project database")
+	configKeysCmd.Flags().Bool("markdown", false, "Print a Markdown table")
 	rootCmd.AddCommand(configCmd)
 }
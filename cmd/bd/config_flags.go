@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/steveyegge/beads/internal/config"
+)
+
+// configFlags maps the global flags generated from the config schema to
+// their config keys.
+var configFlags = make(map[*pflag.Flag]string)
+
+// registerConfigFlags adds a hidden global flag for every config key that
+// has no flag of its own, named after the key: --jira.url, --sync.mode,
+// --flush-debounce. Run it after every other global flag is registered.
+func registerConfigFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	for _, spec := range config.Schema {
+		name := config.FlagName(spec.Key)
+		if strings.Contains(spec.Key, "*") || !config.Overridable(spec.Key) || flags.Lookup(name) != nil {
+			continue
+		}
+		if spec.Type == config.TypeBool {
+			flags.Bool(name, false, spec.Description)
+		} else {
+			flags.String(name, "", spec.Description)
+		}
+		_ = flags.MarkHidden(name)
+		configFlags[flags.Lookup(name)] = spec.Key
+	}
+}
+
+// applyConfigFlags validates the generated config flags given on the
+// command line and layers them over every other config source.
+func applyConfigFlags(cmd *cobra.Command) {
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		// A subcommand's own flag of the same name shadows ours
+		key, ok := configFlags[f]
+		if !ok {
+			return
+		}
+		value := f.Value.String()
+		if err := config.ValidateValue(key, value); err != nil {
+			FatalErrorWithHint(err.Error(), "run 'bd config keys' for the type of each key")
+		}
+		config.SetFlagOverride(key, value)
+	})
+}
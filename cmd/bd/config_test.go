@@ -224,3 +224,43 @@ func setupTestDB(t *testing.T) (*sqlite.SQLiteStorage, func()) {
 
 	return store, cleanup
 }
+
+func TestMigrateRenamedConfig(t *testing.T) {
+	testStore, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	origStore, origCtx := store, rootCtx
+	store, rootCtx = testStore, ctx
+	t.Cleanup(func() { store, rootCtx = origStore, origCtx })
+
+	for key, value := range map[string]string{
+		"daemon.auto_pull":   "true",
+		"daemon.auto_commit": "false",
+		"daemon.auto_push":   "true",
+	} {
+		if err := testStore.SetConfig(ctx, key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := testStore.GetAllConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrated, err := migrateRenamedConfig(all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrated) != 3 {
+		t.Errorf("migrated = %v, want 3 keys", migrated)
+	}
+	for key, want := range map[string]string{
+		"daemon.auto-pull":   "true",
+		"daemon.auto-sync":   "true",
+		"daemon.auto_commit": "",
+		"daemon.auto_push":   "",
+	} {
+		if got, _ := testStore.GetConfig(ctx, key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -872,7 +872,7 @@ func cookFormula(ctx context.Context, s storage.Storage, f *formula.Formula, pro
 		if issuesCreated {
 			cleanupErr := s.RunInTransaction(ctx, func(tx storage.Transaction) error {
 				for i := len(issues) - 1; i >= 0; i-- {
-					_ = tx.DeleteIssue(ctx, issues[i].ID) // Best effort cleanup
+					_ = tx.DeleteIssue(ctx, issues[i].ID, actor) // Best effort cleanup
 				}
 				return nil
 			})
@@ -970,7 +970,7 @@ func deleteProtoSubgraph(ctx context.Context, s storage.Storage, protoID string)
 	return s.RunInTransaction(ctx, func(tx storage.Transaction) error {
 		for i := len(subgraph.Issues) - 1; i >= 0; i-- {
 			issue := subgraph.Issues[i]
-			if err := tx.DeleteIssue(ctx, issue.ID); err != nil {
+			if err := tx.DeleteIssue(ctx, issue.ID, actor); err != nil {
 				return fmt.Errorf("delete %s: %w", issue.ID, err)
 			}
 		}
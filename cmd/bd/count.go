@@ -1,11 +1,9 @@
 package main
 
 import (
-	"cmp"
 	"encoding/json"
 	"fmt"
 	"os"
-	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -190,13 +188,7 @@ Examples:
 				}
 			} else {
 				// Grouped count
-				var result struct {
-					Total  int `json:"total"`
-					Groups []struct {
-						Group string `json:"group"`
-						Count int    `json:"count"`
-					} `json:"groups"`
-				}
+				var result types.IssueCounts
 				if err := json.Unmarshal(resp.Data, &result); err != nil {
 					fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
 					os.Exit(1)
@@ -205,14 +197,6 @@ Examples:
 				if jsonOutput {
 					outputJSON(result)
 				} else {
-					// Sort groups for consistent output
-					slices.SortFunc(result.Groups, func(a, b struct {
-						Group string `json:"group"`
-						Count int    `json:"count"`
-					}) int {
-						return cmp.Compare(a.Group, b.Group)
-					})
-
 					fmt.Printf("Total: %d\n\n", result.Total)
 					for _, g := range result.Groups {
 						fmt.Printf("%s: %d\n", g.Group, g.Count)
@@ -323,7 +307,7 @@ Examples:
 			filter.PriorityMax = &priorityMax
 		}
 
-		issues, err := store.SearchIssues(ctx, "", filter)
+		counts, err := store.CountIssues(ctx, "", filter, groupBy)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -334,89 +318,19 @@ Examples:
 			if jsonOutput {
 				result := struct {
 					Count int `json:"count"`
-				}{Count: len(issues)}
+				}{Count: counts.Total}
 				outputJSON(result)
 			} else {
-				fmt.Println(len(issues))
+				fmt.Println(counts.Total)
 			}
 			return
 		}
 
-		// Group by the specified field
-		counts := make(map[string]int)
-
-		// For label grouping, fetch all labels in one query to avoid N+1
-		var labelsMap map[string][]string
-		if groupBy == "label" {
-			issueIDs := make([]string, len(issues))
-			for i, issue := range issues {
-				issueIDs[i] = issue.ID
-			}
-			var err error
-			labelsMap, err = store.GetLabelsForIssues(ctx, issueIDs)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error getting labels: %v\n", err)
-				os.Exit(1)
-			}
-		}
-
-		for _, issue := range issues {
-			var groupKey string
-			switch groupBy {
-			case "status":
-				groupKey = string(issue.Status)
-			case "priority":
-				groupKey = fmt.Sprintf("P%d", issue.Priority)
-			case "type":
-				groupKey = string(issue.IssueType)
-			case "assignee":
-				if issue.Assignee == "" {
-					groupKey = "(unassigned)"
-				} else {
-					groupKey = issue.Assignee
-				}
-			case "label":
-				// For labels, count each label separately
-				labels := labelsMap[issue.ID]
-				if len(labels) > 0 {
-					for _, label := range labels {
-						counts[label]++
-					}
-					continue
-				} else {
-					groupKey = "(no labels)"
-				}
-			}
-			counts[groupKey]++
-		}
-
-		type GroupCount struct {
-			Group string `json:"group"`
-			Count int    `json:"count"`
-		}
-
-		groups := make([]GroupCount, 0, len(counts))
-		for group, count := range counts {
-			groups = append(groups, GroupCount{Group: group, Count: count})
-		}
-
-		// Sort for consistent output
-		slices.SortFunc(groups, func(a, b GroupCount) int {
-			return cmp.Compare(a.Group, b.Group)
-		})
-
 		if jsonOutput {
-			result := struct {
-				Total  int          `json:"total"`
-				Groups []GroupCount `json:"groups"`
-			}{
-				Total:  len(issues),
-				Groups: groups,
-			}
-			outputJSON(result)
+			outputJSON(counts)
 		} else {
-			fmt.Printf("Total: %d\n\n", len(issues))
-			for _, g := range groups {
+			fmt.Printf("Total: %d\n\n", counts.Total)
+			for _, g := range counts.Groups {
 				fmt.Printf("%s: %d\n", g.Group, g.Count)
 			}
 		}
@@ -465,3 +379,27 @@ func init() {
 
 	rootCmd.AddCommand(countCmd)
 }
+
+// outputIssueCounts prints the result of 'bd list --count': the bare total
+// (handy in shell prompts) or a group/count table, or the counts as JSON.
+func outputIssueCounts(counts *types.IssueCounts, groupBy string) {
+	if jsonOutput {
+		outputJSON(counts)
+		return
+	}
+	if groupBy == "" {
+		fmt.Println(counts.Total)
+		return
+	}
+
+	header := strings.ToUpper(groupBy)
+	width := len(header)
+	for _, g := range counts.Groups {
+		width = max(width, len(g.Group))
+	}
+	fmt.Printf("%-*s  %s\n", width, header, "COUNT")
+	for _, g := range counts.Groups {
+		fmt.Printf("%-*s  %5d\n", width, g.Group, g.Count)
+	}
+	fmt.Printf("%-*s  %5d\n", width, "TOTAL", counts.Total)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/debug"
 	"github.com/steveyegge/beads/internal/hooks"
+	"github.com/steveyegge/beads/internal/i18n"
 	"github.com/steveyegge/beads/internal/routing"
 	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/storage"
@@ -48,6 +49,16 @@ var createCmd = &cobra.Command{
 			return
 		}
 
+		// If batch flag is provided, create issues from a JSONL/YAML stream
+		if batch, _ := cmd.Flags().GetString("batch"); batch != "" {
+			if len(args) > 0 {
+				FatalError("cannot specify both title and --batch flag")
+			}
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			createIssuesFromBatch(batch, dryRun)
+			return
+		}
+
 		// Original single-issue creation logic
 		// Get title from flag or positional argument
 		titleFlag, _ := cmd.Flags().GetString("title")
@@ -112,6 +123,25 @@ var createCmd = &cobra.Command{
 			labels = append(labels, labelAlias...)
 		}
 
+		// Components are component:<name> labels plus configured defaults
+		if component, _ := cmd.Flags().GetString("component"); component != "" {
+			if err := validateComponentName(component); err != nil {
+				FatalError("%v", err)
+			}
+			assignee, labels = applyComponent(component, assignee, labels)
+		}
+
+		// Keyword rules (labels.auto) label the issue from its text
+		if noAuto, _ := cmd.Flags().GetBool("no-auto-labels"); !noAuto {
+			labels = append(labels, autoLabels(&types.Issue{
+				Title:              title,
+				Description:        description,
+				Design:             design,
+				AcceptanceCriteria: acceptance,
+				Notes:              notes,
+			}, labels)...)
+		}
+
 		explicitID, _ := cmd.Flags().GetString("id")
 		parentID, _ := cmd.Flags().GetString("parent")
 		externalRef, _ := cmd.Flags().GetString("external-ref")
@@ -281,6 +311,11 @@ var createCmd = &cobra.Command{
 			estimatedMinutes = &est
 		}
 
+		// Repro metadata (--repro-*), validated before anything is created
+		issueRepro := createRepro(cmd.Flags())
+		issueCC := createCC(cmd.Flags())
+		issueVisibility := createVisibility(cmd.Flags())
+
 		// Validate template based on --validate flag or config
 		validateTemplate, _ := cmd.Flags().GetBool("validate")
 		if validateTemplate {
@@ -303,6 +338,14 @@ var createCmd = &cobra.Command{
 			}
 		}
 
+		// Project content rules (validation.* in config.yaml) are enforced
+		// when the issue is written
+		warnValidationConfig()
+		var externalRefPtr *string
+		if externalRef != "" {
+			externalRefPtr = &externalRef
+		}
+
 		// Use global jsonOutput set by PersistentPreRun
 
 		// Determine target repository using routing logic
@@ -447,11 +490,6 @@ var createCmd = &cobra.Command{
 			}
 		}
 
-		var externalRefPtr *string
-		if externalRef != "" {
-			externalRefPtr = &externalRef
-		}
-
 		// If daemon is running, use RPC
 		if daemonClient != nil {
 			createArgs := &rpc.CreateArgs{
@@ -483,6 +521,7 @@ var createCmd = &cobra.Command{
 				EventPayload:       eventPayload,
 				DueAt:              formatTimeForRPC(dueAt),
 				DeferUntil:         formatTimeForRPC(deferUntil),
+				Force:              forceCreate,
 			}
 
 			resp, err := daemonClient.Create(createArgs)
@@ -496,17 +535,21 @@ var createCmd = &cobra.Command{
 				FatalError("parsing response: %v", err)
 			}
 
+			setCreatedCC(issue.ID, issueCC)
+			setCreatedVisibility(issue.ID, issueVisibility)
+
 			// Run create hook
 			if hookRunner != nil {
 				hookRunner.Run(hooks.EventCreate, &issue)
 			}
+			setCreatedRepro(issue.ID, issueRepro)
 
 			if jsonOutput {
 				fmt.Println(string(resp.Data))
 			} else if silent {
 				fmt.Println(issue.ID)
 			} else {
-				fmt.Printf("%s Created issue: %s\n", ui.RenderPass("✓"), issue.ID)
+				fmt.Printf("%s %s\n", ui.RenderPass("✓"), i18n.T("Created issue: %s", issue.ID))
 				fmt.Printf("  Title: %s\n", issue.Title)
 				fmt.Printf("  Priority: P%d\n", issue.Priority)
 				fmt.Printf("  Status: %s\n", issue.Status)
@@ -529,6 +572,7 @@ var createCmd = &cobra.Command{
 			Priority:           priority,
 			IssueType:          types.IssueType(issueType).Normalize(),
 			Assignee:           assignee,
+			Labels:             labels, // Added below; set so the validation rules see them
 			ExternalRef:        externalRefPtr,
 			EstimatedMinutes:   estimatedMinutes,
 			Ephemeral:          wisp,
@@ -546,6 +590,9 @@ var createCmd = &cobra.Command{
 		}
 
 		ctx := rootCtx
+		if forceCreate {
+			ctx = validation.WithForce(ctx, actor)
+		}
 
 		// Check if any dependencies are discovered-from type
 		// If so, inherit source_repo from the parent issue
@@ -706,17 +753,23 @@ var createCmd = &cobra.Command{
 		// Schedule auto-flush
 		markDirtyAndScheduleFlush()
 
+		setCreatedCC(issue.ID, issueCC)
+		setCreatedVisibility(issue.ID, issueVisibility)
+
 		// Run create hook
 		if hookRunner != nil {
 			hookRunner.Run(hooks.EventCreate, issue)
 		}
+		setCreatedRepro(issue.ID, issueRepro)
+		issue.Repro = issueRepro
+		issue.CC = issueCC
 
 		if jsonOutput {
 			outputJSON(issue)
 		} else if silent {
 			fmt.Println(issue.ID)
 		} else {
-			fmt.Printf("%s Created issue: %s\n", ui.RenderPass("✓"), issue.ID)
+			fmt.Printf("%s %s\n", ui.RenderPass("✓"), i18n.T("Created issue: %s", issue.ID))
 			fmt.Printf("  Title: %s\n", issue.Title)
 			fmt.Printf("  Priority: P%d\n", issue.Priority)
 			fmt.Printf("  Status: %s\n", issue.Status)
@@ -732,6 +785,7 @@ var createCmd = &cobra.Command{
 
 func init() {
 	createCmd.Flags().StringP("file", "f", "", "Create multiple issues from markdown file")
+	createCmd.Flags().String("batch", "", "Create issues atomically from a JSONL/YAML file of specs ('-' for stdin); specs can reference each other by key")
 	createCmd.Flags().String("title", "", "Issue title (alternative to positional argument)")
 	createCmd.Flags().Bool("silent", false, "Output only the issue ID (for scripting)")
 	createCmd.Flags().Bool("dry-run", false, "Preview what would be created without actually creating")
@@ -741,12 +795,14 @@ func init() {
 	createCmd.Flags().StringSliceP("labels", "l", []string{}, "Labels (comma-separated)")
 	createCmd.Flags().StringSlice("label", []string{}, "Alias for --labels")
 	_ = createCmd.Flags().MarkHidden("label") // Only fails if flag missing (caught in tests)
+	createCmd.Flags().Bool("no-auto-labels", false, "Don't apply the labels.auto keyword rules")
+	createCmd.Flags().String("component", "", "Component (sub-project) to create the issue in; applies its configured default assignee and labels")
 	createCmd.Flags().String("id", "", "Explicit issue ID (e.g., 'bd-42' for partitioning)")
 	createCmd.Flags().String("parent", "", "Parent issue ID for hierarchical child (e.g., 'bd-a3f8e9')")
 	createCmd.Flags().StringSlice("deps", []string{}, "Dependencies in format 'type:id' or 'id' (e.g., 'discovered-from:bd-20,blocks:bd-15' or 'bd-20')")
 	createCmd.Flags().String("waits-for", "", "Spawner issue ID to wait for (creates waits-for dependency for fanout gate)")
 	createCmd.Flags().String("waits-for-gate", "all-children", "Gate type: all-children (wait for all) or any-children (wait for first)")
-	createCmd.Flags().Bool("force", false, "Force creation even if prefix doesn't match database prefix")
+	createCmd.Flags().Bool("force", false, "Force creation even if prefix doesn't match database prefix or the issue breaks validation rules")
 	createCmd.Flags().String("repo", "", "Target repository for issue (overrides auto-routing)")
 	createCmd.Flags().String("rig", "", "Create issue in a different rig (e.g., --rig beads)")
 	createCmd.Flags().String("prefix", "", "Create issue in rig by prefix (e.g., --prefix bd- or --prefix bd or --prefix beads)")
@@ -754,6 +810,9 @@ func init() {
 	createCmd.Flags().Bool("ephemeral", false, "Create as ephemeral (ephemeral, not exported to JSONL)")
 	createCmd.Flags().String("mol-type", "", "Molecule type: swarm (multi-polecat), patrol (recurring ops), work (default)")
 	createCmd.Flags().Bool("validate", false, "Validate description contains required sections for issue type")
+	addReproFlags(createCmd, "repro-")
+	createCmd.Flags().String("visibility", "internal", "Who may see the issue: public, internal or private (never leaves this database)")
+	createCmd.Flags().StringSlice("cc", nil, "Stakeholders to keep informed, names or email addresses (comma-separated)")
 	// Agent-specific flags (only valid when --type=agent)
 	createCmd.Flags().String("role-type", "", "Agent role type: polecat|crew|witness|refinery|mayor|deacon (requires --type=agent)")
 	createCmd.Flags().String("agent-rig", "", "Agent's rig name (requires --type=agent)")
@@ -863,6 +922,7 @@ func createInRig(cmd *cobra.Command, rigName, title, description, issueType stri
 		Priority:           priority,
 		IssueType:          types.IssueType(issueType).Normalize(),
 		Assignee:           assignee,
+		Labels:             labels, // Added below; set so the validation rules see them
 		ExternalRef:        externalRefPtr,
 		Ephemeral:          wisp,
 		CreatedBy:          getActorWithGit(),
@@ -883,6 +943,9 @@ func createInRig(cmd *cobra.Command, rigName, title, description, issueType stri
 		PrefixOverride: prefixOverride,
 	}
 
+	if force, _ := cmd.Flags().GetBool("force"); force {
+		ctx = validation.WithForce(ctx, actor)
+	}
 	if err := targetStore.CreateIssue(ctx, issue, actor); err != nil {
 		FatalError("failed to create issue in rig %q: %v", rigName, err)
 	}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/hooks"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/timeparsing"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+	"github.com/steveyegge/beads/internal/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// batchIssueSpec describes one issue in a `bd create --batch` stream.
+// Key is a temporary name that other specs in the same batch can use in
+// parent/deps before real IDs exist.
+type batchIssueSpec struct {
+	Key                string   `json:"key,omitempty" yaml:"key,omitempty"`
+	ID                 string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Title              string   `json:"title" yaml:"title"`
+	Description        string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Design             string   `json:"design,omitempty" yaml:"design,omitempty"`
+	AcceptanceCriteria string   `json:"acceptance_criteria,omitempty" yaml:"acceptance_criteria,omitempty"`
+	Notes              string   `json:"notes,omitempty" yaml:"notes,omitempty"`
+	Type               string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Priority           *string  `json:"priority,omitempty" yaml:"priority,omitempty"` // "1" or "P1"
+	Assignee           string   `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+	Labels             []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Parent             string   `json:"parent,omitempty" yaml:"parent,omitempty"` // Key or existing issue ID
+	Deps               []string `json:"deps,omitempty" yaml:"deps,omitempty"`     // "key", "type:key", or existing IDs
+	Estimate           *int     `json:"estimate,omitempty" yaml:"estimate,omitempty"`
+	Due                string   `json:"due,omitempty" yaml:"due,omitempty"`
+	Defer              string   `json:"defer,omitempty" yaml:"defer,omitempty"`
+	ExternalRef        string   `json:"external_ref,omitempty" yaml:"external_ref,omitempty"`
+}
+
+// UnmarshalJSON accepts priority as either a number or a string ("P1").
+func (s *batchIssueSpec) UnmarshalJSON(data []byte) error {
+	type plain batchIssueSpec
+	var raw struct {
+		plain
+		Priority json.RawMessage `json:"priority,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = batchIssueSpec(raw.plain)
+	if len(raw.Priority) > 0 && string(raw.Priority) != "null" {
+		p := strings.Trim(string(raw.Priority), `"`)
+		s.Priority = &p
+	}
+	return nil
+}
+
+// batchDep is a dependency edge whose target is a batch key or existing ID.
+type batchDep struct {
+	Type   types.DependencyType
+	Target string
+}
+
+// parseBatchDep parses "target" or "type:target" (default type: blocks).
+func parseBatchDep(spec string) (batchDep, error) {
+	spec = strings.TrimSpace(spec)
+	if depType, target, ok := strings.Cut(spec, ":"); ok {
+		dt := types.DependencyType(strings.TrimSpace(depType))
+		if dt.IsValid() {
+			return batchDep{Type: dt, Target: strings.TrimSpace(target)}, nil
+		}
+	}
+	if spec == "" {
+		return batchDep{}, fmt.Errorf("empty dependency")
+	}
+	return batchDep{Type: types.DepBlocks, Target: spec}, nil
+}
+
+// parseBatchSpecs reads issue specs as JSONL (one object per line), a JSON
+// array, or YAML (a list, or a document with an "issues" list).
+func parseBatchSpecs(r io.Reader) ([]*batchIssueSpec, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("no issue specs in input")
+	}
+
+	var specs []*batchIssueSpec
+	switch trimmed[0] {
+	case '[':
+		if err := json.Unmarshal(trimmed, &specs); err != nil {
+			return nil, fmt.Errorf("parsing JSON array: %w", err)
+		}
+	case '{':
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var spec batchIssueSpec
+			if err := json.Unmarshal(line, &spec); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			specs = append(specs, &spec)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	default:
+		var doc struct {
+			Issues []*batchIssueSpec `yaml:"issues"`
+		}
+		if err := yaml.Unmarshal(trimmed, &specs); err != nil {
+			if err2 := yaml.Unmarshal(trimmed, &doc); err2 != nil {
+				return nil, fmt.Errorf("parsing YAML: %w", err)
+			}
+			specs = doc.Issues
+		}
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no issue specs in input")
+	}
+	return specs, nil
+}
+
+// batchPlan is a validated batch ready to be written.
+type batchPlan struct {
+	Specs  []*batchIssueSpec
+	Issues []*types.Issue    // Parallel to Specs
+	Deps   [][]batchDep      // Parallel to Specs (parent included as parent-child)
+	Keys   map[string]int    // Batch key -> index
+	IDs    map[string]string // Existing-issue reference -> resolved ID
+}
+
+// planBatch validates specs and resolves references to existing issues.
+// Nothing is written.
+func planBatch(ctx context.Context, specs []*batchIssueSpec, now time.Time) (*batchPlan, error) {
+	plan := &batchPlan{
+		Specs: specs,
+		Keys:  make(map[string]int),
+		IDs:   make(map[string]string),
+	}
+	for i, spec := range specs {
+		if spec.Key == "" {
+			continue
+		}
+		if _, dup := plan.Keys[spec.Key]; dup {
+			return nil, fmt.Errorf("duplicate key %q", spec.Key)
+		}
+		plan.Keys[spec.Key] = i
+	}
+
+	resolve := func(ref string) error {
+		if _, ok := plan.Keys[ref]; ok {
+			return nil
+		}
+		if _, ok := plan.IDs[ref]; ok {
+			return nil
+		}
+		id, err := utils.ResolvePartialID(ctx, store, ref)
+		if err != nil {
+			return fmt.Errorf("%q is neither a key in this batch nor an existing issue", ref)
+		}
+		plan.IDs[ref] = id
+		return nil
+	}
+
+	for i, spec := range specs {
+		where := fmt.Sprintf("issue %d", i+1)
+		if spec.Key != "" {
+			where = fmt.Sprintf("issue %q", spec.Key)
+		}
+		if strings.TrimSpace(spec.Title) == "" {
+			return nil, fmt.Errorf("%s: title is required", where)
+		}
+
+		priority := 2
+		if spec.Priority != nil {
+			p, err := validation.ValidatePriority(*spec.Priority)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", where, err)
+			}
+			priority = p
+		}
+		issueType := types.TypeTask
+		if spec.Type != "" {
+			issueType = types.IssueType(spec.Type).Normalize()
+		}
+		issue := &types.Issue{
+			ID:                 spec.ID,
+			Title:              spec.Title,
+			Description:        spec.Description,
+			Design:             spec.Design,
+			AcceptanceCriteria: spec.AcceptanceCriteria,
+			Notes:              spec.Notes,
+			Status:             types.StatusOpen,
+			Priority:           priority,
+			IssueType:          issueType,
+			Assignee:           spec.Assignee,
+			EstimatedMinutes:   spec.Estimate,
+			CreatedBy:          getActorWithGit(),
+			Owner:              getOwner(),
+		}
+		if spec.ExternalRef != "" {
+			ref := spec.ExternalRef
+			issue.ExternalRef = &ref
+		}
+		if spec.Due != "" {
+			t, err := timeparsing.ParseRelativeTime(spec.Due, now)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid due %q", where, spec.Due)
+			}
+			issue.DueAt = &t
+		}
+		if spec.Defer != "" {
+			t, err := timeparsing.ParseRelativeTime(spec.Defer, now)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid defer %q", where, spec.Defer)
+			}
+			issue.DeferUntil = &t
+		}
+
+		var deps []batchDep
+		if spec.Parent != "" {
+			deps = append(deps, batchDep{Type: types.DepParentChild, Target: spec.Parent})
+		}
+		for _, d := range spec.Deps {
+			dep, err := parseBatchDep(d)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", where, err)
+			}
+			deps = append(deps, dep)
+		}
+		for _, dep := range deps {
+			if dep.Target == spec.Key && spec.Key != "" {
+				return nil, fmt.Errorf("%s: cannot depend on itself", where)
+			}
+			if err := resolve(dep.Target); err != nil {
+				return nil, fmt.Errorf("%s: %w", where, err)
+			}
+		}
+
+		plan.Issues = append(plan.Issues, issue)
+		plan.Deps = append(plan.Deps, deps)
+	}
+	return plan, nil
+}
+
+// apply writes the batch in a single transaction: all issues first (so keys
+// get real IDs), then labels and dependencies. Any failure rolls back.
+func (p *batchPlan) apply(ctx context.Context) error {
+	return store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		for i, issue := range p.Issues {
+			issue.Labels = p.Specs[i].Labels // So the validation rules see them
+			if err := tx.CreateIssue(ctx, issue, actor); err != nil {
+				return fmt.Errorf("creating %q: %w", p.Specs[i].Title, err)
+			}
+		}
+		for i, issue := range p.Issues {
+			for _, label := range p.Specs[i].Labels {
+				if err := tx.AddLabel(ctx, issue.ID, label, actor); err != nil {
+					return fmt.Errorf("adding label %s to %s: %w", label, issue.ID, err)
+				}
+			}
+			for _, dep := range p.Deps[i] {
+				target := p.IDs[dep.Target]
+				if idx, ok := p.Keys[dep.Target]; ok {
+					target = p.Issues[idx].ID
+				}
+				if err := tx.AddDependency(ctx, &types.Dependency{
+					IssueID:     issue.ID,
+					DependsOnID: target,
+					Type:        dep.Type,
+				}, actor); err != nil {
+					return fmt.Errorf("adding dependency %s -> %s: %w", issue.ID, target, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// createIssuesFromBatch implements `bd create --batch <file|->`.
+func createIssuesFromBatch(source string, dryRun bool) {
+	if err := ensureDirectMode("create --batch requires direct database access"); err != nil {
+		FatalError("%v", err)
+	}
+	if actor == "" {
+		actor = "bd"
+	}
+	ctx := rootCtx
+
+	var r io.Reader = os.Stdin
+	if source != "-" {
+		f, err := os.Open(source) // #nosec G304 -- user-specified batch file
+		if err != nil {
+			FatalError("opening batch file: %v", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	specs, err := parseBatchSpecs(r)
+	if err != nil {
+		FatalError("%v", err)
+	}
+	plan, err := planBatch(ctx, specs, time.Now())
+	if err != nil {
+		FatalError("%v", err)
+	}
+
+	if dryRun {
+		if jsonOutput {
+			outputJSON(specs)
+			return
+		}
+		fmt.Printf("Would create %d issue(s):\n", len(plan.Issues))
+		for i, issue := range plan.Issues {
+			key := plan.Specs[i].Key
+			if key == "" {
+				key = "-"
+			}
+			fmt.Printf("  %-12s %s [P%d, %s]\n", key, issue.Title, issue.Priority, issue.IssueType)
+		}
+		return
+	}
+
+	if err := plan.apply(ctx); err != nil {
+		FatalError("batch create failed, nothing was created: %v", err)
+	}
+	markDirtyAndScheduleFlush()
+	if hookRunner != nil {
+		for _, issue := range plan.Issues {
+			hookRunner.Run(hooks.EventCreate, issue)
+		}
+	}
+
+	if jsonOutput {
+		keys := make(map[string]string, len(plan.Keys))
+		for key, idx := range plan.Keys {
+			keys[key] = plan.Issues[idx].ID
+		}
+		outputJSON(map[string]interface{}{
+			"created": plan.Issues,
+			"keys":    keys,
+		})
+		return
+	}
+	fmt.Printf("%s Created %d issues:\n", ui.RenderPass("✓"), len(plan.Issues))
+	for i, issue := range plan.Issues {
+		key := ""
+		if k := plan.Specs[i].Key; k != "" {
+			key = ui.RenderMuted(" (" + k + ")")
+		}
+		fmt.Printf("  %s: %s [P%d, %s]%s\n", issue.ID, issue.Title, issue.Priority, issue.IssueType, key)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParseBatchSpecs(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"jsonl", `{"key":"a","title":"One","priority":1}
+{"key":"b","title":"Two","priority":"P0","deps":["a"]}`},
+		{"json array", `[{"key":"a","title":"One","priority":1},{"key":"b","title":"Two","priority":"P0","deps":["a"]}]`},
+		{"yaml list", `- key: a
+  title: One
+  priority: 1
+- key: b
+  title: Two
+  priority: P0
+  deps: [a]`},
+		{"yaml document", `issues:
+  - key: a
+    title: One
+    priority: 1
+  - key: b
+    title: Two
+    priority: P0
+    deps: [a]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, err := parseBatchSpecs(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("parseBatchSpecs: %v", err)
+			}
+			if len(specs) != 2 {
+				t.Fatalf("expected 2 specs, got %d", len(specs))
+			}
+			if specs[0].Priority == nil || *specs[0].Priority != "1" {
+				t.Errorf("unexpected priority: %v", specs[0].Priority)
+			}
+			if specs[1].Priority == nil || *specs[1].Priority != "P0" {
+				t.Errorf("unexpected priority: %v", specs[1].Priority)
+			}
+			if len(specs[1].Deps) != 1 || specs[1].Deps[0] != "a" {
+				t.Errorf("unexpected deps: %v", specs[1].Deps)
+			}
+		})
+	}
+
+	if _, err := parseBatchSpecs(strings.NewReader("  \n")); err == nil {
+		t.Error("expected error for empty input")
+	}
+}
+
+func TestParseBatchDep(t *testing.T) {
+	tests := map[string]batchDep{
+		"a":                 {Type: types.DepBlocks, Target: "a"},
+		"related:a":         {Type: types.DepRelated, Target: "a"},
+		"discovered-from:b": {Type: types.DepDiscoveredFrom, Target: "b"},
+	}
+	for in, want := range tests {
+		got, err := parseBatchDep(in)
+		if err != nil || got != want {
+			t.Errorf("parseBatchDep(%q) = %+v, %v; want %+v", in, got, err, want)
+		}
+	}
+}
+
+func TestBatchPlanApply(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	oldStore := store
+	defer func() { store = oldStore }()
+	store = s
+
+	existing := &types.Issue{Title: "Existing", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := s.CreateIssue(ctx, existing, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	specs, err := parseBatchSpecs(strings.NewReader(`{"key":"epic","title":"Epic","type":"epic"}
+{"key":"a","title":"A","parent":"epic","labels":["x"]}
+{"key":"b","title":"B","parent":"epic","deps":["a","related:` + existing.ID + `"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := planBatch(ctx, specs, time.Now())
+	if err != nil {
+		t.Fatalf("planBatch: %v", err)
+	}
+	if err := plan.apply(ctx); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	epicID := plan.Issues[plan.Keys["epic"]].ID
+	aID := plan.Issues[plan.Keys["a"]].ID
+	bID := plan.Issues[plan.Keys["b"]].ID
+
+	deps, err := s.GetDependencyRecords(ctx, bID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]types.DependencyType{}
+	for _, d := range deps {
+		found[d.DependsOnID] = d.Type
+	}
+	if found[epicID] != types.DepParentChild || found[aID] != types.DepBlocks || found[existing.ID] != types.DepRelated {
+		t.Errorf("unexpected dependencies for %s: %v", bID, found)
+	}
+	labels, _ := s.GetLabels(ctx, aID)
+	if len(labels) != 1 || labels[0] != "x" {
+		t.Errorf("unexpected labels: %v", labels)
+	}
+}
+
+func TestPlanBatchErrors(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	oldStore := store
+	defer func() { store = oldStore }()
+	store = s
+
+	tests := map[string]string{
+		"duplicate key":   `[{"key":"a","title":"A"},{"key":"a","title":"B"}]`,
+		"missing title":   `[{"key":"a"}]`,
+		"unknown ref":     `[{"key":"a","title":"A","deps":["nope"]}]`,
+		"self dependency": `[{"key":"a","title":"A","deps":["a"]}]`,
+		"bad priority":    `[{"title":"A","priority":"P9"}]`,
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			specs, err := parseBatchSpecs(strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := planBatch(ctx, specs, time.Now()); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
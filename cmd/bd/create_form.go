@@ -10,6 +10,7 @@ import (
 
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/i18n"
 	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/types"
@@ -390,7 +391,7 @@ func runCreateForm(cmd *cobra.Command) {
 }
 
 func printCreatedIssue(issue *types.Issue) {
-	fmt.Printf("\n%s Created issue: %s\n", ui.RenderPass("✓"), issue.ID)
+	fmt.Printf("\n%s %s\n", ui.RenderPass("✓"), i18n.T("Created issue: %s", issue.ID))
 	fmt.Printf("  Title:    %s\n", issue.Title)
 	fmt.Printf("  Type:     %s\n", issue.IssueType)
 	fmt.Printf("  Priority: P%d\n", issue.Priority)
@@ -38,6 +38,7 @@ The daemon will:
 Common operations:
   bd daemon start                Start the daemon (background)
   bd daemon start --foreground   Start in foreground (for systemd/supervisord)
+  bd daemon install              Run as a user service (systemd, launchd, Windows)
   bd daemon stop                 Stop current workspace daemon
   bd daemon status               Show daemon status
   bd daemon status --all         Show all daemons with health check
@@ -512,6 +513,9 @@ func runDaemonLoop(interval time.Duration, autoCommit, autoPush, autoPull, local
 	if err != nil {
 		return
 	}
+	startFeedServer(serverCtx, store, log)
+	startAPIServer(serverCtx, store, server, log)
+	startScheduler(serverCtx, store, beadsDir, log)
 
 	// Choose event loop based on BEADS_DAEMON_MODE (need to determine early for SetConfig)
 	daemonMode := os.Getenv("BEADS_DAEMON_MODE")
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/beads/internal/api"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/httpx"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage"
+)
+
+// startAPIServer serves the HTTP API (GraphQL at /graphql, its mutations
+// applied by the RPC server, and the RPC server's mutation events at
+// /events) on api.listen until ctx is canceled. It does nothing when api.listen is unset; a listener that fails
+// is logged without stopping the daemon.
+func startAPIServer(ctx context.Context, s storage.Storage, server *rpc.Server, log daemonLogger) {
+	addr := config.GetString("api.listen")
+	if addr == "" {
+		return
+	}
+	oidc, err := apiOIDC(ctx)
+	if err != nil {
+		// Serving without it could show internal issues to anyone
+		log.Warn("API server not started", "addr", addr, "error", err)
+		return
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Warn("API server not started", "addr", addr, "error", err)
+		return
+	}
+	opts := api.Options{
+		Token:          config.GetString("api.token"),
+		Events:         server,
+		Writer:         server,
+		OIDC:           oidc,
+		RateLimits:     apiRateLimits(log),
+		AuditRetention: config.GetDuration("api.audit-retention"),
+	}
+	handler := api.Handler(s, opts)
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+		// Write the access log entries still buffered
+		if c, ok := handler.(io.Closer); ok {
+			_ = c.Close()
+		}
+	}()
+	go func() {
+		log.Info("serving HTTP API", "url", "http://"+ln.Addr().String()+"/graphql")
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("API server error", "error", err)
+		}
+	}()
+}
+
+// apiOIDC sets up single sign-on from the api.oidc.* keys, or returns nil
+// when api.oidc.issuer is unset.
+func apiOIDC(ctx context.Context) (*api.OIDC, error) {
+	issuer := config.GetString("api.oidc.issuer")
+	if issuer == "" {
+		return nil, nil
+	}
+	roles, err := api.ParseRoles(config.GetString("api.oidc.roles"))
+	if err != nil {
+		return nil, fmt.Errorf("api.oidc.roles: %w", err)
+	}
+	return api.NewOIDC(ctx, api.OIDCConfig{
+		Issuer:        issuer,
+		ClientID:      config.GetString("api.oidc.client-id"),
+		ClientSecret:  config.GetString("api.oidc.client-secret"),
+		RedirectURL:   config.GetString("api.oidc.redirect-url"),
+		GroupsClaim:   config.GetString("api.oidc.groups-claim"),
+		Roles:         roles,
+		DefaultRole:   config.GetString("api.oidc.default-role"),
+		SessionSecret: []byte(config.GetString("api.oidc.session-secret")),
+	})
+}
+
+// apiRateLimits reads api.rate-limits, e.g.
+//
+//	api:
+//	  rate-limits:
+//	    ci-agent: 10/s
+//	    "*": 300/m
+func apiRateLimits(log daemonLogger) map[string]float64 {
+	limits := make(map[string]float64)
+	for name, raw := range config.GetStringMapString("api.rate-limits") {
+		perSecond, err := httpx.ParseRate(raw)
+		if err != nil {
+			log.Warn("ignoring API rate limit", "key", "api.rate-limits."+name, "error", err)
+			continue
+		}
+		limits[name] = perSecond
+	}
+	return limits
+}
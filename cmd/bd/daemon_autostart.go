@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/beads/internal/lockfile"
 	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/ui"
+	"golang.org/x/mod/semver"
 )
 
 // daemonShutdownTimeout is how long to wait for graceful shutdown before force killing.
@@ -164,6 +165,32 @@ func restartDaemonForVersionMismatch() bool {
 	return false
 }
 
+// daemonUpgradeSide returns which side of an incompatible connection needs
+// upgrading: rpc.UpgradeDaemon or rpc.UpgradeClient. Daemons from before
+// protocol negotiation don't say, so their version is compared instead.
+func daemonUpgradeSide(health *rpc.HealthResponse) string {
+	if health.Upgrade != "" {
+		return health.Upgrade
+	}
+	daemonVer, clientVer := "v"+strings.TrimPrefix(health.Version, "v"), "v"+strings.TrimPrefix(Version, "v")
+	if semver.IsValid(daemonVer) && semver.IsValid(clientVer) && semver.Compare(daemonVer, clientVer) > 0 {
+		return rpc.UpgradeClient
+	}
+	return rpc.UpgradeDaemon
+}
+
+// versionMismatchDetail explains an incompatible daemon and what to do.
+func versionMismatchDetail(health *rpc.HealthResponse, upgrade string, restartFailed bool) string {
+	switch {
+	case upgrade == rpc.UpgradeClient:
+		return fmt.Sprintf("bd %s is older than the running daemon (%s); upgrade bd to use the daemon", Version, health.Version)
+	case restartFailed:
+		return fmt.Sprintf("daemon %s is older than bd %s and restarting it failed; run 'bd daemons killall'", health.Version, Version)
+	default:
+		return fmt.Sprintf("daemon %s is older than bd %s; run 'bd daemons killall' or set daemon.auto_upgrade: true", health.Version, Version)
+	}
+}
+
 // isDaemonRunningQuiet checks if daemon is running without output
 func isDaemonRunningQuiet(pidFile string) bool {
 	isRunning, _ := isDaemonRunningFn(pidFile)
@@ -527,6 +554,9 @@ func emitVerboseWarning() {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to auto-start daemon. Running in direct mode. Hint: bd daemon status\n")
 	case FallbackDaemonUnsupported:
 		fmt.Fprintf(os.Stderr, "Warning: Daemon does not support this command yet. Running in direct mode. Hint: update daemon or use local mode.\n")
+	case FallbackVersionMismatch:
+		// Already warned with what to upgrade
+		return
 	case FallbackWorktreeSafety:
 		// Don't warn - this is expected behavior. User can configure sync-branch to enable daemon.
 		return
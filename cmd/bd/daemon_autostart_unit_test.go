@@ -14,6 +14,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/rpc"
 )
 
 func tempSockDir(t *testing.T) string {
@@ -290,6 +291,7 @@ func TestDaemonAutostart_EmitVerboseWarning(t *testing.T) {
 		{FallbackAutoStartDisabled, true},
 		{FallbackAutoStartFailed, true},
 		{FallbackDaemonUnsupported, true},
+		{FallbackVersionMismatch, false},
 		{FallbackWorktreeSafety, false},
 		{FallbackFlagNoDaemon, false},
 	} {
@@ -588,3 +590,24 @@ func TestIsWispOperation(t *testing.T) {
 		})
 	}
 }
+
+func TestDaemonUpgradeSide(t *testing.T) {
+	oldVersion := Version
+	defer func() { Version = oldVersion }()
+	Version = "0.30.0"
+
+	for _, tt := range []struct {
+		health rpc.HealthResponse
+		want   string
+	}{
+		{rpc.HealthResponse{Version: "0.31.0", Upgrade: rpc.UpgradeDaemon}, rpc.UpgradeDaemon},
+		// Daemons from before negotiation don't say; compare versions
+		{rpc.HealthResponse{Version: "0.29.0"}, rpc.UpgradeDaemon},
+		{rpc.HealthResponse{Version: "1.0.0"}, rpc.UpgradeClient},
+		{rpc.HealthResponse{Version: "dev"}, rpc.UpgradeDaemon},
+	} {
+		if got := daemonUpgradeSide(&tt.health); got != tt.want {
+			t.Errorf("daemonUpgradeSide(%+v) = %q, want %q", tt.health, got, tt.want)
+		}
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
+	"sync/atomic"
 	"time"
 
 	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/refs"
 	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
 )
 
 // DefaultRemoteSyncInterval is the default interval for periodic remote sync.
@@ -85,6 +89,7 @@ func runEventDrivenLoop(
 					return
 				}
 				log.log("Mutation detected: %s %s", event.Type, event.IssueID)
+				runDaemonAutomation(ctx, store, event, log)
 				exportDebouncer.Trigger()
 
 			case <-ctx.Done():
@@ -116,6 +121,47 @@ func runEventDrivenLoop(
 		log.log("Auto-pull disabled: use 'git pull' manually to sync remote changes")
 	}
 
+	// Periodic external ref liveness check (bd ref check --all), off by
+	// default; configure via refs.check-interval
+	var refCheckTicker *time.Ticker
+	if interval := config.GetDuration("refs.check-interval"); interval > 0 {
+		if interval < time.Minute {
+			log.log("Warning: refs.check-interval too low (%v), using minimum 1m", interval)
+			interval = time.Minute
+		}
+		refCheckTicker = time.NewTicker(interval)
+		defer refCheckTicker.Stop()
+		log.log("Ref checks enabled: checking external refs every %v", interval)
+	}
+	var refCheckRunning atomic.Bool
+
+	// Periodic refresh of linked pull request statuses (bd pr), off by
+	// default; configure via pr.refresh-interval
+	var prRefreshTicker *time.Ticker
+	if interval := config.GetDuration("pr.refresh-interval"); interval > 0 {
+		if interval < time.Minute {
+			log.log("Warning: pr.refresh-interval too low (%v), using minimum 1m", interval)
+			interval = time.Minute
+		}
+		prRefreshTicker = time.NewTicker(interval)
+		defer prRefreshTicker.Stop()
+		log.log("PR refresh enabled: fetching linked pull requests every %v", interval)
+	}
+	var prRefreshRunning atomic.Bool
+
+	// Periodic evaluation of condition gates (bd dep add --until-cmd/
+	// --until-url); configure via gates.check-interval
+	var conditionTicker *time.Ticker
+	if interval := config.GetDuration("gates.check-interval"); interval > 0 {
+		if interval < 10*time.Second {
+			log.log("Warning: gates.check-interval too low (%v), using minimum 10s", interval)
+			interval = 10 * time.Second
+		}
+		conditionTicker = time.NewTicker(interval)
+		defer conditionTicker.Stop()
+	}
+	var conditionCheckRunning atomic.Bool
+
 	// Parent process check (every 10 seconds)
 	parentCheckTicker := time.NewTicker(10 * time.Second)
 	defer parentCheckTicker.Stop()
@@ -151,6 +197,52 @@ func runEventDrivenLoop(
 			log.log("Periodic remote sync: checking for updates")
 			doAutoImport()
 
+		case <-func() <-chan time.Time {
+			if refCheckTicker != nil {
+				return refCheckTicker.C
+			}
+			return make(chan time.Time)
+		}():
+			// Network checks can be slow; run them off the loop, one at a time
+			if refCheckRunning.CompareAndSwap(false, true) {
+				go func() {
+					defer refCheckRunning.Store(false)
+					if checkDaemonRefs(ctx, store, jsonlPath, log) {
+						exportDebouncer.Trigger()
+					}
+				}()
+			}
+
+		case <-func() <-chan time.Time {
+			if prRefreshTicker != nil {
+				return prRefreshTicker.C
+			}
+			return make(chan time.Time)
+		}():
+			// Statuses are a local cache, so there is nothing to export
+			if prRefreshRunning.CompareAndSwap(false, true) {
+				go func() {
+					defer prRefreshRunning.Store(false)
+					refreshDaemonPRs(ctx, store, jsonlPath, log)
+				}()
+			}
+
+		case <-func() <-chan time.Time {
+			if conditionTicker != nil {
+				return conditionTicker.C
+			}
+			return make(chan time.Time)
+		}():
+			// Commands and URLs can be slow; run them off the loop, one at a time
+			if conditionCheckRunning.CompareAndSwap(false, true) {
+				go func() {
+					defer conditionCheckRunning.Store(false)
+					if checkDaemonConditions(ctx, store, jsonlPath, log) {
+						exportDebouncer.Trigger()
+					}
+				}()
+			}
+
 		case <-parentCheckTicker.C:
 			// Check if parent process is still alive
 			if !checkParentProcessAlive(parentPID) {
@@ -259,6 +351,34 @@ func checkDaemonHealth(ctx context.Context, store storage.Storage, log daemonLog
 	}
 }
 
+// checkDaemonRefs re-checks every issue's external refs, updating the
+// ref:dead labels. Returns true if any issue had refs to check.
+func checkDaemonRefs(ctx context.Context, store storage.Storage, jsonlPath string, log daemonLogger) bool {
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		log.log("Ref check failed: %v", err)
+		return false
+	}
+	if err := refs.Populate(ctx, store, issues); err != nil {
+		log.log("Ref check failed: %v", err)
+		return false
+	}
+	// Doc refs are relative to the repository holding .beads/
+	docRoot := filepath.Dir(filepath.Dir(jsonlPath))
+	results, err := refs.CheckIssues(ctx, store, newRefChecker(ctx, store, docRoot), issues, "daemon")
+	if err != nil {
+		log.log("Ref check failed: %v", err)
+	}
+	dead := 0
+	for _, r := range results {
+		if r.Dead {
+			dead++
+		}
+	}
+	log.log("Ref check: %d issue(s) checked, %d with dead refs", len(results), dead)
+	return len(results) > 0
+}
+
 // getRemoteSyncInterval returns the interval for periodic remote sync.
 // Configuration sources (in order of precedence):
 //  1. BEADS_REMOTE_SYNC_INTERVAL environment variable
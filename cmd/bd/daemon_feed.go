@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/beads/internal/calendar"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/feed"
+	"github.com/steveyegge/beads/internal/storage"
+)
+
+// startFeedServer serves the activity feed and the calendar over HTTP on
+// feed.listen until ctx is canceled. It does nothing when feed.listen is
+// unset; a listener that fails is logged without stopping the daemon. With
+// feed.token set, only requests carrying the token see internal issues.
+func startFeedServer(ctx context.Context, s storage.Storage, log daemonLogger) {
+	addr := config.GetString("feed.listen")
+	if addr == "" {
+		return
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Warn("feed server not started", "addr", addr, "error", err)
+		return
+	}
+	name := "beads"
+	if prefix, _ := s.GetConfig(ctx, "issue_prefix"); prefix != "" {
+		name = prefix
+	}
+	token := config.GetString("feed.token")
+	sprint, err := calendar.SprintEvent(config.GetString("sprint.start"), config.GetInt("sprint.length-days"))
+	if err != nil {
+		log.Warn("calendar served without sprints", "error", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", feed.Handler(s, name, token))
+	mux.Handle("/calendar.ics", calendar.Handler(s, token, sprint))
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		log.Info("serving activity feed", "url", "http://"+ln.Addr().String()+"/feed.atom",
+			"calendar", "http://"+ln.Addr().String()+"/calendar.ics")
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("feed server error", "error", err)
+		}
+	}()
+}
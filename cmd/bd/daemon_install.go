@@ -0,0 +1,349 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var daemonInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Run this workspace's daemon as a user service that starts at login",
+	Long: `Register the daemon for the current workspace with the OS service manager,
+so it starts at login (or boot) and restarts if it crashes, without shell
+profile hacks:
+
+  Linux    systemd user unit  ~/.config/systemd/user/<name>.service
+  macOS    launchd agent      ~/Library/LaunchAgents/<label>.plist
+  Windows  scheduled task     runs at logon under your account
+
+The service runs 'bd daemon start --foreground' with this workspace's
+database, so it uses the same socket as the CLI (.beads/bd.sock, or a short
+path under /tmp when that is too long). A daemon that is already running is
+stopped first so the service can take over. Installing again rewrites the
+service with the current binary and PATH.
+
+On Linux, user services only start at boot if lingering is enabled:
+  loginctl enable-linger $USER`,
+	Example: `  bd daemon install
+  bd daemon install --dry-run     # Show the service definition only
+  bd daemon uninstall`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		svc := currentDaemonService()
+		path, content, err := svc.definition(runtime.GOOS)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		steps := svc.installCommands(runtime.GOOS, path)
+
+		if dryRun {
+			if path != "" {
+				fmt.Printf("# %s\n%s\n", path, content)
+			}
+			for _, step := range steps {
+				fmt.Printf("$ %s\n", strings.Join(step, " "))
+			}
+			return
+		}
+
+		// Take down a previous installation and any running daemon so the
+		// service starts fresh with this binary
+		for _, step := range svc.uninstallCommands(runtime.GOOS, path) {
+			_, _ = runServiceCommand(step)
+		}
+		if pidFile, err := getPIDFilePath(); err == nil && isDaemonRunningQuiet(pidFile) {
+			stopDaemonQuiet(pidFile)
+		}
+		if path != "" {
+			if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+				FatalError("creating %s: %v", filepath.Dir(path), err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				FatalError("writing %s: %v", path, err)
+			}
+		}
+		for _, step := range steps {
+			if out, err := runServiceCommand(step); err != nil {
+				FatalErrorWithHint(fmt.Sprintf("%s failed: %v\n%s", step[0], err, strings.TrimSpace(out)),
+					"run 'bd daemon install --dry-run' to see the service definition")
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]string{"name": svc.Name, "file": path, "socket": getSocketPath()})
+			return
+		}
+		fmt.Printf("%s Installed daemon service %s\n", ui.RenderPass("✓"), ui.RenderAccent(svc.Name))
+		if path != "" {
+			fmt.Printf("  Service: %s\n", path)
+		}
+		fmt.Printf("  Socket:  %s\n", getSocketPath())
+		if runtime.GOOS == "linux" {
+			fmt.Printf("  %s\n", ui.RenderMuted("To start it at boot without logging in: loginctl enable-linger $USER"))
+		}
+	},
+}
+
+var daemonUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the user service installed by 'bd daemon install'",
+	Long: `Stop and remove this workspace's daemon service. The daemon itself is
+stopped too; it will auto-start again on the next bd command unless
+auto-start is disabled.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := currentDaemonService()
+		path, _, err := svc.definition(runtime.GOOS)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		// Best-effort: the service may already be stopped or half removed
+		for _, step := range svc.uninstallCommands(runtime.GOOS, path) {
+			_, _ = runServiceCommand(step)
+		}
+		if path != "" {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				FatalError("removing %s: %v", path, err)
+			}
+		}
+		if jsonOutput {
+			outputJSON(map[string]string{"removed": svc.Name})
+			return
+		}
+		fmt.Printf("%s Removed daemon service %s\n", ui.RenderPass("✓"), svc.Name)
+	},
+}
+
+// runServiceCommand runs a service manager command; replaced in tests.
+var runServiceCommand = func(argv []string) (string, error) {
+	out, err := exec.Command(argv[0], argv[1:]...).CombinedOutput() // #nosec G204 -- fixed service manager commands
+	return string(out), err
+}
+
+// daemonService describes the service that runs one workspace's daemon.
+type daemonService struct {
+	Name      string // systemd unit and scheduled task name
+	Label     string // launchd label
+	Exe       string
+	DBPath    string
+	Workspace string
+	Env       map[string]string
+	Home      string
+}
+
+// currentDaemonService describes the service for the current workspace and
+// binary.
+func currentDaemonService() daemonService {
+	db := dbPath
+	if db == "" {
+		db = beads.FindDatabasePath()
+	}
+	if db == "" {
+		FatalErrorWithHint("no beads database found", "run 'bd init' first")
+	}
+	db, _ = filepath.Abs(db)
+	exe, err := os.Executable()
+	if err != nil {
+		FatalError("locating bd: %v", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	home, _ := os.UserHomeDir()
+	return newDaemonService(exe, db, home, os.Getenv)
+}
+
+func newDaemonService(exe, db, home string, getenv func(string) string) daemonService {
+	workspace := filepath.Dir(filepath.Dir(db))
+	sum := sha256.Sum256([]byte(workspace))
+	id := serviceNameSafe(filepath.Base(workspace)) + "-" + hex.EncodeToString(sum[:4])
+	svc := daemonService{
+		Name:      "beads-daemon-" + id,
+		Label:     "com.beads.daemon." + id,
+		Exe:       exe,
+		DBPath:    db,
+		Workspace: workspace,
+		Env:       map[string]string{},
+		Home:      home,
+	}
+	// The daemon runs git and hooks, so it needs the user's PATH; the
+	// socket override must match what the CLI uses.
+	for _, key := range []string{"PATH", "BD_SOCKET"} {
+		if v := getenv(key); v != "" {
+			svc.Env[key] = v
+		}
+	}
+	return svc
+}
+
+func serviceNameSafe(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	if b.Len() == 0 {
+		return "workspace"
+	}
+	return b.String()
+}
+
+func (s daemonService) args() []string {
+	return []string{s.Exe, "--db", s.DBPath, "daemon", "start", "--foreground"}
+}
+
+// definition returns the service file to write for goos and its content.
+// Windows has no file; the task is created by command.
+func (s daemonService) definition(goos string) (path, content string, err error) {
+	switch goos {
+	case "linux":
+		return filepath.Join(s.Home, ".config", "systemd", "user", s.Name+".service"), s.systemdUnit(), nil
+	case "darwin":
+		return filepath.Join(s.Home, "Library", "LaunchAgents", s.Label+".plist"), s.launchdPlist(), nil
+	case "windows":
+		return "", "", nil
+	}
+	return "", "", fmt.Errorf("bd daemon install is not supported on %s; run 'bd daemon start --foreground' under your service manager", goos)
+}
+
+func (s daemonService) installCommands(goos, path string) [][]string {
+	switch goos {
+	case "linux":
+		return [][]string{
+			{"systemctl", "--user", "daemon-reload"},
+			{"systemctl", "--user", "enable", "--now", s.Name + ".service"},
+		}
+	case "darwin":
+		return [][]string{{"launchctl", "load", "-w", path}}
+	case "windows":
+		return [][]string{
+			{"schtasks", "/Create", "/F", "/SC", "ONLOGON", "/RL", "LIMITED", "/TN", s.Name, "/TR", s.windowsTaskCommand()},
+			{"schtasks", "/Run", "/TN", s.Name},
+		}
+	}
+	return nil
+}
+
+func (s daemonService) uninstallCommands(goos, path string) [][]string {
+	switch goos {
+	case "linux":
+		return [][]string{
+			{"systemctl", "--user", "disable", "--now", s.Name + ".service"},
+			{"systemctl", "--user", "daemon-reload"},
+		}
+	case "darwin":
+		return [][]string{{"launchctl", "unload", "-w", path}}
+	case "windows":
+		return [][]string{
+			{"schtasks", "/End", "/TN", s.Name},
+			{"schtasks", "/Delete", "/F", "/TN", s.Name},
+		}
+	}
+	return nil
+}
+
+// systemdUnit restarts the daemon only when it fails, so 'bd daemon stop'
+// and version-mismatch restarts by the CLI aren't fought by systemd. The
+// unit deliberately has no PrivateTmp: long workspace paths put the socket
+// under /tmp, where the CLI must be able to find it.
+func (s daemonService) systemdUnit() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=beads daemon for %s\nAfter=network-online.target\n\n", s.Workspace)
+	b.WriteString("[Service]\nType=simple\n")
+	fmt.Fprintf(&b, "WorkingDirectory=%s\n", systemdQuote(s.Workspace))
+	for _, key := range sortedKeys(s.Env) {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(key+"="+s.Env[key]))
+	}
+	quoted := make([]string, 0, len(s.args()))
+	for _, arg := range s.args() {
+		quoted = append(quoted, systemdQuote(arg))
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(quoted, " "))
+	b.WriteString("Restart=on-failure\nRestartSec=10\n\n[Install]\nWantedBy=default.target\n")
+	return b.String()
+}
+
+func systemdQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+// launchdPlist keeps the agent alive only after unsuccessful exits, for the
+// same reason as the systemd unit.
+func (s daemonService) launchdPlist() string {
+	esc := html.EscapeString
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", esc(s.Label))
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range s.args() {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", esc(arg))
+	}
+	b.WriteString("\t</array>\n")
+	fmt.Fprintf(&b, "\t<key>WorkingDirectory</key>\n\t<string>%s</string>\n", esc(s.Workspace))
+	if len(s.Env) > 0 {
+		b.WriteString("\t<key>EnvironmentVariables</key>\n\t<dict>\n")
+		for _, key := range sortedKeys(s.Env) {
+			fmt.Fprintf(&b, "\t\t<key>%s</key>\n\t\t<string>%s</string>\n", esc(key), esc(s.Env[key]))
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	b.WriteString(`	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+</dict>
+</plist>
+`)
+	return b.String()
+}
+
+// windowsTaskCommand is the scheduled task's command line. Tasks can't set
+// a working directory, so it changes into the workspace first.
+func (s daemonService) windowsTaskCommand() string {
+	quoted := make([]string, 0, len(s.args()))
+	for _, arg := range s.args() {
+		quoted = append(quoted, `"`+arg+`"`)
+	}
+	return fmt.Sprintf(`cmd /c cd /d "%s" && %s`, s.Workspace, strings.Join(quoted, " "))
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	daemonInstallCmd.Flags().Bool("dry-run", false, "Print the service definition and commands without installing")
+	daemonCmd.AddCommand(daemonInstallCmd, daemonUninstallCmd)
+}
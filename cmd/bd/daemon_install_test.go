@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testDaemonService(t *testing.T, workspace string) daemonService {
+	t.Helper()
+	env := map[string]string{"PATH": "/usr/local/bin:/usr/bin"}
+	return newDaemonService("/usr/local/bin/bd", filepath.Join(workspace, ".beads", "beads.db"), "/home/u",
+		func(key string) string { return env[key] })
+}
+
+func TestDaemonServiceNames(t *testing.T) {
+	a := testDaemonService(t, "/src/My Project")
+	b := testDaemonService(t, "/other/My Project")
+	if !strings.HasPrefix(a.Name, "beads-daemon-my-project-") || !strings.HasPrefix(a.Label, "com.beads.daemon.my-project-") {
+		t.Errorf("unexpected names %q, %q", a.Name, a.Label)
+	}
+	if a.Name == b.Name {
+		t.Errorf("workspaces with the same base name share service %q", a.Name)
+	}
+	if again := testDaemonService(t, "/src/My Project"); again.Name != a.Name {
+		t.Errorf("service name not stable: %q vs %q", again.Name, a.Name)
+	}
+}
+
+func TestDaemonServiceSystemdUnit(t *testing.T) {
+	svc := testDaemonService(t, "/src/My Project")
+	path, unit, err := svc.definition("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join("/home/u", ".config", "systemd", "user", svc.Name+".service") {
+		t.Errorf("path = %s", path)
+	}
+	for _, want := range []string{
+		`WorkingDirectory="/src/My Project"`,
+		`Environment=PATH=/usr/local/bin:/usr/bin`,
+		`ExecStart=/usr/local/bin/bd --db "/src/My Project/.beads/beads.db" daemon start --foreground`,
+		"Restart=on-failure",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit missing %q:\n%s", want, unit)
+		}
+	}
+	if strings.Contains(unit, "PrivateTmp") {
+		t.Errorf("unit must not isolate /tmp, where long socket paths live")
+	}
+}
+
+func TestDaemonServiceLaunchdPlist(t *testing.T) {
+	svc := testDaemonService(t, "/src/R&D")
+	path, plist, err := svc.definition("darwin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != svc.Label+".plist" {
+		t.Errorf("path = %s", path)
+	}
+	if err := xml.Unmarshal([]byte(plist), new(struct{})); err != nil {
+		t.Fatalf("plist is not well-formed XML: %v\n%s", err, plist)
+	}
+	for _, want := range []string{"<string>/src/R&amp;D/.beads/beads.db</string>", "<key>SuccessfulExit</key>", "<key>PATH</key>"} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist missing %q:\n%s", want, plist)
+		}
+	}
+}
+
+func TestDaemonServiceWindowsAndUnsupported(t *testing.T) {
+	svc := testDaemonService(t, `C:\src\proj`)
+	steps := svc.installCommands("windows", "")
+	if len(steps) == 0 || steps[0][0] != "schtasks" {
+		t.Fatalf("steps = %v", steps)
+	}
+	if cmd := svc.windowsTaskCommand(); !strings.Contains(cmd, "daemon\" \"start\" \"--foreground\"") || !strings.Contains(cmd, "cd /d") {
+		t.Errorf("task command = %s", cmd)
+	}
+	if _, _, err := svc.definition("plan9"); err == nil {
+		t.Error("expected an error for an unsupported OS")
+	}
+}
@@ -228,6 +228,12 @@ func showDaemonMetrics() {
 	fmt.Printf("  Active: %d\n", metrics.ActiveConns)
 	fmt.Printf("  Rejected: %d\n\n", metrics.RejectedConns)
 
+	// Read cache metrics
+	fmt.Printf("Read Cache:\n")
+	fmt.Printf("  Hits: %d\n", metrics.CacheHits)
+	fmt.Printf("  Misses: %d\n", metrics.CacheMisses)
+	fmt.Printf("  Entries: %d\n\n", metrics.CacheEntries)
+
 	// System metrics
 	fmt.Printf("System Metrics:\n")
 	fmt.Printf("  Memory Alloc: %d MB\n", metrics.MemoryAllocMB)
@@ -6,17 +6,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/steveyegge/beads/internal/advisory"
+	"github.com/steveyegge/beads/internal/atomicfile"
 	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/cc"
 	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/goals"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/repro"
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/subset"
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/visibility"
+	"github.com/steveyegge/beads/internal/votes"
 )
 
 // exportToJSONLWithStore exports issues to JSONL using the provided store.
@@ -37,6 +47,12 @@ func exportToJSONLWithStore(ctx context.Context, store storage.Storage, jsonlPat
 	}
 
 	// Single-repo mode - use existing logic
+	unlock, err := lockJSONL(ctx, jsonlPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Get all issues including tombstones for sync propagation
 	// Tombstones must be exported so they propagate to other clones and prevent resurrection
 	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{IncludeTombstones: true})
@@ -91,52 +107,66 @@ func exportToJSONLWithStore(ctx context.Context, store storage.Storage, jsonlPat
 		issue.Comments = comments
 	}
 
-	// Create temp file for atomic write
-	dir := filepath.Dir(jsonlPath)
-	base := filepath.Base(jsonlPath)
-	tempFile, err := os.CreateTemp(dir, base+".tmp.*")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+	// Populate typed external refs
+	if err := refs.Populate(ctx, store, issues); err != nil {
+		return err
 	}
-	tempPath := tempFile.Name()
 
-	// Use defer pattern for proper cleanup
-	var writeErr error
-	defer func() {
-		_ = tempFile.Close()
-		if writeErr != nil {
-			_ = os.Remove(tempPath) // Remove temp file on error
-		}
-	}()
+	// Populate security advisories
+	if err := advisory.Populate(ctx, store, issues); err != nil {
+		return err
+	}
+	if err := repro.Populate(ctx, store, issues); err != nil {
+		return err
+	}
+	if err := votes.Populate(ctx, store, issues); err != nil {
+		return err
+	}
+	if err := cc.Populate(ctx, store, issues); err != nil {
+		return err
+	}
+	if err := goals.Populate(ctx, store, issues); err != nil {
+		return err
+	}
+	// Private issues never reach the committed JSONL
+	issues, err = visibility.WithholdPrivate(ctx, store, issues)
+	if err != nil {
+		return err
+	}
 
-	// Write JSONL
-	for _, issue := range issues {
-		data, marshalErr := json.Marshal(issue)
-		if marshalErr != nil {
-			writeErr = fmt.Errorf("failed to marshal issue %s: %w", issue.ID, marshalErr)
-			return writeErr
-		}
-		if _, writeErr = tempFile.Write(data); writeErr != nil {
-			writeErr = fmt.Errorf("failed to write issue %s: %w", issue.ID, writeErr)
-			return writeErr
-		}
-		if _, writeErr = tempFile.WriteString("\n"); writeErr != nil {
-			writeErr = fmt.Errorf("failed to write newline: %w", writeErr)
-			return writeErr
-		}
+	// Write subset stubs back in full
+	issues, err = subset.PreserveStubs(ctx, store, jsonlPath, issues)
+	if err != nil {
+		return err
 	}
 
-	// Close before rename
-	if writeErr = tempFile.Close(); writeErr != nil {
-		writeErr = fmt.Errorf("failed to close temp file: %w", writeErr)
-		return writeErr
+	// Write JSONL atomically; atomicfile fsyncs and journals the swap so a
+	// crash can't leave a truncated JSONL behind
+	_, err = atomicfile.Write(jsonlPath, func(w io.Writer) error {
+		for _, issue := range issues {
+			data, err := json.Marshal(issue)
+			if err != nil {
+				return fmt.Errorf("failed to marshal issue %s: %w", issue.ID, err)
+			}
+			if _, err := w.Write(data); err != nil {
+				return fmt.Errorf("failed to write issue %s: %w", issue.ID, err)
+			}
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return fmt.Errorf("failed to write newline: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// Atomic rename
-	if writeErr = os.Rename(tempPath, jsonlPath); writeErr != nil {
-		writeErr = fmt.Errorf("failed to rename temp file: %w", writeErr)
-		return writeErr
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
 	}
+	recordOpLog(ctx, store, jsonlPath, ids)
+	recordStatusSnapshot(ctx, store)
 
 	return nil
 }
@@ -157,6 +187,14 @@ func importToJSONLWithStore(ctx context.Context, store storage.Storage, jsonlPat
 	}
 
 	// Single-repo mode - use existing logic
+	unlock, err := lockJSONL(ctx, jsonlPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	recoverInterruptedExport(jsonlPath)
+
 	// Read JSONL file
 	file, err := os.Open(jsonlPath) // #nosec G304 - controlled path from config
 	if err != nil {
@@ -202,8 +240,12 @@ func importToJSONLWithStore(ctx context.Context, store storage.Storage, jsonlPat
 		SkipPrefixValidation: true, // Skip prefix validation for auto-import
 	}
 
-	_, err = importIssuesCore(ctx, "", store, issues, opts)
-	return err
+	recordPendingOpLog(ctx, store, jsonlPath)
+	if _, err := importIssuesCore(ctx, "", store, issues, opts); err != nil {
+		return err
+	}
+	applyOpLog(ctx, store, jsonlPath)
+	return nil
 }
 
 // getRepoKeyForPath extracts the stable repo identifier from a JSONL path.
@@ -437,6 +479,11 @@ func performExport(ctx context.Context, store storage.Storage, autoCommit, autoP
 		}
 		log.log("Exported to JSONL")
 
+		// Keep the Obsidian vault (bd export obsidian), changelog and status document in step, if configured
+		syncObsidianVault(exportCtx, store, beadsDir, log)
+		syncChangelog(exportCtx, store, beadsDir, log)
+		syncSummary(exportCtx, store, beadsDir, log)
+
 		// GH#885: Defer metadata updates until AFTER git commit succeeds.
 		// This is a helper to finalize the export after git operations.
 		finalizeExportMetadata := func() {
@@ -737,6 +784,11 @@ func performSync(ctx context.Context, store storage.Storage, autoCommit, autoPus
 		}
 		log.log("Exported to JSONL")
 
+		// Keep the Obsidian vault (bd export obsidian), changelog and status document in step, if configured
+		syncObsidianVault(syncCtx, store, beadsDir, log)
+		syncChangelog(syncCtx, store, beadsDir, log)
+		syncSummary(syncCtx, store, beadsDir, log)
+
 		// GH#885: Defer metadata updates until AFTER git commit succeeds.
 		// Define helper to finalize after git operations.
 		dbPath := filepath.Join(beadsDir, "beads.db")
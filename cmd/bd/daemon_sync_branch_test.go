@@ -1904,7 +1904,7 @@ func TestDaemonSyncBranchForceOverwrite(t *testing.T) {
 		}
 
 		// Now delete the issue from database
-		if err := storeA.DeleteIssue(ctx, issue2ID); err != nil {
+		if err := storeA.DeleteIssue(ctx, issue2ID, "test-user"); err != nil {
 			t.Fatalf("Failed to delete issue: %v", err)
 		}
 		t.Logf("Deleted issue %s from database", issue2ID)
@@ -350,10 +350,10 @@ func deleteIssue(ctx context.Context, issueID string) error {
 	// We need to access the SQLite storage directly
 	// Check if store is SQLite storage
 	type deleter interface {
-		DeleteIssue(ctx context.Context, id string) error
+		DeleteIssue(ctx context.Context, id, actor string) error
 	}
 	if d, ok := store.(deleter); ok {
-		return d.DeleteIssue(ctx, issueID)
+		return d.DeleteIssue(ctx, issueID, actor)
 	}
 	return fmt.Errorf("delete operation not supported by this storage backend")
 }
@@ -461,7 +461,7 @@ func deleteBatch(_ *cobra.Command, issueIDs []string, force bool, dryRun bool, c
 	}
 	// Dry-run or preview mode
 	if dryRun || !force {
-		result, err := d.DeleteIssues(ctx, issueIDs, cascade, false, true)
+		result, err := d.DeleteIssues(ctx, issueIDs, cascade, false, true, actor)
 		if err != nil {
 			// Try to show preview even if there are dependency issues
 			showDeletionPreview(issueIDs, issues, cascade, err)
@@ -515,7 +515,7 @@ func deleteBatch(_ *cobra.Command, issueIDs []string, force bool, dryRun bool, c
 		}
 	}
 	// Actually delete (creates tombstones)
-	result, err := d.DeleteIssues(ctx, issueIDs, cascade, force, false)
+	result, err := d.DeleteIssues(ctx, issueIDs, cascade, force, false, actor)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -160,7 +160,7 @@ func TestBulkDeleteNoResurrection(t *testing.T) {
 	dbPath = testDB
 	autoImportEnabled = true
 
-	result, err := s.DeleteIssues(ctx, toDelete, false, true, false)
+	result, err := s.DeleteIssues(ctx, toDelete, false, true, false, "test-user")
 	if err != nil {
 		t.Fatalf("DeleteIssues failed: %v", err)
 	}
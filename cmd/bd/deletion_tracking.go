@@ -107,7 +107,7 @@ func merge3WayAndPruneDeletions(ctx context.Context, store storage.Storage, json
 	var deletionErrors []error
 	var alreadyGone int
 	for _, id := range acceptedDeletions {
-		if err := store.DeleteIssue(ctx, id); err != nil {
+		if err := store.DeleteIssue(ctx, id, actor); err != nil {
 			if isIssueNotFoundError(err) {
 				alreadyGone++
 				continue
@@ -99,7 +99,7 @@ func TestMultiWorkspaceDeletionSync(t *testing.T) {
 	}
 
 	// Step 2: Clone A deletes the issue
-	if err := storeA.DeleteIssue(ctx, "bd-delete-me"); err != nil {
+	if err := storeA.DeleteIssue(ctx, "bd-delete-me", "test-user"); err != nil {
 		t.Fatalf("Failed to delete issue in store A: %v", err)
 	}
 
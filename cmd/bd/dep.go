@@ -229,12 +229,28 @@ Examples:
   bd dep add bd-42 bd-41                              # Positional args
   bd dep add bd-42 --blocked-by bd-41                 # Flag syntax (same effect)
   bd dep add bd-42 --depends-on bd-41                 # Alias (same effect)
-  bd dep add gt-xyz external:beads:mol-run-assignee   # Cross-project dependency`,
+  bd dep add gt-xyz external:beads:mol-run-assignee   # Cross-project dependency
+
+External conditions block an issue until something outside beads holds.
+The dependency is a cmd or url gate (see bd gate) that the daemon, or
+bd gate check, closes once the condition holds:
+  - --until-cmd: the shell command exits 0 without printing "false"
+  - --until-url: the URL answers with a 2xx status
+
+  bd dep add bd-12 --until-cmd "gh pr view 123 --json merged -q .merged"
+  bd dep add bd-12 --until-url https://staging.example.com/healthz --until-timeout 48h`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		blockedBy, _ := cmd.Flags().GetString("blocked-by")
 		dependsOn, _ := cmd.Flags().GetString("depends-on")
 		hasFlag := blockedBy != "" || dependsOn != ""
 
+		if untilCmd, untilURL := depUntilFlags(cmd); untilCmd != "" || untilURL != "" {
+			if hasFlag || (untilCmd != "" && untilURL != "") {
+				return fmt.Errorf("--until-cmd and --until-url can't be combined with each other or another blocker")
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+
 		if hasFlag {
 			// If a flag is provided, we only need 1 positional arg (the dependent issue)
 			if len(args) < 1 {
@@ -253,6 +269,10 @@ Examples:
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		CheckReadonly("dep add")
+		if untilCmd, untilURL := depUntilFlags(cmd); untilCmd != "" || untilURL != "" {
+			addConditionDependency(cmd, args[0], untilCmd, untilURL)
+			return
+		}
 		depType, _ := cmd.Flags().GetString("type")
 
 		// Get the dependency target from flag or positional arg
@@ -1158,6 +1178,9 @@ func init() {
 	depAddCmd.Flags().StringP("type", "t", "blocks", "Dependency type (blocks|tracks|related|parent-child|discovered-from|until|caused-by|validates|relates-to|supersedes)")
 	depAddCmd.Flags().String("blocked-by", "", "Issue ID that blocks the first issue (alternative to positional arg)")
 	depAddCmd.Flags().String("depends-on", "", "Issue ID that the first issue depends on (alias for --blocked-by)")
+	depAddCmd.Flags().String("until-cmd", "", "Block until this shell command succeeds (creates a cmd gate)")
+	depAddCmd.Flags().String("until-url", "", "Block until this URL answers 2xx (creates a url gate)")
+	depAddCmd.Flags().Duration("until-timeout", 0, "Escalate the condition gate if still unmet after this long (e.g. 48h)")
 
 	depTreeCmd.Flags().Bool("show-all-paths", false, "Show all paths to nodes (no deduplication for diamond dependencies)")
 	depTreeCmd.Flags().IntP("max-depth", "d", 50, "Maximum tree depth to display (safety limit)")
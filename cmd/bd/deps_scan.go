@@ -0,0 +1,461 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/advisory"
+	"github.com/steveyegge/beads/internal/depscan"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// Upgrade issues are labeled upgrades, with external_ref gomod:<module>,
+// and are children of an epic with external_ref gomod-upgrades:<main module>.
+const (
+	upgradesLabel      = "upgrades"
+	vulnerableLabel    = "vulnerability"
+	gomodRefPrefix     = "gomod:"
+	gomodEpicRefPrefix = "gomod-upgrades:"
+)
+
+// Scan actions
+const (
+	depsCreated   = "created"
+	depsUpdated   = "updated"
+	depsReopened  = "reopened"
+	depsUnchanged = "unchanged"
+	depsClosed    = "closed"
+)
+
+// DepsScanModule is the outcome of bd deps scan for one module.
+type DepsScanModule struct {
+	*depscan.Finding
+	IssueID string `json:"issue_id,omitempty"`
+	Action  string `json:"action,omitempty"`
+}
+
+// DepsScanResult is the JSON output of bd deps scan.
+type DepsScanResult struct {
+	GoMod   string            `json:"go_mod"`
+	Module  string            `json:"module"`
+	EpicID  string            `json:"epic_id,omitempty"`
+	Modules []*DepsScanModule `json:"modules"`
+}
+
+var depsCmd = &cobra.Command{
+	Use:     "deps",
+	GroupID: "deps",
+	Short:   "Track upgrades of the project's own dependencies",
+}
+
+var depsScanCmd = &cobra.Command{
+	Use:   "scan [go.mod]",
+	Short: "Create issues for outdated or vulnerable Go modules",
+	Long: `Check the modules a go.mod requires against the Go module proxy and the
+Go vulnerability database, and keep one issue per module that needs an
+upgrade:
+
+  vulnerable   a bug (P1, labeled vulnerability) listing the advisories and
+               the version that fixes them; its CVE goes in bd security
+  outdated     a task (P3) to upgrade to the latest release
+
+Issues are labeled upgrades, carry external_ref gomod:<module>, and are
+children of a "Dependency upgrades" epic, created on first use. Re-running
+the scan updates them as new releases and advisories appear, and closes
+them once go.mod requires a version that is current and unaffected, or no
+longer requires the module. Run it on the main branch after merges (e.g. in
+CI) so landed upgrades close their issues.
+
+Indirect requirements are skipped unless --indirect is given. GOPROXY and
+GOVULNDB select the endpoints, as for the go command.`,
+	Example: `  bd deps scan
+  bd deps scan tools/go.mod --indirect
+  bd deps scan --vulns-only --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		indirect, _ := cmd.Flags().GetBool("indirect")
+		vulnsOnly, _ := cmd.Flags().GetBool("vulns-only")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		goModPath := "go.mod"
+		if len(args) > 0 {
+			goModPath = args[0]
+		}
+		data, err := os.ReadFile(goModPath) // #nosec G304 -- user-specified go.mod
+		if err != nil {
+			FatalErrorRespectJSON("reading %s: %v", goModPath, err)
+		}
+		mainPath, mods, err := depscan.ParseGoMod(goModPath, data)
+		if err != nil {
+			FatalErrorRespectJSON("parsing %s: %v", goModPath, err)
+		}
+		required := make(map[string]bool, len(mods))
+		var scan []depscan.Module
+		for _, m := range mods {
+			required[m.Path] = true
+			if indirect || !m.Indirect {
+				scan = append(scan, m)
+			}
+		}
+
+		if !dryRun {
+			CheckReadonly("deps scan")
+		}
+		if err := ensureDirectMode("deps scan requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		findings, err := depscan.NewScanner().Scan(ctx, scan)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		result := &DepsScanResult{GoMod: filepath.ToSlash(goModPath), Module: mainPath, Modules: []*DepsScanModule{}}
+		epicID := ""
+		epic := func() string {
+			if epicID == "" && !dryRun {
+				epicID = upgradesEpic(ctx, mainPath)
+				result.EpicID = epicID
+			}
+			return epicID
+		}
+		for _, f := range findings {
+			m := &DepsScanModule{Finding: f}
+			result.Modules = append(result.Modules, m)
+			needed := f.Vulnerable() || (f.Outdated() && !vulnsOnly)
+			existing, err := store.GetIssueByExternalRef(ctx, gomodRefPrefix+f.Path)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if existing != nil {
+				m.IssueID = existing.ID
+			}
+			switch {
+			case needed:
+				m.Action = applyUpgradeIssue(ctx, m, existing, epic, dryRun)
+			case f.Error != "":
+				// Unknown: leave any issue as it is
+			case existing != nil && existing.Status != types.StatusClosed:
+				m.Action = depsClosed
+				if !dryRun {
+					closeUpgradeIssue(ctx, existing.ID, "go.mod now requires "+f.Version)
+				}
+			}
+		}
+
+		// Issues of modules this go.mod no longer requires, among the
+		// children of its epic (other go.mod files have their own)
+		open, err := store.SearchIssues(ctx, "", types.IssueFilter{
+			Labels:        []string{upgradesLabel},
+			ExcludeStatus: []types.Status{types.StatusClosed},
+		})
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		epicIssue, err := store.GetIssueByExternalRef(ctx, gomodEpicRefPrefix+mainPath)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		for _, issue := range open {
+			if epicIssue == nil || issue.ExternalRef == nil || !strings.HasPrefix(*issue.ExternalRef, gomodRefPrefix) {
+				continue
+			}
+			path := strings.TrimPrefix(*issue.ExternalRef, gomodRefPrefix)
+			if required[path] || !hasParent(ctx, issue.ID, epicIssue.ID) {
+				continue
+			}
+			result.Modules = append(result.Modules, &DepsScanModule{
+				Finding: &depscan.Finding{Module: depscan.Module{Path: path}},
+				IssueID: issue.ID,
+				Action:  depsClosed,
+			})
+			if !dryRun {
+				closeUpgradeIssue(ctx, issue.ID, "go.mod no longer requires "+path)
+			}
+		}
+		if !dryRun {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		displayDepsScan(result, dryRun)
+	},
+}
+
+// applyUpgradeIssue creates, updates or reopens the issue of a module that
+// needs an upgrade and returns the action taken.
+func applyUpgradeIssue(ctx context.Context, m *DepsScanModule, existing *types.Issue, epic func() string, dryRun bool) string {
+	f := m.Finding
+	title := upgradeTitle(f)
+	description := upgradeDescription(f)
+	issueType, priority := types.TypeTask, 3
+	if f.Vulnerable() {
+		issueType, priority = types.TypeBug, 1
+	}
+
+	if existing == nil {
+		if dryRun {
+			return depsCreated
+		}
+		ref := gomodRefPrefix + f.Path
+		issue := &types.Issue{
+			Title:       title,
+			Description: description,
+			Status:      types.StatusOpen,
+			Priority:    priority,
+			IssueType:   issueType,
+			ExternalRef: &ref,
+			CreatedBy:   getActorWithGit(),
+			Owner:       getOwner(),
+		}
+		if err := store.CreateIssue(ctx, issue, actor); err != nil {
+			FatalErrorRespectJSON("creating issue for %s: %v", f.Path, err)
+		}
+		m.IssueID = issue.ID
+		if epicID := epic(); epicID != "" {
+			dep := &types.Dependency{IssueID: issue.ID, DependsOnID: epicID, Type: types.DepParentChild}
+			if err := store.AddDependency(ctx, dep, actor); err != nil {
+				WarnError("failed to add %s to epic %s: %v", issue.ID, epicID, err)
+			}
+		}
+		labelUpgradeIssue(ctx, issue.ID, f)
+		return depsCreated
+	}
+
+	// A closed issue reopens when the upgrade it asked for changed; one
+	// closed without upgrading stays closed until a new release or advisory
+	if existing.Status == types.StatusClosed && existing.Title == title {
+		return depsUnchanged
+	}
+	updates := map[string]interface{}{}
+	if existing.Title != title {
+		updates["title"] = title
+	}
+	if existing.Description != description {
+		updates["description"] = description
+	}
+	if f.Vulnerable() && existing.IssueType != types.TypeBug {
+		updates["issue_type"] = string(issueType)
+		updates["priority"] = priority
+	}
+	action := depsUpdated
+	if existing.Status == types.StatusClosed {
+		updates["status"] = string(types.StatusOpen)
+		action = depsReopened
+	}
+	if len(updates) == 0 {
+		return depsUnchanged
+	}
+	if !dryRun {
+		if err := store.UpdateIssue(ctx, existing.ID, updates, actor); err != nil {
+			FatalErrorRespectJSON("updating %s: %v", existing.ID, err)
+		}
+		labelUpgradeIssue(ctx, existing.ID, f)
+	}
+	return action
+}
+
+// labelUpgradeIssue labels an upgrade issue and records the CVE of a
+// vulnerable module as its security advisory.
+func labelUpgradeIssue(ctx context.Context, id string, f *depscan.Finding) {
+	labels := []string{upgradesLabel}
+	if f.Vulnerable() {
+		labels = append(labels, vulnerableLabel)
+	}
+	for _, label := range labels {
+		if err := store.AddLabel(ctx, id, label, actor); err != nil {
+			WarnError("failed to add label %s: %v", label, err)
+		}
+	}
+	cve := depscan.CVE(f.Vulns)
+	if cve == "" {
+		return
+	}
+	as, err := advisory.For(store)
+	if err != nil {
+		return
+	}
+	sec, err := as.GetSecurity(ctx, id)
+	if err != nil || (sec != nil && sec.CVE != "") {
+		return
+	}
+	if sec == nil {
+		sec = &types.Security{}
+	}
+	sec.CVE = cve
+	if sec.AffectedVersions == "" {
+		sec.AffectedVersions = f.Path + "@" + f.Version
+	}
+	if err := as.SetSecurity(ctx, id, sec, actor); err != nil {
+		WarnError("failed to record %s on %s: %v", cve, id, err)
+	}
+}
+
+// closeUpgradeIssue closes an upgrade issue whose upgrade has landed.
+func closeUpgradeIssue(ctx context.Context, id, reason string) {
+	if err := store.CloseIssue(ctx, id, reason, actor, ""); err != nil {
+		FatalErrorRespectJSON("closing %s: %v", id, err)
+	}
+}
+
+// hasParent reports whether issueID has a parent-child dependency on parentID.
+func hasParent(ctx context.Context, issueID, parentID string) bool {
+	deps, err := store.GetDependencyRecords(ctx, issueID)
+	if err != nil {
+		return false
+	}
+	for _, dep := range deps {
+		if dep.DependsOnID == parentID && dep.Type == types.DepParentChild {
+			return true
+		}
+	}
+	return false
+}
+
+// upgradesEpic returns the ID of the upgrades epic of a main module,
+// creating it on first use.
+func upgradesEpic(ctx context.Context, mainPath string) string {
+	ref := gomodEpicRefPrefix + mainPath
+	epic, err := store.GetIssueByExternalRef(ctx, ref)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	if epic != nil {
+		return epic.ID
+	}
+	epic = &types.Issue{
+		Title:       "Dependency upgrades: " + mainPath,
+		Description: "Upgrades of outdated or vulnerable modules required by " + mainPath + ", tracked by bd deps scan.",
+		Status:      types.StatusOpen,
+		Priority:    2,
+		IssueType:   types.TypeEpic,
+		ExternalRef: &ref,
+		CreatedBy:   getActorWithGit(),
+		Owner:       getOwner(),
+	}
+	if err := store.CreateIssue(ctx, epic, actor); err != nil {
+		FatalErrorRespectJSON("creating upgrades epic: %v", err)
+	}
+	if err := store.AddLabel(ctx, epic.ID, upgradesLabel, actor); err != nil {
+		WarnError("failed to add label %s: %v", upgradesLabel, err)
+	}
+	return epic.ID
+}
+
+// upgradeTitle is the title of a module's upgrade issue.
+func upgradeTitle(f *depscan.Finding) string {
+	target := f.Target()
+	switch {
+	case f.Vulnerable() && target == "":
+		return fmt.Sprintf("Vulnerable %s %s (no fixed release)", f.Path, f.Version)
+	case f.Vulnerable():
+		return fmt.Sprintf("Upgrade %s from %s to %s (vulnerable)", f.Path, f.Version, target)
+	}
+	return fmt.Sprintf("Upgrade %s from %s to %s", f.Path, f.Version, target)
+}
+
+// upgradeDescription is the description of a module's upgrade issue.
+func upgradeDescription(f *depscan.Finding) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "go.mod requires `%s %s`.", f.Path, f.Version)
+	if f.Outdated() {
+		fmt.Fprintf(&b, " The latest release is %s.", f.Latest)
+	}
+	if f.Vulnerable() {
+		b.WriteString("\n\nKnown vulnerabilities:\n")
+		for _, v := range f.Vulns {
+			line := "- " + v.ID
+			if len(v.Aliases) > 0 {
+				line += " (" + strings.Join(v.Aliases, ", ") + ")"
+			}
+			if v.Summary != "" {
+				line += ": " + v.Summary
+			}
+			if v.Fixed != "" {
+				line += " — fixed in " + v.Fixed
+			} else {
+				line += " — no fix yet"
+			}
+			b.WriteString(line + "\n")
+		}
+		if fixed := f.FixedIn(); fixed != "" {
+			fmt.Fprintf(&b, "\nUpgrading to %s or later fixes them all.", fixed)
+		}
+	}
+	fmt.Fprintf(&b, "\n\nUpgrade with `go get %s@%s`.", f.Path, orLatest(f.Target()))
+	return strings.TrimSpace(b.String())
+}
+
+func orLatest(version string) string {
+	if version == "" {
+		return "latest"
+	}
+	return version
+}
+
+// displayDepsScan prints the modules that need attention and what the scan
+// did about them.
+func displayDepsScan(r *DepsScanResult, dryRun bool) {
+	prefix := ""
+	if dryRun {
+		prefix = "(dry run) "
+	}
+	var errs []*DepsScanModule
+	shown := 0
+	for _, m := range r.Modules {
+		if m.Error != "" && m.Action == "" {
+			errs = append(errs, m)
+			continue
+		}
+		if m.Action == "" {
+			continue
+		}
+		if shown == 0 {
+			fmt.Printf("\n%s %sDependency upgrades for %s\n\n", ui.RenderAccent("📦"), prefix, r.GoMod)
+		}
+		shown++
+		icon := ui.RenderWarn("↑")
+		if m.Vulnerable() {
+			icon = ui.RenderFail("!")
+		}
+		if m.Action == depsClosed {
+			icon = ui.RenderPass("✓")
+		}
+		id := m.IssueID
+		if id == "" {
+			id = "-"
+		}
+		line := fmt.Sprintf("  %s %-10s %-9s %s %s", icon, id, m.Action, m.Path, m.Version)
+		if target := m.Target(); target != "" && m.Action != depsClosed {
+			line += " → " + target
+		}
+		if n := len(m.Vulns); n > 0 {
+			line += ui.RenderMuted(fmt.Sprintf("  (%d vulnerabilities)", n))
+		}
+		fmt.Println(line)
+	}
+	if shown == 0 {
+		fmt.Printf("%s %d modules are up to date with no known vulnerabilities\n", ui.RenderPass("✓"), len(r.Modules)-len(errs))
+	} else {
+		fmt.Println()
+	}
+	for _, m := range errs {
+		fmt.Fprintf(os.Stderr, "%s Couldn't check %s: %s\n", ui.RenderWarn("⚠"), m.Path, m.Error)
+	}
+}
+
+func init() {
+	depsScanCmd.Flags().Bool("indirect", false, "Also scan indirect requirements")
+	depsScanCmd.Flags().Bool("vulns-only", false, "Only track vulnerable modules, not merely outdated ones")
+	depsScanCmd.Flags().Bool("dry-run", false, "Show what would change without writing")
+	depsCmd.AddCommand(depsScanCmd)
+	rootCmd.AddCommand(depsCmd)
+}
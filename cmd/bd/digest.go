@@ -0,0 +1,366 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/mail"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/cc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// digestListLimit caps each section of the digest; the rest are counted.
+const digestListLimit = 25
+
+var digestCmd = &cobra.Command{
+	Use:     "digest",
+	GroupID: "views",
+	Short:   "Summary report of recent activity for status emails",
+	Long: `Summarize a period: issues created, closed, newly blocked and stale, with
+counts compared to the period before.
+
+Formats:
+  markdown   Markdown report, for posting to a channel or wiki (default)
+  email      The same report as an RFC 5322 message; pipe it to sendmail -t.
+             Email addresses cc'd on the listed issues (--cc) are copied,
+             unless --no-cc
+
+"Newly blocked" issues became blocked during the period: their status was
+set to blocked, or a blocking dependency was added. "Stale" issues are open
+and haven't been updated in --stale-days.
+
+Examples:
+  bd digest                                    # Last week, markdown
+  bd digest --since 2w --stale-days 14
+  bd digest --format email --to team@example.com | sendmail -t
+  bd digest --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceStr, _ := cmd.Flags().GetString("since")
+		format, _ := cmd.Flags().GetString("format")
+		staleDays, _ := cmd.Flags().GetInt("stale-days")
+		to, _ := cmd.Flags().GetStringSlice("to")
+		from, _ := cmd.Flags().GetString("from")
+		output, _ := cmd.Flags().GetString("output")
+		noCC, _ := cmd.Flags().GetBool("no-cc")
+
+		if format != "markdown" && format != "email" {
+			FatalErrorWithHint(fmt.Sprintf("unknown format %q", format), "use --format markdown or --format email")
+		}
+		period, err := parseDurationString(sinceStr)
+		if err != nil || period <= 0 {
+			FatalError("invalid --since %q: use a duration such as 1w, 7d or 48h", sinceStr)
+		}
+		if err := ensureDirectMode("digest requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+
+		until := time.Now()
+		report, err := buildDigest(ctx, store, until.Add(-period), until, staleDays)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		report.Project = feedName()
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			f, err := os.Create(output) // #nosec G304 -- user-specified output path
+			if err != nil {
+				FatalError("failed to create %s: %v", output, err)
+			}
+			defer func() { _ = f.Close() }()
+			w = f
+		}
+		if format == "email" {
+			if from == "" {
+				from = digestSender()
+			}
+			var ccAddrs []string
+			if !noCC {
+				ccAddrs = digestCC(report)
+			}
+			writeDigestEmailHeaders(w, report, from, to, ccAddrs)
+		}
+		writeDigestMarkdown(w, report)
+	},
+}
+
+// DigestReport is the data behind bd digest.
+type DigestReport struct {
+	Project      string        `json:"project"`
+	Since        time.Time     `json:"since"`
+	Until        time.Time     `json:"until"`
+	StaleDays    int           `json:"stale_days"`
+	Created      []DigestIssue `json:"created"`
+	Closed       []DigestIssue `json:"closed"`
+	NewlyBlocked []DigestIssue `json:"newly_blocked"`
+	Stale        []DigestIssue `json:"stale"`
+	Stats        DigestStats   `json:"stats"`
+}
+
+// DigestIssue is an issue listed in a digest.
+type DigestIssue struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Priority  int       `json:"priority"`
+	Assignee  string    `json:"assignee,omitempty"`
+	BlockedBy []string  `json:"blocked_by,omitempty"`
+	CC        []string  `json:"cc,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DigestStats compares the period with the one before it. Open counts are
+// taken at the end of each period.
+type DigestStats struct {
+	Created         int `json:"created"`
+	CreatedPrevious int `json:"created_previous"`
+	Closed          int `json:"closed"`
+	ClosedPrevious  int `json:"closed_previous"`
+	Open            int `json:"open"`
+	OpenPrevious    int `json:"open_previous"`
+	Blocked         int `json:"blocked"`
+}
+
+// buildDigest gathers the report for [since, until).
+func buildDigest(ctx context.Context, s storage.Storage, since, until time.Time, staleDays int) (*DigestReport, error) {
+	report := &DigestReport{Since: since, Until: until, StaleDays: staleDays}
+	prevSince := since.Add(-until.Sub(since))
+
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	in := func(t time.Time, from, to time.Time) bool { return !t.Before(from) && t.Before(to) }
+	openAt := func(issue *types.Issue, t time.Time) bool {
+		return issue.CreatedAt.Before(t) && (issue.ClosedAt == nil || !issue.ClosedAt.Before(t))
+	}
+	for _, issue := range issues {
+		if issue.Status == types.StatusTombstone {
+			continue
+		}
+		if in(issue.CreatedAt, since, until) {
+			report.Created = append(report.Created, newDigestIssue(issue))
+		} else if in(issue.CreatedAt, prevSince, since) {
+			report.Stats.CreatedPrevious++
+		}
+		if issue.Status == types.StatusClosed && issue.ClosedAt != nil {
+			if in(*issue.ClosedAt, since, until) {
+				report.Closed = append(report.Closed, newDigestIssue(issue))
+			} else if in(*issue.ClosedAt, prevSince, since) {
+				report.Stats.ClosedPrevious++
+			}
+		}
+		if issue.Status != types.StatusClosed && openAt(issue, until) {
+			report.Stats.Open++
+		}
+		if openAt(issue, since) {
+			report.Stats.OpenPrevious++
+		}
+	}
+	report.Stats.Created = len(report.Created)
+	report.Stats.Closed = len(report.Closed)
+
+	blocked, err := s.GetBlockedIssues(ctx, types.WorkFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked issues: %w", err)
+	}
+	report.Stats.Blocked = len(blocked)
+	for _, b := range blocked {
+		newly := b.Status == types.StatusBlocked && in(b.UpdatedAt, since, until)
+		if !newly && len(b.BlockedBy) > 0 {
+			deps, err := s.GetDependencyRecords(ctx, b.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get dependencies of %s: %w", b.ID, err)
+			}
+			for _, dep := range deps {
+				if dep.Type.AffectsReadyWork() && slices.Contains(b.BlockedBy, dep.DependsOnID) && in(dep.CreatedAt, since, until) {
+					newly = true
+					break
+				}
+			}
+		}
+		if newly {
+			item := newDigestIssue(&b.Issue)
+			item.BlockedBy = b.BlockedBy
+			report.NewlyBlocked = append(report.NewlyBlocked, item)
+		}
+	}
+
+	if staleDays > 0 {
+		stale, err := s.GetStaleIssues(ctx, types.StaleFilter{Days: staleDays})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stale issues: %w", err)
+		}
+		for _, issue := range stale {
+			report.Stale = append(report.Stale, newDigestIssue(issue))
+		}
+	}
+
+	byPriority := func(a, b DigestIssue) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.ID, b.ID))
+	}
+	if err := populateDigestCC(ctx, s, report); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(report.Created, byPriority)
+	slices.SortFunc(report.Closed, byPriority)
+	slices.SortFunc(report.NewlyBlocked, byPriority)
+	slices.SortFunc(report.Stale, func(a, b DigestIssue) int {
+		return cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return report, nil
+}
+
+// populateDigestCC fills in the stakeholders cc'd on the issues listed.
+func populateDigestCC(ctx context.Context, s storage.Storage, r *DigestReport) error {
+	cs, err := cc.For(s)
+	if err != nil {
+		return nil
+	}
+	sections := []([]DigestIssue){r.Created, r.Closed, r.NewlyBlocked, r.Stale}
+	var ids []string
+	for _, items := range sections {
+		for _, it := range items {
+			ids = append(ids, it.ID)
+		}
+	}
+	byIssue, err := cs.GetCCForIssues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get cc lists: %w", err)
+	}
+	for _, items := range sections {
+		for i := range items {
+			items[i].CC = byIssue[items[i].ID]
+		}
+	}
+	return nil
+}
+
+// digestCC returns the email addresses cc'd on the issues a digest lists.
+func digestCC(r *DigestReport) []string {
+	var lists [][]string
+	for _, items := range [][]DigestIssue{r.Created, r.Closed, r.NewlyBlocked, r.Stale} {
+		for _, it := range items {
+			lists = append(lists, it.CC)
+		}
+	}
+	return cc.Addresses(lists...)
+}
+
+func newDigestIssue(issue *types.Issue) DigestIssue {
+	return DigestIssue{
+		ID:        issue.ID,
+		Title:     issue.Title,
+		Priority:  issue.Priority,
+		Assignee:  issue.Assignee,
+		UpdatedAt: issue.UpdatedAt,
+	}
+}
+
+// digestSubject is the email subject, e.g. "bd digest Jan 2 – Jan 9: 5 created, 3 closed".
+func digestSubject(r *DigestReport) string {
+	return fmt.Sprintf("%s digest %s: %d created, %d closed", r.Project, digestPeriod(r), r.Stats.Created, r.Stats.Closed)
+}
+
+func digestPeriod(r *DigestReport) string {
+	return r.Since.Local().Format("Jan 2") + " – " + r.Until.Local().Format("Jan 2, 2006")
+}
+
+// digestSender is the git identity as "Name <email>", or the actor name
+// when git has no email configured.
+func digestSender() string {
+	out, err := exec.Command("git", "config", "user.email").Output()
+	if email := strings.TrimSpace(string(out)); err == nil && email != "" {
+		return (&mail.Address{Name: getActorWithGit(), Address: email}).String()
+	}
+	return getActorWithGit()
+}
+
+// writeDigestEmailHeaders writes the headers of a plain-text message; the
+// markdown report is the body.
+func writeDigestEmailHeaders(w io.Writer, r *DigestReport, from string, to, ccAddrs []string) {
+	fmt.Fprintf(w, "From: %s\n", from)
+	if len(to) > 0 {
+		fmt.Fprintf(w, "To: %s\n", strings.Join(to, ", "))
+	}
+	if len(ccAddrs) > 0 {
+		fmt.Fprintf(w, "Cc: %s\n", strings.Join(ccAddrs, ", "))
+	}
+	fmt.Fprintf(w, "Subject: %s\n", mime.QEncoding.Encode("utf-8", digestSubject(r)))
+	fmt.Fprintf(w, "Date: %s\n", r.Until.Format(time.RFC1123Z))
+	fmt.Fprintf(w, "MIME-Version: 1.0\n")
+	fmt.Fprintf(w, "Content-Type: text/plain; charset=utf-8\n")
+	fmt.Fprintf(w, "Content-Transfer-Encoding: 8bit\n\n")
+}
+
+func writeDigestMarkdown(w io.Writer, r *DigestReport) {
+	fmt.Fprintf(w, "# %s digest: %s\n\n", r.Project, digestPeriod(r))
+
+	fmt.Fprintln(w, "| | This period | Previous period | Change |")
+	fmt.Fprintln(w, "|---|---:|---:|---:|")
+	row := func(name string, now, before int) {
+		fmt.Fprintf(w, "| %s | %d | %d | %+d |\n", name, now, before, now-before)
+	}
+	row("Created", r.Stats.Created, r.Stats.CreatedPrevious)
+	row("Closed", r.Stats.Closed, r.Stats.ClosedPrevious)
+	row("Open at end", r.Stats.Open, r.Stats.OpenPrevious)
+	fmt.Fprintf(w, "\n%d issue(s) are currently blocked.\n", r.Stats.Blocked)
+
+	section := func(title string, items []DigestIssue, blockers bool) {
+		fmt.Fprintf(w, "\n## %s (%d)\n\n", title, len(items))
+		if len(items) == 0 {
+			fmt.Fprintln(w, "None.")
+			return
+		}
+		for i, it := range items {
+			if i == digestListLimit {
+				fmt.Fprintf(w, "- …and %d more\n", len(items)-digestListLimit)
+				break
+			}
+			line := fmt.Sprintf("- **%s** %s (P%d", it.ID, it.Title, it.Priority)
+			if it.Assignee != "" {
+				line += ", @" + it.Assignee
+			}
+			if len(it.CC) > 0 {
+				line += "; cc " + strings.Join(it.CC, ", ")
+			}
+			line += ")"
+			if blockers && len(it.BlockedBy) > 0 {
+				line += " — blocked by " + strings.Join(it.BlockedBy, ", ")
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+	section("Created", r.Created, false)
+	section("Closed", r.Closed, false)
+	section("Newly blocked", r.NewlyBlocked, true)
+	if r.StaleDays > 0 {
+		section(fmt.Sprintf("Stale, no updates in %d days", r.StaleDays), r.Stale, false)
+	}
+}
+
+func init() {
+	digestCmd.Flags().String("since", "1w", "Length of the period, ending now (e.g., 1w, 14d, 48h)")
+	digestCmd.Flags().String("format", "markdown", "Output format: markdown or email")
+	digestCmd.Flags().Int("stale-days", 30, "List open issues not updated in this many days (0 to omit)")
+	digestCmd.Flags().StringSlice("to", nil, "Recipients for --format email (repeatable)")
+	digestCmd.Flags().String("from", "", "Sender for --format email (default: your git identity)")
+	digestCmd.Flags().Bool("no-cc", false, "Don't copy the stakeholders cc'd on listed issues in --format email")
+	digestCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	rootCmd.AddCommand(digestCmd)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestBuildDigest(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	for _, issue := range []*types.Issue{
+		{ID: "test-1", Title: "Blocker", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+		{ID: "test-2", Title: "Waits", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "test-3", Title: "Done", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	} {
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dep := &types.Dependency{IssueID: "test-2", DependsOnID: "test-1", Type: types.DepBlocks}
+	if err := s.AddDependency(ctx, dep, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseIssue(ctx, "test-3", "done", "tester", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCC(ctx, "test-2", []string{"alice", "pm@example.com"}, "tester"); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	r, err := buildDigest(ctx, s, now.Add(-time.Hour), now.Add(time.Minute), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Stats.Created != 3 || r.Stats.Closed != 1 || r.Stats.Open != 2 || r.Stats.OpenPrevious != 0 {
+		t.Errorf("stats = %+v", r.Stats)
+	}
+	if len(r.NewlyBlocked) != 1 || r.NewlyBlocked[0].ID != "test-2" || r.NewlyBlocked[0].BlockedBy[0] != "test-1" {
+		t.Errorf("newly blocked = %+v", r.NewlyBlocked)
+	}
+	if r.Created[0].ID != "test-1" {
+		t.Errorf("created not sorted by priority: %+v", r.Created)
+	}
+	if cc := digestCC(r); len(r.NewlyBlocked[0].CC) != 2 || len(cc) != 1 || cc[0] != "pm@example.com" {
+		t.Errorf("cc = %v, digest cc = %v", r.NewlyBlocked[0].CC, cc)
+	}
+
+	// The next period compares against this one
+	r, err = buildDigest(ctx, s, now.Add(time.Minute), now.Add(time.Hour+time.Minute), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Stats.Created != 0 || r.Stats.CreatedPrevious != 3 || r.Stats.ClosedPrevious != 1 || r.Stats.OpenPrevious != 2 {
+		t.Errorf("next period stats = %+v", r.Stats)
+	}
+	if len(r.NewlyBlocked) != 0 {
+		t.Errorf("blocking from the previous period reported as new: %+v", r.NewlyBlocked)
+	}
+
+	var buf bytes.Buffer
+	r.Project = "test"
+	writeDigestEmailHeaders(&buf, r, "ana@example.com", []string{"team@example.com"}, nil)
+	writeDigestMarkdown(&buf, r)
+	out := buf.String()
+	for _, want := range []string{"To: team@example.com\n", "Subject: =?utf-8?q?test_digest", "| Created | 0 | 3 | -3 |", "## Newly blocked (0)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "## Stale") {
+		t.Error("stale section written with --stale-days 0")
+	}
+}
@@ -74,7 +74,7 @@ func StaleClosedIssues(path string) error {
 			continue
 		}
 
-		if err := store.DeleteIssue(ctx, issue.ID); err != nil {
+		if err := store.DeleteIssue(ctx, issue.ID, "doctor"); err != nil {
 			fmt.Printf("  Warning: failed to delete %s: %v\n", issue.ID, err)
 			continue
 		}
@@ -26,6 +26,8 @@ daemon.pid
 bd.sock
 sync-state.json
 last-touched
+schedule-runs.log
+active-session.json
 
 # Local version tracking (prevents upgrade notification spam after git ops)
 .local_version
@@ -49,6 +51,8 @@ beads.right.meta.json
 # Sync state (local-only, per-machine)
 # These files are machine-specific and should not be shared across clones
 .sync.lock
+.jsonl.lock
+*.export-intent
 sync_base.jsonl
 
 # NOTE: Do NOT add negation patterns (e.g., !issues.jsonl) here.
@@ -70,6 +74,8 @@ var requiredPatterns = []string{
 	"redirect",
 	"last-touched",
 	".sync.lock",
+	".jsonl.lock",
+	"*.export-intent",
 	"sync_base.jsonl",
 }
 
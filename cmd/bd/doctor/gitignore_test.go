@@ -1376,6 +1376,8 @@ func TestRequiredPatterns_ContainsRedirect(t *testing.T) {
 func TestGitignoreTemplate_ContainsSyncStateFiles(t *testing.T) {
 	syncStateFiles := []string{
 		".sync.lock",      // Concurrency guard
+		".jsonl.lock",     // Guards JSONL read-modify-write across processes
+		"*.export-intent", // Journal left by an export interrupted mid-write
 		"sync_base.jsonl", // Base state for 3-way merge (per-machine)
 	}
 
@@ -1392,6 +1394,8 @@ func TestGitignoreTemplate_ContainsSyncStateFiles(t *testing.T) {
 func TestRequiredPatterns_ContainsSyncStatePatterns(t *testing.T) {
 	syncStatePatterns := []string{
 		".sync.lock",
+		".jsonl.lock",
+		"*.export-intent",
 		"sync_base.jsonl",
 	}
 
@@ -361,6 +361,8 @@ func formatDuplicateGroupsJSON(groups [][]*types.Issue, refCounts map[string]int
 // Returns a map with the merge result for JSON output
 func performMerge(targetID string, sourceIDs []string) map[string]interface{} {
 	ctx := rootCtx
+	// duplicates is read-only at startup, which leaves the actor unresolved
+	actor = getActorWithGit()
 	result := map[string]interface{}{
 		"target":  targetID,
 		"sources": sourceIDs,
@@ -3,8 +3,6 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -55,24 +53,6 @@ Examples:
 			fieldToEdit = "acceptance_criteria"
 		}
 
-		// Get the editor from environment
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = os.Getenv("VISUAL")
-		}
-		if editor == "" {
-			// Try common defaults
-			for _, defaultEditor := range []string{"vim", "vi", "nano", "emacs"} {
-				if _, err := exec.LookPath(defaultEditor); err == nil {
-					editor = defaultEditor
-					break
-				}
-			}
-		}
-		if editor == "" {
-			FatalErrorRespectJSON("no editor found. Set $EDITOR or $VISUAL environment variable")
-		}
-
 		// Get the current issue
 		var issue *types.Issue
 		var err error
@@ -115,41 +95,12 @@ Examples:
 			currentValue = issue.AcceptanceCriteria
 		}
 
-		// Create a temporary file with the current value
-		tmpFile, err := os.CreateTemp("", fmt.Sprintf("bd-edit-%s-*.txt", fieldToEdit))
-		if err != nil {
-			FatalErrorRespectJSON("creating temp file: %v", err)
-		}
-		tmpPath := tmpFile.Name()
-		defer func() { _ = os.Remove(tmpPath) }()
-
-		// Write current value to temp file
-		if _, err := tmpFile.WriteString(currentValue); err != nil {
-			_ = tmpFile.Close()
-			FatalErrorRespectJSON("writing to temp file: %v", err)
-		}
-		_ = tmpFile.Close()
-
-		// Open the editor - parse command and args (handles "vim -w" or "zeditor --wait")
-		editorParts := strings.Fields(editor)
-		editorArgs := append(editorParts[1:], tmpPath)
-		editorCmd := exec.Command(editorParts[0], editorArgs...) //nolint:gosec // G204: editor from trusted $EDITOR/$VISUAL env or known defaults
-		editorCmd.Stdin = os.Stdin
-		editorCmd.Stdout = os.Stdout
-		editorCmd.Stderr = os.Stderr
-
-		if err := editorCmd.Run(); err != nil {
-			FatalErrorRespectJSON("running editor: %v", err)
-		}
-
-		// Read the edited content
-		// #nosec G304 -- tmpPath was created earlier in this function
-		editedContent, err := os.ReadFile(tmpPath)
+		editedContent, err := editText(fmt.Sprintf("bd-edit-%s-*.txt", fieldToEdit), currentValue)
 		if err != nil {
-			FatalErrorRespectJSON("reading edited file: %v", err)
+			FatalErrorRespectJSON("%v", err)
 		}
 
-		newValue := string(editedContent)
+		newValue := editedContent
 
 		// Check if the value changed
 		if newValue == currentValue {
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/lock"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/validation"
+)
+
+// ErrorCode is a stable, machine-readable failure class. Codes and their exit
+// statuses never change meaning once released; new codes may be added.
+type ErrorCode string
+
+const (
+	ErrCodeGeneric    ErrorCode = "E_GENERIC"     // Anything not covered below
+	ErrCodeUsage      ErrorCode = "E_USAGE"       // Bad command, flag, or argument
+	ErrCodeNotFound   ErrorCode = "E_NOT_FOUND"   // Issue or other resource doesn't exist
+	ErrCodeCycle      ErrorCode = "E_CYCLE"       // Change would create a dependency cycle
+	ErrCodeConflict   ErrorCode = "E_CONFLICT"    // Duplicate, already exists, or concurrent change
+	ErrCodeInvalid    ErrorCode = "E_INVALID"     // Value failed validation
+	ErrCodeReadonly   ErrorCode = "E_READONLY"    // Write attempted in read-only mode
+	ErrCodeNoDatabase ErrorCode = "E_NO_DATABASE" // No beads database found or initialized
+	ErrCodeDaemon     ErrorCode = "E_DAEMON"      // Daemon unreachable or RPC failure
+	ErrCodeTimeout    ErrorCode = "E_TIMEOUT"     // Command exceeded --timeout
+	ErrCodeInterrupt  ErrorCode = "E_INTERRUPTED" // Cancelled by Ctrl-C or SIGTERM
+	ErrCodeLocked     ErrorCode = "E_LOCKED"      // Issue locked or project frozen (bd lock, bd freeze)
+)
+
+// errorCodeExits maps each code to its process exit status.
+var errorCodeExits = map[ErrorCode]int{
+	ErrCodeGeneric:    1,
+	ErrCodeUsage:      2,
+	ErrCodeNotFound:   3,
+	ErrCodeCycle:      4,
+	ErrCodeConflict:   5,
+	ErrCodeInvalid:    6,
+	ErrCodeReadonly:   7,
+	ErrCodeNoDatabase: 8,
+	ErrCodeDaemon:     9,
+	ErrCodeTimeout:    10,
+	ErrCodeLocked:     11,
+	ErrCodeInterrupt:  130, // 128 + SIGINT, as shells report it
+}
+
+// ExitCode returns the process exit status for the code.
+func (c ErrorCode) ExitCode() int {
+	if code, ok := errorCodeExits[c]; ok {
+		return code
+	}
+	return 1
+}
+
+// cliError is the JSON document written to stderr for fatal errors in
+// --json mode.
+type cliError struct {
+	Error    string    `json:"error"`
+	Code     ErrorCode `json:"code"`
+	ExitCode int       `json:"exit_code"`
+	Hint     string    `json:"hint,omitempty"`
+}
+
+// usageError marks errors from cobra's argument and flag parsing.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// codedError carries an explicit error code through error returns.
+type codedError struct {
+	code ErrorCode
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// withErrorCode attaches code to err so FatalError and friends report it.
+func withErrorCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// errorMessagePatterns classify errors that don't carry a sentinel, by
+// lowercase message substring. Order matters: the first match wins.
+var errorMessagePatterns = []struct {
+	substr string
+	code   ErrorCode
+}{
+	{"context deadline exceeded", ErrCodeTimeout},
+	{"timed out after", ErrCodeTimeout},
+	{"context canceled", ErrCodeInterrupt},
+	{"interrupted", ErrCodeInterrupt},
+	{"read-only mode", ErrCodeReadonly},
+	{"issue is locked", ErrCodeLocked},
+	{"project is frozen", ErrCodeLocked},
+	{"breaks validation rules", ErrCodeInvalid},
+	{"cycle", ErrCodeCycle},
+	{"no issue found", ErrCodeNotFound},
+	{"not found", ErrCodeNotFound},
+	{"does not exist", ErrCodeNotFound},
+	{"no such issue", ErrCodeNotFound},
+	{"already exists", ErrCodeConflict},
+	{"unique constraint", ErrCodeConflict},
+	{"conflict", ErrCodeConflict},
+	{"no beads database", ErrCodeNoDatabase},
+	{"no database", ErrCodeNoDatabase},
+	{"database not initialized", ErrCodeNoDatabase},
+	{"connect to daemon", ErrCodeDaemon},
+	{"daemon not running", ErrCodeDaemon},
+	{"rpc error", ErrCodeDaemon},
+	{"validation failed", ErrCodeInvalid},
+	{"invalid", ErrCodeInvalid},
+	{"unknown command", ErrCodeUsage},
+	{"unknown flag", ErrCodeUsage},
+	{"accepts ", ErrCodeUsage}, // cobra: "accepts 1 arg(s), received 2"
+	{"requires at least", ErrCodeUsage},
+}
+
+// classifyError picks an error code for a fatal error, preferring typed
+// errors among args and falling back to the message text.
+func classifyError(msg string, args []interface{}) ErrorCode {
+	for _, arg := range args {
+		err, ok := arg.(error)
+		if !ok {
+			continue
+		}
+		var coded *codedError
+		switch {
+		case errors.As(err, &coded):
+			return coded.code
+		case errors.As(err, new(*usageError)):
+			return ErrCodeUsage
+		case errors.Is(err, context.DeadlineExceeded):
+			return ErrCodeTimeout
+		case errors.Is(err, context.Canceled):
+			return ErrCodeInterrupt
+		case errors.Is(err, sqlite.ErrNotFound):
+			return ErrCodeNotFound
+		case errors.Is(err, sqlite.ErrCycle):
+			return ErrCodeCycle
+		case errors.Is(err, sqlite.ErrConflict):
+			return ErrCodeConflict
+		case errors.Is(err, sqlite.ErrInvalidID):
+			return ErrCodeInvalid
+		case errors.As(err, new(*lock.Error)):
+			return ErrCodeLocked
+		case errors.As(err, new(*validation.RuleError)):
+			return ErrCodeInvalid
+		}
+	}
+	lower := strings.ToLower(msg)
+	for _, p := range errorMessagePatterns {
+		if strings.Contains(lower, p.substr) {
+			return p.code
+		}
+	}
+	return ErrCodeGeneric
+}
+
+// writeCLIError reports a fatal error on stderr: a JSON document in --json
+// mode, otherwise "Error: ..." text (plus an optional hint).
+func writeCLIError(code ErrorCode, msg, hint string) {
+	if hint == "" && code == ErrCodeTimeout && commandTimeout > 0 {
+		hint = fmt.Sprintf("the command ran longer than --timeout %s", commandTimeout)
+	}
+	if jsonOutput {
+		data, _ := json.Marshal(cliError{Error: msg, Code: code, ExitCode: code.ExitCode(), Hint: hint})
+		fmt.Fprintln(os.Stderr, string(data))
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: %s\n", msg)
+	if hint != "" {
+		fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
+	}
+}
+
+// FatalErrorCode reports an error with an explicit code and exits with the
+// code's exit status. Use it when the message alone doesn't identify the
+// failure class.
+//
+// Example:
+//
+//	if issue == nil {
+//	    FatalErrorCode(ErrCodeNotFound, "issue %s not found", id)
+//	}
+func FatalErrorCode(code ErrorCode, format string, args ...interface{}) {
+	writeCLIError(code, fmt.Sprintf(format, args...), "")
+	exitFunc(code.ExitCode())
+}
+
+// exitCodeForExecuteError maps an error returned by rootCmd.Execute to an
+// exit status, reporting it as JSON on stderr in --json mode (cobra has
+// already printed the plain-text form). Flag errors happen before --json is
+// parsed, so the raw arguments are checked too.
+func exitCodeForExecuteError(err error) int {
+	code := classifyError(err.Error(), []interface{}{err})
+	if !jsonOutput {
+		for _, arg := range os.Args[1:] {
+			if arg == "--json" || arg == "--json=true" {
+				jsonOutput = true
+				break
+			}
+		}
+	}
+	if jsonOutput {
+		writeCLIError(code, err.Error(), "")
+	}
+	return code.ExitCode()
+}
+
+func init() {
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/lock"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/validation"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		msg  string
+		args []interface{}
+		want ErrorCode
+	}{
+		{"wrapped", []interface{}{fmt.Errorf("get issue: %w", sqlite.ErrNotFound)}, ErrCodeNotFound},
+		{"wrapped", []interface{}{fmt.Errorf("add: %w", sqlite.ErrCycle)}, ErrCodeCycle},
+		{"explicit", []interface{}{withErrorCode(ErrCodeConflict, errors.New("x"))}, ErrCodeConflict},
+		{"bad flag", []interface{}{&usageError{err: errors.New("unknown flag: --x")}}, ErrCodeUsage},
+		{`no issue found matching "bd-x"`, nil, ErrCodeNotFound},
+		{"cannot add dependency: would create a cycle (a → b → ... → a)", nil, ErrCodeCycle},
+		{"issue bd-1 already exists", nil, ErrCodeConflict},
+		{"operation 'create' is not allowed in read-only mode", nil, ErrCodeReadonly},
+		{"database not initialized: issue_prefix config is missing", nil, ErrCodeNoDatabase},
+		{"invalid priority \"P9\"", nil, ErrCodeInvalid},
+		{`accepts 1 arg(s), received 2`, nil, ErrCodeUsage},
+		{"list", []interface{}{fmt.Errorf("query: %w", context.DeadlineExceeded)}, ErrCodeTimeout},
+		{"list", []interface{}{fmt.Errorf("query: %w", context.Canceled)}, ErrCodeInterrupt},
+		{"import interrupted after 3 of 9 issues (3 created, 0 updated): timed out after 5s", nil, ErrCodeTimeout},
+		{"update", []interface{}{fmt.Errorf("update: %w", &lock.Error{IssueID: "bd-1"})}, ErrCodeLocked},
+		{"cannot modify bd-1: project is frozen (release freeze)", nil, ErrCodeLocked},
+		{"create", []interface{}{fmt.Errorf("create: %w", &validation.RuleError{ID: "bd-1"})}, ErrCodeInvalid},
+		{"bd-1 breaks validation rules:\n  - title is shorter than 10 characters", nil, ErrCodeInvalid},
+		{"title required", nil, ErrCodeGeneric},
+	}
+	for _, tt := range tests {
+		if got := classifyError(tt.msg, tt.args); got != tt.want {
+			t.Errorf("classifyError(%q) = %s, want %s", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestErrorCodeExitCodesDistinct(t *testing.T) {
+	seen := make(map[int]ErrorCode)
+	for code, exit := range errorCodeExits {
+		if other, dup := seen[exit]; dup {
+			t.Errorf("%s and %s share exit code %d", code, other, exit)
+		}
+		seen[exit] = code
+	}
+	if ErrorCode("E_UNKNOWN").ExitCode() != 1 {
+		t.Error("unknown codes should exit 1")
+	}
+}
+
+func TestStructuredErrorsOnStderr(t *testing.T) {
+	p := newTestProject(t)
+
+	decode := func(t *testing.T, stderr string) cliError {
+		t.Helper()
+		lines := strings.Split(strings.TrimSpace(stderr), "\n")
+		var e cliError
+		if err := json.Unmarshal([]byte(lines[len(lines)-1]), &e); err != nil {
+			t.Fatalf("last stderr line is not JSON: %v\n%s", err, stderr)
+		}
+		return e
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		res := p.RunCommand("show", "test-missing", "--json")
+		if res.ExitCode() != 3 {
+			t.Fatalf("exit = %d, want 3\nstderr: %s", res.ExitCode(), res.Stderr)
+		}
+		if e := decode(t, res.Stderr); e.Code != ErrCodeNotFound || e.ExitCode != 3 {
+			t.Errorf("unexpected error: %+v", e)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		var a, b map[string]interface{}
+		p.RunJSON(&a, "create", "A")
+		p.RunJSON(&b, "create", "B")
+		p.Run("dep", "add", a["id"].(string), b["id"].(string))
+		res := p.RunCommand("dep", "add", b["id"].(string), a["id"].(string), "--json")
+		if res.ExitCode() != 4 {
+			t.Fatalf("exit = %d, want 4\nstdout: %s\nstderr: %s", res.ExitCode(), res.Stdout, res.Stderr)
+		}
+		if e := decode(t, res.Stderr); e.Code != ErrCodeCycle {
+			t.Errorf("unexpected error: %+v", e)
+		}
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		res := p.RunCommand("list", "--no-such-flag", "--json")
+		if res.ExitCode() != 2 {
+			t.Fatalf("exit = %d, want 2\nstderr: %s", res.ExitCode(), res.Stderr)
+		}
+		if e := decode(t, res.Stderr); e.Code != ErrCodeUsage {
+			t.Errorf("unexpected error: %+v", e)
+		}
+	})
+
+	t.Run("text mode", func(t *testing.T) {
+		res := p.RunCommand("show", "test-missing")
+		if res.ExitCode() != 3 || strings.Contains(res.Stderr, `"code"`) {
+			t.Errorf("exit = %d, stderr: %s", res.ExitCode(), res.Stderr)
+		}
+	})
+}
@@ -6,9 +6,17 @@ import (
 	"os"
 )
 
-// FatalError writes an error message to stderr and exits with code 1.
+// exitFunc terminates the process. The error helpers below exit through it
+// so that tests running commands in-process can turn exits into errors.
+var exitFunc = os.Exit
+
+// FatalError writes an error message to stderr and exits.
 // Use this for fatal errors that prevent the command from completing.
 //
+// The exit status comes from the error code (see error_codes.go), which is
+// taken from typed errors in args or else from the message. With --json the
+// error is written to stderr as {"error": ..., "code": ..., "exit_code": ...}.
+//
 // Pattern A from ERROR_HANDLING.md:
 // - User input validation failures
 // - Critical preconditions not met
@@ -20,13 +28,15 @@ import (
 //	    FatalError("%v", err)
 //	}
 func FatalError(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
-	os.Exit(1)
+	msg := fmt.Sprintf(format, args...)
+	code := classifyError(msg, args)
+	writeCLIError(code, msg, "")
+	exitFunc(code.ExitCode())
 }
 
-// FatalErrorRespectJSON writes an error message and exits with code 1.
-// If --json flag is set, outputs structured JSON to stdout.
-// Otherwise, outputs plain text to stderr.
+// FatalErrorRespectJSON writes an error message and exits like FatalError.
+// If --json flag is set, it also outputs {"error": ..., "code": ...} to
+// stdout for callers that only read stdout.
 //
 // Use this for errors in commands that support --json output.
 //
@@ -37,13 +47,13 @@ func FatalError(format string, args ...interface{}) {
 //	}
 func FatalErrorRespectJSON(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
+	code := classifyError(msg, args)
 	if jsonOutput {
-		data, _ := json.MarshalIndent(map[string]string{"error": msg}, "", "  ")
+		data, _ := json.MarshalIndent(map[string]string{"error": msg, "code": string(code)}, "", "  ")
 		fmt.Println(string(data))
-	} else {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", msg)
 	}
-	os.Exit(1)
+	writeCLIError(code, msg, "")
+	exitFunc(code.ExitCode())
 }
 
 // FatalErrorWithHint writes an error message with a hint to stderr and exits.
@@ -53,9 +63,9 @@ func FatalErrorRespectJSON(format string, args ...interface{}) {
 //
 //	FatalErrorWithHint("database not found", "Run 'bd init' to create a database")
 func FatalErrorWithHint(message, hint string) {
-	fmt.Fprintf(os.Stderr, "Error: %s\n", message)
-	fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
-	os.Exit(1)
+	code := classifyError(message, nil)
+	writeCLIError(code, message, hint)
+	exitFunc(code.ExitCode())
 }
 
 // WarnError writes a warning message to stderr and returns.
@@ -88,7 +98,8 @@ func WarnError(format string, args ...interface{}) {
 //	    },
 //	}
 func CheckReadonly(operation string) {
+	sessionWriteOp = operation
 	if readonlyMode {
-		FatalError("operation '%s' is not allowed in read-only mode", operation)
+		FatalErrorCode(ErrCodeReadonly, "operation '%s' is not allowed in read-only mode", operation)
 	}
 }
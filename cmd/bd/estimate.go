@@ -0,0 +1,421 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// estimateMaxSimilar caps how many similar past issues feed a suggestion.
+const estimateMaxSimilar = 10
+
+// estimateSample is a closed issue paired with its measured cycle time.
+type estimateSample struct {
+	Issue  *types.Issue
+	Actual time.Duration
+}
+
+// EstimateSuggestion is the result of `bd estimate suggest`.
+type EstimateSuggestion struct {
+	IssueID          string   `json:"issue_id"`
+	CurrentMinutes   *int     `json:"current_minutes,omitempty"`
+	SuggestedMinutes int      `json:"suggested_minutes"`
+	Calibration      float64  `json:"calibration,omitempty"` // Median actual/estimate ratio of similar issues
+	SampleSize       int      `json:"sample_size"`
+	SimilarIssues    []string `json:"similar_issues"`
+	Basis            string   `json:"basis"` // "history" or "calibrated"
+}
+
+// EstimateCalibrationRow summarizes estimated vs actual cycle time for a group.
+type EstimateCalibrationRow struct {
+	Group          string  `json:"group"`
+	Closed         int     `json:"closed"`
+	Estimated      int     `json:"estimated"`
+	MedianEstimate int     `json:"median_estimate_minutes"`
+	MedianActual   int     `json:"median_actual_minutes"`
+	Ratio          float64 `json:"ratio,omitempty"` // Median actual/estimate (>1 = underestimated)
+}
+
+var estimateCmd = &cobra.Command{
+	Use:     "estimate",
+	GroupID: "views",
+	Short:   "Estimate work from historical cycle times",
+	Long: `Estimate work using the cycle times of similar closed issues.
+
+Cycle time runs from when an issue first moved to in_progress (or its
+creation, if it was never started) to when it was closed.`,
+}
+
+var estimateSuggestCmd = &cobra.Command{
+	Use:   "suggest <id>",
+	Short: "Suggest an estimate based on similar past issues",
+	Long: `Suggest an estimate for an issue based on similar closed issues.
+
+Similarity is scored on matching issue type and shared labels. The suggestion
+is the median cycle time of the most similar issues. If the issue already has
+an estimate and similar issues were estimated too, the existing estimate is
+scaled by how far off those past estimates were (the calibration ratio).
+
+Examples:
+  bd estimate suggest bd-42
+  bd estimate suggest bd-42 --apply   # Save the suggestion as the estimate
+  bd estimate suggest bd-42 --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apply, _ := cmd.Flags().GetBool("apply")
+		if apply {
+			CheckReadonly("estimate suggest --apply")
+		}
+		if err := ensureDirectMode("estimate suggest requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		id, err := utils.ResolvePartialID(ctx, store, args[0])
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		issue, err := store.GetIssue(ctx, id)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if issue == nil {
+			FatalErrorRespectJSON("issue %s not found", id)
+		}
+		issue.Labels, _ = store.GetLabels(ctx, id)
+
+		samples, err := loadEstimateSamples(ctx)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		suggestion := suggestEstimate(issue, samples)
+		if suggestion == nil {
+			FatalErrorRespectJSON("no similar closed issues to base an estimate on for %s", id)
+		}
+
+		if apply {
+			updates := map[string]interface{}{"estimated_minutes": suggestion.SuggestedMinutes}
+			if err := store.UpdateIssue(ctx, id, updates, actor); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(suggestion)
+			return
+		}
+
+		fmt.Printf("\n%s Suggested estimate for %s: %s\n", ui.RenderAccent("⏱"),
+			ui.RenderID(id), ui.RenderBold(formatEstimateMinutes(suggestion.SuggestedMinutes)))
+		if suggestion.CurrentMinutes != nil {
+			fmt.Printf("  Current estimate: %s\n", formatEstimateMinutes(*suggestion.CurrentMinutes))
+		}
+		if suggestion.Basis == "calibrated" {
+			fmt.Printf("  Calibration: similar issues took %.2fx their estimate\n", suggestion.Calibration)
+		}
+		fmt.Printf("  Based on %d similar closed issue(s): %s\n", suggestion.SampleSize,
+			ui.RenderMuted(strings.Join(suggestion.SimilarIssues, ", ")))
+		if apply {
+			fmt.Printf("%s Updated estimate for %s\n", ui.RenderPass("✓"), id)
+		}
+		fmt.Println()
+	},
+}
+
+var estimateCalibrationCmd = &cobra.Command{
+	Use:   "calibration",
+	Short: "Compare estimated vs actual cycle time",
+	Long: `Compare estimated vs actual cycle time of closed issues, grouped by
+issue type (default) or label.
+
+A ratio above 1 means work in that group tends to take longer than estimated.
+
+Examples:
+  bd estimate calibration
+  bd estimate calibration --by label
+  bd estimate calibration --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		by, _ := cmd.Flags().GetString("by")
+		if by != "type" && by != "label" {
+			FatalErrorRespectJSON("--by must be 'type' or 'label', got %q", by)
+		}
+		if err := ensureDirectMode("estimate calibration requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		samples, err := loadEstimateSamples(rootCtx)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		rows := buildEstimateCalibration(samples, by)
+
+		if jsonOutput {
+			outputJSON(rows)
+			return
+		}
+		if len(rows) == 0 {
+			fmt.Println("No closed issues to calibrate against")
+			return
+		}
+
+		fmt.Printf("\n%s Estimate calibration by %s:\n\n", ui.RenderAccent("📊"), by)
+		fmt.Printf("  %-20s %7s %10s %12s %12s %7s\n", "GROUP", "CLOSED", "ESTIMATED", "MED. EST.", "MED. ACTUAL", "RATIO")
+		for _, row := range rows {
+			est, ratio := "-", "-"
+			if row.Estimated > 0 {
+				est = formatEstimateMinutes(row.MedianEstimate)
+				ratio = fmt.Sprintf("%.2f", row.Ratio)
+			}
+			fmt.Printf("  %-20s %7d %10d %12s %12s %7s\n", row.Group, row.Closed, row.Estimated,
+				est, formatEstimateMinutes(row.MedianActual), ratio)
+		}
+		fmt.Println()
+	},
+}
+
+// loadEstimateSamples returns closed issues (with labels) and their cycle times.
+func loadEstimateSamples(ctx context.Context) ([]estimateSample, error) {
+	closed := types.StatusClosed
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{Status: &closed})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	labels, err := store.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]estimateSample, 0, len(issues))
+	for _, issue := range issues {
+		if issue.ClosedAt == nil {
+			continue
+		}
+		issue.Labels = labels[issue.ID]
+		start := issue.CreatedAt
+		if events, err := store.GetEvents(ctx, issue.ID, 0); err == nil {
+			if started := firstStartedAt(events); started != nil {
+				start = *started
+			}
+		}
+		actual := issue.ClosedAt.Sub(start)
+		if actual <= 0 {
+			continue
+		}
+		samples = append(samples, estimateSample{Issue: issue, Actual: actual})
+	}
+	return samples, nil
+}
+
+// firstStartedAt returns when an issue first moved to in_progress, if ever.
+func firstStartedAt(events []*types.Event) *time.Time {
+	var started *time.Time
+	for _, e := range events {
+		if e.EventType != types.EventStatusChanged || e.NewValue == nil {
+			continue
+		}
+		var updates map[string]interface{}
+		if err := json.Unmarshal([]byte(*e.NewValue), &updates); err != nil {
+			continue
+		}
+		if updates["status"] != string(types.StatusInProgress) {
+			continue
+		}
+		if started == nil || e.CreatedAt.Before(*started) {
+			t := e.CreatedAt
+			started = &t
+		}
+	}
+	return started
+}
+
+// estimateSimilarity scores how alike two issues are: 2 for a matching type,
+// plus 1 per shared label. Zero means unrelated.
+func estimateSimilarity(a, b *types.Issue) int {
+	score := 0
+	if a.IssueType == b.IssueType {
+		score += 2
+	}
+	shared := make(map[string]bool, len(a.Labels))
+	for _, l := range a.Labels {
+		shared[l] = true
+	}
+	for _, l := range b.Labels {
+		if shared[l] {
+			score++
+		}
+	}
+	return score
+}
+
+// suggestEstimate proposes an estimate for target from similar samples.
+// Returns nil if no sample is similar.
+func suggestEstimate(target *types.Issue, samples []estimateSample) *EstimateSuggestion {
+	type scored struct {
+		estimateSample
+		score int
+	}
+	var similar []scored
+	for _, s := range samples {
+		if s.Issue.ID == target.ID {
+			continue
+		}
+		if score := estimateSimilarity(target, s.Issue); score > 0 {
+			similar = append(similar, scored{s, score})
+		}
+	}
+	if len(similar) == 0 {
+		return nil
+	}
+	// Most similar first; break ties by most recently closed.
+	sort.Slice(similar, func(i, j int) bool {
+		if similar[i].score != similar[j].score {
+			return similar[i].score > similar[j].score
+		}
+		return similar[i].Issue.ClosedAt.After(*similar[j].Issue.ClosedAt)
+	})
+	if len(similar) > estimateMaxSimilar {
+		similar = similar[:estimateMaxSimilar]
+	}
+
+	suggestion := &EstimateSuggestion{
+		IssueID:        target.ID,
+		CurrentMinutes: target.EstimatedMinutes,
+		SampleSize:     len(similar),
+		Basis:          "history",
+	}
+	var actuals, ratios []float64
+	for _, s := range similar {
+		suggestion.SimilarIssues = append(suggestion.SimilarIssues, s.Issue.ID)
+		actuals = append(actuals, s.Actual.Minutes())
+		if est := s.Issue.EstimatedMinutes; est != nil && *est > 0 {
+			ratios = append(ratios, s.Actual.Minutes()/float64(*est))
+		}
+	}
+	suggestion.SuggestedMinutes = roundEstimateMinutes(median(actuals))
+
+	if len(ratios) > 0 {
+		suggestion.Calibration = median(ratios)
+		if target.EstimatedMinutes != nil && *target.EstimatedMinutes > 0 {
+			suggestion.SuggestedMinutes = roundEstimateMinutes(float64(*target.EstimatedMinutes) * suggestion.Calibration)
+			suggestion.Basis = "calibrated"
+		}
+	}
+	return suggestion
+}
+
+// buildEstimateCalibration groups samples by issue type or label and compares
+// median estimates with median actual cycle times.
+func buildEstimateCalibration(samples []estimateSample, by string) []*EstimateCalibrationRow {
+	type group struct {
+		estimates, actuals, ratios []float64
+		closed                     int
+	}
+	groups := make(map[string]*group)
+	add := func(key string, s estimateSample) {
+		g, ok := groups[key]
+		if !ok {
+			g = &group{}
+			groups[key] = g
+		}
+		g.closed++
+		g.actuals = append(g.actuals, s.Actual.Minutes())
+		if est := s.Issue.EstimatedMinutes; est != nil && *est > 0 {
+			g.estimates = append(g.estimates, float64(*est))
+			g.ratios = append(g.ratios, s.Actual.Minutes()/float64(*est))
+		}
+	}
+	for _, s := range samples {
+		if by == "label" {
+			for _, l := range s.Issue.Labels {
+				add(l, s)
+			}
+			continue
+		}
+		add(string(s.Issue.IssueType), s)
+	}
+
+	rows := make([]*EstimateCalibrationRow, 0, len(groups))
+	for key, g := range groups {
+		row := &EstimateCalibrationRow{
+			Group:        key,
+			Closed:       g.closed,
+			Estimated:    len(g.estimates),
+			MedianActual: int(median(g.actuals) + 0.5),
+		}
+		if len(g.estimates) > 0 {
+			row.MedianEstimate = int(median(g.estimates) + 0.5)
+			row.Ratio = median(g.ratios)
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Closed != rows[j].Closed {
+			return rows[i].Closed > rows[j].Closed
+		}
+		return rows[i].Group < rows[j].Group
+	})
+	return rows
+}
+
+// median returns the median of values (0 for an empty slice).
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// roundEstimateMinutes rounds to the nearest 15 minutes, with a 15 minute floor.
+func roundEstimateMinutes(minutes float64) int {
+	rounded := int(minutes/15+0.5) * 15
+	if rounded < 15 {
+		return 15
+	}
+	return rounded
+}
+
+// formatEstimateMinutes renders minutes as a compact duration (e.g. "2h30m", "3d4h").
+func formatEstimateMinutes(minutes int) string {
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes < 24*60:
+		if minutes%60 == 0 {
+			return fmt.Sprintf("%dh", minutes/60)
+		}
+		return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+	default:
+		days, hours := minutes/(24*60), (minutes%(24*60))/60
+		if hours == 0 {
+			return fmt.Sprintf("%dd", days)
+		}
+		return fmt.Sprintf("%dd%dh", days, hours)
+	}
+}
+
+func init() {
+	estimateSuggestCmd.Flags().Bool("apply", false, "Save the suggested estimate on the issue")
+	estimateCalibrationCmd.Flags().String("by", "type", "Group by 'type' or 'label'")
+	estimateCmd.AddCommand(estimateSuggestCmd)
+	estimateCmd.AddCommand(estimateCalibrationCmd)
+	rootCmd.AddCommand(estimateCmd)
+}
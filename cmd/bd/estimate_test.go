@@ -0,0 +1,114 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func intPtr(v int) *int { return &v }
+
+func closedSample(id string, issueType types.IssueType, labels []string, estimate *int, actual time.Duration, closedAt time.Time) estimateSample {
+	return estimateSample{
+		Issue: &types.Issue{
+			ID:               id,
+			IssueType:        issueType,
+			Labels:           labels,
+			EstimatedMinutes: estimate,
+			ClosedAt:         &closedAt,
+		},
+		Actual: actual,
+	}
+}
+
+func TestSuggestEstimate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	samples := []estimateSample{
+		closedSample("bd-1", types.TypeBug, []string{"backend"}, intPtr(60), 2*time.Hour, now),
+		closedSample("bd-2", types.TypeBug, []string{"backend"}, intPtr(120), 4*time.Hour, now.Add(-time.Hour)),
+		closedSample("bd-3", types.TypeBug, nil, nil, 3*time.Hour, now.Add(-2*time.Hour)),
+		closedSample("bd-4", types.TypeFeature, []string{"ui"}, nil, 40*time.Hour, now),
+	}
+
+	t.Run("history", func(t *testing.T) {
+		target := &types.Issue{ID: "bd-9", IssueType: types.TypeBug, Labels: []string{"backend"}}
+		got := suggestEstimate(target, samples)
+		if got == nil {
+			t.Fatal("expected a suggestion")
+		}
+		if got.SampleSize != 3 || got.SimilarIssues[0] != "bd-1" {
+			t.Errorf("unexpected similar issues: %v", got.SimilarIssues)
+		}
+		if got.SuggestedMinutes != 180 || got.Basis != "history" {
+			t.Errorf("got %d minutes (%s), want 180 (history)", got.SuggestedMinutes, got.Basis)
+		}
+		if got.Calibration != 2 {
+			t.Errorf("Calibration = %v, want 2", got.Calibration)
+		}
+	})
+
+	t.Run("calibrated", func(t *testing.T) {
+		target := &types.Issue{ID: "bd-9", IssueType: types.TypeBug, EstimatedMinutes: intPtr(90)}
+		got := suggestEstimate(target, samples)
+		if got == nil || got.Basis != "calibrated" || got.SuggestedMinutes != 180 {
+			t.Errorf("expected calibrated 180 minute suggestion, got %+v", got)
+		}
+	})
+
+	t.Run("no similar issues", func(t *testing.T) {
+		target := &types.Issue{ID: "bd-9", IssueType: types.TypeChore}
+		if got := suggestEstimate(target, samples); got != nil {
+			t.Errorf("expected nil, got %+v", got)
+		}
+	})
+}
+
+func TestBuildEstimateCalibration(t *testing.T) {
+	now := time.Now()
+	samples := []estimateSample{
+		closedSample("bd-1", types.TypeBug, []string{"backend"}, intPtr(60), 2*time.Hour, now),
+		closedSample("bd-2", types.TypeBug, []string{"backend", "api"}, nil, time.Hour, now),
+		closedSample("bd-3", types.TypeTask, []string{"api"}, intPtr(60), 30*time.Minute, now),
+	}
+
+	byType := buildEstimateCalibration(samples, "type")
+	if len(byType) != 2 || byType[0].Group != "bug" {
+		t.Fatalf("unexpected rows: %+v", byType)
+	}
+	if byType[0].Closed != 2 || byType[0].Estimated != 1 || byType[0].Ratio != 2 || byType[0].MedianActual != 90 {
+		t.Errorf("unexpected bug row: %+v", byType[0])
+	}
+
+	byLabel := buildEstimateCalibration(samples, "label")
+	if len(byLabel) != 2 || byLabel[0].Group != "api" || byLabel[0].Ratio != 0.5 {
+		t.Errorf("unexpected label rows: %+v", byLabel)
+	}
+}
+
+func TestFirstStartedAt(t *testing.T) {
+	t1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	started := `{"status":"in_progress"}`
+	other := `{"title":"x"}`
+	events := []*types.Event{
+		{EventType: types.EventStatusChanged, NewValue: &started, CreatedAt: t2},
+		{EventType: types.EventUpdated, NewValue: &other, CreatedAt: t1},
+		{EventType: types.EventStatusChanged, NewValue: &started, CreatedAt: t1},
+	}
+	if got := firstStartedAt(events); got == nil || !got.Equal(t1) {
+		t.Errorf("firstStartedAt = %v, want %v", got, t1)
+	}
+	if got := firstStartedAt(events[1:2]); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestFormatEstimateMinutes(t *testing.T) {
+	tests := map[int]string{30: "30m", 60: "1h", 150: "2h30m", 1440: "1d", 1560: "1d2h"}
+	for in, want := range tests {
+		if got := formatEstimateMinutes(in); got != want {
+			t.Errorf("formatEstimateMinutes(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
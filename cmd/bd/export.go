@@ -2,21 +2,32 @@ package main
 
 import (
 	"cmp"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/advisory"
+	"github.com/steveyegge/beads/internal/atomicfile"
+	"github.com/steveyegge/beads/internal/cc"
 	"github.com/steveyegge/beads/internal/debug"
+	"github.com/steveyegge/beads/internal/goals"
+	"github.com/steveyegge/beads/internal/redact"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/repro"
+	"github.com/steveyegge/beads/internal/signing"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/subset"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/util"
 	"github.com/steveyegge/beads/internal/validation"
+	"github.com/steveyegge/beads/internal/visibility"
+	"github.com/steveyegge/beads/internal/votes"
 )
 
 // countIssuesInJSONL counts the number of issues in a JSONL file
@@ -79,6 +90,28 @@ func getIssueIDsFromJSONL(path string) (map[string]bool, error) {
 	return ids, nil
 }
 
+// signExport writes a detached signature of the file at path to path.sig
+// with the configured key and returns the signature's path.
+func signExport(path string) (string, error) {
+	key, err := signing.FromConfig()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path) // #nosec G304 - the export just written
+	if err != nil {
+		return "", err
+	}
+	sig, err := key.Sign(data)
+	if err != nil {
+		return "", err
+	}
+	sigPath := path + ".sig"
+	if err := os.WriteFile(sigPath, []byte(sig), 0600); err != nil {
+		return "", err
+	}
+	return sigPath, nil
+}
+
 // validateExportPath checks if the output path is safe to write to
 func validateExportPath(path string) error {
 	// Get absolute path to normalize it
@@ -130,7 +163,25 @@ Examples:
   bd export --format obsidian                    # outputs to ai_docs/changes-log.md
   bd export --format obsidian -o custom.md       # outputs to custom.md
   bd export --type bug --priority-max 1
-  bd export --created-after 2025-01-01 --assignee alice`,
+  bd export --created-after 2025-01-01 --assignee alice
+  bd export --redact -o public/issues.jsonl      # emails and tokens scrubbed
+  bd export --visibility public -o public/issues.jsonl
+
+Redaction (--redact) replaces matches of the built-in rules in redact.rules
+(emails and tokens by default; ips is also available) and of the regexes
+under redact.patterns.<name> with [redacted:<rule>], in text fields, people
+fields and comments. Redacted issues get the "redacted" label. The tracked
+issues.jsonl is never redacted, so the full data stays local.
+
+Security issues under embargo (bd security set --embargo-until) are always
+withheld: until the date passes, exports other than the tracked
+issues.jsonl carry only their ID, status, priority, type, dependencies and
+embargo date, labeled "embargoed".
+
+Private issues (bd visibility <id> private) never leave the database: they
+are withheld from the tracked issues.jsonl and, unless --visibility private
+is given, from every other export. --visibility public exports only
+issues marked public.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		format, _ := cmd.Flags().GetString("format")
 		output, _ := cmd.Flags().GetString("output")
@@ -150,7 +201,18 @@ Examples:
 		updatedAfter, _ := cmd.Flags().GetString("updated-after")
 		updatedBefore, _ := cmd.Flags().GetString("updated-before")
 
+		sign, _ := cmd.Flags().GetBool("sign")
+		redactExport, _ := cmd.Flags().GetBool("redact")
+		redactPatterns, _ := cmd.Flags().GetStringArray("redact-pattern")
+		visibilityFlag, _ := cmd.Flags().GetString("visibility")
+		audience, err := visibility.Parse(visibilityFlag)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
 		debug.Logf("Debug: export flags - output=%q, force=%v\n", output, force)
+		if sign && output == "" {
+			FatalErrorCode(ErrCodeUsage, "--sign requires --output (the signature is written to <output>.sig)")
+		}
 
 		if format != "jsonl" && format != "obsidian" {
 			fmt.Fprintf(os.Stderr, "Error: format must be 'jsonl' or 'obsidian'\n")
@@ -396,21 +458,115 @@ Examples:
 			issue.Dependencies = allDeps[issue.ID]
 		}
 
+		// Labels are read per issue and then each issue is written, so the
+		// bar counts both passes
+		progress := newProgress("Exporting", 2*len(issues))
+		defer progress.Finish()
+
 		// Populate labels for all issues
 		for _, issue := range issues {
 			labels, err := store.GetLabels(ctx, issue.ID)
 			if err != nil {
+				progress.Finish()
 				fmt.Fprintf(os.Stderr, "Error getting labels for %s: %v\n", issue.ID, err)
 				os.Exit(1)
 			}
 			issue.Labels = labels
+			progress.Add(1)
+		}
+
+		// Populate typed external refs
+		if err := refs.Populate(ctx, store, issues); err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting refs: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Populate security advisories
+		if err := advisory.Populate(ctx, store, issues); err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting security advisories: %v\n", err)
+			os.Exit(1)
+		}
+		if err := repro.Populate(ctx, store, issues); err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting repro metadata: %v\n", err)
+			os.Exit(1)
+		}
+		if err := votes.Populate(ctx, store, issues); err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting votes: %v\n", err)
+			os.Exit(1)
+		}
+		if err := cc.Populate(ctx, store, issues); err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting cc lists: %v\n", err)
+			os.Exit(1)
+		}
+		if err := goals.Populate(ctx, store, issues); err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting key results: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Subset stubs are exported in full, from the project JSONL
+		issues, err = subset.PreserveStubs(ctx, store, findJSONLPath(), issues)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error restoring subset stubs: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Issues beyond the audience's visibility are withheld; private
+		// issues never reach the tracked JSONL
+		absOutput, _ := filepath.Abs(output)
+		trackedOutput := absOutput == findJSONLPath() || sameFile(output, findJSONLPath())
+		if trackedOutput && audience == visibility.Private {
+			FatalErrorCode(ErrCodeUsage, "--visibility private requires --output other than the tracked %s", filepath.Base(findJSONLPath()))
+		}
+		withheldCount := len(issues)
+		if trackedOutput {
+			issues, err = visibility.WithholdPrivate(ctx, store, issues)
+		} else if err = visibility.Populate(ctx, store, issues); err == nil {
+			issues = visibility.Filter(issues, audience)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting visibility: %v\n", err)
+			os.Exit(1)
+		}
+		withheldCount -= len(issues)
+
+		// Embargoed security issues are withheld from every export but the
+		// tracked JSONL, which is the database's own sync copy
+		embargoedCount := 0
+		absExport, _ := filepath.Abs(output)
+		if output == "" || (absExport != findJSONLPath() && !sameFile(output, findJSONLPath())) {
+			now := time.Now()
+			for i, issue := range issues {
+				var withheld bool
+				if issues[i], withheld = redact.Embargo(issue, now); withheld {
+					embargoedCount++
+				}
+			}
+		}
+
+		// Redacted copies are for publishing; the tracked JSONL would feed
+		// them back into the local database on the next import
+		var redactor *redact.Redactor
+		if redactExport || len(redactPatterns) > 0 {
+			absOutput, _ := filepath.Abs(output)
+			if output == "" || absOutput == findJSONLPath() || sameFile(output, findJSONLPath()) {
+				FatalErrorCode(ErrCodeUsage, "--redact requires --output other than the tracked %s", filepath.Base(findJSONLPath()))
+			}
+			var err error
+			if redactor, err = redact.FromConfig(redactPatterns); err != nil {
+				FatalErrorCode(ErrCodeInvalid, "%v", err)
+			}
+		}
+		redactedCount := 0
+		for i, issue := range issues {
+			if redactor == nil {
+				break
+			}
+			var changed bool
+			if issues[i], changed = redactor.Issue(issue); changed {
+				redactedCount++
+			}
 		}
 
-		// Open output
-		out := os.Stdout
-		var tempFile *os.File
-		var tempPath string
-		var finalPath string
 		if output != "" {
 			// Validate output path before creating files
 			if err := validateExportPath(output); err != nil {
@@ -418,58 +574,77 @@ Examples:
 				os.Exit(1)
 			}
 
-			// Create temporary file in same directory for atomic rename
-			dir := filepath.Dir(output)
-			base := filepath.Base(output)
-
 			// Ensure output directory exists
-			if err := os.MkdirAll(dir, 0755); err != nil {
+			if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
 				fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
 				os.Exit(1)
 			}
-
-			var err error
-			tempFile, err = os.CreateTemp(dir, base+".tmp.*")
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error creating temporary file: %v\n", err)
-				os.Exit(1)
-			}
-			tempPath = tempFile.Name()
-			finalPath = output
-
-			// Ensure cleanup on failure
-			defer func() {
-				if tempFile != nil {
-					_ = tempFile.Close()
-					_ = os.Remove(tempPath) // Clean up temp file if we haven't renamed it
-				}
-			}()
-
-			out = tempFile
 		}
+		finalPath := output
 
 		// Write output based on format
 		exportedIDs := make([]string, 0, len(issues))
 		skippedCount := 0
 
-		if format == "obsidian" {
-			// Write Obsidian Tasks markdown format
-			if err := writeObsidianExport(out, issues); err != nil {
-				fmt.Fprintf(os.Stderr, "Error writing Obsidian export: %v\n", err)
-				os.Exit(1)
-			}
-			for _, issue := range issues {
-				exportedIDs = append(exportedIDs, issue.ID)
+		writeExport := func(out io.Writer) error {
+			if format == "obsidian" {
+				// Write Obsidian Tasks markdown format
+				if err := writeObsidianExport(out, issues); err != nil {
+					return fmt.Errorf("writing Obsidian export: %w", err)
+				}
+				for _, issue := range issues {
+					exportedIDs = append(exportedIDs, issue.ID)
+				}
+				return nil
 			}
-		} else {
 			// Write JSONL (timestamp-only deduplication DISABLED due to bd-160)
 			encoder := json.NewEncoder(out)
 			for _, issue := range issues {
 				if err := encoder.Encode(issue); err != nil {
-					fmt.Fprintf(os.Stderr, "Error encoding issue %s: %v\n", issue.ID, err)
-					os.Exit(1)
+					return fmt.Errorf("encoding issue %s: %w", issue.ID, err)
 				}
 				exportedIDs = append(exportedIDs, issue.ID)
+				progress.Add(1)
+			}
+			progress.Finish()
+			return nil
+		}
+
+		// Files are replaced atomically: the new contents are fsynced and the
+		// swap is journaled, so a crash never leaves a truncated export
+		var written *atomicfile.Result
+		if output == "" {
+			if err := writeExport(os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			var err error
+			written, err = atomicfile.Write(finalPath, writeExport)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error exporting to %s: %v\n", finalPath, err)
+				os.Exit(1)
+			}
+		}
+
+		if withheldCount > 0 && !jsonOutput {
+			fmt.Fprintf(os.Stderr, "Withheld %d issue(s) beyond %s visibility\n", withheldCount, audience)
+		}
+		if embargoedCount > 0 && !jsonOutput {
+			fmt.Fprintf(os.Stderr, "Withheld %d embargoed security issue(s)\n", embargoedCount)
+		}
+		if redactor != nil && !jsonOutput {
+			fmt.Fprintf(os.Stderr, "Redacted %d issue(s) in %s\n", redactedCount, finalPath)
+		}
+
+		if sign {
+			sigPath, err := signExport(finalPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error signing %s: %v\n", finalPath, err)
+				os.Exit(1)
+			}
+			if !jsonOutput {
+				fmt.Fprintf(os.Stderr, "Signed %s (%s)\n", finalPath, sigPath)
 			}
 		}
 
@@ -491,33 +666,16 @@ Examples:
 			clearAutoFlushState()
 
 			// Store JSONL file hash for integrity validation
-			// nolint:gosec // G304: finalPath is validated JSONL export path
-			jsonlData, err := os.ReadFile(finalPath)
-			if err == nil {
-				hasher := sha256.New()
-				hasher.Write(jsonlData)
-				fileHash := hex.EncodeToString(hasher.Sum(nil))
-				if err := store.SetJSONLFileHash(ctx, fileHash); err != nil {
+			if written != nil {
+				if err := store.SetJSONLFileHash(ctx, written.SHA256); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to update jsonl_file_hash: %v\n", err)
 				}
+				recordOpLog(ctx, store, finalPath, exportedIDs)
+				recordStatusSnapshot(ctx, store)
 			}
 		}
 
-		// If writing to file, atomically replace the target file
-		if tempFile != nil {
-			// Close the temp file before renaming
-			if err := tempFile.Close(); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to close temporary file: %v\n", err)
-			}
-			tempFile = nil // Prevent cleanup
-
-			// Atomically replace the target file
-			if err := os.Rename(tempPath, finalPath); err != nil {
-				_ = os.Remove(tempPath) // Clean up on failure
-				fmt.Fprintf(os.Stderr, "Error replacing output file: %v\n", err)
-				os.Exit(1)
-			}
-
+		if written != nil {
 			// Set appropriate file permissions (0600: rw-------)
 			// Skip chmod for symlinks - os.Chmod follows symlinks and would change the target's
 			// permissions, which may be in a read-only location (e.g., /nix/store on NixOS).
@@ -567,6 +725,16 @@ Examples:
 			if output != "" {
 				stats["output_file"] = output
 			}
+			if withheldCount > 0 {
+				stats["withheld_issues"] = withheldCount
+			}
+			if embargoedCount > 0 {
+				stats["embargoed_issues"] = embargoedCount
+			}
+			if redactor != nil {
+				stats["redacted_issues"] = redactedCount
+				stats["redactions"] = redactor.Matches
+			}
 			data, _ := json.MarshalIndent(stats, "", "  ")
 			fmt.Fprintln(os.Stderr, string(data))
 		}
@@ -578,6 +746,10 @@ func init() {
 	exportCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
 	exportCmd.Flags().StringP("status", "s", "", "Filter by status")
 	exportCmd.Flags().Bool("force", false, "Force export even if database is empty")
+	exportCmd.Flags().Bool("redact", false, "Replace emails, tokens and redact.patterns matches with [redacted:<rule>] markers (requires -o)")
+	exportCmd.Flags().StringArray("redact-pattern", nil, "Extra regex to redact (repeatable; implies --redact)")
+	exportCmd.Flags().String("visibility", visibility.Internal, "Most confidential visibility to export: public, internal or private")
+	exportCmd.Flags().Bool("sign", false, "Write a detached signature to <output>.sig (check with bd verify <output>)")
 	exportCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output export statistics in JSON format")
 
 	// Filter flags
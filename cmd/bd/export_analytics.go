@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/analytics"
+	"github.com/steveyegge/beads/internal/redact"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+var exportAnalyticsCmd = &cobra.Command{
+	Use:   "analytics",
+	Short: "Export fact tables for DuckDB and BI tools (Parquet or SQLite)",
+	Long: `Export a denormalized snapshot of the issues for analysis in DuckDB,
+pandas or a BI tool. Its tables don't follow the database schema, so
+queries against them keep working as bd changes:
+
+  issues       one row per issue: status, priority, type, assignee, parent,
+               labels (comma-separated), created/started/closed/due times,
+               lead and cycle time in hours, comment and dependency counts
+  transitions  one row per status change: issue, time, from, to, actor
+  work_logs    one row per usage report (bd usage add): issue, time, agent,
+               session, model, tokens, cost
+
+--format parquet writes issues.parquet, transitions.parquet and
+work_logs.parquet into the --output directory. --format sqlite writes one
+database file, replacing any existing file.
+
+Private issues are left out unless --visibility private is given, and
+embargoed security issues are redacted as in bd export.`,
+	Example: `  bd export analytics -o analytics/                  # Parquet files
+  bd export analytics --format sqlite -o beads-analytics.db
+  duckdb -c "SELECT status, count(*) FROM 'analytics/issues.parquet' GROUP BY 1"`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		visibilityFlag, _ := cmd.Flags().GetString("visibility")
+		if format != "parquet" && format != "sqlite" {
+			FatalErrorCode(ErrCodeUsage, "invalid --format %q (must be parquet or sqlite)", format)
+		}
+		if output == "" {
+			FatalErrorCode(ErrCodeUsage, "--output is required (a directory for parquet, a file for sqlite)")
+		}
+		audience, err := visibility.Parse(visibilityFlag)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if err := ensureDirectMode("export analytics requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if err := visibility.Populate(ctx, store, issues); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		issues = visibility.Filter(issues, audience)
+		now := time.Now()
+		exported := make(map[string]bool, len(issues))
+		for i, issue := range issues {
+			issues[i], _ = redact.Embargo(issue, now)
+			exported[issue.ID] = true
+		}
+
+		records, err := loadUsageRecords(time.Time{})
+		if err != nil {
+			FatalErrorRespectJSON("reading work logs: %v", err)
+		}
+		var workLogs []analytics.WorkLogFact
+		for _, r := range records {
+			if !exported[r.IssueID] {
+				continue
+			}
+			workLogs = append(workLogs, analytics.WorkLogFact{
+				ID:      r.ID,
+				IssueID: r.IssueID,
+				At:      analytics.Millis(r.CreatedAt),
+				Agent:   r.Agent,
+				Session: r.Session,
+				Model:   r.Model,
+				Tokens:  r.Tokens,
+				CostUSD: r.CostUSD,
+			})
+		}
+
+		snap, err := analytics.Build(ctx, store, issues, workLogs)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		files := []string{output}
+		if format == "sqlite" {
+			err = analytics.WriteSQLite(ctx, output, snap)
+		} else {
+			files, err = analytics.WriteParquet(output, snap)
+		}
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"format":      format,
+				"files":       files,
+				"issues":      len(snap.Issues),
+				"transitions": len(snap.Transitions),
+				"work_logs":   len(snap.WorkLogs),
+			})
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%s Exported %d issue(s), %d transition(s) and %d work log(s) to %s\n",
+			ui.RenderPass("✓"), len(snap.Issues), len(snap.Transitions), len(snap.WorkLogs), output)
+	},
+}
+
+func init() {
+	exportAnalyticsCmd.Flags().String("format", "parquet", "Output format (parquet, sqlite)")
+	exportAnalyticsCmd.Flags().StringP("output", "o", "", "Output directory (parquet) or database file (sqlite)")
+	exportAnalyticsCmd.Flags().String("visibility", visibility.Internal, "Most confidential visibility to export: public, internal or private")
+	exportCmd.AddCommand(exportAnalyticsCmd)
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// obsidianVaultMarker is the front-matter key that identifies notes written
+// by bd. Only notes carrying it are ever deleted from the vault directory.
+const obsidianVaultMarker = "beads_id"
+
+var exportObsidianCmd = &cobra.Command{
+	Use:   "obsidian",
+	Short: "Write one Markdown note per issue into an Obsidian vault",
+	Long: `Write one Markdown note per issue into a directory of an Obsidian vault.
+
+Each note is named <issue-id>.md and starts with YAML front-matter (status,
+priority, type, assignee, tags, dates) that Obsidian's Properties view and
+Dataview can query. Dependencies become wiki-links, so the graph view shows
+how issues relate. Notes are only rewritten when their content changes, and
+notes for deleted issues are removed; other files in the directory are left
+alone.
+
+To have the daemon keep the vault up to date after every change, set the
+directory in config.yaml (relative to the repository root):
+
+  bd config set obsidian.vault-dir vault/issues
+
+Examples:
+  bd export obsidian --dir ./vault/issues
+  bd export obsidian                         # Uses obsidian.vault-dir`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		if dir == "" {
+			dir = obsidianVaultDir()
+		}
+		if dir == "" {
+			FatalErrorRespectJSON("no vault directory given\nRun: bd export obsidian --dir ./vault/issues\nOr: bd config set obsidian.vault-dir vault/issues")
+		}
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("database not available: %v", err)
+		}
+		ctx := rootCtx
+
+		issues, err := loadObsidianVaultIssues(ctx, store)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		written, removed, err := writeObsidianVault(dir, issues)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"dir":     dir,
+				"issues":  len(issues),
+				"written": written,
+				"removed": removed,
+			})
+			return
+		}
+		fmt.Printf("Exported %d issue(s) to %s (%d written, %d removed)\n", len(issues), dir, written, removed)
+	},
+}
+
+// obsidianVaultDir returns the configured vault directory, resolved against
+// the repository root, or "" when none is configured.
+func obsidianVaultDir() string {
+	dir := config.GetString("obsidian.vault-dir")
+	if dir == "" || filepath.IsAbs(dir) {
+		return dir
+	}
+	if dbPath == "" {
+		return dir
+	}
+	return filepath.Join(filepath.Dir(filepath.Dir(dbPath)), dir)
+}
+
+// loadObsidianVaultIssues returns every live issue with its labels and
+// dependencies populated.
+func loadObsidianVaultIssues(ctx context.Context, s storage.Storage) ([]*types.Issue, error) {
+	issues, err := searchFullIssues(ctx, s, types.IssueFilter{})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	deps, err := s.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	for _, issue := range issues {
+		issue.Labels = labels[issue.ID]
+		issue.Dependencies = deps[issue.ID]
+	}
+	slices.SortFunc(issues, func(a, b *types.Issue) int { return strings.Compare(a.ID, b.ID) })
+	return issues, nil
+}
+
+// writeObsidianVault writes a note per issue into dir and removes notes for
+// issues that no longer exist. It returns how many notes were (re)written
+// and removed.
+func writeObsidianVault(dir string, issues []*types.Issue) (written, removed int, err error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return 0, 0, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	byID := make(map[string]*types.Issue, len(issues))
+	dependents := make(map[string][]*types.Dependency)
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+		for _, dep := range issue.Dependencies {
+			dependents[dep.DependsOnID] = append(dependents[dep.DependsOnID], dep)
+		}
+	}
+
+	keep := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		name := issue.ID + ".md"
+		keep[name] = true
+		path := filepath.Join(dir, name)
+		note := formatObsidianNote(issue, byID, dependents[issue.ID])
+		// #nosec G304 - path is built from the vault dir and an issue ID
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, note) {
+			continue
+		}
+		if err := os.WriteFile(path, note, 0o644); err != nil { // #nosec G306 - notes are meant to be shared
+			return written, removed, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written++
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return written, removed, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".md") || keep[name] {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if !isObsidianVaultNote(path) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return written, removed, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		removed++
+	}
+	return written, removed, nil
+}
+
+// isObsidianVaultNote reports whether the note at path was written by bd.
+func isObsidianVaultNote(path string) bool {
+	data, err := os.ReadFile(path) // #nosec G304 - path is inside the vault dir
+	if err != nil {
+		return false
+	}
+	return bytes.HasPrefix(data, []byte("---\n"+obsidianVaultMarker+": "))
+}
+
+// formatObsidianNote renders an issue as a Markdown note. dependents are
+// the dependencies other issues have on this one.
+func formatObsidianNote(issue *types.Issue, byID map[string]*types.Issue, dependents []*types.Dependency) []byte {
+	var b strings.Builder
+
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "%s: %s\n", obsidianVaultMarker, issue.ID)
+	fmt.Fprintf(&b, "aliases: [%s]\n", strconv.Quote(issue.Title))
+	fmt.Fprintf(&b, "status: %s\n", issue.Status)
+	fmt.Fprintf(&b, "priority: %d\n", issue.Priority)
+	fmt.Fprintf(&b, "type: %s\n", issue.IssueType)
+	if issue.Assignee != "" {
+		fmt.Fprintf(&b, "assignee: %s\n", strconv.Quote(issue.Assignee))
+	}
+	tags := []string{"beads"}
+	for _, label := range issue.Labels {
+		tags = append(tags, obsidianTag(label))
+	}
+	fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(tags, ", "))
+	fmt.Fprintf(&b, "created: %s\n", issue.CreatedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "updated: %s\n", issue.UpdatedAt.UTC().Format(time.RFC3339))
+	if issue.ClosedAt != nil {
+		fmt.Fprintf(&b, "closed: %s\n", issue.ClosedAt.UTC().Format(time.RFC3339))
+	}
+	if issue.DueAt != nil {
+		fmt.Fprintf(&b, "due: %s\n", issue.DueAt.Format("2006-01-02"))
+	}
+	if issue.ExternalRef != nil && *issue.ExternalRef != "" {
+		fmt.Fprintf(&b, "external_ref: %s\n", strconv.Quote(*issue.ExternalRef))
+	}
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s\n", issue.Title)
+	for _, section := range []struct{ heading, text string }{
+		{"", issue.Description},
+		{"Design", issue.Design},
+		{"Acceptance Criteria", issue.AcceptanceCriteria},
+		{"Notes", issue.Notes},
+	} {
+		text := strings.TrimSpace(section.text)
+		if text == "" {
+			continue
+		}
+		if section.heading != "" {
+			fmt.Fprintf(&b, "\n## %s\n", section.heading)
+		}
+		fmt.Fprintf(&b, "\n%s\n", text)
+	}
+
+	link := func(id string) string {
+		if target, ok := byID[id]; ok {
+			return fmt.Sprintf("[[%s|%s]]", id, obsidianLinkAlias(target.Title))
+		}
+		// External or deleted issue: plain text, so no dangling note appears
+		return id
+	}
+	if len(issue.Dependencies) > 0 {
+		b.WriteString("\n## Depends On\n\n")
+		for _, dep := range issue.Dependencies {
+			fmt.Fprintf(&b, "- %s (%s)\n", link(dep.DependsOnID), dep.Type)
+		}
+	}
+	if len(dependents) > 0 {
+		b.WriteString("\n## Dependents\n\n")
+		for _, dep := range dependents {
+			fmt.Fprintf(&b, "- %s (%s)\n", link(dep.IssueID), dep.Type)
+		}
+	}
+	return []byte(b.String())
+}
+
+// obsidianTag converts a label to an Obsidian tag: no spaces, and
+// dimension:value labels become nested dimension/value tags.
+func obsidianTag(label string) string {
+	return strings.NewReplacer(" ", "-", ":", "/", ",", "-").Replace(label)
+}
+
+// obsidianLinkAlias strips the characters that would break a wiki-link's
+// display text.
+func obsidianLinkAlias(title string) string {
+	return strings.TrimSpace(strings.NewReplacer("|", "-", "[", "(", "]", ")", "\n", " ").Replace(title))
+}
+
+func init() {
+	exportObsidianCmd.Flags().String("dir", "", "Vault directory for the notes (default: obsidian.vault-dir)")
+	exportCmd.AddCommand(exportObsidianCmd)
+}
+
+// syncObsidianVault rewrites the obsidian.vault-dir vault after a daemon
+// export. It does nothing when no vault is configured.
+func syncObsidianVault(ctx context.Context, s storage.Storage, beadsDir string, log daemonLogger) {
+	dir := config.GetString("obsidian.vault-dir")
+	if dir == "" {
+		return
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(beadsDir), dir)
+	}
+	issues, err := loadObsidianVaultIssues(ctx, s)
+	if err != nil {
+		log.log("Obsidian vault export failed: %v", err)
+		return
+	}
+	written, removed, err := writeObsidianVault(dir, issues)
+	if err != nil {
+		log.log("Obsidian vault export failed: %v", err)
+		return
+	}
+	if written > 0 || removed > 0 {
+		log.log("Obsidian vault: %d note(s) written, %d removed in %s", written, removed, dir)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestWriteObsidianVault(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	parent := &types.Issue{ID: "bd-1", Title: "Epic [v2]", Status: types.StatusOpen, Priority: 1,
+		IssueType: types.TypeEpic, Labels: []string{"component:api", "needs review"}, CreatedAt: now, UpdatedAt: now}
+	child := &types.Issue{ID: "bd-2", Title: "Task", Description: "Do it.", Status: types.StatusClosed,
+		IssueType: types.TypeTask, CreatedAt: now, UpdatedAt: now, ClosedAt: &now,
+		Dependencies: []*types.Dependency{{IssueID: "bd-2", DependsOnID: "bd-1", Type: types.DepParentChild}}}
+
+	written, removed, err := writeObsidianVault(dir, []*types.Issue{parent, child})
+	if err != nil || written != 2 || removed != 0 {
+		t.Fatalf("first write = %d written, %d removed, %v", written, removed, err)
+	}
+
+	note, _ := os.ReadFile(filepath.Join(dir, "bd-2.md"))
+	for _, want := range []string{"---\nbeads_id: bd-2\n", "status: closed\n", "closed: 2025-01-02T03:04:05Z\n",
+		"# Task\n\nDo it.\n", "## Depends On\n\n- [[bd-1|Epic (v2)]] (parent-child)\n"} {
+		if !strings.Contains(string(note), want) {
+			t.Errorf("bd-2.md missing %q:\n%s", want, note)
+		}
+	}
+	note, _ = os.ReadFile(filepath.Join(dir, "bd-1.md"))
+	for _, want := range []string{"tags: [beads, component/api, needs-review]\n", "## Dependents\n\n- [[bd-2|Task]] (parent-child)\n"} {
+		if !strings.Contains(string(note), want) {
+			t.Errorf("bd-1.md missing %q:\n%s", want, note)
+		}
+	}
+
+	// Unchanged notes aren't rewritten; deleted issues' notes are removed,
+	// but notes bd didn't write are kept.
+	if err := os.WriteFile(filepath.Join(dir, "ideas.md"), []byte("# Ideas\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	parent.Dependencies = nil
+	written, removed, err = writeObsidianVault(dir, []*types.Issue{parent})
+	if err != nil || written != 1 || removed != 1 {
+		t.Fatalf("second write = %d written, %d removed, %v", written, removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ideas.md")); err != nil {
+		t.Errorf("user note was removed: %v", err)
+	}
+	written, _, _ = writeObsidianVault(dir, []*types.Issue{parent})
+	if written != 0 {
+		t.Errorf("unchanged vault rewrote %d note(s)", written)
+	}
+}
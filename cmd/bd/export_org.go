@@ -0,0 +1,175 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/orgmode"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var exportOrgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Export issues as an Emacs org-mode outline",
+	Long: `Export issues as an org-mode file for grooming in Emacs and org-agenda.
+
+Each issue becomes a heading:
+  TODO keyword      status (TODO, STARTED, WAITING, DEFERRED, DONE)
+  [#A]-[#E]         priority P0-P4 (the file declares #+PRIORITIES: A E C)
+  :tags:            labels (characters org doesn't allow become _)
+  DEADLINE          due date
+  SCHEDULED         defer date
+  :ID: property     the issue ID, which bd import org uses to update it
+  Body              description
+
+Children are nested under their parent epics. Closed issues are left out
+unless --all is given.
+
+Edit the file and bring the changes back with:
+  bd import org backlog.org
+
+Examples:
+  bd export org -o backlog.org
+  bd export org --all > everything.org`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		all, _ := cmd.Flags().GetBool("all")
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("database not available: %v", err)
+		}
+		ctx := rootCtx
+
+		entries, err := orgEntries(ctx, store, all)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		prefix, _ := store.GetConfig(ctx, "issue_prefix")
+		title := "Issues"
+		if prefix != "" {
+			title = prefix + " issues"
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			f, err := os.Create(output) // #nosec G304 - user-provided output path
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			defer func() { _ = f.Close() }()
+			w = f
+		}
+		if err := orgmode.Write(w, title, entries); err != nil {
+			FatalErrorRespectJSON("writing org file: %v", err)
+		}
+		if output != "" {
+			fmt.Fprintf(os.Stderr, "Exported %d top-level heading(s) to %s\n", len(entries), output)
+		}
+	},
+}
+
+// orgEntries loads issues and nests children under their parents. Issues
+// whose parent isn't exported become top-level headings. Siblings are
+// ordered by priority, then age.
+func orgEntries(ctx context.Context, s storage.Storage, includeClosed bool) ([]*orgmode.Entry, error) {
+	filter := types.IssueFilter{}
+	if !includeClosed {
+		filter.ExcludeStatus = []types.Status{types.StatusClosed}
+	}
+	issues, err := searchFullIssues(ctx, s, filter)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	deps, err := s.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+
+	byID := make(map[string]*orgmode.Entry, len(issues))
+	for _, issue := range issues {
+		issue.Labels = labels[issue.ID]
+		byID[issue.ID] = &orgmode.Entry{Issue: issue}
+	}
+	var roots []*orgmode.Entry
+	for _, issue := range issues {
+		entry := byID[issue.ID]
+		var parent *orgmode.Entry
+		for _, dep := range deps[issue.ID] {
+			if dep.Type == types.DepParentChild && byID[dep.DependsOnID] != nil && dep.DependsOnID != issue.ID {
+				parent = byID[dep.DependsOnID]
+				break
+			}
+		}
+		if parent != nil && !orgIsAncestor(entry, parent) {
+			parent.Children = append(parent.Children, entry)
+		} else {
+			roots = append(roots, entry)
+		}
+	}
+
+	var sortEntries func([]*orgmode.Entry)
+	sortEntries = func(entries []*orgmode.Entry) {
+		slices.SortFunc(entries, func(a, b *orgmode.Entry) int {
+			return cmp.Or(
+				cmp.Compare(a.Issue.Priority, b.Issue.Priority),
+				a.Issue.CreatedAt.Compare(b.Issue.CreatedAt),
+				cmp.Compare(a.Issue.ID, b.Issue.ID),
+			)
+		})
+		for _, e := range entries {
+			sortEntries(e.Children)
+		}
+	}
+	sortEntries(roots)
+	return roots, nil
+}
+
+// searchFullIssues runs a search and loads the full issues, since search
+// results don't carry scheduling fields such as due and defer dates.
+func searchFullIssues(ctx context.Context, s storage.Storage, filter types.IssueFilter) ([]*types.Issue, error) {
+	matches, err := s.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	issues := make([]*types.Issue, 0, len(matches))
+	for _, match := range matches {
+		issue, err := s.GetIssue(ctx, match.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", match.ID, err)
+		}
+		if issue != nil {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// orgIsAncestor reports whether entry is already above candidate in the
+// tree, which would make nesting candidate's child under it a cycle.
+func orgIsAncestor(entry, candidate *orgmode.Entry) bool {
+	for _, child := range entry.Children {
+		if child == candidate || orgIsAncestor(child, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	exportOrgCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	exportOrgCmd.Flags().Bool("all", false, "Include closed issues")
+	exportCmd.AddCommand(exportOrgCmd)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/feed"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+var feedCmd = &cobra.Command{
+	Use:     "feed",
+	GroupID: "views",
+	Short:   "Feed of recently created and closed issues and comments",
+	Long: `Show recent activity: issues created, issues closed and comments added,
+newest first. With --atom or --rss the feed is written for feed readers and
+chat integrations.
+
+Set feed.listen in config.yaml to have the daemon serve the same feed over
+HTTP at /feed.atom and /feed.rss, with ?label=, ?since= and ?limit=
+parameters:
+  feed:
+    listen: 127.0.0.1:7337
+    token: <secret>      # optional: without it, clients see public issues only
+
+Private issues appear only in the terminal, never in --atom or --rss output
+or the daemon's feed.
+
+Examples:
+  bd feed                                  # Last 7 days
+  bd feed --since 24h --label team:web
+  bd feed --atom -o activity.atom
+  curl 'http://127.0.0.1:7337/feed.atom?label=team:web&since=48h'
+  curl -H 'Authorization: Bearer <secret>' http://127.0.0.1:7337/feed.atom`,
+	Run: func(cmd *cobra.Command, args []string) {
+		atom, _ := cmd.Flags().GetBool("atom")
+		rss, _ := cmd.Flags().GetBool("rss")
+		labels, _ := cmd.Flags().GetStringSlice("label")
+		sinceStr, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+		output, _ := cmd.Flags().GetString("output")
+
+		if atom && rss {
+			FatalError("--atom and --rss are mutually exclusive")
+		}
+		since, err := parseDurationString(sinceStr)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		if err := ensureDirectMode("feed requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+
+		items, err := feed.Collect(ctx, store, feed.Options{
+			Labels: labels,
+			Since:  time.Now().Add(-since),
+			Limit:  limit,
+			// Feed files may be published; private issues stay in the terminal
+			Visibility: feedVisibility(atom || rss),
+		})
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if jsonOutput && !atom && !rss {
+			outputJSON(items)
+			return
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			f, err := os.Create(output) // #nosec G304 -- user-specified output path
+			if err != nil {
+				FatalError("failed to create %s: %v", output, err)
+			}
+			defer func() { _ = f.Close() }()
+			w = f
+		}
+		switch {
+		case atom:
+			err = feed.WriteAtom(w, feedName(), items)
+		case rss:
+			err = feed.WriteRSS(w, feedName(), items)
+		default:
+			printFeed(w, items)
+		}
+		if err != nil {
+			FatalError("failed to write feed: %v", err)
+		}
+		if output != "" {
+			fmt.Fprintf(os.Stderr, "%s Wrote %d item(s) to %s\n", ui.RenderPass("✓"), len(items), output)
+		}
+	},
+}
+
+// feedName names the feed after the issue prefix.
+func feedName() string {
+	if prefix, _ := store.GetConfig(rootCtx, "issue_prefix"); prefix != "" {
+		return prefix
+	}
+	return "beads"
+}
+
+func printFeed(w io.Writer, items []feed.Item) {
+	if len(items) == 0 {
+		fmt.Fprintln(w, "No activity in this period.")
+		return
+	}
+	for _, it := range items {
+		symbol := "+"
+		switch it.Kind {
+		case feed.KindClosed:
+			symbol = ui.RenderPass("✓")
+		case feed.KindCommented:
+			symbol = "»"
+		}
+		line := fmt.Sprintf("%s %s %s", ui.RenderMuted(it.Time.Local().Format("2006-01-02 15:04")), symbol, it.Heading())
+		if it.Author != "" {
+			line += ui.RenderMuted(" (" + it.Author + ")")
+		}
+		fmt.Fprintln(w, line)
+		if it.Kind == feed.KindCommented {
+			fmt.Fprintf(w, "      %s\n", truncateString(strings.Join(strings.Fields(it.Summary), " "), 100))
+		}
+	}
+}
+
+func init() {
+	feedCmd.Flags().Bool("atom", false, "Write an Atom feed")
+	feedCmd.Flags().Bool("rss", false, "Write an RSS 2.0 feed")
+	feedCmd.Flags().StringSliceP("label", "l", nil, "Only issues with all of these labels (repeatable)")
+	feedCmd.Flags().String("since", "7d", "How far back to go (e.g., 24h, 7d)")
+	feedCmd.Flags().Int("limit", 100, "Maximum number of items (0 for no limit)")
+	feedCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	rootCmd.AddCommand(feedCmd)
+}
+
+// feedVisibility returns the most confidential issues bd feed shows: feeds
+// written for readers leave out private issues.
+func feedVisibility(forReaders bool) string {
+	if forReaders {
+		return visibility.Internal
+	}
+	return visibility.Private
+}
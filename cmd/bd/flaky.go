@@ -0,0 +1,308 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/timeparsing"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// Flaky-test issues are bugs labeled flaky whose external_ref names the
+// test, so recording the same test again finds its issue.
+const (
+	flakyLabel       = "flaky"
+	flakyRefPrefix   = "flaky:"
+	flakyTitlePrefix = "Flaky test: "
+)
+
+// flakeLine matches the first line of an occurrence comment.
+var flakeLine = regexp.MustCompile(`^Flaked(?:: (\S+))?$`)
+
+// FlakyRecordResult is the output of bd flaky record for one test.
+type FlakyRecordResult struct {
+	Test        string `json:"test"`
+	IssueID     string `json:"issue_id"`
+	Created     bool   `json:"created"`
+	Reopened    bool   `json:"reopened"`
+	Occurrences int    `json:"occurrences"`
+}
+
+// FlakyTest is a tracked test with its occurrences in the bd flaky top window.
+type FlakyTest struct {
+	Test        string       `json:"test"`
+	IssueID     string       `json:"issue_id"`
+	Status      types.Status `json:"status"`
+	Priority    int          `json:"priority"`
+	Assignee    string       `json:"assignee,omitempty"`
+	Occurrences int          `json:"occurrences"` // In the window
+	Total       int          `json:"total"`       // Ever recorded
+	LastSeen    *time.Time   `json:"last_seen,omitempty"`
+	LastRunURL  string       `json:"last_run_url,omitempty"`
+}
+
+var flakyCmd = &cobra.Command{
+	Use:     "flaky",
+	GroupID: "issues",
+	Short:   "Track flaky tests as issues",
+	Long: `Keep a ledger of flaky tests: one bug per test, labeled flaky, with one
+comment per occurrence.
+
+bd flaky record creates the test's issue the first time it flakes and
+records later occurrences on it, reopening it if it was closed. bd flaky
+top ranks tests by recent occurrences, so the worst offenders get fixed
+first.`,
+}
+
+var flakyRecordCmd = &cobra.Command{
+	Use:   "record <test>...",
+	Short: "Record that tests flaked",
+	Long: `Record one occurrence for each named test. A test's first occurrence
+creates a bug "Flaky test: <test>" labeled flaky, with external_ref
+flaky:<test>; later ones add a "Flaked: <run-url>" comment to it. An
+occurrence on a closed issue reopens it: the fix didn't hold.`,
+	Example: `  bd flaky record TestDebouncer_ThreadSafety --run-url "$CI_JOB_URL"
+  bd flaky record TestA TestB --run-url "$CI_JOB_URL" --message "timeout after 30s"
+  go test ./... -json | jq -r 'select(.Action=="fail" and .Test) | .Test' | xargs bd flaky record`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runURL, _ := cmd.Flags().GetString("run-url")
+		message, _ := cmd.Flags().GetString("message")
+		priority, _ := cmd.Flags().GetInt("priority")
+		assignee, _ := cmd.Flags().GetString("assignee")
+		if strings.ContainsAny(runURL, " \n") {
+			FatalErrorCode(ErrCodeUsage, "--run-url can't contain whitespace")
+		}
+		if priority < 0 || priority > 4 {
+			FatalErrorCode(ErrCodeUsage, "--priority must be between 0 and 4")
+		}
+		CheckReadonly("flaky record")
+		if err := ensureDirectMode("flaky record requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		reporter := getActorWithGit()
+
+		var results []*FlakyRecordResult
+		for _, test := range uniqueStrings(args) {
+			test = strings.TrimSpace(test)
+			if test == "" {
+				continue
+			}
+			result := &FlakyRecordResult{Test: test}
+			ref := flakyRefPrefix + test
+			issue, err := store.GetIssueByExternalRef(ctx, ref)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if issue == nil {
+				issue = &types.Issue{
+					Title:       flakyTitlePrefix + test,
+					Description: fmt.Sprintf("`%s` fails intermittently. Each occurrence is recorded as a comment by bd flaky record.", test),
+					Status:      types.StatusOpen,
+					Priority:    priority,
+					IssueType:   types.TypeBug,
+					Assignee:    assignee,
+					ExternalRef: &ref,
+					CreatedBy:   reporter,
+					Owner:       getOwner(),
+				}
+				if err := store.CreateIssue(ctx, issue, actor); err != nil {
+					FatalErrorRespectJSON("creating issue for %s: %v", test, err)
+				}
+				if err := store.AddLabel(ctx, issue.ID, flakyLabel, actor); err != nil {
+					WarnError("failed to add label %s: %v", flakyLabel, err)
+				}
+				result.Created = true
+			} else if issue.Status == types.StatusClosed {
+				if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+					FatalErrorRespectJSON("reopening %s: %v", issue.ID, err)
+				}
+				result.Reopened = true
+			}
+			result.IssueID = issue.ID
+
+			text := "Flaked"
+			if runURL != "" {
+				text += ": " + runURL
+			}
+			if message = strings.TrimSpace(message); message != "" {
+				text += "\n\n" + message
+			}
+			if _, err := store.AddIssueComment(ctx, issue.ID, reporter, text); err != nil {
+				FatalErrorRespectJSON("recording occurrence on %s: %v", issue.ID, err)
+			}
+			comments, err := store.GetIssueComments(ctx, issue.ID)
+			if err != nil {
+				FatalErrorRespectJSON("getting comments: %v", err)
+			}
+			result.Occurrences = len(flakeOccurrences(comments))
+			results = append(results, result)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(results)
+			return
+		}
+		for _, r := range results {
+			switch {
+			case r.Created:
+				fmt.Printf("%s Created %s for %s\n", ui.RenderPass("✓"), ui.RenderID(r.IssueID), r.Test)
+			case r.Reopened:
+				fmt.Printf("%s Reopened %s: %s flaked again (%d occurrences)\n", ui.RenderAccent("↻"), ui.RenderID(r.IssueID), r.Test, r.Occurrences)
+			default:
+				fmt.Printf("%s Recorded %s on %s (%d occurrences)\n", ui.RenderPass("✓"), r.Test, ui.RenderID(r.IssueID), r.Occurrences)
+			}
+		}
+	},
+}
+
+var flakyTopCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Rank flaky tests by recent occurrences",
+	Long: `Rank flaky tests by how often they flaked in the window (--since, default
+30d), then by how recently. Tests whose issue is closed are left out
+unless --all is given.`,
+	Example: `  bd flaky top
+  bd flaky top --since 7d --limit 5
+  bd flaky top --since 2026-01-01 --all --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+		all, _ := cmd.Flags().GetBool("all")
+		since, err := parseSince(sinceFlag, time.Now())
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if err := ensureDirectMode("flaky top requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		filter := types.IssueFilter{Labels: []string{flakyLabel}}
+		if !all {
+			filter.ExcludeStatus = []types.Status{types.StatusClosed}
+		}
+		issues, err := store.SearchIssues(ctx, "", filter)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ids := make([]string, len(issues))
+		for i, issue := range issues {
+			ids[i] = issue.ID
+		}
+		comments, err := store.GetCommentsForIssues(ctx, ids)
+		if err != nil {
+			FatalErrorRespectJSON("getting comments: %v", err)
+		}
+
+		tests := rankFlakyTests(issues, comments, since)
+		if limit > 0 && len(tests) > limit {
+			tests = tests[:limit]
+		}
+		if jsonOutput {
+			outputJSON(tests)
+			return
+		}
+		if len(tests) == 0 {
+			fmt.Println("No flaky tests recorded in this window.")
+			return
+		}
+		fmt.Printf("\n%s Flaky tests since %s\n\n", ui.RenderAccent("🎲"), since.Local().Format("2006-01-02"))
+		fmt.Printf("  %-10s %-44s %6s %6s  %s\n", "ISSUE", "TEST", "RECENT", "TOTAL", "LAST SEEN")
+		for _, t := range tests {
+			last := "-"
+			if t.LastSeen != nil {
+				last = t.LastSeen.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Printf("  %-10s %-44s %6d %6d  %s\n", t.IssueID, truncateTitle(t.Test, 44), t.Occurrences, t.Total, last)
+		}
+		fmt.Println()
+	},
+}
+
+// parseSince reads a window start: a compact duration counted back
+// from now (30d, 2w) or a date.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if timeparsing.IsCompactDuration(s) && !strings.HasPrefix(s, "+") && !strings.HasPrefix(s, "-") {
+		s = "-" + s
+	}
+	t, err := timeparsing.ParseRelativeTime(s, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q (use e.g. 30d, 2w or YYYY-MM-DD)", s)
+	}
+	return t, nil
+}
+
+// flakeOccurrences returns the occurrence comments among comments.
+func flakeOccurrences(comments []*types.Comment) []*types.Comment {
+	var out []*types.Comment
+	for _, c := range comments {
+		first, _, _ := strings.Cut(c.Text, "\n")
+		if flakeLine.MatchString(first) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// rankFlakyTests counts each flaky issue's occurrences since the window
+// start and orders the tests with any, most occurrences first, then most
+// recently seen.
+func rankFlakyTests(issues []*types.Issue, comments map[string][]*types.Comment, since time.Time) []*FlakyTest {
+	tests := []*FlakyTest{}
+	for _, issue := range issues {
+		test := strings.TrimPrefix(issue.Title, flakyTitlePrefix)
+		if issue.ExternalRef != nil && strings.HasPrefix(*issue.ExternalRef, flakyRefPrefix) {
+			test = strings.TrimPrefix(*issue.ExternalRef, flakyRefPrefix)
+		}
+		t := &FlakyTest{Test: test, IssueID: issue.ID, Status: issue.Status, Priority: issue.Priority, Assignee: issue.Assignee}
+		for _, c := range flakeOccurrences(comments[issue.ID]) {
+			t.Total++
+			if c.CreatedAt.Before(since) {
+				continue
+			}
+			t.Occurrences++
+			if t.LastSeen == nil || !c.CreatedAt.Before(*t.LastSeen) {
+				at := c.CreatedAt
+				t.LastSeen = &at
+				first, _, _ := strings.Cut(c.Text, "\n")
+				t.LastRunURL = flakeLine.FindStringSubmatch(first)[1]
+			}
+		}
+		if t.Occurrences > 0 {
+			tests = append(tests, t)
+		}
+	}
+	sort.SliceStable(tests, func(i, j int) bool {
+		a, b := tests[i], tests[j]
+		if a.Occurrences != b.Occurrences {
+			return a.Occurrences > b.Occurrences
+		}
+		if !a.LastSeen.Equal(*b.LastSeen) {
+			return a.LastSeen.After(*b.LastSeen)
+		}
+		return a.Test < b.Test
+	})
+	return tests
+}
+
+func init() {
+	flakyRecordCmd.Flags().String("run-url", "", "Link to the run where the test flaked")
+	flakyRecordCmd.Flags().StringP("message", "m", "", "Failure output to keep with the occurrence")
+	flakyRecordCmd.Flags().IntP("priority", "p", 2, "Priority of newly created issues (0-4)")
+	flakyRecordCmd.Flags().StringP("assignee", "a", "", "Assignee of newly created issues")
+	flakyTopCmd.Flags().String("since", "30d", "Window start: duration back from now (30d, 2w) or date")
+	flakyTopCmd.Flags().IntP("limit", "n", 10, "Show at most this many tests (0 = all)")
+	flakyTopCmd.Flags().Bool("all", false, "Include tests whose issue is closed")
+	flakyCmd.AddCommand(flakyRecordCmd)
+	flakyCmd.AddCommand(flakyTopCmd)
+	rootCmd.AddCommand(flakyCmd)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestRankFlakyTests(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	ref := func(s string) *string { return &s }
+	issues := []*types.Issue{
+		{ID: "bd-1", Title: "Flaky test: TestA", ExternalRef: ref("flaky:TestA")},
+		{ID: "bd-2", Title: "Flaky test: TestB", ExternalRef: ref("flaky:TestB")},
+		{ID: "bd-3", Title: "Flaky test: TestOld", ExternalRef: ref("flaky:TestOld")},
+		{ID: "bd-4", Title: "Flaky test: TestC", ExternalRef: ref("flaky:TestC")},
+	}
+	flake := func(url string, ago time.Duration) *types.Comment {
+		text := "Flaked"
+		if url != "" {
+			text += ": " + url
+		}
+		return &types.Comment{Text: text + "\n\ntimeout", CreatedAt: now.Add(-ago)}
+	}
+	comments := map[string][]*types.Comment{
+		"bd-1": {flake("u1", 40*24*time.Hour), flake("u2", 2*time.Hour), {Text: "Looking into it", CreatedAt: now}},
+		"bd-2": {flake("", 5*time.Hour), flake("u3", time.Hour)},
+		"bd-3": {flake("u4", 60*24*time.Hour)},
+		"bd-4": {flake("u5", 30*time.Minute)},
+	}
+
+	since, err := parseSince("30d", now)
+	if err != nil || !since.Equal(now.AddDate(0, 0, -30)) {
+		t.Fatalf("parseSince(30d) = %v, %v", since, err)
+	}
+	tests := rankFlakyTests(issues, comments, since)
+	var order []string
+	for _, ft := range tests {
+		order = append(order, ft.Test)
+	}
+	// TestB has two recent occurrences; TestC was seen after TestA; TestOld is outside the window
+	if strings.Join(order, ",") != "TestB,TestC,TestA" {
+		t.Fatalf("order = %v", order)
+	}
+	if a := tests[2]; a.Occurrences != 1 || a.Total != 2 || a.LastRunURL != "u2" {
+		t.Errorf("TestA = %+v", a)
+	}
+	if b := tests[0]; b.LastRunURL != "u3" || !b.LastSeen.Equal(now.Add(-time.Hour)) {
+		t.Errorf("TestB = %+v", b)
+	}
+}
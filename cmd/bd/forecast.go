@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// forecastMaxDays bounds a single simulation trial so a near-zero
+// throughput history can't loop forever.
+const forecastMaxDays = 5 * 365
+
+// forecastPercentiles are the confidence levels reported by `bd forecast`.
+var forecastPercentiles = []int{50, 70, 85, 95}
+
+// ForecastPercentile is the completion date reached with a given confidence.
+type ForecastPercentile struct {
+	Percentile int    `json:"percentile"`
+	Days       int    `json:"days"`
+	Date       string `json:"date"`
+}
+
+// ForecastPoint is one entry of the cumulative completion probability series.
+type ForecastPoint struct {
+	Date        string  `json:"date"`
+	Probability float64 `json:"probability"`
+}
+
+// ForecastResult is the output of `bd forecast`.
+type ForecastResult struct {
+	Milestone   string               `json:"milestone"`
+	Total       int                  `json:"total"`
+	Remaining   int                  `json:"remaining"`
+	HistoryDays int                  `json:"history_days"`
+	AvgPerDay   float64              `json:"avg_closed_per_day"`
+	Trials      int                  `json:"trials"`
+	Percentiles []ForecastPercentile `json:"percentiles"`
+	Series      []ForecastPoint      `json:"series"`
+	Incomplete  int                  `json:"incomplete_trials,omitempty"` // Trials that hit the simulation cap
+}
+
+var forecastCmd = &cobra.Command{
+	Use:     "forecast",
+	GroupID: "views",
+	Short:   "Forecast milestone completion with a Monte Carlo simulation",
+	Long: `Forecast when a milestone will be done by simulating future throughput.
+
+Each trial replays randomly sampled days from recent history (issues closed
+per day) until the milestone's remaining open issues are used up. Running
+many trials gives a probability distribution of completion dates.
+
+A milestone is either an issue ID (an epic: its descendants are counted) or a
+label name: issues labeled milestone:<name> are counted.
+
+Examples:
+  bd forecast --milestone v1.2
+  bd forecast --milestone bd-epic1 --history 60
+  bd forecast --milestone v1.2 --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		milestone, _ := cmd.Flags().GetString("milestone")
+		historyDays, _ := cmd.Flags().GetInt("history")
+		trials, _ := cmd.Flags().GetInt("trials")
+		seed, _ := cmd.Flags().GetInt64("seed")
+
+		if milestone == "" {
+			FatalErrorRespectJSON("--milestone is required")
+		}
+		if historyDays <= 0 || trials <= 0 {
+			FatalErrorRespectJSON("--history and --trials must be positive")
+		}
+		if err := ensureDirectMode("forecast requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+		now := time.Now()
+
+		_, issues, err := loadMilestoneIssues(ctx, milestone)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		remaining := 0
+		for _, issue := range issues {
+			if issue.Status != types.StatusClosed {
+				remaining++
+			}
+		}
+
+		since := now.AddDate(0, 0, -historyDays)
+		closedStatus := types.StatusClosed
+		closed, err := store.SearchIssues(ctx, "", types.IssueFilter{
+			Status:      &closedStatus,
+			ClosedAfter: &since,
+		})
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		throughput := dailyThroughput(closed, now, historyDays)
+
+		if seed == 0 {
+			seed = now.UnixNano()
+		}
+		result, err := runForecast(remaining, throughput, trials, rand.New(rand.NewSource(seed)), now) // #nosec G404 -- simulation, not security
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		result.Milestone = milestone
+		result.Total = len(issues)
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+
+		fmt.Printf("\n%s Forecast for %s\n\n", ui.RenderAccent("🔮"), ui.RenderBold(milestone))
+		fmt.Printf("  Remaining:   %d of %d issue(s)\n", result.Remaining, result.Total)
+		fmt.Printf("  Throughput:  %.2f closed/day over the last %d days\n", result.AvgPerDay, result.HistoryDays)
+		fmt.Printf("  Trials:      %d\n\n", result.Trials)
+		if result.Remaining == 0 {
+			fmt.Printf("%s All issues are closed\n\n", ui.RenderPass("✓"))
+			return
+		}
+		for _, p := range result.Percentiles {
+			fmt.Printf("  %3d%% chance by %s  %s\n", p.Percentile, p.Date, ui.RenderMuted(fmt.Sprintf("(%d days)", p.Days)))
+		}
+		if result.Incomplete > 0 {
+			fmt.Printf("\n%s %d trial(s) did not finish within %d days\n", ui.RenderWarn("⚠"), result.Incomplete, forecastMaxDays)
+		}
+		fmt.Println()
+	},
+}
+
+// dailyThroughput counts issues closed on each of the last `days` days
+// (index 0 is the oldest day). Days with no closures count as zero.
+func dailyThroughput(closed []*types.Issue, now time.Time, days int) []int {
+	counts := make([]int, days)
+	today := now.Truncate(24 * time.Hour)
+	for _, issue := range closed {
+		if issue.ClosedAt == nil {
+			continue
+		}
+		age := int(today.Sub(issue.ClosedAt.Truncate(24*time.Hour)).Hours() / 24)
+		if age < 0 || age >= days {
+			continue
+		}
+		counts[days-1-age]++
+	}
+	return counts
+}
+
+// runForecast simulates completing `remaining` issues by sampling daily
+// throughput with replacement, and summarizes the days each trial needed.
+func runForecast(remaining int, throughput []int, trials int, rng *rand.Rand, now time.Time) (*ForecastResult, error) {
+	result := &ForecastResult{
+		Remaining:   remaining,
+		HistoryDays: len(throughput),
+		Trials:      trials,
+	}
+	total := 0
+	for _, n := range throughput {
+		total += n
+	}
+	if len(throughput) > 0 {
+		result.AvgPerDay = float64(total) / float64(len(throughput))
+	}
+	if remaining == 0 {
+		return result, nil
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("no issues closed in the last %d days; cannot forecast", len(throughput))
+	}
+
+	outcomes := make([]int, trials)
+	for i := range outcomes {
+		left, days := remaining, 0
+		for left > 0 && days < forecastMaxDays {
+			left -= throughput[rng.Intn(len(throughput))]
+			days++
+		}
+		if left > 0 {
+			result.Incomplete++
+		}
+		outcomes[i] = days
+	}
+	sort.Ints(outcomes)
+
+	dateFor := func(days int) string {
+		return now.AddDate(0, 0, days).Format("2006-01-02")
+	}
+	for _, p := range forecastPercentiles {
+		idx := (p*trials+99)/100 - 1
+		if idx < 0 {
+			idx = 0
+		}
+		result.Percentiles = append(result.Percentiles, ForecastPercentile{
+			Percentile: p,
+			Days:       outcomes[idx],
+			Date:       dateFor(outcomes[idx]),
+		})
+	}
+
+	// Cumulative probability of completion by each day in the simulated range.
+	for i := 0; i < len(outcomes); {
+		day := outcomes[i]
+		for i < len(outcomes) && outcomes[i] == day {
+			i++
+		}
+		result.Series = append(result.Series, ForecastPoint{
+			Date:        dateFor(day),
+			Probability: float64(i) / float64(trials),
+		})
+	}
+	return result, nil
+}
+
+func init() {
+	forecastCmd.Flags().String("milestone", "", "Milestone to forecast (epic ID or milestone:<name> label)")
+	forecastCmd.Flags().Int("history", 90, "Days of close history to sample throughput from")
+	forecastCmd.Flags().Int("trials", 10000, "Number of Monte Carlo trials")
+	forecastCmd.Flags().Int64("seed", 0, "Random seed for reproducible results (0 = random)")
+	rootCmd.AddCommand(forecastCmd)
+}
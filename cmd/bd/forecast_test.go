@@ -0,0 +1,79 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestDailyThroughput(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	at := func(daysAgo int) *time.Time {
+		t := now.AddDate(0, 0, -daysAgo)
+		return &t
+	}
+	closed := []*types.Issue{
+		{ID: "bd-1", ClosedAt: at(0)},
+		{ID: "bd-2", ClosedAt: at(0)},
+		{ID: "bd-3", ClosedAt: at(2)},
+		{ID: "bd-4", ClosedAt: at(10)}, // Outside window
+		{ID: "bd-5"},                   // Never closed
+	}
+	got := dailyThroughput(closed, now, 3)
+	want := []int{1, 0, 2}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("dailyThroughput = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRunForecast(t *testing.T) {
+	now := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("constant throughput", func(t *testing.T) {
+		result, err := runForecast(10, []int{2, 2, 2}, 100, rand.New(rand.NewSource(1)), now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range result.Percentiles {
+			if p.Days != 5 || p.Date != "2026-05-15" {
+				t.Errorf("P%d = %d days (%s), want 5 days", p.Percentile, p.Days, p.Date)
+			}
+		}
+		if len(result.Series) != 1 || result.Series[0].Probability != 1 {
+			t.Errorf("unexpected series: %+v", result.Series)
+		}
+	})
+
+	t.Run("percentiles are ordered", func(t *testing.T) {
+		result, err := runForecast(20, []int{0, 1, 3, 0, 2}, 2000, rand.New(rand.NewSource(42)), now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i < len(result.Percentiles); i++ {
+			if result.Percentiles[i].Days < result.Percentiles[i-1].Days {
+				t.Errorf("percentiles not monotonic: %+v", result.Percentiles)
+			}
+		}
+		last := result.Series[len(result.Series)-1]
+		if last.Probability != 1 {
+			t.Errorf("series should end at probability 1, got %v", last.Probability)
+		}
+	})
+
+	t.Run("nothing remaining", func(t *testing.T) {
+		result, err := runForecast(0, []int{0, 0}, 10, rand.New(rand.NewSource(1)), now)
+		if err != nil || len(result.Percentiles) != 0 {
+			t.Errorf("expected empty result, got %+v, %v", result, err)
+		}
+	})
+
+	t.Run("no throughput", func(t *testing.T) {
+		if _, err := runForecast(5, []int{0, 0}, 10, rand.New(rand.NewSource(1)), now); err == nil {
+			t.Error("expected error with zero throughput")
+		}
+	})
+}
@@ -25,6 +25,13 @@ func ensureForkProtection() {
 		return // Not in a git repo
 	}
 
+	// Only protect actual forks - repos with any remote pointing to beads (GH#823)
+	// This prevents false positives on user's own projects that just use beads.
+	// Checked first because it rules out almost every repo with one git call.
+	if !isForkOfBeads(gitRoot) {
+		return // Not a fork of beads, user's own project
+	}
+
 	// Check if fork protection is explicitly disabled via git config (GH#823)
 	// Use: git config beads.fork-protection false
 	if isForkProtectionDisabled(gitRoot) {
@@ -37,12 +44,6 @@ func ensureForkProtection() {
 		return // Maintainers can commit issues.jsonl
 	}
 
-	// Only protect actual forks - repos with any remote pointing to beads (GH#823)
-	// This prevents false positives on user's own projects that just use beads
-	if !isForkOfBeads(gitRoot) {
-		return // Not a fork of beads, user's own project
-	}
-
 	// Get actual git directory (handles worktrees where .git is a file) (GH#827)
 	gitDir, err := git.GetGitDir()
 	if err != nil {
@@ -38,6 +38,8 @@ Gate types:
   gh:run  - Waits for GitHub workflow (Phase 3)
   gh:pr   - Waits for PR merge (Phase 3)
   bead    - Waits for cross-rig bead to close (Phase 4)
+  cmd     - Waits for a shell command to succeed (bd dep add --until-cmd)
+  url     - Waits for a URL to answer 2xx (bd dep add --until-url)
 
 For bead gates, await_id format is <rig>:<bead-id> (e.g., "gastown:gt-abc123").
 
@@ -459,6 +461,8 @@ Gate types:
   gh:pr    - Check pull request merge status
   timer    - Check timer gates (auto-expire based on timeout)
   bead     - Check cross-rig bead gates
+  cmd      - Check shell command conditions
+  url      - Check URL conditions
   all      - Check all gate types
 
 GitHub gates use the 'gh' CLI to query status:
@@ -470,10 +474,14 @@ A gate is resolved when:
   - gh:pr: state=MERGED
   - timer: current time > created_at + timeout
   - bead: target bead status=closed
+  - cmd: the command exits 0 and doesn't print "false" (approved
+    commands only; see bd gate approve)
+  - url: the URL answers with a 2xx status
 
 A gate is escalated when:
   - gh:run: status=completed AND conclusion in (failure, canceled)
   - gh:pr: state=CLOSED AND merged=false
+  - cmd, url: still unmet after the gate's timeout
 
 Examples:
   bd gate check              # Check all gates
@@ -567,6 +575,13 @@ Examples:
 				result.resolved, result.escalated, result.reason, result.err = checkTimer(gate, now)
 			case gate.AwaitType == "bead":
 				result.resolved, result.reason = checkBeadGate(ctx, gate.AwaitID)
+			case isConditionGate(gate):
+				// Command approvals live in the local database
+				if err := ensureStoreActive(); err != nil {
+					result.err = err
+					break
+				}
+				result.resolved, result.escalated, result.reason, result.err = checkConditionGate(ctx, store, gate, conditionDir(), now)
 			default:
 				// Skip unsupported gate types (human gates need manual resolution)
 				continue
@@ -975,7 +990,7 @@ func init() {
 	gateResolveCmd.Flags().StringP("reason", "r", "", "Reason for resolving the gate")
 
 	// gate check flags
-	gateCheckCmd.Flags().StringP("type", "t", "", "Gate type to check (gh, gh:run, gh:pr, timer, bead, all)")
+	gateCheckCmd.Flags().StringP("type", "t", "", "Gate type to check (gh, gh:run, gh:pr, timer, bead, cmd, url, all)")
 	gateCheckCmd.Flags().Bool("dry-run", false, "Show what would happen without making changes")
 	gateCheckCmd.Flags().BoolP("escalate", "e", false, "Escalate failed/expired gates")
 	gateCheckCmd.Flags().IntP("limit", "l", 100, "Limit results (default 100)")
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/httpx"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// Condition gate types: a shell command that must succeed, or a URL that
+// must answer 2xx. bd dep add --until-cmd/--until-url creates them.
+const (
+	gateTypeCmd = "cmd"
+	gateTypeURL = "url"
+)
+
+// conditionTimeout bounds one evaluation of a condition gate.
+const conditionTimeout = time.Minute
+
+// isConditionGate reports whether gate is a cmd or url gate.
+func isConditionGate(gate *types.Issue) bool {
+	return gate.AwaitType == gateTypeCmd || gate.AwaitType == gateTypeURL
+}
+
+// createConditionGate creates an open gate for an external condition and
+// makes issueID depend on it, so the issue stays blocked until the gate
+// resolves. Commands created here are approved to run on this machine.
+func createConditionGate(ctx context.Context, s storage.Storage, issueID, awaitType, condition string, timeout time.Duration) (*types.Issue, error) {
+	title := "Until " + condition
+	if awaitType == gateTypeURL {
+		title = "Until " + condition + " is up"
+	}
+	gate := &types.Issue{
+		Title:       truncateTitle(title, 120),
+		Description: fmt.Sprintf("External condition blocking %s (%s gate)", issueID, awaitType),
+		Status:      types.StatusOpen,
+		Priority:    2,
+		IssueType:   types.TypeGate,
+		AwaitType:   awaitType,
+		AwaitID:     condition,
+		Timeout:     timeout,
+	}
+	err := s.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		if err := enableCustomType(ctx, tx, types.TypeGate, "condition gates"); err != nil {
+			return err
+		}
+		if err := tx.CreateIssue(ctx, gate, actor); err != nil {
+			return err
+		}
+		return tx.AddDependency(ctx, &types.Dependency{
+			IssueID:     issueID,
+			DependsOnID: gate.ID,
+			Type:        types.DepBlocks,
+		}, actor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if awaitType == gateTypeCmd {
+		if err := approveGateCommand(ctx, s, condition); err != nil {
+			return gate, err
+		}
+	}
+	return gate, nil
+}
+
+// enableCustomType adds t to types.custom if it isn't there: gates, goals
+// and the other well-known custom types need it to be created. why names
+// what enabled it, in the note printed.
+func enableCustomType(ctx context.Context, tx storage.Transaction, t types.IssueType, why string) error {
+	custom, err := tx.GetConfig(ctx, "types.custom")
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, name := range strings.Split(custom, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			if name == string(t) {
+				return nil
+			}
+			names = append(names, name)
+		}
+	}
+	names = append(names, string(t))
+	if err := tx.SetConfig(ctx, "types.custom", strings.Join(names, ",")); err != nil {
+		return err
+	}
+	if !jsonOutput {
+		fmt.Printf("%s Enabled the %s issue type (types.custom) for %s\n", ui.RenderMuted("Note:"), t, why)
+	}
+	return nil
+}
+
+// gateCommandKey is the local metadata key that approves a command.
+func gateCommandKey(command string) string {
+	sum := sha256.Sum256([]byte(command))
+	return "gate_cmd_approved:" + hex.EncodeToString(sum[:8])
+}
+
+// approveGateCommand lets cmd gates with this command run on this machine.
+// Approvals live in the local database's metadata, which is never synced:
+// a command a teammate adds doesn't run here until someone approves it.
+func approveGateCommand(ctx context.Context, s storage.Storage, command string) error {
+	return s.SetMetadata(ctx, gateCommandKey(command), time.Now().UTC().Format(time.RFC3339))
+}
+
+func gateCommandApproved(ctx context.Context, s storage.Storage, command string) bool {
+	v, err := s.GetMetadata(ctx, gateCommandKey(command))
+	return err == nil && v != ""
+}
+
+// checkConditionGate evaluates a cmd or url gate. dir is where commands
+// run (the repository root). Unapproved commands stay pending.
+func checkConditionGate(ctx context.Context, s storage.Storage, gate *types.Issue, dir string, now time.Time) (resolved, escalated bool, reason string, err error) {
+	if gate.Timeout > 0 && now.After(gate.CreatedAt.Add(gate.Timeout)) {
+		escalated = true
+	}
+	switch gate.AwaitType {
+	case gateTypeCmd:
+		if !gateCommandApproved(ctx, s, gate.AwaitID) {
+			return false, false, fmt.Sprintf("command not approved on this machine (bd gate approve %s)", gate.ID), nil
+		}
+		resolved, reason, err = checkCmdCondition(ctx, gate.AwaitID, dir)
+	case gateTypeURL:
+		resolved, reason, err = checkURLCondition(ctx, gate.AwaitID)
+	default:
+		return false, false, "", fmt.Errorf("not a condition gate: %s", gate.AwaitType)
+	}
+	if resolved || err != nil {
+		escalated = false
+	} else if escalated {
+		reason += fmt.Sprintf("; still unmet after %s", gate.Timeout)
+	}
+	return resolved, escalated, reason, err
+}
+
+// checkCmdCondition runs command through the shell. The condition holds
+// when it exits 0 and doesn't print "false", so both `test -f x` and
+// `gh pr view 1 --json merged -q .merged` work.
+func checkCmdCondition(ctx context.Context, command, dir string) (bool, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, conditionTimeout)
+	defer cancel()
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	c.Dir = dir
+	var out bytes.Buffer
+	c.Stdout = &out
+	c.Stderr = &out
+	err := c.Run()
+	output := strings.TrimSpace(out.String())
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return false, "", fmt.Errorf("command timed out after %s", conditionTimeout)
+	case errors.As(err, &exitErr):
+		return false, fmt.Sprintf("command exited %d", exitErr.ExitCode()), nil
+	case err != nil:
+		return false, "", err
+	case strings.EqualFold(output, "false"):
+		return false, "command printed false", nil
+	}
+	return true, "command succeeded", nil
+}
+
+// checkURLCondition fetches rawURL; the condition holds on a 2xx answer.
+func checkURLCondition(ctx context.Context, rawURL string) (bool, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, conditionTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return false, "", err
+	}
+	resp, err := httpx.NewClient(conditionTimeout).Do(req)
+	if err != nil {
+		return false, fmt.Sprintf("unreachable: %v", err), nil
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return true, fmt.Sprintf("%s answered %s", rawURL, resp.Status), nil
+	}
+	return false, fmt.Sprintf("%s answered %s", rawURL, resp.Status), nil
+}
+
+// checkDaemonConditions evaluates open condition gates and closes the
+// ones that hold, unblocking the issues waiting on them. It reports
+// whether any gate closed.
+func checkDaemonConditions(ctx context.Context, s storage.Storage, jsonlPath string, log daemonLogger) bool {
+	gateType := types.TypeGate
+	gates, err := s.SearchIssues(ctx, "", types.IssueFilter{
+		IssueType:     &gateType,
+		ExcludeStatus: []types.Status{types.StatusClosed},
+	})
+	if err != nil {
+		log.log("Condition check failed: %v", err)
+		return false
+	}
+	// Commands run from the repository holding .beads/
+	dir := filepath.Dir(filepath.Dir(jsonlPath))
+	now := time.Now()
+	closed := 0
+	for _, gate := range gates {
+		if !isConditionGate(gate) {
+			continue
+		}
+		resolved, _, reason, err := checkConditionGate(ctx, s, gate, dir, now)
+		if err != nil {
+			log.log("Condition gate %s: %v", gate.ID, err)
+			continue
+		}
+		if !resolved {
+			continue
+		}
+		if err := s.CloseIssue(ctx, gate.ID, reason, "daemon", ""); err != nil {
+			log.log("Closing condition gate %s: %v", gate.ID, err)
+			continue
+		}
+		log.log("Condition gate %s resolved: %s", gate.ID, reason)
+		closed++
+	}
+	return closed > 0
+}
+
+var gateApproveCmd = &cobra.Command{
+	Use:   "approve <gate-id>...",
+	Short: "Allow cmd gates to run their command on this machine",
+	Long: `Approve the commands of cmd gates so bd gate check and the daemon run them
+here. Gates sync with the issues, but approvals stay in the local database:
+a command someone else added never runs on your machine until you approve
+it. Gates you create with bd dep add --until-cmd are approved already.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("gate approve")
+		if err := ensureDirectMode("gate approve requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		var approved []map[string]string
+		for _, arg := range args {
+			id, err := utils.ResolvePartialID(ctx, store, arg)
+			if err != nil {
+				FatalErrorRespectJSON("resolving %s: %v", arg, err)
+			}
+			gate, err := store.GetIssue(ctx, id)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if gate == nil || gate.AwaitType != gateTypeCmd {
+				FatalErrorRespectJSON("%s is not a cmd gate", id)
+			}
+			if err := approveGateCommand(ctx, store, gate.AwaitID); err != nil {
+				FatalErrorRespectJSON("approving %s: %v", id, err)
+			}
+			approved = append(approved, map[string]string{"id": id, "command": gate.AwaitID})
+			if !jsonOutput {
+				fmt.Printf("%s Approved %s: %s\n", ui.RenderPass("✓"), id, gate.AwaitID)
+			}
+		}
+		if jsonOutput {
+			outputJSON(approved)
+		}
+	},
+}
+
+func init() {
+	gateApproveCmd.ValidArgsFunction = issueIDCompletion
+	gateCmd.AddCommand(gateApproveCmd)
+}
+
+// depUntilFlags returns bd dep add's --until-cmd and --until-url values.
+func depUntilFlags(cmd *cobra.Command) (untilCmd, untilURL string) {
+	untilCmd, _ = cmd.Flags().GetString("until-cmd")
+	untilURL, _ = cmd.Flags().GetString("until-url")
+	return strings.TrimSpace(untilCmd), strings.TrimSpace(untilURL)
+}
+
+// addConditionDependency implements bd dep add --until-cmd/--until-url.
+func addConditionDependency(cmd *cobra.Command, issueArg, untilCmd, untilURL string) {
+	timeout, _ := cmd.Flags().GetDuration("until-timeout")
+	awaitType, condition := gateTypeCmd, untilCmd
+	if untilURL != "" {
+		awaitType, condition = gateTypeURL, untilURL
+		if u, err := url.Parse(untilURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			FatalErrorCode(ErrCodeUsage, "--until-url must be an http(s) URL, got %q", untilURL)
+		}
+	}
+	if err := ensureDirectMode("dep add --until-* requires direct database access"); err != nil {
+		FatalError("%v", err)
+	}
+	ctx := rootCtx
+	issueID, err := utils.ResolvePartialID(ctx, store, issueArg)
+	if err != nil {
+		FatalErrorRespectJSON("resolving issue ID %s: %v", issueArg, err)
+	}
+	gate, err := createConditionGate(ctx, store, issueID, awaitType, condition, timeout)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	markDirtyAndScheduleFlush()
+
+	if jsonOutput {
+		outputJSON(map[string]interface{}{
+			"status":        "added",
+			"issue_id":      issueID,
+			"depends_on_id": gate.ID,
+			"type":          types.DepBlocks,
+			"await_type":    awaitType,
+			"await_id":      condition,
+		})
+		return
+	}
+	fmt.Printf("%s Added dependency: %s waits for %s gate %s\n", ui.RenderPass("✓"), issueID, awaitType, gate.ID)
+	fmt.Printf("  %s %s\n", ui.RenderMuted("Until:"), condition)
+	fmt.Printf("  %s\n", ui.RenderMuted("The daemon (or bd gate check) closes the gate once the condition holds"))
+}
+
+// conditionDir is where bd gate check runs cmd gates: the repository
+// holding .beads/, like the daemon.
+func conditionDir() string {
+	if beadsDir := beads.FindBeadsDir(); beadsDir != "" {
+		return filepath.Dir(beadsDir)
+	}
+	return "."
+}
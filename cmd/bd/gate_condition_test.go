@@ -0,0 +1,116 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestCheckCmdCondition(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		command string
+		want    bool
+		reason  string
+	}{
+		{"test -f ready.txt", false, "command exited 1"},
+		{"echo false", false, "command printed false"},
+		{"echo true", true, "command succeeded"},
+		{"true", true, "command succeeded"},
+	} {
+		got, reason, err := checkCmdCondition(ctx, tc.command, dir)
+		if err != nil || got != tc.want || reason != tc.reason {
+			t.Errorf("%q = %v, %q, %v; want %v, %q", tc.command, got, reason, err, tc.want, tc.reason)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ready.txt"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, _, _ := checkCmdCondition(ctx, "test -f ready.txt", dir); !got {
+		t.Error("command should run in dir")
+	}
+}
+
+func TestCheckURLCondition(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/up" {
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	if got, reason, err := checkURLCondition(ctx, srv.URL+"/up"); err != nil || !got || !strings.Contains(reason, "200") {
+		t.Errorf("up = %v, %q, %v", got, reason, err)
+	}
+	if got, reason, err := checkURLCondition(ctx, srv.URL+"/down"); err != nil || got || !strings.Contains(reason, "503") {
+		t.Errorf("down = %v, %q, %v", got, reason, err)
+	}
+}
+
+func TestConditionGateApproval(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), "test.db"))
+	issue := &types.Issue{Title: "Deploy", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetConfig(ctx, "types.custom", "molecule"); err != nil {
+		t.Fatal(err)
+	}
+	gate, err := createConditionGate(ctx, s, issue.ID, gateTypeCmd, "true", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if custom, _ := s.GetConfig(ctx, "types.custom"); custom != "molecule,gate" {
+		t.Errorf("types.custom = %q, want gate enabled", custom)
+	}
+	blocked, err := s.GetBlockedIssues(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocked) != 1 || blocked[0].ID != issue.ID {
+		t.Errorf("blocked = %+v, want %s blocked by the gate", blocked, issue.ID)
+	}
+
+	dir := t.TempDir()
+	resolved, _, reason, err := checkConditionGate(ctx, s, gate, dir, time.Now())
+	if err != nil || !resolved {
+		t.Errorf("approved gate = %v, %q, %v", resolved, reason, err)
+	}
+
+	// A gate synced from elsewhere carries a command this machine hasn't approved
+	synced := &types.Issue{ID: "x-1", AwaitType: gateTypeCmd, AwaitID: "touch pwned", CreatedAt: time.Now()}
+	resolved, _, reason, err = checkConditionGate(ctx, s, synced, dir, time.Now())
+	if err != nil || resolved || !strings.Contains(reason, "not approved") {
+		t.Errorf("unapproved gate = %v, %q, %v", resolved, reason, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pwned")); err == nil {
+		t.Error("unapproved command ran")
+	}
+
+	// Unmet past the timeout escalates
+	late := &types.Issue{AwaitType: gateTypeCmd, AwaitID: "true", Timeout: time.Minute, CreatedAt: time.Now().Add(-time.Hour)}
+	if _, escalated, _, _ := checkConditionGate(ctx, s, late, dir, time.Now()); escalated {
+		t.Error("a met condition should not escalate")
+	}
+	late.AwaitID = "false"
+	if err := approveGateCommand(ctx, s, "false"); err != nil {
+		t.Fatal(err)
+	}
+	if _, escalated, reason, _ := checkConditionGate(ctx, s, late, dir, time.Now()); !escalated {
+		t.Errorf("overdue gate did not escalate: %q", reason)
+	}
+}
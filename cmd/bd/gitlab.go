@@ -0,0 +1,663 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/gitlab"
+	"github.com/steveyegge/beads/internal/linear"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// gitlabCmd is the root command for GitLab integration.
+var gitlabCmd = &cobra.Command{
+	Use:     "gitlab",
+	GroupID: "advanced",
+	Short:   "GitLab integration commands",
+	Long: `Synchronize issues with a GitLab project and link merge requests.
+
+Configuration:
+  bd config set gitlab.project "group/project"          # Path or numeric ID
+  bd auth login gitlab                                  # Token, kept out of the config
+  bd config set gitlab.url "https://gitlab.example.com" # Self-hosted (default: gitlab.com)
+  bd config set gitlab.close_on_merge false             # Don't close issues on MR merge
+
+Environment variables (alternative to config):
+  GITLAB_TOKEN   - GitLab access token (api scope)
+  GITLAB_URL     - GitLab instance URL
+  GITLAB_PROJECT - GitLab project path or ID
+
+Data mapping:
+  GitLab has no priority or type field, so beads uses scoped labels:
+  priority::0-4, type::<type> and status::in_progress / status::blocked.
+  Opened/closed maps to open/closed; other labels sync as labels.
+
+Examples:
+  bd gitlab sync --pull       # Import issues from GitLab
+  bd gitlab sync --push       # Export issues to GitLab
+  bd gitlab sync              # Bidirectional sync (pull then push)
+  bd gitlab link-mrs          # Link MRs that mention bd IDs, close on merge
+  bd gitlab status            # Show sync status`,
+}
+
+var gitlabSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Synchronize issues with GitLab",
+	Long: `Synchronize issues between beads and a GitLab project.
+
+Modes:
+  --pull         Import issues from GitLab into beads
+  --push         Export issues from beads to GitLab
+  (no flags)     Bidirectional sync: pull then push
+
+Only issues changed since the last sync are transferred. When an issue
+changed on both sides, the newer version wins.
+
+Progress is checkpointed as the sync runs. If it is interrupted (Ctrl-C,
+--timeout, a network error), the next sync resumes: a finished pull is not
+repeated and issues already pushed are skipped. --restart discards the
+checkpoint.
+
+Examples:
+  bd gitlab sync --pull --state opened
+  bd gitlab sync --push --create-only
+  bd gitlab sync --dry-run
+  bd gitlab sync --restart    # Ignore an interrupted sync's checkpoint`,
+	Run: runGitLabSync,
+}
+
+var gitlabLinkMRsCmd = &cobra.Command{
+	Use:   "link-mrs",
+	Short: "Link merge requests that mention issue IDs",
+	Long: `Scan the project's merge requests for beads issue IDs and link them.
+
+Every MR that mentions an issue ID in its title, description or source
+branch is added to that issue's refs. When an MR has merged, the issues it
+closes are closed with reason "Merged in !<n>": IDs in its title or source
+branch, and IDs after a closing keyword in its description ("Closes bd-12").
+Set gitlab.close_on_merge to false to only link.
+
+Only MRs updated since the last run are scanned (gitlab.mr_last_sync);
+--all rescans every MR.
+
+Examples:
+  bd gitlab link-mrs
+  bd gitlab link-mrs --dry-run --json`,
+	Run: runGitLabLinkMRs,
+}
+
+var gitlabStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show GitLab sync status",
+	Run:   runGitLabStatus,
+}
+
+func init() {
+	gitlabSyncCmd.Flags().Bool("pull", false, "Pull issues from GitLab")
+	gitlabSyncCmd.Flags().Bool("push", false, "Push issues to GitLab")
+	gitlabSyncCmd.Flags().Bool("dry-run", false, "Preview sync without making changes")
+	gitlabSyncCmd.Flags().Bool("create-only", false, "Only create new issues, don't update existing")
+	gitlabSyncCmd.Flags().String("state", "all", "Issue state to pull: opened, closed, all")
+	gitlabSyncCmd.Flags().Bool("restart", false, "Discard the checkpoint of an interrupted sync and start over")
+	gitlabLinkMRsCmd.Flags().Bool("dry-run", false, "Preview links and closes without making changes")
+	gitlabLinkMRsCmd.Flags().Bool("all", false, "Scan all merge requests, not just those updated since the last run")
+
+	gitlabCmd.AddCommand(gitlabSyncCmd)
+	gitlabCmd.AddCommand(gitlabLinkMRsCmd)
+	gitlabCmd.AddCommand(gitlabStatusCmd)
+	rootCmd.AddCommand(gitlabCmd)
+}
+
+func runGitLabSync(cmd *cobra.Command, args []string) {
+	pull, _ := cmd.Flags().GetBool("pull")
+	push, _ := cmd.Flags().GetBool("push")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	createOnly, _ := cmd.Flags().GetBool("create-only")
+	state, _ := cmd.Flags().GetString("state")
+	restart, _ := cmd.Flags().GetBool("restart")
+
+	if !dryRun {
+		CheckReadonly("gitlab sync")
+	}
+	if err := ensureStoreActive(); err != nil {
+		FatalErrorRespectJSON("database not available: %v", err)
+	}
+	ctx := rootCtx
+	client, err := getGitLabClient(ctx)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	if !pull && !push {
+		pull = true
+		push = true
+	}
+
+	result := &gitlab.SyncResult{Success: true}
+	var lastSync *time.Time
+	if raw, _ := store.GetConfig(ctx, "gitlab.last_sync"); raw != "" {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			lastSync = &t
+		} else {
+			result.Warnings = append(result.Warnings, "invalid gitlab.last_sync, doing a full sync")
+		}
+	}
+	checkpoint, err := beginSyncCheckpoint(ctx, store, "gitlab", restart, dryRun)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+
+	pulledRefs := make(map[string]bool)
+	if pull && checkpoint.Pulled {
+		for _, ref := range checkpoint.PulledRefs {
+			pulledRefs[ref] = true
+		}
+		if !jsonOutput {
+			fmt.Println("✓ Pull already finished before the interruption")
+		}
+	} else if pull {
+		if !jsonOutput {
+			fmt.Println("→ Pulling issues from GitLab...")
+		}
+		stats, err := doPullFromGitLab(ctx, client, state, lastSync, dryRun, pulledRefs)
+		if err != nil {
+			FatalErrorRespectJSON("pulling from GitLab: %v", err)
+		}
+		result.Pulled = stats.Created + stats.Updated
+		result.Created += stats.Created
+		result.Updated += stats.Updated
+		result.Skipped += stats.Skipped
+		if !dryRun {
+			if err := checkpoint.markPulled(ctx, store, pulledRefs); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to save checkpoint: %v", err))
+			}
+		}
+		if !jsonOutput {
+			fmt.Printf("✓ Pulled %d issues (%d created, %d updated)\n", result.Pulled, stats.Created, stats.Updated)
+		}
+	}
+
+	if push {
+		if !jsonOutput {
+			fmt.Println("→ Pushing issues to GitLab...")
+		}
+		stats, err := doPushToGitLab(ctx, client, lastSync, dryRun, createOnly, pulledRefs, checkpoint)
+		if err != nil {
+			FatalErrorRespectJSON("pushing to GitLab: %v", err)
+		}
+		result.Pushed = stats.Created + stats.Updated
+		result.Created += stats.Created
+		result.Updated += stats.Updated
+		result.Skipped += stats.Skipped
+		result.Errors += stats.Errors
+		if !jsonOutput {
+			fmt.Printf("✓ Pushed %d issues (%d created, %d updated)\n", result.Pushed, stats.Created, stats.Updated)
+		}
+	}
+
+	if !dryRun {
+		// Record when this sync (or the interrupted one it resumed) started,
+		// so changes made during it are picked up next time
+		result.LastSync = checkpoint.Started.UTC().Format(time.RFC3339)
+		if err := store.SetConfig(ctx, "gitlab.last_sync", result.LastSync); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to update last_sync: %v", err))
+		}
+		if err := checkpoint.clear(ctx, store); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to clear checkpoint: %v", err))
+		}
+		markDirtyAndScheduleFlush()
+	}
+
+	if jsonOutput {
+		outputJSON(result)
+		return
+	}
+	if dryRun {
+		fmt.Println("\n✓ Dry run complete (no changes made)")
+	} else {
+		fmt.Println("\n✓ GitLab sync complete")
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("  - %s\n", w)
+	}
+}
+
+// doPullFromGitLab imports GitLab issues updated since lastSync. Issues
+// whose local copy is newer than the GitLab version are skipped so the
+// push step sends the local changes instead. The external refs of pulled
+// issues are recorded in pulled.
+func doPullFromGitLab(ctx context.Context, client *gitlab.Client, state string, lastSync *time.Time, dryRun bool, pulled map[string]bool) (*gitlab.PullStats, error) {
+	stats := &gitlab.PullStats{}
+	remote, err := client.FetchIssues(ctx, state, lastSync)
+	if err != nil {
+		return stats, err
+	}
+	if len(remote) == 0 {
+		return stats, nil
+	}
+
+	local, err := gitlabLinkedIssues(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	var incoming []*types.Issue
+	for i := range remote {
+		issue := gitlab.IssueToBeads(&remote[i])
+		if existing, ok := local[*issue.ExternalRef]; ok {
+			if existing.UpdatedAt.After(issue.UpdatedAt) {
+				stats.Skipped++
+				continue
+			}
+			issue.ID = existing.ID
+		}
+		incoming = append(incoming, issue)
+		pulled[*issue.ExternalRef] = true
+	}
+	if len(incoming) == 0 {
+		return stats, nil
+	}
+
+	// New issues get hash IDs, the same way Linear imports do
+	prefix, _ := store.GetConfig(ctx, "issue_prefix")
+	if prefix == "" {
+		prefix = "bd"
+	}
+	existing, err := store.SearchIssues(ctx, "", types.IssueFilter{IncludeTombstones: true})
+	if err != nil {
+		return stats, fmt.Errorf("failed to fetch existing issues for ID collision avoidance: %w", err)
+	}
+	usedIDs := make(map[string]bool, len(existing))
+	for _, issue := range existing {
+		usedIDs[issue.ID] = true
+	}
+	if err := linear.GenerateIssueIDs(incoming, prefix, "gitlab-import", linear.IDGenerationOptions{UsedIDs: usedIDs}); err != nil {
+		return stats, fmt.Errorf("failed to generate issue IDs: %w", err)
+	}
+
+	result, err := importIssuesCore(ctx, dbPath, store, incoming, ImportOptions{DryRun: dryRun, LockActor: getActor(), CheckRules: true})
+	if err != nil {
+		return stats, fmt.Errorf("import failed: %w", err)
+	}
+	stats.Created = result.Created
+	stats.Updated = result.Updated
+	stats.Skipped += result.Skipped
+	return stats, nil
+}
+
+// doPushToGitLab creates GitLab issues for local issues without an
+// external ref, and updates linked issues changed locally since lastSync
+// (all of them on the first sync), except those just pulled. Each pushed
+// issue is recorded in checkpoint, and issues it already holds are skipped.
+func doPushToGitLab(ctx context.Context, client *gitlab.Client, lastSync *time.Time, dryRun, createOnly bool, pulled map[string]bool, checkpoint *syncCheckpoint) (*gitlab.PushStats, error) {
+	stats := &gitlab.PushStats{}
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return stats, fmt.Errorf("failed to get local issues: %w", err)
+	}
+
+	for _, issue := range issues {
+		if err := ctx.Err(); err != nil {
+			return stats, fmt.Errorf("interrupted after pushing %d issue(s), re-run to resume: %w",
+				len(checkpoint.Pushed), context.Cause(ctx))
+		}
+		if issue.IsTombstone() {
+			continue
+		}
+		if checkpoint.wasPushed(issue.ID) {
+			stats.Skipped++
+			continue
+		}
+		if issue.ExternalRef == nil {
+			if dryRun {
+				stats.Created++
+				continue
+			}
+			if err := createGitLabIssue(ctx, client, issue); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to create %s in GitLab: %v\n", issue.ID, err)
+				stats.Errors++
+				continue
+			}
+			stats.Created++
+			if err := checkpoint.markPushed(ctx, store, issue.ID); err != nil {
+				return stats, fmt.Errorf("failed to save checkpoint: %w", err)
+			}
+			continue
+		}
+
+		ref := *issue.ExternalRef
+		iid := gitlab.ExtractIID(ref)
+		if iid == 0 || !strings.HasPrefix(ref, client.BaseURL) || createOnly || pulled[ref] ||
+			(lastSync != nil && !issue.UpdatedAt.After(*lastSync)) {
+			stats.Skipped++
+			continue
+		}
+		if dryRun {
+			stats.Updated++
+			continue
+		}
+		labels, err := store.GetLabels(ctx, issue.ID)
+		if err != nil {
+			return stats, fmt.Errorf("failed to get labels for %s: %w", issue.ID, err)
+		}
+		fields := map[string]interface{}{
+			"title":       issue.Title,
+			"description": issue.Description,
+			"labels":      strings.Join(gitlab.LabelsForGitLab(issue, labels), ","),
+			"state_event": gitlab.StateEvent(issue.Status),
+		}
+		if _, err := client.UpdateIssue(ctx, iid, fields); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update %s in GitLab: %v\n", issue.ID, err)
+			stats.Errors++
+			continue
+		}
+		stats.Updated++
+		if err := checkpoint.markPushed(ctx, store, issue.ID); err != nil {
+			return stats, fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+		if !jsonOutput {
+			fmt.Printf("  Updated: %s -> #%d\n", issue.ID, iid)
+		}
+	}
+	return stats, nil
+}
+
+// createGitLabIssue files issue in GitLab and records the new issue's URL
+// as its external ref.
+func createGitLabIssue(ctx context.Context, client *gitlab.Client, issue *types.Issue) error {
+	labels, err := store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		return err
+	}
+	created, err := client.CreateIssue(ctx, issue.Title, issue.Description, gitlab.LabelsForGitLab(issue, labels))
+	if err != nil {
+		return err
+	}
+	if issue.Status == types.StatusClosed {
+		if _, err := client.UpdateIssue(ctx, created.IID, map[string]interface{}{"state_event": "close"}); err != nil {
+			return err
+		}
+	}
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"external_ref": created.WebURL}, actor); err != nil {
+		return fmt.Errorf("created #%d but failed to set external_ref: %w", created.IID, err)
+	}
+	if !jsonOutput {
+		fmt.Printf("  Created: %s -> #%d\n", issue.ID, created.IID)
+	}
+	return nil
+}
+
+// gitlabLinkedIssues returns local issues linked to GitLab, by external ref.
+func gitlabLinkedIssues(ctx context.Context) (map[string]*types.Issue, error) {
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local issues: %w", err)
+	}
+	linked := make(map[string]*types.Issue)
+	for _, issue := range issues {
+		if issue.ExternalRef != nil && gitlab.IsGitLabExternalRef(*issue.ExternalRef) {
+			linked[*issue.ExternalRef] = issue
+		}
+	}
+	return linked, nil
+}
+
+func runGitLabLinkMRs(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	all, _ := cmd.Flags().GetBool("all")
+	if !dryRun {
+		CheckReadonly("gitlab link-mrs")
+	}
+	if err := ensureStoreActive(); err != nil {
+		FatalErrorRespectJSON("database not available: %v", err)
+	}
+	ctx := rootCtx
+	client, err := getGitLabClient(ctx)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	var since *time.Time
+	if raw, _ := store.GetConfig(ctx, "gitlab.mr_last_sync"); raw != "" && !all {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			since = &t
+		}
+	}
+	started := time.Now()
+	mrs, err := client.FetchMergeRequests(ctx, "all", since)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+
+	links, err := linkGitLabMRs(ctx, mrs, dryRun)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+
+	if !dryRun {
+		if err := store.SetConfig(ctx, "gitlab.mr_last_sync", started.UTC().Format(time.RFC3339)); err != nil {
+			WarnError("failed to update gitlab.mr_last_sync: %v", err)
+		}
+		if len(links) > 0 {
+			markDirtyAndScheduleFlush()
+		}
+	}
+
+	if jsonOutput {
+		if links == nil {
+			links = []gitlab.MRLink{}
+		}
+		outputJSON(links)
+		return
+	}
+	if len(links) == 0 {
+		fmt.Printf("Scanned %d merge request(s); nothing new to link\n", len(mrs))
+		return
+	}
+	verb := map[bool]string{true: "Would ", false: ""}[dryRun]
+	for _, l := range links {
+		switch {
+		case l.Closed:
+			fmt.Printf("  %sclose %s (merged in !%d)\n", verb, l.IssueID, l.MR)
+		case l.Linked:
+			fmt.Printf("  %slink %s -> !%d (%s)\n", verb, l.IssueID, l.MR, l.State)
+		}
+	}
+}
+
+// linkGitLabMRs adds each merge request to the refs of the issues it
+// mentions and, when it has merged, closes the issues it closes (unless
+// gitlab.close_on_merge is false). Returns the links and closes made, or
+// that would be made in a dry run.
+func linkGitLabMRs(ctx context.Context, mrs []gitlab.MergeRequest, dryRun bool) ([]gitlab.MRLink, error) {
+	rs, err := refs.For(store)
+	if err != nil {
+		return nil, err
+	}
+	prefix, _ := store.GetConfig(ctx, "issue_prefix")
+	if prefix == "" {
+		prefix = "bd"
+	}
+	closeOnMerge := true
+	if v, _ := getGitLabConfig(ctx, "gitlab.close_on_merge"); v == "false" {
+		closeOnMerge = false
+	}
+
+	var links []gitlab.MRLink
+	for i := range mrs {
+		mr := &mrs[i]
+		closes := make(map[string]bool)
+		if mr.State == "merged" && closeOnMerge {
+			for _, id := range gitlab.ClosingIDs(mr, prefix) {
+				closes[id] = true
+			}
+		}
+		for _, id := range gitlab.MentionedIDs(mr.Title+"\n"+mr.Description+"\n"+mr.SourceBranch, prefix) {
+			issue, err := store.GetIssue(ctx, id)
+			if err != nil || issue == nil {
+				continue
+			}
+			link := gitlab.MRLink{IssueID: id, MR: mr.IID, URL: mr.WebURL, State: mr.State}
+			existing, err := rs.GetRefs(ctx, id)
+			if err != nil {
+				return links, err
+			}
+			link.Linked = !hasRefValue(issue, existing, mr.WebURL)
+			link.Closed = closes[id] && issue.Status != types.StatusClosed
+			if !link.Linked && !link.Closed {
+				continue
+			}
+			if !dryRun {
+				if link.Linked {
+					if err := rs.AddRef(ctx, id, types.Ref{Type: types.RefURL, Value: mr.WebURL}, actor); err != nil {
+						return links, fmt.Errorf("linking !%d to %s: %w", mr.IID, id, err)
+					}
+				}
+				if link.Closed {
+					reason := fmt.Sprintf("Merged in !%d", mr.IID)
+					if err := store.CloseIssue(ctx, id, reason, actor, ""); err != nil {
+						return links, fmt.Errorf("closing %s: %w", id, err)
+					}
+				}
+			}
+			links = append(links, link)
+		}
+	}
+
+	return links, nil
+}
+
+// hasRefValue reports whether value is already the issue's external ref or
+// one of its refs.
+func hasRefValue(issue *types.Issue, existing []*types.Ref, value string) bool {
+	if issue.ExternalRef != nil && *issue.ExternalRef == value {
+		return true
+	}
+	for _, r := range existing {
+		if r.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func runGitLabStatus(cmd *cobra.Command, args []string) {
+	if err := ensureStoreActive(); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	ctx := rootCtx
+	baseURL, _ := getGitLabConfig(ctx, "gitlab.url")
+	if baseURL == "" {
+		baseURL = gitlab.DefaultBaseURL
+	}
+	project, _ := getGitLabConfig(ctx, "gitlab.project")
+	token, _ := getGitLabConfig(ctx, "gitlab.token")
+	lastSync, _ := store.GetConfig(ctx, "gitlab.last_sync")
+	mrLastSync, _ := store.GetConfig(ctx, "gitlab.mr_last_sync")
+	checkpoint, interrupted, _ := loadSyncCheckpoint(ctx, store, "gitlab")
+
+	linked, err := gitlabLinkedIssues(ctx)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	pending := 0
+	for _, issue := range issues {
+		if issue.ExternalRef == nil && !issue.IsTombstone() {
+			pending++
+		}
+	}
+
+	configured := project != "" && token != ""
+	if jsonOutput {
+		outputJSON(map[string]interface{}{
+			"configured":      configured,
+			"url":             baseURL,
+			"project":         project,
+			"has_token":       token != "",
+			"last_sync":       lastSync,
+			"mr_last_sync":    mrLastSync,
+			"with_gitlab_ref": len(linked),
+			"pending_push":    pending,
+			"interrupted":     interrupted,
+		})
+		return
+	}
+
+	fmt.Println("GitLab Sync Status")
+	fmt.Println("==================")
+	fmt.Println()
+	if !configured {
+		fmt.Println("Status: Not configured")
+		fmt.Println()
+		fmt.Println("To configure GitLab integration:")
+		fmt.Println("  bd config set gitlab.project \"group/project\"")
+		fmt.Println("  bd auth login gitlab")
+		fmt.Println("  bd config set gitlab.url \"https://gitlab.example.com\"  # Self-hosted only")
+		return
+	}
+	fmt.Printf("Instance:     %s\n", baseURL)
+	fmt.Printf("Project:      %s\n", project)
+	fmt.Printf("Token:        %s\n", maskAPIKey(token))
+	fmt.Printf("Last Sync:    %s\n", valueOr(lastSync, "Never"))
+	fmt.Printf("MRs Scanned:  %s\n", valueOr(mrLastSync, "Never"))
+	if interrupted {
+		fmt.Printf("Interrupted:  sync started %s, %d issue(s) pushed (next sync resumes)\n",
+			checkpoint.Started.Local().Format("2006-01-02 15:04"), len(checkpoint.Pushed))
+	}
+	fmt.Println()
+	fmt.Printf("With GitLab:  %d\n", len(linked))
+	fmt.Printf("Local Only:   %d\n", pending)
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// getGitLabConfig reads a GitLab config value from the project config,
+// falling back to its environment variable and, for the token, the
+// credentials store.
+func getGitLabConfig(ctx context.Context, key string) (value string, source string) {
+	if store != nil {
+		if value, _ = store.GetConfig(ctx, key); value != "" {
+			return value, "project config (bd config)"
+		}
+	}
+	envKey := map[string]string{
+		"gitlab.token":   "GITLAB_TOKEN",
+		"gitlab.url":     "GITLAB_URL",
+		"gitlab.project": "GITLAB_PROJECT",
+	}[key]
+	if envKey != "" {
+		if value = os.Getenv(envKey); value != "" {
+			return value, fmt.Sprintf("environment variable (%s)", envKey)
+		}
+	}
+	if key == "gitlab.token" {
+		return lookupCredential(key)
+	}
+	return "", ""
+}
+
+// getGitLabClient creates a configured GitLab client from beads config.
+func getGitLabClient(ctx context.Context) (*gitlab.Client, error) {
+	token, _ := getGitLabConfig(ctx, "gitlab.token")
+	if token == "" {
+		return nil, fmt.Errorf("GitLab token not configured\nRun: bd auth login gitlab\nOr: export GITLAB_TOKEN=YOUR_TOKEN")
+	}
+	project, _ := getGitLabConfig(ctx, "gitlab.project")
+	if project == "" {
+		return nil, fmt.Errorf("gitlab.project not configured\nRun: bd config set gitlab.project \"group/project\"")
+	}
+	baseURL, _ := getGitLabConfig(ctx, "gitlab.url")
+	return gitlab.NewClient(baseURL, token, project), nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/gitlab"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestGitLabPullAndLinkMRs(t *testing.T) {
+	testStore, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "glpat-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !strings.HasSuffix(r.URL.Path, "/issues") || r.Method != http.MethodGet {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]gitlab.Issue{{
+			ID: 100, IID: 7, Title: "Login fails", Description: "Stack trace",
+			State:     "opened",
+			Labels:    []string{"priority::1", "type::bug", "status::in_progress", "backend"},
+			Assignees: []gitlab.User{{Username: "alice"}},
+			WebURL:    server.URL + "/g/p/-/issues/7",
+			CreatedAt: time.Now().Add(-time.Hour), UpdatedAt: time.Now().Add(-time.Minute),
+		}})
+	}))
+	defer server.Close()
+
+	origStore, origActor := store, actor
+	store, actor = testStore, "test-actor"
+	t.Cleanup(func() { store, actor = origStore, origActor })
+
+	client := gitlab.NewClient(server.URL, "glpat-test", "g/p")
+	pulled := make(map[string]bool)
+	stats, err := doPullFromGitLab(ctx, client, "all", nil, false, pulled)
+	if err != nil {
+		t.Fatalf("doPullFromGitLab: %v", err)
+	}
+	if stats.Created != 1 || len(pulled) != 1 {
+		t.Fatalf("stats = %+v, pulled = %v; want one created", stats, pulled)
+	}
+
+	issue, err := testStore.GetIssueByExternalRef(ctx, server.URL+"/g/p/-/issues/7")
+	if err != nil || issue == nil {
+		t.Fatalf("pulled issue not found: %v", err)
+	}
+	if issue.Priority != 1 || issue.IssueType != types.TypeBug || issue.Status != types.StatusInProgress || issue.Assignee != "alice" {
+		t.Errorf("pulled issue = priority %d, type %s, status %s, assignee %q", issue.Priority, issue.IssueType, issue.Status, issue.Assignee)
+	}
+	if labels, _ := testStore.GetLabels(ctx, issue.ID); len(labels) != 1 || labels[0] != "backend" {
+		t.Errorf("labels = %v, want [backend]", labels)
+	}
+
+	// A merged MR naming the issue in its description with a closing keyword
+	// links and closes it; a second run changes nothing
+	mrs := []gitlab.MergeRequest{{
+		IID: 3, Title: "Fix login", Description: "Closes " + issue.ID + ".",
+		State: "merged", SourceBranch: "fix-login", WebURL: server.URL + "/g/p/-/merge_requests/3",
+	}}
+	links, err := linkGitLabMRs(ctx, mrs, false)
+	if err != nil {
+		t.Fatalf("linkGitLabMRs: %v", err)
+	}
+	if len(links) != 1 || !links[0].Linked || !links[0].Closed {
+		t.Fatalf("links = %+v, want one linked and closed", links)
+	}
+	closed, _ := testStore.GetIssue(ctx, issue.ID)
+	if closed.Status != types.StatusClosed || closed.CloseReason != "Merged in !3" {
+		t.Errorf("issue status = %s (%q), want closed by merge", closed.Status, closed.CloseReason)
+	}
+	if links, _ := linkGitLabMRs(ctx, mrs, false); len(links) != 0 {
+		t.Errorf("second run links = %+v, want none", links)
+	}
+}
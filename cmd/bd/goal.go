@@ -0,0 +1,421 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/beads/internal/goals"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+var goalCmd = &cobra.Command{
+	Use:     "goal",
+	GroupID: "deps",
+	Short:   "Goals (OKRs) with key results above epics",
+	Long: `Track objectives above epics. A goal is an issue of type goal with key
+results, each measured one of two ways:
+
+  issues   linked epics and issues (--issues); progress is the share of
+           them closed, counting the descendants of linked epics
+  metric   a number moving from --start (default 0) to --target, such as
+           a latency to bring down; report it with --current
+
+Goal progress is the mean of its key results, or without key results the
+share of its children closed. Key results sync with the goal.
+
+The goal type is added to types.custom the first time a goal is created.`,
+	Example: `  bd goal create "Reduce p95 latency 30%" --kr "Ship the edge cache"
+  bd goal kr add bd-7 "p95 under 140ms" --start 200 --target 140 --unit ms
+  bd goal kr update bd-7 1 --issues bd-12,bd-15
+  bd goal kr update bd-7 2 --current 172
+  bd goal report`,
+}
+
+var goalCreateCmd = &cobra.Command{
+	Use:   "create <title>",
+	Short: "Create a goal",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("goal create")
+		description, _ := cmd.Flags().GetString("description")
+		assignee, _ := cmd.Flags().GetString("assignee")
+		priority, _ := cmd.Flags().GetInt("priority")
+		krTitles, _ := cmd.Flags().GetStringArray("kr")
+		if strings.TrimSpace(args[0]) == "" {
+			FatalErrorCode(ErrCodeUsage, "goal title cannot be empty")
+		}
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		gs, err := goals.For(store)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		goal := &types.Issue{
+			Title:       args[0],
+			Description: description,
+			Status:      types.StatusOpen,
+			Priority:    priority,
+			IssueType:   types.TypeGoal,
+			Assignee:    assignee,
+		}
+		err = store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+			if err := enableCustomType(ctx, tx, types.TypeGoal, "goals"); err != nil {
+				return err
+			}
+			return tx.CreateIssue(ctx, goal, actor)
+		})
+		if err != nil {
+			FatalErrorRespectJSON("failed to create goal: %v", err)
+		}
+		for _, title := range krTitles {
+			goal.KeyResults = append(goal.KeyResults, &types.KeyResult{Title: strings.TrimSpace(title)})
+		}
+		if len(goal.KeyResults) > 0 {
+			if err := gs.SetKeyResults(ctx, goal.ID, goal.KeyResults, actor); err != nil {
+				FatalErrorRespectJSON("failed to add key results: %v", err)
+			}
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(goal)
+			return
+		}
+		fmt.Printf("%s Created goal %s: %s\n", ui.RenderPass("✓"), goal.ID, goal.Title)
+		printKeyResults(goal)
+	},
+}
+
+var goalKRCmd = &cobra.Command{
+	Use:   "kr",
+	Short: "Add, update and remove key results",
+}
+
+var goalKRAddCmd = &cobra.Command{
+	Use:   "add <goal-id> <title>",
+	Short: "Add a key result to a goal",
+	Example: `  bd goal kr add bd-7 "Ship the edge cache" --issues bd-12,bd-15
+  bd goal kr add bd-7 "p95 under 140ms" --start 200 --target 140 --unit ms`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("goal kr add")
+		editKeyResults(args[0], func(krs []*types.KeyResult) ([]*types.KeyResult, error) {
+			kr := &types.KeyResult{Title: strings.TrimSpace(args[1])}
+			if err := applyKeyResultFlags(cmd.Flags(), kr); err != nil {
+				return nil, err
+			}
+			return append(krs, kr), nil
+		})
+	},
+}
+
+var goalKRUpdateCmd = &cobra.Command{
+	Use:   "update <goal-id> <n>",
+	Short: "Update a key result, or report its current metric value",
+	Example: `  bd goal kr update bd-7 2 --current 172
+  bd goal kr update bd-7 1 --link bd-20 --unlink bd-12
+  bd goal kr update bd-7 1 --title "Ship the cache to all regions"`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("goal kr update")
+		editKeyResults(args[0], func(krs []*types.KeyResult) ([]*types.KeyResult, error) {
+			i, err := keyResultIndex(krs, args[1])
+			if err != nil {
+				return nil, err
+			}
+			kr := *krs[i]
+			if title, _ := cmd.Flags().GetString("title"); title != "" {
+				kr.Title = strings.TrimSpace(title)
+			}
+			if err := applyKeyResultFlags(cmd.Flags(), &kr); err != nil {
+				return nil, err
+			}
+			krs = slices.Clone(krs)
+			krs[i] = &kr
+			return krs, nil
+		})
+	},
+}
+
+var goalKRRemoveCmd = &cobra.Command{
+	Use:   "remove <goal-id> <n>",
+	Short: "Remove a key result",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("goal kr remove")
+		editKeyResults(args[0], func(krs []*types.KeyResult) ([]*types.KeyResult, error) {
+			i, err := keyResultIndex(krs, args[1])
+			if err != nil {
+				return nil, err
+			}
+			return slices.Delete(slices.Clone(krs), i, i+1), nil
+		})
+	},
+}
+
+var goalReportCmd = &cobra.Command{
+	Use:   "report [goal-id...]",
+	Short: "Roll up the progress of goals and their key results",
+	Example: `  bd goal report              # Open goals
+  bd goal report --all        # Closed goals too
+  bd goal report bd-7 --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		var list []*types.Issue
+		if len(args) > 0 {
+			for _, arg := range args {
+				id, err := utils.ResolvePartialID(ctx, store, arg)
+				if err != nil {
+					FatalErrorRespectJSON("resolving %s: %v", arg, err)
+				}
+				issue, err := store.GetIssue(ctx, id)
+				if err != nil || issue == nil {
+					FatalErrorRespectJSON("goal %s not found", id)
+				}
+				list = append(list, issue)
+			}
+		} else {
+			goalType := types.TypeGoal
+			filter := types.IssueFilter{IssueType: &goalType}
+			if !all {
+				filter.ExcludeStatus = []types.Status{types.StatusClosed}
+			}
+			var err error
+			if list, err = store.SearchIssues(ctx, "", filter); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			slices.SortFunc(list, func(a, b *types.Issue) int { return strings.Compare(a.ID, b.ID) })
+		}
+		if err := goals.Populate(ctx, store, list); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		rollups, err := goals.Compute(ctx, store, list)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(rollups)
+			return
+		}
+		if len(rollups) == 0 {
+			fmt.Println("No open goals (create one with bd goal create)")
+			return
+		}
+		for _, r := range rollups {
+			icon := "◎"
+			if r.Status == types.StatusClosed {
+				icon = ui.RenderPass("✓")
+			}
+			fmt.Printf("%s %s %s  %s\n", icon, ui.RenderAccent(r.ID), ui.RenderBold(r.Title), progressBar(r.Progress))
+			if len(r.KeyResults) == 0 {
+				fmt.Printf("   %s\n", ui.RenderMuted(fmt.Sprintf("No key results; %d/%d children closed", r.Closed, r.Total)))
+			}
+			for i, kr := range r.KeyResults {
+				fmt.Printf("   %d. %s  %s  %s\n", i+1, kr.Title, ui.RenderMuted(describeKeyResult(kr.KeyResult, kr.Closed, kr.Total)), percent(kr.Progress))
+			}
+			fmt.Println()
+		}
+	},
+}
+
+// editKeyResults loads a goal's key results, applies edit and saves them.
+func editKeyResults(arg string, edit func([]*types.KeyResult) ([]*types.KeyResult, error)) {
+	if err := ensureStoreActive(); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	gs, err := goals.For(store)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	ctx := rootCtx
+	id, err := utils.ResolvePartialID(ctx, store, arg)
+	if err != nil {
+		FatalErrorRespectJSON("resolving %s: %v", arg, err)
+	}
+	goal, err := store.GetIssue(ctx, id)
+	if err != nil || goal == nil {
+		FatalErrorRespectJSON("goal %s not found", id)
+	}
+	if goal.IssueType != types.TypeGoal {
+		FatalErrorRespectJSON("%s is not a goal (type %s)", id, goal.IssueType)
+	}
+	krs, err := gs.GetKeyResults(ctx, id)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	if krs, err = edit(krs); err != nil {
+		FatalErrorCode(ErrCodeUsage, "%v", err)
+	}
+	for _, kr := range krs {
+		if err := resolveKeyResultIssues(ctx, kr); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+	}
+	if err := gs.SetKeyResults(ctx, id, krs, actor); err != nil {
+		FatalErrorRespectJSON("failed to save key results: %v", err)
+	}
+	markDirtyAndScheduleFlush()
+
+	goal.KeyResults = krs
+	if jsonOutput {
+		outputJSON(map[string]interface{}{"id": id, "key_results": krs})
+		return
+	}
+	fmt.Printf("%s Updated key results of %s\n", ui.RenderPass("✓"), id)
+	printKeyResults(goal)
+}
+
+// keyResultIndex parses a 1-based key result number.
+func keyResultIndex(krs []*types.KeyResult, arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(krs) {
+		return 0, fmt.Errorf("no key result %q (the goal has %d)", arg, len(krs))
+	}
+	return n - 1, nil
+}
+
+// applyKeyResultFlags applies the --issues, --link, --unlink and metric
+// flags to kr.
+func applyKeyResultFlags(flags *pflag.FlagSet, kr *types.KeyResult) error {
+	if flags.Changed("issues") {
+		kr.Issues, _ = flags.GetStringSlice("issues")
+	}
+	if flags.Lookup("link") != nil {
+		link, _ := flags.GetStringSlice("link")
+		unlink, _ := flags.GetStringSlice("unlink")
+		for i, arg := range unlink {
+			if id, err := utils.ResolvePartialID(rootCtx, store, strings.TrimSpace(arg)); err == nil {
+				unlink[i] = id
+			}
+		}
+		for _, id := range link {
+			if !slices.Contains(kr.Issues, id) {
+				kr.Issues = append(kr.Issues, id)
+			}
+		}
+		kr.Issues = slices.DeleteFunc(kr.Issues, func(id string) bool { return slices.Contains(unlink, id) })
+	}
+	for _, f := range []struct {
+		name  string
+		field **float64
+	}{{"start", &kr.Start}, {"target", &kr.Target}, {"current", &kr.Current}} {
+		if flags.Changed(f.name) {
+			v, _ := flags.GetFloat64(f.name)
+			*f.field = &v
+		}
+	}
+	if flags.Changed("unit") {
+		kr.Unit, _ = flags.GetString("unit")
+	}
+	if kr.Target == nil && (kr.Start != nil || kr.Current != nil) {
+		return fmt.Errorf("metric key results need --target")
+	}
+	return nil
+}
+
+// resolveKeyResultIssues expands partial IDs in a key result's links.
+func resolveKeyResultIssues(ctx context.Context, kr *types.KeyResult) error {
+	for i, arg := range kr.Issues {
+		id, err := utils.ResolvePartialID(ctx, store, strings.TrimSpace(arg))
+		if err != nil {
+			return fmt.Errorf("resolving %s: %w", arg, err)
+		}
+		kr.Issues[i] = id
+	}
+	return nil
+}
+
+// describeKeyResult summarizes how a key result is measured.
+func describeKeyResult(kr *types.KeyResult, closed, total int) string {
+	if kr.IsMetric() {
+		start := 0.0
+		if kr.Start != nil {
+			start = *kr.Start
+		}
+		s := fmt.Sprintf("%s → %s%s", formatMetric(start), formatMetric(*kr.Target), kr.Unit)
+		if kr.Current != nil {
+			s += fmt.Sprintf(", now %s%s", formatMetric(*kr.Current), kr.Unit)
+		}
+		return s
+	}
+	if len(kr.Issues) == 0 {
+		return "no linked issues"
+	}
+	return fmt.Sprintf("%d/%d closed", closed, total)
+}
+
+func formatMetric(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func percent(p float64) string {
+	return fmt.Sprintf("%d%%", int(p*100+0.5))
+}
+
+// progressBar renders progress as a 20-cell bar with a percentage.
+func progressBar(p float64) string {
+	filled := int(p*20 + 0.5)
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", 20-filled) + "] " + percent(p)
+}
+
+// printKeyResults lists a goal's key results in bd show. issue.KeyResults
+// must already be populated.
+func printKeyResults(issue *types.Issue) {
+	if len(issue.KeyResults) == 0 {
+		return
+	}
+	fmt.Printf("\n%s\n", ui.RenderBold("KEY RESULTS"))
+	for i, kr := range issue.KeyResults {
+		how := strings.Join(kr.Issues, ", ")
+		if kr.IsMetric() {
+			how = describeKeyResult(kr, 0, 0)
+		}
+		if how != "" {
+			how = "  " + ui.RenderMuted(how)
+		}
+		fmt.Printf("  %d. %s%s\n", i+1, kr.Title, how)
+	}
+}
+
+func init() {
+	goalCreateCmd.Flags().StringP("description", "d", "", "Goal description")
+	goalCreateCmd.Flags().StringP("assignee", "a", "", "Goal owner")
+	goalCreateCmd.Flags().IntP("priority", "p", 2, "Priority (0-4)")
+	goalCreateCmd.Flags().StringArray("kr", nil, "Key result title (repeatable; link issues with bd goal kr update)")
+
+	for _, c := range []*cobra.Command{goalKRAddCmd, goalKRUpdateCmd} {
+		c.Flags().StringSlice("issues", nil, "Linked epics and issues, replacing any (comma-separated)")
+		c.Flags().Float64("start", 0, "Metric baseline")
+		c.Flags().Float64("target", 0, "Metric target; makes this a metric key result")
+		c.Flags().Float64("current", 0, "Current metric value")
+		c.Flags().String("unit", "", "Metric unit, e.g. ms or %")
+	}
+	goalKRUpdateCmd.Flags().String("title", "", "New title")
+	goalKRUpdateCmd.Flags().StringSlice("link", nil, "Link more epics or issues")
+	goalKRUpdateCmd.Flags().StringSlice("unlink", nil, "Unlink epics or issues")
+	goalReportCmd.Flags().Bool("all", false, "Include closed goals")
+
+	for _, c := range []*cobra.Command{goalKRAddCmd, goalKRUpdateCmd, goalKRRemoveCmd, goalReportCmd} {
+		c.ValidArgsFunction = issueIDCompletion
+	}
+	goalKRCmd.AddCommand(goalKRAddCmd, goalKRUpdateCmd, goalKRRemoveCmd)
+	goalCmd.AddCommand(goalCreateCmd, goalKRCmd, goalReportCmd)
+	rootCmd.AddCommand(goalCmd)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/graphquery"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// GraphQueryResult is an issue a graph query reaches.
+type GraphQueryResult struct {
+	ID        string          `json:"id"`
+	Title     string          `json:"title"`
+	Status    types.Status    `json:"status"`
+	Priority  int             `json:"priority"`
+	IssueType types.IssueType `json:"issue_type"`
+	Depth     int             `json:"depth"`
+	Path      []string        `json:"path"`
+}
+
+// GraphQueryOutput is the JSON output of bd graph query.
+type GraphQueryOutput struct {
+	Query   string              `json:"query"`
+	Start   []string            `json:"start"`
+	Steps   []graphquery.Step   `json:"steps"`
+	Results []*GraphQueryResult `json:"results"`
+	Edges   []graphquery.Edge   `json:"edges"`
+}
+
+var graphQueryCmd = &cobra.Command{
+	Use:   "query <expression>",
+	Short: "Traverse the dependency graph with a path expression",
+	Long: `Find the issues reachable from one or more issues along dependency
+links, for dependency analyses that would otherwise mean walking bd list
+output by hand.
+
+An expression is start issues followed by steps, separated by /:
+
+  bd-7/ancestors                   Everything bd-7 depends on, transitively
+  bd-7/descendants                 Everything that depends on bd-7
+  bd-7/dependencies                What bd-7 depends on directly
+  bd-7/dependents                  What depends on bd-7 directly
+  bd-7,bd-9/dependents             Several start issues
+
+A step may filter edge types in [...] and, for ancestors and descendants,
+limit the depth in {...}:
+
+  bd-7/descendants[parent-child]   An epic's children, grandchildren, ...
+  bd-7/ancestors[blocking]         Blocking types only (as bd ready uses)
+  bd-7/descendants{2}              Up to 2 hops away
+  bd-7/descendants{2..}            2 or more hops away
+  bd-7/ancestors{2..4}             2 to 4 hops away
+
+Each step starts from the issues the previous one reached, so
+bd-7/dependencies[blocks]/dependents lists the other issues sharing bd-7's
+blockers. Use --where to keep results matching a query (see bd query).
+
+The JSON output lists each result with its depth and a shortest path from
+a start issue, plus the edges among the start issues and results.`,
+	Example: `  bd graph query 'bd-7/descendants[parent-child]'
+  bd graph query 'bd-7/ancestors[blocking]{3}' --where 'status!=closed'
+  bd graph query 'bd-7/dependencies[blocks]/dependents' --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("graph query requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		expr, err := graphquery.Parse(args[0])
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		filter := types.IssueFilter{}
+		if where, _ := cmd.Flags().GetString("where"); where != "" {
+			filter.Query = parseQueryFlag(where)
+		}
+
+		start := make([]string, len(expr.Start))
+		for i, arg := range expr.Start {
+			if start[i], err = utils.ResolvePartialID(ctx, store, arg); err != nil {
+				FatalErrorRespectJSON("resolving %s: %v", arg, err)
+			}
+		}
+		deps, err := store.GetAllDependencyRecords(ctx)
+		if err != nil {
+			FatalErrorRespectJSON("loading dependencies: %v", err)
+		}
+		graph := graphquery.NewGraph(deps)
+		hits := graph.Eval(expr, start)
+
+		ids := make([]string, len(hits))
+		for i, h := range hits {
+			ids[i] = h.ID
+		}
+		byID := make(map[string]*types.Issue, len(ids))
+		if len(ids) > 0 {
+			filter.IDs = ids
+			issues, err := store.SearchIssues(ctx, "", filter)
+			if err != nil {
+				FatalErrorRespectJSON("loading issues: %v", err)
+			}
+			for _, issue := range issues {
+				byID[issue.ID] = issue
+			}
+		}
+
+		out := &GraphQueryOutput{Query: args[0], Start: start, Steps: expr.Steps, Results: []*GraphQueryResult{}}
+		kept := append([]string{}, start...)
+		for _, h := range hits {
+			issue := byID[h.ID]
+			if issue == nil {
+				continue // Filtered out, or a dangling external reference
+			}
+			out.Results = append(out.Results, &GraphQueryResult{
+				ID:        issue.ID,
+				Title:     issue.Title,
+				Status:    issue.Status,
+				Priority:  issue.Priority,
+				IssueType: issue.IssueType,
+				Depth:     h.Depth,
+				Path:      h.Path,
+			})
+			kept = append(kept, issue.ID)
+		}
+		out.Edges = graph.Edges(kept)
+
+		if jsonOutput {
+			outputJSON(out)
+			return
+		}
+		if len(out.Results) == 0 {
+			fmt.Println("No issues match.")
+			return
+		}
+		for _, r := range out.Results {
+			line := fmt.Sprintf("%s %s %s", ui.RenderAccent(r.ID), truncateTitle(r.Title, 60),
+				ui.RenderMuted(fmt.Sprintf("[P%d %s %s]", r.Priority, r.IssueType, r.Status)))
+			if r.Depth > 1 {
+				line += " " + ui.RenderMuted("via "+strings.Join(r.Path[1:len(r.Path)-1], " → "))
+			}
+			fmt.Println(line)
+		}
+		fmt.Printf("\n%d issue(s)\n", len(out.Results))
+	},
+}
+
+func init() {
+	graphQueryCmd.Flags().String("where", "", "Keep only results matching a query expression (see bd query)")
+	graphCmd.AddCommand(graphQueryCmd)
+}
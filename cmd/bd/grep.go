@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+var grepCmd = &cobra.Command{
+	Use:     "grep [flags] <pattern> [issue-id...]",
+	GroupID: "views",
+	Short:   "Search issue text line by line, grep-style",
+	Long: `Search the long text of issues line by line and print matches the way grep
+prints matches in files, so the output works in grep pipelines.
+
+Each text field is a "file" named after the issue: the description is
+bd-42, other fields are bd-42/design, bd-42/notes, bd-42/acceptance and
+bd-42/comment-<n> (the comment's ID). The pattern is a Go regular
+expression (RE2) unless -F is given.
+
+Exit status is 0 if any line matched and 1 if none did.`,
+	Example: `  bd grep -n "retry logic"          # bd-42:3:...the retry logic backs off...
+  bd grep -l -i timeout | xargs bd show
+  bd grep -c 'TODO|FIXME' --field notes
+  bd grep -F "a.b(c)" bd-42 bd-43     # Only these issues`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		lineNumbers, _ := cmd.Flags().GetBool("line-number")
+		filesOnly, _ := cmd.Flags().GetBool("files-with-matches")
+		countOnly, _ := cmd.Flags().GetBool("count")
+		fields, _ := cmd.Flags().GetStringSlice("field")
+		status, _ := cmd.Flags().GetString("status")
+
+		re, err := grepPattern(cmd, args[0])
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "invalid pattern: %v", err)
+		}
+		fieldSet, err := grepFields(fields)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if err := ensureDirectMode("grep requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		issues, err := grepIssues(args[1:], status)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		docs, err := grepDocuments(issues, fieldSet)
+		if err != nil {
+			FatalErrorRespectJSON("reading comments: %v", err)
+		}
+		matches := grepMatch(docs, re)
+
+		switch {
+		case jsonOutput && (filesOnly || countOnly):
+			outputJSON(grepCounts(matches))
+		case jsonOutput:
+			outputJSON(matches)
+		case filesOnly:
+			for _, c := range grepCounts(matches) {
+				fmt.Println(c.IssueID)
+			}
+		case countOnly:
+			for _, c := range grepCounts(matches) {
+				fmt.Printf("%s:%d\n", c.IssueID, c.Count)
+			}
+		default:
+			for _, m := range matches {
+				prefix := ui.RenderAccent(m.File) + ":"
+				if lineNumbers {
+					prefix += ui.RenderMuted(fmt.Sprintf("%d", m.Line)) + ":"
+				}
+				fmt.Println(prefix + grepHighlight(m.Text, re))
+			}
+		}
+		if len(matches) == 0 {
+			exitFunc(1)
+		}
+	},
+}
+
+// grepFieldNames are the issue fields bd grep searches, in output order.
+var grepFieldNames = []string{"title", "description", "design", "notes", "acceptance", "comments"}
+
+// grepDefaultFields are searched unless --field says otherwise.
+var grepDefaultFields = []string{"description", "design", "notes", "acceptance", "comments"}
+
+// grepDocument is one searchable text: an issue field or a comment.
+type grepDocument struct {
+	IssueID string
+	Field   string
+	File    string // Name printed before matches: bd-42, bd-42/notes, ...
+	Text    string
+
+	CommentID int64 // For comments
+}
+
+// grepMatchLine is one matching line.
+type grepMatchLine struct {
+	IssueID string `json:"issue_id"`
+	Field   string `json:"field"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Text    string `json:"text"`
+}
+
+// grepCount is the number of matching lines in one issue.
+type grepCount struct {
+	IssueID string `json:"issue_id"`
+	Count   int    `json:"count"`
+}
+
+// grepPattern compiles the pattern according to -F, -i and -w.
+func grepPattern(cmd *cobra.Command, pattern string) (*regexp.Regexp, error) {
+	fixed, _ := cmd.Flags().GetBool("fixed-strings")
+	ignoreCase, _ := cmd.Flags().GetBool("ignore-case")
+	word, _ := cmd.Flags().GetBool("word-regexp")
+	if fixed {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if word {
+		pattern = `\b(?:` + pattern + `)\b`
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// grepFields validates --field values; "all" selects every field.
+func grepFields(fields []string) (map[string]bool, error) {
+	if len(fields) == 0 {
+		fields = grepDefaultFields
+	}
+	set := make(map[string]bool)
+	for _, f := range fields {
+		f = strings.ToLower(strings.TrimSpace(f))
+		switch {
+		case f == "all":
+			for _, name := range grepFieldNames {
+				set[name] = true
+			}
+		case f == "comment":
+			set["comments"] = true
+		case slices.Contains(grepFieldNames, f):
+			set[f] = true
+		default:
+			return nil, fmt.Errorf("unknown field %q (want %s or all)", f, strings.Join(grepFieldNames, ", "))
+		}
+	}
+	return set, nil
+}
+
+// grepIssues loads the issues to search: the given IDs, or every issue
+// (with the given status, if any).
+func grepIssues(ids []string, status string) ([]*types.Issue, error) {
+	ctx := rootCtx
+	if len(ids) > 0 {
+		var issues []*types.Issue
+		for _, id := range ids {
+			resolved, err := utils.ResolvePartialID(ctx, store, id)
+			if err != nil {
+				return nil, fmt.Errorf("resolving %s: %w", id, err)
+			}
+			issue, err := store.GetIssue(ctx, resolved)
+			if err != nil {
+				return nil, err
+			}
+			if issue == nil {
+				return nil, fmt.Errorf("issue %s not found", id)
+			}
+			issues = append(issues, issue)
+		}
+		return issues, nil
+	}
+	filter := types.IssueFilter{}
+	if status != "" {
+		s := types.Status(status)
+		filter.Status = &s
+	}
+	issues, err := store.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+	return issues, nil
+}
+
+// grepDocuments splits issues into the documents bd grep searches.
+func grepDocuments(issues []*types.Issue, fields map[string]bool) ([]grepDocument, error) {
+	var comments map[string][]*types.Comment
+	if fields["comments"] && len(issues) > 0 {
+		ids := make([]string, len(issues))
+		for i, issue := range issues {
+			ids[i] = issue.ID
+		}
+		var err error
+		if comments, err = store.GetCommentsForIssues(rootCtx, ids); err != nil {
+			return nil, err
+		}
+	}
+	var docs []grepDocument
+	for _, issue := range issues {
+		for _, f := range []struct{ name, text string }{
+			{"title", issue.Title},
+			{"description", issue.Description},
+			{"design", issue.Design},
+			{"notes", issue.Notes},
+			{"acceptance", issue.AcceptanceCriteria},
+		} {
+			if !fields[f.name] || f.text == "" {
+				continue
+			}
+			file := issue.ID
+			if f.name != "description" {
+				file += "/" + f.name
+			}
+			docs = append(docs, grepDocument{IssueID: issue.ID, Field: f.name, File: file, Text: f.text})
+		}
+		for _, c := range comments[issue.ID] {
+			docs = append(docs, grepDocument{
+				IssueID:   issue.ID,
+				Field:     "comments",
+				File:      fmt.Sprintf("%s/comment-%d", issue.ID, c.ID),
+				Text:      c.Text,
+				CommentID: c.ID,
+			})
+		}
+	}
+	return docs, nil
+}
+
+// grepMatch returns the lines of docs that match re, numbered from 1
+// within each document.
+func grepMatch(docs []grepDocument, re *regexp.Regexp) []grepMatchLine {
+	matches := []grepMatchLine{}
+	for _, doc := range docs {
+		for i, line := range strings.Split(strings.ReplaceAll(doc.Text, "\r\n", "\n"), "\n") {
+			if re.MatchString(line) {
+				matches = append(matches, grepMatchLine{
+					IssueID: doc.IssueID,
+					Field:   doc.Field,
+					File:    doc.File,
+					Line:    i + 1,
+					Text:    line,
+				})
+			}
+		}
+	}
+	return matches
+}
+
+// grepCounts totals matching lines per issue, in match order.
+func grepCounts(matches []grepMatchLine) []grepCount {
+	counts := []grepCount{}
+	index := make(map[string]int)
+	for _, m := range matches {
+		i, ok := index[m.IssueID]
+		if !ok {
+			i = len(counts)
+			index[m.IssueID] = i
+			counts = append(counts, grepCount{IssueID: m.IssueID})
+		}
+		counts[i].Count++
+	}
+	return counts
+}
+
+// grepHighlight colors the matches in line (a no-op without color).
+func grepHighlight(line string, re *regexp.Regexp) string {
+	if !ui.ShouldUseColor() {
+		return line
+	}
+	return re.ReplaceAllStringFunc(line, func(s string) string {
+		if s == "" {
+			return s
+		}
+		return ui.RenderFail(s)
+	})
+}
+
+func init() {
+	grepCmd.Flags().BoolP("line-number", "n", false, "Prefix each match with its line number within the field")
+	grepCmd.Flags().BoolP("files-with-matches", "l", false, "Print only the IDs of issues with a match")
+	grepCmd.Flags().BoolP("count", "c", false, "Print the number of matching lines per issue")
+	grepCmd.Flags().BoolP("ignore-case", "i", false, "Match case-insensitively")
+	grepCmd.Flags().BoolP("fixed-strings", "F", false, "Treat the pattern as a literal string, not a regular expression")
+	grepCmd.Flags().BoolP("word-regexp", "w", false, "Match whole words only")
+	grepCmd.Flags().StringSlice("field", nil, "Fields to search: title, description, design, notes, acceptance, comments, all (default: all but title)")
+	grepCmd.Flags().StringP("status", "s", "", "Only search issues with this status")
+	rootCmd.AddCommand(grepCmd)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestGrepMatch(t *testing.T) {
+	docs := []grepDocument{
+		{IssueID: "bd-1", Field: "description", File: "bd-1", Text: "intro\r\nthe retry logic\nretry again"},
+		{IssueID: "bd-1", Field: "comments", File: "bd-1/comment-7", Text: "no match"},
+		{IssueID: "bd-2", Field: "notes", File: "bd-2/notes", Text: "Retry"},
+	}
+	matches := grepMatch(docs, regexp.MustCompile(`retry`))
+	if len(matches) != 2 || matches[0].Line != 2 || matches[1].Line != 3 || matches[0].Text != "the retry logic" {
+		t.Fatalf("matches = %+v", matches)
+	}
+	matches = grepMatch(docs, regexp.MustCompile(`(?i)retry`))
+	counts := grepCounts(matches)
+	if len(counts) != 2 || counts[0] != (grepCount{"bd-1", 2}) || counts[1] != (grepCount{"bd-2", 1}) {
+		t.Errorf("counts = %+v", counts)
+	}
+	if got := grepMatch(docs, regexp.MustCompile(`zzz`)); got == nil || len(got) != 0 {
+		t.Errorf("no-match result = %#v, want an empty slice for JSON", got)
+	}
+}
+
+func TestGrepFields(t *testing.T) {
+	set, err := grepFields(nil)
+	if err != nil || set["title"] || !set["description"] || !set["comments"] {
+		t.Errorf("default fields = %v, %v", set, err)
+	}
+	if set, _ := grepFields([]string{"all"}); len(set) != len(grepFieldNames) {
+		t.Errorf("all = %v", set)
+	}
+	if set, _ := grepFields([]string{"Title", "comment"}); !set["title"] || !set["comments"] || set["description"] {
+		t.Errorf("title,comment = %v", set)
+	}
+	if _, err := grepFields([]string{"body"}); err == nil {
+		t.Error("unknown field should fail")
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// Heatmap is the age distribution of open issues per group.
+type Heatmap struct {
+	By      string        `json:"by"`
+	Buckets []string      `json:"buckets"` // Age bucket labels, youngest first
+	Rows    []*HeatmapRow `json:"rows"`
+	Max     int           `json:"max"` // Largest cell, the scale for shading
+}
+
+// HeatmapRow is one group's open issues counted per age bucket.
+type HeatmapRow struct {
+	Group      string `json:"group"`
+	Counts     []int  `json:"counts"` // Parallel to Heatmap.Buckets
+	Total      int    `json:"total"`
+	OldestDays int    `json:"oldest_days"`
+	Calcifying bool   `json:"calcifying"` // At least half the group is in the oldest bucket
+}
+
+// heatmapGroupBys are the --by values bd stats heatmap accepts.
+var heatmapGroupBys = []string{"label", "component", "assignee", "type", "priority"}
+
+// heatmapShades fill a cell from empty to the busiest cell in the map.
+var heatmapShades = []string{"  ", "░░", "▒▒", "▓▓", "██"}
+
+var statusHeatmapCmd = &cobra.Command{
+	Use:   "heatmap",
+	Short: "Show the age distribution of open issues as a heatmap",
+	Long: `Show how old the open issues are, per label (or component, assignee,
+type or priority), as a heatmap: one row per group, one column per age
+bucket, darker cells holding more issues. Age is time since creation.
+
+A group is marked as calcifying when at least half of its open issues sit
+in the oldest bucket. Those groups are listed first. Pinned issues are left
+out, since they stay open on purpose. An issue with several labels counts
+once per label.`,
+	Example: `  bd stats heatmap
+  bd stats heatmap --by assignee --buckets 7d,30d,90d
+  bd stats heatmap --buckets 1w,4w,12w,26w
+  bd stats heatmap --format csv > aging.csv
+  bd stats heatmap --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		by, _ := cmd.Flags().GetString("by")
+		bucketSpec, _ := cmd.Flags().GetString("buckets")
+		format, _ := cmd.Flags().GetString("format")
+
+		if !containsString(heatmapGroupBys, by) {
+			FatalErrorCode(ErrCodeUsage, "invalid --by %q (must be one of: %s)", by, strings.Join(heatmapGroupBys, ", "))
+		}
+		if format != "text" && format != "csv" {
+			FatalErrorCode(ErrCodeUsage, "invalid --format %q (must be text or csv)", format)
+		}
+		bounds, err := parseHeatmapBuckets(bucketSpec)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if err := ensureDirectMode("stats heatmap requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		issues, err := store.SearchIssues(ctx, "", types.IssueFilter{
+			ExcludeStatus: []types.Status{types.StatusClosed, types.StatusPinned},
+		})
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if by == "label" || by == "component" {
+			ids := make([]string, len(issues))
+			for i, issue := range issues {
+				ids[i] = issue.ID
+			}
+			labels, err := store.GetLabelsForIssues(ctx, ids)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			for _, issue := range issues {
+				issue.Labels = labels[issue.ID]
+			}
+		}
+
+		heatmap := buildHeatmap(issues, by, bounds, time.Now())
+		switch {
+		case jsonOutput:
+			outputJSON(heatmap)
+		case format == "csv":
+			var buf bytes.Buffer
+			if err := writeHeatmapCSV(&buf, heatmap); err != nil {
+				FatalError("%v", err)
+			}
+			_, _ = os.Stdout.Write(buf.Bytes())
+		default:
+			displayHeatmap(heatmap)
+		}
+	},
+}
+
+// heatmapBound is a boundary between two age buckets, labelled as the user
+// wrote it.
+type heatmapBound struct {
+	Age   time.Duration
+	Label string
+}
+
+// parseHeatmapBuckets parses ascending bucket boundaries such as
+// "7d,30d,90d".
+func parseHeatmapBuckets(spec string) ([]heatmapBound, error) {
+	var bounds []heatmapBound
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		d, err := parseDurationString(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --buckets: %w", err)
+		}
+		if d <= 0 || (len(bounds) > 0 && d <= bounds[len(bounds)-1].Age) {
+			return nil, fmt.Errorf("invalid --buckets %q: boundaries must be positive and ascending", spec)
+		}
+		bounds = append(bounds, heatmapBound{Age: d, Label: part})
+	}
+	if len(bounds) == 0 {
+		return nil, fmt.Errorf("--buckets needs at least one boundary (e.g. 7d,30d,90d)")
+	}
+	return bounds, nil
+}
+
+// heatmapBucketLabels names the buckets the boundaries make: one below the
+// first boundary, one between each pair and one past the last.
+func heatmapBucketLabels(bounds []heatmapBound) []string {
+	labels := []string{"0-" + bounds[0].Label}
+	for i := 1; i < len(bounds); i++ {
+		labels = append(labels, bounds[i-1].Label+"-"+bounds[i].Label)
+	}
+	return append(labels, bounds[len(bounds)-1].Label+"+")
+}
+
+// heatmapGroups returns the groups an issue counts toward.
+func heatmapGroups(issue *types.Issue, by string) []string {
+	var groups []string
+	switch by {
+	case "label":
+		if groups = uniqueStrings(issue.Labels); len(groups) == 0 {
+			groups = []string{types.GroupNoLabels}
+		}
+	case "component":
+		for _, label := range issue.Labels {
+			if name, ok := strings.CutPrefix(label, componentLabelPrefix); ok {
+				groups = append(groups, name)
+			}
+		}
+		if len(groups) == 0 {
+			groups = []string{"(no component)"}
+		}
+	case "assignee":
+		if issue.Assignee != "" {
+			groups = []string{issue.Assignee}
+		} else {
+			groups = []string{types.GroupUnassigned}
+		}
+	case "type":
+		groups = []string{string(issue.IssueType)}
+	case "priority":
+		groups = []string{types.PriorityGroup(issue.Priority)}
+	}
+	return groups
+}
+
+// buildHeatmap counts open issues per group and age bucket. Rows are
+// ordered by how many issues sit in the oldest bucket, then by size, so
+// calcifying groups lead.
+func buildHeatmap(issues []*types.Issue, by string, bounds []heatmapBound, now time.Time) *Heatmap {
+	heatmap := &Heatmap{By: by, Buckets: heatmapBucketLabels(bounds), Rows: []*HeatmapRow{}}
+	rows := make(map[string]*HeatmapRow)
+	for _, issue := range issues {
+		if issue.Status == types.StatusClosed || issue.Status == types.StatusTombstone || issue.Status == types.StatusPinned {
+			continue
+		}
+		age := time.Duration(0)
+		if !issue.CreatedAt.IsZero() && now.After(issue.CreatedAt) {
+			age = now.Sub(issue.CreatedAt)
+		}
+		bucket := sort.Search(len(bounds), func(i int) bool { return age < bounds[i].Age })
+		days := int(age.Hours() / 24)
+
+		for _, group := range heatmapGroups(issue, by) {
+			row, ok := rows[group]
+			if !ok {
+				row = &HeatmapRow{Group: group, Counts: make([]int, len(heatmap.Buckets))}
+				rows[group] = row
+				heatmap.Rows = append(heatmap.Rows, row)
+			}
+			row.Counts[bucket]++
+			row.Total++
+			row.OldestDays = max(row.OldestDays, days)
+		}
+	}
+
+	last := len(heatmap.Buckets) - 1
+	for _, row := range heatmap.Rows {
+		row.Calcifying = row.Counts[last] > 0 && 2*row.Counts[last] >= row.Total
+		for _, count := range row.Counts {
+			heatmap.Max = max(heatmap.Max, count)
+		}
+	}
+	sort.Slice(heatmap.Rows, func(i, j int) bool {
+		a, b := heatmap.Rows[i], heatmap.Rows[j]
+		if a.Counts[last] != b.Counts[last] {
+			return a.Counts[last] > b.Counts[last]
+		}
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Group < b.Group
+	})
+	return heatmap
+}
+
+// heatmapShade picks the fill for a cell relative to the busiest cell.
+func heatmapShade(count, maxCount int) string {
+	if count == 0 || maxCount == 0 {
+		return heatmapShades[0]
+	}
+	steps := len(heatmapShades) - 1
+	return heatmapShades[(count*steps+maxCount-1)/maxCount]
+}
+
+func displayHeatmap(h *Heatmap) {
+	if len(h.Rows) == 0 {
+		fmt.Printf("\n%s No open issues\n\n", ui.RenderPass("✨"))
+		return
+	}
+
+	width := len(h.By)
+	for _, row := range h.Rows {
+		width = max(width, len(row.Group))
+	}
+
+	if ui.IsAccessible() {
+		fmt.Printf("Open issue age by %s, %d groups.\n\n", h.By, len(h.Rows))
+		for _, row := range h.Rows {
+			var parts []string
+			for i, count := range row.Counts {
+				if count > 0 {
+					parts = append(parts, fmt.Sprintf("%d aged %s", count, h.Buckets[i]))
+				}
+			}
+			line := fmt.Sprintf("%s: %d open, %s. Oldest %d days.", row.Group, row.Total, strings.Join(parts, ", "), row.OldestDays)
+			if row.Calcifying {
+				line += " Calcifying."
+			}
+			fmt.Println(line)
+		}
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("\n%s Open issue age by %s:\n\n", ui.RenderAccent("🌡"), h.By)
+	fmt.Printf("  %-*s", width, strings.ToUpper(h.By))
+	for _, label := range h.Buckets {
+		fmt.Printf("  %8s", label)
+	}
+	fmt.Printf("  %6s  %6s\n", "TOTAL", "OLDEST")
+	for _, row := range h.Rows {
+		fmt.Printf("  %-*s", width, row.Group)
+		for _, count := range row.Counts {
+			cell := "       ·"
+			if count > 0 {
+				cell = fmt.Sprintf("%s%6d", heatmapShade(count, h.Max), count)
+			}
+			fmt.Printf("  %s", cell)
+		}
+		fmt.Printf("  %6d  %5dd", row.Total, row.OldestDays)
+		if row.Calcifying {
+			fmt.Printf("  %s", ui.RenderWarn("calcifying"))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+// writeHeatmapCSV writes one row per group with a header row.
+func writeHeatmapCSV(buf *bytes.Buffer, h *Heatmap) error {
+	w := csv.NewWriter(buf)
+	header := append([]string{h.By}, h.Buckets...)
+	_ = w.Write(append(header, "total", "oldest_days", "calcifying"))
+	for _, row := range h.Rows {
+		record := []string{row.Group}
+		for _, count := range row.Counts {
+			record = append(record, strconv.Itoa(count))
+		}
+		record = append(record, strconv.Itoa(row.Total), strconv.Itoa(row.OldestDays), strconv.FormatBool(row.Calcifying))
+		_ = w.Write(record)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func init() {
+	statusHeatmapCmd.Flags().String("by", "label", "Group rows by: "+strings.Join(heatmapGroupBys, ", "))
+	statusHeatmapCmd.Flags().String("buckets", "7d,30d,90d", "Ascending age boundaries between columns")
+	statusHeatmapCmd.Flags().String("format", "text", "Output format (text, csv)")
+	statusCmd.AddCommand(statusHeatmapCmd)
+}
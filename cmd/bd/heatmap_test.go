@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParseHeatmapBuckets(t *testing.T) {
+	bounds, err := parseHeatmapBuckets("7d, 30d,12w")
+	if err != nil {
+		t.Fatalf("parseHeatmapBuckets: %v", err)
+	}
+	if got := heatmapBucketLabels(bounds); !slices.Equal(got, []string{"0-7d", "7d-30d", "30d-12w", "12w+"}) {
+		t.Errorf("labels = %v", got)
+	}
+	for _, bad := range []string{"", "30d,7d", "7d,7d", "soon", "0d"} {
+		if _, err := parseHeatmapBuckets(bad); err == nil {
+			t.Errorf("parseHeatmapBuckets(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestBuildHeatmap(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	ago := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	issues := []*types.Issue{
+		{ID: "t-1", Status: types.StatusOpen, CreatedAt: ago(1), Labels: []string{"api"}},
+		{ID: "t-2", Status: types.StatusOpen, CreatedAt: ago(100), Labels: []string{"api", "db"}},
+		{ID: "t-3", Status: types.StatusBlocked, CreatedAt: ago(200), Labels: []string{"api"}},
+		{ID: "t-4", Status: types.StatusOpen, CreatedAt: ago(10)},
+		{ID: "t-5", Status: types.StatusPinned, CreatedAt: ago(300), Labels: []string{"api"}},
+		{ID: "t-6", Status: types.StatusClosed, CreatedAt: ago(300), Labels: []string{"db"}},
+	}
+	bounds, _ := parseHeatmapBuckets("7d,30d,90d")
+	h := buildHeatmap(issues, "label", bounds, now)
+
+	var groups []string
+	for _, row := range h.Rows {
+		groups = append(groups, row.Group)
+	}
+	if !slices.Equal(groups, []string{"api", "db", "(no labels)"}) {
+		t.Fatalf("rows = %v, want [api db (no labels)] (oldest bucket first)", groups)
+	}
+	api := h.Rows[0]
+	if !slices.Equal(api.Counts, []int{1, 0, 0, 2}) || api.Total != 3 || api.OldestDays != 200 || !api.Calcifying {
+		t.Errorf("api row = %+v", api)
+	}
+	if unlabeled := h.Rows[2]; !slices.Equal(unlabeled.Counts, []int{0, 1, 0, 0}) || unlabeled.Calcifying {
+		t.Errorf("(no labels) row = %+v", unlabeled)
+	}
+	if h.Max != 2 {
+		t.Errorf("Max = %d, want 2", h.Max)
+	}
+
+	var buf bytes.Buffer
+	if err := writeHeatmapCSV(&buf, h); err != nil {
+		t.Fatal(err)
+	}
+	want := "label,0-7d,7d-30d,30d-90d,90d+,total,oldest_days,calcifying\n" +
+		"api,1,0,0,2,3,200,true\n" +
+		"db,0,0,0,1,1,100,true\n" +
+		"(no labels),0,1,0,0,1,10,false\n"
+	if buf.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestHeatmapShade(t *testing.T) {
+	if heatmapShade(0, 5) != heatmapShades[0] || heatmapShade(5, 5) != heatmapShades[4] || heatmapShade(1, 5) != heatmapShades[1] {
+		t.Errorf("shades = %q %q %q", heatmapShade(0, 5), heatmapShade(1, 5), heatmapShade(5, 5))
+	}
+}
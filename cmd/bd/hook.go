@@ -369,7 +369,7 @@ func hookPreCommit() int {
 	}
 
 	// Stage JSONL files
-	jsonlFiles := []string{".beads/beads.jsonl", ".beads/issues.jsonl", ".beads/deletions.jsonl", ".beads/interactions.jsonl"}
+	jsonlFiles := []string{".beads/beads.jsonl", ".beads/issues.jsonl", ".beads/deletions.jsonl", ".beads/interactions.jsonl", ".beads/ops.jsonl"}
 	if os.Getenv("BEADS_NO_AUTO_STAGE") == "" {
 		rc, rcErr := beads.GetRepoContext()
 		ctx := context.Background()
@@ -478,7 +478,7 @@ func hookPreCommitDolt(beadsDir, worktreeRoot string) int {
 	// Stage JSONL files
 	if os.Getenv("BEADS_NO_AUTO_STAGE") == "" {
 		rc, rcErr := beads.GetRepoContext()
-		jsonlFiles := []string{".beads/issues.jsonl", ".beads/deletions.jsonl", ".beads/interactions.jsonl"}
+		jsonlFiles := []string{".beads/issues.jsonl", ".beads/deletions.jsonl", ".beads/interactions.jsonl", ".beads/ops.jsonl"}
 		for _, f := range jsonlFiles {
 			if _, err := os.Stat(f); err == nil {
 				var gitAdd *exec.Cmd
@@ -521,7 +521,7 @@ func hookPreCommitDoltFallback(ctx context.Context, store storage.Storage, beads
 	// Stage JSONL files
 	if os.Getenv("BEADS_NO_AUTO_STAGE") == "" {
 		rc, rcErr := beads.GetRepoContext()
-		jsonlFiles := []string{".beads/issues.jsonl", ".beads/deletions.jsonl", ".beads/interactions.jsonl"}
+		jsonlFiles := []string{".beads/issues.jsonl", ".beads/deletions.jsonl", ".beads/interactions.jsonl", ".beads/ops.jsonl"}
 		for _, f := range jsonlFiles {
 			if _, err := os.Stat(f); err == nil {
 				var gitAdd *exec.Cmd
@@ -575,7 +575,7 @@ func runPreCommitHook() int {
 	// By default, we auto-stage for convenience. Users with conflicting git hooks
 	// (e.g., hooks that read the staging area) can set BEADS_NO_AUTO_STAGE=1 to
 	// disable this and stage manually. See: https://github.com/steveyegge/beads/issues/826
-	jsonlFiles := []string{".beads/beads.jsonl", ".beads/issues.jsonl", ".beads/deletions.jsonl", ".beads/interactions.jsonl"}
+	jsonlFiles := []string{".beads/beads.jsonl", ".beads/issues.jsonl", ".beads/deletions.jsonl", ".beads/interactions.jsonl", ".beads/ops.jsonl"}
 
 	if os.Getenv("BEADS_NO_AUTO_STAGE") != "" {
 		// Safe mode: check for unstaged changes and block if found
@@ -697,7 +697,7 @@ func runPrePushHook(args []string) int {
 
 	// Check for uncommitted JSONL changes
 	files := []string{}
-	for _, f := range []string{".beads/beads.jsonl", ".beads/issues.jsonl", ".beads/deletions.jsonl", ".beads/interactions.jsonl"} {
+	for _, f := range []string{".beads/beads.jsonl", ".beads/issues.jsonl", ".beads/deletions.jsonl", ".beads/interactions.jsonl", ".beads/ops.jsonl"} {
 		// Check if file exists or is tracked
 		if _, err := os.Stat(f); err == nil {
 			files = append(files, f)
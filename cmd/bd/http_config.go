@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/httpx"
+)
+
+// applyHTTPConfig configures the HTTP client shared by the connectors from
+// http.log and http.rate-limits, e.g.
+//
+//	http:
+//	  rate-limits:
+//	    api.linear.app: 2/s
+//	    "*": 100/m
+func applyHTTPConfig() {
+	if config.GetBool("http.log") {
+		httpx.SetLog(os.Stderr)
+	}
+	for host, raw := range config.GetStringMapString("http.rate-limits") {
+		perSecond, err := httpx.ParseRate(raw)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: http.rate-limits.%s: %v\n", host, err)
+			continue
+		}
+		httpx.DefaultLimits.Set(host, perSecond)
+	}
+}
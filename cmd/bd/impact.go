@@ -0,0 +1,327 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// ImpactIssue is an open issue transitively blocked by the analyzed one.
+type ImpactIssue struct {
+	ID               string       `json:"id"`
+	Title            string       `json:"title"`
+	Status           types.Status `json:"status"`
+	Priority         int          `json:"priority"`
+	EstimatedMinutes *int         `json:"estimated_minutes,omitempty"`
+	Depth            int          `json:"depth"` // 1 = blocked directly
+	Via              string       `json:"via"`   // The issue that blocks it on the path from the root
+	DueAt            *time.Time   `json:"due_at,omitempty"`
+}
+
+// ImpactMilestone is a milestone with open work behind the analyzed issue.
+type ImpactMilestone struct {
+	Name    string     `json:"name"`
+	Kind    string     `json:"kind"` // "label" (milestone:<name>) or "epic"
+	Title   string     `json:"title,omitempty"`
+	Blocked []string   `json:"blocked"` // Its issues at risk: the analyzed one or those it blocks
+	Open    int        `json:"open"`    // Its open issues in total
+	DueAt   *time.Time `json:"due_at,omitempty"`
+	Overdue bool       `json:"overdue,omitempty"`
+}
+
+// ImpactReport is the output of bd impact.
+type ImpactReport struct {
+	Issue       ImpactIssue        `json:"issue"`
+	Blocked     []*ImpactIssue     `json:"blocked"`
+	Estimate    int                `json:"estimated_minutes"` // Sum over blocked issues with an estimate
+	Unestimated int                `json:"unestimated"`
+	Milestones  []*ImpactMilestone `json:"milestones"`
+}
+
+var impactCmd = &cobra.Command{
+	Use:     "impact <issue-id>",
+	GroupID: "deps",
+	Short:   "Show everything an issue blocks, the work behind it and the milestones at risk",
+	Long: `Show the impact of an issue not getting done: every open issue it blocks,
+directly or transitively, the total estimate of that work, and the
+milestones with blocked issues. Use it before deprioritizing or deferring
+something.
+
+Blocking follows the same links as bd ready (blocks, parent-child,
+conditional-blocks, waits-for); closed issues are left out. A milestone is
+a milestone:<name> label or an epic containing blocked issues. One with a
+due date shows it, and those already past due are flagged.`,
+	Example: `  bd impact bd-7
+  bd impact bd-7 --json | jq .estimated_minutes`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("impact requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		id, err := utils.ResolvePartialID(ctx, store, args[0])
+		if err != nil {
+			FatalErrorRespectJSON("resolving %s: %v", args[0], err)
+		}
+		issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+		if err != nil {
+			FatalErrorRespectJSON("loading issues: %v", err)
+		}
+		ids := make([]string, len(issues))
+		for i, issue := range issues {
+			ids[i] = issue.ID
+		}
+		labels, err := store.GetLabelsForIssues(ctx, ids)
+		if err != nil {
+			FatalErrorRespectJSON("loading labels: %v", err)
+		}
+		for _, issue := range issues {
+			issue.Labels = labels[issue.ID]
+		}
+		// Search results don't carry due dates; load them for issues that have one
+		dated, err := store.SearchIssues(ctx, "", types.IssueFilter{DueAfter: &time.Time{}})
+		if err != nil {
+			FatalErrorRespectJSON("loading due dates: %v", err)
+		}
+		due := make(map[string]*time.Time, len(dated))
+		for _, match := range dated {
+			full, err := store.GetIssue(ctx, match.ID)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if full != nil {
+				due[full.ID] = full.DueAt
+			}
+		}
+		for _, issue := range issues {
+			if issue.DueAt == nil {
+				issue.DueAt = due[issue.ID]
+			}
+		}
+		deps, err := store.GetAllDependencyRecords(ctx)
+		if err != nil {
+			FatalErrorRespectJSON("loading dependencies: %v", err)
+		}
+
+		report, err := computeImpact(id, issues, deps, time.Now())
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		printImpact(report)
+	},
+}
+
+// computeImpact walks the blocking links backwards from rootID. deps maps
+// an issue to the dependencies it has (as GetAllDependencyRecords
+// returns them).
+func computeImpact(rootID string, issues []*types.Issue, deps map[string][]*types.Dependency, now time.Time) (*ImpactReport, error) {
+	byID := make(map[string]*types.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	root := byID[rootID]
+	if root == nil {
+		return nil, fmt.Errorf("issue %s not found", rootID)
+	}
+
+	// blockedBy[x] lists the issues x blocks; parent lists parent-child
+	// links upwards, for finding the epics blocked issues belong to
+	blockedBy := make(map[string][]string)
+	parent := make(map[string]string)
+	for issueID, list := range deps {
+		for _, dep := range list {
+			if dep.Type == types.DepParentChild {
+				parent[issueID] = dep.DependsOnID
+			}
+			if dep.Type.AffectsReadyWork() {
+				blockedBy[dep.DependsOnID] = append(blockedBy[dep.DependsOnID], issueID)
+			}
+		}
+	}
+	for _, list := range blockedBy {
+		sort.Strings(list)
+	}
+
+	report := &ImpactReport{Issue: *impactIssue(root, 0, ""), Blocked: []*ImpactIssue{}, Milestones: []*ImpactMilestone{}}
+	seen := map[string]bool{rootID: true}
+	queue := []*ImpactIssue{&report.Issue}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, id := range blockedBy[cur.ID] {
+			issue := byID[id]
+			if seen[id] || issue == nil || issue.Status == types.StatusClosed || issue.Status == types.StatusTombstone {
+				continue
+			}
+			seen[id] = true
+			blocked := impactIssue(issue, cur.Depth+1, cur.ID)
+			report.Blocked = append(report.Blocked, blocked)
+			queue = append(queue, blocked)
+			if issue.EstimatedMinutes != nil {
+				report.Estimate += *issue.EstimatedMinutes
+			} else {
+				report.Unestimated++
+			}
+		}
+	}
+
+	// Milestones: labels on the issue and those it blocks, and epics above them
+	milestones := make(map[string]*ImpactMilestone)
+	var order []string
+	add := func(key, name, kind string, blockedID string) {
+		m := milestones[key]
+		if m == nil {
+			m = &ImpactMilestone{Name: name, Kind: kind}
+			milestones[key] = m
+			order = append(order, key)
+		}
+		if len(m.Blocked) == 0 || m.Blocked[len(m.Blocked)-1] != blockedID {
+			m.Blocked = append(m.Blocked, blockedID)
+		}
+	}
+	for _, b := range append([]*ImpactIssue{&report.Issue}, report.Blocked...) {
+		for _, label := range byID[b.ID].Labels {
+			if strings.HasPrefix(label, milestoneLabelPrefix) {
+				name := strings.TrimPrefix(label, milestoneLabelPrefix)
+				add("label:"+name, name, "label", b.ID)
+			}
+		}
+		visited := map[string]bool{}
+		for p := parent[b.ID]; p != "" && !visited[p]; p = parent[p] {
+			visited[p] = true
+			if epic := byID[p]; epic != nil && epic.IssueType == types.TypeEpic && p != rootID {
+				add("epic:"+p, p, "epic", b.ID)
+			}
+		}
+	}
+
+	// Size and due date of each milestone
+	for _, issue := range issues {
+		if issue.Status == types.StatusClosed || issue.Status == types.StatusTombstone {
+			continue
+		}
+		for _, label := range issue.Labels {
+			if m := milestones["label:"+strings.TrimPrefix(label, milestoneLabelPrefix)]; m != nil && strings.HasPrefix(label, milestoneLabelPrefix) {
+				m.Open++
+				if issue.DueAt != nil && (m.DueAt == nil || issue.DueAt.After(*m.DueAt)) {
+					m.DueAt = issue.DueAt
+				}
+			}
+		}
+		visited := map[string]bool{}
+		for p := parent[issue.ID]; p != "" && !visited[p]; p = parent[p] {
+			visited[p] = true
+			if m := milestones["epic:"+p]; m != nil {
+				m.Open++
+			}
+		}
+	}
+	for _, key := range order {
+		m := milestones[key]
+		if m.Kind == "epic" {
+			epic := byID[m.Name]
+			m.Title = epic.Title
+			if epic.DueAt != nil {
+				m.DueAt = epic.DueAt
+			}
+		}
+		m.Overdue = m.DueAt != nil && m.DueAt.Before(now)
+		report.Milestones = append(report.Milestones, m)
+	}
+	sort.SliceStable(report.Milestones, func(i, j int) bool {
+		a, b := report.Milestones[i], report.Milestones[j]
+		if (a.DueAt == nil) != (b.DueAt == nil) {
+			return a.DueAt != nil
+		}
+		if a.DueAt != nil && !a.DueAt.Equal(*b.DueAt) {
+			return a.DueAt.Before(*b.DueAt)
+		}
+		return len(a.Blocked) > len(b.Blocked)
+	})
+	return report, nil
+}
+
+func impactIssue(issue *types.Issue, depth int, via string) *ImpactIssue {
+	return &ImpactIssue{
+		ID:               issue.ID,
+		Title:            issue.Title,
+		Status:           issue.Status,
+		Priority:         issue.Priority,
+		EstimatedMinutes: issue.EstimatedMinutes,
+		Depth:            depth,
+		Via:              via,
+		DueAt:            issue.DueAt,
+	}
+}
+
+func printImpact(r *ImpactReport) {
+	fmt.Printf("Impact of %s: %s %s\n\n", ui.RenderAccent(r.Issue.ID), r.Issue.Title,
+		ui.RenderMuted(fmt.Sprintf("[P%d %s]", r.Issue.Priority, r.Issue.Status)))
+	if len(r.Blocked) == 0 {
+		fmt.Println("Nothing open is blocked by this issue.")
+		return
+	}
+
+	effort := "no estimates"
+	if r.Estimate > 0 {
+		effort = "est. " + formatEstimateMinutes(r.Estimate)
+	}
+	if r.Unestimated > 0 {
+		effort += fmt.Sprintf(", %d unestimated", r.Unestimated)
+	}
+	fmt.Printf("Blocks %d open issue(s) (%s)\n", len(r.Blocked), effort)
+
+	// Print as a tree: children of each issue in BFS order
+	children := make(map[string][]*ImpactIssue)
+	for _, b := range r.Blocked {
+		children[b.Via] = append(children[b.Via], b)
+	}
+	var walk func(id string)
+	walk = func(id string) {
+		for _, b := range children[id] {
+			line := fmt.Sprintf("%s%s %s %s", strings.Repeat("  ", b.Depth), ui.RenderAccent(b.ID), b.Title,
+				ui.RenderMuted(fmt.Sprintf("[P%d %s]", b.Priority, b.Status)))
+			if b.EstimatedMinutes != nil {
+				line += " " + ui.RenderMuted(formatEstimateMinutes(*b.EstimatedMinutes))
+			}
+			fmt.Println(line)
+			walk(b.ID)
+		}
+	}
+	walk(r.Issue.ID)
+
+	if len(r.Milestones) == 0 {
+		return
+	}
+	fmt.Printf("\nMilestones at risk:\n")
+	for _, m := range r.Milestones {
+		name := m.Name
+		if m.Kind == "epic" {
+			name = fmt.Sprintf("%s (epic: %s)", m.Name, m.Title)
+		}
+		line := fmt.Sprintf("  %s  %d of %d open issue(s) at risk", name, len(m.Blocked), m.Open)
+		if m.DueAt != nil {
+			due := "due " + m.DueAt.Local().Format("2006-01-02")
+			if m.Overdue {
+				due = ui.RenderFail(due + ", overdue")
+			}
+			line += "  " + due
+		}
+		fmt.Println(line)
+	}
+}
+
+func init() {
+	impactCmd.ValidArgsFunction = issueIDCompletion
+	rootCmd.AddCommand(impactCmd)
+}
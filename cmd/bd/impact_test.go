@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestComputeImpact(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-24 * time.Hour)
+	future := now.Add(30 * 24 * time.Hour)
+	est := func(m int) *int { return &m }
+	issues := []*types.Issue{
+		{ID: "bd-1", Title: "Auth", Status: types.StatusOpen, Labels: []string{"milestone:v2"}},
+		{ID: "bd-2", Title: "Login", Status: types.StatusOpen, EstimatedMinutes: est(60), Labels: []string{"milestone:v1"}, DueAt: &future},
+		{ID: "bd-3", Title: "Dashboard", Status: types.StatusInProgress, EstimatedMinutes: est(120), Labels: []string{"milestone:v1"}, DueAt: &past},
+		{ID: "bd-4", Title: "Done already", Status: types.StatusClosed},
+		{ID: "bd-5", Title: "Behind the closed one", Status: types.StatusOpen},
+		{ID: "bd-6", Title: "Release", Status: types.StatusOpen, IssueType: types.TypeEpic},
+		{ID: "bd-6.1", Title: "Settings", Status: types.StatusOpen},
+		{ID: "bd-7", Title: "Unrelated", Status: types.StatusOpen, Labels: []string{"milestone:v1"}},
+		{ID: "bd-8", Title: "Only related", Status: types.StatusOpen},
+	}
+	dep := func(from, to string, typ types.DependencyType) *types.Dependency {
+		return &types.Dependency{IssueID: from, DependsOnID: to, Type: typ}
+	}
+	deps := map[string][]*types.Dependency{
+		"bd-2":   {dep("bd-2", "bd-1", types.DepBlocks)},
+		"bd-3":   {dep("bd-3", "bd-2", types.DepBlocks), dep("bd-3", "bd-1", types.DepBlocks)},
+		"bd-4":   {dep("bd-4", "bd-1", types.DepBlocks)},
+		"bd-5":   {dep("bd-5", "bd-4", types.DepBlocks)},
+		"bd-6.1": {dep("bd-6.1", "bd-6", types.DepParentChild), dep("bd-6.1", "bd-3", types.DepBlocks)},
+		"bd-8":   {dep("bd-8", "bd-1", types.DepRelated)},
+	}
+
+	r, err := computeImpact("bd-1", issues, deps, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, b := range r.Blocked {
+		got = append(got, b.ID)
+	}
+	// bd-3 is blocked by bd-1 directly; closed bd-4 and what's behind it,
+	// and the related bd-8, aren't blocked
+	if want := []string{"bd-2", "bd-3", "bd-6.1"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("blocked = %v, want %v", got, want)
+	}
+	if r.Blocked[1].Depth != 1 || r.Blocked[2].Depth != 2 || r.Blocked[2].Via != "bd-3" {
+		t.Errorf("depths = %+v %+v", r.Blocked[1], r.Blocked[2])
+	}
+	if r.Estimate != 180 || r.Unestimated != 1 {
+		t.Errorf("estimate = %d, unestimated = %d", r.Estimate, r.Unestimated)
+	}
+
+	if len(r.Milestones) != 3 {
+		t.Fatalf("milestones = %+v", r.Milestones)
+	}
+	v1 := r.Milestones[0]
+	if v1.Name != "v1" || v1.Open != 3 || len(v1.Blocked) != 2 || v1.DueAt == nil || !v1.DueAt.Equal(future) || v1.Overdue {
+		t.Errorf("v1 = %+v (want latest due date, 2 of 3 at risk)", v1)
+	}
+	names := r.Milestones[1].Name + "," + r.Milestones[2].Name
+	if names != "v2,bd-6" && names != "bd-6,v2" {
+		t.Errorf("other milestones = %s", names)
+	}
+
+	if _, err := computeImpact("bd-99", issues, deps, now); err == nil {
+		t.Error("unknown issue should fail")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -16,9 +17,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/beads/internal/beads"
 	"github.com/steveyegge/beads/internal/debug"
+	"github.com/steveyegge/beads/internal/importer"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/utils"
+	"github.com/steveyegge/beads/internal/validation"
 	"golang.org/x/term"
 )
 
@@ -118,6 +121,8 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 		// Open input
 		in := os.Stdin
 		if input != "" {
+			recoverInterruptedExport(input)
+
 			// #nosec G304 - user-provided file path is intentional
 			f, err := os.Open(input)
 			if err != nil {
@@ -253,6 +258,16 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 			fmt.Fprintf(os.Stderr, "✓ Initialized database with prefix '%s' (detected from %s)\n", detectedPrefix, prefixSource)
 		}
 
+		// Content rules apply to issues coming from outside; the project's own
+		// JSONL carries changes already made (and checked) elsewhere.
+		checkRules := !isProjectJSONL(input)
+		if checkRules {
+			warnValidationConfig()
+			if force {
+				ctx = validation.WithForce(ctx, getActor())
+			}
+		}
+
 		// Phase 2: Use shared import logic
 		opts := ImportOptions{
 			DryRun:                     dryRun,
@@ -261,6 +276,8 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 			RenameOnImport:             renameOnImport,
 			ClearDuplicateExternalRefs: clearDuplicateExternalRefs,
 			OrphanHandling:             orphanHandling,
+			LockActor:                  getActor(),
+			CheckRules:                 checkRules,
 		}
 
 		// If --protect-left-snapshot is set, read the left snapshot and build timestamp map
@@ -280,7 +297,13 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 			}
 		}
 
+		if !dryRun {
+			recordPendingOpLog(ctx, store, findJSONLPath())
+		}
+		progress := newProgress("Importing", len(allIssues))
+		opts.Progress = func(done, _ int) { progress.Set(done) }
 		result, err := importIssuesCore(ctx, dbPath, store, allIssues, opts)
+		progress.Finish()
 
 		// Check for uncommitted changes in JSONL after import
 		// Only check if we have an input file path (not stdin) and it's the default beads file
@@ -315,9 +338,19 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 				fmt.Fprintf(os.Stderr, "This may indicate manual ID manipulation or a bug.\n")
 				os.Exit(1)
 			}
+			var interrupted *importer.InterruptedError
+			if errors.As(err, &interrupted) {
+				FatalErrorWithHint(err.Error(), "completed changes were saved; re-run 'bd import' to finish")
+			}
+			if errors.As(err, new(*validation.RuleError)) {
+				FatalErrorWithHint(err.Error(), "nothing was imported; fix the issues or import with --force")
+			}
 			fmt.Fprintf(os.Stderr, "Import failed: %v\n", err)
 			os.Exit(1)
 		}
+		if !dryRun {
+			applyOpLog(ctx, store, findJSONLPath())
+		}
 
 		// Handle dry-run mode
 		if dryRun {
@@ -444,6 +477,12 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 			fmt.Fprintf(os.Stderr, "The import continued successfully - you may want to review the skipped dependencies.\n")
 		}
 
+		// Print issues left alone because they are locked or the project is frozen
+		if len(result.Locked) > 0 {
+			fmt.Fprintf(os.Stderr, "\n⚠️  Warning: Skipped changes to %d locked issue(s): %s\n", len(result.Locked), strings.Join(result.Locked, ", "))
+			fmt.Fprintf(os.Stderr, "Unlock them with 'bd unlock' (or end the freeze with 'bd freeze off') and import again.\n")
+		}
+
 		// Print force message if metadata was updated despite no changes
 		if force && result.Created == 0 && result.Updated == 0 && len(result.IDMapping) == 0 {
 			fmt.Fprintf(os.Stderr, "Metadata updated (database already in sync with JSONL)\n")
@@ -762,6 +801,21 @@ func attemptAutoMerge(conflictedPath string) error {
 	return nil
 }
 
+// isProjectJSONL reports whether path is this project's own JSONL file,
+// as bd sync imports it.
+func isProjectJSONL(path string) bool {
+	if path == "" {
+		return false
+	}
+	own := findJSONLPath()
+	if own == "" {
+		return false
+	}
+	a, err1 := filepath.Abs(path)
+	b, err2 := filepath.Abs(own)
+	return err1 == nil && err2 == nil && a == b
+}
+
 // detectPrefixFromIssues extracts the common prefix from issue IDs
 // Uses utils.ExtractIssuePrefix which handles multi-part prefixes correctly
 func detectPrefixFromIssues(issues []*types.Issue) string {
@@ -800,7 +854,7 @@ func init() {
 	importCmd.Flags().Bool("rename-on-import", false, "Rename imported issues to match database prefix (updates all references)")
 	importCmd.Flags().Bool("clear-duplicate-external-refs", false, "Clear duplicate external_ref values (keeps first occurrence)")
 	importCmd.Flags().String("orphan-handling", "", "How to handle missing parent issues: strict/resurrect/skip/allow (default: use config or 'allow')")
-	importCmd.Flags().Bool("force", false, "Force metadata update even when database is already in sync with JSONL")
+	importCmd.Flags().Bool("force", false, "Force metadata update even when database is already in sync with JSONL, and import issues that break validation rules")
 	importCmd.Flags().Bool("protect-left-snapshot", false, "Protect issues in left snapshot from git-history-backfill")
 	importCmd.Flags().Bool("no-git-history", false, "Skip git history backfill for deletions (passed by bd sync)")
 	importCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output import statistics in JSON format")
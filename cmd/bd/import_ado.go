@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/ado"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// defaultADOQuery selects every work item in the configured project.
+const defaultADOQuery = "SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project ORDER BY [System.Id]"
+
+var importADOCmd = &cobra.Command{
+	Use:   "ado [export.csv]",
+	Short: "Import Azure DevOps / TFS work items",
+	Long: `Import work items from Azure DevOps or TFS.
+
+From a CSV export (Queries > Export to CSV), no credentials needed:
+  bd import ado backlog.csv
+
+Or straight from a WIQL query against the REST API:
+  bd config set ado.org_url "https://dev.azure.com/fabrikam"   # Or a TFS collection URL
+  bd config set ado.project "Fabrikam Fiber"
+  bd auth login ado                                            # Or AZURE_DEVOPS_EXT_PAT
+  bd import ado --query "SELECT [System.Id] FROM WorkItems WHERE [System.State] <> 'Removed'"
+
+Without a file or --query, every work item in the project is imported.
+
+Mapping:
+  Area path       label area:<path>, e.g. area:Fabrikam/Web
+  Tags            labels
+  Parent link     parent-child dependency (Epic > Feature > Story > Task)
+  Epic, Feature   epic; User Story/PBI/Requirement feature; Bug bug; else task
+  State           New/To Do open; Active/Committed/Resolved in_progress;
+                  Closed/Done/Removed closed
+  Priority 1-4    priority 0-3
+
+Each issue's external_ref is the work item URL (ado:<id> for CSV imports),
+so importing again updates the same issues. For CSV exports, include the
+ID column, and either a Parent column or use a tree query (Title 1, Title 2
+... columns) to keep the hierarchy.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		query, _ := cmd.Flags().GetString("query")
+		if len(args) > 0 && query != "" {
+			FatalErrorRespectJSON("give a CSV file or --query, not both")
+		}
+		if !dryRun {
+			CheckReadonly("import ado")
+		}
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("database not available: %v", err)
+		}
+		ctx := rootCtx
+
+		var items []ado.WorkItem
+		if len(args) > 0 {
+			f, err := os.Open(args[0]) // #nosec G304 - user-provided export
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			items, err = ado.ParseCSV(f)
+			_ = f.Close()
+			if err != nil {
+				FatalErrorRespectJSON("parsing %s: %v", args[0], err)
+			}
+		} else {
+			client, err := getADOClient(ctx)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if query == "" {
+				query = defaultADOQuery
+			}
+			ids, err := client.Query(ctx, query)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if items, err = client.GetWorkItems(ctx, ids); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
+
+		issues, parents := adoToBeads(items)
+		result, err := importExternalIssues(ctx, "Azure DevOps", issues, parents, "ado-import", dryRun)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if !dryRun {
+			markDirtyAndScheduleFlush()
+		}
+		printExternalImportResult(result)
+	},
+}
+
+// adoToBeads converts work items to issues, and their parent links to a
+// child-to-parent map of external refs. Links to work items outside the
+// import are dropped.
+func adoToBeads(items []ado.WorkItem) ([]*types.Issue, map[string]string) {
+	refByID := make(map[int]string, len(items))
+	for i := range items {
+		refByID[items[i].ID] = items[i].ExternalRef()
+	}
+	issues := make([]*types.Issue, 0, len(items))
+	parents := make(map[string]string)
+	for i := range items {
+		issues = append(issues, ado.ToBeads(&items[i]))
+		if parentRef, ok := refByID[items[i].ParentID]; ok {
+			parents[items[i].ExternalRef()] = parentRef
+		}
+	}
+	return issues, parents
+}
+
+// getADOClient creates an Azure DevOps client from config, with the token
+// also read from AZURE_DEVOPS_EXT_PAT (the az devops CLI variable) or the
+// credentials store.
+func getADOClient(ctx context.Context) (*ado.Client, error) {
+	orgURL, _ := store.GetConfig(ctx, "ado.org_url")
+	project, _ := store.GetConfig(ctx, "ado.project")
+	pat, _ := lookupSecret(ctx, "ado.pat")
+	switch {
+	case orgURL == "":
+		return nil, fmt.Errorf("ado.org_url not configured\nRun: bd config set ado.org_url \"https://dev.azure.com/<org>\"")
+	case project == "":
+		return nil, fmt.Errorf("ado.project not configured\nRun: bd config set ado.project \"<project>\"")
+	case pat == "":
+		return nil, fmt.Errorf("Azure DevOps token not configured\nRun: bd auth login ado\nOr: export AZURE_DEVOPS_EXT_PAT=YOUR_PAT")
+	}
+	return ado.NewClient(orgURL, project, pat), nil
+}
+
+func init() {
+	importADOCmd.Flags().String("query", "", "WIQL query selecting the work items to import")
+	importADOCmd.Flags().Bool("dry-run", false, "Preview the import without making changes")
+	importCmd.AddCommand(importADOCmd)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/linear"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ExternalImportResult is the JSON output of the bd import <tracker>
+// commands.
+type ExternalImportResult struct {
+	Source       string `json:"source"`
+	Items        int    `json:"items"`
+	Created      int    `json:"created"`
+	Updated      int    `json:"updated"`
+	Unchanged    int    `json:"unchanged"`
+	Skipped      int    `json:"skipped"`
+	Dependencies int    `json:"dependencies"`
+	DryRun       bool   `json:"dry_run,omitempty"`
+}
+
+// importExternalIssues imports issues converted from another tracker. Each
+// issue must carry an external_ref: re-imports update the issues they
+// created before instead of duplicating them. New issues get hash IDs
+// seeded with creator. parents maps a child's external_ref to its parent's;
+// those become parent-child dependencies once both issues exist.
+func importExternalIssues(ctx context.Context, source string, issues []*types.Issue, parents map[string]string, creator string, dryRun bool) (*ExternalImportResult, error) {
+	result := &ExternalImportResult{Source: source, Items: len(issues), DryRun: dryRun}
+	if len(issues) == 0 {
+		return result, nil
+	}
+
+	prefix, _ := store.GetConfig(ctx, "issue_prefix")
+	if prefix == "" {
+		prefix = "bd"
+	}
+	existing, err := store.SearchIssues(ctx, "", types.IssueFilter{IncludeTombstones: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch existing issues for ID collision avoidance: %w", err)
+	}
+	usedIDs := make(map[string]bool, len(existing))
+	knownRefs := make(map[string]bool, len(existing))
+	for _, issue := range existing {
+		usedIDs[issue.ID] = true
+		if issue.ExternalRef != nil {
+			knownRefs[*issue.ExternalRef] = true
+		}
+	}
+	// Keyword rules (labels.auto) label issues imported for the first time
+	for _, issue := range issues {
+		if issue.ExternalRef == nil || !knownRefs[*issue.ExternalRef] {
+			issue.Labels = append(issue.Labels, autoLabels(issue, issue.Labels)...)
+		}
+	}
+	if err := linear.GenerateIssueIDs(issues, prefix, creator, linear.IDGenerationOptions{UsedIDs: usedIDs}); err != nil {
+		return nil, fmt.Errorf("failed to generate issue IDs: %w", err)
+	}
+
+	imported, err := importIssuesCore(ctx, dbPath, store, issues, ImportOptions{DryRun: dryRun, LockActor: getActor(), CheckRules: true})
+	if err != nil {
+		return nil, fmt.Errorf("import failed: %w", err)
+	}
+	result.Created = imported.Created
+	result.Updated = imported.Updated
+	result.Unchanged = imported.Unchanged
+	result.Skipped = imported.Skipped
+	if dryRun {
+		result.Dependencies = len(parents)
+		return result, nil
+	}
+
+	idFor := func(ref string) string {
+		issue, err := store.GetIssueByExternalRef(ctx, ref)
+		if err != nil || issue == nil {
+			return ""
+		}
+		return issue.ID
+	}
+	for childRef, parentRef := range parents {
+		childID, parentID := idFor(childRef), idFor(parentRef)
+		if childID == "" || parentID == "" || hasDependency(ctx, childID, parentID) {
+			continue
+		}
+		dep := &types.Dependency{
+			IssueID:     childID,
+			DependsOnID: parentID,
+			Type:        types.DepParentChild,
+			CreatedAt:   time.Now(),
+		}
+		if err := store.AddDependency(ctx, dep, actor); err != nil {
+			return result, fmt.Errorf("failed to link %s to parent %s: %w", childID, parentID, err)
+		}
+		result.Dependencies++
+	}
+	return result, nil
+}
+
+// hasDependency reports whether issueID already depends on dependsOnID.
+func hasDependency(ctx context.Context, issueID, dependsOnID string) bool {
+	deps, err := store.GetDependencyRecords(ctx, issueID)
+	if err != nil {
+		return false
+	}
+	for _, dep := range deps {
+		if dep.DependsOnID == dependsOnID {
+			return true
+		}
+	}
+	return false
+}
+
+// printExternalImportResult reports an import in text or JSON.
+func printExternalImportResult(result *ExternalImportResult) {
+	if jsonOutput {
+		outputJSON(result)
+		return
+	}
+	verb := "Imported"
+	if result.DryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d %s item(s): %d created, %d updated, %d unchanged", verb, result.Items, result.Source,
+		result.Created, result.Updated, result.Unchanged)
+	if result.Skipped > 0 {
+		fmt.Printf(", %d skipped", result.Skipped)
+	}
+	fmt.Println()
+	if result.Dependencies > 0 {
+		fmt.Printf("Linked %d child issue(s) to their parents\n", result.Dependencies)
+	}
+}
+
+// columnStatus maps a board column or status name to a beads status.
+// overrides (from <source>.status_map.<name> config, lowercase keys) win;
+// otherwise common names are recognized and anything else is open.
+func columnStatus(name string, overrides map[string]string) types.Status {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if status, ok := overrides[key]; ok {
+		return types.Status(status)
+	}
+	switch {
+	case key == "":
+		return types.StatusOpen
+	case strings.Contains(key, "done"), strings.Contains(key, "complete"), strings.Contains(key, "closed"),
+		strings.Contains(key, "shipped"), strings.Contains(key, "released"), strings.Contains(key, "cancel"),
+		strings.Contains(key, "archive"):
+		return types.StatusClosed
+	case strings.Contains(key, "block"), strings.Contains(key, "waiting"), strings.Contains(key, "on hold"):
+		return types.StatusBlocked
+	case strings.Contains(key, "progress"), strings.Contains(key, "doing"), strings.Contains(key, "started"),
+		strings.Contains(key, "review"), strings.Contains(key, "wip"), strings.Contains(key, "active"):
+		return types.StatusInProgress
+	}
+	return types.StatusOpen
+}
+
+// statusOverrides loads <source>.status_map.<name> = <status> config.
+func statusOverrides(ctx context.Context, source string) map[string]string {
+	overrides := make(map[string]string)
+	all, err := store.GetAllConfig(ctx)
+	if err != nil {
+		return overrides
+	}
+	prefix := source + ".status_map."
+	for key, value := range all {
+		if name, ok := strings.CutPrefix(key, prefix); ok {
+			overrides[strings.ToLower(name)] = value
+		}
+	}
+	return overrides
+}
+
+// namedPriority parses a priority written as P0-P4, 0-4 or a word
+// (critical/urgent, high, medium/normal, low, backlog/lowest).
+func namedPriority(name string) (int, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.TrimSuffix(strings.TrimSuffix(key, " priority"), "-priority")
+	if p, err := strconv.Atoi(strings.TrimPrefix(key, "p")); err == nil && p >= 0 && p <= 4 {
+		return p, true
+	}
+	switch key {
+	case "critical", "urgent", "highest":
+		return 0, true
+	case "high":
+		return 1, true
+	case "medium", "normal":
+		return 2, true
+	case "low":
+		return 3, true
+	case "lowest", "backlog", "someday":
+		return 4, true
+	}
+	return 0, false
+}
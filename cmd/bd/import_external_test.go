@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/trello"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestColumnStatus(t *testing.T) {
+	overrides := map[string]string{"icebox": "deferred"}
+	tests := map[string]types.Status{
+		"":            types.StatusOpen,
+		"To Do":       types.StatusOpen,
+		"Backlog":     types.StatusOpen,
+		"In Progress": types.StatusInProgress,
+		"Doing":       types.StatusInProgress,
+		"Code Review": types.StatusInProgress,
+		"Blocked":     types.StatusBlocked,
+		"On Hold":     types.StatusBlocked,
+		"Done ✅":      types.StatusClosed,
+		"Shipped":     types.StatusClosed,
+		"Icebox":      types.StatusDeferred,
+	}
+	for name, want := range tests {
+		if got := columnStatus(name, overrides); got != want {
+			t.Errorf("columnStatus(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestNamedPriority(t *testing.T) {
+	tests := map[string]int{"P0": 0, "p3": 3, "2": 2, "Urgent": 0, "High": 1, "high priority": 1, "Low": 3, "someday": 4}
+	for name, want := range tests {
+		if got, ok := namedPriority(name); !ok || got != want {
+			t.Errorf("namedPriority(%q) = %d, %v; want %d", name, got, ok, want)
+		}
+	}
+	for _, name := range []string{"", "P5", "frontend"} {
+		if _, ok := namedPriority(name); ok {
+			t.Errorf("namedPriority(%q) should not parse", name)
+		}
+	}
+}
+
+func TestTrelloToBeads(t *testing.T) {
+	board, err := trello.Parse(strings.NewReader(`{
+	  "id": "b1",
+	  "lists": [{"id": "l1", "name": "Doing"}, {"id": "l2", "name": "Done"}],
+	  "members": [{"id": "m1", "username": "ana"}],
+	  "cards": [
+	    {"id": "5f1a2b3c0000000000000001", "name": " Card one ", "idList": "l1", "idMembers": ["m1"],
+	     "labels": [{"name": "High"}, {"name": "ui"}], "shortUrl": "https://trello.com/c/one",
+	     "due": "2024-05-01T12:00:00.000Z"},
+	    {"id": "5f1a2b3c0000000000000002", "name": "Card two", "idList": "l2", "shortUrl": "https://trello.com/c/two"},
+	    {"id": "5f1a2b3c0000000000000003", "name": "Archived", "idList": "l1", "closed": true}
+	  ]
+	}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	issues := trelloToBeads(board, nil, false)
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2 (archived card skipped)", len(issues))
+	}
+	one := issues[0]
+	if one.Title != "Card one" || one.Status != types.StatusInProgress || one.Priority != 1 || one.Assignee != "ana" {
+		t.Errorf("card one = %q status %s priority %d assignee %q", one.Title, one.Status, one.Priority, one.Assignee)
+	}
+	if len(one.Labels) != 1 || one.Labels[0] != "ui" {
+		t.Errorf("labels = %v, want [ui]", one.Labels)
+	}
+	if one.DueAt == nil || one.ExternalRef == nil || *one.ExternalRef != "https://trello.com/c/one" {
+		t.Errorf("due_at = %v, external_ref = %v", one.DueAt, one.ExternalRef)
+	}
+	if two := issues[1]; two.Status != types.StatusClosed || two.ClosedAt == nil {
+		t.Errorf("card two = status %s, closed_at %v", two.Status, two.ClosedAt)
+	}
+
+	all := trelloToBeads(board, nil, true)
+	if len(all) != 3 || all[2].Status != types.StatusClosed {
+		t.Errorf("with archived: got %d issues", len(all))
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/gitmine"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// Issues mined from history are labeled git-log; reverts and hotspots also
+// get a label of their kind and an external_ref naming what they came from.
+const (
+	gitLogLabel         = "git-log"
+	gitRevertRefPrefix  = "git-revert:"
+	gitHotspotRefPrefix = "git-hotspot:"
+)
+
+// gitLogCommitsShown bounds the commits listed in a mined issue.
+const gitLogCommitsShown = 10
+
+// GitLogImportResult is the JSON output of bd import git-log.
+type GitLogImportResult struct {
+	*ExternalImportResult
+	Commits   int                 `json:"commits"`
+	Proposals []*gitmine.Proposal `json:"proposals"`
+}
+
+var importGitLogCmd = &cobra.Command{
+	Use:   "git-log",
+	Short: "Seed issues from the repository's commit history",
+	Long: `Mine the commit history for issues, to seed a tracker on an existing
+codebase:
+
+  References   each issue commit messages refer to (#12, GH-12,
+               owner/repo#12, PROJ-123) becomes an issue titled after the
+               first such commit; "fixes/closes/resolves <ref>" closes it
+  Reverts      each reverted change becomes an open bug, closed if a later
+               commit reapplies it
+  Hotspots     each file touched by at least --min-fixes fix commits
+               (fix/bug/hotfix/revert subjects, fixup! and squash!) becomes
+               a task to look at, worst first, up to --max-hotspots
+
+The PR number GitHub appends to squash-merged subjects, like (#45), is not
+an issue reference. Merge commits are skipped.
+
+Issues are labeled git-log (and revert or hotspot), dated by their commits,
+and carry an external_ref: the reference itself, git-revert:<commit> or
+git-hotspot:<file>. Proposals whose external_ref is already tracked are
+left alone, so re-running later only adds what's new and keeps your edits.`,
+	Example: `  bd import git-log --dry-run
+  bd import git-log --since 1y
+  bd import git-log --since 2025-01-01 --min-fixes 5 --max-hotspots 20`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		minFixes, _ := cmd.Flags().GetInt("min-fixes")
+		maxHotspots, _ := cmd.Flags().GetInt("max-hotspots")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		since, err := parseSince(sinceFlag, time.Now())
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if !dryRun {
+			CheckReadonly("import git-log")
+		}
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("database not available: %v", err)
+		}
+		ctx := rootCtx
+
+		root, err := getGitRoot(ctx)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		commits, err := gitmine.Log(ctx, root, since)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		proposals := gitmine.Mine(commits, gitmine.Options{
+			MinFixes:    minFixes,
+			MaxHotspots: maxHotspots,
+			Exists: func(file string) bool {
+				_, err := os.Stat(filepath.Join(root, filepath.FromSlash(file)))
+				return err == nil
+			},
+		})
+
+		var issues []*types.Issue
+		var fresh []*gitmine.Proposal
+		known := 0
+		for _, p := range proposals {
+			issue := gitLogIssue(p, since)
+			existing, err := store.GetIssueByExternalRef(ctx, *issue.ExternalRef)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if existing != nil {
+				known++
+				continue
+			}
+			issues = append(issues, issue)
+			fresh = append(fresh, p)
+		}
+
+		result, err := importExternalIssues(ctx, "git-log", issues, nil, "git-log-import", dryRun)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		result.Items += known
+		result.Unchanged += known
+		if !dryRun && result.Created > 0 {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(&GitLogImportResult{ExternalImportResult: result, Commits: len(commits), Proposals: fresh})
+			return
+		}
+		fmt.Printf("Mined %d commit(s) since %s\n", len(commits), since.Format("2006-01-02"))
+		if dryRun {
+			for i, p := range fresh {
+				state := ""
+				if issues[i].Status == types.StatusClosed {
+					state = ui.RenderMuted("  (closed)")
+				}
+				fmt.Printf("  %-9s %s%s\n", p.Kind, issues[i].Title, state)
+			}
+		}
+		printExternalImportResult(result)
+	},
+}
+
+// gitLogIssue converts a proposal to the issue it would create.
+func gitLogIssue(p *gitmine.Proposal, since time.Time) *types.Issue {
+	first, last := p.First(), p.Last()
+	issue := &types.Issue{
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeTask,
+		CreatedBy: first.Author,
+		CreatedAt: first.Date,
+		UpdatedAt: last.Date,
+		Labels:    []string{gitLogLabel},
+	}
+	var b strings.Builder
+	var ref string
+	switch p.Kind {
+	case gitmine.KindReference:
+		ref = p.Key
+		issue.Title = p.Title
+		for _, c := range p.Commits {
+			if gitmine.IsFix(c) {
+				issue.IssueType = types.TypeBug
+				break
+			}
+		}
+		fmt.Fprintf(&b, "Referenced as %s by %d commit(s):\n\n", p.Key, len(p.Commits))
+	case gitmine.KindRevert:
+		ref = gitRevertRefPrefix + p.Key
+		issue.Title = "Reverted: " + p.Title
+		issue.IssueType = types.TypeBug
+		issue.Labels = append(issue.Labels, "revert")
+		fmt.Fprintf(&b, "The change %q was reverted", p.Title)
+		if p.Fixed {
+			b.WriteString(" and later reapplied")
+		}
+		b.WriteString(":\n\n")
+	case gitmine.KindHotspot:
+		ref = gitHotspotRefPrefix + p.File
+		issue.Title = "Hotspot: " + p.File
+		issue.Labels = append(issue.Labels, "hotspot")
+		fmt.Fprintf(&b, "`%s` needed %d fix commit(s) since %s. Look for the underlying cause: missing tests, unclear ownership or a design that invites bugs.\n\n",
+			p.File, p.Fixes, since.Format("2006-01-02"))
+	}
+	commits := p.Commits
+	if len(commits) > gitLogCommitsShown {
+		commits = commits[len(commits)-gitLogCommitsShown:]
+		fmt.Fprintf(&b, "Latest %d:\n\n", gitLogCommitsShown)
+	}
+	for _, c := range commits {
+		fmt.Fprintf(&b, "- %s %s (%s, %s)\n", c.Short(), c.Subject, c.Author, c.Date.Format("2006-01-02"))
+	}
+	issue.Title = truncateTitle(issue.Title, 200)
+	issue.Description = strings.TrimSpace(b.String())
+	issue.ExternalRef = &ref
+	if p.Fixed {
+		issue.Status = types.StatusClosed
+		closedAt := last.Date
+		issue.ClosedAt = &closedAt
+	}
+	return issue
+}
+
+func init() {
+	importGitLogCmd.Flags().String("since", "1y", "How far back to mine (1y, 6m, 90d or YYYY-MM-DD)")
+	importGitLogCmd.Flags().Int("min-fixes", 3, "Fix commits a file needs to be proposed as a hotspot (0 disables hotspots)")
+	importGitLogCmd.Flags().Int("max-hotspots", 10, "Most hotspots to propose (0 for no limit)")
+	importGitLogCmd.Flags().Bool("dry-run", false, "Preview the proposed issues without creating them")
+	importCmd.AddCommand(importGitLogCmd)
+}
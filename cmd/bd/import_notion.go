@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/notion"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var importNotionCmd = &cobra.Command{
+	Use:   "notion [database-id]",
+	Short: "Import pages from a Notion database",
+	Long: `Import the pages of a Notion database (a task board or tracker).
+
+Configuration:
+  bd auth login notion                           # Or NOTION_TOKEN
+  bd config set notion.database_id "<id>"        # Or pass it as an argument
+
+Create an internal integration at https://www.notion.so/my-integrations and
+share the database with it.
+
+Mapping:
+  Title property        title
+  Status property       status (the database's Status property, or a
+                        select named Status); Done/Complete close the issue,
+                        In progress/Doing/Review mark it in_progress,
+                        Blocked/Waiting block it, anything else is open
+  Priority property     priority, from P0-P4 or urgent/high/medium/low
+  Multi-select          labels (notion.labels_property, default: Tags, or
+                        the first multi-select property)
+  People                the first person becomes the assignee
+  Page content          description; to-do blocks become the acceptance
+                        criteria as a Markdown task list (skip with
+                        --no-content)
+
+Override property names and statuses:
+  bd config set notion.status_property "Stage"
+  bd config set notion.priority_property "Urgency"
+  bd config set notion.status_map.icebox deferred
+
+Each issue's external_ref is the page URL, so importing again updates the
+same issues.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		noContent, _ := cmd.Flags().GetBool("no-content")
+		if !dryRun {
+			CheckReadonly("import notion")
+		}
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("database not available: %v", err)
+		}
+		ctx := rootCtx
+
+		token, _ := lookupSecret(ctx, "notion.token")
+		if token == "" {
+			FatalErrorRespectJSON("Notion token not configured\nRun: bd auth login notion\nOr: export NOTION_TOKEN=secret_...")
+		}
+		databaseID, _ := store.GetConfig(ctx, "notion.database_id")
+		if len(args) > 0 {
+			databaseID = args[0]
+		}
+		if databaseID == "" {
+			FatalErrorRespectJSON("no database given\nRun: bd import notion <database-id>\nOr: bd config set notion.database_id \"<id>\"")
+		}
+
+		client := notion.NewClient(token)
+		if endpoint, _ := store.GetConfig(ctx, "notion.api_endpoint"); endpoint != "" {
+			client.BaseURL = strings.TrimSuffix(endpoint, "/")
+		}
+		pages, err := client.QueryDatabase(ctx, databaseID)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		issues, err := notionToBeads(ctx, client, pages, !noContent)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		result, err := importExternalIssues(ctx, "Notion", issues, nil, "notion-import", dryRun)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if !dryRun {
+			markDirtyAndScheduleFlush()
+		}
+		printExternalImportResult(result)
+	},
+}
+
+// notionProperties names the database properties that map to issue fields.
+type notionProperties struct {
+	status, priority, labels, assignee string
+}
+
+// resolveNotionProperties picks the property names from config, falling
+// back to conventional names and then to the first property of the right
+// type.
+func resolveNotionProperties(ctx context.Context, page *notion.Page) notionProperties {
+	pick := func(configKey string, defaults []string, types ...string) string {
+		if name, _ := store.GetConfig(ctx, configKey); name != "" {
+			return name
+		}
+		for _, name := range defaults {
+			if _, ok := page.Properties[name]; ok {
+				return name
+			}
+		}
+		for _, t := range types {
+			if name := page.PropertyOfType(t); name != "" {
+				return name
+			}
+		}
+		return ""
+	}
+	return notionProperties{
+		status:   pick("notion.status_property", []string{"Status"}, "status"),
+		priority: pick("notion.priority_property", []string{"Priority"}),
+		labels:   pick("notion.labels_property", []string{"Tags", "Labels"}, "multi_select"),
+		assignee: pick("notion.assignee_property", []string{"Assignee", "Assigned to", "Owner"}, "people"),
+	}
+}
+
+// notionToBeads converts database pages to issues, reading each page's
+// content when withContent is set.
+func notionToBeads(ctx context.Context, client *notion.Client, pages []notion.Page, withContent bool) ([]*types.Issue, error) {
+	overrides := statusOverrides(ctx, "notion")
+	var issues []*types.Issue
+	var props notionProperties
+	for i := range pages {
+		page := &pages[i]
+		if page.Archived {
+			continue
+		}
+		if props == (notionProperties{}) {
+			props = resolveNotionProperties(ctx, page)
+		}
+
+		issue := &types.Issue{
+			Title:     page.Title(),
+			Status:    columnStatus(page.Text(props.status), overrides),
+			Priority:  2,
+			IssueType: types.TypeTask,
+			Labels:    page.Options(props.labels),
+			Assignee:  page.Person(props.assignee),
+			CreatedAt: page.CreatedTime,
+			UpdatedAt: page.LastEditedTime,
+		}
+		if issue.Title == "" {
+			issue.Title = "(untitled)"
+		}
+		if p, ok := namedPriority(page.Text(props.priority)); ok {
+			issue.Priority = p
+		}
+		if issue.Status == types.StatusClosed {
+			closedAt := issue.UpdatedAt
+			issue.ClosedAt = &closedAt
+		}
+		if issue.CreatedAt.IsZero() {
+			issue.CreatedAt = time.Now()
+		}
+		if withContent {
+			blocks, err := client.PageContent(ctx, page.ID)
+			if err != nil {
+				return nil, fmt.Errorf("page %q: %w", issue.Title, err)
+			}
+			issue.Description, issue.AcceptanceCriteria = notion.Markdown(blocks)
+		}
+		ref := page.URL
+		if ref == "" {
+			ref = "notion:" + page.ID
+		}
+		issue.ExternalRef = &ref
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func init() {
+	importNotionCmd.Flags().Bool("dry-run", false, "Preview the import without making changes")
+	importNotionCmd.Flags().Bool("no-content", false, "Don't read page content (faster; no description or to-dos)")
+	importCmd.AddCommand(importNotionCmd)
+}
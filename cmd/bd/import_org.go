@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/orgmode"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var importOrgCmd = &cobra.Command{
+	Use:   "org <file.org>",
+	Short: "Import issues from an Emacs org-mode outline",
+	Long: `Import an org-mode file, such as one written by bd export org and then
+groomed in Emacs.
+
+Headings with an :ID: property update that issue; headings with a TODO
+keyword but no ID create new issues. Plain headings without a keyword are
+treated as section titles and skipped. New headings don't get an :ID: written
+back, so export again (bd export org -o <file>) before the next round of
+edits, or they will be created twice.
+
+Mapping:
+  TODO/NEXT                 open
+  STARTED/DOING             in_progress
+  WAITING/HOLD/BLOCKED      blocked
+  DEFERRED/SOMEDAY          deferred
+  DONE/CANCELLED            closed (and any done keyword from #+TODO lines)
+  [#A]-[#E]                 priority P0-P4 (unchanged when there's no cookie)
+  :tags:                    labels
+  DEADLINE / SCHEDULED      due date / defer date
+  :TYPE: / :ASSIGNEE:       issue type / assignee
+  Body                      description
+  Nesting                   the enclosing task heading becomes the parent
+
+Examples:
+  bd import org backlog.org --dry-run
+  bd import org backlog.org`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if !dryRun {
+			CheckReadonly("import org")
+		}
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("database not available: %v", err)
+		}
+		ctx := rootCtx
+
+		f, err := os.Open(args[0]) // #nosec G304 - user-provided org file
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		doc, err := orgmode.Parse(f)
+		_ = f.Close()
+		if err != nil {
+			FatalErrorRespectJSON("%s: %v", args[0], err)
+		}
+
+		result, err := importOrgDocument(ctx, doc, dryRun)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if !dryRun && (result.Created > 0 || result.Updated > 0 || result.Dependencies > 0) {
+			markDirtyAndScheduleFlush()
+		}
+		printExternalImportResult(result)
+	},
+}
+
+// importOrgDocument applies an org outline: updating issues named by :ID:
+// properties, creating issues for new task headings and linking nested
+// headings to their parents.
+func importOrgDocument(ctx context.Context, doc *orgmode.Document, dryRun bool) (*ExternalImportResult, error) {
+	result := &ExternalImportResult{Source: "org", DryRun: dryRun}
+
+	// ids maps heading index to issue ID for task headings
+	ids := make(map[int]string)
+	isTask := func(h *orgmode.Heading) bool {
+		return h.Keyword != "" || h.Properties["ID"] != ""
+	}
+	taskParent := func(i int) int {
+		for p := doc.Headings[i].Parent; p >= 0; p = doc.Headings[p].Parent {
+			if isTask(doc.Headings[p]) {
+				return p
+			}
+		}
+		return -1
+	}
+
+	for i, h := range doc.Headings {
+		if !isTask(h) {
+			continue
+		}
+		result.Items++
+
+		var existing *types.Issue
+		if id := h.Properties["ID"]; id != "" {
+			issue, err := store.GetIssue(ctx, id)
+			if err != nil {
+				return result, fmt.Errorf("looking up %s: %w", id, err)
+			}
+			if issue != nil && issue.Status != types.StatusTombstone {
+				existing = issue
+			}
+		}
+
+		if existing == nil {
+			result.Created++
+			if dryRun {
+				ids[i] = fmt.Sprintf("new-%d", i)
+				continue
+			}
+			issue := orgHeadingIssue(doc, h)
+			if err := store.CreateIssue(ctx, issue, actor); err != nil {
+				return result, fmt.Errorf("creating %q: %w", h.Title, err)
+			}
+			for _, tag := range h.Tags {
+				if err := store.AddLabel(ctx, issue.ID, tag, actor); err != nil {
+					return result, fmt.Errorf("labeling %s: %w", issue.ID, err)
+				}
+			}
+			ids[i] = issue.ID
+			continue
+		}
+
+		ids[i] = existing.ID
+		updates := orgHeadingUpdates(doc, h, existing)
+		addLabels, removeLabels := tagLabelChanges(h.Tags, existing.Labels, orgmode.Tag)
+		if len(updates) == 0 && len(addLabels) == 0 && len(removeLabels) == 0 {
+			result.Unchanged++
+			continue
+		}
+		result.Updated++
+		if dryRun {
+			continue
+		}
+		if len(updates) > 0 {
+			if err := store.UpdateIssue(ctx, existing.ID, updates, actor); err != nil {
+				return result, fmt.Errorf("updating %s: %w", existing.ID, err)
+			}
+		}
+		if err := applyLabelUpdates(ctx, store, existing.ID, actor, nil, addLabels, removeLabels); err != nil {
+			return result, fmt.Errorf("updating labels of %s: %w", existing.ID, err)
+		}
+	}
+
+	// Nesting becomes parent-child dependencies. A heading moved out to the
+	// top level is detached from a parent that is also in the file.
+	inFile := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		inFile[id] = true
+	}
+	for i := range doc.Headings {
+		childID, ok := ids[i]
+		if !ok {
+			continue
+		}
+		parentID := ""
+		if p := taskParent(i); p >= 0 {
+			parentID = ids[p]
+		}
+		var current []string
+		if !dryRun || !isNewOrgID(childID) {
+			deps, err := store.GetDependencyRecords(ctx, childID)
+			if err != nil {
+				return result, fmt.Errorf("reading dependencies of %s: %w", childID, err)
+			}
+			for _, dep := range deps {
+				if dep.Type == types.DepParentChild {
+					current = append(current, dep.DependsOnID)
+				}
+			}
+		}
+		if parentID != "" && slices.Contains(current, parentID) {
+			continue
+		}
+
+		for _, old := range current {
+			if old == parentID || (parentID == "" && !inFile[old]) {
+				continue
+			}
+			result.Dependencies++
+			if !dryRun {
+				if err := store.RemoveDependency(ctx, childID, old, actor); err != nil {
+					return result, fmt.Errorf("detaching %s from %s: %w", childID, old, err)
+				}
+			}
+		}
+		if parentID == "" {
+			continue
+		}
+		result.Dependencies++
+		if dryRun {
+			continue
+		}
+		dep := &types.Dependency{
+			IssueID:     childID,
+			DependsOnID: parentID,
+			Type:        types.DepParentChild,
+			CreatedAt:   time.Now(),
+		}
+		if err := store.AddDependency(ctx, dep, actor); err != nil {
+			return result, fmt.Errorf("linking %s to parent %s: %w", childID, parentID, err)
+		}
+	}
+	return result, nil
+}
+
+// isNewOrgID reports whether id is a dry-run placeholder for a heading that
+// would be created.
+func isNewOrgID(id string) bool {
+	return strings.HasPrefix(id, "new-")
+}
+
+// orgHeadingIssue builds a new issue from a heading.
+func orgHeadingIssue(doc *orgmode.Document, h *orgmode.Heading) *types.Issue {
+	issue := &types.Issue{
+		Title:       h.Title,
+		Description: h.Body,
+		Status:      doc.Status(h.Keyword),
+		Priority:    2,
+		IssueType:   types.TypeTask,
+		Assignee:    h.Properties["ASSIGNEE"],
+		DueAt:       h.Deadline,
+		DeferUntil:  h.Scheduled,
+	}
+	if h.Priority >= 0 {
+		issue.Priority = h.Priority
+	}
+	if t := h.Properties["TYPE"]; t != "" {
+		issue.IssueType = types.IssueType(t)
+	}
+	if issue.Status == types.StatusClosed {
+		closedAt := time.Now()
+		if h.Closed != nil {
+			closedAt = *h.Closed
+		}
+		issue.ClosedAt = &closedAt
+	}
+	return issue
+}
+
+// orgHeadingUpdates returns the changes a heading makes to an existing
+// issue. A missing priority cookie or :TYPE: leaves those fields alone.
+func orgHeadingUpdates(doc *orgmode.Document, h *orgmode.Heading, issue *types.Issue) map[string]interface{} {
+	updates := make(map[string]interface{})
+	// Export writes titles on one line and trims descriptions
+	if title := strings.Join(strings.Fields(h.Title), " "); title != "" && title != strings.Join(strings.Fields(issue.Title), " ") {
+		updates["title"] = h.Title
+	}
+	if h.Body != strings.TrimSpace(issue.Description) {
+		updates["description"] = h.Body
+	}
+	// Keep statuses org has no keyword for (pinned, hooked) when the
+	// heading still shows the keyword they export as
+	if status := doc.Status(h.Keyword); status != issue.Status && orgmode.Keyword(issue.Status) != h.Keyword {
+		updates["status"] = string(status)
+	}
+	if h.Priority >= 0 && h.Priority != issue.Priority {
+		updates["priority"] = h.Priority
+	}
+	if t := h.Properties["TYPE"]; t != "" && types.IssueType(t) != issue.IssueType {
+		updates["issue_type"] = t
+	}
+	if assignee := h.Properties["ASSIGNEE"]; assignee != issue.Assignee {
+		if assignee == "" {
+			updates["assignee"] = nil
+		} else {
+			updates["assignee"] = assignee
+		}
+	}
+	orgDateUpdate(updates, "due_at", h.Deadline, issue.DueAt)
+	orgDateUpdate(updates, "defer_until", h.Scheduled, issue.DeferUntil)
+	return updates
+}
+
+// orgDateUpdate records a date change. Org dates have minute precision, so
+// dates within the same minute are equal.
+func orgDateUpdate(updates map[string]interface{}, field string, org, current *time.Time) {
+	switch {
+	case org == nil && current == nil:
+	case org == nil:
+		updates[field] = nil
+	case current == nil || !org.Truncate(time.Minute).Equal(current.Truncate(time.Minute)):
+		updates[field] = *org
+	}
+}
+
+// tagLabelChanges compares tags from another tool with an issue's labels.
+// A label whose tag form (toTag) is among the tags is kept as is; other tags
+// are added verbatim.
+func tagLabelChanges(tags, labels []string, toTag func(string) string) (add, remove []string) {
+	tagSet := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tagSet[tag] = true
+	}
+	kept := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		if tag := toTag(label); tagSet[tag] {
+			kept[tag] = true
+		} else {
+			remove = append(remove, label)
+		}
+	}
+	for _, tag := range tags {
+		if !kept[tag] {
+			add = append(add, tag)
+			kept[tag] = true
+		}
+	}
+	return add, remove
+}
+
+func init() {
+	importOrgCmd.Flags().Bool("dry-run", false, "Preview the import without making changes")
+	importCmd.AddCommand(importOrgCmd)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/orgmode"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestTagLabelChanges(t *testing.T) {
+	add, remove := tagLabelChanges([]string{"area_api", "new"}, []string{"area:api", "stale"}, orgmode.Tag)
+	if !reflect.DeepEqual(add, []string{"new"}) || !reflect.DeepEqual(remove, []string{"stale"}) {
+		t.Errorf("add = %v, remove = %v", add, remove)
+	}
+}
+
+func TestOrgHeadingUpdates(t *testing.T) {
+	doc, err := orgmode.Parse(strings.NewReader("* DONE Fixed  title\n:PROPERTIES:\n:ID: bd-1\n:END:\nBody\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	due := time.Now()
+	issue := &types.Issue{ID: "bd-1", Title: "Fixed title", Description: "Body\n", Status: types.StatusOpen,
+		Priority: 1, IssueType: types.TypeBug, Assignee: "ana", DueAt: &due}
+
+	updates := orgHeadingUpdates(doc, doc.Headings[0], issue)
+	want := map[string]interface{}{"status": "closed", "assignee": nil, "due_at": nil}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("updates = %v, want %v", updates, want)
+	}
+
+	// Pinned issues export as TODO; an untouched heading leaves them pinned
+	doc, _ = orgmode.Parse(strings.NewReader("* TODO Pinned\n:PROPERTIES:\n:ID: bd-2\n:END:\n"))
+	pinned := &types.Issue{ID: "bd-2", Title: "Pinned", Status: types.StatusPinned, Priority: 2}
+	if updates := orgHeadingUpdates(doc, doc.Headings[0], pinned); len(updates) != 0 {
+		t.Errorf("updates for pinned issue = %v", updates)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/steveyegge/beads/internal/importer"
@@ -167,6 +168,9 @@ type ImportOptions struct {
 	ClearDuplicateExternalRefs bool              // Clear duplicate external_ref values instead of erroring
 	OrphanHandling             string            // Orphan handling mode: strict/resurrect/skip/allow (empty = use config)
 	ProtectLocalExportIDs      map[string]time.Time // IDs from left snapshot with timestamps for timestamp-aware protection (GH#865)
+	Progress                   func(done, total int) // Reports import progress (optional)
+	LockActor                  string            // Skip changes to issues this actor may not modify (bd lock, bd freeze)
+	CheckRules                 bool              // Fail if the import breaks the validation rules in config.yaml
 }
 
 // ImportResult contains statistics about the import operation
@@ -182,6 +186,7 @@ type ImportResult struct {
 	ExpectedPrefix      string            // Database configured prefix
 	MismatchPrefixes    map[string]int    // Map of mismatched prefixes to count
 	SkippedDependencies []string          // Dependencies skipped due to FK constraint violations
+	Locked              []string          // Changed issues skipped because they are locked or the project is frozen
 }
 
 // importIssuesCore handles the core import logic used by both manual and auto-import.
@@ -222,15 +227,19 @@ func importIssuesCore(ctx context.Context, dbPath string, store storage.Storage,
 		ClearDuplicateExternalRefs: opts.ClearDuplicateExternalRefs,
 		OrphanHandling:             importer.OrphanHandling(orphanHandling),
 		ProtectLocalExportIDs:      opts.ProtectLocalExportIDs,
+		Progress:                   opts.Progress,
+		LockActor:                  opts.LockActor,
+		CheckRules:                 opts.CheckRules,
 	}
 
 	// Delegate to the importer package
 	result, err := importer.ImportIssues(ctx, dbPath, store, issues, importerOpts)
-	if err != nil {
+	var interrupted *importer.InterruptedError
+	if err != nil && !errors.As(err, &interrupted) {
 		return nil, err
 	}
 
-	// Convert importer.Result to ImportResult
+	// Convert importer.Result to ImportResult (partial when interrupted)
 	return &ImportResult{
 		Created:             result.Created,
 		Updated:             result.Updated,
@@ -243,7 +252,8 @@ func importIssuesCore(ctx context.Context, dbPath string, store storage.Storage,
 		ExpectedPrefix:      result.ExpectedPrefix,
 		MismatchPrefixes:    result.MismatchPrefixes,
 		SkippedDependencies: result.SkippedDependencies,
-	}, nil
+		Locked:              result.Locked,
+	}, err
 }
 
 
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/trello"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var importTrelloCmd = &cobra.Command{
+	Use:   "trello <board.json>",
+	Short: "Import cards from a Trello board export",
+	Long: `Import the cards of a Trello board exported as JSON
+(Board menu > Print, export and share > Export as JSON).
+
+Mapping:
+  List            status: lists named like Done/Complete/Shipped close the
+                  card, Doing/In Progress/Review mark it in_progress,
+                  Blocked/Waiting block it, anything else is open
+  Labels          labels (unnamed labels by color); a P0-P4 or
+                  high/medium/low label sets the priority instead
+  Checklists      acceptance criteria as a Markdown task list
+  Members         the first member becomes the assignee
+  Due date        due_at
+
+Override the status of any list:
+  bd config set trello.status_map.icebox deferred
+
+Archived cards and cards in archived lists are skipped unless
+--include-archived is given, in which case they are imported closed. Each
+issue's external_ref is the card URL, so importing a newer export of the
+same board updates the same issues.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		includeArchived, _ := cmd.Flags().GetBool("include-archived")
+		if !dryRun {
+			CheckReadonly("import trello")
+		}
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("database not available: %v", err)
+		}
+		ctx := rootCtx
+
+		f, err := os.Open(args[0]) // #nosec G304 - user-provided export
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		board, err := trello.Parse(f)
+		_ = f.Close()
+		if err != nil {
+			FatalErrorRespectJSON("%s: %v", args[0], err)
+		}
+
+		issues := trelloToBeads(board, statusOverrides(ctx, "trello"), includeArchived)
+		result, err := importExternalIssues(ctx, "Trello", issues, nil, "trello-import", dryRun)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if !dryRun {
+			markDirtyAndScheduleFlush()
+		}
+		printExternalImportResult(result)
+	},
+}
+
+// trelloToBeads converts a board's cards to issues.
+func trelloToBeads(board *trello.Board, overrides map[string]string, includeArchived bool) []*types.Issue {
+	archivedLists := make(map[string]bool)
+	for _, l := range board.Lists {
+		archivedLists[l.ID] = l.Closed
+	}
+
+	var issues []*types.Issue
+	for i := range board.Cards {
+		card := &board.Cards[i]
+		archived := card.Closed || archivedLists[card.IDList]
+		if archived && !includeArchived {
+			continue
+		}
+
+		issue := &types.Issue{
+			Title:              strings.TrimSpace(card.Name),
+			Description:        card.Desc,
+			AcceptanceCriteria: board.ChecklistMarkdown(card),
+			Status:             columnStatus(board.ListName(card), overrides),
+			Priority:           2,
+			IssueType:          types.TypeTask,
+			CreatedAt:          card.CreatedAt(),
+			UpdatedAt:          card.DateLastActivity,
+		}
+		if issue.UpdatedAt.IsZero() {
+			issue.UpdatedAt = issue.CreatedAt
+		}
+		for _, label := range trello.LabelNames(card) {
+			if p, ok := namedPriority(label); ok {
+				issue.Priority = p
+				continue
+			}
+			issue.Labels = append(issue.Labels, label)
+		}
+		if len(card.IDMembers) > 0 {
+			issue.Assignee = board.Username(card.IDMembers[0])
+		}
+		if card.Due != nil {
+			if due, err := time.Parse(time.RFC3339, *card.Due); err == nil {
+				issue.DueAt = &due
+			}
+		}
+		if archived {
+			issue.Status = types.StatusClosed
+		}
+		if issue.Status == types.StatusClosed {
+			closedAt := issue.UpdatedAt
+			issue.ClosedAt = &closedAt
+		}
+		ref := card.ShortURL
+		if ref == "" {
+			ref = card.URL
+		}
+		if ref == "" {
+			ref = "trello:" + card.ID
+		}
+		issue.ExternalRef = &ref
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+func init() {
+	importTrelloCmd.Flags().Bool("dry-run", false, "Preview the import without making changes")
+	importTrelloCmd.Flags().Bool("include-archived", false, "Also import archived cards, as closed issues")
+	importCmd.AddCommand(importTrelloCmd)
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var ingestCmd = &cobra.Command{
+	Use:     "ingest",
+	GroupID: "sync",
+	Short:   "Create issues from external inputs (email, ...)",
+	Long: `Create issues from external inputs piped into bd.
+
+Ingested issues record where they came from in external_ref, so feeding the
+same input twice is a no-op.`,
+}
+
+func init() {
+	rootCmd.AddCommand(ingestCmd)
+}
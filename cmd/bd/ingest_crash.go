@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// Crash issues are bugs labeled crash whose external_ref holds the
+// fingerprint of their stack trace, so the same crash lands on one issue.
+const (
+	crashLabel     = "crash"
+	crashRefPrefix = "crash:"
+	// crashFrames is how many of the innermost frames identify a crash.
+	crashFrames = 5
+	// crashMaxTraceLines caps the trace kept in the description.
+	crashMaxTraceLines = 200
+)
+
+var (
+	// crashLine matches the first line of an occurrence comment.
+	crashLine = regexp.MustCompile(`^Crashed$`)
+
+	goFramePattern     = regexp.MustCompile(`^([\w./*()\[\]-]+)\(.*\)$`)
+	pythonFramePattern = regexp.MustCompile(`^\s*File "([^"]+)", line \d+, in (\S+)`)
+	atFramePattern     = regexp.MustCompile(`^\s*at (?:async )?([^\s(]+)`)
+	hexPattern         = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+	quotedPattern      = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	numberPattern      = regexp.MustCompile(`\d+`)
+)
+
+// crashReport is a stack trace reduced to what identifies it.
+type crashReport struct {
+	Message     string   // The error line, e.g. "panic: runtime error: ..."
+	Frames      []string // Function names, innermost first
+	Fingerprint string
+	Trace       string
+}
+
+// CrashOccurrence is one recorded occurrence of a crash.
+type CrashOccurrence struct {
+	At          time.Time `json:"at"`
+	Release     string    `json:"release,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+}
+
+// CrashStats summarizes the occurrences recorded on a crash issue.
+type CrashStats struct {
+	Fingerprint string     `json:"fingerprint"`
+	Count       int        `json:"count"`
+	FirstSeen   *time.Time `json:"first_seen,omitempty"`
+	LastSeen    *time.Time `json:"last_seen,omitempty"`
+	Releases    []string   `json:"releases,omitempty"`
+}
+
+// IngestCrashResult is the JSON output of `bd ingest crash`.
+type IngestCrashResult struct {
+	Issue    *types.Issue `json:"issue"`
+	Created  bool         `json:"created"`
+	Reopened bool         `json:"reopened"` // A closed crash recurred
+	Stats    *CrashStats  `json:"stats"`
+}
+
+var ingestCrashCmd = &cobra.Command{
+	Use:   "crash",
+	Short: "Create or update an issue from a stack trace read from stdin",
+	Long: `Create or update an issue from a crash report read from stdin.
+
+The stack trace is fingerprinted from its error line and innermost frames,
+ignoring addresses, line numbers and other values that change between
+runs. The first occurrence of a fingerprint creates a bug labeled crash with
+external_ref crash:<fingerprint> and the trace in its description; every
+occurrence, including the first, adds a "Crashed" comment, so bd show can
+report the count and when the crash was first and last seen. A crash whose
+issue is closed reopens it: it regressed.
+
+Go panics, Python tracebacks and Java/JavaScript "at ..." traces are
+understood. Other input is fingerprinted by its first line. Use
+--fingerprint to group by an ID your error tracker already computed.
+
+Examples:
+  ./server 2> >(bd ingest crash --release "$VERSION")
+  bd ingest crash --env production --label api < panic.txt
+  bd ingest crash --fingerprint "$SENTRY_GROUP_ID" --json < event.txt`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("ingest crash")
+		labels, _ := cmd.Flags().GetStringSlice("label")
+		priority, _ := cmd.Flags().GetInt("priority")
+		assignee, _ := cmd.Flags().GetString("assignee")
+		release, _ := cmd.Flags().GetString("release")
+		env, _ := cmd.Flags().GetString("env")
+		fingerprint, _ := cmd.Flags().GetString("fingerprint")
+		if strings.ContainsAny(release+env+fingerprint, "\n") {
+			FatalErrorCode(ErrCodeUsage, "--release, --env and --fingerprint can't contain newlines")
+		}
+
+		if err := ensureDirectMode("ingest crash requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			FatalErrorRespectJSON("reading stdin: %v", err)
+		}
+		report := parseCrash(string(data))
+		if report == nil {
+			FatalErrorRespectJSON("no stack trace on stdin")
+		}
+		if fingerprint = strings.TrimSpace(fingerprint); fingerprint != "" {
+			report.Fingerprint = fingerprint
+		}
+
+		result := &IngestCrashResult{}
+		ref := crashRefPrefix + report.Fingerprint
+		issue, err := store.GetIssueByExternalRef(ctx, ref)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if issue == nil {
+			issue = &types.Issue{
+				Title:       truncateTitle(report.Message, 120),
+				Description: formatCrashDescription(report),
+				Status:      types.StatusOpen,
+				Priority:    priority,
+				IssueType:   types.TypeBug,
+				Assignee:    assignee,
+				ExternalRef: &ref,
+				CreatedBy:   getActorWithGit(),
+				Owner:       getOwner(),
+			}
+			if err := store.CreateIssue(ctx, issue, actor); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			for _, label := range append([]string{crashLabel}, labels...) {
+				if err := store.AddLabel(ctx, issue.ID, label, actor); err != nil {
+					WarnError("failed to add label %s: %v", label, err)
+				}
+			}
+			result.Created = true
+		} else if issue.Status == types.StatusClosed {
+			if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+				FatalErrorRespectJSON("reopening %s: %v", issue.ID, err)
+			}
+			issue.Status = types.StatusOpen
+			result.Reopened = true
+		}
+
+		text := "Crashed"
+		if release != "" {
+			text += "\nRelease: " + release
+		}
+		if env != "" {
+			text += "\nEnvironment: " + env
+		}
+		if _, err := store.AddIssueComment(ctx, issue.ID, getActorWithGit(), text); err != nil {
+			FatalErrorRespectJSON("recording occurrence on %s: %v", issue.ID, err)
+		}
+		markDirtyAndScheduleFlush()
+
+		comments, err := store.GetIssueComments(ctx, issue.ID)
+		if err != nil {
+			FatalErrorRespectJSON("getting comments: %v", err)
+		}
+		result.Issue = issue
+		result.Stats = crashStats(issue, comments)
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		switch {
+		case result.Created:
+			fmt.Printf("%s Created issue %s: %s\n", ui.RenderPass("✓"), ui.RenderID(issue.ID), issue.Title)
+		case result.Reopened:
+			fmt.Printf("%s Reopened %s: crash regressed (%d occurrences)\n", ui.RenderAccent("↻"), ui.RenderID(issue.ID), result.Stats.Count)
+		default:
+			fmt.Printf("%s Recorded crash on %s (%d occurrences)\n", ui.RenderPass("✓"), ui.RenderID(issue.ID), result.Stats.Count)
+		}
+	},
+}
+
+// parseCrash extracts the error line and frames of a stack trace and
+// fingerprints them. It returns nil for empty input.
+func parseCrash(trace string) *crashReport {
+	trace = strings.TrimSpace(strings.ReplaceAll(trace, "\r\n", "\n"))
+	if trace == "" {
+		return nil
+	}
+	lines := strings.Split(trace, "\n")
+	r := &crashReport{Trace: trace}
+
+	python := false
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "Traceback (most recent call last)"):
+			python = true
+		case strings.HasPrefix(line, "panic: "), strings.HasPrefix(line, "fatal error: "):
+			if r.Message == "" {
+				r.Message = line
+			}
+		case pythonFramePattern.MatchString(line):
+			m := pythonFramePattern.FindStringSubmatch(line)
+			r.Frames = append(r.Frames, path.Base(strings.ReplaceAll(m[1], `\`, "/"))+":"+m[2])
+		case atFramePattern.MatchString(line):
+			m := atFramePattern.FindStringSubmatch(line)
+			r.Frames = append(r.Frames, m[1])
+		case goFramePattern.MatchString(line) && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") && strings.Contains(lines[i+1], ".go:"):
+			fn := goFramePattern.FindStringSubmatch(line)[1]
+			if fn != "panic" && !strings.HasPrefix(fn, "runtime.") {
+				r.Frames = append(r.Frames, fn)
+			}
+		}
+	}
+	if python {
+		// Python prints innermost last, with the error after the frames
+		for i, j := 0, len(r.Frames)-1; i < j; i, j = i+1, j-1 {
+			r.Frames[i], r.Frames[j] = r.Frames[j], r.Frames[i]
+		}
+		for i := len(lines) - 1; i >= 0 && r.Message == ""; i-- {
+			if line := lines[i]; line != "" && !strings.HasPrefix(line, " ") {
+				r.Message = line
+			}
+		}
+	}
+	if r.Message == "" {
+		r.Message = strings.TrimSpace(lines[0])
+	}
+	if len(r.Frames) > crashFrames {
+		r.Frames = r.Frames[:crashFrames]
+	}
+
+	h := sha256.New()
+	h.Write([]byte(normalizeCrashMessage(r.Message)))
+	for _, f := range r.Frames {
+		h.Write([]byte("\n" + f))
+	}
+	r.Fingerprint = hex.EncodeToString(h.Sum(nil))[:16]
+	return r
+}
+
+// normalizeCrashMessage blanks the parts of an error line that vary
+// between occurrences of the same crash: addresses, numbers and quoted values.
+func normalizeCrashMessage(msg string) string {
+	msg = hexPattern.ReplaceAllString(msg, "0x?")
+	msg = quotedPattern.ReplaceAllString(msg, `"?"`)
+	return numberPattern.ReplaceAllString(msg, "?")
+}
+
+// formatCrashDescription renders the description of a new crash issue.
+func formatCrashDescription(r *crashReport) string {
+	lines := strings.Split(r.Trace, "\n")
+	if len(lines) > crashMaxTraceLines {
+		lines = append(lines[:crashMaxTraceLines], fmt.Sprintf("... (%d more lines)", len(lines)-crashMaxTraceLines))
+	}
+	return fmt.Sprintf("Crash fingerprint `%s`, recorded by bd ingest crash.\n\n```\n%s\n```", r.Fingerprint, strings.Join(lines, "\n"))
+}
+
+// parseCrashOccurrence reads an occurrence back from its comment, or
+// returns nil for other comments.
+func parseCrashOccurrence(c *types.Comment) *CrashOccurrence {
+	lines := strings.Split(c.Text, "\n")
+	if !crashLine.MatchString(lines[0]) {
+		return nil
+	}
+	o := &CrashOccurrence{At: c.CreatedAt}
+	for _, line := range lines[1:] {
+		key, value, _ := strings.Cut(line, ": ")
+		switch key {
+		case "Release":
+			o.Release = value
+		case "Environment":
+			o.Environment = value
+		}
+	}
+	return o
+}
+
+// crashStats summarizes a crash issue's occurrences, or returns nil if the
+// issue wasn't created by bd ingest crash.
+func crashStats(issue *types.Issue, comments []*types.Comment) *CrashStats {
+	if issue.ExternalRef == nil || !strings.HasPrefix(*issue.ExternalRef, crashRefPrefix) {
+		return nil
+	}
+	stats := &CrashStats{Fingerprint: strings.TrimPrefix(*issue.ExternalRef, crashRefPrefix)}
+	for _, c := range comments {
+		o := parseCrashOccurrence(c)
+		if o == nil {
+			continue
+		}
+		stats.Count++
+		at := o.At
+		if stats.FirstSeen == nil || at.Before(*stats.FirstSeen) {
+			stats.FirstSeen = &at
+		}
+		if stats.LastSeen == nil || !at.Before(*stats.LastSeen) {
+			stats.LastSeen = &at
+		}
+		if o.Release != "" && !containsString(stats.Releases, o.Release) {
+			stats.Releases = append(stats.Releases, o.Release)
+		}
+	}
+	return stats
+}
+
+// printCrashStats prints the occurrence summary of a crash issue in bd show.
+func printCrashStats(ctx context.Context, s storage.Storage, issue *types.Issue) {
+	if issue.ExternalRef == nil || !strings.HasPrefix(*issue.ExternalRef, crashRefPrefix) {
+		return
+	}
+	comments, _ := s.GetIssueComments(ctx, issue.ID)
+	printCrashOccurrences(issue, comments)
+}
+
+// printCrashOccurrences is printCrashStats for callers that already have
+// the issue's comments.
+func printCrashOccurrences(issue *types.Issue, comments []*types.Comment) {
+	stats := crashStats(issue, comments)
+	if stats == nil || stats.Count == 0 {
+		return
+	}
+	line := fmt.Sprintf("Crash: %d occurrences · first %s · last %s",
+		stats.Count, stats.FirstSeen.Local().Format("2006-01-02 15:04"), stats.LastSeen.Local().Format("2006-01-02 15:04"))
+	if len(stats.Releases) > 0 {
+		line += " · releases " + strings.Join(stats.Releases, ", ")
+	}
+	fmt.Println(line)
+}
+
+func init() {
+	ingestCrashCmd.Flags().StringSliceP("label", "l", nil, "Labels to add to a new issue (repeatable)")
+	ingestCrashCmd.Flags().IntP("priority", "p", 1, "Priority of a new issue (0-4)")
+	ingestCrashCmd.Flags().StringP("assignee", "a", "", "Assignee of a new issue")
+	ingestCrashCmd.Flags().String("release", "", "Release or version that crashed")
+	ingestCrashCmd.Flags().String("env", "", "Environment that crashed (e.g. production)")
+	ingestCrashCmd.Flags().String("fingerprint", "", "Group by this ID instead of fingerprinting the trace")
+	ingestCmd.AddCommand(ingestCrashCmd)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParseCrash(t *testing.T) {
+	goTrace := func(addr, index string) string {
+		return `panic: runtime error: index out of range [` + index + `] with length 3
+
+goroutine 1 [running]:
+main.(*Server).handle(0x` + addr + `, {0x1, 0x2})
+	/app/server.go:42 +0x1d
+main.main()
+	/app/main.go:10 +0x25
+exit status 2`
+	}
+	a, b := parseCrash(goTrace("c000010000", "5")), parseCrash(goTrace("c000020000", "7"))
+	if a.Message != "panic: runtime error: index out of range [5] with length 3" {
+		t.Errorf("go message = %q", a.Message)
+	}
+	if strings.Join(a.Frames, ",") != "main.(*Server).handle,main.main" {
+		t.Errorf("go frames = %v", a.Frames)
+	}
+	if a.Fingerprint != b.Fingerprint {
+		t.Errorf("addresses and indexes changed the fingerprint")
+	}
+
+	py := parseCrash(`Traceback (most recent call last):
+  File "/srv/app.py", line 12, in <module>
+    main()
+  File "/srv/app.py", line 8, in main
+    load("x")
+KeyError: 'x'`)
+	if py.Message != "KeyError: 'x'" || strings.Join(py.Frames, ",") != "app.py:main,app.py:<module>" {
+		t.Errorf("python = %q %v", py.Message, py.Frames)
+	}
+
+	java := parseCrash(`java.lang.NullPointerException: null
+	at com.example.Foo.bar(Foo.java:12)
+	at com.example.Main.main(Main.java:3)`)
+	if java.Message != "java.lang.NullPointerException: null" || strings.Join(java.Frames, ",") != "com.example.Foo.bar,com.example.Main.main" {
+		t.Errorf("java = %q %v", java.Message, java.Frames)
+	}
+	if java.Fingerprint == a.Fingerprint {
+		t.Errorf("different crashes share a fingerprint")
+	}
+	if parseCrash("  \n") != nil {
+		t.Errorf("empty input parsed")
+	}
+}
+
+func TestCrashStats(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	ref := crashRefPrefix + "abc"
+	issue := &types.Issue{ID: "bd-1", ExternalRef: &ref}
+	comments := []*types.Comment{
+		{Text: "Crashed\nRelease: v1.0\nEnvironment: prod", CreatedAt: now.Add(-2 * time.Hour)},
+		{Text: "Looking into it", CreatedAt: now.Add(-time.Hour)},
+		{Text: "Crashed\nRelease: v1.1", CreatedAt: now},
+		{Text: "Crashed\nRelease: v1.0", CreatedAt: now.Add(-time.Hour)},
+	}
+	stats := crashStats(issue, comments)
+	if stats.Fingerprint != "abc" || stats.Count != 3 || !stats.FirstSeen.Equal(now.Add(-2*time.Hour)) || !stats.LastSeen.Equal(now) {
+		t.Errorf("stats = %+v", stats)
+	}
+	if strings.Join(stats.Releases, ",") != "v1.0,v1.1" {
+		t.Errorf("releases = %v", stats.Releases)
+	}
+	if crashStats(&types.Issue{ID: "bd-2"}, comments) != nil {
+		t.Errorf("stats for an issue without a crash ref")
+	}
+}
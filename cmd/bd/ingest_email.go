@@ -0,0 +1,489 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/attachments"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/imap"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// defaultEmailReplyTemplate is the reply suggested after ingesting an email.
+const defaultEmailReplyTemplate = `To: {{.Sender}}
+Subject: Re: {{.Subject}}
+{{- if .MessageID}}
+In-Reply-To: <{{.MessageID}}>
+{{- end}}
+
+Thanks for getting in touch. Your request is tracked as {{.ID}}:
+  {{.Title}}
+
+Please include {{.ID}} in the subject of any follow-up messages.
+`
+
+// parsedEmail is the part of an RFC 822 message that becomes an issue.
+type parsedEmail struct {
+	From        string // Sender address
+	FromName    string
+	Subject     string
+	MessageID   string
+	Body        string
+	Attachments []emailAttachment
+}
+
+// emailAttachment is a decoded MIME attachment.
+type emailAttachment struct {
+	Filename string
+	Data     []byte
+}
+
+// IngestEmailResult is the JSON output of `bd ingest email`.
+type IngestEmailResult struct {
+	Issue       *types.Issue `json:"issue"`
+	Created     bool         `json:"created"` // False if the message was already ingested
+	Attachments []string     `json:"attachments,omitempty"`
+	Reply       string       `json:"reply"`
+}
+
+var ingestEmailCmd = &cobra.Command{
+	Use:   "email",
+	Short: "Create issues from RFC 822 emails on stdin or in an IMAP mailbox",
+	Long: `Create an issue from an email message (RFC 822 format) read from stdin, or
+from each unseen message of an IMAP mailbox with --imap.
+
+The subject becomes the title and the plain-text body becomes the description.
+The sender is recorded on the issue, and the Message-ID is stored as
+external_ref (email:<message-id>) so re-delivered messages are not duplicated.
+Attachments are saved under .beads/attachments/<issue-id>/ and listed in a
+comment on the issue.
+
+A reply containing the new issue ID is printed so it can be sent back to the
+requester. Customize it with --reply-template (Go text/template with .ID,
+.Title, .Subject, .Sender, and .MessageID).
+
+With --imap, bd logs in to the mailbox in email.imap.url
+(imaps://host[:port]/mailbox, INBOX by default) as email.imap.username, with
+the password from 'bd auth login imap' or BD_IMAP_PASSWORD. Each unseen
+message is ingested and then flagged seen; messages that fail to parse are
+left unseen. One run polls once: schedule it to keep a support inbox flowing.
+
+Examples:
+  bd ingest email < message.eml
+  bd ingest email --label support --type bug --priority 1 < message.eml
+  bd ingest email --reply-template reply.tmpl --json < message.eml
+  bd ingest email --imap --label support
+  bd schedule add "*/5 * * * *" "ingest email --imap --label support"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("ingest email")
+		useIMAP, _ := cmd.Flags().GetBool("imap")
+		labels, _ := cmd.Flags().GetStringSlice("label")
+		issueType, _ := cmd.Flags().GetString("type")
+		priority, _ := cmd.Flags().GetInt("priority")
+		assignee, _ := cmd.Flags().GetString("assignee")
+		replyTemplatePath, _ := cmd.Flags().GetString("reply-template")
+
+		if err := ensureDirectMode("ingest email requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		replyTmpl := defaultEmailReplyTemplate
+		if replyTemplatePath != "" {
+			data, err := os.ReadFile(replyTemplatePath) // #nosec G304 -- user-specified template path
+			if err != nil {
+				FatalErrorRespectJSON("reading reply template: %v", err)
+			}
+			replyTmpl = string(data)
+		}
+		tmpl, err := template.New("reply").Parse(replyTmpl)
+		if err != nil {
+			FatalErrorRespectJSON("parsing reply template: %v", err)
+		}
+		opts := &emailIngestOptions{
+			labels:    labels,
+			issueType: issueType,
+			priority:  priority,
+			assignee:  assignee,
+			reply:     tmpl,
+		}
+
+		if useIMAP {
+			results, err := ingestIMAP(ctx, opts)
+			if jsonOutput {
+				if err != nil {
+					FatalErrorRespectJSON("%v", err)
+				}
+				outputJSON(results)
+				return
+			}
+			for i, result := range results {
+				if i > 0 {
+					fmt.Println()
+				}
+				printIngestEmailResult(result)
+			}
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if len(results) == 0 {
+				fmt.Println("No unseen messages")
+			}
+			return
+		}
+
+		email, err := parseEmail(os.Stdin)
+		if err != nil {
+			FatalErrorRespectJSON("parsing email: %v", err)
+		}
+		result, err := ingestEmail(ctx, email, opts)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		printIngestEmailResult(result)
+	},
+}
+
+// emailIngestOptions are the fields given to issues created from emails
+// and the reply suggested for them.
+type emailIngestOptions struct {
+	labels    []string
+	issueType string
+	priority  int
+	assignee  string
+	reply     *template.Template
+}
+
+// ingestEmail creates an issue from email, or finds the issue it was
+// already ingested as, and renders the reply.
+func ingestEmail(ctx context.Context, email *parsedEmail, opts *emailIngestOptions) (*IngestEmailResult, error) {
+	result := &IngestEmailResult{}
+	var externalRef string
+	if email.MessageID != "" {
+		externalRef = "email:" + email.MessageID
+		existing, err := store.GetIssueByExternalRef(ctx, externalRef)
+		if err != nil {
+			return nil, err
+		}
+		result.Issue = existing
+	}
+
+	if result.Issue == nil {
+		issue := &types.Issue{
+			Title:       email.Subject,
+			Description: email.Body,
+			Status:      types.StatusOpen,
+			Priority:    opts.priority,
+			IssueType:   types.IssueType(opts.issueType).Normalize(),
+			Assignee:    opts.assignee,
+			Labels:      opts.labels, // Added below; set so the validation rules see them
+			Sender:      email.From,
+			CreatedBy:   getActorWithGit(),
+			Owner:       getOwner(),
+		}
+		if externalRef != "" {
+			issue.ExternalRef = &externalRef
+		}
+		if err := store.CreateIssue(ctx, issue, actor); err != nil {
+			return nil, err
+		}
+		for _, label := range opts.labels {
+			if err := store.AddLabel(ctx, issue.ID, label, actor); err != nil {
+				WarnError("failed to add label %s: %v", label, err)
+			}
+		}
+
+		saved, err := saveEmailAttachments(issue.ID, email.Attachments)
+		if err != nil {
+			WarnError("failed to save attachments: %v", err)
+		}
+		if len(saved) > 0 {
+			text := "Attachments from email:\n- " + strings.Join(describeAttachments(saved), "\n- ")
+			if _, err := store.AddIssueComment(ctx, issue.ID, email.From, text); err != nil {
+				WarnError("failed to record attachments: %v", err)
+			}
+		}
+		markDirtyAndScheduleFlush()
+
+		result.Issue = issue
+		result.Created = true
+		for _, a := range saved {
+			result.Attachments = append(result.Attachments, a.Path)
+		}
+	}
+
+	var reply bytes.Buffer
+	if err := opts.reply.Execute(&reply, map[string]string{
+		"ID":        result.Issue.ID,
+		"Title":     result.Issue.Title,
+		"Subject":   email.Subject,
+		"Sender":    email.From,
+		"MessageID": email.MessageID,
+	}); err != nil {
+		return nil, fmt.Errorf("rendering reply template: %w", err)
+	}
+	result.Reply = reply.String()
+	return result, nil
+}
+
+// ingestIMAP ingests the unseen messages of the configured IMAP mailbox,
+// flagging each one seen once its issue exists. The results of the
+// messages ingested before a failure are returned with the error.
+func ingestIMAP(ctx context.Context, opts *emailIngestOptions) ([]*IngestEmailResult, error) {
+	rawURL, _ := store.GetConfig(ctx, "email.imap.url")
+	if rawURL == "" {
+		return nil, fmt.Errorf("email.imap.url is not set (e.g. bd config set email.imap.url imaps://imap.example.com/INBOX)")
+	}
+	srv, err := imap.ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	username, _ := store.GetConfig(ctx, "email.imap.username")
+	if username == "" {
+		return nil, fmt.Errorf("email.imap.username is not set")
+	}
+	password, _ := lookupSecret(ctx, "email.imap.password")
+	if password == "" {
+		return nil, fmt.Errorf("no IMAP password (run 'bd auth login imap' or set BD_IMAP_PASSWORD)")
+	}
+
+	client, err := imap.Dial(ctx, srv)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+	if err := client.Login(username, password); err != nil {
+		return nil, err
+	}
+	if err := client.Select(srv.Mailbox); err != nil {
+		return nil, err
+	}
+	uids, err := client.Unseen()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*IngestEmailResult{}
+	for _, uid := range uids {
+		raw, err := client.Fetch(uid)
+		if err != nil {
+			return results, err
+		}
+		email, err := parseEmail(bytes.NewReader(raw))
+		if err != nil {
+			WarnError("skipping message %d in %s: %v", uid, srv.Mailbox, err)
+			continue
+		}
+		result, err := ingestEmail(ctx, email, opts)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+		// A crash before this leaves the message unseen; its Message-ID
+		// keeps the next poll from filing it twice.
+		if err := client.MarkSeen(uid); err != nil {
+			return results, err
+		}
+	}
+	return results, client.Logout()
+}
+
+// printIngestEmailResult prints the issue an email became and its reply.
+func printIngestEmailResult(result *IngestEmailResult) {
+	if result.Created {
+		fmt.Printf("%s Created issue %s from %s: %s\n", ui.RenderPass("✓"),
+			ui.RenderID(result.Issue.ID), result.Issue.Sender, result.Issue.Title)
+		for _, path := range result.Attachments {
+			fmt.Printf("  Attachment: %s\n", path)
+		}
+	} else {
+		fmt.Printf("%s Already ingested as %s\n", ui.RenderMuted("○"), ui.RenderID(result.Issue.ID))
+	}
+	fmt.Printf("\n%s\n%s", ui.RenderMuted("--- reply ---"), result.Reply)
+}
+
+// parseEmail reads an RFC 822 message and extracts the subject, sender,
+// plain-text body, and attachments.
+func parseEmail(r io.Reader) (*parsedEmail, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := new(mime.WordDecoder)
+	decode := func(s string) string {
+		if out, err := dec.DecodeHeader(s); err == nil {
+			return out
+		}
+		return s
+	}
+
+	email := &parsedEmail{
+		Subject:   strings.TrimSpace(decode(msg.Header.Get("Subject"))),
+		MessageID: strings.Trim(strings.TrimSpace(msg.Header.Get("Message-ID")), "<>"),
+	}
+	if email.Subject == "" {
+		email.Subject = "(no subject)"
+	}
+	from := msg.Header.Get("From")
+	if from == "" {
+		return nil, fmt.Errorf("missing From header")
+	}
+	if addr, err := mail.ParseAddress(from); err == nil {
+		email.From = addr.Address
+		email.FromName = addr.Name
+	} else {
+		email.From = strings.TrimSpace(decode(from))
+	}
+
+	var htmlBody string
+	err = walkEmailPart(msg.Header, msg.Body, func(mediaType, filename string, data []byte) {
+		switch {
+		case filename != "":
+			email.Attachments = append(email.Attachments, emailAttachment{Filename: filename, Data: data})
+		case mediaType == "text/plain" && email.Body == "":
+			email.Body = string(data)
+		case mediaType == "text/html" && htmlBody == "":
+			htmlBody = string(data)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if email.Body == "" && htmlBody != "" {
+		email.Body = stripHTML(htmlBody)
+	}
+	email.Body = strings.TrimSpace(strings.ReplaceAll(email.Body, "\r\n", "\n"))
+	return email, nil
+}
+
+// walkEmailPart decodes a (possibly multipart) MIME entity and calls visit for
+// each leaf part with its media type, attachment filename (if any), and
+// decoded content.
+func walkEmailPart(header interface{ Get(string) string }, body io.Reader, visit func(mediaType, filename string, data []byte)) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := walkEmailPart(part.Header, part, visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	var reader io.Reader = body
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		reader = base64.NewDecoder(base64.StdEncoding, newBase64Cleaner(body))
+	case "quoted-printable":
+		reader = quotedprintable.NewReader(body)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	filename := ""
+	if _, dparams, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil {
+		filename = dparams["filename"]
+	}
+	if filename == "" && !strings.HasPrefix(mediaType, "text/") {
+		filename = params["name"]
+	}
+	visit(mediaType, filename, data)
+	return nil
+}
+
+// newBase64Cleaner strips line breaks so base64 bodies can be decoded.
+func newBase64Cleaner(r io.Reader) io.Reader {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return bytes.NewReader(nil)
+	}
+	return strings.NewReader(strings.NewReplacer("\r", "", "\n", "", " ", "").Replace(string(data)))
+}
+
+var (
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</p>`)
+	htmlTagPattern   = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// stripHTML is a crude HTML-to-text fallback for HTML-only messages.
+func stripHTML(s string) string {
+	s = htmlBreakPattern.ReplaceAllString(s, "\n")
+	return htmlTagPattern.ReplaceAllString(s, "")
+}
+
+// saveEmailAttachments stores attachments for an issue within the
+// configured size limits, offloading those over the limits if a store is
+// configured. Attachments refused for their size are reported and skipped.
+func saveEmailAttachments(issueID string, attached []emailAttachment) ([]*attachments.Attachment, error) {
+	if len(attached) == 0 {
+		return nil, nil
+	}
+	beadsDir := beads.FindBeadsDir()
+	if beadsDir == "" {
+		return nil, fmt.Errorf("no .beads directory found")
+	}
+	opts, err := attachmentOptions()
+	if err != nil {
+		return nil, err
+	}
+	var saved []*attachments.Attachment
+	for i, a := range attached {
+		name := filepath.Base(a.Filename)
+		if name == "." || name == ".." || name == string(filepath.Separator) {
+			name = fmt.Sprintf("attachment-%d", i+1)
+		}
+		s, err := attachments.Save(rootCtx, beadsDir, issueID, name, bytes.NewReader(a.Data), opts)
+		if errors.Is(err, attachments.ErrTooLarge) {
+			WarnError("skipping attachment: %v", attachmentErrorHint(err))
+			continue
+		}
+		if err != nil {
+			return saved, err
+		}
+		saved = append(saved, s)
+	}
+	return saved, nil
+}
+
+func init() {
+	ingestEmailCmd.Flags().Bool("imap", false, "Ingest the unseen messages of the mailbox in email.imap.url instead of stdin")
+	ingestEmailCmd.Flags().StringSliceP("label", "l", nil, "Labels to add to the issue (repeatable)")
+	ingestEmailCmd.Flags().StringP("type", "t", "task", "Issue type")
+	ingestEmailCmd.Flags().IntP("priority", "p", 2, "Priority (0-4)")
+	ingestEmailCmd.Flags().StringP("assignee", "a", "", "Assignee")
+	ingestEmailCmd.Flags().String("reply-template", "", "Path to a Go text/template for the reply message")
+	ingestCmd.AddCommand(ingestEmailCmd)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestParseEmailMultipart(t *testing.T) {
+	raw := strings.Join([]string{
+		"From: Alice Example <alice@example.com>",
+		"To: support@example.com",
+		"Subject: =?UTF-8?Q?Login_fails_=E2=80=94_urgent?=",
+		"Message-ID: <abc123@mail.example.com>",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/mixed; boundary="XYZ"`,
+		"",
+		"--XYZ",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"I can't log in since =",
+		"this morning.",
+		"--XYZ",
+		"Content-Type: text/plain",
+		`Content-Disposition: attachment; filename="trace.log"`,
+		"Content-Transfer-Encoding: base64",
+		"",
+		"cGFuaWM6IG5p",
+		"bCBwb2ludGVy",
+		"--XYZ--",
+		"",
+	}, "\r\n")
+
+	email, err := parseEmail(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parseEmail: %v", err)
+	}
+	if email.From != "alice@example.com" || email.FromName != "Alice Example" {
+		t.Errorf("unexpected sender: %q <%q>", email.FromName, email.From)
+	}
+	if email.Subject != "Login fails — urgent" {
+		t.Errorf("Subject = %q", email.Subject)
+	}
+	if email.MessageID != "abc123@mail.example.com" {
+		t.Errorf("MessageID = %q", email.MessageID)
+	}
+	if email.Body != "I can't log in since this morning." {
+		t.Errorf("Body = %q", email.Body)
+	}
+	if len(email.Attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(email.Attachments))
+	}
+	if a := email.Attachments[0]; a.Filename != "trace.log" || string(a.Data) != "panic: nil pointer" {
+		t.Errorf("unexpected attachment: %s %q", a.Filename, a.Data)
+	}
+}
+
+func TestParseEmailHTMLOnly(t *testing.T) {
+	raw := "From: bob@example.com\r\nSubject: Hi\r\nContent-Type: text/html\r\n\r\n<p>Hello<br>world</p>\r\n"
+	email, err := parseEmail(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parseEmail: %v", err)
+	}
+	if email.Body != "Hello\nworld" {
+		t.Errorf("Body = %q", email.Body)
+	}
+}
+
+func TestParseEmailMissingFrom(t *testing.T) {
+	if _, err := parseEmail(strings.NewReader("Subject: x\r\n\r\nbody\r\n")); err == nil {
+		t.Error("expected error for message without From header")
+	}
+}
+
+// serveIMAP answers one IMAP session on l with the given messages (by UID),
+// sending the UIDs flagged seen to seen.
+func serveIMAP(l net.Listener, messages map[int]string, seen chan<- int) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	defer close(seen)
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "* OK ready\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, cmd, _ := strings.Cut(strings.TrimSpace(line), " ")
+		var uid int
+		switch {
+		case cmd == "UID SEARCH UNSEEN":
+			fmt.Fprint(conn, "* SEARCH 1 2\r\n")
+		case strings.HasPrefix(cmd, "UID FETCH"):
+			_, _ = fmt.Sscanf(cmd, "UID FETCH %d", &uid)
+			fmt.Fprintf(conn, "* %d FETCH (UID %d BODY[] {%d}\r\n%s)\r\n", uid, uid, len(messages[uid]), messages[uid])
+		case strings.HasPrefix(cmd, "UID STORE"):
+			_, _ = fmt.Sscanf(cmd, "UID STORE %d", &uid)
+			seen <- uid
+		case cmd == "LOGOUT":
+			fmt.Fprintf(conn, "* BYE\r\n%s OK bye\r\n", tag)
+			return
+		}
+		fmt.Fprintf(conn, "%s OK done\r\n", tag)
+	}
+}
+
+func TestIngestIMAP(t *testing.T) {
+	testStore, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	origStore := store
+	store = testStore
+	t.Cleanup(func() { store = origStore })
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	messages := map[int]string{
+		1: "From: alice@example.com\r\nSubject: Printer on fire\r\nMessage-ID: <m1@example.com>\r\n\r\nHelp!\r\n",
+		2: "Subject: No sender\r\n\r\nUnparseable\r\n",
+	}
+	seen := make(chan int, len(messages))
+	go serveIMAP(l, messages, seen)
+
+	for key, value := range map[string]string{
+		"email.imap.url":      "imap://" + l.Addr().String() + "/Support",
+		"email.imap.username": "support",
+	} {
+		if err := testStore.SetConfig(ctx, key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("BD_IMAP_PASSWORD", "secret")
+
+	opts := &emailIngestOptions{
+		labels:    []string{"support"},
+		issueType: "task",
+		priority:  2,
+		reply:     template.Must(template.New("reply").Parse(defaultEmailReplyTemplate)),
+	}
+	results, err := ingestIMAP(ctx, opts)
+	if err != nil {
+		t.Fatalf("ingestIMAP failed: %v", err)
+	}
+	if len(results) != 1 || !results[0].Created || results[0].Issue.Title != "Printer on fire" {
+		t.Fatalf("results = %+v, want one issue from message 1", results)
+	}
+	var flagged []int
+	for uid := range seen {
+		flagged = append(flagged, uid)
+	}
+	if len(flagged) != 1 || flagged[0] != 1 {
+		t.Errorf("flagged seen %v, want only the ingested message", flagged)
+	}
+
+	// A message already filed maps to the same issue
+	email, err := parseEmail(strings.NewReader(messages[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := ingestEmail(ctx, email, opts)
+	if err != nil || again.Created || again.Issue.ID != results[0].Issue.ID {
+		t.Errorf("re-ingest = %+v, %v, want the existing issue", again, err)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/factory"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/subset"
 	"github.com/steveyegge/beads/internal/syncbranch"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/ui"
@@ -33,10 +34,26 @@ and database file. Optionally specify a custom issue prefix.
 
 With --no-db: creates .beads/ directory and issues.jsonl file instead of SQLite database.
 
+With --interactive (-i): walks through the setup step by step: the issue prefix
+(suggested from the repository name), the routing mode, git hooks, description
+templates and optional starter issues. Press Enter to accept each default.
+
+With --from <file>: bootstraps the project from an existing JSONL export (e.g.
+'bd export' from another clone or tool). The file becomes .beads/issues.jsonl,
+its issues are imported, and the prefix is taken from its first issue unless
+--prefix is given.
+
 With --from-jsonl: imports from the current .beads/issues.jsonl file on disk instead
 of scanning git history. Use this after manual JSONL cleanup (e.g., bd compact --purge-tombstones)
 to prevent deleted issues from being resurrected during re-initialization.
 
+With --subset: materializes only the issues matching a filter (e.g. "label:frontend")
+in this clone's database. Other issues are kept as stubs (ID, title, status, labels,
+dependencies, without long text or comments) so their IDs still resolve. Exports write
+stubs back in full from the JSONL, so the shared file keeps the whole project. Filter
+terms are key:value (keys: label, type, status, assignee, priority, prefix); terms
+are ANDed and comma-separated values are ORed.
+
 With --stealth: configures per-repository git settings for invisible beads usage:
   • .git/info/exclude to prevent beads files from being committed
   • Claude Code settings with bd onboard instruction
@@ -53,6 +70,34 @@ With --stealth: configures per-repository git settings for invisible beads usage
 		skipHooks, _ := cmd.Flags().GetBool("skip-hooks")
 		force, _ := cmd.Flags().GetBool("force")
 		fromJSONL, _ := cmd.Flags().GetBool("from-jsonl")
+		subsetExpr, _ := cmd.Flags().GetString("subset")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		fromPath, _ := cmd.Flags().GetString("from")
+
+		if interactive && quiet {
+			fmt.Fprintf(os.Stderr, "Error: --interactive and --quiet can't be used together\n")
+			os.Exit(1)
+		}
+		if fromPath != "" {
+			if fromJSONL {
+				fmt.Fprintf(os.Stderr, "Error: --from and --from-jsonl can't be used together\n")
+				os.Exit(1)
+			}
+			if _, err := os.Stat(fromPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --from: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		var subsetFilter *subset.Filter
+		if subsetExpr != "" {
+			f, err := subset.Parse(subsetExpr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			subsetFilter = f
+		}
 
 		// Validate backend flag
 		if backend != "" && backend != configfile.BackendSQLite && backend != configfile.BackendDolt {
@@ -62,6 +107,10 @@ With --stealth: configures per-repository git settings for invisible beads usage
 		if backend == "" {
 			backend = configfile.BackendSQLite // Default to SQLite
 		}
+		if subsetFilter != nil && backend != configfile.BackendSQLite {
+			fmt.Fprintf(os.Stderr, "Error: --subset requires the sqlite backend\n")
+			os.Exit(1)
+		}
 
 		// Initialize config (PersistentPreRun doesn't run for init command)
 		if err := config.Initialize(); err != nil {
@@ -105,6 +154,13 @@ With --stealth: configures per-repository git settings for invisible beads usage
 			prefix = config.GetString("issue-prefix")
 		}
 
+		// auto-detect prefix from the export given with --from
+		if prefix == "" && fromPath != "" {
+			if firstIssue, err := readFirstIssueFromJSONL(fromPath); firstIssue != nil && err == nil {
+				prefix = utils.ExtractIssuePrefix(firstIssue.ID)
+			}
+		}
+
 		// auto-detect prefix from first issue in JSONL file
 		if prefix == "" {
 			issueCount, jsonlPath, gitRef := checkGitForIssues()
@@ -117,6 +173,7 @@ With --stealth: configures per-repository git settings for invisible beads usage
 		}
 
 		// auto-detect prefix from directory name
+		prefixGuessed := prefix == ""
 		if prefix == "" {
 			// Auto-detect from directory name
 			cwd, err := os.Getwd()
@@ -131,6 +188,33 @@ With --stealth: configures per-repository git settings for invisible beads usage
 		// The hyphen is added automatically during ID generation
 		prefix = strings.TrimRight(prefix, "-")
 
+		// Guided setup: confirm the prefix and pick the rest of the setup
+		var choices initChoices
+		if interactive {
+			defaults := initChoices{
+				Prefix:       prefix,
+				RoutingMode:  "auto",
+				InstallHooks: true,
+				Validation:   config.GetString("validation.on-create"),
+			}
+			if prefixGuessed {
+				defaults.Prefix = suggestPrefix()
+			}
+			if contributor {
+				defaults.RoutingMode = "contributor"
+			}
+			if defaults.Validation == "" {
+				defaults.Validation = "none"
+			}
+			askHooks := !skipHooks && !stealth && isGitRepo() && !hooksInstalled()
+			choices = runInitWizard(os.Stdin, os.Stdout, defaults, askHooks)
+			prefix = choices.Prefix
+			if askHooks && !choices.InstallHooks {
+				skipHooks = true
+			}
+			contributor = choices.RoutingMode == "contributor"
+		}
+
 		// Create database
 		// Use global dbPath if set via --db flag or BEADS_DB env var, otherwise default to .beads/beads.db
 		initDBPath := dbPath
@@ -215,6 +299,14 @@ With --stealth: configures per-repository git settings for invisible beads usage
 				os.Exit(1)
 			}
 
+			// --from: the export becomes the project's issues file
+			if fromPath != "" {
+				if err := seedJSONL(fromPath, filepath.Join(beadsDir, "issues.jsonl"), force); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
 			// Handle --no-db mode: create issues.jsonl file instead of database
 			if noDb {
 				// Create empty issues.jsonl file
@@ -255,6 +347,9 @@ With --stealth: configures per-repository git settings for invisible beads usage
 					fmt.Fprintf(os.Stderr, "Warning: failed to create README.md: %v\n", err)
 					// Non-fatal - continue anyway
 				}
+				if interactive {
+					applyInitChoices(choices)
+				}
 
 				if !quiet {
 					fmt.Printf("\n%s bd initialized successfully in --no-db mode!\n\n", ui.RenderPass("✓"))
@@ -321,6 +416,18 @@ With --stealth: configures per-repository git settings for invisible beads usage
 			os.Exit(1)
 		}
 
+		// Store the subset before importing so the import stubs non-matching issues
+		if subsetFilter != nil {
+			if err := store.SetConfig(ctx, subset.ConfigKey, subsetFilter.String()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to set subset: %v\n", err)
+				_ = store.Close()
+				os.Exit(1)
+			}
+			if !quiet {
+				fmt.Printf("  Subset: %s\n", subsetFilter)
+			}
+		}
+
 		// === TRACKING METADATA (Pattern B: Warn and Continue) ===
 		// Tracking metadata enhances functionality (diagnostics, version checks, collision detection)
 		// but the system works without it. Failures here degrade gracefully - we warn but continue.
@@ -429,9 +536,28 @@ With --stealth: configures per-repository git settings for invisible beads usage
 			}
 		}
 
+		if interactive {
+			applyInitChoices(choices)
+		}
+
 		// Check if git has existing issues to import (fresh clone scenario)
+		// With --from: import the given export (seeded into .beads/ above)
 		// With --from-jsonl: import from local file instead of git history
-		if fromJSONL {
+		if fromPath != "" {
+			importPath := fromPath
+			if useLocalBeads {
+				importPath = filepath.Join(beadsDir, "issues.jsonl")
+			}
+			issueCount, err := importFromLocalJSONL(ctx, initDBPath, store, importPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: import from %s failed: %v\n", fromPath, err)
+				_ = store.Close()
+				os.Exit(1)
+			}
+			if !quiet {
+				fmt.Fprintf(os.Stderr, "✓ Imported %d issues from %s\n\n", issueCount, fromPath)
+			}
+		} else if fromJSONL {
 			// Import from current working tree's JSONL file
 			localJSONLPath := filepath.Join(beadsDir, "issues.jsonl")
 			if _, err := os.Stat(localJSONLPath); err == nil {
@@ -485,6 +611,15 @@ With --stealth: configures per-repository git settings for invisible beads usage
 			}
 		}
 
+		if choices.StarterIssues {
+			ids, err := createStarterIssues(ctx, store, getActorWithGit())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to create starter issues: %v\n", err)
+			} else if !quiet {
+				fmt.Printf("  Starter issues: %s\n", strings.Join(ids, ", "))
+			}
+		}
+
 		if err := store.Close(); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to close database: %v\n", err)
 		}
@@ -595,6 +730,9 @@ func init() {
 	initCmd.Flags().Bool("skip-hooks", false, "Skip git hooks installation")
 	initCmd.Flags().Bool("skip-merge-driver", false, "Skip git merge driver setup")
 	initCmd.Flags().Bool("force", false, "Force re-initialization even if JSONL already has issues (may cause data loss)")
+	initCmd.Flags().String("subset", "", "Only materialize issues matching this filter (e.g. \"label:frontend\"); others become stubs")
+	initCmd.Flags().BoolP("interactive", "i", false, "Guided setup: prefix, routing mode, git hooks, templates and starter issues")
+	initCmd.Flags().String("from", "", "Bootstrap from an existing JSONL export (copied to .beads/issues.jsonl and imported)")
 	initCmd.Flags().Bool("from-jsonl", false, "Import from current .beads/issues.jsonl file instead of git history (preserves manual cleanups)")
 	rootCmd.AddCommand(initCmd)
 }
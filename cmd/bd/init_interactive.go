@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// initChoices holds the answers given in the guided setup (bd init -i).
+type initChoices struct {
+	Prefix        string
+	RoutingMode   string // auto, maintainer or contributor
+	InstallHooks  bool
+	Validation    string // validation.on-create: none, warn or error
+	StarterIssues bool
+}
+
+// runInitWizard asks for each choice in turn, offering the values in
+// defaults. An empty answer (or end of input) keeps the default, so
+// answers can also be piped in. askHooks is false when hooks are already
+// installed or were ruled out by a flag.
+func runInitWizard(in io.Reader, out io.Writer, defaults initChoices, askHooks bool) initChoices {
+	reader := bufio.NewReader(in)
+	ask := func(prompt, def string) string {
+		fmt.Fprintf(out, "%s [%s]: ", prompt, def)
+		answer, _ := reader.ReadString('\n')
+		if answer = strings.TrimSpace(answer); answer == "" {
+			return def
+		}
+		return answer
+	}
+	choose := func(prompt, def string, options ...string) string {
+		for {
+			answer := strings.ToLower(ask(prompt+" ("+strings.Join(options, "/")+")", def))
+			for _, o := range options {
+				if answer == o {
+					return o
+				}
+			}
+			fmt.Fprintf(out, "  Please answer one of: %s\n", strings.Join(options, ", "))
+		}
+	}
+	yesNo := func(prompt string, def bool) bool {
+		d := "Y/n"
+		if !def {
+			d = "y/N"
+		}
+		switch strings.ToLower(ask(prompt, d)) {
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		default:
+			return def
+		}
+	}
+
+	if defaults.Prefix = sanitizePrefix(defaults.Prefix); defaults.Prefix == "" {
+		defaults.Prefix = "bd"
+	}
+	choices := defaults
+	fmt.Fprintf(out, "\n%s %s\n\n", ui.RenderBold("bd"), ui.RenderBold("Project Setup"))
+
+	for {
+		choices.Prefix = strings.TrimRight(ask("Issue prefix (issues are named <prefix>-<hash>)", defaults.Prefix), "-")
+		if sanitizePrefix(choices.Prefix) == choices.Prefix {
+			break
+		}
+		fmt.Fprintf(out, "  Use lowercase letters, digits and dashes, e.g. %s\n", sanitizePrefix(choices.Prefix))
+	}
+
+	fmt.Fprintln(out, "\nWhere should new issues go?")
+	fmt.Fprintln(out, "  auto         maintainers file here, contributors in a separate planning repo")
+	fmt.Fprintln(out, "  maintainer   always file issues in this repo")
+	fmt.Fprintln(out, "  contributor  set up a planning repo now (runs the contributor wizard)")
+	choices.RoutingMode = choose("Routing mode", defaults.RoutingMode, "auto", "maintainer", "contributor")
+
+	if askHooks {
+		fmt.Fprintln(out, "\nGit hooks keep .beads/issues.jsonl in sync with the database on commit, merge and checkout.")
+		choices.InstallHooks = yesNo("Install git hooks?", defaults.InstallHooks)
+	}
+
+	fmt.Fprintln(out, "\nDescription templates require sections in new issues, e.g. \"## Steps to Reproduce\"")
+	fmt.Fprintln(out, "for bugs and \"## Acceptance Criteria\" for tasks and features.")
+	choices.Validation = choose("Check templates on create", defaults.Validation, "none", "warn", "error")
+
+	fmt.Fprintln(out)
+	choices.StarterIssues = yesNo("Create starter issues to get going?", defaults.StarterIssues)
+	fmt.Fprintln(out)
+	return choices
+}
+
+// applyInitChoices writes the routing mode and template checks chosen in
+// the guided setup to .beads/config.yaml. Defaults are left commented out.
+func applyInitChoices(choices initChoices) {
+	settings := [][2]string{}
+	if choices.RoutingMode == "maintainer" {
+		settings = append(settings, [2]string{"routing.mode", "maintainer"})
+	}
+	if choices.Validation != "" && choices.Validation != "none" {
+		settings = append(settings, [2]string{"validation.on-create", choices.Validation})
+	}
+	for _, kv := range settings {
+		if err := config.SetYamlConfig(kv[0], kv[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to set %s: %v\n", kv[0], err)
+		}
+	}
+}
+
+// suggestPrefix suggests an issue prefix from the repository name: the
+// origin remote's repo name, else the git root's directory name, else the
+// current directory's.
+func suggestPrefix() string {
+	var name string
+	if isGitRepo() {
+		if out, err := exec.Command("git", "remote", "get-url", "origin").Output(); err == nil {
+			// git@host:org/repo.git, https://host/org/repo.git, /path/to/repo
+			url := strings.TrimSuffix(strings.TrimSpace(string(out)), ".git")
+			url = url[strings.LastIndex(url, ":")+1:]
+			name = path.Base(filepath.ToSlash(url))
+		}
+		if name == "" || name == "." || name == "/" {
+			if out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+				name = filepath.Base(strings.TrimSpace(string(out)))
+			}
+		}
+	}
+	if name == "" {
+		if cwd, err := os.Getwd(); err == nil {
+			name = filepath.Base(cwd)
+		}
+	}
+	if prefix := sanitizePrefix(name); prefix != "" {
+		return prefix
+	}
+	return "bd"
+}
+
+// sanitizePrefix turns a name into a valid issue prefix: lowercase letters,
+// digits and single dashes, e.g. "My_Project.js" becomes "my-project-js".
+func sanitizePrefix(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
+
+// starterIssues are created by bd init when asked to in the guided setup.
+var starterIssues = []struct {
+	title       string
+	description string
+}{
+	{
+		title: "Commit the .beads directory",
+		description: "Add .beads/ to git so everyone working on the repo shares the same issues.\n\n" +
+			"## Acceptance Criteria\n.beads/issues.jsonl, .beads/config.yaml and .beads/.gitignore are committed.",
+	},
+	{
+		title: "Teach your coding agent to use bd",
+		description: "Run `bd onboard` (or `bd setup <agent>`) so agents find work with `bd ready` and file what they discover.\n\n" +
+			"## Acceptance Criteria\nThe agent instructions in the repo mention bd.",
+	},
+	{
+		title: "File the first real issues",
+		description: "Capture the work in flight with `bd create`, link blockers with `bd dep add`, and check `bd ready`.\n\n" +
+			"## Acceptance Criteria\n`bd ready` lists the next thing to work on.",
+	},
+}
+
+// createStarterIssues creates the starter issues and returns their IDs.
+func createStarterIssues(ctx context.Context, s storage.Storage, actor string) ([]string, error) {
+	var ids []string
+	for _, starter := range starterIssues {
+		issue := &types.Issue{
+			Title:       starter.title,
+			Description: starter.description,
+			Status:      types.StatusOpen,
+			Priority:    2,
+			IssueType:   types.TypeTask,
+		}
+		if err := s.CreateIssue(ctx, issue, actor); err != nil {
+			return ids, fmt.Errorf("creating %q: %w", starter.title, err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	return ids, nil
+}
+
+// seedJSONL copies an existing JSONL export to dest, the project's issues
+// file, for bd init --from. An existing non-empty dest is only replaced
+// with force.
+func seedJSONL(src, dest string, force bool) error {
+	srcAbs, _ := filepath.Abs(src)
+	destAbs, _ := filepath.Abs(dest)
+	if srcAbs == destAbs {
+		return nil
+	}
+	// #nosec G304 -- path given to bd init --from
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", src, err)
+	}
+	if info, err := os.Stat(dest); err == nil && info.Size() > 0 && !force {
+		return fmt.Errorf("%s already has issues; use --force to replace it with %s", dest, src)
+	}
+	// nolint:gosec // G306: JSONL file needs to be readable by other tools
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", dest, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitizePrefix(t *testing.T) {
+	for name, want := range map[string]string{
+		"beads":         "beads",
+		"My_Project.js": "my-project-js",
+		"--api--v2--":   "api-v2",
+		"日本":            "",
+	} {
+		if got := sanitizePrefix(name); got != want {
+			t.Errorf("sanitizePrefix(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRunInitWizard(t *testing.T) {
+	defaults := initChoices{Prefix: "My Repo", RoutingMode: "auto", InstallHooks: true, Validation: "none"}
+
+	// End of input keeps every default
+	got := runInitWizard(strings.NewReader(""), io.Discard, defaults, true)
+	want := initChoices{Prefix: "my-repo", RoutingMode: "auto", InstallHooks: true, Validation: "none"}
+	if got != want {
+		t.Errorf("defaults: got %+v, want %+v", got, want)
+	}
+
+	// Invalid answers are asked again
+	answers := "Bad Prefix\nacme\nadmin\nmaintainer\nn\nerror\ny\n"
+	got = runInitWizard(strings.NewReader(answers), io.Discard, defaults, true)
+	want = initChoices{Prefix: "acme", RoutingMode: "maintainer", InstallHooks: false, Validation: "error", StarterIssues: true}
+	if got != want {
+		t.Errorf("answers: got %+v, want %+v", got, want)
+	}
+
+	// The hooks question is skipped when hooks can't be installed
+	got = runInitWizard(strings.NewReader("acme\n\nwarn\n"), io.Discard, defaults, false)
+	if got.Validation != "warn" {
+		t.Errorf("without the hooks question: got %+v", got)
+	}
+}
+
+func TestInitFromExport(t *testing.T) {
+	origDBPath := dbPath
+	defer func() { dbPath = origDBPath }()
+	dbPath = ""
+	defer func() {
+		for _, flag := range []string{"from", "quiet", "skip-hooks", "skip-merge-driver"} {
+			_ = initCmd.Flags().Set(flag, initCmd.Flags().Lookup(flag).DefValue)
+		}
+	}()
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	export := filepath.Join(t.TempDir(), "export.jsonl")
+	content := `{"id":"web-a1","title":"First","status":"open","priority":1,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-01T00:00:00Z"}
+{"id":"web-b2","title":"Second","status":"closed","priority":2,"issue_type":"bug","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-02T00:00:00Z","closed_at":"2026-01-02T00:00:00Z"}
+`
+	if err := os.WriteFile(export, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd.SetArgs([]string{"init", "--from", export, "--quiet", "--skip-hooks", "--skip-merge-driver"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init --from failed: %v", err)
+	}
+
+	seeded, err := os.ReadFile(filepath.Join(tmpDir, ".beads", "issues.jsonl"))
+	if err != nil || string(seeded) != content {
+		t.Errorf("issues.jsonl not seeded from the export: %v", err)
+	}
+
+	store, err := openExistingTestDB(t, filepath.Join(tmpDir, ".beads", "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if prefix, _ := store.GetConfig(ctx, "issue_prefix"); prefix != "web" {
+		t.Errorf("issue_prefix = %q, want it taken from the export", prefix)
+	}
+	for _, id := range []string{"web-a1", "web-b2"} {
+		if issue, err := store.GetIssue(ctx, id); err != nil || issue == nil {
+			t.Errorf("%s not imported: %v", id, err)
+		}
+	}
+}
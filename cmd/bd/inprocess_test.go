@@ -0,0 +1,198 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/beads/beadstest"
+	"github.com/steveyegge/beads/internal/git"
+)
+
+// inProcessMutex serializes in-process command execution: rootCmd, cobra
+// flag state, viper, and the package globals are not safe for concurrent use.
+var inProcessMutex sync.Mutex
+
+// inProcessExit is the panic value exitFunc raises during an in-process run.
+type inProcessExit struct {
+	code int
+}
+
+// newTestProject returns a beadstest.Project whose commands run in-process.
+func newTestProject(t *testing.T, opts ...beadstest.Option) *beadstest.Project {
+	t.Helper()
+	return beadstest.New(t, runInProcess, opts...)
+}
+
+// runInProcess is a beadstest.Runner that executes rootCmd directly instead
+// of spawning a bd binary. Commands that exit through FatalError and friends
+// return a *beadstest.ExitError; commands that call os.Exit directly still
+// terminate the test binary, so prefer the error helpers in new code.
+func runInProcess(dir string, args []string, stdout, stderr io.Writer) (err error) {
+	inProcessMutex.Lock()
+	defer inProcessMutex.Unlock()
+
+	// Add --no-daemon to all commands except init
+	if len(args) > 0 && args[0] != "init" {
+		args = append([]string{"--no-daemon"}, args...)
+	}
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	oldDir, _ := os.Getwd()
+	oldArgs := os.Args
+	oldExit := exitFunc
+
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+	git.ResetCaches()
+	// Unit tests in this package poke globals directly; start from defaults.
+	resetCommandState()
+
+	beadsDir := filepath.Join(dir, ".beads")
+	restoreEnv := setTestEnv(map[string]string{
+		"BEADS_NO_DAEMON": "1",
+		"BEADS_TEST_MODE": "1",
+		"BEADS_DIR":       beadsDir,
+		"BEADS_DB":        filepath.Join(beadsDir, "beads.db"),
+		"BD_ACTOR":        "test-user",
+	})
+
+	// Drain output concurrently so large outputs can't fill the pipe.
+	rOut, wOut, _ := os.Pipe()
+	rErr, wErr, _ := os.Pipe()
+	var copying sync.WaitGroup
+	copying.Add(2)
+	go func() { _, _ = io.Copy(stdout, rOut); copying.Done() }()
+	go func() { _, _ = io.Copy(stderr, rErr); copying.Done() }()
+	os.Stdout, os.Stderr = wOut, wErr
+
+	exitFunc = func(code int) { panic(inProcessExit{code}) }
+	rootCmd.SetArgs(args)
+	os.Args = append([]string{"bd"}, args...)
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				exit, ok := r.(inProcessExit)
+				if !ok {
+					panic(r)
+				}
+				if exit.code != 0 {
+					err = &beadstest.ExitError{Code: exit.code}
+				}
+			}
+		}()
+		if execErr := rootCmd.Execute(); execErr != nil {
+			// Same mapping as main()
+			err = &beadstest.ExitError{Code: exitCodeForExecuteError(execErr)}
+		}
+	}()
+
+	resetCommandState()
+
+	// Give SQLite time to release file locks before cleanup
+	time.Sleep(10 * time.Millisecond)
+
+	_ = wOut.Close()
+	_ = wErr.Close()
+	copying.Wait()
+	_ = rOut.Close()
+	_ = rErr.Close()
+
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	exitFunc = oldExit
+	restoreEnv()
+	_ = os.Chdir(oldDir)
+	git.ResetCaches()
+	os.Args = oldArgs
+	rootCmd.SetArgs(nil)
+	return err
+}
+
+// resetCommandState closes the store and restores the globals and flags a
+// command may have changed, so the next in-process run starts clean.
+func resetCommandState() {
+	if store != nil {
+		_ = store.Close()
+		store = nil
+	}
+	if daemonClient != nil {
+		_ = daemonClient.Close()
+		daemonClient = nil
+	}
+	if flushManager != nil {
+		_ = flushManager.Shutdown()
+		flushManager = nil
+	}
+
+	dbPath = ""
+	actor = ""
+	jsonOutput = false
+	noDaemon = false
+	noAutoFlush = false
+	noAutoImport = false
+	sandboxMode = false
+	allowStale = false
+	noDb = false
+	readonlyMode = false
+	autoFlushEnabled = true
+	storeActive = false
+	flushFailureCount = 0
+	lastFlushError = nil
+	skipFinalFlush = false
+	rootCtx = nil
+	rootCancel = nil
+
+	resetFlags(rootCmd)
+}
+
+// resetFlags restores every flag in the command tree to its default, since
+// cobra keeps parsed values between Execute calls.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// setTestEnv sets environment variables and returns a function restoring
+// their previous values.
+func setTestEnv(vars map[string]string) func() {
+	type saved struct {
+		value string
+		set   bool
+	}
+	old := make(map[string]saved, len(vars))
+	for k, v := range vars {
+		value, set := os.LookupEnv(k)
+		old[k] = saved{value, set}
+		_ = os.Setenv(k, v)
+	}
+	return func() {
+		for k, s := range old {
+			if s.set {
+				_ = os.Setenv(k, s.value)
+			} else {
+				_ = os.Unsetenv(k)
+			}
+		}
+	}
+}
@@ -151,7 +151,7 @@ func TestContentBasedComparison(t *testing.T) {
 	}
 
 	// Remove the second issue from database
-	if err := localStore.DeleteIssue(ctx, "test-2"); err != nil {
+	if err := localStore.DeleteIssue(ctx, "test-2", "test-user"); err != nil {
 		t.Fatalf("Failed to delete issue: %v", err)
 	}
 
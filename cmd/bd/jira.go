@@ -18,6 +18,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/httpx"
 	"github.com/steveyegge/beads/internal/types"
 )
 
@@ -50,7 +51,7 @@ var jiraCmd = &cobra.Command{
 Configuration:
   bd config set jira.url "https://company.atlassian.net"
   bd config set jira.project "PROJ"
-  bd config set jira.api_token "YOUR_TOKEN"
+  bd auth login jira                                     # API token, kept out of the config
   bd config set jira.username "your_email@company.com"  # For Jira Cloud
 
 Environment variables (alternative to config):
@@ -80,11 +81,17 @@ Conflict Resolution:
   --prefer-local   Always prefer local beads version
   --prefer-jira    Always prefer Jira version
 
+Progress is checkpointed as the sync runs. If it is interrupted (Ctrl-C,
+--timeout, a network error), the next sync resumes: a finished pull is not
+repeated and issues already created in Jira are not sent again. --restart
+discards the checkpoint.
+
 Examples:
   bd jira sync --pull                # Import from Jira
   bd jira sync --push --create-only  # Push new issues only
   bd jira sync --dry-run             # Preview without changes
-  bd jira sync --prefer-local        # Bidirectional, local wins`,
+  bd jira sync --prefer-local        # Bidirectional, local wins
+  bd jira sync --restart             # Ignore an interrupted sync's checkpoint`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Flag errors are unlikely but check one to ensure cobra is working
 		pull, _ := cmd.Flags().GetBool("pull")
@@ -95,6 +102,7 @@ Examples:
 		createOnly, _ := cmd.Flags().GetBool("create-only")
 		updateRefs, _ := cmd.Flags().GetBool("update-refs")
 		state, _ := cmd.Flags().GetString("state")
+		restart, _ := cmd.Flags().GetBool("restart")
 
 		// Block writes in readonly mode (sync modifies data)
 		if !dryRun {
@@ -127,9 +135,16 @@ Examples:
 
 		ctx := rootCtx
 		result := &JiraSyncResult{Success: true}
+		checkpoint, err := beginSyncCheckpoint(ctx, store, "jira", restart, dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 
 		// Step 1: Pull from Jira
-		if pull {
+		if pull && checkpoint.Pulled {
+			fmt.Println("✓ Pull already finished before the interruption")
+		} else if pull {
 			if dryRun {
 				fmt.Println("→ [DRY RUN] Would pull issues from Jira")
 			} else {
@@ -154,6 +169,9 @@ Examples:
 			result.Stats.Skipped += pullStats.Skipped
 
 			if !dryRun {
+				if err := checkpoint.markPulled(ctx, store, nil); err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("failed to save checkpoint: %v", err))
+				}
 				fmt.Printf("✓ Pulled %d issues (%d created, %d updated)\n",
 					result.Stats.Pulled, pullStats.Created, pullStats.Updated)
 			}
@@ -193,7 +211,7 @@ Examples:
 				fmt.Println("→ Pushing issues to Jira...")
 			}
 
-			pushStats, err := doPushToJira(ctx, dryRun, createOnly, updateRefs)
+			pushStats, err := doPushToJira(ctx, dryRun, createOnly, updateRefs, checkpoint)
 			if err != nil {
 				result.Success = false
 				result.Error = err.Error()
@@ -217,12 +235,16 @@ Examples:
 			}
 		}
 
-		// Update last sync timestamp
+		// Update last sync timestamp to when this sync (or the interrupted
+		// one it resumed) started, so changes made during it are picked up
 		if !dryRun && result.Success {
-			result.LastSync = time.Now().Format(time.RFC3339)
+			result.LastSync = checkpoint.Started.Format(time.RFC3339)
 			if err := store.SetConfig(ctx, "jira.last_sync", result.LastSync); err != nil {
 				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to update last_sync: %v", err))
 			}
+			if err := checkpoint.clear(ctx, store); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to clear checkpoint: %v", err))
+			}
 		}
 
 		// Output result
@@ -263,6 +285,7 @@ var jiraStatusCmd = &cobra.Command{
 		jiraURL, _ := store.GetConfig(ctx, "jira.url")
 		jiraProject, _ := store.GetConfig(ctx, "jira.project")
 		lastSync, _ := store.GetConfig(ctx, "jira.last_sync")
+		checkpoint, interrupted, _ := loadSyncCheckpoint(ctx, store, "jira")
 
 		// Check if configured
 		configured := jiraURL != "" && jiraProject != ""
@@ -295,6 +318,7 @@ var jiraStatusCmd = &cobra.Command{
 				"total_issues":  len(allIssues),
 				"with_jira_ref": withJiraRef,
 				"pending_push":  pendingPush,
+				"interrupted":   interrupted,
 			})
 			return
 		}
@@ -309,7 +333,7 @@ var jiraStatusCmd = &cobra.Command{
 			fmt.Println("To configure Jira integration:")
 			fmt.Println("  bd config set jira.url \"https://company.atlassian.net\"")
 			fmt.Println("  bd config set jira.project \"PROJ\"")
-			fmt.Println("  bd config set jira.api_token \"YOUR_TOKEN\"")
+			fmt.Println("  bd auth login jira")
 			fmt.Println("  bd config set jira.username \"your@email.com\"")
 			return
 		}
@@ -321,6 +345,10 @@ var jiraStatusCmd = &cobra.Command{
 		} else {
 			fmt.Println("Last Sync:    Never")
 		}
+		if interrupted {
+			fmt.Printf("Interrupted:  sync started %s, %d issue(s) pushed (next sync resumes)\n",
+				checkpoint.Started.Local().Format("2006-01-02 15:04"), len(checkpoint.Pushed))
+		}
 		fmt.Println()
 		fmt.Printf("Total Issues: %d\n", len(allIssues))
 		fmt.Printf("With Jira:    %d\n", withJiraRef)
@@ -343,6 +371,7 @@ func init() {
 	jiraSyncCmd.Flags().Bool("create-only", false, "Only create new issues, don't update existing")
 	jiraSyncCmd.Flags().Bool("update-refs", true, "Update external_ref after creating Jira issues")
 	jiraSyncCmd.Flags().String("state", "all", "Issue state to sync: open, closed, all")
+	jiraSyncCmd.Flags().Bool("restart", false, "Discard the checkpoint of an interrupted sync and start over")
 
 	jiraCmd.AddCommand(jiraSyncCmd)
 	jiraCmd.AddCommand(jiraStatusCmd)
@@ -366,10 +395,9 @@ func validateJiraConfig() error {
 		return fmt.Errorf("jira.project not configured\nRun: bd config set jira.project \"PROJ\"")
 	}
 
-	// Check for API token (from config or env)
-	apiToken, _ := store.GetConfig(ctx, "jira.api_token")
-	if apiToken == "" && os.Getenv("JIRA_API_TOKEN") == "" {
-		return fmt.Errorf("Jira API token not configured\nRun: bd config set jira.api_token \"YOUR_TOKEN\"\nOr: export JIRA_API_TOKEN=YOUR_TOKEN")
+	// Check for API token (from config, env or the credentials store)
+	if apiToken, _ := lookupSecret(ctx, "jira.api_token"); apiToken == "" {
+		return fmt.Errorf("Jira API token not configured\nRun: bd auth login jira\nOr: export JIRA_API_TOKEN=YOUR_TOKEN")
 	}
 
 	return nil
@@ -382,6 +410,17 @@ type PullStats struct {
 	Skipped int
 }
 
+// jiraScriptEnv returns the environment for jira2jsonl.py. The script reads
+// the token from the project config or JIRA_API_TOKEN, so a token from the
+// credentials store is passed in that variable.
+func jiraScriptEnv(ctx context.Context) []string {
+	env := os.Environ()
+	if token, source := lookupSecret(ctx, "jira.api_token"); strings.HasPrefix(source, "credentials store") {
+		env = append(env, "JIRA_API_TOKEN="+token)
+	}
+	return env
+}
+
 // doPullFromJira imports issues from Jira using the Python script.
 func doPullFromJira(ctx context.Context, dryRun bool, state string) (*PullStats, error) {
 	stats := &PullStats{}
@@ -401,6 +440,7 @@ func doPullFromJira(ctx context.Context, dryRun bool, state string) (*PullStats,
 	// Run Python script to get JSONL output
 	cmd := exec.CommandContext(ctx, "python3", args...)
 	cmd.Stderr = os.Stderr
+	cmd.Env = jiraScriptEnv(ctx)
 
 	output, err := cmd.Output()
 	if err != nil {
@@ -445,6 +485,8 @@ func doPullFromJira(ctx context.Context, dryRun bool, state string) (*PullStats,
 	opts := ImportOptions{
 		DryRun:     false,
 		SkipUpdate: false,
+		LockActor:  getActor(),
+		CheckRules: true,
 	}
 
 	result, err := importIssuesCore(ctx, dbPath, store, issues, opts)
@@ -467,8 +509,10 @@ type PushStats struct {
 	Errors  int
 }
 
-// doPushToJira exports issues to Jira using the Python script.
-func doPushToJira(ctx context.Context, dryRun bool, createOnly bool, updateRefs bool) (*PushStats, error) {
+// doPushToJira exports issues to Jira using the Python script. The script
+// reports each issue it creates as it goes; those are recorded in
+// checkpoint, and issues it already holds are not sent again.
+func doPushToJira(ctx context.Context, dryRun bool, createOnly bool, updateRefs bool, checkpoint *syncCheckpoint) (*PushStats, error) {
 	stats := &PushStats{}
 
 	// Find the Python script
@@ -491,6 +535,10 @@ func doPushToJira(ctx context.Context, dryRun bool, createOnly bool, updateRefs
 	// Generate JSONL for export
 	var jsonlLines []string
 	for _, issue := range issues {
+		if checkpoint.wasPushed(issue.ID) {
+			stats.Skipped++
+			continue
+		}
 		data, err := json.Marshal(issue)
 		if err != nil {
 			return stats, fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
@@ -515,14 +563,19 @@ func doPushToJira(ctx context.Context, dryRun bool, createOnly bool, updateRefs
 	cmd := exec.CommandContext(ctx, "python3", args...)
 	cmd.Stdin = strings.NewReader(jsonlContent)
 	cmd.Stderr = os.Stderr
+	cmd.Env = jiraScriptEnv(ctx)
 
-	output, err := cmd.Output()
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return stats, fmt.Errorf("failed to push to Jira: %w", err)
 	}
+	if err := cmd.Start(); err != nil {
+		return stats, fmt.Errorf("failed to push to Jira: %w", err)
+	}
 
-	// Parse output for statistics and external_ref updates
-	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	// Parse output for statistics and external_ref updates as it arrives, so
+	// an interrupted push still records the issues it created
+	scanner := bufio.NewScanner(stdout)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" {
@@ -548,9 +601,24 @@ func doPushToJira(ctx context.Context, dryRun bool, createOnly bool, updateRefs
 					stats.Errors++
 				}
 			}
+			if !dryRun {
+				if err := checkpoint.markPushed(ctx, store, mapping.BDID); err != nil {
+					_ = cmd.Process.Kill()
+					_ = cmd.Wait()
+					return stats, fmt.Errorf("failed to save checkpoint: %w", err)
+				}
+			}
 		}
 	}
 
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return stats, fmt.Errorf("interrupted after pushing %d issue(s), re-run to resume: %w",
+				len(checkpoint.Pushed), context.Cause(ctx))
+		}
+		return stats, fmt.Errorf("failed to push to Jira: %w", err)
+	}
+
 	return stats, nil
 }
 
@@ -807,11 +875,8 @@ func fetchJiraIssueTimestamp(ctx context.Context, jiraKey string) (time.Time, er
 	}
 	jiraURL = strings.TrimSuffix(jiraURL, "/")
 
-	// Get credentials (config takes precedence over env)
-	apiToken, _ := store.GetConfig(ctx, "jira.api_token")
-	if apiToken == "" {
-		apiToken = os.Getenv("JIRA_API_TOKEN")
-	}
+	// Get credentials (config, then env, then the credentials store)
+	apiToken, _ := lookupSecret(ctx, "jira.api_token")
 	if apiToken == "" {
 		return zero, fmt.Errorf("jira API token not configured")
 	}
@@ -849,8 +914,7 @@ func fetchJiraIssueTimestamp(ctx context.Context, jiraKey string) (time.Time, er
 	req.Header.Set("User-Agent", "bd-jira-sync/1.0")
 
 	// Execute request
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := httpx.NewClient(30 * time.Second).Do(req)
 	if err != nil {
 		return zero, fmt.Errorf("failed to fetch issue %s: %w", jiraKey, err)
 	}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/beads/internal/atomicfile"
+)
+
+// jsonlLockFile serializes JSONL exports and imports across bd processes.
+// An incremental flush reads the JSONL, merges dirty issues and writes it
+// back; without the lock two processes flushing at once each write their own
+// merge and the later one drops the other's issues.
+const jsonlLockFile = ".jsonl.lock"
+
+// jsonlLockTimeout bounds how long a flush or import waits for another
+// process. Flushes are retried on the next command, so giving up is safe.
+var jsonlLockTimeout = 30 * time.Second
+
+// lockJSONL takes the advisory lock for the JSONL file at jsonlPath and
+// returns a function that releases it.
+func lockJSONL(ctx context.Context, jsonlPath string) (func(), error) {
+	if ctx == nil {
+		// Flushes can run before rootCtx is set (tests, early startup)
+		ctx = context.Background()
+	}
+	lock := flock.New(filepath.Join(filepath.Dir(jsonlPath), jsonlLockFile))
+	lockCtx, cancel := context.WithTimeout(ctx, jsonlLockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(lockCtx, 20*time.Millisecond)
+	if err != nil {
+		if lockCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timed out after %s waiting for another bd process to finish with %s", jsonlLockTimeout, filepath.Base(jsonlPath))
+		}
+		return nil, fmt.Errorf("acquiring JSONL lock: %w", err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("acquiring JSONL lock: lock not acquired")
+	}
+	return func() { _ = lock.Unlock() }, nil
+}
+
+// recoverInterruptedExport finishes or undoes a JSONL export that crashed
+// mid-write, so the file is never read while truncated. Call it with the
+// JSONL lock held, before reading the file.
+func recoverInterruptedExport(jsonlPath string) {
+	r, err := atomicfile.Recover(jsonlPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	if r != atomicfile.RecoveryNone {
+		fmt.Fprintf(os.Stderr, "Recovered interrupted export of %s (%s)\n", filepath.Base(jsonlPath), r)
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// LabelStats is one label's activity in a window, against the window before.
+type LabelStats struct {
+	Label          string  `json:"label"`
+	Open           int     `json:"open"`   // Open now
+	Opened         int     `json:"opened"` // Created in the window
+	Closed         int     `json:"closed"` // Closed in the window
+	PreviousOpened int     `json:"previous_opened"`
+	PreviousClosed int     `json:"previous_closed"`
+	OpenedTrend    string  `json:"opened_trend"` // up, down or flat against the previous window
+	ClosedTrend    string  `json:"closed_trend"`
+	CycleTimeHours float64 `json:"avg_cycle_time_hours,omitempty"` // Mean over issues closed in the window
+}
+
+// LabelStatsReport is per-label activity over a window.
+type LabelStatsReport struct {
+	Since  time.Time     `json:"since"`
+	Until  time.Time     `json:"until"`
+	Labels []*LabelStats `json:"labels"` // Most churn first
+}
+
+// Trend directions in LabelStats.
+const (
+	trendUp   = "up"
+	trendDown = "down"
+	trendFlat = "flat"
+)
+
+var statusLabelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "Show open and close rates and cycle time per label",
+	Long: `Show, for each label, how many issues were opened and closed in the window,
+how many are open now and the average cycle time of those closed. Arrows
+compare the opened and closed counts with the window of the same length
+before it. Labels with the most churn (opened plus closed) come first,
+pointing at the areas that generate the most work.
+
+Cycle time runs from when an issue first went in progress to when it
+closed, or from creation for issues never marked in progress.`,
+	Example: `  bd stats labels                 # Last 30 days against the 30 before
+  bd stats labels --since 7d --limit 10
+  bd stats labels --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+		now := time.Now()
+		since, err := parsePastSince(sinceFlag, now)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if err := ensureDirectMode("stats labels requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		report, err := buildLabelStats(rootCtx, store, since, now)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if limit > 0 && len(report.Labels) > limit {
+			report.Labels = report.Labels[:limit]
+		}
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		displayLabelStats(report)
+	},
+}
+
+// buildLabelStats counts each label's activity from since to now and in
+// the window of the same length before it.
+func buildLabelStats(ctx context.Context, s storage.Storage, since, now time.Time) (*LabelStatsReport, error) {
+	previous := since.Add(-now.Sub(since))
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+
+	in := func(t time.Time, from, to time.Time) bool { return !t.Before(from) && t.Before(to) }
+	byLabel := make(map[string]*LabelStats)
+	cycleTotals := make(map[string]float64)
+	cycleCounts := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Status == types.StatusTombstone || len(labels[issue.ID]) == 0 {
+			continue
+		}
+		closedInWindow := issue.ClosedAt != nil && in(*issue.ClosedAt, since, now)
+		var cycle float64
+		if closedInWindow {
+			start := issue.CreatedAt
+			events, err := s.GetEvents(ctx, issue.ID, 0)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get events of %s: %w", issue.ID, err)
+			}
+			if started := firstStartedAt(events); started != nil && started.Before(*issue.ClosedAt) {
+				start = *started
+			}
+			cycle = issue.ClosedAt.Sub(start).Hours()
+		}
+
+		for _, label := range uniqueStrings(labels[issue.ID]) {
+			st, ok := byLabel[label]
+			if !ok {
+				st = &LabelStats{Label: label}
+				byLabel[label] = st
+			}
+			if issue.Status != types.StatusClosed {
+				st.Open++
+			}
+			switch {
+			case in(issue.CreatedAt, since, now):
+				st.Opened++
+			case in(issue.CreatedAt, previous, since):
+				st.PreviousOpened++
+			}
+			if closedInWindow {
+				st.Closed++
+				cycleTotals[label] += cycle
+				cycleCounts[label]++
+			} else if issue.ClosedAt != nil && in(*issue.ClosedAt, previous, since) {
+				st.PreviousClosed++
+			}
+		}
+	}
+
+	report := &LabelStatsReport{Since: since, Until: now, Labels: []*LabelStats{}}
+	for label, st := range byLabel {
+		st.OpenedTrend = labelTrend(st.Opened, st.PreviousOpened)
+		st.ClosedTrend = labelTrend(st.Closed, st.PreviousClosed)
+		if cycleCounts[label] > 0 {
+			st.CycleTimeHours = cycleTotals[label] / float64(cycleCounts[label])
+		}
+		report.Labels = append(report.Labels, st)
+	}
+	sort.Slice(report.Labels, func(i, j int) bool {
+		a, b := report.Labels[i], report.Labels[j]
+		if a.Opened+a.Closed != b.Opened+b.Closed {
+			return a.Opened+a.Closed > b.Opened+b.Closed
+		}
+		if a.Open != b.Open {
+			return a.Open > b.Open
+		}
+		return a.Label < b.Label
+	})
+	return report, nil
+}
+
+// labelTrend compares a count with the previous window's.
+func labelTrend(current, previous int) string {
+	switch {
+	case current > previous:
+		return trendUp
+	case current < previous:
+		return trendDown
+	default:
+		return trendFlat
+	}
+}
+
+// trendArrow renders a trend as an arrow.
+func trendArrow(t string) string {
+	switch t {
+	case trendUp:
+		return "↑"
+	case trendDown:
+		return "↓"
+	default:
+		return "→"
+	}
+}
+
+func displayLabelStats(r *LabelStatsReport) {
+	if len(r.Labels) == 0 {
+		fmt.Printf("\n%s No labelled issues\n\n", ui.RenderPass("✨"))
+		return
+	}
+	width := len("LABEL")
+	for _, st := range r.Labels {
+		width = max(width, len(st.Label))
+	}
+	fmt.Printf("\n%s Label activity since %s (arrows compare with the %d days before):\n\n",
+		ui.RenderAccent("🏷"), r.Since.Format("2006-01-02"), int(r.Until.Sub(r.Since).Hours()/24+0.5))
+	fmt.Printf("  %-*s  %6s  %8s  %8s  %s\n", width, "LABEL", "OPEN", "OPENED", "CLOSED", "AVG CYCLE TIME")
+	for _, st := range r.Labels {
+		cycle := "-"
+		if st.CycleTimeHours > 0 {
+			cycle = formatDuration(st.CycleTimeHours)
+		}
+		fmt.Printf("  %-*s  %6d  %6d %s  %6d %s  %s\n", width, st.Label, st.Open,
+			st.Opened, trendArrow(st.OpenedTrend), st.Closed, trendArrow(st.ClosedTrend), cycle)
+	}
+	fmt.Println()
+}
+
+func init() {
+	statusLabelsCmd.Flags().String("since", "30d", "Start of the window: a duration such as 30d, or a date")
+	statusLabelsCmd.Flags().Int("limit", 0, "Show at most this many labels (0 for all)")
+	statusCmd.AddCommand(statusLabelsCmd)
+}
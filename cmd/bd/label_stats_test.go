@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestBuildLabelStats(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	now := time.Now()
+	for _, tt := range []struct {
+		id      string
+		created time.Time
+		labels  []string
+	}{
+		{"test-1", now.AddDate(0, 0, -2), []string{"api"}},
+		{"test-2", now.AddDate(0, 0, -3), []string{"api", "ui"}},
+		{"test-3", now.AddDate(0, 0, -10), []string{"ui"}},
+		{"test-4", now.AddDate(0, 0, -12), []string{"ui"}},
+		{"test-5", now.AddDate(0, 0, -1), nil},
+	} {
+		issue := &types.Issue{ID: tt.id, Title: tt.id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, CreatedAt: tt.created}
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+		for _, label := range tt.labels {
+			if err := s.AddLabel(ctx, tt.id, label, "tester"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := s.UpdateIssue(ctx, "test-1", map[string]interface{}{"status": string(types.StatusInProgress)}, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseIssue(ctx, "test-1", "done", "tester", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := buildLabelStats(ctx, s, now.AddDate(0, 0, -7), now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Labels) != 2 {
+		t.Fatalf("got %d labels, want 2", len(r.Labels))
+	}
+	api, ui := r.Labels[0], r.Labels[1]
+	if api.Label != "api" || api.Opened != 2 || api.Closed != 1 || api.Open != 1 || api.OpenedTrend != trendUp || api.ClosedTrend != trendUp {
+		t.Errorf("api = %+v", api)
+	}
+	// Cycle time counts from going in progress, moments before the close
+	if api.CycleTimeHours <= 0 || api.CycleTimeHours > 1 {
+		t.Errorf("api cycle time = %vh, want under an hour", api.CycleTimeHours)
+	}
+	if ui.Label != "ui" || ui.Opened != 1 || ui.PreviousOpened != 2 || ui.OpenedTrend != trendDown || ui.ClosedTrend != trendFlat || ui.Open != 3 {
+		t.Errorf("ui = %+v", ui)
+	}
+}
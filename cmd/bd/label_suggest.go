@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/labeler"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+var labelSuggestCmd = &cobra.Command{
+	Use:   "suggest <issue-id>...",
+	Short: "Suggest labels from keyword rules and similar labeled issues",
+	Long: `Suggest labels an issue is missing. Two sources vote:
+
+  rule      A keyword rule under labels.auto in config.yaml matches the
+            issue's text. New issues get these labels automatically on
+            bd create and on imports from other trackers.
+  similar   Labeled issues whose text is most like this one carry the
+            label. Issues are compared as TF-IDF text embeddings (words and
+            word pairs, weighted by rarity), computed locally; the score is
+            the label's share of the nearest neighbours' similarity.
+
+Keyword rules look like this; /.../ is a regular expression, anything else
+matches as a whole word or phrase, ignoring case:
+
+  labels:
+    auto:
+      security: [cve, xss, "sql injection"]
+      flaky: /flak(y|iness)/
+
+Use --apply to add the suggested labels.`,
+	Example: `  bd label suggest bd-42
+  bd label suggest bd-42 bd-43 --min-score 0.5 --apply`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+		apply, _ := cmd.Flags().GetBool("apply")
+		minScore, _ := cmd.Flags().GetFloat64("min-score")
+		neighbors, _ := cmd.Flags().GetInt("neighbors")
+		if neighbors < 1 {
+			FatalErrorCode(ErrCodeUsage, "--neighbors must be at least 1")
+		}
+		if apply {
+			CheckReadonly("label suggest --apply")
+		}
+		if err := ensureDirectMode("label suggest requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+
+		rules := autoLabelRules()
+		all, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+		if err != nil {
+			FatalErrorRespectJSON("loading issues: %v", err)
+		}
+		ids := make([]string, len(all))
+		for i, issue := range all {
+			ids[i] = issue.ID
+		}
+		labels, err := store.GetLabelsForIssues(ctx, ids)
+		if err != nil {
+			FatalErrorRespectJSON("loading labels: %v", err)
+		}
+		byID := make(map[string]*types.Issue, len(all))
+		for _, issue := range all {
+			issue.Labels = labels[issue.ID]
+			byID[issue.ID] = issue
+		}
+		index := labeler.NewIndex(all)
+
+		type result struct {
+			IssueID     string               `json:"issue_id"`
+			Title       string               `json:"title"`
+			Suggestions []labeler.Suggestion `json:"suggestions"`
+			Applied     []string             `json:"applied,omitempty"`
+		}
+		var results []result
+		changed := false
+		for _, arg := range args {
+			id, err := utils.ResolvePartialID(ctx, store, arg)
+			if err != nil {
+				FatalErrorRespectJSON("resolving %s: %v", arg, err)
+			}
+			issue := byID[id]
+			if issue == nil {
+				FatalErrorRespectJSON("issue %s not found", arg)
+			}
+			r := result{IssueID: id, Title: issue.Title, Suggestions: index.Suggest(issue, rules, neighbors, minScore)}
+			if apply {
+				for _, s := range r.Suggestions {
+					if err := store.AddLabel(ctx, id, s.Label, actor); err != nil {
+						FatalErrorRespectJSON("adding %s to %s: %v", s.Label, id, err)
+					}
+					r.Applied = append(r.Applied, s.Label)
+					changed = true
+				}
+			}
+			results = append(results, r)
+		}
+		if changed {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(results)
+			return
+		}
+		for _, r := range results {
+			fmt.Printf("%s %s\n", ui.RenderAccent(r.IssueID), r.Title)
+			if len(r.Suggestions) == 0 {
+				fmt.Printf("  %s\n", ui.RenderMuted("No suggestions"))
+				continue
+			}
+			for _, s := range r.Suggestions {
+				why := fmt.Sprintf("keyword %q", s.Keyword)
+				if s.Source == "similar" {
+					why = "like " + strings.Join(s.Similar, ", ")
+				}
+				fmt.Printf("  %-20s %.2f  %s\n", s.Label, s.Score, ui.RenderMuted(why))
+			}
+			if len(r.Applied) > 0 {
+				fmt.Printf("  %s Added %s\n", ui.RenderPass("✓"), strings.Join(r.Applied, ", "))
+			}
+		}
+	},
+}
+
+// autoLabelRules compiles labels.auto, warning about rules that don't.
+func autoLabelRules() []*labeler.Rule {
+	rules, errs := labeler.Compile(config.GetAutoLabelRules())
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "%s %v\n", ui.RenderWarn("⚠"), err)
+	}
+	return rules
+}
+
+// autoLabels returns the labels.auto labels for a new issue that aren't in
+// have already.
+func autoLabels(issue *types.Issue, have []string) []string {
+	return labeler.Apply(autoLabelRules(), issue, have)
+}
+
+func init() {
+	labelSuggestCmd.Flags().Bool("apply", false, "Add the suggested labels")
+	labelSuggestCmd.Flags().Float64("min-score", 0.3, "Only suggest labels from similar issues scoring at least this (0-1)")
+	labelSuggestCmd.Flags().Int("neighbors", 10, "Number of most similar labeled issues that vote")
+	labelCmd.AddCommand(labelSuggestCmd)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/i18n"
+)
+
+// applyLanguage switches human-readable output to the lang setting
+// (BEADS_LANG, --lang or lang in config.yaml). JSON output stays English.
+func applyLanguage() {
+	lang := config.GetString("lang")
+	if lang == "" || jsonOutput {
+		return
+	}
+	if err := i18n.SetLanguage(lang); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
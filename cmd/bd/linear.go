@@ -24,7 +24,7 @@ var linearCmd = &cobra.Command{
 	Long: `Synchronize issues between beads and Linear.
 
 Configuration:
-  bd config set linear.api_key "YOUR_API_KEY"
+  bd auth login linear                          # API key, kept out of the config
   bd config set linear.team_id "TEAM_ID"
   bd config set linear.project_id "PROJECT_ID"  # Optional: sync only this project
 
@@ -59,6 +59,11 @@ Data Mapping (optional, sensible defaults provided):
     bd config set linear.relation_map.duplicate duplicates
     bd config set linear.relation_map.related related
 
+  Projects, cycles and estimates:
+    bd config set linear.project_map.<project-uuid> api   # Project -> component:api
+    bd config set linear.estimate_minutes 60              # Minutes per estimate point
+    Pulled issues are labeled cycle:<number> for their Linear cycle.
+
   ID generation (optional, hash IDs to match bd/Jira hash mode):
     bd config set linear.id_mode "hash"      # hash (default)
     bd config set linear.hash_length "6"     # hash length 3-8 (default: 6)
@@ -87,11 +92,17 @@ Conflict Resolution:
   --prefer-local    Always prefer local beads version
   --prefer-linear   Always prefer Linear version
 
+Progress is checkpointed as the sync runs. If it is interrupted (Ctrl-C,
+--timeout, a network error), the next sync resumes: a finished pull is not
+repeated and issues already pushed are skipped. --restart discards the
+checkpoint.
+
 Examples:
   bd linear sync --pull                # Import from Linear
   bd linear sync --push --create-only  # Push new issues only
   bd linear sync --dry-run             # Preview without changes
-  bd linear sync --prefer-local        # Bidirectional, local wins`,
+  bd linear sync --prefer-local        # Bidirectional, local wins
+  bd linear sync --restart             # Ignore an interrupted sync's checkpoint`,
 	Run: runLinearSync,
 }
 
@@ -130,6 +141,7 @@ func init() {
 	linearSyncCmd.Flags().Bool("create-only", false, "Only create new issues, don't update existing")
 	linearSyncCmd.Flags().Bool("update-refs", true, "Update external_ref after creating Linear issues")
 	linearSyncCmd.Flags().String("state", "all", "Issue state to sync: open, closed, all")
+	linearSyncCmd.Flags().Bool("restart", false, "Discard the checkpoint of an interrupted sync and start over")
 
 	linearCmd.AddCommand(linearSyncCmd)
 	linearCmd.AddCommand(linearStatusCmd)
@@ -146,6 +158,7 @@ func runLinearSync(cmd *cobra.Command, args []string) {
 	createOnly, _ := cmd.Flags().GetBool("create-only")
 	updateRefs, _ := cmd.Flags().GetBool("update-refs")
 	state, _ := cmd.Flags().GetString("state")
+	restart, _ := cmd.Flags().GetBool("restart")
 
 	if !dryRun {
 		CheckReadonly("linear sync")
@@ -173,12 +186,19 @@ func runLinearSync(cmd *cobra.Command, args []string) {
 
 	ctx := rootCtx
 	result := &linear.SyncResult{Success: true}
+	checkpoint, err := beginSyncCheckpoint(ctx, store, "linear", restart, dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 	var forceUpdateIDs map[string]bool
 	var skipUpdateIDs map[string]bool
 	var prePullConflicts []linear.Conflict
 	var prePullSkipLinearIDs map[string]bool
 
-	if pull {
+	if pull && checkpoint.Pulled {
+		fmt.Println("✓ Pull already finished before the interruption")
+	} else if pull {
 		if preferLocal || preferLinear {
 			conflicts, err := detectLinearConflicts(ctx)
 			if err != nil {
@@ -225,6 +245,9 @@ func runLinearSync(cmd *cobra.Command, args []string) {
 		result.Stats.Skipped += pullStats.Skipped
 
 		if !dryRun {
+			if err := checkpoint.markPulled(ctx, store, nil); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to save checkpoint: %v", err))
+			}
 			fmt.Printf("✓ Pulled %d issues (%d created, %d updated)\n",
 				result.Stats.Pulled, pullStats.Created, pullStats.Updated)
 		}
@@ -314,7 +337,7 @@ func runLinearSync(cmd *cobra.Command, args []string) {
 			fmt.Println("→ Pushing issues to Linear...")
 		}
 
-		pushStats, err := doPushToLinear(ctx, dryRun, createOnly, updateRefs, forceUpdateIDs, skipUpdateIDs)
+		pushStats, err := doPushToLinear(ctx, dryRun, createOnly, updateRefs, forceUpdateIDs, skipUpdateIDs, checkpoint)
 		if err != nil {
 			result.Success = false
 			result.Error = err.Error()
@@ -339,10 +362,15 @@ func runLinearSync(cmd *cobra.Command, args []string) {
 	}
 
 	if !dryRun && result.Success {
-		result.LastSync = time.Now().Format(time.RFC3339)
+		// Record when this sync (or the interrupted one it resumed) started,
+		// so changes made during it are picked up next time
+		result.LastSync = checkpoint.Started.Format(time.RFC3339)
 		if err := store.SetConfig(ctx, "linear.last_sync", result.LastSync); err != nil {
 			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to update last_sync: %v", err))
 		}
+		if err := checkpoint.clear(ctx, store); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to clear checkpoint: %v", err))
+		}
 	}
 
 	if jsonOutput {
@@ -371,6 +399,7 @@ func runLinearStatus(cmd *cobra.Command, args []string) {
 	apiKey, _ := getLinearConfig(ctx, "linear.api_key")
 	teamID, _ := getLinearConfig(ctx, "linear.team_id")
 	lastSync, _ := store.GetConfig(ctx, "linear.last_sync")
+	checkpoint, interrupted, _ := loadSyncCheckpoint(ctx, store, "linear")
 
 	configured := apiKey != "" && teamID != ""
 
@@ -400,6 +429,7 @@ func runLinearStatus(cmd *cobra.Command, args []string) {
 			"total_issues":    len(allIssues),
 			"with_linear_ref": withLinearRef,
 			"pending_push":    pendingPush,
+			"interrupted":     interrupted,
 		})
 		return
 	}
@@ -412,7 +442,7 @@ func runLinearStatus(cmd *cobra.Command, args []string) {
 		fmt.Println("Status: Not configured")
 		fmt.Println()
 		fmt.Println("To configure Linear integration:")
-		fmt.Println("  bd config set linear.api_key \"YOUR_API_KEY\"")
+		fmt.Println("  bd auth login linear")
 		fmt.Println("  bd config set linear.team_id \"TEAM_ID\"")
 		fmt.Println()
 		fmt.Println("Or use environment variables:")
@@ -428,6 +458,10 @@ func runLinearStatus(cmd *cobra.Command, args []string) {
 	} else {
 		fmt.Println("Last Sync:    Never")
 	}
+	if interrupted {
+		fmt.Printf("Interrupted:  sync started %s, %d issue(s) pushed (next sync resumes)\n",
+			checkpoint.Started.Local().Format("2006-01-02 15:04"), len(checkpoint.Pushed))
+	}
 	fmt.Println()
 	fmt.Printf("Total Issues: %d\n", len(allIssues))
 	fmt.Printf("With Linear:  %d\n", withLinearRef)
@@ -445,7 +479,7 @@ func runLinearTeams(cmd *cobra.Command, args []string) {
 	apiKey, apiKeySource := getLinearConfig(ctx, "linear.api_key")
 	if apiKey == "" {
 		fmt.Fprintf(os.Stderr, "Error: Linear API key not configured\n")
-		fmt.Fprintf(os.Stderr, "Run: bd config set linear.api_key \"YOUR_API_KEY\"\n")
+		fmt.Fprintf(os.Stderr, "Run: bd auth login linear\n")
 		fmt.Fprintf(os.Stderr, "Or:  export LINEAR_API_KEY=YOUR_API_KEY\n")
 		os.Exit(1)
 	}
@@ -500,7 +534,7 @@ func validateLinearConfig() error {
 
 	apiKey, _ := getLinearConfig(ctx, "linear.api_key")
 	if apiKey == "" {
-		return fmt.Errorf("Linear API key not configured\nRun: bd config set linear.api_key \"YOUR_API_KEY\"\nOr: export LINEAR_API_KEY=YOUR_API_KEY")
+		return fmt.Errorf("Linear API key not configured\nRun: bd auth login linear\nOr: export LINEAR_API_KEY=YOUR_API_KEY")
 	}
 
 	teamID, _ := getLinearConfig(ctx, "linear.team_id")
@@ -526,7 +560,7 @@ func maskAPIKey(key string) string {
 
 // getLinearConfig reads a Linear configuration value, handling both daemon mode
 // (where store is nil) and direct mode. Returns the value and its source.
-// Priority: project config > environment variable.
+// Priority: project config > environment variable > credentials store (API key).
 func getLinearConfig(ctx context.Context, key string) (value string, source string) {
 	// Try to read from store (works in direct mode)
 	if store != nil {
@@ -554,6 +588,9 @@ func getLinearConfig(ctx context.Context, key string) (value string, source stri
 		}
 	}
 
+	if key == "linear.api_key" {
+		return lookupCredential(key)
+	}
 	return "", ""
 }
 
@@ -138,6 +138,8 @@ func doPullFromLinear(ctx context.Context, dryRun bool, state string, skipLinear
 	opts := ImportOptions{
 		DryRun:     dryRun,
 		SkipUpdate: false,
+		LockActor:  getActor(),
+		CheckRules: true,
 	}
 
 	result, err := importIssuesCore(ctx, dbPath, store, beadsIssues, opts)
@@ -208,8 +210,9 @@ func doPullFromLinear(ctx context.Context, dryRun bool, state string, skipLinear
 	return stats, nil
 }
 
-// doPushToLinear exports issues to Linear using the GraphQL API.
-func doPushToLinear(ctx context.Context, dryRun bool, createOnly bool, updateRefs bool, forceUpdateIDs map[string]bool, skipUpdateIDs map[string]bool) (*linear.PushStats, error) {
+// doPushToLinear exports issues to Linear using the GraphQL API. Each pushed
+// issue is recorded in checkpoint, and issues it already holds are skipped.
+func doPushToLinear(ctx context.Context, dryRun bool, createOnly bool, updateRefs bool, forceUpdateIDs map[string]bool, skipUpdateIDs map[string]bool, checkpoint *syncCheckpoint) (*linear.PushStats, error) {
 	stats := &linear.PushStats{}
 
 	client, err := getLinearClient(ctx)
@@ -229,6 +232,10 @@ func doPushToLinear(ctx context.Context, dryRun bool, createOnly bool, updateRef
 		if issue.IsTombstone() {
 			continue
 		}
+		if checkpoint.wasPushed(issue.ID) {
+			stats.Skipped++
+			continue
+		}
 
 		if issue.ExternalRef != nil && linear.IsLinearExternalRef(*issue.ExternalRef) {
 			if !createOnly {
@@ -249,7 +256,18 @@ func doPushToLinear(ctx context.Context, dryRun bool, createOnly bool, updateRef
 
 	mappingConfig := loadLinearMappingConfig(ctx)
 
+	interrupted := func() error {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("interrupted after pushing %d issue(s), re-run to resume: %w",
+				len(checkpoint.Pushed), context.Cause(ctx))
+		}
+		return nil
+	}
+
 	for _, issue := range toCreate {
+		if err := interrupted(); err != nil {
+			return stats, err
+		}
 		if dryRun {
 			stats.Created++
 			continue
@@ -260,7 +278,8 @@ func doPushToLinear(ctx context.Context, dryRun bool, createOnly bool, updateRef
 
 		description := linear.BuildLinearDescription(issue)
 
-		linearIssue, err := client.CreateIssue(ctx, issue.Title, description, linearPriority, stateID, nil)
+		fields := linearPushFields(ctx, issue, mappingConfig)
+		linearIssue, err := client.CreateIssueWithFields(ctx, issue.Title, description, linearPriority, stateID, nil, fields)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to create issue '%s' in Linear: %v\n", issue.Title, err)
 			stats.Errors++
@@ -283,10 +302,16 @@ func doPushToLinear(ctx context.Context, dryRun bool, createOnly bool, updateRef
 				stats.Errors++
 			}
 		}
+		if err := checkpoint.markPushed(ctx, store, issue.ID); err != nil {
+			return stats, fmt.Errorf("failed to save checkpoint: %w", err)
+		}
 	}
 
 	if len(toUpdate) > 0 && !createOnly {
 		for _, issue := range toUpdate {
+			if err := interrupted(); err != nil {
+				return stats, err
+			}
 			if skipUpdateIDs != nil && skipUpdateIDs[issue.ID] {
 				stats.Skipped++
 				continue
@@ -359,6 +384,10 @@ func doPushToLinear(ctx context.Context, dryRun bool, createOnly bool, updateRef
 				updatePayload["stateId"] = stateID
 			}
 
+			for k, v := range linearPushFields(ctx, issue, mappingConfig) {
+				updatePayload[k] = v
+			}
+
 			updatedLinearIssue, err := client.UpdateIssue(ctx, linearIssue.ID, updatePayload)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to update Linear issue %s: %v\n",
@@ -369,6 +398,9 @@ func doPushToLinear(ctx context.Context, dryRun bool, createOnly bool, updateRef
 
 			stats.Updated++
 			fmt.Printf("  Updated: %s -> %s\n", issue.ID, updatedLinearIssue.Identifier)
+			if err := checkpoint.markPushed(ctx, store, issue.ID); err != nil {
+				return stats, fmt.Errorf("failed to save checkpoint: %w", err)
+			}
 		}
 	}
 
@@ -381,3 +413,26 @@ func doPushToLinear(ctx context.Context, dryRun bool, createOnly bool, updateRef
 
 	return stats, nil
 }
+
+// linearPushFields returns the Linear fields derived from an issue's
+// estimate and component: estimate points (linear.estimate_minutes) and the
+// project its component maps to (linear.project_map.<project-id>).
+func linearPushFields(ctx context.Context, issue *types.Issue, config *linear.MappingConfig) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if estimate := linear.MinutesToEstimate(issue.EstimatedMinutes, config); estimate > 0 {
+		fields["estimate"] = estimate
+	}
+	if len(config.ProjectMap) > 0 {
+		labels, err := store.GetLabels(ctx, issue.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to get labels for %s: %v\n", issue.ID, err)
+		}
+		for _, component := range issueComponents(labels) {
+			if projectID := linear.ComponentToProjectID(component, config); projectID != "" {
+				fields["projectId"] = projectID
+				break
+			}
+		}
+	}
+	return fields
+}
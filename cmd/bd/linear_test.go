@@ -1219,8 +1219,12 @@ func TestDoPushToLinearPreferLocalForcesUpdate(t *testing.T) {
 		actor = origActor
 	})
 
+	checkpoint, _, err := loadSyncCheckpoint(ctx, testStore, "linear")
+	if err != nil {
+		t.Fatalf("loadSyncCheckpoint failed: %v", err)
+	}
 	forceUpdateIDs := map[string]bool{issue.ID: true}
-	stats, err := doPushToLinear(ctx, false, false, true, forceUpdateIDs, nil)
+	stats, err := doPushToLinear(ctx, false, false, true, forceUpdateIDs, nil, checkpoint)
 	if err != nil {
 		t.Fatalf("doPushToLinear failed: %v", err)
 	}
@@ -1233,6 +1237,20 @@ func TestDoPushToLinearPreferLocalForcesUpdate(t *testing.T) {
 	if stats.Skipped != 0 {
 		t.Fatalf("expected Skipped=0, got %d", stats.Skipped)
 	}
+
+	// A resumed push skips issues the interrupted one already pushed
+	resumed, ok, err := loadSyncCheckpoint(ctx, testStore, "linear")
+	if err != nil || !ok {
+		t.Fatalf("reload checkpoint: resumed=%v err=%v", ok, err)
+	}
+	updatedCalled = false
+	stats, err = doPushToLinear(ctx, false, false, true, forceUpdateIDs, nil, resumed)
+	if err != nil {
+		t.Fatalf("resumed doPushToLinear failed: %v", err)
+	}
+	if updatedCalled || stats.Updated != 0 || stats.Skipped != 1 {
+		t.Fatalf("resumed push: updated=%v stats=%+v, want the issue skipped", updatedCalled, stats)
+	}
 }
 
 func TestLinearClientFetchIssues(t *testing.T) {
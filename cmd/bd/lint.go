@@ -41,6 +41,9 @@ Examples:
   bd lint bd-abc bd-def      # Lint multiple issues
   bd lint --type bug         # Lint only bugs
   bd lint --status all       # Lint all issues (including closed)
+
+To check issue text for broken markdown, dead references and empty
+descriptions, see 'bd lint issues'.
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := rootCtx
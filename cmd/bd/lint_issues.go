@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+	"github.com/steveyegge/beads/internal/validation"
+)
+
+var lintIssuesCmd = &cobra.Command{
+	Use:   "issues [issue-id...]",
+	Short: "Check issue text for broken markdown, dead references and empty descriptions",
+	Long: `Check issues' titles and text fields (description, design, acceptance
+criteria, notes) for:
+
+  broken-markdown     unclosed code blocks and inline code, headings
+                      missing their space (##Plan), links with no target
+                      or no closing )
+  dead-reference      issue IDs mentioned in the text that don't exist
+                      (or were deleted)
+  empty-description   bug, feature, task and epic issues with no description
+
+Each problem comes with how to fix it. --fix repairs the markdown problems
+it can (closing code blocks and links, spacing headings) and reports the
+rest.
+
+By default, lints all open issues. Exits 1 when problems remain.
+
+Examples:
+  bd lint issues                     # Lint all open issues
+  bd lint issues bd-abc              # Lint one issue
+  bd lint issues --status all --json
+  bd lint issues --rule dead-reference
+  bd lint issues --fix`,
+	Run: func(cmd *cobra.Command, args []string) {
+		typeFilter, _ := cmd.Flags().GetString("type")
+		statusFilter, _ := cmd.Flags().GetString("status")
+		ruleFlag, _ := cmd.Flags().GetString("rule")
+		fix, _ := cmd.Flags().GetBool("fix")
+		rules := make(map[string]bool)
+		for _, r := range splitConfigList([]string{ruleFlag}) {
+			switch r {
+			case validation.RuleBrokenMarkdown, validation.RuleDeadReference, validation.RuleEmptyDescription:
+				rules[r] = true
+			default:
+				FatalErrorCode(ErrCodeUsage, "unknown rule %q (want %s, %s or %s)", r,
+					validation.RuleBrokenMarkdown, validation.RuleDeadReference, validation.RuleEmptyDescription)
+			}
+		}
+		if fix {
+			CheckReadonly("lint issues --fix")
+		}
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		// Every live issue, to tell dead references from live ones
+		all, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		known := make(map[string]bool, len(all))
+		for _, issue := range all {
+			known[strings.ToLower(issue.ID)] = true
+		}
+		exists := func(id string) bool { return known[id] }
+		prefix, _ := store.GetConfig(ctx, "issue_prefix")
+
+		var issues []*types.Issue
+		if len(args) > 0 {
+			for _, id := range args {
+				fullID, err := utils.ResolvePartialID(ctx, store, id)
+				if err != nil {
+					FatalErrorRespectJSON("resolving %s: %v", id, err)
+				}
+				issue, err := store.GetIssue(ctx, fullID)
+				if err != nil || issue == nil {
+					FatalErrorRespectJSON("issue %s not found", fullID)
+				}
+				issues = append(issues, issue)
+			}
+		} else {
+			for _, issue := range all {
+				if statusFilter != "all" && string(issue.Status) != statusFilter {
+					continue
+				}
+				if typeFilter != "" && string(issue.IssueType) != typeFilter {
+					continue
+				}
+				issues = append(issues, issue)
+			}
+		}
+
+		lint := func(issue *types.Issue) []validation.TextFinding {
+			var kept []validation.TextFinding
+			for _, f := range validation.LintText(issue, prefix, exists) {
+				if len(rules) == 0 || rules[f.Rule] {
+					kept = append(kept, f)
+				}
+			}
+			return kept
+		}
+
+		var findings []validation.TextFinding
+		var fixed []string
+		for _, issue := range issues {
+			found := lint(issue)
+			if fix && hasAutoFix(found) {
+				updates := markdownFixes(issue)
+				if len(updates) > 0 {
+					if err := store.UpdateIssue(ctx, issue.ID, updates, actor); err != nil {
+						FatalErrorRespectJSON("fixing %s: %v", issue.ID, err)
+					}
+					fixed = append(fixed, issue.ID)
+					found = lint(validation.ApplyUpdates(issue, updates))
+				}
+			}
+			findings = append(findings, found...)
+		}
+		if len(fixed) > 0 {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			if findings == nil {
+				findings = []validation.TextFinding{}
+			}
+			if fixed == nil {
+				fixed = []string{}
+			}
+			outputJSON(struct {
+				Checked  int                      `json:"checked"`
+				Fixed    []string                 `json:"fixed"`
+				Findings []validation.TextFinding `json:"findings"`
+			}{len(issues), fixed, findings})
+		} else {
+			printTextFindings(issues, findings, fixed)
+		}
+
+		if len(findings) > 0 {
+			// Exiting skips the final flush, so write the fixes first
+			if len(fixed) > 0 && flushManager != nil {
+				if err := flushManager.FlushNow(); err != nil {
+					WarnError("flush failed: %v", err)
+				}
+			}
+			os.Exit(1)
+		}
+	},
+}
+
+func hasAutoFix(findings []validation.TextFinding) bool {
+	for _, f := range findings {
+		if f.AutoFix {
+			return true
+		}
+	}
+	return false
+}
+
+// markdownFixes returns the updates that repair an issue's fixable
+// markdown. Titles are left alone: their problems are never auto-fixable.
+func markdownFixes(issue *types.Issue) map[string]interface{} {
+	updates := make(map[string]interface{})
+	for field, text := range map[string]string{
+		"description":         issue.Description,
+		"design":              issue.Design,
+		"acceptance_criteria": issue.AcceptanceCriteria,
+		"notes":               issue.Notes,
+	} {
+		if fixedText := validation.FixMarkdown(text); fixedText != text {
+			updates[field] = fixedText
+		}
+	}
+	return updates
+}
+
+func printTextFindings(issues []*types.Issue, findings []validation.TextFinding, fixed []string) {
+	for _, id := range fixed {
+		fmt.Printf("%s Fixed markdown in %s\n", ui.RenderPass("✓"), id)
+	}
+	if len(findings) == 0 {
+		fmt.Printf("%s No text problems found (%d issues checked)\n", ui.RenderPass("✓"), len(issues))
+		return
+	}
+
+	titles := make(map[string]string, len(issues))
+	for _, issue := range issues {
+		titles[issue.ID] = issue.Title
+	}
+	fixable, affected := 0, 0
+	for i, f := range findings {
+		if i == 0 || findings[i-1].ID != f.ID {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s: %s\n", ui.RenderID(f.ID), titles[f.ID])
+			affected++
+		}
+		where := f.Field
+		if f.Line > 0 {
+			where = fmt.Sprintf("%s:%d", f.Field, f.Line)
+		}
+		fmt.Printf("  %s %-22s %s\n", ui.RenderWarn("⚠"), where, f.Message)
+		hint := "fix: " + f.Fix
+		if f.AutoFix {
+			hint += " (bd lint issues --fix)"
+			fixable++
+		}
+		fmt.Printf("    %s\n", ui.RenderMuted(hint))
+	}
+	fmt.Printf("\n%d problem(s) in %d issue(s)", len(findings), affected)
+	if fixable > 0 {
+		fmt.Printf(", %d fixable with --fix", fixable)
+	}
+	fmt.Println()
+}
+
+func init() {
+	lintIssuesCmd.Flags().StringP("type", "t", "", "Filter by issue type (bug, task, feature, epic)")
+	lintIssuesCmd.Flags().StringP("status", "s", "open", "Filter by status (use 'all' for all)")
+	lintIssuesCmd.Flags().String("rule", "", "Only these rules, comma-separated: broken-markdown, dead-reference, empty-description")
+	lintIssuesCmd.Flags().Bool("fix", false, "Repair fixable markdown problems in place")
+	lintIssuesCmd.ValidArgsFunction = issueIDCompletion
+	lintCmd.AddCommand(lintIssuesCmd)
+}
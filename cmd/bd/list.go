@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 	"syscall"
@@ -18,6 +19,7 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/query"
 	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
@@ -26,6 +28,7 @@ import (
 	"github.com/steveyegge/beads/internal/ui"
 	"github.com/steveyegge/beads/internal/util"
 	"github.com/steveyegge/beads/internal/validation"
+	"github.com/steveyegge/beads/internal/votes"
 )
 
 // parseTimeFlag parses time strings using the layered time parsing architecture.
@@ -58,6 +61,9 @@ func renderStatusIcon(status types.Status) string {
 // formatPrettyIssue formats a single issue for pretty output
 // Uses semantic colors: status icon colored, priority P0/P1 colored, rest neutral
 func formatPrettyIssue(issue *types.Issue) string {
+	if ui.IsAccessible() {
+		return accessibleIssueLine(issue, issue.Labels)
+	}
 	// Use shared helpers from ui package
 	statusIcon := ui.RenderStatusIcon(string(issue.Status))
 	priorityTag := renderPriorityTag(issue.Priority)
@@ -169,6 +175,12 @@ func printPrettyTree(childrenMap map[string][]*types.Issue, parentID string, pre
 	})
 
 	for i, child := range children {
+		if ui.IsAccessible() {
+			// No tree drawing: say where each child belongs
+			fmt.Printf("%s Child of %s.\n", formatPrettyIssue(child), parentID)
+			printPrettyTree(childrenMap, child.ID, prefix)
+			continue
+		}
 		isLast := i == len(children)-1
 		connector := "├── "
 		if isLast {
@@ -192,7 +204,9 @@ func displayPrettyList(issues []*types.Issue, showHeader bool) {
 
 // displayPrettyListWithDeps displays issues in tree format using dependency data
 func displayPrettyListWithDeps(issues []*types.Issue, showHeader bool, allDeps map[string][]*types.Dependency) {
-	if showHeader {
+	if showHeader && ui.IsAccessible() {
+		fmt.Printf("Beads - Open & In Progress (%s)\n\n", time.Now().Format("15:04:05"))
+	} else if showHeader {
 		// Clear screen and show header
 		fmt.Print("\033[2J\033[H")
 		fmt.Println(strings.Repeat("=", 80))
@@ -215,7 +229,9 @@ func displayPrettyListWithDeps(issues []*types.Issue, showHeader bool, allDeps m
 
 	// Summary
 	fmt.Println()
-	fmt.Println(strings.Repeat("-", 80))
+	if !ui.IsAccessible() {
+		fmt.Println(strings.Repeat("-", 80))
+	}
 	openCount := 0
 	inProgressCount := 0
 	for _, issue := range issues {
@@ -227,8 +243,10 @@ func displayPrettyListWithDeps(issues []*types.Issue, showHeader bool, allDeps m
 		}
 	}
 	fmt.Printf("Total: %d issues (%d open, %d in progress)\n", len(issues), openCount, inProgressCount)
-	fmt.Println()
-	fmt.Println("Status: ○ open  ◐ in_progress  ● blocked  ✓ closed  ❄ deferred")
+	if !ui.IsAccessible() {
+		fmt.Println()
+		fmt.Println("Status: ○ open  ◐ in_progress  ● blocked  ✓ closed  ❄ deferred")
+	}
 }
 
 // watchIssues starts watching for changes and re-displays (GH#654)
@@ -255,6 +273,9 @@ func watchIssues(ctx context.Context, store storage.Storage, filter types.IssueF
 
 	// Initial display
 	issues, _ := store.SearchIssues(ctx, "", filter)
+	if sortBy == "votes" {
+		_ = votes.Populate(ctx, store, issues)
+	}
 	sortIssues(issues, sortBy, reverse)
 	displayPrettyList(issues, true)
 
@@ -287,6 +308,9 @@ func watchIssues(ctx context.Context, store storage.Storage, filter types.IssueF
 					}
 					debounceTimer = time.AfterFunc(debounceDelay, func() {
 						issues, _ := store.SearchIssues(ctx, "", filter)
+						if sortBy == "votes" {
+							_ = votes.Populate(ctx, store, issues)
+						}
 						sortIssues(issues, sortBy, reverse)
 						displayPrettyList(issues, true)
 						fmt.Fprintf(os.Stderr, "\nWatching for changes... (Press Ctrl+C to exit)\n")
@@ -343,6 +367,9 @@ func sortIssues(issues []*types.Issue, sortBy string, reverse bool) {
 			result = cmp.Compare(a.IssueType, b.IssueType)
 		case "assignee":
 			result = cmp.Compare(a.Assignee, b.Assignee)
+		case "votes":
+			// Default: highest score first (up votes minus down votes)
+			result = cmp.Compare(types.VoteScore(b.Votes), types.VoteScore(a.Votes))
 		default:
 			// Unknown sort field, no sorting
 			result = 0
@@ -357,6 +384,10 @@ func sortIssues(issues []*types.Issue, sortBy string, reverse bool) {
 
 // formatIssueLong formats a single issue in long format to a buffer
 func formatIssueLong(buf *strings.Builder, issue *types.Issue, labels []string) {
+	if ui.IsAccessible() {
+		buf.WriteString(accessibleIssueLine(issue, labels) + "\n")
+		return
+	}
 	status := string(issue.Status)
 	if status == "closed" {
 		line := fmt.Sprintf("%s%s [P%d] [%s] %s\n  %s",
@@ -392,6 +423,10 @@ func formatAgentIssue(buf *strings.Builder, issue *types.Issue) {
 // Uses status icons for better scanability - consistent with bd graph
 // Format: [icon] [pin] ID [Priority] [Type] @assignee [labels] - Title
 func formatIssueCompact(buf *strings.Builder, issue *types.Issue, labels []string) {
+	if ui.IsAccessible() {
+		buf.WriteString(accessibleIssueLine(issue, labels) + "\n")
+		return
+	}
 	labelsStr := ""
 	if len(labels) > 0 {
 		labelsStr = fmt.Sprintf(" %v", labels)
@@ -436,10 +471,21 @@ var listCmd = &cobra.Command{
 		allFlag, _ := cmd.Flags().GetBool("all")
 		formatStr, _ := cmd.Flags().GetString("format")
 		labels, _ := cmd.Flags().GetStringSlice("label")
+		if component, _ := cmd.Flags().GetString("component"); component != "" {
+			labels = append(labels, componentLabel(component))
+		}
 		labelsAny, _ := cmd.Flags().GetStringSlice("label-any")
+		labelGlobs, _ := cmd.Flags().GetStringSlice("label-glob")
+		notLabels, _ := cmd.Flags().GetStringSlice("not-label")
 		titleSearch, _ := cmd.Flags().GetString("title")
 		idFilter, _ := cmd.Flags().GetString("id")
 		longFormat, _ := cmd.Flags().GetBool("long")
+		withPR, _ := cmd.Flags().GetBool("with-pr")
+		securityOnly, _ := cmd.Flags().GetBool("security")
+		minCVSS, _ := cmd.Flags().GetFloat64("min-cvss")
+		reproText, _ := cmd.Flags().GetString("repro")
+		ccPerson, _ := cmd.Flags().GetString("cc")
+		visibilityLevel, _ := cmd.Flags().GetString("visibility")
 		sortBy, _ := cmd.Flags().GetString("sort")
 		reverse, _ := cmd.Flags().GetBool("reverse")
 
@@ -447,6 +493,7 @@ var listCmd = &cobra.Command{
 		titleContains, _ := cmd.Flags().GetString("title-contains")
 		descContains, _ := cmd.Flags().GetString("desc-contains")
 		notesContains, _ := cmd.Flags().GetString("notes-contains")
+		titleRegex, _ := cmd.Flags().GetString("title-re")
 
 		// Date range flags
 		createdAfter, _ := cmd.Flags().GetString("created-after")
@@ -513,6 +560,13 @@ var listCmd = &cobra.Command{
 		// Ready filter (bd-ihu31)
 		readyFlag, _ := cmd.Flags().GetBool("ready")
 
+		// Query expression (bd query)
+		queryStr, _ := cmd.Flags().GetString("query")
+
+		// Aggregation flags
+		countOnly, _ := cmd.Flags().GetBool("count")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+
 		// Watch mode implies pretty format
 		if watchMode {
 			prettyFormat = true
@@ -523,9 +577,24 @@ var listCmd = &cobra.Command{
 		// Normalize labels: trim, dedupe, remove empty
 		labels = util.NormalizeLabels(labels)
 		labelsAny = util.NormalizeLabels(labelsAny)
+		labelGlobs = util.NormalizeLabels(labelGlobs)
+		notLabels = util.NormalizeLabels(notLabels)
+
+		// Validate patterns up front so both direct and daemon mode report
+		// them the same way
+		for _, pattern := range labelGlobs {
+			if _, err := util.CompileGlob(pattern); err != nil {
+				FatalErrorCode(ErrCodeInvalid, "invalid --label-glob %q: %v", pattern, err)
+			}
+		}
+		if titleRegex != "" {
+			if _, err := regexp.Compile(titleRegex); err != nil {
+				FatalErrorCode(ErrCodeInvalid, "invalid --title-re: %v", err)
+			}
+		}
 
 		// Apply directory-aware label scoping if no labels explicitly provided (GH#541)
-		if len(labels) == 0 && len(labelsAny) == 0 {
+		if len(labels) == 0 && len(labelsAny) == 0 && len(labelGlobs) == 0 {
 			if dirLabels := config.GetDirectoryLabels(); len(dirLabels) > 0 {
 				labelsAny = dirLabels
 			}
@@ -545,6 +614,30 @@ var listCmd = &cobra.Command{
 			Limit: effectiveLimit,
 		}
 
+		// --cursor: fetch one extra row to learn whether another page follows
+		paginate, cursor, after := cursorFlag(cmd)
+		if paginate {
+			if sortBy != "" || watchMode {
+				FatalErrorCode(ErrCodeUsage, "--cursor cannot be combined with --sort or --watch (pages use the default stable order)")
+			}
+			filter.After = after
+			if effectiveLimit > 0 {
+				filter.Limit = effectiveLimit + 1
+			}
+		}
+		var nextCursor string
+
+		// --count: aggregate in the database instead of fetching rows
+		if groupBy != "" && !countOnly {
+			FatalErrorCode(ErrCodeUsage, "--group-by requires --count")
+		}
+		if err := types.ValidateGroupBy(groupBy); err != nil {
+			FatalErrorCode(ErrCodeInvalid, "%v", err)
+		}
+		if countOnly && (paginate || watchMode) {
+			FatalErrorCode(ErrCodeUsage, "--count cannot be combined with --cursor or --watch")
+		}
+
 		// --ready flag: show only open issues (excludes hooked/in_progress/blocked/deferred) (bd-ihu31)
 		if readyFlag {
 			s := types.StatusOpen
@@ -554,8 +647,16 @@ var listCmd = &cobra.Command{
 			filter.Status = &s
 		}
 
-		// Default to non-closed issues unless --all or explicit --status (GH#788)
-		if status == "" && !allFlag && !readyFlag {
+		var queryExpr query.Expr
+		if queryStr != "" {
+			queryExpr = parseQueryFlag(queryStr)
+			filter.Query = queryExpr
+		}
+
+		// Default to non-closed issues unless --all, explicit --status, or a
+		// query that tests status or closed date (GH#788)
+		queryTestsStatus := queryExpr != nil && (query.References(queryExpr, "status") || query.References(queryExpr, "closed"))
+		if status == "" && !allFlag && !readyFlag && !queryTestsStatus {
 			filter.ExcludeStatus = []types.Status{types.StatusClosed}
 		}
 		// Use Changed() to properly handle P0 (priority=0)
@@ -581,6 +682,12 @@ var listCmd = &cobra.Command{
 		if len(labelsAny) > 0 {
 			filter.LabelsAny = labelsAny
 		}
+		if len(labelGlobs) > 0 {
+			filter.LabelGlobs = labelGlobs
+		}
+		if len(notLabels) > 0 {
+			filter.NotLabels = notLabels
+		}
 		if titleSearch != "" {
 			filter.TitleSearch = titleSearch
 		}
@@ -590,6 +697,86 @@ var listCmd = &cobra.Command{
 				filter.IDs = ids
 			}
 		}
+		if securityOnly || minCVSS > 0 {
+			if minCVSS < 0 || minCVSS > 10 {
+				FatalErrorCode(ErrCodeUsage, "--min-cvss must be between 0 and 10")
+			}
+			secIDs, err := securityFilterIDs(rootCtx, minCVSS)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if len(filter.IDs) > 0 {
+				var both []string
+				for _, id := range secIDs {
+					if containsString(filter.IDs, id) {
+						both = append(both, id)
+					}
+				}
+				secIDs = both
+			}
+			// An empty ID list means no ID filter; match nothing instead
+			if len(secIDs) == 0 {
+				secIDs = []string{""}
+			}
+			filter.IDs = secIDs
+		}
+		if ccPerson != "" {
+			ccIDs, err := ccFilterIDs(rootCtx, ccPerson)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if len(filter.IDs) > 0 {
+				var both []string
+				for _, id := range ccIDs {
+					if containsString(filter.IDs, id) {
+						both = append(both, id)
+					}
+				}
+				ccIDs = both
+			}
+			if len(ccIDs) == 0 {
+				ccIDs = []string{""}
+			}
+			filter.IDs = ccIDs
+		}
+		if visibilityLevel != "" {
+			levelIDs, err := visibilityFilterIDs(rootCtx, visibilityLevel)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if len(filter.IDs) > 0 {
+				var both []string
+				for _, id := range levelIDs {
+					if containsString(filter.IDs, id) {
+						both = append(both, id)
+					}
+				}
+				levelIDs = both
+			}
+			if len(levelIDs) == 0 {
+				levelIDs = []string{""}
+			}
+			filter.IDs = levelIDs
+		}
+		if cmd.Flags().Changed("repro") {
+			reproIDs, err := reproFilterIDs(rootCtx, reproText)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if len(filter.IDs) > 0 {
+				var both []string
+				for _, id := range reproIDs {
+					if containsString(filter.IDs, id) {
+						both = append(both, id)
+					}
+				}
+				reproIDs = both
+			}
+			if len(reproIDs) == 0 {
+				reproIDs = []string{""}
+			}
+			filter.IDs = reproIDs
+		}
 
 		// Pattern matching
 		if titleContains != "" {
@@ -601,6 +788,9 @@ var listCmd = &cobra.Command{
 		if notesContains != "" {
 			filter.NotesContains = notesContains
 		}
+		if titleRegex != "" {
+			filter.TitleRegex = titleRegex
+		}
 
 		// Date ranges
 		if createdAfter != "" {
@@ -779,6 +969,8 @@ var listCmd = &cobra.Command{
 				IssueType: issueType,
 				Assignee:  assignee,
 				Limit:     effectiveLimit,
+				Paginate:  paginate,
+				Cursor:    cursor,
 			}
 			if cmd.Flags().Changed("priority") {
 				priorityStr, _ := cmd.Flags().GetString("priority")
@@ -795,6 +987,8 @@ var listCmd = &cobra.Command{
 			if len(labelsAny) > 0 {
 				listArgs.LabelsAny = labelsAny
 			}
+			listArgs.LabelGlobs = filter.LabelGlobs
+			listArgs.NotLabels = filter.NotLabels
 			// Forward title search via Query field (searches title/description/id)
 			if titleSearch != "" {
 				listArgs.Query = titleSearch
@@ -806,6 +1000,7 @@ var listCmd = &cobra.Command{
 			// Pattern matching
 			listArgs.TitleContains = titleContains
 			listArgs.DescriptionContains = descContains
+			listArgs.TitleRegex = titleRegex
 			listArgs.NotesContains = notesContains
 
 			// Date ranges
@@ -876,23 +1071,44 @@ var listCmd = &cobra.Command{
 			}
 			listArgs.Overdue = filter.Overdue
 
+			if queryExpr != nil {
+				listArgs.Expr = queryExpr.String()
+			}
+
 			// Pass through --allow-stale flag for resilient queries (bd-dpkdm)
 			listArgs.AllowStale = allowStale
 
+			listArgs.CountOnly = countOnly
+			listArgs.GroupBy = groupBy
+
 			resp, err := daemonClient.List(listArgs)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 
+			if countOnly {
+				var counts types.IssueCounts
+				if err := json.Unmarshal(resp.Data, &counts); err != nil {
+					fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
+					os.Exit(1)
+				}
+				outputIssueCounts(&counts, groupBy)
+				return
+			}
+
 			if jsonOutput {
 				// For JSON output, preserve the full response with counts
-				var issuesWithCounts []*types.IssueWithCounts
-				if err := json.Unmarshal(resp.Data, &issuesWithCounts); err != nil {
+				// (a types.IssuePage envelope when paginating)
+				var out interface{} = &[]*types.IssueWithCounts{}
+				if paginate {
+					out = &types.IssuePage[*types.IssueWithCounts]{}
+				}
+				if err := json.Unmarshal(resp.Data, out); err != nil {
 					fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
 					os.Exit(1)
 				}
-				outputJSON(issuesWithCounts)
+				outputJSON(out)
 				return
 			}
 
@@ -900,12 +1116,24 @@ var listCmd = &cobra.Command{
 			maybeShowUpgradeNotification()
 
 			var issues []*types.Issue
-			if err := json.Unmarshal(resp.Data, &issues); err != nil {
+			if paginate {
+				var page types.IssuePage[*types.Issue]
+				if err := json.Unmarshal(resp.Data, &page); err != nil {
+					fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
+					os.Exit(1)
+				}
+				issues, nextCursor = page.Issues, page.NextCursor
+			} else if err := json.Unmarshal(resp.Data, &issues); err != nil {
 				fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
 				os.Exit(1)
 			}
 
 			// Apply sorting
+			if sortBy == "votes" {
+				if err := populateListVotes(ctx, issues); err != nil {
+					FatalErrorRespectJSON("%v", err)
+				}
+			}
 			sortIssues(issues, sortBy, reverse)
 
 			// Handle watch mode (GH#654)
@@ -929,12 +1157,25 @@ var listCmd = &cobra.Command{
 					}
 				}
 				displayPrettyListWithDeps(issues, false, allDeps)
-				if effectiveLimit > 0 && len(issues) == effectiveLimit {
-					fmt.Fprintf(os.Stderr, "\nShowing %d issues (use --limit 0 for all)\n", effectiveLimit)
-				}
+				// Show truncation or next-page hint (GH#788)
+				printPageHint(effectiveLimit, len(issues), paginate, nextCursor)
 				return
 			}
 
+			// Linked pull requests come from the local cache; in daemon mode,
+			// read it through a read-only connection
+			var prLines map[string][]string
+			if withPR {
+				if store != nil {
+					prLines = pullRequestLines(ctx, store, issues)
+				} else if dbPath != "" {
+					if roStore, err := sqlite.NewReadOnlyWithTimeout(ctx, dbPath, lockTimeout); err == nil {
+						prLines = pullRequestLines(ctx, roStore, issues)
+						_ = roStore.Close()
+					}
+				}
+			}
+
 			// Build output in buffer for pager support (bd-jdz3)
 			var buf strings.Builder
 			if ui.IsAgentMode() {
@@ -949,11 +1190,13 @@ var listCmd = &cobra.Command{
 				buf.WriteString(fmt.Sprintf("\nFound %d issues:\n\n", len(issues)))
 				for _, issue := range issues {
 					formatIssueLong(&buf, issue, issue.Labels)
+					writePRLines(&buf, prLines[issue.ID])
 				}
 			} else {
 				// Compact format: one line per issue
 				for _, issue := range issues {
 					formatIssueCompact(&buf, issue, issue.Labels)
+					writePRLines(&buf, prLines[issue.ID])
 				}
 			}
 
@@ -964,15 +1207,22 @@ var listCmd = &cobra.Command{
 				}
 			}
 
-			// Show truncation hint if we hit the limit (GH#788)
-			if effectiveLimit > 0 && len(issues) == effectiveLimit {
-				fmt.Fprintf(os.Stderr, "\nShowing %d issues (use --limit 0 for all)\n", effectiveLimit)
-			}
+			// Show truncation or next-page hint (GH#788)
+			printPageHint(effectiveLimit, len(issues), paginate, nextCursor)
 			return
 		}
 
 		// Direct mode
 		// ctx already created above for staleness check
+		if countOnly {
+			counts, err := store.CountIssues(ctx, "", filter, groupBy)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			outputIssueCounts(counts, groupBy)
+			return
+		}
 		issues, err := store.SearchIssues(ctx, "", filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -991,7 +1241,16 @@ var listCmd = &cobra.Command{
 			}
 		}
 
+		if paginate {
+			issues, nextCursor = types.Paginate(issues, effectiveLimit, types.CursorFor)
+		}
+
 		// Apply sorting
+		if sortBy == "votes" {
+			if err := populateListVotes(ctx, issues); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
 		sortIssues(issues, sortBy, reverse)
 
 		// Handle watch mode (GH#654) - must be before other output modes
@@ -1005,10 +1264,8 @@ var listCmd = &cobra.Command{
 			// Load dependencies for tree structure
 			allDeps, _ := store.GetAllDependencyRecords(ctx)
 			displayPrettyListWithDeps(issues, false, allDeps)
-			// Show truncation hint if we hit the limit (GH#788)
-			if effectiveLimit > 0 && len(issues) == effectiveLimit {
-				fmt.Fprintf(os.Stderr, "\nShowing %d issues (use --limit 0 for all)\n", effectiveLimit)
-			}
+			// Show truncation or next-page hint (GH#788)
+			printPageHint(effectiveLimit, len(issues), paginate, nextCursor)
 			return
 		}
 
@@ -1048,6 +1305,10 @@ var listCmd = &cobra.Command{
 					DependentCount:  counts.DependentCount,
 				}
 			}
+			if paginate {
+				outputJSON(types.IssuePage[*types.IssueWithCounts]{Issues: issuesWithCounts, NextCursor: nextCursor})
+				return
+			}
 			outputJSON(issuesWithCounts)
 			return
 		}
@@ -1061,6 +1322,10 @@ var listCmd = &cobra.Command{
 			issueIDs[i] = issue.ID
 		}
 		labelsMap, _ := store.GetLabelsForIssues(ctx, issueIDs)
+		var prLines map[string][]string
+		if withPR {
+			prLines = pullRequestLines(ctx, store, issues)
+		}
 
 		// Build output in buffer for pager support (bd-jdz3)
 		var buf strings.Builder
@@ -1077,12 +1342,14 @@ var listCmd = &cobra.Command{
 			for _, issue := range issues {
 				labels := labelsMap[issue.ID]
 				formatIssueLong(&buf, issue, labels)
+				writePRLines(&buf, prLines[issue.ID])
 			}
 		} else {
 			// Compact format: one line per issue
 			for _, issue := range issues {
 				labels := labelsMap[issue.ID]
 				formatIssueCompact(&buf, issue, labels)
+				writePRLines(&buf, prLines[issue.ID])
 			}
 		}
 
@@ -1093,10 +1360,8 @@ var listCmd = &cobra.Command{
 			}
 		}
 
-		// Show truncation hint if we hit the limit (GH#788)
-		if effectiveLimit > 0 && len(issues) == effectiveLimit {
-			fmt.Fprintf(os.Stderr, "\nShowing %d issues (use --limit 0 for all)\n", effectiveLimit)
-		}
+		// Show truncation or next-page hint (GH#788)
+		printPageHint(effectiveLimit, len(issues), paginate, nextCursor)
 
 		// Show tip after successful list (direct mode only)
 		maybeShowTip(store)
@@ -1110,19 +1375,30 @@ func init() {
 	listCmd.Flags().StringP("type", "t", "", "Filter by type (bug, feature, task, epic, chore, merge-request, molecule, gate, convoy). Aliases: mr→merge-request, feat→feature, mol→molecule")
 	listCmd.Flags().StringSliceP("label", "l", []string{}, "Filter by labels (AND: must have ALL). Can combine with --label-any")
 	listCmd.Flags().StringSlice("label-any", []string{}, "Filter by labels (OR: must have AT LEAST ONE). Can combine with --label")
+	listCmd.Flags().String("component", "", "Filter by component (same as --label component:<name>)")
+	listCmd.Flags().StringSlice("label-glob", []string{}, "Filter by label pattern, e.g. 'area/*' (AND: each must match a label; * ? [...] wildcards)")
+	listCmd.Flags().StringSlice("not-label", []string{}, "Exclude issues with any of these labels")
 	listCmd.Flags().String("title", "", "Filter by title text (case-insensitive substring match)")
 	listCmd.Flags().String("id", "", "Filter by specific issue IDs (comma-separated, e.g., bd-1,bd-5,bd-10)")
 	listCmd.Flags().IntP("limit", "n", 50, "Limit results (default 50, use 0 for unlimited)")
+	addCursorFlag(listCmd)
 	listCmd.Flags().String("format", "", "Output format: 'digraph' (for golang.org/x/tools/cmd/digraph), 'dot' (Graphviz), or Go template")
 	listCmd.Flags().Bool("all", false, "Show all issues including closed (overrides default filter)")
 	listCmd.Flags().Bool("long", false, "Show detailed multi-line output for each issue")
-	listCmd.Flags().String("sort", "", "Sort by field: priority, created, updated, closed, status, id, title, type, assignee")
+	listCmd.Flags().Bool("with-pr", false, "Show the cached status of each issue's linked pull requests (bd pr)")
+	listCmd.Flags().Bool("security", false, "Show only issues with security advisory metadata (bd security)")
+	listCmd.Flags().Float64("min-cvss", 0, "Show only security issues with a CVSS score of at least this (implies --security)")
+	listCmd.Flags().String("repro", "", "Show only issues whose repro metadata (bd repro) contains this text; \"\" matches any repro")
+	listCmd.Flags().String("cc", "", "Show only issues this person or address is cc'd on")
+	listCmd.Flags().String("visibility", "", "Show only public or private issues")
+	listCmd.Flags().String("sort", "", "Sort by field: priority, created, updated, closed, status, id, title, type, assignee, votes")
 	listCmd.Flags().BoolP("reverse", "r", false, "Reverse sort order")
 
 	// Pattern matching
 	listCmd.Flags().String("title-contains", "", "Filter by title substring (case-insensitive)")
 	listCmd.Flags().String("desc-contains", "", "Filter by description substring (case-insensitive)")
 	listCmd.Flags().String("notes-contains", "", "Filter by notes substring (case-insensitive)")
+	listCmd.Flags().String("title-re", "", "Filter by title regular expression (Go syntax; prefix with (?i) to ignore case)")
 
 	// Date ranges
 	listCmd.Flags().String("created-after", "", "Filter issues created after date (YYYY-MM-DD or RFC3339)")
@@ -1166,6 +1442,13 @@ func init() {
 	listCmd.Flags().String("due-before", "", "Filter issues due before date (supports relative: +6h, tomorrow)")
 	listCmd.Flags().Bool("overdue", false, "Show only issues with due_at in the past (not closed)")
 
+	// Aggregation
+	listCmd.Flags().Bool("count", false, "Print only the number of matching issues (counted in the database, no rows fetched)")
+	listCmd.Flags().String("group-by", "", "With --count, count per group: status, priority, type, assignee, or label")
+
+	// Query expression (see 'bd query --help')
+	listCmd.Flags().String("query", "", "Filter by query expression, e.g. 'priority<=1 or label:security' (see 'bd query --help')")
+
 	// Pretty and watch flags (GH#654)
 	listCmd.Flags().Bool("pretty", false, "Display issues in a tree format with status/priority symbols")
 	listCmd.Flags().Bool("tree", false, "Alias for --pretty: hierarchical tree format")
@@ -439,6 +439,42 @@ func TestListQueryCapabilitiesSuite(t *testing.T) {
 			}
 		})
 
+	t.Run("title regex", func(t *testing.T) {
+		results, err := s.SearchIssues(ctx, "", types.IssueFilter{
+			TitleRegex: `^(Add|Update) `,
+		})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(results) != 2 {
+			t.Errorf("Expected 2 results matching title regex, got %d", len(results))
+		}
+	})
+
+	t.Run("label glob", func(t *testing.T) {
+		results, err := s.SearchIssues(ctx, "", types.IssueFilter{
+			LabelGlobs: []string{"s?c*", "*ical"},
+		})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(results) != 1 || results[0].ID != issue1.ID {
+			t.Errorf("Expected only issue1 to match both globs, got %d results", len(results))
+		}
+	})
+
+	t.Run("not label", func(t *testing.T) {
+		results, err := s.SearchIssues(ctx, "", types.IssueFilter{
+			NotLabels: []string{"security", "docs"},
+		})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(results) != 1 || results[0].ID != issue2.ID {
+			t.Errorf("Expected only issue2 without excluded labels, got %d results", len(results))
+		}
+	})
+
 	t.Run("combined filters", func(t *testing.T) {
 			minPrio := 0
 			maxPrio := 2
@@ -801,3 +837,35 @@ func TestListTimeBasedFilters(t *testing.T) {
 		}
 	})
 }
+
+func TestListCount(t *testing.T) {
+	p := newTestProject(t)
+
+	p.Run("create", "Login bug", "-t", "bug", "-l", "api")
+	p.Run("create", "Settings page", "-l", "ui", "--assignee", "alice")
+	p.Run("create", "Cleanup")
+
+	if got := strings.TrimSpace(p.Run("list", "--count", "--label", "api")); got != "1" {
+		t.Errorf("list --count --label api = %q, want 1", got)
+	}
+
+	var counts types.IssueCounts
+	p.RunJSON(&counts, "list", "--count", "--group-by", "assignee", "--limit", "1")
+	if counts.Total != 3 || len(counts.Groups) != 2 || counts.Groups[0] != (types.GroupCount{Group: "(unassigned)", Count: 2}) {
+		t.Errorf("grouped counts = %+v", counts)
+	}
+
+	table := p.Run("list", "--count", "--group-by", "label")
+	for _, want := range []string{"LABEL", "(no labels)", "api", "ui", "TOTAL"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("table missing %q:\n%s", want, table)
+		}
+	}
+
+	if res := p.RunCommand("list", "--group-by", "status"); res.ExitCode() != ErrCodeUsage.ExitCode() {
+		t.Errorf("--group-by without --count exit = %d, want %d", res.ExitCode(), ErrCodeUsage.ExitCode())
+	}
+	if res := p.RunCommand("list", "--count", "--group-by", "color"); res.ExitCode() != ErrCodeInvalid.ExitCode() {
+		t.Errorf("invalid --group-by exit = %d, want %d", res.ExitCode(), ErrCodeInvalid.ExitCode())
+	}
+}
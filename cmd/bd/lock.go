@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/lock"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+var lockCmd = &cobra.Command{
+	Use:     "lock <id>...",
+	GroupID: "issues",
+	Short:   "Lock issues against further changes",
+	Long: `Lock issues so they can't be changed, e.g. while a release branch is cut.
+
+Updates, closes, deletes, comments, labels and dependencies on a locked
+issue are refused with error code E_LOCKED, from the CLI, the daemon and
+bd import alike. Actors listed in lock.admins may still change locked
+issues; when lock.admins is set, only they may lock and unlock.
+
+The lock is the "locked" label and the reason a "Locked: ..." comment, so
+it travels with the issue through git. Find locked issues with
+'bd list --label locked'.`,
+	Example: `  bd lock bd-12 --reason "release freeze"
+  bd lock bd-12 bd-13`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runLockCommand(cmd, args, true)
+	},
+}
+
+var unlockCmd = &cobra.Command{
+	Use:     "unlock <id>...",
+	GroupID: "issues",
+	Short:   "Unlock issues locked with bd lock",
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runLockCommand(cmd, args, false)
+	},
+}
+
+var freezeCmd = &cobra.Command{
+	Use:     "freeze [on|off]",
+	GroupID: "issues",
+	Short:   "Freeze the whole project against changes",
+	Long: `Freeze the project: while on, every create and change is refused with error
+code E_LOCKED, except by actors listed in lock.admins. Without an argument,
+shows whether the project is frozen.
+
+The freeze is freeze.enabled (and freeze.reason) in .beads/config.yaml, so
+committing the file freezes every clone. A running daemon is stopped so it
+restarts with the new setting.`,
+	Example: `  bd freeze on --reason "cutting v1.4"
+  bd freeze
+  bd freeze off`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"on", "off"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			frozen, reason := lock.Frozen()
+			if jsonOutput {
+				outputJSON(map[string]interface{}{"frozen": frozen, "reason": reason})
+				return
+			}
+			switch {
+			case !frozen:
+				fmt.Println("Project is not frozen")
+			case reason != "":
+				fmt.Printf("%s Project is frozen: %s\n", ui.RenderWarn("❄"), reason)
+			default:
+				fmt.Printf("%s Project is frozen\n", ui.RenderWarn("❄"))
+			}
+			return
+		}
+
+		var frozen bool
+		switch args[0] {
+		case "on":
+			frozen = true
+		case "off":
+		default:
+			FatalErrorCode(ErrCodeUsage, "expected on or off, got %q", args[0])
+		}
+		CheckReadonly("freeze")
+		requireLockAdmin("freeze")
+
+		reason, _ := cmd.Flags().GetString("reason")
+		if !frozen {
+			reason = ""
+		}
+		settings := [][2]string{
+			{lock.KeyFreezeEnabled, fmt.Sprintf("%t", frozen)},
+			{lock.KeyFreezeReason, reason},
+		}
+		for _, kv := range settings {
+			if err := config.SetYamlConfig(kv[0], kv[1]); err != nil {
+				FatalErrorRespectJSON("failed to set %s: %v", kv[0], err)
+			}
+		}
+		stopDaemonForLockConfig()
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"frozen": frozen, "reason": reason})
+			return
+		}
+		if frozen {
+			fmt.Printf("%s Project frozen", ui.RenderPass("✓"))
+			if reason != "" {
+				fmt.Printf(": %s", reason)
+			}
+			fmt.Println()
+			fmt.Println("Commit .beads/config.yaml to freeze other clones too.")
+		} else {
+			fmt.Printf("%s Project unfrozen\n", ui.RenderPass("✓"))
+		}
+	},
+}
+
+// runLockCommand locks or unlocks the issues named in args.
+func runLockCommand(cmd *cobra.Command, args []string, locking bool) {
+	name := "unlock"
+	if locking {
+		name = "lock"
+	}
+	CheckReadonly(name)
+	requireLockAdmin(name)
+	if err := ensureDirectMode(name + " requires direct database access"); err != nil {
+		FatalError("%v", err)
+	}
+	reason, _ := cmd.Flags().GetString("reason")
+
+	// The lock itself is a change to the issue, so it bypasses the check
+	ctx := lock.WithBypass(rootCtx)
+	actor := getActor()
+	type lockResult struct {
+		ID     string `json:"id"`
+		Locked bool   `json:"locked"`
+		Reason string `json:"reason,omitempty"`
+	}
+	var results []lockResult
+	changed := false
+	for _, arg := range args {
+		id, err := utils.ResolvePartialID(ctx, store, arg)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		labels, err := store.GetLabels(ctx, id)
+		if err != nil {
+			FatalErrorRespectJSON("failed to get labels of %s: %v", id, err)
+		}
+		wasLocked := false
+		for _, l := range labels {
+			if l == lock.Label {
+				wasLocked = true
+			}
+		}
+
+		switch {
+		case locking && (!wasLocked || reason != ""):
+			if _, err := store.AddIssueComment(ctx, id, actor, strings.TrimSpace(lock.CommentPrefix+reason)); err != nil {
+				FatalErrorRespectJSON("failed to lock %s: %v", id, err)
+			}
+			if err := store.AddLabel(ctx, id, lock.Label, actor); err != nil {
+				FatalErrorRespectJSON("failed to lock %s: %v", id, err)
+			}
+			changed = true
+		case !locking && wasLocked:
+			if err := store.RemoveLabel(ctx, id, lock.Label, actor); err != nil {
+				FatalErrorRespectJSON("failed to unlock %s: %v", id, err)
+			}
+			changed = true
+		}
+		results = append(results, lockResult{ID: id, Locked: locking, Reason: reason})
+
+		if jsonOutput {
+			continue
+		}
+		switch {
+		case locking == wasLocked && !(locking && reason != ""):
+			state := "not locked"
+			if wasLocked {
+				state = "already locked"
+			}
+			fmt.Printf("%s is %s\n", id, state)
+		case locking && reason != "":
+			fmt.Printf("%s Locked %s: %s\n", ui.RenderPass("✓"), id, reason)
+		case locking:
+			fmt.Printf("%s Locked %s\n", ui.RenderPass("✓"), id)
+		default:
+			fmt.Printf("%s Unlocked %s\n", ui.RenderPass("✓"), id)
+		}
+	}
+	if changed {
+		markDirtyAndScheduleFlush()
+	}
+	if jsonOutput {
+		outputJSON(results)
+	}
+}
+
+// requireLockAdmin stops a non-admin from locking, unlocking or freezing
+// once lock.admins is set.
+func requireLockAdmin(operation string) {
+	if len(lock.Admins()) == 0 || lock.IsAdmin(getActor()) {
+		return
+	}
+	FatalErrorWithHint(
+		fmt.Sprintf("%s is restricted to lock.admins and %q is not listed", operation, getActor()),
+		"ask one of: "+strings.Join(lock.Admins(), ", "))
+}
+
+// stopDaemonForLockConfig stops a running daemon after the freeze or the
+// admin list changes in config.yaml. The daemon reads config only at
+// startup; the next command starts it again with the new settings.
+func stopDaemonForLockConfig() {
+	if pidFile, err := getPIDFilePath(); err == nil && isDaemonRunningQuiet(pidFile) {
+		stopDaemonQuiet(pidFile)
+		if !jsonOutput {
+			fmt.Fprintln(os.Stderr, "Stopped the daemon so it picks up the change")
+		}
+	}
+}
+
+// isLockConfigKey reports whether key configures locks or the freeze.
+func isLockConfigKey(key string) bool {
+	return strings.HasPrefix(key, "freeze.") || strings.HasPrefix(key, "lock.")
+}
+
+func init() {
+	lockCmd.Flags().String("reason", "", "Why the issue is locked")
+	freezeCmd.Flags().String("reason", "", "Why the project is frozen")
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(unlockCmd)
+	rootCmd.AddCommand(freezeCmd)
+}
@@ -5,14 +5,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"os/signal"
 	"path/filepath"
 	"runtime/pprof"
 	"runtime/trace"
 	"slices"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -26,6 +24,7 @@ import (
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/factory"
 	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/ui"
 	"github.com/steveyegge/beads/internal/utils"
 )
 
@@ -78,7 +77,9 @@ var (
 	noDb           bool          // Use --no-db mode: load from JSONL, write back after each command
 	readonlyMode   bool          // Read-only mode: block write operations (for worker sandboxes)
 	lockTimeout    time.Duration // SQLite busy_timeout (default 30s, 0 = fail immediately)
+	commandTimeout time.Duration // --timeout: cancel rootCtx after this long (0 = no limit)
 	profileEnabled bool
+	configProfile  string // --config-profile: named profile from the config files
 	profileFile    *os.File
 	traceFile      *os.File
 	verboseFlag    bool // Enable verbose/debug output
@@ -110,23 +111,13 @@ func isReadOnlyCommand(cmdName string) bool {
 }
 
 // getActorWithGit returns the actor for audit trails with git config fallback.
-// Priority: --actor flag > BD_ACTOR env > BEADS_ACTOR env > git config user.name > $USER > "unknown"
+// Priority: --actor flag > BD_ACTOR env > BEADS_ACTOR env > agent session > git config user.name > $USER > "unknown"
 // This provides a sensible default for developers: their git identity is used unless
 // explicitly overridden
 func getActorWithGit() string {
-	// If actor is already set (from --actor flag), use it
-	if actor != "" {
-		return actor
-	}
-
-	// Check BD_ACTOR env var (primary env override)
-	if bdActor := os.Getenv("BD_ACTOR"); bdActor != "" {
-		return bdActor
-	}
-
-	// Check BEADS_ACTOR env var (alias for MCP/integration compatibility)
-	if beadsActor := os.Getenv("BEADS_ACTOR"); beadsActor != "" {
-		return beadsActor
+	// Explicit flag or env override wins
+	if explicit := getActorWithoutGit(); explicit != "" {
+		return explicit
 	}
 
 	// Try git config user.name - the natural default for a git-native tool
@@ -144,6 +135,29 @@ func getActorWithGit() string {
 	return "unknown"
 }
 
+// getActorWithoutGit returns the actor from the --actor flag, BD_ACTOR,
+// BEADS_ACTOR or the active agent session (bd session start) without
+// spawning git. Returns "" when none is set.
+func getActorWithoutGit() string {
+	// If actor is already set (from --actor flag), use it
+	if actor != "" {
+		return actor
+	}
+
+	// Check BD_ACTOR env var (primary env override)
+	if bdActor := os.Getenv("BD_ACTOR"); bdActor != "" {
+		return bdActor
+	}
+
+	// Check BEADS_ACTOR env var (alias for MCP/integration compatibility)
+	if beadsActor := os.Getenv("BEADS_ACTOR"); beadsActor != "" {
+		return beadsActor
+	}
+
+	// An open agent session runs commands as its agent
+	return sessionAgent()
+}
+
 // getOwner returns the human owner for CV attribution.
 // Priority: GIT_AUTHOR_EMAIL env > git config user.email > "" (empty)
 // This is the foundation for HOP CV (curriculum vitae) chains per Decision 008.
@@ -189,13 +203,20 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&noDb, "no-db", false, "Use no-db mode: load from JSONL, no SQLite")
 	rootCmd.PersistentFlags().BoolVar(&readonlyMode, "readonly", false, "Read-only mode: block write operations (for worker sandboxes)")
 	rootCmd.PersistentFlags().DurationVar(&lockTimeout, "lock-timeout", 30*time.Second, "SQLite busy timeout (0 = fail immediately if locked)")
-	rootCmd.PersistentFlags().BoolVar(&profileEnabled, "profile", false, "Generate CPU profile for performance analysis")
+	rootCmd.PersistentFlags().DurationVar(&commandTimeout, "timeout", 0, "Cancel the command after this long, e.g. 5s (0 = no limit)")
+	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "Don't draw progress bars for long operations (import, export, sync, compact)")
+	rootCmd.PersistentFlags().BoolVar(&accessibleMode, "accessible", false, "Screen-reader-friendly output: no color, symbols, tables or trees")
+	rootCmd.PersistentFlags().StringVar(&configProfile, "config-profile", "", "Apply a named profile from the config files (default: $BD_CONFIG_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&profileEnabled, "profile", false, "Print a startup timing breakdown and write CPU profile and trace files")
 	rootCmd.PersistentFlags().BoolVarP(&verboseFlag, "verbose", "v", false, "Enable verbose/debug output")
 	rootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "Suppress non-essential output (errors only)")
 
 	// Add --version flag to root command (same behavior as version subcommand)
 	rootCmd.Flags().BoolP("version", "V", false, "Print version information")
 
+	// Hidden --<key> flags for every config key without a flag of its own
+	registerConfigFlags(rootCmd)
+
 	// Command groups for organized help output (Tufte-inspired)
 	rootCmd.AddGroup(&cobra.Group{ID: "issues", Title: "Working With Issues:"})
 	rootCmd.AddGroup(&cobra.Group{ID: "views", Title: "Views & Reports:"})
@@ -227,16 +248,40 @@ var rootCmd = &cobra.Command{
 		_ = cmd.Help()
 	},
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Start the phase timer first so --profile can attribute startup cost
+		if profileEnabled {
+			startupTimer = newPhaseTimer(processStart)
+			startupTimer.mark("init")
+		}
+
 		// Initialize CommandContext to hold runtime state (replaces scattered globals)
 		initCommandContext()
 
 		// Set up signal-aware context for graceful cancellation
-		rootCtx, rootCancel = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		rootCtx, rootCancel = newRootContext(cmd)
 
 		// Apply verbosity flags early (before any output)
 		debug.SetVerbose(verboseFlag)
 		debug.SetQuiet(quietFlag)
 
+		// Config keys given as flags (--jira.url ...) win over every source
+		applyConfigFlags(cmd)
+
+		// Layer the named config profile before reading any setting
+		if !cmd.Flags().Changed("config-profile") {
+			configProfile = os.Getenv(config.ProfileEnv)
+		}
+		if configProfile != "" {
+			if err := config.UseProfile(configProfile); err != nil {
+				FatalErrorWithHint(err.Error(), "profiles are defined under 'profiles:' in config.yaml; see docs/CONFIG.md")
+			}
+		}
+		if !quietFlag {
+			for _, warning := range config.DeprecationWarnings() {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+			}
+		}
+
 		// Apply viper configuration if flags weren't explicitly set
 		// Priority: flags > viper (config file + env vars) > defaults
 		// Do this BEFORE early-return so init/version/help respect config
@@ -296,6 +341,15 @@ var rootCmd = &cobra.Command{
 				WasSet bool
 			}{readonlyMode, true}
 		}
+		if !cmd.Flags().Changed("no-progress") {
+			noProgress = config.GetBool("no-progress")
+		}
+		if !cmd.Flags().Changed("accessible") {
+			accessibleMode = config.GetBool("accessible")
+		}
+		ui.SetAccessible(accessibleMode)
+		applyHTTPConfig()
+		applyLanguage()
 		if !cmd.Flags().Changed("lock-timeout") {
 			lockTimeout = config.GetDuration("lock-timeout")
 		} else {
@@ -328,6 +382,7 @@ var rootCmd = &cobra.Command{
 				config.LogOverride(override)
 			}
 		}
+		startupTimer.mark("config")
 
 		// GH#1093: Check noDbCommands BEFORE expensive operations (ensureForkProtection,
 		// signalOrchestratorActivity) to avoid spawning git subprocesses for simple commands
@@ -336,8 +391,10 @@ var rootCmd = &cobra.Command{
 			cmdDaemon,
 			"__complete",       // Cobra's internal completion command (shell completions work without db)
 			"__completeNoDesc", // Cobra's completion without descriptions (used by fish)
+			"auth",
 			"bash",
 			"completion",
+			"concurrency",
 			"doctor",
 			"fish",
 			"help",
@@ -348,11 +405,14 @@ var rootCmd = &cobra.Command{
 			"onboard",
 			"powershell",
 			"prime",
+			"prompt",
 			"quickstart",
 			"repair",
 			"resolve-conflicts",
+			"schema",
 			"setup",
 			"version",
+			"web",
 			"zsh",
 		}
 		// Check both the command name and parent command name for subcommands
@@ -381,8 +441,16 @@ var rootCmd = &cobra.Command{
 		// GH#1093: Moved after noDbCommands check to avoid git subprocesses for simple commands
 		defer signalOrchestratorActivity()
 
+		// Read-only commands never write issues.jsonl, so they don't need fork
+		// protection, a git-derived actor, or a daemon of their own. Skipping
+		// those keeps cold reads like "bd list" free of git subprocesses.
+		readOnlyCmd := isReadOnlyCommand(cmd.Name())
+
 		// Protect forks from accidentally committing upstream issue database
-		ensureForkProtection()
+		if !readOnlyCmd {
+			ensureForkProtection()
+			startupTimer.mark("fork-check")
+		}
 
 		// Performance profiling setup
 		// When --profile is enabled, force direct mode to capture actual database operations
@@ -535,22 +603,31 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
+		startupTimer.mark("discover")
+
 		// Set actor for audit trail
-		actor = getActorWithGit()
+		// Read-only commands don't record events, so skip the git config lookup
+		if readOnlyCmd {
+			actor = getActorWithoutGit()
+		} else {
+			actor = getActorWithGit()
+		}
 
 		// Track bd version changes
 		// Best-effort tracking - failures are silent
 		trackBdVersion()
+		startupTimer.mark("actor")
 
 		// Initialize daemon status
+		// AutoStartEnabled is resolved only when a daemon connection is attempted,
+		// since it may need git worktree detection.
 		socketPath := getSocketPath()
 		daemonStatus = DaemonStatus{
-			Mode:             "direct",
-			Connected:        false,
-			Degraded:         true,
-			SocketPath:       socketPath,
-			AutoStartEnabled: shouldAutoStartDaemon(),
-			FallbackReason:   FallbackNone,
+			Mode:           "direct",
+			Connected:      false,
+			Degraded:       true,
+			SocketPath:     socketPath,
+			FallbackReason: FallbackNone,
 		}
 
 		// Doctor should always run in direct mode. It's specifically used to diagnose and
@@ -583,6 +660,10 @@ var rootCmd = &cobra.Command{
 			daemonStatus.FallbackReason = FallbackWorktreeSafety
 			debug.Logf("git worktree detected without sync-branch, using direct mode for safety")
 		} else {
+			// Read-only commands use a running daemon but never start one:
+			// a direct read-only open is cheaper than spawning and waiting.
+			daemonStatus.AutoStartEnabled = !readOnlyCmd && shouldAutoStartDaemon()
+
 			// Attempt daemon connection
 			client, err := rpc.TryConnect(socketPath)
 			if err == nil && client != nil {
@@ -597,40 +678,54 @@ var rootCmd = &cobra.Command{
 				if healthErr == nil && health.Status == statusHealthy {
 					// Check version compatibility
 					if !health.Compatible {
-						debug.Logf("daemon version mismatch (daemon: %s, client: %s), restarting daemon",
-							health.Version, Version)
 						_ = client.Close()
-
-						// Kill old daemon and restart with new version
-						if restartDaemonForVersionMismatch() {
-							// Retry connection after restart
-							client, err = rpc.TryConnect(socketPath)
-							if err == nil && client != nil {
-								if dbPath != "" {
-									absDBPath, _ := filepath.Abs(dbPath)
-									client.SetDatabasePath(absDBPath)
-								}
-								health, healthErr = client.Health()
-								if healthErr == nil && health.Status == statusHealthy {
-									client.SetActor(actor)
-									daemonClient = client
-									daemonStatus.Mode = cmdDaemon
-									daemonStatus.Connected = true
-									daemonStatus.Degraded = false
-									daemonStatus.Health = health.Status
-									debug.Logf("connected to restarted daemon (version: %s)", health.Version)
-									warnWorktreeDaemon(dbPath)
-									return
+						mismatch := health
+						upgrade := daemonUpgradeSide(mismatch)
+						restartFailed := false
+
+						// Restart an outdated daemon with this binary; a newer
+						// daemon is left alone rather than downgraded
+						if upgrade == rpc.UpgradeDaemon && config.GetBool("daemon.auto_upgrade") {
+							debug.Logf("daemon version mismatch (daemon: %s, client: %s), restarting daemon",
+								health.Version, Version)
+							if restartDaemonForVersionMismatch() {
+								// Retry connection after restart
+								client, err = rpc.TryConnect(socketPath)
+								if err == nil && client != nil {
+									if dbPath != "" {
+										absDBPath, _ := filepath.Abs(dbPath)
+										client.SetDatabasePath(absDBPath)
+									}
+									health, healthErr = client.Health()
+									if healthErr == nil && health.Status == statusHealthy && health.Compatible {
+										client.SetActor(actor)
+										client.SetContext(rootCtx)
+										daemonClient = client
+										daemonStatus.Mode = cmdDaemon
+										daemonStatus.Connected = true
+										daemonStatus.Degraded = false
+										daemonStatus.Health = health.Status
+										debug.Logf("connected to restarted daemon (version: %s)", health.Version)
+										warnWorktreeDaemon(dbPath)
+										startupTimer.mark("daemon")
+										return
+									}
+									_ = client.Close()
 								}
 							}
+							restartFailed = true
+						}
+						// Fall through to direct mode; auto-start would only
+						// find the same daemon again
+						daemonStatus.FallbackReason = FallbackVersionMismatch
+						daemonStatus.Detail = versionMismatchDetail(mismatch, upgrade, restartFailed)
+						if !quietFlag {
+							fmt.Fprintf(os.Stderr, "Warning: %s (using direct mode)\n", daemonStatus.Detail)
 						}
-						// If restart failed, fall through to direct mode
-						daemonStatus.FallbackReason = FallbackHealthFailed
-						daemonStatus.Detail = fmt.Sprintf("version mismatch (daemon: %s, client: %s) and restart failed",
-							health.Version, Version)
 					} else {
 						// Daemon is healthy and compatible - use it
 						client.SetActor(actor)
+						client.SetContext(rootCtx)
 						daemonClient = client
 						daemonStatus.Mode = cmdDaemon
 						daemonStatus.Connected = true
@@ -639,6 +734,7 @@ var rootCmd = &cobra.Command{
 						debug.Logf("connected to daemon at %s (health: %s)", socketPath, health.Status)
 						// Warn if using daemon with git worktrees
 						warnWorktreeDaemon(dbPath)
+						startupTimer.mark("daemon")
 						return // Skip direct storage initialization
 					}
 				} else {
@@ -664,7 +760,7 @@ var rootCmd = &cobra.Command{
 			}
 
 			// Daemon not running or unhealthy - try auto-start if enabled
-			if daemonStatus.AutoStartEnabled {
+			if daemonStatus.AutoStartEnabled && daemonStatus.FallbackReason != FallbackVersionMismatch {
 				daemonStatus.AutoStartAttempted = true
 				debug.Logf("attempting to auto-start daemon")
 				startTime := time.Now()
@@ -682,6 +778,7 @@ var rootCmd = &cobra.Command{
 						health, healthErr := client.Health()
 						if healthErr == nil && health.Status == statusHealthy {
 							client.SetActor(actor)
+							client.SetContext(rootCtx)
 							daemonClient = client
 							daemonStatus.Mode = cmdDaemon
 							daemonStatus.Connected = true
@@ -693,6 +790,7 @@ var rootCmd = &cobra.Command{
 							debug.Logf("auto-start succeeded; connected at %s in %dms", socketPath, elapsed)
 							// Warn if using daemon with git worktrees
 							warnWorktreeDaemon(dbPath)
+							startupTimer.mark("daemon")
 							return // Skip direct storage initialization
 						} else {
 							// Auto-started daemon is unhealthy
@@ -728,6 +826,9 @@ var rootCmd = &cobra.Command{
 					daemonStatus.FallbackReason = FallbackAutoStartFailed
 					debug.Logf("auto-start failed; falling back to direct mode")
 				}
+			} else if readOnlyCmd {
+				// Preserve the connect failure reason; nothing was attempted
+				debug.Logf("auto-start skipped for read-only command %s", cmd.Name())
 			} else {
 				// Auto-start disabled - preserve the actual failure reason
 				// Don't override connect_failed or health_failed with auto_start_disabled
@@ -742,11 +843,12 @@ var rootCmd = &cobra.Command{
 
 			debug.Logf("using direct mode (reason: %s)", daemonStatus.FallbackReason)
 		}
+		startupTimer.mark("daemon")
 
 		// Check if this is a read-only command (GH#804)
 		// Read-only commands open SQLite in read-only mode to avoid modifying
 		// the database file (which breaks file watchers).
-		useReadOnly := isReadOnlyCommand(cmd.Name())
+		useReadOnly := readOnlyCmd
 
 		// Auto-migrate database on version bump
 		// Skip for read-only commands - they can't write anyway
@@ -754,6 +856,7 @@ var rootCmd = &cobra.Command{
 		// This ensures: 1) no daemon has DB open, 2) we don't open DB twice
 		if !useReadOnly {
 			autoMigrateOnVersionBump(dbPath)
+			startupTimer.mark("migrate")
 		}
 
 		// Fall back to direct storage access
@@ -796,6 +899,8 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		startupTimer.mark("open-store")
+
 		// Mark store as active for flush goroutine safety
 		storeMutex.Lock()
 		storeActive = true
@@ -817,6 +922,7 @@ var rootCmd = &cobra.Command{
 		if dbPath != "" {
 			beadsDir := filepath.Dir(dbPath)
 			hookRunner = hooks.NewRunner(filepath.Join(beadsDir, "hooks"))
+			hookRunner.SetPrepare(prepareHookIssue)
 		}
 
 		// Warn if multiple databases detected in directory hierarchy
@@ -842,11 +948,13 @@ var rootCmd = &cobra.Command{
 				autoImportIfNewer()
 			}
 		}
+		startupTimer.mark("auto-import")
 
 		// Load molecule templates from hierarchical catalog locations
 		// Templates are loaded after auto-import to ensure the database is up-to-date.
 		// Skip for import command to avoid conflicts during import operations.
-		if cmd.Name() != "import" && store != nil {
+		// Skip for read-only stores - the template writes would fail anyway.
+		if cmd.Name() != "import" && store != nil && (!useReadOnly || needsBootstrap) {
 			beadsDir := filepath.Dir(dbPath)
 			loader := molecules.NewLoader(store)
 			if result, err := loader.LoadAll(rootCtx, beadsDir); err != nil {
@@ -854,6 +962,7 @@ var rootCmd = &cobra.Command{
 			} else if result.Loaded > 0 {
 				debug.Logf("loaded %d molecules from %v", result.Loaded, result.Sources)
 			}
+			startupTimer.mark("molecules")
 		}
 
 		// Tips (including sync conflict proactive checks) are shown via maybeShowTip()
@@ -863,6 +972,13 @@ var rootCmd = &cobra.Command{
 		syncCommandContext()
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		// Print the --profile breakdown once cleanup below has finished
+		startupTimer.mark("command")
+		defer printStartupProfile(os.Stderr, cmd.Name())
+
+		// Log successful write commands to the active agent session
+		recordSessionCommand(cmd, args)
+
 		// Handle --no-db mode: write memory storage back to JSONL
 		if noDb {
 			if store != nil {
@@ -932,6 +1048,6 @@ var rootCmd = &cobra.Command{
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitCodeForExecuteError(err))
 	}
 }
@@ -17,7 +17,7 @@ type DaemonStatus struct {
 	AutoStartEnabled   bool   `json:"auto_start_enabled"`
 	AutoStartAttempted bool   `json:"auto_start_attempted"`
 	AutoStartSucceeded bool   `json:"auto_start_succeeded"`
-	FallbackReason     string `json:"fallback_reason,omitempty"` // "none","flag_no_daemon","connect_failed","health_failed","auto_start_disabled","auto_start_failed"
+	FallbackReason     string `json:"fallback_reason,omitempty"` // "none","flag_no_daemon","connect_failed","health_failed","version_mismatch","auto_start_disabled","auto_start_failed"
 	Detail             string `json:"detail,omitempty"`          // short diagnostic
 	Health             string `json:"health,omitempty"`          // "healthy","degraded","unhealthy"
 }
@@ -36,6 +36,7 @@ const (
 	FallbackAutoStartFailed   = "auto_start_failed"
 	FallbackDaemonUnsupported = "daemon_unsupported"
 	FallbackWispOperation     = "wisp_operation"
+	FallbackVersionMismatch   = "version_mismatch"
 )
 
 // Command group IDs for help organization
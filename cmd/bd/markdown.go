@@ -351,6 +351,7 @@ func createIssuesFromMarkdown(_ *cobra.Command, filepath string) {
 			Priority:           template.Priority,
 			IssueType:          template.IssueType,
 			Assignee:           template.Assignee,
+			Labels:             template.Labels, // Added below; set so the validation rules see them
 		}
 
 		if err := store.CreateIssue(ctx, issue, actor); err != nil {
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// milestoneLabelPrefix marks issues that belong to a milestone
+// (e.g. "milestone:v1.2"). An epic can also act as a milestone, in which
+// case its descendants are the milestone's issues.
+const milestoneLabelPrefix = "milestone:"
+
+// milestoneLabel returns the label used to tag issues with a milestone.
+func milestoneLabel(name string) string {
+	return milestoneLabelPrefix + name
+}
+
+// loadMilestoneIssues returns all issues (including closed ones) in a milestone.
+// If name resolves to an existing issue, that issue is returned as the root
+// and the list holds its descendants only; otherwise the root is nil and
+// the list holds the issues labeled milestone:<name>.
+func loadMilestoneIssues(ctx context.Context, name string) (*types.Issue, []*types.Issue, error) {
+	name = strings.TrimPrefix(name, milestoneLabelPrefix)
+	if id, err := utils.ResolvePartialID(ctx, store, name); err == nil {
+		root, err := store.GetIssue(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if root != nil {
+			descendants, err := collectDescendants(ctx, id)
+			if err != nil {
+				return nil, nil, err
+			}
+			return root, descendants, nil
+		}
+	}
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{
+		Labels: []string{milestoneLabel(name)},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(issues) == 0 {
+		return nil, nil, fmt.Errorf("milestone %q not found (no issue with that ID and no issues labeled %s)", name, milestoneLabel(name))
+	}
+	return nil, issues, nil
+}
+
+// collectDescendants walks parent-child links below rootID (breadth-first).
+func collectDescendants(ctx context.Context, rootID string) ([]*types.Issue, error) {
+	var result []*types.Issue
+	seen := map[string]bool{rootID: true}
+	queue := []string{rootID}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		children, err := store.SearchIssues(ctx, "", types.IssueFilter{ParentID: &parent})
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			result = append(result, child)
+			queue = append(queue, child.ID)
+		}
+	}
+	return result, nil
+}
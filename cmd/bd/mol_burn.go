@@ -404,7 +404,7 @@ func burnWisps(ctx context.Context, s interface{}, ids []string) (*BurnResult, e
 	}
 
 	for _, id := range ids {
-		if err := sqliteStore.DeleteIssue(ctx, id); err != nil {
+		if err := sqliteStore.DeleteIssue(ctx, id, actor); err != nil {
 			// Log but continue - try to delete as many as possible
 			fmt.Fprintf(os.Stderr, "Warning: failed to delete %s: %v\n", id, err)
 			continue
@@ -303,7 +303,7 @@ func deleteWispChildren(ctx context.Context, s storage.Storage, ids []string) (i
 	deleted := 0
 	var lastErr error
 	for _, id := range ids {
-		if err := d.DeleteIssue(ctx, id); err != nil {
+		if err := d.DeleteIssue(ctx, id, actor); err != nil {
 			lastErr = err
 			continue
 		}
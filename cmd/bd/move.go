@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/routing"
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
@@ -15,31 +19,43 @@ import (
 )
 
 var moveCmd = &cobra.Command{
-	Use:     "move <issue-id> --to <rig|prefix>",
+	Use:     "move <issue-id> --to <rig|prefix|project|path>",
 	GroupID: "issues",
-	Short:   "Move an issue to a different rig with dependency remapping",
-	Long: `Move an issue from one rig to another, updating dependencies.
+	Short:   "Move an issue to a different rig or database with dependency remapping",
+	Long: `Move an issue from one beads database to another, updating dependencies.
 
 This command:
-1. Creates a new issue in the target rig with the same content
-2. Updates dependencies that reference the old ID (see below)
-3. Closes the source issue with a redirect note
-
-The target rig can be specified as:
+1. Creates a new issue in the target database with the same content
+2. Copies labels, comments (with their original authors and timestamps)
+   and attachments under .beads/attachments/<id>/
+3. Recreates the issue's own dependencies where the target can resolve them
+4. Updates dependencies that reference the old ID (see below)
+5. Hands the external ref (e.g. gh-123) over to the new issue
+6. Leaves a redirect behind: the source is closed with "Moved to <new-id>",
+   or turned into a tombstone with --tombstone
+
+The target can be specified as:
   - A rig name: beads, gastown
   - A prefix: bd-, gt-
   - A prefix without hyphen: bd, gt
+  - A project name from external_projects in config.yaml
+  - A path to another project, or to its .beads directory
 
-Dependency handling for cross-rig moves:
+Dependency handling for cross-database moves:
   - Issues that depend ON the moved issue: updated to external refs
-  - Issues that the moved issue DEPENDS ON: removed (recreate manually in target)
+  - Issues that the moved issue DEPENDS ON: recreated in the target when the
+    target can resolve them (external refs into the target become local
+    dependencies, other external refs carry over), otherwise removed
+    (recreate manually in target)
 
-Note: Labels are copied. Comments and event history are not transferred.
+Note: Event history is not transferred.
 
 Examples:
-  bd move hq-c21fj --to beads     # Move to beads by rig name
-  bd move hq-q3tki --to gt-       # Move to gastown by prefix
-  bd move hq-1h2to --to gt        # Move to gastown (prefix without hyphen)`,
+  bd move hq-c21fj --to beads              # Move to beads by rig name
+  bd move hq-q3tki --to gt-                # Move to gastown by prefix
+  bd move hq-1h2to --to gt                 # Move to gastown (prefix without hyphen)
+  bd move bd-42 --to ../api                # Move to the project at ../api
+  bd move bd-42 --to api --tombstone       # Leave a tombstone instead of a closed issue`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		CheckReadonly("move")
@@ -47,11 +63,18 @@ Examples:
 		sourceID := args[0]
 		targetRig, _ := cmd.Flags().GetString("to")
 		if targetRig == "" {
-			FatalError("--to flag is required. Specify target rig (e.g., --to beads, --to gt-)")
+			FatalError("--to flag is required. Specify target rig or project (e.g., --to beads, --to gt-, --to ../api)")
 		}
 
 		keepOpen, _ := cmd.Flags().GetBool("keep-open")
 		skipDeps, _ := cmd.Flags().GetBool("skip-deps")
+		tombstone, _ := cmd.Flags().GetBool("tombstone")
+		if tombstone && keepOpen {
+			FatalError("--tombstone and --keep-open are mutually exclusive")
+		}
+		if tombstone && skipDeps {
+			FatalError("--tombstone needs dependency remapping; drop --skip-deps")
+		}
 
 		ctx := rootCtx
 
@@ -79,37 +102,31 @@ Examples:
 			fmt.Fprintf(os.Stderr, "%s Source issue %s is ephemeral (wisp). Moving ephemeral issues may not be appropriate.\n", ui.RenderWarn("⚠"), resolvedSourceID)
 		}
 
-		// Step 2: Find the town-level beads directory
-		townBeadsDir, err := findTownBeadsDir()
-		if err != nil {
-			FatalError("cannot move: %v", err)
-		}
-
-		// Step 3: Resolve the target rig's beads directory
-		targetBeadsDir, targetPrefix, err := routing.ResolveBeadsDirForRig(targetRig, townBeadsDir)
+		// Step 2: Resolve the target database
+		targetBeadsDir, targetPrefix, err := resolveMoveTarget(targetRig)
 		if err != nil {
 			FatalError("%v", err)
 		}
 
-		// Check we're not moving to the same rig
+		// Check we're not moving within the same database
 		sourcePrefix := routing.ExtractPrefix(resolvedSourceID)
-		if sourcePrefix == targetPrefix {
-			FatalError("source issue %s is already in rig %q", resolvedSourceID, targetRig)
+		targetDBPath := filepath.Join(targetBeadsDir, "beads.db")
+		if sourcePrefix == targetPrefix || sameFile(storeDBPath(sourceStore), targetDBPath) {
+			FatalError("source issue %s is already in %q", resolvedSourceID, targetRig)
 		}
 
-		// Step 4: Open storage for the target rig
-		targetDBPath := filepath.Join(targetBeadsDir, "beads.db")
+		// Step 3: Open storage for the target database
 		targetStore, err := sqlite.New(ctx, targetDBPath)
 		if err != nil {
-			FatalError("failed to open target rig database: %v", err)
+			FatalError("failed to open target database: %v", err)
 		}
 		defer func() {
 			if err := targetStore.Close(); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to close target rig database: %v\n", err)
+				fmt.Fprintf(os.Stderr, "warning: failed to close target database: %v\n", err)
 			}
 		}()
 
-		// Step 5: Create the new issue in target rig (copy all fields)
+		// Step 4: Create the new issue in target database (copy all fields)
 		newIssue := &types.Issue{
 			// Don't copy ID - let target rig generate new one
 			Title:              sourceIssue.Title,
@@ -140,12 +157,12 @@ Examples:
 		newIssue.Description += fmt.Sprintf("(Moved from %s)", resolvedSourceID)
 
 		if err := targetStore.CreateIssue(ctx, newIssue, actor); err != nil {
-			FatalError("failed to create issue in target rig: %v", err)
+			FatalError("failed to create issue in target database: %v", err)
 		}
 
 		newID := newIssue.ID
 
-		// Step 6: Copy labels if any
+		// Step 5: Copy labels if any
 		labels, err := sourceStore.GetLabels(ctx, resolvedSourceID)
 		if err == nil && len(labels) > 0 {
 			for _, label := range labels {
@@ -155,42 +172,84 @@ Examples:
 			}
 		}
 
-		// Step 7: Remap dependencies in the source store
-		// targetRig is used to create external references for cross-rig moves
-		var depsRemapped int
+		// Step 6: Move attachments, then copy comments pointing at their new home
+		attachmentsMoved, err := moveAttachments(filepath.Dir(storeDBPath(sourceStore)), targetBeadsDir, resolvedSourceID, newID)
+		if err != nil {
+			WarnError("failed to move attachments: %v", err)
+		}
+		commentsCopied, err := copyComments(ctx, sourceStore, targetStore, resolvedSourceID, newID)
+		if err != nil {
+			WarnError("failed to copy comments: %v", err)
+		}
+
+		// Step 7: Recreate the issue's own dependencies the target can resolve,
+		// then remap dependencies in the source store. targetRig is used to
+		// create external references for cross-database moves
+		var depsCopied, depsRemapped int
 		if !skipDeps {
+			depsCopied, err = copyResolvableDependencies(ctx, sourceStore, targetStore, resolvedSourceID, newID, actor)
+			if err != nil {
+				WarnError("failed to copy some dependencies: %v", err)
+			}
 			depsRemapped, err = remapDependencies(ctx, sourceStore, resolvedSourceID, newID, targetRig, actor)
 			if err != nil {
 				WarnError("failed to remap some dependencies: %v", err)
 			}
 		}
 
-		// Step 8: Close the source issue (unless --keep-open)
+		// Step 8: The new issue owns the external ref now, so trackers that
+		// sync by it update the moved issue rather than the redirect
+		if sourceIssue.ExternalRef != nil && !keepOpen {
+			if err := sourceStore.UpdateIssue(ctx, resolvedSourceID, map[string]interface{}{"external_ref": nil}, actor); err != nil {
+				WarnError("failed to clear external ref on source issue: %v", err)
+			}
+		}
+
+		// Step 9: Leave a redirect behind (unless --keep-open)
 		if !keepOpen {
-			closeReason := fmt.Sprintf("Moved to %s", newID)
-			if err := sourceStore.CloseIssue(ctx, resolvedSourceID, closeReason, actor, ""); err != nil {
+			reason := fmt.Sprintf("Moved to %s", newID)
+			if tombstone {
+				if err := tombstoneIssue(ctx, sourceStore, resolvedSourceID, actor, reason); err != nil {
+					WarnError("failed to tombstone source issue: %v", err)
+				}
+			} else if err := sourceStore.CloseIssue(ctx, resolvedSourceID, reason, actor, ""); err != nil {
 				WarnError("failed to close source issue: %v", err)
 			}
-			// Schedule auto-flush if source was local store
-			if !result.Routed {
-				markDirtyAndScheduleFlush()
-			}
+		}
+		// Schedule auto-flush if source was local store
+		if !result.Routed {
+			markDirtyAndScheduleFlush()
 		}
 
 		// Output
 		if jsonOutput {
 			outputJSON(map[string]interface{}{
-				"source":        resolvedSourceID,
-				"target":        newID,
-				"closed":        !keepOpen,
-				"deps_remapped": depsRemapped,
+				"source":            resolvedSourceID,
+				"target":            newID,
+				"closed":            !keepOpen && !tombstone,
+				"tombstoned":        tombstone,
+				"comments_copied":   commentsCopied,
+				"attachments_moved": attachmentsMoved,
+				"deps_copied":       depsCopied,
+				"deps_remapped":     depsRemapped,
 			})
 		} else {
 			fmt.Printf("%s Moved %s → %s\n", ui.RenderPass("✓"), resolvedSourceID, newID)
+			if commentsCopied > 0 {
+				fmt.Printf("  Copied %d comments\n", commentsCopied)
+			}
+			if attachmentsMoved > 0 {
+				fmt.Printf("  Moved %d attachments\n", attachmentsMoved)
+			}
+			if depsCopied > 0 {
+				fmt.Printf("  Recreated %d dependencies in target\n", depsCopied)
+			}
 			if depsRemapped > 0 {
 				fmt.Printf("  Remapped %d dependencies\n", depsRemapped)
 			}
-			if !keepOpen {
+			if tombstone {
+				fmt.Printf("  Source issue tombstoned\n")
+			} else if !keepOpen {
 				fmt.Printf("  Source issue closed\n")
 			}
 		}
@@ -272,10 +331,179 @@ func remapDependencies(ctx context.Context, s storage.Storage, oldID, newID, tar
 	return count, nil
 }
 
+// resolveMoveTarget finds the .beads directory bd move --to points at: a
+// rig or prefix from routes.jsonl, a project from external_projects, or a
+// path to a project or its .beads directory. prefix is the rig's issue prefix
+// when routing knows it, "" otherwise.
+func resolveMoveTarget(target string) (beadsDir, prefix string, err error) {
+	var routeErr error
+	if townBeadsDir, err := findTownBeadsDir(); err == nil {
+		beadsDir, prefix, routeErr = routing.ResolveBeadsDirForRig(target, townBeadsDir)
+		if routeErr == nil {
+			return beadsDir, prefix, nil
+		}
+	}
+
+	candidates := []string{target}
+	if path := config.ResolveExternalProjectPath(target); path != "" {
+		candidates = append([]string{path}, candidates...)
+	}
+	for _, path := range candidates {
+		dir := path
+		if filepath.Base(dir) != ".beads" {
+			dir = filepath.Join(dir, ".beads")
+		}
+		dir = beads.FollowRedirect(dir)
+		if info, statErr := os.Stat(filepath.Join(dir, "beads.db")); statErr == nil && !info.IsDir() {
+			return dir, "", nil
+		}
+	}
+
+	if routeErr != nil {
+		return "", "", fmt.Errorf("%w (and no beads database found at %q)", routeErr, target)
+	}
+	return "", "", fmt.Errorf("target %q is not a rig, an external project or a path to a beads database", target)
+}
+
+// storeDBPath returns the database file behind s, or "" if it isn't file-backed.
+func storeDBPath(s storage.Storage) string {
+	if p, ok := s.(interface{ Path() string }); ok {
+		return p.Path()
+	}
+	return ""
+}
+
+// sameFile reports whether a and b name the same existing file.
+func sameFile(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(ai, bi)
+}
+
+// moveAttachments moves .beads/attachments/<oldID>/ from the source database
+// to .beads/attachments/<newID>/ in the target. Returns the number of files moved.
+func moveAttachments(sourceBeadsDir, targetBeadsDir, oldID, newID string) (int, error) {
+	if sourceBeadsDir == "" {
+		return 0, nil
+	}
+	from := filepath.Join(sourceBeadsDir, "attachments", oldID)
+	entries, err := os.ReadDir(from)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	to := filepath.Join(targetBeadsDir, "attachments", newID)
+	if err := os.MkdirAll(to, 0750); err != nil {
+		return 0, err
+	}
+	moved := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		src, dst := filepath.Join(from, entry.Name()), filepath.Join(to, entry.Name())
+		if err := os.Rename(src, dst); err != nil {
+			// Rename fails across filesystems; fall back to copy and remove
+			// #nosec G304 - attachment under the source .beads directory
+			data, readErr := os.ReadFile(src)
+			if readErr != nil {
+				return moved, readErr
+			}
+			if err := os.WriteFile(dst, data, 0600); err != nil {
+				return moved, err
+			}
+			_ = os.Remove(src)
+		}
+		moved++
+	}
+	_ = os.Remove(from)
+	return moved, nil
+}
+
+// copyComments copies oldID's comments onto newID in the target, keeping
+// their authors and timestamps. Attachment paths in comment text are
+// rewritten to the moved attachments.
+func copyComments(ctx context.Context, source storage.Storage, target *sqlite.SQLiteStorage, oldID, newID string) (int, error) {
+	comments, err := source.GetIssueComments(ctx, oldID)
+	if err != nil {
+		return 0, err
+	}
+	oldPath, newPath := "attachments/"+oldID+"/", "attachments/"+newID+"/"
+	for i, c := range comments {
+		text := strings.ReplaceAll(c.Text, oldPath, newPath)
+		if _, err := target.ImportIssueComment(ctx, newID, c.Author, text, c.CreatedAt.UTC().Format(time.RFC3339)); err != nil {
+			return i, err
+		}
+	}
+	return len(comments), nil
+}
+
+// copyResolvableDependencies recreates oldID's own dependencies on newID in
+// the target. An external ref into the target becomes a local dependency
+// again, other external refs carry over as they are, and local ones only
+// when the target database has the same issue. Returns the number copied.
+func copyResolvableDependencies(ctx context.Context, source storage.Storage, target storage.Storage, oldID, newID, actor string) (int, error) {
+	deps, err := source.GetDependencyRecords(ctx, oldID)
+	if err != nil {
+		return 0, fmt.Errorf("getting dependencies from %s: %w", oldID, err)
+	}
+	count := 0
+	for _, dep := range deps {
+		dependsOn := dep.DependsOnID
+		if strings.HasPrefix(dependsOn, "external:") {
+			parts := strings.SplitN(dependsOn, ":", 3)
+			if len(parts) == 3 {
+				if issue, err := target.GetIssue(ctx, parts[2]); err == nil && issue != nil {
+					dependsOn = parts[2]
+				}
+			}
+		} else if issue, err := target.GetIssue(ctx, dependsOn); err != nil || issue == nil {
+			continue
+		}
+		newDep := &types.Dependency{
+			IssueID:     newID,
+			DependsOnID: dependsOn,
+			Type:        dep.Type,
+			CreatedBy:   actor,
+			Metadata:    dep.Metadata,
+			ThreadID:    dep.ThreadID,
+		}
+		if err := target.AddDependency(ctx, newDep, actor); err != nil {
+			fmt.Fprintf(os.Stderr, "  warning: failed to add dep %s->%s: %v\n", newID, dependsOn, err)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// tombstoneIssue turns id into a tombstone in s, the store that holds it.
+func tombstoneIssue(ctx context.Context, s storage.Storage, id, actor, reason string) error {
+	type tombstoner interface {
+		CreateTombstone(ctx context.Context, id string, actor string, reason string) error
+	}
+	if t, ok := s.(tombstoner); ok {
+		return t.CreateTombstone(ctx, id, actor, reason)
+	}
+	return fmt.Errorf("tombstone operation not supported by this storage backend")
+}
+
 func init() {
-	moveCmd.Flags().String("to", "", "Target rig or prefix (required)")
+	moveCmd.Flags().String("to", "", "Target rig, prefix, project or path (required)")
 	moveCmd.Flags().Bool("keep-open", false, "Keep the source issue open (don't close it)")
 	moveCmd.Flags().Bool("skip-deps", false, "Skip dependency remapping")
+	moveCmd.Flags().Bool("tombstone", false, "Leave a tombstone instead of a closed issue behind")
 	moveCmd.ValidArgsFunction = issueIDCompletion
 	rootCmd.AddCommand(moveCmd)
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -233,3 +234,91 @@ func TestRemapDependencies_PreservesMetadata(t *testing.T) {
 		t.Errorf("Metadata not preserved: got %s", bDepRecords[0].Metadata)
 	}
 }
+
+func TestMoveCopiesCommentsDepsAndAttachments(t *testing.T) {
+	ctx := context.Background()
+	sourceDir, targetDir := t.TempDir(), t.TempDir()
+	newStore := func(dir, prefix string) *sqlite.SQLiteStorage {
+		s, err := sqlite.New(ctx, filepath.Join(dir, "beads.db"))
+		if err != nil {
+			t.Fatalf("Failed to create test database: %v", err)
+		}
+		t.Cleanup(func() { s.Close() })
+		if err := s.SetConfig(ctx, "issue_prefix", prefix); err != nil {
+			t.Fatalf("Failed to set issue_prefix: %v", err)
+		}
+		return s
+	}
+	source, target := newStore(sourceDir, "src"), newStore(targetDir, "dst")
+
+	for _, issue := range []*types.Issue{
+		{ID: "src-a", Title: "Moved", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+		{ID: "src-b", Title: "Stays", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+	} {
+		if err := source.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue %s: %v", issue.ID, err)
+		}
+	}
+	shared := &types.Issue{ID: "dst-shared", Title: "In target", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	moved := &types.Issue{ID: "dst-new", Title: "Moved", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{shared, moved} {
+		if err := target.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue %s: %v", issue.ID, err)
+		}
+	}
+
+	// src-a depends on an issue only the source has, one in the target
+	// through an external ref, and an external capability elsewhere
+	for _, on := range []string{"src-b", "external:dst:dst-shared", "external:lib:cap"} {
+		if err := source.AddDependency(ctx, &types.Dependency{IssueID: "src-a", DependsOnID: on, Type: types.DepBlocks}, "test"); err != nil {
+			t.Fatalf("Failed to add dep src-a->%s: %v", on, err)
+		}
+	}
+	if _, err := source.AddIssueComment(ctx, "src-a", "alice", "See attachments/src-a/log.txt"); err != nil {
+		t.Fatal(err)
+	}
+	attachDir := filepath.Join(sourceDir, "attachments", "src-a")
+	if err := os.MkdirAll(attachDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(attachDir, "log.txt"), []byte("boom"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := moveAttachments(sourceDir, targetDir, "src-a", "dst-new")
+	if err != nil || n != 1 {
+		t.Fatalf("moveAttachments = %d, %v; want 1 file", n, err)
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, "attachments", "dst-new", "log.txt")); err != nil {
+		t.Errorf("attachment not in target: %v", err)
+	}
+	if _, err := os.Stat(attachDir); !os.IsNotExist(err) {
+		t.Errorf("source attachment directory should be gone, stat err = %v", err)
+	}
+
+	if n, err := copyComments(ctx, source, target, "src-a", "dst-new"); err != nil || n != 1 {
+		t.Fatalf("copyComments = %d, %v; want 1", n, err)
+	}
+	comments, err := target.GetIssueComments(ctx, "dst-new")
+	if err != nil || len(comments) != 1 {
+		t.Fatalf("target comments = %v, %v", comments, err)
+	}
+	if comments[0].Author != "alice" || comments[0].Text != "See attachments/dst-new/log.txt" {
+		t.Errorf("comment = %q by %q, want author kept and attachment path rewritten", comments[0].Text, comments[0].Author)
+	}
+
+	if n, err := copyResolvableDependencies(ctx, source, target, "src-a", "dst-new", "test"); err != nil || n != 2 {
+		t.Fatalf("copyResolvableDependencies = %d, %v; want 2 (dst-shared and the external ref)", n, err)
+	}
+	deps, err := target.GetDependencyRecords(ctx, "dst-new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{}
+	for _, dep := range deps {
+		got[dep.DependsOnID] = true
+	}
+	if !got["dst-shared"] || !got["external:lib:cap"] || got["src-b"] {
+		t.Errorf("target deps = %v, want dst-shared and external:lib:cap only", got)
+	}
+}
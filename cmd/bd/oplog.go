@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/debug"
+	"github.com/steveyegge/beads/internal/git"
+	"github.com/steveyegge/beads/internal/oplog"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var oplogCmd = &cobra.Command{
+	Use:     "oplog",
+	GroupID: "sync",
+	Short:   "Field-level sync through an append-only operation log",
+	Long: `Replicate issue edits through .beads/ops.jsonl, an append-only log of
+field changes, alongside issues.jsonl.
+
+With sync.oplog enabled, every export appends one operation per changed
+field or label, and every import replays the merged log:
+
+  - scalar fields (title, status, priority, assignee, ...) are
+    last-writer-wins per field, so two clones editing different fields of
+    the same issue offline both keep their edit
+  - labels are an observed-remove set, so a label removed on one clone
+    stays removed while a concurrent add on another survives
+
+Ties are broken by replica ID, so every clone converges on the same result
+without manual conflict resolution. The log only grows, and git's union
+merge driver combines two clones' logs without conflicts.
+
+Examples:
+  bd oplog enable          # Turn on op-log sync and seed the log
+  bd oplog show bd-42      # Operation history for one issue
+  bd oplog show --json     # Whole log as JSON`,
+}
+
+var oplogEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enable op-log sync and seed the log from the current issues",
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("oplog enable")
+		if err := ensureStoreActive(); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		jsonlPath := findJSONLPath()
+
+		if err := config.SetYamlConfig("sync.oplog", "true"); err != nil {
+			FatalError("failed to enable sync.oplog: %v", err)
+		}
+
+		unlock, err := lockJSONL(ctx, jsonlPath)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		defer unlock()
+
+		issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+		if err != nil {
+			FatalError("failed to list issues: %v", err)
+		}
+		ids := make([]string, len(issues))
+		for i, issue := range issues {
+			ids[i] = issue.ID
+		}
+		n, err := oplog.Record(ctx, store, jsonlPath, ids, getActorWithGit(), opLogSigner())
+		if err != nil {
+			FatalError("failed to seed op log: %v", err)
+		}
+
+		attrs, err := ensureOpLogUnionMerge(oplog.Path(jsonlPath))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"enabled":        true,
+				"path":           oplog.Path(jsonlPath),
+				"ops_recorded":   n,
+				"gitattributes":  attrs,
+				"issues_tracked": len(ids),
+			})
+			return
+		}
+		fmt.Printf("%s Op-log sync enabled (sync.oplog: true)\n", ui.RenderPass("✓"))
+		fmt.Printf("  Seeded %s with %d operation(s) for %d issue(s)\n", oplog.Path(jsonlPath), n, len(ids))
+		if attrs != "" {
+			fmt.Printf("  Added union merge for the log to %s\n", attrs)
+		}
+		fmt.Println("  Commit .beads/ops.jsonl and .gitattributes so other clones replay it")
+	},
+}
+
+var oplogShowCmd = &cobra.Command{
+	Use:   "show [issue-id]",
+	Short: "Show the operation history for one issue or the whole log",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ops, err := oplog.Read(oplog.Path(findJSONLPath()))
+		if err != nil {
+			FatalError("%v", err)
+		}
+		if len(args) == 1 {
+			var filtered []oplog.Op
+			for _, op := range ops {
+				if op.Issue == args[0] {
+					filtered = append(filtered, op)
+				}
+			}
+			ops = filtered
+		}
+
+		if jsonOutput {
+			if ops == nil {
+				ops = []oplog.Op{}
+			}
+			outputJSON(ops)
+			return
+		}
+		if len(ops) == 0 {
+			fmt.Println("No operations recorded")
+			return
+		}
+		for _, op := range ops {
+			fmt.Printf("%s  %s  %-8s %-8s %s\n",
+				op.Time.Local().Format("2006-01-02 15:04:05"), op.Issue, op.Replica, op.Actor, describeOp(op))
+		}
+	},
+}
+
+// describeOp renders one op for bd oplog show.
+func describeOp(op oplog.Op) string {
+	switch op.Kind {
+	case oplog.KindSet:
+		var v interface{}
+		_ = json.Unmarshal(op.Value, &v)
+		s := fmt.Sprint(v)
+		if len(s) > 60 {
+			s = s[:57] + "..."
+		}
+		return fmt.Sprintf("%s = %s", op.Field, strings.ReplaceAll(s, "\n", " "))
+	case oplog.KindLabelAdd:
+		return "+label " + op.Label
+	case oplog.KindLabelRemove:
+		return "-label " + op.Label
+	}
+	return string(op.Kind)
+}
+
+// oplogEnabled reports whether exports and imports go through the op log.
+func oplogEnabled() bool {
+	return config.GetBool("sync.oplog")
+}
+
+// recordOpLog appends ops for the exported issues' changes. Call it after a
+// successful JSONL export, with the JSONL lock held. Failures only warn: the
+// next export diffs against the log again and records what was missed.
+func recordOpLog(ctx context.Context, s storage.Storage, jsonlPath string, ids []string) {
+	if !oplogEnabled() {
+		return
+	}
+	n, err := oplog.Record(ctx, s, jsonlPath, ids, actor, opLogSigner())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record op log: %v\n", err)
+		return
+	}
+	debug.Logf("oplog: recorded %d op(s)", n)
+}
+
+// opLogSigner returns the key new ops are signed with (sign.ops), or nil.
+// A missing key only warns, leaving the ops unsigned for bd verify to flag.
+func opLogSigner() oplog.Signer {
+	signer, err := oplog.ConfiguredSigner()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: op log not signed: %v\n", err)
+		return nil
+	}
+	return signer
+}
+
+// recordPendingOpLog records edits that haven't been exported yet. Call it
+// before an import overwrites them.
+func recordPendingOpLog(ctx context.Context, s storage.Storage, jsonlPath string) {
+	if !oplogEnabled() {
+		return
+	}
+	if _, err := oplog.RecordPending(ctx, s, jsonlPath, actor, opLogSigner()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record op log: %v\n", err)
+	}
+}
+
+// applyOpLog replays the merged op log over the issues just imported.
+func applyOpLog(ctx context.Context, s storage.Storage, jsonlPath string) {
+	if !oplogEnabled() {
+		return
+	}
+	n, err := oplog.Apply(ctx, s, jsonlPath, "oplog")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to apply op log: %v\n", err)
+		return
+	}
+	if n > 0 {
+		debug.Logf("oplog: merged field-level changes into %d issue(s)", n)
+	}
+}
+
+// ensureOpLogUnionMerge adds a union merge attribute for the op log to the
+// repository's .gitattributes. Returns the file it changed, or "" when the
+// attribute was already there.
+func ensureOpLogUnionMerge(logPath string) (string, error) {
+	root := git.GetRepoRoot()
+	if root == "" {
+		return "", fmt.Errorf("not in a git repository; add '%s merge=union' to .gitattributes yourself", oplog.FileName)
+	}
+	absLog, err := filepath.Abs(logPath)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, absLog)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("op log %s is outside the repository", logPath)
+	}
+	attr := filepath.ToSlash(rel) + " merge=union"
+
+	attrsPath := filepath.Join(root, ".gitattributes")
+	// #nosec G304 - .gitattributes at the repository root
+	content, err := os.ReadFile(attrsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+	existing := string(content)
+	if strings.Contains(existing, attr) {
+		return "", nil
+	}
+	if existing != "" && !strings.HasSuffix(existing, "\n") {
+		existing += "\n"
+	}
+	existing += "\n# Op log lines are append-only; union merge combines clones' logs\n" + attr + "\n"
+	// #nosec G306 - .gitattributes needs to be readable
+	if err := os.WriteFile(attrsPath, []byte(existing), 0644); err != nil {
+		return "", fmt.Errorf("failed to update .gitattributes: %w", err)
+	}
+	return attrsPath, nil
+}
+
+func init() {
+	oplogCmd.AddCommand(oplogEnableCmd)
+	oplogCmd.AddCommand(oplogShowCmd)
+	rootCmd.AddCommand(oplogCmd)
+}
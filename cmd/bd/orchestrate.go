@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// OrchestrationManifestVersion is bumped when the manifest changes in a way
+// consumers must handle.
+const OrchestrationManifestVersion = 1
+
+// OrchestrationClaim is who is working on a task. A claim is held by the
+// assignee of an in_progress issue (bd update --claim).
+type OrchestrationClaim struct {
+	Assignee  string    `json:"assignee"`
+	ClaimedAt time.Time `json:"claimed_at"` // Last update of the issue
+}
+
+// OrchestrationLease bounds how long a claim stays valid without activity.
+// Any update to the issue renews it; an orchestrator may reassign tasks
+// whose lease has expired.
+type OrchestrationLease struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	Expired   bool      `json:"expired"`
+}
+
+// OrchestrationContext points at what an agent should read before starting
+// a task, without inlining it.
+type OrchestrationContext struct {
+	Show        string   `json:"show"`     // Command printing the issue with its description, design and notes
+	Comments    string   `json:"comments"` // Command printing its comments
+	Parent      string   `json:"parent,omitempty"`
+	Related     []string `json:"related,omitempty"` // Linked by related or discovered-from, either way
+	ExternalRef string   `json:"external_ref,omitempty"`
+}
+
+// OrchestrationTask is one node of the work DAG.
+type OrchestrationTask struct {
+	ID               string                `json:"id"`
+	Title            string                `json:"title"`
+	Status           types.Status          `json:"status"`
+	Priority         int                   `json:"priority"`
+	IssueType        types.IssueType       `json:"issue_type"`
+	Labels           []string              `json:"labels,omitempty"`
+	EstimatedMinutes *int                  `json:"estimated_minutes,omitempty"`
+	Wave             int                   `json:"wave"`                 // From bd order; 0 if stuck in a cycle
+	DependsOn        []string              `json:"depends_on"`           // Tasks that must finish first
+	WaitingOn        []string              `json:"waiting_on,omitempty"` // Open blockers outside the manifest
+	Ready            bool                  `json:"ready"`                // Unblocked and unclaimed
+	Claim            *OrchestrationClaim   `json:"claim,omitempty"`
+	Lease            *OrchestrationLease   `json:"lease,omitempty"`
+	Context          *OrchestrationContext `json:"context"`
+}
+
+// OrchestrationOp is an RPC request template; "<id>" stands for a task ID.
+type OrchestrationOp struct {
+	Operation string                 `json:"operation"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+// OrchestrationDaemon tells consumers how to report progress back.
+type OrchestrationDaemon struct {
+	Socket          string                     `json:"socket"`
+	ProtocolVersion int                        `json:"protocol_version"`
+	Operations      map[string]OrchestrationOp `json:"operations"`
+}
+
+// OrchestrationManifest is the output of bd orchestrate export.
+type OrchestrationManifest struct {
+	Version          int                  `json:"version"`
+	GeneratedAt      time.Time            `json:"generated_at"`
+	Filter           string               `json:"filter,omitempty"`
+	LeaseTTL         string               `json:"lease_ttl"`
+	EstimatedMinutes int                  `json:"estimated_minutes"` // Sum over tasks with an estimate
+	Waves            int                  `json:"waves"`
+	Tasks            []*OrchestrationTask `json:"tasks"`
+	Daemon           OrchestrationDaemon  `json:"daemon"`
+}
+
+var orchestrateCmd = &cobra.Command{
+	Use:     "orchestrate",
+	GroupID: "advanced",
+	Short:   "Hand work to external orchestrators",
+}
+
+var orchestrateExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export open work as a DAG manifest for external orchestrators",
+	Long: `Export open issues as a work DAG for an external orchestrator, such as a
+swarm of coding agents, to schedule.
+
+Each task carries its dependencies and wave (as bd order computes them),
+its estimate, its claim and lease, and pointers to the context an agent
+should read before starting. The manifest also says how to report back
+through the daemon's RPC socket: claim a task with the update operation
+and claim=true, renew its lease with any update, and finish it with close.
+
+A claim is the assignee of an in_progress issue. Its lease runs --lease-ttl
+from the issue's last update; tasks whose lease has expired can be
+reclaimed. A task is ready when nothing open blocks it and nobody holds an
+unexpired claim.`,
+	Example: `  bd orchestrate export --format json > manifest.json
+  bd orchestrate export --filter 'milestone=v1.2' --lease-ttl 30m -o manifest.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		if format != "json" {
+			FatalErrorCode(ErrCodeUsage, "unsupported format %q (supported: json)", format)
+		}
+		ttl, _ := cmd.Flags().GetDuration("lease-ttl")
+		if ttl <= 0 {
+			FatalErrorCode(ErrCodeUsage, "--lease-ttl must be positive")
+		}
+		if err := ensureDirectMode("orchestrate export requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		filter, _ := cmd.Flags().GetString("filter")
+		output, _ := cmd.Flags().GetString("output")
+
+		all, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+		if err != nil {
+			FatalError("loading issues: %v", err)
+		}
+		selected := all
+		if filter != "" {
+			if selected, err = queryMatchingIssues(ctx, parseQueryFlag(filter)); err != nil {
+				FatalError("running filter: %v", err)
+			}
+		}
+		ids := make([]string, len(selected))
+		for i, issue := range selected {
+			ids[i] = issue.ID
+		}
+		labels, err := store.GetLabelsForIssues(ctx, ids)
+		if err != nil {
+			FatalError("loading labels: %v", err)
+		}
+		for _, issue := range selected {
+			issue.Labels = labels[issue.ID]
+		}
+		deps, err := store.GetAllDependencyRecords(ctx)
+		if err != nil {
+			FatalError("loading dependencies: %v", err)
+		}
+
+		manifest := buildOrchestrationManifest(selected, all, deps, ttl, time.Now().UTC())
+		manifest.Filter = filter
+		manifest.Daemon.Socket = getSocketPath()
+
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(manifest); err != nil {
+			FatalError("encoding manifest: %v", err)
+		}
+		if output == "" || output == "-" {
+			_, _ = os.Stdout.Write(buf.Bytes())
+			return
+		}
+		if err := os.WriteFile(output, buf.Bytes(), 0o644); err != nil { // #nosec G306 -- manifest holds no secrets
+			FatalError("writing %s: %v", output, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s Exported %d task(s) in %d wave(s) to %s\n", ui.RenderPass("✓"), len(manifest.Tasks), manifest.Waves, output)
+	},
+}
+
+// buildOrchestrationManifest turns the open issues of selected into tasks.
+// all and deps are as for computeOrder.
+func buildOrchestrationManifest(selected, all []*types.Issue, deps map[string][]*types.Dependency, ttl time.Duration, now time.Time) *OrchestrationManifest {
+	order := computeOrder(selected, all, deps)
+	byID := make(map[string]*types.Issue, len(selected))
+	for _, issue := range selected {
+		byID[issue.ID] = issue
+	}
+
+	// Parents and related links, in both directions
+	parent := make(map[string]string)
+	related := make(map[string][]string)
+	for id, list := range deps {
+		for _, dep := range list {
+			switch dep.Type {
+			case types.DepParentChild:
+				parent[id] = dep.DependsOnID
+			case types.DepRelated, types.DepDiscoveredFrom:
+				related[id] = append(related[id], dep.DependsOnID)
+				related[dep.DependsOnID] = append(related[dep.DependsOnID], id)
+			}
+		}
+	}
+
+	for _, list := range related {
+		sort.Strings(list)
+	}
+
+	m := &OrchestrationManifest{
+		Version:     OrchestrationManifestVersion,
+		GeneratedAt: now,
+		LeaseTTL:    ttl.String(),
+		Waves:       len(order.Waves),
+		Tasks:       []*OrchestrationTask{},
+		Daemon: OrchestrationDaemon{
+			ProtocolVersion: rpc.ProtocolVersion,
+			Operations: map[string]OrchestrationOp{
+				"claim":    {rpc.OpUpdate, map[string]interface{}{"id": "<id>", "claim": true}},
+				"renew":    {rpc.OpUpdate, map[string]interface{}{"id": "<id>", "status": "in_progress"}},
+				"release":  {rpc.OpUpdate, map[string]interface{}{"id": "<id>", "status": "open", "assignee": ""}},
+				"complete": {rpc.OpClose, map[string]interface{}{"id": "<id>", "reason": "<reason>"}},
+				"comment":  {rpc.OpCommentAdd, map[string]interface{}{"id": "<id>", "author": "<agent>", "text": "<text>"}},
+			},
+		},
+	}
+	var nodes []*OrderIssue
+	for _, w := range order.Waves {
+		nodes = append(nodes, w.Issues...)
+	}
+	nodes = append(nodes, order.Unordered...)
+	for _, node := range nodes {
+		issue := byID[node.ID]
+		task := &OrchestrationTask{
+			ID:               issue.ID,
+			Title:            issue.Title,
+			Status:           issue.Status,
+			Priority:         issue.Priority,
+			IssueType:        issue.IssueType,
+			Labels:           issue.Labels,
+			EstimatedMinutes: issue.EstimatedMinutes,
+			Wave:             node.Wave,
+			DependsOn:        node.After,
+			WaitingOn:        node.Waiting,
+			Context: &OrchestrationContext{
+				Show:     "bd show " + issue.ID + " --json",
+				Comments: "bd comments " + issue.ID + " --json",
+				Parent:   parent[issue.ID],
+				Related:  uniqueStrings(related[issue.ID]),
+			},
+		}
+		if issue.ExternalRef != nil {
+			task.Context.ExternalRef = *issue.ExternalRef
+		}
+		if issue.EstimatedMinutes != nil {
+			m.EstimatedMinutes += *issue.EstimatedMinutes
+		}
+		claimed := false
+		if issue.Status == types.StatusInProgress && issue.Assignee != "" {
+			task.Claim = &OrchestrationClaim{Assignee: issue.Assignee, ClaimedAt: issue.UpdatedAt.UTC()}
+			expires := issue.UpdatedAt.Add(ttl).UTC()
+			task.Lease = &OrchestrationLease{ExpiresAt: expires, Expired: !expires.After(now)}
+			claimed = !task.Lease.Expired
+		}
+		// Tasks in the manifest are open, so any of them in DependsOn blocks
+		blocked := len(node.After) > 0 || len(node.Waiting) > 0 || node.Wave == 0
+		task.Ready = !blocked && !claimed && issue.Status != types.StatusBlocked && issue.Status != types.StatusDeferred
+		m.Tasks = append(m.Tasks, task)
+	}
+	return m
+}
+
+func init() {
+	orchestrateExportCmd.Flags().String("format", "json", "Manifest format (json)")
+	orchestrateExportCmd.Flags().String("filter", "", "Only export issues matching this query (e.g. 'milestone=v1.2')")
+	orchestrateExportCmd.Flags().Duration("lease-ttl", 2*time.Hour, "How long a claim stays valid after the issue's last update")
+	orchestrateExportCmd.Flags().StringP("output", "o", "", "Write the manifest to a file instead of stdout")
+	orchestrateCmd.AddCommand(orchestrateExportCmd)
+	rootCmd.AddCommand(orchestrateCmd)
+}
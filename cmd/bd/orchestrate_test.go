@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestBuildOrchestrationManifest(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	est := func(m int) *int { return &m }
+	ref := "gh-12"
+	all := []*types.Issue{
+		{ID: "bd-1", Title: "Schema", Status: types.StatusInProgress, Assignee: "agent-a", UpdatedAt: now.Add(-10 * time.Minute), EstimatedMinutes: est(60)},
+		{ID: "bd-2", Title: "API", Status: types.StatusOpen, EstimatedMinutes: est(90), ExternalRef: &ref},
+		{ID: "bd-3", Title: "Stale claim", Status: types.StatusInProgress, Assignee: "agent-b", UpdatedAt: now.Add(-3 * time.Hour)},
+		{ID: "bd-4", Title: "Free", Status: types.StatusOpen},
+		{ID: "bd-5", Title: "Epic", Status: types.StatusOpen, IssueType: types.TypeEpic},
+	}
+	dep := func(from, to string, typ types.DependencyType) *types.Dependency {
+		return &types.Dependency{IssueID: from, DependsOnID: to, Type: typ}
+	}
+	deps := map[string][]*types.Dependency{
+		"bd-2": {dep("bd-2", "bd-1", types.DepBlocks), dep("bd-2", "bd-5", types.DepParentChild)},
+		"bd-4": {dep("bd-4", "bd-2", types.DepDiscoveredFrom)},
+	}
+
+	m := buildOrchestrationManifest(all, all, deps, 2*time.Hour, now)
+	if m.Version != OrchestrationManifestVersion || m.Waves != 2 || m.EstimatedMinutes != 150 || len(m.Tasks) != 5 {
+		t.Fatalf("manifest = %+v", m)
+	}
+	tasks := make(map[string]*OrchestrationTask)
+	for _, task := range m.Tasks {
+		tasks[task.ID] = task
+	}
+
+	schema := tasks["bd-1"]
+	if schema.Claim == nil || schema.Claim.Assignee != "agent-a" || schema.Lease.Expired || schema.Ready {
+		t.Errorf("bd-1 = %+v, want claimed with a live lease, not ready", schema)
+	}
+	if stale := tasks["bd-3"]; stale.Lease == nil || !stale.Lease.Expired || !stale.Ready {
+		t.Errorf("bd-3 = %+v, want an expired lease and ready", stale)
+	}
+	api := tasks["bd-2"]
+	if api.Wave != 2 || strings.Join(api.DependsOn, ",") != "bd-1" || api.Ready {
+		t.Errorf("bd-2 = %+v, want wave 2 after bd-1, not ready", api)
+	}
+	if api.Context.Parent != "bd-5" || api.Context.ExternalRef != "gh-12" || strings.Join(api.Context.Related, ",") != "bd-4" {
+		t.Errorf("bd-2 context = %+v", api.Context)
+	}
+	if free := tasks["bd-4"]; !free.Ready || strings.Join(free.Context.Related, ",") != "bd-2" {
+		t.Errorf("bd-4 = %+v, want ready and related to bd-2", free)
+	}
+	if op := m.Daemon.Operations["claim"]; op.Operation != "update" || op.Args["claim"] != true {
+		t.Errorf("claim operation = %+v", op)
+	}
+}
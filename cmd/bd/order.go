@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// OrderIssue is an issue's place in an execution order.
+type OrderIssue struct {
+	ID        string          `json:"id"`
+	Title     string          `json:"title"`
+	Status    types.Status    `json:"status"`
+	Priority  int             `json:"priority"`
+	IssueType types.IssueType `json:"issue_type"`
+	Wave      int             `json:"wave"`                 // 1-based; 0 if stuck in a cycle
+	After     []string        `json:"after"`                // Selected issues that must finish first
+	Waiting   []string        `json:"waiting_on,omitempty"` // Open blockers outside the selection
+}
+
+// OrderWave is a set of issues that can run in parallel once the earlier
+// waves are done.
+type OrderWave struct {
+	Wave   int           `json:"wave"`
+	Issues []*OrderIssue `json:"issues"`
+}
+
+// OrderReport is the output of bd order.
+type OrderReport struct {
+	Filter    string        `json:"filter,omitempty"`
+	Order     []string      `json:"order"` // Every ordered issue, wave by wave
+	Waves     []*OrderWave  `json:"waves"`
+	Unordered []*OrderIssue `json:"unordered"` // In or behind a dependency cycle
+}
+
+var orderCmd = &cobra.Command{
+	Use:     "order",
+	GroupID: "deps",
+	Short:   "List open issues in dependency order, grouped into parallel waves",
+	Long: `List open issues in a valid execution order: every issue comes after the
+issues that block it. Issues are grouped into waves; the issues in a wave
+don't depend on each other, so they can be worked on concurrently once
+the earlier waves are done. Within a wave, higher priority comes first.
+
+Use --filter to order a subset, such as a milestone. Ordering follows
+blocks, conditional-blocks and waits-for links between the selected
+issues; parent-child links group work rather than sequence it, so they are
+ignored. Open blockers outside the selection are listed per issue as
+waiting_on. Issues in a dependency cycle, and those behind one, can't be
+ordered and are reported separately.
+
+The JSON output is meant for orchestrators scheduling parallel agents:
+"order" is a flat list and "waves" the parallel groups.`,
+	Example: `  bd order --filter 'milestone=v1.2'
+  bd order --filter 'label:backend and priority<=2' --json
+  bd order --json | jq -r '.waves[0].issues[].id'`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("order requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		filter, _ := cmd.Flags().GetString("filter")
+
+		all, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+		if err != nil {
+			FatalErrorRespectJSON("loading issues: %v", err)
+		}
+		selected := all
+		if filter != "" {
+			if selected, err = queryMatchingIssues(ctx, parseQueryFlag(filter)); err != nil {
+				FatalErrorRespectJSON("running filter: %v", err)
+			}
+		}
+		deps, err := store.GetAllDependencyRecords(ctx)
+		if err != nil {
+			FatalErrorRespectJSON("loading dependencies: %v", err)
+		}
+
+		report := computeOrder(selected, all, deps)
+		report.Filter = filter
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		printOrder(report)
+	},
+}
+
+// computeOrder orders the open issues of selected. all is used to tell
+// whether blockers outside the selection are still open; deps maps an
+// issue to the dependencies it has.
+func computeOrder(selected, all []*types.Issue, deps map[string][]*types.Dependency) *OrderReport {
+	done := func(issue *types.Issue) bool {
+		return issue.Status == types.StatusClosed || issue.Status == types.StatusTombstone
+	}
+	open := make(map[string]bool, len(all))
+	for _, issue := range all {
+		open[issue.ID] = !done(issue)
+	}
+
+	nodes := make(map[string]*OrderIssue)
+	var ids []string
+	for _, issue := range selected {
+		if done(issue) || nodes[issue.ID] != nil {
+			continue
+		}
+		nodes[issue.ID] = &OrderIssue{
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			Priority:  issue.Priority,
+			IssueType: issue.IssueType,
+			After:     []string{},
+		}
+		ids = append(ids, issue.ID)
+	}
+
+	pending := make(map[string]int)       // Unfinished blockers of each issue
+	unblocks := make(map[string][]string) // Issue → selected issues it blocks
+	for _, id := range ids {
+		node := nodes[id]
+		for _, dep := range deps[id] {
+			if !dep.Type.AffectsReadyWork() || dep.Type == types.DepParentChild {
+				continue
+			}
+			switch {
+			case nodes[dep.DependsOnID] != nil:
+				if !containsString(node.After, dep.DependsOnID) {
+					node.After = append(node.After, dep.DependsOnID)
+					unblocks[dep.DependsOnID] = append(unblocks[dep.DependsOnID], id)
+					pending[id]++
+				}
+			case open[dep.DependsOnID]:
+				node.Waiting = append(node.Waiting, dep.DependsOnID)
+			}
+		}
+		sort.Strings(node.After)
+		sort.Strings(node.Waiting)
+	}
+
+	// Kahn's algorithm, one wave per round
+	report := &OrderReport{Order: []string{}, Waves: []*OrderWave{}, Unordered: []*OrderIssue{}}
+	var wave []*OrderIssue
+	for _, id := range ids {
+		if pending[id] == 0 {
+			wave = append(wave, nodes[id])
+		}
+	}
+	for len(wave) > 0 {
+		sort.Slice(wave, func(i, j int) bool {
+			if wave[i].Priority != wave[j].Priority {
+				return wave[i].Priority < wave[j].Priority
+			}
+			return wave[i].ID < wave[j].ID
+		})
+		w := &OrderWave{Wave: len(report.Waves) + 1, Issues: wave}
+		report.Waves = append(report.Waves, w)
+		var next []*OrderIssue
+		for _, node := range wave {
+			node.Wave = w.Wave
+			report.Order = append(report.Order, node.ID)
+			for _, id := range unblocks[node.ID] {
+				if pending[id]--; pending[id] == 0 {
+					next = append(next, nodes[id])
+				}
+			}
+		}
+		wave = next
+	}
+
+	for _, id := range ids {
+		if nodes[id].Wave == 0 {
+			report.Unordered = append(report.Unordered, nodes[id])
+		}
+	}
+	sort.Slice(report.Unordered, func(i, j int) bool { return report.Unordered[i].ID < report.Unordered[j].ID })
+	return report
+}
+
+func printOrder(r *OrderReport) {
+	if len(r.Order) == 0 && len(r.Unordered) == 0 {
+		fmt.Println("No open issues to order.")
+		return
+	}
+	line := func(o *OrderIssue) string {
+		s := fmt.Sprintf("  %s %s %s", ui.RenderAccent(o.ID), truncateTitle(o.Title, 60),
+			ui.RenderMuted(fmt.Sprintf("[P%d %s]", o.Priority, o.IssueType)))
+		if len(o.After) > 0 {
+			s += " " + ui.RenderMuted("after "+strings.Join(o.After, ", "))
+		}
+		if len(o.Waiting) > 0 {
+			s += " " + ui.RenderWarn("waiting on "+strings.Join(o.Waiting, ", "))
+		}
+		return s
+	}
+	for _, w := range r.Waves {
+		fmt.Printf("Wave %d (%d issue(s))\n", w.Wave, len(w.Issues))
+		for _, o := range w.Issues {
+			fmt.Println(line(o))
+		}
+	}
+	if len(r.Unordered) > 0 {
+		fmt.Printf("\n%s %d issue(s) are in or behind a dependency cycle:\n", ui.RenderWarn("⚠"), len(r.Unordered))
+		for _, o := range r.Unordered {
+			fmt.Println(line(o))
+		}
+	}
+	fmt.Printf("\n%d issue(s) in %d wave(s)\n", len(r.Order), len(r.Waves))
+}
+
+func init() {
+	orderCmd.Flags().String("filter", "", "Only order issues matching this query (e.g. 'milestone=v1.2')")
+	rootCmd.AddCommand(orderCmd)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestComputeOrder(t *testing.T) {
+	all := []*types.Issue{
+		{ID: "bd-1", Title: "Schema", Status: types.StatusOpen, Priority: 2},
+		{ID: "bd-2", Title: "API", Status: types.StatusOpen, Priority: 1},
+		{ID: "bd-3", Title: "UI", Status: types.StatusInProgress, Priority: 2},
+		{ID: "bd-4", Title: "Docs", Status: types.StatusOpen, Priority: 3},
+		{ID: "bd-5", Title: "Done", Status: types.StatusClosed},
+		{ID: "bd-6", Title: "Outside", Status: types.StatusOpen},
+		{ID: "bd-7", Title: "Cycle a", Status: types.StatusOpen},
+		{ID: "bd-8", Title: "Cycle b", Status: types.StatusOpen},
+		{ID: "bd-9", Title: "Behind cycle", Status: types.StatusOpen},
+		{ID: "bd-10", Title: "Epic", Status: types.StatusOpen, IssueType: types.TypeEpic},
+	}
+	dep := func(from, to string, typ types.DependencyType) *types.Dependency {
+		return &types.Dependency{IssueID: from, DependsOnID: to, Type: typ}
+	}
+	deps := map[string][]*types.Dependency{
+		"bd-2": {dep("bd-2", "bd-1", types.DepBlocks), dep("bd-2", "bd-5", types.DepBlocks), dep("bd-2", "bd-10", types.DepParentChild)},
+		"bd-3": {dep("bd-3", "bd-2", types.DepBlocks), dep("bd-3", "bd-1", types.DepWaitsFor)},
+		"bd-4": {dep("bd-4", "bd-6", types.DepBlocks), dep("bd-4", "bd-1", types.DepRelated)},
+		"bd-7": {dep("bd-7", "bd-8", types.DepBlocks)},
+		"bd-8": {dep("bd-8", "bd-7", types.DepBlocks)},
+		"bd-9": {dep("bd-9", "bd-8", types.DepBlocks)},
+	}
+	selected := append(append([]*types.Issue{}, all[:5]...), all[6:]...) // All but bd-6
+
+	r := computeOrder(selected, all, deps)
+	if got := strings.Join(r.Order, " "); got != "bd-10 bd-1 bd-4 bd-2 bd-3" {
+		t.Errorf("order = %s", got)
+	}
+	if len(r.Waves) != 3 || len(r.Waves[0].Issues) != 3 || r.Waves[2].Issues[0].ID != "bd-3" {
+		t.Errorf("waves = %+v", r.Waves)
+	}
+	api := r.Waves[1].Issues[0]
+	if api.ID != "bd-2" || strings.Join(api.After, ",") != "bd-1" || api.Wave != 2 {
+		t.Errorf("bd-2 = %+v, want after bd-1 only (bd-5 is closed, parent-child ignored)", api)
+	}
+	if docs := r.Waves[0].Issues[2]; docs.ID != "bd-4" || strings.Join(docs.Waiting, ",") != "bd-6" {
+		t.Errorf("bd-4 = %+v, want waiting on bd-6", docs)
+	}
+	var stuck []string
+	for _, o := range r.Unordered {
+		stuck = append(stuck, o.ID)
+	}
+	if got := strings.Join(stuck, " "); got != "bd-7 bd-8 bd-9" {
+		t.Errorf("unordered = %s", got)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// addCursorFlag registers --cursor on a listing command.
+func addCursorFlag(cmd *cobra.Command) {
+	cmd.Flags().String("cursor", "", "Return one page of --limit results plus a next_cursor for the following page (use --cursor '' for the first page)")
+}
+
+// cursorFlag reports whether --cursor was given and the position it
+// resumes after (nil for the first page). Pagination only applies when the
+// flag is present, so existing --json consumers keep receiving plain arrays.
+func cursorFlag(cmd *cobra.Command) (paginate bool, cursor string, after *types.Cursor) {
+	if !cmd.Flags().Changed("cursor") {
+		return false, "", nil
+	}
+	cursor, _ = cmd.Flags().GetString("cursor")
+	after, err := types.DecodeCursor(cursor)
+	if err != nil {
+		FatalErrorCode(ErrCodeInvalid, "%v (pass the next_cursor value from the previous page)", err)
+	}
+	return true, cursor, after
+}
+
+// printPageHint tells humans how to continue a paginated listing, or that a
+// limited listing was truncated.
+func printPageHint(limit, shown int, paginate bool, nextCursor string) {
+	switch {
+	case paginate && nextCursor != "":
+		fmt.Fprintf(os.Stderr, "\nShowing %d issues. Next page: --cursor %s\n", shown, nextCursor)
+	case !paginate && limit > 0 && shown == limit:
+		// GH#788
+		fmt.Fprintf(os.Stderr, "\nShowing %d issues (use --limit 0 for all)\n", limit)
+	}
+}
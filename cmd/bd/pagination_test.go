@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestCursorPagination(t *testing.T) {
+	p := newTestProject(t)
+
+	want := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		var issue types.Issue
+		// Same priority for all, so pages rely on the ID tiebreaker
+		p.RunJSON(&issue, "create", fmt.Sprintf("Paged issue %d", i), "-p", "2")
+		want[issue.ID] = true
+	}
+
+	walk := func(t *testing.T, args ...string) {
+		t.Helper()
+		seen := map[string]bool{}
+		cursor := ""
+		for pages := 1; ; pages++ {
+			var page types.IssuePage[*types.Issue]
+			p.RunJSON(&page, append(args, "--limit", "2", "--cursor", cursor)...)
+			if len(page.Issues) > 2 {
+				t.Fatalf("page %d has %d issues, want at most 2", pages, len(page.Issues))
+			}
+			for _, issue := range page.Issues {
+				if seen[issue.ID] {
+					t.Fatalf("%s returned on more than one page", issue.ID)
+				}
+				seen[issue.ID] = true
+			}
+			if page.NextCursor == "" {
+				if pages != 3 {
+					t.Errorf("got %d pages, want 3", pages)
+				}
+				break
+			}
+			cursor = page.NextCursor
+		}
+		for id := range want {
+			if !seen[id] {
+				t.Errorf("%s never returned", id)
+			}
+		}
+	}
+
+	t.Run("list", func(t *testing.T) { walk(t, "list") })
+	t.Run("ready", func(t *testing.T) { walk(t, "ready") })
+	t.Run("search", func(t *testing.T) { walk(t, "search", "Paged") })
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		res := p.RunCommand("list", "--cursor", "not-a-cursor", "--json")
+		if res.ExitCode() != ErrCodeInvalid.ExitCode() {
+			t.Fatalf("exit = %d, want %d\nstderr: %s", res.ExitCode(), ErrCodeInvalid.ExitCode(), res.Stderr)
+		}
+	})
+
+	t.Run("no cursor keeps arrays", func(t *testing.T) {
+		var issues []*types.IssueWithCounts
+		p.RunJSON(&issues, "list", "--limit", "2")
+		if len(issues) != 2 {
+			t.Fatalf("got %d issues, want 2", len(issues))
+		}
+	})
+}
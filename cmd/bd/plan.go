@@ -0,0 +1,528 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+// planRefPrefix namespaces the external_ref that ties an issue to the plan
+// entry that owns it: plan:<plan-name>:<key>.
+const planRefPrefix = "plan:"
+
+var planNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// planFile is a declarative description of a set of issues.
+type planFile struct {
+	Name   string            `yaml:"name"`
+	Issues []*batchIssueSpec `yaml:"issues"`
+}
+
+// planAction is what `bd plan apply` will do for one plan entry.
+type planAction string
+
+const (
+	planCreate planAction = "create"
+	planUpdate planAction = "update"
+	planNoop   planAction = "no-op"
+	planClose  planAction = "close"
+)
+
+// planChange is the diff for one plan entry (or an orphaned issue).
+type planChange struct {
+	Action        planAction             `json:"action"`
+	Key           string                 `json:"key"`
+	ID            string                 `json:"id,omitempty"`
+	Title         string                 `json:"title"`
+	Fields        map[string]interface{} `json:"fields,omitempty"` // Field updates
+	Changes       []string               `json:"changes,omitempty"`
+	AddLabels     []string               `json:"add_labels,omitempty"`
+	RemoveLabels  []string               `json:"remove_labels,omitempty"`
+	AddDeps       []batchDep             `json:"-"`
+	RemoveDepsIDs []string               `json:"-"`
+	index         int                    // Index into the batch plan (-1 for orphans)
+}
+
+// planDiff is the full set of changes for a plan.
+type planDiff struct {
+	Name    string        `json:"name"`
+	Changes []*planChange `json:"changes"`
+	batch   *batchPlan
+}
+
+// Counts returns the number of changes per action.
+func (d *planDiff) Counts() map[planAction]int {
+	counts := make(map[planAction]int)
+	for _, c := range d.Changes {
+		counts[c.Action]++
+	}
+	return counts
+}
+
+var planCmd = &cobra.Command{
+	Use:     "plan",
+	GroupID: "issues",
+	Short:   "Manage issues declaratively from a plan file",
+	Long: `Manage a set of issues declaratively from a plan file.
+
+A plan file lists the issues you want (epics, tasks, and their dependencies).
+'bd plan diff' shows what would change and 'bd plan apply' makes it so:
+missing issues are created, changed fields are updated, and everything else
+is left alone. Applying the same plan twice is a no-op.
+
+Plan files are YAML, or Markdown with the plan in YAML front matter:
+
+  name: auth-rewrite          # Identifies the plan (defaults to the file name)
+  issues:
+    - key: epic               # Stable key, unique within the plan
+      title: Rewrite auth
+      type: epic
+      priority: P1
+    - key: login
+      title: New login form
+      parent: epic            # Key in this plan, or an existing issue ID
+      labels: [ui]
+    - key: sessions
+      title: Session store
+      parent: epic
+      deps: [login]           # "key", "type:key", or an existing issue ID
+
+Issue fields are the same as for 'bd create --batch'. Each issue created by a
+plan records plan:<name>:<key> as its external_ref; that is how later runs
+find it. Use absolute dates for due/defer so they don't drift between runs.`,
+}
+
+var planDiffCmd = &cobra.Command{
+	Use:   "diff <plan-file>",
+	Short: "Show what applying a plan would change",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		prune, _ := cmd.Flags().GetBool("prune")
+		diff := loadPlanDiff(args[0], prune)
+		if jsonOutput {
+			outputJSON(diff)
+			return
+		}
+		printPlanDiff(diff)
+	},
+}
+
+var planApplyCmd = &cobra.Command{
+	Use:   "apply <plan-file>",
+	Short: "Create and update issues to match a plan",
+	Long: `Create and update issues to match a plan file, in a single transaction.
+
+With --prune, open issues that were created by this plan but are no longer
+in it are closed.
+
+Examples:
+  bd plan apply plan.yaml
+  bd plan apply roadmap.md --prune
+  bd plan apply plan.yaml --dry-run   # Same as bd plan diff`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		prune, _ := cmd.Flags().GetBool("prune")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if !dryRun {
+			CheckReadonly("plan apply")
+		}
+		diff := loadPlanDiff(args[0], prune)
+
+		if dryRun {
+			if jsonOutput {
+				outputJSON(diff)
+				return
+			}
+			printPlanDiff(diff)
+			return
+		}
+
+		counts := diff.Counts()
+		if counts[planCreate]+counts[planUpdate]+counts[planClose] > 0 {
+			if err := applyPlanDiff(rootCtx, diff); err != nil {
+				FatalError("plan apply failed, nothing was changed: %v", err)
+			}
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(diff)
+			return
+		}
+		printPlanDiff(diff)
+		fmt.Printf("\n%s Applied plan %s\n", ui.RenderPass("✓"), diff.Name)
+	},
+}
+
+// loadPlanDiff reads a plan file and diffs it against the database, exiting
+// on error.
+func loadPlanDiff(path string, prune bool) *planDiff {
+	if err := ensureDirectMode("plan requires direct database access"); err != nil {
+		FatalError("%v", err)
+	}
+	if actor == "" {
+		actor = "bd"
+	}
+	plan, err := readPlanFile(path)
+	if err != nil {
+		FatalError("%v", err)
+	}
+	diff, err := diffPlan(rootCtx, plan, prune, time.Now())
+	if err != nil {
+		FatalError("%v", err)
+	}
+	return diff
+}
+
+// readPlanFile parses a YAML plan, or Markdown with YAML front matter.
+func readPlanFile(path string) (*planFile, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-specified plan file
+	if err != nil {
+		return nil, err
+	}
+	plan, err := parsePlan(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if plan.Name == "" {
+		plan.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if !planNameRe.MatchString(plan.Name) {
+		return nil, fmt.Errorf("%s: invalid plan name %q (use letters, digits, '.', '_', '-')", path, plan.Name)
+	}
+	return plan, nil
+}
+
+// parsePlan parses plan YAML, extracting it from Markdown front matter if present.
+func parsePlan(data []byte) (*planFile, error) {
+	trimmed := bytes.TrimLeft(data, "\ufeff \t\r\n")
+	if bytes.HasPrefix(trimmed, []byte("---")) {
+		rest := trimmed[3:]
+		end := bytes.Index(rest, []byte("\n---"))
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated front matter")
+		}
+		trimmed = rest[:end]
+	}
+
+	var plan planFile
+	if err := yaml.Unmarshal(trimmed, &plan); err != nil {
+		return nil, fmt.Errorf("parsing plan: %w", err)
+	}
+	if len(plan.Issues) == 0 {
+		return nil, fmt.Errorf("plan has no issues")
+	}
+	for i, spec := range plan.Issues {
+		if spec.Key == "" {
+			return nil, fmt.Errorf("issue %d (%q): key is required in plans", i+1, spec.Title)
+		}
+		if spec.ExternalRef != "" || spec.ID != "" {
+			return nil, fmt.Errorf("issue %q: id and external_ref are managed by the plan", spec.Key)
+		}
+	}
+	return &plan, nil
+}
+
+// planRef returns the external_ref for a plan entry.
+func planRef(planName, key string) string {
+	return planRefPrefix + planName + ":" + key
+}
+
+// diffPlan compares a plan with the database. Nothing is written.
+func diffPlan(ctx context.Context, plan *planFile, prune bool, now time.Time) (*planDiff, error) {
+	for _, spec := range plan.Issues {
+		spec.ExternalRef = planRef(plan.Name, spec.Key)
+	}
+	batch, err := planBatch(ctx, plan.Issues, now)
+	if err != nil {
+		return nil, err
+	}
+	diff := &planDiff{Name: plan.Name, batch: batch}
+
+	// Every issue this plan has created, including ones since dropped from it.
+	all, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, err
+	}
+	prefix := planRefPrefix + plan.Name + ":"
+	var planIssues []*types.Issue
+	owned := make(map[string]*types.Issue) // By key
+	for _, issue := range all {
+		if issue.ExternalRef == nil || !strings.HasPrefix(*issue.ExternalRef, prefix) || issue.Status == types.StatusTombstone {
+			continue
+		}
+		planIssues = append(planIssues, issue)
+		owned[strings.TrimPrefix(*issue.ExternalRef, prefix)] = issue
+	}
+	targetID := func(ref string) string {
+		if idx, ok := batch.Keys[ref]; ok {
+			if existing := owned[batch.Specs[idx].Key]; existing != nil {
+				return existing.ID
+			}
+			return ""
+		}
+		return batch.IDs[ref]
+	}
+
+	for i, spec := range plan.Issues {
+		desired := batch.Issues[i]
+		existing := owned[spec.Key]
+		if existing == nil {
+			diff.Changes = append(diff.Changes, &planChange{
+				Action: planCreate, Key: spec.Key, Title: desired.Title,
+				AddLabels: spec.Labels, AddDeps: batch.Deps[i], index: i,
+			})
+			continue
+		}
+
+		// Search results omit scheduling fields; load the full issue to compare.
+		existing, err := store.GetIssue(ctx, existing.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		change := &planChange{Action: planNoop, Key: spec.Key, ID: existing.ID, Title: desired.Title, index: i}
+		change.Fields, change.Changes = diffPlanFields(existing, desired)
+
+		labels, err := store.GetLabels(ctx, existing.ID)
+		if err != nil {
+			return nil, err
+		}
+		change.AddLabels, change.RemoveLabels = diffStringSets(labels, spec.Labels)
+		for _, l := range change.AddLabels {
+			change.Changes = append(change.Changes, "label +"+l)
+		}
+		for _, l := range change.RemoveLabels {
+			change.Changes = append(change.Changes, "label -"+l)
+		}
+
+		records, err := store.GetDependencyRecords(ctx, existing.ID)
+		if err != nil {
+			return nil, err
+		}
+		have := make(map[string]types.DependencyType, len(records))
+		for _, r := range records {
+			have[r.DependsOnID] = r.Type
+		}
+		want := make(map[string]bool)
+		for _, dep := range batch.Deps[i] {
+			id := targetID(dep.Target)
+			if id != "" {
+				want[id] = true
+				if have[id] == dep.Type {
+					continue
+				}
+			}
+			change.AddDeps = append(change.AddDeps, dep)
+			change.Changes = append(change.Changes, fmt.Sprintf("dep +%s:%s", dep.Type, dep.Target))
+		}
+		// Drop edges to other plan issues that the plan no longer declares.
+		for _, other := range planIssues {
+			if _, linked := have[other.ID]; linked && !want[other.ID] {
+				change.RemoveDepsIDs = append(change.RemoveDepsIDs, other.ID)
+				change.Changes = append(change.Changes, "dep -"+other.ID)
+			}
+		}
+
+		if len(change.Changes) > 0 {
+			change.Action = planUpdate
+		}
+		diff.Changes = append(diff.Changes, change)
+	}
+
+	if prune {
+		for _, issue := range planIssues {
+			key := strings.TrimPrefix(*issue.ExternalRef, prefix)
+			if _, inPlan := batch.Keys[key]; inPlan || issue.Status == types.StatusClosed {
+				continue
+			}
+			diff.Changes = append(diff.Changes, &planChange{
+				Action: planClose, Key: key, ID: issue.ID, Title: issue.Title, index: -1,
+			})
+		}
+	}
+	return diff, nil
+}
+
+// diffPlanFields returns the updates needed to make existing match desired,
+// plus human-readable descriptions of each.
+func diffPlanFields(existing, desired *types.Issue) (map[string]interface{}, []string) {
+	fields := make(map[string]interface{})
+	var changes []string
+	setString := func(name, have, want string) {
+		if have != want {
+			fields[name] = want
+			changes = append(changes, name)
+		}
+	}
+	setString("title", existing.Title, desired.Title)
+	setString("description", existing.Description, desired.Description)
+	setString("design", existing.Design, desired.Design)
+	setString("acceptance_criteria", existing.AcceptanceCriteria, desired.AcceptanceCriteria)
+	setString("notes", existing.Notes, desired.Notes)
+	setString("assignee", existing.Assignee, desired.Assignee)
+	if existing.IssueType != desired.IssueType {
+		fields["issue_type"] = string(desired.IssueType)
+		changes = append(changes, fmt.Sprintf("type %s → %s", existing.IssueType, desired.IssueType))
+	}
+	if existing.Priority != desired.Priority {
+		fields["priority"] = desired.Priority
+		changes = append(changes, fmt.Sprintf("priority P%d → P%d", existing.Priority, desired.Priority))
+	}
+	if desired.EstimatedMinutes != nil && !intPtrEqual(existing.EstimatedMinutes, desired.EstimatedMinutes) {
+		fields["estimated_minutes"] = *desired.EstimatedMinutes
+		changes = append(changes, "estimate")
+	}
+	sameDay := func(a, b *time.Time) bool {
+		return a != nil && b != nil && a.Local().Format("2006-01-02") == b.Local().Format("2006-01-02")
+	}
+	if desired.DueAt != nil && !sameDay(existing.DueAt, desired.DueAt) {
+		fields["due_at"] = *desired.DueAt
+		changes = append(changes, "due")
+	}
+	if desired.DeferUntil != nil && !sameDay(existing.DeferUntil, desired.DeferUntil) {
+		fields["defer_until"] = *desired.DeferUntil
+		changes = append(changes, "defer")
+	}
+	return fields, changes
+}
+
+// diffStringSets returns the elements to add to and remove from have to get want.
+func diffStringSets(have, want []string) (add, remove []string) {
+	haveSet := make(map[string]bool, len(have))
+	for _, s := range have {
+		haveSet[s] = true
+	}
+	wantSet := make(map[string]bool, len(want))
+	for _, s := range want {
+		wantSet[s] = true
+		if !haveSet[s] {
+			add = append(add, s)
+		}
+	}
+	for _, s := range have {
+		if !wantSet[s] {
+			remove = append(remove, s)
+		}
+	}
+	sort.Strings(add)
+	sort.Strings(remove)
+	return add, remove
+}
+
+// applyPlanDiff writes a plan diff in one transaction.
+func applyPlanDiff(ctx context.Context, diff *planDiff) error {
+	batch := diff.batch
+	return store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		// Create first so keys of new issues resolve to real IDs.
+		keyIDs := make(map[string]string)
+		for _, c := range diff.Changes {
+			switch c.Action {
+			case planCreate:
+				issue := batch.Issues[c.index]
+				issue.Labels = c.AddLabels // Added below; set so the validation rules see them
+				if err := tx.CreateIssue(ctx, issue, actor); err != nil {
+					return fmt.Errorf("creating %q: %w", c.Key, err)
+				}
+				c.ID = issue.ID
+				keyIDs[c.Key] = issue.ID
+			case planUpdate, planNoop:
+				keyIDs[c.Key] = c.ID
+			}
+		}
+		resolve := func(ref string) string {
+			if id, ok := keyIDs[ref]; ok {
+				return id
+			}
+			return batch.IDs[ref]
+		}
+
+		for _, c := range diff.Changes {
+			switch c.Action {
+			case planCreate, planUpdate:
+				// Labels go on before field changes and come off after, so
+				// the validation rules see each step with the labels wanted
+				for _, l := range c.AddLabels {
+					if err := tx.AddLabel(ctx, c.ID, l, actor); err != nil {
+						return fmt.Errorf("adding label %s to %s: %w", l, c.ID, err)
+					}
+				}
+				if len(c.Fields) > 0 {
+					if err := tx.UpdateIssue(ctx, c.ID, c.Fields, actor); err != nil {
+						return fmt.Errorf("updating %s: %w", c.ID, err)
+					}
+				}
+				for _, l := range c.RemoveLabels {
+					if err := tx.RemoveLabel(ctx, c.ID, l, actor); err != nil {
+						return fmt.Errorf("removing label %s from %s: %w", l, c.ID, err)
+					}
+				}
+				for _, id := range c.RemoveDepsIDs {
+					if err := tx.RemoveDependency(ctx, c.ID, id, actor); err != nil {
+						return fmt.Errorf("removing dependency %s -> %s: %w", c.ID, id, err)
+					}
+				}
+				for _, dep := range c.AddDeps {
+					target := resolve(dep.Target)
+					if err := tx.AddDependency(ctx, &types.Dependency{
+						IssueID: c.ID, DependsOnID: target, Type: dep.Type,
+					}, actor); err != nil {
+						return fmt.Errorf("adding dependency %s -> %s: %w", c.ID, target, err)
+					}
+				}
+			case planClose:
+				reason := fmt.Sprintf("Removed from plan %s", diff.Name)
+				if err := tx.CloseIssue(ctx, c.ID, reason, actor, ""); err != nil {
+					return fmt.Errorf("closing %s: %w", c.ID, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// printPlanDiff prints a terraform-style summary of a plan diff.
+func printPlanDiff(diff *planDiff) {
+	fmt.Printf("\nPlan %s:\n\n", ui.RenderBold(diff.Name))
+	for _, c := range diff.Changes {
+		id := c.ID
+		if id == "" {
+			id = "(new)"
+		}
+		switch c.Action {
+		case planCreate:
+			fmt.Printf("  %s %-16s %-10s %s\n", ui.RenderPass("+"), c.Key, id, c.Title)
+		case planUpdate:
+			fmt.Printf("  %s %-16s %-10s %s\n", ui.RenderWarn("~"), c.Key, id, c.Title)
+			fmt.Printf("      %s\n", ui.RenderMuted(strings.Join(c.Changes, ", ")))
+		case planClose:
+			fmt.Printf("  %s %-16s %-10s %s\n", ui.RenderFail("-"), c.Key, id, c.Title)
+		default:
+			fmt.Printf("  %s %-16s %-10s %s\n", ui.RenderMuted("="), c.Key, id, ui.RenderMuted(c.Title))
+		}
+	}
+	counts := diff.Counts()
+	fmt.Printf("\n%d to create, %d to update, %d unchanged, %d to close\n",
+		counts[planCreate], counts[planUpdate], counts[planNoop], counts[planClose])
+}
+
+func init() {
+	planDiffCmd.Flags().Bool("prune", false, "Also show plan issues that would be closed because they were removed from the plan")
+	planApplyCmd.Flags().Bool("prune", false, "Close open issues created by this plan that are no longer in it")
+	planApplyCmd.Flags().Bool("dry-run", false, "Show changes without applying them")
+	planCmd.AddCommand(planDiffCmd)
+	planCmd.AddCommand(planApplyCmd)
+	rootCmd.AddCommand(planCmd)
+}
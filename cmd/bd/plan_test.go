@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParsePlanFrontMatter(t *testing.T) {
+	plan, err := parsePlan([]byte(`---
+name: roadmap
+issues:
+  - key: epic
+    title: Epic
+    type: epic
+  - key: a
+    title: A
+    parent: epic
+---
+
+# Roadmap
+
+Prose below the front matter is ignored.
+`))
+	if err != nil {
+		t.Fatalf("parsePlan: %v", err)
+	}
+	if plan.Name != "roadmap" || len(plan.Issues) != 2 || plan.Issues[1].Parent != "epic" {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+
+	for _, bad := range []string{
+		"issues:\n  - title: No key\n",
+		"issues:\n  - key: a\n    title: A\n    external_ref: gh-1\n",
+		"name: empty\n",
+		"---\nissues: []\n",
+	} {
+		if _, err := parsePlan([]byte(bad)); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestPlanApplyIdempotent(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	oldStore := store
+	defer func() { store = oldStore }()
+	store = s
+
+	load := func(src string) *planFile {
+		t.Helper()
+		plan, err := parsePlan([]byte(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return plan
+	}
+	v1 := `name: p
+issues:
+  - key: epic
+    title: Epic
+    type: epic
+  - key: a
+    title: A
+    parent: epic
+    labels: [x]
+  - key: b
+    title: B
+    parent: epic
+    deps: [a]
+`
+	diff, err := diffPlan(ctx, load(v1), false, time.Now())
+	if err != nil {
+		t.Fatalf("diffPlan: %v", err)
+	}
+	if got := diff.Counts()[planCreate]; got != 3 {
+		t.Fatalf("expected 3 creates, got %d", got)
+	}
+	if err := applyPlanDiff(ctx, diff); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	// Re-applying the same plan changes nothing.
+	diff, err = diffPlan(ctx, load(v1), false, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := diff.Counts()[planNoop]; got != 3 {
+		t.Fatalf("expected 3 no-ops, got %+v", diff.Counts())
+	}
+
+	// Retitle b, swap its label set, drop its dep on a, and remove a.
+	v2 := `name: p
+issues:
+  - key: epic
+    title: Epic
+    type: epic
+  - key: b
+    title: B renamed
+    parent: epic
+    labels: [y]
+`
+	diff, err = diffPlan(ctx, load(v2), true, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := diff.Counts()
+	if counts[planUpdate] != 1 || counts[planClose] != 1 || counts[planNoop] != 1 {
+		t.Fatalf("unexpected counts: %+v", counts)
+	}
+	if err := applyPlanDiff(ctx, diff); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	b, err := s.GetIssueByExternalRef(ctx, planRef("p", "b"))
+	if err != nil || b == nil {
+		t.Fatalf("lookup b: %v", err)
+	}
+	if b.Title != "B renamed" {
+		t.Errorf("title = %q", b.Title)
+	}
+	labels, _ := s.GetLabels(ctx, b.ID)
+	if len(labels) != 1 || labels[0] != "y" {
+		t.Errorf("labels = %v", labels)
+	}
+	deps, _ := s.GetDependencyRecords(ctx, b.ID)
+	if len(deps) != 1 || deps[0].Type != types.DepParentChild {
+		t.Errorf("deps = %+v", deps)
+	}
+	a, _ := s.GetIssueByExternalRef(ctx, planRef("p", "a"))
+	if a == nil || a.Status != types.StatusClosed {
+		t.Errorf("expected a to be closed, got %+v", a)
+	}
+}
+
+func TestDiffStringSets(t *testing.T) {
+	add, remove := diffStringSets([]string{"a", "b"}, []string{"b", "c"})
+	if len(add) != 1 || add[0] != "c" || len(remove) != 1 || remove[0] != "a" {
+		t.Errorf("add=%v remove=%v", add, remove)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/portfolio"
+	"github.com/steveyegge/beads/internal/routing"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+var portfolioCmd = &cobra.Command{
+	Use:     "portfolio",
+	GroupID: "views",
+	Short:   "Health-scored summary of every project in the workspace",
+	Long: `Summarize this project and every repository registered with bd repo add
+in one table: open issues, how many are blocked, how many have gone stale,
+how many break their service level, and a health score from 0 to 100.
+
+The score starts at 100 and loses up to 30 points for the blocked share of
+open work, 30 for the stale share and 40 for the share breaking its SLA.
+Projects scoring 80 or more are healthy, 50 or more at risk, the rest
+critical. The worst projects are listed first.
+
+An open issue breaks its SLA when it is past its due date or has been open
+longer than portfolio.sla allows for its priority:
+  portfolio:
+    sla: p0=1d,p1=7d,p2=30d
+
+This project is read from its database; other projects from their committed
+issues.jsonl, so they show what their last sync pushed. Private issues are
+not counted.`,
+	Example: `  bd portfolio                   # Table of every registered project
+  bd portfolio --stale-days 14   # Count issues idle for two weeks as stale
+  bd portfolio --sla p0=4h,p1=3d # Override portfolio.sla
+  bd portfolio --json            # Scores for dashboards`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		staleDays, _ := cmd.Flags().GetInt("stale-days")
+		slaSpec, _ := cmd.Flags().GetString("sla")
+		if !cmd.Flags().Changed("sla") {
+			slaSpec = config.GetString("portfolio.sla")
+		}
+		sla, err := portfolio.ParseSLA(slaSpec)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if err := ensureDirectMode("portfolio requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+
+		opts := portfolio.Options{StaleDays: staleDays, SLA: sla}
+		projects := buildPortfolio(rootCtx, time.Now(), opts)
+		if jsonOutput {
+			outputJSON(projects)
+			return
+		}
+		printPortfolio(projects)
+	},
+}
+
+// buildPortfolio assesses this project and each repos.additional entry,
+// worst first.
+func buildPortfolio(ctx context.Context, now time.Time, opts portfolio.Options) []portfolio.Project {
+	root := "."
+	if dbPath != "" {
+		root = filepath.Dir(filepath.Dir(dbPath))
+	}
+
+	var projects []portfolio.Project
+	issues, err := localPortfolioIssues(ctx)
+	if err != nil {
+		projects = append(projects, portfolio.Failed(filepath.Base(root), ".", err))
+	} else {
+		projects = append(projects, portfolio.Assess(filepath.Base(root), ".", issues, now, opts))
+	}
+
+	for _, repo := range config.GetStringSlice("repos.additional") {
+		path := repo
+		if !strings.HasPrefix(path, "~") && !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		path = routing.ExpandPath(path)
+		name := filepath.Base(path)
+
+		issues, err := loadIssuesFromJSONL(filepath.Join(path, ".beads", "issues.jsonl"))
+		if err != nil {
+			projects = append(projects, portfolio.Failed(name, repo, err))
+			continue
+		}
+		projects = append(projects, portfolio.Assess(name, repo, issues, now, opts))
+	}
+
+	portfolio.Sort(projects)
+	return projects
+}
+
+// localPortfolioIssues loads this project's own issues with their
+// dependencies, leaving out issues hydrated from other repositories and
+// private issues.
+func localPortfolioIssues(ctx context.Context) ([]*types.Issue, error) {
+	all, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, err
+	}
+	deps, err := store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []*types.Issue
+	for _, issue := range all {
+		if issue.SourceRepo != "" && issue.SourceRepo != "." {
+			continue
+		}
+		issue.Dependencies = deps[issue.ID]
+		issues = append(issues, issue)
+	}
+	if err := visibility.Populate(ctx, store, issues); err != nil {
+		return nil, err
+	}
+	return visibility.Filter(issues, visibility.Internal), nil
+}
+
+func printPortfolio(projects []portfolio.Project) {
+	nameWidth := len("PROJECT")
+	for _, p := range projects {
+		nameWidth = max(nameWidth, len(p.Name))
+	}
+	fmt.Printf("%-*s  %5s  %4s  %7s  %5s  %3s  %-13s  %s\n",
+		nameWidth, "PROJECT", "SCORE", "OPEN", "BLOCKED", "STALE", "SLA", "LAST ACTIVITY", "HEALTH")
+	for _, p := range projects {
+		if p.Error != "" {
+			fmt.Printf("%-*s  %s\n", nameWidth, p.Name, ui.RenderWarn("unreadable: "+p.Error))
+			continue
+		}
+		last := "-"
+		if p.LastActivity != nil {
+			last = formatTimeAgo(*p.LastActivity)
+		}
+		blocked := fmt.Sprintf("%d (%d%%)", p.Blocked, int(p.BlockedRatio*100+0.5))
+		fmt.Printf("%-*s  %5d  %4d  %7s  %5d  %3d  %-13s  %s\n",
+			nameWidth, p.Name, p.Score, p.Open, blocked, p.Stale, p.SLABreaches, last, renderHealth(p.Health))
+	}
+}
+
+func renderHealth(health string) string {
+	switch health {
+	case portfolio.Healthy:
+		return ui.RenderPass(health)
+	case portfolio.AtRisk:
+		return ui.RenderWarn(health)
+	default:
+		return ui.RenderFail(health)
+	}
+}
+
+func init() {
+	portfolioCmd.Flags().Int("stale-days", portfolio.DefaultStaleDays, "Days without an update before an open issue counts as stale")
+	portfolioCmd.Flags().String("sla", portfolio.DefaultSLA, "Longest an issue may stay open by priority (overrides portfolio.sla)")
+	rootCmd.AddCommand(portfolioCmd)
+}
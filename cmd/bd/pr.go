@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// PRLink is a pull request linked to an issue, with its last fetched status.
+type PRLink struct {
+	IssueID string          `json:"issue_id"`
+	URL     string          `json:"url"`
+	Status  *types.PRStatus `json:"status,omitempty"` // nil until fetched
+}
+
+var prCmd = &cobra.Command{
+	Use:     "pr",
+	GroupID: "issues",
+	Short:   "Link issues to GitHub pull requests and track their status",
+	Long: `Link issues to the GitHub pull requests that implement them, and see each
+pull request's state (open, draft, merged, closed), review status
+(approved, changes requested, pending) and CI status.
+
+A linked pull request is a github ref (bd ref) holding its canonical URL,
+so links sync with the issue. Statuses are fetched from the GitHub API and
+cached per clone; bd show and bd list --with-pr display them. The daemon
+can refresh them periodically:
+  bd config set pr.refresh-interval 15m
+
+Private repositories and higher rate limits need GITHUB_TOKEN or
+'bd auth login github'. Unlink with 'bd ref remove <id> <url>'.`,
+}
+
+var prLinkCmd = &cobra.Command{
+	Use:   "link <issue-id> [pull-request]",
+	Short: "Link a pull request to an issue",
+	Long: `Link a pull request to an issue and fetch its status.
+
+The pull request can be given as a URL, owner/repo#N, github.com/owner/repo#N,
+or #N in the project's repository (github.org and github.repo, else the
+origin remote). Without one, the newest pull request from the current
+branch is linked.`,
+	Example: `  bd pr link bd-12 github.com/org/repo#456
+  bd pr link bd-12 https://github.com/org/repo/pull/456
+  bd pr link bd-12 '#456'
+  bd pr link bd-12                 # Pull request of the current branch`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("pr link")
+		noFetch, _ := cmd.Flags().GetBool("no-fetch")
+		rs, id := refStoreAndID(args[0])
+		ctx := rootCtx
+		checker := newRefChecker(ctx, store, refDocRoot())
+		repo := prDefaultRepo(ctx, checker)
+
+		var prURL string
+		if len(args) == 2 {
+			var err error
+			if prURL, err = refs.NormalizePR(args[1], repo); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		} else {
+			if repo == "" {
+				FatalErrorWithHint("can't tell which GitHub repository to search",
+					"set github.org and github.repo, or give the pull request explicitly")
+			}
+			branch, err := getCurrentBranch(ctx)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if prURL, err = checker.FindPR(ctx, repo, branch); err != nil {
+				FatalErrorRespectJSON("searching %s for a pull request from %s: %v", repo, branch, err)
+			}
+			if prURL == "" {
+				FatalErrorWithHint(fmt.Sprintf("no pull request from branch %s in %s", branch, repo),
+					"open one first, or give the pull request explicitly")
+			}
+		}
+
+		if err := rs.AddRef(ctx, id, types.Ref{Type: types.RefGitHub, Value: prURL}, actor); err != nil {
+			FatalErrorRespectJSON("failed to link pull request: %v", err)
+		}
+		markDirtyAndScheduleFlush()
+
+		link := &PRLink{IssueID: id, URL: prURL}
+		if !noFetch {
+			statuses, err := refs.RefreshPRs(ctx, store, checker, []string{prURL})
+			if err != nil {
+				WarnError("caching pull request status: %v", err)
+			}
+			if len(statuses) > 0 {
+				link.Status = &statuses[0]
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(link)
+			return
+		}
+		fmt.Printf("%s Linked %s to %s\n", ui.RenderPass("✓"), prURL, id)
+		if link.Status != nil {
+			fmt.Printf("  %s\n", formatPRStatus(prURL, link.Status))
+		}
+	},
+}
+
+var prStatusCmd = &cobra.Command{
+	Use:   "status [issue-id...]",
+	Short: "Show the status of linked pull requests",
+	Long: `Show the state, review status and CI status of the pull requests linked
+to the given issues, or to every issue that isn't closed.
+
+Statuses come from the local cache unless --refresh fetches them again.`,
+	Example: `  bd pr status
+  bd pr status bd-12 --refresh
+  bd pr status --all --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		refresh, _ := cmd.Flags().GetBool("refresh")
+		all, _ := cmd.Flags().GetBool("all")
+		if refresh {
+			CheckReadonly("pr status --refresh")
+		}
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		var issues []*types.Issue
+		if len(args) > 0 {
+			for _, arg := range args {
+				id, err := utils.ResolvePartialID(ctx, store, arg)
+				if err != nil {
+					FatalErrorRespectJSON("resolving %s: %v", arg, err)
+				}
+				issue, err := store.GetIssue(ctx, id)
+				if err != nil || issue == nil {
+					FatalErrorRespectJSON("issue %s not found", id)
+				}
+				issues = append(issues, issue)
+			}
+		} else {
+			filter := types.IssueFilter{}
+			if !all {
+				filter.ExcludeStatus = []types.Status{types.StatusClosed}
+			}
+			var err error
+			if issues, err = store.SearchIssues(ctx, "", filter); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
+		if err := refs.Populate(ctx, store, issues); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if refresh {
+			var urls []string
+			for _, issue := range issues {
+				urls = append(urls, refs.PullRequests(issue)...)
+			}
+			if _, err := refs.RefreshPRs(ctx, store, newRefChecker(ctx, store, refDocRoot()), urls); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
+		statuses, err := refs.PRStatuses(ctx, store)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		links := []*PRLink{}
+		for _, issue := range issues {
+			for _, u := range refs.PullRequests(issue) {
+				links = append(links, &PRLink{IssueID: issue.ID, URL: u, Status: statuses[u]})
+			}
+		}
+		if jsonOutput {
+			outputJSON(links)
+			return
+		}
+		if len(links) == 0 {
+			fmt.Println("No linked pull requests. Link one with 'bd pr link <id> <pull-request>'.")
+			return
+		}
+		titles := make(map[string]string, len(issues))
+		for _, issue := range issues {
+			titles[issue.ID] = issue.Title
+		}
+		last := ""
+		for _, link := range links {
+			if link.IssueID != last {
+				fmt.Printf("%s %s\n", ui.RenderID(link.IssueID), titles[link.IssueID])
+				last = link.IssueID
+			}
+			fmt.Printf("  %s\n", formatPRStatus(link.URL, link.Status))
+		}
+	},
+}
+
+// prDefaultRepo is the owner/repo that bare pull request numbers refer to:
+// github.org and github.repo, else the origin remote if it is on GitHub.
+func prDefaultRepo(ctx context.Context, checker *refs.Checker) string {
+	if strings.Contains(checker.GitHubRepo, "/") {
+		return checker.GitHubRepo
+	}
+	var cmd *exec.Cmd
+	if rc, err := beads.GetRepoContext(); err == nil {
+		cmd = rc.GitCmd(ctx, "remote", "get-url", "origin")
+	} else {
+		cmd = exec.CommandContext(ctx, "git", "remote", "get-url", "origin")
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return refs.RepoFromRemote(string(out))
+}
+
+// formatPRStatus renders one pull request as a line: state, review and CI,
+// then its URL and title.
+func formatPRStatus(prURL string, pr *types.PRStatus) string {
+	if pr == nil {
+		return prURL + ui.RenderMuted("  (status not fetched)")
+	}
+	var parts []string
+	switch pr.State {
+	case types.PRStateOpen:
+		parts = append(parts, ui.RenderPass("open"))
+	case types.PRStateMerged:
+		parts = append(parts, ui.RenderAccent("merged"))
+	case types.PRStateClosed:
+		parts = append(parts, ui.RenderFail("closed"))
+	case types.PRStateDraft:
+		parts = append(parts, ui.RenderMuted("draft"))
+	default:
+		parts = append(parts, ui.RenderWarn("unknown"))
+	}
+	switch pr.Review {
+	case types.PRReviewApproved:
+		parts = append(parts, ui.RenderPass("approved"))
+	case types.PRReviewChangesRequested:
+		parts = append(parts, ui.RenderFail("changes requested"))
+	case types.PRReviewPending:
+		parts = append(parts, ui.RenderMuted("review pending"))
+	}
+	switch pr.CI {
+	case types.PRCISuccess:
+		parts = append(parts, ui.RenderPass("✓ CI"))
+	case types.PRCIFailure:
+		parts = append(parts, ui.RenderFail("✗ CI"))
+	case types.PRCIPending:
+		parts = append(parts, ui.RenderWarn("● CI"))
+	}
+	line := strings.Join(parts, " · ") + "  " + prURL
+	if pr.Title != "" {
+		line += "  " + pr.Title
+	}
+	if pr.Detail != "" {
+		line += ui.RenderMuted("  " + pr.Detail)
+	}
+	return line
+}
+
+// printPullRequests prints the cached status of an issue's pull requests
+// in bd show. issue.Refs must already be populated.
+func printPullRequests(ctx context.Context, s storage.Storage, issue *types.Issue) {
+	urls := refs.PullRequests(issue)
+	if len(urls) == 0 {
+		return
+	}
+	statuses, _ := refs.PRStatuses(ctx, s)
+	for _, u := range urls {
+		fmt.Printf("PR: %s\n", formatPRStatus(u, statuses[u]))
+	}
+}
+
+// pullRequestLines renders the cached pull request statuses of issues for
+// bd list --with-pr, by issue ID.
+func pullRequestLines(ctx context.Context, s storage.Storage, issues []*types.Issue) map[string][]string {
+	if s == nil || refs.Populate(ctx, s, issues) != nil {
+		return nil
+	}
+	statuses, _ := refs.PRStatuses(ctx, s)
+	lines := make(map[string][]string)
+	for _, issue := range issues {
+		for _, u := range refs.PullRequests(issue) {
+			lines[issue.ID] = append(lines[issue.ID], "    PR: "+formatPRStatus(u, statuses[u]))
+		}
+	}
+	return lines
+}
+
+// writePRLines appends the lines of pullRequestLines for one issue.
+func writePRLines(buf *strings.Builder, lines []string) {
+	for _, line := range lines {
+		buf.WriteString(line + "\n")
+	}
+}
+
+// refreshDaemonPRs refreshes the pull requests of issues that aren't
+// closed. Merged pull requests don't change and are skipped.
+func refreshDaemonPRs(ctx context.Context, s storage.Storage, jsonlPath string, log daemonLogger) {
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{ExcludeStatus: []types.Status{types.StatusClosed}})
+	if err != nil {
+		log.log("PR refresh failed: %v", err)
+		return
+	}
+	if err := refs.Populate(ctx, s, issues); err != nil {
+		log.log("PR refresh failed: %v", err)
+		return
+	}
+	cached, _ := refs.PRStatuses(ctx, s)
+	var urls []string
+	for _, issue := range issues {
+		for _, u := range refs.PullRequests(issue) {
+			if pr := cached[u]; pr == nil || pr.State != types.PRStateMerged {
+				urls = append(urls, u)
+			}
+		}
+	}
+	if len(urls) == 0 {
+		return
+	}
+	start := time.Now()
+	checker := newRefChecker(ctx, s, filepath.Dir(filepath.Dir(jsonlPath)))
+	statuses, err := refs.RefreshPRs(ctx, s, checker, urls)
+	if err != nil {
+		log.log("PR refresh failed: %v", err)
+	}
+	log.log("PR refresh: %d pull request(s) in %v", len(statuses), time.Since(start).Round(time.Millisecond))
+}
+
+func init() {
+	prLinkCmd.Flags().Bool("no-fetch", false, "Link without fetching the pull request's status")
+	prLinkCmd.ValidArgsFunction = issueIDCompletion
+	prStatusCmd.Flags().Bool("refresh", false, "Fetch statuses from GitHub instead of using the cache")
+	prStatusCmd.Flags().Bool("all", false, "Include closed issues")
+	prStatusCmd.ValidArgsFunction = issueIDCompletion
+	prCmd.AddCommand(prLinkCmd)
+	prCmd.AddCommand(prStatusCmd)
+	rootCmd.AddCommand(prCmd)
+}
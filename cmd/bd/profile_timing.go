@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// processStart approximates when the process started so --profile can
+// report package initialization (command registration, config) separately.
+var processStart = time.Now()
+
+// phaseTimer records how long each startup phase takes for --profile.
+// A nil *phaseTimer is valid and records nothing, so call sites don't need
+// to check whether profiling is enabled.
+type phaseTimer struct {
+	start  time.Time
+	last   time.Time
+	phases []phaseTiming
+}
+
+type phaseTiming struct {
+	name     string
+	duration time.Duration
+}
+
+// startupTimer is non-nil while --profile is active.
+var startupTimer *phaseTimer
+
+func newPhaseTimer(start time.Time) *phaseTimer {
+	return &phaseTimer{start: start, last: start}
+}
+
+// mark attributes the time since the previous mark to the named phase.
+func (t *phaseTimer) mark(name string) {
+	if t == nil {
+		return
+	}
+	now := time.Now()
+	t.phases = append(t.phases, phaseTiming{name: name, duration: now.Sub(t.last)})
+	t.last = now
+}
+
+// total returns the time between the timer start and the last mark.
+func (t *phaseTimer) total() time.Duration {
+	return t.last.Sub(t.start)
+}
+
+// write prints the breakdown as an aligned table.
+func (t *phaseTimer) write(w io.Writer, cmdName string) {
+	if t == nil {
+		return
+	}
+	total := t.total()
+	_, _ = fmt.Fprintf(w, "\nProfile (bd %s): %s total\n", cmdName, formatPhaseDuration(total))
+	for _, p := range t.phases {
+		pct := 0.0
+		if total > 0 {
+			pct = float64(p.duration) * 100 / float64(total)
+		}
+		_, _ = fmt.Fprintf(w, "  %-14s %10s %5.1f%%\n", p.name, formatPhaseDuration(p.duration), pct)
+	}
+}
+
+func formatPhaseDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
+
+// printStartupProfile finishes the timer and prints the breakdown to stderr
+// along with the paths of the CPU profile and trace files.
+func printStartupProfile(w io.Writer, cmdName string) {
+	if startupTimer == nil {
+		return
+	}
+	startupTimer.mark("cleanup")
+	startupTimer.write(w, cmdName)
+	if profileFile != nil {
+		_, _ = fmt.Fprintf(w, "CPU profile: %s\n", profileFile.Name())
+	}
+	if traceFile != nil {
+		_, _ = fmt.Fprintf(w, "Trace: %s\n", traceFile.Name())
+	}
+	startupTimer = nil
+}
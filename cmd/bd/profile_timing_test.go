@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPhaseTimer(t *testing.T) {
+	timer := newPhaseTimer(time.Now())
+	timer.mark("config")
+	time.Sleep(2 * time.Millisecond)
+	timer.mark("open-store")
+
+	if len(timer.phases) != 2 {
+		t.Fatalf("expected 2 phases, got %d", len(timer.phases))
+	}
+	if timer.phases[1].duration < 2*time.Millisecond {
+		t.Errorf("open-store = %v, want >= 2ms", timer.phases[1].duration)
+	}
+	var sum time.Duration
+	for _, p := range timer.phases {
+		sum += p.duration
+	}
+	if sum != timer.total() {
+		t.Errorf("phases sum to %v, total is %v", sum, timer.total())
+	}
+
+	var buf bytes.Buffer
+	timer.write(&buf, "list")
+	out := buf.String()
+	for _, want := range []string{"Profile (bd list):", "config", "open-store", "%"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPhaseTimerNil(t *testing.T) {
+	var timer *phaseTimer
+	timer.mark("config")
+	var buf bytes.Buffer
+	timer.write(&buf, "list")
+	if buf.Len() != 0 {
+		t.Errorf("nil timer wrote output: %q", buf.String())
+	}
+}
+
+func TestPrintStartupProfile(t *testing.T) {
+	origTimer := startupTimer
+	defer func() { startupTimer = origTimer }()
+
+	startupTimer = newPhaseTimer(time.Now())
+	startupTimer.mark("config")
+	var buf bytes.Buffer
+	printStartupProfile(&buf, "ready")
+	if !strings.Contains(buf.String(), "cleanup") {
+		t.Errorf("expected cleanup phase in output:\n%s", buf.String())
+	}
+	if startupTimer != nil {
+		t.Error("expected timer to be cleared after printing")
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+
+	"github.com/steveyegge/beads/internal/ui"
+	"golang.org/x/term"
+)
+
+// noProgress is set by --no-progress (or the no-progress setting).
+var noProgress bool
+
+// progressEnabled reports whether long operations should draw progress bars:
+// only for a person watching stderr, never for --json, --quiet or scripts.
+func progressEnabled() bool {
+	return !noProgress && !jsonOutput && !quietFlag && !ui.IsAgentMode() && !ui.IsAccessible() &&
+		term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// newProgress starts a progress bar on stderr for label out of total steps.
+// It draws nothing when progressEnabled is false.
+func newProgress(label string, total int) *ui.Progress {
+	return ui.NewProgress(os.Stderr, label, total, progressEnabled())
+}
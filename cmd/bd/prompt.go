@@ -0,0 +1,214 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/configfile"
+	"github.com/steveyegge/beads/internal/debug"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/factory"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var promptCmd = &cobra.Command{
+	Use:     "prompt",
+	GroupID: "views",
+	Short:   "One-line work summary for shell prompts",
+	Long: `Print a one-line summary of ready, blocked and in-progress work, e.g.
+
+  3 ready ▪ 1 blocked ▪ bd-42 in_progress
+
+Designed to be called on every prompt render (PS1, starship, powerline):
+results are cached per project for --ttl and refreshed sooner when the
+database changes. Cache misses are answered by the daemon when it is
+running, otherwise the database is read directly. Outside a beads project,
+or on any error, it prints nothing and exits 0 so prompts never break.
+
+Examples:
+  bd prompt                      # 3 ready ▪ 1 blocked ▪ bd-42 in_progress
+  bd prompt --mine               # Only your in-progress issues
+  bd prompt --json               # {"ready":3,"blocked":1,"in_progress":["bd-42"]}
+
+  # bash/zsh
+  PS1='$(bd prompt 2>/dev/null) \$ '
+
+  # starship.toml
+  [custom.beads]
+  command = "bd prompt"
+  when = "test -d .beads"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		mine, _ := cmd.Flags().GetBool("mine")
+		assignee, _ := cmd.Flags().GetString("assignee")
+		if mine && assignee == "" {
+			assignee = getActorWithGit()
+		}
+
+		summary, err := promptSummary(assignee, ttl)
+		if err != nil {
+			debug.Logf("prompt: %v", err)
+			return
+		}
+		if summary == nil {
+			return
+		}
+		if jsonOutput {
+			outputJSON(summary)
+			return
+		}
+		fmt.Println(formatPromptSummary(summary))
+	},
+}
+
+// promptSummary returns the summary for the current project, from the cache
+// when it is fresh. It returns nil outside a beads project.
+func promptSummary(assignee string, ttl time.Duration) (*types.PromptSummary, error) {
+	if dbPath == "" {
+		dbPath = beads.FindDatabasePath()
+	}
+	beadsDir := beads.FindBeadsDir()
+	if dbPath != "" {
+		beadsDir = filepath.Dir(dbPath)
+	}
+	if beadsDir == "" {
+		return nil, nil
+	}
+
+	cachePath := promptCachePath(beadsDir, assignee)
+	if ttl > 0 {
+		if summary := readPromptCache(cachePath, beadsDir, ttl); summary != nil {
+			return summary, nil
+		}
+	}
+
+	summary, err := computePromptSummary(beadsDir, assignee)
+	if err != nil {
+		return nil, err
+	}
+	if ttl > 0 {
+		writePromptCache(cachePath, summary)
+	}
+	return summary, nil
+}
+
+// computePromptSummary asks the daemon, falling back to a read-only store.
+func computePromptSummary(beadsDir, assignee string) (*types.PromptSummary, error) {
+	if dbPath != "" && !noDaemon {
+		if client, _ := rpc.TryConnectWithTimeout(getSocketPath(), 100*time.Millisecond); client != nil {
+			defer func() { _ = client.Close() }()
+			resp, err := client.Prompt(&rpc.PromptArgs{Assignee: assignee})
+			if err == nil {
+				var summary types.PromptSummary
+				if err := json.Unmarshal(resp.Data, &summary); err != nil {
+					return nil, err
+				}
+				return &summary, nil
+			}
+			// Older daemons don't know the prompt operation; read directly
+		}
+	}
+
+	backend := factory.GetBackendFromConfig(beadsDir)
+	path := dbPath
+	if backend == configfile.BackendDolt {
+		path = filepath.Join(beadsDir, "dolt")
+	}
+	if path == "" {
+		return nil, nil
+	}
+	s, err := factory.NewWithOptions(rootCtx, backend, path, factory.Options{ReadOnly: true, LockTimeout: 100 * time.Millisecond})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = s.Close() }()
+	return storage.Summarize(rootCtx, s, assignee)
+}
+
+// formatPromptSummary renders the one-line summary.
+func formatPromptSummary(summary *types.PromptSummary) string {
+	parts := []string{fmt.Sprintf("%d ready", summary.Ready)}
+	if summary.Blocked > 0 {
+		parts = append(parts, fmt.Sprintf("%d blocked", summary.Blocked))
+	}
+	switch n := len(summary.InProgress); {
+	case n == 1:
+		parts = append(parts, summary.InProgress[0]+" in_progress")
+	case n > 1:
+		parts = append(parts, fmt.Sprintf("%s in_progress (+%d)", summary.InProgress[n-1], n-1))
+	}
+	return strings.Join(parts, " ▪ ")
+}
+
+// promptCachePath keeps the cache outside the repository so it never shows
+// up in git status.
+func promptCachePath(beadsDir, assignee string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	sum := sha256.Sum256([]byte(beadsDir + "\x00" + assignee))
+	return filepath.Join(dir, "beads", "prompt", hex.EncodeToString(sum[:8])+".json")
+}
+
+// readPromptCache returns the cached summary if it is younger than ttl and
+// no database file changed after it was written.
+func readPromptCache(cachePath, beadsDir string, ttl time.Duration) *types.PromptSummary {
+	info, err := os.Stat(cachePath)
+	if err != nil || time.Since(info.ModTime()) >= ttl {
+		return nil
+	}
+	watched := []string{filepath.Join(beadsDir, "issues.jsonl"), filepath.Join(beadsDir, "dolt")}
+	if dbPath != "" {
+		watched = append(watched, dbPath, dbPath+"-wal")
+	}
+	for _, path := range watched {
+		if fi, err := os.Stat(path); err == nil && fi.ModTime().After(info.ModTime()) {
+			return nil
+		}
+	}
+	// #nosec G304 - path is derived from the user cache directory
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil
+	}
+	var summary types.PromptSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil
+	}
+	return &summary
+}
+
+// writePromptCache stores the summary; failures only cost a cache miss.
+func writePromptCache(cachePath string, summary *types.PromptSummary) {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o700); err != nil {
+		return
+	}
+	tmp := fmt.Sprintf("%s.%d.tmp", cachePath, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, cachePath); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
+
+func init() {
+	promptCmd.Flags().Duration("ttl", 5*time.Second, "Reuse a cached summary this old when the database is unchanged (0 disables the cache)")
+	promptCmd.Flags().Bool("mine", false, "Only show in-progress issues assigned to you")
+	promptCmd.Flags().String("assignee", "", "Only show in-progress issues assigned to this person")
+	rootCmd.AddCommand(promptCmd)
+}
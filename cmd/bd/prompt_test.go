@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestFormatPromptSummary(t *testing.T) {
+	tests := []struct {
+		summary types.PromptSummary
+		want    string
+	}{
+		{types.PromptSummary{}, "0 ready"},
+		{types.PromptSummary{Ready: 3, Blocked: 1, InProgress: []string{"bd-42"}}, "3 ready ▪ 1 blocked ▪ bd-42 in_progress"},
+		{types.PromptSummary{Ready: 2, InProgress: []string{"bd-1", "bd-2", "bd-3"}}, "2 ready ▪ bd-3 in_progress (+2)"},
+	}
+	for _, tt := range tests {
+		if got := formatPromptSummary(&tt.summary); got != tt.want {
+			t.Errorf("formatPromptSummary(%+v) = %q, want %q", tt.summary, got, tt.want)
+		}
+	}
+}
+
+func TestPromptCache(t *testing.T) {
+	beadsDir := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), "prompt.json")
+	want := &types.PromptSummary{Ready: 4, InProgress: []string{"bd-7"}}
+
+	writePromptCache(cachePath, want)
+	got := readPromptCache(cachePath, beadsDir, time.Minute)
+	if got == nil || got.Ready != 4 || len(got.InProgress) != 1 {
+		t.Fatalf("readPromptCache = %+v, want %+v", got, want)
+	}
+	if got := readPromptCache(cachePath, beadsDir, time.Nanosecond); got != nil {
+		t.Errorf("expired cache returned %+v", got)
+	}
+
+	if promptCachePath(beadsDir, "") == promptCachePath(beadsDir, "alice") {
+		t.Error("cache path should depend on the assignee")
+	}
+}
+
+func TestPromptCommand(t *testing.T) {
+	p := newTestProject(t)
+
+	var issue types.Issue
+	p.RunJSON(&issue, "create", "Active work")
+	p.Run("create", "Waiting")
+	p.Run("update", issue.ID, "--status", "in_progress")
+
+	out := strings.TrimSpace(p.Run("prompt", "--ttl", "0"))
+	if want := issue.ID + " in_progress"; !strings.Contains(out, want) || !strings.Contains(out, "ready") {
+		t.Errorf("prompt = %q, want it to mention %q", out, want)
+	}
+
+	var summary types.PromptSummary
+	p.RunJSON(&summary, "prompt", "--ttl", "0", "--assignee", "nobody")
+	if len(summary.InProgress) != 0 {
+		t.Errorf("--assignee nobody in_progress = %v, want none", summary.InProgress)
+	}
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/query"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var queryCmd = &cobra.Command{
+	Use:     "query <expression>",
+	GroupID: "issues",
+	Short:   "List issues matching a query expression",
+	Long: `List issues matching a query expression. Accepts every 'bd list' flag.
+
+Expressions compare fields and combine them with and, or, not and
+parentheses. Adjacent terms are ANDed.
+
+  bd query 'status=open and (priority<=1 or label:security) and updated>-7d'
+  bd query 'assignee=none type=bug'
+  bd query 'title:login not label:wontfix'
+
+Fields:
+  id, status, type, assignee, owner      exact match
+  title, description (desc), notes       free text
+  priority (p)                           0-4 or P0-P4
+  created, updated, closed, due, defer   dates: 2025-01-31, -7d, +2w, tomorrow
+  label, parent                          issue has a matching label / parent
+
+Operators:
+  =  !=  <  <=  >  >=     comparisons (dates and priority support all)
+  ~  !~                   case-insensitive contains / doesn't contain
+  :                       contains for free text, = for everything else
+
+Use the value none to test for an empty field (assignee=none, due!=none,
+label=none). Quote values containing spaces: title~"sign in".
+
+Unless the expression mentions status or closed, closed issues are excluded as in
+'bd list' (use --all to include them).
+
+Saved filters:
+  bd query save mine 'assignee=alice status!=closed'
+  bd query '@mine and priority<2'
+  bd list --query @mine
+
+The same expressions work with --query on list, update, close and status.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		expr := strings.Join(args, " ")
+		if existing, _ := cmd.Flags().GetString("query"); existing != "" {
+			expr = "(" + existing + ") and (" + expr + ")"
+		}
+		if err := cmd.Flags().Set("query", expr); err != nil {
+			FatalError("%v", err)
+		}
+		if explain, _ := cmd.Flags().GetBool("explain"); explain {
+			explainQuery(parseQueryFlag(expr))
+			return
+		}
+		listCmd.Run(cmd, nil)
+	},
+}
+
+var querySaveCmd = &cobra.Command{
+	Use:   "save <name> <expression>",
+	Short: "Save a filter for use as @name",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("query save")
+		if err := ensureDirectMode("query save writes config directly"); err != nil {
+			FatalError("%v", err)
+		}
+		name, src := strings.TrimPrefix(args[0], "@"), strings.Join(args[1:], " ")
+		if !validSavedQueryName(name) {
+			FatalErrorCode(ErrCodeInvalid, "invalid filter name %q (use letters, digits, - and _)", name)
+		}
+
+		// Validate before saving; references to other saved filters must
+		// resolve (and not loop back to this one).
+		expr, err := query.Parse(src, query.Options{Saved: func(ref string) (string, error) {
+			if ref == name {
+				return "", fmt.Errorf("saved filter @%s refers to itself", name)
+			}
+			return savedQueryLookup(rootCtx)(ref)
+		}})
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if err := store.SetConfig(rootCtx, query.ConfigPrefix+name, src); err != nil {
+			FatalError("saving filter: %v", err)
+		}
+		if jsonOutput {
+			outputJSON(map[string]string{"name": name, "expression": src, "expanded": expr.String()})
+			return
+		}
+		fmt.Printf("%s Saved @%s: %s\n", ui.RenderPass("✓"), name, src)
+	},
+}
+
+var queryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved filters",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("query list reads config directly"); err != nil {
+			FatalError("%v", err)
+		}
+		saved, err := savedQueries(rootCtx)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		if jsonOutput {
+			outputJSON(saved)
+			return
+		}
+		if len(saved) == 0 {
+			fmt.Println("No saved filters (create one with 'bd query save <name> <expression>')")
+			return
+		}
+		names := make([]string, 0, len(saved))
+		for name := range saved {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("@%-16s %s\n", name, saved[name])
+		}
+	},
+}
+
+var queryDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved filter",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("query delete")
+		if err := ensureDirectMode("query delete writes config directly"); err != nil {
+			FatalError("%v", err)
+		}
+		name := strings.TrimPrefix(args[0], "@")
+		key := query.ConfigPrefix + name
+		if src, _ := store.GetConfig(rootCtx, key); src == "" {
+			FatalErrorCode(ErrCodeNotFound, "no saved filter @%s", name)
+		}
+		if err := store.DeleteConfig(rootCtx, key); err != nil {
+			FatalError("deleting filter: %v", err)
+		}
+		if jsonOutput {
+			outputJSON(map[string]string{"deleted": name})
+			return
+		}
+		fmt.Printf("%s Deleted @%s\n", ui.RenderPass("✓"), name)
+	},
+}
+
+func validSavedQueryName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if c != '-' && c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// savedQueries returns saved filter expressions keyed by name.
+func savedQueries(ctx context.Context) (map[string]string, error) {
+	all, err := store.GetAllConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	saved := make(map[string]string)
+	for key, value := range all {
+		if name, ok := strings.CutPrefix(key, query.ConfigPrefix); ok && value != "" {
+			saved[name] = value
+		}
+	}
+	return saved, nil
+}
+
+// savedQueryLookup resolves @name references through the daemon or the
+// local store, whichever is active.
+func savedQueryLookup(ctx context.Context) func(string) (string, error) {
+	return query.ConfigLookup(func(key string) (string, error) {
+		if daemonClient != nil {
+			resp, err := daemonClient.GetConfig(&rpc.GetConfigArgs{Key: key})
+			if err != nil {
+				return "", err
+			}
+			return resp.Value, nil
+		}
+		if store == nil {
+			return "", fmt.Errorf("no database available to look up saved filters")
+		}
+		return store.GetConfig(ctx, key)
+	})
+}
+
+// parseQueryFlag compiles a --query expression, exiting with E_INVALID on
+// syntax errors.
+func parseQueryFlag(src string) query.Expr {
+	expr, err := query.Parse(src, query.Options{Saved: savedQueryLookup(rootCtx)})
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	return expr
+}
+
+// queryMatchingIssues returns all non-tombstone issues matching expr.
+func queryMatchingIssues(ctx context.Context, expr query.Expr) ([]*types.Issue, error) {
+	if daemonClient != nil {
+		resp, err := daemonClient.List(&rpc.ListArgs{Expr: expr.String()})
+		if err != nil {
+			return nil, err
+		}
+		var issues []*types.Issue
+		if err := json.Unmarshal(resp.Data, &issues); err != nil {
+			return nil, fmt.Errorf("parsing response: %w", err)
+		}
+		return issues, nil
+	}
+	return store.SearchIssues(ctx, "", types.IssueFilter{Query: expr})
+}
+
+// queryIssueIDs returns the IDs of issues matching a --query expression,
+// for bulk commands.
+func queryIssueIDs(src string) []string {
+	issues, err := queryMatchingIssues(rootCtx, parseQueryFlag(src))
+	if err != nil {
+		FatalErrorRespectJSON("running query: %v", err)
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	return ids
+}
+
+// appendQueryMatches adds the issues matching a bulk command's --query flag
+// to args. restrict, if set, is ANDed with the user's expression. It returns
+// false after reporting an empty result when nothing matched and no IDs were
+// given explicitly.
+func appendQueryMatches(cmd *cobra.Command, args []string, restrict string) ([]string, bool) {
+	src, _ := cmd.Flags().GetString("query")
+	if src == "" {
+		return args, true
+	}
+	if restrict != "" {
+		src = "(" + src + ") and " + restrict
+	}
+	matched := queryIssueIDs(src)
+	if len(matched) == 0 && len(args) == 0 {
+		if jsonOutput {
+			outputJSON([]*types.Issue{})
+		} else {
+			fmt.Println("No issues match the query")
+		}
+		return nil, false
+	}
+	return append(args, matched...), true
+}
+
+// getQueryStatistics computes status counts for issues matching expr.
+func getQueryStatistics(ctx context.Context, expr query.Expr) (*types.Statistics, error) {
+	issues, err := queryMatchingIssues(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var ready []*types.Issue
+	if daemonClient != nil {
+		resp, err := daemonClient.Ready(&rpc.ReadyArgs{})
+		if err == nil {
+			_ = json.Unmarshal(resp.Data, &ready)
+		}
+	} else {
+		ready, _ = store.GetReadyWork(ctx, types.WorkFilter{})
+	}
+	readyIDs := make(map[string]bool, len(ready))
+	for _, issue := range ready {
+		readyIDs[issue.ID] = true
+	}
+
+	stats := &types.Statistics{TotalIssues: len(issues)}
+	for _, issue := range issues {
+		switch issue.Status {
+		case types.StatusOpen:
+			stats.OpenIssues++
+		case types.StatusInProgress:
+			stats.InProgressIssues++
+		case types.StatusBlocked:
+			stats.BlockedIssues++
+		case types.StatusDeferred:
+			stats.DeferredIssues++
+		case types.StatusClosed:
+			stats.ClosedIssues++
+		}
+		if issue.Pinned {
+			stats.PinnedIssues++
+		}
+		if readyIDs[issue.ID] {
+			stats.ReadyIssues++
+		}
+	}
+	return stats, nil
+}
+
+func explainQuery(expr query.Expr) {
+	where, args := expr.SQL()
+	if jsonOutput {
+		outputJSON(map[string]interface{}{
+			"expression": expr.String(),
+			"sql":        where,
+			"args":       args,
+		})
+		return
+	}
+	fmt.Printf("Expression: %s\n", expr.String())
+	fmt.Printf("SQL:        %s\n", where)
+	if len(args) > 0 {
+		fmt.Printf("Args:       %v\n", args)
+	}
+}
+
+func init() {
+	// listCmd's flags are registered in list.go, whose init runs first.
+	// Sharing the flag objects lets listCmd.Run read them from queryCmd.
+	queryCmd.Flags().AddFlagSet(listCmd.Flags())
+	queryCmd.Flags().Bool("explain", false, "Print the compiled SQL instead of running the query")
+	queryCmd.AddCommand(querySaveCmd)
+	queryCmd.AddCommand(queryListCmd)
+	queryCmd.AddCommand(queryDeleteCmd)
+	rootCmd.AddCommand(queryCmd)
+}
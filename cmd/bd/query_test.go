@@ -0,0 +1,116 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestQueryCommand(t *testing.T) {
+	p := newTestProject(t)
+
+	create := func(args ...string) string {
+		var issue types.Issue
+		p.RunJSON(&issue, append([]string{"create"}, args...)...)
+		return issue.ID
+	}
+	urgent := create("Login broken", "-p", "0", "-t", "bug", "-l", "api")
+	docs := create("API docs", "-p", "3", "-l", "api,docs")
+	chore := create("Cleanup", "-p", "2", "--assignee", "alice")
+	done := create("Old login work", "-p", "1")
+	p.Run("close", done)
+
+	ids := func(args ...string) string {
+		var issues []*types.IssueWithCounts
+		p.RunJSON(&issues, args...)
+		var got []string
+		for _, issue := range issues {
+			got = append(got, issue.ID)
+		}
+		sort.Strings(got)
+		return strings.Join(got, ",")
+	}
+	sorted := func(ids ...string) string {
+		sort.Strings(ids)
+		return strings.Join(ids, ",")
+	}
+
+	t.Run("query", func(t *testing.T) {
+		if got, want := ids("query", "priority<=1 or label:docs"), sorted(urgent, docs); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		// Mentioning status opts out of the default closed exclusion.
+		if got, want := ids("query", "title:login status=closed"), done; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		if got, want := ids("query", "assignee=none not label=docs"), urgent; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		if got, want := ids("list", "--query", "title:login", "--all"), sorted(urgent, done); got != want {
+			t.Errorf("list --query: got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		res := p.RunCommand("query", "color=red", "--json")
+		if res.ExitCode() != ErrCodeInvalid.ExitCode() {
+			t.Fatalf("exit = %d, want %d\nstderr: %s", res.ExitCode(), ErrCodeInvalid.ExitCode(), res.Stderr)
+		}
+		if !strings.Contains(res.Stderr, `unknown field \"color\"`) {
+			t.Errorf("stderr = %s", res.Stderr)
+		}
+	})
+
+	t.Run("saved", func(t *testing.T) {
+		p.Run("query", "save", "api", "label=api")
+		if got, want := ids("query", "@api and priority<2"), urgent; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		var saved map[string]string
+		p.RunJSON(&saved, "query", "list")
+		if saved["api"] != "label=api" {
+			t.Errorf("saved filters = %v", saved)
+		}
+		if res := p.RunCommand("query", "save", "loop", "@loop"); res.ExitCode() == 0 {
+			t.Error("self-referencing filter was saved")
+		}
+		p.Run("query", "delete", "api")
+		if res := p.RunCommand("query", "@api"); res.ExitCode() == 0 {
+			t.Error("deleted filter still resolves")
+		}
+	})
+
+	t.Run("bulk update", func(t *testing.T) {
+		var updated []*types.Issue
+		p.RunJSON(&updated, "update", "--query", "label=api", "--assignee", "bob")
+		if len(updated) != 2 {
+			t.Fatalf("updated %d issues, want 2", len(updated))
+		}
+		if got, want := ids("query", "assignee=bob"), sorted(urgent, docs); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		var status StatusOutput
+		p.RunJSON(&status, "status", "--query", "assignee=bob")
+		if status.Summary.TotalIssues != 2 || status.Summary.OpenIssues != 2 {
+			t.Errorf("summary = %+v", status.Summary)
+		}
+	})
+
+	t.Run("bulk close", func(t *testing.T) {
+		var closed []*types.Issue
+		p.RunJSON(&closed, "close", "--query", "assignee=alice", "--reason", "done")
+		if len(closed) != 1 || closed[0].ID != chore {
+			t.Fatalf("closed = %v, want [%s]", closed, chore)
+		}
+		// Nothing left to match: reports an empty result rather than failing.
+		out := p.Run("close", "--query", "assignee=alice")
+		if !strings.Contains(out, "No issues match") {
+			t.Errorf("output = %q", out)
+		}
+	})
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/i18n"
 	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
 	"github.com/steveyegge/beads/internal/types"
@@ -26,6 +28,12 @@ Use --mol to filter to a specific molecule's steps:
 Use --gated to find molecules ready for gate-resume dispatch:
   bd ready --gated           # Find molecules where a gate closed
 
+Use --for to see what an assignee can pick up next. If they are at their
+WIP limit (capacity.<name> or capacity.default in config.yaml), only work
+already in progress is shown:
+  bd config set capacity.alice 2
+  bd ready --for alice       # Excludes new work once alice has 2 in progress
+
 This is useful for agents executing molecules to see which steps can run next.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Handle --gated flag (gate-resume discovery)
@@ -47,6 +55,9 @@ This is useful for agents executing molecules to see which steps can run next.`,
 		unassigned, _ := cmd.Flags().GetBool("unassigned")
 		sortPolicy, _ := cmd.Flags().GetString("sort")
 		labels, _ := cmd.Flags().GetStringSlice("label")
+		if component, _ := cmd.Flags().GetString("component"); component != "" {
+			labels = append(labels, componentLabel(component))
+		}
 		labelsAny, _ := cmd.Flags().GetStringSlice("label-any")
 		issueType, _ := cmd.Flags().GetString("type")
 		issueType = util.NormalizeIssueType(issueType) // Expand aliases (mr→merge-request, etc.)
@@ -54,6 +65,7 @@ This is useful for agents executing molecules to see which steps can run next.`,
 		molTypeStr, _ := cmd.Flags().GetString("mol-type")
 		prettyFormat, _ := cmd.Flags().GetBool("pretty")
 		includeDeferred, _ := cmd.Flags().GetBool("include-deferred")
+		forAssignee, _ := cmd.Flags().GetString("for")
 		var molType *types.MolType
 		if molTypeStr != "" {
 			mt := types.MolType(molTypeStr)
@@ -65,6 +77,15 @@ This is useful for agents executing molecules to see which steps can run next.`,
 		}
 		// Use global jsonOutput set by PersistentPreRun (respects config.yaml + env vars)
 
+		// --for narrows to an assignee and applies their WIP limit
+		if forAssignee != "" {
+			if unassigned {
+				fmt.Fprintf(os.Stderr, "Error: --for cannot be combined with --unassigned\n")
+				os.Exit(1)
+			}
+			assignee = forAssignee
+		}
+
 		// Normalize labels: trim, dedupe, remove empty
 		labels = util.NormalizeLabels(labels)
 		labelsAny = util.NormalizeLabels(labelsAny)
@@ -105,6 +126,15 @@ This is useful for agents executing molecules to see which steps can run next.`,
 			fmt.Fprintf(os.Stderr, "Error: invalid sort policy '%s'. Valid values: hybrid, priority, oldest\n", sortPolicy)
 			os.Exit(1)
 		}
+		// --cursor: fetch one extra row to learn whether another page follows
+		paginate, cursor, after := cursorFlag(cmd)
+		if paginate {
+			filter.ResumeAfter(after, time.Now())
+			if limit > 0 {
+				filter.Limit = limit + 1
+			}
+		}
+		var nextCursor string
 		// If daemon is running, use RPC
 		if daemonClient != nil {
 			readyArgs := &rpc.ReadyArgs{
@@ -118,6 +148,8 @@ This is useful for agents executing molecules to see which steps can run next.`,
 				ParentID:        parentID,
 				MolType:         molTypeStr,
 				IncludeDeferred: includeDeferred, // GH#820
+				Paginate:        paginate,
+				Cursor:          cursor,
 			}
 			if cmd.Flags().Changed("priority") {
 				priority, _ := cmd.Flags().GetInt("priority")
@@ -129,20 +161,33 @@ This is useful for agents executing molecules to see which steps can run next.`,
 				os.Exit(1)
 			}
 			var issues []*types.Issue
-			if err := json.Unmarshal(resp.Data, &issues); err != nil {
+			if paginate {
+				var page types.IssuePage[*types.Issue]
+				if err := json.Unmarshal(resp.Data, &page); err != nil {
+					fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
+					os.Exit(1)
+				}
+				issues, nextCursor = page.Issues, page.NextCursor
+			} else if err := json.Unmarshal(resp.Data, &issues); err != nil {
 				fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
 				os.Exit(1)
 			}
+			capacity := applyReadyCapacity(forAssignee, &issues)
 			if jsonOutput {
 				if issues == nil {
 					issues = []*types.Issue{}
 				}
+				if paginate {
+					outputJSON(types.IssuePage[*types.Issue]{Issues: issues, NextCursor: nextCursor})
+					return
+				}
 				outputJSON(issues)
 				return
 			}
 
 			// Show upgrade notification if needed
 			maybeShowUpgradeNotification()
+			printCapacityNotice(capacity)
 
 			if len(issues) == 0 {
 				// Check if there are any open issues at all
@@ -155,31 +200,38 @@ This is useful for agents executing molecules to see which steps can run next.`,
 					}
 				}
 				if hasOpenIssues {
-					fmt.Printf("\n%s No ready work found (all issues have blocking dependencies)\n\n",
-						ui.RenderWarn("✨"))
+					fmt.Printf("\n%s %s\n\n",
+						ui.RenderWarn("✨"), i18n.T("No ready work found (all issues have blocking dependencies)"))
 				} else {
-					fmt.Printf("\n%s No open issues\n\n", ui.RenderPass("✨"))
+					fmt.Printf("\n%s %s\n\n", ui.RenderPass("✨"), i18n.T("No open issues"))
 				}
 				return
 			}
 			if prettyFormat {
 				displayPrettyList(issues, false)
 			} else {
-				fmt.Printf("\n%s Ready work (%d issues with no blockers):\n\n", ui.RenderAccent("📋"), len(issues))
+				fmt.Printf("\n%s %s\n\n", ui.RenderAccent("📋"), i18n.T("Ready work (%d issues with no blockers):", len(issues)))
 				for i, issue := range issues {
+					if ui.IsAccessible() {
+						fmt.Printf("%d. %s\n", i+1, accessibleIssueLine(issue, nil))
+						continue
+					}
 					fmt.Printf("%d. [%s] [%s] %s: %s\n", i+1,
 						ui.RenderPriority(issue.Priority),
 						ui.RenderType(string(issue.IssueType)),
 						ui.RenderID(issue.ID), issue.Title)
 					if issue.EstimatedMinutes != nil {
-						fmt.Printf("   Estimate: %d min\n", *issue.EstimatedMinutes)
+						fmt.Printf("   %s\n", i18n.T("Estimate: %d min", *issue.EstimatedMinutes))
 					}
 					if issue.Assignee != "" {
-						fmt.Printf("   Assignee: %s\n", issue.Assignee)
+						fmt.Printf("   %s\n", i18n.T("Assignee: %s", issue.Assignee))
 					}
 				}
 				fmt.Println()
 			}
+			if paginate {
+				printPageHint(limit, len(issues), paginate, nextCursor)
+			}
 			return
 		}
 		// Direct mode
@@ -210,16 +262,25 @@ This is useful for agents executing molecules to see which steps can run next.`,
 			}
 		}
 	}
+		if paginate {
+			issues, nextCursor = types.Paginate(issues, limit, filter.PageKey)
+		}
+		capacity := applyReadyCapacity(forAssignee, &issues)
 		if jsonOutput {
 			// Always output array, even if empty
 			if issues == nil {
 				issues = []*types.Issue{}
 			}
+			if paginate {
+				outputJSON(types.IssuePage[*types.Issue]{Issues: issues, NextCursor: nextCursor})
+				return
+			}
 			outputJSON(issues)
 			return
 		}
 		// Show upgrade notification if needed
 		maybeShowUpgradeNotification()
+		printCapacityNotice(capacity)
 
 		if len(issues) == 0 {
 			// Check if there are any open issues at all
@@ -228,10 +289,10 @@ This is useful for agents executing molecules to see which steps can run next.`,
 				hasOpenIssues = stats.OpenIssues > 0 || stats.InProgressIssues > 0
 			}
 			if hasOpenIssues {
-				fmt.Printf("\n%s No ready work found (all issues have blocking dependencies)\n\n",
-					ui.RenderWarn("✨"))
+				fmt.Printf("\n%s %s\n\n",
+					ui.RenderWarn("✨"), i18n.T("No ready work found (all issues have blocking dependencies)"))
 			} else {
-				fmt.Printf("\n%s No open issues\n\n", ui.RenderPass("✨"))
+				fmt.Printf("\n%s %s\n\n", ui.RenderPass("✨"), i18n.T("No open issues"))
 			}
 			// Show tip even when no ready work found
 			maybeShowTip(store)
@@ -240,21 +301,28 @@ This is useful for agents executing molecules to see which steps can run next.`,
 		if prettyFormat {
 			displayPrettyList(issues, false)
 		} else {
-			fmt.Printf("\n%s Ready work (%d issues with no blockers):\n\n", ui.RenderAccent("📋"), len(issues))
+			fmt.Printf("\n%s %s\n\n", ui.RenderAccent("📋"), i18n.T("Ready work (%d issues with no blockers):", len(issues)))
 			for i, issue := range issues {
+				if ui.IsAccessible() {
+					fmt.Printf("%d. %s\n", i+1, accessibleIssueLine(issue, nil))
+					continue
+				}
 				fmt.Printf("%d. [%s] [%s] %s: %s\n", i+1,
 					ui.RenderPriority(issue.Priority),
 					ui.RenderType(string(issue.IssueType)),
 					ui.RenderID(issue.ID), issue.Title)
 				if issue.EstimatedMinutes != nil {
-					fmt.Printf("   Estimate: %d min\n", *issue.EstimatedMinutes)
+					fmt.Printf("   %s\n", i18n.T("Estimate: %d min", *issue.EstimatedMinutes))
 				}
 				if issue.Assignee != "" {
-					fmt.Printf("   Assignee: %s\n", issue.Assignee)
+					fmt.Printf("   %s\n", i18n.T("Assignee: %s", issue.Assignee))
 				}
 			}
 			fmt.Println()
 		}
+		if paginate {
+			printPageHint(limit, len(issues), paginate, nextCursor)
+		}
 
 		// Show tip after successful ready (direct mode only)
 		maybeShowTip(store)
@@ -295,10 +363,10 @@ var blockedCmd = &cobra.Command{
 			return
 		}
 		if len(blocked) == 0 {
-			fmt.Printf("\n%s No blocked issues\n\n", ui.RenderPass("✨"))
+			fmt.Printf("\n%s %s\n\n", ui.RenderPass("✨"), i18n.T("No blocked issues"))
 			return
 		}
-		fmt.Printf("\n%s Blocked issues (%d):\n\n", ui.RenderFail("🚫"), len(blocked))
+		fmt.Printf("\n%s %s\n\n", ui.RenderFail("🚫"), i18n.T("Blocked issues (%d):", len(blocked)))
 		for _, issue := range blocked {
 			fmt.Printf("[%s] %s: %s\n",
 				ui.RenderPriority(issue.Priority),
@@ -307,8 +375,7 @@ var blockedCmd = &cobra.Command{
 			if blockedBy == nil {
 				blockedBy = []string{}
 			}
-			fmt.Printf("  Blocked by %d open dependencies: %v\n",
-				issue.BlockedByCount, blockedBy)
+			fmt.Printf("  %s\n", i18n.T("Blocked by %d open dependencies: %v", issue.BlockedByCount, blockedBy))
 			fmt.Println()
 		}
 	},
@@ -378,7 +445,7 @@ func runMoleculeReady(_ *cobra.Command, molIDArg string) {
 	fmt.Printf("   Total: %d steps, %d ready\n", analysis.TotalSteps, len(readySteps))
 
 	if len(readySteps) == 0 {
-		fmt.Printf("\n%s No ready steps (all blocked or completed)\n\n", ui.RenderWarn("✨"))
+		fmt.Printf("\n%s %s\n\n", ui.RenderWarn("✨"), i18n.T("No ready steps (all blocked or completed)"))
 		return
 	}
 
@@ -449,12 +516,14 @@ type MoleculeReadyOutput struct {
 
 func init() {
 	readyCmd.Flags().IntP("limit", "n", 10, "Maximum issues to show")
+	addCursorFlag(readyCmd)
 	readyCmd.Flags().IntP("priority", "p", 0, "Filter by priority")
 	readyCmd.Flags().StringP("assignee", "a", "", "Filter by assignee")
 	readyCmd.Flags().BoolP("unassigned", "u", false, "Show only unassigned issues")
 	readyCmd.Flags().StringP("sort", "s", "hybrid", "Sort policy: hybrid (default), priority, oldest")
 	readyCmd.Flags().StringSliceP("label", "l", []string{}, "Filter by labels (AND: must have ALL). Can combine with --label-any")
 	readyCmd.Flags().StringSlice("label-any", []string{}, "Filter by labels (OR: must have AT LEAST ONE). Can combine with --label")
+	readyCmd.Flags().String("component", "", "Filter by component (same as --label component:<name>)")
 	readyCmd.Flags().StringP("type", "t", "", "Filter by issue type (task, bug, feature, epic, merge-request). Aliases: mr→merge-request, feat→feature, mol→molecule")
 	readyCmd.Flags().String("mol", "", "Filter to steps within a specific molecule")
 	readyCmd.Flags().String("parent", "", "Filter to descendants of this bead/epic")
@@ -462,6 +531,7 @@ func init() {
 	readyCmd.Flags().Bool("pretty", false, "Display issues in a tree format with status/priority symbols")
 	readyCmd.Flags().Bool("include-deferred", false, "Include issues with future defer_until timestamps")
 	readyCmd.Flags().Bool("gated", false, "Find molecules ready for gate-resume dispatch")
+	readyCmd.Flags().String("for", "", "Show work for an assignee, respecting their WIP limit (capacity.<name> in config)")
 	rootCmd.AddCommand(readyCmd)
 	blockedCmd.Flags().String("parent", "", "Filter to descendants of this bead/epic")
 	rootCmd.AddCommand(blockedCmd)
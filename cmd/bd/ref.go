@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+var refCmd = &cobra.Command{
+	Use:     "ref",
+	GroupID: "issues",
+	Short:   "Manage an issue's external references (github, jira, url, doc)",
+	Long: `Attach any number of typed external references to an issue.
+
+Each ref has a type and a value:
+  github   gh-12, owner/repo#12, https://github.com/owner/repo/issues/12
+  jira     PROJ-123, https://example.atlassian.net/browse/PROJ-123
+  url      any other link
+  doc      a document or path, e.g. docs/design.md
+
+The type is inferred from the value unless --type is given. The issue's
+primary external_ref (bd update --external-ref) is listed first; sync
+integrations match remote items against every ref, not just the primary.
+
+bd ref check verifies that refs still resolve and labels issues with dead
+refs ref:dead, so 'bd list --label ref:dead' finds them.
+
+Examples:
+  bd ref add bd-42 PROJ-123                      # Jira key
+  bd ref add bd-42 https://example.com/rfc --type doc
+  bd ref list bd-42
+  bd ref remove bd-42 PROJ-123
+  bd ref check --all                             # Find dead links`,
+}
+
+var refAddCmd = &cobra.Command{
+	Use:   "add <issue-id> <value>",
+	Short: "Add an external reference to an issue",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("ref add")
+		refType, _ := cmd.Flags().GetString("type")
+		ref, err := refs.New(refType, args[1])
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		rs, id := refStoreAndID(args[0])
+		if err := rs.AddRef(rootCtx, id, ref, actor); err != nil {
+			FatalErrorRespectJSON("failed to add ref: %v", err)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"id": id, "type": ref.Type, "value": ref.Value})
+			return
+		}
+		fmt.Printf("%s Added %s ref %s to %s\n", ui.RenderPass("✓"), ref.Type, ref.Value, id)
+	},
+}
+
+var refRemoveCmd = &cobra.Command{
+	Use:   "remove <issue-id> <value>",
+	Short: "Remove an external reference from an issue",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("ref remove")
+		ctx := rootCtx
+		rs, id := refStoreAndID(args[0])
+		removed, err := rs.RemoveRef(ctx, id, args[1], actor)
+		if err != nil {
+			FatalErrorRespectJSON("failed to remove ref: %v", err)
+		}
+		if !removed {
+			// The value may be the primary external_ref
+			issue, err := store.GetIssue(ctx, id)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if issue == nil || issue.ExternalRef == nil || *issue.ExternalRef != args[1] {
+				FatalErrorRespectJSON("%s has no ref %q", id, args[1])
+			}
+			if err := store.UpdateIssue(ctx, id, map[string]interface{}{"external_ref": nil}, actor); err != nil {
+				FatalErrorRespectJSON("failed to clear external_ref: %v", err)
+			}
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"id": id, "value": args[1], "removed": true})
+			return
+		}
+		fmt.Printf("%s Removed ref %s from %s\n", ui.RenderPass("✓"), args[1], id)
+	},
+}
+
+var refListCmd = &cobra.Command{
+	Use:   "list <issue-id>",
+	Short: "List an issue's external references",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+		_, id := refStoreAndID(args[0])
+		issue, err := store.GetIssue(ctx, id)
+		if err != nil || issue == nil {
+			FatalErrorRespectJSON("issue %s not found", id)
+		}
+		if err := refs.Populate(ctx, store, []*types.Issue{issue}); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		all := refs.All(issue)
+
+		if jsonOutput {
+			if all == nil {
+				all = []*types.Ref{}
+			}
+			outputJSON(all)
+			return
+		}
+		if len(all) == 0 {
+			fmt.Printf("%s has no external refs\n", id)
+			return
+		}
+		fmt.Printf("\n%s External refs for %s:\n", ui.RenderAccent("🔗"), id)
+		for i, ref := range all {
+			line := fmt.Sprintf("  %-7s %s", ref.Type, ref.Value)
+			if i == 0 && issue.ExternalRef != nil && *issue.ExternalRef == ref.Value {
+				line += ui.RenderMuted("  (primary)")
+			}
+			fmt.Println(line)
+		}
+		fmt.Println()
+	},
+}
+
+var refCheckCmd = &cobra.Command{
+	Use:   "check [issue-id...]",
+	Short: "Check that external references still resolve",
+	Long: `Check that issues' external references still resolve.
+
+  github   the issue or pull request exists (GitHub API; gh-<n> refs need
+           github.org and github.repo, GITHUB_TOKEN raises the rate limit)
+  jira     <jira.url>/browse/<KEY> answers 2xx
+  url      the page answers 2xx (HEAD, falling back to GET)
+  doc      the file exists, relative to the repository root
+
+404 and 410 count as dead; timeouts, auth failures and server errors are
+reported as unknown and never change labels. Issues with a dead ref get the
+label ref:dead, which is removed once all their refs check alive.
+
+The daemon can re-check every issue periodically:
+  bd config set refs.check-interval 24h
+
+Exits 1 when a dead ref is found.
+
+Examples:
+  bd ref check bd-42
+  bd ref check --all --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		if all == (len(args) > 0) {
+			FatalErrorRespectJSON("give issue IDs or --all")
+		}
+		CheckReadonly("ref check")
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		var issues []*types.Issue
+		if all {
+			var err error
+			issues, err = store.SearchIssues(ctx, "", types.IssueFilter{})
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		} else {
+			for _, id := range args {
+				fullID, err := utils.ResolvePartialID(ctx, store, id)
+				if err != nil {
+					FatalErrorRespectJSON("resolving %s: %v", id, err)
+				}
+				issue, err := store.GetIssue(ctx, fullID)
+				if err != nil || issue == nil {
+					FatalErrorRespectJSON("issue %s not found", fullID)
+				}
+				issues = append(issues, issue)
+			}
+		}
+		if err := refs.Populate(ctx, store, issues); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		checker := newRefChecker(ctx, store, refDocRoot())
+		results, err := refs.CheckIssues(ctx, store, checker, issues, actor)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		markDirtyAndScheduleFlush()
+
+		dead := 0
+		for _, r := range results {
+			if r.Dead {
+				dead++
+			}
+		}
+		if jsonOutput {
+			if results == nil {
+				results = []refs.IssueCheck{}
+			}
+			outputJSON(results)
+		} else {
+			if len(results) == 0 {
+				fmt.Println("No external refs to check")
+			}
+			for _, r := range results {
+				fmt.Println(ui.RenderID(r.IssueID))
+				for _, check := range r.Checks {
+					icon := ui.RenderPass("✓")
+					switch check.Status {
+					case refs.StatusDead:
+						icon = ui.RenderFail("✗")
+					case refs.StatusUnknown:
+						icon = ui.RenderWarn("?")
+					}
+					line := fmt.Sprintf("  %s %s", icon, check.Value)
+					if check.Detail != "" {
+						line += ui.RenderMuted("  " + check.Detail)
+					}
+					fmt.Println(line)
+				}
+			}
+			if dead > 0 {
+				fmt.Printf("\n%d issue(s) with dead refs, labeled %s\n", dead, refs.DeadLabel)
+			}
+		}
+		if dead > 0 {
+			// Exiting skips the final flush, so write the label changes first
+			if flushManager != nil {
+				if err := flushManager.FlushNow(); err != nil {
+					WarnError("flush failed: %v", err)
+				}
+			}
+			os.Exit(1)
+		}
+	},
+}
+
+// refDocRoot is the directory doc refs are relative to: the repository
+// holding .beads.
+func refDocRoot() string {
+	if beadsDir := beads.FindBeadsDir(); beadsDir != "" {
+		return filepath.Dir(beadsDir)
+	}
+	cwd, _ := os.Getwd()
+	return cwd
+}
+
+// refStoreAndID opens the local store and resolves id. Refs are managed in
+// direct mode; the daemon picks them up from the next export.
+func refStoreAndID(id string) (refs.Store, string) {
+	if err := ensureStoreActive(); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	rs, err := refs.For(store)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	fullID, err := utils.ResolvePartialID(rootCtx, store, id)
+	if err != nil {
+		FatalErrorRespectJSON("resolving %s: %v", id, err)
+	}
+	return rs, fullID
+}
+
+// newRefChecker returns a ref checker that also uses a GitHub token saved
+// with 'bd auth login github' when GITHUB_TOKEN is unset.
+func newRefChecker(ctx context.Context, s storage.Storage, docRoot string) *refs.Checker {
+	checker := refs.NewChecker(ctx, s, docRoot)
+	if checker.GitHubToken == "" {
+		checker.GitHubToken, _ = lookupCredential("github.token")
+	}
+	return checker
+}
+
+func init() {
+	refAddCmd.Flags().String("type", "", "Ref type: github, jira, url or doc (default: inferred)")
+	refAddCmd.ValidArgsFunction = issueIDCompletion
+	refRemoveCmd.ValidArgsFunction = issueIDCompletion
+	refListCmd.ValidArgsFunction = issueIDCompletion
+	refCheckCmd.Flags().Bool("all", false, "Check every issue's refs")
+	refCheckCmd.ValidArgsFunction = issueIDCompletion
+	refCmd.AddCommand(refAddCmd)
+	refCmd.AddCommand(refRemoveCmd)
+	refCmd.AddCommand(refListCmd)
+	refCmd.AddCommand(refCheckCmd)
+	rootCmd.AddCommand(refCmd)
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/beads/internal/repro"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// reproFlags are the bd repro set flags, also offered by bd create with a
+// repro- prefix, and the repro field each sets.
+var reproFlags = []struct {
+	name, usage string
+	field       func(*types.Repro) *string
+}{
+	{"os", "Operating system and platform, e.g. \"macOS 15.1 arm64\"", func(r *types.Repro) *string { return &r.OS }},
+	{"version", "Version that misbehaved", func(r *types.Repro) *string { return &r.Version }},
+	{"steps", "Steps to reproduce (\"-\" reads stdin)", func(r *types.Repro) *string { return &r.Steps }},
+	{"expected", "Expected behavior", func(r *types.Repro) *string { return &r.Expected }},
+	{"actual", "Actual behavior", func(r *types.Repro) *string { return &r.Actual }},
+}
+
+var reproCmd = &cobra.Command{
+	Use:     "repro",
+	GroupID: "issues",
+	Short:   "Record environment and reproduction steps on bug reports",
+	Long: `Record structured repro metadata on an issue: OS, version, steps to
+reproduce, expected and actual behavior. The metadata syncs with the issue,
+bd show prints it in a REPRO section, and bd list --repro searches it.
+
+OS and version are single lines; expected or actual behavior needs steps.
+bd create takes the same fields as --repro-os, --repro-steps and so on.
+
+Examples:
+  bd repro set bd-42 --os "Ubuntu 24.04" --version 1.4.2
+  bd repro set bd-42 --steps "1. bd init\n2. bd sync" --expected "Synced" --actual "panic: nil map"
+  bd repro set bd-42 --steps - < steps.md
+  bd repro edit bd-42                 # Fill in the template in $EDITOR
+  bd repro clear bd-42
+  bd list --repro "ubuntu"`,
+}
+
+var reproSetCmd = &cobra.Command{
+	Use:   "set <issue-id>",
+	Short: "Set an issue's repro fields",
+	Long: `Set an issue's repro fields. Only the fields given change; an empty value
+clears that field. "\n" in --steps, --expected and --actual is a newline.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("repro set")
+		if !reproFlagsChanged(cmd.Flags(), "") {
+			FatalErrorCode(ErrCodeUsage, "nothing to set: give --os, --version, --steps, --expected or --actual")
+		}
+		rs, id := reproStoreAndID(args[0])
+		ctx := rootCtx
+		current, err := rs.GetRepro(ctx, id)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		r := &types.Repro{}
+		if current != nil {
+			*r = *current
+		}
+		applyReproFlags(cmd.Flags(), "", r)
+		saveRepro(ctx, rs, id, r)
+	},
+}
+
+var reproEditCmd = &cobra.Command{
+	Use:   "edit <issue-id>",
+	Short: "Edit an issue's repro in $EDITOR",
+	Long: `Open the issue's repro as a Markdown template in $EDITOR, one section per
+field (## OS, ## Version, ## Steps to Reproduce, ## Expected, ## Actual),
+and save what you write. Empty sections clear their field.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("repro edit")
+		rs, id := reproStoreAndID(args[0])
+		ctx := rootCtx
+		current, err := rs.GetRepro(ctx, id)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		before := repro.Template(id, current)
+		after, err := editText("bd-repro-"+id+"-*.md", before)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if after == before {
+			fmt.Println("No changes made")
+			return
+		}
+		r, err := repro.ParseTemplate(after)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		saveRepro(ctx, rs, id, r)
+	},
+}
+
+var reproClearCmd = &cobra.Command{
+	Use:   "clear <issue-id>",
+	Short: "Remove an issue's repro metadata",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("repro clear")
+		rs, id := reproStoreAndID(args[0])
+		saveRepro(rootCtx, rs, id, nil)
+	},
+}
+
+// saveRepro validates and stores a repro, then reports it.
+func saveRepro(ctx context.Context, rs repro.Store, id string, r *types.Repro) {
+	if r != nil {
+		repro.Normalize(r)
+		if err := repro.Validate(r); err != nil {
+			FatalErrorCode(ErrCodeUsage, "invalid repro: %v", err)
+		}
+	}
+	if err := rs.SetRepro(ctx, id, r, actor); err != nil {
+		FatalErrorRespectJSON("failed to set repro: %v", err)
+	}
+	markDirtyAndScheduleFlush()
+
+	if jsonOutput {
+		outputJSON(map[string]interface{}{"id": id, "repro": r})
+		return
+	}
+	if r.IsEmpty() {
+		fmt.Printf("%s Cleared repro of %s\n", ui.RenderPass("✓"), id)
+		return
+	}
+	fmt.Printf("%s Repro of %s:\n\n%s\n", ui.RenderPass("✓"), id, ui.RenderMarkdown(repro.Markdown(r)))
+}
+
+// reproFlagsChanged reports whether any repro flag (with the given name
+// prefix) was given.
+func reproFlagsChanged(flags *pflag.FlagSet, prefix string) bool {
+	for _, f := range reproFlags {
+		if flags.Changed(prefix + f.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyReproFlags sets the fields of r whose flags were given. "-" reads
+// the value from stdin; "\n" escapes become newlines in the long fields.
+func applyReproFlags(flags *pflag.FlagSet, prefix string, r *types.Repro) {
+	for _, f := range reproFlags {
+		if !flags.Changed(prefix + f.name) {
+			continue
+		}
+		value, _ := flags.GetString(prefix + f.name)
+		if value == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				FatalErrorRespectJSON("reading --%s%s from stdin: %v", prefix, f.name, err)
+			}
+			value = string(data)
+		} else if f.name != "os" && f.name != "version" {
+			value = strings.ReplaceAll(value, `\n`, "\n")
+		}
+		*f.field(r) = value
+	}
+}
+
+// addReproFlags registers the repro flags on a command.
+func addReproFlags(cmd *cobra.Command, prefix string) {
+	for _, f := range reproFlags {
+		cmd.Flags().String(prefix+f.name, "", f.usage)
+	}
+}
+
+// reproStoreAndID resolves an issue ID against the repro store.
+func reproStoreAndID(id string) (repro.Store, string) {
+	if err := ensureStoreActive(); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	rs, err := repro.For(store)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	fullID, err := utils.ResolvePartialID(rootCtx, store, id)
+	if err != nil {
+		FatalErrorRespectJSON("resolving %s: %v", id, err)
+	}
+	return rs, fullID
+}
+
+// createRepro reads and validates bd create's --repro-* flags, before the
+// issue is created. It returns nil when none were given.
+func createRepro(flags *pflag.FlagSet) *types.Repro {
+	if !reproFlagsChanged(flags, "repro-") {
+		return nil
+	}
+	r := &types.Repro{}
+	applyReproFlags(flags, "repro-", r)
+	repro.Normalize(r)
+	if err := repro.Validate(r); err != nil {
+		FatalErrorCode(ErrCodeUsage, "invalid repro: %v", err)
+	}
+	return r
+}
+
+// setCreatedRepro stores the repro given to bd create on the new issue.
+func setCreatedRepro(id string, r *types.Repro) {
+	if r.IsEmpty() {
+		return
+	}
+	rs, fullID := reproStoreAndID(id)
+	if err := rs.SetRepro(rootCtx, fullID, r, actor); err != nil {
+		WarnError("failed to set repro on %s: %v", fullID, err)
+		return
+	}
+	markDirtyAndScheduleFlush()
+}
+
+// printRepro prints an issue's repro in bd show. issue.Repro must already
+// be populated.
+func printRepro(issue *types.Issue) {
+	if issue.Repro.IsEmpty() {
+		return
+	}
+	fmt.Printf("\n%s\n%s\n", ui.RenderBold("REPRO"), ui.RenderMarkdown(repro.Markdown(issue.Repro)))
+}
+
+// reproFilterIDs returns the IDs of issues with repro metadata containing
+// text, for bd list --repro. In daemon mode it reads them through a
+// read-only connection.
+func reproFilterIDs(ctx context.Context, text string) ([]string, error) {
+	s := store
+	if s == nil {
+		if dbPath == "" {
+			return nil, repro.ErrUnsupported
+		}
+		roStore, err := sqlite.NewReadOnlyWithTimeout(ctx, dbPath, lockTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = roStore.Close() }()
+		s = roStore
+	}
+	rs, err := repro.For(s)
+	if err != nil {
+		return nil, err
+	}
+	return rs.SearchReproIssueIDs(ctx, text)
+}
+
+// editText opens text in the user's editor, in a temp file named after
+// pattern (as for os.CreateTemp), and returns what they saved.
+func editText(pattern, text string) (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	if editor == "" {
+		for _, defaultEditor := range []string{"vim", "vi", "nano", "emacs"} {
+			if _, err := exec.LookPath(defaultEditor); err == nil {
+				editor = defaultEditor
+				break
+			}
+		}
+	}
+	if editor == "" {
+		return "", fmt.Errorf("no editor found. Set $EDITOR or $VISUAL environment variable")
+	}
+
+	tmpFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+	if _, err := tmpFile.WriteString(text); err != nil {
+		_ = tmpFile.Close()
+		return "", fmt.Errorf("writing to temp file: %w", err)
+	}
+	_ = tmpFile.Close()
+
+	editorParts := strings.Fields(editor)
+	editorCmd := exec.Command(editorParts[0], append(editorParts[1:], tmpPath)...) //nolint:gosec // G204: editor from trusted $EDITOR/$VISUAL env or known defaults
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("running editor: %w", err)
+	}
+	// #nosec G304 -- tmpPath was created above
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("reading edited file: %w", err)
+	}
+	return string(edited), nil
+}
+
+func init() {
+	addReproFlags(reproSetCmd, "")
+	reproSetCmd.ValidArgsFunction = issueIDCompletion
+	reproEditCmd.ValidArgsFunction = issueIDCompletion
+	reproClearCmd.ValidArgsFunction = issueIDCompletion
+	reproCmd.AddCommand(reproSetCmd)
+	reproCmd.AddCommand(reproEditCmd)
+	reproCmd.AddCommand(reproClearCmd)
+	rootCmd.AddCommand(reproCmd)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// ResponseTimesReport is first-response and resolution times of the issues
+// created in a period.
+type ResponseTimesReport struct {
+	Since         time.Time           `json:"since"`
+	Issues        int                 `json:"issues"`
+	FirstResponse DurationStats       `json:"first_response"`
+	Resolution    DurationStats       `json:"resolution"`
+	Awaiting      []*ResponseTimeItem `json:"awaiting"` // Open without a response, oldest first
+	Items         []*ResponseTimeItem `json:"items"`
+}
+
+// DurationStats summarizes a set of durations, in hours.
+type DurationStats struct {
+	Count  int     `json:"count"`
+	Median float64 `json:"median_hours"`
+	P90    float64 `json:"p90_hours"`
+	Mean   float64 `json:"mean_hours"`
+}
+
+// ResponseTimeItem is one issue's response and resolution times.
+type ResponseTimeItem struct {
+	ID             string       `json:"id"`
+	Title          string       `json:"title"`
+	Status         types.Status `json:"status"`
+	CreatedBy      string       `json:"created_by,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	FirstResponder string       `json:"first_responder,omitempty"`
+	FirstResponse  *float64     `json:"first_response_hours,omitempty"`
+	Resolution     *float64     `json:"resolution_hours,omitempty"`
+	WaitingFor     float64      `json:"waiting_hours,omitempty"` // Age, for issues still awaiting a response
+}
+
+var statusResponseTimesCmd = &cobra.Command{
+	Use:   "response-times",
+	Short: "Show time to first response and to resolution",
+	Long: `Show how quickly issues get a first response and get resolved, for teams
+using beads as a support queue.
+
+The first response is the earliest comment by someone other than the
+issue's creator. Resolution is the time from creation to close. Both are
+summarized as median, 90th percentile and mean over the issues created
+since --since. Open issues still waiting for a response are listed, oldest
+first.`,
+	Example: `  bd stats response-times
+  bd stats response-times --label support --since 7d
+  bd stats response-times --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		labels, _ := cmd.Flags().GetStringSlice("label")
+		limit, _ := cmd.Flags().GetInt("limit")
+		now := time.Now()
+		since, err := parsePastSince(sinceFlag, now)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if err := ensureDirectMode("stats response-times requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		report, err := buildResponseTimes(rootCtx, store, since, labels, now)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		displayResponseTimes(report, limit)
+	},
+}
+
+// buildResponseTimes measures the issues created since the given time,
+// optionally only those with all of labels.
+func buildResponseTimes(ctx context.Context, s storage.Storage, since time.Time, labels []string, now time.Time) (*ResponseTimesReport, error) {
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{CreatedAfter: &since, Labels: labels})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	comments, err := s.GetCommentsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	report := &ResponseTimesReport{Since: since, Awaiting: []*ResponseTimeItem{}, Items: []*ResponseTimeItem{}}
+	var responses, resolutions []float64
+	for _, issue := range issues {
+		if issue.Status == types.StatusTombstone {
+			continue
+		}
+		item := &ResponseTimeItem{
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			CreatedBy: issue.CreatedBy,
+			CreatedAt: issue.CreatedAt,
+		}
+		if first := firstResponse(issue, comments[issue.ID]); first != nil {
+			hours := first.CreatedAt.Sub(issue.CreatedAt).Hours()
+			item.FirstResponder = first.Author
+			item.FirstResponse = &hours
+			responses = append(responses, hours)
+		}
+		if issue.Status == types.StatusClosed && issue.ClosedAt != nil {
+			hours := issue.ClosedAt.Sub(issue.CreatedAt).Hours()
+			item.Resolution = &hours
+			resolutions = append(resolutions, hours)
+		} else if item.FirstResponse == nil {
+			item.WaitingFor = now.Sub(issue.CreatedAt).Hours()
+			report.Awaiting = append(report.Awaiting, item)
+		}
+		report.Items = append(report.Items, item)
+	}
+	report.Issues = len(report.Items)
+	report.FirstResponse = summarizeDurations(responses)
+	report.Resolution = summarizeDurations(resolutions)
+	sort.SliceStable(report.Awaiting, func(i, j int) bool {
+		return report.Awaiting[i].CreatedAt.Before(report.Awaiting[j].CreatedAt)
+	})
+	return report, nil
+}
+
+// firstResponse returns the earliest comment by someone other than the
+// issue's creator, or nil.
+func firstResponse(issue *types.Issue, comments []*types.Comment) *types.Comment {
+	var first *types.Comment
+	for _, c := range comments {
+		if c.Author == "" || c.Author == issue.CreatedBy || c.CreatedAt.Before(issue.CreatedAt) {
+			continue
+		}
+		if first == nil || c.CreatedAt.Before(first.CreatedAt) {
+			first = c
+		}
+	}
+	return first
+}
+
+// summarizeDurations computes the median, nearest-rank 90th percentile and
+// mean of hours.
+func summarizeDurations(hours []float64) DurationStats {
+	stats := DurationStats{Count: len(hours)}
+	if len(hours) == 0 {
+		return stats
+	}
+	sorted := append([]float64(nil), hours...)
+	sort.Float64s(sorted)
+	total := 0.0
+	for _, h := range sorted {
+		total += h
+	}
+	stats.Median = median(sorted)
+	stats.P90 = sorted[int(math.Ceil(0.9*float64(len(sorted))))-1]
+	stats.Mean = total / float64(len(sorted))
+	return stats
+}
+
+func displayResponseTimes(r *ResponseTimesReport, limit int) {
+	fmt.Printf("\n%s Response times for %d issue(s) created since %s:\n\n",
+		ui.RenderAccent("⏱"), r.Issues, r.Since.Format("2006-01-02"))
+	row := func(name string, st DurationStats) {
+		if st.Count == 0 {
+			fmt.Printf("  %-16s %s\n", name, ui.RenderMuted("none yet"))
+			return
+		}
+		fmt.Printf("  %-16s median %-14s p90 %-14s mean %-14s (%d)\n",
+			name, formatDuration(st.Median), formatDuration(st.P90), formatDuration(st.Mean), st.Count)
+	}
+	row("First response", r.FirstResponse)
+	row("Resolution", r.Resolution)
+
+	if len(r.Awaiting) == 0 {
+		fmt.Printf("\n%s Every open issue has a response\n\n", ui.RenderPass("✓"))
+		return
+	}
+	fmt.Printf("\n%s Awaiting a first response (%d):\n\n", ui.RenderWarn("⚠"), len(r.Awaiting))
+	for i, item := range r.Awaiting {
+		if limit > 0 && i == limit {
+			fmt.Printf("  ... and %d more\n", len(r.Awaiting)-limit)
+			break
+		}
+		fmt.Printf("  %s  %-12s %s\n", ui.RenderID(item.ID), formatDuration(item.WaitingFor), item.Title)
+	}
+	fmt.Println()
+}
+
+func init() {
+	statusResponseTimesCmd.Flags().String("since", "30d", "Measure issues created since: a duration such as 30d, or a date")
+	statusResponseTimesCmd.Flags().StringSliceP("label", "l", nil, "Only issues with these labels (comma-separated, all must match)")
+	statusResponseTimesCmd.Flags().Int("limit", 10, "Awaiting issues to list (0 for all)")
+	statusCmd.AddCommand(statusResponseTimesCmd)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestSummarizeDurations(t *testing.T) {
+	st := summarizeDurations([]float64{10, 1, 4, 2, 3, 5, 6, 7, 8, 9})
+	if st.Count != 10 || st.Median != 5.5 || st.P90 != 9 || st.Mean != 5.5 {
+		t.Errorf("got %+v", st)
+	}
+	if st := summarizeDurations(nil); st != (DurationStats{}) {
+		t.Errorf("empty = %+v", st)
+	}
+}
+
+func TestBuildResponseTimes(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	now := time.Now()
+	created := now.Add(-4 * time.Hour)
+	for _, issue := range []*types.Issue{
+		{ID: "test-1", Title: "Login fails", CreatedBy: "carol", CreatedAt: created},
+		{ID: "test-2", Title: "Refund", CreatedBy: "dave", CreatedAt: created.Add(time.Hour)},
+		{ID: "test-3", Title: "Typo", CreatedBy: "erin", CreatedAt: created},
+		{ID: "test-4", Title: "Old", CreatedBy: "erin", CreatedAt: now.AddDate(0, 0, -60)},
+	} {
+		issue.Status = types.StatusOpen
+		issue.Priority = 2
+		issue.IssueType = types.TypeBug
+		if err := s.CreateIssue(ctx, issue, issue.CreatedBy); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The reporter's own follow-up is not a response
+	if _, err := s.AddIssueComment(ctx, "test-1", "carol", "Still broken"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddIssueComment(ctx, "test-1", "alice", "Looking"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseIssue(ctx, "test-3", "fixed", "alice", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := buildResponseTimes(ctx, s, now.AddDate(0, 0, -30), nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Issues != 3 {
+		t.Fatalf("Issues = %d, want 3 (test-4 is too old)", r.Issues)
+	}
+	if r.FirstResponse.Count != 1 || math.Abs(r.FirstResponse.Median-4) > 0.1 {
+		t.Errorf("FirstResponse = %+v, want one response after ~4h", r.FirstResponse)
+	}
+	if r.Resolution.Count != 1 {
+		t.Errorf("Resolution = %+v, want one", r.Resolution)
+	}
+	if len(r.Awaiting) != 1 || r.Awaiting[0].ID != "test-2" {
+		t.Errorf("Awaiting = %+v, want [test-2]", r.Awaiting)
+	}
+	for _, item := range r.Items {
+		if item.ID == "test-1" && item.FirstResponder != "alice" {
+			t.Errorf("first responder = %q, want alice", item.FirstResponder)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/audit"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/retention"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var retentionCmd = &cobra.Command{
+	Use:     "retention",
+	GroupID: "maint",
+	Short:   "Show and apply the data retention policy",
+	Long: `Purge old data according to the project's retention policy.
+
+The policy is set in .beads/config.yaml, so every clone applies the same one:
+
+  retention.closed-comments   comments of issues closed longer ago (e.g. 1y)
+  retention.events            audit trail events (bd history) older than this
+  retention.interactions      .beads/interactions.jsonl entries older than this
+
+Ages are a count of h, d, w, m (months) or y. Unset keeps data forever.
+
+The daemon applies the policy on the retention.schedule cron (@daily by
+default), as the "retention" job in bd schedule list. Purged comments are
+dropped from issues.jsonl on the next export.`,
+	Example: `  bd config set retention.closed-comments 1y
+  bd config set retention.events 2y
+  bd retention preview
+  bd retention apply`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		policy := retentionPolicy()
+		spec := config.GetString(retention.KeySchedule)
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"policy": policy, "schedule": spec})
+			return
+		}
+		if policy.Empty() {
+			fmt.Println("No retention policy: all data is kept")
+			fmt.Println("Set one with e.g. 'bd config set retention.closed-comments 1y'")
+			return
+		}
+		printRetentionPolicy(policy)
+		if spec == "" {
+			fmt.Printf("  %s\n", ui.RenderMuted("Not scheduled (retention.schedule is empty); run 'bd retention apply'"))
+		} else {
+			fmt.Printf("  Applied by the daemon on %q\n", spec)
+		}
+	},
+}
+
+var retentionPreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Show what the retention policy would purge, without deleting",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runRetention(true)
+	},
+}
+
+var retentionApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Purge the data the retention policy no longer keeps",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("retention apply")
+		runRetention(false)
+	},
+}
+
+// retentionPolicy returns the configured policy, exiting on invalid ages.
+func retentionPolicy() retention.Policy {
+	policy, err := retention.PolicyFromConfig()
+	if err != nil {
+		FatalErrorCode(ErrCodeInvalid, "%v", err)
+	}
+	return policy
+}
+
+func printRetentionPolicy(policy retention.Policy) {
+	fmt.Println("Retention policy:")
+	for _, item := range []struct{ what, age string }{
+		{"Comments on closed issues", policy.ClosedComments},
+		{"Audit trail events", policy.Events},
+		{"Interactions log entries", policy.Interactions},
+	} {
+		age := "kept forever"
+		if item.age != "" {
+			age = "purged after " + item.age
+		}
+		fmt.Printf("  %-26s %s\n", item.what+":", age)
+	}
+}
+
+// runRetention applies the policy, or with dryRun reports what it would
+// purge.
+func runRetention(dryRun bool) {
+	if err := ensureDirectMode("retention requires direct database access"); err != nil {
+		FatalError("%v", err)
+	}
+	policy := retentionPolicy()
+	interactions, _ := audit.Path()
+	res, err := retention.Run(rootCtx, store, interactions, policy, time.Now(), dryRun)
+	if errors.Is(err, retention.ErrUnsupported) {
+		FatalErrorWithHint(err.Error(), "retention.interactions still works; unset the other retention keys")
+	}
+	if err != nil {
+		FatalErrorRespectJSON("retention failed: %v", err)
+	}
+	if !dryRun && res.Comments > 0 {
+		markDirtyAndScheduleFlush()
+	}
+
+	if jsonOutput {
+		outputJSON(res)
+		return
+	}
+	if policy.Empty() {
+		fmt.Println("No retention policy: nothing to purge")
+		return
+	}
+	verb := "Purged"
+	if dryRun {
+		verb = "Would purge"
+	}
+	if res.Total() == 0 {
+		fmt.Println("Nothing to purge")
+		return
+	}
+	if policy.ClosedComments != "" {
+		fmt.Printf("%s %d comment(s) on %d issue(s) closed more than %s ago\n", verb, res.Comments, len(res.CommentIssues), policy.ClosedComments)
+	}
+	if policy.Events != "" {
+		fmt.Printf("%s %d audit trail event(s) older than %s\n", verb, res.Events, policy.Events)
+	}
+	if policy.Interactions != "" {
+		fmt.Printf("%s %d interactions log entries older than %s\n", verb, res.Interactions, policy.Interactions)
+	}
+	if dryRun {
+		fmt.Printf("  %s\n", ui.RenderMuted("Run 'bd retention apply' to purge"))
+	}
+}
+
+func init() {
+	retentionCmd.AddCommand(retentionPreviewCmd, retentionApplyCmd)
+	rootCmd.AddCommand(retentionCmd)
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/todoscan"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// TODO issues are labeled todo, with external_ref todo:<file>#<fingerprint>
+// so a rescan finds them and knows which file they belong to.
+const (
+	todoLabel     = "todo"
+	todoRefPrefix = "todo:"
+	todoGone      = "Comment removed from the code"
+)
+
+// Scan actions
+const (
+	todoCreated   = "created"
+	todoUpdated   = "updated"
+	todoReopened  = "reopened"
+	todoUnchanged = "unchanged"
+	todoClosed    = "closed"
+)
+
+// ScanTodoItem is the outcome of bd scan todos for one comment.
+type ScanTodoItem struct {
+	todoscan.Todo
+	IssueID string `json:"issue_id,omitempty"`
+	Title   string `json:"title"`
+	Action  string `json:"action"`
+}
+
+// ScanTodosResult is the JSON output of bd scan todos.
+type ScanTodosResult struct {
+	Root  string          `json:"root"`
+	Paths []string        `json:"paths"`
+	Items []*ScanTodoItem `json:"items"`
+}
+
+var scanCmd = &cobra.Command{
+	Use:     "scan",
+	GroupID: "issues",
+	Short:   "Create issues from markers in the codebase",
+}
+
+var scanTodosCmd = &cobra.Command{
+	Use:   "todos",
+	Short: "Track TODO, FIXME and HACK comments as issues",
+	Long: `Find TODO, FIXME and HACK comments in the codebase and keep one issue per
+comment, labeled todo, with a link to its location:
+
+  TODO    a task
+  FIXME   a bug
+  HACK    a chore
+
+A marker counts when it starts a comment (//, #, /*, *, --, ; or <!--),
+optionally as TODO(name): with the author recorded in the issue.
+
+Each comment is fingerprinted by its file, marker and text, so a rescan
+updates its issue's location when lines move above it instead of filing a
+new one. Issues of comments that disappeared from the scanned paths are
+closed; one that comes back reopens its issue. Issues closed by hand stay
+closed. Editing a comment's text or moving it to another file replaces its
+issue.
+
+Paths are files or directories, scanned recursively; a Go-style ./...
+suffix is accepted. Files ignored by git, and hidden, vendor,
+node_modules and testdata directories, are skipped. Locations are relative
+to the root of the git work tree.`,
+	Example: `  bd scan todos
+  bd scan todos --path ./cmd/... --path ./internal/...
+  bd scan todos --dry-run --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		paths, _ := cmd.Flags().GetStringSlice("path")
+		priority, _ := cmd.Flags().GetInt("priority")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if priority < 0 || priority > 4 {
+			FatalErrorCode(ErrCodeUsage, "--priority must be between 0 and 4")
+		}
+		if !dryRun {
+			CheckReadonly("scan todos")
+		}
+		if err := ensureDirectMode("scan todos requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		root, patterns, err := todoScanPatterns(paths)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		todos, err := todoscan.Scan(root, patterns)
+		if err != nil {
+			FatalErrorRespectJSON("scanning %s: %v", root, err)
+		}
+		repo := prDefaultRepo(ctx, refs.NewChecker(ctx, store, root))
+
+		result := &ScanTodosResult{Root: root, Paths: patterns, Items: []*ScanTodoItem{}}
+		found := make(map[string]bool, len(todos))
+		for _, todo := range todos {
+			ref := todoRef(todo)
+			found[ref] = true
+			item := &ScanTodoItem{Todo: todo}
+			result.Items = append(result.Items, item)
+			existing, err := store.GetIssueByExternalRef(ctx, ref)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			item.Action = applyTodoIssue(ctx, item, existing, repo, priority, dryRun)
+		}
+
+		// Issues of comments in the scanned paths that are gone
+		open, err := store.SearchIssues(ctx, "", types.IssueFilter{
+			Labels:        []string{todoLabel},
+			ExcludeStatus: []types.Status{types.StatusClosed},
+		})
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		for _, issue := range open {
+			if issue.ExternalRef == nil || found[*issue.ExternalRef] {
+				continue
+			}
+			file, ok := todoRefFile(*issue.ExternalRef)
+			if !ok || !withinAny(file, patterns) {
+				continue
+			}
+			result.Items = append(result.Items, &ScanTodoItem{
+				Todo:    todoscan.Todo{File: file},
+				IssueID: issue.ID,
+				Title:   issue.Title,
+				Action:  todoClosed,
+			})
+			if !dryRun {
+				if err := store.CloseIssue(ctx, issue.ID, todoGone, actor, ""); err != nil {
+					FatalErrorRespectJSON("closing %s: %v", issue.ID, err)
+				}
+			}
+		}
+		if !dryRun {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		displayScanTodos(result, dryRun)
+	},
+}
+
+// applyTodoIssue creates, updates or reopens the issue of a comment and
+// returns the action taken.
+func applyTodoIssue(ctx context.Context, item *ScanTodoItem, existing *types.Issue, repo string, priority int, dryRun bool) string {
+	title := todoTitle(&item.Todo)
+	item.Title = title
+	description := todoDescription(&item.Todo, repo)
+
+	if existing == nil {
+		if dryRun {
+			return todoCreated
+		}
+		ref := todoRef(item.Todo)
+		issue := &types.Issue{
+			Title:       title,
+			Description: description,
+			Status:      types.StatusOpen,
+			Priority:    priority,
+			IssueType:   todoIssueType(item.Kind),
+			ExternalRef: &ref,
+			CreatedBy:   getActorWithGit(),
+			Owner:       getOwner(),
+		}
+		if err := store.CreateIssue(ctx, issue, actor); err != nil {
+			FatalErrorRespectJSON("creating issue for %s: %v", item.Location(), err)
+		}
+		if err := store.AddLabel(ctx, issue.ID, todoLabel, actor); err != nil {
+			WarnError("failed to add label %s: %v", todoLabel, err)
+		}
+		item.IssueID = issue.ID
+		return todoCreated
+	}
+
+	item.IssueID = existing.ID
+	if existing.Status == types.StatusClosed && existing.CloseReason != todoGone {
+		return todoUnchanged
+	}
+	updates := map[string]interface{}{}
+	if existing.Title != title {
+		updates["title"] = title
+	}
+	if existing.Description != description {
+		updates["description"] = description
+	}
+	action := todoUpdated
+	if existing.Status == types.StatusClosed {
+		updates["status"] = string(types.StatusOpen)
+		action = todoReopened
+	}
+	if len(updates) == 0 {
+		return todoUnchanged
+	}
+	if !dryRun {
+		if err := store.UpdateIssue(ctx, existing.ID, updates, actor); err != nil {
+			FatalErrorRespectJSON("updating %s: %v", existing.ID, err)
+		}
+	}
+	return action
+}
+
+// todoScanPatterns resolves --path values against the root of the git work
+// tree containing the current directory (or the current directory outside
+// git), returning the root and the paths relative to it.
+func todoScanPatterns(paths []string) (string, []string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", nil, err
+	}
+	root := cwd
+	if out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		root = strings.TrimSpace(string(out))
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = resolved
+	}
+	if len(paths) == 0 {
+		paths = []string{"./..."}
+	}
+	var patterns []string
+	for _, p := range paths {
+		recursive := strings.HasSuffix(p, "...")
+		p = strings.TrimSuffix(p, "...")
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(cwd, p)
+		}
+		rel, err := filepath.Rel(root, filepath.Clean(p))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", nil, fmt.Errorf("--path %s is outside %s", p, root)
+		}
+		rel = filepath.ToSlash(rel)
+		if recursive && rel != "." {
+			rel += "/..."
+		}
+		patterns = append(patterns, rel)
+	}
+	return root, uniqueStrings(patterns), nil
+}
+
+// withinAny reports whether a root-relative file is selected by a pattern.
+func withinAny(file string, patterns []string) bool {
+	for _, p := range patterns {
+		if todoscan.Within(file, strings.TrimSuffix(strings.TrimSuffix(p, "..."), "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// todoRef is the external_ref of a comment's issue.
+func todoRef(t todoscan.Todo) string {
+	return todoRefPrefix + t.File + "#" + t.Fingerprint
+}
+
+// todoRefFile returns the file a todo external_ref belongs to.
+func todoRefFile(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, todoRefPrefix) {
+		return "", false
+	}
+	i := strings.LastIndex(ref, "#")
+	if i < 0 {
+		return "", false
+	}
+	return ref[len(todoRefPrefix):i], true
+}
+
+// todoIssueType maps a marker to the type of its issue.
+func todoIssueType(kind string) types.IssueType {
+	switch kind {
+	case "FIXME":
+		return types.TypeBug
+	case "HACK":
+		return types.TypeChore
+	}
+	return types.TypeTask
+}
+
+// todoTitle is the title of a comment's issue.
+func todoTitle(t *todoscan.Todo) string {
+	if t.Text == "" {
+		return fmt.Sprintf("%s in %s", t.Kind, t.File)
+	}
+	return truncateTitle(t.Kind+": "+t.Text, 100)
+}
+
+// todoDescription is the description of a comment's issue: its text, author
+// and location, linked on GitHub when the repo is known.
+func todoDescription(t *todoscan.Todo, repo string) string {
+	var b strings.Builder
+	location := "`" + t.Location() + "`"
+	if repo != "" {
+		location = fmt.Sprintf("[%s](https://github.com/%s/blob/HEAD/%s#L%d)", t.Location(), repo, t.File, t.Line)
+	}
+	fmt.Fprintf(&b, "%s comment at %s", t.Kind, location)
+	if t.Author != "" {
+		fmt.Fprintf(&b, " by %s", t.Author)
+	}
+	b.WriteString(":\n\n")
+	text := t.Text
+	if text == "" {
+		text = "(no text)"
+	}
+	b.WriteString("> " + text + "\n\n")
+	b.WriteString("Tracked by bd scan todos; closes when the comment is removed.")
+	return b.String()
+}
+
+// displayScanTodos prints what the scan changed and a summary.
+func displayScanTodos(r *ScanTodosResult, dryRun bool) {
+	prefix := ""
+	if dryRun {
+		prefix = "(dry run) "
+	}
+	counts := make(map[string]int)
+	for _, item := range r.Items {
+		counts[item.Action]++
+		if item.Action == todoUnchanged {
+			continue
+		}
+		icon := ui.RenderAccent("+")
+		switch item.Action {
+		case todoClosed:
+			icon = ui.RenderPass("✓")
+		case todoUpdated, todoReopened:
+			icon = ui.RenderWarn("↻")
+		}
+		id := item.IssueID
+		if id == "" {
+			id = "-"
+		}
+		where := item.File
+		if item.Line > 0 {
+			where = item.Location()
+		}
+		fmt.Printf("  %s %-10s %-9s %s %s\n", icon, id, item.Action, where, ui.RenderMuted(item.Title))
+	}
+	fmt.Printf("%s %s%d comments: %d created, %d updated, %d reopened, %d closed, %d unchanged\n",
+		ui.RenderPass("✓"), prefix, len(r.Items)-counts[todoClosed],
+		counts[todoCreated], counts[todoUpdated], counts[todoReopened], counts[todoClosed], counts[todoUnchanged])
+}
+
+func init() {
+	scanTodosCmd.Flags().StringSlice("path", []string{"./..."}, "Files or directories to scan (repeatable)")
+	scanTodosCmd.Flags().IntP("priority", "p", 3, "Priority of new issues (0-4)")
+	scanTodosCmd.Flags().Bool("dry-run", false, "Show what would change without writing")
+	scanCmd.AddCommand(scanTodosCmd)
+	rootCmd.AddCommand(scanCmd)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/todoscan"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestTodoRef(t *testing.T) {
+	todo := todoscan.Todo{Kind: "TODO", File: "cmd/a#b.go", Line: 3, Fingerprint: "abc123"}
+	ref := todoRef(todo)
+	if ref != "todo:cmd/a#b.go#abc123" {
+		t.Fatalf("todoRef = %q", ref)
+	}
+	if file, ok := todoRefFile(ref); !ok || file != "cmd/a#b.go" {
+		t.Errorf("todoRefFile = %q, %v", file, ok)
+	}
+	if _, ok := todoRefFile("flaky:TestX"); ok {
+		t.Errorf("todoRefFile accepted a non-todo ref")
+	}
+}
+
+func TestWithinAny(t *testing.T) {
+	patterns := []string{"cmd/...", "main.go"}
+	for file, want := range map[string]bool{
+		"cmd/bd/a.go":   true,
+		"main.go":       true,
+		"cmdx/a.go":     false,
+		"internal/b.go": false,
+	} {
+		if got := withinAny(file, patterns); got != want {
+			t.Errorf("withinAny(%q) = %v, want %v", file, got, want)
+		}
+	}
+	if !withinAny("anything.go", []string{"."}) {
+		t.Errorf("withinAny with root pattern = false")
+	}
+}
+
+func TestTodoIssueText(t *testing.T) {
+	todo := &todoscan.Todo{Kind: "FIXME", Author: "ann", Text: "off by one", File: "a.go", Line: 7}
+	if got := todoTitle(todo); got != "FIXME: off by one" {
+		t.Errorf("todoTitle = %q", got)
+	}
+	if got := todoTitle(&todoscan.Todo{Kind: "TODO", File: "a.go"}); got != "TODO in a.go" {
+		t.Errorf("todoTitle without text = %q", got)
+	}
+	desc := todoDescription(todo, "acme/widgets")
+	if !strings.Contains(desc, "https://github.com/acme/widgets/blob/HEAD/a.go#L7") || !strings.Contains(desc, "by ann") {
+		t.Errorf("todoDescription = %q", desc)
+	}
+	if desc := todoDescription(todo, ""); !strings.Contains(desc, "`a.go:7`") {
+		t.Errorf("todoDescription without repo = %q", desc)
+	}
+	if todoIssueType("FIXME") != types.TypeBug || todoIssueType("HACK") != types.TypeChore || todoIssueType("TODO") != types.TypeTask {
+		t.Errorf("todoIssueType mapping wrong")
+	}
+}
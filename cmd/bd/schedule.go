@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/retention"
+	"github.com/steveyegge/beads/internal/schedule"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// scheduleRunsFile holds the run history in .beads (gitignored, local-only).
+const scheduleRunsFile = "schedule-runs.log"
+
+// schedulerTick is how often the daemon checks for due jobs.
+const schedulerTick = 30 * time.Second
+
+var scheduleCmd = &cobra.Command{
+	Use:     "schedule",
+	GroupID: "maint",
+	Short:   "Run bd commands on a cron schedule inside the daemon",
+	Long: `Schedule recurring bd commands - reports, stale scans, backups, syncs -
+without an external cron. The daemon checks the schedule every 30 seconds
+and runs due jobs as "bd <command>" in the repository root. Jobs only run
+while the daemon is running; runs missed while it was stopped are skipped.
+
+Schedules are standard five-field cron expressions (minute hour day month
+weekday), e.g. "0 9 * * 1" for Mondays at 9:00 local time, or one of
+@hourly, @daily, @weekly, @monthly, @yearly.
+
+Jobs are stored in the database config; the output and exit code of each
+run are kept in .beads/schedule-runs.log (see bd schedule runs).`,
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <cron> <command>",
+	Short: "Schedule a bd command",
+	Long: `Schedule a bd command. Quote the command so its flags aren't taken as
+flags of bd schedule add. Jobs are named after the command unless --name
+is given.`,
+	Example: `  bd schedule add "0 9 * * 1" "digest --since 1w -o weekly.md"
+  bd schedule add "*/30 * * * *" "sync" --name sync
+  bd schedule add @daily "stale --days 30"`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("schedule add")
+		if err := ensureDirectMode("schedule add writes config directly"); err != nil {
+			FatalError("%v", err)
+		}
+		ctx := rootCtx
+		name, _ := cmd.Flags().GetString("name")
+		spec, command := args[0], strings.Join(args[1:], " ")
+
+		c, err := schedule.ParseCron(spec)
+		if err != nil {
+			FatalErrorCode(ErrCodeInvalid, "%v", err)
+		}
+		cmdArgs, err := schedule.Args(command)
+		if err != nil {
+			FatalErrorCode(ErrCodeInvalid, "invalid command: %v", err)
+		}
+		if target, _, err := rootCmd.Find(cmdArgs); err != nil || target == rootCmd {
+			FatalErrorCode(ErrCodeInvalid, "unknown bd command %q", cmdArgs[0])
+		}
+
+		jobs, err := scheduledJobs(ctx, store)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		if name == "" {
+			name = uniqueJobName(jobs, cmdArgs[0])
+		} else if !validSavedQueryName(name) {
+			FatalErrorCode(ErrCodeInvalid, "invalid job name %q (use letters, digits, - and _)", name)
+		}
+		for _, j := range jobs {
+			if j.ID == name {
+				FatalErrorCode(ErrCodeInvalid, "job %s already exists (remove it first)", name)
+			}
+		}
+
+		job := schedule.Job{ID: name, Cron: spec, Command: command, CreatedAt: time.Now().UTC()}
+		if err := store.SetConfig(ctx, schedule.ConfigPrefix+name, job.Encode()); err != nil {
+			FatalError("saving job: %v", err)
+		}
+		next := c.Next(time.Now())
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"job": job, "next_run": next})
+			return
+		}
+		fmt.Printf("%s Scheduled %s: bd %s\n", ui.RenderPass("✓"), ui.RenderAccent(name), command)
+		if next.IsZero() {
+			fmt.Printf("  %s %q never matches a date\n", ui.RenderWarn("⚠"), spec)
+		} else {
+			fmt.Printf("  Next run: %s\n", next.Format("Mon 2006-01-02 15:04"))
+		}
+		if pidFile, err := getPIDFilePath(); err == nil && !isDaemonRunningQuiet(pidFile) {
+			fmt.Printf("  %s\n", ui.RenderMuted("Jobs run inside the daemon; start it with 'bd daemon start'"))
+		}
+	},
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled jobs with their next and last runs",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("schedule list reads config directly"); err != nil {
+			FatalError("%v", err)
+		}
+		jobs, err := scheduledJobs(rootCtx, store)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		runs, _ := schedule.ReadRuns(scheduleRunsPath())
+		last := make(map[string]schedule.Run)
+		for _, r := range runs {
+			last[r.Job] = r
+		}
+
+		now := time.Now()
+		if jsonOutput {
+			type jobJSON struct {
+				schedule.Job
+				NextRun *time.Time    `json:"next_run,omitempty"`
+				LastRun *schedule.Run `json:"last_run,omitempty"`
+			}
+			out := make([]jobJSON, 0, len(jobs))
+			for _, j := range jobs {
+				item := jobJSON{Job: j}
+				if next := j.Next(now); !next.IsZero() {
+					item.NextRun = &next
+				}
+				if r, ok := last[j.ID]; ok {
+					item.LastRun = &r
+				}
+				out = append(out, item)
+			}
+			outputJSON(out)
+			return
+		}
+		if len(jobs) == 0 {
+			fmt.Println("No scheduled jobs (add one with 'bd schedule add <cron> <command>')")
+			return
+		}
+		for _, j := range jobs {
+			fmt.Printf("%s  %s  bd %s\n", ui.RenderAccent(j.ID), j.Cron, j.Command)
+			next := "never"
+			if n := j.Next(now); !n.IsZero() {
+				next = n.Format("Mon 2006-01-02 15:04")
+			}
+			lastRun := "never"
+			if r, ok := last[j.ID]; ok {
+				lastRun = r.Started.Local().Format("Mon 2006-01-02 15:04") + " " + runStatus(r)
+			}
+			fmt.Printf("  next: %s   last: %s\n", next, lastRun)
+		}
+	},
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:     "remove <id>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a scheduled job",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("schedule remove")
+		if err := ensureDirectMode("schedule remove writes config directly"); err != nil {
+			FatalError("%v", err)
+		}
+		key := schedule.ConfigPrefix + args[0]
+		if value, _ := store.GetConfig(rootCtx, key); value == "" {
+			if _, ok := retentionJob(); ok && args[0] == retention.JobID {
+				FatalErrorWithHint("the retention job runs the retention policy",
+					"stop it with 'bd config set retention.schedule \"\"'")
+			}
+			FatalErrorCode(ErrCodeNotFound, "no scheduled job %s", args[0])
+		}
+		if err := store.DeleteConfig(rootCtx, key); err != nil {
+			FatalError("removing job: %v", err)
+		}
+		if jsonOutput {
+			outputJSON(map[string]string{"removed": args[0]})
+			return
+		}
+		fmt.Printf("%s Removed %s\n", ui.RenderPass("✓"), args[0])
+	},
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run <id>",
+	Short: "Run a scheduled job now and record it in the history",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("schedule run reads config directly"); err != nil {
+			FatalError("%v", err)
+		}
+		jobs, err := scheduledJobs(rootCtx, store)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		var job schedule.Job
+		for _, j := range jobs {
+			if j.ID == args[0] {
+				job = j
+			}
+		}
+		if job.ID == "" {
+			FatalErrorCode(ErrCodeNotFound, "no scheduled job %s", args[0])
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			FatalError("locating bd: %v", err)
+		}
+		run := schedule.Execute(rootCtx, exe, filepath.Dir(filepath.Dir(dbPath)), job, schedule.DefaultTimeout)
+		if err := schedule.AppendRun(scheduleRunsPath(), run); err != nil {
+			fmt.Fprintf(os.Stderr, "%s recording run: %v\n", ui.RenderWarn("⚠"), err)
+		}
+		if jsonOutput {
+			outputJSON(run)
+		} else {
+			if run.Output != "" {
+				fmt.Println(run.Output)
+			}
+			fmt.Printf("%s %s in %.1fs\n", ui.RenderAccent(job.ID), runStatus(run), run.Duration)
+		}
+		if !run.OK() {
+			os.Exit(1)
+		}
+	},
+}
+
+var scheduleRunsCmd = &cobra.Command{
+	Use:   "runs [id]",
+	Short: "Show the run history, newest first",
+	Example: `  bd schedule runs
+  bd schedule runs digest --limit 5 --output`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		showOutput, _ := cmd.Flags().GetBool("output")
+		runs, err := schedule.ReadRuns(scheduleRunsPath())
+		if err != nil {
+			FatalError("reading run history: %v", err)
+		}
+		var shown []schedule.Run
+		for i := len(runs) - 1; i >= 0 && (limit <= 0 || len(shown) < limit); i-- {
+			if len(args) == 0 || runs[i].Job == args[0] {
+				shown = append(shown, runs[i])
+			}
+		}
+		if jsonOutput {
+			if shown == nil {
+				shown = []schedule.Run{}
+			}
+			outputJSON(shown)
+			return
+		}
+		if len(shown) == 0 {
+			fmt.Println("No runs recorded yet")
+			return
+		}
+		for _, r := range shown {
+			fmt.Printf("%s  %-16s %-12s %6.1fs  bd %s\n", r.Started.Local().Format("2006-01-02 15:04"),
+				r.Job, runStatus(r), r.Duration, truncateString(r.Command, 50))
+			if showOutput && r.Output != "" {
+				for _, line := range strings.Split(r.Output, "\n") {
+					fmt.Printf("    %s\n", ui.RenderMuted(line))
+				}
+			}
+		}
+	},
+}
+
+func runStatus(r schedule.Run) string {
+	switch {
+	case r.OK():
+		return ui.RenderPass("ok")
+	case r.Error != "":
+		return ui.RenderFail(r.Error)
+	default:
+		return ui.RenderFail(fmt.Sprintf("exit %d", r.ExitCode))
+	}
+}
+
+func scheduleRunsPath() string {
+	return filepath.Join(filepath.Dir(dbPath), scheduleRunsFile)
+}
+
+// scheduledJobs returns the jobs stored in config, plus the retention
+// policy's job when one is set, sorted by ID.
+func scheduledJobs(ctx context.Context, s storage.Storage) ([]schedule.Job, error) {
+	all, err := s.GetAllConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	var jobs []schedule.Job
+	for key, value := range all {
+		id, ok := strings.CutPrefix(key, schedule.ConfigPrefix)
+		if !ok || value == "" {
+			continue
+		}
+		job, err := schedule.Decode(id, value)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if job, ok := retentionJob(); ok && all[schedule.ConfigPrefix+job.ID] == "" {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs, nil
+}
+
+// retentionJob returns the job applying the retention policy, if a policy
+// and retention.schedule are set.
+func retentionJob() (schedule.Job, bool) {
+	policy, err := retention.PolicyFromConfig()
+	spec := config.GetString(retention.KeySchedule)
+	if err != nil || policy.Empty() || spec == "" {
+		return schedule.Job{}, false
+	}
+	return schedule.Job{ID: retention.JobID, Cron: spec, Command: "retention apply"}, true
+}
+
+// uniqueJobName names a job after its command, adding -2, -3, ... as needed.
+func uniqueJobName(jobs []schedule.Job, base string) string {
+	taken := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		taken[j.ID] = true
+	}
+	name := base
+	for n := 2; taken[name]; n++ {
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+	return name
+}
+
+// startScheduler runs scheduled jobs until ctx is canceled. Jobs are
+// re-read on every tick, so changes apply without restarting the daemon. A
+// job still running when it comes due again is skipped that time.
+func startScheduler(ctx context.Context, s storage.Storage, beadsDir string, log daemonLogger) {
+	exe, err := os.Executable()
+	if err != nil {
+		log.Warn("scheduler not started", "error", err)
+		return
+	}
+	runsPath := filepath.Join(beadsDir, scheduleRunsFile)
+	workDir := filepath.Dir(beadsDir)
+	var mu sync.Mutex
+	running := make(map[string]bool)
+
+	go func() {
+		ticker := time.NewTicker(schedulerTick)
+		defer ticker.Stop()
+		last := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				jobs, err := scheduledJobs(ctx, s)
+				if err != nil {
+					log.Error("scheduler failed to read jobs", "error", err)
+					continue
+				}
+				for _, job := range schedule.Due(jobs, last, now) {
+					mu.Lock()
+					busy := running[job.ID]
+					running[job.ID] = true
+					mu.Unlock()
+					if busy {
+						log.Warn("scheduled job still running, skipping", "job", job.ID)
+						continue
+					}
+					go func(job schedule.Job) {
+						log.Info("running scheduled job", "job", job.ID, "command", job.Command)
+						run := schedule.Execute(ctx, exe, workDir, job, schedule.DefaultTimeout)
+						mu.Lock()
+						delete(running, job.ID)
+						err := schedule.AppendRun(runsPath, run)
+						mu.Unlock()
+						if err != nil {
+							log.Error("failed to record scheduled run", "job", job.ID, "error", err)
+						}
+						if run.OK() {
+							log.Info("scheduled job finished", "job", job.ID, "seconds", run.Duration)
+						} else {
+							log.Warn("scheduled job failed", "job", job.ID, "exit_code", run.ExitCode, "error", run.Error)
+						}
+					}(job)
+				}
+				last = now
+			}
+		}
+	}()
+}
+
+func init() {
+	scheduleAddCmd.Flags().String("name", "", "Job ID (default: the command name)")
+	scheduleRunsCmd.Flags().Int("limit", 20, "Maximum runs to show (0 for all)")
+	scheduleRunsCmd.Flags().Bool("output", false, "Show each run's output")
+	scheduleCmd.AddCommand(scheduleAddCmd, scheduleListCmd, scheduleRemoveCmd, scheduleRunCmd, scheduleRunsCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
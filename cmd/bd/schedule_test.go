@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/schedule"
+)
+
+func TestScheduledJobs(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	for _, job := range []schedule.Job{
+		{ID: "stale", Cron: "@daily", Command: "stale --days 30", CreatedAt: time.Now().UTC()},
+		{ID: "digest", Cron: "0 9 * * 1", Command: "digest", CreatedAt: time.Now().UTC()},
+	} {
+		if err := s.SetConfig(ctx, schedule.ConfigPrefix+job.ID, job.Encode()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SetConfig(ctx, "scheduled_unrelated", "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := scheduledJobs(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != "digest" || jobs[1].ID != "stale" || jobs[1].Command != "stale --days 30" {
+		t.Fatalf("scheduledJobs = %+v", jobs)
+	}
+
+	if got := uniqueJobName(jobs, "digest"); got != "digest-2" {
+		t.Errorf("uniqueJobName(digest) = %q, want digest-2", got)
+	}
+	if got := uniqueJobName(jobs, "sync"); got != "sync" {
+		t.Errorf("uniqueJobName(sync) = %q, want sync", got)
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/jsonschema"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// jsonOutputVersion is the version of bd's --json output and JSONL record
+// format. Within a version, changes are additive only: new fields and new
+// optional properties may appear, but existing fields keep their name, type
+// and meaning. Removing or retyping a field requires a new version.
+const jsonOutputVersion = 1
+
+// outputSchema describes the JSON document a set of commands produce.
+type outputSchema struct {
+	Name        string
+	Commands    []string
+	Description string
+	Value       interface{} // zero value of the Go type that is encoded
+}
+
+// jsonOutputSchemas lists every published schema. Keep it in sync with the
+// types commands pass to outputJSON.
+var jsonOutputSchemas = []outputSchema{
+	{
+		Name:        "jsonl-record",
+		Commands:    []string{"export", "import", "sync"},
+		Description: "One line of .beads/issues.jsonl",
+		Value:       types.Issue{},
+	},
+	{
+		Name:        "issue",
+		Commands:    []string{"create"},
+		Description: "A single issue",
+		Value:       types.Issue{},
+	},
+	{
+		Name:        "issue-array",
+		Commands:    []string{"update", "close", "reopen", "ready", "stale"},
+		Description: "Issues affected or returned by the command",
+		Value:       []*types.Issue{},
+	},
+	{
+		Name:        "issue-list",
+		Commands:    []string{"list", "search"},
+		Description: "Issues with dependency counts",
+		Value:       []*types.IssueWithCounts{},
+	},
+	{
+		Name:        "issue-page",
+		Commands:    []string{"list --cursor", "search --cursor"},
+		Description: "One page of issues plus the cursor for the next page",
+		Value:       types.IssuePage[*types.IssueWithCounts]{},
+	},
+	{
+		Name:        "ready-page",
+		Commands:    []string{"ready --cursor"},
+		Description: "One page of ready issues plus the cursor for the next page",
+		Value:       types.IssuePage[*types.Issue]{},
+	},
+	{
+		Name:        "issue-counts",
+		Commands:    []string{"list --count", "count --by-*"},
+		Description: "Issue total, optionally with per-group counts",
+		Value:       types.IssueCounts{},
+	},
+	{
+		Name:        "issue-details",
+		Commands:    []string{"show"},
+		Description: "Issues with labels, dependencies, dependents and comments",
+		Value:       []*types.IssueDetails{},
+	},
+	{
+		Name:        "blocked",
+		Commands:    []string{"blocked"},
+		Description: "Blocked issues and the issues blocking them",
+		Value:       []*types.BlockedIssue{},
+	},
+	{
+		Name:        "dep-tree",
+		Commands:    []string{"dep tree"},
+		Description: "Dependency tree nodes in display order",
+		Value:       []*types.TreeNode{},
+	},
+	{
+		Name:        "status",
+		Commands:    []string{"status", "stats"},
+		Description: "Database summary and recent activity",
+		Value:       StatusOutput{},
+	},
+	{
+		Name:        "prompt",
+		Commands:    []string{"prompt"},
+		Description: "Ready, blocked and in-progress work summary",
+		Value:       types.PromptSummary{},
+	},
+	{
+		Name:        "error",
+		Commands:    []string{"*"},
+		Description: "Fatal error, written to stderr in --json mode",
+		Value:       cliError{},
+	},
+}
+
+// schemaBundle is the document written by 'bd schema dump' without --output.
+type schemaBundle struct {
+	Output  schemaBundleOutput            `json:"output"`
+	Schemas map[string]*jsonschema.Schema `json:"schemas"`
+}
+
+type schemaBundleOutput struct {
+	Version int `json:"version"`
+}
+
+var schemaCmd = &cobra.Command{
+	Use:     "schema",
+	GroupID: "advanced",
+	Short:   "JSON Schemas for --json output and the JSONL format",
+	Long: `Publish JSON Schema (draft 2020-12) documents describing bd's machine-readable
+output, so downstream tools can validate what they consume.
+
+Every schema carries the output version ("x-output-version"). Within a
+version, changes are additive only: fields may be added, but existing fields
+keep their name, type and meaning. Tools should ignore unknown fields.`,
+}
+
+var schemaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List published schemas",
+	Run: func(cmd *cobra.Command, args []string) {
+		if jsonOutput {
+			type entry struct {
+				Name        string   `json:"name"`
+				Commands    []string `json:"commands"`
+				Description string   `json:"description"`
+			}
+			entries := make([]entry, 0, len(jsonOutputSchemas))
+			for _, s := range jsonOutputSchemas {
+				entries = append(entries, entry{s.Name, s.Commands, s.Description})
+			}
+			outputJSON(entries)
+			return
+		}
+		fmt.Printf("Output version: %d\n\n", jsonOutputVersion)
+		for _, s := range jsonOutputSchemas {
+			fmt.Printf("  %-15s %s (%s)\n", s.Name, s.Description, strings.Join(s.Commands, ", "))
+		}
+	},
+}
+
+var schemaDumpCmd = &cobra.Command{
+	Use:   "dump [name...]",
+	Short: "Write JSON Schema documents",
+	Long: `Write JSON Schema documents for bd's --json output.
+
+Without --output, prints a single document:
+
+  {"output": {"version": 1}, "schemas": {"issue": {...}, ...}}
+
+With --output DIR, writes one <name>.schema.json file per schema.
+
+Examples:
+  bd schema dump                        # All schemas to stdout
+  bd schema dump issue-list             # Just the list/search output
+  bd schema dump -o docs/schema         # One file per schema`,
+	Run: func(cmd *cobra.Command, args []string) {
+		outDir, _ := cmd.Flags().GetString("output")
+
+		schemas, err := buildOutputSchemas(args)
+		if err != nil {
+			FatalErrorCode(ErrCodeNotFound, "%v", err)
+		}
+
+		if outDir == "" {
+			data, err := json.MarshalIndent(schemaBundle{
+				Output:  schemaBundleOutput{Version: jsonOutputVersion},
+				Schemas: schemas,
+			}, "", "  ")
+			if err != nil {
+				FatalError("encoding schemas: %v", err)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			FatalError("creating %s: %v", outDir, err)
+		}
+		names := make([]string, 0, len(schemas))
+		for name := range schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			data, err := json.MarshalIndent(schemas[name], "", "  ")
+			if err != nil {
+				FatalError("encoding schema %s: %v", name, err)
+			}
+			path := filepath.Join(outDir, name+".schema.json")
+			// #nosec G306 - schemas are public documentation
+			if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+				FatalError("writing %s: %v", path, err)
+			}
+		}
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"output_version": jsonOutputVersion,
+				"dir":            outDir,
+				"schemas":        names,
+			})
+			return
+		}
+		fmt.Printf("Wrote %d schemas to %s\n", len(names), outDir)
+	},
+}
+
+// buildOutputSchemas generates the named schemas, or all of them if names
+// is empty.
+func buildOutputSchemas(names []string) (map[string]*jsonschema.Schema, error) {
+	byName := make(map[string]outputSchema, len(jsonOutputSchemas))
+	for _, s := range jsonOutputSchemas {
+		byName[s.Name] = s
+	}
+	if len(names) == 0 {
+		for _, s := range jsonOutputSchemas {
+			names = append(names, s.Name)
+		}
+	}
+
+	out := make(map[string]*jsonschema.Schema, len(names))
+	for _, name := range names {
+		s, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown schema %q (see 'bd schema list')", name)
+		}
+		doc := jsonschema.Reflect(s.Value)
+		doc.Title = s.Name
+		doc.Description = s.Description
+		doc.Extensions = map[string]interface{}{
+			"x-output-version": jsonOutputVersion,
+			"x-commands":       s.Commands,
+		}
+		out[name] = doc
+	}
+	return out, nil
+}
+
+func init() {
+	schemaDumpCmd.Flags().StringP("output", "o", "", "Write one <name>.schema.json file per schema to this directory")
+	schemaCmd.AddCommand(schemaListCmd)
+	schemaCmd.AddCommand(schemaDumpCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
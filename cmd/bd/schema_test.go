@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/jsonschema"
+)
+
+// checkSchema reports every place where v (decoded JSON) doesn't match s:
+// wrong types, missing required properties, and properties the schema
+// doesn't declare.
+func checkSchema(root, s *jsonschema.Schema, v interface{}, path string) []string {
+	if s.Ref != "" {
+		return checkSchema(root, root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")], v, path)
+	}
+	if len(s.AnyOf) > 0 {
+		var first []string
+		for i, alt := range s.AnyOf {
+			errs := checkSchema(root, alt, v, path)
+			if len(errs) == 0 {
+				return nil
+			}
+			if i == 0 {
+				first = errs
+			}
+		}
+		return first
+	}
+
+	var errs []string
+	switch s.Type {
+	case nil:
+	case "null":
+		if v != nil {
+			errs = append(errs, path+": want null")
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			errs = append(errs, fmt.Sprintf("%s: want string, got %T", path, v))
+		}
+	case "integer", "number":
+		if _, ok := v.(float64); !ok {
+			errs = append(errs, fmt.Sprintf("%s: want number, got %T", path, v))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			errs = append(errs, fmt.Sprintf("%s: want boolean, got %T", path, v))
+		}
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: want array, got %T", path, v))
+		}
+		for i, item := range arr {
+			errs = append(errs, checkSchema(root, s.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: want object, got %T", path, v))
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required %q", path, name))
+			}
+		}
+		for name, val := range obj {
+			prop, ok := s.Properties[name]
+			if !ok && s.AdditionalProperties != nil {
+				prop, ok = s.AdditionalProperties, true
+			}
+			if !ok {
+				errs = append(errs, fmt.Sprintf("%s: undeclared property %q", path, name))
+				continue
+			}
+			errs = append(errs, checkSchema(root, prop, val, path+"."+name)...)
+		}
+	}
+	return errs
+}
+
+func TestSchemaMatchesCommandOutput(t *testing.T) {
+	p := newTestProject(t)
+
+	var parent, child map[string]interface{}
+	p.RunJSON(&parent, "create", "Parent", "-t", "epic", "-l", "area", "--due", "+2d")
+	p.RunJSON(&child, "create", "Child", "--parent", parent["id"].(string), "--estimate", "30")
+	blocker := p.Run("create", "Blocker", "--silent")
+	p.Run("dep", "add", child["id"].(string), strings.TrimSpace(blocker))
+	p.Run("comments", "add", child["id"].(string), "looks good")
+
+	schemas, err := buildOutputSchemas(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		schema string
+		args   []string
+	}{
+		{"issue", []string{"create", "Another", "--external-ref", "gh-1"}},
+		{"issue-array", []string{"update", child["id"].(string), "--status", "in_progress"}},
+		{"issue-array", []string{"ready"}},
+		{"issue-list", []string{"list"}},
+		{"issue-details", []string{"show", child["id"].(string), parent["id"].(string)}},
+		{"blocked", []string{"blocked"}},
+		{"dep-tree", []string{"dep", "tree", child["id"].(string)}},
+		{"status", []string{"status", "--no-activity"}},
+	}
+	for _, tc := range cases {
+		t.Run(strings.Join(tc.args[:1], " ")+"/"+tc.schema, func(t *testing.T) {
+			var v interface{}
+			p.RunJSON(&v, tc.args...)
+			s := schemas[tc.schema]
+			for _, e := range checkSchema(s, s, v, "$") {
+				t.Error(e)
+			}
+		})
+	}
+
+	t.Run("jsonl-record", func(t *testing.T) {
+		p.Run("sync", "--flush-only")
+		data, err := os.ReadFile(filepath.Join(p.BeadsDir, "issues.jsonl"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := schemas["jsonl-record"]
+		for i, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var v interface{}
+			if err := json.Unmarshal([]byte(line), &v); err != nil {
+				t.Fatalf("line %d: %v", i+1, err)
+			}
+			for _, e := range checkSchema(s, s, v, fmt.Sprintf("line %d", i+1)) {
+				t.Error(e)
+			}
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		res := p.RunCommand("show", "test-nope", "--json")
+		lines := strings.Split(strings.TrimSpace(res.Stderr), "\n")
+		var v interface{}
+		if err := json.Unmarshal([]byte(lines[len(lines)-1]), &v); err != nil {
+			t.Fatalf("stderr is not JSON: %v\n%s", err, res.Stderr)
+		}
+		s := schemas["error"]
+		for _, e := range checkSchema(s, s, v, "$") {
+			t.Error(e)
+		}
+	})
+}
+
+func TestSchemaDump(t *testing.T) {
+	p := newTestProject(t)
+
+	var bundle struct {
+		Output struct {
+			Version int `json:"version"`
+		} `json:"output"`
+		Schemas map[string]map[string]interface{} `json:"schemas"`
+	}
+	if err := json.Unmarshal([]byte(p.Run("schema", "dump")), &bundle); err != nil {
+		t.Fatal(err)
+	}
+	if bundle.Output.Version != jsonOutputVersion || len(bundle.Schemas) != len(jsonOutputSchemas) {
+		t.Fatalf("version=%d schemas=%d", bundle.Output.Version, len(bundle.Schemas))
+	}
+	if v := bundle.Schemas["issue"]["x-output-version"]; v != float64(jsonOutputVersion) {
+		t.Errorf("x-output-version = %v", v)
+	}
+
+	dir := t.TempDir()
+	p.Run("schema", "dump", "issue", "error", "-o", dir)
+	for _, name := range []string{"issue", "error"} {
+		if _, err := os.Stat(filepath.Join(dir, name+".schema.json")); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if res := p.RunCommand("schema", "dump", "nope"); res.ExitCode() != ErrCodeNotFound.ExitCode() {
+		t.Errorf("unknown schema exit = %d", res.ExitCode())
+	}
+}
@@ -172,6 +172,19 @@ Examples:
 			filter.PriorityMax = &priorityMax
 		}
 
+		// --cursor: fetch one extra row to learn whether another page follows
+		paginate, cursor, after := cursorFlag(cmd)
+		if paginate {
+			if sortBy != "" {
+				FatalErrorCode(ErrCodeUsage, "--cursor cannot be combined with --sort (pages follow the default order)")
+			}
+			filter.After = after
+			if limit > 0 {
+				filter.Limit = limit + 1
+			}
+		}
+		var nextCursor string
+
 		ctx := rootCtx
 
 		// Check database freshness before reading (skip when using daemon)
@@ -190,6 +203,8 @@ Examples:
 				IssueType: issueType,
 				Assignee:  assignee,
 				Limit:     limit,
+				Paginate:  paginate,
+				Cursor:    cursor,
 			}
 
 			if len(labels) > 0 {
@@ -231,6 +246,16 @@ Examples:
 			}
 
 			if jsonOutput {
+				if paginate {
+					// Already an IssuePage envelope
+					var page types.IssuePage[*types.IssueWithCounts]
+					if err := json.Unmarshal(resp.Data, &page); err != nil {
+						fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
+						os.Exit(1)
+					}
+					outputJSON(page)
+					return
+				}
 				var issuesWithCounts []*types.IssueWithCounts
 				if err := json.Unmarshal(resp.Data, &issuesWithCounts); err != nil {
 					fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
@@ -241,7 +266,14 @@ Examples:
 			}
 
 			var issues []*types.Issue
-			if err := json.Unmarshal(resp.Data, &issues); err != nil {
+			if paginate {
+				var page types.IssuePage[*types.Issue]
+				if err := json.Unmarshal(resp.Data, &page); err != nil {
+					fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
+					os.Exit(1)
+				}
+				issues, nextCursor = page.Issues, page.NextCursor
+			} else if err := json.Unmarshal(resp.Data, &issues); err != nil {
 				fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
 				os.Exit(1)
 			}
@@ -250,6 +282,9 @@ Examples:
 			sortIssues(issues, sortBy, reverse)
 
 			outputSearchResults(issues, query, longFormat)
+			if paginate {
+				printPageHint(limit, len(issues), paginate, nextCursor)
+			}
 			return
 		}
 
@@ -273,6 +308,10 @@ Examples:
 			}
 		}
 
+		if paginate {
+			issues, nextCursor = types.Paginate(issues, limit, types.CursorFor)
+		}
+
 		// Apply sorting
 		sortIssues(issues, sortBy, reverse)
 
@@ -311,6 +350,10 @@ Examples:
 					DependentCount:  counts.DependentCount,
 				}
 			}
+			if paginate {
+				outputJSON(types.IssuePage[*types.IssueWithCounts]{Issues: issuesWithCounts, NextCursor: nextCursor})
+				return
+			}
 			outputJSON(issuesWithCounts)
 			return
 		}
@@ -326,6 +369,9 @@ Examples:
 		}
 
 		outputSearchResults(issues, query, longFormat)
+		if paginate {
+			printPageHint(limit, len(issues), paginate, nextCursor)
+		}
 	},
 }
 
@@ -377,6 +423,7 @@ func init() {
 	searchCmd.Flags().StringSliceP("label", "l", []string{}, "Filter by labels (AND: must have ALL)")
 	searchCmd.Flags().StringSlice("label-any", []string{}, "Filter by labels (OR: must have AT LEAST ONE)")
 	searchCmd.Flags().IntP("limit", "n", 50, "Limit results (default: 50)")
+	addCursorFlag(searchCmd)
 	searchCmd.Flags().Bool("long", false, "Show detailed multi-line output for each issue")
 	searchCmd.Flags().String("sort", "", "Sort by field: priority, created, updated, closed, status, id, title, type, assignee")
 	searchCmd.Flags().BoolP("reverse", "r", false, "Reverse sort order")
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/advisory"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/timeparsing"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+var securityCmd = &cobra.Command{
+	Use:     "security",
+	GroupID: "issues",
+	Short:   "Track security advisory metadata on issues",
+	Long: `Record security advisory metadata on issues: CVE ID, CVSS score, affected
+versions and an embargo date. The metadata syncs with the issue, bd show
+prints it, and bd list --security / --min-cvss filter on it.
+
+While an issue is under embargo, bd export withholds its details from
+every output but the tracked issues.jsonl: the issue appears with its ID,
+status, priority and embargo date only, labeled "embargoed". Once the date
+passes, exports include it in full.
+
+Examples:
+  bd security set bd-42 --cve CVE-2026-1234 --cvss 9.8 --affected "<1.4.3"
+  bd security set bd-42 --embargo-until 2026-11-01
+  bd security set bd-42 --embargo-until ""      # Lift the embargo now
+  bd security clear bd-42
+  bd list --security --min-cvss 7`,
+}
+
+var securitySetCmd = &cobra.Command{
+	Use:   "set <issue-id>",
+	Short: "Set an issue's advisory fields",
+	Long: `Set an issue's advisory fields. Only the fields given change; an empty
+value clears that field.
+
+--embargo-until takes a date (2026-11-01), a timestamp or a duration from
+now (+14d).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("security set")
+		flags := cmd.Flags()
+		if !flags.Changed("cve") && !flags.Changed("cvss") && !flags.Changed("affected") && !flags.Changed("embargo-until") {
+			FatalErrorCode(ErrCodeUsage, "nothing to set: give --cve, --cvss, --affected or --embargo-until")
+		}
+		as, id := advisoryStoreAndID(args[0])
+		ctx := rootCtx
+		current, err := as.GetSecurity(ctx, id)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		sec := &types.Security{}
+		if current != nil {
+			*sec = *current
+		}
+
+		if flags.Changed("cve") {
+			value, _ := flags.GetString("cve")
+			sec.CVE = ""
+			if value != "" {
+				if sec.CVE, err = advisory.ParseCVE(value); err != nil {
+					FatalErrorCode(ErrCodeUsage, "%v", err)
+				}
+			}
+		}
+		if flags.Changed("cvss") {
+			value, _ := flags.GetString("cvss")
+			sec.CVSS = nil
+			if value != "" {
+				score, err := advisory.ParseCVSS(value)
+				if err != nil {
+					FatalErrorCode(ErrCodeUsage, "%v", err)
+				}
+				sec.CVSS = &score
+			}
+		}
+		if flags.Changed("affected") {
+			value, _ := flags.GetString("affected")
+			sec.AffectedVersions = strings.TrimSpace(value)
+		}
+		if flags.Changed("embargo-until") {
+			value, _ := flags.GetString("embargo-until")
+			sec.EmbargoUntil = nil
+			if value != "" {
+				until, err := timeparsing.ParseRelativeTime(value, time.Now())
+				if err != nil {
+					FatalErrorCode(ErrCodeUsage, "invalid --embargo-until %q: %v", value, err)
+				}
+				sec.EmbargoUntil = &until
+			}
+		}
+
+		if err := as.SetSecurity(ctx, id, sec, actor); err != nil {
+			FatalErrorRespectJSON("failed to set security advisory: %v", err)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"id": id, "security": sec})
+			return
+		}
+		if sec.IsEmpty() {
+			fmt.Printf("%s Cleared security advisory of %s\n", ui.RenderPass("✓"), id)
+			return
+		}
+		fmt.Printf("%s Security advisory of %s: %s\n", ui.RenderPass("✓"), id, advisory.Format(sec))
+	},
+}
+
+var securityClearCmd = &cobra.Command{
+	Use:   "clear <issue-id>",
+	Short: "Remove an issue's advisory metadata",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("security clear")
+		as, id := advisoryStoreAndID(args[0])
+		if err := as.SetSecurity(rootCtx, id, nil, actor); err != nil {
+			FatalErrorRespectJSON("failed to clear security advisory: %v", err)
+		}
+		markDirtyAndScheduleFlush()
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"id": id, "cleared": true})
+			return
+		}
+		fmt.Printf("%s Cleared security advisory of %s\n", ui.RenderPass("✓"), id)
+	},
+}
+
+// advisoryStoreAndID resolves an issue ID against the advisory store.
+func advisoryStoreAndID(id string) (advisory.Store, string) {
+	if err := ensureStoreActive(); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	as, err := advisory.For(store)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	fullID, err := utils.ResolvePartialID(rootCtx, store, id)
+	if err != nil {
+		FatalErrorRespectJSON("resolving %s: %v", id, err)
+	}
+	return as, fullID
+}
+
+// printSecurity prints an issue's advisory in bd show. issue.Security must
+// already be populated.
+func printSecurity(issue *types.Issue) {
+	if issue.Security.IsEmpty() {
+		return
+	}
+	line := "Security: " + advisory.Format(issue.Security)
+	if issue.Security.Embargoed(time.Now()) {
+		fmt.Printf("%s %s\n", ui.RenderWarn("🔒"), line)
+		return
+	}
+	fmt.Println(line)
+}
+
+// securityFilterIDs returns the IDs of issues with advisory metadata and a
+// CVSS score of at least minCVSS, for bd list --security. In daemon mode
+// it reads them through a read-only connection.
+func securityFilterIDs(ctx context.Context, minCVSS float64) ([]string, error) {
+	s := store
+	if s == nil {
+		if dbPath == "" {
+			return nil, advisory.ErrUnsupported
+		}
+		roStore, err := sqlite.NewReadOnlyWithTimeout(ctx, dbPath, lockTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = roStore.Close() }()
+		s = roStore
+	}
+	as, err := advisory.For(s)
+	if err != nil {
+		return nil, err
+	}
+	return as.GetSecurityIssueIDs(ctx, minCVSS)
+}
+
+func init() {
+	securitySetCmd.Flags().String("cve", "", "CVE ID (CVE-YYYY-NNNN)")
+	securitySetCmd.Flags().String("cvss", "", "CVSS base score (0.0-10.0)")
+	securitySetCmd.Flags().String("affected", "", `Affected versions, e.g. ">=1.2, <1.4.3"`)
+	securitySetCmd.Flags().String("embargo-until", "", "Withhold details from exports until this date (2026-11-01, +14d)")
+	securitySetCmd.ValidArgsFunction = issueIDCompletion
+	securityClearCmd.ValidArgsFunction = issueIDCompletion
+	securityCmd.AddCommand(securitySetCmd)
+	securityCmd.AddCommand(securityClearCmd)
+	rootCmd.AddCommand(securityCmd)
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+var sedCmd = &cobra.Command{
+	Use:     "sed [flags] s/<regex>/<replacement>/[gi] [issue-id...]",
+	GroupID: "issues",
+	Short:   "Find and replace across issues, in one transaction",
+	Long: `Apply a sed-style substitution to issue text across many issues at once, for
+renames such as a service or component name.
+
+The expression is s/regex/replacement/flags. Any character may replace the
+slashes (s|a/b|c/d|). The regex is a Go regular expression (RE2) matched
+line by line; without the g flag only the first match on each line is
+replaced, and the i flag ignores case. In the replacement, \1..\9 are
+capture groups and & is the whole match (\& for a literal &).
+
+Fields are searched as in bd grep: titles, descriptions, design, notes,
+acceptance criteria and comments by default. All changes are made in one
+transaction, so either every issue is rewritten or none is. Use --dry-run
+to preview the changes as a diff first.`,
+	Example: `  bd sed 's/old-service/new-service/g' --filter 'label:platform' --dry-run
+  bd sed 's/old-service/new-service/g' --filter 'label:platform'
+  bd sed 's|api/v1|api/v2|g' --field description,notes
+  bd sed 's/\bteh\b/the/gi' bd-42 bd-43`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filter, _ := cmd.Flags().GetString("filter")
+		fields, _ := cmd.Flags().GetStringSlice("field")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		expr, err := parseSedExpr(args[0])
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if len(fields) == 0 {
+			fields = []string{"all"}
+		}
+		fieldSet, err := grepFields(fields)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if !dryRun {
+			CheckReadonly("sed")
+		}
+		if err := ensureDirectMode("sed requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+
+		issues, err := sedIssues(args[1:], filter)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		docs, err := grepDocuments(issues, fieldSet)
+		if err != nil {
+			FatalErrorRespectJSON("reading comments: %v", err)
+		}
+		edits := sedEdits(docs, expr)
+
+		if !dryRun && len(edits) > 0 {
+			if err := applySedEdits(rootCtx, store, edits, getActor()); err != nil {
+				FatalErrorRespectJSON("sed failed, nothing was changed: %v", err)
+			}
+			markDirtyAndScheduleFlush()
+		}
+
+		summary := summarizeSedEdits(edits, dryRun)
+		if jsonOutput {
+			outputJSON(summary)
+			return
+		}
+		if len(edits) == 0 {
+			fmt.Println("No matches")
+			return
+		}
+		for _, e := range edits {
+			fmt.Println(ui.RenderAccent(e.doc.File))
+			for _, l := range e.Lines {
+				fmt.Printf("%s %s\n", ui.RenderMuted(fmt.Sprintf("%4d", l.Line)), ui.RenderFail("- "+l.Old))
+				fmt.Printf("%s %s\n", ui.RenderMuted("    "), ui.RenderPass("+ "+l.New))
+			}
+		}
+		fmt.Println()
+		verb := "Replaced"
+		if dryRun {
+			verb = "Would replace"
+		}
+		fmt.Printf("%s %d match(es) in %d field(s) of %d issue(s)\n", verb, summary.Replacements, len(edits), len(summary.Issues))
+		if dryRun {
+			fmt.Printf("  %s\n", ui.RenderMuted("Run without --dry-run to apply"))
+		}
+	},
+}
+
+// sedExpr is a parsed s/regex/replacement/flags expression.
+type sedExpr struct {
+	re     *regexp.Regexp
+	repl   string // In regexp.Expand syntax
+	global bool
+}
+
+// parseSedExpr parses s/regex/replacement/flags. The character after the
+// s is the delimiter; a backslash escapes it.
+func parseSedExpr(expr string) (*sedExpr, error) {
+	if len(expr) < 2 || expr[0] != 's' {
+		return nil, fmt.Errorf("expression must look like s/regex/replacement/, got %q", expr)
+	}
+	delim := []rune(expr[1:])[0]
+	if delim == '\\' || delim == '\n' {
+		return nil, fmt.Errorf("invalid delimiter %q", delim)
+	}
+	var parts []string
+	var cur strings.Builder
+	rest := []rune(expr[1+len(string(delim)):])
+	for i := 0; i < len(rest); i++ {
+		r := rest[i]
+		switch {
+		case r == '\\' && i+1 < len(rest) && rest[i+1] == delim:
+			cur.WriteRune(delim)
+			i++
+		case r == '\\' && i+1 < len(rest):
+			cur.WriteRune(r)
+			cur.WriteRune(rest[i+1])
+			i++
+		case r == delim && len(parts) < 2:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("unterminated expression %q: want s/regex/replacement/", expr)
+	}
+	flags := cur.String()
+
+	pattern := parts[0]
+	global := false
+	for _, f := range flags {
+		switch f {
+		case 'g':
+			global = true
+		case 'i':
+			pattern = "(?i)" + pattern
+		default:
+			return nil, fmt.Errorf("unknown flag %q (want g or i)", f)
+		}
+	}
+	if parts[0] == "" {
+		return nil, errors.New("empty regex")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	return &sedExpr{re: re, repl: sedReplacement(parts[1]), global: global}, nil
+}
+
+// sedReplacement converts a sed replacement (\1, &, \&, \n, \\) to
+// regexp.Expand syntax, escaping literal dollars.
+func sedReplacement(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			next := s[i+1]
+			i++
+			switch {
+			case next >= '0' && next <= '9':
+				b.WriteString("${" + string(next) + "}")
+			case next == 'n':
+				b.WriteByte('\n')
+			case next == 't':
+				b.WriteByte('\t')
+			case next == '$':
+				b.WriteString("$$")
+			default:
+				b.WriteByte(next)
+			}
+		case c == '&':
+			b.WriteString("${0}")
+		case c == '$':
+			b.WriteString("$$")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// replaceLine applies the expression to one line and returns the new
+// line and the number of replacements.
+func (e *sedExpr) replaceLine(line string) (string, int) {
+	matches := e.re.FindAllStringSubmatchIndex(line, -1)
+	if len(matches) == 0 {
+		return line, 0
+	}
+	if !e.global {
+		matches = matches[:1]
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(line[last:m[0]])
+		b.Write(e.re.ExpandString(nil, e.repl, line, m))
+		last = m[1]
+	}
+	b.WriteString(line[last:])
+	return b.String(), len(matches)
+}
+
+// sedLine is one changed line.
+type sedLine struct {
+	Line int    `json:"line"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// sedEdit is the change to one field or comment.
+type sedEdit struct {
+	doc          grepDocument
+	IssueID      string    `json:"issue_id"`
+	Field        string    `json:"field"`
+	File         string    `json:"file"`
+	Lines        []sedLine `json:"lines"`
+	Replacements int       `json:"replacements"`
+	NewText      string    `json:"-"`
+}
+
+// sedEdits applies expr to every document line by line and returns the
+// documents that change.
+func sedEdits(docs []grepDocument, expr *sedExpr) []*sedEdit {
+	var edits []*sedEdit
+	for _, doc := range docs {
+		lines := strings.Split(doc.Text, "\n")
+		edit := &sedEdit{doc: doc, IssueID: doc.IssueID, Field: doc.Field, File: doc.File}
+		for i, line := range lines {
+			replaced, n := expr.replaceLine(line)
+			if n == 0 || replaced == line {
+				continue
+			}
+			edit.Lines = append(edit.Lines, sedLine{Line: i + 1, Old: line, New: replaced})
+			edit.Replacements += n
+			lines[i] = replaced
+		}
+		if len(edit.Lines) > 0 {
+			edit.NewText = strings.Join(lines, "\n")
+			edits = append(edits, edit)
+		}
+	}
+	return edits
+}
+
+// sedIssueFields maps bd grep field names to UpdateIssue keys.
+var sedIssueFields = map[string]string{
+	"title":       "title",
+	"description": "description",
+	"design":      "design",
+	"notes":       "notes",
+	"acceptance":  "acceptance_criteria",
+}
+
+// commentTextUpdater is implemented by transactions that can edit comments
+// (SQLite).
+type commentTextUpdater interface {
+	UpdateCommentText(ctx context.Context, issueID string, commentID int64, text, actor string) error
+}
+
+// applySedEdits writes every edit in one transaction.
+func applySedEdits(ctx context.Context, s storage.Storage, edits []*sedEdit, actor string) error {
+	updates := make(map[string]map[string]interface{})
+	var order []string
+	for _, e := range edits {
+		key, ok := sedIssueFields[e.Field]
+		if !ok {
+			continue
+		}
+		if updates[e.IssueID] == nil {
+			updates[e.IssueID] = make(map[string]interface{})
+			order = append(order, e.IssueID)
+		}
+		updates[e.IssueID][key] = e.NewText
+	}
+	return s.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		for _, id := range order {
+			if err := tx.UpdateIssue(ctx, id, updates[id], actor); err != nil {
+				return fmt.Errorf("%s: %w", id, err)
+			}
+		}
+		for _, e := range edits {
+			if e.Field != "comments" {
+				continue
+			}
+			cu, ok := tx.(commentTextUpdater)
+			if !ok {
+				return errors.New("editing comments requires the SQLite backend; use --field to leave comments out")
+			}
+			if err := cu.UpdateCommentText(ctx, e.IssueID, e.doc.CommentID, e.NewText, actor); err != nil {
+				return fmt.Errorf("%s: %w", e.File, err)
+			}
+		}
+		return nil
+	})
+}
+
+// sedSummary is the --json output of bd sed.
+type sedSummary struct {
+	DryRun       bool       `json:"dry_run"`
+	Issues       []string   `json:"issues"`
+	Replacements int        `json:"replacements"`
+	Changes      []*sedEdit `json:"changes"`
+}
+
+func summarizeSedEdits(edits []*sedEdit, dryRun bool) *sedSummary {
+	summary := &sedSummary{DryRun: dryRun, Issues: []string{}, Changes: edits}
+	if summary.Changes == nil {
+		summary.Changes = []*sedEdit{}
+	}
+	seen := make(map[string]bool)
+	for _, e := range edits {
+		summary.Replacements += e.Replacements
+		if !seen[e.IssueID] {
+			seen[e.IssueID] = true
+			summary.Issues = append(summary.Issues, e.IssueID)
+		}
+	}
+	return summary
+}
+
+// sedIssues loads the issues to rewrite: those matching --filter (every
+// issue without one), narrowed to the given IDs if any.
+func sedIssues(ids []string, filter string) ([]*types.Issue, error) {
+	if filter == "" {
+		return grepIssues(ids, "")
+	}
+	issues, err := queryMatchingIssues(rootCtx, parseQueryFlag(filter))
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return issues, nil
+	}
+	want := make(map[string]bool)
+	for _, id := range ids {
+		resolved, err := utils.ResolvePartialID(rootCtx, store, id)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", id, err)
+		}
+		want[resolved] = true
+	}
+	var kept []*types.Issue
+	for _, issue := range issues {
+		if want[issue.ID] {
+			kept = append(kept, issue)
+		}
+	}
+	return kept, nil
+}
+
+func init() {
+	sedCmd.Flags().String("filter", "", "Only rewrite issues matching this query (e.g. 'label:platform and status!=closed')")
+	sedCmd.Flags().StringSlice("field", nil, "Fields to rewrite: title, description, design, notes, acceptance, comments, all (default: all)")
+	sedCmd.Flags().Bool("dry-run", false, "Show the changes as a diff without applying them")
+	rootCmd.AddCommand(sedCmd)
+}
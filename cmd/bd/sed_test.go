@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestParseSedExpr(t *testing.T) {
+	for _, tc := range []struct {
+		expr, line, want string
+		n                int
+	}{
+		{"s/old/new/", "old old", "new old", 1},
+		{"s/old/new/g", "old old", "new new", 2},
+		{"s/OLD/new/gi", "Old old", "new new", 2},
+		{"s|api/v1|api/v2|", "GET api/v1/x", "GET api/v2/x", 1},
+		{`s/a\/b/c/`, "a/b", "c", 1},
+		{`s/(\w+)-svc/\1-service/`, "auth-svc", "auth-service", 1},
+		{"s/svc/[&]/g", "svc", "[svc]", 1},
+		{`s/svc/\&$1/`, "svc", "&$1", 1},
+		{"s/zzz/y/", "abc", "abc", 0},
+	} {
+		expr, err := parseSedExpr(tc.expr)
+		if err != nil {
+			t.Errorf("parseSedExpr(%q): %v", tc.expr, err)
+			continue
+		}
+		got, n := expr.replaceLine(tc.line)
+		if got != tc.want || n != tc.n {
+			t.Errorf("%s on %q = %q (%d), want %q (%d)", tc.expr, tc.line, got, n, tc.want, tc.n)
+		}
+	}
+
+	for _, bad := range []string{"", "s", "q/a/b/", "s/a/b", "s/a/b/x", "s//b/", "s/(/b/"} {
+		if _, err := parseSedExpr(bad); err == nil {
+			t.Errorf("parseSedExpr(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSedEdits(t *testing.T) {
+	docs := []grepDocument{
+		{IssueID: "bd-1", Field: "description", File: "bd-1", Text: "uses old-service\nkeep\nold-service again"},
+		{IssueID: "bd-1", Field: "comments", File: "bd-1/comment-3", Text: "nothing here", CommentID: 3},
+		{IssueID: "bd-2", Field: "comments", File: "bd-2/comment-4", Text: "old-service old-service", CommentID: 4},
+	}
+	expr, err := parseSedExpr("s/old-service/new-service/g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	edits := sedEdits(docs, expr)
+	if len(edits) != 2 {
+		t.Fatalf("edits = %+v", edits)
+	}
+	if edits[0].NewText != "uses new-service\nkeep\nnew-service again" || len(edits[0].Lines) != 2 || edits[0].Lines[1].Line != 3 {
+		t.Errorf("description edit = %+v", edits[0])
+	}
+	if edits[1].doc.CommentID != 4 || edits[1].Replacements != 2 {
+		t.Errorf("comment edit = %+v", edits[1])
+	}
+
+	summary := summarizeSedEdits(edits, true)
+	if !summary.DryRun || summary.Replacements != 4 || len(summary.Issues) != 2 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if empty := summarizeSedEdits(nil, false); empty.Changes == nil || empty.Issues == nil {
+		t.Error("empty summary should have empty slices for JSON")
+	}
+}
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var selftestCmd = &cobra.Command{
+	Use:     "selftest",
+	GroupID: "maint",
+	Short:   "Stress-test bd in a scratch database",
+	Long: `Run built-in stress tests against a temporary database.
+
+Self-tests never touch the current project; each one creates its own
+scratch .beads directory and removes it afterwards unless --keep is given.`,
+}
+
+var selftestConcurrencyCmd = &cobra.Command{
+	Use:   "concurrency",
+	Short: "Check that concurrent direct-mode bd processes don't lose writes",
+	Long: `Start several bd processes in direct mode (no daemon) that create, label,
+link, update and close issues in the same database at the same time while
+flushing to JSONL. When they finish, verify that:
+
+  - SQLite reports no corruption (PRAGMA integrity_check)
+  - every write from every process is present in the database
+  - issues.jsonl contains exactly the issues in the database
+
+Use it to check a filesystem (network drives, WSL mounts, sync folders)
+before pointing several agents at one database.
+
+Examples:
+  bd selftest concurrency
+  bd selftest concurrency --workers 16 --ops 50
+  bd selftest concurrency --keep --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		workers, _ := cmd.Flags().GetInt("workers")
+		ops, _ := cmd.Flags().GetInt("ops")
+		keep, _ := cmd.Flags().GetBool("keep")
+		if workers < 1 || ops < 1 {
+			FatalError("--workers and --ops must be at least 1")
+		}
+
+		result, err := runConcurrencySelftest(rootCtx, workers, ops, keep)
+		if err != nil {
+			FatalError("selftest setup failed: %v", err)
+		}
+		if jsonOutput {
+			outputJSON(result)
+		} else {
+			printConcurrencySelftest(result)
+		}
+		if !result.Passed {
+			os.Exit(1)
+		}
+	},
+}
+
+// selftestWorkerCmd is the child process started by 'bd selftest concurrency'.
+// It goes through the normal startup path so it exercises the same locking,
+// auto-import and auto-flush code as a real command.
+var selftestWorkerCmd = &cobra.Command{
+	Use:    "concurrency-worker",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		worker, _ := cmd.Flags().GetInt("worker")
+		ops, _ := cmd.Flags().GetInt("ops")
+		if daemonClient != nil {
+			FatalError("concurrency-worker must run with --no-daemon")
+		}
+
+		ids, err := runSelftestWorker(rootCtx, worker, ops)
+		if err != nil {
+			FatalError("worker %d: %v", worker, err)
+		}
+		outputJSON(ids)
+	},
+}
+
+// ConcurrencySelftestResult is the outcome of 'bd selftest concurrency'.
+type ConcurrencySelftestResult struct {
+	Passed       bool     `json:"passed"`
+	Workers      int      `json:"workers"`
+	OpsPerWorker int      `json:"ops_per_worker"`
+	Issues       int      `json:"issues"`
+	Writes       int      `json:"writes"`
+	DurationMs   int64    `json:"duration_ms"`
+	Failures     []string `json:"failures,omitempty"`
+	Dir          string   `json:"dir,omitempty"`
+}
+
+// selftestWritesPerOp is the number of write transactions a worker makes per
+// op: create, label, update, plus a dependency and close on most ops.
+const selftestWritesPerOp = 5
+
+func selftestTitle(worker, op int) string {
+	return fmt.Sprintf("selftest w%d #%d", worker, op)
+}
+
+func selftestNotes(worker, op int) string {
+	return fmt.Sprintf("written by worker %d op %d", worker, op)
+}
+
+func runConcurrencySelftest(ctx context.Context, workers, ops int, keep bool) (*ConcurrencySelftestResult, error) {
+	dir, err := os.MkdirTemp("", "bd-selftest-*")
+	if err != nil {
+		return nil, err
+	}
+	if !keep {
+		defer func() { _ = os.RemoveAll(dir) }()
+	}
+	beadsDir := filepath.Join(dir, ".beads")
+	scratchDB := filepath.Join(beadsDir, "beads.db")
+
+	s, err := sqlite.New(ctx, scratchDB)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SetConfig(ctx, "issue_prefix", "st"); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	if err := s.Close(); err != nil {
+		return nil, err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve current executable: %w", err)
+	}
+
+	result := &ConcurrencySelftestResult{Workers: workers, OpsPerWorker: ops}
+	if keep {
+		result.Dir = dir
+	}
+	workerIDs := make([][]string, workers)
+	var mu sync.Mutex
+	fail := func(format string, args ...interface{}) {
+		mu.Lock()
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+		mu.Unlock()
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			args := []string{"--no-daemon", "--db", scratchDB, "--actor", fmt.Sprintf("selftest-%d", w),
+				"selftest", "concurrency-worker", "--worker", fmt.Sprint(w), "--ops", fmt.Sprint(ops)}
+			cmd := exec.CommandContext(ctx, exe, args...) // #nosec G204 - re-runs this bd binary
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "BEADS_AUTO_START_DAEMON=false", "BD_ACTOR=")
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout, cmd.Stderr = &stdout, &stderr
+			if err := cmd.Run(); err != nil {
+				fail("worker %d exited with %v: %s", w, err, strings.TrimSpace(stderr.String()))
+				return
+			}
+			var ids []string
+			if err := json.Unmarshal(stdout.Bytes(), &ids); err != nil {
+				fail("worker %d: unreadable output: %v", w, err)
+				return
+			}
+			workerIDs[w] = ids
+		}(w)
+	}
+	wg.Wait()
+	result.DurationMs = time.Since(start).Milliseconds()
+	result.Writes = workers * ops * selftestWritesPerOp
+
+	if err := verifyConcurrencySelftest(ctx, scratchDB, workerIDs, result, fail); err != nil {
+		return nil, err
+	}
+	result.Passed = len(result.Failures) == 0
+	return result, nil
+}
+
+// verifyConcurrencySelftest checks the database and JSONL left behind by
+// the workers and records every discrepancy as a failure.
+func verifyConcurrencySelftest(ctx context.Context, scratchDB string, workerIDs [][]string, result *ConcurrencySelftestResult, fail func(string, ...interface{})) error {
+	s, err := sqlite.New(ctx, scratchDB)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.Close() }()
+
+	var integrity string
+	if err := s.UnderlyingDB().QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&integrity); err != nil {
+		return err
+	}
+	if integrity != "ok" {
+		fail("integrity check: %s", integrity)
+	}
+
+	for w, ids := range workerIDs {
+		if ids == nil {
+			continue // worker already reported as failed
+		}
+		if len(ids) != result.OpsPerWorker {
+			fail("worker %d reported %d issues, want %d", w, len(ids), result.OpsPerWorker)
+		}
+		for op, id := range ids {
+			issue, err := s.GetIssue(ctx, id)
+			if err != nil || issue == nil {
+				fail("%s (worker %d op %d) missing from database", id, w, op)
+				continue
+			}
+			if issue.Title != selftestTitle(w, op) || issue.Notes != selftestNotes(w, op) {
+				fail("%s: lost update (title %q, notes %q)", id, issue.Title, issue.Notes)
+			}
+			wantStatus := types.StatusOpen
+			if op%2 == 1 {
+				wantStatus = types.StatusClosed
+			}
+			if issue.Status != wantStatus {
+				fail("%s: status %s, want %s", id, issue.Status, wantStatus)
+			}
+			if want := fmt.Sprintf("worker-%d", w); !slices.Contains(issue.Labels, want) {
+				fail("%s: label %s missing", id, want)
+			}
+			if op > 0 {
+				deps, err := s.GetDependencyRecords(ctx, id)
+				if err != nil || len(deps) != 1 || deps[0].DependsOnID != ids[op-1] {
+					fail("%s: dependency on %s missing", id, ids[op-1])
+				}
+			}
+		}
+	}
+
+	stats, err := s.GetStatistics(ctx)
+	if err != nil {
+		return err
+	}
+	result.Issues = stats.TotalIssues
+
+	// After every process has exited and flushed, the JSONL must match
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return err
+	}
+	dbIDs := make([]string, len(issues))
+	for i, issue := range issues {
+		dbIDs[i] = issue.ID
+	}
+	jsonlIDs, err := readSelftestJSONLIDs(filepath.Join(filepath.Dir(scratchDB), "issues.jsonl"))
+	if err != nil {
+		fail("issues.jsonl: %v", err)
+		return nil
+	}
+	if missing := diffStrings(dbIDs, jsonlIDs); len(missing) > 0 {
+		fail("issues.jsonl is missing %d issue(s): %s", len(missing), strings.Join(missing, ", "))
+	}
+	if extra := diffStrings(jsonlIDs, dbIDs); len(extra) > 0 {
+		fail("issues.jsonl has %d issue(s) not in the database: %s", len(extra), strings.Join(extra, ", "))
+	}
+	return nil
+}
+
+func readSelftestJSONLIDs(path string) ([]string, error) {
+	// #nosec G304 - path is inside the selftest scratch directory
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var issue struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(line, &issue); err != nil {
+			return nil, fmt.Errorf("corrupt line: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	return ids, scanner.Err()
+}
+
+// diffStrings returns the sorted elements of a that are not in b.
+func diffStrings(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if !inB[s] {
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// runSelftestWorker performs the worker's share of writes through the global
+// store, one transaction per write, and returns the IDs it created.
+func runSelftestWorker(ctx context.Context, worker, ops int) ([]string, error) {
+	if err := ensureStoreActive(); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, ops)
+	label := fmt.Sprintf("worker-%d", worker)
+	for op := 0; op < ops; op++ {
+		issue := &types.Issue{
+			Title:     selftestTitle(worker, op),
+			Status:    types.StatusOpen,
+			Priority:  op % 5,
+			IssueType: types.TypeTask,
+		}
+		if err := store.CreateIssue(ctx, issue, actor); err != nil {
+			return nil, fmt.Errorf("create: %w", err)
+		}
+		if err := store.AddLabel(ctx, issue.ID, label, actor); err != nil {
+			return nil, fmt.Errorf("label %s: %w", issue.ID, err)
+		}
+		if op > 0 {
+			dep := &types.Dependency{IssueID: issue.ID, DependsOnID: ids[op-1], Type: types.DepBlocks}
+			if err := store.AddDependency(ctx, dep, actor); err != nil {
+				return nil, fmt.Errorf("dep %s: %w", issue.ID, err)
+			}
+		}
+		if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"notes": selftestNotes(worker, op)}, actor); err != nil {
+			return nil, fmt.Errorf("update %s: %w", issue.ID, err)
+		}
+		if op%2 == 1 {
+			if err := store.CloseIssue(ctx, issue.ID, "selftest", actor, ""); err != nil {
+				return nil, fmt.Errorf("close %s: %w", issue.ID, err)
+			}
+		}
+		// Interleave reads with other processes' writes
+		if _, err := store.GetReadyWork(ctx, types.WorkFilter{}); err != nil {
+			return nil, fmt.Errorf("ready: %w", err)
+		}
+		ids = append(ids, issue.ID)
+
+		// Flush now and then so JSONL exports race with each other
+		markDirtyAndScheduleFlush()
+		if op%5 == 4 && flushManager != nil {
+			if err := flushManager.FlushNow(); err != nil {
+				return nil, fmt.Errorf("flush: %w", err)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func printConcurrencySelftest(result *ConcurrencySelftestResult) {
+	secs := float64(result.DurationMs) / 1000
+	rate := 0.0
+	if secs > 0 {
+		rate = float64(result.Writes) / secs
+	}
+	fmt.Printf("Concurrency selftest: %d processes × %d ops, ~%d writes in %.1fs (%.0f writes/s)\n",
+		result.Workers, result.OpsPerWorker, result.Writes, secs, rate)
+	fmt.Printf("Issues in database: %d\n", result.Issues)
+	if result.Dir != "" {
+		fmt.Printf("Scratch directory: %s\n", result.Dir)
+	}
+	if result.Passed {
+		fmt.Printf("\n%s No corruption or lost writes\n", ui.RenderPass("✓"))
+		return
+	}
+	fmt.Printf("\n%s %d problem(s) found:\n", ui.RenderFail("✗"), len(result.Failures))
+	for _, f := range result.Failures {
+		fmt.Printf("  - %s\n", f)
+	}
+}
+
+func init() {
+	selftestConcurrencyCmd.Flags().Int("workers", 8, "Number of concurrent bd processes")
+	selftestConcurrencyCmd.Flags().Int("ops", 25, "Issues each process creates (each is several writes)")
+	selftestConcurrencyCmd.Flags().Bool("keep", false, "Keep the scratch directory for inspection")
+	selftestWorkerCmd.Flags().Int("worker", 0, "Worker number")
+	selftestWorkerCmd.Flags().Int("ops", 25, "Issues to create")
+	selftestCmd.AddCommand(selftestConcurrencyCmd)
+	selftestCmd.AddCommand(selftestWorkerCmd)
+	rootCmd.AddCommand(selftestCmd)
+}
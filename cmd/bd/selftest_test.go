@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestLockJSONLWaitsForOtherHolder(t *testing.T) {
+	jsonlPath := filepath.Join(t.TempDir(), "issues.jsonl")
+	other := flock.New(filepath.Join(filepath.Dir(jsonlPath), jsonlLockFile))
+	if err := other.Lock(); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	old := jsonlLockTimeout
+	jsonlLockTimeout = 50 * time.Millisecond
+	defer func() { jsonlLockTimeout = old }()
+
+	if _, err := lockJSONL(context.Background(), jsonlPath); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout while another process holds the lock, got %v", err)
+	}
+
+	_ = other.Unlock()
+	unlock, err := lockJSONL(context.Background(), jsonlPath)
+	if err != nil {
+		t.Fatalf("lockJSONL after release failed: %v", err)
+	}
+	unlock()
+}
+
+func TestVerifyConcurrencySelftestReportsLostJSONLWrites(t *testing.T) {
+	ctx := context.Background()
+	dbFile := filepath.Join(t.TempDir(), ".beads", "beads.db")
+	s := newTestStore(t, dbFile)
+
+	// What one worker with two ops leaves behind
+	var ids []string
+	for op := 0; op < 2; op++ {
+		issue := &types.Issue{Title: selftestTitle(0, op), Notes: selftestNotes(0, op), Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if err := s.AddLabel(ctx, issue.ID, "worker-0", "test"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: ids[1], DependsOnID: ids[0], Type: types.DepBlocks}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := s.CloseIssue(ctx, ids[1], "done", "test", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	// The JSONL only has the first issue, as if a concurrent flush dropped it
+	jsonl := `{"id":"` + ids[0] + `"}` + "\n"
+	if err := os.WriteFile(filepath.Join(filepath.Dir(dbFile), "issues.jsonl"), []byte(jsonl), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &ConcurrencySelftestResult{Workers: 1, OpsPerWorker: 2}
+	var failures []string
+	fail := func(format string, args ...interface{}) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}
+	if err := verifyConcurrencySelftest(ctx, dbFile, [][]string{ids}, result, fail); err != nil {
+		t.Fatalf("verifyConcurrencySelftest failed: %v", err)
+	}
+	if len(failures) != 1 || !strings.Contains(failures[0], "issues.jsonl is missing") {
+		t.Errorf("failures = %v, want only the missing JSONL issue", failures)
+	}
+	if result.Issues != 2 {
+		t.Errorf("Issues = %d, want 2", result.Issues)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steveyegge/beads/internal/api"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var serverCmd = &cobra.Command{
+	Use:     "server",
+	GroupID: "advanced",
+	Short:   "Inspect the daemon's HTTP API server",
+	Long: `Inspect the HTTP API the daemon serves on api.listen.
+
+The daemon logs every API request (method, path, actor, status and
+latency) in the local database for api.audit-retention (default 30 days).
+Requests over a client's rate limit (api.rate-limits) are refused with 429
+and show up as throttled.`,
+}
+
+var serverAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Query the API access log",
+	Example: `  bd server audit                          # Last day's requests, newest first
+  bd server audit --summary --since 7d     # Requests, errors and latency per actor
+  bd server audit --actor ci-agent --errors
+  bd server audit --token tok-1a2b3c4d --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		since, err := parseDurationString(sinceFlag)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "invalid --since: %v", err)
+		}
+		filter := types.APIAccessFilter{Since: time.Now().Add(-since).UTC()}
+		filter.Actor, _ = cmd.Flags().GetString("actor")
+		filter.TokenID, _ = cmd.Flags().GetString("token")
+		if errorsOnly, _ := cmd.Flags().GetBool("errors"); errorsOnly {
+			filter.MinStatus = 400
+		}
+		summary, _ := cmd.Flags().GetBool("summary")
+		if !summary {
+			filter.Limit, _ = cmd.Flags().GetInt("limit")
+		}
+
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		audit, err := api.AuditFor(store)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		entries, err := audit.GetAPIAccess(rootCtx, filter)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if summary {
+			printAccessSummary(api.SummarizeAccess(entries), sinceFlag)
+			return
+		}
+		if jsonOutput {
+			if entries == nil {
+				entries = []*types.APIAccess{}
+			}
+			outputJSON(entries)
+			return
+		}
+		if len(entries) == 0 {
+			fmt.Printf("No API requests in the last %s\n", sinceFlag)
+			return
+		}
+		for _, e := range entries {
+			fmt.Printf("%s  %-6s %-20s %-20s %s %6dms\n", e.Time.Local().Format("2006-01-02 15:04:05"),
+				e.Method, e.Path, e.Actor, renderAccessStatus(e.Status), e.LatencyMS)
+		}
+	},
+}
+
+func printAccessSummary(summaries []*api.AccessSummary, since string) {
+	if jsonOutput {
+		outputJSON(summaries)
+		return
+	}
+	if len(summaries) == 0 {
+		fmt.Printf("No API requests in the last %s\n", since)
+		return
+	}
+	fmt.Printf("%-24s %9s %7s %9s %8s %8s  %s\n", "ACTOR", "REQUESTS", "ERRORS", "THROTTLED", "AVG", "MAX", "LAST")
+	for _, s := range summaries {
+		fmt.Printf("%-24s %9d %7d %9d %6dms %6dms  %s\n", s.Actor, s.Requests, s.Errors, s.Throttled,
+			s.AvgLatencyMS, s.MaxLatencyMS, formatTimeAgo(s.Last))
+	}
+}
+
+func renderAccessStatus(status int) string {
+	s := fmt.Sprint(status)
+	switch {
+	case status >= 500:
+		return ui.RenderFail(s)
+	case status >= 400:
+		return ui.RenderWarn(s)
+	default:
+		return s
+	}
+}
+
+func init() {
+	serverAuditCmd.Flags().String("since", "24h", "How far back to look (e.g. 1h, 7d)")
+	serverAuditCmd.Flags().String("actor", "", "Only requests by this token name or user")
+	serverAuditCmd.Flags().String("token", "", "Only requests with this token ID")
+	serverAuditCmd.Flags().Bool("errors", false, "Only failed requests (status 400 and up, including throttled)")
+	serverAuditCmd.Flags().Bool("summary", false, "Totals per actor instead of individual requests")
+	serverAuditCmd.Flags().Int("limit", 50, "Most requests to show")
+	serverCmd.AddCommand(serverAuditCmd)
+	rootCmd.AddCommand(serverCmd)
+}
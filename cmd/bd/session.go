@@ -0,0 +1,520 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/beads/internal/audit"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// Interaction log entry kinds for agent sessions
+const (
+	sessionStartKind   = "session_start"
+	sessionEndKind     = "session_end"
+	sessionCommandKind = "session_command"
+)
+
+// activeSessionFile holds the workspace's active session under .beads/.
+// It is per-machine and ignored by git.
+const activeSessionFile = "active-session.json"
+
+// agentSession is an agent session as recorded in the interactions log.
+type agentSession struct {
+	ID        string     `json:"id"`
+	Agent     string     `json:"agent"`
+	Note      string     `json:"note,omitempty"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Commands  int        `json:"commands"`
+}
+
+// sessionCommand is a write command run during a session.
+type sessionCommand struct {
+	At      time.Time `json:"at"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args,omitempty"`
+}
+
+// sessionIssue is an issue the session's agent changed, with its events.
+type sessionIssue struct {
+	ID     string         `json:"id"`
+	Title  string         `json:"title"`
+	Status types.Status   `json:"status"`
+	Events []*types.Event `json:"events"`
+}
+
+// sessionReport is the output of bd session show.
+type sessionReport struct {
+	Session  *agentSession    `json:"session"`
+	Commands []sessionCommand `json:"commands"`
+	Issues   []*sessionIssue  `json:"issues"`
+	Counts   map[string]int   `json:"counts"` // Events by type
+}
+
+// sessionWriteOp is set by CheckReadonly, which every write command calls,
+// so the command can be logged to the active session when it succeeds.
+var sessionWriteOp string
+
+var sessionCmd = &cobra.Command{
+	Use:     "session",
+	GroupID: "advanced",
+	Short:   "Record agent sessions for post-hoc review",
+	Long: `Record what an agent does while working autonomously, so a human can
+review it afterwards.
+
+bd session start opens a session for an agent. Until bd session end, every
+bd command in the workspace runs as that agent (unless --actor or BD_ACTOR
+says otherwise) and each successful write command is logged to the
+session. bd session show summarizes the session: the commands it ran and
+every issue the agent created, changed, commented on or closed.
+
+Sessions are kept in .beads/interactions.jsonl with the other audit
+entries. One session is active per workspace; agents sharing a workspace
+can each export the BD_SESSION printed by bd session start instead.`,
+}
+
+var sessionStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start an agent session",
+	Example: `  bd session start --agent claude-1
+  bd session start --agent claude-1 --note "Triage the flaky-test backlog"
+  export BD_SESSION=$(bd session start --agent claude-2 --json | jq -r .id)`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		agent, _ := cmd.Flags().GetString("agent")
+		note, _ := cmd.Flags().GetString("note")
+		if agent == "" {
+			agent = getActorWithGit()
+		}
+		if cur := currentSession(); cur != nil {
+			FatalErrorWithHint(fmt.Sprintf("session %s (%s) is already active", cur.ID, cur.Agent),
+				"end it with 'bd session end', or export BD_SESSION for a parallel session")
+		}
+
+		s := &agentSession{ID: newSessionID(), Agent: agent, Note: note, StartedAt: time.Now().UTC()}
+		if _, err := audit.Append(&audit.Entry{Kind: sessionStartKind, Actor: agent, Session: s.ID, Reason: note, CreatedAt: s.StartedAt}); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if os.Getenv("BD_SESSION") == "" {
+			if err := writeActiveSession(s); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(s)
+			return
+		}
+		fmt.Printf("%s Started session %s for %s\n", ui.RenderPass("✓"), ui.RenderAccent(s.ID), agent)
+		fmt.Printf("  Commands now run as %s; end with 'bd session end'.\n", agent)
+		fmt.Printf("  Parallel agents in this workspace: export BD_SESSION=%s\n", s.ID)
+	},
+}
+
+var sessionEndCmd = &cobra.Command{
+	Use:   "end",
+	Short: "End the active agent session",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s := currentSession()
+		if s == nil {
+			FatalErrorRespectJSON("no active session")
+		}
+		now := time.Now().UTC()
+		if _, err := audit.Append(&audit.Entry{Kind: sessionEndKind, Actor: s.Agent, Session: s.ID, CreatedAt: now}); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if path := activeSessionPath(); path != "" {
+			if stored := readActiveSession(); stored != nil && stored.ID == s.ID {
+				_ = os.Remove(path)
+			}
+		}
+		s.EndedAt = &now
+
+		if jsonOutput {
+			outputJSON(s)
+			return
+		}
+		fmt.Printf("%s Ended session %s (%s, %s)\n", ui.RenderPass("✓"), ui.RenderAccent(s.ID), s.Agent,
+			formatSessionDuration(now.Sub(s.StartedAt)))
+		fmt.Printf("  Review it with 'bd session show %s'\n", s.ID)
+	},
+}
+
+var sessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List agent sessions",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		agent, _ := cmd.Flags().GetString("agent")
+		sessions, _, err := loadSessions()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		var out []*agentSession
+		for _, s := range sessions {
+			if agent == "" || s.Agent == agent {
+				out = append(out, s)
+			}
+		}
+		if jsonOutput {
+			if out == nil {
+				out = []*agentSession{}
+			}
+			outputJSON(out)
+			return
+		}
+		if len(out) == 0 {
+			fmt.Println("No sessions recorded.")
+			return
+		}
+		for _, s := range out {
+			state := ui.RenderWarn("active")
+			if s.EndedAt != nil {
+				state = formatSessionDuration(s.EndedAt.Sub(s.StartedAt))
+			}
+			line := fmt.Sprintf("%s  %-16s %s  %s  %d command(s)", ui.RenderAccent(s.ID), s.Agent,
+				s.StartedAt.Local().Format("2006-01-02 15:04"), state, s.Commands)
+			if s.Note != "" {
+				line += "  " + ui.RenderMuted(s.Note)
+			}
+			fmt.Println(line)
+		}
+	},
+}
+
+var sessionShowCmd = &cobra.Command{
+	Use:   "show [session-id]",
+	Short: "Summarize what an agent did in a session",
+	Long: `Summarize a session (by default the active one, or else the latest): the
+write commands it ran and the issues its agent created, changed, commented
+on or closed while it was open, with their events.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("session show requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		sessions, entries, err := loadSessions()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		var s *agentSession
+		switch {
+		case len(args) == 1:
+			for _, candidate := range sessions {
+				if candidate.ID == args[0] || strings.TrimPrefix(candidate.ID, "ses-") == args[0] {
+					s = candidate
+				}
+			}
+			if s == nil {
+				FatalErrorRespectJSON("session %s not found", args[0])
+			}
+		case currentSession() != nil:
+			id := currentSession().ID
+			for _, candidate := range sessions {
+				if candidate.ID == id {
+					s = candidate
+				}
+			}
+		case len(sessions) > 0:
+			s = sessions[len(sessions)-1]
+		}
+		if s == nil {
+			FatalErrorRespectJSON("no sessions recorded")
+		}
+
+		report, err := buildSessionReport(s, entries, time.Now().UTC())
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		printSessionReport(report)
+	},
+}
+
+// buildSessionReport collects a session's commands from the log entries
+// and its agent's issue events from the database.
+func buildSessionReport(s *agentSession, entries []*audit.Entry, now time.Time) (*sessionReport, error) {
+	ctx := rootCtx
+	report := &sessionReport{Session: s, Commands: []sessionCommand{}, Issues: []*sessionIssue{}, Counts: map[string]int{}}
+	for _, e := range entries {
+		if e.Kind != sessionCommandKind || e.Session != s.ID {
+			continue
+		}
+		c := sessionCommand{At: e.CreatedAt, Command: e.ToolName}
+		if args, ok := e.Extra["args"].([]interface{}); ok {
+			for _, a := range args {
+				c.Args = append(c.Args, fmt.Sprint(a))
+			}
+		}
+		report.Commands = append(report.Commands, c)
+	}
+
+	// Events are stored with second precision
+	from := s.StartedAt.Truncate(time.Second)
+	to := now
+	if s.EndedAt != nil {
+		to = *s.EndedAt
+	}
+	touched, err := store.SearchIssues(ctx, "", types.IssueFilter{UpdatedAfter: &from})
+	if err != nil {
+		return nil, fmt.Errorf("loading issues: %w", err)
+	}
+	for _, issue := range touched {
+		events, err := store.GetEvents(ctx, issue.ID, 0)
+		if err != nil {
+			return nil, fmt.Errorf("loading events of %s: %w", issue.ID, err)
+		}
+		var mine []*types.Event
+		for _, ev := range events {
+			if ev.Actor == s.Agent && !ev.CreatedAt.Before(from) && !ev.CreatedAt.After(to) {
+				mine = append(mine, ev)
+				report.Counts[string(ev.EventType)]++
+			}
+		}
+		if len(mine) == 0 {
+			continue
+		}
+		sort.SliceStable(mine, func(i, j int) bool { return mine[i].CreatedAt.Before(mine[j].CreatedAt) })
+		report.Issues = append(report.Issues, &sessionIssue{ID: issue.ID, Title: issue.Title, Status: issue.Status, Events: mine})
+	}
+	sort.Slice(report.Issues, func(i, j int) bool {
+		return report.Issues[i].Events[0].CreatedAt.Before(report.Issues[j].Events[0].CreatedAt)
+	})
+	return report, nil
+}
+
+func printSessionReport(r *sessionReport) {
+	s := r.Session
+	state := "active"
+	if s.EndedAt != nil {
+		state = "ended " + s.EndedAt.Local().Format("15:04") + ", " + formatSessionDuration(s.EndedAt.Sub(s.StartedAt))
+	}
+	fmt.Printf("Session %s  %s  started %s (%s)\n", ui.RenderAccent(s.ID), s.Agent, s.StartedAt.Local().Format("2006-01-02 15:04"), state)
+	if s.Note != "" {
+		fmt.Printf("  %s\n", s.Note)
+	}
+
+	var counts []string
+	for _, t := range []types.EventType{types.EventCreated, types.EventUpdated, types.EventStatusChanged, types.EventCommented, types.EventClosed, types.EventReopened} {
+		if n := r.Counts[string(t)]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, strings.ReplaceAll(string(t), "_", " ")))
+		}
+	}
+	summary := fmt.Sprintf("%d issue(s) touched", len(r.Issues))
+	if len(counts) > 0 {
+		summary += ": " + strings.Join(counts, ", ")
+	}
+	fmt.Printf("  %s\n", summary)
+
+	if len(r.Commands) > 0 {
+		fmt.Printf("\nCommands (%d):\n", len(r.Commands))
+		for _, c := range r.Commands {
+			fmt.Printf("  %s  bd %s %s\n", ui.RenderMuted(c.At.Local().Format("15:04:05")), c.Command, strings.Join(c.Args, " "))
+		}
+	}
+	if len(r.Issues) > 0 {
+		fmt.Printf("\nIssues:\n")
+		for _, issue := range r.Issues {
+			fmt.Printf("  %s %s %s\n", ui.RenderAccent(issue.ID), truncateTitle(issue.Title, 60), ui.RenderMuted("["+string(issue.Status)+"]"))
+			for _, ev := range issue.Events {
+				fmt.Printf("    %s  %s\n", ui.RenderMuted(ev.CreatedAt.Local().Format("15:04:05")), describeSessionEvent(ev))
+			}
+		}
+	}
+}
+
+// describeSessionEvent renders an event as "status changed: open →
+// in_progress" or "updated: priority, notes".
+func describeSessionEvent(ev *types.Event) string {
+	desc := strings.ReplaceAll(string(ev.EventType), "_", " ")
+	if ev.Comment != nil && *ev.Comment != "" {
+		return desc + ": " + truncateTitle(strings.ReplaceAll(*ev.Comment, "\n", " "), 70)
+	}
+	if ev.NewValue == nil || ev.EventType == types.EventCreated {
+		return desc
+	}
+	var updates map[string]interface{}
+	if json.Unmarshal([]byte(*ev.NewValue), &updates) != nil {
+		return desc
+	}
+	var fields []string
+	for field := range updates {
+		if field != "status" && field != "updated_at" {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	if status, ok := updates["status"].(string); ok && ev.OldValue != nil {
+		var old struct {
+			Status string `json:"status"`
+		}
+		if json.Unmarshal([]byte(*ev.OldValue), &old) == nil && old.Status != "" && old.Status != status {
+			fields = append([]string{"status " + old.Status + " → " + status}, fields...)
+		}
+	}
+	if len(fields) > 0 {
+		desc += ": " + strings.Join(fields, ", ")
+	}
+	return desc
+}
+
+func formatSessionDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(time.Minute).String()
+}
+
+// loadSessions reads every session from the interactions log, oldest
+// first, along with the log's entries.
+func loadSessions() ([]*agentSession, []*audit.Entry, error) {
+	path, err := audit.Path()
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := audit.Read(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]*agentSession)
+	var sessions []*agentSession
+	for _, e := range entries {
+		if e.Session == "" {
+			continue
+		}
+		s := byID[e.Session]
+		switch {
+		case e.Kind == sessionStartKind && s == nil:
+			s = &agentSession{ID: e.Session, Agent: e.Actor, Note: e.Reason, StartedAt: e.CreatedAt}
+			byID[s.ID] = s
+			sessions = append(sessions, s)
+		case s == nil:
+		case e.Kind == sessionEndKind:
+			at := e.CreatedAt
+			s.EndedAt = &at
+		case e.Kind == sessionCommandKind:
+			s.Commands++
+		}
+	}
+	return sessions, entries, nil
+}
+
+// currentSession returns the session named by BD_SESSION, or else the
+// workspace's active session, or nil.
+func currentSession() *agentSession {
+	if id := os.Getenv("BD_SESSION"); id != "" {
+		sessions, _, err := loadSessions()
+		if err != nil {
+			return nil
+		}
+		for _, s := range sessions {
+			if s.ID == id && s.EndedAt == nil {
+				return s
+			}
+		}
+		return nil
+	}
+	return readActiveSession()
+}
+
+func activeSessionPath() string {
+	beadsDir := beads.FindBeadsDir()
+	if beadsDir == "" {
+		return ""
+	}
+	return filepath.Join(beadsDir, activeSessionFile)
+}
+
+func readActiveSession() *agentSession {
+	path := activeSessionPath()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 - fixed file in .beads
+	if err != nil {
+		return nil
+	}
+	var s agentSession
+	if json.Unmarshal(data, &s) != nil || s.ID == "" {
+		return nil
+	}
+	return &s
+}
+
+func writeActiveSession(s *agentSession) error {
+	path := activeSessionPath()
+	if path == "" {
+		return fmt.Errorf("no .beads directory found")
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write active session: %w", err)
+	}
+	return nil
+}
+
+// sessionAgent returns the active session's agent, the default actor
+// while a session is open.
+func sessionAgent() string {
+	if s := currentSession(); s != nil {
+		return s.Agent
+	}
+	return ""
+}
+
+// recordSessionCommand logs a successful write command to the active
+// session. Failures are ignored: logging must not break the command.
+func recordSessionCommand(cmd *cobra.Command, args []string) {
+	if sessionWriteOp == "" || cmd.Parent() == sessionCmd {
+		return
+	}
+	s := currentSession()
+	if s == nil {
+		return
+	}
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	e := &audit.Entry{Kind: sessionCommandKind, Actor: getActor(), Session: s.ID, ToolName: command}
+	argv := append([]string{}, args...)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Name != "json" && f.Name != "actor" {
+			argv = append(argv, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+	if len(argv) > 0 {
+		e.Extra = map[string]any{"args": argv}
+	}
+	_, _ = audit.Append(e)
+}
+
+func newSessionID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return "ses-" + hex.EncodeToString(b[:])
+}
+
+func init() {
+	sessionStartCmd.Flags().String("agent", "", "Agent name the session's commands run as (default: the current actor)")
+	sessionStartCmd.Flags().String("note", "", "What the session is for")
+	sessionListCmd.Flags().String("agent", "", "Only sessions of this agent")
+	sessionCmd.AddCommand(sessionStartCmd, sessionEndCmd, sessionListCmd, sessionShowCmd)
+	rootCmd.AddCommand(sessionCmd)
+}
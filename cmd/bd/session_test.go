@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/audit"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestAgentSessions(t *testing.T) {
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	if err := os.MkdirAll(beadsDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_DIR", beadsDir)
+	t.Setenv("BD_SESSION", "")
+
+	if currentSession() != nil || sessionAgent() != "" {
+		t.Fatal("session active before start")
+	}
+	for _, e := range []*audit.Entry{
+		{Kind: sessionStartKind, Actor: "agent-1", Session: "ses-1", Reason: "triage"},
+		{Kind: sessionCommandKind, Actor: "agent-1", Session: "ses-1", ToolName: "update"},
+		{Kind: sessionStartKind, Actor: "agent-2", Session: "ses-2"},
+		{Kind: "llm_call", Actor: "agent-1"},
+		{Kind: sessionCommandKind, Actor: "agent-1", Session: "ses-1", ToolName: "close"},
+		{Kind: sessionEndKind, Actor: "agent-1", Session: "ses-1"},
+	} {
+		if _, err := audit.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sessions, entries, err := loadSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 6 || len(sessions) != 2 {
+		t.Fatalf("sessions = %+v", sessions)
+	}
+	if s := sessions[0]; s.Agent != "agent-1" || s.Note != "triage" || s.Commands != 2 || s.EndedAt == nil {
+		t.Errorf("ses-1 = %+v", s)
+	}
+	if s := sessions[1]; s.Agent != "agent-2" || s.EndedAt != nil {
+		t.Errorf("ses-2 = %+v", s)
+	}
+
+	// BD_SESSION selects an open session; ended ones don't count
+	t.Setenv("BD_SESSION", "ses-2")
+	if sessionAgent() != "agent-2" {
+		t.Errorf("BD_SESSION=ses-2: agent = %q", sessionAgent())
+	}
+	t.Setenv("BD_SESSION", "ses-1")
+	if currentSession() != nil {
+		t.Error("ended session still current")
+	}
+
+	// Without BD_SESSION, the workspace's active session file decides
+	t.Setenv("BD_SESSION", "")
+	if err := writeActiveSession(sessions[1]); err != nil {
+		t.Fatal(err)
+	}
+	if s := currentSession(); s == nil || s.ID != "ses-2" {
+		t.Errorf("active session = %+v", s)
+	}
+}
+
+func TestDescribeSessionEvent(t *testing.T) {
+	str := func(s string) *string { return &s }
+	for _, tc := range []struct {
+		ev   types.Event
+		want string
+	}{
+		{types.Event{EventType: types.EventCreated, NewValue: str(`{"title":"x"}`)}, "created"},
+		{types.Event{EventType: types.EventClosed, Comment: str("done")}, "closed: done"},
+		{types.Event{EventType: types.EventStatusChanged, OldValue: str(`{"status":"open"}`), NewValue: str(`{"status":"in_progress","assignee":"a"}`)},
+			"status changed: status open → in_progress, assignee"},
+		{types.Event{EventType: types.EventUpdated, NewValue: str(`{"priority":1,"notes":"n"}`)}, "updated: notes, priority"},
+	} {
+		if got := describeSessionEvent(&tc.ev); got != tc.want {
+			t.Errorf("describeSessionEvent(%s) = %q, want %q", tc.ev.EventType, got, tc.want)
+		}
+	}
+}
@@ -8,11 +8,19 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/advisory"
+	"github.com/steveyegge/beads/internal/cc"
+	"github.com/steveyegge/beads/internal/goals"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/repro"
 	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/subset"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/visibility"
+	"github.com/steveyegge/beads/internal/votes"
 )
 
 var showCmd = &cobra.Command{
@@ -97,6 +105,9 @@ var showCmd = &cobra.Command{
 			return
 		}
 
+		// Requested IDs that couldn't be shown; sets the exit status at the end
+		var failures showFailures
+
 		// If daemon is running, use RPC (but fall back to direct mode for routed IDs)
 		if daemonClient != nil {
 			allDetails := []interface{}{}
@@ -110,6 +121,7 @@ var showCmd = &cobra.Command{
 						result.Close()
 					}
 					fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
+					failures.add(id, err)
 					continue
 				}
 				if result == nil || result.Issue == nil {
@@ -117,10 +129,18 @@ var showCmd = &cobra.Command{
 						result.Close()
 					}
 					fmt.Fprintf(os.Stderr, "Issue %s not found\n", id)
+					failures.add(id, nil)
 					continue
 				}
 				issue := result.Issue
 				issueStore := result.Store
+				_ = refs.Populate(ctx, issueStore, []*types.Issue{issue})
+				_ = advisory.Populate(ctx, issueStore, []*types.Issue{issue})
+				_ = repro.Populate(ctx, issueStore, []*types.Issue{issue})
+				_ = votes.Populate(ctx, issueStore, []*types.Issue{issue})
+				_ = cc.Populate(ctx, issueStore, []*types.Issue{issue})
+				_ = visibility.Populate(ctx, issueStore, []*types.Issue{issue})
+				_ = goals.Populate(ctx, issueStore, []*types.Issue{issue})
 				if shortMode {
 					fmt.Println(formatShortIssue(issue))
 					result.Close()
@@ -151,9 +171,18 @@ var showCmd = &cobra.Command{
 					fmt.Printf("\n%s\n", formatIssueHeader(issue))
 					// Metadata: Owner · Type | Created · Updated
 					fmt.Println(formatIssueMetadata(issue))
+					printDeadRefs(ctx, issueStore, issue)
+					printPullRequests(ctx, issueStore, issue)
+					printCrashStats(ctx, issueStore, issue)
+					printSecurity(issue)
+					printVotes(issue)
+					printCC(issue)
+					printVisibility(issue)
 					if issue.Description != "" {
 						fmt.Printf("\n%s\n%s\n", ui.RenderBold("DESCRIPTION"), ui.RenderMarkdown(issue.Description))
 					}
+					printRepro(issue)
+					printKeyResults(issue)
 					fmt.Println()
 					displayIdx++
 				}
@@ -166,6 +195,7 @@ var showCmd = &cobra.Command{
 				resp, err := daemonClient.Show(showArgs)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
+					failures.add(id, err)
 					continue
 				}
 
@@ -185,6 +215,7 @@ var showCmd = &cobra.Command{
 					// Check if issue exists (daemon returns null for non-existent issues)
 					if string(resp.Data) == "null" || len(resp.Data) == 0 {
 						fmt.Fprintf(os.Stderr, "Issue %s not found\n", id)
+						failures.add(id, nil)
 						continue
 					}
 
@@ -211,6 +242,11 @@ var showCmd = &cobra.Command{
 
 					// Metadata: Owner · Type | Created · Updated
 					fmt.Println(formatIssueMetadata(issue))
+					printCrashOccurrences(issue, details.Comments)
+					printSecurity(issue)
+					printVotes(issue)
+					printCC(issue)
+					printVisibility(issue)
 
 					// Compaction info (if applicable)
 					if issue.CompactionLevel > 0 {
@@ -230,6 +266,8 @@ var showCmd = &cobra.Command{
 					if issue.Description != "" {
 						fmt.Printf("\n%s\n%s\n", ui.RenderBold("DESCRIPTION"), ui.RenderMarkdown(issue.Description))
 					}
+					printRepro(issue)
+					printKeyResults(issue)
 					if issue.Design != "" {
 						fmt.Printf("\n%s\n%s\n", ui.RenderBold("DESIGN"), ui.RenderMarkdown(issue.Design))
 					}
@@ -322,6 +360,7 @@ var showCmd = &cobra.Command{
 			} else if len(routedArgs) > 0 {
 				SetLastTouchedID(routedArgs[0])
 			}
+			failures.exit()
 			return
 		}
 
@@ -335,6 +374,7 @@ var showCmd = &cobra.Command{
 					result.Close()
 				}
 				fmt.Fprintf(os.Stderr, "Error fetching %s: %v\n", id, err)
+				failures.add(id, err)
 				continue
 			}
 			if result == nil || result.Issue == nil {
@@ -342,10 +382,18 @@ var showCmd = &cobra.Command{
 					result.Close()
 				}
 				fmt.Fprintf(os.Stderr, "Issue %s not found\n", id)
+				failures.add(id, nil)
 				continue
 			}
 			issue := result.Issue
 			issueStore := result.Store // Use the store that contains this issue
+			_ = refs.Populate(ctx, issueStore, []*types.Issue{issue})
+			_ = advisory.Populate(ctx, issueStore, []*types.Issue{issue})
+			_ = repro.Populate(ctx, issueStore, []*types.Issue{issue})
+			_ = votes.Populate(ctx, issueStore, []*types.Issue{issue})
+			_ = cc.Populate(ctx, issueStore, []*types.Issue{issue})
+			_ = visibility.Populate(ctx, issueStore, []*types.Issue{issue})
+			_ = goals.Populate(ctx, issueStore, []*types.Issue{issue})
 			// Note: result.Close() called at end of loop iteration
 
 			if shortMode {
@@ -397,6 +445,18 @@ var showCmd = &cobra.Command{
 
 			// Metadata: Owner · Type | Created · Updated
 			fmt.Println(formatIssueMetadata(issue))
+			printDeadRefs(ctx, issueStore, issue)
+			printPullRequests(ctx, issueStore, issue)
+			printCrashStats(ctx, issueStore, issue)
+			printSecurity(issue)
+			printVotes(issue)
+			printCC(issue)
+			printVisibility(issue)
+
+			// Subset clones (bd init --subset) hold other issues as stubs
+			if stubs, _ := subset.StubIDs(ctx, issueStore); stubs[issue.ID] {
+				fmt.Println(ui.RenderMuted("Stub: outside this clone's subset; long text and comments are only in the JSONL"))
+			}
 
 			// Compaction info (if applicable)
 			if issue.CompactionLevel > 0 {
@@ -416,6 +476,8 @@ var showCmd = &cobra.Command{
 			if issue.Description != "" {
 				fmt.Printf("\n%s\n%s\n", ui.RenderBold("DESCRIPTION"), ui.RenderMarkdown(issue.Description))
 			}
+			printRepro(issue)
+			printKeyResults(issue)
 			if issue.Design != "" {
 				fmt.Printf("\n%s\n%s\n", ui.RenderBold("DESIGN"), ui.RenderMarkdown(issue.Design))
 			}
@@ -529,13 +591,46 @@ var showCmd = &cobra.Command{
 		if len(args) > 0 {
 			SetLastTouchedID(args[0])
 		}
+		failures.exit()
 	},
 }
 
+// showFailures collects IDs that bd show couldn't display. Each failure is
+// reported as text when it happens; exit then sets the exit status (and, with
+// --json, writes the structured error) once everything found has been shown.
+type showFailures struct {
+	ids  []string
+	code ErrorCode
+}
+
+func (f *showFailures) add(id string, err error) {
+	f.ids = append(f.ids, id)
+	code := ErrCodeNotFound
+	if err != nil {
+		code = classifyError(err.Error(), []interface{}{err})
+	}
+	if f.code == "" || f.code == ErrCodeGeneric {
+		f.code = code
+	}
+}
+
+func (f *showFailures) exit() {
+	if len(f.ids) == 0 {
+		return
+	}
+	if jsonOutput {
+		writeCLIError(f.code, fmt.Sprintf("could not show %s", strings.Join(f.ids, ", ")), "")
+	}
+	exitFunc(f.code.ExitCode())
+}
+
 
 // formatShortIssue returns a compact one-line representation of an issue
 // Format: STATUS_ICON ID PRIORITY [Type] Title
 func formatShortIssue(issue *types.Issue) string {
+	if ui.IsAccessible() {
+		return accessibleIssueLine(issue, nil)
+	}
 	statusIcon := ui.RenderStatusIcon(string(issue.Status))
 	priorityTag := ui.RenderPriority(issue.Priority)
 
@@ -565,6 +660,11 @@ func formatShortIssue(issue *types.Issue) string {
 // Format: ID · Title   [Priority · STATUS]
 // All elements in bd show get semantic colors since focus is on one issue
 func formatIssueHeader(issue *types.Issue) string {
+	if ui.IsAccessible() {
+		// Type follows in the metadata
+		return fmt.Sprintf("%s: %s.\nStatus %s. Priority P%d.", issue.ID, strings.TrimRight(issue.Title, "."),
+			strings.ReplaceAll(string(issue.Status), "_", " "), issue.Priority)
+	}
 	// Get status icon and style
 	statusIcon := ui.RenderStatusIcon(string(issue.Status))
 	statusStyle := ui.GetStatusStyle(string(issue.Status))
@@ -623,8 +723,13 @@ func formatIssueMetadata(issue *types.Issue) string {
 	}
 	metaParts = append(metaParts, fmt.Sprintf("Type: %s", typeStr))
 
+	// One fact per line for screen readers
+	sep := " · "
+	if ui.IsAccessible() {
+		sep = "\n"
+	}
 	if len(metaParts) > 0 {
-		lines = append(lines, strings.Join(metaParts, " · "))
+		lines = append(lines, strings.Join(metaParts, sep))
 	}
 
 	// Line 2: Created · Updated · Due/Defer
@@ -639,7 +744,7 @@ func formatIssueMetadata(issue *types.Issue) string {
 		timeParts = append(timeParts, fmt.Sprintf("Deferred: %s", issue.DeferUntil.Format("2006-01-02")))
 	}
 	if len(timeParts) > 0 {
-		lines = append(lines, strings.Join(timeParts, " · "))
+		lines = append(lines, strings.Join(timeParts, sep))
 	}
 
 	// Line 3: Close reason (if closed)
@@ -647,17 +752,45 @@ func formatIssueMetadata(issue *types.Issue) string {
 		lines = append(lines, ui.RenderMuted(fmt.Sprintf("Close reason: %s", issue.CloseReason)))
 	}
 
-	// Line 4: External ref (if exists)
-	if issue.ExternalRef != nil && *issue.ExternalRef != "" {
-		lines = append(lines, fmt.Sprintf("External: %s", *issue.ExternalRef))
+	// Line 4: External refs (if any)
+	if all := refs.All(issue); len(all) > 0 {
+		parts := make([]string, len(all))
+		for i, ref := range all {
+			parts[i] = fmt.Sprintf("%s %s", ref.Value, ui.RenderMuted("("+string(ref.Type)+")"))
+		}
+		lines = append(lines, "External: "+strings.Join(parts, sep))
 	}
 
 	return strings.Join(lines, "\n")
 }
 
+// printDeadRefs warns about refs that the last bd ref check found dead.
+func printDeadRefs(ctx context.Context, s storage.Storage, issue *types.Issue) {
+	checks, _ := refs.Checks(ctx, s)
+	for _, ref := range refs.All(issue) {
+		if check := checks[ref.Value]; check != nil && check.Status == refs.StatusDead {
+			fmt.Printf("%s Dead ref: %s (%s, checked %s)\n", ui.RenderWarn("⚠"), ref.Value,
+				check.Detail, check.CheckedAt.Local().Format("2006-01-02"))
+		}
+	}
+}
+
+// dependencyRelations spells out the arrows bd show puts before related
+// issues, for accessible output.
+var dependencyRelations = map[string]string{
+	"→": "Depends on",
+	"↳": "Child",
+	"←": "Blocks",
+	"↔": "Related",
+	"◊": "Discovered",
+}
+
 // formatDependencyLine formats a single dependency with semantic colors
 // Closed items get entire row muted - the work is done, no need for attention
 func formatDependencyLine(prefix string, dep *types.IssueWithDependencyMetadata) string {
+	if ui.IsAccessible() {
+		return "  " + accessibleRelationLine(dependencyRelations[prefix], dep.ID, dep.Title, dep.Status, dep.Priority)
+	}
 	// Status icon (always rendered with semantic color)
 	statusIcon := ui.GetStatusIcon(string(dep.Status))
 
@@ -689,6 +822,9 @@ func formatDependencyLine(prefix string, dep *types.IssueWithDependencyMetadata)
 // formatSimpleDependencyLine formats a dependency without metadata (fallback)
 // Closed items get entire row muted - the work is done, no need for attention
 func formatSimpleDependencyLine(prefix string, dep *types.Issue) string {
+	if ui.IsAccessible() {
+		return "  " + accessibleRelationLine(dependencyRelations[prefix], dep.ID, dep.Title, dep.Status, dep.Priority)
+	}
 	statusIcon := ui.GetStatusIcon(string(dep.Status))
 
 	// Closed items: mute entire row since the work is complete
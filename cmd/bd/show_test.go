@@ -1,60 +1,20 @@
 package main
 
 import (
-	"encoding/json"
-	"os/exec"
-	"path/filepath"
 	"strings"
 	"testing"
 )
 
 func TestShow_ExternalRef(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping CLI test in short mode")
-	}
-
-	// Build bd binary
-	tmpBin := filepath.Join(t.TempDir(), "bd")
-	buildCmd := exec.Command("go", "build", "-o", tmpBin, "./")
-	buildCmd.Dir = "."
-	if out, err := buildCmd.CombinedOutput(); err != nil {
-		t.Fatalf("failed to build bd: %v\n%s", err, out)
-	}
-
-	// Create temp directory for test database
-	tmpDir := t.TempDir()
-
-	// Initialize beads
-	initCmd := exec.Command(tmpBin, "init", "--prefix", "test", "--quiet")
-	initCmd.Dir = tmpDir
-	if out, err := initCmd.CombinedOutput(); err != nil {
-		t.Fatalf("init failed: %v\n%s", err, out)
-	}
-
-	// Create issue with external ref
-	createCmd := exec.Command(tmpBin, "--no-daemon", "create", "External ref test", "-p", "1",
-		"--external-ref", "https://example.com/spec.md", "--json")
-	createCmd.Dir = tmpDir
-	createOut, err := createCmd.CombinedOutput()
-	if err != nil {
-		t.Fatalf("create failed: %v\n%s", err, createOut)
-	}
+	p := newTestProject(t)
 
 	var issue map[string]interface{}
-	if err := json.Unmarshal(createOut, &issue); err != nil {
-		t.Fatalf("failed to parse create output: %v, output: %s", err, createOut)
-	}
+	p.RunJSON(&issue, "create", "External ref test", "-p", "1",
+		"--external-ref", "https://example.com/spec.md")
 	id := issue["id"].(string)
 
 	// Show the issue and verify external ref is displayed
-	showCmd := exec.Command(tmpBin, "--no-daemon", "show", id)
-	showCmd.Dir = tmpDir
-	showOut, err := showCmd.CombinedOutput()
-	if err != nil {
-		t.Fatalf("show failed: %v\n%s", err, showOut)
-	}
-
-	out := string(showOut)
+	out := p.Run("show", id)
 	if !strings.Contains(out, "External:") {
 		t.Errorf("expected 'External:' in output, got: %s", out)
 	}
@@ -64,51 +24,31 @@ func TestShow_ExternalRef(t *testing.T) {
 }
 
 func TestShow_NoExternalRef(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping CLI test in short mode")
-	}
-
-	// Build bd binary
-	tmpBin := filepath.Join(t.TempDir(), "bd")
-	buildCmd := exec.Command("go", "build", "-o", tmpBin, "./")
-	buildCmd.Dir = "."
-	if out, err := buildCmd.CombinedOutput(); err != nil {
-		t.Fatalf("failed to build bd: %v\n%s", err, out)
-	}
-
-	tmpDir := t.TempDir()
-
-	// Initialize beads
-	initCmd := exec.Command(tmpBin, "init", "--prefix", "test", "--quiet")
-	initCmd.Dir = tmpDir
-	if out, err := initCmd.CombinedOutput(); err != nil {
-		t.Fatalf("init failed: %v\n%s", err, out)
-	}
-
-	// Create issue WITHOUT external ref
-	createCmd := exec.Command(tmpBin, "--no-daemon", "create", "No ref test", "-p", "1", "--json")
-	createCmd.Dir = tmpDir
-	createOut, err := createCmd.CombinedOutput()
-	if err != nil {
-		t.Fatalf("create failed: %v\n%s", err, createOut)
-	}
+	p := newTestProject(t)
 
 	var issue map[string]interface{}
-	if err := json.Unmarshal(createOut, &issue); err != nil {
-		t.Fatalf("failed to parse create output: %v, output: %s", err, createOut)
-	}
+	p.RunJSON(&issue, "create", "No ref test", "-p", "1")
 	id := issue["id"].(string)
 
 	// Show the issue - should NOT contain External Ref line
-	showCmd := exec.Command(tmpBin, "--no-daemon", "show", id)
-	showCmd.Dir = tmpDir
-	showOut, err := showCmd.CombinedOutput()
-	if err != nil {
-		t.Fatalf("show failed: %v\n%s", err, showOut)
-	}
-
-	out := string(showOut)
+	out := p.Run("show", id)
 	if strings.Contains(out, "External:") {
 		t.Errorf("expected no 'External:' line for issue without external ref, got: %s", out)
 	}
 }
+
+func TestCreate_FatalErrorExitCode(t *testing.T) {
+	p := newTestProject(t)
+
+	// FatalError exits are reported as errors instead of ending the test binary.
+	res := p.RunCommand("create")
+	if res.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1, got %d (err=%v)", res.ExitCode(), res.Err)
+	}
+	if !strings.Contains(res.Stderr, "title required") {
+		t.Errorf("expected error on stderr, got: %s", res.Stderr)
+	}
+
+	// The harness recovers: later commands still work.
+	p.Run("create", "After failure", "-p", "2")
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"slices"
 
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/types"
@@ -28,19 +29,23 @@ func validateIssueClosable(id string, issue *types.Issue, force bool) error {
 }
 
 func applyLabelUpdates(ctx context.Context, st storage.Storage, issueID, actor string, setLabels, addLabels, removeLabels []string) error {
-	// Set labels (replaces all existing labels)
+	// Set labels (replaces all existing labels). The new ones go on first
+	// so the issue is never left without labels it is required to have.
 	if len(setLabels) > 0 {
 		currentLabels, err := st.GetLabels(ctx, issueID)
 		if err != nil {
 			return err
 		}
-		for _, label := range currentLabels {
-			if err := st.RemoveLabel(ctx, issueID, label, actor); err != nil {
+		for _, label := range setLabels {
+			if err := st.AddLabel(ctx, issueID, label, actor); err != nil {
 				return err
 			}
 		}
-		for _, label := range setLabels {
-			if err := st.AddLabel(ctx, issueID, label, actor); err != nil {
+		for _, label := range currentLabels {
+			if slices.Contains(setLabels, label) {
+				continue
+			}
+			if err := st.RemoveLabel(ctx, issueID, label, actor); err != nil {
 				return err
 			}
 		}
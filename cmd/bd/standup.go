@@ -0,0 +1,267 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/timeparsing"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+var standupCmd = &cobra.Command{
+	Use:     "standup",
+	GroupID: "views",
+	Short:   "Daily standup: what you finished, are working on and are blocked on",
+	Long: `Write a standup update from the issue history: issues closed since the last
+standup (done), issues in progress and issues blocked, each with the
+comments you added in the period.
+
+An issue counts as yours when it's assigned to you or you changed its status
+in the period. --since defaults to yesterday: the start of the previous
+working day, so a Monday standup covers Friday. It also takes today, a
+duration (24h, 3d), a date or an expression such as "last monday".
+
+Formats:
+  text      For the terminal (default)
+  markdown  For a wiki, an issue or a chat that renders Markdown
+  slack     Slack mrkdwn, ready to paste or post with a webhook
+
+Private issues only appear in text output.`,
+	Example: `  bd standup
+  bd standup --for alice --since 3d
+  bd standup --format slack | pbcopy
+  bd standup --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		person, _ := cmd.Flags().GetString("for")
+		sinceStr, _ := cmd.Flags().GetString("since")
+		format, _ := cmd.Flags().GetString("format")
+
+		if format != "text" && format != "markdown" && format != "slack" {
+			FatalErrorWithHint(fmt.Sprintf("unknown format %q", format), "use --format text, markdown or slack")
+		}
+		if person == "" {
+			person = actor
+		}
+		if person == "" {
+			FatalErrorCode(ErrCodeUsage, "cannot tell whose standup this is: use --for, --actor, BD_ACTOR or git user.name")
+		}
+		now := time.Now()
+		since, err := parseStandupSince(sinceStr, now)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if err := ensureDirectMode("standup requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+
+		audience := visibility.Private
+		if format != "text" || jsonOutput {
+			audience = visibility.Internal
+		}
+		report, err := buildStandup(rootCtx, store, strings.TrimPrefix(person, "@"), since, audience)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		writeStandup(os.Stdout, report, format)
+	},
+}
+
+// StandupReport is the data behind bd standup.
+type StandupReport struct {
+	Person     string         `json:"person"`
+	Since      time.Time      `json:"since"`
+	Done       []StandupIssue `json:"done"`
+	InProgress []StandupIssue `json:"in_progress"`
+	Blocked    []StandupIssue `json:"blocked"`
+}
+
+// StandupIssue is an issue listed in a standup, with the person's comments
+// from the period, oldest first.
+type StandupIssue struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Priority  int      `json:"priority"`
+	BlockedBy []string `json:"blocked_by,omitempty"`
+	Comments  []string `json:"comments,omitempty"`
+}
+
+// parseStandupSince resolves --since: yesterday is the start of the
+// previous working day, today the start of this one, and a bare duration
+// reaches back from now.
+func parseStandupSince(s string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "yesterday":
+		day := today.AddDate(0, 0, -1)
+		for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			day = day.AddDate(0, 0, -1)
+		}
+		return day, nil
+	case "today":
+		return today, nil
+	}
+	return parsePastSince(s, now)
+}
+
+// parsePastSince parses a --since value naming a time in the past: a date,
+// a relative time, or a bare duration such as 3d meaning that long ago.
+func parsePastSince(s string, now time.Time) (time.Time, error) {
+	if timeparsing.IsCompactDuration(s) && !strings.HasPrefix(s, "+") {
+		return timeparsing.ParseCompactDuration("-"+strings.TrimPrefix(s, "-"), now)
+	}
+	t, err := timeparsing.ParseRelativeTime(s, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: use a duration such as 3d or a date", s)
+	}
+	if t.After(now) {
+		return time.Time{}, fmt.Errorf("--since %q is in the future", s)
+	}
+	return t, nil
+}
+
+// buildStandup gathers person's standup since the given time, leaving out
+// issues the audience may not see.
+func buildStandup(ctx context.Context, s storage.Storage, person string, since time.Time, audience string) (*StandupReport, error) {
+	report := &StandupReport{Person: person, Since: since}
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	if err := visibility.Populate(ctx, s, issues); err != nil {
+		return nil, err
+	}
+	issues = visibility.Filter(issues, audience)
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	comments, err := s.GetCommentsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	blocked, err := s.GetBlockedIssues(ctx, types.WorkFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked issues: %w", err)
+	}
+	blockers := make(map[string][]string, len(blocked))
+	for _, b := range blocked {
+		blockers[b.ID] = b.BlockedBy
+	}
+
+	for _, issue := range issues {
+		if issue.Status == types.StatusTombstone {
+			continue
+		}
+		// Status changes made by the person in the period
+		moved := false
+		if !issue.UpdatedAt.Before(since) {
+			events, err := s.GetEvents(ctx, issue.ID, 0)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get events of %s: %w", issue.ID, err)
+			}
+			for _, e := range events {
+				switch e.EventType {
+				case types.EventStatusChanged, types.EventClosed, types.EventReopened:
+					if e.Actor == person && !e.CreatedAt.Before(since) {
+						moved = true
+					}
+				}
+			}
+		}
+		if issue.Assignee != person && !moved {
+			continue
+		}
+
+		item := StandupIssue{ID: issue.ID, Title: issue.Title, Priority: issue.Priority}
+		for _, c := range comments[issue.ID] {
+			if c.Author == person && !c.CreatedAt.Before(since) {
+				item.Comments = append(item.Comments, strings.Join(strings.Fields(c.Text), " "))
+			}
+		}
+		switch {
+		case issue.Status == types.StatusClosed:
+			if issue.ClosedAt != nil && !issue.ClosedAt.Before(since) {
+				report.Done = append(report.Done, item)
+			}
+		case issue.Status == types.StatusBlocked || blockers[issue.ID] != nil:
+			item.BlockedBy = blockers[issue.ID]
+			report.Blocked = append(report.Blocked, item)
+		case issue.Status == types.StatusInProgress:
+			report.InProgress = append(report.InProgress, item)
+		}
+	}
+
+	byPriority := func(a, b StandupIssue) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.ID, b.ID))
+	}
+	slices.SortFunc(report.Done, byPriority)
+	slices.SortFunc(report.InProgress, byPriority)
+	slices.SortFunc(report.Blocked, byPriority)
+	return report, nil
+}
+
+// writeStandup writes the report as text, markdown or slack.
+func writeStandup(w io.Writer, r *StandupReport, format string) {
+	since := r.Since.Local().Format("Mon Jan 2")
+	heading, bullet, empty := "\n%s\n", "  - ", "  (nothing)"
+	quote := func(c string) string { return "      “" + c + "”" }
+	code := func(id string) string { return id }
+	switch format {
+	case "markdown":
+		fmt.Fprintf(w, "## Standup: %s (since %s)\n", r.Person, since)
+		heading, bullet, empty = "\n**%s**\n\n", "- ", "- Nothing"
+		quote = func(c string) string { return "  > " + c }
+		code = func(id string) string { return "`" + id + "`" }
+	case "slack":
+		fmt.Fprintf(w, "*Standup: %s* (since %s)\n", r.Person, since)
+		heading, bullet, empty = "\n*%s*\n", "• ", "• _Nothing_"
+		quote = func(c string) string { return "    _“" + c + "”_" }
+		code = func(id string) string { return "`" + id + "`" }
+	default:
+		fmt.Fprintf(w, "Standup for %s since %s\n", r.Person, since)
+	}
+
+	section := func(title string, items []StandupIssue) {
+		fmt.Fprintf(w, heading, title)
+		if len(items) == 0 {
+			fmt.Fprintln(w, empty)
+		}
+		for _, it := range items {
+			line := fmt.Sprintf("%s%s (%s)", bullet, it.Title, code(it.ID))
+			if len(it.BlockedBy) > 0 {
+				blockedBy := make([]string, len(it.BlockedBy))
+				for i, id := range it.BlockedBy {
+					blockedBy[i] = code(id)
+				}
+				line += " — blocked by " + strings.Join(blockedBy, ", ")
+			}
+			fmt.Fprintln(w, line)
+			for _, c := range it.Comments {
+				fmt.Fprintln(w, quote(c))
+			}
+		}
+	}
+	section("Done", r.Done)
+	section("In progress", r.InProgress)
+	section("Blocked", r.Blocked)
+}
+
+func init() {
+	standupCmd.Flags().String("for", "", "Whose standup (default: you, from --actor, BD_ACTOR or git user.name)")
+	standupCmd.Flags().String("since", "yesterday", "Start of the period: yesterday, today, a duration (24h, 3d) or a date")
+	standupCmd.Flags().String("format", "text", "Output format: text, markdown or slack")
+	rootCmd.AddCommand(standupCmd)
+}
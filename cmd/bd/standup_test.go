@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+func TestParseStandupSince(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 9, 30, 0, 0, time.Local)
+	for in, want := range map[string]time.Time{
+		"yesterday":  time.Date(2026, 2, 27, 0, 0, 0, 0, time.Local), // Friday
+		"today":      time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local),
+		"24h":        monday.Add(-24 * time.Hour),
+		"2026-02-20": time.Date(2026, 2, 20, 0, 0, 0, 0, time.Local),
+	} {
+		got, err := parseStandupSince(in, monday)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseStandupSince(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseStandupSince("next friday", monday); err == nil {
+		t.Error("parseStandupSince accepted a future time")
+	}
+}
+
+func TestBuildStandup(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	for _, issue := range []*types.Issue{
+		{ID: "test-1", Title: "Outage", Status: types.StatusInProgress, Priority: 0, IssueType: types.TypeBug, Assignee: "alice"},
+		{ID: "test-2", Title: "Billing", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, Assignee: "alice"},
+		{ID: "test-3", Title: "Docs", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "test-4", Title: "Salaries", Status: types.StatusInProgress, Priority: 2, IssueType: types.TypeTask, Assignee: "alice"},
+		{ID: "test-5", Title: "Someone else's", Status: types.StatusInProgress, Priority: 2, IssueType: types.TypeTask, Assignee: "bob"},
+	} {
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: "test-2", DependsOnID: "test-1", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseIssue(ctx, "test-3", "done", "alice", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddIssueComment(ctx, "test-1", "alice", "Rolled back\nthe deploy"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetVisibility(ctx, "test-4", visibility.Private, "tester"); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := buildStandup(ctx, s, "alice", time.Now().Add(-time.Hour), visibility.Internal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Done) != 1 || r.Done[0].ID != "test-3" {
+		t.Errorf("done = %+v", r.Done)
+	}
+	if len(r.InProgress) != 1 || r.InProgress[0].ID != "test-1" || len(r.InProgress[0].Comments) != 1 {
+		t.Errorf("in progress = %+v", r.InProgress)
+	}
+	if len(r.Blocked) != 1 || r.Blocked[0].BlockedBy[0] != "test-1" {
+		t.Errorf("blocked = %+v", r.Blocked)
+	}
+
+	var buf bytes.Buffer
+	writeStandup(&buf, r, "slack")
+	for _, want := range []string{"*Standup: alice*", "• Outage (`test-1`)", "_“Rolled back the deploy”_", "blocked by `test-1`"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("slack output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
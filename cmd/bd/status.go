@@ -58,7 +58,14 @@ Examples:
   bd status --no-activity      # Skip git activity (faster)
   bd status --json             # JSON format output
   bd status --assigned         # Show issues assigned to current user
-  bd stats                     # Alias for bd status`,
+  bd status --query label:api  # Counts for issues matching a query
+  bd status --component api    # Counts for one component
+  bd stats                     # Alias for bd status
+  bd stats workload            # Open work per assignee by priority and age
+  bd stats heatmap             # Age of open issues per label
+  bd stats cfd --format csv    # Daily counts per status for a cumulative flow diagram
+  bd stats response-times      # Time to first response and to resolution
+  bd stats labels              # Open/close rates and cycle time per label`,
 	Run: func(cmd *cobra.Command, args []string) {
 		showAll, _ := cmd.Flags().GetBool("all")
 		showAssigned, _ := cmd.Flags().GetBool("assigned")
@@ -106,6 +113,24 @@ Examples:
 			}
 		}
 
+		// Filter by query expression (overrides stats with filtered counts)
+		if queryStr, _ := cmd.Flags().GetString("query"); queryStr != "" {
+			stats, err = getQueryStatistics(ctx, parseQueryFlag(queryStr))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		// Scope to one component (component:<name> label)
+		if component, _ := cmd.Flags().GetString("component"); component != "" {
+			stats, err = getQueryStatistics(ctx, parseQueryFlag("label="+componentLabel(component)))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
 		// Filter by assignee if requested (overrides stats with filtered counts)
 		if showAssigned {
 			stats = getAssignedStatistics(actor)
@@ -343,6 +368,8 @@ func init() {
 	statusCmd.Flags().Bool("all", false, "Show all issues (default behavior)")
 	statusCmd.Flags().Bool("assigned", false, "Show issues assigned to current user")
 	statusCmd.Flags().Bool("no-activity", false, "Skip git activity tracking (faster)")
+	statusCmd.Flags().String("query", "", "Count only issues matching this query expression (see 'bd query --help')")
+	statusCmd.Flags().String("component", "", "Count only issues in this component")
 	// Note: --json flag is defined as a persistent flag in main.go, not here
 	rootCmd.AddCommand(statusCmd)
 }
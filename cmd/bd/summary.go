@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/statusdoc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var summaryCmd = &cobra.Command{
+	Use:     "summary",
+	GroupID: "views",
+	Short:   "Maintain a project status document (STATUS.md)",
+	Long: `Keep a human-readable project status document in the repository: top
+priorities, work in progress, blocked issues and recent closes, for readers
+who don't run bd.
+
+bd summary write regenerates the summary between its begin and end markers
+and leaves the rest of the file alone, so it can sit inside a README. The
+file only changes when the issues do. Private issues are left out.
+
+Set summary.file in config.yaml to have the daemon refresh it after every
+export:
+  summary:
+    file: STATUS.md
+or refresh it from a git hook with bd summary write --check || bd summary write.`,
+}
+
+var summaryWriteCmd = &cobra.Command{
+	Use:   "write [file]",
+	Short: "Write or refresh the status document",
+	Example: `  bd summary write                 # summary.file or STATUS.md
+  bd summary write README.md       # Inside the README, between markers
+  bd summary write --days 7 --limit 5
+  bd summary write --check         # Exit 1 if the document is out of date`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := ""
+		if len(args) > 0 {
+			file = args[0]
+		}
+		check, _ := cmd.Flags().GetBool("check")
+		limit, _ := cmd.Flags().GetInt("limit")
+		days, _ := cmd.Flags().GetInt("days")
+		if err := ensureDirectMode("summary write requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		path := summaryPath(file)
+
+		changed, err := writeSummaryFile(rootCtx, store, path, statusdoc.Options{Limit: limit, Days: days}, check)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"file": path, "changed": changed, "check": check})
+		} else if check && changed {
+			fmt.Printf("%s is out of date (run bd summary write)\n", path)
+		} else if changed {
+			fmt.Printf("%s Wrote %s\n", ui.RenderPass("✓"), path)
+		} else {
+			fmt.Printf("%s is up to date\n", path)
+		}
+		if check && changed {
+			os.Exit(1)
+		}
+	},
+}
+
+// summaryPath resolves the status document: the argument, else
+// summary.file, else STATUS.md, relative to the repository root.
+func summaryPath(file string) string {
+	if file == "" {
+		file = config.GetString("summary.file")
+	}
+	if file == "" {
+		file = "STATUS.md"
+	}
+	if filepath.IsAbs(file) || dbPath == "" {
+		return file
+	}
+	return filepath.Join(filepath.Dir(filepath.Dir(dbPath)), file)
+}
+
+// writeSummaryFile refreshes the summary in the document at path, creating
+// it if needed, and reports whether the file changed (or, with checkOnly,
+// would change).
+func writeSummaryFile(ctx context.Context, s storage.Storage, path string, opts statusdoc.Options, checkOnly bool) (bool, error) {
+	content, err := os.ReadFile(path) // #nosec G304 -- user-configured summary path
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	sum, err := statusdoc.Build(ctx, s, time.Now(), opts)
+	if err != nil {
+		return false, err
+	}
+	updated := statusdoc.Splice(string(content), statusdoc.Render(sum))
+	if updated == string(content) || checkOnly {
+		return updated != string(content), nil
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil { // #nosec G306 -- status document is a public repo file
+		return false, err
+	}
+	return true, nil
+}
+
+// syncSummary refreshes summary.file after a daemon export. It does nothing
+// when no status document is configured.
+func syncSummary(ctx context.Context, s storage.Storage, beadsDir string, log daemonLogger) {
+	file := config.GetString("summary.file")
+	if file == "" {
+		return
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(filepath.Dir(beadsDir), file)
+	}
+	changed, err := writeSummaryFile(ctx, s, file, statusdoc.Options{}, false)
+	if err != nil {
+		log.log("Status document update failed: %v", err)
+		return
+	}
+	if changed {
+		log.log("Status document refreshed: %s", file)
+	}
+}
+
+func init() {
+	summaryWriteCmd.Flags().Bool("check", false, "Report whether the document is out of date without writing it (exit 1 if so)")
+	summaryWriteCmd.Flags().Int("limit", statusdoc.DefaultLimit, "Issues listed per section")
+	summaryWriteCmd.Flags().Int("days", statusdoc.DefaultDays, "How many days of closed issues to list")
+	summaryCmd.AddCommand(summaryWriteCmd)
+	rootCmd.AddCommand(summaryCmd)
+}
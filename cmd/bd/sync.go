@@ -11,10 +11,18 @@ import (
 
 	"github.com/gofrs/flock"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/advisory"
 	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/cc"
 	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/debug"
+	"github.com/steveyegge/beads/internal/goals"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/repro"
+	"github.com/steveyegge/beads/internal/subset"
 	"github.com/steveyegge/beads/internal/syncbranch"
+	"github.com/steveyegge/beads/internal/visibility"
+	"github.com/steveyegge/beads/internal/votes"
 )
 
 var syncCmd = &cobra.Command{
@@ -355,6 +363,38 @@ func doPullFirstSync(ctx context.Context, jsonlPath string, renameOnImport, noGi
 	if err != nil {
 		return fmt.Errorf("loading local issues: %w", err)
 	}
+	if err := refs.Populate(ctx, store, localIssues); err != nil {
+		return fmt.Errorf("loading refs: %w", err)
+	}
+	if err := advisory.Populate(ctx, store, localIssues); err != nil {
+		return fmt.Errorf("loading security advisories: %w", err)
+	}
+	if err := repro.Populate(ctx, store, localIssues); err != nil {
+		return fmt.Errorf("loading repro metadata: %w", err)
+	}
+	if err := votes.Populate(ctx, store, localIssues); err != nil {
+		return fmt.Errorf("loading votes: %w", err)
+	}
+	if err := cc.Populate(ctx, store, localIssues); err != nil {
+		return fmt.Errorf("loading cc lists: %w", err)
+	}
+	if err := goals.Populate(ctx, store, localIssues); err != nil {
+		return fmt.Errorf("loading key results: %w", err)
+	}
+	// Private issues stay out of the merge and so out of the committed JSONL.
+	// This doesn't redact them: a clone that received one before it was made
+	// private still exports it, and the merge below then pulls that copy in
+	// (the importer keeps it private here)
+	localIssues, err = visibility.WithholdPrivate(ctx, store, localIssues)
+	if err != nil {
+		return fmt.Errorf("withholding private issues: %w", err)
+	}
+	// Subset stubs lack long text; merge them in full so the gap doesn't
+	// read as a local edit
+	localIssues, err = subset.PreserveStubs(ctx, store, jsonlPath, localIssues)
+	if err != nil {
+		return fmt.Errorf("restoring subset stubs: %w", err)
+	}
 	fmt.Printf("→ Loaded %d local issues from database\n", len(localIssues))
 
 	// Acquire exclusive lock to prevent concurrent sync corruption
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+)
+
+// syncCheckpoint records how far a tracker sync got, so a sync cut short by
+// Ctrl-C, --timeout or a network failure resumes instead of starting over.
+// It is stored in the database config as "<tracker>.sync_checkpoint" and
+// deleted when the sync completes.
+type syncCheckpoint struct {
+	Started    time.Time `json:"started"`               // When the interrupted sync began; becomes last_sync
+	Pulled     bool      `json:"pulled,omitempty"`      // Pull phase finished
+	PulledRefs []string  `json:"pulled_refs,omitempty"` // External refs the pull imported
+	Pushed     []string  `json:"pushed,omitempty"`      // Local issue IDs already pushed
+
+	key    string
+	pushed map[string]bool
+}
+
+func syncCheckpointKey(tracker string) string {
+	return tracker + ".sync_checkpoint"
+}
+
+// loadSyncCheckpoint returns the checkpoint of an interrupted sync, or a
+// fresh one starting now. resumed reports which.
+func loadSyncCheckpoint(ctx context.Context, s storage.Storage, tracker string) (cp *syncCheckpoint, resumed bool, err error) {
+	cp = &syncCheckpoint{Started: time.Now().UTC(), key: syncCheckpointKey(tracker), pushed: map[string]bool{}}
+	raw, err := s.GetConfig(ctx, cp.key)
+	if err != nil || raw == "" {
+		return cp, false, err
+	}
+	if err := json.Unmarshal([]byte(raw), cp); err != nil {
+		return nil, false, fmt.Errorf("invalid %s (run with --restart to discard it): %w", cp.key, err)
+	}
+	for _, id := range cp.Pushed {
+		cp.pushed[id] = true
+	}
+	return cp, true, nil
+}
+
+// beginSyncCheckpoint returns the checkpoint a tracker sync starts from,
+// after discarding any interrupted one when restart is set, and announces a
+// resume. A dry run neither resumes nor leaves a checkpoint behind.
+func beginSyncCheckpoint(ctx context.Context, s storage.Storage, tracker string, restart, dryRun bool) (*syncCheckpoint, error) {
+	if dryRun {
+		return &syncCheckpoint{Started: time.Now().UTC(), pushed: map[string]bool{}}, nil
+	}
+	if restart {
+		if err := s.DeleteConfig(ctx, syncCheckpointKey(tracker)); err != nil {
+			return nil, fmt.Errorf("discarding checkpoint: %w", err)
+		}
+	}
+	cp, resumed, err := loadSyncCheckpoint(ctx, s, tracker)
+	if err != nil {
+		return nil, err
+	}
+	if resumed && !jsonOutput {
+		fmt.Printf("→ Resuming sync interrupted at %s (%d issue(s) already pushed)\n",
+			cp.Started.Local().Format("2006-01-02 15:04"), len(cp.Pushed))
+	}
+	return cp, nil
+}
+
+// markPulled records that the pull phase finished.
+func (cp *syncCheckpoint) markPulled(ctx context.Context, s storage.Storage, refs map[string]bool) error {
+	cp.Pulled = true
+	cp.PulledRefs = cp.PulledRefs[:0]
+	for ref := range refs {
+		cp.PulledRefs = append(cp.PulledRefs, ref)
+	}
+	sort.Strings(cp.PulledRefs)
+	return cp.save(ctx, s)
+}
+
+// markPushed records that issue id has been pushed.
+func (cp *syncCheckpoint) markPushed(ctx context.Context, s storage.Storage, id string) error {
+	if cp.pushed[id] {
+		return nil
+	}
+	cp.pushed[id] = true
+	cp.Pushed = append(cp.Pushed, id)
+	return cp.save(ctx, s)
+}
+
+// wasPushed reports whether issue id was pushed before the interruption.
+func (cp *syncCheckpoint) wasPushed(id string) bool {
+	return cp.pushed[id]
+}
+
+func (cp *syncCheckpoint) save(ctx context.Context, s storage.Storage) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	// The sync itself may have been cancelled; the checkpoint must still
+	// be written so the next run can resume
+	return s.SetConfig(context.WithoutCancel(ctx), cp.key, string(data))
+}
+
+// clear deletes the checkpoint once the sync has completed.
+func (cp *syncCheckpoint) clear(ctx context.Context, s storage.Storage) error {
+	return s.DeleteConfig(ctx, cp.key)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSyncCheckpointResume(t *testing.T) {
+	testStore, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	cp, resumed, err := loadSyncCheckpoint(ctx, testStore, "gitlab")
+	if err != nil || resumed {
+		t.Fatalf("fresh checkpoint: resumed=%v err=%v", resumed, err)
+	}
+	if err := cp.markPulled(ctx, testStore, map[string]bool{"https://gl/i/2": true, "https://gl/i/1": true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A cancelled context must not stop the checkpoint from being saved
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	for _, id := range []string{"bd-1", "bd-2", "bd-1"} {
+		if err := cp.markPushed(cancelled, testStore, id); err != nil {
+			t.Fatalf("markPushed(%s): %v", id, err)
+		}
+	}
+
+	again, resumed, err := loadSyncCheckpoint(ctx, testStore, "gitlab")
+	if err != nil || !resumed {
+		t.Fatalf("reload: resumed=%v err=%v", resumed, err)
+	}
+	if !again.Started.Equal(cp.Started) {
+		t.Errorf("Started = %v, want %v", again.Started, cp.Started)
+	}
+	if !again.Pulled || strings.Join(again.PulledRefs, ",") != "https://gl/i/1,https://gl/i/2" {
+		t.Errorf("pull state = %v %v", again.Pulled, again.PulledRefs)
+	}
+	if len(again.Pushed) != 2 || !again.wasPushed("bd-1") || !again.wasPushed("bd-2") || again.wasPushed("bd-3") {
+		t.Errorf("Pushed = %v", again.Pushed)
+	}
+
+	if err := again.clear(ctx, testStore); err != nil {
+		t.Fatal(err)
+	}
+	if _, resumed, _ := loadSyncCheckpoint(ctx, testStore, "gitlab"); resumed {
+		t.Error("checkpoint survived clear")
+	}
+}
+
+func TestSyncCheckpointInvalid(t *testing.T) {
+	testStore, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := testStore.SetConfig(ctx, syncCheckpointKey("gitlab"), "{not json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := loadSyncCheckpoint(ctx, testStore, "gitlab"); err == nil || !strings.Contains(err.Error(), "--restart") {
+		t.Errorf("err = %v, want a hint to use --restart", err)
+	}
+}
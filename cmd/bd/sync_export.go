@@ -5,16 +5,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
 	"time"
 
+	"github.com/steveyegge/beads/internal/advisory"
+	"github.com/steveyegge/beads/internal/atomicfile"
+	"github.com/steveyegge/beads/internal/cc"
 	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/goals"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/repro"
 	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/subset"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/ui"
 	"github.com/steveyegge/beads/internal/validation"
+	"github.com/steveyegge/beads/internal/visibility"
+	"github.com/steveyegge/beads/internal/votes"
 )
 
 // ExportResult contains information needed to finalize an export after git commit.
@@ -187,6 +197,10 @@ func exportToJSONLDeferred(ctx context.Context, jsonlPath string) (*ExportResult
 		issue.Dependencies = allDeps[issue.ID]
 	}
 
+	// Labels and comments are read per issue, then each issue is written
+	progress := newProgress("Exporting", 3*len(issues))
+	defer progress.Finish()
+
 	// Populate labels for all issues
 	for _, issue := range issues {
 		labels, err := store.GetLabels(ctx, issue.ID)
@@ -194,6 +208,7 @@ func exportToJSONLDeferred(ctx context.Context, jsonlPath string) (*ExportResult
 			return nil, fmt.Errorf("failed to get labels for %s: %w", issue.ID, err)
 		}
 		issue.Labels = labels
+		progress.Add(1)
 	}
 
 	// Populate comments for all issues
@@ -203,38 +218,61 @@ func exportToJSONLDeferred(ctx context.Context, jsonlPath string) (*ExportResult
 			return nil, fmt.Errorf("failed to get comments for %s: %w", issue.ID, err)
 		}
 		issue.Comments = comments
+		progress.Add(1)
 	}
 
-	// Create temp file for atomic write
-	dir := filepath.Dir(jsonlPath)
-	base := filepath.Base(jsonlPath)
-	tempFile, err := os.CreateTemp(dir, base+".tmp.*")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	// Populate typed external refs
+	if err := refs.Populate(ctx, store, issues); err != nil {
+		return nil, err
 	}
-	tempPath := tempFile.Name()
-	defer func() {
-		_ = tempFile.Close()
-		_ = os.Remove(tempPath)
-	}()
 
-	// Write JSONL
-	encoder := json.NewEncoder(tempFile)
-	exportedIDs := make([]string, 0, len(issues))
-	for _, issue := range issues {
-		if err := encoder.Encode(issue); err != nil {
-			return nil, fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
-		}
-		exportedIDs = append(exportedIDs, issue.ID)
+	// Populate security advisories
+	if err := advisory.Populate(ctx, store, issues); err != nil {
+		return nil, err
+	}
+	if err := repro.Populate(ctx, store, issues); err != nil {
+		return nil, err
+	}
+	if err := votes.Populate(ctx, store, issues); err != nil {
+		return nil, err
+	}
+	if err := cc.Populate(ctx, store, issues); err != nil {
+		return nil, err
+	}
+	if err := goals.Populate(ctx, store, issues); err != nil {
+		return nil, err
+	}
+	// Private issues never reach the committed JSONL
+	issues, err = visibility.WithholdPrivate(ctx, store, issues)
+	if err != nil {
+		return nil, err
 	}
 
-	// Close temp file before rename (error checked implicitly by Rename success)
-	_ = tempFile.Close()
+	// Write subset stubs back in full
+	issues, err = subset.PreserveStubs(ctx, store, jsonlPath, issues)
+	if err != nil {
+		return nil, err
+	}
 
-	// Atomic replace
-	if err := os.Rename(tempPath, jsonlPath); err != nil {
+	// Write JSONL atomically (fsynced and journaled, see internal/atomicfile)
+	exportedIDs := make([]string, 0, len(issues))
+	_, err = atomicfile.Write(jsonlPath, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		for _, issue := range issues {
+			if err := encoder.Encode(issue); err != nil {
+				return fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
+			}
+			exportedIDs = append(exportedIDs, issue.ID)
+			progress.Add(1)
+		}
+		return nil
+	})
+	progress.Finish()
+	if err != nil {
 		return nil, fmt.Errorf("failed to replace JSONL file: %w", err)
 	}
+	recordOpLog(ctx, store, jsonlPath, exportedIDs)
+	recordStatusSnapshot(ctx, store)
 
 	// Set appropriate file permissions (0600: rw-------)
 	if err := os.Chmod(jsonlPath, 0600); err != nil {
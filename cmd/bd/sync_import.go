@@ -107,10 +107,15 @@ func importFromJSONLInline(ctx context.Context, jsonlPath string, renameOnImport
 	opts := ImportOptions{
 		RenameOnImport: renameOnImport,
 	}
+	recordPendingOpLog(ctx, store, jsonlPath)
+	progress := newProgress("Importing", len(allIssues))
+	opts.Progress = func(done, _ int) { progress.Set(done) }
 	result, err := importIssuesCore(ctx, dbPath, store, allIssues, opts)
+	progress.Finish()
 	if err != nil {
 		return fmt.Errorf("import failed: %w", err)
 	}
+	applyOpLog(ctx, store, jsonlPath)
 
 	// Update staleness metadata (same as import.go lines 386-411)
 	// This is critical: without this, CheckStaleness will still report stale
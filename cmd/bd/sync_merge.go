@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/types"
 )
 
 // MergeResult contains the outcome of a 3-way merge
@@ -48,10 +49,15 @@ var FieldRules = map[string]FieldMergeRule{
 	"design":      RuleLWW,
 	"issue_type":  RuleLWW,
 	"notes":       RuleLWW,
+	"visibility":  RuleLWW,
+	"key_results": RuleLWW,
 
 	// Set fields - union (no data loss)
 	"labels":       RuleUnion,
 	"dependencies": RuleUnion,
+	"refs":         RuleUnion,
+	"votes":        RuleUnion,
+	"cc":           RuleUnion,
 
 	// Append-only fields
 	"comments": RuleAppend,
@@ -61,6 +67,13 @@ var FieldRules = map[string]FieldMergeRule{
 // Returns a new issue with:
 // - Scalar fields: from the newer issue (LWW by updated_at, remote wins on tie)
 // - Labels: union of both
+// - Refs: union of both (by value)
+// - Security advisory: from the newer issue, like scalars
+// - Repro metadata: from the newer issue, like scalars
+// - Visibility: from the newer issue, like scalars
+// - Key results: from the newer issue, like scalars
+// - Votes: union of both (by voter; the newer issue's vote wins)
+// - CC: union of both
 // - Dependencies: union of both (by DependsOnID+Type)
 // - Comments: append from both (deduplicated by ID or content)
 func mergeFieldLevel(_base, local, remote *beads.Issue) *beads.Issue {
@@ -88,6 +101,19 @@ func mergeFieldLevel(_base, local, remote *beads.Issue) *beads.Issue {
 	// Union merge: Labels
 	merged.Labels = mergeLabels(local.Labels, remote.Labels)
 
+	// Union merge: Refs (by value)
+	merged.Refs = mergeRefs(local.Refs, remote.Refs)
+
+	// Union merge: Votes (by voter, newer issue first)
+	if localNewer {
+		merged.Votes = mergeVotes(local.Votes, remote.Votes)
+	} else {
+		merged.Votes = mergeVotes(remote.Votes, local.Votes)
+	}
+
+	// Union merge: CC (a set of names, like labels)
+	merged.CC = mergeLabels(local.CC, remote.CC)
+
 	// Union merge: Dependencies (by DependsOnID+Type key)
 	merged.Dependencies = mergeDependencies(local.Dependencies, remote.Dependencies)
 
@@ -123,6 +149,52 @@ func mergeLabels(local, remote []string) []string {
 	return result
 }
 
+// mergeRefs performs set union on typed external refs, keyed by value
+func mergeRefs(local, remote []*beads.Ref) []*beads.Ref {
+	seen := make(map[string]bool)
+	var result []*beads.Ref
+	for _, ref := range append(append([]*beads.Ref{}, local...), remote...) {
+		if ref != nil && !seen[ref.Value] {
+			seen[ref.Value] = true
+			result = append(result, ref)
+		}
+	}
+	return result
+}
+
+// mergeVotes performs set union on votes, keyed by voter. A voter's vote in
+// newer wins over theirs in older.
+func mergeVotes(newer, older []*beads.Vote) []*beads.Vote {
+	seen := make(map[string]bool)
+	var result []*beads.Vote
+	for _, v := range append(append([]*beads.Vote{}, newer...), older...) {
+		if v != nil && !seen[v.Voter] {
+			seen[v.Voter] = true
+			result = append(result, v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Voter < result[j].Voter })
+	return result
+}
+
+// voteKeys returns votes as voter:value strings for comparison
+func voteKeys(votes []*beads.Vote) []string {
+	keys := make([]string, 0, len(votes))
+	for _, v := range votes {
+		keys = append(keys, fmt.Sprintf("%s:%d", v.Voter, v.Value))
+	}
+	return keys
+}
+
+// refKeys returns refs as type:value strings for comparison
+func refKeys(refs []*beads.Ref) []string {
+	keys := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		keys = append(keys, string(ref.Type)+":"+ref.Value)
+	}
+	return keys
+}
+
 // dependencyKey creates a unique key for deduplication
 // Uses DependsOnID + Type as the identity (same target+type = same dependency)
 func dependencyKey(d *beads.Dependency) string {
@@ -426,6 +498,41 @@ func issueEqual(a, b *beads.Issue) bool {
 		return false
 	}
 
+	// Typed external refs
+	if !stringSliceEqual(refKeys(a.Refs), refKeys(b.Refs)) {
+		return false
+	}
+
+	// Security advisory
+	if !a.Security.Equal(b.Security) {
+		return false
+	}
+
+	// Repro metadata
+	if !a.Repro.Equal(b.Repro) {
+		return false
+	}
+
+	// Votes
+	if !stringSliceEqual(voteKeys(a.Votes), voteKeys(b.Votes)) {
+		return false
+	}
+
+	// CC
+	if !stringSliceEqual(a.CC, b.CC) {
+		return false
+	}
+
+	// Visibility
+	if a.Visibility != b.Visibility {
+		return false
+	}
+
+	// Key results
+	if !types.KeyResultsEqual(a.KeyResults, b.KeyResults) {
+		return false
+	}
+
 	return true
 }
 
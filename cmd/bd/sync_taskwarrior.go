@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/taskwarrior"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var syncTaskwarriorCmd = &cobra.Command{
+	Use:   "taskwarrior",
+	Short: "Mirror your assigned issues into Taskwarrior, both ways",
+	Long: `Mirror the issues assigned to you into Taskwarrior and bring back the
+changes you make there.
+
+Synced tasks live in one project (default: beads) and carry the issue ID in
+a "beadsid" UDA. Add it to ~/.taskrc to see it in reports:
+  uda.beadsid.type=string
+  uda.beadsid.label=Beads
+
+Configuration:
+  bd config set taskwarrior.project "work.beads"   # Project for synced tasks
+  bd config set taskwarrior.assignee "ana"         # Whose issues (default: you)
+  bd config set taskwarrior.bin "/usr/local/bin/task"
+
+Data mapping:
+  title                 description
+  status                pending; in_progress starts the task; blocked adds
+                        +blocked; closed completes it
+  priority              P0-P1 H, P2 M, P3 L, P4 none
+  labels                tags; a component:<name> label becomes the
+                        subproject <project>.<name>
+  due / defer date      due / wait
+
+New tasks added to the project in Taskwarrior become issues assigned to
+you. Completing or deleting a task closes its issue. Only changes since the
+last sync are transferred; when both sides changed, the newer one wins.
+
+Examples:
+  bd sync taskwarrior              # Pull then push
+  bd sync taskwarrior --pull       # Only bring back Taskwarrior changes
+  bd sync taskwarrior --dry-run`,
+	Run: runTaskwarriorSync,
+}
+
+// TaskwarriorSyncResult is the JSON output of bd sync taskwarrior.
+type TaskwarriorSyncResult struct {
+	Created  int      `json:"created"` // issues created from new tasks
+	Updated  int      `json:"updated"` // issues updated from tasks
+	Pushed   int      `json:"pushed"`  // tasks added or updated
+	LastSync string   `json:"last_sync,omitempty"`
+	DryRun   bool     `json:"dry_run,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// taskwarriorConfig holds the settings of bd sync taskwarrior.
+type taskwarriorConfig struct {
+	project  string
+	assignee string
+	lastSync *time.Time
+}
+
+func init() {
+	syncTaskwarriorCmd.Flags().Bool("pull", false, "Only bring Taskwarrior changes into beads")
+	syncTaskwarriorCmd.Flags().Bool("push", false, "Only mirror issues into Taskwarrior")
+	syncTaskwarriorCmd.Flags().Bool("dry-run", false, "Preview sync without making changes")
+	syncCmd.AddCommand(syncTaskwarriorCmd)
+}
+
+func runTaskwarriorSync(cmd *cobra.Command, args []string) {
+	pull, _ := cmd.Flags().GetBool("pull")
+	push, _ := cmd.Flags().GetBool("push")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if !pull && !push {
+		pull, push = true, true
+	}
+	if !dryRun {
+		CheckReadonly("sync taskwarrior")
+	}
+	if err := ensureStoreActive(); err != nil {
+		FatalErrorRespectJSON("database not available: %v", err)
+	}
+	ctx := rootCtx
+
+	result := &TaskwarriorSyncResult{DryRun: dryRun}
+	cfg := taskwarriorConfig{project: "beads", assignee: getActorWithGit()}
+	if v, _ := store.GetConfig(ctx, "taskwarrior.project"); v != "" {
+		cfg.project = v
+	}
+	if v, _ := store.GetConfig(ctx, "taskwarrior.assignee"); v != "" {
+		cfg.assignee = v
+	}
+	if raw, _ := store.GetConfig(ctx, "taskwarrior.last_sync"); raw != "" {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			cfg.lastSync = &t
+		} else {
+			result.Warnings = append(result.Warnings, "invalid taskwarrior.last_sync, doing a full sync")
+		}
+	}
+	bin := "task"
+	if v, _ := store.GetConfig(ctx, "taskwarrior.bin"); v != "" {
+		bin = v
+	}
+	client := taskwarrior.NewClient(bin)
+	syncStarted := time.Now()
+
+	tasks, err := client.Export(ctx, cfg.project)
+	if err != nil {
+		FatalErrorRespectJSON("reading Taskwarrior: %v", err)
+	}
+
+	// Tasks to import back into Taskwarrior: new tasks that now have an
+	// issue ID, and issues changed in beads
+	var outgoing []taskwarrior.Task
+	pulled := make(map[string]bool)
+	if pull {
+		linked, err := pullFromTaskwarrior(ctx, tasks, cfg, dryRun, result, pulled)
+		if err != nil {
+			FatalErrorRespectJSON("pulling from Taskwarrior: %v", err)
+		}
+		outgoing = append(outgoing, linked...)
+	}
+	if push {
+		changed, err := taskwarriorPushTasks(ctx, tasks, cfg, pulled)
+		if err != nil {
+			FatalErrorRespectJSON("pushing to Taskwarrior: %v", err)
+		}
+		result.Pushed = len(changed)
+		outgoing = append(outgoing, changed...)
+	}
+
+	if !dryRun {
+		if err := client.Import(ctx, outgoing); err != nil {
+			FatalErrorRespectJSON("writing to Taskwarrior: %v", err)
+		}
+		result.LastSync = syncStarted.UTC().Format(time.RFC3339)
+		if err := store.SetConfig(ctx, "taskwarrior.last_sync", result.LastSync); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to update last_sync: %v", err))
+		}
+		if result.Created > 0 || result.Updated > 0 {
+			markDirtyAndScheduleFlush()
+		}
+	}
+
+	if jsonOutput {
+		outputJSON(result)
+		return
+	}
+	verb := "Synced"
+	if dryRun {
+		verb = "Would sync"
+	}
+	fmt.Printf("%s with Taskwarrior project %s: %d issue(s) created, %d updated, %d task(s) pushed\n",
+		verb, cfg.project, result.Created, result.Updated, result.Pushed)
+	for _, w := range result.Warnings {
+		fmt.Printf("  - %s\n", w)
+	}
+}
+
+// pullFromTaskwarrior applies task changes to their issues and creates
+// issues for new pending tasks. It returns the new tasks with their issue
+// IDs set, to be written back. IDs of created and updated issues are added
+// to pulled.
+func pullFromTaskwarrior(ctx context.Context, tasks []taskwarrior.Task, cfg taskwarriorConfig, dryRun bool, result *TaskwarriorSyncResult, pulled map[string]bool) ([]taskwarrior.Task, error) {
+	var linked []taskwarrior.Task
+	for i := range tasks {
+		task := &tasks[i]
+		if task.BeadsID == "" {
+			if task.Status != "pending" && task.Status != "waiting" {
+				continue
+			}
+			result.Created++
+			if dryRun {
+				continue
+			}
+			issue := taskwarriorNewIssue(task, cfg)
+			if err := store.CreateIssue(ctx, issue, actor); err != nil {
+				return linked, fmt.Errorf("creating issue for %q: %w", task.Description, err)
+			}
+			for _, label := range issue.Labels {
+				if err := store.AddLabel(ctx, issue.ID, label, actor); err != nil {
+					return linked, fmt.Errorf("labeling %s: %w", issue.ID, err)
+				}
+			}
+			task.BeadsID = issue.ID
+			linked = append(linked, *task)
+			pulled[issue.ID] = true
+			continue
+		}
+
+		if cfg.lastSync != nil && task.Modified != nil && !task.Modified.After(*cfg.lastSync) {
+			continue
+		}
+		issue, err := store.GetIssue(ctx, task.BeadsID)
+		if err != nil {
+			return linked, fmt.Errorf("looking up %s: %w", task.BeadsID, err)
+		}
+		if issue == nil || issue.Status == types.StatusTombstone {
+			continue
+		}
+		// Both sides changed: the newer one wins
+		if task.Modified != nil && issue.UpdatedAt.After(task.Modified.Time) {
+			continue
+		}
+		updates, addLabels, removeLabels := taskwarriorChanges(task, issue, cfg.project)
+		if len(updates) == 0 && len(addLabels) == 0 && len(removeLabels) == 0 {
+			continue
+		}
+		result.Updated++
+		pulled[issue.ID] = true
+		if dryRun {
+			continue
+		}
+		if len(updates) > 0 {
+			if err := store.UpdateIssue(ctx, issue.ID, updates, actor); err != nil {
+				return linked, fmt.Errorf("updating %s: %w", issue.ID, err)
+			}
+		}
+		if err := applyLabelUpdates(ctx, store, issue.ID, actor, nil, addLabels, removeLabels); err != nil {
+			return linked, fmt.Errorf("updating labels of %s: %w", issue.ID, err)
+		}
+	}
+	return linked, nil
+}
+
+// taskwarriorChanges compares a task with the task bd would write for the
+// issue; only the attributes that differ were changed in Taskwarrior. This
+// keeps lossy mappings (P0 and P1 are both H) from rewriting the issue.
+func taskwarriorChanges(task *taskwarrior.Task, issue *types.Issue, project string) (updates map[string]interface{}, addLabels, removeLabels []string) {
+	mirror := taskwarrior.FromIssue(issue, task.UUID, project)
+	updates = make(map[string]interface{})
+
+	if task.Description != "" && task.Description != mirror.Description {
+		updates["title"] = task.Description
+	}
+	if status := taskwarrior.Status(task); status != taskwarrior.Status(&mirror) {
+		updates["status"] = string(status)
+	}
+	if task.Priority != mirror.Priority {
+		updates["priority"] = taskwarrior.BeadsPriority(task.Priority)
+	}
+	taskwarriorDateUpdate(updates, "due_at", task.Due, mirror.Due)
+	if task.Status != "completed" && task.Status != "deleted" {
+		taskwarriorDateUpdate(updates, "defer_until", task.Wait, mirror.Wait)
+	}
+
+	// Tags map to labels, except +blocked (the status) and the component,
+	// which is the subproject
+	tags := slices.DeleteFunc(slices.Clone(task.Tags), func(t string) bool { return t == "blocked" })
+	var labels []string
+	for _, label := range issue.Labels {
+		if !strings.HasPrefix(label, "component:") {
+			labels = append(labels, label)
+		}
+	}
+	if mirrorTags := slices.DeleteFunc(slices.Clone(mirror.Tags), func(t string) bool { return t == "blocked" }); !sameTags(tags, mirrorTags) {
+		addLabels, removeLabels = tagLabelChanges(tags, labels, taskwarrior.Tag)
+	}
+	if task.Project != mirror.Project {
+		for _, label := range issue.Labels {
+			if strings.HasPrefix(label, "component:") {
+				removeLabels = append(removeLabels, label)
+			}
+		}
+		if component, ok := strings.CutPrefix(task.Project, project+"."); ok && component != "" {
+			addLabels = append(addLabels, "component:"+component)
+		}
+	}
+	return updates, addLabels, removeLabels
+}
+
+func sameTags(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// taskwarriorDateUpdate records a date change; Taskwarrior dates have
+// second precision.
+func taskwarriorDateUpdate(updates map[string]interface{}, field string, task, mirror *taskwarrior.Time) {
+	switch {
+	case task == nil && mirror == nil:
+	case task == nil:
+		updates[field] = nil
+	case mirror == nil || !task.Truncate(time.Second).Equal(mirror.Truncate(time.Second)):
+		updates[field] = task.Time
+	}
+}
+
+// taskwarriorNewIssue builds an issue for a task created in Taskwarrior.
+func taskwarriorNewIssue(task *taskwarrior.Task, cfg taskwarriorConfig) *types.Issue {
+	issue := &types.Issue{
+		Title:     task.Description,
+		Status:    taskwarrior.Status(task),
+		Priority:  taskwarrior.BeadsPriority(task.Priority),
+		IssueType: types.TypeTask,
+		Assignee:  cfg.assignee,
+	}
+	if task.Priority == "" {
+		issue.Priority = 2
+	}
+	for _, tag := range task.Tags {
+		if tag != "blocked" {
+			issue.Labels = append(issue.Labels, tag)
+		}
+	}
+	if component, ok := strings.CutPrefix(task.Project, cfg.project+"."); ok && component != "" {
+		issue.Labels = append(issue.Labels, "component:"+component)
+	}
+	if task.Due != nil {
+		due := task.Due.Time
+		issue.DueAt = &due
+	}
+	if task.Wait != nil {
+		wait := task.Wait.Time
+		issue.DeferUntil = &wait
+	}
+	return issue
+}
+
+// taskwarriorPushTasks returns the tasks to write for the assignee's issues
+// that changed since the last sync. Closed issues are only pushed to
+// complete a task that already exists.
+func taskwarriorPushTasks(ctx context.Context, tasks []taskwarrior.Task, cfg taskwarriorConfig, pulled map[string]bool) ([]taskwarrior.Task, error) {
+	byIssue := make(map[string]*taskwarrior.Task)
+	for i := range tasks {
+		if tasks[i].BeadsID != "" {
+			byIssue[tasks[i].BeadsID] = &tasks[i]
+		}
+	}
+	assignee := cfg.assignee
+	issues, err := searchFullIssues(ctx, store, types.IssueFilter{Assignee: &assignee})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := store.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+
+	var out []taskwarrior.Task
+	for _, issue := range issues {
+		if pulled[issue.ID] {
+			continue
+		}
+		issue.Labels = labels[issue.ID]
+		existing := byIssue[issue.ID]
+		if existing == nil && issue.Status == types.StatusClosed {
+			continue
+		}
+		if existing != nil {
+			if cfg.lastSync != nil && !issue.UpdatedAt.After(*cfg.lastSync) {
+				continue
+			}
+			if existing.Modified != nil && existing.Modified.After(issue.UpdatedAt) {
+				continue
+			}
+		}
+		uuid := ""
+		if existing != nil {
+			// A deleted task stays deleted; don't resurrect it as pending
+			if existing.Status == "deleted" {
+				continue
+			}
+			uuid = existing.UUID
+		}
+		task := taskwarrior.FromIssue(issue, uuid, cfg.project)
+		if existing != nil {
+			task.Raw = existing.Raw
+			task.Entry = existing.Entry
+		}
+		out = append(out, task)
+	}
+	return out, nil
+}
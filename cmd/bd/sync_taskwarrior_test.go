@@ -0,0 +1,36 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/taskwarrior"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestTaskwarriorChanges(t *testing.T) {
+	issue := &types.Issue{ID: "bd-1", Title: "Fix login", Status: types.StatusOpen, Priority: 0,
+		Labels: []string{"auth", "component:api"}}
+
+	// An untouched mirror changes nothing, even though P0 exported as H
+	task := taskwarrior.FromIssue(issue, "", "beads")
+	updates, add, remove := taskwarriorChanges(&task, issue, "beads")
+	if len(updates) != 0 || len(add) != 0 || len(remove) != 0 {
+		t.Fatalf("unchanged task: updates=%v add=%v remove=%v", updates, add, remove)
+	}
+
+	task.Status = "completed"
+	task.Priority = "L"
+	task.Tags = []string{"urgent"}
+	task.Project = "beads.web"
+	updates, add, remove = taskwarriorChanges(&task, issue, "beads")
+	if updates["status"] != string(types.StatusClosed) || updates["priority"] != 3 {
+		t.Errorf("updates = %v", updates)
+	}
+	if !slices.Equal(add, []string{"urgent", "component:web"}) {
+		t.Errorf("add = %v", add)
+	}
+	if !slices.Equal(remove, []string{"auth", "component:api"}) {
+		t.Errorf("remove = %v", remove)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/steveyegge/beads/internal/apitoken"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:     "token",
+	GroupID: "setup",
+	Short:   "Manage credentials for the daemon's HTTP API",
+	Long: `Create, list and revoke tokens for the daemon's HTTP API (api.listen), so
+each agent or integration gets its own credential that can be revoked on
+its own.
+
+A token's secret is shown once, when it is created; only its hash is
+stored. Clients send it as "Authorization: Bearer <token>". Tokens with
+the read scope see internal issues; once any token is active, requests
+without one see public issues only.
+
+Scopes:
+  read            See internal issues
+  write:issues    Create, update and close issues (GraphQL mutations)
+  write:comments  Comment on issues
+
+Changes are made as the token's name and only reach issues the token can
+see; no other credential can change data through the API.
+
+Names are unique among tokens that haven't been revoked; revoke a token
+before reusing its name.
+
+Tokens are local to this database and are never exported.
+
+Examples:
+  bd token create ci-agent --scope read --expires 30d
+  bd token create triage-bot --scope read,write:issues,write:comments
+  bd token list
+  bd token revoke ci-agent`,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an API token and print its secret",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("token create")
+		scopeFlag, _ := cmd.Flags().GetString("scope")
+		scopes, err := apitoken.ParseScopes(scopeFlag)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		var ttl time.Duration
+		if expires, _ := cmd.Flags().GetString("expires"); expires != "" {
+			if ttl, err = parseDurationString(expires); err != nil || ttl <= 0 {
+				FatalErrorCode(ErrCodeUsage, "invalid --expires %q (use e.g. 12h, 30d or 1w)", expires)
+			}
+		}
+
+		ts := mustTokenStore()
+		token, secret, err := apitoken.Create(rootCtx, ts, args[0], scopes, ttl, actor)
+		if err != nil {
+			FatalErrorRespectJSON("failed to create token: %v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(struct {
+				*types.APIToken
+				Secret string `json:"secret"`
+			}{token, secret})
+			return
+		}
+		fmt.Printf("%s Created token %s (%s) with scopes %s\n", ui.RenderPass("✓"), token.Name, token.ID, strings.Join(token.Scopes, ","))
+		if token.ExpiresAt != nil {
+			fmt.Printf("  Expires %s\n", token.ExpiresAt.Local().Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("\n  %s\n\n", secret)
+		fmt.Println(ui.RenderWarn("Copy it now: the secret is not stored and can't be shown again."))
+	},
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API tokens",
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		tokens, err := mustTokenStore().ListAPITokens(rootCtx)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		now := time.Now()
+		shown := make([]*types.APIToken, 0, len(tokens))
+		for _, t := range tokens {
+			if all || t.Active(now) {
+				shown = append(shown, t)
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(shown)
+			return
+		}
+		if len(shown) == 0 {
+			fmt.Println("No API tokens (create one with 'bd token create <name> --scope read')")
+			return
+		}
+		for _, t := range shown {
+			used := "never used"
+			if t.LastUsedAt != nil {
+				used = "last used " + formatTimeAgo(*t.LastUsedAt)
+			}
+			fmt.Printf("%-12s %-20s %-28s %s, %s\n", t.ID, t.Name, strings.Join(t.Scopes, ","), tokenState(t, now), used)
+		}
+	},
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <id|name>",
+	Short: "Revoke an API token",
+	Long: `Revoke an API token by ID, or by name the token with that name that
+hasn't been revoked yet. Requests carrying a revoked token are refused.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("token revoke")
+		if err := mustTokenStore().RevokeAPIToken(rootCtx, args[0], time.Now().UTC()); err != nil {
+			FatalErrorRespectJSON("failed to revoke %s: %v", args[0], err)
+		}
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"token": args[0], "revoked": true})
+			return
+		}
+		fmt.Printf("%s Revoked %s\n", ui.RenderPass("✓"), args[0])
+	},
+}
+
+// tokenState describes whether a token can be used, for bd token list.
+func tokenState(t *types.APIToken, now time.Time) string {
+	switch {
+	case t.RevokedAt != nil:
+		return ui.RenderMuted("revoked")
+	case t.ExpiresAt != nil && !now.Before(*t.ExpiresAt):
+		return ui.RenderMuted("expired")
+	case t.ExpiresAt != nil:
+		return "expires " + t.ExpiresAt.Local().Format("2006-01-02")
+	default:
+		return "no expiry"
+	}
+}
+
+func mustTokenStore() apitoken.Store {
+	if err := ensureStoreActive(); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	ts, err := apitoken.For(store)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	return ts
+}
+
+func init() {
+	tokenCreateCmd.Flags().String("scope", apitoken.ScopeRead, "Comma-separated scopes: read, write:issues, write:comments")
+	tokenCreateCmd.Flags().String("expires", "", "Lifetime, e.g. 12h, 30d or 1w (default: never)")
+	tokenListCmd.Flags().Bool("all", false, "Include revoked and expired tokens")
+	tokenCmd.AddCommand(tokenCreateCmd, tokenListCmd, tokenRevokeCmd)
+	rootCmd.AddCommand(tokenCmd)
+}
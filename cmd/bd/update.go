@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/beads/internal/hooks"
+	"github.com/steveyegge/beads/internal/i18n"
 	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/timeparsing"
 	"github.com/steveyegge/beads/internal/types"
@@ -28,6 +29,12 @@ create, update, show, or close operation).`,
 	Run: func(cmd *cobra.Command, args []string) {
 		CheckReadonly("update")
 
+		// Bulk selection by query expression (bd query)
+		args, ok := appendQueryMatches(cmd, args, "")
+		if !ok {
+			return
+		}
+
 		// If no IDs provided, use last touched issue
 		if len(args) == 0 {
 			lastTouched := GetLastTouchedID()
@@ -166,8 +173,11 @@ create, update, show, or close operation).`,
 			}
 		}
 
+		addCCUpdates(cmd.Flags(), updates)
+
 		// Get claim flag
 		claimFlag, _ := cmd.Flags().GetBool("claim")
+		force, _ := cmd.Flags().GetBool("force")
 
 		if len(updates) == 0 && !claimFlag {
 			fmt.Println("No updates specified")
@@ -175,6 +185,7 @@ create, update, show, or close operation).`,
 		}
 
 		ctx := rootCtx
+		warnValidationConfig()
 
 		// Resolve partial IDs first, checking for cross-rig routing
 		var resolvedIDs []string
@@ -206,7 +217,7 @@ create, update, show, or close operation).`,
 			updatedIssues := []*types.Issue{}
 			var firstUpdatedID string // Track first successful update for last-touched
 			for _, id := range resolvedIDs {
-				updateArgs := &rpc.UpdateArgs{ID: id}
+				updateArgs := &rpc.UpdateArgs{ID: id, Force: force}
 
 				// Map updates to RPC args
 				if status, ok := updates["status"].(string); ok {
@@ -287,6 +298,10 @@ create, update, show, or close operation).`,
 					fmt.Fprintf(os.Stderr, "Error updating %s: %v\n", id, err)
 					continue
 				}
+				if err := updateCCDirect(ctx, id, updates); err != nil {
+					fmt.Fprintf(os.Stderr, "Error updating cc for %s: %v\n", id, err)
+					continue
+				}
 
 				var issue types.Issue
 				if err := json.Unmarshal(resp.Data, &issue); err == nil {
@@ -299,7 +314,7 @@ create, update, show, or close operation).`,
 					}
 				}
 				if !jsonOutput {
-					fmt.Printf("%s Updated issue: %s\n", ui.RenderPass("✓"), id)
+					fmt.Printf("%s %s\n", ui.RenderPass("✓"), i18n.T("Updated issue: %s", id))
 				}
 
 				// Track first successful update for last-touched
@@ -334,6 +349,13 @@ create, update, show, or close operation).`,
 					continue
 				}
 
+				ctx, err := checkUpdateRules(ctx, issueStore, issue, updates, claimFlag, force)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s\n", err)
+					result.Close()
+					continue
+				}
+
 				// Handle claim operation atomically
 				if claimFlag {
 					if issue.Assignee != "" {
@@ -355,7 +377,7 @@ create, update, show, or close operation).`,
 				// Apply regular field updates if any
 				regularUpdates := make(map[string]interface{})
 				for k, v := range updates {
-					if k != "add_labels" && k != "remove_labels" && k != "set_labels" && k != "parent" {
+					if k != "add_labels" && k != "remove_labels" && k != "set_labels" && k != "parent" && !isCCUpdate(k) {
 						regularUpdates[k] = v
 					}
 				}
@@ -386,6 +408,12 @@ create, update, show, or close operation).`,
 					}
 				}
 
+				if err := applyCCUpdates(ctx, issueStore, result.ResolvedID, updates); err != nil {
+					fmt.Fprintf(os.Stderr, "Error updating cc for %s: %v\n", id, err)
+					result.Close()
+					continue
+				}
+
 				// Run update hook
 				updatedIssue, _ := issueStore.GetIssue(ctx, result.ResolvedID)
 				if updatedIssue != nil && hookRunner != nil {
@@ -397,7 +425,7 @@ create, update, show, or close operation).`,
 						updatedIssues = append(updatedIssues, updatedIssue)
 					}
 				} else {
-					fmt.Printf("%s Updated issue: %s\n", ui.RenderPass("✓"), result.ResolvedID)
+					fmt.Printf("%s %s\n", ui.RenderPass("✓"), i18n.T("Updated issue: %s", result.ResolvedID))
 				}
 
 				if firstUpdatedID == "" {
@@ -446,6 +474,13 @@ create, update, show, or close operation).`,
 				continue
 			}
 
+			ctx, err := checkUpdateRules(ctx, issueStore, issue, updates, claimFlag, force)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", err)
+				result.Close()
+				continue
+			}
+
 			// Handle claim operation atomically
 			if claimFlag {
 				// Check if already claimed (has non-empty assignee)
@@ -469,7 +504,7 @@ create, update, show, or close operation).`,
 			// Apply regular field updates if any
 			regularUpdates := make(map[string]interface{})
 			for k, v := range updates {
-				if k != "add_labels" && k != "remove_labels" && k != "set_labels" && k != "parent" {
+				if k != "add_labels" && k != "remove_labels" && k != "set_labels" && k != "parent" && !isCCUpdate(k) {
 					regularUpdates[k] = v
 				}
 			}
@@ -500,6 +535,12 @@ create, update, show, or close operation).`,
 				}
 			}
 
+			if err := applyCCUpdates(ctx, issueStore, result.ResolvedID, updates); err != nil {
+				fmt.Fprintf(os.Stderr, "Error updating cc for %s: %v\n", id, err)
+				result.Close()
+				continue
+			}
+
 			// Handle parent reparenting
 			if newParent, ok := updates["parent"].(string); ok {
 				// Validate new parent exists (unless empty string to remove parent)
@@ -559,7 +600,7 @@ create, update, show, or close operation).`,
 					updatedIssues = append(updatedIssues, updatedIssue)
 				}
 			} else {
-				fmt.Printf("%s Updated issue: %s\n", ui.RenderPass("✓"), result.ResolvedID)
+				fmt.Printf("%s %s\n", ui.RenderPass("✓"), i18n.T("Updated issue: %s", result.ResolvedID))
 			}
 
 			// Track first successful update for last-touched
@@ -596,10 +637,14 @@ func init() {
 	updateCmd.Flags().IntP("estimate", "e", 0, "Time estimate in minutes (e.g., 60 for 1 hour)")
 	updateCmd.Flags().StringSlice("add-label", nil, "Add labels (repeatable)")
 	updateCmd.Flags().StringSlice("remove-label", nil, "Remove labels (repeatable)")
+	updateCmd.Flags().StringSlice("cc", nil, "Replace the stakeholders kept informed (comma-separated; \"\" clears)")
+	updateCmd.Flags().StringSlice("add-cc", nil, "Add stakeholders to keep informed (repeatable)")
+	updateCmd.Flags().StringSlice("remove-cc", nil, "Remove stakeholders (repeatable)")
 	updateCmd.Flags().StringSlice("set-labels", nil, "Set labels, replacing all existing (repeatable)")
 	updateCmd.Flags().String("parent", "", "New parent issue ID (reparents the issue, use empty string to remove parent)")
 	updateCmd.Flags().Bool("claim", false, "Atomically claim the issue (sets assignee to you, status to in_progress; fails if already claimed)")
 	updateCmd.Flags().String("session", "", "Claude Code session ID for status=closed (or set CLAUDE_SESSION_ID env var)")
+	updateCmd.Flags().Bool("force", false, "Apply changes that break validation rules (validation.* in config.yaml)")
 	// Time-based scheduling flags (GH#820)
 	// Examples:
 	//   --due=+6h           Due in 6 hours
@@ -613,6 +658,7 @@ func init() {
 	updateCmd.Flags().String("defer", "", "Defer until date (empty to clear). Issue hidden from bd ready until then")
 	// Gate fields (bd-z6kw)
 	updateCmd.Flags().String("await-id", "", "Set gate await_id (e.g., GitHub run ID for gh:run gates)")
+	updateCmd.Flags().String("query", "", "Also update every issue matching this query expression (see 'bd query --help')")
 	updateCmd.ValidArgsFunction = issueIDCompletion
 	rootCmd.AddCommand(updateCmd)
 }
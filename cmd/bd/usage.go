@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/audit"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// usageKind is the interactions log entry kind written by bd usage add.
+const usageKind = "usage"
+
+// usageNoGroup names the group of usage whose issue has no value for the
+// grouping field (no labels, unassigned, unknown model, ...).
+const usageNoGroup = "(none)"
+
+// usageGroupFields are the accepted values of bd stats cost --group-by.
+var usageGroupFields = []string{"issue", "label", "type", "assignee", "agent", "model", "session"}
+
+// UsageRecord is one report of resources spent on an issue.
+type UsageRecord struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	IssueID   string    `json:"issue_id"`
+	Agent     string    `json:"agent,omitempty"`
+	Session   string    `json:"session,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	Tokens    int64     `json:"tokens"`
+	CostUSD   float64   `json:"cost_usd"`
+}
+
+// CostGroup is the usage attributed to one group in bd stats cost.
+type CostGroup struct {
+	Group   string  `json:"group"`
+	Issues  int     `json:"issues"`
+	Records int     `json:"records"`
+	Tokens  int64   `json:"tokens"`
+	CostUSD float64 `json:"cost_usd"`
+}
+
+// CostReport is the output of bd stats cost. With --group-by label, an
+// issue counts toward each of its labels, so groups can add up to more
+// than Total.
+type CostReport struct {
+	GroupBy string       `json:"group_by"`
+	Groups  []*CostGroup `json:"groups"`
+	Total   CostGroup    `json:"total"`
+}
+
+var usageCmd = &cobra.Command{
+	Use:     "usage",
+	GroupID: "advanced",
+	Short:   "Record tokens and cost spent on issues",
+	Long: `Record the resources agents spend working on issues, so the cost of
+autonomous development can be attributed to work items.
+
+Usage is kept in .beads/interactions.jsonl with the other audit entries.
+Any entry there that names an issue and carries tokens or cost_usd counts,
+so bd audit record can report usage along with the LLM call itself.
+
+See also: bd stats cost`,
+}
+
+var usageAddCmd = &cobra.Command{
+	Use:   "add <id>",
+	Short: "Report tokens and cost spent on an issue",
+	Long: `Report tokens and cost spent on an issue. At least one of --tokens and
+--cost is required; cost is in US dollars.
+
+The usage is attributed to the current actor and, if one is active, to
+the agent session (bd session).`,
+	Example: `  bd usage add bd-12 --tokens 45231 --cost 0.87
+  bd usage add bd-12 --tokens 1200 --model claude-sonnet-4`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("usage add")
+		if !cmd.Flags().Changed("tokens") && !cmd.Flags().Changed("cost") {
+			FatalErrorCode(ErrCodeUsage, "at least one of --tokens and --cost is required")
+		}
+		tokens, _ := cmd.Flags().GetInt64("tokens")
+		cost, _ := cmd.Flags().GetFloat64("cost")
+		model, _ := cmd.Flags().GetString("model")
+		if tokens < 0 || cost < 0 {
+			FatalErrorCode(ErrCodeUsage, "--tokens and --cost must not be negative")
+		}
+
+		issueID := args[0]
+		if daemonClient != nil {
+			resp, err := daemonClient.ResolveID(&rpc.ResolveIDArgs{ID: issueID})
+			if err != nil {
+				FatalErrorRespectJSON("resolving ID %s: %v", issueID, err)
+			}
+			if err := json.Unmarshal(resp.Data, &issueID); err != nil {
+				FatalErrorRespectJSON("unmarshaling resolved ID: %v", err)
+			}
+		} else {
+			if err := ensureStoreActive(); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			fullID, err := utils.ResolvePartialID(rootCtx, store, issueID)
+			if err != nil {
+				FatalErrorRespectJSON("resolving %s: %v", issueID, err)
+			}
+			issueID = fullID
+		}
+
+		e := &audit.Entry{Kind: usageKind, Actor: getActorWithGit(), IssueID: issueID, Model: model}
+		if cmd.Flags().Changed("tokens") {
+			e.Tokens = &tokens
+		}
+		if cmd.Flags().Changed("cost") {
+			e.CostUSD = &cost
+		}
+		if s := currentSession(); s != nil {
+			e.Session = s.ID
+		}
+		id, err := audit.Append(e)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(usageRecordFromEntry(e))
+			return
+		}
+		var spent []string
+		if e.Tokens != nil {
+			spent = append(spent, formatNumber(int(tokens))+" tokens")
+		}
+		if e.CostUSD != nil {
+			spent = append(spent, fmt.Sprintf("$%.2f", cost))
+		}
+		fmt.Printf("%s Recorded %s on %s (%s)\n", ui.RenderPass("✓"), strings.Join(spent, ", "), issueID, id)
+	},
+}
+
+var usageExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export usage records as CSV or JSON",
+	Long: `Export every usage record, oldest first, for analysis elsewhere.
+
+CSV columns: id, created_at, issue_id, agent, session, model, tokens, cost_usd.`,
+	Example: `  bd usage export --format csv -o usage.csv
+  bd usage export --format json --since 2026-01-01`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		since, err := parseUsageSince(cmd)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		records, err := loadUsageRecords(since)
+		if err != nil {
+			FatalError("%v", err)
+		}
+
+		var buf bytes.Buffer
+		switch format {
+		case "csv":
+			if err := writeUsageCSV(&buf, records); err != nil {
+				FatalError("encoding usage: %v", err)
+			}
+		case "json":
+			enc := json.NewEncoder(&buf)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(records); err != nil {
+				FatalError("encoding usage: %v", err)
+			}
+		default:
+			FatalErrorCode(ErrCodeUsage, "unsupported format %q (supported: csv, json)", format)
+		}
+
+		if output == "" || output == "-" {
+			_, _ = os.Stdout.Write(buf.Bytes())
+			return
+		}
+		if err := os.WriteFile(output, buf.Bytes(), 0o644); err != nil { // #nosec G306 -- usage holds no secrets
+			FatalError("writing %s: %v", output, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s Exported %d usage record(s) to %s\n", ui.RenderPass("✓"), len(records), output)
+	},
+}
+
+var statusCostCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Show tokens and cost spent, grouped by issue, label, agent, ...",
+	Long: `Show the tokens and cost reported with bd usage add, grouped by issue,
+label, type, assignee, agent, model or session.
+
+With --group-by label, an issue's usage counts toward each of its labels,
+so the groups can add up to more than the total. Usage on issues with no
+value for the grouping field is shown as (none).`,
+	Example: `  bd stats cost
+  bd stats cost --group-by label
+  bd stats cost --group-by agent --since 2026-01-01 --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		if !containsString(usageGroupFields, groupBy) {
+			FatalErrorCode(ErrCodeUsage, "invalid --group-by %q (valid: %s)", groupBy, strings.Join(usageGroupFields, ", "))
+		}
+		since, err := parseUsageSince(cmd)
+		if err != nil {
+			FatalErrorCode(ErrCodeUsage, "%v", err)
+		}
+		if err := ensureDirectMode("stats cost requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		records, err := loadUsageRecords(since)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		var ids []string
+		for _, r := range records {
+			ids = append(ids, r.IssueID)
+		}
+		ids = uniqueStrings(ids)
+		issues := make(map[string]*types.Issue)
+		if len(ids) > 0 {
+			found, err := store.SearchIssues(ctx, "", types.IssueFilter{IDs: ids})
+			if err != nil {
+				FatalErrorRespectJSON("loading issues: %v", err)
+			}
+			labels, err := store.GetLabelsForIssues(ctx, ids)
+			if err != nil {
+				FatalErrorRespectJSON("loading labels: %v", err)
+			}
+			for _, issue := range found {
+				issue.Labels = labels[issue.ID]
+				issues[issue.ID] = issue
+			}
+		}
+
+		report := buildCostReport(records, issues, groupBy)
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		displayCostReport(report, issues)
+	},
+}
+
+// parseUsageSince reads the --since flag as a date (YYYY-MM-DD) or RFC3339 time.
+func parseUsageSince(cmd *cobra.Command) (time.Time, error) {
+	s, _ := cmd.Flags().GetString("since")
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q (use YYYY-MM-DD or RFC3339)", s)
+	}
+	return t, nil
+}
+
+// usageRecordFromEntry returns the usage carried by an interactions log
+// entry, or nil if it names no issue or carries neither tokens nor cost.
+func usageRecordFromEntry(e *audit.Entry) *UsageRecord {
+	if e.IssueID == "" || (e.Tokens == nil && e.CostUSD == nil) {
+		return nil
+	}
+	r := &UsageRecord{ID: e.ID, CreatedAt: e.CreatedAt, IssueID: e.IssueID, Agent: e.Actor, Session: e.Session, Model: e.Model}
+	if e.Tokens != nil {
+		r.Tokens = *e.Tokens
+	}
+	if e.CostUSD != nil {
+		r.CostUSD = *e.CostUSD
+	}
+	return r
+}
+
+// loadUsageRecords reads the usage records created at or after since
+// (zero for all) from the interactions log, oldest first.
+func loadUsageRecords(since time.Time) ([]*UsageRecord, error) {
+	path, err := audit.Path()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := audit.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	records := []*UsageRecord{}
+	for _, e := range entries {
+		if r := usageRecordFromEntry(e); r != nil && !r.CreatedAt.Before(since) {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// writeUsageCSV writes records as CSV with a header row.
+func writeUsageCSV(buf *bytes.Buffer, records []*UsageRecord) error {
+	w := csv.NewWriter(buf)
+	_ = w.Write([]string{"id", "created_at", "issue_id", "agent", "session", "model", "tokens", "cost_usd"})
+	for _, r := range records {
+		_ = w.Write([]string{
+			r.ID,
+			r.CreatedAt.UTC().Format(time.RFC3339),
+			r.IssueID,
+			r.Agent,
+			r.Session,
+			r.Model,
+			strconv.FormatInt(r.Tokens, 10),
+			strconv.FormatFloat(r.CostUSD, 'f', -1, 64),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// usageGroupKeys returns the groups a record counts toward. issue is nil
+// if the issue no longer exists.
+func usageGroupKeys(r *UsageRecord, issue *types.Issue, groupBy string) []string {
+	var keys []string
+	switch groupBy {
+	case "issue":
+		keys = []string{r.IssueID}
+	case "agent":
+		keys = []string{r.Agent}
+	case "model":
+		keys = []string{r.Model}
+	case "session":
+		keys = []string{r.Session}
+	case "label":
+		if issue != nil {
+			keys = uniqueStrings(issue.Labels)
+		}
+	case "type":
+		if issue != nil {
+			keys = []string{string(issue.IssueType)}
+		}
+	case "assignee":
+		if issue != nil {
+			keys = []string{issue.Assignee}
+		}
+	}
+	if len(keys) == 0 || (len(keys) == 1 && keys[0] == "") {
+		return []string{usageNoGroup}
+	}
+	return keys
+}
+
+// buildCostReport totals records per group, most expensive first.
+func buildCostReport(records []*UsageRecord, issues map[string]*types.Issue, groupBy string) *CostReport {
+	report := &CostReport{GroupBy: groupBy, Groups: []*CostGroup{}, Total: CostGroup{Group: "total"}}
+	groups := make(map[string]*CostGroup)
+	groupIssues := make(map[string]map[string]bool)
+	allIssues := make(map[string]bool)
+	for _, r := range records {
+		for _, key := range usageGroupKeys(r, issues[r.IssueID], groupBy) {
+			g := groups[key]
+			if g == nil {
+				g = &CostGroup{Group: key}
+				groups[key] = g
+				groupIssues[key] = make(map[string]bool)
+				report.Groups = append(report.Groups, g)
+			}
+			g.Records++
+			g.Tokens += r.Tokens
+			g.CostUSD += r.CostUSD
+			groupIssues[key][r.IssueID] = true
+		}
+		report.Total.Records++
+		report.Total.Tokens += r.Tokens
+		report.Total.CostUSD += r.CostUSD
+		allIssues[r.IssueID] = true
+	}
+	for key, g := range groups {
+		g.Issues = len(groupIssues[key])
+	}
+	report.Total.Issues = len(allIssues)
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.CostUSD != b.CostUSD {
+			return a.CostUSD > b.CostUSD
+		}
+		if a.Tokens != b.Tokens {
+			return a.Tokens > b.Tokens
+		}
+		return a.Group < b.Group
+	})
+	return report
+}
+
+// displayCostReport prints the report as a table. issues supplies titles
+// when grouping by issue.
+func displayCostReport(report *CostReport, issues map[string]*types.Issue) {
+	if report.Total.Records == 0 {
+		fmt.Println("No usage recorded. Report some with 'bd usage add <id> --tokens N --cost X'.")
+		return
+	}
+	fmt.Printf("\n%s Cost by %s (%d records on %d issues)\n\n", ui.RenderAccent("💰"), report.GroupBy, report.Total.Records, report.Total.Issues)
+	fmt.Printf("  %-32s %7s %14s %10s\n", strings.ToUpper(report.GroupBy), "ISSUES", "TOKENS", "COST")
+	for _, g := range report.Groups {
+		name := g.Group
+		if report.GroupBy == "issue" {
+			if issue := issues[g.Group]; issue != nil {
+				name += " " + issue.Title
+			}
+		}
+		fmt.Printf("  %-32s %7d %14s %10s\n", truncateTitle(name, 32), g.Issues, formatNumber(int(g.Tokens)), fmt.Sprintf("$%.2f", g.CostUSD))
+	}
+	fmt.Printf("  %-32s %7d %14s %10s\n", "TOTAL", report.Total.Issues, formatNumber(int(report.Total.Tokens)), fmt.Sprintf("$%.2f", report.Total.CostUSD))
+	if report.GroupBy == "label" {
+		fmt.Println()
+		fmt.Println(ui.RenderMuted("  Issues with several labels count toward each of them."))
+	}
+	fmt.Println()
+}
+
+func init() {
+	usageAddCmd.Flags().Int64("tokens", 0, "Tokens spent")
+	usageAddCmd.Flags().Float64("cost", 0, "Cost in US dollars")
+	usageAddCmd.Flags().String("model", "", "Model the tokens were spent on")
+	usageExportCmd.Flags().String("format", "csv", "Export format (csv, json)")
+	usageExportCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+	usageExportCmd.Flags().String("since", "", "Only export usage recorded on or after this date (YYYY-MM-DD or RFC3339)")
+	usageCmd.AddCommand(usageAddCmd, usageExportCmd)
+	rootCmd.AddCommand(usageCmd)
+
+	statusCostCmd.Flags().String("group-by", "issue", "Group by: "+strings.Join(usageGroupFields, ", "))
+	statusCostCmd.Flags().String("since", "", "Only count usage recorded on or after this date (YYYY-MM-DD or RFC3339)")
+	statusCmd.AddCommand(statusCostCmd)
+}
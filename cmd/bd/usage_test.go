@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/audit"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestUsageRecordFromEntry(t *testing.T) {
+	tokens := int64(100)
+	cost := 0.5
+	if r := usageRecordFromEntry(&audit.Entry{Kind: "llm_call", IssueID: "bd-1", Tokens: &tokens}); r == nil || r.Tokens != 100 || r.CostUSD != 0 {
+		t.Errorf("llm_call with tokens = %+v", r)
+	}
+	if r := usageRecordFromEntry(&audit.Entry{Kind: usageKind, CostUSD: &cost}); r != nil {
+		t.Errorf("usage without issue = %+v, want nil", r)
+	}
+	if r := usageRecordFromEntry(&audit.Entry{Kind: "label", IssueID: "bd-1"}); r != nil {
+		t.Errorf("entry without usage = %+v, want nil", r)
+	}
+}
+
+func TestBuildCostReport(t *testing.T) {
+	issues := map[string]*types.Issue{
+		"bd-1": {ID: "bd-1", IssueType: types.TypeBug, Assignee: "alice", Labels: []string{"backend", "api"}},
+		"bd-2": {ID: "bd-2", IssueType: types.TypeTask, Labels: []string{"backend"}},
+		"bd-3": {ID: "bd-3", IssueType: types.TypeTask},
+	}
+	records := []*UsageRecord{
+		{IssueID: "bd-1", Agent: "agent-a", Tokens: 1000, CostUSD: 1.0},
+		{IssueID: "bd-1", Agent: "agent-b", Tokens: 500, CostUSD: 0.5},
+		{IssueID: "bd-2", Agent: "agent-a", Tokens: 200, CostUSD: 0.25},
+		{IssueID: "bd-3", Tokens: 50},
+		{IssueID: "bd-gone", Agent: "agent-a", CostUSD: 2},
+	}
+
+	byLabel := buildCostReport(records, issues, "label")
+	if byLabel.Total.Records != 5 || byLabel.Total.Issues != 4 || byLabel.Total.Tokens != 1750 || byLabel.Total.CostUSD != 3.75 {
+		t.Fatalf("total = %+v", byLabel.Total)
+	}
+	var got []string
+	for _, g := range byLabel.Groups {
+		got = append(got, g.Group)
+	}
+	// (none) holds bd-3 and the deleted issue; most expensive first
+	if strings.Join(got, ",") != "(none),backend,api" {
+		t.Errorf("label groups = %v", got)
+	}
+	if g := byLabel.Groups[1]; g.Issues != 2 || g.Records != 3 || g.Tokens != 1700 || g.CostUSD != 1.75 {
+		t.Errorf("backend = %+v", g)
+	}
+
+	byAgent := buildCostReport(records, issues, "agent")
+	if g := byAgent.Groups[0]; g.Group != "agent-a" || g.Issues != 3 || g.CostUSD != 3.25 {
+		t.Errorf("agent-a = %+v", g)
+	}
+	byAssignee := buildCostReport(records, issues, "assignee")
+	if len(byAssignee.Groups) != 2 || byAssignee.Groups[0].Group != usageNoGroup {
+		t.Errorf("assignee groups = %+v", byAssignee.Groups)
+	}
+}
+
+func TestWriteUsageCSV(t *testing.T) {
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	if err := writeUsageCSV(&buf, []*UsageRecord{{ID: "int-1", CreatedAt: at, IssueID: "bd-1", Agent: "a", Model: "m", Tokens: 45231, CostUSD: 0.87}}); err != nil {
+		t.Fatal(err)
+	}
+	want := "id,created_at,issue_id,agent,session,model,tokens,cost_usd\nint-1,2026-05-01T12:00:00Z,bd-1,a,,m,45231,0.87\n"
+	if buf.String() != want {
+		t.Errorf("csv = %q, want %q", buf.String(), want)
+	}
+}
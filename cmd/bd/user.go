@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/audit"
+	"github.com/steveyegge/beads/internal/privacy"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var userCmd = &cobra.Command{
+	Use:     "user",
+	GroupID: "maint",
+	Short:   "Export or erase the data attributed to a person",
+	Long: `Handle data access and erasure requests for a person.
+
+A person is named by one or more identities (email, actor name, display
+name), matched case-insensitively against issue assignee, owner, creator,
+deleter and sender, comment authors, audit trail events, dependency
+creators, and the actor of .beads/interactions.jsonl entries. Mentions in
+titles, descriptions and comment text are not attribution and are not
+touched.`,
+}
+
+var userExportCmd = &cobra.Command{
+	Use:   "export <identity> [alias...]",
+	Short: "Export every record attributed to a person as JSON",
+	Example: `  bd user export alice@example.com
+  bd user export alice@example.com alice "Alice Smith" -o alice.json`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDirectMode("user export requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		output, _ := cmd.Flags().GetString("output")
+		interactions, _ := audit.Path()
+		export, err := privacy.Collect(rootCtx, store, interactions, privacy.Identity(args))
+		if err != nil {
+			FatalErrorRespectJSON("user export failed: %v", err)
+		}
+
+		if output == "" {
+			outputJSON(export)
+			return
+		}
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			FatalErrorRespectJSON("encoding export: %v", err)
+		}
+		// The export holds personal data: keep it private to the owner
+		if err := os.WriteFile(output, append(data, '\n'), 0o600); err != nil {
+			FatalErrorRespectJSON("writing %s: %v", output, err)
+		}
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"output":       output,
+				"issues":       len(export.Issues),
+				"comments":     len(export.Comments),
+				"events":       len(export.Events),
+				"dependencies": len(export.Dependencies),
+				"interactions": len(export.Interactions),
+			})
+			return
+		}
+		fmt.Printf("%s Exported %d record(s) attributed to %s to %s\n", ui.RenderPass("✓"), export.Total(), args[0], output)
+		fmt.Printf("  Issues: %d, comments: %d, events: %d, dependencies: %d, interactions: %d\n",
+			len(export.Issues), len(export.Comments), len(export.Events), len(export.Dependencies), len(export.Interactions))
+	},
+}
+
+var userForgetCmd = &cobra.Command{
+	Use:   "forget <identity> [alias...]",
+	Short: "Anonymize every record attributed to a person",
+	Long: `Re-attribute every record attributed to a person to a pseudonym.
+
+By default records are attributed to "forgotten-<hash>", derived from the
+first identity so the person's records still group together; --as picks the
+name. --delete-comments deletes the person's comments instead of only
+anonymizing their author.
+
+Anonymized issues are marked dirty and reach other clones on the next sync.
+Comments, audit trail events and the interactions log are per-clone: run
+bd user forget in each clone. Git history still holds earlier versions of
+issues.jsonl; rewriting it is out of scope.`,
+	Example: `  bd user forget alice@example.com --dry-run
+  bd user forget alice@example.com alice --delete-comments`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		as, _ := cmd.Flags().GetString("as")
+		deleteComments, _ := cmd.Flags().GetBool("delete-comments")
+		if !dryRun {
+			CheckReadonly("user forget")
+		}
+		if err := ensureDirectMode("user forget requires direct database access"); err != nil {
+			FatalError("%v", err)
+		}
+		id := privacy.Identity(args)
+		if as != "" && id.Matches(as) {
+			FatalErrorCode(ErrCodeUsage, "--as %q names the person being forgotten", as)
+		}
+
+		interactions, _ := audit.Path()
+		report, err := privacy.Forget(rootCtx, store, interactions, id, privacy.ForgetOptions{
+			Replacement:    as,
+			DeleteComments: deleteComments,
+			DryRun:         dryRun,
+		})
+		if errors.Is(err, privacy.ErrUnsupported) {
+			FatalErrorWithHint(err.Error(), "bd user export works on every backend")
+		}
+		if err != nil {
+			FatalErrorRespectJSON("user forget failed: %v", err)
+		}
+		if !dryRun && len(report.Issues) > 0 {
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		if report.Total() == 0 {
+			fmt.Printf("No records attributed to %s\n", args[0])
+			return
+		}
+		verb := "Anonymized"
+		if dryRun {
+			verb = "Would anonymize"
+		}
+		fmt.Printf("%s %d record(s) attributed to %s as %s:\n", verb, report.Total(), args[0], report.Replacement)
+		fields := make([]string, 0, len(report.Touched))
+		for field := range report.Touched {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			action := ""
+			if field == "comments.author" && deleteComments {
+				action = " (deleted)"
+			}
+			fmt.Printf("  %-24s %d%s\n", field, report.Touched[field], action)
+		}
+		if len(report.Issues) > 0 {
+			fmt.Printf("  Issues changed: %d\n", len(report.Issues))
+		}
+		if dryRun {
+			fmt.Printf("  %s\n", ui.RenderMuted("Run without --dry-run to apply"))
+		} else {
+			fmt.Printf("  %s\n", ui.RenderWarn("Run 'bd user forget' in every other clone: comments and history are per-clone"))
+		}
+	},
+}
+
+func init() {
+	userExportCmd.Flags().StringP("output", "o", "", "Write the export to this file (default: stdout)")
+	userForgetCmd.Flags().String("as", "", "Name to attribute the records to (default: forgotten-<hash>)")
+	userForgetCmd.Flags().Bool("delete-comments", false, "Delete the person's comments instead of anonymizing them")
+	userForgetCmd.Flags().Bool("dry-run", false, "Report what would change without changing it")
+	userCmd.AddCommand(userExportCmd, userForgetCmd)
+	rootCmd.AddCommand(userCmd)
+}
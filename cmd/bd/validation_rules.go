@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/validation"
+)
+
+// warnValidationConfig reports required field names under
+// validation.required-fields that the rules ignore. The rules themselves
+// are enforced by the storage layer on every write.
+func warnValidationConfig() {
+	_, unknown := validation.Configured()
+	for _, name := range unknown {
+		t, field, _ := strings.Cut(name, ".")
+		fmt.Fprintf(os.Stderr, "%s validation.required-fields.%s: unknown field %q (want one of %s)\n",
+			ui.RenderWarn("⚠"), t, field, strings.Join(validation.RequirableFields, ", "))
+	}
+}
+
+// checkUpdateRules checks bd update's whole change to issue (updates plus
+// --claim) against the validation rules, and returns the context to make
+// it with. bd update writes a change in several steps, so the store is told
+// not to check them one by one.
+func checkUpdateRules(ctx context.Context, st storage.Storage, issue *types.Issue, updates map[string]interface{}, claim, force bool) (context.Context, error) {
+	change := make(map[string]interface{}, len(updates)+2)
+	for k, v := range updates {
+		change[k] = v
+	}
+	if claim {
+		change["assignee"], change["status"] = actor, string(types.StatusInProgress)
+	}
+	checkCtx := ctx
+	if force {
+		checkCtx = validation.WithForce(ctx, actor)
+	}
+	if err := validation.CheckUpdate(checkCtx, st, issue, change); err != nil {
+		return nil, err
+	}
+	return validation.WithBypass(ctx), nil
+}
+
+// splitConfigList flattens a list value, splitting entries that arrive
+// comma-separated from an env var or bd config set.
+func splitConfigList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/beadstest"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// TestValidationRulesOnEveryWriter checks that commands creating issues
+// outside the single-issue bd create path are held to the rules too.
+func TestValidationRulesOnEveryWriter(t *testing.T) {
+	p := newTestProject(t)
+	// In-process commands share the config loaded at startup
+	config.Set("validation.title-min-length", 10)
+	config.Set("validation.required-fields.bug", []string{"labels"})
+	t.Cleanup(func() {
+		config.Set("validation.title-min-length", 0)
+		config.Set("validation.required-fields.bug", []string{})
+	})
+
+	refused := func(t *testing.T, res beadstest.Result) {
+		t.Helper()
+		if res.ExitCode() != ErrCodeInvalid.ExitCode() || !strings.Contains(res.Stderr, "breaks validation rules") {
+			t.Fatalf("exit = %d, want the rules to refuse it\nstdout: %s\nstderr: %s", res.ExitCode(), res.Stdout, res.Stderr)
+		}
+	}
+	count := func(t *testing.T) int {
+		t.Helper()
+		issues, err := p.Store().SearchIssues(context.Background(), "", types.IssueFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(issues)
+	}
+
+	t.Run("batch", func(t *testing.T) {
+		batch := filepath.Join(p.Dir, "batch.jsonl")
+		content := `{"title": "Write the release notes"}
+{"title": "Short"}
+`
+		if err := os.WriteFile(batch, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		refused(t, p.RunCommand("create", "--batch", batch))
+		if n := count(t); n != 0 {
+			t.Errorf("%d issues created, want the whole batch refused", n)
+		}
+
+		content = `{"title": "Write the release notes"}
+{"title": "Crash on empty config", "type": "bug", "labels": ["config"]}
+`
+		if err := os.WriteFile(batch, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		p.Run("create", "--batch", batch)
+		if n := count(t); n != 2 {
+			t.Errorf("%d issues created, want 2", n)
+		}
+	})
+
+	t.Run("capture", func(t *testing.T) {
+		before := count(t)
+		refused(t, p.RunCommand("capture", "Fix it"))
+		refused(t, p.RunCommand("capture", "Login redirects forever type:bug"))
+		if n := count(t); n != before {
+			t.Errorf("%d issues created, want none", n-before)
+		}
+
+		// Labels given in the capture text count toward required fields
+		p.Run("capture", "Login redirects forever type:bug #auth")
+		if n := count(t); n != before+1 {
+			t.Errorf("%d issues created, want 1", n-before)
+		}
+	})
+	t.Run("update", func(t *testing.T) {
+		var issue types.Issue
+		p.RunJSON(&issue, "create", "Login redirects forever")
+
+		// bd update reports each refused issue and goes on to the next
+		skipped := func(t *testing.T, args ...string) {
+			t.Helper()
+			res := p.RunCommand(append([]string{"update", issue.ID}, args...)...)
+			if !strings.Contains(res.Stderr, "breaks validation rules") {
+				t.Fatalf("bd update %v was not refused\nstderr: %s", args, res.Stderr)
+			}
+		}
+
+		// bd update writes fields and labels separately; the rules see
+		// the change as a whole
+		skipped(t, "--type", "bug")
+		p.Run("update", issue.ID, "--type", "bug", "--add-label", "auth")
+		p.Run("update", issue.ID, "--set-labels", "login")
+		skipped(t, "--remove-label", "login")
+
+		got, err := p.Store().GetIssue(context.Background(), issue.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.IssueType != types.TypeBug || len(got.Labels) != 1 || got.Labels[0] != "login" {
+			t.Errorf("issue is %s with labels %v, want a bug labelled [login]", got.IssueType, got.Labels)
+		}
+	})
+}
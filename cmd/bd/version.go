@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"runtime/debug"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -38,8 +39,9 @@ var versionCmd = &cobra.Command{
 
 		if jsonOutput {
 			result := map[string]string{
-				"version": Version,
-				"build":   Build,
+				"version":        Version,
+				"build":          Build,
+				"output_version": strconv.Itoa(jsonOutputVersion),
 			}
 			if commit != "" {
 				result["commit"] = commit
@@ -109,6 +111,8 @@ func showDaemonVersion() {
 }
 
 func init() {
+	// Sent with every RPC request so the daemon can check compatibility
+	rpc.ClientVersion = Version
 	versionCmd.Flags().Bool("daemon", false, "Check daemon version and compatibility")
 	rootCmd.AddCommand(versionCmd)
 }
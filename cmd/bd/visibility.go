@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+var visibilityCmd = &cobra.Command{
+	Use:     "visibility <issue-id> [public|internal|private]",
+	GroupID: "issues",
+	Short:   "Show or set who may see an issue",
+	Long: `Show or set an issue's visibility, so one database can hold both
+shareable and confidential issues:
+
+  public    may be shown outside the team (feeds for anonymous readers,
+            bd export --visibility public)
+  internal  the default: synced with the team through the committed JSONL
+  private   never leaves this database: withheld from the committed JSONL,
+            from exports (unless --visibility private) and from feeds
+
+Making an issue private removes it from issues.jsonl on the next export,
+but doesn't redact copies already shared: they stay in git history, other
+clones keep theirs as internal issues, and the next export from one of
+those clones writes it back to issues.jsonl. To withdraw a shared issue,
+make it private in every clone that has it.`,
+	Example: `  bd visibility bd-12               # Show the level
+  bd visibility bd-12 private
+  bd create "Salary bands" --visibility private
+  bd list --visibility public`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		level := ""
+		if len(args) > 1 {
+			CheckReadonly("visibility")
+			var err error
+			if level, err = visibility.Parse(args[1]); err != nil {
+				FatalErrorCode(ErrCodeUsage, "%v", err)
+			}
+		}
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		vs, err := visibility.For(store)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+		id, err := utils.ResolvePartialID(ctx, store, args[0])
+		if err != nil {
+			FatalErrorRespectJSON("resolving %s: %v", args[0], err)
+		}
+
+		if level != "" {
+			// Checked before the flush below withholds it
+			shared := level == visibility.Private && inCommittedJSONL(id)
+			if err := vs.SetVisibility(ctx, id, level, actor); err != nil {
+				FatalErrorRespectJSON("failed to set visibility: %v", err)
+			}
+			markDirtyAndScheduleFlush()
+			if shared {
+				WarnError("%s is already in issues.jsonl; other clones keep their copy and re-export it until it is made private there too", id)
+			}
+		} else {
+			levels, err := vs.GetVisibilityForIssues(ctx, []string{id})
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			level = visibility.Of(&types.Issue{Visibility: levels[id]})
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{"id": id, "visibility": level})
+			return
+		}
+		if len(args) > 1 {
+			fmt.Printf("%s %s is now %s\n", ui.RenderPass("✓"), id, level)
+			return
+		}
+		fmt.Printf("%s is %s\n", id, level)
+	},
+}
+
+// inCommittedJSONL reports whether issue id is in the committed JSONL, so
+// other clones may already hold a copy.
+func inCommittedJSONL(id string) bool {
+	path := findJSONLPath()
+	if path == "" {
+		return false
+	}
+	issues, err := loadIssuesFromJSONL(path)
+	if err != nil {
+		return false
+	}
+	for _, issue := range issues {
+		if issue.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// createVisibility reads and validates bd create's --visibility flag,
+// before the issue is created.
+func createVisibility(flags *pflag.FlagSet) string {
+	value, _ := flags.GetString("visibility")
+	level, err := visibility.Parse(value)
+	if err != nil {
+		FatalErrorCode(ErrCodeUsage, "%v", err)
+	}
+	return level
+}
+
+// setCreatedVisibility stores the visibility given to bd create on the new
+// issue.
+func setCreatedVisibility(id, level string) {
+	if level == visibility.Internal {
+		return
+	}
+	if err := ensureStoreActive(); err != nil {
+		WarnError("failed to set visibility of %s: %v", id, err)
+		return
+	}
+	vs, err := visibility.For(store)
+	if err == nil {
+		err = vs.SetVisibility(rootCtx, id, level, actor)
+	}
+	if err != nil {
+		WarnError("failed to set visibility of %s: %v", id, err)
+		return
+	}
+	markDirtyAndScheduleFlush()
+}
+
+// printVisibility prints an issue's visibility in bd show, unless it's
+// the default. issue.Visibility must already be populated.
+func printVisibility(issue *types.Issue) {
+	switch level := visibility.Of(issue); level {
+	case visibility.Private:
+		fmt.Printf("Visibility: %s\n", ui.RenderWarn(level))
+	case visibility.Public:
+		fmt.Printf("Visibility: %s\n", level)
+	}
+}
+
+// visibilityFilterIDs returns the IDs of the public or private issues, for
+// bd list --visibility. In daemon mode it reads them through a read-only
+// connection.
+func visibilityFilterIDs(ctx context.Context, value string) ([]string, error) {
+	level, err := visibility.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if level == visibility.Internal {
+		return nil, fmt.Errorf("--visibility filters public or private issues")
+	}
+	s := store
+	if s == nil {
+		if dbPath == "" {
+			return nil, visibility.ErrUnsupported
+		}
+		roStore, err := sqlite.NewReadOnlyWithTimeout(ctx, dbPath, lockTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = roStore.Close() }()
+		s = roStore
+	}
+	vs, err := visibility.For(s)
+	if err != nil {
+		return nil, err
+	}
+	return vs.GetVisibilityIssueIDs(ctx, level)
+}
+
+func init() {
+	visibilityCmd.ValidArgsFunction = issueIDCompletion
+	rootCmd.AddCommand(visibilityCmd)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInCommittedJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.jsonl")
+	if err := os.WriteFile(path, []byte(`{"id":"bd-1","title":"Roadmap","status":"open","priority":2,"issue_type":"task"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_JSONL", path)
+
+	if !inCommittedJSONL("bd-1") {
+		t.Error("bd-1 is in the JSONL")
+	}
+	if inCommittedJSONL("bd-2") {
+		t.Error("bd-2 is not in the JSONL")
+	}
+
+	t.Setenv("BEADS_JSONL", filepath.Join(t.TempDir(), "missing.jsonl"))
+	if inCommittedJSONL("bd-1") {
+		t.Error("a missing JSONL holds no issues")
+	}
+}
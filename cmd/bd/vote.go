@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+	"github.com/steveyegge/beads/internal/votes"
+)
+
+var voteCmd = &cobra.Command{
+	Use:     "vote <issue-id> [+1|-1|0]",
+	GroupID: "issues",
+	Short:   "Vote an issue up or down",
+	Long: `Cast your +1 or -1 on an issue, so stakeholder demand shows up next to
+priority in shared databases. Each voter (--actor, BD_ACTOR or git user)
+holds one vote per issue: voting again replaces it, and 0 retracts it.
+
+Votes sync with the issue. bd show prints the tally and voters, and
+bd list --sort votes puts the most wanted issues first.`,
+	Example: `  bd vote bd-12             # +1
+  bd vote bd-12 -1
+  bd vote bd-12 0           # Retract your vote
+  bd list --sort votes`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("vote")
+		value := 1
+		down, _ := cmd.Flags().GetBool("down")
+		switch {
+		case down && len(args) > 1:
+			FatalErrorCode(ErrCodeUsage, "give one vote: +1, -1 or 0")
+		case down:
+			value = -1
+		case len(args) > 1:
+			v, err := votes.ParseValue(args[1])
+			if err != nil {
+				FatalErrorCode(ErrCodeUsage, "%v", err)
+			}
+			value = v
+		}
+		if actor == "" {
+			FatalErrorCode(ErrCodeUsage, "cannot tell who is voting: set --actor, BD_ACTOR or git user.name")
+		}
+
+		if err := ensureStoreActive(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		vs, err := votes.For(store)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+		id, err := utils.ResolvePartialID(ctx, store, args[0])
+		if err != nil {
+			FatalErrorRespectJSON("resolving %s: %v", args[0], err)
+		}
+		if err := vs.SetVote(ctx, id, actor, value, actor); err != nil {
+			FatalErrorRespectJSON("failed to vote: %v", err)
+		}
+		markDirtyAndScheduleFlush()
+		all, err := vs.GetVotes(ctx, id)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if jsonOutput {
+			up, down := types.VoteTally(all)
+			outputJSON(map[string]interface{}{
+				"id": id, "voter": actor, "value": value,
+				"up": up, "down": down, "score": up - down, "votes": all,
+			})
+			return
+		}
+		if value == 0 {
+			fmt.Printf("%s Retracted your vote on %s (%s)\n", ui.RenderPass("✓"), id, votes.Format(all))
+			return
+		}
+		fmt.Printf("%s Voted %+d on %s (%s)\n", ui.RenderPass("✓"), value, id, votes.Format(all))
+	},
+}
+
+// printVotes prints an issue's vote tally and voters in bd show.
+// issue.Votes must already be populated.
+func printVotes(issue *types.Issue) {
+	if len(issue.Votes) == 0 {
+		return
+	}
+	voters := make([]string, len(issue.Votes))
+	for i, v := range issue.Votes {
+		voters[i] = fmt.Sprintf("%s %+d", v.Voter, v.Value)
+	}
+	fmt.Printf("Votes: %s %s\n", votes.Format(issue.Votes), ui.RenderMuted("("+strings.Join(voters, ", ")+")"))
+}
+
+// populateListVotes fills in issue votes for bd list --sort votes. In
+// daemon mode it reads them through a read-only connection.
+func populateListVotes(ctx context.Context, issues []*types.Issue) error {
+	s := store
+	if s == nil {
+		if dbPath == "" {
+			return votes.ErrUnsupported
+		}
+		roStore, err := sqlite.NewReadOnlyWithTimeout(ctx, dbPath, lockTimeout)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = roStore.Close() }()
+		s = roStore
+	}
+	return votes.Populate(ctx, s, issues)
+}
+
+func init() {
+	voteCmd.Flags().BoolP("down", "1", false, "Vote -1 (so that \"bd vote <id> -1\" works)")
+	_ = voteCmd.Flags().MarkHidden("down")
+	voteCmd.ValidArgsFunction = issueIDCompletion
+	rootCmd.AddCommand(voteCmd)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/web"
+)
+
+var webCmd = &cobra.Command{
+	Use:     "web",
+	GroupID: "views",
+	Short:   "Serve a web UI with board, list, issue and dependency graph views",
+	Long: `Serve a browser UI for people who'd rather not use the CLI: a board by
+status, a filterable list, issue details with comments, and a dependency
+graph. Pages update live as issues change.
+
+The UI is backed by the daemon's HTTP API, so the daemon must be running
+with api.listen set:
+  api:
+    listen: 127.0.0.1:7781
+
+bd web sends api.token to the API, so everyone who can reach --listen sees
+internal issues; with --public they see public issues only. Private issues
+are never shown.
+
+Examples:
+  bd web                        # http://127.0.0.1:7780
+  bd web --listen :7780         # Share with the team
+  bd web --listen :8080 --public`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listen, _ := cmd.Flags().GetString("listen")
+		public, _ := cmd.Flags().GetBool("public")
+
+		apiURL, err := daemonAPIURL(config.GetString("api.listen"))
+		if err != nil {
+			FatalErrorWithHint(err.Error(), "set api.listen (bd config set api.listen 127.0.0.1:7781) and restart the daemon")
+		}
+		token := config.GetString("api.token")
+		if public {
+			token = ""
+		}
+		if err := checkDaemonAPI(rootCtx, apiURL); err != nil {
+			fmt.Fprintf(os.Stderr, "%s daemon API not reachable at %s (%v); pages will fail until the daemon is running\n",
+				ui.RenderWarn("⚠"), apiURL, err)
+		}
+
+		ln, err := net.Listen("tcp", listen)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		srv := &http.Server{
+			Handler:           web.Handler(web.Options{API: apiURL, Token: token}),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			<-rootCtx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = srv.Shutdown(shutdownCtx)
+		}()
+		fmt.Printf("Serving the web UI at %s (Ctrl+C to stop)\n", ui.RenderAccent("http://"+displayAddr(ln.Addr())))
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			FatalError("%v", err)
+		}
+	},
+}
+
+// daemonAPIURL returns the URL to reach the daemon's API on, given
+// api.listen. Wildcard hosts are reached on the loopback address.
+func daemonAPIURL(listen string) (*url.URL, error) {
+	if listen == "" {
+		return nil, errors.New("bd web needs the daemon's HTTP API, but api.listen is not set")
+	}
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api.listen %q: %w", listen, err)
+	}
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return &url.URL{Scheme: "http", Host: net.JoinHostPort(host, port)}, nil
+}
+
+// checkDaemonAPI reports whether the API answers a trivial query.
+func checkDaemonAPI(ctx context.Context, apiURL *url.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL.String()+"/graphql?query="+url.QueryEscape("{ stats { total } }"), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// displayAddr turns a wildcard listen address into one a browser can open.
+func displayAddr(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+func init() {
+	webCmd.Flags().String("listen", "127.0.0.1:7780", "Address to serve the UI on")
+	webCmd.Flags().Bool("public", false, "Show only public issues (don't send api.token)")
+	rootCmd.AddCommand(webCmd)
+}
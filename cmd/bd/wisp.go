@@ -645,7 +645,7 @@ func runWispGC(cmd *cobra.Command, args []string) {
 	}
 
 	for _, issue := range abandoned {
-		if err := sqliteStore.DeleteIssue(ctx, issue.ID); err != nil {
+		if err := sqliteStore.DeleteIssue(ctx, issue.ID, actor); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to delete %s: %v\n", issue.ID, err)
 			continue
 		}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// WorkloadEntry summarizes the open work held by a single assignee.
+type WorkloadEntry struct {
+	Assignee   string         `json:"assignee"` // Empty for unassigned work
+	Total      int            `json:"total"`
+	Open       int            `json:"open"`
+	InProgress int            `json:"in_progress"`
+	Blocked    int            `json:"blocked"`
+	ByPriority map[string]int `json:"by_priority"`     // "P0".."P4" -> count
+	ByAge      map[string]int `json:"by_age"`          // Age bucket label -> count
+	OldestDays int            `json:"oldest_days"`     // Age of the oldest open issue
+	Limit      int            `json:"limit,omitempty"` // WIP limit (0 = unlimited)
+	AtLimit    bool           `json:"at_limit"`
+	Overloaded bool           `json:"overloaded"`
+	Idle       bool           `json:"idle"` // Assigned work but nothing in progress
+}
+
+// workloadAgeBucket is an age range (days since creation) in the workload view.
+type workloadAgeBucket struct {
+	Label   string
+	MaxDays int // Exclusive upper bound; 0 = unbounded
+}
+
+// workloadAgeBuckets are ordered from youngest to oldest.
+var workloadAgeBuckets = []workloadAgeBucket{
+	{Label: "0-7d", MaxDays: 7},
+	{Label: "7-30d", MaxDays: 30},
+	{Label: "30-90d", MaxDays: 90},
+	{Label: "90d+"},
+}
+
+// ageBucketFor returns the label of the bucket containing an issue of the given age.
+func ageBucketFor(days int) string {
+	for _, b := range workloadAgeBuckets {
+		if b.MaxDays == 0 || days < b.MaxDays {
+			return b.Label
+		}
+	}
+	return workloadAgeBuckets[len(workloadAgeBuckets)-1].Label
+}
+
+var statusWorkloadCmd = &cobra.Command{
+	Use:   "workload",
+	Short: "Show open work per assignee by priority and age",
+	Long: `Show open work per assignee, broken down by status, priority, and age.
+
+Age buckets are based on creation time: 0-7d, 7-30d, 30-90d, 90d+.
+
+Assignees with a WIP limit configured (capacity.<name> or capacity.default
+in config.yaml) are flagged when they are at or over their limit. Assignees
+that hold work but have nothing in progress are flagged as idle.
+
+Examples:
+  bd stats workload
+  bd stats workload --assignee alice
+  bd stats workload --json
+  bd config set capacity.default 3`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assigneeFilter, _ := cmd.Flags().GetString("assignee")
+
+		if err := ensureDirectMode("stats workload requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		ctx := rootCtx
+
+		filter := types.IssueFilter{
+			ExcludeStatus: []types.Status{types.StatusClosed},
+		}
+		if assigneeFilter != "" {
+			filter.Assignee = &assigneeFilter
+		}
+		issues, err := store.SearchIssues(ctx, "", filter)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		entries := buildWorkload(issues, config.GetCapacity, time.Now())
+
+		if jsonOutput {
+			outputJSON(entries)
+			return
+		}
+
+		if len(entries) == 0 {
+			fmt.Printf("\n%s No open work\n\n", ui.RenderPass("✨"))
+			return
+		}
+		displayWorkload(entries)
+	},
+}
+
+// buildWorkload groups non-closed issues by assignee and compares each
+// assignee's in-progress count against the limit returned by limitFor.
+// Entries are sorted by in-progress count (descending), then total open
+// work (descending), then assignee name.
+func buildWorkload(issues []*types.Issue, limitFor func(string) int, now time.Time) []*WorkloadEntry {
+	byAssignee := make(map[string]*WorkloadEntry)
+	for _, issue := range issues {
+		if issue.Status == types.StatusClosed || issue.Status == types.StatusTombstone {
+			continue
+		}
+		entry, ok := byAssignee[issue.Assignee]
+		if !ok {
+			entry = &WorkloadEntry{
+				Assignee:   issue.Assignee,
+				ByPriority: make(map[string]int),
+				ByAge:      make(map[string]int),
+			}
+			byAssignee[issue.Assignee] = entry
+		}
+		entry.Total++
+		switch issue.Status {
+		case types.StatusInProgress, types.StatusHooked:
+			entry.InProgress++
+		case types.StatusBlocked:
+			entry.Blocked++
+		default:
+			entry.Open++
+		}
+		entry.ByPriority[fmt.Sprintf("P%d", issue.Priority)]++
+
+		ageDays := 0
+		if !issue.CreatedAt.IsZero() && now.After(issue.CreatedAt) {
+			ageDays = int(now.Sub(issue.CreatedAt).Hours() / 24)
+		}
+		entry.ByAge[ageBucketFor(ageDays)]++
+		if ageDays > entry.OldestDays {
+			entry.OldestDays = ageDays
+		}
+	}
+
+	entries := make([]*WorkloadEntry, 0, len(byAssignee))
+	for _, entry := range byAssignee {
+		if entry.Assignee != "" {
+			entry.Idle = entry.InProgress == 0
+			if limitFor != nil {
+				capacity := CapacityStatus{
+					Assignee:   entry.Assignee,
+					InProgress: entry.InProgress,
+					Limit:      limitFor(entry.Assignee),
+				}
+				entry.Limit = capacity.Limit
+				entry.AtLimit = capacity.AtLimit()
+				entry.Overloaded = capacity.Overloaded()
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].InProgress != entries[j].InProgress {
+			return entries[i].InProgress > entries[j].InProgress
+		}
+		if entries[i].Total != entries[j].Total {
+			return entries[i].Total > entries[j].Total
+		}
+		return entries[i].Assignee < entries[j].Assignee
+	})
+	return entries
+}
+
+// displayWorkload renders the workload tables: status/capacity, then
+// priority and age breakdowns.
+func displayWorkload(entries []*WorkloadEntry) {
+	name := func(e *WorkloadEntry) string {
+		if e.Assignee == "" {
+			return "(unassigned)"
+		}
+		return e.Assignee
+	}
+
+	if ui.IsAccessible() {
+		displayWorkloadAccessible(entries, name)
+		return
+	}
+
+	fmt.Printf("\n%s Workload by assignee:\n\n", ui.RenderAccent("👥"))
+	fmt.Printf("  %-24s %6s %12s %8s %6s\n", "ASSIGNEE", "OPEN", "IN PROGRESS", "BLOCKED", "LIMIT")
+	for _, e := range entries {
+		limit := "-"
+		if e.Limit > 0 {
+			limit = fmt.Sprintf("%d", e.Limit)
+		}
+		line := fmt.Sprintf("  %-24s %6d %12d %8d %6s", name(e), e.Open, e.InProgress, e.Blocked, limit)
+		switch {
+		case e.Overloaded:
+			fmt.Printf("%s  %s\n", line, ui.RenderFail("overloaded"))
+		case e.AtLimit:
+			fmt.Printf("%s  %s\n", line, ui.RenderWarn("at limit"))
+		case e.Idle:
+			fmt.Printf("%s  %s\n", line, ui.RenderMuted("idle"))
+		default:
+			fmt.Println(line)
+		}
+	}
+
+	fmt.Printf("\nBy priority:\n\n")
+	fmt.Printf("  %-24s", "ASSIGNEE")
+	for p := 0; p <= 4; p++ {
+		fmt.Printf(" %5s", fmt.Sprintf("P%d", p))
+	}
+	fmt.Println()
+	for _, e := range entries {
+		fmt.Printf("  %-24s", name(e))
+		for p := 0; p <= 4; p++ {
+			count := e.ByPriority[fmt.Sprintf("P%d", p)]
+			cell := fmt.Sprintf("%5d", count)
+			if p <= 1 && count > 0 {
+				cell = ui.RenderWarn(cell)
+			}
+			fmt.Printf(" %s", cell)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("\nBy age:\n\n")
+	fmt.Printf("  %-24s", "ASSIGNEE")
+	for _, b := range workloadAgeBuckets {
+		fmt.Printf(" %7s", b.Label)
+	}
+	fmt.Printf(" %7s\n", "OLDEST")
+	for _, e := range entries {
+		var cells []string
+		for _, b := range workloadAgeBuckets {
+			cells = append(cells, fmt.Sprintf("%7d", e.ByAge[b.Label]))
+		}
+		fmt.Printf("  %-24s %s %6dd\n", name(e), strings.Join(cells, " "), e.OldestDays)
+	}
+	fmt.Println()
+}
+
+// displayWorkloadAccessible renders the workload as one line per
+// assignee instead of tables.
+func displayWorkloadAccessible(entries []*WorkloadEntry, name func(*WorkloadEntry) string) {
+	fmt.Printf("Workload by assignee, %d assignees.\n\n", len(entries))
+	for _, e := range entries {
+		parts := []string{fmt.Sprintf("%s: %d open, %d in progress, %d blocked.", name(e), e.Open, e.InProgress, e.Blocked)}
+		if e.Limit > 0 {
+			parts = append(parts, fmt.Sprintf("Limit %d.", e.Limit))
+		}
+		switch {
+		case e.Overloaded:
+			parts = append(parts, "Overloaded.")
+		case e.AtLimit:
+			parts = append(parts, "At limit.")
+		case e.Idle:
+			parts = append(parts, "Idle.")
+		}
+		var byPriority []string
+		for p := 0; p <= 4; p++ {
+			if count := e.ByPriority[fmt.Sprintf("P%d", p)]; count > 0 {
+				byPriority = append(byPriority, fmt.Sprintf("%d at P%d", count, p))
+			}
+		}
+		if len(byPriority) > 0 {
+			parts = append(parts, "By priority: "+strings.Join(byPriority, ", ")+".")
+		}
+		var byAge []string
+		for _, b := range workloadAgeBuckets {
+			if count := e.ByAge[b.Label]; count > 0 {
+				byAge = append(byAge, fmt.Sprintf("%d aged %s", count, b.Label))
+			}
+		}
+		if len(byAge) > 0 {
+			parts = append(parts, "By age: "+strings.Join(byAge, ", ")+".")
+		}
+		if e.OldestDays > 0 {
+			parts = append(parts, fmt.Sprintf("Oldest %d days.", e.OldestDays))
+		}
+		fmt.Println(strings.Join(parts, " "))
+	}
+	fmt.Println()
+}
+
+func init() {
+	statusWorkloadCmd.Flags().StringP("assignee", "a", "", "Only show workload for this assignee")
+	statusCmd.AddCommand(statusWorkloadCmd)
+}
@@ -517,7 +517,8 @@ class BeadsToJira:
                         new_ref = f"{self.jira_url}/browse/{new_key}"
                         print(
                             json.dumps({"bd_id": bd_id, "jira_key": new_key, "external_ref": new_ref}),
-                            file=sys.stdout
+                            file=sys.stdout,
+                            flush=True,
                         )
 
         except RuntimeError as e:
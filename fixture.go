@@ -0,0 +1,52 @@
+package beads
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/fixture"
+)
+
+// LoadFixture loads a fixture written by 'bd fixture export' into s.
+// Issue IDs are preserved; if s has no issue_prefix yet it is taken from
+// the fixture.
+func LoadFixture(ctx context.Context, s Storage, fixturePath string) error {
+	f, err := os.Open(fixturePath) // #nosec G304 -- caller-provided fixture path
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	issues, err := fixture.Read(f)
+	if err != nil {
+		return err
+	}
+	return fixture.Load(ctx, s, issues, fixture.LoadOptions{})
+}
+
+// NewFixtureStorage creates a SQLite database in a temporary directory,
+// loads the fixture at fixturePath into it, and closes it when the test
+// ends. It is meant for tests of code built on beads:
+//
+//	func TestReport(t *testing.T) {
+//		store := beads.NewFixtureStorage(t, "testdata/small.jsonl")
+//		...
+//	}
+func NewFixtureStorage(tb testing.TB, fixturePath string) Storage {
+	tb.Helper()
+	ctx := context.Background()
+	dbPath := filepath.Join(tb.TempDir(), ".beads", "beads.db")
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o750); err != nil {
+		tb.Fatalf("creating fixture dir: %v", err)
+	}
+	s, err := NewSQLiteStorage(ctx, dbPath)
+	if err != nil {
+		tb.Fatalf("opening fixture database: %v", err)
+	}
+	tb.Cleanup(func() { _ = s.Close() })
+	if err := LoadFixture(ctx, s, fixturePath); err != nil {
+		tb.Fatalf("loading fixture %s: %v", fixturePath, err)
+	}
+	return s
+}
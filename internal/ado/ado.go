@@ -0,0 +1,172 @@
+// Package ado reads Azure DevOps (and TFS) work items, either from a WIQL
+// query against the REST API or from a CSV export, and maps them to beads
+// issues.
+package ado
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// WorkItem is the part of an Azure DevOps work item that beads imports.
+type WorkItem struct {
+	ID            int
+	Type          string // Epic, Feature, User Story, Task, Bug, ...
+	Title         string
+	State         string
+	AreaPath      string // e.g. Fabrikam\Web\Checkout
+	IterationPath string
+	AssignedTo    string
+	Description   string // HTML in the API, usually plain text in CSV
+	Priority      int    // 1 (highest) - 4, 0 if unset
+	Tags          []string
+	ParentID      int // 0 if none
+	CreatedAt     time.Time
+	ChangedAt     time.Time
+	URL           string // Web URL, if known
+}
+
+// ExternalRef returns the external_ref recorded on the imported issue: the
+// work item's web URL when known, otherwise ado:<id>.
+func (w *WorkItem) ExternalRef() string {
+	if w.URL != "" {
+		return w.URL
+	}
+	return "ado:" + strconv.Itoa(w.ID)
+}
+
+// AreaLabel returns the label for an area path: area:<path> with
+// backslashes turned into slashes, or "" for no area.
+func AreaLabel(areaPath string) string {
+	areaPath = strings.Trim(strings.ReplaceAll(areaPath, `\`, "/"), "/ ")
+	if areaPath == "" {
+		return ""
+	}
+	return "area:" + areaPath
+}
+
+// MapType maps an Azure DevOps work item type to a beads issue type. Epics
+// and Features both sit above stories in the backlog hierarchy, so both
+// become epics.
+func MapType(workItemType string) types.IssueType {
+	switch strings.ToLower(workItemType) {
+	case "epic", "feature":
+		return types.TypeEpic
+	case "bug", "impediment":
+		return types.TypeBug
+	case "user story", "product backlog item", "requirement":
+		return types.TypeFeature
+	case "task", "issue", "test case":
+		return types.TypeTask
+	}
+	return types.TypeTask
+}
+
+// MapState maps a work item state from the Agile, Scrum, CMMI or Basic
+// process to a beads status. Unknown states stay open.
+func MapState(state string) types.Status {
+	switch strings.ToLower(state) {
+	case "active", "committed", "in progress", "doing", "resolved":
+		return types.StatusInProgress
+	case "closed", "done", "completed", "removed":
+		return types.StatusClosed
+	}
+	return types.StatusOpen
+}
+
+// MapPriority maps Azure DevOps priority 1-4 to beads priority 0-3,
+// defaulting to 2 when unset.
+func MapPriority(priority int) int {
+	if priority < 1 || priority > 4 {
+		return 2
+	}
+	return priority - 1
+}
+
+var (
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</(?:p|div|li|h\d)>`)
+	htmlTagPattern   = regexp.MustCompile(`<[^>]+>`)
+	blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+)
+
+// PlainText converts a work item's HTML description to plain text.
+func PlainText(s string) string {
+	if !strings.Contains(s, "<") {
+		return strings.TrimSpace(s)
+	}
+	s = htmlBreakPattern.ReplaceAllString(s, "\n")
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	return strings.TrimSpace(blankLinesRegexp.ReplaceAllString(s, "\n\n"))
+}
+
+// ToBeads converts a work item to a beads issue. The area path becomes an
+// area:<path> label and tags become labels; parent links are returned by
+// the caller's own lookup of ParentID.
+func ToBeads(w *WorkItem) *types.Issue {
+	issue := &types.Issue{
+		Title:       w.Title,
+		Description: PlainText(w.Description),
+		Status:      MapState(w.State),
+		Priority:    MapPriority(w.Priority),
+		IssueType:   MapType(w.Type),
+		Assignee:    w.AssignedTo,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.ChangedAt,
+	}
+	if issue.CreatedAt.IsZero() {
+		issue.CreatedAt = time.Now()
+	}
+	if issue.UpdatedAt.IsZero() {
+		issue.UpdatedAt = issue.CreatedAt
+	}
+	if issue.Status == types.StatusClosed {
+		closedAt := issue.UpdatedAt
+		issue.ClosedAt = &closedAt
+	}
+	if label := AreaLabel(w.AreaPath); label != "" {
+		issue.Labels = append(issue.Labels, label)
+	}
+	issue.Labels = append(issue.Labels, w.Tags...)
+	ref := w.ExternalRef()
+	issue.ExternalRef = &ref
+	return issue
+}
+
+// splitTags splits an Azure DevOps tag string ("a; b; c").
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// identityName extracts the account from an identity string such as
+// "Jamal Hartnett <fabrikamfiber4@hotmail.com>", preferring the email.
+func identityName(s string) string {
+	s = strings.TrimSpace(s)
+	if start := strings.LastIndex(s, "<"); start >= 0 && strings.HasSuffix(s, ">") {
+		return s[start+1 : len(s)-1]
+	}
+	return s
+}
+
+// parseTime parses the date formats found in API responses and CSV exports.
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339Nano, "1/2/2006 3:04:05 PM", "1/2/2006 3:04 PM", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", s)
+}
@@ -0,0 +1,112 @@
+package ado
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParseCSVTreeQuery(t *testing.T) {
+	csv := "\ufeffID,Work Item Type,Title 1,Title 2,Title 3,State,Area Path,Tags,Priority,Assigned To\n" +
+		"1,Epic,Checkout,,,Active,Fabrikam\\Web,,2,\n" +
+		"2,User Story,,Pay by card,,New,Fabrikam\\Web\\Pay,pci; ux,1,Jamal Hartnett <jamal@example.com>\n" +
+		"3,Task,,,Wire up Stripe,Done,Fabrikam\\Web\\Pay,,,\n" +
+		"4,User Story,,Guest checkout,,New,Fabrikam\\Web,,,\n"
+	items, err := ParseCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("got %d items, want 4", len(items))
+	}
+	parents := map[int]int{}
+	for _, item := range items {
+		parents[item.ID] = item.ParentID
+	}
+	if want := map[int]int{1: 0, 2: 1, 3: 2, 4: 1}; !reflect.DeepEqual(parents, want) {
+		t.Errorf("parents = %v, want %v", parents, want)
+	}
+
+	story := items[1]
+	if story.Title != "Pay by card" || story.AssignedTo != "jamal@example.com" || !reflect.DeepEqual(story.Tags, []string{"pci", "ux"}) {
+		t.Errorf("story = %+v", story)
+	}
+	issue := ToBeads(&story)
+	if issue.IssueType != types.TypeFeature || issue.Priority != 0 || issue.Status != types.StatusOpen {
+		t.Errorf("issue = type %s, priority %d, status %s", issue.IssueType, issue.Priority, issue.Status)
+	}
+	if want := []string{"area:Fabrikam/Web/Pay", "pci", "ux"}; !reflect.DeepEqual(issue.Labels, want) {
+		t.Errorf("labels = %v, want %v", issue.Labels, want)
+	}
+	if *issue.ExternalRef != "ado:2" {
+		t.Errorf("external_ref = %s, want ado:2", *issue.ExternalRef)
+	}
+	if done := ToBeads(&items[2]); done.Status != types.StatusClosed || done.ClosedAt == nil {
+		t.Errorf("Done task = status %s, closed_at %v", done.Status, done.ClosedAt)
+	}
+}
+
+func TestParseCSVRequiresID(t *testing.T) {
+	if _, err := ParseCSV(strings.NewReader("Title,State\nx,New\n")); err == nil {
+		t.Error("expected an error for a CSV without an ID column")
+	}
+}
+
+func TestClientQueryAndGetWorkItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "" || pass != "pat" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_apis/wit/wiql"):
+			_, _ = w.Write([]byte(`{"workItems":[{"id":10},{"id":11}]}`))
+		case strings.HasSuffix(r.URL.Path, "/_apis/wit/workitems"):
+			if r.URL.Query().Get("ids") != "10,11" {
+				t.Errorf("ids = %q", r.URL.Query().Get("ids"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"value": []interface{}{
+				map[string]interface{}{"id": 10, "fields": map[string]interface{}{
+					"System.WorkItemType": "Feature", "System.Title": "Search", "System.State": "New",
+					"System.AreaPath": `Proj\Search`, "System.CreatedDate": "2024-03-01T10:00:00.123Z",
+				}, "_links": map[string]interface{}{"html": map[string]string{"href": "https://dev.azure.com/o/p/_workitems/edit/10"}}},
+				map[string]interface{}{"id": 11, "fields": map[string]interface{}{
+					"System.WorkItemType": "Bug", "System.Title": "Crash", "System.State": "Active",
+					"System.AssignedTo":              map[string]string{"displayName": "Ann", "uniqueName": "ann@example.com"},
+					"System.Description":             "<div>Steps<br>1. open</div>",
+					"Microsoft.VSTS.Common.Priority": 1,
+				}, "relations": []map[string]string{{"rel": "System.LinkTypes.Hierarchy-Reverse", "url": "https://dev.azure.com/o/_apis/wit/workItems/10"}}},
+				nil,
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "p", "pat")
+	ids, err := client.Query(context.Background(), "SELECT [System.Id] FROM WorkItems")
+	if err != nil || !reflect.DeepEqual(ids, []int{10, 11}) {
+		t.Fatalf("Query = %v, %v", ids, err)
+	}
+	items, err := client.GetWorkItems(context.Background(), ids)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("GetWorkItems = %+v, %v", items, err)
+	}
+	bug := items[1]
+	if bug.ParentID != 10 || bug.AssignedTo != "ann@example.com" || bug.Priority != 1 {
+		t.Errorf("bug = %+v", bug)
+	}
+	if got := ToBeads(&bug).Description; got != "Steps\n1. open" {
+		t.Errorf("description = %q", got)
+	}
+	if items[0].ExternalRef() != "https://dev.azure.com/o/p/_workitems/edit/10" || items[0].CreatedAt.IsZero() {
+		t.Errorf("feature = %+v", items[0])
+	}
+}
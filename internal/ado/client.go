@@ -0,0 +1,226 @@
+package ado
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/httpx"
+)
+
+const (
+	// APIVersion is the REST API version requested.
+	APIVersion = "7.0"
+
+	// BatchSize is the most work items fetched per request (API limit).
+	BatchSize = 200
+
+	// DefaultTimeout is the default HTTP request timeout.
+	DefaultTimeout = 30 * time.Second
+)
+
+// Client queries work items in one Azure DevOps project.
+type Client struct {
+	OrgURL     string // https://dev.azure.com/<org> or a TFS collection URL
+	Project    string
+	PAT        string // Personal access token with Work Items (Read) scope
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for project in the organization at orgURL.
+func NewClient(orgURL, project, pat string) *Client {
+	return &Client{
+		OrgURL:     strings.TrimSuffix(orgURL, "/"),
+		Project:    project,
+		PAT:        pat,
+		HTTPClient: httpx.NewClient(DefaultTimeout),
+	}
+}
+
+// do sends a request with basic auth (empty user, PAT as password) and
+// decodes the JSON response into out.
+func (c *Client) do(ctx context.Context, method, rawURL string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, payload)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+c.PAT)))
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Unauthenticated requests get redirected to a sign-in page
+		return fmt.Errorf("API error: %s (status %d)", strings.TrimSpace(string(data)), resp.StatusCode)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response (check ado.org_url and the token): %w", err)
+	}
+	return nil
+}
+
+// Query runs a WIQL query and returns the IDs of the matching work items.
+// For tree (link) queries, the IDs of both ends of each link are returned.
+func (c *Client) Query(ctx context.Context, wiql string) ([]int, error) {
+	var resp struct {
+		WorkItems []struct {
+			ID int `json:"id"`
+		} `json:"workItems"`
+		WorkItemRelations []struct {
+			Source *struct {
+				ID int `json:"id"`
+			} `json:"source"`
+			Target *struct {
+				ID int `json:"id"`
+			} `json:"target"`
+		} `json:"workItemRelations"`
+	}
+	endpoint := c.OrgURL + "/" + url.PathEscape(c.Project) + "/_apis/wit/wiql?api-version=" + APIVersion
+	if err := c.do(ctx, http.MethodPost, endpoint, map[string]string{"query": wiql}, &resp); err != nil {
+		return nil, fmt.Errorf("WIQL query failed: %w", err)
+	}
+
+	seen := make(map[int]bool)
+	var ids []int
+	add := func(id int) {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, wi := range resp.WorkItems {
+		add(wi.ID)
+	}
+	for _, rel := range resp.WorkItemRelations {
+		if rel.Source != nil {
+			add(rel.Source.ID)
+		}
+		if rel.Target != nil {
+			add(rel.Target.ID)
+		}
+	}
+	return ids, nil
+}
+
+// apiWorkItem is a work item as returned by the REST API.
+type apiWorkItem struct {
+	ID        int                    `json:"id"`
+	Fields    map[string]interface{} `json:"fields"`
+	Relations []struct {
+		Rel string `json:"rel"`
+		URL string `json:"url"`
+	} `json:"relations"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"_links"`
+}
+
+// GetWorkItems fetches the work items with the given IDs, including their
+// parent links.
+func (c *Client) GetWorkItems(ctx context.Context, ids []int) ([]WorkItem, error) {
+	var items []WorkItem
+	for start := 0; start < len(ids); start += BatchSize {
+		batch := ids[start:min(start+BatchSize, len(ids))]
+		strIDs := make([]string, len(batch))
+		for i, id := range batch {
+			strIDs[i] = strconv.Itoa(id)
+		}
+		endpoint := c.OrgURL + "/" + url.PathEscape(c.Project) + "/_apis/wit/workitems?ids=" +
+			strings.Join(strIDs, ",") + "&$expand=all&errorPolicy=omit&api-version=" + APIVersion
+
+		var resp struct {
+			Value []*apiWorkItem `json:"value"`
+		}
+		if err := c.do(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+			return nil, fmt.Errorf("fetching work items: %w", err)
+		}
+		for _, wi := range resp.Value {
+			if wi != nil { // errorPolicy=omit returns null for deleted items
+				items = append(items, wi.toWorkItem())
+			}
+		}
+	}
+	return items, nil
+}
+
+func (wi *apiWorkItem) toWorkItem() WorkItem {
+	str := func(field string) string {
+		switch v := wi.Fields[field].(type) {
+		case string:
+			return v
+		case map[string]interface{}: // identity fields
+			if name, ok := v["uniqueName"].(string); ok && name != "" {
+				return name
+			}
+			name, _ := v["displayName"].(string)
+			return name
+		}
+		return ""
+	}
+	num := func(field string) int {
+		if v, ok := wi.Fields[field].(float64); ok {
+			return int(v)
+		}
+		return 0
+	}
+
+	item := WorkItem{
+		ID:            wi.ID,
+		Type:          str("System.WorkItemType"),
+		Title:         str("System.Title"),
+		State:         str("System.State"),
+		AreaPath:      str("System.AreaPath"),
+		IterationPath: str("System.IterationPath"),
+		AssignedTo:    identityName(str("System.AssignedTo")),
+		Description:   str("System.Description"),
+		Priority:      num("Microsoft.VSTS.Common.Priority"),
+		Tags:          splitTags(str("System.Tags")),
+		ParentID:      num("System.Parent"),
+		URL:           wi.Links.HTML.Href,
+	}
+	if item.Description == "" {
+		item.Description = str("Microsoft.VSTS.TCM.ReproSteps") // Bugs keep their text here
+	}
+	if t, err := parseTime(str("System.CreatedDate")); err == nil {
+		item.CreatedAt = t
+	}
+	if t, err := parseTime(str("System.ChangedDate")); err == nil {
+		item.ChangedAt = t
+	}
+	if item.ParentID == 0 {
+		for _, rel := range wi.Relations {
+			if rel.Rel == "System.LinkTypes.Hierarchy-Reverse" {
+				item.ParentID, _ = strconv.Atoi(rel.URL[strings.LastIndex(rel.URL, "/")+1:])
+			}
+		}
+	}
+	return item
+}
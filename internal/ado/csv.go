@@ -0,0 +1,109 @@
+package ado
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseCSV reads work items from an Azure DevOps query exported as CSV
+// ("Export to CSV" on a query or backlog). Columns are matched by their
+// display names; only ID and Title are required.
+//
+// Parents come from a Parent column if the query included one. Tree
+// queries instead export the title in "Title 1", "Title 2", ... columns by
+// depth, and each row's parent is the nearest earlier row one level up.
+func ParseCSV(r io.Reader) ([]WorkItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	cols := make(map[string]int)
+	var titleLevels []int // column index per tree depth
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		cols[name] = i
+		if rest, ok := strings.CutPrefix(name, "title "); ok {
+			if level, err := strconv.Atoi(rest); err == nil && level >= 1 {
+				for len(titleLevels) < level {
+					titleLevels = append(titleLevels, -1)
+				}
+				titleLevels[level-1] = i
+			}
+		}
+	}
+	if _, ok := cols["id"]; !ok {
+		return nil, fmt.Errorf("CSV has no ID column (export the query with the ID field)")
+	}
+	if _, ok := cols["title"]; !ok && len(titleLevels) == 0 {
+		return nil, fmt.Errorf("CSV has no Title column")
+	}
+
+	var items []WorkItem
+	var ancestors []int // work item ID at each tree depth
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		get := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		id, err := strconv.Atoi(get("id"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid ID %q", line, get("id"))
+		}
+		item := WorkItem{
+			ID:            id,
+			Type:          get("work item type"),
+			Title:         get("title"),
+			State:         get("state"),
+			AreaPath:      get("area path"),
+			IterationPath: get("iteration path"),
+			AssignedTo:    identityName(get("assigned to")),
+			Description:   get("description"),
+			Tags:          splitTags(get("tags")),
+		}
+		item.Priority, _ = strconv.Atoi(get("priority"))
+		item.ParentID, _ = strconv.Atoi(get("parent"))
+		if t, err := parseTime(get("created date")); err == nil {
+			item.CreatedAt = t
+		}
+		if t, err := parseTime(get("changed date")); err == nil {
+			item.ChangedAt = t
+		}
+
+		for level, col := range titleLevels {
+			if col < 0 || col >= len(record) || strings.TrimSpace(record[col]) == "" {
+				continue
+			}
+			if item.Title == "" {
+				item.Title = strings.TrimSpace(record[col])
+			}
+			ancestors = append(ancestors[:min(level, len(ancestors))], id)
+			if item.ParentID == 0 && level > 0 && len(ancestors) > level {
+				item.ParentID = ancestors[level-1]
+			}
+			break
+		}
+
+		if item.Title == "" {
+			return nil, fmt.Errorf("line %d: work item %d has no title", line, id)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
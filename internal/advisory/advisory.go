@@ -0,0 +1,95 @@
+// Package advisory holds the security advisory metadata of issues (CVE,
+// CVSS score, affected versions, embargo date) set with bd security. The
+// metadata travels with issues through the JSONL like labels and refs.
+package advisory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ErrUnsupported is returned for storage backends without advisory metadata.
+var ErrUnsupported = errors.New("security advisories require the SQLite backend")
+
+// Store is the storage interface for advisory metadata.
+type Store interface {
+	SetSecurity(ctx context.Context, issueID string, sec *types.Security, actor string) error
+	GetSecurity(ctx context.Context, issueID string) (*types.Security, error)
+	GetSecurityForIssues(ctx context.Context, issueIDs []string) (map[string]*types.Security, error)
+	GetSecurityIssueIDs(ctx context.Context, minCVSS float64) ([]string, error)
+}
+
+// For returns s as an advisory store.
+func For(s storage.Storage) (Store, error) {
+	as, ok := s.(Store)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return as, nil
+}
+
+// Populate fills in the Security of issues, as exports and show do. Stores
+// that don't keep advisories leave issues unchanged.
+func Populate(ctx context.Context, s storage.Storage, issues []*types.Issue) error {
+	as, err := For(s)
+	if err != nil || len(issues) == 0 {
+		return nil
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	byIssue, err := as.GetSecurityForIssues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get security advisories: %w", err)
+	}
+	for _, issue := range issues {
+		issue.Security = byIssue[issue.ID]
+	}
+	return nil
+}
+
+var cvePattern = regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`)
+
+// ParseCVE validates a CVE ID, normalizing its case.
+func ParseCVE(value string) (string, error) {
+	cve := strings.ToUpper(strings.TrimSpace(value))
+	if !cvePattern.MatchString(cve) {
+		return "", fmt.Errorf("invalid CVE ID %q (expected CVE-YYYY-NNNN)", value)
+	}
+	return cve, nil
+}
+
+// ParseCVSS validates a CVSS base score.
+func ParseCVSS(value string) (float64, error) {
+	score, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || score < 0 || score > 10 {
+		return 0, fmt.Errorf("invalid CVSS score %q (expected 0.0-10.0)", value)
+	}
+	return score, nil
+}
+
+// Format renders an advisory on one line for bd show and bd security.
+func Format(sec *types.Security) string {
+	var parts []string
+	if sec.CVE != "" {
+		parts = append(parts, sec.CVE)
+	}
+	if sec.CVSS != nil {
+		parts = append(parts, fmt.Sprintf("CVSS %.1f (%s)", *sec.CVSS, sec.Severity()))
+	}
+	if sec.AffectedVersions != "" {
+		parts = append(parts, "affects "+sec.AffectedVersions)
+	}
+	if sec.EmbargoUntil != nil {
+		parts = append(parts, "embargoed until "+sec.EmbargoUntil.Local().Format("2006-01-02 15:04"))
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,38 @@
+package advisory
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParseCVE(t *testing.T) {
+	for value, want := range map[string]string{
+		"CVE-2026-1234":   "CVE-2026-1234",
+		" cve-2026-12345": "CVE-2026-12345",
+		"CVE-26-1234":     "",
+		"GHSA-xxxx":       "",
+	} {
+		got, err := ParseCVE(value)
+		if got != want || (err != nil) != (want == "") {
+			t.Errorf("ParseCVE(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+}
+
+func TestParseCVSSAndSeverity(t *testing.T) {
+	for value, want := range map[string]string{"9.8": "critical", "7": "high", "4.0": "medium", "0.1": "low", "0": "none"} {
+		score, err := ParseCVSS(value)
+		if err != nil {
+			t.Fatalf("ParseCVSS(%q): %v", value, err)
+		}
+		if got := (&types.Security{CVSS: &score}).Severity(); got != want {
+			t.Errorf("severity of %s = %q, want %q", value, got, want)
+		}
+	}
+	for _, value := range []string{"10.1", "-1", "high"} {
+		if _, err := ParseCVSS(value); err == nil {
+			t.Errorf("ParseCVSS(%q) accepted", value)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package analytics builds a denormalized snapshot of the issue database
+// for BI tools and DuckDB (bd export analytics): one fact table of issues,
+// one of status transitions and one of work logs. The tables are meant to
+// be stable for queries, independent of the operational schema.
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/cfd"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Table names, used for SQLite tables and Parquet file names.
+const (
+	IssuesTable      = "issues"
+	TransitionsTable = "transitions"
+	WorkLogsTable    = "work_logs"
+)
+
+// IssueFact is one issue, with its labels, parent and timings flattened in.
+// Times are Unix milliseconds, so every tool reads them the same way.
+type IssueFact struct {
+	ID               string   `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Title            string   `parquet:"name=title, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Status           string   `parquet:"name=status, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Priority         int32    `parquet:"name=priority, type=INT32"`
+	IssueType        string   `parquet:"name=issue_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Assignee         string   `parquet:"name=assignee, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CreatedBy        string   `parquet:"name=created_by, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ParentID         string   `parquet:"name=parent_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Labels           string   `parquet:"name=labels, type=BYTE_ARRAY, convertedtype=UTF8"` // Comma-separated, sorted
+	CreatedAt        int64    `parquet:"name=created_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	UpdatedAt        int64    `parquet:"name=updated_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	StartedAt        *int64   `parquet:"name=started_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	ClosedAt         *int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	DueAt            *int64   `parquet:"name=due_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	EstimatedMinutes *int32   `parquet:"name=estimated_minutes, type=INT32, repetitiontype=OPTIONAL"`
+	LeadTimeHours    *float64 `parquet:"name=lead_time_hours, type=DOUBLE, repetitiontype=OPTIONAL"`  // Created to closed
+	CycleTimeHours   *float64 `parquet:"name=cycle_time_hours, type=DOUBLE, repetitiontype=OPTIONAL"` // Started to closed
+	Comments         int32    `parquet:"name=comments, type=INT32"`
+	Dependencies     int32    `parquet:"name=dependencies, type=INT32"` // Blocking dependencies
+}
+
+// TransitionFact is one change of an issue's status.
+type TransitionFact struct {
+	IssueID    string `parquet:"name=issue_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	At         int64  `parquet:"name=at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	FromStatus string `parquet:"name=from_status, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ToStatus   string `parquet:"name=to_status, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Actor      string `parquet:"name=actor, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// WorkLogFact is one report of resources spent on an issue.
+type WorkLogFact struct {
+	ID      string  `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	IssueID string  `parquet:"name=issue_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	At      int64   `parquet:"name=at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Agent   string  `parquet:"name=agent, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Session string  `parquet:"name=session, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Model   string  `parquet:"name=model, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Tokens  int64   `parquet:"name=tokens, type=INT64"`
+	CostUSD float64 `parquet:"name=cost_usd, type=DOUBLE"`
+}
+
+// Snapshot is the fact tables.
+type Snapshot struct {
+	Issues      []IssueFact
+	Transitions []TransitionFact
+	WorkLogs    []WorkLogFact
+}
+
+// Millis converts a time to Unix milliseconds.
+func Millis(t time.Time) int64 {
+	return t.UnixMilli()
+}
+
+func optionalMillis(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	ms := t.UnixMilli()
+	return &ms
+}
+
+// Build assembles the issue and transition tables from the given issues,
+// tombstones aside. Work logs come from the caller.
+func Build(ctx context.Context, s storage.Storage, issues []*types.Issue, workLogs []WorkLogFact) (*Snapshot, error) {
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	comments, err := s.GetCommentsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	deps, err := s.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+
+	snap := &Snapshot{Issues: []IssueFact{}, Transitions: []TransitionFact{}, WorkLogs: workLogs}
+	if snap.WorkLogs == nil {
+		snap.WorkLogs = []WorkLogFact{}
+	}
+	for _, issue := range issues {
+		if issue.Status == types.StatusTombstone {
+			continue
+		}
+		events, err := s.GetEvents(ctx, issue.ID, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get events of %s: %w", issue.ID, err)
+		}
+		history := cfd.NewHistory(issue, events)
+
+		fact := IssueFact{
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    string(issue.Status),
+			Priority:  int32(issue.Priority), // #nosec G115 -- priorities are 0-4
+			IssueType: string(issue.IssueType),
+			Assignee:  issue.Assignee,
+			CreatedBy: issue.CreatedBy,
+			CreatedAt: Millis(issue.CreatedAt),
+			UpdatedAt: Millis(issue.UpdatedAt),
+			ClosedAt:  optionalMillis(issue.ClosedAt),
+			DueAt:     optionalMillis(issue.DueAt),
+			Comments:  int32(len(comments[issue.ID])), // #nosec G115 -- comment counts fit
+		}
+		sorted := append([]string(nil), labels[issue.ID]...)
+		sort.Strings(sorted)
+		fact.Labels = strings.Join(sorted, ",")
+		if issue.EstimatedMinutes != nil {
+			minutes := int32(*issue.EstimatedMinutes) // #nosec G115 -- estimates fit
+			fact.EstimatedMinutes = &minutes
+		}
+		for _, dep := range deps[issue.ID] {
+			switch {
+			case dep.Type == types.DepParentChild:
+				fact.ParentID = dep.DependsOnID
+			case dep.Type.AffectsReadyWork():
+				fact.Dependencies++
+			}
+		}
+
+		from := history.Initial
+		var started *time.Time
+		for _, tr := range history.Transitions {
+			snap.Transitions = append(snap.Transitions, TransitionFact{
+				IssueID:    issue.ID,
+				At:         Millis(tr.At),
+				FromStatus: string(from),
+				ToStatus:   string(tr.Status),
+				Actor:      tr.Actor,
+			})
+			if tr.Status == types.StatusInProgress && started == nil {
+				at := tr.At
+				started = &at
+			}
+			from = tr.Status
+		}
+		fact.StartedAt = optionalMillis(started)
+		if issue.ClosedAt != nil {
+			lead := issue.ClosedAt.Sub(issue.CreatedAt).Hours()
+			fact.LeadTimeHours = &lead
+			if started != nil && started.Before(*issue.ClosedAt) {
+				cycle := issue.ClosedAt.Sub(*started).Hours()
+				fact.CycleTimeHours = &cycle
+			}
+		}
+		snap.Issues = append(snap.Issues, fact)
+	}
+	return snap, nil
+}
@@ -0,0 +1,164 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func buildTestSnapshot(t *testing.T) *Snapshot {
+	t.Helper()
+	ctx := context.Background()
+	s, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Now().Add(-48 * time.Hour)
+	for _, id := range []string{"bd-1", "bd-2", "bd-3"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, CreatedAt: created}
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: "bd-2", DependsOnID: "bd-1", Type: types.DepParentChild}, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: "bd-2", DependsOnID: "bd-3", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	for _, label := range []string{"ui", "backend"} {
+		if err := s.AddLabel(ctx, "bd-1", label, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.AddIssueComment(ctx, "bd-1", "alice", "on it"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateIssue(ctx, "bd-1", map[string]interface{}{"status": string(types.StatusInProgress)}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseIssue(ctx, "bd-1", "done", "alice", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workLogs := []WorkLogFact{{ID: "u-1", IssueID: "bd-1", At: Millis(time.Now()), Agent: "alice", Tokens: 1200, CostUSD: 0.5}}
+	snap, err := Build(ctx, s, issues, workLogs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snap
+}
+
+func TestBuild(t *testing.T) {
+	snap := buildTestSnapshot(t)
+	if len(snap.Issues) != 3 {
+		t.Fatalf("got %d issues, want 3", len(snap.Issues))
+	}
+	facts := make(map[string]IssueFact)
+	for _, f := range snap.Issues {
+		facts[f.ID] = f
+	}
+
+	closed := facts["bd-1"]
+	if closed.Labels != "backend,ui" || closed.Comments != 1 {
+		t.Errorf("bd-1 labels = %q, comments = %d", closed.Labels, closed.Comments)
+	}
+	if closed.StartedAt == nil || closed.ClosedAt == nil || closed.LeadTimeHours == nil || closed.CycleTimeHours == nil {
+		t.Fatalf("bd-1 timings missing: %+v", closed)
+	}
+	if *closed.LeadTimeHours < 47 || *closed.CycleTimeHours > *closed.LeadTimeHours {
+		t.Errorf("lead = %v, cycle = %v", *closed.LeadTimeHours, *closed.CycleTimeHours)
+	}
+	if child := facts["bd-2"]; child.ParentID != "bd-1" || child.Dependencies != 1 {
+		t.Errorf("bd-2 parent = %q, dependencies = %d", child.ParentID, child.Dependencies)
+	}
+	if open := facts["bd-3"]; open.StartedAt != nil || open.LeadTimeHours != nil {
+		t.Errorf("bd-3 should have no timings: %+v", open)
+	}
+
+	if len(snap.Transitions) != 2 {
+		t.Fatalf("got %d transitions, want 2: %+v", len(snap.Transitions), snap.Transitions)
+	}
+	if tr := snap.Transitions[0]; tr.FromStatus != "open" || tr.ToStatus != "in_progress" || tr.Actor != "alice" {
+		t.Errorf("first transition = %+v", tr)
+	}
+	if tr := snap.Transitions[1]; tr.FromStatus != "in_progress" || tr.ToStatus != "closed" {
+		t.Errorf("second transition = %+v", tr)
+	}
+}
+
+func TestWriteSQLite(t *testing.T) {
+	snap := buildTestSnapshot(t)
+	path := filepath.Join(t.TempDir(), "analytics.db")
+	// Writing twice replaces the database rather than failing on its tables
+	for i := 0; i < 2; i++ {
+		if err := WriteSQLite(context.Background(), path, snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for table, want := range map[string]int{IssuesTable: 3, TransitionsTable: 2, WorkLogsTable: 1} {
+		var got int
+		if err := db.QueryRow("SELECT count(*) FROM " + table).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s has %d rows, want %d", table, got, want)
+		}
+	}
+	var closedAt sql.NullString
+	if err := db.QueryRow("SELECT closed_at FROM issues WHERE id = 'bd-3'").Scan(&closedAt); err != nil {
+		t.Fatal(err)
+	}
+	if closedAt.Valid {
+		t.Errorf("open issue has closed_at %q", closedAt.String)
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	snap := buildTestSnapshot(t)
+	files, err := WriteParquet(filepath.Join(t.TempDir(), "out"), snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int64{3, 2, 1}
+	if len(files) != len(want) {
+		t.Fatalf("wrote %v", files)
+	}
+	for i, path := range files {
+		fr, err := local.NewLocalFileReader(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pr, err := reader.NewParquetReader(fr, nil, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := pr.GetNumRows(); got != want[i] {
+			t.Errorf("%s has %d rows, want %d", path, got, want[i])
+		}
+		pr.ReadStop()
+		_ = fr.Close()
+	}
+}
@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// WriteParquet writes the snapshot to dir as one Parquet file per table
+// (issues.parquet, transitions.parquet, work_logs.parquet), creating dir
+// if needed. It returns the files written.
+func WriteParquet(dir string, snap *Snapshot) ([]string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	tables := []struct {
+		name string
+		obj  interface{}
+		rows []interface{}
+	}{
+		{IssuesTable, new(IssueFact), rowsOf(snap.Issues)},
+		{TransitionsTable, new(TransitionFact), rowsOf(snap.Transitions)},
+		{WorkLogsTable, new(WorkLogFact), rowsOf(snap.WorkLogs)},
+	}
+	var files []string
+	for _, t := range tables {
+		path := filepath.Join(dir, t.name+".parquet")
+		if err := writeParquetFile(path, t.obj, t.rows); err != nil {
+			return files, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+func rowsOf[T any](facts []T) []interface{} {
+	rows := make([]interface{}, len(facts))
+	for i := range facts {
+		rows[i] = facts[i]
+	}
+	return rows
+}
+
+func writeParquetFile(path string, obj interface{}, rows []interface{}) (err error) {
+	f, err := os.Create(path) // #nosec G304 -- user-chosen export directory
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	pw, err := writer.NewParquetWriterFromWriter(f, obj, 1)
+	if err != nil {
+		return err
+	}
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	for _, row := range rows {
+		if err := pw.Write(row); err != nil {
+			return err
+		}
+	}
+	return pw.WriteStop()
+}
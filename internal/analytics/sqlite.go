@@ -0,0 +1,122 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	// SQLite driver, as used by the issue database
+	_ "github.com/ncruces/go-sqlite3/driver"
+	_ "github.com/ncruces/go-sqlite3/embed"
+)
+
+// sqliteTimeLayout is how times are stored in the SQLite snapshot: UTC ISO
+// 8601 text, which SQLite date functions and DuckDB both parse.
+const sqliteTimeLayout = "2006-01-02T15:04:05.000Z"
+
+const sqliteSchema = `
+CREATE TABLE issues (
+	id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	status TEXT NOT NULL,
+	priority INTEGER NOT NULL,
+	issue_type TEXT NOT NULL,
+	assignee TEXT NOT NULL,
+	created_by TEXT NOT NULL,
+	parent_id TEXT NOT NULL,
+	labels TEXT NOT NULL,
+	created_at TEXT NOT NULL,
+	updated_at TEXT NOT NULL,
+	started_at TEXT,
+	closed_at TEXT,
+	due_at TEXT,
+	estimated_minutes INTEGER,
+	lead_time_hours REAL,
+	cycle_time_hours REAL,
+	comments INTEGER NOT NULL,
+	dependencies INTEGER NOT NULL
+);
+CREATE TABLE transitions (
+	issue_id TEXT NOT NULL,
+	at TEXT NOT NULL,
+	from_status TEXT NOT NULL,
+	to_status TEXT NOT NULL,
+	actor TEXT NOT NULL
+);
+CREATE INDEX idx_transitions_issue ON transitions(issue_id);
+CREATE TABLE work_logs (
+	id TEXT NOT NULL,
+	issue_id TEXT NOT NULL,
+	at TEXT NOT NULL,
+	agent TEXT NOT NULL,
+	session TEXT NOT NULL,
+	model TEXT NOT NULL,
+	tokens INTEGER NOT NULL,
+	cost_usd REAL NOT NULL
+);
+CREATE INDEX idx_work_logs_issue ON work_logs(issue_id);
+`
+
+func sqliteTime(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format(sqliteTimeLayout)
+}
+
+func sqliteOptionalTime(ms *int64) interface{} {
+	if ms == nil {
+		return nil
+	}
+	return sqliteTime(*ms)
+}
+
+// WriteSQLite writes the snapshot to a new SQLite database at path,
+// replacing any file there.
+func WriteSQLite(ctx context.Context, path string, snap *Snapshot) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() { _ = db.Close() }()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(ctx, sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create tables: %w", err)
+	}
+
+	for _, f := range snap.Issues {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO issues (id, title, status, priority, issue_type, assignee, created_by, parent_id, labels,
+				created_at, updated_at, started_at, closed_at, due_at, estimated_minutes,
+				lead_time_hours, cycle_time_hours, comments, dependencies)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, f.ID, f.Title, f.Status, f.Priority, f.IssueType, f.Assignee, f.CreatedBy, f.ParentID, f.Labels,
+			sqliteTime(f.CreatedAt), sqliteTime(f.UpdatedAt), sqliteOptionalTime(f.StartedAt),
+			sqliteOptionalTime(f.ClosedAt), sqliteOptionalTime(f.DueAt), f.EstimatedMinutes,
+			f.LeadTimeHours, f.CycleTimeHours, f.Comments, f.Dependencies); err != nil {
+			return fmt.Errorf("failed to write issue %s: %w", f.ID, err)
+		}
+	}
+	for _, f := range snap.Transitions {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO transitions (issue_id, at, from_status, to_status, actor) VALUES (?, ?, ?, ?, ?)
+		`, f.IssueID, sqliteTime(f.At), f.FromStatus, f.ToStatus, f.Actor); err != nil {
+			return fmt.Errorf("failed to write transition of %s: %w", f.IssueID, err)
+		}
+	}
+	for _, f := range snap.WorkLogs {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO work_logs (id, issue_id, at, agent, session, model, tokens, cost_usd) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, f.ID, f.IssueID, sqliteTime(f.At), f.Agent, f.Session, f.Model, f.Tokens, f.CostUSD); err != nil {
+			return fmt.Errorf("failed to write work log %s: %w", f.ID, err)
+		}
+	}
+	return tx.Commit()
+}
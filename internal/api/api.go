@@ -0,0 +1,157 @@
+// Package api is the daemon's HTTP API, served on api.listen for dashboards
+// and integrations: a GraphQL endpoint at /graphql and a WebSocket stream
+// of mutation events at /events.
+//
+// Private issues are never served. With a token, only requests carrying it
+// (as "Authorization: Bearer <token>" or token=, which browsers need for
+// WebSockets) see internal issues; the others see public issues only.
+// Without a token every request sees internal issues, as befits a listener
+// on localhost.
+//
+// With OIDC configured, users sign in through the company's identity
+// provider instead, and the role their groups map to decides what they see;
+// requests from nobody signed in see public issues only.
+//
+// API tokens (bd token create) identify each agent or integration: tokens
+// with the read scope see internal issues. Once any token is active,
+// requests without one see public issues only, as with a static token.
+// GraphQL mutations need a token with a write scope (write:issues,
+// write:comments); no other credential can change data.
+//
+// Every request is logged in the access log (bd server audit), and clients
+// over their rate limit are refused with 429 Too Many Requests, as are
+// addresses that keep failing authentication, before authenticating.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/apitoken"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+// Options configures the API handler.
+type Options struct {
+	Token  string      // Bearer token that lets clients see internal issues
+	Events EventSource // Mutation events for /events; nil disables it
+	Writer Writer      // Applies GraphQL mutations; nil makes the API read-only
+	OIDC   *OIDC       // Single sign-on; nil disables it
+
+	// RateLimits are requests per second by token name or signed-in user;
+	// "*" applies to every other client, anonymous ones by address.
+	RateLimits map[string]float64
+	// AuditRetention is how long the access log keeps requests; zero keeps
+	// them forever.
+	AuditRetention time.Duration
+}
+
+// Handler serves the API for s. The handler is an io.Closer: closing it,
+// once the server has shut down, writes the buffered access log.
+func Handler(s storage.Storage, opts Options) http.Handler {
+	mux := http.NewServeMux()
+	gql := graphqlHandler(s, opts.Writer)
+	mux.Handle("GET /graphql", gql)
+	mux.Handle("POST /graphql", gql)
+	if opts.Events != nil {
+		mux.Handle("GET /events", eventsHandler(s, opts.Events, opts.Token))
+	}
+	if opts.OIDC != nil {
+		opts.OIDC.routes(mux)
+	}
+	tokens, _ := apitoken.For(s)
+	audit, _ := AuditFor(s)
+	return &gate{next: mux, opts: opts, tokens: tokens, log: newAccessLog(audit, opts.AuditRetention), limits: newRateLimits(opts.RateLimits)}
+}
+
+// principal is who made a request, and the visibility level they may see.
+type principal struct {
+	Subject  string // Token name, signed-in user or ActorStaticToken; empty if anonymous
+	Audience string
+	TokenID  string   // The API token the request carried, if any
+	Scopes   []string // Scopes of that token
+}
+
+type principalKey struct{}
+
+// requestPrincipal works out who made a request. A request with an invalid
+// API token is refused, so misconfigured clients fail loudly instead of
+// silently seeing fewer issues.
+func requestPrincipal(r *http.Request, opts Options, tokens apitoken.Store) (principal, error) {
+	given := credential(r)
+	if opts.Token != "" && subtle.ConstantTimeCompare([]byte(given), []byte(opts.Token)) == 1 {
+		return principal{Subject: ActorStaticToken, Audience: visibility.Internal}, nil
+	}
+	if tokens != nil && strings.HasPrefix(given, apitoken.Prefix) {
+		token, err := apitoken.Authenticate(r.Context(), tokens, given)
+		if err != nil {
+			return principal{}, err
+		}
+		p := principal{Subject: token.Name, Audience: visibility.Public, TokenID: token.ID, Scopes: token.Scopes}
+		if apitoken.HasScope(token, apitoken.ScopeRead) {
+			p.Audience = visibility.Internal
+		}
+		return p, nil
+	}
+	if opts.OIDC != nil {
+		if p, ok := opts.OIDC.identify(r); ok {
+			return p, nil
+		}
+		return principal{Audience: visibility.Public}, nil
+	}
+	if opts.Token == "" && !hasActiveTokens(r.Context(), tokens) {
+		return principal{Audience: visibility.Internal}, nil
+	}
+	return principal{Audience: visibility.Public}, nil
+}
+
+// credential returns the token a request carries, if any.
+func credential(r *http.Request) string {
+	if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(auth)
+	}
+	return r.URL.Query().Get("token")
+}
+
+// hasToken reports whether a request carries the token.
+func hasToken(r *http.Request, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(credential(r)), []byte(token)) == 1
+}
+
+// hasActiveTokens reports whether any API token may be used, in which case
+// anonymous requests no longer see internal issues. Errors count as yes.
+func hasActiveTokens(ctx context.Context, tokens apitoken.Store) bool {
+	if tokens == nil {
+		return false
+	}
+	list, err := tokens.ListAPITokens(ctx)
+	if err != nil {
+		return true
+	}
+	now := time.Now()
+	for _, t := range list {
+		if t.Active(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// audience returns the visibility level of the request behind ctx, public
+// if unknown.
+func audience(ctx context.Context) string {
+	return principalOf(ctx).Audience
+}
+
+// principalOf returns who made the request behind ctx, an anonymous
+// member of the public if unknown.
+func principalOf(ctx context.Context) principal {
+	if p, ok := ctx.Value(principalKey{}).(principal); ok {
+		return p
+	}
+	return principal{Audience: visibility.Public}
+}
@@ -0,0 +1,444 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/beads/internal/apitoken"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"golang.org/x/time/rate"
+)
+
+// AuditStore is the storage interface for the API access log.
+type AuditStore interface {
+	RecordAPIAccess(ctx context.Context, entries []*types.APIAccess) error
+	GetAPIAccess(ctx context.Context, filter types.APIAccessFilter) ([]*types.APIAccess, error)
+	PruneAPIAccess(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ErrAuditUnsupported is returned for storage backends without an access
+// log.
+var ErrAuditUnsupported = errors.New("the API access log requires the SQLite backend")
+
+// AuditFor returns s as an access log store.
+func AuditFor(s storage.Storage) (AuditStore, error) {
+	as, ok := s.(AuditStore)
+	if !ok {
+		return nil, ErrAuditUnsupported
+	}
+	return as, nil
+}
+
+const (
+	// pruneInterval is how often the access log drops entries older than
+	// the retention period.
+	pruneInterval = time.Hour
+	// Access log entries are written in batches of up to accessLogBatch,
+	// at least every accessLogFlush; up to accessLogBuffer wait meanwhile,
+	// and more are dropped.
+	accessLogBatch  = 256
+	accessLogFlush  = time.Second
+	accessLogBuffer = 4096
+)
+
+// Each client address may fail authentication authFailureBurst times in a
+// row, then once per authFailureInterval; past that its requests are
+// refused before authenticating, so tokens can't be guessed at speed.
+const (
+	authFailureInterval = time.Second
+	authFailureBurst    = 10
+)
+
+// sweepInterval is how often idle rate limiters are dropped.
+const sweepInterval = time.Minute
+
+// Actors of requests without a token name or signed-in user.
+const (
+	ActorStaticToken = "token" // Carried api.token
+	ActorAnonymous   = "anonymous"
+)
+
+// actorOf names who made a request in the access log.
+func actorOf(p principal) string {
+	if p.Subject != "" {
+		return p.Subject
+	}
+	return ActorAnonymous
+}
+
+// limiterSet holds a token bucket per client. Buckets that have refilled
+// are dropped, as a new one behaves the same, so the set only holds
+// clients seen recently.
+type limiterSet struct {
+	mu        sync.Mutex
+	limiters  map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+// get returns the bucket for key, creating it with r and burst if needed.
+func (s *limiterSet) get(key string, r rate.Limit, burst int) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.lastSweep = now
+		for k, l := range s.limiters {
+			if l.TokensAt(now) >= float64(l.Burst()) {
+				delete(s.limiters, k)
+			}
+		}
+	}
+	if s.limiters == nil {
+		s.limiters = make(map[string]*rate.Limiter)
+	}
+	l := s.limiters[key]
+	if l == nil {
+		l = rate.NewLimiter(r, burst)
+		s.limiters[key] = l
+	}
+	return l
+}
+
+// peek returns the bucket for key, or nil if there is none.
+func (s *limiterSet) peek(key string) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limiters[key]
+}
+
+// size returns how many buckets the set holds.
+func (s *limiterSet) size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.limiters)
+}
+
+// take spends a request from l, or reports how long until one is left.
+func take(l *rate.Limiter) (bool, time.Duration) {
+	res := l.Reserve()
+	if delay := res.Delay(); delay > 0 {
+		res.Cancel()
+		return false, delay
+	}
+	return true, 0
+}
+
+// rateLimits throttles each client: API tokens by ID, signed-in users by
+// name and anonymous clients by address.
+type rateLimits struct {
+	rates    map[string]rate.Limit // Token name or user (or "*" for every client) -> requests per second
+	limiters limiterSet
+}
+
+func newRateLimits(perSecond map[string]float64) *rateLimits {
+	if len(perSecond) == 0 {
+		return nil
+	}
+	l := &rateLimits{rates: make(map[string]rate.Limit)}
+	for name, r := range perSecond {
+		if r > 0 {
+			l.rates[strings.ToLower(name)] = rate.Limit(r)
+		}
+	}
+	return l
+}
+
+// allow reports whether a client may make a request now, and if not, how
+// long until it may.
+func (l *rateLimits) allow(key, name string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	r, ok := l.rates[strings.ToLower(name)]
+	if !ok {
+		if r, ok = l.rates["*"]; !ok {
+			return true, 0
+		}
+	}
+	return take(l.limiters.get(key, r, max(int(r), 1)))
+}
+
+// gate throttles, authenticates and logs every request before passing it
+// on.
+type gate struct {
+	next     http.Handler
+	opts     Options
+	tokens   apitoken.Store
+	log      *accessLog
+	limits   *rateLimits
+	failures limiterSet // Failed authentications by client address
+}
+
+func (g *gate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	host := remoteHost(r)
+	var p principal
+	if failed := g.failures.peek(host); failed != nil && failed.Tokens() < 1 {
+		retryAfter(rec, time.Duration((1-failed.Tokens())*float64(authFailureInterval)))
+		http.Error(rec, "too many failed authentication attempts", http.StatusTooManyRequests)
+	} else if pr, err := requestPrincipal(r, g.opts, g.tokens); err != nil {
+		g.failures.get(host, rate.Every(authFailureInterval), authFailureBurst).Allow()
+		http.Error(rec, err.Error(), http.StatusUnauthorized)
+	} else if ok, wait := g.limits.allow(limitKey(pr, r), pr.Subject); !ok {
+		p = pr
+		retryAfter(rec, wait)
+		http.Error(rec, "rate limit exceeded", http.StatusTooManyRequests)
+	} else {
+		p = pr
+		g.next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), principalKey{}, p)))
+	}
+	g.log.add(&types.APIAccess{
+		Time:      start.UTC(),
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Actor:     actorOf(p),
+		TokenID:   p.TokenID,
+		Remote:    host,
+		Status:    rec.status,
+		LatencyMS: time.Since(start).Milliseconds(),
+	})
+}
+
+// Close writes the access log entries still buffered. Call it once the
+// server has shut down.
+func (g *gate) Close() error {
+	return g.log.close()
+}
+
+func retryAfter(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", fmt.Sprint(max(int(math.Ceil(wait.Seconds())), 1)))
+}
+
+// limitKey identifies a client for rate limiting.
+func limitKey(p principal, r *http.Request) string {
+	switch {
+	case p.TokenID != "":
+		return "token:" + p.TokenID
+	case p.Subject != "":
+		return "user:" + p.Subject
+	default:
+		return "addr:" + remoteHost(r)
+	}
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// accessLog writes access log entries from one goroutine, in batches, so
+// requests never wait on the database. The log is best effort: entries
+// that find the buffer full are dropped, and write errors are ignored.
+type accessLog struct {
+	store     AuditStore
+	retention time.Duration
+	entries   chan *types.APIAccess
+	flushes   chan chan struct{}
+	done      chan struct{}
+	mu        sync.RWMutex
+	closed    bool
+	lastPrune time.Time
+}
+
+// newAccessLog starts writing entries to store, or returns nil (which
+// logs nothing) when store is nil.
+func newAccessLog(store AuditStore, retention time.Duration) *accessLog {
+	if store == nil {
+		return nil
+	}
+	l := &accessLog{
+		store:     store,
+		retention: retention,
+		entries:   make(chan *types.APIAccess, accessLogBuffer),
+		flushes:   make(chan chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+func (l *accessLog) add(e *types.APIAccess) {
+	if l == nil {
+		return
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return
+	}
+	select {
+	case l.entries <- e:
+	default:
+	}
+}
+
+// flush waits until every entry added so far is written.
+func (l *accessLog) flush() {
+	if l == nil {
+		return
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return
+	}
+	ack := make(chan struct{})
+	l.flushes <- ack
+	<-ack
+}
+
+func (l *accessLog) close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.entries)
+	}
+	l.mu.Unlock()
+	<-l.done
+	return nil
+}
+
+func (l *accessLog) run() {
+	defer close(l.done)
+	ticker := time.NewTicker(accessLogFlush)
+	defer ticker.Stop()
+	var batch []*types.APIAccess
+	write := func() {
+		l.write(batch)
+		batch = nil
+	}
+	for {
+		select {
+		case e, ok := <-l.entries:
+			if !ok {
+				write()
+				return
+			}
+			if batch = append(batch, e); len(batch) >= accessLogBatch {
+				write()
+			}
+		case <-ticker.C:
+			write()
+		case ack := <-l.flushes:
+			for drained := false; !drained; {
+				select {
+				case e := <-l.entries:
+					batch = append(batch, e)
+				default:
+					drained = true
+				}
+			}
+			write()
+			close(ack)
+		}
+	}
+}
+
+// write records a batch, and drops entries past the retention period when
+// that is due.
+func (l *accessLog) write(batch []*types.APIAccess) {
+	ctx := context.Background()
+	if len(batch) > 0 {
+		_ = l.store.RecordAPIAccess(ctx, batch)
+	}
+	if l.retention <= 0 {
+		return
+	}
+	now := time.Now().UTC()
+	if now.Sub(l.lastPrune) >= pruneInterval {
+		l.lastPrune = now
+		_, _ = l.store.PruneAPIAccess(ctx, now.Add(-l.retention))
+	}
+}
+
+// statusRecorder remembers the status of a response. It passes hijacking
+// through, which WebSocket upgrades need.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not support hijacking")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// AccessSummary is one actor's requests in the access log.
+type AccessSummary struct {
+	Actor        string    `json:"actor"`
+	Requests     int       `json:"requests"`
+	Errors       int       `json:"errors"`    // Status 400 and up, including throttled
+	Throttled    int       `json:"throttled"` // Status 429
+	AvgLatencyMS int64     `json:"avg_latency_ms"`
+	MaxLatencyMS int64     `json:"max_latency_ms"`
+	Last         time.Time `json:"last"`
+}
+
+// SummarizeAccess groups access log entries by actor, busiest first.
+func SummarizeAccess(entries []*types.APIAccess) []*AccessSummary {
+	byActor := make(map[string]*AccessSummary)
+	total := make(map[string]int64)
+	for _, e := range entries {
+		s := byActor[e.Actor]
+		if s == nil {
+			s = &AccessSummary{Actor: e.Actor}
+			byActor[e.Actor] = s
+		}
+		s.Requests++
+		if e.Status >= 400 {
+			s.Errors++
+		}
+		if e.Status == http.StatusTooManyRequests {
+			s.Throttled++
+		}
+		total[e.Actor] += e.LatencyMS
+		s.MaxLatencyMS = max(s.MaxLatencyMS, e.LatencyMS)
+		if e.Time.After(s.Last) {
+			s.Last = e.Time
+		}
+	}
+	summaries := make([]*AccessSummary, 0, len(byActor))
+	for actor, s := range byActor {
+		s.AvgLatencyMS = total[actor] / int64(s.Requests)
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Requests != summaries[j].Requests {
+			return summaries[i].Requests > summaries[j].Requests
+		}
+		return summaries[i].Actor < summaries[j].Actor
+	})
+	return summaries
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/apitoken"
+	"github.com/steveyegge/beads/internal/types"
+	"golang.org/x/time/rate"
+)
+
+func TestAuditAndRateLimits(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	_, secret, err := apitoken.Create(ctx, s, "ci-agent", []string{apitoken.ScopeRead}, 0, "tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := Handler(s, Options{Token: "secret", RateLimits: map[string]float64{"ci-agent": 0.001}})
+
+	get := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/graphql?query={stats{total}}", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	// One request per 1000s: the second is throttled, other clients aren't
+	for i, tt := range []struct {
+		token string
+		want  int
+	}{
+		{secret, http.StatusOK},
+		{secret, http.StatusTooManyRequests},
+		{"secret", http.StatusOK},
+		{"", http.StatusOK},
+		{apitoken.Prefix + "bogus", http.StatusUnauthorized},
+	} {
+		if got := get(tt.token); got != tt.want {
+			t.Errorf("request %d: status %d, want %d", i, got, tt.want)
+		}
+	}
+
+	h.(*gate).log.flush()
+	entries, err := s.GetAPIAccess(ctx, types.APIAccessFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("logged %d requests, want 5", len(entries))
+	}
+	summaries := SummarizeAccess(entries)
+	byActor := make(map[string]*AccessSummary)
+	for _, sum := range summaries {
+		byActor[sum.Actor] = sum
+	}
+	if ci := byActor["ci-agent"]; ci == nil || ci.Requests != 2 || ci.Throttled != 1 || ci.Errors != 1 {
+		t.Errorf("ci-agent summary = %+v", ci)
+	}
+	if anon := byActor[ActorAnonymous]; anon == nil || anon.Requests != 2 || anon.Errors != 1 {
+		t.Errorf("anonymous summary = %+v", anon)
+	}
+	if tok := byActor[ActorStaticToken]; tok == nil || tok.Requests != 1 {
+		t.Errorf("static token summary = %+v", tok)
+	}
+	if summaries[0].Requests != 2 || summaries[len(summaries)-1].Actor != ActorStaticToken {
+		t.Errorf("summaries not busiest first: %v", summaries)
+	}
+	if entries[0].Time.After(time.Now()) {
+		t.Errorf("entry time %v in the future", entries[0].Time)
+	}
+}
+
+func TestFailedAuthenticationThrottled(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	_, secret, err := apitoken.Create(ctx, s, "ci-agent", []string{apitoken.ScopeRead}, 0, "tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := Handler(s, Options{})
+	defer func() { _ = h.(io.Closer).Close() }()
+
+	get := func(token, addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/graphql?query={stats{total}}", nil)
+		req.RemoteAddr = addr + ":4000"
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	for i := 0; i < authFailureBurst; i++ {
+		if rec := get(apitoken.Prefix+"guess", "10.0.0.1"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: status %d, want 401", i, rec.Code)
+		}
+	}
+	// Out of failures: refused before the token is even checked
+	rec := get(secret, "10.0.0.1")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("after %d failures: status %d, want 429 with Retry-After", authFailureBurst, rec.Code)
+	}
+	if rec := get(secret, "10.0.0.2"); rec.Code != http.StatusOK {
+		t.Errorf("other address: status %d, want 200", rec.Code)
+	}
+}
+
+func TestLimiterSetDropsIdleClients(t *testing.T) {
+	var set limiterSet
+	set.get("busy", rate.Every(time.Hour), 1).Allow()
+	set.get("idle", rate.Inf, 1)
+	set.lastSweep = time.Time{}
+	set.get("new", rate.Inf, 1)
+	if set.size() != 2 || set.peek("idle") != nil || set.peek("busy") == nil {
+		t.Errorf("after sweep: %d limiters, want busy and new", set.size())
+	}
+}
+
+func TestAccessLogClose(t *testing.T) {
+	s := newTestStore(t)
+	h := Handler(s, Options{})
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query={stats{total}}", nil)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if err := h.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := s.GetAPIAccess(context.Background(), types.APIAccessFilter{})
+	if err != nil || len(entries) != 1 {
+		t.Errorf("after Close: %d entries, %v; want the buffered request written", len(entries), err)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), req) // Logging after Close is a no-op
+}
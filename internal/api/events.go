@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+// EventSource publishes the daemon's mutation events, as seen by bd
+// activity: the RPC server.
+type EventSource interface {
+	Subscribe() (<-chan rpc.MutationEvent, func())
+}
+
+// WebSocket timings: writes that take longer than writeWait drop the
+// connection, and clients that answer no ping within pongWait are gone.
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+)
+
+// Event is a mutation as sent to WebSocket clients.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"` // create, update, delete, comment, status, ...
+	IssueID   string    `json:"issue_id"`
+	Title     string    `json:"title,omitempty"`
+	Assignee  string    `json:"assignee,omitempty"`
+	Actor     string    `json:"actor,omitempty"`
+	OldStatus string    `json:"old_status,omitempty"`
+	NewStatus string    `json:"new_status,omitempty"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	StepCount int       `json:"step_count,omitempty"`
+}
+
+func newEvent(e rpc.MutationEvent) Event {
+	return Event{
+		Timestamp: e.Timestamp,
+		Type:      e.Type,
+		IssueID:   e.IssueID,
+		Title:     e.Title,
+		Assignee:  e.Assignee,
+		Actor:     e.Actor,
+		OldStatus: e.OldStatus,
+		NewStatus: e.NewStatus,
+		ParentID:  e.ParentID,
+		StepCount: e.StepCount,
+	}
+}
+
+// EventFilter selects the events a connection receives. Empty fields match
+// everything.
+type EventFilter struct {
+	Types    []string `json:"types,omitempty"`
+	Issue    string   `json:"issue,omitempty"` // Issue ID prefix, as bd activity --mol
+	Assignee string   `json:"assignee,omitempty"`
+	Actor    string   `json:"actor,omitempty"`
+}
+
+// parseEventFilter reads a filter from query parameters: type= (repeated
+// or comma-separated), issue=, assignee= and actor=.
+func parseEventFilter(q url.Values) EventFilter {
+	var f EventFilter
+	for _, v := range q["type"] {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				f.Types = append(f.Types, t)
+			}
+		}
+	}
+	f.Issue = q.Get("issue")
+	f.Assignee = q.Get("assignee")
+	f.Actor = q.Get("actor")
+	return f
+}
+
+// Matches reports whether the filter selects e.
+func (f EventFilter) Matches(e rpc.MutationEvent) bool {
+	if len(f.Types) > 0 && !containsString(f.Types, e.Type) {
+		return false
+	}
+	if f.Issue != "" && !strings.HasPrefix(e.IssueID, f.Issue) {
+		return false
+	}
+	if f.Assignee != "" && e.Assignee != f.Assignee {
+		return false
+	}
+	return f.Actor == "" || e.Actor == f.Actor
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// eventsHandler streams mutation events over a WebSocket at /events. The
+// filter comes from the query parameters; clients can replace it at any
+// time by sending an EventFilter as a JSON message.
+func eventsHandler(s storage.Storage, events EventSource, token string) http.Handler {
+	upgrader := websocket.Upgrader{
+		// Browsers apply no CORS rules to WebSockets, so pages from other
+		// origins may only connect with a token.
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" || (token != "" && hasToken(r, token)) || principalOf(r.Context()).TokenID != "" {
+				return true
+			}
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return // Upgrade has replied
+		}
+		defer func() { _ = conn.Close() }()
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		mutations, unsubscribe := events.Subscribe()
+		defer unsubscribe()
+
+		filters := make(chan EventFilter, 1)
+		go readFilters(conn, filters, cancel)
+
+		filter := parseEventFilter(r.URL.Query())
+		ping := time.NewTicker(pingPeriod)
+		defer ping.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case filter = <-filters:
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+					return
+				}
+			case m, ok := <-mutations:
+				if !ok {
+					return
+				}
+				if !filter.Matches(m) || !eventVisible(ctx, s, m.IssueID) {
+					continue
+				}
+				_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+				if err := conn.WriteJSON(newEvent(m)); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// readFilters reads filter updates until the connection closes, then
+// calls done.
+func readFilters(conn *websocket.Conn, filters chan EventFilter, done func()) {
+	defer done()
+	conn.SetReadLimit(64 << 10)
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var f EventFilter
+		if err := json.Unmarshal(data, &f); err != nil {
+			continue
+		}
+		// Only the latest filter matters
+		select {
+		case <-filters:
+		default:
+		}
+		filters <- f
+	}
+}
+
+// eventVisible reports whether the request behind ctx may see events of
+// an issue. Deleted issues keep no visibility and count as internal.
+func eventVisible(ctx context.Context, s storage.Storage, issueID string) bool {
+	vs, err := visibility.For(s)
+	if err != nil {
+		return visibility.Allows(audience(ctx), visibility.Internal)
+	}
+	levels, err := vs.GetVisibilityForIssues(ctx, []string{issueID})
+	if err != nil {
+		return false
+	}
+	return visibility.Allows(audience(ctx), levels[issueID])
+}
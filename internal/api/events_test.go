@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/steveyegge/beads/internal/rpc"
+)
+
+// fakeEvents hands out one subscription, fed by the test.
+type fakeEvents struct {
+	ch         chan rpc.MutationEvent
+	subscribed chan struct{}
+}
+
+func newFakeEvents() *fakeEvents {
+	return &fakeEvents{ch: make(chan rpc.MutationEvent, 16), subscribed: make(chan struct{})}
+}
+
+func (f *fakeEvents) Subscribe() (<-chan rpc.MutationEvent, func()) {
+	close(f.subscribed)
+	return f.ch, func() {}
+}
+
+func dialEvents(t *testing.T, srv *httptest.Server, query string, header http.Header) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/events" + query
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		t.Fatalf("dial: %v (status %d)", err, status)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func readEvent(t *testing.T, conn *websocket.Conn) Event {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var e Event
+	if err := conn.ReadJSON(&e); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return e
+}
+
+func TestEventsFilter(t *testing.T) {
+	events := newFakeEvents()
+	srv := httptest.NewServer(Handler(newTestStore(t), Options{Events: events}))
+	defer srv.Close()
+
+	conn := dialEvents(t, srv, "?type=create,comment", nil)
+	<-events.subscribed
+	events.ch <- rpc.MutationEvent{Type: rpc.MutationUpdate, IssueID: "bd-1"}
+	events.ch <- rpc.MutationEvent{Type: rpc.MutationCreate, IssueID: "bd-1", Title: "issue bd-1"}
+	if e := readEvent(t, conn); e.Type != rpc.MutationCreate || e.Title != "issue bd-1" {
+		t.Errorf("got %+v, want the create event", e)
+	}
+
+	// Replacing the filter
+	if err := conn.WriteJSON(EventFilter{Issue: "bd-2"}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	events.ch <- rpc.MutationEvent{Type: rpc.MutationCreate, IssueID: "bd-1"}
+	events.ch <- rpc.MutationEvent{Type: rpc.MutationUpdate, IssueID: "bd-2"}
+	if e := readEvent(t, conn); e.Type != rpc.MutationUpdate || e.IssueID != "bd-2" {
+		t.Errorf("got %+v, want the bd-2 update", e)
+	}
+}
+
+func TestEventsVisibility(t *testing.T) {
+	events := newFakeEvents()
+	srv := httptest.NewServer(Handler(newTestStore(t), Options{Token: "secret", Events: events}))
+	defer srv.Close()
+
+	// Without the token: only the public issue bd-3
+	conn := dialEvents(t, srv, "", nil)
+	<-events.subscribed
+	for _, id := range []string{"bd-1", "bd-4", "bd-3"} {
+		events.ch <- rpc.MutationEvent{Type: rpc.MutationUpdate, IssueID: id}
+	}
+	if e := readEvent(t, conn); e.IssueID != "bd-3" {
+		t.Errorf("got %+v, want only bd-3", e)
+	}
+}
+
+func TestEventsOrigin(t *testing.T) {
+	srv := httptest.NewServer(Handler(newTestStore(t), Options{Token: "secret", Events: newFakeEvents()}))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/events"
+
+	header := http.Header{"Origin": {"https://evil.example"}}
+	if _, resp, err := websocket.DefaultDialer.Dial(url, header); err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("cross-origin connection without the token was not refused")
+	}
+	dialEvents(t, srv, "?token=secret", header)
+}
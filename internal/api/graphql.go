@@ -0,0 +1,528 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+// Page sizes for connections: the default when first is omitted, and the
+// most a client may ask for.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// maxQueryDepth bounds nesting, so a query can't walk the dependency graph
+// indefinitely.
+const maxQueryDepth = 12
+
+// Schema is the GraphQL schema served at /graphql. Lists are Relay-style
+// connections: pass pageInfo.endCursor as after to fetch the next page.
+const Schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+scalar Time
+
+type Query {
+	# One issue, or null if it doesn't exist or isn't visible.
+	issue(id: ID!): Issue
+	# Issues in priority order, newest first within a priority.
+	issues(first: Int, after: String, status: String, type: String, priority: Int, assignee: String, labels: [String!], search: String): IssueConnection!
+	stats: Stats!
+}
+
+# Changes need an API token with the write:issues scope (write:comments for
+# addComment), are made as the token's name, and only reach issues the
+# token can see. They return null for results the token can't read.
+type Mutation {
+	createIssue(input: CreateIssueInput!): Issue
+	updateIssue(id: ID!, input: UpdateIssueInput!): Issue
+	closeIssue(id: ID!, reason: String): Issue
+	addComment(issueId: ID!, text: String!): Comment!
+}
+
+input CreateIssueInput {
+	title: String!
+	description: String
+	# Defaults to task.
+	type: String
+	# Defaults to 2.
+	priority: Int
+	assignee: String
+	labels: [String!]
+}
+
+input UpdateIssueInput {
+	title: String
+	description: String
+	status: String
+	priority: Int
+	assignee: String
+	addLabels: [String!]
+	removeLabels: [String!]
+}
+
+type Issue {
+	id: ID!
+	title: String!
+	description: String!
+	design: String!
+	acceptanceCriteria: String!
+	notes: String!
+	status: String!
+	priority: Int!
+	type: String!
+	assignee: String
+	createdBy: String!
+	createdAt: Time!
+	updatedAt: Time!
+	closedAt: Time
+	closeReason: String
+	dueAt: Time
+	labels: [String!]!
+	parent: Issue
+	children(first: Int, after: String): IssueConnection!
+	# Issues this one depends on.
+	dependencies: [Dependency!]!
+	# Issues that depend on this one.
+	dependents: [Dependency!]!
+	comments(first: Int, after: String): CommentConnection!
+}
+
+type Dependency {
+	type: String!
+	issue: Issue!
+}
+
+type Comment {
+	id: ID!
+	author: String!
+	text: String!
+	createdAt: Time!
+}
+
+type IssueConnection {
+	edges: [IssueEdge!]!
+	nodes: [Issue!]!
+	pageInfo: PageInfo!
+	totalCount: Int!
+}
+
+type IssueEdge {
+	cursor: String!
+	node: Issue!
+}
+
+type CommentConnection {
+	edges: [CommentEdge!]!
+	nodes: [Comment!]!
+	pageInfo: PageInfo!
+	totalCount: Int!
+}
+
+type CommentEdge {
+	cursor: String!
+	node: Comment!
+}
+
+type PageInfo {
+	hasNextPage: Boolean!
+	endCursor: String
+}
+
+# Counts over the whole database, as in bd stats.
+type Stats {
+	total: Int!
+	open: Int!
+	inProgress: Int!
+	blocked: Int!
+	deferred: Int!
+	ready: Int!
+	closed: Int!
+	pinned: Int!
+	epicsEligibleForClosure: Int!
+	averageLeadTimeHours: Float!
+}
+`
+
+// graphqlRequest is a GraphQL request, as a POST body or GET parameters.
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func graphqlHandler(s storage.Storage, writer Writer) http.Handler {
+	schema := graphql.MustParseSchema(Schema, &queryResolver{s: s, w: writer}, graphql.MaxDepth(maxQueryDepth))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		if r.Method == http.MethodGet {
+			q := r.URL.Query()
+			req.Query = q.Get("query")
+			req.OperationName = q.Get("operationName")
+			if vars := q.Get("variables"); vars != "" {
+				if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+					http.Error(w, "invalid variables: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+		} else if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+			http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Query == "" {
+			http.Error(w, "missing query", http.StatusBadRequest)
+			return
+		}
+		ctx := r.Context()
+		if r.Method == http.MethodGet {
+			// Links and img tags send GETs; they mustn't change anything
+			ctx = context.WithValue(ctx, readOnlyKey{}, true)
+		}
+		resp := schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// visible returns the issues the request behind ctx may see.
+func visible(ctx context.Context, s storage.Storage, issues []*types.Issue) ([]*types.Issue, error) {
+	if err := visibility.Populate(ctx, s, issues); err != nil {
+		return nil, err
+	}
+	return visibility.Filter(issues, audience(ctx)), nil
+}
+
+type pageArgs struct {
+	First *int32
+	After *string
+}
+
+// connection is one page of a list.
+type connection[T any] struct {
+	edges   []*edge[T]
+	hasNext bool
+	total   int
+}
+
+type edge[T any] struct {
+	cursor string
+	node   T
+}
+
+// pageItem is a list element that knows its place in the list's order.
+type pageItem interface {
+	key() types.Cursor
+	after(c *types.Cursor) bool // whether the item sorts after c
+}
+
+// paginate returns the page of items selected by args. Edge cursors hold
+// the item's sort key, with the same encoding as bd list --cursor, so a
+// page resumes after its predecessor's last item even when the list
+// changes in between.
+func paginate[T pageItem](items []T, args pageArgs) (*connection[T], error) {
+	c := &connection[T]{total: len(items), edges: []*edge[T]{}}
+	if args.After != nil {
+		after, err := types.DecodeCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		if after != nil {
+			start := sort.Search(len(items), func(i int) bool { return items[i].after(after) })
+			items = items[start:]
+		}
+	}
+	limit := DefaultPageSize
+	if args.First != nil {
+		limit = int(*args.First)
+		if limit < 0 || limit > MaxPageSize {
+			return nil, fmt.Errorf("first must be between 0 and %d", MaxPageSize)
+		}
+	}
+	for i := 0; i < len(items) && i < limit; i++ {
+		c.edges = append(c.edges, &edge[T]{cursor: types.EncodeCursor(items[i].key()), node: items[i]})
+	}
+	c.hasNext = limit < len(items)
+	return c, nil
+}
+
+func (c *connection[T]) Edges() []*edge[T] { return c.edges }
+func (c *connection[T]) TotalCount() int32 { return int32(c.total) } // #nosec G115 -- counts fit
+
+func (c *connection[T]) Nodes() []T {
+	nodes := make([]T, len(c.edges))
+	for i, e := range c.edges {
+		nodes[i] = e.node
+	}
+	return nodes
+}
+
+func (c *connection[T]) PageInfo() *pageInfo {
+	info := &pageInfo{hasNext: c.hasNext}
+	if len(c.edges) > 0 {
+		info.endCursor = &c.edges[len(c.edges)-1].cursor
+	}
+	return info
+}
+
+func (e *edge[T]) Cursor() string { return e.cursor }
+func (e *edge[T]) Node() T        { return e.node }
+
+type pageInfo struct {
+	hasNext   bool
+	endCursor *string
+}
+
+func (p *pageInfo) HasNextPage() bool  { return p.hasNext }
+func (p *pageInfo) EndCursor() *string { return p.endCursor }
+
+type queryResolver struct {
+	s storage.Storage
+	w Writer // nil when the API is read-only
+}
+
+func (q *queryResolver) Issue(ctx context.Context, args struct{ ID graphql.ID }) (*issueResolver, error) {
+	issue, err := q.s.GetIssue(ctx, string(args.ID))
+	if err != nil || issue == nil || issue.Status == types.StatusTombstone {
+		return nil, err
+	}
+	issues, err := visible(ctx, q.s, []*types.Issue{issue})
+	if err != nil || len(issues) == 0 {
+		return nil, err
+	}
+	return &issueResolver{s: q.s, issue: issues[0]}, nil
+}
+
+type issuesArgs struct {
+	pageArgs
+	Status   *string
+	Type     *string
+	Priority *int32
+	Assignee *string
+	Labels   *[]string
+	Search   *string
+}
+
+func (q *queryResolver) Issues(ctx context.Context, args issuesArgs) (*connection[*issueResolver], error) {
+	var filter types.IssueFilter
+	if args.Status != nil {
+		status := types.Status(*args.Status)
+		filter.Status = &status
+	}
+	if args.Type != nil {
+		issueType := types.IssueType(*args.Type)
+		filter.IssueType = &issueType
+	}
+	if args.Priority != nil {
+		priority := int(*args.Priority)
+		filter.Priority = &priority
+	}
+	filter.Assignee = args.Assignee
+	if args.Labels != nil {
+		filter.Labels = *args.Labels
+	}
+	search := ""
+	if args.Search != nil {
+		search = *args.Search
+	}
+	return q.issueConnection(ctx, search, filter, args.pageArgs)
+}
+
+func (q *queryResolver) issueConnection(ctx context.Context, search string, filter types.IssueFilter, page pageArgs) (*connection[*issueResolver], error) {
+	issues, err := q.s.SearchIssues(ctx, search, filter)
+	if err != nil {
+		return nil, err
+	}
+	if issues, err = visible(ctx, q.s, issues); err != nil {
+		return nil, err
+	}
+	return paginate(issueResolvers(q.s, issues), page)
+}
+
+func issueResolvers(s storage.Storage, issues []*types.Issue) []*issueResolver {
+	out := make([]*issueResolver, len(issues))
+	for i, issue := range issues {
+		out[i] = &issueResolver{s: s, issue: issue}
+	}
+	return out
+}
+
+func (q *queryResolver) Stats(ctx context.Context) (*statsResolver, error) {
+	stats, err := q.s.GetStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &statsResolver{stats}, nil
+}
+
+type issueResolver struct {
+	s     storage.Storage
+	issue *types.Issue
+}
+
+// Issue lists are in SearchIssues order.
+func (r *issueResolver) key() types.Cursor { return types.CursorFor(r.issue) }
+func (r *issueResolver) after(c *types.Cursor) bool {
+	return types.SearchLess(c.Issue(), r.issue)
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+func (r *issueResolver) ID() graphql.ID             { return graphql.ID(r.issue.ID) }
+func (r *issueResolver) Title() string              { return r.issue.Title }
+func (r *issueResolver) Description() string        { return r.issue.Description }
+func (r *issueResolver) Design() string             { return r.issue.Design }
+func (r *issueResolver) AcceptanceCriteria() string { return r.issue.AcceptanceCriteria }
+func (r *issueResolver) Notes() string              { return r.issue.Notes }
+func (r *issueResolver) Status() string             { return string(r.issue.Status) }
+func (r *issueResolver) Priority() int32            { return int32(r.issue.Priority) } // #nosec G115 -- priorities are 0-4
+func (r *issueResolver) Type() string               { return string(r.issue.IssueType) }
+func (r *issueResolver) Assignee() *string          { return optionalString(r.issue.Assignee) }
+func (r *issueResolver) CreatedBy() string          { return r.issue.CreatedBy }
+func (r *issueResolver) CreatedAt() graphql.Time    { return graphql.Time{Time: r.issue.CreatedAt} }
+func (r *issueResolver) UpdatedAt() graphql.Time    { return graphql.Time{Time: r.issue.UpdatedAt} }
+func (r *issueResolver) ClosedAt() *graphql.Time    { return optionalTime(r.issue.ClosedAt) }
+func (r *issueResolver) CloseReason() *string       { return optionalString(r.issue.CloseReason) }
+func (r *issueResolver) DueAt() *graphql.Time       { return optionalTime(r.issue.DueAt) }
+
+func (r *issueResolver) Labels(ctx context.Context) ([]string, error) {
+	labels, err := r.s.GetLabels(ctx, r.issue.ID)
+	if labels == nil {
+		labels = []string{}
+	}
+	return labels, err
+}
+
+func (r *issueResolver) Parent(ctx context.Context) (*issueResolver, error) {
+	deps, err := r.s.GetDependencyRecords(ctx, r.issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range deps {
+		if dep.Type == types.DepParentChild {
+			return (&queryResolver{s: r.s}).Issue(ctx, struct{ ID graphql.ID }{graphql.ID(dep.DependsOnID)})
+		}
+	}
+	return nil, nil
+}
+
+func (r *issueResolver) Children(ctx context.Context, args pageArgs) (*connection[*issueResolver], error) {
+	return (&queryResolver{s: r.s}).issueConnection(ctx, "", types.IssueFilter{ParentID: &r.issue.ID}, args)
+}
+
+func (r *issueResolver) Dependencies(ctx context.Context) ([]*dependencyResolver, error) {
+	deps, err := r.s.GetDependenciesWithMetadata(ctx, r.issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.dependencyResolvers(ctx, deps)
+}
+
+func (r *issueResolver) Dependents(ctx context.Context) ([]*dependencyResolver, error) {
+	deps, err := r.s.GetDependentsWithMetadata(ctx, r.issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.dependencyResolvers(ctx, deps)
+}
+
+func (r *issueResolver) dependencyResolvers(ctx context.Context, deps []*types.IssueWithDependencyMetadata) ([]*dependencyResolver, error) {
+	issues := make([]*types.Issue, len(deps))
+	kinds := make(map[string]types.DependencyType, len(deps))
+	for i, dep := range deps {
+		issues[i] = &dep.Issue
+		kinds[dep.ID] = dep.DependencyType
+	}
+	issues, err := visible(ctx, r.s, issues)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*dependencyResolver, len(issues))
+	for i, issue := range issues {
+		out[i] = &dependencyResolver{kind: kinds[issue.ID], issue: &issueResolver{s: r.s, issue: issue}}
+	}
+	return out, nil
+}
+
+func (r *issueResolver) Comments(ctx context.Context, args pageArgs) (*connection[*commentResolver], error) {
+	comments, err := r.s.GetIssueComments(ctx, r.issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*commentResolver, len(comments))
+	for i, c := range comments {
+		out[i] = &commentResolver{c}
+	}
+	return paginate(out, args)
+}
+
+type dependencyResolver struct {
+	kind  types.DependencyType
+	issue *issueResolver
+}
+
+func (d *dependencyResolver) Type() string          { return string(d.kind) }
+func (d *dependencyResolver) Issue() *issueResolver { return d.issue }
+
+type commentResolver struct {
+	c *types.Comment
+}
+
+func (c *commentResolver) ID() graphql.ID          { return graphql.ID(fmt.Sprint(c.c.ID)) }
+func (c *commentResolver) Author() string          { return c.c.Author }
+func (c *commentResolver) Text() string            { return c.c.Text }
+func (c *commentResolver) CreatedAt() graphql.Time { return graphql.Time{Time: c.c.CreatedAt} }
+
+// Comments are listed oldest first, in the order they were added.
+func (c *commentResolver) key() types.Cursor {
+	return types.Cursor{CreatedAt: c.c.CreatedAt, ID: strconv.FormatInt(c.c.ID, 10)}
+}
+func (c *commentResolver) after(cur *types.Cursor) bool {
+	if !c.c.CreatedAt.Equal(cur.CreatedAt) {
+		return c.c.CreatedAt.After(cur.CreatedAt)
+	}
+	id, _ := strconv.ParseInt(cur.ID, 10, 64)
+	return c.c.ID > id
+}
+
+type statsResolver struct {
+	s *types.Statistics
+}
+
+func count(n int) int32 { return int32(n) } // #nosec G115 -- counts fit
+
+func (r *statsResolver) Total() int32                   { return count(r.s.TotalIssues) }
+func (r *statsResolver) Open() int32                    { return count(r.s.OpenIssues) }
+func (r *statsResolver) InProgress() int32              { return count(r.s.InProgressIssues) }
+func (r *statsResolver) Blocked() int32                 { return count(r.s.BlockedIssues) }
+func (r *statsResolver) Deferred() int32                { return count(r.s.DeferredIssues) }
+func (r *statsResolver) Ready() int32                   { return count(r.s.ReadyIssues) }
+func (r *statsResolver) Closed() int32                  { return count(r.s.ClosedIssues) }
+func (r *statsResolver) Pinned() int32                  { return count(r.s.PinnedIssues) }
+func (r *statsResolver) EpicsEligibleForClosure() int32 { return count(r.s.EpicsEligibleForClosure) }
+func (r *statsResolver) AverageLeadTimeHours() float64  { return r.s.AverageLeadTime }
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/apitoken"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+func newTestStore(t *testing.T) *sqlite.SQLiteStorage {
+	t.Helper()
+	ctx := context.Background()
+	s, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	if err := s.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range []string{"bd-1", "bd-2", "bd-3", "bd-4"} {
+		issue := &types.Issue{ID: id, Title: "issue " + id, Status: types.StatusOpen, Priority: i, IssueType: types.TypeTask}
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, dep := range []*types.Dependency{
+		{IssueID: "bd-2", DependsOnID: "bd-1", Type: types.DepParentChild},
+		{IssueID: "bd-3", DependsOnID: "bd-1", Type: types.DepParentChild},
+		{IssueID: "bd-2", DependsOnID: "bd-3", Type: types.DepBlocks},
+	} {
+		if err := s.AddDependency(ctx, dep, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.AddIssueComment(ctx, "bd-1", "alice", "first"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetVisibility(ctx, "bd-3", visibility.Public, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetVisibility(ctx, "bd-4", visibility.Private, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func query(t *testing.T, h http.Handler, token, q string, vars map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	body, _ := json.Marshal(graphqlRequest{Query: q, Variables: vars})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []interface{}          `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %v", resp.Errors)
+	}
+	return resp.Data
+}
+
+func TestGraphQLIssue(t *testing.T) {
+	h := Handler(newTestStore(t), Options{})
+	data := query(t, h, "", `{
+		issue(id: "bd-2") {
+			title
+			parent { id }
+			dependencies { type issue { id } }
+		}
+		parent: issue(id: "bd-1") {
+			children(first: 10) { totalCount nodes { id } }
+			comments { nodes { author text } }
+		}
+		stats { total open }
+	}`, nil)
+
+	issue := data["issue"].(map[string]interface{})
+	if issue["title"] != "issue bd-2" || issue["parent"].(map[string]interface{})["id"] != "bd-1" {
+		t.Errorf("issue = %v", issue)
+	}
+	deps := issue["dependencies"].([]interface{})
+	if len(deps) != 2 {
+		t.Errorf("dependencies = %v", deps)
+	}
+	parent := data["parent"].(map[string]interface{})
+	if n := parent["children"].(map[string]interface{})["totalCount"]; n != float64(2) {
+		t.Errorf("children totalCount = %v", n)
+	}
+	comments := parent["comments"].(map[string]interface{})["nodes"].([]interface{})
+	if len(comments) != 1 || comments[0].(map[string]interface{})["author"] != "alice" {
+		t.Errorf("comments = %v", comments)
+	}
+	if stats := data["stats"].(map[string]interface{}); stats["open"] != float64(4) {
+		t.Errorf("stats = %v", stats)
+	}
+}
+
+func TestGraphQLPagination(t *testing.T) {
+	h := Handler(newTestStore(t), Options{})
+	const q = `query($after: String) {
+		issues(first: 2, after: $after) {
+			totalCount
+			nodes { id }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`
+	var ids []interface{}
+	var after interface{}
+	for page := 0; page < 3; page++ {
+		conn := query(t, h, "", q, map[string]interface{}{"after": after})["issues"].(map[string]interface{})
+		if conn["totalCount"] != float64(3) {
+			t.Fatalf("totalCount = %v, want 3 (private issue withheld)", conn["totalCount"])
+		}
+		ids = append(ids, conn["nodes"].([]interface{})...)
+		info := conn["pageInfo"].(map[string]interface{})
+		if info["hasNextPage"] != true {
+			break
+		}
+		after = info["endCursor"]
+	}
+	if len(ids) != 3 {
+		t.Errorf("paged through %v, want 3 issues", ids)
+	}
+}
+
+func TestGraphQLAudience(t *testing.T) {
+	h := Handler(newTestStore(t), Options{Token: "secret"})
+	const q = `{ issues { nodes { id } } private: issue(id: "bd-4") { id } }`
+
+	public := query(t, h, "", q, nil)
+	if nodes := public["issues"].(map[string]interface{})["nodes"].([]interface{}); len(nodes) != 1 {
+		t.Errorf("without the token got %v, want only the public issue", nodes)
+	}
+	team := query(t, h, "secret", q, nil)
+	if nodes := team["issues"].(map[string]interface{})["nodes"].([]interface{}); len(nodes) != 3 {
+		t.Errorf("with the token got %v, want 3 issues", nodes)
+	}
+	if team["private"] != nil {
+		t.Errorf("private issue served: %v", team["private"])
+	}
+}
+
+func TestGraphQLAPITokens(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	h := Handler(s, Options{})
+	const q = `{ issues { nodes { id } } }`
+	count := func(data map[string]interface{}) int {
+		return len(data["issues"].(map[string]interface{})["nodes"].([]interface{}))
+	}
+
+	if n := count(query(t, h, "", q, nil)); n != 3 {
+		t.Errorf("without tokens got %d issues, want 3", n)
+	}
+	_, reader, err := apitoken.Create(ctx, s, "dashboard", []string{apitoken.ScopeRead}, 0, "tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := count(query(t, h, "", q, nil)); n != 1 {
+		t.Errorf("anonymous with active tokens got %d issues, want only the public one", n)
+	}
+	if n := count(query(t, h, reader, q, nil)); n != 3 {
+		t.Errorf("read token got %d issues, want 3", n)
+	}
+
+	if err := s.RevokeAPIToken(ctx, "dashboard", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query={stats{total}}", nil)
+	req.Header.Set("Authorization", "Bearer "+reader)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("revoked token: status %d, want 401", rec.Code)
+	}
+}
+
+// storeWriter applies mutations straight to a store, standing in for the
+// daemon's RPC server.
+type storeWriter struct {
+	s        *sqlite.SQLiteStorage
+	requests []*rpc.Request
+}
+
+func (w *storeWriter) Execute(req *rpc.Request) rpc.Response {
+	w.requests = append(w.requests, req)
+	ctx := context.Background()
+	var data interface{}
+	var err error
+	switch req.Operation {
+	case rpc.OpCreate:
+		var args rpc.CreateArgs
+		_ = json.Unmarshal(req.Args, &args)
+		issue := &types.Issue{Title: args.Title, Status: types.StatusOpen, Priority: args.Priority, IssueType: types.IssueType(args.IssueType), CreatedBy: args.CreatedBy}
+		err = w.s.CreateIssue(ctx, issue, req.Actor)
+		data = issue
+	case rpc.OpCommentAdd:
+		var args rpc.CommentAddArgs
+		_ = json.Unmarshal(req.Args, &args)
+		data, err = w.s.AddIssueComment(ctx, args.ID, args.Author, args.Text)
+	default:
+		err = fmt.Errorf("unexpected operation %s", req.Operation)
+	}
+	if err != nil {
+		return rpc.Response{Error: err.Error()}
+	}
+	raw, _ := json.Marshal(data)
+	return rpc.Response{Success: true, Data: raw}
+}
+
+func TestGraphQLMutations(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	w := &storeWriter{s: s}
+	h := Handler(s, Options{Writer: w})
+	_, reader, err := apitoken.Create(ctx, s, "dashboard", []string{apitoken.ScopeRead}, 0, "tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, bot, err := apitoken.Create(ctx, s, "triage-bot", []string{apitoken.ScopeRead, apitoken.ScopeWriteIssues}, 0, "tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := func(method, token, q string) (map[string]interface{}, []interface{}) {
+		t.Helper()
+		var req *http.Request
+		if method == http.MethodGet {
+			req = httptest.NewRequest(method, "/graphql?query="+url.QueryEscape(q), nil)
+		} else {
+			body, _ := json.Marshal(graphqlRequest{Query: q})
+			req = httptest.NewRequest(method, "/graphql", bytes.NewReader(body))
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var resp struct {
+			Data   map[string]interface{} `json:"data"`
+			Errors []interface{}          `json:"errors"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+		}
+		return resp.Data, resp.Errors
+	}
+	const create = `mutation { createIssue(input: {title: "From the bot", priority: 1}) { id title createdBy } }`
+
+	if _, errs := send(http.MethodPost, reader, create); len(errs) == 0 {
+		t.Error("read token created an issue")
+	}
+	if _, errs := send(http.MethodGet, bot, create); len(errs) == 0 {
+		t.Error("mutation over GET succeeded")
+	}
+	data, errs := send(http.MethodPost, bot, create)
+	if len(errs) > 0 {
+		t.Fatalf("createIssue: %v", errs)
+	}
+	if issue := data["createIssue"].(map[string]interface{}); issue["title"] != "From the bot" || issue["createdBy"] != "triage-bot" {
+		t.Errorf("created %v, want it made as the token's name", issue)
+	}
+
+	// Commenting needs its own scope, and hidden issues can't be reached
+	if _, errs := send(http.MethodPost, bot, `mutation { addComment(issueId: "bd-1", text: "hi") { id } }`); len(errs) == 0 {
+		t.Error("write:issues token commented")
+	}
+	_, commenter, err := apitoken.Create(ctx, s, "commenter", []string{apitoken.ScopeWriteComments}, 0, "tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := send(http.MethodPost, commenter, `mutation { addComment(issueId: "bd-1", text: "hi") { id } }`); len(errs) == 0 {
+		t.Error("token without read scope commented on an internal issue")
+	}
+	data, errs = send(http.MethodPost, commenter, `mutation { addComment(issueId: "bd-3", text: "hi") { author text } }`)
+	if len(errs) > 0 || data["addComment"].(map[string]interface{})["author"] != "commenter" {
+		t.Errorf("addComment = %v, %v", data, errs)
+	}
+	if len(w.requests) != 2 {
+		t.Errorf("writer got %d requests, want only the two allowed ones", len(w.requests))
+	}
+
+	readOnly := Handler(s, Options{})
+	body, _ := json.Marshal(graphqlRequest{Query: create})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+bot)
+	rec := httptest.NewRecorder()
+	readOnly.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), "read-only") {
+		t.Errorf("without a writer: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/steveyegge/beads/internal/apitoken"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Writer applies mutations: the daemon's RPC server, so API writes get the
+// same lock, validation, event and export handling as bd commands.
+type Writer interface {
+	Execute(req *rpc.Request) rpc.Response
+}
+
+// readOnlyKey marks requests that may not change data (GET requests).
+type readOnlyKey struct{}
+
+// write sends a change needing scope to the writer, as the token behind
+// ctx, and decodes the result into out if it is not nil.
+func (q *queryResolver) write(ctx context.Context, scope, op string, args, out any) error {
+	if q.w == nil {
+		return errors.New("this API is read-only")
+	}
+	if readOnly, _ := ctx.Value(readOnlyKey{}).(bool); readOnly {
+		return errors.New("mutations must be sent with POST")
+	}
+	p := principalOf(ctx)
+	if p.TokenID == "" || !slices.Contains(p.Scopes, scope) {
+		return fmt.Errorf("this change needs an API token with the %s scope", scope)
+	}
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	resp := q.w.Execute(&rpc.Request{Operation: op, Args: raw, Actor: p.Subject})
+	if !resp.Success {
+		return errors.New(resp.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, out)
+}
+
+// target returns an error unless the request behind ctx can see issue id,
+// so tokens can't change issues hidden from them.
+func (q *queryResolver) target(ctx context.Context, id graphql.ID) error {
+	issue, err := q.Issue(ctx, struct{ ID graphql.ID }{id})
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	return nil
+}
+
+type createIssueInput struct {
+	Title       string
+	Description *string
+	Type        *string
+	Priority    *int32
+	Assignee    *string
+	Labels      *[]string
+}
+
+func (q *queryResolver) CreateIssue(ctx context.Context, args struct{ Input createIssueInput }) (*issueResolver, error) {
+	in := args.Input
+	create := rpc.CreateArgs{Title: in.Title, IssueType: string(types.TypeTask), Priority: 2}
+	if in.Description != nil {
+		create.Description = *in.Description
+	}
+	if in.Type != nil {
+		create.IssueType = *in.Type
+	}
+	if in.Priority != nil {
+		create.Priority = int(*in.Priority)
+	}
+	if in.Assignee != nil {
+		create.Assignee = *in.Assignee
+	}
+	if in.Labels != nil {
+		create.Labels = *in.Labels
+	}
+	create.CreatedBy = principalOf(ctx).Subject
+
+	var issue types.Issue
+	if err := q.write(ctx, apitoken.ScopeWriteIssues, rpc.OpCreate, create, &issue); err != nil {
+		return nil, err
+	}
+	return q.Issue(ctx, struct{ ID graphql.ID }{graphql.ID(issue.ID)})
+}
+
+type updateIssueInput struct {
+	Title        *string
+	Description  *string
+	Status       *string
+	Priority     *int32
+	Assignee     *string
+	AddLabels    *[]string
+	RemoveLabels *[]string
+}
+
+func (q *queryResolver) UpdateIssue(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateIssueInput
+}) (*issueResolver, error) {
+	if err := q.target(ctx, args.ID); err != nil {
+		return nil, err
+	}
+	in := args.Input
+	update := rpc.UpdateArgs{
+		ID:          string(args.ID),
+		Title:       in.Title,
+		Description: in.Description,
+		Status:      in.Status,
+		Assignee:    in.Assignee,
+	}
+	if in.Priority != nil {
+		priority := int(*in.Priority)
+		update.Priority = &priority
+	}
+	if in.AddLabels != nil {
+		update.AddLabels = *in.AddLabels
+	}
+	if in.RemoveLabels != nil {
+		update.RemoveLabels = *in.RemoveLabels
+	}
+	if err := q.write(ctx, apitoken.ScopeWriteIssues, rpc.OpUpdate, update, nil); err != nil {
+		return nil, err
+	}
+	return q.Issue(ctx, struct{ ID graphql.ID }{args.ID})
+}
+
+func (q *queryResolver) CloseIssue(ctx context.Context, args struct {
+	ID     graphql.ID
+	Reason *string
+}) (*issueResolver, error) {
+	if err := q.target(ctx, args.ID); err != nil {
+		return nil, err
+	}
+	close := rpc.CloseArgs{ID: string(args.ID), Reason: "Closed"}
+	if args.Reason != nil {
+		close.Reason = *args.Reason
+	}
+	if err := q.write(ctx, apitoken.ScopeWriteIssues, rpc.OpClose, close, nil); err != nil {
+		return nil, err
+	}
+	return q.Issue(ctx, struct{ ID graphql.ID }{args.ID})
+}
+
+func (q *queryResolver) AddComment(ctx context.Context, args struct {
+	IssueID graphql.ID
+	Text    string
+}) (*commentResolver, error) {
+	if err := q.target(ctx, args.IssueID); err != nil {
+		return nil, err
+	}
+	add := rpc.CommentAddArgs{ID: string(args.IssueID), Author: principalOf(ctx).Subject, Text: args.Text}
+	var comment types.Comment
+	if err := q.write(ctx, apitoken.ScopeWriteComments, rpc.OpCommentAdd, add, &comment); err != nil {
+		return nil, err
+	}
+	return &commentResolver{&comment}, nil
+}
@@ -0,0 +1,371 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/steveyegge/beads/internal/visibility"
+	"golang.org/x/oauth2"
+)
+
+// Roles a group can map to.
+const (
+	RoleViewer = "viewer" // Sees public issues
+	RoleMember = "member" // Sees public and internal issues
+)
+
+// DefaultGroupsClaim is the ID token claim listing a user's groups.
+const DefaultGroupsClaim = "groups"
+
+// Cookies: the signed-in session, and the state of a login in progress.
+const (
+	sessionCookie = "bd_session"
+	loginCookie   = "bd_login"
+)
+
+// Session lifetimes.
+const (
+	sessionTTL = 12 * time.Hour
+	loginTTL   = 10 * time.Minute
+)
+
+// OIDCConfig configures single sign-on through an OpenID Connect provider.
+type OIDCConfig struct {
+	Issuer        string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string            // The /auth/callback URL registered with the provider
+	GroupsClaim   string            // Defaults to DefaultGroupsClaim
+	Roles         map[string]string // Group to role
+	DefaultRole   string            // Role of users in no mapped group; empty turns them away
+	SessionSecret []byte            // Signs session cookies; random (sessions end on restart) if empty
+}
+
+// ParseRoles parses group-to-role mappings such as
+// "eng=member,contractors=viewer".
+func ParseRoles(s string) (map[string]string, error) {
+	roles := make(map[string]string)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		group, role, ok := strings.Cut(part, "=")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if !ok || group == "" {
+			return nil, fmt.Errorf("invalid role mapping %q (want group=role)", part)
+		}
+		if err := validateRole(role); err != nil {
+			return nil, err
+		}
+		roles[group] = role
+	}
+	return roles, nil
+}
+
+func validateRole(role string) error {
+	if role != RoleViewer && role != RoleMember {
+		return fmt.Errorf("invalid role %q (must be %s or %s)", role, RoleViewer, RoleMember)
+	}
+	return nil
+}
+
+// roleAudience returns the visibility level a role may see.
+func roleAudience(role string) string {
+	if role == RoleMember {
+		return visibility.Internal
+	}
+	return visibility.Public
+}
+
+// OIDC authenticates API users with an OpenID Connect provider: browsers
+// sign in at /auth/login and hold a session cookie, other clients send an
+// ID token from the provider as a bearer token.
+type OIDC struct {
+	cfg      OIDCConfig
+	oauth    oauth2.Config
+	verifier *oidc.IDTokenVerifier
+	secret   []byte
+	secure   bool // Cookies only over HTTPS
+}
+
+// NewOIDC discovers the provider's endpoints and keys.
+func NewOIDC(ctx context.Context, cfg OIDCConfig) (*OIDC, error) {
+	if cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, errors.New("OIDC needs a client ID and a redirect URL")
+	}
+	if cfg.DefaultRole != "" {
+		if err := validateRole(cfg.DefaultRole); err != nil {
+			return nil, err
+		}
+	}
+	redirect, err := url.Parse(cfg.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect URL: %w", err)
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = DefaultGroupsClaim
+	}
+	provider, err := oidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC discovery for %s failed: %w", cfg.Issuer, err)
+	}
+	secret := cfg.SessionSecret
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	return &OIDC{
+		cfg: cfg,
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		secret:   secret,
+		secure:   redirect.Scheme == "https",
+	}, nil
+}
+
+// session is the content of the session cookie.
+type session struct {
+	Subject string `json:"sub"`
+	Role    string `json:"role"`
+	Expires int64  `json:"exp"`
+}
+
+// login is the content of the login cookie, tying a callback to the
+// browser that started the login.
+type login struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Return   string `json:"return"`
+	Expires  int64  `json:"exp"`
+}
+
+// sign encodes v as a cookie value with an HMAC.
+func (o *OIDC) sign(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, o.secret)
+	mac.Write(data)
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// open decodes a cookie value made by sign.
+func (o *OIDC) open(value string, v any) error {
+	payload, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return errors.New("malformed cookie")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return err
+	}
+	given, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, o.secret)
+	mac.Write(data)
+	if !hmac.Equal(given, mac.Sum(nil)) {
+		return errors.New("bad cookie signature")
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (o *OIDC) setCookie(w http.ResponseWriter, name, value string, ttl time.Duration) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   o.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// identify returns who made a request: the holder of a session cookie, or
+// of an ID token sent as a bearer token.
+func (o *OIDC) identify(r *http.Request) (principal, bool) {
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		var s session
+		if o.open(c.Value, &s) == nil && time.Now().Unix() < s.Expires {
+			return principal{Subject: s.Subject, Audience: roleAudience(s.Role)}, true
+		}
+	}
+	if raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token, err := o.verifier.Verify(r.Context(), strings.TrimSpace(raw))
+		if err != nil {
+			return principal{}, false
+		}
+		subject, role, err := o.roleOf(token)
+		if err != nil {
+			return principal{}, false
+		}
+		return principal{Subject: subject, Audience: roleAudience(role)}, true
+	}
+	return principal{}, false
+}
+
+// roleOf returns the user an ID token is for and their role: the widest
+// role among their groups, else the default role.
+func (o *OIDC) roleOf(token *oidc.IDToken) (subject, role string, err error) {
+	claims := map[string]any{}
+	if err := token.Claims(&claims); err != nil {
+		return "", "", err
+	}
+	subject = token.Subject
+	if email, ok := claims["email"].(string); ok && email != "" {
+		subject = email
+	}
+	var groups []string
+	switch v := claims[o.cfg.GroupsClaim].(type) {
+	case string:
+		groups = []string{v}
+	case []any:
+		for _, g := range v {
+			if s, ok := g.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	}
+	for _, g := range groups {
+		switch o.cfg.Roles[g] {
+		case RoleMember:
+			return subject, RoleMember, nil
+		case RoleViewer:
+			role = RoleViewer
+		}
+	}
+	if role == "" {
+		role = o.cfg.DefaultRole
+	}
+	if role == "" {
+		return subject, "", fmt.Errorf("%s is in no group with access", subject)
+	}
+	return subject, role, nil
+}
+
+// routes adds the login endpoints to mux.
+func (o *OIDC) routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /auth/login", o.handleLogin)
+	mux.HandleFunc("GET /auth/callback", o.handleCallback)
+	mux.HandleFunc("GET /auth/logout", o.handleLogout)
+	mux.HandleFunc("GET /auth/me", o.handleMe)
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// safeReturn keeps post-login redirects on this site.
+func safeReturn(s string) string {
+	if !strings.HasPrefix(s, "/") || strings.HasPrefix(s, "//") || strings.HasPrefix(s, "/\\") {
+		return "/"
+	}
+	return s
+}
+
+func (o *OIDC) handleLogin(w http.ResponseWriter, r *http.Request) {
+	l := login{
+		State:    randomString(),
+		Nonce:    randomString(),
+		Verifier: oauth2.GenerateVerifier(),
+		Return:   safeReturn(r.URL.Query().Get("return")),
+		Expires:  time.Now().Add(loginTTL).Unix(),
+	}
+	value, err := o.sign(l)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	o.setCookie(w, loginCookie, value, loginTTL)
+	http.Redirect(w, r, o.oauth.AuthCodeURL(l.State, oidc.Nonce(l.Nonce), oauth2.S256ChallengeOption(l.Verifier)), http.StatusFound)
+}
+
+func (o *OIDC) handleCallback(w http.ResponseWriter, r *http.Request) {
+	var l login
+	c, err := r.Cookie(loginCookie)
+	if err != nil || o.open(c.Value, &l) != nil || time.Now().Unix() >= l.Expires {
+		http.Error(w, "login expired; start again at /auth/login", http.StatusBadRequest)
+		return
+	}
+	o.setCookie(w, loginCookie, "", -time.Second)
+	q := r.URL.Query()
+	if msg := q.Get("error"); msg != "" {
+		http.Error(w, "login failed: "+msg+" "+q.Get("error_description"), http.StatusUnauthorized)
+		return
+	}
+	if q.Get("state") != l.State {
+		http.Error(w, "login state mismatch", http.StatusBadRequest)
+		return
+	}
+
+	tok, err := o.oauth.Exchange(r.Context(), q.Get("code"), oauth2.VerifierOption(l.Verifier))
+	if err != nil {
+		http.Error(w, "login failed: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	raw, _ := tok.Extra("id_token").(string)
+	idToken, err := o.verifier.Verify(r.Context(), raw)
+	if err != nil {
+		http.Error(w, "login failed: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if idToken.Nonce != l.Nonce {
+		http.Error(w, "login failed: nonce mismatch", http.StatusUnauthorized)
+		return
+	}
+	subject, role, err := o.roleOf(idToken)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	value, err := o.sign(session{Subject: subject, Role: role, Expires: time.Now().Add(sessionTTL).Unix()})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	o.setCookie(w, sessionCookie, value, sessionTTL)
+	http.Redirect(w, r, l.Return, http.StatusFound)
+}
+
+func (o *OIDC) handleLogout(w http.ResponseWriter, r *http.Request) {
+	o.setCookie(w, sessionCookie, "", -time.Second)
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// handleMe reports who is signed in, for UIs.
+func (o *OIDC) handleMe(w http.ResponseWriter, r *http.Request) {
+	p, ok := o.identify(r)
+	if !ok {
+		http.Error(w, "not signed in", http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"subject": p.Subject, "visibility": p.Audience})
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+func TestParseRoles(t *testing.T) {
+	roles, err := ParseRoles(" eng=member, contractors = viewer,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles) != 2 || roles["eng"] != RoleMember || roles["contractors"] != RoleViewer {
+		t.Errorf("roles = %v", roles)
+	}
+	for _, bad := range []string{"eng", "=member", "eng=admin"} {
+		if _, err := ParseRoles(bad); err == nil {
+			t.Errorf("ParseRoles(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestSafeReturn(t *testing.T) {
+	for in, want := range map[string]string{
+		"/issues/bd-1":      "/issues/bd-1",
+		"":                  "/",
+		"https://evil.test": "/",
+		"//evil.test":       "/",
+		"/\\evil.test":      "/",
+	} {
+		if got := safeReturn(in); got != want {
+			t.Errorf("safeReturn(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOIDCSession(t *testing.T) {
+	o := &OIDC{secret: []byte("secret")}
+	cookie := func(s session) *http.Cookie {
+		value, err := o.sign(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &http.Cookie{Name: sessionCookie, Value: value}
+	}
+	expires := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name     string
+		cookie   *http.Cookie
+		ok       bool
+		audience string
+	}{
+		{"member", cookie(session{Subject: "a@example.com", Role: RoleMember, Expires: expires}), true, visibility.Internal},
+		{"viewer", cookie(session{Subject: "b@example.com", Role: RoleViewer, Expires: expires}), true, visibility.Public},
+		{"expired", cookie(session{Subject: "a@example.com", Role: RoleMember, Expires: time.Now().Add(-time.Minute).Unix()}), false, ""},
+		{"forged", &http.Cookie{Name: sessionCookie, Value: cookie(session{Role: RoleMember, Expires: expires}).Value + "x"}, false, ""},
+		{"none", nil, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			p, ok := o.identify(req)
+			if ok != tt.ok || p.Audience != tt.audience {
+				t.Errorf("identify = %+v, %v", p, ok)
+			}
+
+			want := tt.audience
+			if !ok {
+				want = visibility.Public
+			}
+			if got, _ := requestPrincipal(req, Options{OIDC: o}, nil); got.Audience != want {
+				t.Errorf("audience = %q, want %q", got.Audience, want)
+			}
+		})
+	}
+}
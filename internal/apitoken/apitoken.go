@@ -0,0 +1,146 @@
+// Package apitoken manages credentials for the daemon's HTTP API (bd
+// token), so each agent or integration authenticates as itself. A token's
+// secret is shown once, when it is created; only its SHA-256 is stored.
+package apitoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Prefix starts every token secret, so leaked tokens are easy to spot.
+const Prefix = "bd_"
+
+// Scopes a token can hold.
+const (
+	ScopeRead          = "read"           // Read issues, including internal ones
+	ScopeWriteIssues   = "write:issues"   // Create, update and close issues
+	ScopeWriteComments = "write:comments" // Comment on issues
+)
+
+// Scopes lists every scope.
+var Scopes = []string{ScopeRead, ScopeWriteIssues, ScopeWriteComments}
+
+// ErrUnsupported is returned for storage backends without API tokens.
+var ErrUnsupported = errors.New("API tokens require the SQLite backend")
+
+// ErrInvalid is returned for secrets that match no active token.
+var ErrInvalid = errors.New("invalid, expired or revoked API token")
+
+// Store is the storage interface for API tokens.
+type Store interface {
+	CreateAPIToken(ctx context.Context, token *types.APIToken, hash string) error
+	GetAPITokenByHash(ctx context.Context, hash string) (*types.APIToken, error)
+	ListAPITokens(ctx context.Context) ([]*types.APIToken, error)
+	RevokeAPIToken(ctx context.Context, idOrName string, at time.Time) error
+	TouchAPIToken(ctx context.Context, id string, at time.Time) error
+}
+
+// For returns s as a token store.
+func For(s storage.Storage) (Store, error) {
+	ts, ok := s.(Store)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return ts, nil
+}
+
+// ParseScopes parses a comma-separated scope list such as
+// "read,write:issues".
+func ParseScopes(s string) ([]string, error) {
+	var scopes []string
+	for _, scope := range strings.Split(s, ",") {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope == "" || has(scopes, scope) {
+			continue
+		}
+		if !has(Scopes, scope) {
+			return nil, fmt.Errorf("invalid scope %q (must be one of %s)", scope, strings.Join(Scopes, ", "))
+		}
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("a token needs at least one scope")
+	}
+	return scopes, nil
+}
+
+// HasScope reports whether a token holds scope.
+func HasScope(token *types.APIToken, scope string) bool {
+	return has(token.Scopes, scope)
+}
+
+func has(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Hash returns the stored form of a secret.
+func Hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create stores a new token and returns it with its secret. A zero ttl
+// never expires.
+func Create(ctx context.Context, s Store, name string, scopes []string, ttl time.Duration, actor string) (*types.APIToken, string, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, "", errors.New("a token needs a name")
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	secret := Prefix + base64.RawURLEncoding.EncodeToString(b)
+	now := time.Now().UTC()
+	token := &types.APIToken{
+		ID:        "tok-" + Hash(secret)[:8],
+		Name:      name,
+		Scopes:    scopes,
+		CreatedBy: actor,
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		expires := now.Add(ttl)
+		token.ExpiresAt = &expires
+	}
+	if err := s.CreateAPIToken(ctx, token, Hash(secret)); err != nil {
+		return nil, "", err
+	}
+	return token, secret, nil
+}
+
+// Authenticate returns the active token with the given secret, and records
+// its use.
+func Authenticate(ctx context.Context, s Store, secret string) (*types.APIToken, error) {
+	if !strings.HasPrefix(secret, Prefix) {
+		return nil, ErrInvalid
+	}
+	token, err := s.GetAPITokenByHash(ctx, Hash(secret))
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	now := time.Now().UTC()
+	if !token.Active(now) {
+		return nil, ErrInvalid
+	}
+	if err := s.TouchAPIToken(ctx, token.ID, now); err != nil {
+		return nil, err
+	}
+	token.LastUsedAt = &now
+	return token, nil
+}
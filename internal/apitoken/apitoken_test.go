@@ -0,0 +1,63 @@
+package apitoken
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+)
+
+func TestParseScopes(t *testing.T) {
+	got, err := ParseScopes(" read, WRITE:issues,read,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{ScopeRead, ScopeWriteIssues}; !slices.Equal(got, want) {
+		t.Errorf("ParseScopes = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"", "admin", "read,write"} {
+		if _, err := ParseScopes(bad); err == nil {
+			t.Errorf("ParseScopes(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestCreateAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	s, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+
+	token, secret, err := Create(ctx, s, "ci-agent", []string{ScopeRead}, time.Hour, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(secret, Prefix) || token.ExpiresAt == nil {
+		t.Fatalf("Create = %+v, %q", token, secret)
+	}
+
+	got, err := Authenticate(ctx, s, secret)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if got.ID != token.ID || !HasScope(got, ScopeRead) || got.LastUsedAt == nil {
+		t.Errorf("Authenticate = %+v", got)
+	}
+	if _, err := Authenticate(ctx, s, secret+"x"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("wrong secret: err = %v, want ErrInvalid", err)
+	}
+
+	if err := s.RevokeAPIToken(ctx, token.ID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Authenticate(ctx, s, secret); !errors.Is(err, ErrInvalid) {
+		t.Errorf("revoked token: err = %v, want ErrInvalid", err)
+	}
+}
@@ -0,0 +1,251 @@
+// Package atomicfile replaces files so that a crash at any point leaves
+// either the complete old contents or the complete new contents.
+//
+// A plain write-to-temp-then-rename is not enough on its own: without an
+// fsync the rename can reach disk before the data does, and after a power
+// loss the target is empty or truncated. For issues.jsonl that is worse than
+// losing the export, because a truncated file imports as mass deletion.
+//
+// Write therefore fsyncs the new contents before renaming and records an
+// intent journal (<path>.export-intent) holding the new file's SHA-256 for
+// the duration of the swap. Recover, run before a file is read, uses the
+// journal to finish or undo a swap that was interrupted.
+package atomicfile
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// IntentSuffix is appended to the target path to name the intent journal.
+const IntentSuffix = ".export-intent"
+
+// intent is the journal entry written once the new contents are durable and
+// removed once the rename is durable.
+type intent struct {
+	Target  string    `json:"target"`
+	Temp    string    `json:"temp"`
+	SHA256  string    `json:"sha256"`
+	Size    int64     `json:"size"`
+	PID     int       `json:"pid"`
+	Started time.Time `json:"started"`
+}
+
+// Result describes the file written by Write.
+type Result struct {
+	SHA256 string
+	Size   int64
+}
+
+// Recovery reports what Recover did.
+type Recovery int
+
+const (
+	// RecoveryNone means no interrupted write was found.
+	RecoveryNone Recovery = iota
+	// RecoveryCompleted means the rename had happened; only the journal was left.
+	RecoveryCompleted
+	// RecoveryRolledForward means the new contents were durable and have now
+	// been moved into place.
+	RecoveryRolledForward
+	// RecoveryRolledBack means the new contents were incomplete; the old file
+	// was kept.
+	RecoveryRolledBack
+)
+
+func (r Recovery) String() string {
+	switch r {
+	case RecoveryCompleted:
+		return "completed"
+	case RecoveryRolledForward:
+		return "rolled forward"
+	case RecoveryRolledBack:
+		return "rolled back"
+	default:
+		return "none"
+	}
+}
+
+// IntentPath returns the journal path for target.
+func IntentPath(target string) string {
+	return target + IntentSuffix
+}
+
+// Write replaces target with whatever write produces. The temp file is
+// created next to target so the rename stays on one filesystem. If write
+// returns an error, target is left untouched.
+func Write(target string, write func(w io.Writer) error) (*Result, error) {
+	if _, err := Recover(target); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(target)
+	tmp, err := os.CreateTemp(dir, filepath.Base(target)+".tmp.*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	renamed := false
+	defer func() {
+		if !renamed {
+			_ = tmp.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	hasher := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(tmp, hasher)}
+	bw := bufio.NewWriterSize(counter, 64*1024)
+	if err := write(bw); err != nil {
+		return nil, err
+	}
+	if err := bw.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	result := &Result{SHA256: hex.EncodeToString(hasher.Sum(nil)), Size: counter.n}
+	in := intent{
+		Target:  filepath.Base(target),
+		Temp:    filepath.Base(tmpPath),
+		SHA256:  result.SHA256,
+		Size:    result.Size,
+		PID:     os.Getpid(),
+		Started: time.Now().UTC(),
+	}
+	if err := writeIntent(target, &in); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmpPath, target); err != nil {
+		_ = os.Remove(IntentPath(target))
+		return nil, fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	renamed = true
+	syncDir(dir)
+
+	if err := os.Remove(IntentPath(target)); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove export intent: %w", err)
+	}
+	return result, nil
+}
+
+// Recover finishes or undoes a Write to target that was interrupted by a
+// crash. It is a no-op when no intent journal exists. Call it before
+// reading a file that Write may have been replacing.
+func Recover(target string) (Recovery, error) {
+	intentPath := IntentPath(target)
+	// #nosec G304 - journal path is derived from the caller's target
+	data, err := os.ReadFile(intentPath)
+	if os.IsNotExist(err) {
+		return RecoveryNone, nil
+	}
+	if err != nil {
+		return RecoveryNone, fmt.Errorf("failed to read export intent: %w", err)
+	}
+
+	dir := filepath.Dir(target)
+	var in intent
+	if err := json.Unmarshal(data, &in); err != nil || in.SHA256 == "" || in.Temp == "" {
+		// Crashed while writing the journal itself: the rename never
+		// happened, so the old file is intact.
+		return RecoveryRolledBack, removeIntent(intentPath)
+	}
+	tmpPath := filepath.Join(dir, filepath.Base(in.Temp))
+
+	if sum, err := fileSHA256(target); err == nil && sum == in.SHA256 {
+		_ = os.Remove(tmpPath)
+		return RecoveryCompleted, removeIntent(intentPath)
+	}
+	if sum, err := fileSHA256(tmpPath); err == nil && sum == in.SHA256 {
+		if err := os.Rename(tmpPath, target); err != nil {
+			return RecoveryNone, fmt.Errorf("failed to finish interrupted export: %w", err)
+		}
+		syncDir(dir)
+		return RecoveryRolledForward, removeIntent(intentPath)
+	}
+	_ = os.Remove(tmpPath)
+	return RecoveryRolledBack, removeIntent(intentPath)
+}
+
+func writeIntent(target string, in *intent) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	// #nosec G304 - journal path is derived from the caller's target
+	f, err := os.OpenFile(IntentPath(target), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write export intent: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write export intent: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to sync export intent: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write export intent: %w", err)
+	}
+	// The journal must be on disk before the rename it describes
+	syncDir(filepath.Dir(target))
+	return nil
+}
+
+func removeIntent(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove export intent: %w", err)
+	}
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	// #nosec G304 - path is the caller's target or its temp file
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// syncDir makes a rename in dir durable. Not every platform can fsync a
+// directory (Windows can't), so failures are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir) // #nosec G304 - directory of the caller's target
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
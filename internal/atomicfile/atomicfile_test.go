@@ -0,0 +1,168 @@
+package atomicfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeString(s string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	return string(data)
+}
+
+func sum(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func TestWriteReplacesFile(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "issues.jsonl")
+	if err := os.WriteFile(target, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Write(target, writeString("new\n"))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if got := readFile(t, target); got != "new\n" {
+		t.Errorf("target = %q, want %q", got, "new\n")
+	}
+	if result.SHA256 != sum("new\n") || result.Size != 4 {
+		t.Errorf("result = %+v", result)
+	}
+	if _, err := os.Stat(IntentPath(target)); !os.IsNotExist(err) {
+		t.Errorf("intent journal left behind: %v", err)
+	}
+	leftovers, _ := filepath.Glob(target + ".tmp.*")
+	if len(leftovers) != 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestWriteErrorKeepsOldFile(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "issues.jsonl")
+	if err := os.WriteFile(target, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	boom := errors.New("boom")
+	_, err := Write(target, func(w io.Writer) error {
+		_, _ = io.WriteString(w, "partial")
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("Write error = %v, want boom", err)
+	}
+	if got := readFile(t, target); got != "old\n" {
+		t.Errorf("target = %q after failed write", got)
+	}
+	leftovers, _ := filepath.Glob(target + ".tmp.*")
+	if len(leftovers) != 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+// simulateCrash leaves the state a Write would leave if the process died
+// after journaling the intent: the temp file holds tempContents (possibly
+// truncated) and the journal names newContents' hash.
+func simulateCrash(t *testing.T, target, tempContents, newContents string) string {
+	t.Helper()
+	tmpPath := target + ".tmp.123"
+	if err := os.WriteFile(tmpPath, []byte(tempContents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(intent{Target: filepath.Base(target), Temp: filepath.Base(tmpPath), SHA256: sum(newContents)})
+	if err := os.WriteFile(IntentPath(target), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return tmpPath
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("no journal", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "issues.jsonl")
+		if r, err := Recover(target); err != nil || r != RecoveryNone {
+			t.Errorf("Recover = %v, %v", r, err)
+		}
+	})
+
+	t.Run("crash before rename rolls forward", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "issues.jsonl")
+		_ = os.WriteFile(target, []byte("old\n"), 0o600)
+		tmpPath := simulateCrash(t, target, "new\n", "new\n")
+
+		if r, err := Recover(target); err != nil || r != RecoveryRolledForward {
+			t.Fatalf("Recover = %v, %v", r, err)
+		}
+		if got := readFile(t, target); got != "new\n" {
+			t.Errorf("target = %q, want new contents", got)
+		}
+		if _, err := os.Stat(tmpPath); !os.IsNotExist(err) {
+			t.Error("temp file should have been renamed")
+		}
+	})
+
+	t.Run("crash after rename completes", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "issues.jsonl")
+		_ = os.WriteFile(target, []byte("new\n"), 0o600)
+		simulateCrash(t, target, "", "new\n")
+		_ = os.Remove(target + ".tmp.123")
+
+		if r, err := Recover(target); err != nil || r != RecoveryCompleted {
+			t.Fatalf("Recover = %v, %v", r, err)
+		}
+		if got := readFile(t, target); got != "new\n" {
+			t.Errorf("target = %q", got)
+		}
+	})
+
+	t.Run("truncated temp rolls back", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "issues.jsonl")
+		_ = os.WriteFile(target, []byte("old\n"), 0o600)
+		tmpPath := simulateCrash(t, target, "ne", "new\n")
+
+		if r, err := Recover(target); err != nil || r != RecoveryRolledBack {
+			t.Fatalf("Recover = %v, %v", r, err)
+		}
+		if got := readFile(t, target); got != "old\n" {
+			t.Errorf("target = %q, want old contents kept", got)
+		}
+		if _, err := os.Stat(tmpPath); !os.IsNotExist(err) {
+			t.Error("truncated temp file should have been removed")
+		}
+	})
+
+	t.Run("torn journal rolls back", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "issues.jsonl")
+		_ = os.WriteFile(target, []byte("old\n"), 0o600)
+		_ = os.WriteFile(IntentPath(target), []byte(`{"target":"iss`), 0o600)
+
+		if r, err := Recover(target); err != nil || r != RecoveryRolledBack {
+			t.Fatalf("Recover = %v, %v", r, err)
+		}
+		if got := readFile(t, target); got != "old\n" {
+			t.Errorf("target = %q, want old contents kept", got)
+		}
+		if _, err := os.Stat(IntentPath(target)); !os.IsNotExist(err) {
+			t.Error("journal should have been removed")
+		}
+	})
+}
@@ -0,0 +1,390 @@
+// Package attachments keeps files attached to issues under
+// .beads/attachments/<issue-id>/, within size limits. Attachments over the
+// limits can be offloaded to an external blob store (S3, GCS), leaving a
+// small pointer file in the repository in their place.
+package attachments
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Dir is the directory under .beads holding attachments, one subdirectory
+// per issue.
+const Dir = "attachments"
+
+// PointerSuffix ends the name of the file that stands in for an offloaded
+// attachment: report.pdf becomes report.pdf.offloaded.json.
+const PointerSuffix = ".offloaded.json"
+
+// tempPrefix starts the names of uploads in progress.
+const tempPrefix = ".upload-"
+
+// ErrTooLarge is returned for an attachment over the size limits when
+// there is no store to offload it to.
+var ErrTooLarge = errors.New("attachment over the size limit")
+
+// Pointer is the content of a pointer file.
+type Pointer struct {
+	URL    string `json:"url"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Attachment is one file attached to an issue.
+type Attachment struct {
+	IssueID string `json:"issue_id"`
+	Name    string `json:"name"`
+	Path    string `json:"path"` // Relative to .beads: the file, or its pointer when offloaded
+	Size    int64  `json:"size"`
+	URL     string `json:"url,omitempty"`    // Where an offloaded attachment lives
+	SHA256  string `json:"sha256,omitempty"` // Of an offloaded attachment's content
+}
+
+// Offloaded reports whether the attachment lives in an external store.
+func (a *Attachment) Offloaded() bool {
+	return a.URL != ""
+}
+
+// Options are the limits on attachments kept in the repository.
+type Options struct {
+	MaxSize int64     // Largest attachment kept (0 = no limit)
+	Quota   int64     // Total size of attachments kept (0 = no limit)
+	Store   BlobStore // Where attachments over the limits go; nil refuses them
+}
+
+// Save stores an attachment read from r for an issue. It is kept in the
+// repository if it fits the limits, offloaded to opts.Store if not, and
+// refused with ErrTooLarge if there is no store. A previous attachment
+// with the same name is replaced.
+func Save(ctx context.Context, beadsDir, issueID, name string, r io.Reader, opts Options) (*Attachment, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(beadsDir, Dir, issueID)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(dir, tempPrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if err != nil {
+		return nil, err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	// The attachment being replaced no longer counts toward the quota
+	used, err := Usage(beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	if old, err := os.Stat(filepath.Join(dir, name)); err == nil {
+		used -= old.Size()
+	}
+	reason := overLimit(name, size, used, opts)
+	if reason == "" {
+		if err := tmp.Close(); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+			return nil, err
+		}
+		_ = os.Remove(filepath.Join(dir, name+PointerSuffix))
+		return &Attachment{IssueID: issueID, Name: name, Path: relPath(issueID, name), Size: size}, nil
+	}
+	if opts.Store == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTooLarge, reason)
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	a, err := offload(ctx, opts.Store, dir, issueID, name, tmp, size, sum)
+	if err != nil {
+		return nil, err
+	}
+	_ = os.Remove(filepath.Join(dir, name))
+	return a, nil
+}
+
+// overLimit says why an attachment of size doesn't fit in the repository
+// next to used bytes of others, or returns "".
+func overLimit(name string, size, used int64, opts Options) string {
+	if opts.MaxSize > 0 && size > opts.MaxSize {
+		return fmt.Sprintf("%s is %s, over the %s limit", name, humanize.Bytes(uint64(size)), humanize.Bytes(uint64(opts.MaxSize)))
+	}
+	if opts.Quota > 0 && used+size > opts.Quota {
+		return fmt.Sprintf("%s (%s) would take attachments to %s, over the %s quota", name,
+			humanize.Bytes(uint64(size)), humanize.Bytes(uint64(used+size)), humanize.Bytes(uint64(opts.Quota)))
+	}
+	return ""
+}
+
+// offload uploads an attachment's content and writes its pointer file.
+// Keys are content-addressed, so uploading the same file twice is harmless.
+func offload(ctx context.Context, store BlobStore, dir, issueID, name string, r io.Reader, size int64, sum string) (*Attachment, error) {
+	url, err := store.Put(ctx, sum+"/"+name, r, size)
+	if err != nil {
+		return nil, fmt.Errorf("offloading %s: %w", name, err)
+	}
+	p := Pointer{URL: url, Size: size, SHA256: sum}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+PointerSuffix), append(data, '\n'), 0600); err != nil {
+		return nil, err
+	}
+	return &Attachment{IssueID: issueID, Name: name, Path: relPath(issueID, name+PointerSuffix), Size: size, URL: url, SHA256: sum}, nil
+}
+
+// List returns an issue's attachments by name.
+func List(beadsDir, issueID string) ([]*Attachment, error) {
+	dir := filepath.Join(beadsDir, Dir, issueID)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []*Attachment
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), tempPrefix) {
+			continue
+		}
+		if name, ok := strings.CutSuffix(entry.Name(), PointerSuffix); ok {
+			p, err := readPointer(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, &Attachment{IssueID: issueID, Name: name, Path: relPath(issueID, entry.Name()), Size: p.Size, URL: p.URL, SHA256: p.SHA256})
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &Attachment{IssueID: issueID, Name: entry.Name(), Path: relPath(issueID, entry.Name()), Size: info.Size()})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// ListAll returns every issue's attachments, by issue and name.
+func ListAll(beadsDir string) ([]*Attachment, error) {
+	entries, err := os.ReadDir(filepath.Join(beadsDir, Dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var all []*Attachment
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		list, err := List(beadsDir, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, list...)
+	}
+	return all, nil
+}
+
+// Usage is the total size of the attachments kept in the repository, not
+// counting offloaded ones.
+func Usage(beadsDir string) (int64, error) {
+	all, err := ListAll(beadsDir)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, a := range all {
+		if !a.Offloaded() {
+			total += a.Size
+		}
+	}
+	return total, nil
+}
+
+// Open returns the content of an attachment, fetching it from store if it
+// was offloaded. Offloaded content is checked against its recorded hash as
+// it is read.
+func Open(ctx context.Context, beadsDir, issueID, name string, store BlobStore) (io.ReadCloser, *Attachment, error) {
+	list, err := List(beadsDir, issueID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, a := range list {
+		if a.Name != name {
+			continue
+		}
+		if !a.Offloaded() {
+			// #nosec G304 - attachment under the .beads directory
+			f, err := os.Open(filepath.Join(beadsDir, filepath.FromSlash(a.Path)))
+			return f, a, err
+		}
+		if store == nil {
+			return nil, a, fmt.Errorf("%s is offloaded to %s and no attachment store is configured", name, a.URL)
+		}
+		rc, err := store.Get(ctx, a.URL)
+		if err != nil {
+			return nil, a, fmt.Errorf("fetching %s: %w", a.URL, err)
+		}
+		return &verifyingReader{rc: rc, h: sha256.New(), want: a.SHA256}, a, nil
+	}
+	return nil, nil, fmt.Errorf("%s has no attachment named %q", issueID, name)
+}
+
+// Offload moves the attachments of the given issues (every issue if none)
+// that are over opts.MaxSize to opts.Store, then the largest remaining
+// ones until the repository is within opts.Quota. It returns the
+// attachments it offloaded.
+func Offload(ctx context.Context, beadsDir string, issueIDs []string, opts Options) ([]*Attachment, error) {
+	if opts.Store == nil {
+		return nil, errors.New("no attachment store is configured")
+	}
+	all, err := ListAll(beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(issueIDs))
+	for _, id := range issueIDs {
+		wanted[id] = true
+	}
+	var used int64
+	var candidates []*Attachment
+	for _, a := range all {
+		if a.Offloaded() {
+			continue
+		}
+		used += a.Size
+		if len(wanted) == 0 || wanted[a.IssueID] {
+			candidates = append(candidates, a)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Size > candidates[j].Size })
+
+	var moved []*Attachment
+	for _, a := range candidates {
+		overSize := opts.MaxSize > 0 && a.Size > opts.MaxSize
+		overQuota := opts.Quota > 0 && used > opts.Quota
+		if !overSize && !overQuota {
+			continue
+		}
+		offloaded, err := offloadFile(ctx, beadsDir, a, opts.Store)
+		if err != nil {
+			return moved, err
+		}
+		used -= a.Size
+		moved = append(moved, offloaded)
+	}
+	return moved, nil
+}
+
+// offloadFile uploads an attachment kept in the repository and replaces it
+// with a pointer.
+func offloadFile(ctx context.Context, beadsDir string, a *Attachment, store BlobStore) (*Attachment, error) {
+	path := filepath.Join(beadsDir, filepath.FromSlash(a.Path))
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G304 - attachment under the .beads directory
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	offloaded, err := offload(ctx, store, filepath.Dir(path), a.IssueID, a.Name, f, a.Size, sum)
+	_ = f.Close()
+	if err != nil {
+		return nil, err
+	}
+	return offloaded, os.Remove(path)
+}
+
+func fileSHA256(path string) (string, error) {
+	// #nosec G304 - attachment under the .beads directory
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readPointer(path string) (*Pointer, error) {
+	// #nosec G304 - pointer file under the .beads directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Pointer
+	if err := json.Unmarshal(data, &p); err != nil || p.URL == "" {
+		return nil, fmt.Errorf("%s is not a valid attachment pointer", filepath.Base(path))
+	}
+	return &p, nil
+}
+
+// cleanName reduces name to a plain file name an attachment can be saved
+// under.
+func cleanName(name string) (string, error) {
+	name = filepath.Base(filepath.Clean(name))
+	if name == "." || name == ".." || name == string(filepath.Separator) || name == "" ||
+		strings.HasPrefix(name, tempPrefix) || strings.HasSuffix(name, PointerSuffix) {
+		return "", fmt.Errorf("invalid attachment name %q", name)
+	}
+	return name, nil
+}
+
+func relPath(issueID, file string) string {
+	return Dir + "/" + issueID + "/" + file
+}
+
+// verifyingReader fails the read that reaches EOF if the content doesn't
+// match the expected SHA-256.
+type verifyingReader struct {
+	rc   io.ReadCloser
+	h    hash.Hash
+	want string
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.rc.Read(p)
+	v.h.Write(p[:n])
+	if err == io.EOF && v.want != "" && hex.EncodeToString(v.h.Sum(nil)) != v.want {
+		return n, fmt.Errorf("offloaded content does not match its SHA-256 %s", v.want)
+	}
+	return n, err
+}
+
+func (v *verifyingReader) Close() error {
+	return v.rc.Close()
+}
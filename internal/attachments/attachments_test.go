@@ -0,0 +1,172 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func fileTestStore(t *testing.T) BlobStore {
+	t.Helper()
+	store, err := OpenStore("file://"+filepath.ToSlash(t.TempDir()), StoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func readAttachment(t *testing.T, beadsDir, issueID, name string, store BlobStore) string {
+	t.Helper()
+	rc, _, err := Open(context.Background(), beadsDir, issueID, name, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSaveWithinLimits(t *testing.T) {
+	ctx := context.Background()
+	beadsDir := t.TempDir()
+	a, err := Save(ctx, beadsDir, "bd-1", "../notes.txt", strings.NewReader("hello"), Options{MaxSize: 10, Quota: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "notes.txt" || a.Path != "attachments/bd-1/notes.txt" || a.Size != 5 || a.Offloaded() {
+		t.Errorf("Save() = %+v", a)
+	}
+	if got := readAttachment(t, beadsDir, "bd-1", "notes.txt", nil); got != "hello" {
+		t.Errorf("content = %q", got)
+	}
+
+	// Replacing an attachment frees its share of the quota
+	if _, err := Save(ctx, beadsDir, "bd-1", "notes.txt", strings.NewReader("0123456789"), Options{Quota: 10}); err != nil {
+		t.Errorf("replacing within quota: %v", err)
+	}
+}
+
+func TestSaveOverLimitWithoutStore(t *testing.T) {
+	ctx := context.Background()
+	beadsDir := t.TempDir()
+	if _, err := Save(ctx, beadsDir, "bd-1", "big.bin", strings.NewReader("0123456789"), Options{MaxSize: 4}); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("over max size: err = %v, want ErrTooLarge", err)
+	}
+	if _, err := Save(ctx, beadsDir, "bd-1", "a.txt", strings.NewReader("abc"), Options{Quota: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Save(ctx, beadsDir, "bd-2", "b.txt", strings.NewReader("abc"), Options{Quota: 5}); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("over quota: err = %v, want ErrTooLarge", err)
+	}
+	list, err := ListAll(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Name != "a.txt" {
+		t.Errorf("ListAll() = %+v, want only a.txt (no temp files left)", list)
+	}
+}
+
+func TestSaveOffloads(t *testing.T) {
+	ctx := context.Background()
+	beadsDir := t.TempDir()
+	store := fileTestStore(t)
+	a, err := Save(ctx, beadsDir, "bd-1", "big.bin", strings.NewReader("0123456789"), Options{MaxSize: 4, Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Offloaded() || a.Path != "attachments/bd-1/big.bin"+PointerSuffix || a.Size != 10 {
+		t.Errorf("Save() = %+v, want offloaded", a)
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, Dir, "bd-1", "big.bin")); !os.IsNotExist(err) {
+		t.Error("offloaded content left in the repository")
+	}
+	if got := readAttachment(t, beadsDir, "bd-1", "big.bin", store); got != "0123456789" {
+		t.Errorf("content = %q", got)
+	}
+	if used, _ := Usage(beadsDir); used != 0 {
+		t.Errorf("Usage() = %d, want 0 for offloaded attachments", used)
+	}
+	if _, _, err := Open(ctx, beadsDir, "bd-1", "big.bin", nil); err == nil {
+		t.Error("Open() without a store succeeded for an offloaded attachment")
+	}
+}
+
+func TestOpenDetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	beadsDir := t.TempDir()
+	store := fileTestStore(t)
+	a, err := Save(ctx, beadsDir, "bd-1", "big.bin", strings.NewReader("0123456789"), Options{MaxSize: 1, Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(strings.TrimPrefix(a.URL, "file://"), []byte("tampered!!"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	rc, _, err := Open(ctx, beadsDir, "bd-1", "big.bin", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if _, err := io.ReadAll(rc); err == nil {
+		t.Error("reading tampered content succeeded")
+	}
+}
+
+func TestOffload(t *testing.T) {
+	ctx := context.Background()
+	beadsDir := t.TempDir()
+	for _, f := range []struct{ issue, name, content string }{
+		{"bd-1", "huge.bin", "0123456789"},
+		{"bd-1", "mid.txt", "012345"},
+		{"bd-2", "small.txt", "012"},
+		{"bd-2", "tiny.txt", "0"},
+	} {
+		if _, err := Save(ctx, beadsDir, f.issue, f.name, strings.NewReader(f.content), Options{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store := fileTestStore(t)
+
+	// huge.bin is over the size limit; then mid.txt, the largest left,
+	// brings the rest (4 bytes) within the quota
+	moved, err := Offload(ctx, beadsDir, nil, Options{MaxSize: 8, Quota: 5, Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moved) != 2 || moved[0].Name != "huge.bin" || moved[1].Name != "mid.txt" {
+		t.Errorf("Offload() moved %+v, want huge.bin and mid.txt", moved)
+	}
+	if used, _ := Usage(beadsDir); used != 4 {
+		t.Errorf("Usage() = %d, want 4", used)
+	}
+	if got := readAttachment(t, beadsDir, "bd-1", "mid.txt", store); got != "012345" {
+		t.Errorf("offloaded content = %q", got)
+	}
+
+	// Limited to an issue, and with nothing over the limits, nothing moves
+	moved, err = Offload(ctx, beadsDir, []string{"bd-2"}, Options{MaxSize: 8, Store: store})
+	if err != nil || len(moved) != 0 {
+		t.Errorf("Offload(bd-2) = %+v, %v, want nothing", moved, err)
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	for _, bad := range []string{"ftp://host/x", "s3:///prefix", "gs://", "file://"} {
+		if _, err := OpenStore(bad, StoreOptions{}); err == nil {
+			t.Errorf("OpenStore(%q) succeeded", bad)
+		}
+	}
+	for _, good := range []string{"s3://bucket/beads", "gs://bucket", "file:///srv/blobs"} {
+		if _, err := OpenStore(good, StoreOptions{}); err != nil {
+			t.Errorf("OpenStore(%q): %v", good, err)
+		}
+	}
+}
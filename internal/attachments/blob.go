@@ -0,0 +1,103 @@
+package attachments
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// BlobStore holds offloaded attachment content.
+type BlobStore interface {
+	// Put stores size bytes read from r under key and returns the URL
+	// they can be fetched from.
+	Put(ctx context.Context, key string, r io.Reader, size int64) (string, error)
+	// Get fetches content by the URL Put returned.
+	Get(ctx context.Context, url string) (io.ReadCloser, error)
+}
+
+// StoreOptions configure the S3 backend.
+type StoreOptions struct {
+	S3Region   string // Defaults to the AWS environment's region
+	S3Endpoint string // For S3-compatible services such as MinIO
+}
+
+// OpenStore returns the blob store for a URL: s3://bucket/prefix,
+// gs://bucket/prefix or file:///dir. Credentials come from the usual
+// AWS and Google Cloud environment and are only looked up on first use.
+func OpenStore(rawURL string, opts StoreOptions) (BlobStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attachment store %q: %w", rawURL, err)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("attachment store %q has no bucket", rawURL)
+		}
+		return &s3Store{bucket: u.Host, prefix: prefix, opts: opts}, nil
+	case "gs":
+		if u.Host == "" {
+			return nil, fmt.Errorf("attachment store %q has no bucket", rawURL)
+		}
+		return &gcsStore{bucket: u.Host, prefix: prefix}, nil
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("attachment store %q has no directory", rawURL)
+		}
+		return &fileStore{dir: filepath.FromSlash(u.Path)}, nil
+	default:
+		return nil, fmt.Errorf("attachment store %q: unsupported scheme (want s3, gs or file)", rawURL)
+	}
+}
+
+// bucketURL splits a scheme://bucket/key URL.
+func bucketURL(rawURL, scheme string) (bucket, key string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != scheme || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return "", "", fmt.Errorf("not a %s:// object URL: %q", scheme, rawURL)
+	}
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
+// fileStore keeps blobs in a local directory, such as a shared mount.
+type fileStore struct {
+	dir string
+}
+
+func (s *fileStore) Put(_ context.Context, key string, r io.Reader, _ int64) (string, error) {
+	dest := filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+key)))
+	if err := os.MkdirAll(filepath.Dir(dest), 0750); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), tempPrefix+"*")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(dest)}).String(), nil
+}
+
+func (s *fileStore) Get(_ context.Context, rawURL string) (io.ReadCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "file" {
+		return nil, fmt.Errorf("not a file:// URL: %q", rawURL)
+	}
+	// #nosec G304 - reading the configured attachment store
+	return os.Open(filepath.FromSlash(u.Path))
+}
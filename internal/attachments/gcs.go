@@ -0,0 +1,56 @@
+package attachments
+
+import (
+	"context"
+	"io"
+	"path"
+	"sync"
+
+	"cloud.google.com/go/storage"
+)
+
+// gcsStore keeps blobs in a Google Cloud Storage bucket.
+type gcsStore struct {
+	bucket string
+	prefix string
+
+	once   sync.Once
+	client *storage.Client
+	err    error
+}
+
+func (s *gcsStore) storageClient(ctx context.Context) (*storage.Client, error) {
+	s.once.Do(func() {
+		s.client, s.err = storage.NewClient(ctx)
+	})
+	return s.client, s.err
+}
+
+func (s *gcsStore) Put(ctx context.Context, key string, r io.Reader, _ int64) (string, error) {
+	client, err := s.storageClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	key = path.Join(s.prefix, key)
+	w := client.Bucket(s.bucket).Object(key).NewWriter(ctx)
+	if _, err := io.Copy(w, r); err != nil {
+		_ = w.Close()
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return "gs://" + s.bucket + "/" + key, nil
+}
+
+func (s *gcsStore) Get(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	bucket, key, err := bucketURL(rawURL, "gs")
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.storageClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Bucket(bucket).Object(key).NewReader(ctx)
+}
@@ -0,0 +1,77 @@
+package attachments
+
+import (
+	"context"
+	"io"
+	"path"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// s3Store keeps blobs in an S3 (or S3-compatible) bucket.
+type s3Store struct {
+	bucket string
+	prefix string
+	opts   StoreOptions
+
+	once sync.Once
+	sess *session.Session
+	err  error
+}
+
+func (s *s3Store) session() (*session.Session, error) {
+	s.once.Do(func() {
+		cfg := aws.NewConfig()
+		if s.opts.S3Region != "" {
+			cfg = cfg.WithRegion(s.opts.S3Region)
+		}
+		if s.opts.S3Endpoint != "" {
+			cfg = cfg.WithEndpoint(s.opts.S3Endpoint).WithS3ForcePathStyle(true)
+		}
+		s.sess, s.err = session.NewSessionWithOptions(session.Options{
+			Config:            *cfg,
+			SharedConfigState: session.SharedConfigEnable,
+		})
+	})
+	return s.sess, s.err
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader, _ int64) (string, error) {
+	sess, err := s.session()
+	if err != nil {
+		return "", err
+	}
+	key = path.Join(s.prefix, key)
+	_, err = s3manager.NewUploader(sess).UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   r,
+	})
+	if err != nil {
+		return "", err
+	}
+	return "s3://" + s.bucket + "/" + key, nil
+}
+
+func (s *s3Store) Get(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	bucket, key, err := bucketURL(rawURL, "s3")
+	if err != nil {
+		return nil, err
+	}
+	sess, err := s.session()
+	if err != nil {
+		return nil, err
+	}
+	out, err := s3.New(sess).GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
@@ -2,14 +2,17 @@ package audit
 
 import (
 	"bufio"
+	"bytes"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/steveyegge/beads/internal/atomicfile"
 	"github.com/steveyegge/beads/internal/beads"
 )
 
@@ -29,6 +32,7 @@ type Entry struct {
 	// Common metadata
 	Actor   string `json:"actor,omitempty"`
 	IssueID string `json:"issue_id,omitempty"`
+	Session string `json:"session,omitempty"` // Agent session the entry belongs to (bd session)
 
 	// LLM call
 	Model    string `json:"model,omitempty"`
@@ -40,6 +44,10 @@ type Entry struct {
 	ToolName string `json:"tool_name,omitempty"`
 	ExitCode *int   `json:"exit_code,omitempty"`
 
+	// Resource usage (bd usage), attributed to IssueID
+	Tokens  *int64   `json:"tokens,omitempty"`
+	CostUSD *float64 `json:"cost_usd,omitempty"`
+
 	// Labeling (append-only)
 	ParentID string `json:"parent_id,omitempty"`
 	Label    string `json:"label,omitempty"`  // "good" | "bad" | etc
@@ -125,6 +133,110 @@ func Append(e *Entry) (string, error) {
 	return e.ID, nil
 }
 
+// Read returns the entries in the log at path, oldest first. A missing log
+// has no entries; lines that don't parse are skipped.
+func Read(path string) ([]*Entry, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - the interactions log in .beads
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read interactions log: %w", err)
+	}
+	var entries []*Entry
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var e Entry
+		if line = bytes.TrimSpace(line); len(line) > 0 && json.Unmarshal(line, &e) == nil {
+			entries = append(entries, &e)
+		}
+	}
+	return entries, nil
+}
+
+// Prune removes the entries created before the cutoff from the log at
+// path, for retention policies, and returns how many there were. Lines that
+// don't parse are kept. With dryRun the log is left untouched.
+func Prune(path string, before time.Time, dryRun bool) (int, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - the interactions log in .beads
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read interactions log: %w", err)
+	}
+	var kept bytes.Buffer
+	pruned := 0
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		var e struct {
+			CreatedAt time.Time `json:"created_at"`
+		}
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) > 0 && json.Unmarshal(trimmed, &e) == nil && !e.CreatedAt.IsZero() && e.CreatedAt.Before(before) {
+			pruned++
+			continue
+		}
+		kept.Write(line)
+	}
+	if dryRun || pruned == 0 {
+		return pruned, nil
+	}
+	_, err = atomicfile.Write(path, func(w io.Writer) error {
+		_, err := w.Write(kept.Bytes())
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to rewrite interactions log: %w", err)
+	}
+	// nolint:gosec // JSONL is intended to be shared via git across clones/tools.
+	if err := os.Chmod(path, 0644); err != nil {
+		return 0, fmt.Errorf("failed to set interactions log permissions: %w", err)
+	}
+	return pruned, nil
+}
+
+// ReplaceActor rewrites the actor of entries for which match returns true
+// to replacement, and returns how many there were. Other lines are kept as
+// they are. With dryRun the log is left untouched.
+func ReplaceActor(path string, match func(actor string) bool, replacement string, dryRun bool) (int, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - the interactions log in .beads
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read interactions log: %w", err)
+	}
+	var out bytes.Buffer
+	replaced := 0
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		var e Entry
+		if err := json.Unmarshal(bytes.TrimSpace(line), &e); err != nil || e.Actor == "" || !match(e.Actor) {
+			out.Write(line)
+			continue
+		}
+		replaced++
+		e.Actor = replacement
+		enc := json.NewEncoder(&out)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(&e); err != nil {
+			return 0, fmt.Errorf("failed to encode interactions log entry: %w", err)
+		}
+	}
+	if dryRun || replaced == 0 {
+		return replaced, nil
+	}
+	if _, err := atomicfile.Write(path, func(w io.Writer) error {
+		_, err := w.Write(out.Bytes())
+		return err
+	}); err != nil {
+		return 0, fmt.Errorf("failed to rewrite interactions log: %w", err)
+	}
+	// nolint:gosec // JSONL is intended to be shared via git across clones/tools.
+	if err := os.Chmod(path, 0644); err != nil {
+		return 0, fmt.Errorf("failed to set interactions log permissions: %w", err)
+	}
+	return replaced, nil
+}
+
 func newID() (string, error) {
 	var b [4]byte
 	if _, err := rand.Read(b[:]); err != nil {
@@ -52,3 +52,25 @@ func TestAppend_CreatesFileAndWritesJSONL(t *testing.T) {
 		t.Fatalf("expected 2 lines, got %d", lines)
 	}
 }
+
+func TestRead(t *testing.T) {
+	p := filepath.Join(t.TempDir(), FileName)
+	if entries, err := Read(p); err != nil || len(entries) != 0 {
+		t.Fatalf("missing log: %v, %v", entries, err)
+	}
+	data := `{"id":"int-1","kind":"session_start","actor":"agent-1","session":"ses-1"}
+not json
+
+{"id":"int-2","kind":"session_end","session":"ses-1"}
+`
+	if err := os.WriteFile(p, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := Read(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Session != "ses-1" || entries[1].Kind != "session_end" {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
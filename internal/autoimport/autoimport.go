@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/steveyegge/beads/internal/atomicfile"
 	"github.com/steveyegge/beads/internal/debug"
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/types"
@@ -74,6 +75,14 @@ func AutoImportIfNewer(ctx context.Context, store storage.Storage, dbPath string
 		return nil
 	}
 
+	// Finish or undo an export that crashed mid-write before reading, so a
+	// truncated JSONL is never imported as deletions
+	if r, err := atomicfile.Recover(jsonlPath); err != nil {
+		notify.Warnf("failed to recover interrupted export: %v", err)
+	} else if r != atomicfile.RecoveryNone {
+		notify.Infof("Recovered interrupted export of %s (%s)", filepath.Base(jsonlPath), r)
+	}
+
 	jsonlData, err := os.ReadFile(jsonlPath) // #nosec G304 - controlled path from config
 	if err != nil {
 		notify.Debugf("auto-import skipped, JSONL not readable: %v", err)
@@ -182,6 +182,46 @@ func TestAutoImportIfNewer_ChangedHash(t *testing.T) {
 	}
 }
 
+func TestAutoImportIfNewer_RecoversInterruptedExport(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "bd.db")
+	jsonlPath := filepath.Join(tmpDir, "issues.jsonl")
+
+	// The export crashed after the new contents were synced but before the
+	// rename: the old JSONL is still in place next to the finished temp file
+	oldContents := `{"id":"test-1","title":"Old","status":"open","priority":1,"issue_type":"task"}` + "\n"
+	newContents := oldContents + `{"id":"test-2","title":"New","status":"open","priority":1,"issue_type":"task"}` + "\n"
+	if err := os.WriteFile(jsonlPath, []byte(oldContents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(jsonlPath+".tmp.1", []byte(newContents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(newContents))
+	intent := `{"target":"issues.jsonl","temp":"issues.jsonl.tmp.1","sha256":"` + hex.EncodeToString(sum[:]) + `"}`
+	if err := os.WriteFile(jsonlPath+".export-intent", []byte(intent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store := memory.New("")
+	ctx := context.Background()
+	var receivedIssues []*types.Issue
+	importFunc := func(ctx context.Context, issues []*types.Issue) (int, int, map[string]string, error) {
+		receivedIssues = issues
+		return len(issues), 0, nil, nil
+	}
+
+	if err := AutoImportIfNewer(ctx, store, dbPath, &testNotifier{}, importFunc, nil); err != nil {
+		t.Fatalf("AutoImportIfNewer failed: %v", err)
+	}
+	if len(receivedIssues) != 2 {
+		t.Errorf("Expected the finished export's 2 issues, got %d", len(receivedIssues))
+	}
+	if _, err := os.Stat(jsonlPath + ".export-intent"); !os.IsNotExist(err) {
+		t.Error("Expected export intent to be cleared")
+	}
+}
+
 func TestAutoImportIfNewer_MergeConflict(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "bd-autoimport-test-*")
 	if err != nil {
@@ -0,0 +1,243 @@
+// Package automation evaluates "when X then Y" rules against issues. A
+// rule's condition is a query expression (see package query) and its
+// actions set fields or labels. Actions only report work when they would
+// change the issue, so rules can be re-evaluated after every mutation
+// without repeating themselves.
+package automation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/query"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Action kinds
+const (
+	AddLabel    = "add-label"
+	RemoveLabel = "remove-label"
+	SetStatus   = "set-status"
+	SetPriority = "set-priority"
+	Assign      = "assign"
+)
+
+// MaxPasses bounds how often rules are re-evaluated on one issue when the
+// actions of one rule make another match.
+const MaxPasses = 5
+
+// Action is one step of a rule's then clause.
+type Action struct {
+	Kind string `json:"kind"`
+	Arg  string `json:"arg"`
+}
+
+func (a Action) String() string {
+	return a.Kind + " " + a.Arg
+}
+
+// ParseAction parses "add-label <label>", "remove-label <label>",
+// "set-status <status>", "set-priority <0-4>" or "assign <name|none>".
+func ParseAction(s string) (Action, error) {
+	kind, arg, _ := strings.Cut(strings.TrimSpace(s), " ")
+	a := Action{Kind: strings.ToLower(kind), Arg: strings.TrimSpace(arg)}
+	if a.Arg == "" {
+		return a, fmt.Errorf("action %q needs an argument", s)
+	}
+	switch a.Kind {
+	case AddLabel, RemoveLabel, Assign:
+	case SetStatus:
+		if !types.Status(a.Arg).IsValid() {
+			return a, fmt.Errorf("action %q: unknown status %q", s, a.Arg)
+		}
+	case SetPriority:
+		p, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(a.Arg), "P"))
+		if err != nil || p < 0 || p > 4 {
+			return a, fmt.Errorf("action %q: priority must be 0-4", s)
+		}
+		a.Arg = strconv.Itoa(p)
+	default:
+		return a, fmt.Errorf("unknown action %q (use add-label, remove-label, set-status, set-priority or assign)", kind)
+	}
+	return a, nil
+}
+
+// Rule is a compiled rule.
+type Rule struct {
+	Name string
+	When query.Expr
+	Then []Action
+}
+
+// Compile parses configured rules. Rules that don't parse are returned as
+// errors and left out; the others still apply.
+func Compile(cfg []config.AutomationRule, opts query.Options) ([]*Rule, []error) {
+	var rules []*Rule
+	var errs []error
+	for _, c := range cfg {
+		if strings.TrimSpace(c.When) == "" {
+			errs = append(errs, fmt.Errorf("rule %s: missing when", c.Name))
+			continue
+		}
+		when, err := query.Parse(c.When, opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", c.Name, err))
+			continue
+		}
+		if len(c.Then) == 0 {
+			errs = append(errs, fmt.Errorf("rule %s: missing then", c.Name))
+			continue
+		}
+		rule := &Rule{Name: c.Name, When: when}
+		for _, src := range c.Then {
+			action, err := ParseAction(src)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rule %s: %w", c.Name, err))
+				rule = nil
+				break
+			}
+			rule.Then = append(rule.Then, action)
+		}
+		if rule != nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, errs
+}
+
+// Plan returns the actions of r that would change issue, or nil when the
+// rule doesn't match. issue needs its Labels and Dependencies loaded.
+func (r *Rule) Plan(issue *types.Issue) []Action {
+	if !r.When.Match(issue) {
+		return nil
+	}
+	var planned []Action
+	for _, a := range r.Then {
+		var changes bool
+		switch a.Kind {
+		case AddLabel:
+			changes = !slices.Contains(issue.Labels, a.Arg)
+		case RemoveLabel:
+			changes = slices.Contains(issue.Labels, a.Arg)
+		case SetStatus:
+			changes = string(issue.Status) != a.Arg
+		case SetPriority:
+			changes = strconv.Itoa(issue.Priority) != a.Arg
+		case Assign:
+			changes = issue.Assignee != assignee(a.Arg)
+		}
+		if changes {
+			planned = append(planned, a)
+		}
+	}
+	return planned
+}
+
+func assignee(arg string) string {
+	if arg == "none" {
+		return ""
+	}
+	return arg
+}
+
+// Firing records a rule that changed an issue.
+type Firing struct {
+	Rule    string   `json:"rule"`
+	IssueID string   `json:"issue_id"`
+	Actions []Action `json:"actions"`
+}
+
+// Load returns the issue with the fields rules read, or nil if it doesn't
+// exist.
+func Load(ctx context.Context, s storage.Storage, id string) (*types.Issue, error) {
+	issue, err := s.GetIssue(ctx, id)
+	if err != nil || issue == nil {
+		return nil, err
+	}
+	if issue.Labels, err = s.GetLabels(ctx, id); err != nil {
+		return nil, err
+	}
+	if issue.Dependencies, err = s.GetDependencyRecords(ctx, id); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// Run evaluates rules against the issue and applies the actions of those
+// that match, repeating while rules keep firing (up to MaxPasses). With
+// dryRun, it only reports what the first pass would do.
+func Run(ctx context.Context, s storage.Storage, rules []*Rule, id, actor string, dryRun bool) ([]Firing, error) {
+	var fired []Firing
+	for pass := 0; pass < MaxPasses; pass++ {
+		issue, err := Load(ctx, s, id)
+		if err != nil {
+			return fired, fmt.Errorf("loading %s: %w", id, err)
+		}
+		if issue == nil || issue.Status == types.StatusTombstone {
+			return fired, nil
+		}
+		changed := false
+		for _, rule := range rules {
+			actions := rule.Plan(issue)
+			if len(actions) == 0 {
+				continue
+			}
+			fired = append(fired, Firing{Rule: rule.Name, IssueID: id, Actions: actions})
+			if dryRun {
+				continue
+			}
+			if err := apply(ctx, s, issue, rule.Name, actions, actor); err != nil {
+				return fired, fmt.Errorf("rule %s on %s: %w", rule.Name, id, err)
+			}
+			changed = true
+			// Later rules see this rule's changes on the next pass
+			break
+		}
+		if dryRun || !changed {
+			return fired, nil
+		}
+	}
+	return fired, nil
+}
+
+func apply(ctx context.Context, s storage.Storage, issue *types.Issue, rule string, actions []Action, actor string) error {
+	updates := map[string]interface{}{}
+	for _, a := range actions {
+		switch a.Kind {
+		case AddLabel:
+			if err := s.AddLabel(ctx, issue.ID, a.Arg, actor); err != nil {
+				return err
+			}
+		case RemoveLabel:
+			if err := s.RemoveLabel(ctx, issue.ID, a.Arg, actor); err != nil {
+				return err
+			}
+		case SetStatus:
+			if a.Arg == string(types.StatusClosed) {
+				if err := s.CloseIssue(ctx, issue.ID, "automation: "+rule, actor, ""); err != nil {
+					return err
+				}
+				continue
+			}
+			updates["status"] = a.Arg
+		case SetPriority:
+			p, _ := strconv.Atoi(a.Arg)
+			updates["priority"] = p
+		case Assign:
+			if name := assignee(a.Arg); name != "" {
+				updates["assignee"] = name
+			} else {
+				updates["assignee"] = nil
+			}
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return s.UpdateIssue(ctx, issue.ID, updates, actor)
+}
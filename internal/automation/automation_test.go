@@ -0,0 +1,89 @@
+package automation
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/query"
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParseAction(t *testing.T) {
+	a, err := ParseAction("set-priority P1")
+	if err != nil || a.Kind != SetPriority || a.Arg != "1" {
+		t.Errorf("ParseAction(set-priority P1) = %+v, %v", a, err)
+	}
+	for _, bad := range []string{"add-label", "set-priority 7", "set-status nope", "delete bd-1"} {
+		if _, err := ParseAction(bad); err == nil {
+			t.Errorf("ParseAction(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCompileReportsBadRules(t *testing.T) {
+	rules, errs := Compile([]config.AutomationRule{
+		{Name: "ok", When: "status=closed", Then: []string{"add-label done"}},
+		{Name: "bad-when", When: "status=", Then: []string{"add-label x"}},
+		{Name: "bad-then", When: "status=open", Then: []string{"explode"}},
+		{Name: "no-then", When: "status=open"},
+	}, query.Options{})
+	if len(rules) != 1 || rules[0].Name != "ok" || len(errs) != 3 {
+		t.Errorf("rules = %d, errs = %v", len(rules), errs)
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New("")
+	issue := &types.Issue{ID: "bd-1", Title: "Ship", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddLabel(ctx, "bd-1", "needs-release", "tester"); err != nil {
+		t.Fatal(err)
+	}
+	rules, errs := Compile([]config.AutomationRule{
+		{Name: "release", When: "status=closed and label=needs-release", Then: []string{"add-label pending-release", "remove-label needs-release"}},
+		{Name: "chain", When: "label=pending-release", Then: []string{"set-priority 1", "assign none"}},
+	}, query.Options{})
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+
+	fired, err := Run(ctx, s, rules, "bd-1", "automation", false)
+	if err != nil || len(fired) != 0 {
+		t.Fatalf("open issue: fired %v, %v", fired, err)
+	}
+
+	if err := s.CloseIssue(ctx, "bd-1", "done", "tester", ""); err != nil {
+		t.Fatal(err)
+	}
+	fired, err = Run(ctx, s, rules, "bd-1", "automation", true)
+	if err != nil || len(fired) != 1 || fired[0].Rule != "release" {
+		t.Fatalf("dry run fired %v, %v", fired, err)
+	}
+	if labels, _ := s.GetLabels(ctx, "bd-1"); !slices.Equal(labels, []string{"needs-release"}) {
+		t.Errorf("dry run changed labels to %v", labels)
+	}
+
+	fired, err = Run(ctx, s, rules, "bd-1", "automation", false)
+	if err != nil || len(fired) != 2 || fired[1].Rule != "chain" {
+		t.Fatalf("fired %v, %v; want release then chain", fired, err)
+	}
+	// assign none is skipped: the issue has no assignee
+	if len(fired[1].Actions) != 1 || fired[1].Actions[0].Kind != SetPriority {
+		t.Errorf("chain actions = %v", fired[1].Actions)
+	}
+	got, _ := Load(ctx, s, "bd-1")
+	if !slices.Equal(got.Labels, []string{"pending-release"}) || got.Priority != 1 {
+		t.Errorf("issue after rules: labels %v, priority %d", got.Labels, got.Priority)
+	}
+
+	// Nothing left to change: the rules don't fire again
+	if fired, _ = Run(ctx, s, rules, "bd-1", "automation", false); len(fired) != 0 {
+		t.Errorf("rules fired again: %v", fired)
+	}
+}
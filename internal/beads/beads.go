@@ -316,6 +316,10 @@ type (
 	EventType = types.EventType
 	// Label represents a tag attached to an issue.
 	Label = types.Label
+	// Ref represents a typed external reference on an issue.
+	Ref = types.Ref
+	// Vote represents a voter's +1 or -1 on an issue.
+	Vote = types.Vote
 	// BlockedIssue represents an issue with blocking dependencies.
 	BlockedIssue = types.BlockedIssue
 	// TreeNode represents a node in a dependency tree.
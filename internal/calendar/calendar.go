@@ -0,0 +1,264 @@
+// Package calendar builds an iCalendar (.ics) feed of due dates, milestones
+// and sprint boundaries, for subscription in calendar apps.
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/feed"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+// icsDateFormat is the iCalendar DATE value format (all-day events).
+const icsDateFormat = "20060102"
+
+// milestoneLabelPrefix marks the issues of a milestone, as in bd milestone.
+const milestoneLabelPrefix = "milestone:"
+
+// Event is a single all-day entry in the calendar.
+type Event struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	Categories  []string
+	RRule       string // Optional recurrence rule (sprint boundaries)
+}
+
+// Options selects the issues of a calendar.
+type Options struct {
+	IncludeClosed bool
+
+	// Visibility is the most confidential visibility level included
+	// (public, internal or private); empty includes every issue.
+	Visibility string
+}
+
+// Collect returns the due-date and milestone events of the issues matching
+// opts, sorted by date.
+func Collect(ctx context.Context, s storage.Storage, opts Options) ([]*Event, error) {
+	// Only issues with a due date matter; the zero time matches any due_at.
+	filter := types.IssueFilter{DueAfter: &time.Time{}}
+	if !opts.IncludeClosed {
+		filter.ExcludeStatus = []types.Status{types.StatusClosed}
+	}
+	matches, err := s.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	// Search results don't carry scheduling fields, so load full issues.
+	issues := make([]*types.Issue, 0, len(matches))
+	for _, match := range matches {
+		issue, err := s.GetIssue(ctx, match.ID)
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil {
+			issues = append(issues, issue)
+		}
+	}
+	if opts.Visibility != "" {
+		if err := visibility.Populate(ctx, s, issues); err != nil {
+			return nil, err
+		}
+		issues = visibility.Filter(issues, opts.Visibility)
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		issue.Labels = labels[issue.ID]
+	}
+	return BuildEvents(issues), nil
+}
+
+// BuildEvents creates due-date events for issues and one event per
+// milestone label, dated at the latest due date among the milestone's issues.
+func BuildEvents(issues []*types.Issue) []*Event {
+	var events []*Event
+	milestones := make(map[string]*Event)
+
+	for _, issue := range issues {
+		if issue.DueAt == nil || issue.Status == types.StatusTombstone {
+			continue
+		}
+		due := issue.DueAt.Local()
+		summary := fmt.Sprintf("[%s] %s", issue.ID, issue.Title)
+		if issue.Status == types.StatusClosed {
+			summary = "✓ " + summary
+		}
+		events = append(events, &Event{
+			UID:         issue.ID + "-due@beads",
+			Date:        due,
+			Summary:     summary,
+			Description: fmt.Sprintf("%s P%d %s (%s)", issue.IssueType, issue.Priority, issue.ID, issue.Status),
+			Categories:  append([]string{string(issue.IssueType)}, issue.Labels...),
+		})
+
+		for _, label := range issue.Labels {
+			if !strings.HasPrefix(label, milestoneLabelPrefix) {
+				continue
+			}
+			name := strings.TrimPrefix(label, milestoneLabelPrefix)
+			m, ok := milestones[name]
+			if !ok {
+				m = &Event{
+					UID:         "milestone-" + name + "@beads",
+					Summary:     "Milestone: " + name,
+					Description: "Latest due date among issues labeled " + label,
+					Categories:  []string{"milestone"},
+				}
+				milestones[name] = m
+			}
+			if due.After(m.Date) {
+				m.Date = due
+			}
+		}
+	}
+
+	for _, m := range milestones {
+		events = append(events, m)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Date.Equal(events[j].Date) {
+			return events[i].Date.Before(events[j].Date)
+		}
+		return events[i].UID < events[j].UID
+	})
+	return events
+}
+
+// SprintEvent returns a recurring event marking the start of each sprint,
+// or nil if no sprint cadence is configured (start is empty).
+func SprintEvent(start string, lengthDays int) (*Event, error) {
+	if start == "" {
+		return nil, nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", start, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sprint.start %q (expected YYYY-MM-DD)", start)
+	}
+	if lengthDays < 1 {
+		lengthDays = 14
+	}
+	rrule := fmt.Sprintf("FREQ=DAILY;INTERVAL=%d", lengthDays)
+	if lengthDays%7 == 0 {
+		rrule = fmt.Sprintf("FREQ=WEEKLY;INTERVAL=%d", lengthDays/7)
+	}
+	return &Event{
+		UID:         "sprint-start@beads",
+		Date:        date,
+		Summary:     "Sprint start",
+		Description: fmt.Sprintf("New %d-day sprint begins", lengthDays),
+		Categories:  []string{"sprint"},
+		RRule:       rrule,
+	}, nil
+}
+
+// WriteICS writes events as an RFC 5545 iCalendar document.
+func WriteICS(w io.Writer, events []*Event, now time.Time) error {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldLine(s))
+		b.WriteString("\r\n")
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//beads//bd calendar export//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:beads")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + escapeText(e.UID))
+		line("DTSTAMP:" + stamp)
+		line("DTSTART;VALUE=DATE:" + e.Date.Format(icsDateFormat))
+		line("DTEND;VALUE=DATE:" + e.Date.AddDate(0, 0, 1).Format(icsDateFormat))
+		if e.RRule != "" {
+			line("RRULE:" + e.RRule)
+		}
+		line("SUMMARY:" + escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeText(e.Description))
+		}
+		if len(e.Categories) > 0 {
+			cats := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				cats[i] = escapeText(c)
+			}
+			line("CATEGORIES:" + strings.Join(cats, ","))
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Handler serves the calendar at /calendar.ics; all=true includes closed
+// issues. sprint, if not nil, is added to every response. Private issues
+// are never served, and with token set only requests carrying it see
+// internal issues, as for the activity feed.
+func Handler(s storage.Storage, token string, sprint *Event) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /calendar.ics", func(w http.ResponseWriter, r *http.Request) {
+		opts := Options{
+			IncludeClosed: r.URL.Query().Get("all") == "true",
+			Visibility:    feed.Audience(r, token),
+		}
+		events, err := Collect(r.Context(), s, opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if sprint != nil {
+			events = append(events, sprint)
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		_ = WriteICS(w, events, time.Now())
+	})
+	return mux
+}
+
+// escapeText escapes a TEXT value per RFC 5545 section 3.3.11.
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// foldLine folds content lines longer than 75 octets, without splitting
+// multi-byte UTF-8 characters.
+func foldLine(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1 // Leading space counts toward the limit
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,151 @@
+package calendar
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestBuildEvents(t *testing.T) {
+	d1 := time.Date(2026, 6, 1, 12, 0, 0, 0, time.Local)
+	d2 := time.Date(2026, 6, 15, 12, 0, 0, 0, time.Local)
+	issues := []*types.Issue{
+		{ID: "bd-1", Title: "First", IssueType: types.TypeTask, Status: types.StatusOpen, DueAt: &d2, Labels: []string{"milestone:v1"}},
+		{ID: "bd-2", Title: "Second", IssueType: types.TypeBug, Status: types.StatusOpen, DueAt: &d1, Labels: []string{"milestone:v1"}},
+		{ID: "bd-3", Title: "No due date", Status: types.StatusOpen, Labels: []string{"milestone:v2"}},
+	}
+
+	events := BuildEvents(issues)
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if events[0].UID != "bd-2-due@beads" {
+		t.Errorf("expected events sorted by date, first = %s", events[0].UID)
+	}
+	var milestone *Event
+	for _, e := range events {
+		if e.UID == "milestone-v1@beads" {
+			milestone = e
+		}
+	}
+	if milestone == nil || !milestone.Date.Equal(d2) {
+		t.Errorf("expected milestone v1 on latest due date, got %+v", milestone)
+	}
+}
+
+func TestSprintEvent(t *testing.T) {
+	if e, err := SprintEvent("", 14); e != nil || err != nil {
+		t.Errorf("expected no sprint event without sprint.start, got %+v, %v", e, err)
+	}
+	if e, _ := SprintEvent("2026-01-05", 14); e == nil || e.RRule != "FREQ=WEEKLY;INTERVAL=2" {
+		t.Errorf("unexpected sprint event: %+v", e)
+	}
+	if e, _ := SprintEvent("2026-01-05", 10); e == nil || e.RRule != "FREQ=DAILY;INTERVAL=10" {
+		t.Errorf("unexpected sprint event: %+v", e)
+	}
+	if _, err := SprintEvent("next monday", 14); err == nil {
+		t.Error("expected an error for an invalid sprint.start")
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	events := []*Event{{
+		UID:        "bd-1-due@beads",
+		Date:       time.Date(2026, 6, 1, 0, 0, 0, 0, time.Local),
+		Summary:    "[bd-1] Fix parser; handle commas, too",
+		Categories: []string{"bug"},
+	}}
+	var b strings.Builder
+	if err := WriteICS(&b, events, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTAMP:20260501T000000Z\r\n",
+		"DTSTART;VALUE=DATE:20260601\r\n",
+		"DTEND;VALUE=DATE:20260602\r\n",
+		`SUMMARY:[bd-1] Fix parser\; handle commas\, too` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	long := "DESCRIPTION:" + strings.Repeat("é", 60)
+	folded := foldLine(long)
+	for _, line := range strings.Split(folded, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line exceeds 75 octets: %d", len(line))
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != long {
+		t.Error("unfolding should restore the original line")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	s, err := sqlite.New(ctx, t.TempDir()+"/test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	due := time.Date(2026, 6, 1, 12, 0, 0, 0, time.Local)
+	for _, issue := range []*types.Issue{
+		{ID: "bd-1", Title: "Launch", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, DueAt: &due},
+		{ID: "bd-2", Title: "Retro", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, DueAt: &due},
+	} {
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.CloseIssue(ctx, "bd-2", "done", "tester", ""); err != nil {
+		t.Fatal(err)
+	}
+	sprint, _ := SprintEvent("2026-01-05", 14)
+	srv := httptest.NewServer(Handler(s, "s3cret", sprint))
+	defer srv.Close()
+
+	get := func(query string) (string, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + "/calendar.ics" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Header.Get("Content-Type"), string(body)
+	}
+
+	// The test issues are internal: only requests with the token see them
+	contentType, body := get("")
+	if !strings.HasPrefix(contentType, "text/calendar") {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+	if strings.Contains(body, "bd-1") || !strings.Contains(body, "UID:sprint-start@beads") {
+		t.Errorf("anonymous calendar should hold only the sprint:\n%s", body)
+	}
+	if _, body := get("?token=s3cret"); !strings.Contains(body, "UID:bd-1-due@beads") || strings.Contains(body, "bd-2") {
+		t.Errorf("calendar with token should hold open bd-1 only:\n%s", body)
+	}
+	if _, body := get("?token=s3cret&all=true"); !strings.Contains(body, "UID:bd-2-due@beads") {
+		t.Errorf("all=true should include closed bd-2:\n%s", body)
+	}
+}
@@ -0,0 +1,118 @@
+// Package cc holds the stakeholders cc'd on issues (--cc): people or email
+// addresses who aren't the assignee but want to stay informed. The list
+// travels with issues through the JSONL, and digests and hook payloads
+// include it.
+package cc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ErrUnsupported is returned for storage backends without cc lists.
+var ErrUnsupported = errors.New("cc lists require the SQLite backend")
+
+// MaxEntry bounds the length of a cc entry.
+const MaxEntry = 200
+
+// Store is the storage interface for cc lists.
+type Store interface {
+	SetCC(ctx context.Context, issueID string, cc []string, actor string) error
+	GetCC(ctx context.Context, issueID string) ([]string, error)
+	GetCCForIssues(ctx context.Context, issueIDs []string) (map[string][]string, error)
+	GetCCIssueIDs(ctx context.Context, person string) ([]string, error)
+}
+
+// For returns s as a cc store.
+func For(s storage.Storage) (Store, error) {
+	cs, ok := s.(Store)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return cs, nil
+}
+
+// Populate fills in the CC of issues, as exports, show, digests and hooks
+// do. Stores that don't keep cc lists leave issues unchanged.
+func Populate(ctx context.Context, s storage.Storage, issues []*types.Issue) error {
+	cs, err := For(s)
+	if err != nil || len(issues) == 0 {
+		return nil
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	byIssue, err := cs.GetCCForIssues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get cc lists: %w", err)
+	}
+	for _, issue := range issues {
+		issue.CC = byIssue[issue.ID]
+	}
+	return nil
+}
+
+// Normalize cleans up --cc values: entries are trimmed, a leading @ is
+// dropped, empty entries are skipped and duplicates removed. The result is
+// sorted. Entries are names or email addresses and can't be longer than
+// MaxEntry.
+func Normalize(entries []string) ([]string, error) {
+	var out []string
+	for _, e := range entries {
+		e = strings.TrimPrefix(strings.TrimSpace(e), "@")
+		if e == "" {
+			continue
+		}
+		if len(e) > MaxEntry {
+			return nil, fmt.Errorf("cc entry %q is longer than %d characters", e[:20]+"…", MaxEntry)
+		}
+		if strings.ContainsAny(e, ",;\r\n") {
+			return nil, fmt.Errorf("invalid cc entry %q", e)
+		}
+		out = append(out, e)
+	}
+	slices.Sort(out)
+	return slices.Compact(out), nil
+}
+
+// Apply returns current with add added and remove removed.
+func Apply(current, add, remove []string) []string {
+	var out []string
+	for _, e := range append(slices.Clone(current), add...) {
+		if !slices.Contains(remove, e) {
+			out = append(out, e)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// Addresses returns the entries of cc lists that are email addresses, for
+// mail recipients, deduplicated in order.
+func Addresses(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, list := range lists {
+		for _, e := range list {
+			addr, err := mail.ParseAddress(e)
+			if err != nil || seen[strings.ToLower(addr.Address)] {
+				continue
+			}
+			seen[strings.ToLower(addr.Address)] = true
+			if addr.Name == "" {
+				out = append(out, addr.Address)
+			} else {
+				out = append(out, addr.String())
+			}
+		}
+	}
+	return out
+}
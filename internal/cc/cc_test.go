@@ -0,0 +1,37 @@
+package cc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	got, err := Normalize([]string{" @alice ", "pm@example.com", "", "alice", "Jane Doe <jane@example.com>"})
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	want := []string{"Jane Doe <jane@example.com>", "alice", "pm@example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Normalize = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"a;b", "line\nbreak"} {
+		if _, err := Normalize([]string{bad}); err == nil {
+			t.Errorf("Normalize(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	got := Apply([]string{"alice", "bob"}, []string{"carol", "alice"}, []string{"bob"})
+	if want := []string{"alice", "carol"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply = %v, want %v", got, want)
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	got := Addresses([]string{"alice", "pm@example.com"}, []string{"PM@example.com", "Jane Doe <jane@example.com>"})
+	want := []string{"pm@example.com", `"Jane Doe" <jane@example.com>`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Addresses = %v, want %v", got, want)
+	}
+}
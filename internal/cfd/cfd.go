@@ -0,0 +1,256 @@
+// Package cfd builds cumulative flow diagram data: how many issues were in
+// each status at the end of each day (bd stats cfd).
+//
+// Days are taken from daily snapshots where they were recorded and
+// otherwise backfilled from each issue's status history. Issues that came
+// in through JSONL have no history in this database; they count as open
+// from creation until they were closed.
+package cfd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// DayLayout is the format of snapshot days.
+const DayLayout = "2006-01-02"
+
+// ErrUnsupported is returned for storage backends without status snapshots.
+var ErrUnsupported = errors.New("status snapshots require the SQLite backend")
+
+// Store is the storage interface for daily status snapshots.
+type Store interface {
+	RecordStatusSnapshot(ctx context.Context, day string, counts map[string]int) error
+	GetStatusSnapshots(ctx context.Context, first, last string) (map[string]map[string]int, error)
+}
+
+// For returns s as a snapshot store.
+func For(s storage.Storage) (Store, error) {
+	ss, ok := s.(Store)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return ss, nil
+}
+
+// Day is the issue count per status at the end of one day.
+type Day struct {
+	Date       string         `json:"date"`
+	Counts     map[string]int `json:"counts"`
+	Backfilled bool           `json:"backfilled,omitempty"` // Rebuilt from history, not a recorded snapshot
+}
+
+// Diagram is the daily status counts from Since to today.
+type Diagram struct {
+	Since    string   `json:"since"`
+	Statuses []string `json:"statuses"` // Workflow order, closed last
+	Days     []Day    `json:"days"`
+}
+
+// workflowOrder orders the built-in statuses in a diagram. Custom statuses
+// go between these and closed.
+var workflowOrder = []string{
+	string(types.StatusOpen),
+	string(types.StatusInProgress),
+	string(types.StatusBlocked),
+	string(types.StatusDeferred),
+	string(types.StatusHooked),
+	string(types.StatusPinned),
+}
+
+// Counts returns the number of issues in each status, tombstones aside.
+func Counts(issues []*types.Issue) map[string]int {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Status != types.StatusTombstone {
+			counts[string(issue.Status)]++
+		}
+	}
+	return counts
+}
+
+// Record stores today's counts as the snapshot of today.
+func Record(ctx context.Context, s storage.Storage, now time.Time) error {
+	ss, err := For(s)
+	if err != nil {
+		return err
+	}
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return fmt.Errorf("failed to get issues: %w", err)
+	}
+	return ss.RecordStatusSnapshot(ctx, now.Format(DayLayout), Counts(issues))
+}
+
+// History is an issue's status over time.
+type History struct {
+	Created     time.Time
+	Initial     types.Status
+	Transitions []Transition // Oldest first
+}
+
+// Transition is a change of status.
+type Transition struct {
+	At     time.Time
+	Status types.Status
+	Actor  string // Empty when inferred from the close time
+}
+
+// NewHistory rebuilds an issue's status history from its events. Without
+// status events, the issue is taken to have been open until it was closed,
+// or in its current status since creation.
+func NewHistory(issue *types.Issue, events []*types.Event) *History {
+	h := &History{Created: issue.CreatedAt}
+	sorted := slices.Clone(events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+	for _, e := range sorted {
+		var status types.Status
+		switch e.EventType {
+		case types.EventClosed:
+			status = types.StatusClosed
+		case types.EventStatusChanged, types.EventReopened:
+			status = statusField(e.NewValue)
+		}
+		if status == "" {
+			continue
+		}
+		if len(h.Transitions) == 0 {
+			h.Initial = statusField(e.OldValue)
+		}
+		h.Transitions = append(h.Transitions, Transition{At: e.CreatedAt, Status: status, Actor: e.Actor})
+	}
+
+	switch {
+	case len(h.Transitions) > 0 && h.Initial == "":
+		h.Initial = types.StatusOpen
+	case len(h.Transitions) == 0 && issue.Status == types.StatusClosed && issue.ClosedAt != nil:
+		h.Initial = types.StatusOpen
+		h.Transitions = []Transition{{At: *issue.ClosedAt, Status: types.StatusClosed}}
+	case len(h.Transitions) == 0:
+		h.Initial = issue.Status
+	}
+	return h
+}
+
+// statusField reads the status out of an event's JSON value.
+func statusField(value *string) types.Status {
+	if value == nil {
+		return ""
+	}
+	var fields struct {
+		Status types.Status `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(*value), &fields); err != nil {
+		return ""
+	}
+	return fields.Status
+}
+
+// At returns the status at t, and false if the issue did not exist yet.
+func (h *History) At(t time.Time) (types.Status, bool) {
+	if t.Before(h.Created) {
+		return "", false
+	}
+	status := h.Initial
+	for _, tr := range h.Transitions {
+		if tr.At.After(t) {
+			break
+		}
+		status = tr.Status
+	}
+	return status, true
+}
+
+// Build assembles the diagram from since to now. Recorded snapshots are
+// used as they are; other past days are backfilled from history, and today
+// is always counted live.
+func Build(ctx context.Context, s storage.Storage, since, now time.Time) (*Diagram, error) {
+	loc := now.Location()
+	first := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if first.After(today) {
+		first = today
+	}
+
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	snapshots := map[string]map[string]int{}
+	if ss, err := For(s); err == nil {
+		if snapshots, err = ss.GetStatusSnapshots(ctx, first.Format(DayLayout), today.Format(DayLayout)); err != nil {
+			return nil, fmt.Errorf("failed to get status snapshots: %w", err)
+		}
+	}
+
+	// Issues untouched since the first day kept one status all along, so
+	// only the others need their events read.
+	histories := make([]*History, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Status == types.StatusTombstone {
+			continue
+		}
+		var events []*types.Event
+		if !issue.UpdatedAt.Before(first) {
+			if events, err = s.GetEvents(ctx, issue.ID, 0); err != nil {
+				return nil, fmt.Errorf("failed to get events of %s: %w", issue.ID, err)
+			}
+		}
+		histories = append(histories, NewHistory(issue, events))
+	}
+
+	d := &Diagram{Since: first.Format(DayLayout), Days: []Day{}}
+	seen := make(map[string]bool)
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(DayLayout)
+		var entry Day
+		switch {
+		case day.Equal(today):
+			entry = Day{Date: date, Counts: Counts(issues)}
+		case snapshots[date] != nil:
+			entry = Day{Date: date, Counts: snapshots[date]}
+		default:
+			end := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			entry = Day{Date: date, Counts: make(map[string]int), Backfilled: true}
+			for _, h := range histories {
+				if status, ok := h.At(end); ok {
+					entry.Counts[string(status)]++
+				}
+			}
+		}
+		for status := range entry.Counts {
+			seen[status] = true
+		}
+		d.Days = append(d.Days, entry)
+	}
+	d.Statuses = orderStatuses(seen)
+	return d, nil
+}
+
+// orderStatuses lists the statuses of a diagram: open, in progress, blocked
+// and closed always, other statuses only when they occur.
+func orderStatuses(seen map[string]bool) []string {
+	statuses := []string{}
+	for _, status := range workflowOrder {
+		if seen[status] || status == string(types.StatusOpen) || status == string(types.StatusInProgress) || status == string(types.StatusBlocked) {
+			statuses = append(statuses, status)
+		}
+	}
+	var custom []string
+	for status := range seen {
+		if !slices.Contains(workflowOrder, status) && status != string(types.StatusClosed) {
+			custom = append(custom, status)
+		}
+	}
+	sort.Strings(custom)
+	statuses = append(statuses, custom...)
+	return append(statuses, string(types.StatusClosed))
+}
@@ -0,0 +1,112 @@
+package cfd
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func strptr(s string) *string { return &s }
+
+func TestHistory(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	issue := &types.Issue{ID: "bd-1", Status: types.StatusClosed, CreatedAt: created}
+	events := []*types.Event{
+		// Out of order on purpose: GetEvents returns newest first
+		{EventType: types.EventClosed, CreatedAt: created.Add(3 * day)},
+		{EventType: types.EventStatusChanged, CreatedAt: created.Add(day),
+			OldValue: strptr(`{"status":"blocked"}`), NewValue: strptr(`{"status":"in_progress"}`)},
+		{EventType: types.EventUpdated, CreatedAt: created.Add(2 * day), NewValue: strptr(`{"title":"x"}`)},
+	}
+	h := NewHistory(issue, events)
+	for _, tt := range []struct {
+		at   time.Time
+		want types.Status
+		ok   bool
+	}{
+		{created.Add(-time.Hour), "", false},
+		{created, types.StatusBlocked, true},
+		{created.Add(day), types.StatusInProgress, true},
+		{created.Add(2 * day), types.StatusInProgress, true},
+		{created.Add(4 * day), types.StatusClosed, true},
+	} {
+		if got, ok := h.At(tt.at); got != tt.want || ok != tt.ok {
+			t.Errorf("At(%v) = %q, %v; want %q, %v", tt.at, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// Without events a closed issue was open until it closed
+	closedAt := created.Add(2 * day)
+	h = NewHistory(&types.Issue{Status: types.StatusClosed, CreatedAt: created, ClosedAt: &closedAt}, nil)
+	if got, _ := h.At(created.Add(day)); got != types.StatusOpen {
+		t.Errorf("before close = %q, want open", got)
+	}
+	if got, _ := h.At(closedAt); got != types.StatusClosed {
+		t.Errorf("after close = %q, want closed", got)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	s, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for _, id := range []string{"bd-1", "bd-2"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, CreatedAt: now.AddDate(0, 0, -5)}
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.CloseIssue(ctx, "bd-2", "done", "tester", ""); err != nil {
+		t.Fatal(err)
+	}
+	yesterday := now.AddDate(0, 0, -1).Format(DayLayout)
+	if err := s.RecordStatusSnapshot(ctx, yesterday, map[string]int{"open": 7, "in_progress": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Build(ctx, s, now.AddDate(0, 0, -3), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Days) != 4 {
+		t.Fatalf("got %d days, want 4", len(d.Days))
+	}
+	if !slices.Equal(d.Statuses, []string{"open", "in_progress", "blocked", "closed"}) {
+		t.Errorf("Statuses = %v", d.Statuses)
+	}
+	// Backfilled: both issues existed and bd-2 only closed today
+	if first := d.Days[0]; !first.Backfilled || first.Counts["open"] != 2 || first.Counts["closed"] != 0 {
+		t.Errorf("first day = %+v, want 2 open, backfilled", first)
+	}
+	if snap := d.Days[2]; snap.Date != yesterday || snap.Backfilled || snap.Counts["open"] != 7 {
+		t.Errorf("yesterday = %+v, want the recorded snapshot", snap)
+	}
+	if today := d.Days[3]; today.Counts["open"] != 1 || today.Counts["closed"] != 1 {
+		t.Errorf("today = %+v, want 1 open and 1 closed", today)
+	}
+
+	if err := Record(ctx, s, now); err != nil {
+		t.Fatal(err)
+	}
+	snaps, err := s.GetStatusSnapshots(ctx, now.Format(DayLayout), now.Format(DayLayout))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := snaps[now.Format(DayLayout)]; got["open"] != 1 || got["closed"] != 1 {
+		t.Errorf("recorded snapshot = %v", got)
+	}
+}
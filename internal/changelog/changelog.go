@@ -0,0 +1,221 @@
+// Package changelog edits CHANGELOG.md files in the Keep a Changelog format
+// (https://keepachangelog.com): entries go under "## [Unreleased]", grouped
+// in "### Added", "### Fixed", ... subsections, until a release turns the
+// Unreleased section into a versioned one.
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Sections are the Keep a Changelog change types, in their usual order.
+var Sections = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// Entry is one line to add.
+type Entry struct {
+	Section string // one of Sections
+	Text    string
+}
+
+// Template is the file written when there is no changelog yet.
+const Template = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+
+## [Unreleased]
+`
+
+var (
+	unreleasedRe = regexp.MustCompile(`(?i)^##\s+\[?unreleased\]?\s*$`)
+	versionRe    = regexp.MustCompile(`^##\s`)
+	subsectionRe = regexp.MustCompile(`^###\s+(.+?)\s*$`)
+)
+
+// Section maps a change type name, case-insensitively and allowing a few
+// common synonyms (fix, feature, ...), to one of Sections; ok is false for
+// anything else.
+func Section(name string) (section string, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "added", "add", "feature", "new":
+		return "Added", true
+	case "changed", "change", "changes":
+		return "Changed", true
+	case "deprecated", "deprecate", "deprecation":
+		return "Deprecated", true
+	case "removed", "remove", "removal":
+		return "Removed", true
+	case "fixed", "fix", "bugfix":
+		return "Fixed", true
+	case "security":
+		return "Security", true
+	}
+	return "", false
+}
+
+// Insert adds entries under the Unreleased section of content, creating
+// that section and any missing subsections. New subsections are placed in
+// the order of Sections; existing ones are left where they are.
+func Insert(content string, entries []Entry) string {
+	if len(entries) == 0 {
+		return content
+	}
+	if strings.TrimSpace(content) == "" {
+		content = Template
+	}
+	lines := splitLines(content)
+	start, end := unreleasedBlock(lines)
+	if start < 0 {
+		lines = addUnreleased(lines)
+		start, end = unreleasedBlock(lines)
+	}
+
+	// The block is a preamble followed by ### subsections
+	type subsection struct {
+		name  string
+		lines []string
+	}
+	var preamble []string
+	var subs []*subsection
+	for _, line := range lines[start+1 : end] {
+		if m := subsectionRe.FindStringSubmatch(line); m != nil {
+			subs = append(subs, &subsection{name: m[1]})
+			continue
+		}
+		if len(subs) == 0 {
+			preamble = append(preamble, line)
+		} else {
+			subs[len(subs)-1].lines = append(subs[len(subs)-1].lines, line)
+		}
+	}
+	find := func(name string) *subsection {
+		for _, s := range subs {
+			if strings.EqualFold(s.name, name) {
+				return s
+			}
+		}
+		return nil
+	}
+	for _, e := range entries {
+		sub := find(e.Section)
+		if sub == nil {
+			sub = &subsection{name: e.Section}
+			rank := slices.Index(Sections, e.Section)
+			at := len(subs)
+			for i, s := range subs {
+				if r := slices.Index(Sections, s.name); r > rank {
+					at = i
+					break
+				}
+			}
+			subs = slices.Insert(subs, at, sub)
+		}
+		sub.lines = append(trimBlank(sub.lines), "- "+oneLine(e.Text))
+	}
+
+	block := []string{lines[start]}
+	if p := trimBlank(preamble); len(p) > 0 {
+		block = append(block, "")
+		block = append(block, trimLeadingBlank(p)...)
+	}
+	for _, s := range subs {
+		block = append(block, "", "### "+s.name)
+		if body := trimLeadingBlank(trimBlank(s.lines)); len(body) > 0 {
+			block = append(block, "")
+			block = append(block, body...)
+		}
+	}
+	if end < len(lines) {
+		block = append(block, "")
+	}
+	return joinLines(slices.Concat(lines[:start], block, lines[end:]))
+}
+
+// Release turns the Unreleased section into "## [version] - date" and
+// starts a new, empty Unreleased section above it. It fails when there is
+// no Unreleased section or it has no entries.
+func Release(content, version string, date time.Time) (string, error) {
+	lines := splitLines(content)
+	start, end := unreleasedBlock(lines)
+	if start < 0 {
+		return content, fmt.Errorf("no [Unreleased] section")
+	}
+	if len(trimLeadingBlank(trimBlank(slices.Clone(lines[start+1:end])))) == 0 {
+		return content, fmt.Errorf("the [Unreleased] section is empty")
+	}
+	heading := fmt.Sprintf("## [%s] - %s", strings.TrimPrefix(version, "v"), date.Format("2006-01-02"))
+	out := slices.Concat(lines[:start], []string{"## [Unreleased]", "", heading}, lines[start+1:])
+	return joinLines(out), nil
+}
+
+// unreleasedBlock returns the line range of the Unreleased section: its
+// heading and the lines up to the next "## " heading. start is -1 when
+// there is none.
+func unreleasedBlock(lines []string) (start, end int) {
+	start = -1
+	for i, line := range lines {
+		if start < 0 {
+			if unreleasedRe.MatchString(line) {
+				start = i
+			}
+			continue
+		}
+		if versionRe.MatchString(line) {
+			return start, i
+		}
+	}
+	return start, len(lines)
+}
+
+// addUnreleased inserts an Unreleased heading before the first release, or
+// at the end.
+func addUnreleased(lines []string) []string {
+	at := len(lines)
+	for i, line := range lines {
+		if versionRe.MatchString(line) {
+			at = i
+			break
+		}
+	}
+	out := trimBlank(slices.Clone(lines[:at]))
+	if len(out) > 0 {
+		out = append(out, "")
+	}
+	out = append(out, "## [Unreleased]")
+	if at < len(lines) {
+		out = append(out, "")
+	}
+	return append(out, lines[at:]...)
+}
+
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(strings.TrimRight(s, "\n"), "\n")
+}
+
+func joinLines(lines []string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func trimBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func trimLeadingBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	return lines
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
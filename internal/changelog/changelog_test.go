@@ -0,0 +1,94 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInsert(t *testing.T) {
+	in := `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Crash on start
+
+## [1.0.0] - 2026-01-01
+
+### Added
+
+- First release
+`
+	got := Insert(in, []Entry{
+		{Section: "Security", Text: "Escape titles (bd-3)"},
+		{Section: "Fixed", Text: "Login  loop\n(bd-1)"},
+		{Section: "Added", Text: "Dark mode (bd-2)"},
+	})
+	want := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Dark mode (bd-2)
+
+### Fixed
+
+- Crash on start
+- Login loop (bd-1)
+
+### Security
+
+- Escape titles (bd-3)
+
+## [1.0.0] - 2026-01-01
+
+### Added
+
+- First release
+`
+	if got != want {
+		t.Errorf("Insert =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestInsertCreatesUnreleased(t *testing.T) {
+	got := Insert("# Changelog\n\nNotes.\n\n## [1.0.0] - 2026-01-01\n", []Entry{{Section: "Changed", Text: "Faster sync"}})
+	want := "# Changelog\n\nNotes.\n\n## [Unreleased]\n\n### Changed\n\n- Faster sync\n\n## [1.0.0] - 2026-01-01\n"
+	if got != want {
+		t.Errorf("Insert =\n%q\nwant\n%q", got, want)
+	}
+
+	got = Insert("", []Entry{{Section: "Added", Text: "Everything"}})
+	if !strings.HasPrefix(got, "# Changelog\n") || !strings.HasSuffix(got, "## [Unreleased]\n\n### Added\n\n- Everything\n") {
+		t.Errorf("Insert into empty file =\n%s", got)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	in := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Dark mode\n\n## [1.0.0] - 2026-01-01\n"
+	got, err := Release(in, "v1.1.0", time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Changelog\n\n## [Unreleased]\n\n## [1.1.0] - 2026-02-03\n\n### Added\n\n- Dark mode\n\n## [1.0.0] - 2026-01-01\n"
+	if got != want {
+		t.Errorf("Release =\n%q\nwant\n%q", got, want)
+	}
+	if _, err := Release(got, "1.2.0", time.Now()); err == nil {
+		t.Error("releasing an empty Unreleased section should fail")
+	}
+}
+
+func TestSection(t *testing.T) {
+	for in, want := range map[string]string{"fix": "Fixed", "Added": "Added", "SECURITY": "Security", "feature": "Added"} {
+		if got, ok := Section(in); !ok || got != want {
+			t.Errorf("Section(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if _, ok := Section("misc"); ok {
+		t.Error("Section(misc) should not match")
+	}
+}
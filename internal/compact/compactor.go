@@ -21,6 +21,7 @@ type Config struct {
 	DryRun       bool
 	AuditEnabled bool
 	Actor        string
+	Progress     func(done, total int) // Called as batch results arrive (optional)
 }
 
 // Compactor handles issue compaction using AI summarization.
@@ -165,6 +166,11 @@ func (c *Compactor) CompactTier1(ctx context.Context, issueID string) error {
 	return nil
 }
 
+// SetProgress registers fn to be called as CompactTier1Batch results arrive.
+func (c *Compactor) SetProgress(fn func(done, total int)) {
+	c.config.Progress = fn
+}
+
 // CompactTier1Batch performs tier-1 compaction on multiple issues in a single batch.
 func (c *Compactor) CompactTier1Batch(ctx context.Context, issueIDs []string) ([]*Result, error) {
 	if len(issueIDs) == 0 {
@@ -247,8 +253,13 @@ func (c *Compactor) CompactTier1Batch(ctx context.Context, issueIDs []string) ([
 		close(resultCh)
 	}()
 
+	done := 0
 	for result := range resultCh {
 		results = append(results, result)
+		done++
+		if c.config.Progress != nil {
+			c.config.Progress(done, len(eligibleIDs))
+		}
 	}
 
 	return results, nil
@@ -259,7 +259,9 @@ func TestCompactTier1Batch_MixedResults(t *testing.T) {
 		},
 	}
 	summary := &stubSummarizer{summary: "short"}
+	var progress []string
 	c := &Compactor{store: store, summarizer: summary, config: &Config{Concurrency: 2}}
+	c.SetProgress(func(done, total int) { progress = append(progress, fmt.Sprintf("%d/%d", done, total)) })
 
 	results, err := c.CompactTier1Batch(context.Background(), []string{"bd-1", "bd-2"})
 	if err != nil {
@@ -288,4 +290,7 @@ func TestCompactTier1Batch_MixedResults(t *testing.T) {
 	if summary.calls != 1 {
 		t.Fatalf("summarizer should run once; got %d", summary.calls)
 	}
+	if strings.Join(progress, ",") != "1/1" {
+		t.Fatalf("progress = %v, want one report for the eligible issue", progress)
+	}
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/viper"
 	"github.com/steveyegge/beads/internal/debug"
 )
@@ -83,48 +84,9 @@ func Initialize() error {
 	// Set config type to yaml (we only load config.yaml, not config.json)
 	v.SetConfigType("yaml")
 
-	// Explicitly locate config.yaml and use SetConfigFile to avoid picking up config.json
-	// Precedence: project .beads/config.yaml > ~/.config/bd/config.yaml > ~/.beads/config.yaml
-	configFileSet := false
-
-	// 1. Walk up from CWD to find project .beads/config.yaml
-	//    This allows commands to work from subdirectories
-	cwd, err := os.Getwd()
-	if err == nil && !configFileSet {
-		// Walk up parent directories to find .beads/config.yaml
-		for dir := cwd; dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
-			beadsDir := filepath.Join(dir, ".beads")
-			configPath := filepath.Join(beadsDir, "config.yaml")
-			if _, err := os.Stat(configPath); err == nil {
-				// Found .beads/config.yaml - set it explicitly
-				v.SetConfigFile(configPath)
-				configFileSet = true
-				break
-			}
-		}
-	}
-
-	// 2. User config directory (~/.config/bd/config.yaml)
-	if !configFileSet {
-		if configDir, err := os.UserConfigDir(); err == nil {
-			configPath := filepath.Join(configDir, "bd", "config.yaml")
-			if _, err := os.Stat(configPath); err == nil {
-				v.SetConfigFile(configPath)
-				configFileSet = true
-			}
-		}
-	}
-
-	// 3. Home directory (~/.beads/config.yaml)
-	if !configFileSet {
-		if homeDir, err := os.UserHomeDir(); err == nil {
-			configPath := filepath.Join(homeDir, ".beads", "config.yaml")
-			if _, err := os.Stat(configPath); err == nil {
-				v.SetConfigFile(configPath)
-				configFileSet = true
-			}
-		}
-	}
+	// Layered config files: system, user, project, directory-local, profile.
+	// See layers.go for discovery and precedence.
+	activeProfile = os.Getenv(ProfileEnv)
 
 	// Automatic environment variable binding
 	// Environment variables take precedence over config file
@@ -136,6 +98,24 @@ func Initialize() error {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
 	v.AutomaticEnv()
 
+	// BEADS_<KEY> for every key in the schema (see overrides.go)
+	bindEnv()
+
+	setDefaults(v)
+
+	if err := loadLayers(); err != nil {
+		return err
+	}
+	if len(layers) == 0 {
+		// No config.yaml found - use defaults and environment variables
+		debug.Logf("Debug: no config.yaml found; using defaults and environment variables\n")
+	}
+
+	return nil
+}
+
+// setDefaults registers the default value of every tool-level setting.
+func setDefaults(v *viper.Viper) {
 	// Set defaults for all flags
 	v.SetDefault("json", false)
 	v.SetDefault("no-daemon", false)
@@ -144,19 +124,18 @@ func Initialize() error {
 	v.SetDefault("no-db", false)
 	v.SetDefault("db", "")
 	v.SetDefault("actor", "")
+	v.SetDefault("lang", "") // English; see internal/i18n for the catalogs
 	v.SetDefault("issue-prefix", "")
 	v.SetDefault("lock-timeout", "30s")
-
-	// Additional environment variables (not prefixed with BD_)
-	// These are bound explicitly for backward compatibility
-	_ = v.BindEnv("flush-debounce", "BEADS_FLUSH_DEBOUNCE")
-	_ = v.BindEnv("auto-start-daemon", "BEADS_AUTO_START_DAEMON")
-	_ = v.BindEnv("identity", "BEADS_IDENTITY")
-	_ = v.BindEnv("remote-sync-interval", "BEADS_REMOTE_SYNC_INTERVAL")
+	v.SetDefault("timeout", "0s")
+	v.SetDefault("no-progress", false)
+	v.SetDefault("accessible", false)
+	v.SetDefault("http.log", false) // Log every connector HTTP request to stderr
 
 	// Set defaults for additional settings
 	v.SetDefault("flush-debounce", "30s")
 	v.SetDefault("auto-start-daemon", true)
+	v.SetDefault("daemon.auto_upgrade", true) // Restart a daemon older than the CLI with the new binary
 	v.SetDefault("identity", "")
 	v.SetDefault("remote-sync-interval", "30s")
 
@@ -168,6 +147,7 @@ func Initialize() error {
 
 	// Sync configuration defaults (bd-4u8)
 	v.SetDefault("sync.require_confirmation_on_mass_delete", false)
+	v.SetDefault("sync.oplog", false) // field-level sync through .beads/ops.jsonl
 
 	// Sync mode configuration (hq-ew1mbr.3)
 	// See docs/CONFIG.md for detailed documentation
@@ -196,11 +176,110 @@ func Initialize() error {
 	v.SetDefault("validation.on-create", "none")
 	v.SetDefault("validation.on-sync", "none")
 
+	// Content rules checked on create, update and import (0 or empty = off).
+	// validation.required-fields.<type> lists fields each type must set.
+	v.SetDefault("validation.title-min-length", 0)
+	v.SetDefault("validation.title-max-length", 0)
+	v.SetDefault("validation.forbidden-words", []string{})
+	v.SetDefault("validation.max-open-p0", 0)
+	v.SetDefault("validation.description-max-size", "")
+
+	// Attachments under .beads/attachments (empty = no limit, no offloading)
+	v.SetDefault("attachments.max-size", "")
+	v.SetDefault("attachments.quota", "")
+	v.SetDefault("attachments.store", "")
+
 	// Hierarchy configuration defaults (GH#995)
 	// Maximum nesting depth for hierarchical IDs (e.g., bd-abc.1.2.3)
 	// Default matches types.MaxHierarchyDepth constant
 	v.SetDefault("hierarchy.max-depth", 3)
 
+	// Release freeze (bd freeze) and who may override it and issue locks
+	v.SetDefault("freeze.enabled", false)
+	v.SetDefault("freeze.reason", "")
+	v.SetDefault("lock.admins", []string{})
+
+	// Signed op log records and exports (bd verify); key and format fall
+	// back to git's commit signing settings
+	v.SetDefault("sign.ops", false)
+	v.SetDefault("sign.format", "")
+	v.SetDefault("sign.key", "")
+	v.SetDefault("sign.allowed-signers", "")
+
+	// Redaction for bd export --redact: built-in rules (emails, tokens, ips)
+	// and named custom regexes under redact.patterns
+	v.SetDefault("redact.rules", []string{"emails", "tokens"})
+	v.SetDefault("redact.patterns", map[string]string{})
+
+	// Retention policy (bd retention): ages such as 90d or 1y, applied by the
+	// daemon on the retention.schedule cron. Empty keeps data forever.
+	v.SetDefault("retention.closed-comments", "")
+	v.SetDefault("retention.events", "")
+	v.SetDefault("retention.interactions", "")
+	v.SetDefault("retention.schedule", "@daily")
+
+	// Sprint cadence, used for sprint boundaries in calendar exports
+	v.SetDefault("sprint.start", "")        // First day of any sprint (YYYY-MM-DD); empty = no sprints
+	v.SetDefault("sprint.length-days", 14) // Sprint length in days
+
+	// External ref liveness checks (bd ref check); 0 disables the daemon's
+	// periodic re-check
+	v.SetDefault("refs.check-interval", "0")
+
+	// Linked pull request statuses (bd pr); 0 disables the daemon's
+	// periodic refresh
+	v.SetDefault("pr.refresh-interval", "0")
+
+	// Condition gates (bd dep add --until-cmd/--until-url) the daemon
+	// evaluates; 0 disables
+	v.SetDefault("gates.check-interval", "5m")
+
+	// Obsidian vault kept up to date by the daemon (bd export obsidian);
+	// relative to the repository root, empty disables
+	v.SetDefault("obsidian.vault-dir", "")
+
+	// Address the daemon serves the activity feed on (bd feed); empty disables
+	v.SetDefault("feed.listen", "")
+
+	// Bearer token feed clients need to see internal issues; without one set
+	// every client does
+	v.SetDefault("feed.token", "")
+
+	// Address the daemon serves the HTTP API (GraphQL) on; empty disables
+	v.SetDefault("api.listen", "")
+
+	// Bearer token API clients need to see internal issues; without one set
+	// every client does
+	v.SetDefault("api.token", "")
+
+	// Single sign-on for the API through an OpenID Connect provider; empty
+	// issuer disables. Roles map groups to viewer or member, e.g.
+	// "eng=member,contractors=viewer"
+	v.SetDefault("api.oidc.issuer", "")
+	v.SetDefault("api.oidc.client-id", "")
+	v.SetDefault("api.oidc.client-secret", "")
+	v.SetDefault("api.oidc.redirect-url", "")
+	v.SetDefault("api.oidc.groups-claim", "groups")
+	v.SetDefault("api.oidc.roles", "")
+	v.SetDefault("api.oidc.default-role", "")
+	v.SetDefault("api.oidc.session-secret", "")
+
+	// How long the API access log (bd server audit) keeps requests; 0 keeps
+	// them forever. Per-client rate limits live under api.rate-limits
+	v.SetDefault("api.audit-retention", "720h")
+
+	// CHANGELOG.md the daemon keeps current (bd changelog update); relative
+	// to the repository root, empty disables
+	v.SetDefault("changelog.file", "")
+
+	// Status document the daemon keeps current (bd summary write); relative
+	// to the repository root, empty disables
+	v.SetDefault("summary.file", "")
+
+	// Longest an issue may stay open, by priority, before bd portfolio
+	// counts it as an SLA breach
+	v.SetDefault("portfolio.sla", "p0=1d,p1=7d")
+
 	// Git configuration defaults (GH#600)
 	v.SetDefault("git.author", "")         // Override commit author (e.g., "beads-bot <beads@example.com>")
 	v.SetDefault("git.no-gpg-sign", false) // Disable GPG signing for beads commits
@@ -212,19 +291,6 @@ func Initialize() error {
 	// External projects for cross-project dependency resolution (bd-h807)
 	// Maps project names to paths for resolving external: blocked_by references
 	v.SetDefault("external_projects", map[string]string{})
-
-	// Read config file if it was found
-	if configFileSet {
-		if err := v.ReadInConfig(); err != nil {
-			return fmt.Errorf("error reading config file: %w", err)
-		}
-		debug.Logf("Debug: loaded config from %s\n", v.ConfigFileUsed())
-	} else {
-		// No config.yaml found - use defaults and environment variables
-		debug.Logf("Debug: no config.yaml found; using defaults and environment variables\n")
-	}
-
-	return nil
 }
 
 // ResetForTesting clears the config state, allowing Initialize() to be called again.
@@ -408,6 +474,19 @@ func GetInt(key string) int {
 	return v.GetInt(key)
 }
 
+// GetSize retrieves a byte size configuration value such as 10MB. Unset or
+// unparseable values are 0.
+func GetSize(key string) int64 {
+	if v == nil {
+		return 0
+	}
+	n, err := humanize.ParseBytes(v.GetString(key))
+	if err != nil {
+		return 0
+	}
+	return int64(n)
+}
+
 // GetDuration retrieves a duration configuration value
 func GetDuration(key string) time.Duration {
 	if v == nil {
@@ -468,6 +547,109 @@ func GetStringMapString(key string) map[string]string {
 	return v.GetStringMapString(key)
 }
 
+// GetStringMapByPrefix returns the key.<name> values under key, whether
+// config.yaml nests them or spells them as dotted keys the way bd config set
+// writes them.
+func GetStringMapByPrefix(key string) map[string]string {
+	m := GetStringMapString(key)
+	if v == nil {
+		return m
+	}
+	prefix := key + "."
+	for _, k := range v.AllKeys() {
+		if name := strings.TrimPrefix(k, prefix); name != k && name != "" {
+			m[name] = v.GetString(k)
+		}
+	}
+	return m
+}
+
+// GetListMapByPrefix returns the key.<name> lists under key, nested or
+// dotted as with GetStringMapByPrefix.
+func GetListMapByPrefix(key string) map[string][]string {
+	m := make(map[string][]string)
+	if v == nil {
+		return m
+	}
+	prefix := key + "."
+	for _, k := range v.AllKeys() {
+		if name := strings.TrimPrefix(k, prefix); name != k && name != "" {
+			m[name] = v.GetStringSlice(k)
+		}
+	}
+	return m
+}
+
+// AutomationRule is one entry of automation.rules in config.yaml. Then
+// holds one action per element; a single string is one action.
+type AutomationRule struct {
+	Name string
+	When string
+	Then []string
+}
+
+// GetAutomationRules returns the rules configured under automation.rules.
+// Example config.yaml:
+//
+//	automation:
+//	  rules:
+//	    - name: release-tracking
+//	      when: status=closed and label=needs-release
+//	      then: add-label pending-release
+func GetAutomationRules() []AutomationRule {
+	if v == nil {
+		return nil
+	}
+	raw, _ := v.Get("automation.rules").([]interface{})
+	rules := make([]AutomationRule, 0, len(raw))
+	for i, item := range raw {
+		m, _ := item.(map[string]interface{})
+		rule := AutomationRule{Name: fmt.Sprint(m["name"]), When: fmt.Sprint(m["when"])}
+		if m["name"] == nil {
+			rule.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if m["when"] == nil {
+			rule.When = ""
+		}
+		switch then := m["then"].(type) {
+		case string:
+			rule.Then = []string{then}
+		case []interface{}:
+			for _, action := range then {
+				rule.Then = append(rule.Then, fmt.Sprint(action))
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// GetAutoLabelRules returns the keyword lists configured under labels.auto,
+// by label. A single string is one keyword. Example config.yaml:
+//
+//	labels:
+//	  auto:
+//	    security: [cve, xss, "sql injection"]
+//	    flaky: /flak(y|iness)/
+func GetAutoLabelRules() map[string][]string {
+	if v == nil {
+		return nil
+	}
+	raw, _ := v.Get("labels.auto").(map[string]interface{})
+	rules := make(map[string][]string, len(raw))
+	for label, value := range raw {
+		switch value := value.(type) {
+		case string:
+			rules[label] = []string{value}
+		case []interface{}:
+			for _, kw := range value {
+				rules[label] = append(rules[label], fmt.Sprint(kw))
+			}
+		}
+	}
+	return rules
+}
+
 // GetDirectoryLabels returns labels for the current working directory based on config.
 // It checks directory.labels config for matching patterns.
 // Returns nil if no labels are configured for the current directory.
@@ -743,3 +925,25 @@ func NeedsJSONL() bool {
 	mode := GetSyncMode()
 	return mode == SyncModeGitPortable || mode == SyncModeRealtime || mode == SyncModeBeltAndSuspenders
 }
+
+// GetCapacity returns the work-in-progress limit for an assignee.
+// Looks up capacity.<assignee> first, then falls back to capacity.default.
+// Returns 0 when no limit is configured (unlimited capacity).
+//
+// Example config.yaml:
+//
+//	capacity:
+//	  default: 3
+//	  alice: 2
+func GetCapacity(assignee string) int {
+	if v == nil {
+		return 0
+	}
+	if assignee != "" {
+		key := "capacity." + strings.ToLower(assignee)
+		if v.IsSet(key) {
+			return v.GetInt(key)
+		}
+	}
+	return v.GetInt("capacity.default")
+}
@@ -1412,3 +1412,41 @@ func TestGetSovereigntyInvalid(t *testing.T) {
 		t.Errorf("GetSovereignty() with invalid tier = %q, want empty (fallback)", got)
 	}
 }
+
+func TestGetCapacity(t *testing.T) {
+	restore := envSnapshot(t)
+	defer restore()
+
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0750); err != nil {
+		t.Fatalf("failed to create .beads directory: %v", err)
+	}
+	configContent := `
+capacity:
+  default: 3
+  alice: 2
+capacity.bob: 5
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "config.yaml"), []byte(configContent), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Chdir(tmpDir)
+
+	if err := Initialize(); err != nil {
+		t.Fatalf("Initialize() returned error: %v", err)
+	}
+
+	tests := map[string]int{
+		"alice": 2,
+		"Alice": 2, // viper keys are case-insensitive
+		"bob":   5, // flat dotted key as written by bd config set
+		"carol": 3, // falls back to capacity.default
+		"":      3,
+	}
+	for assignee, want := range tests {
+		if got := GetCapacity(assignee); got != want {
+			t.Errorf("GetCapacity(%q) = %d, want %d", assignee, got, want)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"github.com/steveyegge/beads/internal/debug"
+)
+
+// Config files are layered, lowest precedence first:
+//
+//	system     /etc/bd/config.yaml (%ProgramData%\bd\config.yaml on Windows)
+//	user       ~/.beads/config.yaml (legacy), then ~/.config/bd/config.yaml
+//	project    .beads/config.yaml, found by walking up from the working directory
+//	directory  .bd.yaml in each directory from the project root down to the
+//	           working directory, the innermost winning
+//	profile    the profiles.<name> section of each file above, in the same
+//	           order, when a profile is selected
+//
+// Environment variables and command-line flags override every file.
+
+// Layer names, as reported by Explain.
+const (
+	LayerDefault   = "default"
+	LayerSystem    = "system"
+	LayerUser      = "user"
+	LayerProject   = "project"
+	LayerDirectory = "directory"
+	LayerProfile   = "profile"
+	LayerEnv       = "env"
+	LayerFlag      = "flag"
+)
+
+// DirConfigName is the directory-local override file.
+const DirConfigName = ".bd.yaml"
+
+// ProfileEnv selects a named profile, like the --config-profile flag.
+const ProfileEnv = "BD_CONFIG_PROFILE"
+
+// profilesKey is the section of a config file holding named profiles.
+const profilesKey = "profiles"
+
+// systemConfigPath is a variable so tests can point it elsewhere.
+var systemConfigPath = defaultSystemConfigPath()
+
+func defaultSystemConfigPath() string {
+	if runtime.GOOS == "windows" {
+		base := os.Getenv("ProgramData")
+		if base == "" {
+			base = `C:\ProgramData`
+		}
+		return filepath.Join(base, "bd", "config.yaml")
+	}
+	return "/etc/bd/config.yaml"
+}
+
+// fileLayer is one config file that was found and read.
+type fileLayer struct {
+	name   string // LayerSystem, LayerUser, LayerProject or LayerDirectory
+	path   string
+	values map[string]interface{}
+}
+
+var (
+	layers        []fileLayer
+	activeProfile string
+	deprecations  []string // Deprecated keys found in config files
+)
+
+// loadLayers discovers and reads every config file, then merges them into
+// viper in precedence order.
+func loadLayers() error {
+	found, primary := discoverLayers()
+	layers = layers[:0]
+	for _, l := range found {
+		values, err := readConfigFile(l.path)
+		if err != nil {
+			return fmt.Errorf("error reading config file: %w", err)
+		}
+		l.values = values
+		layers = append(layers, l)
+		debug.Logf("Debug: loaded %s config from %s\n", l.name, l.path)
+	}
+	if primary != "" {
+		// Relative paths in the config are resolved against this file
+		v.SetConfigFile(primary)
+	}
+	return applyLayers()
+}
+
+// discoverLayers returns the config files that exist, lowest precedence
+// first, and the one ConfigFileUsed reports: the project file, else the
+// highest-precedence user file.
+func discoverLayers() (found []fileLayer, primary string) {
+	seen := make(map[string]bool)
+	add := func(name, path string) bool {
+		if path == "" || seen[path] {
+			return false
+		}
+		if fi, err := os.Stat(path); err != nil || fi.IsDir() {
+			return false
+		}
+		seen[path] = true
+		found = append(found, fileLayer{name: name, path: path})
+		return true
+	}
+
+	cwd, _ := os.Getwd()
+	projectRoot, projectFile := "", ""
+	if cwd != "" {
+		for dir := cwd; dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+			configPath := filepath.Join(dir, ".beads", "config.yaml")
+			if _, err := os.Stat(configPath); err == nil {
+				projectRoot, projectFile = dir, configPath
+				break
+			}
+		}
+	}
+	// The project file may also be a user file (a project in $HOME); it
+	// counts once, as the project.
+	if projectFile != "" {
+		seen[projectFile] = true
+	}
+
+	add(LayerSystem, systemConfigPath)
+	var userFiles []string
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		userFiles = append(userFiles, filepath.Join(homeDir, ".beads", "config.yaml"))
+	}
+	if configDir, err := os.UserConfigDir(); err == nil {
+		userFiles = append(userFiles, filepath.Join(configDir, "bd", "config.yaml"))
+	}
+	for _, path := range userFiles {
+		if add(LayerUser, path) {
+			primary = path
+		}
+	}
+	if projectFile != "" {
+		delete(seen, projectFile)
+		add(LayerProject, projectFile)
+		primary = projectFile
+	}
+
+	// Directory-local overrides, outermost first
+	if cwd != "" {
+		stop := projectRoot
+		if stop == "" {
+			stop = cwd
+		}
+		var dirs []string
+		for dir := cwd; ; dir = filepath.Dir(dir) {
+			dirs = append(dirs, dir)
+			if dir == stop || dir == filepath.Dir(dir) {
+				break
+			}
+		}
+		for i := len(dirs) - 1; i >= 0; i-- {
+			add(LayerDirectory, filepath.Join(dirs[i], DirConfigName))
+		}
+	}
+	return found, primary
+}
+
+func readConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - config file locations are fixed
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return lowerKeys(values), nil
+}
+
+// applyLayers rebuilds viper's config from the file layers and the active
+// profile.
+func applyLayers() error {
+	if err := v.ReadConfig(strings.NewReader("")); err != nil {
+		return err
+	}
+	deprecations = deprecations[:0]
+	merge := func(values map[string]interface{}, path string) error {
+		// Merge copies: viper keeps references to the maps it is given
+		values = lowerKeys(values)
+		for _, r := range migrateRenamed(values) {
+			deprecations = append(deprecations, fmt.Sprintf("%s in %s is deprecated; rename it to %s", r.Old, path, r.New))
+		}
+		return v.MergeConfigMap(values)
+	}
+	for _, l := range layers {
+		if err := merge(l.values, l.path); err != nil {
+			return fmt.Errorf("merging %s: %w", l.path, err)
+		}
+	}
+	if activeProfile == "" {
+		return nil
+	}
+	for _, l := range layers {
+		if section := profileSection(l.values, activeProfile); section != nil {
+			if err := merge(section, l.path); err != nil {
+				return fmt.Errorf("merging profile %q from %s: %w", activeProfile, l.path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Reload re-reads every config file, keeping the active profile.
+func Reload() error {
+	if v == nil {
+		return nil
+	}
+	return loadLayers()
+}
+
+// UseProfile layers the named profile over the config files. An empty name
+// clears the profile. Naming a profile no config file defines is an error,
+// so a typo never silently runs with the wrong settings.
+func UseProfile(name string) error {
+	if v == nil {
+		return fmt.Errorf("config not initialized")
+	}
+	if name != "" && !hasProfile(name) {
+		available := Profiles()
+		if len(available) == 0 {
+			return fmt.Errorf("unknown config profile %q (no config file defines a %s section)", name, profilesKey)
+		}
+		return fmt.Errorf("unknown config profile %q (available: %s)", name, strings.Join(available, ", "))
+	}
+	activeProfile = name
+	return applyLayers()
+}
+
+// DeprecationWarnings describes each deprecated key set in a config file.
+// Its value still applies, under the new key.
+func DeprecationWarnings() []string {
+	return deprecations
+}
+
+// ActiveProfile returns the selected profile, or "" if none.
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// Profiles returns the names of every profile defined in any config file.
+func Profiles() []string {
+	names := make(map[string]bool)
+	for _, l := range layers {
+		if profiles, ok := l.values[profilesKey].(map[string]interface{}); ok {
+			for name := range profiles {
+				names[name] = true
+			}
+		}
+	}
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func hasProfile(name string) bool {
+	for _, l := range layers {
+		if profileSection(l.values, name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func profileSection(values map[string]interface{}, name string) map[string]interface{} {
+	profiles, ok := values[profilesKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	section, _ := profiles[strings.ToLower(name)].(map[string]interface{})
+	return section
+}
+
+// Origin is one source that sets a config key.
+type Origin struct {
+	Layer  string      `json:"layer"`            // One of the Layer* constants
+	Source string      `json:"source,omitempty"` // File path or environment variable
+	Value  interface{} `json:"value"`
+}
+
+// Explain returns every source that sets key, highest precedence first, so
+// the first entry is the effective value (unless a flag overrides it). The
+// default, if any, is always last.
+func Explain(key string) []Origin {
+	if v == nil {
+		return nil
+	}
+	key = strings.ToLower(key)
+	var origins []Origin
+
+	flagMu.RLock()
+	value, ok := flagValues[key]
+	flagMu.RUnlock()
+	if ok {
+		origins = append(origins, Origin{Layer: LayerFlag, Source: "--" + FlagName(key), Value: value})
+	}
+	envNames := EnvNames(key)
+	if _, known := LookupKey(key); !known {
+		envNames = envNames[:1] // Only viper's automatic BD_ binding applies
+	} else if !Overridable(key) {
+		envNames = nil
+	}
+	for _, env := range envNames {
+		if value := os.Getenv(env); value != "" {
+			origins = append(origins, Origin{Layer: LayerEnv, Source: env, Value: value})
+		}
+	}
+
+	if activeProfile != "" {
+		for i := len(layers) - 1; i >= 0; i-- {
+			if value, ok := lookupKey(profileSection(layers[i].values, activeProfile), key); ok {
+				origins = append(origins, Origin{
+					Layer:  LayerProfile,
+					Source: fmt.Sprintf("%s (%s)", layers[i].path, activeProfile),
+					Value:  value,
+				})
+			}
+		}
+	}
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		if value, ok := lookupKey(layers[i].values, key); ok {
+			origins = append(origins, Origin{Layer: layers[i].name, Source: layers[i].path, Value: value})
+		}
+	}
+
+	defaults := viper.New()
+	setDefaults(defaults)
+	if defaults.IsSet(key) {
+		origins = append(origins, Origin{Layer: LayerDefault, Value: defaults.Get(key)})
+	}
+	return origins
+}
+
+// lookupKey finds a dotted key in nested config maps.
+func lookupKey(values map[string]interface{}, key string) (interface{}, bool) {
+	if values == nil {
+		return nil, false
+	}
+	if value, ok := values[key]; ok {
+		return value, true
+	}
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) < 2 {
+		return nil, false
+	}
+	child, ok := values[parts[0]].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookupKey(child, parts[1])
+}
+
+// lowerKeys lower-cases map keys recursively, matching viper's
+// case-insensitive lookups.
+func lowerKeys(values map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for k, value := range values {
+		if child, ok := value.(map[string]interface{}); ok {
+			value = lowerKeys(child)
+		}
+		out[strings.ToLower(k)] = value
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// layeredTree builds a system, user, project and two directory-local config
+// files and changes into the innermost directory.
+func layeredTree(t *testing.T) (root, sub string) {
+	t.Helper()
+	restore := envSnapshot(t)
+	t.Cleanup(restore)
+
+	base := t.TempDir()
+	home := filepath.Join(base, "home")
+	root = filepath.Join(base, "repo")
+	sub = filepath.Join(root, "services", "api")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	origSystem := systemConfigPath
+	systemConfigPath = filepath.Join(base, "etc", "bd", "config.yaml")
+	t.Cleanup(func() { systemConfigPath = origSystem })
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(systemConfigPath, "actor: system\nlock-timeout: 5s\nsprint.length-days: 7\n")
+	write(filepath.Join(home, ".config", "bd", "config.yaml"),
+		"actor: user\nno-progress: true\nprofiles:\n  work:\n    actor: user-work\n    http:\n      log: true\n")
+	write(filepath.Join(root, ".beads", "config.yaml"), "actor: project\nissue-prefix: proj\n")
+	write(filepath.Join(root, "services", DirConfigName), "issue-prefix: svc\nlock-timeout: 10s\n")
+	write(filepath.Join(sub, DirConfigName), "issue-prefix: api\nprofiles:\n  work:\n    issue-prefix: api-work\n")
+
+	t.Chdir(sub)
+	if err := Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	return root, sub
+}
+
+func TestLayerPrecedence(t *testing.T) {
+	root, _ := layeredTree(t)
+
+	for key, want := range map[string]string{
+		"actor":              "project", // project over user over system
+		"no-progress":        "true",    // only the user file sets it
+		"sprint.length-days": "7",       // only the system file sets it
+		"lock-timeout":       "10s",     // directory over system
+		"issue-prefix":       "api",     // innermost directory wins
+		"flush-debounce":     "30s",     // default
+	} {
+		if got := GetString(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if got, want := ConfigFileUsed(), filepath.Join(root, ".beads", "config.yaml"); got != want {
+		t.Errorf("ConfigFileUsed = %q, want the project file %q", got, want)
+	}
+
+	t.Setenv("BD_ISSUE_PREFIX", "env")
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetString("issue-prefix"); got != "env" {
+		t.Errorf("issue-prefix = %q, want the environment to override every file", got)
+	}
+}
+
+func TestUseProfile(t *testing.T) {
+	layeredTree(t)
+
+	if err := UseProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetString("actor"); got != "user-work" {
+		t.Errorf("actor = %q, want the profile to override the project file", got)
+	}
+	if got := GetString("issue-prefix"); got != "api-work" {
+		t.Errorf("issue-prefix = %q, want the directory's profile section", got)
+	}
+	if !GetBool("http.log") {
+		t.Error("nested profile value not applied")
+	}
+
+	if err := UseProfile(""); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetString("actor"); got != "project" {
+		t.Errorf("actor = %q after clearing the profile", got)
+	}
+
+	err := UseProfile("home")
+	if err == nil || !strings.Contains(err.Error(), "available: work") {
+		t.Errorf("UseProfile(unknown) = %v, want an error listing the profiles", err)
+	}
+
+	t.Setenv(ProfileEnv, "work")
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if ActiveProfile() != "work" || GetString("actor") != "user-work" {
+		t.Errorf("%s not applied: profile %q, actor %q", ProfileEnv, ActiveProfile(), GetString("actor"))
+	}
+}
+
+func TestExplain(t *testing.T) {
+	root, sub := layeredTree(t)
+	if err := UseProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BD_LOCK_TIMEOUT", "1m")
+
+	got := Explain("issue-prefix")
+	want := []Origin{
+		{Layer: LayerProfile, Source: filepath.Join(sub, DirConfigName) + " (work)", Value: "api-work"},
+		{Layer: LayerDirectory, Source: filepath.Join(sub, DirConfigName), Value: "api"},
+		{Layer: LayerDirectory, Source: filepath.Join(root, "services", DirConfigName), Value: "svc"},
+		{Layer: LayerProject, Source: filepath.Join(root, ".beads", "config.yaml"), Value: "proj"},
+		{Layer: LayerDefault, Value: ""},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Explain(issue-prefix) = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("origin %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	lock := Explain("lock-timeout")
+	if len(lock) == 0 || lock[0].Layer != LayerEnv || lock[0].Source != "BD_LOCK_TIMEOUT" {
+		t.Errorf("Explain(lock-timeout) = %+v, want the environment first", lock)
+	}
+	if layers := Explain("http.log"); len(layers) != 2 || layers[0].Layer != LayerProfile || layers[1].Layer != LayerDefault {
+		t.Errorf("Explain(http.log) = %+v", layers)
+	}
+	if unset := Explain("no.such.key"); len(unset) != 0 {
+		t.Errorf("Explain(unset key) = %+v", unset)
+	}
+}
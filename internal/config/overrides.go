@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// Every config key in the schema can be overridden without touching a
+// file, highest precedence first:
+//
+//	--<key> <value>   a global flag named after the key (--jira.url,
+//	                  --sync.mode), or the existing flag for startup
+//	                  settings such as --actor and --lock-timeout
+//	BD_<KEY>          e.g. BD_JIRA_URL, BD_SYNC_MODE
+//	BEADS_<KEY>       e.g. BEADS_JIRA_URL
+//
+// The key is upper-cased with dots and dashes turned into underscores.
+// Overrides apply to config.yaml settings and to settings stored in the
+// project database (bd config set) alike.
+
+var envReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// EnvNames returns the environment variables that override key, highest
+// precedence first.
+func EnvNames(key string) []string {
+	suffix := strings.ToUpper(envReplacer.Replace(strings.ToLower(key)))
+	return []string{"BD_" + suffix, "BEADS_" + suffix}
+}
+
+// FlagName returns the global flag generated for key.
+func FlagName(key string) string {
+	return key
+}
+
+var (
+	flagMu     sync.RWMutex
+	flagValues = make(map[string]string)
+)
+
+// SetFlagOverride records a value given on the command line for key. It
+// wins over every other source until the process exits.
+func SetFlagOverride(key, value string) {
+	key = strings.ToLower(key)
+	flagMu.Lock()
+	flagValues[key] = value
+	flagMu.Unlock()
+	if v != nil {
+		v.Set(key, value)
+	}
+}
+
+// Overridable reports whether key can be set from a flag or the
+// environment. Keys bd maintains itself, and keys that identify the
+// project's data, cannot.
+func Overridable(key string) bool {
+	spec, ok := LookupKey(key)
+	return ok && !spec.Internal && !spec.NoOverride && spec.Type != TypeMap
+}
+
+// Override returns the value a flag or environment variable gives key,
+// and the flag or variable it came from. Storage backends consult it
+// before the project database, so every bd config set key can be
+// overridden the same way as config.yaml settings.
+func Override(key string) (value, source string, ok bool) {
+	if !Overridable(key) {
+		return "", "", false
+	}
+	flagMu.RLock()
+	value, ok = flagValues[strings.ToLower(key)]
+	flagMu.RUnlock()
+	if ok {
+		return value, "--" + FlagName(key), true
+	}
+	for _, env := range EnvNames(key) {
+		if value := os.Getenv(env); value != "" {
+			return value, env, true
+		}
+	}
+	return "", "", false
+}
+
+// bindEnv makes viper read BEADS_<KEY> for every schema key, after the
+// BD_<KEY> variables AutomaticEnv already covers.
+func bindEnv() {
+	for _, spec := range Schema {
+		if strings.Contains(spec.Key, "*") || !Overridable(spec.Key) {
+			continue
+		}
+		_ = v.BindEnv(spec.Key, EnvNames(spec.Key)[1])
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvNames(t *testing.T) {
+	for key, want := range map[string][]string{
+		"jira.url":       {"BD_JIRA_URL", "BEADS_JIRA_URL"},
+		"flush-debounce": {"BD_FLUSH_DEBOUNCE", "BEADS_FLUSH_DEBOUNCE"},
+		"Sync.Mode":      {"BD_SYNC_MODE", "BEADS_SYNC_MODE"},
+	} {
+		if got := EnvNames(key); !reflect.DeepEqual(got, want) {
+			t.Errorf("EnvNames(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestOverride(t *testing.T) {
+	restore := envSnapshot(t)
+	defer restore()
+	defer func() {
+		flagMu.Lock()
+		flagValues = make(map[string]string)
+		flagMu.Unlock()
+	}()
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, ok := Override("jira.url"); ok {
+		t.Fatal("Override reported a value with nothing set")
+	}
+
+	t.Setenv("BEADS_JIRA_URL", "https://beads.example.com")
+	if value, source, _ := Override("jira.url"); value != "https://beads.example.com" || source != "BEADS_JIRA_URL" {
+		t.Errorf("Override = %q from %q, want BEADS_JIRA_URL", value, source)
+	}
+	t.Setenv("BD_JIRA_URL", "https://bd.example.com")
+	if value, source, _ := Override("jira.url"); value != "https://bd.example.com" || source != "BD_JIRA_URL" {
+		t.Errorf("Override = %q from %q, want BD_ over BEADS_", value, source)
+	}
+	SetFlagOverride("jira.url", "https://flag.example.com")
+	if value, source, _ := Override("jira.url"); value != "https://flag.example.com" || source != "--jira.url" {
+		t.Errorf("Override = %q from %q, want the flag over the environment", value, source)
+	}
+	if got := GetString("jira.url"); got != "https://flag.example.com" {
+		t.Errorf("GetString(jira.url) = %q, want the flag value", got)
+	}
+
+	// Keys identifying the project's data and internal keys never are
+	t.Setenv("BEADS_ISSUE_PREFIX", "other")
+	t.Setenv("BEADS_SYNC_REMOTE_SHA", "abc")
+	for _, key := range []string{"issue_prefix", "sync.remote_sha", "no.such.key"} {
+		if _, _, ok := Override(key); ok {
+			t.Errorf("Override(%q) applied", key)
+		}
+	}
+}
+
+func TestBeadsEnvBinding(t *testing.T) {
+	restore := envSnapshot(t)
+	defer restore()
+	t.Setenv("BEADS_FLUSH_DEBOUNCE", "5s")
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetString("flush-debounce"); got != "5s" {
+		t.Errorf("flush-debounce = %q, want BEADS_FLUSH_DEBOUNCE", got)
+	}
+
+	t.Setenv("BD_FLUSH_DEBOUNCE", "7s")
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetString("flush-debounce"); got != "7s" {
+		t.Errorf("flush-debounce = %q, want BD_ over BEADS_", got)
+	}
+}
@@ -163,11 +163,9 @@ func SetReposInYAML(configPath string, repos *ReposConfig) error {
 	}
 
 	// Reload viper config so changes take effect immediately
-	if v != nil {
-		if err := v.ReadInConfig(); err != nil {
-			// Not fatal - config is on disk, will be picked up on next command
-			_ = err
-		}
+	if err := Reload(); err != nil {
+		// Not fatal - config is on disk, will be picked up on next command
+		_ = err
 	}
 
 	return nil
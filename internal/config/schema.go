@@ -0,0 +1,602 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// ValueType is the type of a config value, checked by ValidateValue.
+type ValueType string
+
+const (
+	TypeString   ValueType = "string"
+	TypeBool     ValueType = "bool"
+	TypeInt      ValueType = "int" // Non-negative unless KeySpec.Min says otherwise
+	TypeFloat    ValueType = "float"
+	TypeDuration ValueType = "duration" // Go duration, e.g. 30s, 5m
+	TypeURL      ValueType = "url"
+	TypeEnum     ValueType = "enum"
+	TypeDate     ValueType = "date" // YYYY-MM-DD
+	TypeList     ValueType = "list" // Comma-separated, or a YAML list
+	TypeRate     ValueType = "rate" // Requests per second, minute or hour, e.g. 10/s
+	TypeSize     ValueType = "size" // Bytes, e.g. 512KB, 10MB or 1048576
+	TypeMap      ValueType = "map"  // A YAML section, edited in config.yaml
+)
+
+// KeySpec describes one config key. Key may contain * wildcards (matching
+// one or more dotted segments) for keys named after user data, such as
+// jira.status_map.<status>.
+type KeySpec struct {
+	Key         string
+	Type        ValueType
+	Values      []string // Allowed values of an enum; "" allows unsetting
+	Min         int      // Smallest allowed int
+	Schemes     []string // Allowed URL schemes (default http, https)
+	Description string
+	Internal    bool // Written by bd itself (sync cursors etc.), not by users
+	NoOverride  bool // Identifies the project's data; never set from a flag or env var
+}
+
+// Rename records a config key that was renamed. The old key keeps working
+// with a deprecation warning until it is migrated.
+type Rename struct {
+	Old, New string
+	// AnyTrue merges several boolean keys into New: it becomes true if any
+	// of them was.
+	AnyTrue bool
+}
+
+// Renames lists every deprecated key and its replacement.
+var Renames = []Rename{
+	{Old: "daemon.auto_pull", New: "daemon.auto-pull"},
+	{Old: "daemon.auto_commit", New: "daemon.auto-sync", AnyTrue: true},
+	{Old: "daemon.auto_push", New: "daemon.auto-sync", AnyTrue: true},
+}
+
+var (
+	routingModes   = []string{"auto", "maintainer", "contributor"}
+	validationMode = []string{"none", "warn", "error"}
+	orphanModes    = []string{"strict", "resurrect", "skip", "allow"}
+	exportPolicies = []string{"strict", "best-effort", "partial", "required-core"}
+)
+
+// Schema lists every config key bd reads, from config.yaml and from the
+// project database (bd config set).
+var Schema = []KeySpec{
+	// Global flags and startup settings
+	{Key: "json", Type: TypeBool, Description: "Output in JSON format"},
+	{Key: "no-daemon", Type: TypeBool, Description: "Force direct mode, bypass the daemon"},
+	{Key: "no-auto-flush", Type: TypeBool, Description: "Disable automatic JSONL export"},
+	{Key: "no-auto-import", Type: TypeBool, Description: "Disable automatic JSONL import"},
+	{Key: "no-db", Type: TypeBool, Description: "Use JSONL only, no database"},
+	{Key: "no-progress", Type: TypeBool, Description: "Don't draw progress bars"},
+	{Key: "accessible", Type: TypeBool, Description: "Screen-reader-friendly output: no color, symbols, tables or trees"},
+	{Key: "readonly", Type: TypeBool, Description: "Block write operations"},
+	{Key: "db", Type: TypeString, Description: "Database path"},
+	{Key: "actor", Type: TypeString, Description: "Actor name for the audit trail"},
+	{Key: "identity", Type: TypeString, Description: "Identity for messages and assignment"},
+	{Key: "lang", Type: TypeString, Description: "Language of human-readable output (en, de); JSON is never translated"},
+	{Key: "issue-prefix", Type: TypeString, Description: "Issue ID prefix used by bd init and no-db mode"},
+	{Key: "lock-timeout", Type: TypeDuration, Description: "SQLite busy timeout"},
+	{Key: "timeout", Type: TypeDuration, Description: "Cancel commands after this long (0 = no limit)"},
+	{Key: "flush-debounce", Type: TypeDuration, Description: "Delay before auto-export after a change"},
+	{Key: "auto-start-daemon", Type: TypeBool, Description: "Start the daemon automatically"},
+	{Key: "remote-sync-interval", Type: TypeDuration, Description: "How often the daemon pulls from the remote"},
+	{Key: "no-push", Type: TypeBool, Description: "Don't push after sync"},
+	{Key: "no-git-ops", Type: TypeBool, Description: "Skip git operations in the session close protocol"},
+	{Key: "create.require-description", Type: TypeBool, Description: "Require a description on bd create"},
+	{Key: "hierarchy.max-depth", Type: TypeInt, Min: 1, Description: "Maximum nesting depth of hierarchical IDs"},
+
+	// Daemon
+	{Key: "daemon.auto_upgrade", Type: TypeBool, Description: "Restart an older daemon with the new binary"},
+	{Key: "daemon.auto-sync", Type: TypeBool, Description: "Auto-commit, push and pull from the daemon"},
+	{Key: "daemon.auto-pull", Type: TypeBool, Description: "Auto-pull from the daemon"},
+
+	// Sync
+	{Key: "sync-branch", Type: TypeString, Description: "Branch beads commits go to"},
+	{Key: "sync.branch", Type: TypeString, Description: "Branch beads commits go to"},
+	{Key: "sync.mode", Type: TypeEnum, Values: []string{SyncModeGitPortable, SyncModeRealtime, SyncModeDoltNative, SyncModeBeltAndSuspenders}, Description: "How beads syncs with git and remotes"},
+	{Key: "sync.export_on", Type: TypeEnum, Values: []string{SyncTriggerPush, SyncTriggerChange}, Description: "When to export JSONL"},
+	{Key: "sync.import_on", Type: TypeEnum, Values: []string{SyncTriggerPull, SyncTriggerChange}, Description: "When to import JSONL"},
+	{Key: "sync.oplog", Type: TypeBool, Description: "Field-level sync through .beads/ops.jsonl"},
+	{Key: "sync.require_confirmation_on_mass_delete", Type: TypeBool, Description: "Ask before a sync deletes many issues"},
+	{Key: "sync.remote", Type: TypeString, Description: "Git remote to push to"},
+	{Key: "sync.local-only", Type: TypeBool, Description: "Never push or pull"},
+	{Key: "sync.nodb", Type: TypeBool, Description: "Sync in no-db mode"},
+	{Key: "sync.remote_sha", Type: TypeString, Internal: true},
+	{Key: "*.sync_checkpoint", Type: TypeString, Internal: true},
+	{Key: "conflict.strategy", Type: TypeEnum, Values: []string{ConflictStrategyNewest, ConflictStrategyOurs, ConflictStrategyTheirs, ConflictStrategyManual}, Description: "How sync conflicts are resolved"},
+	{Key: "federation.remote", Type: TypeURL, Schemes: []string{"dolthub", "gs", "s3", "file", "http", "https"}, Description: "Dolt remote for federation"},
+	{Key: "federation.sovereignty", Type: TypeEnum, Values: []string{"", SovereigntyT1, SovereigntyT2, SovereigntyT3, SovereigntyT4}, Description: "Data sovereignty tier"},
+	{Key: "team.enabled", Type: TypeBool, Description: "Team workflow enabled by bd init --team"},
+	{Key: "team.sync_branch", Type: TypeString, Description: "Team sync branch"},
+
+	// Git
+	{Key: "git.author", Type: TypeString, Description: "Author of beads commits"},
+	{Key: "git.no-gpg-sign", Type: TypeBool, Description: "Don't sign beads commits"},
+
+	// Routing
+	{Key: "routing.mode", Type: TypeEnum, Values: routingModes, Description: "Where new issues are created"},
+	{Key: "routing.default", Type: TypeString, Description: "Default repository for new issues"},
+	{Key: "routing.maintainer", Type: TypeString, Description: "Repository for maintainers' issues"},
+	{Key: "routing.contributor", Type: TypeString, Description: "Repository for contributors' issues"},
+	{Key: "contributor.auto_route", Type: TypeBool, Description: "Route contributors' issues to the planning repo"},
+	{Key: "contributor.planning_repo", Type: TypeString, Description: "Contributors' planning repository"},
+	{Key: "repos.primary", Type: TypeString, Description: "Primary repository for multi-repo hydration"},
+	{Key: "repos.additional", Type: TypeList, Description: "Additional repositories to hydrate from"},
+	{Key: "external_projects.*", Type: TypeString, Description: "Path of an external project"},
+	{Key: "directory.labels", Type: TypeMap, Description: "Labels applied per directory"},
+	{Key: "directory.labels.*", Type: TypeString, Description: "Label for a directory"},
+
+	// Validation and workflow
+	{Key: "validation.on-create", Type: TypeEnum, Values: validationMode, Description: "Template validation on bd create"},
+	{Key: "validation.on-sync", Type: TypeEnum, Values: validationMode, Description: "Template validation on sync"},
+	{Key: "validation.title-min-length", Type: TypeInt, Description: "Shortest allowed issue title in characters (0 = no minimum)"},
+	{Key: "validation.title-max-length", Type: TypeInt, Description: "Longest allowed issue title in characters (0 = no maximum)"},
+	{Key: "validation.required-fields.*", Type: TypeList, Description: "Fields an issue of this type must set"},
+	{Key: "validation.forbidden-words", Type: TypeList, Description: "Words refused in titles and descriptions"},
+	{Key: "validation.max-open-p0", Type: TypeInt, Description: "Most issues open at P0 (0 = unlimited)"},
+	{Key: "validation.description-max-size", Type: TypeSize, Description: "Largest allowed issue description, e.g. 64KB"},
+	{Key: "attachments.max-size", Type: TypeSize, Description: "Largest attachment kept in the repository; larger ones are offloaded or refused"},
+	{Key: "attachments.quota", Type: TypeSize, Description: "Total size of attachments kept in the repository"},
+	{Key: "attachments.store", Type: TypeURL, Schemes: []string{"s3", "gs", "file"}, Description: "External store for offloaded attachments (s3://bucket/prefix, gs://bucket/prefix)"},
+	{Key: "attachments.s3-region", Type: TypeString, Description: "AWS region of the attachments.store S3 bucket"},
+	{Key: "attachments.s3-endpoint", Type: TypeURL, Description: "S3-compatible endpoint for attachments.store (e.g. MinIO)"},
+	{Key: "freeze.enabled", Type: TypeBool, Description: "Refuse changes from everyone but lock.admins (bd freeze)"},
+	{Key: "freeze.reason", Type: TypeString, Description: "Why the project is frozen"},
+	{Key: "lock.admins", Type: TypeList, Description: "Actors who may lock, unlock and change locked issues"},
+	{Key: "sign.ops", Type: TypeBool, Description: "Sign op log records (bd verify checks them)"},
+	{Key: "sign.format", Type: TypeEnum, Values: []string{"", "ssh", "gpg"}, Description: "Signing key type (default: git's gpg.format)"},
+	{Key: "sign.key", Type: TypeString, Description: "SSH key file or GPG key ID (default: git's user.signingkey)"},
+	{Key: "sign.allowed-signers", Type: TypeString, Description: "ssh-keygen allowed signers file bd verify trusts"},
+	{Key: "redact.rules", Type: TypeList, Description: "Built-in rules bd export --redact applies (emails, tokens, ips)"},
+	{Key: "redact.patterns", Type: TypeMap, Description: "Custom regexes bd export --redact replaces, by name"},
+	{Key: "redact.patterns.*", Type: TypeString, Description: "Regex replaced with [redacted:<name>]"},
+	{Key: "retention.closed-comments", Type: TypeString, Description: "Purge comments of issues closed longer ago than this (e.g. 1y)"},
+	{Key: "retention.events", Type: TypeString, Description: "Purge audit trail events older than this (e.g. 2y)"},
+	{Key: "retention.interactions", Type: TypeString, Description: "Purge .beads/interactions.jsonl entries older than this"},
+	{Key: "retention.schedule", Type: TypeString, Description: "Cron schedule the daemon applies the retention policy on"},
+	{Key: "sprint.start", Type: TypeDate, Description: "First day of any sprint"},
+	{Key: "sprint.length-days", Type: TypeInt, Min: 1, Description: "Sprint length in days"},
+	{Key: "capacity.*", Type: TypeInt, Description: "WIP limit per assignee (0 = unlimited)"},
+	{Key: "components.*.assignee", Type: TypeString, Description: "Default assignee for a component"},
+	{Key: "components.*.labels", Type: TypeList, Description: "Default labels for a component"},
+	{Key: "automation.rules", Type: TypeMap, Description: "Automation rules"},
+	{Key: "labels.auto", Type: TypeMap, Description: "Keyword rules that label new issues, by label"},
+	{Key: "labels.auto.*", Type: TypeList, Description: "Keywords (or /regex/) that add the label"},
+	{Key: "status.custom", Type: TypeList, Description: "Custom statuses"},
+	{Key: "types.custom", Type: TypeList, Description: "Custom issue types"},
+	{Key: "query.*", Type: TypeString, Description: "Saved query"},
+	{Key: "schedule.*", Type: TypeString, Internal: true},
+	{Key: "hints.doctor", Type: TypeBool, Description: "Show bd doctor hints"},
+	{Key: "mail.delegate", Type: TypeString, Description: "Command bd mail delegates to"},
+
+	// Daemon-served outputs
+	{Key: "refs.check-interval", Type: TypeDuration, Description: "How often the daemon re-checks external refs (0 = never)"},
+	{Key: "pr.refresh-interval", Type: TypeDuration, Description: "How often the daemon refreshes linked pull request statuses (0 = never)"},
+	{Key: "gates.check-interval", Type: TypeDuration, Description: "How often the daemon evaluates cmd and url gates (0 = never)"},
+	{Key: "obsidian.vault-dir", Type: TypeString, Description: "Obsidian vault the daemon keeps current"},
+	{Key: "feed.listen", Type: TypeString, Description: "Address the daemon serves the activity feed on"},
+	{Key: "feed.token", Type: TypeString, Description: "Bearer token that lets feed clients see internal issues"},
+	{Key: "api.listen", Type: TypeString, Description: "Address the daemon serves the HTTP API (GraphQL) on"},
+	{Key: "api.token", Type: TypeString, Description: "Bearer token that lets API clients see internal issues"},
+	{Key: "api.oidc.issuer", Type: TypeURL, Description: "OpenID Connect issuer URL API users sign in with"},
+	{Key: "api.oidc.client-id", Type: TypeString, Description: "OAuth client ID registered with the OIDC provider"},
+	{Key: "api.oidc.client-secret", Type: TypeString, Description: "OAuth client secret registered with the OIDC provider"},
+	{Key: "api.oidc.redirect-url", Type: TypeURL, Description: "The API's /auth/callback URL registered with the OIDC provider"},
+	{Key: "api.oidc.groups-claim", Type: TypeString, Description: "ID token claim listing a user's groups"},
+	{Key: "api.oidc.roles", Type: TypeString, Description: "Group to role mappings (eng=member,contractors=viewer)"},
+	{Key: "api.oidc.default-role", Type: TypeEnum, Values: []string{"", "viewer", "member"}, Description: "Role of signed-in users in no mapped group"},
+	{Key: "api.oidc.session-secret", Type: TypeString, Description: "Key that signs API session cookies"},
+	{Key: "api.rate-limits.*", Type: TypeRate, Description: "Request rate limit for an API token or user"},
+	{Key: "api.audit-retention", Type: TypeDuration, Description: "How long the API access log keeps requests (0 = forever)"},
+	{Key: "changelog.file", Type: TypeString, Description: "CHANGELOG.md the daemon keeps current"},
+	{Key: "summary.file", Type: TypeString, Description: "Status document (STATUS.md) the daemon keeps current"},
+	{Key: "portfolio.sla", Type: TypeString, Description: "Longest an issue may stay open by priority (p0=1d,p1=7d)"},
+	{Key: "http.log", Type: TypeBool, Description: "Log every connector HTTP request"},
+	{Key: "http.rate-limits.*", Type: TypeRate, Description: "Request rate limit for a host"},
+
+	// Project database settings
+	{Key: "issue_prefix", Type: TypeString, NoOverride: true, Description: "Issue ID prefix"},
+	{Key: "allowed_prefixes", Type: TypeList, NoOverride: true, Description: "Other prefixes accepted on import"},
+	{Key: "min_hash_length", Type: TypeInt, Min: 3, Description: "Shortest hash ID"},
+	{Key: "max_hash_length", Type: TypeInt, Min: 3, Description: "Longest hash ID"},
+	{Key: "max_collision_prob", Type: TypeFloat, Description: "Acceptable ID collision probability"},
+	{Key: "compact_tier1_days", Type: TypeInt, Description: "Days closed before tier 1 compaction"},
+	{Key: "compact_tier1_dep_levels", Type: TypeInt, Description: "Dependency levels checked before tier 1 compaction"},
+	{Key: "compact_tier2_days", Type: TypeInt, Description: "Days closed before tier 2 compaction"},
+	{Key: "compact_tier2_commits", Type: TypeInt, Description: "Commits before tier 2 compaction"},
+	{Key: "compact_tier2_dep_levels", Type: TypeInt, Description: "Dependency levels checked before tier 2 compaction"},
+	{Key: "compaction_enabled", Type: TypeBool, Description: "Allow compaction"},
+	{Key: "auto_compact_enabled", Type: TypeBool, Description: "Compact automatically"},
+	{Key: "compact_model", Type: TypeString, Description: "Model used to summarize compacted issues"},
+	{Key: "compact_batch_size", Type: TypeInt, Min: 1, Description: "Issues compacted per batch"},
+	{Key: "compact_parallel_workers", Type: TypeInt, Min: 1, Description: "Parallel compaction workers"},
+	{Key: "tombstone.ttl_days", Type: TypeInt, Description: "Days deletions are remembered"},
+	{Key: "import.orphan_handling", Type: TypeEnum, Values: orphanModes, Description: "What import does with issues whose parent is missing"},
+	{Key: "import.missing_parents", Type: TypeEnum, Values: orphanModes, Description: "What import does with issues whose parent is missing"},
+	{Key: "export.error_policy", Type: TypeEnum, Values: exportPolicies, Description: "Error handling of bd export"},
+	{Key: "auto_export.error_policy", Type: TypeEnum, Values: exportPolicies, Description: "Error handling of auto-export"},
+	{Key: "export.retry_attempts", Type: TypeInt, Description: "Retries of a failed export step"},
+	{Key: "export.retry_backoff_ms", Type: TypeInt, Description: "Delay between export retries"},
+	{Key: "export.skip_encoding_errors", Type: TypeBool, Description: "Skip issues that fail to encode"},
+	{Key: "export.write_manifest", Type: TypeBool, Description: "Write an export manifest"},
+	{Key: "last_created_issue", Type: TypeString, Internal: true},
+	{Key: "subset", Type: TypeString, Internal: true},
+	{Key: "oplog_replica", Type: TypeString, Internal: true},
+	{Key: "custom.*", Type: TypeString, Description: "Free-form settings for custom integrations"},
+
+	// Connectors
+	{Key: "jira.url", Type: TypeURL, Description: "Jira site URL"},
+	{Key: "jira.project", Type: TypeString, Description: "Jira project key"},
+	{Key: "jira.username", Type: TypeString, Description: "Jira user name"},
+	{Key: "jira.api_token", Type: TypeString, Description: "Jira API token (use bd auth login jira)"},
+	{Key: "jira.status_map.*", Type: TypeString, Description: "Jira status for a beads status"},
+	{Key: "jira.type_map.*", Type: TypeString, Description: "Jira issue type for a beads type"},
+	{Key: "jira.last_sync", Type: TypeString, Internal: true},
+	{Key: "linear.api_key", Type: TypeString, Description: "Linear API key (use bd auth login linear)"},
+	{Key: "linear.team_id", Type: TypeString, Description: "Linear team ID"},
+	{Key: "linear.project_id", Type: TypeString, Description: "Linear project ID"},
+	{Key: "linear.api_endpoint", Type: TypeURL, Description: "Linear GraphQL endpoint"},
+	{Key: "linear.id_mode", Type: TypeEnum, Values: []string{"hash", "db"}, Description: "How imported issues get IDs"},
+	{Key: "linear.hash_length", Type: TypeInt, Min: 3, Description: "Hash ID length for imported issues"},
+	{Key: "linear.estimate_minutes", Type: TypeInt, Description: "Minutes per estimate point"},
+	{Key: "linear.state_map.*", Type: TypeString, Description: "Beads status for a Linear state"},
+	{Key: "linear.label_type_map.*", Type: TypeString, Description: "Beads type for a Linear label"},
+	{Key: "linear.priority_map.*", Type: TypeString, Description: "Beads priority for a Linear priority"},
+	{Key: "linear.relation_map.*", Type: TypeString, Description: "Beads dependency type for a Linear relation"},
+	{Key: "linear.project_map.*", Type: TypeString, Description: "Component for a Linear project"},
+	{Key: "linear.last_sync", Type: TypeString, Internal: true},
+	{Key: "github.org", Type: TypeString, Description: "GitHub organization"},
+	{Key: "github.repo", Type: TypeString, Description: "GitHub repository"},
+	{Key: "github.token", Type: TypeString, Description: "GitHub token (use bd auth login github)"},
+	{Key: "github.label_map.*", Type: TypeString, Description: "GitHub label for a beads type"},
+	{Key: "gitlab.url", Type: TypeURL, Description: "GitLab instance URL"},
+	{Key: "gitlab.project", Type: TypeString, Description: "GitLab project path or ID"},
+	{Key: "gitlab.token", Type: TypeString, Description: "GitLab token (use bd auth login gitlab)"},
+	{Key: "gitlab.close_on_merge", Type: TypeBool, Description: "Close issues when their merge request merges"},
+	{Key: "gitlab.last_sync", Type: TypeString, Internal: true},
+	{Key: "gitlab.mr_last_sync", Type: TypeString, Internal: true},
+	{Key: "email.imap.url", Type: TypeURL, Schemes: []string{"imaps", "imap"}, Description: "Mailbox bd ingest email --imap polls (imaps://host[:port]/mailbox)"},
+	{Key: "email.imap.username", Type: TypeString, Description: "IMAP user name"},
+	{Key: "email.imap.password", Type: TypeString, Description: "IMAP password (use bd auth login imap)"},
+	{Key: "notion.token", Type: TypeString, Description: "Notion integration token (use bd auth login notion)"},
+	{Key: "notion.database_id", Type: TypeString, Description: "Notion database ID"},
+	{Key: "notion.api_endpoint", Type: TypeURL, Description: "Notion API endpoint"},
+	{Key: "notion.*_property", Type: TypeString, Description: "Notion property name"},
+	{Key: "notion.status_map.*", Type: TypeString, Description: "Beads status for a Notion status"},
+	{Key: "ado.org_url", Type: TypeURL, Description: "Azure DevOps organization URL"},
+	{Key: "ado.project", Type: TypeString, Description: "Azure DevOps project"},
+	{Key: "ado.pat", Type: TypeString, Description: "Azure DevOps token (use bd auth login ado)"},
+	{Key: "ado.status_map.*", Type: TypeString, Description: "Beads status for an Azure DevOps state"},
+	{Key: "trello.status_map.*", Type: TypeString, Description: "Beads status for a Trello list"},
+	{Key: "taskwarrior.project", Type: TypeString, Description: "Taskwarrior project"},
+	{Key: "taskwarrior.assignee", Type: TypeString, Description: "Assignee of imported tasks"},
+	{Key: "taskwarrior.bin", Type: TypeString, Description: "Taskwarrior binary"},
+	{Key: "taskwarrior.last_sync", Type: TypeString, Internal: true},
+}
+
+// LookupKey returns the schema entry for key. Exact entries win over
+// wildcard ones.
+func LookupKey(key string) (KeySpec, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, spec := range Schema {
+		if spec.Key == key {
+			return spec, true
+		}
+	}
+	for _, spec := range Schema {
+		if strings.Contains(spec.Key, "*") {
+			if ok, _ := path.Match(spec.Key, key); ok {
+				return spec, true
+			}
+		}
+	}
+	return KeySpec{}, false
+}
+
+// LookupRename returns the rename of a deprecated key.
+func LookupRename(key string) (Rename, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, r := range Renames {
+		if r.Old == key {
+			return r, true
+		}
+	}
+	return Rename{}, false
+}
+
+// UnknownKeyError is returned by ValidateValue for a key not in the schema.
+type UnknownKeyError struct {
+	Key        string
+	Suggestion string // Closest known key, if any is close
+}
+
+func (e *UnknownKeyError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("unknown config key %q (did you mean %q?)", e.Key, e.Suggestion)
+	}
+	return fmt.Sprintf("unknown config key %q", e.Key)
+}
+
+// ValidateValue checks that key is known and value has the right type.
+func ValidateValue(key, value string) error {
+	spec, ok := LookupKey(key)
+	if !ok {
+		return &UnknownKeyError{Key: key, Suggestion: SuggestKey(key)}
+	}
+	if err := spec.Check(value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return validateYamlConfigValue(key, value)
+}
+
+var rateRe = regexp.MustCompile(`^\s*\d+(\.\d+)?\s*(/\s*[smh])?\s*$`)
+
+// Check validates a value against the key's type.
+func (spec KeySpec) Check(value string) error {
+	switch spec.Type {
+	case TypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("want true or false, got %q", value)
+		}
+	case TypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("want an integer, got %q", value)
+		}
+		if n < spec.Min {
+			return fmt.Errorf("must be at least %d, got %d", spec.Min, n)
+		}
+	case TypeFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("want a number, got %q", value)
+		}
+	case TypeDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("want a duration such as 30s or 5m, got %q", value)
+		}
+	case TypeDate:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return fmt.Errorf("want a date (YYYY-MM-DD), got %q", value)
+		}
+	case TypeEnum:
+		for _, allowed := range spec.Values {
+			if value == allowed {
+				return nil
+			}
+		}
+		var shown []string
+		for _, allowed := range spec.Values {
+			if allowed != "" {
+				shown = append(shown, allowed)
+			}
+		}
+		return fmt.Errorf("want one of %s, got %q", strings.Join(shown, ", "), value)
+	case TypeURL:
+		schemes := spec.Schemes
+		if len(schemes) == 0 {
+			schemes = []string{"http", "https"}
+		}
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "" && u.Path == "") {
+			return fmt.Errorf("want a URL such as %s://example.com, got %q", schemes[0], value)
+		}
+		for _, scheme := range schemes {
+			if strings.EqualFold(u.Scheme, scheme) {
+				return nil
+			}
+		}
+		return fmt.Errorf("URL scheme must be one of %s, got %q", strings.Join(schemes, ", "), u.Scheme)
+	case TypeRate:
+		if !rateRe.MatchString(value) {
+			return fmt.Errorf("want a rate such as 10/s, 100/m or 1000/h, got %q", value)
+		}
+	case TypeSize:
+		if _, err := humanize.ParseBytes(value); err != nil {
+			return fmt.Errorf("want a size such as 512KB or 10MB, got %q", value)
+		}
+	case TypeMap:
+		return fmt.Errorf("is a section; edit it in config.yaml")
+	}
+	return nil
+}
+
+// SuggestKey returns the known key closest to a misspelt one, or "".
+func SuggestKey(key string) string {
+	key = strings.ToLower(key)
+	best, bestDist := "", -1
+	for _, spec := range Schema {
+		if spec.Internal || strings.Contains(spec.Key, "*") {
+			continue
+		}
+		if d := editDistance(key, spec.Key); bestDist < 0 || d < bestDist {
+			best, bestDist = spec.Key, d
+		}
+	}
+	if bestDist < 0 || bestDist > max(2, len(key)/5) {
+		return ""
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// LintIssue is one problem found by Lint.
+type LintIssue struct {
+	Source  string `json:"source"` // Config file, or "database"
+	Key     string `json:"key"`
+	Problem string `json:"problem"` // "unknown", "invalid" or "deprecated"
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"` // Replacement key of a deprecated key
+}
+
+// LintValues checks flat key/value settings from one source.
+func LintValues(source string, values map[string]string) []LintIssue {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var issues []LintIssue
+	for _, key := range keys {
+		if issue, ok := lintValue(source, key, values[key]); ok {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+func lintValue(source, key, value string) (LintIssue, bool) {
+	if r, ok := LookupRename(key); ok {
+		return LintIssue{Source: source, Key: key, Problem: "deprecated",
+			Message: fmt.Sprintf("%s is deprecated; use %s", key, r.New), Fix: r.New}, true
+	}
+	err := ValidateValue(key, value)
+	if err == nil {
+		return LintIssue{}, false
+	}
+	if _, unknown := err.(*UnknownKeyError); unknown {
+		return LintIssue{Source: source, Key: key, Problem: "unknown", Message: err.Error()}, true
+	}
+	return LintIssue{Source: source, Key: key, Problem: "invalid", Message: err.Error()}, true
+}
+
+// LintFiles checks every loaded config file, including its profiles.
+func LintFiles() []LintIssue {
+	var issues []LintIssue
+	for _, l := range layers {
+		flat := make(map[string]string)
+		for key, value := range l.values {
+			if key == profilesKey {
+				continue
+			}
+			flattenSetting(key, value, flat)
+		}
+		issues = append(issues, LintValues(l.path, flat)...)
+		if profiles, ok := l.values[profilesKey].(map[string]interface{}); ok {
+			names := make([]string, 0, len(profiles))
+			for name := range profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				flat := make(map[string]string)
+				section, _ := profiles[name].(map[string]interface{})
+				for key, value := range section {
+					flattenSetting(key, value, flat)
+				}
+				for _, issue := range LintValues(l.path, flat) {
+					issue.Key = profilesKey + "." + name + "." + issue.Key
+					issues = append(issues, issue)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// flattenSetting turns a YAML value into dotted keys, stopping at keys the
+// schema knows so sections and lists are checked as a whole.
+func flattenSetting(key string, value interface{}, out map[string]string) {
+	spec, known := LookupKey(key)
+	child, isMap := value.(map[string]interface{})
+	switch {
+	case value == nil:
+		return // "key:" with no value leaves it unset
+	case known && spec.Type == TypeMap:
+		return // Sections are free-form
+	case isMap && !(known && !strings.Contains(spec.Key, "*")):
+		for k, v := range child {
+			flattenSetting(key+"."+k, v, out)
+		}
+	case known && spec.Type == TypeList:
+		out[key] = "" // YAML lists and comma-separated strings both work
+	default:
+		out[key] = fmt.Sprint(value)
+	}
+}
+
+// migrateRenamed moves deprecated keys in one file's settings to their
+// replacements, so old config files keep working, and returns the
+// deprecated keys it found.
+func migrateRenamed(values map[string]interface{}) []Rename {
+	var found []Rename
+	for _, r := range Renames {
+		old, ok := lookupKey(values, r.Old)
+		if !ok {
+			continue
+		}
+		found = append(found, r)
+		deleteKey(values, r.Old)
+		_, exists := lookupKey(values, r.New)
+		switch {
+		case !exists:
+			setKey(values, r.New, old)
+		case r.AnyTrue && isTrue(old):
+			setKey(values, r.New, true)
+		}
+	}
+	return found
+}
+
+func isTrue(value interface{}) bool {
+	b, err := strconv.ParseBool(fmt.Sprint(value))
+	return err == nil && b
+}
+
+// setKey stores a dotted key in nested config maps.
+func setKey(values map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := values[part].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			values[part] = child
+		}
+		values = child
+	}
+	values[parts[len(parts)-1]] = value
+}
+
+// deleteKey removes a dotted key, written flat or nested, from config maps.
+func deleteKey(values map[string]interface{}, key string) {
+	if _, ok := values[key]; ok {
+		delete(values, key)
+		return
+	}
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) < 2 {
+		return
+	}
+	if child, ok := values[parts[0]].(map[string]interface{}); ok {
+		deleteKey(child, parts[1])
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateValue(t *testing.T) {
+	valid := map[string]string{
+		"lock-timeout":                    "45s",
+		"no-daemon":                       "true",
+		"routing.mode":                    "contributor",
+		"jira.url":                        "https://company.atlassian.net",
+		"federation.remote":               "dolthub://org/beads",
+		"federation.sovereignty":          "",
+		"sprint.start":                    "2026-01-05",
+		"capacity.alice":                  "3",
+		"jira.status_map.in_progress":     "In Progress",
+		"http.rate-limits.api.github.com": "10/s",
+		"components.api.assignee":         "bob",
+		"custom.anything.goes":            "x",
+		"gitlab.sync_checkpoint":          "{}",
+	}
+	for key, value := range valid {
+		if err := ValidateValue(key, value); err != nil {
+			t.Errorf("ValidateValue(%q, %q) = %v", key, value, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"lock-timeout":        "5",
+		"no-daemon":           "yes please",
+		"routing.mode":        "admin",
+		"jira.url":            "company.atlassian.net",
+		"federation.remote":   "ftp://host/x",
+		"hierarchy.max-depth": "0",
+		"capacity.alice":      "-1",
+		"sprint.start":        "next monday",
+		"http.rate-limits.x":  "fast",
+		"directory.labels":    "x",
+	}
+	for key, value := range invalid {
+		err := ValidateValue(key, value)
+		var unknown *UnknownKeyError
+		if err == nil || errors.As(err, &unknown) {
+			t.Errorf("ValidateValue(%q, %q) = %v, want a type error", key, value, err)
+		}
+	}
+}
+
+func TestUnknownKeySuggestion(t *testing.T) {
+	for typo, want := range map[string]string{
+		"jira.ulr":      "jira.url",
+		"isue_prefix":   "issue_prefix",
+		"lock-timout":   "lock-timeout",
+		"totally.fresh": "",
+	} {
+		var unknown *UnknownKeyError
+		if err := ValidateValue(typo, "x"); !errors.As(err, &unknown) || unknown.Suggestion != want {
+			t.Errorf("ValidateValue(%q) = %v, want a suggestion of %q", typo, err, want)
+		}
+	}
+}
+
+func TestLintFilesAndRenamedKeys(t *testing.T) {
+	restore := envSnapshot(t)
+	defer restore()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, ".config"))
+	origSystem := systemConfigPath
+	systemConfigPath = filepath.Join(dir, "none")
+	defer func() { systemConfigPath = origSystem }()
+
+	config := `no-daemon: maybe
+actor: alice
+daemon:
+  auto_pull: true
+  auto_commit: true
+http:
+  rate-limits:
+    api.github.com: 10/s
+directory:
+  labels:
+    services/api: api
+profiles:
+  ci:
+    lock-timout: 1s
+`
+	if err := os.MkdirAll(filepath.Join(dir, ".beads"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".beads", "config.yaml"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, issue := range LintFiles() {
+		got[issue.Key] = issue.Problem
+	}
+	want := map[string]string{
+		"no-daemon":               "invalid",
+		"daemon.auto_pull":        "deprecated",
+		"daemon.auto_commit":      "deprecated",
+		"profiles.ci.lock-timout": "unknown",
+	}
+	if len(got) != len(want) {
+		t.Errorf("LintFiles = %v, want %v", got, want)
+	}
+	for key, problem := range want {
+		if got[key] != problem {
+			t.Errorf("%s: got %q, want %q", key, got[key], problem)
+		}
+	}
+
+	// Deprecated keys keep working under their new names
+	if !GetBool("daemon.auto-pull") || !GetBool("daemon.auto-sync") {
+		t.Errorf("renamed keys not applied: auto-pull=%v auto-sync=%v", GetBool("daemon.auto-pull"), GetBool("daemon.auto-sync"))
+	}
+	warnings := strings.Join(DeprecationWarnings(), "\n")
+	if !strings.Contains(warnings, "daemon.auto_pull") || !strings.Contains(warnings, "daemon.auto-sync") {
+		t.Errorf("DeprecationWarnings = %q", warnings)
+	}
+}
+
+func TestMigrateRenamedAnyTrue(t *testing.T) {
+	values := map[string]interface{}{
+		"daemon": map[string]interface{}{"auto-sync": false, "auto_push": true},
+	}
+	migrateRenamed(values)
+	if got, _ := lookupKey(values, "daemon.auto-sync"); got != true {
+		t.Errorf("daemon.auto-sync = %v, want true when any legacy key was", got)
+	}
+	if _, ok := lookupKey(values, "daemon.auto_push"); ok {
+		t.Error("deprecated key not removed")
+	}
+}
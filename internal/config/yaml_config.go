@@ -8,6 +8,10 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/i18n"
+	"github.com/steveyegge/beads/internal/schedule"
 )
 
 // YamlOnlyKeys are configuration keys that must be stored in config.yaml
@@ -25,11 +29,13 @@ var YamlOnlyKeys = map[string]bool{
 	"no-auto-import":    true,
 	"json":              true,
 	"auto-start-daemon": true,
+	"accessible":        true,
 
 	// Database and identity
 	"db":       true,
 	"actor":    true,
 	"identity": true,
+	"lang":     true, // Output language (BEADS_LANG)
 
 	// Timing settings
 	"flush-debounce":       true,
@@ -68,6 +74,46 @@ var YamlOnlyKeys = map[string]bool{
 
 	// Hierarchy settings (GH#995)
 	"hierarchy.max-depth": true,
+
+	// External ref checks
+	"refs.check-interval": true,
+
+	// Linked pull requests
+	"pr.refresh-interval": true,
+
+	// Condition gates (bd dep add --until-cmd/--until-url)
+	"gates.check-interval": true,
+
+	// Obsidian vault export
+	"obsidian.vault-dir": true,
+
+	// Activity feed served by the daemon
+	"feed.listen": true,
+	"feed.token":  true,
+
+	// HTTP API served by the daemon
+	"api.listen": true,
+	"api.token":  true,
+
+	// Single sign-on for the HTTP API
+	"api.oidc.issuer":         true,
+	"api.oidc.client-id":      true,
+	"api.oidc.client-secret":  true,
+	"api.oidc.redirect-url":   true,
+	"api.oidc.groups-claim":   true,
+	"api.oidc.roles":          true,
+	"api.oidc.default-role":   true,
+	"api.oidc.session-secret": true,
+	"api.audit-retention":     true,
+
+	// Changelog maintained by the daemon
+	"changelog.file": true,
+
+	// Status document maintained by the daemon
+	"summary.file": true,
+
+	// Service levels scored by bd portfolio
+	"portfolio.sla": true,
 }
 
 // IsYamlOnlyKey returns true if the given key should be stored in config.yaml
@@ -79,7 +125,7 @@ func IsYamlOnlyKey(key string) bool {
 	}
 
 	// Check prefix matches for nested keys
-	prefixes := []string{"routing.", "sync.", "git.", "directory.", "repos.", "external_projects.", "validation.", "daemon.", "hierarchy."}
+	prefixes := []string{"routing.", "sync.", "git.", "directory.", "repos.", "external_projects.", "validation.", "daemon.", "hierarchy.", "capacity.", "sprint.", "components.", "freeze.", "lock.", "sign.", "redact.", "retention.", "api.rate-limits.", "attachments."}
 	for _, prefix := range prefixes {
 		if strings.HasPrefix(key, prefix) {
 			return true
@@ -286,6 +332,10 @@ func needsQuoting(s string) bool {
 
 // validateYamlConfigValue validates a configuration value before setting.
 // Returns an error if the value is invalid for the given key.
+// retentionAgeRe matches retention ages: a count of hours, days, weeks,
+// months or years.
+var retentionAgeRe = regexp.MustCompile(`^\d+[hdwmy]$`)
+
 func validateYamlConfigValue(key, value string) error {
 	switch key {
 	case "hierarchy.max-depth":
@@ -297,6 +347,43 @@ func validateYamlConfigValue(key, value string) error {
 		if depth < 1 {
 			return fmt.Errorf("hierarchy.max-depth must be at least 1, got %d", depth)
 		}
+	case "sprint.start":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return fmt.Errorf("sprint.start must be a date (YYYY-MM-DD), got %q", value)
+		}
+	case "sprint.length-days":
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 {
+			return fmt.Errorf("sprint.length-days must be a positive integer, got %q", value)
+		}
+	}
+	switch key {
+	case "lang":
+		if _, err := i18n.Load(value); err != nil {
+			return fmt.Errorf("lang: %w", err)
+		}
+	case "retention.closed-comments", "retention.events", "retention.interactions":
+		if value != "" && !retentionAgeRe.MatchString(value) {
+			return fmt.Errorf("%s must be an age such as 90d, 6m or 1y, got %q", key, value)
+		}
+	case "retention.schedule":
+		if value != "" {
+			if _, err := schedule.ParseCron(value); err != nil {
+				return fmt.Errorf("retention.schedule: %w", err)
+			}
+		}
+	}
+	if strings.HasPrefix(key, "redact.patterns.") {
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("%s must be a valid regular expression: %v", key, err)
+		}
+	}
+	if strings.HasPrefix(key, "capacity.") {
+		// WIP limits must be non-negative integers (0 = unlimited)
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return fmt.Errorf("%s must be a non-negative integer, got %q", key, value)
+		}
 	}
 	return nil
 }
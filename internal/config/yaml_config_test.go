@@ -41,6 +41,10 @@ func TestIsYamlOnlyKey(t *testing.T) {
 		{"hierarchy.max-depth", true},
 		{"hierarchy.custom_setting", true}, // prefix match
 
+		// Component defaults
+		{"components.api.assignee", true},
+		{"components.api.labels", true},
+
 		// SQLite keys (should return false)
 		{"jira.url", false},
 		{"jira.project", false},
@@ -0,0 +1,318 @@
+// Package credentials stores connector secrets (API tokens) outside the
+// project config, so they never end up in an exported or committed file.
+//
+// Secrets go to the OS keychain where one is available (macOS Keychain,
+// the Secret Service on Linux via secret-tool, Windows Credential Manager)
+// and otherwise to an AES-GCM encrypted file in the user's config
+// directory, whose key is kept in a separate owner-only file.
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Service is the keychain service every credential is filed under.
+const Service = "beads"
+
+// BackendEnv forces a backend: "keychain" or "file".
+const BackendEnv = "BD_CREDENTIALS_BACKEND"
+
+// Backend names, as reported by Get, Set and Available.
+const (
+	BackendKeychain = "keychain"
+	BackendFile     = "file"
+)
+
+// ErrNotFound is returned when no credential is stored under a key.
+var ErrNotFound = errors.New("credential not found")
+
+var validKey = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+type backend interface {
+	get(key string) (string, error)
+	set(key, secret string) error
+	delete(key string) error
+}
+
+// Get returns the secret stored under key (e.g. "gitlab.token") and the
+// backend it came from.
+func Get(key string) (secret, from string, err error) {
+	if err := checkKey(key); err != nil {
+		return "", "", err
+	}
+	for _, name := range backends() {
+		b, err := open(name)
+		if err != nil {
+			continue
+		}
+		secret, err := b.get(key)
+		if err == nil {
+			return secret, name, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return "", "", fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return "", "", ErrNotFound
+}
+
+// Set stores secret under key, in the keychain if possible, and returns
+// the backend used. A copy in the other backend is removed so Get never
+// returns a stale secret.
+func Set(key, secret string) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", errors.New("empty secret")
+	}
+	var errs []error
+	for _, name := range backends() {
+		b, err := open(name)
+		if err == nil {
+			err = b.set(key, secret)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		for _, other := range backends() {
+			if other != name {
+				if ob, err := open(other); err == nil {
+					_ = ob.delete(key)
+				}
+			}
+		}
+		return name, nil
+	}
+	return "", errors.Join(errs...)
+}
+
+// Delete removes key from every backend. It returns ErrNotFound if no
+// backend had it.
+func Delete(key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	found := false
+	for _, name := range backends() {
+		b, err := open(name)
+		if err != nil {
+			continue
+		}
+		switch err := b.delete(key); {
+		case err == nil:
+			found = true
+		case !errors.Is(err, ErrNotFound):
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if !found {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Available returns the backend new credentials are stored in.
+func Available() string {
+	return backends()[0]
+}
+
+func backends() []string {
+	switch strings.ToLower(os.Getenv(BackendEnv)) {
+	case BackendFile:
+		return []string{BackendFile}
+	case BackendKeychain:
+		return []string{BackendKeychain}
+	}
+	if keychainAvailable() {
+		return []string{BackendKeychain, BackendFile}
+	}
+	return []string{BackendFile}
+}
+
+func open(name string) (backend, error) {
+	if name == BackendKeychain {
+		if !keychainAvailable() {
+			return nil, errors.New("no OS keychain available")
+		}
+		return keychain{}, nil
+	}
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return fileBackend{dir: dir}, nil
+}
+
+func checkKey(key string) error {
+	if !validKey.MatchString(key) {
+		return fmt.Errorf("invalid credential key %q", key)
+	}
+	return nil
+}
+
+// Dir is where the encrypted credentials file and its key live.
+func Dir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "bd"), nil
+}
+
+// fileBackend keeps every credential in one AES-GCM encrypted JSON object.
+// The key sits next to it in a separate owner-only file: this keeps tokens
+// out of backups, dotfile repos and screen shares of the credentials file,
+// but not from other programs running as the same user.
+type fileBackend struct {
+	dir string
+}
+
+const (
+	credentialsFile = "credentials.enc"
+	keyFile         = "credentials.key"
+)
+
+func (f fileBackend) get(key string) (string, error) {
+	all, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := all[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (f fileBackend) set(key, secret string) error {
+	all, err := f.load()
+	if err != nil {
+		return err
+	}
+	all[key] = secret
+	return f.save(all)
+}
+
+func (f fileBackend) delete(key string) error {
+	all, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := all[key]; !ok {
+		return ErrNotFound
+	}
+	delete(all, key)
+	return f.save(all)
+}
+
+func (f fileBackend) load() (map[string]string, error) {
+	all := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(f.dir, credentialsFile)) // #nosec G304 - fixed name in the user config dir
+	if os.IsNotExist(err) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	aead, err := f.cipher(false)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("credentials file is truncated")
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt credentials file (was %s replaced?): %w", keyFile, err)
+	}
+	if err := json.Unmarshal(plain, &all); err != nil {
+		return nil, fmt.Errorf("corrupt credentials file: %w", err)
+	}
+	return all, nil
+}
+
+func (f fileBackend) save(all map[string]string) error {
+	path := filepath.Join(f.dir, credentialsFile)
+	if len(all) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	aead, err := f.cipher(true)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	return writePrivate(path, aead.Seal(nonce, nonce, plain, nil))
+}
+
+// cipher returns the file's AEAD, creating the key if create is set.
+func (f fileBackend) cipher(create bool) (cipher.AEAD, error) {
+	path := filepath.Join(f.dir, keyFile)
+	raw, err := os.ReadFile(path) // #nosec G304 - fixed name in the user config dir
+	if os.IsNotExist(err) && create {
+		raw = make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		raw = []byte(hex.EncodeToString(raw))
+		if err := writePrivate(path, raw); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("reading credentials key: %w", err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("invalid credentials key in %s", path)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writePrivate replaces path with data, readable only by the owner.
+func writePrivate(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,99 @@
+package credentials
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// useFile points the file backend at a temporary config dir.
+func useFile(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+	t.Setenv(BackendEnv, BackendFile)
+	d, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestFileBackendRoundTrip(t *testing.T) {
+	dir := useFile(t)
+
+	if _, _, err := Get("gitlab.token"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get before Set = %v, want ErrNotFound", err)
+	}
+	if from, err := Set("gitlab.token", "glpat-secret"); err != nil || from != BackendFile {
+		t.Fatalf("Set = %q, %v", from, err)
+	}
+	if _, err := Set("linear.api_key", "lin_api_other"); err != nil {
+		t.Fatal(err)
+	}
+	secret, from, err := Get("gitlab.token")
+	if err != nil || secret != "glpat-secret" || from != BackendFile {
+		t.Fatalf("Get = %q, %q, %v", secret, from, err)
+	}
+
+	// The secret must not be readable from the file on disk
+	data, err := os.ReadFile(filepath.Join(dir, credentialsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("glpat-secret")) {
+		t.Error("credentials file contains the plaintext secret")
+	}
+	if runtime.GOOS != "windows" {
+		for _, name := range []string{credentialsFile, keyFile} {
+			fi, err := os.Stat(filepath.Join(dir, name))
+			if err != nil || fi.Mode().Perm() != 0o600 {
+				t.Errorf("%s mode = %v, %v; want 0600", name, fi.Mode().Perm(), err)
+			}
+		}
+	}
+
+	if err := Delete("gitlab.token"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Get("gitlab.token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v", err)
+	}
+	if err := Delete("gitlab.token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+	if secret, _, _ := Get("linear.api_key"); secret != "lin_api_other" {
+		t.Errorf("other credential = %q after Delete", secret)
+	}
+}
+
+func TestFileBackendWrongKey(t *testing.T) {
+	dir := useFile(t)
+	if _, err := Set("jira.api_token", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	other := bytes.Repeat([]byte("ab"), 32)
+	if err := os.WriteFile(filepath.Join(dir, keyFile), other, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Get("jira.api_token"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get with the wrong key = %v, want a decryption error", err)
+	}
+}
+
+func TestInvalidKeysAndSecrets(t *testing.T) {
+	useFile(t)
+	for _, key := range []string{"", "Gitlab.Token", "../x", "a b"} {
+		if _, _, err := Get(key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q) = %v, want invalid key", key, err)
+		}
+	}
+	if _, err := Set("gitlab.token", ""); err == nil {
+		t.Error("Set accepted an empty secret")
+	}
+}
@@ -0,0 +1,77 @@
+//go:build darwin
+
+package credentials
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// keychain stores credentials as generic passwords in the login keychain
+// through the security(1) tool.
+type keychain struct{}
+
+func keychainAvailable() bool {
+	_, err := exec.LookPath("security")
+	return err == nil
+}
+
+func (keychain) get(key string) (string, error) {
+	out, err := security(nil, "find-generic-password", "-s", Service, "-a", key, "-w")
+	if err != nil {
+		if isExit(err, 44) { // errSecItemNotFound
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (keychain) set(key, secret string) error {
+	// Feed the command through interactive mode with a hex-encoded secret,
+	// so the token never appears in a process listing
+	cmd := fmt.Sprintf("add-generic-password -U -s %s -a %s -l %s -X %s\n",
+		Service, key, Service+"."+key, hex.EncodeToString([]byte(secret)))
+	_, err := security(strings.NewReader(cmd), "-i")
+	return err
+}
+
+func (keychain) delete(key string) error {
+	_, err := security(nil, "delete-generic-password", "-s", Service, "-a", key)
+	if isExit(err, 44) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func security(stdin *strings.Reader, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), keychainTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "security", args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 && !isExit(err, 44) {
+			return nil, fmt.Errorf("security %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+func isExit(err error, code int) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == code
+}
+
+// keychainTimeout bounds each call, in case the keychain prompts for an
+// unlock nobody will answer.
+const keychainTimeout = 30 * time.Second
@@ -0,0 +1,80 @@
+//go:build linux
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// keychain stores credentials in the desktop keyring (GNOME Keyring,
+// KWallet, KeePassXC) through the Secret Service's secret-tool(1). It is
+// only used inside a session with a D-Bus bus, so headless machines fall
+// back to the encrypted file.
+type keychain struct{}
+
+func keychainAvailable() bool {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+func (keychain) get(key string) (string, error) {
+	out, err := secretTool(nil, "lookup", "service", Service, "account", key)
+	if err != nil {
+		return "", err
+	}
+	// lookup prints nothing (and may exit 1) when there is no match
+	if len(out) == 0 {
+		return "", ErrNotFound
+	}
+	return string(out), nil
+}
+
+func (keychain) set(key, secret string) error {
+	// The secret is read from stdin, never the command line
+	_, err := secretTool(strings.NewReader(secret), "store", "--label", Service+" "+key, "service", Service, "account", key)
+	return err
+}
+
+func (keychain) delete(key string) error {
+	if _, err := (keychain{}).get(key); err != nil {
+		return err
+	}
+	_, err := secretTool(nil, "clear", "service", Service, "account", key)
+	return err
+}
+
+func secretTool(stdin *strings.Reader, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), keychainTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "secret-tool", args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+				return nil, fmt.Errorf("secret-tool %s: %s", args[0], msg)
+			}
+			if args[0] == "lookup" {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// keychainTimeout bounds each call, in case the keyring prompts for an
+// unlock nobody will answer.
+const keychainTimeout = 30 * time.Second
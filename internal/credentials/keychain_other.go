@@ -0,0 +1,15 @@
+//go:build !darwin && !linux && !windows
+
+package credentials
+
+import "errors"
+
+// keychain is unavailable on this platform; credentials use the
+// encrypted file.
+type keychain struct{}
+
+func keychainAvailable() bool { return false }
+
+func (keychain) get(string) (string, error) { return "", errors.New("no OS keychain") }
+func (keychain) set(string, string) error   { return errors.New("no OS keychain") }
+func (keychain) delete(string) error        { return errors.New("no OS keychain") }
@@ -0,0 +1,301 @@
+// Package depscan checks the modules a go.mod requires for newer releases
+// (via the module proxy) and known vulnerabilities (via the Go vulnerability
+// database), for bd deps scan.
+package depscan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+
+	"github.com/steveyegge/beads/internal/httpx"
+)
+
+// Default endpoints, overridden by GOPROXY and GOVULNDB like the go command
+const (
+	DefaultProxy  = "https://proxy.golang.org"
+	DefaultVulnDB = "https://vuln.go.dev"
+)
+
+// Module is one requirement of a go.mod.
+type Module struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Indirect bool   `json:"indirect,omitempty"`
+}
+
+// Vuln is a Go vulnerability database entry affecting a module version.
+type Vuln struct {
+	ID      string   `json:"id"` // GO-YYYY-NNNN
+	Aliases []string `json:"aliases,omitempty"`
+	Summary string   `json:"summary,omitempty"`
+	Fixed   string   `json:"fixed,omitempty"` // First fixed version after the required one, "" if none
+}
+
+// Finding is the scan result for one module.
+type Finding struct {
+	Module
+	Latest string `json:"latest,omitempty"` // Latest release on the proxy
+	Vulns  []Vuln `json:"vulns,omitempty"`
+	Error  string `json:"error,omitempty"` // Why the module couldn't be checked
+}
+
+// Outdated reports whether a newer release than the required one exists.
+func (f *Finding) Outdated() bool {
+	return f.Latest != "" && semver.Compare(f.Latest, f.Version) > 0
+}
+
+// Vulnerable reports whether the required version has known vulnerabilities.
+func (f *Finding) Vulnerable() bool {
+	return len(f.Vulns) > 0
+}
+
+// FixedIn is the lowest version that fixes every known vulnerability, or ""
+// if some vulnerability has no fix.
+func (f *Finding) FixedIn() string {
+	fixed := ""
+	for _, v := range f.Vulns {
+		if v.Fixed == "" {
+			return ""
+		}
+		if semver.Compare(v.Fixed, fixed) > 0 {
+			fixed = v.Fixed
+		}
+	}
+	return fixed
+}
+
+// Target is the version to upgrade to: the latest release, or the fix
+// version when the latest is unknown.
+func (f *Finding) Target() string {
+	if f.Outdated() {
+		return f.Latest
+	}
+	return f.FixedIn()
+}
+
+// ParseGoMod reads a go.mod and returns its module path and requirements.
+// Requirements replaced by a local directory are skipped, since their
+// version in the require line isn't what builds.
+func ParseGoMod(file string, data []byte) (string, []Module, error) {
+	f, err := modfile.Parse(file, data, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	local := make(map[string]bool)
+	for _, r := range f.Replace {
+		if r.New.Version == "" {
+			local[r.Old.Path] = true
+		}
+	}
+	var mods []Module
+	for _, r := range f.Require {
+		if local[r.Mod.Path] {
+			continue
+		}
+		mods = append(mods, Module{Path: r.Mod.Path, Version: r.Mod.Version, Indirect: r.Indirect})
+	}
+	mainPath := ""
+	if f.Module != nil {
+		mainPath = f.Module.Mod.Path
+	}
+	return mainPath, mods, nil
+}
+
+// Scanner queries the module proxy and the vulnerability database.
+type Scanner struct {
+	Client *http.Client
+	Proxy  string // Module proxy base URL
+	VulnDB string // Vulnerability database base URL
+}
+
+// NewScanner returns a scanner using GOPROXY and GOVULNDB when they name
+// an HTTP endpoint, else the public defaults.
+func NewScanner() *Scanner {
+	s := &Scanner{Client: httpx.NewClient(20 * time.Second), Proxy: DefaultProxy, VulnDB: DefaultVulnDB}
+	for _, p := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		if strings.HasPrefix(p, "https://") || strings.HasPrefix(p, "http://") {
+			s.Proxy = strings.TrimSuffix(p, "/")
+			break
+		}
+	}
+	if db := os.Getenv("GOVULNDB"); strings.HasPrefix(db, "https://") || strings.HasPrefix(db, "http://") {
+		s.VulnDB = strings.TrimSuffix(db, "/")
+	}
+	return s
+}
+
+// Scan checks every module. Lookup failures are recorded on the module's
+// finding rather than failing the scan; only a failure to load the
+// vulnerability index is returned.
+func (s *Scanner) Scan(ctx context.Context, mods []Module) ([]*Finding, error) {
+	index, err := s.vulnIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading vulnerability index: %w", err)
+	}
+	findings := make([]*Finding, 0, len(mods))
+	for _, m := range mods {
+		f := &Finding{Module: m}
+		findings = append(findings, f)
+		if latest, err := s.latest(ctx, m.Path); err != nil {
+			f.Error = "latest version: " + err.Error()
+		} else {
+			f.Latest = latest
+		}
+		for _, id := range index[m.Path] {
+			v, affected, err := s.vuln(ctx, id, m)
+			if err != nil {
+				f.Error = id + ": " + err.Error()
+				continue
+			}
+			if affected {
+				f.Vulns = append(f.Vulns, v)
+			}
+		}
+	}
+	return findings, nil
+}
+
+// latest returns the latest release of a module from the proxy.
+func (s *Scanner) latest(ctx context.Context, path string) (string, error) {
+	escaped, err := module.EscapePath(path)
+	if err != nil {
+		return "", err
+	}
+	var info struct {
+		Version string `json:"Version"`
+	}
+	if err := s.getJSON(ctx, s.Proxy+"/"+escaped+"/@latest", &info); err != nil {
+		return "", err
+	}
+	return info.Version, nil
+}
+
+// vulnIndex returns the IDs of the vulnerabilities of each module with any.
+func (s *Scanner) vulnIndex(ctx context.Context) (map[string][]string, error) {
+	var entries []struct {
+		Path  string `json:"path"`
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	}
+	if err := s.getJSON(ctx, s.VulnDB+"/index/modules.json", &entries); err != nil {
+		return nil, err
+	}
+	index := make(map[string][]string, len(entries))
+	for _, e := range entries {
+		for _, v := range e.Vulns {
+			index[e.Path] = append(index[e.Path], v.ID)
+		}
+	}
+	return index, nil
+}
+
+// osvEntry is the part of an OSV entry the scan reads.
+type osvEntry struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Summary  string   `json:"summary"`
+	Affected []struct {
+		Package struct {
+			Name string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string `json:"type"`
+			Events []struct {
+				Introduced string `json:"introduced,omitempty"`
+				Fixed      string `json:"fixed,omitempty"`
+			} `json:"events"`
+		} `json:"ranges"`
+	} `json:"affected"`
+}
+
+// vuln fetches an entry and reports whether it affects the module version.
+func (s *Scanner) vuln(ctx context.Context, id string, m Module) (Vuln, bool, error) {
+	var e osvEntry
+	if err := s.getJSON(ctx, s.VulnDB+"/ID/"+id+".json", &e); err != nil {
+		return Vuln{}, false, err
+	}
+	v := Vuln{ID: e.ID, Aliases: e.Aliases, Summary: e.Summary}
+	for _, a := range e.Affected {
+		if a.Package.Name != m.Path {
+			continue
+		}
+		for _, r := range a.Ranges {
+			if r.Type != "SEMVER" {
+				continue
+			}
+			// Events alternate introduced/fixed in version order
+			introduced := ""
+			for _, ev := range r.Events {
+				switch {
+				case ev.Introduced != "":
+					introduced = osvVersion(ev.Introduced)
+				case ev.Fixed != "" && introduced != "":
+					fixed := osvVersion(ev.Fixed)
+					if semver.Compare(m.Version, introduced) >= 0 && semver.Compare(m.Version, fixed) < 0 {
+						v.Fixed = fixed
+						return v, true, nil
+					}
+					introduced = ""
+				}
+			}
+			if introduced != "" && semver.Compare(m.Version, introduced) >= 0 {
+				return v, true, nil // No fix yet
+			}
+		}
+	}
+	return v, false, nil
+}
+
+// osvVersion converts an OSV version ("1.2.3", "0" for the start of
+// history) to Go's semver form.
+func osvVersion(v string) string {
+	if v == "0" {
+		return "v0.0.0-0"
+	}
+	return "v" + v
+}
+
+// CVE returns the first CVE alias of a set of vulnerabilities.
+func CVE(vulns []Vuln) string {
+	var cves []string
+	for _, v := range vulns {
+		for _, a := range v.Aliases {
+			if strings.HasPrefix(a, "CVE-") {
+				cves = append(cves, a)
+			}
+		}
+	}
+	sort.Strings(cves)
+	if len(cves) == 0 {
+		return ""
+	}
+	return cves[0]
+}
+
+func (s *Scanner) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "beads-deps-scan")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
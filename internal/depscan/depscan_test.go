@@ -0,0 +1,79 @@
+package depscan
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseGoMod(t *testing.T) {
+	mainPath, mods, err := ParseGoMod("go.mod", []byte(`module example.com/app
+
+go 1.22
+
+require (
+	golang.org/x/net v0.10.0
+	github.com/local/fork v1.0.0
+	golang.org/x/text v0.9.0 // indirect
+)
+
+replace github.com/local/fork => ../fork
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mainPath != "example.com/app" || len(mods) != 2 {
+		t.Fatalf("ParseGoMod = %q, %v", mainPath, mods)
+	}
+	if mods[0] != (Module{Path: "golang.org/x/net", Version: "v0.10.0"}) || !mods[1].Indirect {
+		t.Errorf("mods = %v", mods)
+	}
+}
+
+func TestScan(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/proxy/golang.org/x/net/@latest":
+			fmt.Fprint(w, `{"Version":"v0.30.0"}`)
+		case "/proxy/github.com/!burnt!sushi/toml/@latest":
+			fmt.Fprint(w, `{"Version":"v1.3.2"}`)
+		case "/vulndb/index/modules.json":
+			fmt.Fprint(w, `[{"path":"golang.org/x/net","vulns":[{"id":"GO-2023-0001"},{"id":"GO-2023-0002"},{"id":"GO-2024-0003"}]}]`)
+		case "/vulndb/ID/GO-2023-0001.json":
+			fmt.Fprint(w, `{"id":"GO-2023-0001","aliases":["CVE-2023-1111","GHSA-x"],"affected":[{"package":{"name":"golang.org/x/net"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"0.13.0"}]}]}]}`)
+		case "/vulndb/ID/GO-2023-0002.json":
+			// Affects only a later series
+			fmt.Fprint(w, `{"id":"GO-2023-0002","affected":[{"package":{"name":"golang.org/x/net"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0.20.0"},{"fixed":"0.21.0"}]}]}]}`)
+		case "/vulndb/ID/GO-2024-0003.json":
+			fmt.Fprint(w, `{"id":"GO-2024-0003","aliases":["CVE-2024-2222"],"affected":[{"package":{"name":"golang.org/x/net"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0.5.0"},{"fixed":"0.17.0"}]}]}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	s := &Scanner{Client: srv.Client(), Proxy: srv.URL + "/proxy", VulnDB: srv.URL + "/vulndb"}
+
+	findings, err := s.Scan(context.Background(), []Module{
+		{Path: "golang.org/x/net", Version: "v0.10.0"},
+		{Path: "github.com/BurntSushi/toml", Version: "v1.3.2"},
+		{Path: "example.com/gone", Version: "v1.0.0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	net, toml, gone := findings[0], findings[1], findings[2]
+	if !net.Outdated() || !net.Vulnerable() || len(net.Vulns) != 2 {
+		t.Errorf("x/net = %+v", net)
+	}
+	if net.FixedIn() != "v0.17.0" || net.Target() != "v0.30.0" || CVE(net.Vulns) != "CVE-2023-1111" {
+		t.Errorf("x/net fixed in %s, target %s, CVE %s", net.FixedIn(), net.Target(), CVE(net.Vulns))
+	}
+	if toml.Outdated() || toml.Vulnerable() || toml.Error != "" {
+		t.Errorf("toml = %+v", toml)
+	}
+	if gone.Error == "" || gone.Outdated() {
+		t.Errorf("missing module = %+v", gone)
+	}
+}
@@ -0,0 +1,342 @@
+// Package feed builds an activity feed of recently created and closed
+// issues and new comments, written as Atom or RSS for feed readers.
+package feed
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+// Item kinds
+const (
+	KindCreated   = "created"
+	KindClosed    = "closed"
+	KindCommented = "commented"
+)
+
+// Item is one entry of the feed.
+type Item struct {
+	Kind    string    `json:"kind"`
+	IssueID string    `json:"issue_id"`
+	Title   string    `json:"title"` // issue title
+	Summary string    `json:"summary,omitempty"`
+	Author  string    `json:"author,omitempty"`
+	Labels  []string  `json:"labels,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Options selects the items of a feed.
+type Options struct {
+	Labels []string  // issues must have all of these labels
+	Since  time.Time // zero for no lower bound
+	Limit  int       // 0 for no limit
+
+	// Visibility is the most confidential visibility level included
+	// (public, internal or private); empty includes every issue.
+	Visibility string
+}
+
+// Collect returns the activity matching opts, newest first.
+func Collect(ctx context.Context, s storage.Storage, opts Options) ([]Item, error) {
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{Labels: opts.Labels})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	if opts.Visibility != "" {
+		if err := visibility.Populate(ctx, s, issues); err != nil {
+			return nil, err
+		}
+		issues = visibility.Filter(issues, opts.Visibility)
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	comments, err := s.GetCommentsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	inRange := func(t time.Time) bool { return !t.IsZero() && !t.Before(opts.Since) }
+	var items []Item
+	for _, issue := range issues {
+		if issue.Status == types.StatusTombstone {
+			continue
+		}
+		if inRange(issue.CreatedAt) {
+			items = append(items, Item{
+				Kind:    KindCreated,
+				IssueID: issue.ID,
+				Title:   issue.Title,
+				Summary: issue.Description,
+				Author:  issue.CreatedBy,
+				Labels:  labels[issue.ID],
+				Time:    issue.CreatedAt,
+			})
+		}
+		if issue.Status == types.StatusClosed && issue.ClosedAt != nil && inRange(*issue.ClosedAt) {
+			items = append(items, Item{
+				Kind:    KindClosed,
+				IssueID: issue.ID,
+				Title:   issue.Title,
+				Summary: issue.CloseReason,
+				Author:  issue.Assignee,
+				Labels:  labels[issue.ID],
+				Time:    *issue.ClosedAt,
+			})
+		}
+		for _, c := range comments[issue.ID] {
+			if inRange(c.CreatedAt) {
+				items = append(items, Item{
+					Kind:    KindCommented,
+					IssueID: issue.ID,
+					Title:   issue.Title,
+					Summary: c.Text,
+					Author:  c.Author,
+					Labels:  labels[issue.ID],
+					Time:    c.CreatedAt,
+				})
+			}
+		}
+	}
+
+	slices.SortStableFunc(items, func(a, b Item) int {
+		if c := b.Time.Compare(a.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.IssueID, b.IssueID)
+	})
+	if opts.Limit > 0 && len(items) > opts.Limit {
+		items = items[:opts.Limit]
+	}
+	return items, nil
+}
+
+// Heading is the one-line title of an item, e.g. "Closed bd-12: Fix login".
+func (it Item) Heading() string {
+	verb := "Created"
+	switch it.Kind {
+	case KindClosed:
+		verb = "Closed"
+	case KindCommented:
+		verb = "Comment on"
+	}
+	return fmt.Sprintf("%s %s: %s", verb, it.IssueID, it.Title)
+}
+
+// id is a stable, unique identifier for an item.
+func (it Item) id(base string) string {
+	return fmt.Sprintf("%s:%s:%s:%d", base, it.IssueID, it.Kind, it.Time.Unix())
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// WriteAtom writes items as an Atom feed. name identifies the project
+// (usually the issue prefix) in the feed title and IDs.
+func WriteAtom(w io.Writer, name string, items []Item) error {
+	base := "urn:beads:" + name
+	feed := atomFeed{
+		Title:   name + " activity",
+		ID:      base,
+		Updated: updated(items).Format(time.RFC3339),
+	}
+	for _, it := range items {
+		entry := atomEntry{
+			Title:   it.Heading(),
+			ID:      it.id(base),
+			Updated: it.Time.UTC().Format(time.RFC3339),
+			Summary: it.Summary,
+		}
+		if it.Author != "" {
+			entry.Author = &atomAuthor{Name: it.Author}
+		}
+		for _, label := range it.Labels {
+			entry.Categories = append(entry.Categories, atomCategory{Term: label})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return writeXML(w, feed)
+}
+
+type rssFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		Title         string    `xml:"title"`
+		Description   string    `xml:"description"`
+		LastBuildDate string    `xml:"lastBuildDate"`
+		Items         []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Author      string   `xml:"author,omitempty"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes items as an RSS 2.0 feed.
+func WriteRSS(w io.Writer, name string, items []Item) error {
+	base := "urn:beads:" + name
+	var feed rssFeed
+	feed.Version = "2.0"
+	feed.Channel.Title = name + " activity"
+	feed.Channel.Description = "Issues created and closed and comments added in " + name
+	feed.Channel.LastBuildDate = updated(items).Format(time.RFC1123Z)
+	for _, it := range items {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       it.Heading(),
+			GUID:        rssGUID{Value: it.id(base)},
+			PubDate:     it.Time.UTC().Format(time.RFC1123Z),
+			Author:      it.Author,
+			Categories:  it.Labels,
+			Description: it.Summary,
+		})
+	}
+	return writeXML(w, feed)
+}
+
+// updated is the time of the newest item, or now for an empty feed.
+func updated(items []Item) time.Time {
+	if len(items) == 0 {
+		return time.Now().UTC()
+	}
+	return items[0].Time.UTC()
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// DefaultWindow is how far back a feed goes when no since is given.
+const DefaultWindow = 7 * 24 * time.Hour
+
+// Handler serves the feed over HTTP: /feed.atom (or /feed) and /feed.rss,
+// taking repeated label= parameters, since= (a duration such as 48h or 7d,
+// or an RFC3339 time) and limit=.
+//
+// Private issues are never served. With a token, only requests carrying it
+// (as "Authorization: Bearer <token>" or token=) see internal issues; the
+// others see public issues only. Without a token every request sees
+// internal issues, as befits a listener on localhost.
+func Handler(s storage.Storage, name, token string) http.Handler {
+	mux := http.NewServeMux()
+	serve := func(write func(io.Writer, string, []Item) error, contentType string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			opts, err := parseQuery(r, time.Now())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			opts.Visibility = Audience(r, token)
+			items, err := Collect(r.Context(), s, opts)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", contentType)
+			_ = write(w, name, items)
+		}
+	}
+	atom := serve(WriteAtom, "application/atom+xml; charset=utf-8")
+	mux.Handle("GET /feed", atom)
+	mux.Handle("GET /feed.atom", atom)
+	mux.Handle("GET /feed.rss", serve(WriteRSS, "application/rss+xml; charset=utf-8"))
+	return mux
+}
+
+// Audience returns the visibility level a request may see: internal with
+// the token (or when none is set), public otherwise.
+func Audience(r *http.Request, token string) string {
+	if token == "" {
+		return visibility.Internal
+	}
+	given := r.URL.Query().Get("token")
+	if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		given = strings.TrimSpace(auth)
+	}
+	if subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+		return visibility.Internal
+	}
+	return visibility.Public
+}
+
+func parseQuery(r *http.Request, now time.Time) (Options, error) {
+	q := r.URL.Query()
+	opts := Options{Labels: q["label"], Since: now.Add(-DefaultWindow), Limit: 100}
+	if since := q.Get("since"); since != "" {
+		if d, err := time.ParseDuration(since); err == nil {
+			opts.Since = now.Add(-d)
+		} else if days, err := strconv.Atoi(strings.TrimSuffix(since, "d")); err == nil && strings.HasSuffix(since, "d") {
+			opts.Since = now.AddDate(0, 0, -days)
+		} else if t, err := time.Parse(time.RFC3339, since); err == nil {
+			opts.Since = t
+		} else {
+			return opts, fmt.Errorf("invalid since %q: use a duration (48h, 7d) or an RFC3339 time", since)
+		}
+	}
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("invalid limit %q", limit)
+		}
+		opts.Limit = n
+	}
+	return opts, nil
+}
@@ -0,0 +1,148 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func newTestStore(t *testing.T) *memory.MemoryStorage {
+	t.Helper()
+	ctx := context.Background()
+	s := memory.New("")
+	for _, issue := range []*types.Issue{
+		{ID: "bd-1", Title: "Login page", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "bd-2", Title: "API keys", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	} {
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddLabel(ctx, "bd-1", "team:web", "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddIssueComment(ctx, "bd-1", "ana", "Looks good"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseIssue(ctx, "bd-2", "shipped", "tester", ""); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestCollect(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	items, err := Collect(ctx, s, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := map[string]int{}
+	for i, it := range items {
+		kinds[it.Kind]++
+		if i > 0 && it.Time.After(items[i-1].Time) {
+			t.Errorf("items not newest first: %v after %v", it.Time, items[i-1].Time)
+		}
+	}
+	if kinds[KindCreated] != 2 || kinds[KindClosed] != 1 || kinds[KindCommented] != 1 {
+		t.Errorf("kinds = %v", kinds)
+	}
+
+	items, err = Collect(ctx, s, Options{Labels: []string{"team:web"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, it := range items {
+		if it.IssueID != "bd-1" {
+			t.Errorf("label filter let through %s", it.IssueID)
+		}
+	}
+
+	items, _ = Collect(ctx, s, Options{Since: time.Now().Add(time.Hour)})
+	if len(items) != 0 {
+		t.Errorf("future since returned %d items", len(items))
+	}
+	items, _ = Collect(ctx, s, Options{Limit: 1})
+	if len(items) != 1 {
+		t.Errorf("limit 1 returned %d items", len(items))
+	}
+}
+
+func TestHandlerToken(t *testing.T) {
+	srv := httptest.NewServer(Handler(newTestStore(t), "bd", "s3cret"))
+	defer srv.Close()
+
+	entries := func(header, query string) int {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/feed.atom"+query, nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var feed atomFeed
+		if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+			t.Fatal(err)
+		}
+		return len(feed.Entries)
+	}
+	// The test issues are internal: only requests with the token see them
+	if n := entries("", ""); n != 0 {
+		t.Errorf("anonymous request got %d entries, want 0", n)
+	}
+	if n := entries("Bearer wrong", ""); n != 0 {
+		t.Errorf("wrong token got %d entries, want 0", n)
+	}
+	if n := entries("Bearer s3cret", ""); n == 0 {
+		t.Error("bearer token got no entries")
+	}
+	if n := entries("", "?token=s3cret"); n == 0 {
+		t.Error("token parameter got no entries")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	srv := httptest.NewServer(Handler(newTestStore(t), "bd", ""))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/feed.atom?label=team:web&since=2d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/atom+xml") {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	var feed atomFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		t.Fatal(err)
+	}
+	if feed.Title != "bd activity" || len(feed.Entries) != 2 {
+		t.Errorf("feed = %+v", feed)
+	}
+	for _, e := range feed.Entries {
+		if !strings.Contains(e.Title, "bd-1") || len(e.Categories) != 1 || e.Categories[0].Term != "team:web" {
+			t.Errorf("entry = %+v", e)
+		}
+	}
+
+	resp, err = http.Get(srv.URL + "/feed.rss?since=yesterday")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad since: status %d, want 400", resp.StatusCode)
+	}
+}
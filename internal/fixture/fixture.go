@@ -0,0 +1,417 @@
+// Package fixture exports and loads deterministic seed datasets.
+//
+// A fixture is a JSONL file in the same format as issues.jsonl, but scrubbed
+// so that exporting the same logical data always produces the same bytes:
+// issues are renumbered to sequential IDs (<prefix>-1, <prefix>-2, ...) and
+// timestamps are replaced with fixed offsets from Epoch that keep the
+// original ordering. Fixtures are meant for tests and demos.
+package fixture
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/importer"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// Epoch is the time scrubbed timestamps are anchored to.
+var Epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Actor is recorded as the actor for everything a fixture load writes.
+const Actor = "fixture"
+
+// ExportOptions controls how a fixture is built.
+type ExportOptions struct {
+	// KeepIDs preserves the original issue IDs instead of renumbering.
+	KeepIDs bool
+	// Prefix overrides the prefix used for renumbered IDs (default: the
+	// database's issue_prefix).
+	Prefix string
+}
+
+// LoadOptions controls how a fixture is loaded.
+type LoadOptions struct {
+	// Rebase shifts every timestamp so the newest one lands on this time,
+	// making ages and due dates relative to today. Zero leaves them anchored
+	// to Epoch.
+	Rebase time.Time
+}
+
+// idTokenRe matches tokens that might be issue IDs in free text.
+var idTokenRe = regexp.MustCompile(`[A-Za-z0-9][A-Za-z0-9_.-]*`)
+
+// Export builds a scrubbed, deterministic copy of every live issue in store,
+// with labels, dependencies and comments attached. Tombstones and ephemeral
+// issues are left out.
+func Export(ctx context.Context, store storage.Storage, opts ExportOptions) ([]*types.Issue, error) {
+	all, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues: %w", err)
+	}
+	var issues []*types.Issue
+	for _, issue := range all {
+		if issue.Ephemeral || issue.Status == types.StatusTombstone {
+			continue
+		}
+		// Search results omit some fields (due/defer); load the full issue.
+		full, err := store.GetIssue(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get issue %s: %w", issue.ID, err)
+		}
+		issues = append(issues, full)
+	}
+
+	// Parents before children, then creation order.
+	sort.SliceStable(issues, func(i, j int) bool {
+		di, dj := importer.GetHierarchyDepth(issues[i].ID), importer.GetHierarchyDepth(issues[j].ID)
+		if di != dj {
+			return di < dj
+		}
+		if !issues[i].CreatedAt.Equal(issues[j].CreatedAt) {
+			return issues[i].CreatedAt.Before(issues[j].CreatedAt)
+		}
+		return issues[i].ID < issues[j].ID
+	})
+
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	allDeps, err := store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	labels, err := store.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	comments, err := store.GetCommentsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	idMap := make(map[string]string, len(issues))
+	if opts.KeepIDs {
+		for _, id := range ids {
+			idMap[id] = id
+		}
+	} else {
+		prefix := opts.Prefix
+		if prefix == "" {
+			prefix, err = store.GetConfig(ctx, "issue_prefix")
+			if err != nil || prefix == "" {
+				return nil, fmt.Errorf("no issue_prefix configured (pass a prefix)")
+			}
+		}
+		next := 1
+		for _, id := range ids {
+			if ok, parent := sqlite.IsHierarchicalID(id); ok {
+				if newParent, known := idMap[parent]; known {
+					idMap[id] = newParent + id[len(parent):]
+					continue
+				}
+			}
+			idMap[id] = fmt.Sprintf("%s-%d", prefix, next)
+			next++
+		}
+	}
+
+	// Creation order determines the scrubbed timestamps.
+	order := make([]*types.Issue, len(issues))
+	copy(order, issues)
+	sort.SliceStable(order, func(i, j int) bool {
+		if !order[i].CreatedAt.Equal(order[j].CreatedAt) {
+			return order[i].CreatedAt.Before(order[j].CreatedAt)
+		}
+		return order[i].ID < order[j].ID
+	})
+	stamps := make(map[string]time.Time, len(order))
+	for i, issue := range order {
+		stamps[issue.ID] = Epoch.Add(time.Duration(i) * time.Minute)
+	}
+
+	out := make([]*types.Issue, 0, len(issues))
+	for _, issue := range issues {
+		stamp := stamps[issue.ID]
+		scrubbed := *issue
+		scrubbed.ID = idMap[issue.ID]
+		scrubbed.ContentHash = ""
+		scrubbed.CreatedAt = stamp
+		scrubbed.UpdatedAt = stamp
+		scrubbed.ClosedBySession = ""
+		scrubbed.ClosedAt = nil
+		if issue.Status == types.StatusClosed {
+			scrubbed.ClosedAt = &stamp
+		}
+		scrubbed.LastActivity = nil
+		scrubbed.CompactedAt = nil
+		scrubbed.CompactedAtCommit = nil
+		scrubbed.DueAt = scrubDate(issue.DueAt, issue.CreatedAt, stamp)
+		scrubbed.DeferUntil = scrubDate(issue.DeferUntil, issue.CreatedAt, stamp)
+		scrubbed.Title = remapText(issue.Title, idMap)
+		scrubbed.Description = remapText(issue.Description, idMap)
+		scrubbed.Design = remapText(issue.Design, idMap)
+		scrubbed.AcceptanceCriteria = remapText(issue.AcceptanceCriteria, idMap)
+		scrubbed.Notes = remapText(issue.Notes, idMap)
+
+		scrubbed.Labels = append([]string(nil), labels[issue.ID]...)
+		sort.Strings(scrubbed.Labels)
+
+		scrubbed.Dependencies = nil
+		for _, dep := range allDeps[issue.ID] {
+			target, ok := idMap[dep.DependsOnID]
+			if !ok {
+				if strings.HasPrefix(dep.DependsOnID, "external:") {
+					target = dep.DependsOnID
+				} else {
+					continue // Dependency on an issue that isn't in the fixture
+				}
+			}
+			scrubbed.Dependencies = append(scrubbed.Dependencies, &types.Dependency{
+				IssueID:     scrubbed.ID,
+				DependsOnID: target,
+				Type:        dep.Type,
+				CreatedAt:   stamp,
+				Metadata:    dep.Metadata,
+			})
+		}
+		sort.Slice(scrubbed.Dependencies, func(i, j int) bool {
+			a, b := scrubbed.Dependencies[i], scrubbed.Dependencies[j]
+			if a.DependsOnID != b.DependsOnID {
+				return a.DependsOnID < b.DependsOnID
+			}
+			return a.Type < b.Type
+		})
+
+		scrubbed.Comments = nil
+		for i, c := range comments[issue.ID] {
+			scrubbed.Comments = append(scrubbed.Comments, &types.Comment{
+				ID:        int64(i + 1),
+				IssueID:   scrubbed.ID,
+				Author:    c.Author,
+				Text:      remapText(c.Text, idMap),
+				CreatedAt: stamp.Add(time.Duration(i+1) * time.Second),
+			})
+		}
+		out = append(out, &scrubbed)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return lessID(out[i].ID, out[j].ID) })
+	return out, nil
+}
+
+// scrubDate keeps a due/defer date the same number of whole days after the
+// issue's (scrubbed) creation time.
+func scrubDate(t *time.Time, created, stamp time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	days := int(t.Sub(created).Round(24*time.Hour) / (24 * time.Hour))
+	d := stamp.AddDate(0, 0, days)
+	return &d
+}
+
+// remapText rewrites issue IDs mentioned in text.
+func remapText(text string, idMap map[string]string) string {
+	if text == "" {
+		return text
+	}
+	return idTokenRe.ReplaceAllStringFunc(text, func(tok string) string {
+		if id, ok := idMap[tok]; ok {
+			return id
+		}
+		// Allow trailing punctuation: "see bd-abc."
+		trimmed := strings.TrimRight(tok, ".-_")
+		if id, ok := idMap[trimmed]; ok {
+			return id + tok[len(trimmed):]
+		}
+		return tok
+	})
+}
+
+// lessID orders IDs with numeric segments compared numerically, so bd-2
+// sorts before bd-10 and bd-1.2 before bd-1.10.
+func lessID(a, b string) bool {
+	as, bs := splitID(a), splitID(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		var an, bn int
+		_, aerr := fmt.Sscanf(as[i], "%d", &an)
+		_, berr := fmt.Sscanf(bs[i], "%d", &bn)
+		if aerr == nil && berr == nil && an != bn {
+			return an < bn
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
+
+func splitID(id string) []string {
+	return strings.FieldsFunc(id, func(r rune) bool { return r == '-' || r == '.' })
+}
+
+// Write encodes a fixture as JSONL.
+func Write(w io.Writer, issues []*types.Issue) error {
+	enc := json.NewEncoder(w)
+	for _, issue := range issues {
+		if err := enc.Encode(issue); err != nil {
+			return fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
+		}
+	}
+	return nil
+}
+
+// Read decodes a JSONL fixture.
+func Read(r io.Reader) ([]*types.Issue, error) {
+	var issues []*types.Issue
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var issue types.Issue
+		if err := json.Unmarshal([]byte(text), &issue); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		issues = append(issues, &issue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// Load creates the fixture's issues in store, keeping their IDs. If store has
+// no issue_prefix yet, it is taken from the fixture. Loading fails if any
+// issue already exists.
+func Load(ctx context.Context, store storage.Storage, issues []*types.Issue, opts LoadOptions) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	prefix, err := store.GetConfig(ctx, "issue_prefix")
+	if err != nil {
+		return fmt.Errorf("failed to get issue_prefix: %w", err)
+	}
+	if prefix == "" {
+		prefix = utils.ExtractIssuePrefix(issues[0].ID)
+		if prefix == "" {
+			return fmt.Errorf("cannot determine prefix from %q", issues[0].ID)
+		}
+		if err := store.SetConfig(ctx, "issue_prefix", prefix); err != nil {
+			return fmt.Errorf("failed to set issue_prefix: %w", err)
+		}
+	}
+
+	if !opts.Rebase.IsZero() {
+		rebase(issues, opts.Rebase)
+	}
+
+	ordered := make([]*types.Issue, len(issues))
+	copy(ordered, issues)
+	importer.SortByDepth(ordered)
+
+	err = store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		for _, issue := range ordered {
+			create := *issue
+			create.Labels, create.Dependencies, create.Comments = nil, nil, nil
+			if err := tx.CreateIssue(ctx, &create, Actor); err != nil {
+				return fmt.Errorf("failed to create %s: %w", issue.ID, err)
+			}
+		}
+		for _, issue := range ordered {
+			for _, label := range issue.Labels {
+				if err := tx.AddLabel(ctx, issue.ID, label, Actor); err != nil {
+					return fmt.Errorf("failed to add label %s to %s: %w", label, issue.ID, err)
+				}
+			}
+			for _, dep := range issue.Dependencies {
+				if err := tx.AddDependency(ctx, &types.Dependency{
+					IssueID:     issue.ID,
+					DependsOnID: dep.DependsOnID,
+					Type:        dep.Type,
+					Metadata:    dep.Metadata,
+				}, Actor); err != nil {
+					return fmt.Errorf("failed to add dependency %s -> %s: %w", issue.ID, dep.DependsOnID, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Comments go through the storage directly so their timestamps survive
+	// (transactions only support AddComment events).
+	for _, issue := range ordered {
+		for _, c := range issue.Comments {
+			if err := importComment(ctx, store, issue.ID, c); err != nil {
+				return fmt.Errorf("failed to add comment to %s: %w", issue.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// commentImporter is implemented by storage backends that can preserve
+// comment timestamps (SQLite).
+type commentImporter interface {
+	ImportIssueComment(ctx context.Context, issueID, author, text string, createdAt string) (*types.Comment, error)
+}
+
+func importComment(ctx context.Context, store storage.Storage, issueID string, c *types.Comment) error {
+	if ci, ok := store.(commentImporter); ok {
+		_, err := ci.ImportIssueComment(ctx, issueID, c.Author, c.Text, c.CreatedAt.UTC().Format(time.RFC3339))
+		return err
+	}
+	_, err := store.AddIssueComment(ctx, issueID, c.Author, c.Text)
+	return err
+}
+
+// rebase shifts every timestamp in issues so the newest lands on now.
+func rebase(issues []*types.Issue, now time.Time) {
+	var newest time.Time
+	for _, issue := range issues {
+		if issue.UpdatedAt.After(newest) {
+			newest = issue.UpdatedAt
+		}
+		for _, c := range issue.Comments {
+			if c.CreatedAt.After(newest) {
+				newest = c.CreatedAt
+			}
+		}
+	}
+	shift := now.Sub(newest)
+	move := func(t *time.Time) {
+		if t != nil && !t.IsZero() {
+			*t = t.Add(shift)
+		}
+	}
+	for _, issue := range issues {
+		move(&issue.CreatedAt)
+		move(&issue.UpdatedAt)
+		move(issue.ClosedAt)
+		move(issue.DueAt)
+		move(issue.DeferUntil)
+		for _, c := range issue.Comments {
+			move(&c.CreatedAt)
+		}
+	}
+}
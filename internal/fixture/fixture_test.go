@@ -0,0 +1,128 @@
+package fixture
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func newStore(t *testing.T, prefix string) *sqlite.SQLiteStorage {
+	t.Helper()
+	ctx := context.Background()
+	s, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	if prefix != "" {
+		if err := s.SetConfig(ctx, "issue_prefix", prefix); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestExportLoadRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := newStore(t, "src")
+
+	due := time.Now().AddDate(0, 0, 3)
+	epic := &types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	task := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, DueAt: &due}
+	done := &types.Issue{Title: "Done", Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{epic, task, done} {
+		if err := src.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * time.Millisecond) // Distinct created_at for ordering
+	}
+	if err := src.UpdateIssue(ctx, task.ID, map[string]interface{}{"description": "Follows up " + done.ID + "."}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AddDependency(ctx, &types.Dependency{IssueID: task.ID, DependsOnID: epic.ID, Type: types.DepParentChild}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AddLabel(ctx, task.ID, "backend", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.AddIssueComment(ctx, task.ID, "alice", "Started"); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Export(ctx, src, ExportOptions{Prefix: "fx"})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if len(issues) != 3 || issues[0].ID != "fx-1" || issues[1].ID != "fx-2" || issues[2].ID != "fx-3" {
+		t.Fatalf("unexpected IDs: %v", issues)
+	}
+	got := issues[1]
+	if got.Title != "Task" || got.Description != "Follows up fx-3." {
+		t.Errorf("unexpected task: %q %q", got.Title, got.Description)
+	}
+	if !got.CreatedAt.Equal(Epoch.Add(time.Minute)) {
+		t.Errorf("CreatedAt = %v", got.CreatedAt)
+	}
+	if got.DueAt == nil || !got.DueAt.Equal(got.CreatedAt.AddDate(0, 0, 3)) {
+		t.Errorf("DueAt = %v", got.DueAt)
+	}
+	if len(got.Dependencies) != 1 || got.Dependencies[0].DependsOnID != "fx-1" {
+		t.Errorf("Dependencies = %+v", got.Dependencies)
+	}
+	var first bytes.Buffer
+	if err := Write(&first, issues); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loading into a fresh database and exporting again is byte-identical.
+	dst := newStore(t, "")
+	read, err := Read(bytes.NewReader(first.Bytes()))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if err := Load(ctx, dst, read, LoadOptions{}); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	again, err := Export(ctx, dst, ExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var second bytes.Buffer
+	if err := Write(&second, again); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("round trip differs:\n%s\n---\n%s", first.String(), second.String())
+	}
+}
+
+func TestLoadRebase(t *testing.T) {
+	ctx := context.Background()
+	s := newStore(t, "")
+	due := Epoch.AddDate(0, 0, 2)
+	issues := []*types.Issue{
+		{ID: "fx-1", Title: "A", Status: types.StatusOpen, IssueType: types.TypeTask, CreatedAt: Epoch, UpdatedAt: Epoch, DueAt: &due},
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := Load(ctx, s, issues, LoadOptions{Rebase: now}); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	got, err := s.GetIssue(ctx, "fx-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if !got.CreatedAt.Equal(now) || got.DueAt == nil || !got.DueAt.Equal(now.AddDate(0, 0, 2)) {
+		t.Errorf("CreatedAt = %v, DueAt = %v", got.CreatedAt, got.DueAt)
+	}
+}
+
+func TestLessID(t *testing.T) {
+	if !lessID("bd-2", "bd-10") || lessID("bd-10", "bd-2") || !lessID("bd-1.2", "bd-1.10") || !lessID("bd-1", "bd-1.1") {
+		t.Error("lessID ordering is wrong")
+	}
+}
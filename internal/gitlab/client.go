@@ -0,0 +1,171 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/httpx"
+)
+
+// NewClient creates a client for project on the GitLab instance at baseURL
+// (gitlab.com if empty).
+func NewClient(baseURL, token, project string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Token:      token,
+		Project:    project,
+		HTTPClient: httpx.NewClient(DefaultTimeout),
+	}
+}
+
+// projectURL returns the API URL for a path under the project.
+func (c *Client) projectURL(path string) string {
+	return c.BaseURL + "/api/v4/projects/" + url.PathEscape(c.Project) + path
+}
+
+// do sends a request and decodes the JSON response into out, returning
+// the response headers (for pagination). Retries and rate limiting are
+// handled by the httpx transport.
+func (c *Client) do(ctx context.Context, method, rawURL string, body interface{}, out interface{}) (http.Header, error) {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("API error: %s (status %d)", strings.TrimSpace(string(respBody)), resp.StatusCode)
+	}
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// listQuery builds the query for a paginated list request.
+func listQuery(state string, since *time.Time, page int) url.Values {
+	q := url.Values{}
+	q.Set("per_page", strconv.Itoa(PageSize))
+	q.Set("page", strconv.Itoa(page))
+	q.Set("order_by", "updated_at")
+	q.Set("sort", "asc")
+	if state != "" && state != "all" {
+		q.Set("state", state)
+	}
+	if since != nil {
+		q.Set("updated_after", since.UTC().Format(time.RFC3339))
+	}
+	return q
+}
+
+// FetchIssues returns the project's issues in state ("opened", "closed" or
+// "all"), optionally only those updated after since.
+func (c *Client) FetchIssues(ctx context.Context, state string, since *time.Time) ([]Issue, error) {
+	var all []Issue
+	for page := 1; page > 0; {
+		var issues []Issue
+		header, err := c.do(ctx, http.MethodGet, c.projectURL("/issues")+"?"+listQuery(state, since, page).Encode(), nil, &issues)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch issues: %w", err)
+		}
+		all = append(all, issues...)
+		page = nextPage(header)
+	}
+	return all, nil
+}
+
+// FetchMergeRequests returns the project's merge requests in state
+// ("opened", "merged", "closed" or "all"), optionally only those updated
+// after since.
+func (c *Client) FetchMergeRequests(ctx context.Context, state string, since *time.Time) ([]MergeRequest, error) {
+	var all []MergeRequest
+	for page := 1; page > 0; {
+		var mrs []MergeRequest
+		header, err := c.do(ctx, http.MethodGet, c.projectURL("/merge_requests")+"?"+listQuery(state, since, page).Encode(), nil, &mrs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch merge requests: %w", err)
+		}
+		all = append(all, mrs...)
+		page = nextPage(header)
+	}
+	return all, nil
+}
+
+// nextPage returns the X-Next-Page header, or 0 on the last page.
+func nextPage(header http.Header) int {
+	next, err := strconv.Atoi(header.Get("X-Next-Page"))
+	if err != nil {
+		return 0
+	}
+	return next
+}
+
+// CreateIssue creates an issue in the project.
+func (c *Client) CreateIssue(ctx context.Context, title, description string, labels []string) (*Issue, error) {
+	body := map[string]interface{}{
+		"title":       title,
+		"description": description,
+		"labels":      strings.Join(labels, ","),
+	}
+	var issue Issue
+	if _, err := c.do(ctx, http.MethodPost, c.projectURL("/issues"), body, &issue); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	return &issue, nil
+}
+
+// UpdateIssue updates issue iid. Fields follow the GitLab edit-issue API,
+// e.g. title, description, labels (comma-separated) and state_event
+// ("close" or "reopen").
+func (c *Client) UpdateIssue(ctx context.Context, iid int, fields map[string]interface{}) (*Issue, error) {
+	var issue Issue
+	if _, err := c.do(ctx, http.MethodPut, c.projectURL("/issues/"+strconv.Itoa(iid)), fields, &issue); err != nil {
+		return nil, fmt.Errorf("failed to update issue #%d: %w", iid, err)
+	}
+	return &issue, nil
+}
+
+// FetchIssue returns issue iid, or nil if it doesn't exist.
+func (c *Client) FetchIssue(ctx context.Context, iid int) (*Issue, error) {
+	var issue Issue
+	if _, err := c.do(ctx, http.MethodGet, c.projectURL("/issues/"+strconv.Itoa(iid)), nil, &issue); err != nil {
+		if strings.Contains(err.Error(), "status 404") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch issue #%d: %w", iid, err)
+	}
+	return &issue, nil
+}
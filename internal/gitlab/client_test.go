@@ -0,0 +1,48 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchIssuesFollowsPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/issues" {
+			t.Errorf("path = %s", r.URL.EscapedPath())
+		}
+		if r.URL.Query().Get("state") != "opened" {
+			t.Errorf("state = %q, want opened", r.URL.Query().Get("state"))
+		}
+		page := r.URL.Query().Get("page")
+		if page == "1" {
+			w.Header().Set("X-Next-Page", "2")
+		}
+		_ = json.NewEncoder(w).Encode([]Issue{{Title: "page " + page}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "token", "group/project")
+	issues, err := client.FetchIssues(context.Background(), "opened", nil)
+	if err != nil {
+		t.Fatalf("FetchIssues: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Title != "page 1" || issues[1].Title != "page 2" {
+		t.Errorf("issues = %+v, want pages 1 and 2", issues)
+	}
+}
+
+func TestFetchIssueNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"404 Not found"}`))
+	}))
+	defer server.Close()
+
+	issue, err := NewClient(server.URL, "token", "1").FetchIssue(context.Background(), 9)
+	if err != nil || issue != nil {
+		t.Errorf("FetchIssue = %v, %v; want nil, nil", issue, err)
+	}
+}
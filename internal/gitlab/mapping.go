@@ -0,0 +1,143 @@
+package gitlab
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Scoped labels carry the beads fields GitLab has no native field for.
+// GitLab treats "key::value" labels as mutually exclusive per key.
+const (
+	priorityLabelPrefix = "priority::"
+	typeLabelPrefix     = "type::"
+	statusLabelPrefix   = "status::"
+)
+
+// issueRefPattern matches GitLab issue URLs and captures the issue number.
+var issueRefPattern = regexp.MustCompile(`/-/issues/(\d+)(?:[/?#]|$)`)
+
+// IsGitLabExternalRef reports whether externalRef is a GitLab issue URL.
+func IsGitLabExternalRef(externalRef string) bool {
+	return issueRefPattern.MatchString(externalRef)
+}
+
+// ExtractIID returns the issue number from a GitLab issue URL, or 0.
+func ExtractIID(externalRef string) int {
+	m := issueRefPattern.FindStringSubmatch(externalRef)
+	if m == nil {
+		return 0
+	}
+	iid, _ := strconv.Atoi(m[1])
+	return iid
+}
+
+// IssueToBeads converts a GitLab issue to a beads issue. Scoped labels
+// priority::N, type::X and status::in_progress/blocked set those fields and
+// are not kept as labels; all other labels are.
+func IssueToBeads(gi *Issue) *types.Issue {
+	issue := &types.Issue{
+		Title:       gi.Title,
+		Description: gi.Description,
+		Status:      types.StatusOpen,
+		Priority:    2,
+		IssueType:   types.TypeTask,
+		CreatedAt:   gi.CreatedAt,
+		UpdatedAt:   gi.UpdatedAt,
+	}
+
+	for _, label := range gi.Labels {
+		lower := strings.ToLower(label)
+		switch {
+		case strings.HasPrefix(lower, priorityLabelPrefix):
+			if p, err := strconv.Atoi(strings.TrimPrefix(lower, priorityLabelPrefix)); err == nil && p >= 0 && p <= 4 {
+				issue.Priority = p
+			}
+		case strings.HasPrefix(lower, typeLabelPrefix):
+			issue.IssueType = types.IssueType(strings.TrimPrefix(lower, typeLabelPrefix))
+		case strings.HasPrefix(lower, statusLabelPrefix):
+			issue.Status = types.Status(strings.ReplaceAll(strings.TrimPrefix(lower, statusLabelPrefix), " ", "_"))
+		default:
+			issue.Labels = append(issue.Labels, label)
+		}
+	}
+	if !issue.IssueType.IsValid() {
+		issue.IssueType = types.TypeTask
+	}
+	if !issue.Status.IsValid() {
+		issue.Status = types.StatusOpen
+	}
+
+	if gi.State == "closed" {
+		issue.Status = types.StatusClosed
+		closedAt := gi.UpdatedAt
+		if gi.ClosedAt != nil {
+			closedAt = *gi.ClosedAt
+		}
+		issue.ClosedAt = &closedAt
+	}
+
+	if len(gi.Assignees) > 0 {
+		issue.Assignee = gi.Assignees[0].Username
+	}
+
+	externalRef := gi.WebURL
+	issue.ExternalRef = &externalRef
+	return issue
+}
+
+// LabelsForGitLab returns the GitLab labels for a beads issue: its own
+// labels plus scoped labels for priority, type and any open status other
+// than plain open.
+func LabelsForGitLab(issue *types.Issue, labels []string) []string {
+	out := append([]string{}, labels...)
+	out = append(out,
+		priorityLabelPrefix+strconv.Itoa(issue.Priority),
+		typeLabelPrefix+string(issue.IssueType))
+	if issue.Status != types.StatusOpen && issue.Status != types.StatusClosed {
+		out = append(out, statusLabelPrefix+string(issue.Status))
+	}
+	return out
+}
+
+// StateEvent returns the GitLab state_event that moves an issue to match a
+// beads status.
+func StateEvent(status types.Status) string {
+	if status == types.StatusClosed {
+		return "close"
+	}
+	return "reopen"
+}
+
+// MentionedIDs returns the beads issue IDs with prefix mentioned in text,
+// in order of first mention.
+func MentionedIDs(text, prefix string) []string {
+	pattern := regexp.MustCompile(`(?i)(?:^|[^\w-])(` + regexp.QuoteMeta(prefix) + `-[0-9a-z]+(?:\.\d+)*)\b`)
+	seen := make(map[string]bool)
+	var ids []string
+	for _, m := range pattern.FindAllStringSubmatch(text, -1) {
+		id := strings.ToLower(m[1])
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// closingKeywordPattern matches GitLab-style closing keywords ("Closes",
+// "fixes", "Resolved" ...) followed by the references they close.
+var closingKeywordPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|closing|fix(?:e[sd])?|fixing|resolve[sd]?|resolving|implement(?:s|ed)?|implementing)\b:?\s+((?:[\w.-]+(?:\s*,\s*|\s+and\s+)?)+)`)
+
+// ClosingIDs returns the beads issue IDs a merge request closes when it
+// merges: those named in its title or source branch, and those following
+// a closing keyword ("Closes bd-12") in its description.
+func ClosingIDs(mr *MergeRequest, prefix string) []string {
+	text := mr.Title + "\n" + mr.SourceBranch
+	for _, m := range closingKeywordPattern.FindAllStringSubmatch(mr.Description, -1) {
+		text += "\n" + m[1]
+	}
+	return MentionedIDs(text, prefix)
+}
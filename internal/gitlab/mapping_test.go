@@ -0,0 +1,62 @@
+package gitlab
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestExtractIID(t *testing.T) {
+	tests := map[string]int{
+		"https://gitlab.com/g/p/-/issues/12":          12,
+		"https://git.example.com/a/b/c/-/issues/3#n1": 3,
+		"https://gitlab.com/g/p/-/merge_requests/4":   0,
+		"gh-12": 0,
+	}
+	for ref, want := range tests {
+		if got := ExtractIID(ref); got != want {
+			t.Errorf("ExtractIID(%q) = %d, want %d", ref, got, want)
+		}
+	}
+}
+
+func TestLabelsForGitLabRoundTrip(t *testing.T) {
+	issue := &types.Issue{Priority: 0, IssueType: types.TypeFeature, Status: types.StatusBlocked}
+	labels := LabelsForGitLab(issue, []string{"ui"})
+	want := []string{"ui", "priority::0", "type::feature", "status::blocked"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Fatalf("LabelsForGitLab = %v, want %v", labels, want)
+	}
+
+	back := IssueToBeads(&Issue{Title: "x", State: "opened", Labels: labels, WebURL: "https://gitlab.com/g/p/-/issues/1"})
+	if back.Priority != 0 || back.IssueType != types.TypeFeature || back.Status != types.StatusBlocked {
+		t.Errorf("IssueToBeads = priority %d, type %s, status %s", back.Priority, back.IssueType, back.Status)
+	}
+	if !reflect.DeepEqual(back.Labels, []string{"ui"}) {
+		t.Errorf("labels = %v, want [ui]", back.Labels)
+	}
+
+	closed := IssueToBeads(&Issue{Title: "x", State: "closed", Labels: []string{"status::in_progress"}})
+	if closed.Status != types.StatusClosed || closed.ClosedAt == nil {
+		t.Errorf("closed GitLab issue = status %s, closed_at %v", closed.Status, closed.ClosedAt)
+	}
+}
+
+func TestMentionedAndClosingIDs(t *testing.T) {
+	mr := &MergeRequest{
+		Title:        "Refactor auth (bd-a1b2)",
+		Description:  "Fixes bd-c3d4 and bd-e5f6.\nSee also bd-0099 and mybd-1111.",
+		SourceBranch: "bd-7788.1-cleanup",
+	}
+	all := MentionedIDs(mr.Title+"\n"+mr.Description+"\n"+mr.SourceBranch, "bd")
+	wantAll := []string{"bd-a1b2", "bd-c3d4", "bd-e5f6", "bd-0099", "bd-7788.1"}
+	if !reflect.DeepEqual(all, wantAll) {
+		t.Errorf("MentionedIDs = %v, want %v", all, wantAll)
+	}
+	closing := ClosingIDs(mr, "bd")
+	wantClosing := []string{"bd-a1b2", "bd-7788.1", "bd-c3d4", "bd-e5f6"}
+	if !reflect.DeepEqual(closing, wantClosing) {
+		t.Errorf("ClosingIDs = %v, want %v", closing, wantClosing)
+	}
+}
@@ -0,0 +1,105 @@
+// Package gitlab provides a client and data mapping for the GitLab REST API.
+//
+// It syncs GitLab project issues with beads and reads merge requests so
+// that MRs mentioning beads IDs can be linked to (and close) those issues.
+// Self-hosted instances are supported by pointing the client at their base
+// URL.
+package gitlab
+
+import (
+	"net/http"
+	"time"
+)
+
+// API configuration constants.
+const (
+	// DefaultBaseURL is the base URL of gitlab.com.
+	DefaultBaseURL = "https://gitlab.com"
+
+	// DefaultTimeout is the default HTTP request timeout.
+	DefaultTimeout = 30 * time.Second
+
+	// PageSize is the number of items fetched per page.
+	PageSize = 100
+)
+
+// Client provides methods to interact with one GitLab project.
+type Client struct {
+	BaseURL    string // Instance URL, e.g. https://gitlab.example.com
+	Token      string // Personal, project or group access token
+	Project    string // Numeric project ID or full path (group/project)
+	HTTPClient *http.Client
+}
+
+// Issue represents a GitLab issue.
+type Issue struct {
+	ID          int        `json:"id"`
+	IID         int        `json:"iid"` // Project-scoped number, e.g. #12
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"` // "opened" or "closed"
+	Labels      []string   `json:"labels"`
+	Assignees   []User     `json:"assignees"`
+	WebURL      string     `json:"web_url"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+// MergeRequest represents a GitLab merge request.
+type MergeRequest struct {
+	IID          int        `json:"iid"` // Project-scoped number, e.g. !7
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	State        string     `json:"state"` // "opened", "closed", "merged" or "locked"
+	SourceBranch string     `json:"source_branch"`
+	WebURL       string     `json:"web_url"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	MergedAt     *time.Time `json:"merged_at"`
+}
+
+// User represents a GitLab user.
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// PullStats tracks pull operation statistics.
+type PullStats struct {
+	Created int
+	Updated int
+	Skipped int
+}
+
+// PushStats tracks push operation statistics.
+type PushStats struct {
+	Created int
+	Updated int
+	Skipped int
+	Errors  int
+}
+
+// SyncResult represents the result of a GitLab sync operation.
+type SyncResult struct {
+	Success  bool     `json:"success"`
+	Pulled   int      `json:"pulled"`
+	Pushed   int      `json:"pushed"`
+	Created  int      `json:"created"`
+	Updated  int      `json:"updated"`
+	Skipped  int      `json:"skipped"`
+	Errors   int      `json:"errors"`
+	LastSync string   `json:"last_sync,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// MRLink records a merge request that mentions a beads issue.
+type MRLink struct {
+	IssueID string `json:"issue_id"`
+	MR      int    `json:"mr"`
+	URL     string `json:"url"`
+	State   string `json:"state"`
+	Linked  bool   `json:"linked"` // the MR URL was newly added as a ref
+	Closed  bool   `json:"closed"` // the issue was closed because the MR merged
+}
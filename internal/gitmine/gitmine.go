@@ -0,0 +1,293 @@
+// Package gitmine reads a repository's commit history and proposes issues
+// from it: issues the commit messages reference, reverted changes, and
+// files that keep needing fixes, for bd import git-log.
+package gitmine
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Proposal kinds
+const (
+	KindReference = "reference" // An issue commit messages refer to
+	KindRevert    = "revert"    // A change that was reverted
+	KindHotspot   = "hotspot"   // A file that keeps needing fixes
+)
+
+// Commit is one commit of the history.
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body,omitempty"`
+	Files   []string  `json:"files,omitempty"`
+}
+
+// Short is the abbreviated hash.
+func (c *Commit) Short() string {
+	if len(c.Hash) > 10 {
+		return c.Hash[:10]
+	}
+	return c.Hash
+}
+
+// Proposal is an issue suggested by the history.
+type Proposal struct {
+	Kind    string    `json:"kind"`
+	Key     string    `json:"key"` // Stable identity, used as the external_ref
+	Title   string    `json:"title"`
+	File    string    `json:"file,omitempty"` // Hotspots
+	Fixed   bool      `json:"fixed"`          // A commit fixed or closed it
+	Fixes   int       `json:"fixes,omitempty"`
+	Commits []*Commit `json:"commits"` // Oldest first
+}
+
+// First is the oldest commit of the proposal.
+func (p *Proposal) First() *Commit { return p.Commits[0] }
+
+// Last is the newest commit of the proposal.
+func (p *Proposal) Last() *Commit { return p.Commits[len(p.Commits)-1] }
+
+// Options tune Mine.
+type Options struct {
+	MinFixes    int                    // Fix commits a file needs to be a hotspot
+	MaxHotspots int                    // Most hotspots proposed, 0 for no limit
+	Exists      func(file string) bool // Whether a file still exists; nil assumes all do
+}
+
+// Separators of the git log format: records and fields
+const (
+	recordSep = "\x1e"
+	fieldSep  = "\x1f"
+)
+
+// Log returns the non-merge commits of the repository at dir since a time,
+// oldest first.
+func Log(ctx context.Context, dir string, since time.Time) ([]*Commit, error) {
+	args := []string{"log", "--no-merges", "--reverse", "--name-only",
+		"--format=" + recordSep + "%H" + fieldSep + "%an" + fieldSep + "%aI" + fieldSep + "%s" + fieldSep + "%b" + fieldSep}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 -- fixed arguments
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return nil, fmt.Errorf("git log: %s", strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	return ParseLog(string(out)), nil
+}
+
+// ParseLog parses the output of the git log format Log uses.
+func ParseLog(out string) []*Commit {
+	var commits []*Commit
+	for _, record := range strings.Split(out, recordSep) {
+		fields := strings.Split(record, fieldSep)
+		if len(fields) < 6 {
+			continue
+		}
+		c := &Commit{
+			Hash:    strings.TrimSpace(fields[0]),
+			Author:  fields[1],
+			Subject: strings.TrimSpace(fields[3]),
+			Body:    strings.TrimSpace(fields[4]),
+		}
+		c.Date, _ = time.Parse(time.RFC3339, fields[2])
+		for _, f := range strings.Split(fields[5], "\n") {
+			if f = strings.TrimSpace(f); f != "" {
+				c.Files = append(c.Files, f)
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+var (
+	// An issue reference: owner/repo#12, #12, GH-12 or a tracker key like PROJ-123
+	refPattern = regexp.MustCompile(`\b([\w.-]+/[\w.-]+)#(\d+)\b|(?:^|[\s(\[,])#(\d+)\b|\b(?i:gh)-(\d+)\b|\b([A-Z][A-Z0-9]{1,9}-\d+)\b`)
+
+	// A closing keyword right before a reference
+	fixKeyword = regexp.MustCompile(`(?i)\b(?:fix(?:e[sd])?|close[sd]?|resolve[sd]?)\s*:?\s*$`)
+
+	// A pull request number GitHub appends to squash-merged subjects
+	prSuffix = regexp.MustCompile(`\s*\(#\d+\)$`)
+
+	revertSubject = regexp.MustCompile(`^Revert "(.+)"$`)
+	revertBody    = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+	reapply       = regexp.MustCompile(`^Reapply "(.+)"$`)
+
+	// Subjects of commits that fix something
+	fixSubject = regexp.MustCompile(`(?i)^(?:fixup|squash|amend)!|^revert\b|\b(?:fix(?:e[sd])?|hotfix|bugfix|bug|regression|broke|crash)\b`)
+)
+
+// notKeys are uppercase prefixes that look like tracker keys but are
+// standards, encodings or identifiers.
+var notKeys = map[string]bool{
+	"UTF": true, "SHA": true, "ISO": true, "CVE": true, "RFC": true, "GHSA": true,
+	"GO": true, "PEP": true, "HTTP": true, "TLS": true, "AES": true, "MD": true,
+	"X": true, "WIP": true,
+}
+
+// IssueRefs returns the issue references in a commit message with whether
+// each is preceded by a closing keyword (fixes #12). Keys are normalized:
+// #12 and GH-12 become gh-12. The pull request number GitHub appends to
+// squash-merged subjects is not an issue reference.
+func IssueRefs(subject, body string) map[string]bool {
+	text := prSuffix.ReplaceAllString(subject, "") + "\n" + body
+	refs := make(map[string]bool)
+	for _, m := range refPattern.FindAllStringSubmatchIndex(text, -1) {
+		var key string
+		switch {
+		case m[2] >= 0:
+			key = text[m[2]:m[3]] + "#" + text[m[4]:m[5]]
+		case m[6] >= 0:
+			key = "gh-" + text[m[6]:m[7]]
+		case m[8] >= 0:
+			key = "gh-" + text[m[8]:m[9]]
+		case m[10] >= 0:
+			key = text[m[10]:m[11]]
+			prefix, _, _ := strings.Cut(key, "-")
+			if notKeys[prefix] {
+				continue
+			}
+		}
+		refs[key] = refs[key] || fixKeyword.MatchString(text[:m[0]])
+	}
+	return refs
+}
+
+// IsFix reports whether a commit fixes something, going by its subject.
+func IsFix(c *Commit) bool {
+	return fixSubject.MatchString(c.Subject)
+}
+
+// Mine proposes issues from commits, oldest first: referenced issues,
+// reverted changes and fix hotspots, in that order.
+func Mine(commits []*Commit, opts Options) []*Proposal {
+	var proposals []*Proposal
+	proposals = append(proposals, references(commits)...)
+	proposals = append(proposals, reverts(commits)...)
+	proposals = append(proposals, hotspots(commits, opts)...)
+	return proposals
+}
+
+// references groups commits by the issues they reference.
+func references(commits []*Commit) []*Proposal {
+	byKey := make(map[string]*Proposal)
+	var order []string
+	for _, c := range commits {
+		for key, fixed := range IssueRefs(c.Subject, c.Body) {
+			p := byKey[key]
+			if p == nil {
+				p = &Proposal{Kind: KindReference, Key: key, Title: prSuffix.ReplaceAllString(c.Subject, "")}
+				byKey[key] = p
+				order = append(order, key)
+			}
+			p.Commits = append(p.Commits, c)
+			p.Fixed = p.Fixed || fixed
+		}
+	}
+	sort.Strings(order)
+	proposals := make([]*Proposal, 0, len(order))
+	for _, key := range order {
+		proposals = append(proposals, byKey[key])
+	}
+	return proposals
+}
+
+// reverts proposes an issue per reverted change. A later commit that
+// reapplies it (same subject, or Reapply "...") marks it fixed.
+func reverts(commits []*Commit) []*Proposal {
+	var proposals []*Proposal
+	for i, c := range commits {
+		m := revertSubject.FindStringSubmatch(c.Subject)
+		if m == nil {
+			continue
+		}
+		original := m[1]
+		if strings.HasPrefix(original, `Revert "`) {
+			continue // Reverting a revert reapplies the change
+		}
+		key := c.Hash
+		if b := revertBody.FindStringSubmatch(c.Body); b != nil {
+			key = b[1]
+		}
+		if len(key) > 12 {
+			key = key[:12]
+		}
+		p := &Proposal{Kind: KindRevert, Key: key, Title: original, Commits: []*Commit{c}}
+		for _, later := range commits[i+1:] {
+			if later.Subject == original || reapplied(later.Subject) == original {
+				p.Fixed = true
+				p.Commits = append(p.Commits, later)
+				break
+			}
+		}
+		proposals = append(proposals, p)
+	}
+	return proposals
+}
+
+// reapplied returns the subject a reapplying commit restores, if any.
+func reapplied(subject string) string {
+	if m := reapply.FindStringSubmatch(subject); m != nil {
+		return m[1]
+	}
+	if m := revertSubject.FindStringSubmatch(subject); m != nil {
+		if inner := revertSubject.FindStringSubmatch(m[1]); inner != nil {
+			return inner[1]
+		}
+	}
+	return ""
+}
+
+// hotspots proposes the files touched by at least MinFixes fix commits,
+// most fixes first.
+func hotspots(commits []*Commit, opts Options) []*Proposal {
+	if opts.MinFixes <= 0 {
+		return nil
+	}
+	byFile := make(map[string]*Proposal)
+	for _, c := range commits {
+		if !IsFix(c) {
+			continue
+		}
+		for _, f := range c.Files {
+			p := byFile[f]
+			if p == nil {
+				p = &Proposal{Kind: KindHotspot, Key: f, Title: f, File: f}
+				byFile[f] = p
+			}
+			p.Fixes++
+			p.Commits = append(p.Commits, c)
+		}
+	}
+	var proposals []*Proposal
+	for f, p := range byFile {
+		if p.Fixes < opts.MinFixes || (opts.Exists != nil && !opts.Exists(f)) {
+			continue
+		}
+		proposals = append(proposals, p)
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		if proposals[i].Fixes != proposals[j].Fixes {
+			return proposals[i].Fixes > proposals[j].Fixes
+		}
+		return proposals[i].File < proposals[j].File
+	})
+	if opts.MaxHotspots > 0 && len(proposals) > opts.MaxHotspots {
+		proposals = proposals[:opts.MaxHotspots]
+	}
+	return proposals
+}
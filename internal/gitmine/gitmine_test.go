@@ -0,0 +1,85 @@
+package gitmine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLog(t *testing.T) {
+	out := "\x1eaaa\x1fAnn\x1f2026-01-02T03:04:05Z\x1fFix crash\x1fLonger\nbody\x1f\n\ncmd/a.go\ncmd/b.go\n" +
+		"\x1ebbb\x1fBob\x1f2026-01-03T00:00:00+01:00\x1fDocs\x1f\x1f\n\nREADME.md\n"
+	commits := ParseLog(out)
+	if len(commits) != 2 {
+		t.Fatalf("ParseLog = %d commits", len(commits))
+	}
+	c := commits[0]
+	if c.Hash != "aaa" || c.Author != "Ann" || c.Subject != "Fix crash" || c.Body != "Longer\nbody" {
+		t.Errorf("commit = %+v", c)
+	}
+	if !reflect.DeepEqual(c.Files, []string{"cmd/a.go", "cmd/b.go"}) {
+		t.Errorf("files = %v", c.Files)
+	}
+	if !c.Date.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("date = %v", c.Date)
+	}
+}
+
+func TestIssueRefs(t *testing.T) {
+	tests := []struct {
+		subject, body string
+		want          map[string]bool
+	}{
+		{"Fix login redirect (fixes #12)", "", map[string]bool{"gh-12": true}},
+		{"Add export (#45)", "", map[string]bool{}},
+		{"Refactor parser, see GH-7", "Closes: PROJ-123", map[string]bool{"gh-7": false, "PROJ-123": true}},
+		{"Bump acme/widgets#3 vendored copy", "", map[string]bool{"acme/widgets#3": false}},
+		{"Switch to UTF-8 and SHA-256", "Addresses CVE-2024-1234", map[string]bool{}},
+		{"Resolve PROJ-9 and mention PROJ-9 again", "", map[string]bool{"PROJ-9": true}},
+	}
+	for _, tt := range tests {
+		got := IssueRefs(tt.subject, tt.body)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("IssueRefs(%q, %q) = %v, want %v", tt.subject, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestMine(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	commits := []*Commit{
+		{Hash: "c1", Date: day(1), Subject: "Add cache layer", Files: []string{"cache.go"}},
+		{Hash: "c2", Date: day(2), Subject: "Fix cache eviction, refs #5", Files: []string{"cache.go"}},
+		{Hash: "c3", Date: day(3), Subject: `Revert "Add cache layer"`, Body: "This reverts commit c1c1c1c1c1c1c1c1.", Files: []string{"cache.go"}},
+		{Hash: "c4", Date: day(4), Subject: "fixup! Fix cache eviction", Files: []string{"cache.go", "gone.go"}},
+		{Hash: "c5", Date: day(5), Subject: "Fix race in cache (fixes #5)", Files: []string{"cache.go", "gone.go"}},
+		{Hash: "c6", Date: day(6), Subject: `Revert "Tweak logging"`, Files: []string{"log.go"}},
+		{Hash: "c7", Date: day(7), Subject: `Reapply "Add cache layer"`, Files: []string{"cache.go"}},
+	}
+	proposals := Mine(commits, Options{MinFixes: 2, Exists: func(f string) bool { return f != "gone.go" }})
+
+	var kinds []string
+	for _, p := range proposals {
+		kinds = append(kinds, p.Kind+":"+p.Key)
+	}
+	want := []string{"reference:gh-5", "revert:c1c1c1c1c1c1", "revert:c6", "hotspot:cache.go"}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("Mine = %v, want %v", kinds, want)
+	}
+
+	ref := proposals[0]
+	if !ref.Fixed || len(ref.Commits) != 2 || ref.Title != "Fix cache eviction, refs #5" {
+		t.Errorf("reference = %+v", ref)
+	}
+	if !proposals[1].Fixed || proposals[1].Title != "Add cache layer" || proposals[1].Last().Hash != "c7" {
+		t.Errorf("reapplied revert = %+v", proposals[1])
+	}
+	if proposals[2].Fixed {
+		t.Errorf("revert c6 marked fixed")
+	}
+	hot := proposals[3]
+	if hot.Fixes != 4 || !strings.HasSuffix(hot.File, "cache.go") {
+		t.Errorf("hotspot = %+v", hot)
+	}
+}
@@ -0,0 +1,183 @@
+// Package goals holds goals (OKRs): issues of type goal whose key results
+// track either a reported metric or the completion of linked epics and
+// issues. Goal progress is the mean progress of its key results.
+package goals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ErrUnsupported is returned for storage backends without key results.
+var ErrUnsupported = errors.New("goals require the SQLite backend")
+
+// Store is the storage interface for key results.
+type Store interface {
+	SetKeyResults(ctx context.Context, issueID string, krs []*types.KeyResult, actor string) error
+	GetKeyResults(ctx context.Context, issueID string) ([]*types.KeyResult, error)
+	GetKeyResultsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.KeyResult, error)
+}
+
+// For returns s as a key result store.
+func For(s storage.Storage) (Store, error) {
+	gs, ok := s.(Store)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return gs, nil
+}
+
+// Populate fills in the KeyResults of issues, as exports and show do.
+// Stores without key results leave issues unchanged.
+func Populate(ctx context.Context, s storage.Storage, issues []*types.Issue) error {
+	gs, err := For(s)
+	if err != nil || len(issues) == 0 {
+		return nil
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	byIssue, err := gs.GetKeyResultsForIssues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get key results: %w", err)
+	}
+	for _, issue := range issues {
+		issue.KeyResults = byIssue[issue.ID]
+	}
+	return nil
+}
+
+// KeyResultProgress is a key result with its progress.
+type KeyResultProgress struct {
+	*types.KeyResult
+	Progress float64 `json:"progress"`         // 0 to 1
+	Closed   int     `json:"closed,omitempty"` // closed issues, for issue key results
+	Total    int     `json:"total,omitempty"`  // counted issues, for issue key results
+}
+
+// Rollup is a goal with its progress.
+type Rollup struct {
+	ID         string              `json:"id"`
+	Title      string              `json:"title"`
+	Status     types.Status        `json:"status"`
+	Assignee   string              `json:"assignee,omitempty"`
+	Progress   float64             `json:"progress"` // 0 to 1
+	KeyResults []KeyResultProgress `json:"key_results"`
+	Closed     int                 `json:"closed,omitempty"` // children closed, for goals without key results
+	Total      int                 `json:"total,omitempty"`
+}
+
+// MetricProgress is how far a metric has gone from start (0 when unset)
+// to target, clamped to [0, 1]. It works for targets below the start, like
+// latencies to reduce.
+func MetricProgress(kr *types.KeyResult) float64 {
+	if kr.Target == nil || kr.Current == nil {
+		return 0
+	}
+	start := 0.0
+	if kr.Start != nil {
+		start = *kr.Start
+	}
+	if *kr.Target == start {
+		if *kr.Current == start {
+			return 1
+		}
+		return 0
+	}
+	return min(max((*kr.Current-start)/(*kr.Target-start), 0), 1)
+}
+
+// Compute rolls up the progress of goals. Their key results must already
+// be populated. Issue key results count the linked issues, or for linked
+// epics and parents their descendants, that are closed.
+func Compute(ctx context.Context, s storage.Storage, goals []*types.Issue) ([]*Rollup, error) {
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	status := make(map[string]types.Status, len(issues))
+	for _, issue := range issues {
+		status[issue.ID] = issue.Status
+	}
+	deps, err := s.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	children := make(map[string][]string)
+	for _, list := range deps {
+		for _, dep := range list {
+			if dep.Type == types.DepParentChild {
+				children[dep.DependsOnID] = append(children[dep.DependsOnID], dep.IssueID)
+			}
+		}
+	}
+
+	// count returns how many of the work items under roots are closed
+	count := func(roots []string) (closed, total int) {
+		seen := make(map[string]bool)
+		var visit func(id string, root bool)
+		visit = func(id string, root bool) {
+			if seen[id] {
+				return
+			}
+			seen[id] = true
+			if kids := children[id]; len(kids) > 0 {
+				for _, kid := range kids {
+					visit(kid, false)
+				}
+				if root {
+					return
+				}
+			}
+			st, ok := status[id]
+			if !ok || st == types.StatusTombstone {
+				return
+			}
+			total++
+			if st == types.StatusClosed {
+				closed++
+			}
+		}
+		for _, id := range roots {
+			visit(id, true)
+		}
+		return closed, total
+	}
+	fraction := func(closed, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(closed) / float64(total)
+	}
+
+	rollups := make([]*Rollup, 0, len(goals))
+	for _, goal := range goals {
+		r := &Rollup{ID: goal.ID, Title: goal.Title, Status: goal.Status, Assignee: goal.Assignee, KeyResults: []KeyResultProgress{}}
+		sum := 0.0
+		for _, kr := range goal.KeyResults {
+			p := KeyResultProgress{KeyResult: kr}
+			if kr.IsMetric() {
+				p.Progress = MetricProgress(kr)
+			} else {
+				p.Closed, p.Total = count(kr.Issues)
+				p.Progress = fraction(p.Closed, p.Total)
+			}
+			sum += p.Progress
+			r.KeyResults = append(r.KeyResults, p)
+		}
+		if len(r.KeyResults) > 0 {
+			r.Progress = sum / float64(len(r.KeyResults))
+		} else {
+			// Without key results, a goal is as far along as its children
+			r.Closed, r.Total = count([]string{goal.ID})
+			r.Progress = fraction(r.Closed, r.Total)
+		}
+		rollups = append(rollups, r)
+	}
+	return rollups, nil
+}
@@ -0,0 +1,76 @@
+package goals
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func ptr(f float64) *float64 { return &f }
+
+func TestMetricProgress(t *testing.T) {
+	for _, tt := range []struct {
+		kr   types.KeyResult
+		want float64
+	}{
+		{types.KeyResult{Target: ptr(100), Current: ptr(25)}, 0.25},
+		{types.KeyResult{Start: ptr(200), Target: ptr(140), Current: ptr(180)}, 1.0 / 3},
+		{types.KeyResult{Start: ptr(200), Target: ptr(140), Current: ptr(120)}, 1},
+		{types.KeyResult{Target: ptr(100)}, 0},
+	} {
+		if got := MetricProgress(&tt.kr); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("MetricProgress(%+v) = %v, want %v", tt.kr, got, tt.want)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New("")
+	for _, issue := range []*types.Issue{
+		{ID: "bd-1", Title: "Cache epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic},
+		{ID: "bd-2", Title: "Design", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+		{ID: "bd-3", Title: "Build", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+		{ID: "bd-4", Title: "Standalone", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+	} {
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, child := range []string{"bd-2", "bd-3"} {
+		if err := s.AddDependency(ctx, &types.Dependency{IssueID: child, DependsOnID: "bd-1", Type: types.DepParentChild}, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.CloseIssue(ctx, "bd-2", "done", "tester", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	goal := &types.Issue{ID: "bd-9", Title: "Faster pages", KeyResults: []*types.KeyResult{
+		{Title: "Ship the cache", Issues: []string{"bd-1", "bd-4"}},
+		{Title: "p95 under 140ms", Start: ptr(200), Target: ptr(140), Current: ptr(140)},
+	}}
+	rollups, err := Compute(ctx, s, []*types.Issue{goal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rollups[0]
+	if kr := r.KeyResults[0]; kr.Closed != 1 || kr.Total != 3 {
+		t.Errorf("issue key result counted %d/%d, want 1/3", kr.Closed, kr.Total)
+	}
+	if want := (1.0/3 + 1) / 2; math.Abs(r.Progress-want) > 1e-9 {
+		t.Errorf("progress = %v, want %v", r.Progress, want)
+	}
+
+	// Without key results, a goal's own children count
+	rollups, err = Compute(ctx, s, []*types.Issue{{ID: "bd-1", Title: "Cache epic"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := rollups[0]; r.Closed != 1 || r.Total != 2 || r.Progress != 0.5 {
+		t.Errorf("rollup without key results = %+v", r)
+	}
+}
@@ -0,0 +1,308 @@
+// Package graphquery evaluates path expressions over the dependency graph,
+// such as "bd-7/descendants[blocks]{..3}". An expression names one or more
+// start issues, then one or more steps; each step moves from the current
+// set of issues along dependency edges:
+//
+//	dependencies   issues the current ones depend on (one hop)
+//	dependents     issues that depend on the current ones (one hop)
+//	ancestors      dependencies, transitively
+//	descendants    dependents, transitively
+//
+// A step may restrict edge types, [blocks,parent-child] ("blocking" means
+// every type that affects ready work), and for ancestors and descendants
+// the depth: {3} is up to 3 hops, {2..4} is 2 to 4 hops and {2..} is 2 or
+// more. Since a child depends on its parent, descendants[parent-child] are
+// an epic's children, grandchildren and so on.
+package graphquery
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Axes
+const (
+	Dependencies = "dependencies"
+	Dependents   = "dependents"
+	Ancestors    = "ancestors"
+	Descendants  = "descendants"
+)
+
+// Step is one move of an expression.
+type Step struct {
+	Axis     string                 `json:"axis"`
+	Types    []types.DependencyType `json:"types,omitempty"` // Empty = any
+	MinDepth int                    `json:"min_depth"`
+	MaxDepth int                    `json:"max_depth"` // 0 = unlimited
+}
+
+// Expr is a parsed path expression.
+type Expr struct {
+	Start []string `json:"start"`
+	Steps []Step   `json:"steps"`
+}
+
+// Parse parses start[,start...]/step[/step...].
+func Parse(src string) (*Expr, error) {
+	parts := strings.Split(strings.TrimSpace(src), "/")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("expression %q needs a start issue and at least one step, e.g. bd-7/descendants", src)
+	}
+	expr := &Expr{}
+	for _, id := range strings.Split(parts[0], ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			expr.Start = append(expr.Start, id)
+		}
+	}
+	if len(expr.Start) == 0 {
+		return nil, fmt.Errorf("expression %q has no start issue", src)
+	}
+	for _, part := range parts[1:] {
+		step, err := parseStep(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		expr.Steps = append(expr.Steps, step)
+	}
+	return expr, nil
+}
+
+func parseStep(src string) (Step, error) {
+	axis := src
+	var filter, depth string
+	if i := strings.IndexAny(src, "[{"); i >= 0 {
+		axis, src = src[:i], src[i:]
+		if strings.HasPrefix(src, "[") {
+			end := strings.Index(src, "]")
+			if end < 0 {
+				return Step{}, fmt.Errorf("step %q: missing ]", axis+src)
+			}
+			filter, src = src[1:end], src[end+1:]
+		}
+		if strings.HasPrefix(src, "{") {
+			if !strings.HasSuffix(src, "}") {
+				return Step{}, fmt.Errorf("step %q: missing }", axis+src)
+			}
+			depth, src = src[1:len(src)-1], ""
+		}
+		if src != "" {
+			return Step{}, fmt.Errorf("step %q: unexpected %q", axis, src)
+		}
+	}
+
+	step := Step{Axis: strings.ToLower(strings.TrimSpace(axis)), MinDepth: 1}
+	switch step.Axis {
+	case Dependencies, Dependents:
+		step.MaxDepth = 1
+		if depth != "" {
+			return step, fmt.Errorf("step %s: one-hop steps take no depth; use %s", step.Axis, transitive(step.Axis))
+		}
+	case Ancestors, Descendants:
+		if depth != "" {
+			if err := parseDepth(depth, &step); err != nil {
+				return step, fmt.Errorf("step %s: %w", step.Axis, err)
+			}
+		}
+	default:
+		return step, fmt.Errorf("unknown step %q (want dependencies, dependents, ancestors or descendants)", axis)
+	}
+
+	for _, t := range strings.Split(filter, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		switch {
+		case t == "":
+		case t == "blocking":
+			step.Types = append(step.Types, types.DepBlocks, types.DepParentChild, types.DepConditionalBlocks, types.DepWaitsFor)
+		case types.DependencyType(t).IsValid():
+			step.Types = append(step.Types, types.DependencyType(t))
+		default:
+			return step, fmt.Errorf("step %s: invalid edge type %q", step.Axis, t)
+		}
+	}
+	return step, nil
+}
+
+func transitive(axis string) string {
+	if axis == Dependencies {
+		return Ancestors + "{n}"
+	}
+	return Descendants + "{n}"
+}
+
+// parseDepth parses n, m..n, m.. or ..n.
+func parseDepth(src string, step *Step) error {
+	lo, hi, ranged := strings.Cut(strings.ReplaceAll(src, " ", ""), "..")
+	num := func(s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid depth %q (want a positive number)", s)
+		}
+		return n, nil
+	}
+	if !ranged {
+		n, err := num(lo)
+		if err != nil {
+			return err
+		}
+		step.MaxDepth = n
+		return nil
+	}
+	if lo != "" {
+		n, err := num(lo)
+		if err != nil {
+			return err
+		}
+		step.MinDepth = n
+	}
+	if hi != "" {
+		n, err := num(hi)
+		if err != nil {
+			return err
+		}
+		step.MaxDepth = n
+	}
+	if step.MaxDepth != 0 && step.MaxDepth < step.MinDepth {
+		return fmt.Errorf("depth range %q is empty", src)
+	}
+	return nil
+}
+
+// Edge is a dependency between two issues in a result.
+type Edge struct {
+	From string               `json:"from"` // The issue that depends
+	To   string               `json:"to"`   // The issue depended on
+	Type types.DependencyType `json:"type"`
+}
+
+// Hit is an issue an expression reaches.
+type Hit struct {
+	ID    string   `json:"id"`
+	Depth int      `json:"depth"` // Hops from the start, summed over steps
+	Path  []string `json:"path"`  // A shortest path from a start issue, inclusive
+}
+
+// Graph is the dependency graph an expression runs over.
+type Graph struct {
+	out map[string][]Edge // Issue → what it depends on
+	in  map[string][]Edge // Issue → what depends on it
+}
+
+// NewGraph builds a graph from dependency records keyed by the issue that
+// has them (as storage.GetAllDependencyRecords returns them).
+func NewGraph(deps map[string][]*types.Dependency) *Graph {
+	g := &Graph{out: make(map[string][]Edge), in: make(map[string][]Edge)}
+	for _, list := range deps {
+		for _, d := range list {
+			e := Edge{From: d.IssueID, To: d.DependsOnID, Type: d.Type}
+			g.out[e.From] = append(g.out[e.From], e)
+			g.in[e.To] = append(g.in[e.To], e)
+		}
+	}
+	for _, m := range []map[string][]Edge{g.out, g.in} {
+		for _, edges := range m {
+			sort.Slice(edges, func(i, j int) bool {
+				if edges[i].From != edges[j].From {
+					return edges[i].From < edges[j].From
+				}
+				return edges[i].To < edges[j].To
+			})
+		}
+	}
+	return g
+}
+
+// Eval runs expr with its start issues resolved to start. Hits are in
+// breadth-first order; start issues only appear when a step reaches them
+// again (through a cycle).
+func (g *Graph) Eval(expr *Expr, start []string) []Hit {
+	current := make([]Hit, len(start))
+	for i, id := range start {
+		current[i] = Hit{ID: id, Path: []string{id}}
+	}
+	for _, step := range expr.Steps {
+		current = g.step(current, step)
+	}
+	return current
+}
+
+func (g *Graph) step(from []Hit, step Step) []Hit {
+	edges, next := g.out, func(e Edge) string { return e.To }
+	if step.Axis == Dependents || step.Axis == Descendants {
+		edges, next = g.in, func(e Edge) string { return e.From }
+	}
+	allowed := func(t types.DependencyType) bool {
+		if len(step.Types) == 0 {
+			return true
+		}
+		for _, want := range step.Types {
+			if t == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	type node struct {
+		hit  Hit
+		hops int
+	}
+	seen := make(map[string]bool)
+	queue := make([]node, 0, len(from))
+	for _, h := range from {
+		if !seen[h.ID] {
+			seen[h.ID] = true
+			queue = append(queue, node{hit: h})
+		}
+	}
+	reached := make(map[string]bool)
+	var hits []Hit
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if step.MaxDepth != 0 && cur.hops >= step.MaxDepth {
+			continue
+		}
+		for _, e := range edges[cur.hit.ID] {
+			if !allowed(e.Type) {
+				continue
+			}
+			id := next(e)
+			hit := Hit{
+				ID:    id,
+				Depth: cur.hit.Depth + 1,
+				Path:  append(append([]string{}, cur.hit.Path...), id),
+			}
+			if cur.hops+1 >= step.MinDepth && !reached[id] {
+				reached[id] = true
+				hits = append(hits, hit)
+			}
+			if !seen[id] {
+				seen[id] = true
+				queue = append(queue, node{hit: hit, hops: cur.hops + 1})
+			}
+		}
+	}
+	return hits
+}
+
+// Edges returns the edges among ids, so a result can be drawn as a
+// subgraph.
+func (g *Graph) Edges(ids []string) []Edge {
+	in := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		in[id] = true
+	}
+	edges := []Edge{}
+	for _, id := range ids {
+		for _, e := range g.out[id] {
+			if in[e.To] {
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges
+}
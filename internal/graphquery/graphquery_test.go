@@ -0,0 +1,121 @@
+package graphquery
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		src  string
+		want string // Steps as axis:types:min-max, or an error fragment
+	}{
+		{"bd-1/ancestors", "ancestors::1-0"},
+		{"bd-1, bd-2/dependents", "dependents::1-1"},
+		{"bd-1/descendants[parent-child]{3}", "descendants:parent-child:1-3"},
+		{"bd-1/descendants{2..}", "descendants::2-0"},
+		{"bd-1/ancestors[blocks, related]{2..4}/dependents", "ancestors:blocks,related:2-4 dependents::1-1"},
+		{"bd-1/ancestors[blocking]{..2}", "ancestors:blocks,parent-child,conditional-blocks,waits-for:1-2"},
+		{"bd-1", "needs a start issue"},
+		{"/ancestors", "no start issue"},
+		{"bd-1/parents", "unknown step"},
+		{"bd-1/dependents{2}", "take no depth"},
+		{"bd-1/ancestors{0}", "invalid depth"},
+		{"bd-1/ancestors{4..2}", "is empty"},
+		{"bd-1/ancestors[blocks", "missing ]"},
+		{"bd-1/ancestors{2", "missing }"},
+		{"bd-1/ancestors[blocks]x", "unexpected"},
+	} {
+		expr, err := Parse(tc.src)
+		if err != nil {
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Parse(%q) error = %v, want %q", tc.src, err, tc.want)
+			}
+			continue
+		}
+		var steps []string
+		for _, s := range expr.Steps {
+			var ts []string
+			for _, typ := range s.Types {
+				ts = append(ts, string(typ))
+			}
+			steps = append(steps, s.Axis+":"+strings.Join(ts, ",")+":"+strconv.Itoa(s.MinDepth)+"-"+strconv.Itoa(s.MaxDepth))
+		}
+		if got := strings.Join(steps, " "); got != tc.want {
+			t.Errorf("Parse(%q) = %s, want %s", tc.src, got, tc.want)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	// epic ← task1 ← task2 ← task3 (parent-child), task3 blocks task4,
+	// task4 is related to task1
+	dep := func(from, to string, typ types.DependencyType) *types.Dependency {
+		return &types.Dependency{IssueID: from, DependsOnID: to, Type: typ}
+	}
+	g := NewGraph(map[string][]*types.Dependency{
+		"task1": {dep("task1", "epic", types.DepParentChild)},
+		"task2": {dep("task2", "task1", types.DepParentChild)},
+		"task3": {dep("task3", "task2", types.DepParentChild)},
+		"task4": {dep("task4", "task3", types.DepBlocks), dep("task4", "task1", types.DepRelated)},
+	})
+
+	run := func(src string) string {
+		t.Helper()
+		expr, err := Parse(src)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", src, err)
+		}
+		var got []string
+		for _, h := range g.Eval(expr, expr.Start) {
+			got = append(got, h.ID+"@"+strconv.Itoa(h.Depth))
+		}
+		return strings.Join(got, " ")
+	}
+
+	for _, tc := range []struct{ src, want string }{
+		{"epic/descendants", "task1@1 task2@2 task4@2 task3@3"},
+		{"epic/descendants[parent-child]", "task1@1 task2@2 task3@3"},
+		{"epic/descendants[parent-child]{2}", "task1@1 task2@2"},
+		{"epic/descendants[parent-child]{2..}", "task2@2 task3@3"},
+		{"epic/dependents", "task1@1"},
+		{"task4/dependencies", "task1@1 task3@1"},
+		{"task4/ancestors[blocking]", "task3@1 task2@2 task1@3 epic@4"},
+		{"task4/dependencies[blocks]/ancestors{1}", "task2@2"},
+		{"task3,task4/dependencies[related]", "task1@1"},
+		{"epic/ancestors", ""},
+	} {
+		if got := run(tc.src); got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.src, got, tc.want)
+		}
+	}
+
+	// Paths are shortest paths from a start issue
+	expr, _ := Parse("task4/ancestors")
+	hits := g.Eval(expr, expr.Start)
+	for _, h := range hits {
+		if h.ID == "epic" && strings.Join(h.Path, ">") != "task4>task1>epic" {
+			t.Errorf("path to epic = %v", h.Path)
+		}
+	}
+
+	edges := g.Edges([]string{"task3", "task4", "epic"})
+	if len(edges) != 1 || edges[0] != (Edge{From: "task4", To: "task3", Type: types.DepBlocks}) {
+		t.Errorf("Edges = %+v", edges)
+	}
+}
+
+func TestEvalCycle(t *testing.T) {
+	g := NewGraph(map[string][]*types.Dependency{
+		"a": {{IssueID: "a", DependsOnID: "b", Type: types.DepBlocks}},
+		"b": {{IssueID: "b", DependsOnID: "a", Type: types.DepBlocks}},
+	})
+	expr, _ := Parse("a/ancestors")
+	hits := g.Eval(expr, expr.Start)
+	if len(hits) != 2 || hits[0].ID != "b" || hits[1].ID != "a" || hits[1].Depth != 2 {
+		t.Errorf("hits = %+v, want b then a (back through the cycle)", hits)
+	}
+}
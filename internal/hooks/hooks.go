@@ -28,6 +28,14 @@ const (
 type Runner struct {
 	hooksDir string
 	timeout  time.Duration
+	prepare  func(*types.Issue)
+}
+
+// SetPrepare sets a function that completes an issue before it is sent to a
+// hook, e.g. with fields kept outside the issues table. It runs
+// synchronously, and only when the event has a hook.
+func (r *Runner) SetPrepare(prepare func(*types.Issue)) {
+	r.prepare = prepare
 }
 
 // NewRunner creates a new hook runner.
@@ -65,6 +73,10 @@ func (r *Runner) Run(event string, issue *types.Issue) {
 		return // Not executable, skip
 	}
 
+	if r.prepare != nil {
+		r.prepare(issue)
+	}
+
 	// Run asynchronously (ignore error as this is fire-and-forget)
 	go func() {
 		_ = r.runHook(hookPath, event, issue)
@@ -91,6 +103,9 @@ func (r *Runner) RunSync(event string, issue *types.Issue) error {
 		return nil // Not executable, skip
 	}
 
+	if r.prepare != nil {
+		r.prepare(issue)
+	}
 	return r.runHook(hookPath, event, issue)
 }
 
@@ -115,6 +115,42 @@ func TestHookExists_Directory(t *testing.T) {
 	}
 }
 
+func TestRunSync_Prepare(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputFile := filepath.Join(tmpDir, "stdin.txt")
+	hookScript := `#!/bin/sh
+cat > ` + outputFile
+	if err := os.WriteFile(filepath.Join(tmpDir, HookOnUpdate), []byte(hookScript), 0755); err != nil {
+		t.Fatalf("Failed to create hook file: %v", err)
+	}
+
+	runner := NewRunner(tmpDir)
+	prepared := 0
+	runner.SetPrepare(func(issue *types.Issue) {
+		prepared++
+		issue.CC = []string{"pm@example.com"}
+	})
+
+	if err := runner.RunSync(EventUpdate, &types.Issue{ID: "bd-test"}); err != nil {
+		t.Fatalf("RunSync returned error: %v", err)
+	}
+	output, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(output), `"cc":["pm@example.com"]`) {
+		t.Errorf("Hook input lacks the prepared cc: %s", output)
+	}
+
+	// No hook for the event: nothing to prepare
+	if err := runner.RunSync(EventClose, &types.Issue{ID: "bd-test"}); err != nil {
+		t.Fatalf("RunSync returned error: %v", err)
+	}
+	if prepared != 1 {
+		t.Errorf("prepare ran %d times, want 1", prepared)
+	}
+}
+
 func TestRunSync_NoHook(t *testing.T) {
 	tmpDir := t.TempDir()
 	runner := NewRunner(tmpDir)
@@ -0,0 +1,363 @@
+// Package httpx is the HTTP client layer shared by bd's connectors (GitLab,
+// Linear, Azure DevOps, Notion, Jira, ref checking). It retries failed
+// requests with exponential backoff, honors Retry-After, applies per-host
+// rate limits and logs every request, so integrations behave under API
+// quotas without each connector reimplementing it.
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/steveyegge/beads/internal/debug"
+)
+
+const (
+	// DefaultTimeout bounds a single attempt, not the whole retry sequence.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultMaxRetries is how many times a request is retried.
+	DefaultMaxRetries = 3
+
+	// DefaultBaseDelay is the first backoff delay; it doubles per retry.
+	DefaultBaseDelay = time.Second
+
+	// DefaultMaxDelay caps any single wait. A Retry-After longer than this
+	// is not waited out: the response is returned to the caller instead.
+	DefaultMaxDelay = time.Minute
+)
+
+// NewClient returns an http.Client whose requests go through the shared
+// Transport, each attempt limited to timeout (DefaultTimeout if zero).
+func NewClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &http.Client{Transport: &Transport{AttemptTimeout: timeout}}
+}
+
+// Transport is an http.RoundTripper that adds retries, rate limiting and
+// logging to Base. The zero value uses the defaults above.
+type Transport struct {
+	Base           http.RoundTripper // http.DefaultTransport if nil
+	AttemptTimeout time.Duration     // per attempt; no limit if zero
+	MaxRetries     int               // DefaultMaxRetries if zero; negative disables retries
+	BaseDelay      time.Duration     // DefaultBaseDelay if zero
+	MaxDelay       time.Duration     // DefaultMaxDelay if zero
+	Limits         *Limits           // DefaultLimits if nil
+}
+
+// RoundTrip sends req, retrying rate-limited (429) responses, server
+// errors (502, 503, 504) and network errors. Server errors and network
+// errors are only retried for requests that are safe to replay: idempotent
+// methods, requests carrying an Idempotency-Key header, and requests whose
+// context was marked with Replayable.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	maxRetries := t.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body cannot be rewound for another attempt
+		maxRetries = -1
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := t.limits().Wait(ctx, req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+		attemptReq, err := rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := t.send(attemptReq)
+		logRequest(req, resp, err, time.Since(start), attempt)
+
+		if attempt >= maxRetries || !t.retryable(req, resp, err) {
+			return resp, err
+		}
+		delay, ok := t.delay(resp, attempt)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			_ = resp.Body.Close()
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			if resp != nil {
+				return nil, fmt.Errorf("%s %s: status %d, retry in %v would pass the deadline", req.Method, redact(req), resp.StatusCode, delay)
+			}
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// send performs one attempt under AttemptTimeout. The timeout stays armed
+// until the response body is closed.
+func (t *Transport) send(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.AttemptTimeout <= 0 {
+		return base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.AttemptTimeout)
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (t *Transport) limits() *Limits {
+	if t.Limits != nil {
+		return t.Limits
+	}
+	return DefaultLimits
+}
+
+func (t *Transport) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return replayable(req) && req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return replayable(req)
+	}
+	return false
+}
+
+// delay returns how long to wait before the next attempt: the server's
+// Retry-After if it sent one, otherwise exponential backoff with jitter.
+// ok is false when the server asked for a longer wait than MaxDelay.
+func (t *Transport) delay(resp *http.Response, attempt int) (time.Duration, bool) {
+	maxDelay := t.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxDelay
+	}
+	if resp != nil {
+		if after, ok := RetryAfter(resp.Header, time.Now()); ok {
+			return after, after <= maxDelay
+		}
+	}
+	base := t.BaseDelay
+	if base <= 0 {
+		base = DefaultBaseDelay
+	}
+	d := base << attempt
+	d += time.Duration(rand.Int63n(int64(d)/2 + 1)) // #nosec G404 - jitter only
+	return min(d, maxDelay), true
+}
+
+// RetryAfter parses a Retry-After header given as seconds or an HTTP date.
+func RetryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := strings.TrimSpace(h.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0), true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+type replayableKey struct{}
+
+// Replayable marks requests made with the returned context as safe to
+// retry whatever their method, e.g. GraphQL queries sent as POST.
+func Replayable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replayableKey{}, true)
+}
+
+func replayable(req *http.Request) bool {
+	if ok, _ := req.Context().Value(replayableKey{}).(bool); ok {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, ok := req.Header["Idempotency-Key"]
+	return ok
+}
+
+// rewind returns the request to send for attempt, with a fresh body after
+// the first.
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("rewinding request body: %w", err)
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// Limits holds per-host request rate limits.
+type Limits struct {
+	mu       sync.Mutex
+	rates    map[string]rate.Limit // host (or "*" for every host) -> requests per second
+	limiters map[string]*rate.Limiter
+}
+
+// DefaultLimits is used by every Transport without its own Limits.
+var DefaultLimits = &Limits{}
+
+// Set limits requests to host to perSecond (0 removes the limit). Host "*"
+// applies to every host without its own limit.
+func (l *Limits) Set(host string, perSecond float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rates == nil {
+		l.rates = make(map[string]rate.Limit)
+	}
+	host = strings.ToLower(host)
+	if perSecond <= 0 {
+		delete(l.rates, host)
+	} else {
+		l.rates[host] = rate.Limit(perSecond)
+	}
+	l.limiters = nil // rebuilt lazily with the new rates
+}
+
+// Wait blocks until a request to host is allowed.
+func (l *Limits) Wait(ctx context.Context, host string) error {
+	limiter := l.limiter(strings.ToLower(host))
+	if limiter == nil {
+		return nil
+	}
+	if err := limiter.Wait(ctx); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("rate limit for %s: %w", host, err)
+	}
+	return nil
+}
+
+func (l *Limits) limiter(host string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r, ok := l.rates[host]
+	if !ok {
+		if r, ok = l.rates["*"]; !ok {
+			return nil
+		}
+	}
+	if l.limiters == nil {
+		l.limiters = make(map[string]*rate.Limiter)
+	}
+	limiter := l.limiters[host]
+	if limiter == nil {
+		limiter = rate.NewLimiter(r, max(int(r), 1))
+		l.limiters[host] = limiter
+	}
+	return limiter
+}
+
+// ParseRate parses a rate limit such as "10", "10/s", "100/m" or "1000/h"
+// into requests per second.
+func ParseRate(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	num, unit, _ := strings.Cut(s, "/")
+	n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid rate %q (want e.g. 10/s, 100/m)", s)
+	}
+	switch strings.TrimSpace(unit) {
+	case "", "s", "sec", "second":
+		return n, nil
+	case "m", "min", "minute":
+		return n / 60, nil
+	case "h", "hour":
+		return n / 3600, nil
+	}
+	return 0, fmt.Errorf("invalid rate unit in %q (want s, m or h)", s)
+}
+
+var (
+	logMu sync.Mutex
+	logW  io.Writer
+)
+
+// SetLog sends a line per request attempt to w. With w nil, requests are
+// only logged when BD_DEBUG is set.
+func SetLog(w io.Writer) {
+	logMu.Lock()
+	logW = w
+	logMu.Unlock()
+}
+
+func logRequest(req *http.Request, resp *http.Response, err error, took time.Duration, attempt int) {
+	outcome := "error: "
+	if err != nil {
+		outcome += err.Error()
+	} else {
+		outcome = strconv.Itoa(resp.StatusCode)
+	}
+	line := fmt.Sprintf("http: %s %s -> %s (%v", req.Method, redact(req), outcome, took.Round(time.Millisecond))
+	if attempt > 0 {
+		line += fmt.Sprintf(", retry %d", attempt)
+	}
+	line += ")\n"
+
+	logMu.Lock()
+	w := logW
+	logMu.Unlock()
+	if w != nil {
+		_, _ = io.WriteString(w, line)
+		return
+	}
+	debug.Logf("%s", line)
+}
+
+// redact drops the query string, which some APIs use for credentials.
+func redact(req *http.Request) string {
+	u := *req.URL
+	if u.RawQuery != "" {
+		u.RawQuery = "..."
+	}
+	u.User = nil
+	return u.String()
+}
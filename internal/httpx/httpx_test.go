@@ -0,0 +1,194 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testClient(limits *Limits) *http.Client {
+	return &http.Client{Transport: &Transport{
+		AttemptTimeout: 2 * time.Second,
+		BaseDelay:      time.Millisecond,
+		MaxDelay:       50 * time.Millisecond,
+		Limits:         limits,
+	}}
+}
+
+func TestRetriesRateLimitedRequests(t *testing.T) {
+	var calls atomic.Int32
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// POST is not idempotent, but a 429 means the server did nothing
+	resp, err := testClient(&Limits{}).Post(server.URL, "text/plain", bytes.NewReader([]byte("payload")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("status %d after %d calls, want 200 after 3", resp.StatusCode, calls.Load())
+	}
+	for i, b := range bodies {
+		if b != "payload" {
+			t.Errorf("attempt %d body = %q, want the body replayed", i+1, b)
+		}
+	}
+}
+
+func TestServerErrorsRetriedOnlyWhenReplayable(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := testClient(&Limits{})
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := calls.Load(); got != DefaultMaxRetries+1 {
+		t.Errorf("GET: %d calls, want %d", got, DefaultMaxRetries+1)
+	}
+
+	calls.Store(0)
+	resp, err = client.Post(server.URL, "text/plain", strings.NewReader("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("POST: %d calls, want 1", got)
+	}
+
+	calls.Store(0)
+	req, _ := http.NewRequestWithContext(Replayable(context.Background()), http.MethodPost, server.URL, strings.NewReader("x"))
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := calls.Load(); got != DefaultMaxRetries+1 {
+		t.Errorf("replayable POST: %d calls, want %d", got, DefaultMaxRetries+1)
+	}
+}
+
+func TestLongRetryAfterReturnedToCaller(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	resp, err := testClient(&Limits{}).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 1 {
+		t.Errorf("status %d after %d calls, want the 429 without waiting an hour", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for value, want := range map[string]time.Duration{
+		"7":  7 * time.Second,
+		"-3": 0,
+		now.Add(90 * time.Second).Format(http.TimeFormat): 90 * time.Second,
+	} {
+		got, ok := RetryAfter(http.Header{"Retry-After": {value}}, now)
+		if !ok || got != want {
+			t.Errorf("RetryAfter(%q) = %v, %v; want %v", value, got, ok, want)
+		}
+	}
+	if _, ok := RetryAfter(http.Header{"Retry-After": {"soon"}}, now); ok {
+		t.Error("RetryAfter accepted garbage")
+	}
+}
+
+func TestLimitsPerHost(t *testing.T) {
+	limits := &Limits{}
+	limits.Set("slow.example", 20)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		if err := limits.Wait(ctx, "fast.example"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("unlimited host waited %v", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 25; i++ {
+		if err := limits.Wait(ctx, "SLOW.example"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The burst of 20 is free; the other 5 take 50ms each
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("limited host finished 25 requests in %v", elapsed)
+	}
+
+	limits.Set("*", 1)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_ = limits.Wait(ctx, "other.example") // spend the burst
+	if err := limits.Wait(cancelled, "other.example"); err == nil {
+		t.Error("Wait ignored a cancelled context")
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	for in, want := range map[string]float64{"10": 10, "10/s": 10, "120/m": 2, "3600/h": 1, " 5 / s ": 5} {
+		if got, err := ParseRate(in); err != nil || got != want {
+			t.Errorf("ParseRate(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "fast", "10/d", "-1/s"} {
+		if _, err := ParseRate(bad); err == nil {
+			t.Errorf("ParseRate(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestRequestLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	SetLog(&log)
+	defer SetLog(nil)
+	resp, err := testClient(&Limits{}).Get(server.URL + "/issues?private_token=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := log.String(); !strings.Contains(got, "GET "+server.URL+"/issues?... -> 200") || strings.Contains(got, "secret") {
+		t.Errorf("log = %q", got)
+	}
+}
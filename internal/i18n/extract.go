@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Extract returns the messages passed as string literals to i18n.T in the
+// Go files under root, sorted and de-duplicated. Test files are skipped.
+func Extract(root string) ([]string, error) {
+	seen := make(map[string]bool)
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "T" {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "i18n" {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			if msg, err := strconv.Unquote(lit.Value); err == nil {
+				seen[msg] = true
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]string, 0, len(seen))
+	for msg := range seen {
+		messages = append(messages, msg)
+	}
+	sort.Strings(messages)
+	return messages, nil
+}
+
+// Verbs returns the format verbs in msg, in order, so a translation can be
+// checked against its source ("%s", "%d", ...; "%%" is not a verb).
+func Verbs(msg string) []string {
+	var verbs []string
+	for i := 0; i < len(msg); i++ {
+		if msg[i] != '%' {
+			continue
+		}
+		j := i + 1
+		for j < len(msg) && strings.IndexByte("+-# 0123456789.[]*", msg[j]) >= 0 {
+			j++
+		}
+		if j >= len(msg) {
+			break
+		}
+		if msg[j] != '%' {
+			verbs = append(verbs, msg[i:j+1])
+		}
+		i = j
+	}
+	return verbs
+}
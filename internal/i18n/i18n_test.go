@@ -0,0 +1,123 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	for tag, want := range map[string]string{
+		"":            "",
+		"de":          "de",
+		"DE":          "de",
+		"de_DE.UTF-8": "de",
+		"de-AT":       "de",
+		"sr@latin":    "sr",
+		"C":           English,
+		"POSIX":       English,
+	} {
+		if got := Normalize(tag); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	defer func() { _ = SetLanguage("") }()
+
+	if err := SetLanguage("de_DE.UTF-8"); err != nil {
+		t.Fatal(err)
+	}
+	if Language() != "de" {
+		t.Errorf("Language() = %q, want de", Language())
+	}
+	if got := T("Created issue: %s", "bd-1"); got != "Issue erstellt: bd-1" {
+		t.Errorf("T = %q", got)
+	}
+	if got := T("not in any catalog: %d", 3); got != "not in any catalog: 3" {
+		t.Errorf("untranslated T = %q", got)
+	}
+	if got := T("100% literal"); got != "100% literal" {
+		t.Errorf("T without args = %q, want the message unformatted", got)
+	}
+
+	if err := SetLanguage("xx"); err == nil {
+		t.Error("SetLanguage(xx) should fail")
+	}
+	if Language() != "de" {
+		t.Error("a failed SetLanguage changed the language")
+	}
+	if err := SetLanguage(""); err != nil || Language() != English {
+		t.Errorf("SetLanguage(\"\") = %v, language %q", err, Language())
+	}
+	if got := T("Created issue: %s", "bd-1"); got != "Created issue: bd-1" {
+		t.Errorf("English T = %q", got)
+	}
+}
+
+func TestVerbs(t *testing.T) {
+	got := Verbs("%s at 100%% of %5.2f, %-3d and %[1]v")
+	want := []string{"%s", "%5.2f", "%-3d", "%[1]v"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Verbs = %v, want %v", got, want)
+	}
+}
+
+var argIndex = regexp.MustCompile(`\[\d+\]`)
+
+// verbKinds returns msg's verbs without explicit argument indexes, sorted,
+// so translations may reorder arguments with %[n]s.
+func verbKinds(msg string) string {
+	verbs := Verbs(msg)
+	for i, v := range verbs {
+		verbs[i] = argIndex.ReplaceAllString(v, "")
+	}
+	sort.Strings(verbs)
+	return strings.Join(verbs, " ")
+}
+
+// TestCatalogsCoverSource checks every catalog against the messages
+// wrapped in i18n.T in cmd/bd: each is translated with the same format
+// verbs, and no catalog has entries the source no longer uses. On failure
+// it prints the missing messages as JSON to paste into the catalog.
+func TestCatalogsCoverSource(t *testing.T) {
+	messages, err := Extract("../../cmd/bd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) == 0 {
+		t.Fatal("no i18n.T messages found in cmd/bd")
+	}
+	inSource := make(map[string]bool, len(messages))
+	for _, msg := range messages {
+		inSource[msg] = true
+	}
+
+	for _, lang := range Languages()[1:] {
+		catalog, err := Load(lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var missing []string
+		for _, msg := range messages {
+			translated, ok := catalog[msg]
+			if !ok || translated == "" {
+				missing = append(missing, `  "`+msg+`": ""`)
+				continue
+			}
+			if verbKinds(msg) != verbKinds(translated) {
+				t.Errorf("%s: %q translates %q with different format verbs", lang, msg, translated)
+			}
+		}
+		if len(missing) > 0 {
+			t.Errorf("%s.json is missing %d message(s):\n%s", lang, len(missing), strings.Join(missing, ",\n"))
+		}
+		for msg := range catalog {
+			if !inSource[msg] {
+				t.Errorf("%s.json has %q, which cmd/bd no longer uses", lang, msg)
+			}
+		}
+	}
+}
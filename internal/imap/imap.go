@@ -0,0 +1,289 @@
+// Package imap is a minimal IMAP4rev1 client (RFC 3501): just enough to log
+// in, list the unseen messages of a mailbox, fetch them and flag them seen.
+package imap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLiteral bounds the size of a single message accepted from the server.
+const maxLiteral = 64 << 20
+
+// Server is a mailbox location parsed from an imaps:// or imap:// URL.
+type Server struct {
+	Addr    string // host:port
+	TLS     bool   // False only for imap:// (e.g. a local mail bridge)
+	Mailbox string
+}
+
+// ParseURL parses imaps://host[:port]/mailbox. The port defaults to 993
+// (143 for imap://) and the mailbox to INBOX.
+func ParseURL(raw string) (*Server, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMAP URL %q: %w", raw, err)
+	}
+	srv := &Server{Mailbox: strings.TrimPrefix(u.Path, "/")}
+	port := "993"
+	switch u.Scheme {
+	case "imaps":
+		srv.TLS = true
+	case "imap":
+		port = "143"
+	default:
+		return nil, fmt.Errorf("invalid IMAP URL %q: scheme must be imaps or imap", raw)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid IMAP URL %q: missing host", raw)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	srv.Addr = net.JoinHostPort(u.Hostname(), port)
+	if srv.Mailbox == "" {
+		srv.Mailbox = "INBOX"
+	}
+	return srv, nil
+}
+
+// Client is a connection to an IMAP server. It is not safe for concurrent
+// use.
+type Client struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+	stop func() bool
+}
+
+// Dial connects to srv and reads the server greeting. Cancelling ctx
+// closes the connection.
+func Dial(ctx context.Context, srv *Server) (*Client, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if srv.TLS {
+		host, _, _ := net.SplitHostPort(srv.Addr)
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", srv.Addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", srv.Addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", srv.Addr, err)
+	}
+	c, err := newClient(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	c.stop = context.AfterFunc(ctx, func() { _ = conn.Close() })
+	return c, nil
+}
+
+// newClient reads the greeting of a connected server.
+func newClient(conn net.Conn) (*Client, error) {
+	c := &Client{conn: conn, r: bufio.NewReader(conn)}
+	greeting, err := c.readResponse()
+	if err != nil {
+		return nil, fmt.Errorf("reading IMAP greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting.text, "* OK") && !strings.HasPrefix(greeting.text, "* PREAUTH") {
+		return nil, fmt.Errorf("unexpected IMAP greeting: %s", greeting.text)
+	}
+	return c, nil
+}
+
+// Login authenticates with a user name and password.
+func (c *Client) Login(username, password string) error {
+	user, err := quote(username)
+	if err != nil {
+		return err
+	}
+	pass, err := quote(password)
+	if err != nil {
+		return err
+	}
+	_, err = c.command("LOGIN " + user + " " + pass)
+	return err
+}
+
+// Select opens mailbox for reading and flagging.
+func (c *Client) Select(mailbox string) error {
+	name, err := quote(mailbox)
+	if err != nil {
+		return err
+	}
+	_, err = c.command("SELECT " + name)
+	return err
+}
+
+// Unseen returns the UIDs of the messages in the selected mailbox that are
+// not flagged \Seen, oldest first.
+func (c *Client) Unseen() ([]uint32, error) {
+	untagged, err := c.command("UID SEARCH UNSEEN")
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, resp := range untagged {
+		fields := strings.Fields(resp.text)
+		if len(fields) < 2 || !strings.EqualFold(fields[1], "SEARCH") {
+			continue
+		}
+		for _, f := range fields[2:] {
+			uid, err := strconv.ParseUint(f, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UID in SEARCH response: %q", f)
+			}
+			uids = append(uids, uint32(uid))
+		}
+	}
+	return uids, nil
+}
+
+// Fetch returns the full RFC 822 message with the given UID, without
+// flagging it \Seen.
+func (c *Client) Fetch(uid uint32) ([]byte, error) {
+	untagged, err := c.command(fmt.Sprintf("UID FETCH %d BODY.PEEK[]", uid))
+	if err != nil {
+		return nil, err
+	}
+	for _, resp := range untagged {
+		if len(resp.literals) > 0 && strings.Contains(strings.ToUpper(resp.text), " FETCH ") {
+			return resp.literals[0], nil
+		}
+	}
+	return nil, fmt.Errorf("message %d not found", uid)
+}
+
+// MarkSeen flags the message with the given UID \Seen.
+func (c *Client) MarkSeen(uid uint32) error {
+	_, err := c.command(fmt.Sprintf(`UID STORE %d +FLAGS.SILENT (\Seen)`, uid))
+	return err
+}
+
+// Logout ends the session and closes the connection.
+func (c *Client) Logout() error {
+	_, err := c.command("LOGOUT")
+	if closeErr := c.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Close closes the connection without logging out.
+func (c *Client) Close() error {
+	if c.stop != nil {
+		c.stop()
+	}
+	err := c.conn.Close()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// response is one server response, with the literals ({n} strings) it
+// carries.
+type response struct {
+	text     string
+	literals [][]byte
+}
+
+// command sends a tagged command and returns the untagged responses that
+// preceded its completion. A NO or BAD completion is an error naming only
+// the command verb, so credentials never end up in messages.
+func (c *Client) command(cmd string) ([]*response, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+	verb := strings.Fields(cmd)[0]
+	if verb == "UID" {
+		verb += " " + strings.Fields(cmd)[1]
+	}
+	if _, err := io.WriteString(c.conn, tag+" "+cmd+"\r\n"); err != nil {
+		return nil, fmt.Errorf("IMAP %s: %w", verb, err)
+	}
+
+	var untagged []*response
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, fmt.Errorf("IMAP %s: %w", verb, err)
+		}
+		if status, ok := strings.CutPrefix(resp.text, tag+" "); ok {
+			if len(status) >= 2 && strings.EqualFold(status[:2], "OK") {
+				return untagged, nil
+			}
+			return nil, fmt.Errorf("IMAP %s failed: %s", verb, status)
+		}
+		if strings.HasPrefix(resp.text, "+") {
+			return nil, fmt.Errorf("IMAP %s: unexpected continuation request", verb)
+		}
+		untagged = append(untagged, resp)
+	}
+}
+
+// readResponse reads one response line, following any literals into the
+// lines that continue it.
+func (c *Client) readResponse() (*response, error) {
+	resp := &response{}
+	var text strings.Builder
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		text.WriteString(line)
+
+		n, ok := literalSize(line)
+		if !ok {
+			break
+		}
+		if n > maxLiteral {
+			return nil, fmt.Errorf("server sent a %d-byte literal (limit %d)", n, maxLiteral)
+		}
+		literal := make([]byte, n)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return nil, err
+		}
+		resp.literals = append(resp.literals, literal)
+	}
+	resp.text = text.String()
+	return resp, nil
+}
+
+// literalSize reports whether line announces a literal ("... {123}") and
+// its size in bytes.
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	open := strings.LastIndexByte(line, '{')
+	if open < 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(line[open+1 : len(line)-1])
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// quote renders s as an IMAP quoted string.
+func quote(s string) (string, error) {
+	if strings.ContainsAny(s, "\r\n\x00") {
+		return "", fmt.Errorf("IMAP strings cannot contain line breaks")
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`, nil
+}
@@ -0,0 +1,128 @@
+package imap
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+const testMessage = "From: alice@example.com\r\nSubject: Printer {on fire}\r\n\r\nHelp!\r\n"
+
+// fakeServer answers the commands of one session over conn, recording
+// them, with a single unseen message (UID 7) in INBOX.
+func fakeServer(t *testing.T, conn net.Conn, commands *[]string) {
+	t.Helper()
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	write := func(format string, args ...any) {
+		_, _ = fmt.Fprintf(conn, format, args...)
+	}
+	write("* OK IMAP4rev1 ready\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, cmd, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		*commands = append(*commands, cmd)
+		switch {
+		case strings.HasPrefix(cmd, "LOGIN"):
+			if cmd != `LOGIN "alice" "p\"w"` {
+				write("%s NO [AUTHENTICATIONFAILED] Invalid credentials\r\n", tag)
+				continue
+			}
+		case strings.HasPrefix(cmd, "SELECT"):
+			write("* 3 EXISTS\r\n* FLAGS (\\Seen)\r\n")
+		case cmd == "UID SEARCH UNSEEN":
+			write("* SEARCH 7\r\n")
+		case cmd == "UID FETCH 7 BODY.PEEK[]":
+			write("* 3 FETCH (UID 7 BODY[] {%d}\r\n%s)\r\n", len(testMessage), testMessage)
+		case strings.HasPrefix(cmd, "UID FETCH"):
+		case cmd == "LOGOUT":
+			write("* BYE\r\n%s OK LOGOUT completed\r\n", tag)
+			return
+		}
+		write("%s OK done\r\n", tag)
+	}
+}
+
+func TestClient(t *testing.T) {
+	client, server := net.Pipe()
+	var commands []string
+	done := make(chan struct{})
+	go func() {
+		fakeServer(t, server, &commands)
+		close(done)
+	}()
+
+	c, err := newClient(client)
+	if err != nil {
+		t.Fatalf("newClient failed: %v", err)
+	}
+	err = c.Login("alice", "wrong")
+	if err == nil || strings.Contains(err.Error(), "wrong") {
+		t.Errorf("bad login error = %v, want a failure without the password", err)
+	}
+	if err := c.Login("alice", `p"w`); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if err := c.Select("INBOX"); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	uids, err := c.Unseen()
+	if err != nil || len(uids) != 1 || uids[0] != 7 {
+		t.Fatalf("Unseen = %v, %v, want [7]", uids, err)
+	}
+	msg, err := c.Fetch(7)
+	if err != nil || string(msg) != testMessage {
+		t.Fatalf("Fetch = %q, %v", msg, err)
+	}
+	if _, err := c.Fetch(8); err == nil {
+		t.Error("fetching a missing message should fail")
+	}
+	if err := c.MarkSeen(7); err != nil {
+		t.Fatalf("MarkSeen failed: %v", err)
+	}
+	if err := c.Logout(); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	<-done
+
+	if got := commands[len(commands)-2]; got != `UID STORE 7 +FLAGS.SILENT (\Seen)` {
+		t.Errorf("MarkSeen sent %q", got)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    Server
+		wantErr bool
+	}{
+		{raw: "imaps://imap.example.com", want: Server{Addr: "imap.example.com:993", TLS: true, Mailbox: "INBOX"}},
+		{raw: "imaps://imap.example.com:1993/Support", want: Server{Addr: "imap.example.com:1993", TLS: true, Mailbox: "Support"}},
+		{raw: "imap://127.0.0.1/INBOX", want: Server{Addr: "127.0.0.1:143", Mailbox: "INBOX"}},
+		{raw: "https://imap.example.com", wantErr: true},
+		{raw: "imaps:///INBOX", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseURL(tt.raw)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseURL(%q) should fail", tt.raw)
+			}
+			continue
+		}
+		if err != nil || *got != tt.want {
+			t.Errorf("ParseURL(%q) = %+v, %v, want %+v", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestQuoteRejectsLineBreaks(t *testing.T) {
+	if _, err := quote("a\r\nb LOGOUT"); err == nil {
+		t.Error("quote should refuse line breaks")
+	}
+}
@@ -2,20 +2,25 @@ package importer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/linear"
+	"github.com/steveyegge/beads/internal/lock"
 	"github.com/steveyegge/beads/internal/routing"
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/subset"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/utils"
+	"github.com/steveyegge/beads/internal/validation"
 )
 
 // OrphanHandling is an alias to sqlite.OrphanHandling for convenience
@@ -42,6 +47,23 @@ type Options struct {
 	OrphanHandling             OrphanHandling  // How to handle missing parent issues (default: allow)
 	ClearDuplicateExternalRefs bool            // Clear duplicate external_ref values instead of erroring
 	ProtectLocalExportIDs      map[string]time.Time // IDs from left snapshot with timestamps for timestamp-aware protection (GH#865)
+	Progress                   func(done, total int) // Called as issues are processed, for progress bars (optional)
+	// LockActor makes the import honour issue locks and the freeze (bd lock,
+	// bd freeze) for this actor: changes to guarded issues are skipped and
+	// reported in Result.Locked. Empty, as for sync and auto-import, applies
+	// everything, since those changes were already allowed where they were made.
+	LockActor string
+	// CheckRules makes the import fail, listing every broken rule, if the
+	// issues it would write break the validation rules in config.yaml. Off,
+	// as for sync and auto-import, the rules were checked where the changes
+	// were made.
+	CheckRules bool
+}
+
+func (o Options) progress(done, total int) {
+	if o.Progress != nil {
+		o.Progress(done, total)
+	}
 }
 
 // Result contains statistics about the import operation
@@ -57,6 +79,41 @@ type Result struct {
 	ExpectedPrefix      string            // Database configured prefix
 	MismatchPrefixes    map[string]int    // Map of mismatched prefixes to count
 	SkippedDependencies []string          // Dependencies skipped due to FK constraint violations
+	Locked              []string          // Changed issues skipped because they are locked or the project is frozen
+}
+
+// InterruptedError reports an import cancelled part way through (Ctrl-C or
+// --timeout). Each write or batch commits on its own, so the database holds the
+// counted changes and no open transaction; re-running the import finishes
+// the job.
+type InterruptedError struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Total     int
+	Cause     error
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("import interrupted after %d of %d issues (%d created, %d updated): %v",
+		e.Created+e.Updated+e.Unchanged, e.Total, e.Created, e.Updated, e.Cause)
+}
+
+func (e *InterruptedError) Unwrap() error { return e.Cause }
+
+// interruptedError converts an error from a cancelled import into an
+// *InterruptedError carrying the partial counts.
+func interruptedError(ctx context.Context, result *Result, total int, err error) error {
+	if errors.As(err, new(*InterruptedError)) {
+		return err
+	}
+	return &InterruptedError{
+		Created:   result.Created,
+		Updated:   result.Updated,
+		Unchanged: result.Unchanged,
+		Total:     total,
+		Cause:     context.Cause(ctx),
+	}
 }
 
 // ImportIssues handles the core import logic used by both manual and auto-import.
@@ -72,16 +129,24 @@ type Result struct {
 // - Setting metadata (e.g., last_import_hash)
 //
 // Parameters:
-// - ctx: Context for cancellation
+// - ctx: Context for cancellation; a cancelled import returns the partial
+//   result with an *InterruptedError
 // - dbPath: Path to SQLite database file
 // - store: Existing storage instance (can be nil for direct mode)
 // - issues: Parsed issues from JSONL
 // - opts: Import options
-func ImportIssues(ctx context.Context, dbPath string, store storage.Storage, issues []*types.Issue, opts Options) (*Result, error) {
+func ImportIssues(ctx context.Context, dbPath string, store storage.Storage, issues []*types.Issue, opts Options) (res *Result, err error) {
 	result := &Result{
 		IDMapping:        make(map[string]string),
 		MismatchPrefixes: make(map[string]int),
 	}
+	total := len(issues)
+	defer func() {
+		if err != nil && ctx.Err() != nil {
+			// Return the partial counts with the error
+			res, err = result, interruptedError(ctx, result, total, err)
+		}
+	}()
 
 	// Normalize Linear external_refs to canonical form to avoid slug-based duplicates.
 	for _, issue := range issues {
@@ -110,6 +175,11 @@ func ImportIssues(ctx context.Context, dbPath string, store storage.Storage, iss
 		}
 	}
 
+	// Locks and validation rules are enforced below for Options.LockActor
+	// and Options.CheckRules, not by the store
+	callerCtx := ctx
+	ctx = validation.WithBypass(lock.WithBypass(ctx))
+
 	// Get or create SQLite store
 	sqliteStore, needCloseStore, err := getOrCreateStore(ctx, dbPath, store)
 	if err != nil {
@@ -133,6 +203,12 @@ func ImportIssues(ctx context.Context, dbPath string, store storage.Storage, iss
 		}
 	}
 
+	// Subset clones (bd init --subset) hold non-matching issues as stubs
+	stubIDs, fullIDs, err := applySubset(ctx, sqliteStore, issues)
+	if err != nil {
+		return nil, err
+	}
+
 	// Read orphan handling from config if not explicitly set
 	if opts.OrphanHandling == "" {
 		opts.OrphanHandling = sqliteStore.GetOrphanHandling(ctx)
@@ -144,6 +220,21 @@ func ImportIssues(ctx context.Context, dbPath string, store storage.Storage, iss
 		return result, err
 	}
 
+	// Skip changes to locked issues, or all changes while frozen
+	if opts.LockActor != "" {
+		issues, err = skipLocked(callerCtx, sqliteStore, issues, opts.LockActor, result)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// Refuse the whole import if what it writes breaks the rules
+	if opts.CheckRules {
+		if err := validation.CheckImport(callerCtx, sqliteStore, issues); err != nil {
+			return result, err
+		}
+	}
+
 	// Validate no duplicate external_ref values in batch
 	if err := validateNoDuplicateExternalRefs(issues, opts.ClearDuplicateExternalRefs, result); err != nil {
 		return result, err
@@ -158,6 +249,17 @@ func ImportIssues(ctx context.Context, dbPath string, store storage.Storage, iss
 		return result, nil
 	}
 
+	// Note which issues have unexported local changes before the upsert
+	// marks imported issues dirty too
+	dirtyIDs, err := sqliteStore.GetDirtyIssues(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dirty issues: %w", err)
+	}
+	dirty := make(map[string]bool, len(dirtyIDs))
+	for _, id := range dirtyIDs {
+		dirty[id] = true
+	}
+
 	// Upsert issues (create new or update existing)
 	if err := upsertIssues(ctx, sqliteStore, issues, opts, result); err != nil {
 		return nil, err
@@ -178,6 +280,20 @@ func ImportIssues(ctx context.Context, dbPath string, store storage.Storage, iss
 		return nil, err
 	}
 
+	// Import refs, security, repro, votes, cc, visibility and key results
+	if err := importExtras(ctx, sqliteStore, issues, dirty, opts); err != nil {
+		return nil, err
+	}
+
+	if !opts.DryRun {
+		if err := sqliteStore.SetStubs(ctx, stubIDs, true); err != nil {
+			return nil, err
+		}
+		if err := sqliteStore.SetStubs(ctx, fullIDs, false); err != nil {
+			return nil, err
+		}
+	}
+
 	// Checkpoint WAL to ensure data persistence and reduce WAL file size
 	if err := sqliteStore.CheckpointWAL(ctx); err != nil {
 		// Non-fatal - just log warning
@@ -187,6 +303,26 @@ func ImportIssues(ctx context.Context, dbPath string, store storage.Storage, iss
 	return result, nil
 }
 
+// applySubset replaces the incoming issues outside the clone's subset with
+// stubs, in place. Returns the stubbed IDs and the IDs held in full; both are
+// nil for a full clone.
+func applySubset(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues []*types.Issue) (stubIDs, fullIDs []string, err error) {
+	filter, err := subset.Load(ctx, sqliteStore)
+	if err != nil || filter == nil {
+		return nil, nil, err
+	}
+	for i, issue := range issues {
+		if filter.Match(issue) || issue.IsTombstone() {
+			fullIDs = append(fullIDs, issue.ID)
+			continue
+		}
+		issues[i] = subset.Stub(issue)
+		issues[i].ContentHash = issues[i].ComputeContentHash()
+		stubIDs = append(stubIDs, issue.ID)
+	}
+	return stubIDs, fullIDs, nil
+}
+
 // getOrCreateStore returns an existing storage or creates a new one
 func getOrCreateStore(ctx context.Context, dbPath string, store storage.Storage) (*sqlite.SQLiteStorage, bool, error) {
 	if store != nil {
@@ -209,6 +345,65 @@ func getOrCreateStore(ctx context.Context, dbPath string, store storage.Storage)
 	return sqliteStore, true, nil
 }
 
+// skipLocked drops incoming issues that actor may not change because they
+// are locked locally or the project is frozen. Issues that match the local
+// copy pass through, since importing them changes nothing.
+func skipLocked(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues []*types.Issue, actor string, result *Result) ([]*types.Issue, error) {
+	locked := func(id string) (bool, string, error) {
+		labels, err := sqliteStore.GetLabels(ctx, id)
+		if err != nil {
+			return false, "", err
+		}
+		for _, l := range labels {
+			if l == lock.Label {
+				return true, "", nil
+			}
+		}
+		return false, "", nil
+	}
+
+	kept := issues[:0:0]
+	for _, issue := range issues {
+		err := lock.Check(ctx, issue.ID, actor, locked)
+		var lockErr *lock.Error
+		if !errors.As(err, &lockErr) {
+			if err != nil {
+				return nil, err
+			}
+			kept = append(kept, issue)
+			continue
+		}
+		existing, err := sqliteStore.GetIssue(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check locked issue %s: %w", issue.ID, err)
+		}
+		if existing != nil && existing.ComputeContentHash() == issue.ContentHash {
+			labels, err := sqliteStore.GetLabels(ctx, issue.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check locked issue %s: %w", issue.ID, err)
+			}
+			if sameLabels(labels, issue.Labels) {
+				kept = append(kept, issue)
+				continue
+			}
+		}
+		result.Locked = append(result.Locked, issue.ID)
+		result.Skipped++
+	}
+	return kept, nil
+}
+
+// sameLabels reports whether a and b hold the same labels in any order.
+func sameLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
 // handlePrefixMismatch checks and handles prefix mismatches.
 // Returns a filtered issues slice with tombstoned issues having wrong prefixes removed.
 func handlePrefixMismatch(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues []*types.Issue, opts Options, result *Result) ([]*types.Issue, error) {
@@ -384,7 +579,7 @@ func handleRename(ctx context.Context, s *sqlite.SQLiteStorage, existing *types.
 			deletedID := ""
 			existingCheck, checkErr := s.GetIssue(ctx, existing.ID)
 			if checkErr == nil && existingCheck != nil {
-				if err := s.DeleteIssue(ctx, existing.ID); err != nil {
+				if err := s.DeleteIssue(ctx, existing.ID, "import-rename"); err != nil {
 					return "", fmt.Errorf("failed to delete old ID %s: %w", existing.ID, err)
 				}
 				deletedID = existing.ID
@@ -439,7 +634,7 @@ func handleRename(ctx context.Context, s *sqlite.SQLiteStorage, existing *types.
 
 			// Delete old ID (only on first attempt)
 			if attempt == 0 {
-				if err := s.DeleteIssue(ctx, oldID); err != nil {
+				if err := s.DeleteIssue(ctx, oldID, "import-rename-collision"); err != nil {
 					return "", fmt.Errorf("failed to delete old ID %s: %w", oldID, err)
 				}
 			}
@@ -487,7 +682,7 @@ func handleRename(ctx context.Context, s *sqlite.SQLiteStorage, existing *types.
 
 	// Delete old ID
 	oldID := existing.ID
-	if err := s.DeleteIssue(ctx, oldID); err != nil {
+	if err := s.DeleteIssue(ctx, oldID, "import-rename"); err != nil {
 		return "", fmt.Errorf("failed to delete old ID %s: %w", oldID, err)
 	}
 
@@ -541,7 +736,11 @@ func upsertIssues(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues
 	seenHashes := make(map[string]bool)
 	seenIDs := make(map[string]bool) // Track IDs to prevent UNIQUE constraint errors
 
-	for _, incoming := range issues {
+	for i, incoming := range issues {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		opts.progress(i, len(issues))
 		hash := incoming.ContentHash
 		if hash == "" {
 			// Shouldn't happen (computed earlier), but be defensive
@@ -572,6 +771,21 @@ func upsertIssues(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues
 				result.Skipped++
 				continue
 			}
+			// A deletion from another clone wins over local edits, as in the
+			// 3-way merge, unless the tombstone has already expired. Applying it
+			// through the normal update path would lose deleted_at/deleted_by
+			// and let a newer local edit resurrect the issue.
+			if incoming.IsTombstone() && !incoming.IsExpired(0) {
+				if opts.SkipUpdate {
+					result.Skipped++
+					continue
+				}
+				if err := sqliteStore.ApplyTombstone(ctx, incoming, "import"); err != nil {
+					return fmt.Errorf("error applying tombstone %s: %w", incoming.ID, err)
+				}
+				result.Updated++
+				continue
+			}
 		}
 
 		// Phase 0: Match by external_ref first (if present)
@@ -749,6 +963,7 @@ func upsertIssues(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues
 	// Filter out orphaned issues if orphan_handling is set to skip
 	// Pre-filter before batch creation to prevent orphans from being created then ID-cleared
 	if opts.OrphanHandling == sqlite.OrphanSkip {
+		newByID := buildIDMap(newIssues)
 		var filteredNewIssues []*types.Issue
 		for _, issue := range newIssues {
 			// Check if this is a hierarchical child whose parent doesn't exist
@@ -757,21 +972,7 @@ func upsertIssues(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues
 				parentID := issue.ID[:lastDot]
 
 				// Check if parent exists in either existing DB issues or in newIssues batch
-				var parentExists bool
-				for _, dbIssue := range dbIssues {
-					if dbIssue.ID == parentID {
-						parentExists = true
-						break
-					}
-				}
-				if !parentExists {
-					for _, newIssue := range newIssues {
-						if newIssue.ID == parentID {
-							parentExists = true
-							break
-						}
-					}
-				}
+				parentExists := dbByID[parentID] != nil || newByID[parentID] != nil
 
 				if !parentExists {
 					// Skip this orphaned issue
@@ -817,27 +1018,32 @@ func upsertIssues(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues
 		}
 	}
 
+	opts.progress(len(issues), len(issues))
+
 	// REMOVED: Counter sync after import - no longer needed with hash IDs
 
 	return nil
 }
 
-// importDependencies imports dependency relationships
+// importDependencies imports dependency relationships.
+// New dependencies are added in a single batch so the blocked cache is
+// rebuilt once rather than once per dependency.
 func importDependencies(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues []*types.Issue, opts Options, result *Result) error {
+	// Fetch all existing dependencies once
+	existingDeps, err := sqliteStore.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return fmt.Errorf("error checking existing dependencies: %w", err)
+	}
+
+	var newDeps []*types.Dependency
 	for _, issue := range issues {
 		if len(issue.Dependencies) == 0 {
 			continue
 		}
 
-		// Fetch existing dependencies once per issue
-		existingDeps, err := sqliteStore.GetDependencyRecords(ctx, issue.ID)
-		if err != nil {
-			return fmt.Errorf("error checking dependencies for %s: %w", issue.ID, err)
-		}
-
 		// Build set of existing dependencies for O(1) lookup
 		existingSet := make(map[string]bool)
-		for _, existing := range existingDeps {
+		for _, existing := range existingDeps[issue.ID] {
 			key := fmt.Sprintf("%s|%s", existing.DependsOnID, existing.Type)
 			existingSet[key] = true
 		}
@@ -848,69 +1054,55 @@ func importDependencies(ctx context.Context, sqliteStore *sqlite.SQLiteStorage,
 			if existingSet[key] {
 				continue
 			}
+			newDeps = append(newDeps, dep)
+		}
+	}
 
-			// Add dependency
-			if err := sqliteStore.AddDependency(ctx, dep, "import"); err != nil {
-				// Check for FOREIGN KEY constraint violation
-				if sqlite.IsForeignKeyConstraintError(err) {
-					// Log warning and track skipped dependency
-					depDesc := fmt.Sprintf("%s → %s (%s)", dep.IssueID, dep.DependsOnID, dep.Type)
-					fmt.Fprintf(os.Stderr, "Warning: Skipping dependency due to missing reference: %s\n", depDesc)
-					if result != nil {
-						result.SkippedDependencies = append(result.SkippedDependencies, depDesc)
-					}
-					continue
-				}
-
-				// For non-FK errors, respect strict mode
-				if opts.Strict {
-					return fmt.Errorf("error adding dependency %s → %s: %w", dep.IssueID, dep.DependsOnID, err)
-				}
-				continue
+	return sqliteStore.AddDependencies(ctx, newDeps, "import", func(dep *types.Dependency, err error) error {
+		// Check for FOREIGN KEY constraint violation
+		if sqlite.IsForeignKeyConstraintError(err) {
+			// Log warning and track skipped dependency
+			depDesc := fmt.Sprintf("%s → %s (%s)", dep.IssueID, dep.DependsOnID, dep.Type)
+			fmt.Fprintf(os.Stderr, "Warning: Skipping dependency due to missing reference: %s\n", depDesc)
+			if result != nil {
+				result.SkippedDependencies = append(result.SkippedDependencies, depDesc)
 			}
+			return nil
 		}
-	}
 
-	return nil
+		// For non-FK errors, respect strict mode
+		if opts.Strict {
+			return fmt.Errorf("error adding dependency %s → %s: %w", dep.IssueID, dep.DependsOnID, err)
+		}
+		return nil
+	})
 }
 
-// importLabels imports labels for issues
+// importLabels imports labels for issues.
+// Labels the issue already has are ignored by the store, so there is no need
+// to read current labels first.
 func importLabels(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues []*types.Issue, opts Options) error {
+	var labels []types.Label
 	for _, issue := range issues {
-		if len(issue.Labels) == 0 {
-			continue
-		}
-
-		// Get current labels
-		currentLabels, err := sqliteStore.GetLabels(ctx, issue.ID)
-		if err != nil {
-			return fmt.Errorf("error getting labels for %s: %w", issue.ID, err)
-		}
-
-		currentLabelSet := make(map[string]bool)
-		for _, label := range currentLabels {
-			currentLabelSet[label] = true
-		}
-
-		// Add missing labels
 		for _, label := range issue.Labels {
-			if !currentLabelSet[label] {
-				if err := sqliteStore.AddLabel(ctx, issue.ID, label, "import"); err != nil {
-					if opts.Strict {
-						return fmt.Errorf("error adding label %s to %s: %w", label, issue.ID, err)
-					}
-					continue
-				}
-			}
+			labels = append(labels, types.Label{IssueID: issue.ID, Label: label})
 		}
 	}
 
-	return nil
+	return sqliteStore.AddLabels(ctx, labels, "import", func(l types.Label, err error) error {
+		if opts.Strict {
+			return fmt.Errorf("error adding label %s to %s: %w", l.Label, l.IssueID, err)
+		}
+		return nil
+	})
 }
 
 // importComments imports comments for issues
 func importComments(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues []*types.Issue, opts Options) error {
 	for _, issue := range issues {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if len(issue.Comments) == 0 {
 			continue
 		}
@@ -948,6 +1140,28 @@ func importComments(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issu
 	return nil
 }
 
+// importExtras replaces the metadata each imported issue keeps outside the
+// issues table with what the JSONL carries, so removals replicate as well as
+// additions. Issues with unexported local changes keep theirs until the next
+// export.
+func importExtras(ctx context.Context, sqliteStore *sqlite.SQLiteStorage, issues []*types.Issue, dirty map[string]bool, opts Options) error {
+	if opts.DryRun {
+		return nil
+	}
+	clean := make([]*types.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if !dirty[issue.ID] {
+			clean = append(clean, issue)
+		}
+	}
+	return sqliteStore.ImportIssueExtras(ctx, clean, "import", func(_ *types.Issue, err error) error {
+		if opts.Strict {
+			return err
+		}
+		return nil
+	})
+}
+
 // shouldProtectFromUpdate checks if an update should be skipped due to timestamp-aware protection (GH#865).
 // Returns true if the update should be skipped (local is newer), false if the update should proceed.
 // If the issue is not in the protection map, returns false (allow update).
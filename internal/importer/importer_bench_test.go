@@ -0,0 +1,90 @@
+//go:build bench
+
+package importer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// benchJSONLIssues builds issues shaped like a real JSONL export: half carry
+// labels, a third block on the previous issue, and a tenth have a comment.
+func benchJSONLIssues(n int) []*types.Issue {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	issues := make([]*types.Issue, n)
+	for i := range issues {
+		id := fmt.Sprintf("bench-%d", i+1)
+		issue := &types.Issue{
+			ID:          id,
+			Title:       fmt.Sprintf("Imported issue %d", i+1),
+			Description: "Imported from JSONL",
+			Status:      types.StatusOpen,
+			Priority:    i % 5,
+			IssueType:   types.TypeTask,
+			CreatedAt:   created,
+			UpdatedAt:   created,
+		}
+		if i%2 == 0 {
+			issue.Labels = []string{"team", fmt.Sprintf("area:%d", i%7)}
+		}
+		if i > 0 && i%3 == 0 {
+			issue.Dependencies = []*types.Dependency{{
+				IssueID:     id,
+				DependsOnID: fmt.Sprintf("bench-%d", i),
+				Type:        types.DepBlocks,
+				CreatedAt:   created,
+			}}
+		}
+		if i%10 == 0 {
+			issue.Comments = []*types.Comment{{Author: "bench", Text: "imported", CreatedAt: created}}
+		}
+		issues[i] = issue
+	}
+	return issues
+}
+
+func benchmarkImport(b *testing.B, n int) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		dbPath := filepath.Join(b.TempDir(), "import.db")
+		store, err := sqlite.New(ctx, dbPath)
+		if err != nil {
+			b.Fatalf("Failed to create storage: %v", err)
+		}
+		if err := store.SetConfig(ctx, "issue_prefix", "bench"); err != nil {
+			b.Fatalf("Failed to set issue_prefix: %v", err)
+		}
+		issues := benchJSONLIssues(n)
+		b.StartTimer()
+
+		result, err := ImportIssues(ctx, dbPath, store, issues, Options{})
+		if err != nil {
+			b.Fatalf("ImportIssues failed: %v", err)
+		}
+		if result.Created != n {
+			b.Fatalf("expected %d created, got %d", n, result.Created)
+		}
+
+		b.StopTimer()
+		_ = store.Close()
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N*n), "µs/issue")
+}
+
+// BenchmarkImportIssues_1K benchmarks importing 1K issues into an empty database
+func BenchmarkImportIssues_1K(b *testing.B) { benchmarkImport(b, 1000) }
+
+// BenchmarkImportIssues_10K benchmarks importing 10K issues into an empty database
+func BenchmarkImportIssues_10K(b *testing.B) { benchmarkImport(b, 10000) }
+
+// BenchmarkImportIssues_50K benchmarks importing 50K issues into an empty database
+func BenchmarkImportIssues_50K(b *testing.B) { benchmarkImport(b, 50000) }
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/lock"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
 	"github.com/steveyegge/beads/internal/types"
 )
@@ -1145,6 +1146,88 @@ func TestImportIssues_TombstoneFromJSONL(t *testing.T) {
 	}
 }
 
+// TestImportIssues_TombstoneDeletesLiveIssue verifies that a deletion made on
+// another clone replicates: the local issue becomes the same tombstone even
+// when it was edited after the delete, instead of resurrecting on next export.
+func TestImportIssues_TombstoneDeletesLiveIssue(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDB := t.TempDir() + "/test.db"
+	store, err := sqlite.New(context.Background(), tmpDB)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Fatalf("Failed to set prefix: %v", err)
+	}
+
+	live := &types.Issue{
+		ID:        "test-abc123",
+		Title:     "Edited locally",
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeBug,
+	}
+	if err := store.CreateIssue(ctx, live, "alice"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	blocked := &types.Issue{ID: "test-def456", Title: "Blocked", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, blocked, "alice"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: blocked.ID, DependsOnID: live.ID, Type: types.DepBlocks}, "alice"); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+
+	// The other clone deleted it before the local edit
+	deletedAt := time.Now().Add(-time.Hour)
+	tombstone := &types.Issue{
+		ID:           "test-abc123",
+		Title:        "Original title",
+		Status:       types.StatusTombstone,
+		Priority:     2,
+		IssueType:    types.TypeBug,
+		CreatedAt:    time.Now().Add(-24 * time.Hour),
+		UpdatedAt:    deletedAt,
+		DeletedAt:    &deletedAt,
+		DeletedBy:    "bob",
+		DeleteReason: "duplicate",
+		OriginalType: "bug",
+	}
+
+	result, err := ImportIssues(ctx, tmpDB, store, []*types.Issue{tombstone}, Options{})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Updated != 1 {
+		t.Errorf("Expected 1 updated, got %d", result.Updated)
+	}
+
+	retrieved, err := store.GetIssue(ctx, "test-abc123")
+	if err != nil || retrieved == nil {
+		t.Fatalf("Failed to get issue: %v", err)
+	}
+	if retrieved.Status != types.StatusTombstone {
+		t.Fatalf("Expected status 'tombstone', got %q", retrieved.Status)
+	}
+	if retrieved.DeletedBy != "bob" || retrieved.DeleteReason != "duplicate" {
+		t.Errorf("Expected deletion by bob for 'duplicate', got %q/%q", retrieved.DeletedBy, retrieved.DeleteReason)
+	}
+	if retrieved.DeletedAt == nil || !retrieved.DeletedAt.Equal(deletedAt) {
+		t.Errorf("Expected DeletedAt %v, got %v", deletedAt, retrieved.DeletedAt)
+	}
+
+	deps, err := store.GetDependencies(ctx, blocked.ID)
+	if err != nil {
+		t.Fatalf("Failed to get dependencies: %v", err)
+	}
+	if len(deps) != 0 {
+		t.Errorf("Expected dependency on the deleted issue to be removed, got %d", len(deps))
+	}
+}
+
 // TestImportOrphanSkip_CountMismatch verifies that orphaned issues are properly
 // skipped during import and tracked in the result count (bd-ckej).
 //
@@ -1799,3 +1882,106 @@ func TestBuildAllowedPrefixSet(t *testing.T) {
 		}
 	})
 }
+
+func TestImportIssues_SubsetStubsNonMatchingIssues(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDB := t.TempDir() + "/test.db"
+	store, err := sqlite.New(context.Background(), tmpDB)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Fatalf("Failed to set prefix: %v", err)
+	}
+	if err := store.SetConfig(ctx, "subset", "label:frontend"); err != nil {
+		t.Fatalf("Failed to set subset: %v", err)
+	}
+
+	now := time.Now()
+	issues := []*types.Issue{
+		{ID: "test-fe1", Title: "Button", Description: "Make it blue", Status: types.StatusOpen, Priority: 2,
+			IssueType: types.TypeTask, Labels: []string{"frontend"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "test-be1", Title: "Schema", Description: "Add a column", Notes: "See RFC", Status: types.StatusOpen, Priority: 1,
+			IssueType: types.TypeTask, Labels: []string{"backend"}, CreatedAt: now, UpdatedAt: now},
+	}
+	if _, err := ImportIssues(ctx, tmpDB, store, issues, Options{}); err != nil {
+		t.Fatalf("ImportIssues failed: %v", err)
+	}
+
+	fe, _ := store.GetIssue(ctx, "test-fe1")
+	if fe == nil || fe.Description != "Make it blue" {
+		t.Errorf("matching issue should be imported in full, got %+v", fe)
+	}
+	be, _ := store.GetIssue(ctx, "test-be1")
+	if be == nil || be.Title != "Schema" || be.Description != "" || be.Notes != "" {
+		t.Errorf("non-matching issue should be a stub with its title, got %+v", be)
+	}
+
+	stubs, err := store.GetStubIDs(ctx)
+	if err != nil {
+		t.Fatalf("GetStubIDs failed: %v", err)
+	}
+	if !stubs["test-be1"] || stubs["test-fe1"] {
+		t.Errorf("stub IDs = %v, want only test-be1", stubs)
+	}
+}
+
+func TestImportIssues_SkipsLocked(t *testing.T) {
+	ctx := context.Background()
+	tmpDB := t.TempDir() + "/test.db"
+	store, err := sqlite.New(ctx, tmpDB)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Fatalf("Failed to set prefix: %v", err)
+	}
+
+	issue := &types.Issue{ID: "test-1", Title: "Release notes", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddLabel(ctx, "test-1", lock.Label, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	incoming := func(title string) []*types.Issue {
+		return []*types.Issue{
+			{ID: "test-1", Title: title, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, Labels: []string{lock.Label}, UpdatedAt: time.Now().Add(time.Hour)},
+			{ID: "test-2", Title: "New", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		}
+	}
+
+	// An explicit import leaves the locked issue alone
+	result, err := ImportIssues(ctx, tmpDB, store, incoming("Changed"), Options{LockActor: "alice"})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(result.Locked) != 1 || result.Locked[0] != "test-1" || result.Created != 1 {
+		t.Errorf("Locked = %v, Created = %d; want test-1 skipped and test-2 created", result.Locked, result.Created)
+	}
+	if got, _ := store.GetIssue(ctx, "test-1"); got.Title != "Release notes" {
+		t.Errorf("locked issue changed to %q", got.Title)
+	}
+
+	// Unchanged locked issues aren't reported
+	result, err = ImportIssues(ctx, tmpDB, store, incoming("Release notes"), Options{LockActor: "alice"})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(result.Locked) != 0 {
+		t.Errorf("Locked = %v for an unchanged issue", result.Locked)
+	}
+
+	// Sync replicates changes made where they were allowed
+	if _, err := ImportIssues(ctx, tmpDB, store, incoming("Changed"), Options{}); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if got, _ := store.GetIssue(ctx, "test-1"); got.Title != "Changed" {
+		t.Errorf("sync import didn't apply the change, title %q", got.Title)
+	}
+}
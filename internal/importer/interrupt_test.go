@@ -0,0 +1,52 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestImportIssuesInterrupted(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	var issues []*types.Issue
+	for i := 0; i < 5; i++ {
+		issues = append(issues, &types.Issue{
+			ID: fmt.Sprintf("bd-%d", i), Title: fmt.Sprintf("issue %d", i), Status: types.StatusOpen, IssueType: types.TypeTask,
+			CreatedAt: time.Now(), UpdatedAt: time.Now(),
+		})
+	}
+
+	cause := errors.New("timed out after 1s")
+	cctx, cancel := context.WithCancelCause(ctx)
+	cancel(cause)
+
+	result, err := ImportIssues(cctx, "", store, issues, Options{})
+	var ie *InterruptedError
+	if !errors.As(err, &ie) {
+		t.Fatalf("err = %v, want *InterruptedError", err)
+	}
+	if result == nil || ie.Total != 5 || !errors.Is(err, cause) {
+		t.Errorf("result = %+v, err = %+v", result, ie)
+	}
+
+	// Nothing was half-written; a normal re-run imports everything
+	result, err = ImportIssues(ctx, "", store, issues, Options{})
+	if err != nil || result.Created != 5 {
+		t.Fatalf("re-run = %+v, %v", result, err)
+	}
+}
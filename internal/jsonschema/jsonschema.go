@@ -0,0 +1,231 @@
+// Package jsonschema generates JSON Schema (draft 2020-12) documents from Go
+// types by following their encoding/json struct tags.
+//
+// It covers the subset of Go types that appear in bd's JSON output: structs
+// (including embedded structs), pointers, slices, maps with string keys,
+// strings, numbers, booleans, time.Time, time.Duration and json.RawMessage.
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"` // string or []string
+	Format               string             `json:"format,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+
+	// Extensions holds "x-" keywords merged into the top level of the
+	// encoded document.
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON encodes the schema with its extension keywords inlined.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	data, err := json.Marshal((*plain)(s))
+	if err != nil || len(s.Extensions) == 0 {
+		return data, err
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for k, v := range s.Extensions {
+		merged[k] = v
+	}
+	return json.Marshal(merged)
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	rawType      = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// Reflect returns a schema document describing the JSON encoding of v's
+// type. Named struct types are emitted once under $defs and referenced by
+// $ref, so recursive types are supported.
+func Reflect(v interface{}) *Schema {
+	return ReflectType(reflect.TypeOf(v))
+}
+
+// ReflectType is like Reflect but takes a reflect.Type.
+func ReflectType(t reflect.Type) *Schema {
+	r := &reflector{defs: make(map[string]*Schema)}
+	root := r.schemaFor(t)
+	root.Schema = Draft
+	if len(r.defs) > 0 {
+		root.Defs = r.defs
+	}
+	return root
+}
+
+type reflector struct {
+	defs map[string]*Schema
+}
+
+func (r *reflector) schemaFor(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Description: "Duration in nanoseconds"}
+	case rawType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return r.schemaFor(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		name := defName(t)
+		if _, ok := r.defs[name]; !ok {
+			r.defs[name] = nil // placeholder breaks recursion
+			r.defs[name] = r.structSchema(t)
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	default:
+		// interface{} and anything else: accept any value
+		return &Schema{}
+	}
+}
+
+func (r *reflector) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addFields(s, t)
+	return s
+}
+
+// addFields adds t's JSON-visible fields to s, flattening embedded structs
+// the way encoding/json does. Fields declared on t win over promoted ones.
+func (r *reflector) addFields(s *Schema, t reflect.Type) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		prop := r.schemaFor(f.Type)
+		omitempty := strings.Contains(opts, "omitempty")
+		if !omitempty && nullable(f.Type) {
+			prop = &Schema{AnyOf: []*Schema{prop, {Type: "null"}}}
+		}
+		s.Properties[name] = prop
+		if !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+	for _, et := range embedded {
+		promoted := &Schema{Properties: make(map[string]*Schema)}
+		r.addFields(promoted, et)
+		shadowed := make(map[string]bool)
+		for name, prop := range promoted.Properties {
+			if _, ok := s.Properties[name]; ok {
+				shadowed[name] = true
+				continue
+			}
+			s.Properties[name] = prop
+		}
+		for _, name := range promoted.Required {
+			if !shadowed[name] {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+}
+
+// nullable reports whether a field of type t can encode as null.
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Interface:
+		return true
+	case reflect.Slice:
+		return t != rawType && t.Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+func defName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	name := genericName(t.Name())
+	if pkg == "" || pkg == "main" || pkg == "types" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// genericName turns an instantiated generic type name such as
+// "IssuePage[*github.com/x/types.Issue]" into a $ref-safe "IssuePage_Issue".
+func genericName(name string) string {
+	open := strings.IndexByte(name, '[')
+	if open < 0 || !strings.HasSuffix(name, "]") {
+		return name
+	}
+	parts := []string{name[:open]}
+	for _, arg := range strings.Split(name[open+1:len(name)-1], ",") {
+		arg = strings.TrimLeft(strings.TrimSpace(arg), "*[]")
+		if i := strings.LastIndex(arg, "."); i >= 0 {
+			arg = arg[i+1:]
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, "_")
+}
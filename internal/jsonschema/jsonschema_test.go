@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type inner struct {
+	Name string `json:"name"`
+}
+
+type base struct {
+	ID      string `json:"id"`
+	Labels  []string
+	Comment string `json:"comment,omitempty"`
+	hidden  int
+}
+
+type node struct {
+	*base
+	Comment  int              `json:"comment"` // shadows base.Comment
+	Parent   *node            `json:"parent,omitempty"`
+	When     time.Time        `json:"when"`
+	Timeout  time.Duration    `json:"timeout,omitempty"`
+	Inner    *inner           `json:"inner"`
+	Meta     map[string]int   `json:"meta,omitempty"`
+	Raw      json.RawMessage  `json:"raw,omitempty"`
+	Any      interface{}      `json:"any,omitempty"`
+	Skipped  string           `json:"-"`
+	Children []*node          `json:"children,omitempty"`
+	Score    *float32         `json:"score,omitempty"`
+	Tags     map[string]inner `json:"tags,omitempty"`
+}
+
+func TestReflect(t *testing.T) {
+	s := Reflect([]*node{})
+	if s.Schema != Draft || s.Type != "array" || s.Items.Ref != "#/$defs/jsonschema.node" {
+		t.Fatalf("unexpected root: %+v", s)
+	}
+
+	n := s.Defs["jsonschema.node"]
+	if n == nil {
+		t.Fatalf("missing node def: %v", s.Defs)
+	}
+	wantProps := []string{"Labels", "any", "children", "comment", "id", "inner", "meta", "parent", "raw", "score", "tags", "timeout", "when"}
+	var gotProps []string
+	for name := range n.Properties {
+		gotProps = append(gotProps, name)
+	}
+	sort.Strings(gotProps)
+	if !reflect.DeepEqual(gotProps, wantProps) {
+		t.Errorf("properties = %v, want %v", gotProps, wantProps)
+	}
+
+	if got := n.Properties["comment"].Type; got != "integer" {
+		t.Errorf("shadowed comment type = %v, want integer", got)
+	}
+	if got := n.Properties["when"]; got.Type != "string" || got.Format != "date-time" {
+		t.Errorf("time field = %+v", got)
+	}
+	if got := n.Properties["parent"].Ref; got != "#/$defs/jsonschema.node" {
+		t.Errorf("recursive ref = %q", got)
+	}
+	if got := n.Properties["inner"]; len(got.AnyOf) != 2 || got.AnyOf[0].Ref != "#/$defs/jsonschema.inner" || got.AnyOf[1].Type != "null" {
+		t.Errorf("non-omitempty pointer should be nullable: %+v", got)
+	}
+	if got := n.Properties["tags"].AdditionalProperties.Ref; got != "#/$defs/jsonschema.inner" {
+		t.Errorf("map value ref = %q", got)
+	}
+
+	wantReq := []string{"comment", "when", "inner", "id", "Labels"}
+	if !reflect.DeepEqual(n.Required, wantReq) {
+		t.Errorf("required = %v, want %v", n.Required, wantReq)
+	}
+}
+
+func TestSchemaExtensions(t *testing.T) {
+	s := Reflect("")
+	s.Extensions = map[string]interface{}{"x-output-version": 1}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"x-output-version":1`, `"type":"string"`, `"$schema"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s missing %s", data, want)
+		}
+	}
+}
+
+type page[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestReflectGeneric(t *testing.T) {
+	s := Reflect(page[*inner]{})
+	if s.Ref != "#/$defs/jsonschema.page_inner" {
+		t.Fatalf("generic ref = %q, defs %v", s.Ref, s.Defs)
+	}
+	if _, ok := s.Defs["jsonschema.inner"]; !ok {
+		t.Errorf("missing type argument def: %v", s.Defs)
+	}
+}
@@ -0,0 +1,112 @@
+// Package labeler labels issues from their text. Keyword rules from config
+// label new issues as they are created or imported; a nearest-neighbour
+// classifier over already-labeled issues suggests labels on demand.
+package labeler
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Rule adds Label to issues whose text contains any of its keywords.
+type Rule struct {
+	Label    string
+	Keywords []string
+	patterns []*regexp.Regexp
+}
+
+// Compile builds rules from label → keywords (see
+// config.GetAutoLabelRules). A keyword matches as a whole word or phrase,
+// ignoring case; /.../ is a regular expression instead. Rules with a bad
+// keyword are returned as errors and left out; the others still apply.
+func Compile(cfg map[string][]string) ([]*Rule, []error) {
+	labels := make([]string, 0, len(cfg))
+	for label := range cfg {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var rules []*Rule
+	var errs []error
+	for _, label := range labels {
+		rule := &Rule{Label: label}
+		for _, kw := range cfg[label] {
+			kw = strings.TrimSpace(kw)
+			if kw == "" {
+				continue
+			}
+			re, err := keywordPattern(kw)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("labels.auto.%s: %w", label, err))
+				rule = nil
+				break
+			}
+			rule.Keywords = append(rule.Keywords, kw)
+			rule.patterns = append(rule.patterns, re)
+		}
+		if rule == nil {
+			continue
+		}
+		if len(rule.Keywords) == 0 {
+			errs = append(errs, fmt.Errorf("labels.auto.%s: no keywords", label))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, errs
+}
+
+func keywordPattern(kw string) (*regexp.Regexp, error) {
+	if len(kw) > 2 && strings.HasPrefix(kw, "/") && strings.HasSuffix(kw, "/") {
+		re, err := regexp.Compile(kw[1 : len(kw)-1])
+		if err != nil {
+			return nil, fmt.Errorf("keyword %s: %w", kw, err)
+		}
+		return re, nil
+	}
+	words := strings.Fields(kw)
+	for i, w := range words {
+		words[i] = regexp.QuoteMeta(w)
+	}
+	return regexp.Compile(`(?i)(^|\W)` + strings.Join(words, `\s+`) + `($|\W)`)
+}
+
+// Match returns the first keyword of r found in text, or "".
+func (r *Rule) Match(text string) string {
+	for i, re := range r.patterns {
+		if re.MatchString(text) {
+			return r.Keywords[i]
+		}
+	}
+	return ""
+}
+
+// Text is the issue text rules and the classifier read: the title,
+// description, design, acceptance criteria and notes.
+func Text(issue *types.Issue) string {
+	return strings.Join([]string{issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes}, "\n")
+}
+
+// Apply returns the labels rules add to issue: those whose rule matches
+// and that aren't in have already.
+func Apply(rules []*Rule, issue *types.Issue, have []string) []string {
+	if len(rules) == 0 {
+		return nil
+	}
+	text := Text(issue)
+	var add []string
+	for _, r := range rules {
+		if slices.Contains(have, r.Label) || slices.Contains(add, r.Label) {
+			continue
+		}
+		if r.Match(text) != "" {
+			add = append(add, r.Label)
+		}
+	}
+	return add
+}
@@ -0,0 +1,84 @@
+package labeler
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestCompileAndApply(t *testing.T) {
+	rules, errs := Compile(map[string][]string{
+		"security": {"CVE", "sql injection"},
+		"flaky":    {"/flak(y|iness)/"},
+		"broken":   {"/(/"},
+		"empty":    {" "},
+	})
+	if len(errs) != 2 {
+		t.Errorf("errs = %v, want the broken and empty rules", errs)
+	}
+	if len(rules) != 2 || rules[0].Label != "flaky" || rules[1].Label != "security" {
+		t.Fatalf("rules = %+v", rules)
+	}
+
+	for _, tc := range []struct {
+		title, desc string
+		have        []string
+		want        string
+	}{
+		{"Patch cve-2024-1234", "", nil, "security"},
+		{"Login form", "Possible SQL\n injection in search", nil, "security"},
+		{"Recvent changes", "", nil, ""}, // "cve" inside a word doesn't match
+		{"Test is flaky", "", nil, "flaky"},
+		{"Patch CVE", "flakiness in CI", []string{"security"}, "flaky"},
+	} {
+		issue := &types.Issue{Title: tc.title, Description: tc.desc}
+		got := strings.Join(Apply(rules, issue, tc.have), ",")
+		if got != tc.want {
+			t.Errorf("Apply(%q, %q) = %q, want %q", tc.title, tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	issues := []*types.Issue{
+		{ID: "bd-1", Title: "Login page crashes on submit", Description: "The login form throws on submit", Labels: []string{"auth", "ui"}},
+		{ID: "bd-2", Title: "Password reset email never arrives", Description: "Reset login password flow", Labels: []string{"auth"}},
+		{ID: "bd-3", Title: "Database migration is slow", Description: "The schema migration takes minutes", Labels: []string{"db"}},
+		{ID: "bd-4", Title: "Unlabeled note about migration"},
+	}
+	ix := NewIndex(issues)
+	rules, _ := Compile(map[string][]string{"security": {"password"}})
+
+	target := &types.Issue{ID: "bd-9", Title: "Login submit button crashes", Description: "Submitting the login form fails; password field too", Labels: []string{"ui"}}
+	got := ix.Suggest(target, rules, 5, 0.2)
+	if len(got) < 2 {
+		t.Fatalf("suggestions = %+v", got)
+	}
+	if got[0].Label != "security" || got[0].Source != "rule" || got[0].Keyword != "password" || got[0].Score != 1 {
+		t.Errorf("first suggestion = %+v, want the security rule", got[0])
+	}
+	if got[1].Label != "auth" || got[1].Source != "similar" || got[1].Similar[0] != "bd-1" {
+		t.Errorf("second suggestion = %+v, want auth from bd-1", got[1])
+	}
+	for _, s := range got {
+		if s.Label == "ui" {
+			t.Error("suggested a label the issue already has")
+		}
+		if s.Label == "db" {
+			t.Errorf("suggested db for an auth issue: %+v", s)
+		}
+	}
+
+	// An indexed issue is not its own neighbour
+	for _, s := range ix.Suggest(issues[2], nil, 5, 0) {
+		if s.Label == "db" || slices.Contains(s.Similar, "bd-3") {
+			t.Errorf("bd-3 voted for itself: %+v", s)
+		}
+	}
+
+	if got := ix.Suggest(&types.Issue{ID: "bd-10", Title: "zzz"}, nil, 5, 0); got == nil || len(got) != 0 {
+		t.Errorf("unrelated issue suggestions = %#v, want an empty slice", got)
+	}
+}
@@ -0,0 +1,219 @@
+package labeler
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Suggestion is a label proposed for an issue.
+type Suggestion struct {
+	Label string  `json:"label"`
+	Score float64 `json:"score"` // 0..1; rule matches score 1
+	// Source is "rule" (a labels.auto keyword matched) or "similar"
+	// (labeled issues with similar text carry it).
+	Source  string   `json:"source"`
+	Keyword string   `json:"keyword,omitempty"`
+	Similar []string `json:"similar,omitempty"` // IDs of the issues that vote for it
+}
+
+// vector is a sparse, L2-normalized TF-IDF embedding of a text.
+type vector map[string]float64
+
+func (a vector) dot(b vector) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var sum float64
+	for term, w := range a {
+		sum += w * b[term]
+	}
+	return sum
+}
+
+// stopWords are too common in issue text to tell issues apart.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "but": true,
+	"by": true, "can": true, "do": true, "for": true, "from": true, "has": true, "have": true,
+	"if": true, "in": true, "into": true, "is": true, "it": true, "its": true, "not": true, "of": true,
+	"on": true, "or": true, "should": true, "so": true, "that": true, "the": true, "this": true,
+	"to": true, "was": true, "we": true, "when": true, "will": true, "with": true,
+}
+
+// terms splits text into lowercase words (minus stop words) and adjacent
+// word pairs, so "login page" and "page login" differ.
+func terms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var out []string
+	prev := ""
+	for _, w := range words {
+		if len(w) < 2 || stopWords[w] {
+			prev = ""
+			continue
+		}
+		out = append(out, w)
+		if prev != "" {
+			out = append(out, prev+" "+w)
+		}
+		prev = w
+	}
+	return out
+}
+
+type document struct {
+	id     string
+	labels []string
+	vec    vector
+}
+
+// Index embeds labeled issues so new ones can be compared with them.
+type Index struct {
+	docs []document
+	df   map[string]int // Issues each term occurs in
+	size int            // Issues indexed
+}
+
+// NewIndex embeds issues, which need their Labels loaded. Issues without
+// labels still count towards term weights but never vote.
+func NewIndex(issues []*types.Issue) *Index {
+	ix := &Index{df: make(map[string]int), size: len(issues)}
+	counts := make([]map[string]int, len(issues))
+	for i, issue := range issues {
+		counts[i] = make(map[string]int)
+		for _, t := range terms(Text(issue)) {
+			counts[i][t]++
+		}
+		for t := range counts[i] {
+			ix.df[t]++
+		}
+	}
+	for i, issue := range issues {
+		if len(issue.Labels) == 0 {
+			continue
+		}
+		ix.docs = append(ix.docs, document{id: issue.ID, labels: issue.Labels, vec: ix.weigh(counts[i])})
+	}
+	return ix
+}
+
+// weigh turns term counts into a normalized TF-IDF vector.
+func (ix *Index) weigh(counts map[string]int) vector {
+	n := float64(ix.size) + 1
+	vec := make(vector, len(counts))
+	var norm float64
+	for t, c := range counts {
+		idf := math.Log(n / float64(ix.df[t]+1))
+		if idf <= 0 {
+			continue
+		}
+		w := (1 + math.Log(float64(c))) * idf
+		vec[t] = w
+		norm += w * w
+	}
+	if norm == 0 {
+		return vec
+	}
+	norm = math.Sqrt(norm)
+	for t := range vec {
+		vec[t] /= norm
+	}
+	return vec
+}
+
+// embed returns the TF-IDF vector of text using the index's term weights.
+func (ix *Index) embed(text string) vector {
+	counts := make(map[string]int)
+	for _, t := range terms(text) {
+		counts[t]++
+	}
+	return ix.weigh(counts)
+}
+
+// similar returns up to k labeled issues most similar to issue, most
+// similar first, skipping the issue itself.
+func (ix *Index) similar(issue *types.Issue, k int) ([]document, []float64) {
+	vec := ix.embed(Text(issue))
+	type scored struct {
+		doc document
+		sim float64
+	}
+	var hits []scored
+	for _, d := range ix.docs {
+		if d.id == issue.ID {
+			continue
+		}
+		if sim := vec.dot(d.vec); sim > 0 {
+			hits = append(hits, scored{d, sim})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].sim > hits[j].sim })
+	if len(hits) > k {
+		hits = hits[:k]
+	}
+	docs := make([]document, len(hits))
+	sims := make([]float64, len(hits))
+	for i, h := range hits {
+		docs[i], sims[i] = h.doc, h.sim
+	}
+	return docs, sims
+}
+
+// Suggest proposes labels for issue that it doesn't have yet: those rules
+// add, then those carried by its k nearest labeled neighbours, scored by
+// their share of the neighbours' similarity. Suggestions scoring below
+// minScore are dropped.
+func (ix *Index) Suggest(issue *types.Issue, rules []*Rule, k int, minScore float64) []Suggestion {
+	have := make(map[string]bool, len(issue.Labels))
+	for _, l := range issue.Labels {
+		have[l] = true
+	}
+	suggestions := []Suggestion{}
+	text := Text(issue)
+	for _, r := range rules {
+		if have[r.Label] {
+			continue
+		}
+		if kw := r.Match(text); kw != "" {
+			suggestions = append(suggestions, Suggestion{Label: r.Label, Score: 1, Source: "rule", Keyword: kw})
+			have[r.Label] = true
+		}
+	}
+
+	docs, sims := ix.similar(issue, k)
+	var total float64
+	for _, s := range sims {
+		total += s
+	}
+	votes := make(map[string]*Suggestion)
+	var order []string
+	for i, d := range docs {
+		for _, l := range d.labels {
+			if have[l] {
+				continue
+			}
+			s := votes[l]
+			if s == nil {
+				s = &Suggestion{Label: l, Source: "similar"}
+				votes[l] = s
+				order = append(order, l)
+			}
+			s.Score += sims[i] / total
+			s.Similar = append(s.Similar, d.id)
+		}
+	}
+	var similar []Suggestion
+	for _, l := range order {
+		s := votes[l]
+		s.Score = math.Round(s.Score*100) / 100
+		if s.Score >= minScore {
+			similar = append(similar, *s)
+		}
+	}
+	sort.SliceStable(similar, func(i, j int) bool { return similar[i].Score > similar[j].Score })
+	return append(suggestions, similar...)
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/beads/internal/httpx"
 	"github.com/steveyegge/beads/internal/types"
 )
 
@@ -52,6 +53,16 @@ const IssuesQuery = `
 					id
 					identifier
 				}
+				estimate
+				cycle {
+					id
+					number
+					name
+				}
+				project {
+					id
+					name
+				}
 				relations {
 					nodes {
 						id
@@ -77,12 +88,10 @@ const IssuesQuery = `
 // NewClient creates a new Linear client with the given API key and team ID.
 func NewClient(apiKey, teamID string) *Client {
 	return &Client{
-		APIKey:   apiKey,
-		TeamID:   teamID,
-		Endpoint: DefaultAPIEndpoint,
-		HTTPClient: &http.Client{
-			Timeout: DefaultTimeout,
-		},
+		APIKey:     apiKey,
+		TeamID:     teamID,
+		Endpoint:   DefaultAPIEndpoint,
+		HTTPClient: httpx.NewClient(DefaultTimeout),
 	}
 }
 
@@ -122,72 +131,58 @@ func (c *Client) WithProjectID(projectID string) *Client {
 	}
 }
 
-// Execute sends a GraphQL request to the Linear API.
-// Handles rate limiting with exponential backoff.
+// Execute sends a GraphQL request to the Linear API. Rate-limited
+// requests are retried by the httpx transport, as are failed queries
+// (mutations are not replayed after a network error).
 func (c *Client) Execute(ctx context.Context, req *GraphQLRequest) (json.RawMessage, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
+	if !strings.HasPrefix(strings.TrimSpace(req.Query), "mutation") {
+		ctx = httpx.Replayable(ctx)
+	}
 
-	var lastErr error
-	for attempt := 0; attempt <= MaxRetries; attempt++ {
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", c.Endpoint, bytes.NewReader(body))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("Authorization", c.APIKey)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
-		resp, err := c.HTTPClient.Do(httpReq)
-		if err != nil {
-			lastErr = fmt.Errorf("request failed (attempt %d/%d): %w", attempt+1, MaxRetries+1, err)
-			continue
-		}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", c.APIKey)
 
-		respBody, err := io.ReadAll(resp.Body)
-		_ = resp.Body.Close()
-		if err != nil {
-			lastErr = fmt.Errorf("failed to read response (attempt %d/%d): %w", attempt+1, MaxRetries+1, err)
-			continue
-		}
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
 
-		if resp.StatusCode == http.StatusTooManyRequests {
-			delay := RetryDelay * time.Duration(1<<attempt) // Exponential backoff
-			lastErr = fmt.Errorf("rate limited (attempt %d/%d), retrying after %v", attempt+1, MaxRetries+1, delay)
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(delay):
-				continue
-			}
-		}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return nil, fmt.Errorf("API error: %s (status %d)", string(respBody), resp.StatusCode)
-		}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("API error: %s (status %d)", string(respBody), resp.StatusCode)
+	}
 
-		var gqlResp struct {
-			Data   json.RawMessage `json:"data"`
-			Errors []GraphQLError  `json:"errors,omitempty"`
-		}
-		if err := json.Unmarshal(respBody, &gqlResp); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w (body: %s)", err, string(respBody))
-		}
+	var gqlResp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []GraphQLError  `json:"errors,omitempty"`
+	}
+	if err := json.Unmarshal(respBody, &gqlResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w (body: %s)", err, string(respBody))
+	}
 
-		if len(gqlResp.Errors) > 0 {
-			errMsgs := make([]string, len(gqlResp.Errors))
-			for i, e := range gqlResp.Errors {
-				errMsgs[i] = e.Message
-			}
-			return nil, fmt.Errorf("GraphQL errors: %s", strings.Join(errMsgs, "; "))
+	if len(gqlResp.Errors) > 0 {
+		errMsgs := make([]string, len(gqlResp.Errors))
+		for i, e := range gqlResp.Errors {
+			errMsgs[i] = e.Message
 		}
-
-		return gqlResp.Data, nil
+		return nil, fmt.Errorf("GraphQL errors: %s", strings.Join(errMsgs, "; "))
 	}
 
-	return nil, fmt.Errorf("max retries (%d) exceeded: %w", MaxRetries+1, lastErr)
+	return gqlResp.Data, nil
 }
 
 // FetchIssues retrieves issues from Linear with optional filtering by state.
@@ -389,6 +384,13 @@ func (c *Client) GetTeamStates(ctx context.Context) ([]State, error) {
 
 // CreateIssue creates a new issue in Linear.
 func (c *Client) CreateIssue(ctx context.Context, title, description string, priority int, stateID string, labelIDs []string) (*Issue, error) {
+	return c.CreateIssueWithFields(ctx, title, description, priority, stateID, labelIDs, nil)
+}
+
+// CreateIssueWithFields creates a new issue in Linear, adding extra
+// IssueCreateInput fields such as estimate or projectId. Extra fields
+// override the client's default project.
+func (c *Client) CreateIssueWithFields(ctx context.Context, title, description string, priority int, stateID string, labelIDs []string, fields map[string]interface{}) (*Issue, error) {
 	query := `
 		mutation CreateIssue($input: IssueCreateInput!) {
 			issueCreate(input: $input) {
@@ -435,6 +437,10 @@ func (c *Client) CreateIssue(ctx context.Context, title, description string, pri
 		input["labelIds"] = labelIDs
 	}
 
+	for k, v := range fields {
+		input[k] = v
+	}
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
@@ -538,6 +544,16 @@ func (c *Client) FetchIssueByIdentifier(ctx context.Context, identifier string)
 							name
 						}
 					}
+					estimate
+					cycle {
+						id
+						number
+						name
+					}
+					project {
+						id
+						name
+					}
 					createdAt
 					updatedAt
 					completedAt
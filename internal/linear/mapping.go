@@ -2,6 +2,7 @@ package linear
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -137,6 +138,14 @@ type MappingConfig struct {
 	// RelationMap maps Linear relation types to Beads dependency types.
 	// Key is Linear relation type, value is Beads dependency type.
 	RelationMap map[string]string
+
+	// ProjectMap maps Linear projects to Beads components.
+	// Key is lowercase project ID or name, value is the component name.
+	ProjectMap map[string]string
+
+	// EstimateMinutes is how many minutes one Linear estimate point is worth
+	// when converting to and from estimated_minutes. 0 disables the mapping.
+	EstimateMinutes int
 }
 
 // DefaultMappingConfig returns sensible default mappings.
@@ -177,6 +186,8 @@ func DefaultMappingConfig() *MappingConfig {
 			"duplicate": "duplicates",
 			"related":   "related",
 		},
+		ProjectMap:      map[string]string{},
+		EstimateMinutes: 60,
 	}
 }
 
@@ -194,6 +205,8 @@ type ConfigLoader interface {
 //	linear.state_map.started = in_progress
 //	linear.label_type_map.bug = bug
 //	linear.relation_map.blocks = blocks
+//	linear.project_map.<project id or name> = api   (component)
+//	linear.estimate_minutes = 60    (minutes per estimate point)
 func LoadMappingConfig(loader ConfigLoader) *MappingConfig {
 	config := DefaultMappingConfig()
 
@@ -233,6 +246,18 @@ func LoadMappingConfig(loader ConfigLoader) *MappingConfig {
 			relationType := strings.TrimPrefix(key, "linear.relation_map.")
 			config.RelationMap[relationType] = value
 		}
+
+		// Parse project-to-component mappings: linear.project_map.<project_id_or_name>
+		if strings.HasPrefix(key, "linear.project_map.") {
+			projectKey := strings.ToLower(strings.TrimPrefix(key, "linear.project_map."))
+			config.ProjectMap[projectKey] = value
+		}
+
+		if key == "linear.estimate_minutes" {
+			if minutes, err := parseIntValue(value); err == nil && minutes >= 0 {
+				config.EstimateMinutes = minutes
+			}
+		}
 	}
 
 	return config
@@ -387,6 +412,85 @@ func RelationToBeadsDep(relationType string, config *MappingConfig) string {
 	return "related" // Default fallback
 }
 
+// CycleLabel returns the label that records an issue's Linear cycle,
+// following the <dimension>:<value> label convention.
+func CycleLabel(cycle *Cycle) string {
+	if cycle == nil || cycle.Number <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("cycle:%d", cycle.Number)
+}
+
+// ProjectToComponent returns the Beads component a Linear project maps to,
+// matching linear.project_map.* by project ID first, then by name.
+func ProjectToComponent(project *Project, config *MappingConfig) string {
+	if project == nil {
+		return ""
+	}
+	if component, ok := config.ProjectMap[strings.ToLower(project.ID)]; ok {
+		return component
+	}
+	return config.ProjectMap[strings.ToLower(project.Name)]
+}
+
+// ComponentToProjectID returns the Linear project ID mapped to a Beads
+// component, or "" if the component isn't mapped by project ID.
+func ComponentToProjectID(component string, config *MappingConfig) string {
+	for key, mapped := range config.ProjectMap {
+		if mapped == component && isProjectID(key) {
+			return key
+		}
+	}
+	return ""
+}
+
+// isProjectID reports whether a project_map key is a Linear UUID rather
+// than a project name.
+func isProjectID(key string) bool {
+	return len(key) == 36 && strings.Count(key, "-") == 4 && !strings.Contains(key, " ")
+}
+
+// EstimateToMinutes converts Linear estimate points to estimated minutes.
+func EstimateToMinutes(estimate *float64, config *MappingConfig) *int {
+	if estimate == nil || config.EstimateMinutes <= 0 {
+		return nil
+	}
+	minutes := int(math.Round(*estimate * float64(config.EstimateMinutes)))
+	return &minutes
+}
+
+// MinutesToEstimate converts estimated minutes to whole Linear estimate
+// points (at least 1 for any positive estimate). Returns 0 if there is no
+// estimate or the mapping is disabled.
+func MinutesToEstimate(minutes *int, config *MappingConfig) int {
+	if minutes == nil || *minutes <= 0 || config.EstimateMinutes <= 0 {
+		return 0
+	}
+	points := int(math.Round(float64(*minutes) / float64(config.EstimateMinutes)))
+	if points < 1 {
+		points = 1
+	}
+	return points
+}
+
+// IssueLabels returns the Beads labels for a Linear issue: its Linear
+// labels, its cycle label and the component label of its mapped project.
+func IssueLabels(li *Issue, config *MappingConfig) []string {
+	var labels []string
+	if li.Labels != nil {
+		for _, label := range li.Labels.Nodes {
+			labels = append(labels, label.Name)
+		}
+	}
+	if label := CycleLabel(li.Cycle); label != "" {
+		labels = append(labels, label)
+	}
+	if component := ProjectToComponent(li.Project, config); component != "" {
+		labels = append(labels, "component:"+component)
+	}
+	return labels
+}
+
 // IssueToBeads converts a Linear issue to a Beads issue.
 func IssueToBeads(li *Issue, config *MappingConfig) *IssueConversion {
 	createdAt, err := time.Parse(time.RFC3339, li.CreatedAt)
@@ -426,12 +530,10 @@ func IssueToBeads(li *Issue, config *MappingConfig) *IssueConversion {
 		}
 	}
 
-	// Copy labels (bidirectional sync preserves all labels)
-	if li.Labels != nil {
-		for _, label := range li.Labels.Nodes {
-			issue.Labels = append(issue.Labels, label.Name)
-		}
-	}
+	// Copy labels (bidirectional sync preserves all labels), plus the
+	// cycle and mapped project
+	issue.Labels = IssueLabels(li, config)
+	issue.EstimatedMinutes = EstimateToMinutes(li.Estimate, config)
 
 	externalRef := li.URL
 	if canonical, ok := CanonicalizeLinearExternalRef(externalRef); ok {
@@ -520,14 +622,14 @@ func BuildLinearToLocalUpdates(li *Issue, config *MappingConfig) map[string]inte
 	}
 
 	// Update labels from Linear
-	if li.Labels != nil {
-		var labels []string
-		for _, label := range li.Labels.Nodes {
-			labels = append(labels, label.Name)
-		}
+	if labels := IssueLabels(li, config); li.Labels != nil || len(labels) > 0 {
 		updates["labels"] = labels
 	}
 
+	if minutes := EstimateToMinutes(li.Estimate, config); minutes != nil {
+		updates["estimated_minutes"] = *minutes
+	}
+
 	// Update timestamps
 	if li.UpdatedAt != "" {
 		if updatedAt, err := time.Parse(time.RFC3339, li.UpdatedAt); err == nil {
@@ -550,6 +550,75 @@ func TestLoadMappingConfig(t *testing.T) {
 	}
 }
 
+func TestIssueToBeadsCycleEstimateProject(t *testing.T) {
+	projectID := "11111111-2222-3333-4444-555555555555"
+	config := LoadMappingConfig(&mockConfigLoader{
+		config: map[string]string{
+			"linear.project_map." + projectID: "api",
+			"linear.estimate_minutes":         "30",
+		},
+	})
+	estimate := 3.0
+	li := &Issue{
+		ID:         "uuid-1",
+		Identifier: "TEAM-1",
+		Title:      "Sized",
+		URL:        "https://linear.app/team/issue/TEAM-1/sized",
+		State:      &State{Type: "started"},
+		Labels:     &Labels{Nodes: []Label{{Name: "bug"}}},
+		Estimate:   &estimate,
+		Cycle:      &Cycle{ID: "c1", Number: 12, Name: "Sprint 12"},
+		Project:    &Project{ID: projectID, Name: "API"},
+		CreatedAt:  "2024-01-15T10:00:00Z",
+		UpdatedAt:  "2024-01-16T12:00:00Z",
+	}
+
+	issue := IssueToBeads(li, config).Issue.(*types.Issue)
+	want := []string{"bug", "cycle:12", "component:api"}
+	if len(issue.Labels) != len(want) {
+		t.Fatalf("Labels = %v, want %v", issue.Labels, want)
+	}
+	for i := range want {
+		if issue.Labels[i] != want[i] {
+			t.Errorf("Labels = %v, want %v", issue.Labels, want)
+		}
+	}
+	if issue.EstimatedMinutes == nil || *issue.EstimatedMinutes != 90 {
+		t.Errorf("EstimatedMinutes = %v, want 90", issue.EstimatedMinutes)
+	}
+
+	updates := BuildLinearToLocalUpdates(li, config)
+	if updates["estimated_minutes"] != 90 {
+		t.Errorf("estimated_minutes update = %v, want 90", updates["estimated_minutes"])
+	}
+
+	// Pushing maps back to whole points and the project ID
+	if got := MinutesToEstimate(issue.EstimatedMinutes, config); got != 3 {
+		t.Errorf("MinutesToEstimate = %d, want 3", got)
+	}
+	small := 5
+	if got := MinutesToEstimate(&small, config); got != 1 {
+		t.Errorf("MinutesToEstimate(5) = %d, want 1", got)
+	}
+	if got := ComponentToProjectID("api", config); got != projectID {
+		t.Errorf("ComponentToProjectID = %q, want %q", got, projectID)
+	}
+
+	// Projects can also be mapped by name, but then only for pull
+	config.ProjectMap = map[string]string{"api": "backend"}
+	if got := ProjectToComponent(li.Project, config); got != "backend" {
+		t.Errorf("ProjectToComponent by name = %q, want backend", got)
+	}
+	if got := ComponentToProjectID("backend", config); got != "" {
+		t.Errorf("ComponentToProjectID for a name mapping = %q, want empty", got)
+	}
+
+	config.EstimateMinutes = 0
+	if got := EstimateToMinutes(&estimate, config); got != nil {
+		t.Errorf("EstimateToMinutes with mapping disabled = %d, want nil", *got)
+	}
+}
+
 func TestLoadMappingConfigNilLoader(t *testing.T) {
 	config := LoadMappingConfig(nil)
 
@@ -18,12 +18,6 @@ const (
 	// DefaultTimeout is the default HTTP request timeout.
 	DefaultTimeout = 30 * time.Second
 
-	// MaxRetries is the maximum number of retries for rate-limited requests.
-	MaxRetries = 3
-
-	// RetryDelay is the base delay between retries (exponential backoff).
-	RetryDelay = time.Second
-
 	// MaxPageSize is the maximum number of issues to fetch per page.
 	MaxPageSize = 100
 )
@@ -71,6 +65,9 @@ type Issue struct {
 	Labels      *Labels    `json:"labels"`
 	Parent      *Parent    `json:"parent,omitempty"`
 	Relations   *Relations `json:"relations,omitempty"`
+	Estimate    *float64   `json:"estimate,omitempty"` // points on the team's estimate scale
+	Cycle       *Cycle     `json:"cycle,omitempty"`
+	Project     *Project   `json:"project,omitempty"`
 	CreatedAt   string     `json:"createdAt"`
 	UpdatedAt   string     `json:"updatedAt"`
 	CompletedAt string     `json:"completedAt,omitempty"`
@@ -108,6 +105,19 @@ type Parent struct {
 	Identifier string `json:"identifier"`
 }
 
+// Cycle represents the cycle (sprint) an issue is scheduled in.
+type Cycle struct {
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+	Name   string `json:"name,omitempty"`
+}
+
+// Project represents the project an issue belongs to.
+type Project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 // Relation represents a relation between issues in Linear.
 type Relation struct {
 	ID           string `json:"id"`
@@ -0,0 +1,96 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/config"
+)
+
+// setConfig initializes config and applies settings, restoring the
+// defaults when the test ends.
+func setConfig(t *testing.T, settings map[string]interface{}) {
+	t.Helper()
+	if err := config.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range settings {
+		config.Set(k, v)
+	}
+	t.Cleanup(func() {
+		config.Set(KeyFreezeEnabled, false)
+		config.Set(KeyFreezeReason, "")
+		config.Set(KeyAdmins, []string{})
+	})
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	locked := func(id string) (bool, string, error) {
+		return id == "bd-1", "release freeze", nil
+	}
+	setConfig(t, map[string]interface{}{KeyAdmins: "alice, bob"})
+
+	if err := Check(ctx, "bd-2", "carol", locked); err != nil {
+		t.Errorf("unlocked issue refused: %v", err)
+	}
+	if err := Check(ctx, "", "carol", locked); err != nil {
+		t.Errorf("create refused without a freeze: %v", err)
+	}
+
+	err := Check(ctx, "bd-1", "carol", locked)
+	var lockErr *Error
+	if !errors.As(err, &lockErr) || lockErr.Frozen || lockErr.Reason != "release freeze" {
+		t.Fatalf("locked issue: got %v", err)
+	}
+	if want := "cannot modify bd-1: issue is locked (release freeze)"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	if err := Check(ctx, "bd-1", "Bob", locked); err != nil {
+		t.Errorf("admin refused: %v", err)
+	}
+	if err := Check(WithBypass(ctx), "bd-1", "carol", locked); err != nil {
+		t.Errorf("bypass refused: %v", err)
+	}
+
+	config.Set(KeyFreezeEnabled, true)
+	config.Set(KeyFreezeReason, "cutting v1.4")
+	for _, id := range []string{"", "bd-2"} {
+		err := Check(ctx, id, "carol", locked)
+		if !errors.As(err, &lockErr) || !lockErr.Frozen || lockErr.Reason != "cutting v1.4" {
+			t.Errorf("frozen %q: got %v", id, err)
+		}
+	}
+	if err := Check(ctx, "bd-2", "alice", locked); err != nil {
+		t.Errorf("admin refused during freeze: %v", err)
+	}
+}
+
+func TestCheckLookupError(t *testing.T) {
+	setConfig(t, nil)
+	boom := errors.New("boom")
+	err := Check(context.Background(), "bd-1", "carol", func(string) (bool, string, error) {
+		return false, "", boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("got %v, want the lookup error", err)
+	}
+}
+
+func TestReasonFromComment(t *testing.T) {
+	for text, want := range map[string]struct {
+		reason string
+		ok     bool
+	}{
+		"Locked: release freeze": {"release freeze", true},
+		"Locked:":                {"", true},
+		"Looks good":             {"", false},
+	} {
+		reason, ok := ReasonFromComment(text)
+		if reason != want.reason || ok != want.ok {
+			t.Errorf("ReasonFromComment(%q) = %q, %v", text, reason, ok)
+		}
+	}
+}
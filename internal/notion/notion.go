@@ -0,0 +1,303 @@
+// Package notion reads pages from a Notion database through the Notion
+// API, for import into beads.
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/httpx"
+)
+
+const (
+	// DefaultBaseURL is the Notion API base URL.
+	DefaultBaseURL = "https://api.notion.com/v1"
+
+	// APIVersion is the Notion-Version header sent with every request.
+	APIVersion = "2022-06-28"
+
+	// DefaultTimeout is the default HTTP request timeout.
+	DefaultTimeout = 30 * time.Second
+)
+
+// Client reads from Notion with an integration token. The integration must
+// be shared with the database.
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client using token.
+func NewClient(token string) *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		Token:      token,
+		HTTPClient: httpx.NewClient(DefaultTimeout),
+	}
+}
+
+// Page is a database row.
+type Page struct {
+	ID             string              `json:"id"`
+	URL            string              `json:"url"`
+	CreatedTime    time.Time           `json:"created_time"`
+	LastEditedTime time.Time           `json:"last_edited_time"`
+	Archived       bool                `json:"archived"`
+	Properties     map[string]Property `json:"properties"`
+}
+
+// Property is a page property value. Only the field matching Type is set.
+type Property struct {
+	Type        string     `json:"type"`
+	Title       []RichText `json:"title"`
+	RichText    []RichText `json:"rich_text"`
+	Select      *Option    `json:"select"`
+	Status      *Option    `json:"status"`
+	MultiSelect []Option   `json:"multi_select"`
+	People      []Person   `json:"people"`
+	Number      *float64   `json:"number"`
+	Date        *struct {
+		Start string `json:"start"`
+	} `json:"date"`
+}
+
+// RichText is a span of rich text.
+type RichText struct {
+	PlainText string `json:"plain_text"`
+}
+
+// Option is a select, status or multi-select option.
+type Option struct {
+	Name string `json:"name"`
+}
+
+// Person is a people property value.
+type Person struct {
+	Name   string `json:"name"`
+	Person *struct {
+		Email string `json:"email"`
+	} `json:"person"`
+}
+
+func plain(spans []RichText) string {
+	var sb strings.Builder
+	for _, s := range spans {
+		sb.WriteString(s.PlainText)
+	}
+	return sb.String()
+}
+
+// Title returns the page title.
+func (p *Page) Title() string {
+	for _, prop := range p.Properties {
+		if prop.Type == "title" {
+			return strings.TrimSpace(plain(prop.Title))
+		}
+	}
+	return ""
+}
+
+// Text returns property name as text: rich text, title, select or status
+// name, or number. Returns "" for missing or empty properties.
+func (p *Page) Text(name string) string {
+	prop, ok := p.Properties[name]
+	if !ok {
+		return ""
+	}
+	switch prop.Type {
+	case "title":
+		return plain(prop.Title)
+	case "rich_text":
+		return plain(prop.RichText)
+	case "select":
+		if prop.Select != nil {
+			return prop.Select.Name
+		}
+	case "status":
+		if prop.Status != nil {
+			return prop.Status.Name
+		}
+	case "number":
+		if prop.Number != nil {
+			return fmt.Sprint(*prop.Number)
+		}
+	case "date":
+		if prop.Date != nil {
+			return prop.Date.Start
+		}
+	}
+	return ""
+}
+
+// Options returns the option names of multi-select property name.
+func (p *Page) Options(name string) []string {
+	var names []string
+	for _, o := range p.Properties[name].MultiSelect {
+		names = append(names, o.Name)
+	}
+	return names
+}
+
+// Person returns the first person in people property name, by email when
+// available.
+func (p *Page) Person(name string) string {
+	people := p.Properties[name].People
+	if len(people) == 0 {
+		return ""
+	}
+	if people[0].Person != nil && people[0].Person.Email != "" {
+		return people[0].Person.Email
+	}
+	return people[0].Name
+}
+
+// PropertyOfType returns the name of the page's first property of type t
+// (alphabetically, since property order isn't preserved), or "".
+func (p *Page) PropertyOfType(t string) string {
+	found := ""
+	for name, prop := range p.Properties {
+		if prop.Type == t && (found == "" || name < found) {
+			found = name
+		}
+	}
+	return found
+}
+
+// do sends a request and decodes the JSON response into out.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, payload)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Notion-Version", APIVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("API error: %s (status %d)", apiErr.Message, resp.StatusCode)
+		}
+		return fmt.Errorf("API error: status %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// QueryDatabase returns every page in a database.
+func (c *Client) QueryDatabase(ctx context.Context, databaseID string) ([]Page, error) {
+	var pages []Page
+	body := map[string]interface{}{"page_size": 100}
+	for {
+		var resp struct {
+			Results    []Page `json:"results"`
+			HasMore    bool   `json:"has_more"`
+			NextCursor string `json:"next_cursor"`
+		}
+		if err := c.do(ctx, http.MethodPost, "/databases/"+databaseID+"/query", body, &resp); err != nil {
+			return nil, fmt.Errorf("querying database: %w", err)
+		}
+		pages = append(pages, resp.Results...)
+		if !resp.HasMore || resp.NextCursor == "" {
+			return pages, nil
+		}
+		body["start_cursor"] = resp.NextCursor
+	}
+}
+
+// Block is a top-level block of page content.
+type Block struct {
+	Type    string
+	Text    string
+	Checked bool // to_do blocks
+}
+
+// PageContent returns the top-level blocks of a page.
+func (c *Client) PageContent(ctx context.Context, pageID string) ([]Block, error) {
+	var blocks []Block
+	cursor := ""
+	for {
+		path := "/blocks/" + pageID + "/children?page_size=100"
+		if cursor != "" {
+			path += "&start_cursor=" + cursor
+		}
+		var resp struct {
+			Results    []map[string]json.RawMessage `json:"results"`
+			HasMore    bool                         `json:"has_more"`
+			NextCursor string                       `json:"next_cursor"`
+		}
+		if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+			return nil, fmt.Errorf("reading page content: %w", err)
+		}
+		for _, raw := range resp.Results {
+			var blockType string
+			_ = json.Unmarshal(raw["type"], &blockType)
+			var content struct {
+				RichText []RichText `json:"rich_text"`
+				Checked  bool       `json:"checked"`
+			}
+			_ = json.Unmarshal(raw[blockType], &content)
+			blocks = append(blocks, Block{Type: blockType, Text: plain(content.RichText), Checked: content.Checked})
+		}
+		if !resp.HasMore || resp.NextCursor == "" {
+			return blocks, nil
+		}
+		cursor = resp.NextCursor
+	}
+}
+
+// Markdown renders page content as a description and a Markdown task list
+// of its to-do items.
+func Markdown(blocks []Block) (description, checklist string) {
+	var desc, todo []string
+	for _, b := range blocks {
+		switch b.Type {
+		case "to_do":
+			box := "[ ]"
+			if b.Checked {
+				box = "[x]"
+			}
+			todo = append(todo, "- "+box+" "+b.Text)
+		case "paragraph", "quote", "callout":
+			desc = append(desc, b.Text)
+		case "heading_1", "heading_2", "heading_3":
+			desc = append(desc, strings.Repeat("#", int(b.Type[len(b.Type)-1]-'0'))+" "+b.Text)
+		case "bulleted_list_item":
+			desc = append(desc, "- "+b.Text)
+		case "numbered_list_item":
+			desc = append(desc, "1. "+b.Text)
+		case "code":
+			desc = append(desc, "```\n"+b.Text+"\n```")
+		}
+	}
+	return strings.TrimSpace(strings.Join(desc, "\n")), strings.Join(todo, "\n")
+}
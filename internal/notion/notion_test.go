@@ -0,0 +1,78 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQueryDatabasePaginates(t *testing.T) {
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Notion-Version") != APIVersion {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "API token is invalid."}`))
+			return
+		}
+		var body struct {
+			StartCursor string `json:"start_cursor"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		cursors = append(cursors, body.StartCursor)
+		if body.StartCursor == "" {
+			_, _ = w.Write([]byte(`{"results": [{"id": "p1", "properties": {
+				"Name": {"type": "title", "title": [{"plain_text": "First"}]},
+				"Status": {"type": "status", "status": {"name": "In progress"}},
+				"Tags": {"type": "multi_select", "multi_select": [{"name": "ui"}, {"name": "bug"}]},
+				"Owner": {"type": "people", "people": [{"name": "Ana", "person": {"email": "ana@example.com"}}]}
+			}}], "has_more": true, "next_cursor": "c2"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [{"id": "p2", "properties": {}}], "has_more": false}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("secret")
+	client.BaseURL = srv.URL
+	pages, err := client.QueryDatabase(context.Background(), "db")
+	if err != nil {
+		t.Fatalf("QueryDatabase: %v", err)
+	}
+	if len(pages) != 2 || len(cursors) != 2 || cursors[1] != "c2" {
+		t.Fatalf("got %d pages with cursors %q", len(pages), cursors)
+	}
+	p := &pages[0]
+	if p.Title() != "First" || p.Text("Status") != "In progress" || p.Person("Owner") != "ana@example.com" {
+		t.Errorf("page = title %q, status %q, owner %q", p.Title(), p.Text("Status"), p.Person("Owner"))
+	}
+	if got := p.Options("Tags"); len(got) != 2 || got[0] != "ui" {
+		t.Errorf("Options = %v", got)
+	}
+	if got := p.PropertyOfType("people"); got != "Owner" {
+		t.Errorf("PropertyOfType(people) = %q, want Owner", got)
+	}
+
+	client.Token = "wrong"
+	if _, err := client.QueryDatabase(context.Background(), "db"); err == nil {
+		t.Error("expected an API error for a bad token")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	desc, todo := Markdown([]Block{
+		{Type: "heading_2", Text: "Context"},
+		{Type: "paragraph", Text: "Users can't log in."},
+		{Type: "to_do", Text: "Reproduce", Checked: true},
+		{Type: "bulleted_list_item", Text: "Safari only"},
+		{Type: "to_do", Text: "Fix"},
+		{Type: "image"},
+	})
+	if want := "## Context\nUsers can't log in.\n- Safari only"; desc != want {
+		t.Errorf("description = %q, want %q", desc, want)
+	}
+	if want := "- [x] Reproduce\n- [ ] Fix"; todo != want {
+		t.Errorf("checklist = %q, want %q", todo, want)
+	}
+}
@@ -0,0 +1,166 @@
+package oplog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/signing"
+)
+
+// Each replica's ops form a hash chain: an op records its sequence number
+// within the replica and the Hash of the replica's previous op. Union merges
+// interleave replicas' lines but never reorder one replica's, so every
+// chain survives merging intact, and an op removed, inserted or edited by
+// hand breaks it. The last op of each recorded batch can carry a signature,
+// which through the chain vouches for everything its replica recorded
+// before.
+
+// Signer signs ops as they are recorded.
+type Signer interface {
+	Sign(data []byte) (string, error)
+	// Name is recorded as the op's Signer
+	Name() string
+}
+
+// ConfiguredSigner returns the signer for new ops: the configured key when
+// sign.ops is on, else nil.
+func ConfiguredSigner() (Signer, error) {
+	if !config.GetBool("sign.ops") {
+		return nil, nil
+	}
+	key, err := signing.FromConfig()
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// VerifyFunc checks that sig is signer's signature of data.
+type VerifyFunc func(data []byte, sig, signer string) (signing.Status, error)
+
+// Hash returns the hex SHA-256 of op's JSON form without its signature.
+func Hash(op Op) string {
+	op.Sig = ""
+	data, _ := json.Marshal(&op)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// link chains newOps, all from one replica, onto that replica's last op in
+// log and signs the last of them when signer is set.
+func link(log, newOps []Op, signer Signer) error {
+	if len(newOps) == 0 {
+		return nil
+	}
+	var last *Op
+	for i := range log {
+		if log[i].Replica == newOps[0].Replica {
+			last = &log[i]
+		}
+	}
+	prev, seq := "", 0
+	if last != nil {
+		prev, seq = Hash(*last), last.Seq
+	}
+	for i := range newOps {
+		seq++
+		newOps[i].Seq = seq
+		newOps[i].Prev = prev
+		if i == len(newOps)-1 && signer != nil {
+			newOps[i].Signer = signer.Name()
+			sig, err := signer.Sign([]byte(Hash(newOps[i])))
+			if err != nil {
+				return fmt.Errorf("failed to sign op log: %w", err)
+			}
+			newOps[i].Sig = sig
+		}
+		prev = Hash(newOps[i])
+	}
+	return nil
+}
+
+// Problem is one way the log fails verification.
+type Problem struct {
+	OpID    string `json:"op_id"`
+	Issue   string `json:"issue"`
+	Replica string `json:"replica"`
+	Message string `json:"message"`
+}
+
+// Report summarizes a verified log.
+type Report struct {
+	Ops       int       `json:"ops"`
+	Replicas  int       `json:"replicas"`
+	Chained   int       `json:"chained"`   // Ops linked into their replica's chain
+	Signed    int       `json:"signed"`    // Ops carrying a good signature
+	Covered   int       `json:"covered"`   // Chained ops vouched for by a later good signature
+	Untrusted int       `json:"untrusted"` // Valid signatures whose signer couldn't be checked
+	Problems  []Problem `json:"problems"`
+}
+
+// OK reports whether no problems were found.
+func (r *Report) OK() bool { return len(r.Problems) == 0 }
+
+// Verify checks every replica's chain and, with verify set, the
+// signatures. Ops recorded before chaining existed (Seq 0) are accepted
+// only ahead of their replica's chain.
+func Verify(ops []Op, verify VerifyFunc) (*Report, error) {
+	report := &Report{Ops: len(ops), Problems: []Problem{}}
+	type chain struct {
+		prev    string
+		seq     int
+		pending int // chained ops since the last good signature
+	}
+	chains := make(map[string]*chain)
+	for _, op := range ops {
+		c := chains[op.Replica]
+		if c == nil {
+			c = &chain{}
+			chains[op.Replica] = c
+		}
+		problem := func(format string, args ...interface{}) {
+			report.Problems = append(report.Problems, Problem{OpID: op.ID, Issue: op.Issue, Replica: op.Replica, Message: fmt.Sprintf(format, args...)})
+		}
+
+		hash := Hash(op)
+		switch {
+		case op.Seq == 0 && c.seq > 0:
+			problem("unchained op after op %d of the replica's chain", c.seq)
+		case op.Seq == 0:
+			// Recorded before chaining
+		case op.Seq != c.seq+1:
+			problem("chain broken: op %d follows op %d (ops missing or reordered)", op.Seq, c.seq)
+		case op.Prev != c.prev:
+			problem("chain broken: op %d doesn't match the op before it (an earlier op was changed)", op.Seq)
+		}
+		if op.Seq > 0 {
+			report.Chained++
+			c.pending++
+			c.seq = op.Seq
+		}
+		c.prev = hash
+
+		if op.Sig == "" || verify == nil {
+			continue
+		}
+		status, err := verify([]byte(hash), op.Sig, op.Signer)
+		if err != nil {
+			return nil, err
+		}
+		switch status {
+		case signing.StatusBad:
+			problem("bad signature by %s", op.Signer)
+			continue
+		case signing.StatusUntrusted:
+			report.Untrusted++
+		}
+		report.Signed++
+		report.Covered += c.pending
+		c.pending = 0
+	}
+	report.Replicas = len(chains)
+	return report, nil
+}
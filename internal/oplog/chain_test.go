@@ -0,0 +1,134 @@
+package oplog
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/signing"
+)
+
+// fakeSigner "signs" by tagging the data with its name.
+type fakeSigner struct{ name string }
+
+func (s fakeSigner) Sign(data []byte) (string, error) { return s.name + ":" + string(data), nil }
+func (s fakeSigner) Name() string                     { return s.name }
+
+func fakeVerify(data []byte, sig, signer string) (signing.Status, error) {
+	if sig != signer+":"+string(data) {
+		return signing.StatusBad, nil
+	}
+	return signing.StatusGood, nil
+}
+
+func setOp(replica, title string) Op {
+	value, _ := json.Marshal(title)
+	return Op{ID: NewID(), Issue: "bd-1", Kind: KindSet, Field: "title", Value: value, Time: time.Now(), Replica: replica}
+}
+
+// chainedLog records two batches from replica a and one from b, interleaved
+// as a union merge would leave them, and reads them back from disk.
+func chainedLog(t *testing.T, signer Signer) []Op {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), FileName)
+	var log []Op
+	for _, batch := range [][]Op{
+		{setOp("a", "one"), setOp("a", "two")},
+		{setOp("b", "three")},
+		{setOp("a", "four")},
+	} {
+		if err := link(log, batch, signer); err != nil {
+			t.Fatalf("link failed: %v", err)
+		}
+		if err := Append(path, batch); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		log = append(log, batch...)
+	}
+	ops, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	return ops
+}
+
+func TestVerifyIntactChain(t *testing.T) {
+	ops := chainedLog(t, fakeSigner{name: "alice"})
+	if ops[2].Seq != 1 || ops[3].Seq != 3 || ops[3].Prev != Hash(ops[1]) {
+		t.Fatalf("unexpected chain links: %+v", ops)
+	}
+	report, err := Verify(ops, fakeVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() {
+		t.Fatalf("intact log failed verification: %+v", report.Problems)
+	}
+	if report.Replicas != 2 || report.Chained != 4 || report.Signed != 3 || report.Covered != 4 {
+		t.Errorf("report = %+v, want 2 replicas, 4 chained, 3 signed, 4 covered", report)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func([]Op) []Op
+		want   string
+	}{
+		{"edited", func(ops []Op) []Op {
+			ops[0].Value = json.RawMessage(`"forged"`)
+			return ops
+		}, "doesn't match"},
+		{"removed", func(ops []Op) []Op {
+			return append(ops[:1], ops[2:]...)
+		}, "ops missing or reordered"},
+		{"reordered", func(ops []Op) []Op {
+			ops[0], ops[1] = ops[1], ops[0]
+			return ops
+		}, "ops missing or reordered"},
+		{"unchained insert", func(ops []Op) []Op {
+			return append(ops, setOp("a", "sneaky"))
+		}, "unchained op"},
+		{"resigned by someone else", func(ops []Op) []Op {
+			ops[3].Signer = "mallory"
+			return ops
+		}, "bad signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := tt.tamper(chainedLog(t, fakeSigner{name: "alice"}))
+			report, err := Verify(ops, fakeVerify)
+			if err != nil {
+				t.Fatal(err)
+			}
+			found := false
+			for _, p := range report.Problems {
+				if strings.Contains(p.Message, tt.want) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("problems = %+v, want one containing %q", report.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAcceptsLegacyOpsBeforeChain(t *testing.T) {
+	// Ops recorded before chaining existed; the first chained op links to them
+	ops := []Op{setOp("a", "old"), setOp("b", "older")}
+	batch := []Op{setOp("a", "new"), setOp("a", "newer")}
+	if err := link(ops, batch, nil); err != nil {
+		t.Fatal(err)
+	}
+	ops = append(ops, batch...)
+	report, err := Verify(ops, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() || report.Chained != 2 || report.Covered != 0 {
+		t.Errorf("report = %+v, want OK with 2 chained and none covered", report)
+	}
+}
@@ -0,0 +1,387 @@
+// Package oplog replicates issue edits through an append-only operation log.
+//
+// Whole-issue JSONL merges pick a winner per issue, so two clones that edit
+// different fields of the same issue offline lose one side's edit, and a
+// label removed on one clone comes back from the other. The op log records
+// each change as its own operation instead:
+//
+//   - scalar fields (title, status, priority, ...) are last-writer-wins
+//     registers ordered by (time, replica, op ID), so every clone picks the
+//     same winner regardless of the order ops arrive in;
+//   - labels are an observed-remove set: a removal only cancels the additions
+//     it had seen, so a concurrent re-add survives.
+//
+// The log lives next to issues.jsonl as ops.jsonl. Lines are only ever
+// appended, so git's union merge combines two clones' logs without conflicts
+// and replaying the union converges on the same state everywhere.
+package oplog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// FileName is the op log's name inside the .beads directory.
+const FileName = "ops.jsonl"
+
+// Kind identifies what an Op does.
+type Kind string
+
+const (
+	// KindSet assigns Value to Field.
+	KindSet Kind = "set"
+	// KindLabelAdd adds Label; the op's ID is the tag later removals refer to.
+	KindLabelAdd Kind = "label_add"
+	// KindLabelRemove removes Label, cancelling the additions listed in Tags.
+	KindLabelRemove Kind = "label_remove"
+)
+
+// Fields are the scalar issue fields replicated through the log.
+var Fields = []string{
+	"title",
+	"description",
+	"design",
+	"acceptance_criteria",
+	"notes",
+	"status",
+	"priority",
+	"issue_type",
+	"assignee",
+}
+
+// Op is one line of the log.
+type Op struct {
+	ID      string          `json:"id"`
+	Issue   string          `json:"issue"`
+	Kind    Kind            `json:"kind"`
+	Field   string          `json:"field,omitempty"`
+	Value   json.RawMessage `json:"value,omitempty"`
+	Label   string          `json:"label,omitempty"`
+	Tags    []string        `json:"tags,omitempty"`
+	Time    time.Time       `json:"time"`
+	Replica string          `json:"replica"`
+	Actor   string          `json:"actor,omitempty"`
+	// Seq numbers the replica's ops from 1, and Prev is the Hash of the
+	// replica's previous op, chaining them so removed or edited lines show
+	// up in bd verify (see chain.go)
+	Seq  int    `json:"seq,omitempty"`
+	Prev string `json:"prev,omitempty"`
+	// Sig signs the op's Hash, which covers every earlier op of the
+	// replica through Prev; Signer names who made it (see package signing)
+	Sig    string `json:"sig,omitempty"`
+	Signer string `json:"signer,omitempty"`
+}
+
+// Register is the winning write for one field.
+type Register struct {
+	Value   json.RawMessage
+	Time    time.Time
+	Replica string
+	OpID    string
+}
+
+// after reports whether op wins over the register's current writer.
+func (r *Register) after(op *Op) bool {
+	if !op.Time.Equal(r.Time) {
+		return op.Time.After(r.Time)
+	}
+	if op.Replica != r.Replica {
+		return op.Replica > r.Replica
+	}
+	return op.ID > r.OpID
+}
+
+// State is one issue as the log describes it.
+type State struct {
+	Fields map[string]*Register
+	// adds maps each label to the tags of the additions not yet removed
+	adds map[string]map[string]bool
+}
+
+// Labels returns the labels currently in the set, sorted.
+func (s *State) Labels() []string {
+	labels := make([]string, 0, len(s.adds))
+	for label, tags := range s.adds {
+		if len(tags) > 0 {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// Replay folds ops into per-issue state. The result does not depend on the
+// order of ops, and duplicate ops are harmless.
+func Replay(ops []Op) map[string]*State {
+	states := make(map[string]*State)
+	removed := make(map[string]bool)
+	for i := range ops {
+		if ops[i].Kind == KindLabelRemove {
+			for _, tag := range ops[i].Tags {
+				removed[tag] = true
+			}
+		}
+	}
+
+	for i := range ops {
+		op := &ops[i]
+		st := states[op.Issue]
+		if st == nil {
+			st = &State{Fields: make(map[string]*Register), adds: make(map[string]map[string]bool)}
+			states[op.Issue] = st
+		}
+		switch op.Kind {
+		case KindSet:
+			reg := st.Fields[op.Field]
+			if reg == nil || reg.after(op) {
+				st.Fields[op.Field] = &Register{Value: op.Value, Time: op.Time, Replica: op.Replica, OpID: op.ID}
+			}
+		case KindLabelAdd:
+			if st.adds[op.Label] == nil {
+				st.adds[op.Label] = make(map[string]bool)
+			}
+			if !removed[op.ID] {
+				st.adds[op.Label][op.ID] = true
+			}
+		}
+	}
+	return states
+}
+
+// Diff returns the ops that move st to issue's current fields and labels.
+// st may be nil for an issue the log has never seen. A set op is stamped
+// with issue.UpdatedAt, bumped past the register it overwrites so a local
+// edit made after observing a write always supersedes it, even when this
+// clone's clock is behind.
+func Diff(st *State, issue *types.Issue, replica, actor string) []Op {
+	var ops []Op
+	for _, field := range Fields {
+		value, err := json.Marshal(FieldValue(issue, field))
+		if err != nil {
+			continue
+		}
+		var reg *Register
+		if st != nil {
+			reg = st.Fields[field]
+		}
+		if reg == nil && isZeroValue(value) {
+			continue
+		}
+		if reg != nil && bytes.Equal(reg.Value, value) {
+			continue
+		}
+		t := issue.UpdatedAt.UTC()
+		if reg != nil && !reg.Time.Before(t) {
+			t = reg.Time.Add(time.Microsecond)
+		}
+		ops = append(ops, Op{ID: NewID(), Issue: issue.ID, Kind: KindSet, Field: field, Value: value, Time: t, Replica: replica, Actor: actor})
+	}
+
+	current := make(map[string]bool, len(issue.Labels))
+	for _, label := range issue.Labels {
+		current[label] = true
+	}
+	var live map[string]map[string]bool
+	if st != nil {
+		live = st.adds
+	}
+	t := issue.UpdatedAt.UTC()
+	for _, label := range sortedKeys(current) {
+		if len(live[label]) == 0 {
+			ops = append(ops, Op{ID: NewID(), Issue: issue.ID, Kind: KindLabelAdd, Label: label, Time: t, Replica: replica, Actor: actor})
+		}
+	}
+	for _, label := range sortedKeys(live) {
+		if len(live[label]) > 0 && !current[label] {
+			ops = append(ops, Op{ID: NewID(), Issue: issue.ID, Kind: KindLabelRemove, Label: label, Tags: sortedKeys(live[label]), Time: t, Replica: replica, Actor: actor})
+		}
+	}
+	return ops
+}
+
+// Changes returns the field updates (in storage.UpdateIssue form) and label
+// edits that bring issue in line with st.
+func Changes(st *State, issue *types.Issue) (updates map[string]interface{}, addLabels, removeLabels []string) {
+	updates = make(map[string]interface{})
+	for _, field := range Fields {
+		reg := st.Fields[field]
+		if reg == nil {
+			continue
+		}
+		current, err := json.Marshal(FieldValue(issue, field))
+		if err != nil || bytes.Equal(current, reg.Value) {
+			continue
+		}
+		if v, ok := decodeField(field, reg.Value); ok {
+			updates[field] = v
+		}
+	}
+
+	if len(st.adds) == 0 {
+		// The log never saw this issue's labels; leave them alone
+		return updates, nil, nil
+	}
+	want := make(map[string]bool)
+	for _, label := range st.Labels() {
+		want[label] = true
+	}
+	have := make(map[string]bool, len(issue.Labels))
+	for _, label := range issue.Labels {
+		have[label] = true
+		if !want[label] {
+			removeLabels = append(removeLabels, label)
+		}
+	}
+	for _, label := range st.Labels() {
+		if !have[label] {
+			addLabels = append(addLabels, label)
+		}
+	}
+	sort.Strings(removeLabels)
+	return updates, addLabels, removeLabels
+}
+
+// FieldValue returns issue's value for one of Fields.
+func FieldValue(issue *types.Issue, field string) interface{} {
+	switch field {
+	case "title":
+		return issue.Title
+	case "description":
+		return issue.Description
+	case "design":
+		return issue.Design
+	case "acceptance_criteria":
+		return issue.AcceptanceCriteria
+	case "notes":
+		return issue.Notes
+	case "status":
+		return string(issue.Status)
+	case "priority":
+		return issue.Priority
+	case "issue_type":
+		return string(issue.IssueType)
+	case "assignee":
+		return issue.Assignee
+	}
+	return nil
+}
+
+func decodeField(field string, raw json.RawMessage) (interface{}, bool) {
+	if field == "priority" {
+		var p int
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, false
+		}
+		return p, true
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, false
+	}
+	if field == "assignee" && s == "" {
+		return nil, true
+	}
+	return s, true
+}
+
+func isZeroValue(raw json.RawMessage) bool {
+	return string(raw) == `""` || string(raw) == "null"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NewID returns a random op or replica ID.
+func NewID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Read returns the ops in the log at path, dropping duplicates. A missing
+// log is empty. Lines that don't parse are skipped, since a union merge can
+// only ever add whole lines and a bad one shouldn't block the rest.
+func Read(path string) ([]Op, error) {
+	// #nosec G304 - path is the op log inside .beads
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open op log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return decode(f)
+}
+
+func decode(r io.Reader) ([]Op, error) {
+	var ops []Op
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var op Op
+		if err := json.Unmarshal(line, &op); err != nil || op.ID == "" || op.Issue == "" {
+			continue
+		}
+		if seen[op.ID] {
+			continue
+		}
+		seen[op.ID] = true
+		ops = append(ops, op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read op log: %w", err)
+	}
+	return ops, nil
+}
+
+// Append adds ops to the end of the log at path and syncs it.
+func Append(path string, ops []Op) error {
+	if len(ops) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for i := range ops {
+		data, err := json.Marshal(&ops[i])
+		if err != nil {
+			return fmt.Errorf("failed to encode op: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	// #nosec G302 G304 - op log is tracked in git like issues.jsonl
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open op log: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to append to op log: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to sync op log: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,171 @@
+package oplog
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var t0 = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func baseIssue() *types.Issue {
+	return &types.Issue{
+		ID:        "bd-1",
+		Title:     "Original",
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeTask,
+		Labels:    []string{"backend"},
+		UpdatedAt: t0,
+	}
+}
+
+// edit applies st to a copy of issue, then lets mutate change it and returns
+// the ops a clone would record for the change.
+func edit(t *testing.T, st *State, replica string, at time.Time, mutate func(*types.Issue)) []Op {
+	t.Helper()
+	issue := baseIssue()
+	if st != nil {
+		updates, add, remove := Changes(st, issue)
+		for field, v := range updates {
+			switch field {
+			case "title":
+				issue.Title = v.(string)
+			case "priority":
+				issue.Priority = v.(int)
+			}
+		}
+		issue.Labels = append(without(issue.Labels, remove), add...)
+	}
+	mutate(issue)
+	issue.UpdatedAt = at
+	return Diff(st, issue, replica, "tester")
+}
+
+func without(labels, remove []string) []string {
+	var out []string
+	for _, l := range labels {
+		keep := true
+		for _, r := range remove {
+			keep = keep && l != r
+		}
+		if keep {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func TestDiffNewIssueRecordsSetFieldsAndLabels(t *testing.T) {
+	ops := Diff(nil, baseIssue(), "a", "tester")
+	got := map[string]bool{}
+	for _, op := range ops {
+		got[string(op.Kind)+":"+op.Field+op.Label] = true
+	}
+	for _, want := range []string{"set:title", "set:status", "set:priority", "set:issue_type", "label_add:backend"} {
+		if !got[want] {
+			t.Errorf("missing op %s in %v", want, got)
+		}
+	}
+	if got["set:description"] {
+		t.Error("empty description should not be recorded")
+	}
+}
+
+func TestConcurrentFieldEditsBothSurvive(t *testing.T) {
+	seed := Diff(nil, baseIssue(), "a", "tester")
+	st := Replay(seed)["bd-1"]
+
+	// Clone A retitles, clone B reprioritizes, offline and later than each other
+	opsA := edit(t, st, "a", t0.Add(time.Minute), func(i *types.Issue) { i.Title = "Renamed" })
+	opsB := edit(t, st, "b", t0.Add(2*time.Minute), func(i *types.Issue) { i.Priority = 0 })
+
+	for _, ops := range [][]Op{
+		append(append(append([]Op{}, seed...), opsA...), opsB...),
+		append(append(append([]Op{}, opsB...), opsA...), seed...),
+	} {
+		merged := Replay(ops)["bd-1"]
+		updates, _, _ := Changes(merged, baseIssue())
+		if updates["title"] != "Renamed" || updates["priority"] != 0 {
+			t.Errorf("merged updates = %v, want both edits", updates)
+		}
+	}
+}
+
+func TestSameFieldLastWriterWinsDeterministically(t *testing.T) {
+	seed := Diff(nil, baseIssue(), "a", "tester")
+	st := Replay(seed)["bd-1"]
+	opsA := edit(t, st, "a", t0.Add(time.Minute), func(i *types.Issue) { i.Title = "From A" })
+	opsB := edit(t, st, "b", t0.Add(time.Minute), func(i *types.Issue) { i.Title = "From B" })
+
+	// Same timestamp: the higher replica ID wins on every clone
+	for _, ops := range [][]Op{
+		append(append(append([]Op{}, seed...), opsA...), opsB...),
+		append(append(append([]Op{}, opsB...), opsA...), seed...),
+	} {
+		updates, _, _ := Changes(Replay(ops)["bd-1"], baseIssue())
+		if updates["title"] != "From B" {
+			t.Errorf("title = %v, want From B", updates["title"])
+		}
+	}
+}
+
+func TestDiffBumpsPastNewerRegister(t *testing.T) {
+	seed := Diff(nil, baseIssue(), "a", "tester")
+	remote := edit(t, Replay(seed)["bd-1"], "b", t0.Add(time.Hour), func(i *types.Issue) { i.Title = "Remote" })
+	st := Replay(append(append([]Op{}, seed...), remote...))["bd-1"]
+
+	// This clone's clock is behind the remote write it has already seen
+	local := edit(t, st, "a", t0.Add(time.Minute), func(i *types.Issue) { i.Title = "Local, later" })
+	updates, _, _ := Changes(Replay(append(append(append([]Op{}, seed...), remote...), local...))["bd-1"], baseIssue())
+	if updates["title"] != "Local, later" {
+		t.Errorf("title = %v, want the edit made after seeing the remote write", updates["title"])
+	}
+}
+
+func TestLabelsAreObservedRemoveSet(t *testing.T) {
+	seed := Diff(nil, baseIssue(), "a", "tester")
+	st := Replay(seed)["bd-1"]
+
+	// A removes "backend" while B adds "urgent"
+	opsA := edit(t, st, "a", t0.Add(time.Minute), func(i *types.Issue) { i.Labels = nil })
+	opsB := edit(t, st, "b", t0.Add(time.Minute), func(i *types.Issue) { i.Labels = append(i.Labels, "urgent") })
+	merged := Replay(append(append(append([]Op{}, seed...), opsA...), opsB...))["bd-1"]
+	if got := merged.Labels(); !reflect.DeepEqual(got, []string{"urgent"}) {
+		t.Errorf("labels = %v, want [urgent]", got)
+	}
+
+	// A concurrent re-add of a removed label survives the removal
+	readd := []Op{{ID: "readd", Issue: "bd-1", Kind: KindLabelAdd, Label: "backend", Time: t0, Replica: "b"}}
+	merged = Replay(append(append(append([]Op{}, seed...), opsA...), readd...))["bd-1"]
+	if got := merged.Labels(); !reflect.DeepEqual(got, []string{"backend"}) {
+		t.Errorf("labels = %v, want the concurrent re-add kept", got)
+	}
+}
+
+func TestAppendAndReadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	ops := Diff(nil, baseIssue(), "a", "tester")
+	if err := Append(path, ops); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	// A union merge can duplicate lines; Read drops them
+	if err := Append(path, ops[:1]); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(got) != len(ops) {
+		t.Errorf("Read returned %d ops, want %d", len(got), len(ops))
+	}
+
+	missing, err := Read(filepath.Join(t.TempDir(), FileName))
+	if err != nil || missing != nil {
+		t.Errorf("Read of missing log = %v, %v", missing, err)
+	}
+}
@@ -0,0 +1,166 @@
+package oplog
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/subset"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// replicaKey is the metadata key holding this clone's replica ID. The
+// database is never committed, so each clone gets its own.
+const replicaKey = "oplog_replica"
+
+// Path returns the op log path for the JSONL file at jsonlPath.
+func Path(jsonlPath string) string {
+	return filepath.Join(filepath.Dir(jsonlPath), FileName)
+}
+
+// Replica returns this clone's replica ID, creating it on first use.
+func Replica(ctx context.Context, s storage.Storage) (string, error) {
+	id, err := s.GetMetadata(ctx, replicaKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to read replica ID: %w", err)
+	}
+	if id != "" {
+		return id, nil
+	}
+	id = NewID()
+	if err := s.SetMetadata(ctx, replicaKey, id); err != nil {
+		return "", fmt.Errorf("failed to store replica ID: %w", err)
+	}
+	return id, nil
+}
+
+// Record appends ops for whatever changed in the given issues since the log
+// last saw them. Tombstones and wisps are skipped: deletions replicate as
+// JSONL tombstones and wisps never leave the clone. The ops are chained onto
+// this replica's earlier ones and, with signer set, signed. Returns the
+// number of ops appended.
+func Record(ctx context.Context, s storage.Storage, jsonlPath string, ids []string, actor string, signer Signer) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	path := Path(jsonlPath)
+	ops, err := Read(path)
+	if err != nil {
+		return 0, err
+	}
+	states := Replay(ops)
+	replica, err := Replica(ctx, s)
+	if err != nil {
+		return 0, err
+	}
+
+	// Stubs in a subset clone lack their long text; diffing them would
+	// record the text as cleared
+	stubs, err := subset.StubIDs(ctx, s)
+	if err != nil {
+		return 0, err
+	}
+
+	var newOps []Op
+	for _, id := range ids {
+		if stubs[id] {
+			continue
+		}
+		issue, err := loadIssue(ctx, s, id)
+		if err != nil {
+			return 0, err
+		}
+		if issue == nil {
+			continue
+		}
+		newOps = append(newOps, Diff(states[id], issue, replica, actor)...)
+	}
+	if err := link(ops, newOps, signer); err != nil {
+		return 0, err
+	}
+	if err := Append(path, newOps); err != nil {
+		return 0, err
+	}
+	return len(newOps), nil
+}
+
+// RecordPending records the edits this clone has not exported yet (its
+// dirty issues). Call it before importing, while the database still holds
+// the local values, so the replay afterwards weighs them against remote ops
+// instead of losing them to the import.
+func RecordPending(ctx context.Context, s storage.Storage, jsonlPath, actor string, signer Signer) (int, error) {
+	dirty, err := s.GetDirtyIssues(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get dirty issues: %w", err)
+	}
+	return Record(ctx, s, jsonlPath, dirty, actor, signer)
+}
+
+// Apply replays the log and updates every issue whose fields or labels
+// differ from the replayed state. Issues the log describes but the
+// database lacks, or holds only as subset stubs, are left to the JSONL
+// import. Returns the number of issues
+// changed.
+func Apply(ctx context.Context, s storage.Storage, jsonlPath, actor string) (int, error) {
+	ops, err := Read(Path(jsonlPath))
+	if err != nil {
+		return 0, err
+	}
+	stubs, err := subset.StubIDs(ctx, s)
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	for id, st := range Replay(ops) {
+		if stubs[id] {
+			continue
+		}
+		issue, err := loadIssue(ctx, s, id)
+		if err != nil {
+			return changed, err
+		}
+		if issue == nil {
+			continue
+		}
+		updates, addLabels, removeLabels := Changes(st, issue)
+		if len(updates) == 0 && len(addLabels) == 0 && len(removeLabels) == 0 {
+			continue
+		}
+		if len(updates) > 0 {
+			if err := s.UpdateIssue(ctx, id, updates, actor); err != nil {
+				return changed, fmt.Errorf("failed to apply op log to %s: %w", id, err)
+			}
+		}
+		for _, label := range addLabels {
+			if err := s.AddLabel(ctx, id, label, actor); err != nil {
+				return changed, fmt.Errorf("failed to add label %q to %s: %w", label, id, err)
+			}
+		}
+		for _, label := range removeLabels {
+			if err := s.RemoveLabel(ctx, id, label, actor); err != nil {
+				return changed, fmt.Errorf("failed to remove label %q from %s: %w", label, id, err)
+			}
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// loadIssue returns the issue with its labels, or nil if it is missing, a
+// tombstone or a wisp.
+func loadIssue(ctx context.Context, s storage.Storage, id string) (*types.Issue, error) {
+	issue, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue %s: %w", id, err)
+	}
+	if issue == nil || issue.IsTombstone() || issue.Ephemeral {
+		return nil, nil
+	}
+	labels, err := s.GetLabels(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels for %s: %w", id, err)
+	}
+	issue.Labels = labels
+	return issue, nil
+}
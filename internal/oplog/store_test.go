@@ -0,0 +1,105 @@
+package oplog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// clone is one checkout: its own database and its own copy of the log.
+type clone struct {
+	store storage.Storage
+	jsonl string
+}
+
+func newClone(t *testing.T) *clone {
+	t.Helper()
+	return &clone{store: memory.New(""), jsonl: filepath.Join(t.TempDir(), "issues.jsonl")}
+}
+
+func (c *clone) log(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile(Path(c.jsonl))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// unionMerge gives both clones the concatenation of their logs, as git's
+// union merge driver would.
+func unionMerge(t *testing.T, a, b *clone) {
+	t.Helper()
+	merged := append(a.log(t), b.log(t)...)
+	for _, c := range []*clone{a, b} {
+		if err := os.WriteFile(Path(c.jsonl), merged, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTwoClonesConvergeAfterOfflineEdits(t *testing.T) {
+	ctx := context.Background()
+	a, b := newClone(t), newClone(t)
+
+	for _, c := range []*clone{a, b} {
+		issue := &types.Issue{ID: "bd-1", Title: "Original", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := c.store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if err := c.store.AddLabel(ctx, "bd-1", "backend", "tester"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+	if _, err := Record(ctx, a.store, a.jsonl, []string{"bd-1"}, "tester", nil); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := os.WriteFile(Path(b.jsonl), a.log(t), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Offline: A retitles and drops the label, B closes and adds a label
+	time.Sleep(2 * time.Millisecond)
+	if err := a.store.UpdateIssue(ctx, "bd-1", map[string]interface{}{"title": "Renamed"}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.store.RemoveLabel(ctx, "bd-1", "backend", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.store.UpdateIssue(ctx, "bd-1", map[string]interface{}{"status": string(types.StatusClosed)}, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.store.AddLabel(ctx, "bd-1", "urgent", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []*clone{a, b} {
+		if _, err := RecordPending(ctx, c.store, c.jsonl, "tester", nil); err != nil {
+			t.Fatalf("RecordPending failed: %v", err)
+		}
+	}
+
+	unionMerge(t, a, b)
+	for name, c := range map[string]*clone{"A": a, "B": b} {
+		if _, err := Apply(ctx, c.store, c.jsonl, "import"); err != nil {
+			t.Fatalf("Apply on %s failed: %v", name, err)
+		}
+		issue, err := c.store.GetIssue(ctx, "bd-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		labels, _ := c.store.GetLabels(ctx, "bd-1")
+		if issue.Title != "Renamed" || issue.Status != types.StatusClosed {
+			t.Errorf("clone %s: title=%q status=%q, want both edits", name, issue.Title, issue.Status)
+		}
+		if !reflect.DeepEqual(labels, []string{"urgent"}) {
+			t.Errorf("clone %s: labels = %v, want [urgent]", name, labels)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+// Package orgmode reads and writes Emacs org-mode outlines of issues: one
+// heading per issue, with the TODO keyword carrying the status, the priority
+// cookie the priority, DEADLINE/SCHEDULED the due and defer dates, and
+// nesting the parent-child hierarchy.
+package orgmode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Keywords is the #+TODO line written to exported files: active states
+// before the bar, done states after it.
+const Keywords = "TODO STARTED WAITING DEFERRED | DONE CANCELLED"
+
+// Heading is one org heading.
+type Heading struct {
+	Level    int
+	Keyword  string // TODO keyword, "" for plain headings
+	Priority int    // 0-4 from [#A]-[#E], -1 when there is no cookie
+	Title    string
+	Tags     []string
+
+	Deadline  *time.Time
+	Scheduled *time.Time
+	Closed    *time.Time
+
+	// Properties holds the :PROPERTIES: drawer; nil when there is none.
+	Properties map[string]string
+	Body       string
+
+	// Parent is the index of the enclosing heading in the parsed list, or -1.
+	Parent int
+}
+
+// Document is a parsed org file.
+type Document struct {
+	Headings []*Heading
+	// done holds the done keywords declared by #+TODO lines, plus DONE and
+	// CANCELLED.
+	done map[string]bool
+	// active holds the other known keywords.
+	active map[string]bool
+}
+
+var (
+	headingRe  = regexp.MustCompile(`^(\*+)\s+(.*)$`)
+	priorityRe = regexp.MustCompile(`^\[#([A-Ea-e])\]\s*`)
+	tagsRe     = regexp.MustCompile(`\s+(:[\w@#%:]+:)\s*$`)
+	planningRe = regexp.MustCompile(`(DEADLINE|SCHEDULED|CLOSED):\s*[<\[](\d{4}-\d{2}-\d{2})(?: [A-Za-z]+)?(?: (\d{1,2}:\d{2}))?[^>\]]*[>\]]`)
+	propertyRe = regexp.MustCompile(`^\s*:([^:\s]+):\s*(.*)$`)
+)
+
+// Parse reads an org file. Keywords declared with #+TODO, #+SEQ_TODO or
+// #+TYP_TODO lines are recognized in addition to the built-in ones.
+func Parse(r io.Reader) (*Document, error) {
+	doc := &Document{done: map[string]bool{}, active: map[string]bool{}}
+	doc.declare(Keywords)
+	doc.declare("NEXT DOING HOLD BLOCKED SOMEDAY | CANCELED")
+
+	var current *Heading
+	var body []string
+	inDrawer := false
+	stack := []int{} // indexes of open ancestors, by level
+	flush := func() {
+		if current != nil {
+			current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		body = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			flush()
+			current = doc.parseHeading(len(m[1]), m[2])
+			for len(stack) > 0 && doc.Headings[stack[len(stack)-1]].Level >= current.Level {
+				stack = stack[:len(stack)-1]
+			}
+			current.Parent = -1
+			if len(stack) > 0 {
+				current.Parent = stack[len(stack)-1]
+			}
+			doc.Headings = append(doc.Headings, current)
+			stack = append(stack, len(doc.Headings)-1)
+			inDrawer = false
+			continue
+		}
+
+		if current == nil {
+			upper := strings.ToUpper(line)
+			for _, prefix := range []string{"#+TODO:", "#+SEQ_TODO:", "#+TYP_TODO:"} {
+				if strings.HasPrefix(upper, prefix) {
+					doc.declare(line[len(prefix):])
+				}
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inDrawer:
+			if strings.EqualFold(trimmed, ":END:") {
+				inDrawer = false
+			} else if m := propertyRe.FindStringSubmatch(line); m != nil {
+				current.Properties[strings.ToUpper(m[1])] = strings.TrimSpace(m[2])
+			}
+			continue
+		case strings.EqualFold(trimmed, ":PROPERTIES:") && len(body) == 0:
+			inDrawer = true
+			if current.Properties == nil {
+				current.Properties = map[string]string{}
+			}
+			continue
+		case len(body) == 0 && current.Properties == nil && planningRe.MatchString(trimmed) &&
+			strings.TrimSpace(planningRe.ReplaceAllString(trimmed, "")) == "":
+			for _, m := range planningRe.FindAllStringSubmatch(trimmed, -1) {
+				t := parseTimestamp(m[2], m[3])
+				switch m[1] {
+				case "DEADLINE":
+					current.Deadline = t
+				case "SCHEDULED":
+					current.Scheduled = t
+				case "CLOSED":
+					current.Closed = t
+				}
+			}
+			continue
+		}
+		// Org escapes heading-like lines in text with a leading comma
+		if strings.HasPrefix(line, ",*") {
+			line = line[1:]
+		}
+		body = append(body, line)
+	}
+	flush()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// declare registers the keywords of a #+TODO line. Keywords after the bar
+// (or the last one, when there is no bar) are done states; "(t)" style
+// fast-access keys are ignored.
+func (d *Document) declare(spec string) {
+	fields := strings.Fields(spec)
+	bar := -1
+	for i, f := range fields {
+		if f == "|" {
+			bar = i
+		}
+	}
+	for i, f := range fields {
+		if f == "|" {
+			continue
+		}
+		if j := strings.IndexByte(f, '('); j > 0 {
+			f = f[:j]
+		}
+		if (bar >= 0 && i > bar) || (bar < 0 && i == len(fields)-1) {
+			d.done[f] = true
+		} else {
+			d.active[f] = true
+		}
+	}
+}
+
+func (d *Document) parseHeading(level int, text string) *Heading {
+	h := &Heading{Level: level, Priority: -1}
+	if word, rest, _ := strings.Cut(text, " "); d.done[word] || d.active[word] {
+		h.Keyword = word
+		text = strings.TrimSpace(rest)
+	}
+	if m := priorityRe.FindStringSubmatch(text); m != nil {
+		h.Priority = int(strings.ToUpper(m[1])[0] - 'A')
+		text = text[len(m[0]):]
+	}
+	if m := tagsRe.FindStringSubmatchIndex(text); m != nil {
+		for _, tag := range strings.Split(text[m[2]:m[3]], ":") {
+			if tag != "" {
+				h.Tags = append(h.Tags, tag)
+			}
+		}
+		text = text[:m[0]]
+	}
+	h.Title = strings.TrimSpace(text)
+	return h
+}
+
+func parseTimestamp(date, clock string) *time.Time {
+	layout, value := "2006-01-02", date
+	if clock != "" {
+		layout, value = "2006-01-02 15:04", date+" "+clock
+	}
+	t, err := time.ParseInLocation(layout, value, time.Local)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// Status maps a heading's keyword to a beads status.
+func (d *Document) Status(keyword string) types.Status {
+	switch keyword {
+	case "STARTED", "DOING", "NEXT":
+		return types.StatusInProgress
+	case "WAITING", "HOLD", "BLOCKED":
+		return types.StatusBlocked
+	case "DEFERRED", "SOMEDAY":
+		return types.StatusDeferred
+	}
+	if d.done[keyword] {
+		return types.StatusClosed
+	}
+	return types.StatusOpen
+}
+
+// Keyword maps a beads status to the keyword written on export.
+func Keyword(status types.Status) string {
+	switch status {
+	case types.StatusInProgress, types.StatusHooked:
+		return "STARTED"
+	case types.StatusBlocked:
+		return "WAITING"
+	case types.StatusDeferred:
+		return "DEFERRED"
+	case types.StatusClosed, types.StatusTombstone:
+		return "DONE"
+	}
+	return "TODO"
+}
+
+// Tag converts a label to an org tag, which may only contain letters,
+// digits, '_', '@', '#' and '%'.
+func Tag(label string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == '@', r == '#', r == '%':
+			return r
+		}
+		return '_'
+	}, label)
+}
+
+// Entry is an issue to write, with the issues nested under it.
+type Entry struct {
+	Issue    *types.Issue
+	Children []*Entry
+}
+
+// Write writes entries as an org outline with a header declaring the beads
+// keywords and the five priorities.
+func Write(w io.Writer, title string, entries []*Entry) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "#+TITLE: %s\n", title)
+	fmt.Fprintf(bw, "#+TODO: %s\n", Keywords)
+	bw.WriteString("#+PRIORITIES: A E C\n")
+	bw.WriteString("#+STARTUP: overview\n")
+	for _, e := range entries {
+		bw.WriteString("\n")
+		writeEntry(bw, e, 1)
+	}
+	return bw.Flush()
+}
+
+func writeEntry(w *bufio.Writer, e *Entry, level int) {
+	issue := e.Issue
+	fmt.Fprintf(w, "%s %s [#%c] %s", strings.Repeat("*", level), Keyword(issue.Status), 'A'+rune(clampPriority(issue.Priority)), oneLine(issue.Title))
+	if len(issue.Labels) > 0 {
+		tags := make([]string, len(issue.Labels))
+		for i, label := range issue.Labels {
+			tags[i] = Tag(label)
+		}
+		fmt.Fprintf(w, " :%s:", strings.Join(tags, ":"))
+	}
+	w.WriteString("\n")
+
+	var planning []string
+	if issue.ClosedAt != nil && issue.Status == types.StatusClosed {
+		planning = append(planning, "CLOSED: "+issue.ClosedAt.Local().Format("[2006-01-02 Mon 15:04]"))
+	}
+	if issue.DueAt != nil {
+		planning = append(planning, "DEADLINE: "+formatActive(*issue.DueAt))
+	}
+	if issue.DeferUntil != nil {
+		planning = append(planning, "SCHEDULED: "+formatActive(*issue.DeferUntil))
+	}
+	if len(planning) > 0 {
+		w.WriteString(strings.Join(planning, " ") + "\n")
+	}
+
+	w.WriteString(":PROPERTIES:\n")
+	fmt.Fprintf(w, ":ID: %s\n", issue.ID)
+	fmt.Fprintf(w, ":TYPE: %s\n", issue.IssueType)
+	if issue.Assignee != "" {
+		fmt.Fprintf(w, ":ASSIGNEE: %s\n", issue.Assignee)
+	}
+	w.WriteString(":END:\n")
+
+	if desc := strings.TrimSpace(issue.Description); desc != "" {
+		for _, line := range strings.Split(desc, "\n") {
+			if strings.HasPrefix(line, "*") {
+				line = "," + line
+			}
+			w.WriteString(line + "\n")
+		}
+	}
+	for _, child := range e.Children {
+		writeEntry(w, child, level+1)
+	}
+}
+
+// formatActive formats an active timestamp, with a time only when t has one.
+func formatActive(t time.Time) string {
+	t = t.Local()
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return t.Format("<2006-01-02 Mon>")
+	}
+	return t.Format("<2006-01-02 Mon 15:04>")
+}
+
+func clampPriority(p int) int {
+	return max(0, min(4, p))
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,115 @@
+package orgmode
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+const sample = `#+TITLE: Backlog
+#+TODO: TODO(t) REVIEW | SHIPPED
+
+* Inbox
+** REVIEW [#a] Polish onboarding :ux:web:
+DEADLINE: <2025-03-14 Fri> SCHEDULED: <2025-03-01 Sat 09:30>
+:PROPERTIES:
+:ID: bd-1
+:ASSIGNEE: ana
+:END:
+First line.
+,* not a heading
+*** SHIPPED Copy tweaks
+CLOSED: [2025-02-20 Thu 17:00]
+** Notes
+* WAITING Vendor reply
+`
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(doc.Headings) != 5 {
+		t.Fatalf("got %d headings, want 5", len(doc.Headings))
+	}
+
+	inbox, polish, copyTweaks, notes, vendor := doc.Headings[0], doc.Headings[1], doc.Headings[2], doc.Headings[3], doc.Headings[4]
+	if inbox.Keyword != "" || inbox.Title != "Inbox" || inbox.Parent != -1 {
+		t.Errorf("inbox = %+v", inbox)
+	}
+	if polish.Keyword != "REVIEW" || polish.Priority != 0 || polish.Title != "Polish onboarding" || polish.Parent != 0 {
+		t.Errorf("polish = %+v", polish)
+	}
+	if !reflect.DeepEqual(polish.Tags, []string{"ux", "web"}) {
+		t.Errorf("tags = %v", polish.Tags)
+	}
+	if polish.Deadline == nil || polish.Deadline.Format("2006-01-02") != "2025-03-14" {
+		t.Errorf("deadline = %v", polish.Deadline)
+	}
+	if polish.Scheduled == nil || polish.Scheduled.Format("15:04") != "09:30" {
+		t.Errorf("scheduled = %v", polish.Scheduled)
+	}
+	if polish.Properties["ID"] != "bd-1" || polish.Properties["ASSIGNEE"] != "ana" {
+		t.Errorf("properties = %v", polish.Properties)
+	}
+	if polish.Body != "First line.\n* not a heading" {
+		t.Errorf("body = %q", polish.Body)
+	}
+	if copyTweaks.Parent != 1 || copyTweaks.Closed == nil || copyTweaks.Priority != -1 {
+		t.Errorf("copy tweaks = %+v", copyTweaks)
+	}
+	if notes.Parent != 0 || vendor.Parent != -1 {
+		t.Errorf("parents = %d, %d", notes.Parent, vendor.Parent)
+	}
+
+	for keyword, want := range map[string]types.Status{
+		"REVIEW": types.StatusOpen, "SHIPPED": types.StatusClosed, "WAITING": types.StatusBlocked,
+		"STARTED": types.StatusInProgress, "DEFERRED": types.StatusDeferred, "CANCELLED": types.StatusClosed,
+	} {
+		if got := doc.Status(keyword); got != want {
+			t.Errorf("Status(%s) = %s, want %s", keyword, got, want)
+		}
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	due := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	epic := &types.Issue{ID: "bd-1", Title: "Epic", Status: types.StatusInProgress, Priority: 1,
+		IssueType: types.TypeEpic, Labels: []string{"area:api", "v2"}, DueAt: &due}
+	task := &types.Issue{ID: "bd-2", Title: "Task", Status: types.StatusBlocked, Priority: 4,
+		IssueType: types.TypeTask, Assignee: "bo", Description: "* starts with a star"}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "bd issues", []*Entry{{Issue: epic, Children: []*Entry{{Issue: task}}}}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"#+TODO: " + Keywords + "\n",
+		"* STARTED [#B] Epic :area_api:v2:\nDEADLINE: <2025-06-01 Sun>\n",
+		"** WAITING [#E] Task\n:PROPERTIES:\n:ID: bd-2\n:TYPE: task\n:ASSIGNEE: bo\n:END:\n,* starts with a star\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	doc, err := Parse(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(doc.Headings) != 2 {
+		t.Fatalf("got %d headings, want 2", len(doc.Headings))
+	}
+	h := doc.Headings[1]
+	if doc.Status(h.Keyword) != types.StatusBlocked || h.Priority != 4 || h.Parent != 0 || h.Body != task.Description {
+		t.Errorf("task heading = %+v", h)
+	}
+	if got := doc.Headings[0].Deadline; got == nil || !got.Equal(due) {
+		t.Errorf("deadline = %v, want %v", got, due)
+	}
+}
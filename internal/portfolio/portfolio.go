@@ -0,0 +1,216 @@
+// Package portfolio scores the health of the projects in a multi-repo
+// workspace (bd portfolio): open work, how much of it is blocked or stale,
+// and how many issues have broken their service level.
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// DefaultStaleDays is how long an open issue can go without an update
+// before it counts as stale.
+const DefaultStaleDays = 30
+
+// DefaultSLA is the service level used when portfolio.sla is not set.
+const DefaultSLA = "p0=1d,p1=7d"
+
+// Health ratings, from best to worst.
+const (
+	Healthy  = "healthy"
+	AtRisk   = "at-risk"
+	Critical = "critical"
+)
+
+// Options shapes an assessment.
+type Options struct {
+	StaleDays int                   // 0 for DefaultStaleDays
+	SLA       map[int]time.Duration // maximum open age by priority
+}
+
+// Project is the health of one project.
+type Project struct {
+	Name         string     `json:"name"`
+	Path         string     `json:"path"`
+	Open         int        `json:"open"`
+	InProgress   int        `json:"in_progress"`
+	Blocked      int        `json:"blocked"`
+	BlockedRatio float64    `json:"blocked_ratio"`
+	Stale        int        `json:"stale"`
+	LastActivity *time.Time `json:"last_activity,omitempty"`
+	SLABreaches  int        `json:"sla_breaches"`
+	Score        int        `json:"score"`
+	Health       string     `json:"health"`
+	Error        string     `json:"error,omitempty"` // set when the project could not be read
+}
+
+// ParseSLA parses a service level such as "p0=1d,p1=7d": the longest an
+// issue of each priority may stay open. Priorities not listed have none.
+func ParseSLA(s string) (map[int]time.Duration, error) {
+	sla := make(map[int]time.Duration)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid SLA entry %q (want p<priority>=<duration>, e.g. p0=1d)", part)
+		}
+		priority, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(key)), "p"))
+		if err != nil || priority < 0 || priority > 4 {
+			return nil, fmt.Errorf("invalid SLA priority %q (want p0-p4)", key)
+		}
+		d, err := parseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid SLA duration %q for %s", value, key)
+		}
+		sla[priority] = d
+	}
+	return sla, nil
+}
+
+// parseDuration accepts Go durations plus days and weeks ("3d", "2w").
+func parseDuration(s string) (time.Duration, error) {
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		days, err := strconv.Atoi(n)
+		return time.Duration(days) * 24 * time.Hour, err
+	}
+	if n, ok := strings.CutSuffix(s, "w"); ok {
+		weeks, err := strconv.Atoi(n)
+		return time.Duration(weeks) * 7 * 24 * time.Hour, err
+	}
+	return time.ParseDuration(s)
+}
+
+// Assess scores one project from its issues. Issues count as open until
+// closed; an open issue is blocked when its status says so or when it
+// waits on an open issue in the same project. An issue breaks its service
+// level when it is past its due date or has been open longer than the SLA
+// for its priority.
+func Assess(name, path string, issues []*types.Issue, now time.Time, opts Options) Project {
+	staleDays := opts.StaleDays
+	if staleDays <= 0 {
+		staleDays = DefaultStaleDays
+	}
+	staleBefore := now.AddDate(0, 0, -staleDays)
+
+	open := make(map[string]bool)
+	for _, issue := range issues {
+		if isOpen(issue) {
+			open[issue.ID] = true
+		}
+	}
+
+	p := Project{Name: name, Path: path}
+	for _, issue := range issues {
+		if issue.Status == types.StatusTombstone {
+			continue
+		}
+		if p.LastActivity == nil || issue.UpdatedAt.After(*p.LastActivity) {
+			updated := issue.UpdatedAt
+			p.LastActivity = &updated
+		}
+		if !isOpen(issue) {
+			continue
+		}
+		p.Open++
+		if issue.Status == types.StatusInProgress {
+			p.InProgress++
+		}
+		if isBlocked(issue, open) {
+			p.Blocked++
+		}
+		if issue.Status != types.StatusPinned && issue.UpdatedAt.Before(staleBefore) {
+			p.Stale++
+		}
+		if breaches(issue, now, opts.SLA) {
+			p.SLABreaches++
+		}
+	}
+	if p.Open > 0 {
+		p.BlockedRatio = round2(float64(p.Blocked) / float64(p.Open))
+	}
+	p.Score = score(p)
+	p.Health = Rating(p.Score)
+	return p
+}
+
+// Failed records a project that could not be read. It sorts last and
+// scores zero.
+func Failed(name, path string, err error) Project {
+	return Project{Name: name, Path: path, Health: Critical, Error: err.Error()}
+}
+
+// Rating names the health band for a score.
+func Rating(score int) string {
+	switch {
+	case score >= 80:
+		return Healthy
+	case score >= 50:
+		return AtRisk
+	default:
+		return Critical
+	}
+}
+
+// Sort orders projects worst first, so the ones that need attention lead
+// the table. Ties keep name order.
+func Sort(projects []Project) {
+	sort.SliceStable(projects, func(i, j int) bool {
+		a, b := projects[i], projects[j]
+		if (a.Error != "") != (b.Error != "") {
+			return a.Error == ""
+		}
+		if a.Score != b.Score {
+			return a.Score < b.Score
+		}
+		return a.Name < b.Name
+	})
+}
+
+// score starts at 100 and loses up to 30 points for the blocked share of
+// open work, 30 for the stale share and 40 for the share breaking its
+// service level. A project with nothing open scores 100.
+func score(p Project) int {
+	if p.Open == 0 {
+		return 100
+	}
+	open := float64(p.Open)
+	penalty := 30*float64(p.Blocked)/open + 30*float64(p.Stale)/open + 40*float64(p.SLABreaches)/open
+	return int(math.Round(100 - penalty))
+}
+
+func isOpen(issue *types.Issue) bool {
+	return issue.Status != types.StatusClosed && issue.Status != types.StatusTombstone
+}
+
+func isBlocked(issue *types.Issue, open map[string]bool) bool {
+	if issue.Status == types.StatusBlocked {
+		return true
+	}
+	for _, dep := range issue.Dependencies {
+		if dep.Type == types.DepBlocks && open[dep.DependsOnID] {
+			return true
+		}
+	}
+	return false
+}
+
+func breaches(issue *types.Issue, now time.Time, sla map[int]time.Duration) bool {
+	if issue.DueAt != nil && issue.DueAt.Before(now) {
+		return true
+	}
+	limit, ok := sla[issue.Priority]
+	return ok && now.Sub(issue.CreatedAt) > limit
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,91 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParseSLA(t *testing.T) {
+	sla, err := ParseSLA("p0=1d, P1=2w,p3=36h")
+	if err != nil {
+		t.Fatalf("ParseSLA: %v", err)
+	}
+	want := map[int]time.Duration{0: 24 * time.Hour, 1: 14 * 24 * time.Hour, 3: 36 * time.Hour}
+	if len(sla) != len(want) {
+		t.Fatalf("got %v, want %v", sla, want)
+	}
+	for p, d := range want {
+		if sla[p] != d {
+			t.Errorf("p%d = %v, want %v", p, sla[p], d)
+		}
+	}
+	for _, bad := range []string{"p0", "p9=1d", "x=1d", "p1=soon", "p1=0d"} {
+		if _, err := ParseSLA(bad); err == nil {
+			t.Errorf("ParseSLA(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestAssess(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	past := now.Add(-day)
+	issues := []*types.Issue{
+		{ID: "a-1", Status: types.StatusOpen, Priority: 2, CreatedAt: now.Add(-2 * day), UpdatedAt: now.Add(-day)},
+		{ID: "a-2", Status: types.StatusInProgress, Priority: 2, CreatedAt: now.Add(-2 * day), UpdatedAt: now,
+			Dependencies: []*types.Dependency{{IssueID: "a-2", DependsOnID: "a-1", Type: types.DepBlocks}}},
+		// Waits on a closed issue, so not blocked; breaks its p0 SLA.
+		{ID: "a-3", Status: types.StatusOpen, Priority: 0, CreatedAt: now.Add(-3 * day), UpdatedAt: now.Add(-day),
+			Dependencies: []*types.Dependency{{IssueID: "a-3", DependsOnID: "a-4", Type: types.DepBlocks}}},
+		{ID: "a-4", Status: types.StatusClosed, Priority: 2, CreatedAt: now.Add(-90 * day), UpdatedAt: now.Add(-90 * day)},
+		// Stale and overdue.
+		{ID: "a-5", Status: types.StatusBlocked, Priority: 3, CreatedAt: now.Add(-60 * day), UpdatedAt: now.Add(-40 * day), DueAt: &past},
+		{ID: "a-6", Status: types.StatusTombstone, Priority: 0, CreatedAt: now.Add(-60 * day), UpdatedAt: now.Add(day)},
+	}
+	p := Assess("app", "../app", issues, now, Options{SLA: map[int]time.Duration{0: day}})
+
+	if p.Open != 4 || p.InProgress != 1 || p.Blocked != 2 || p.Stale != 1 || p.SLABreaches != 2 {
+		t.Fatalf("counts = open %d, in progress %d, blocked %d, stale %d, breaches %d; want 4, 1, 2, 1, 2",
+			p.Open, p.InProgress, p.Blocked, p.Stale, p.SLABreaches)
+	}
+	if p.BlockedRatio != 0.5 {
+		t.Errorf("BlockedRatio = %v, want 0.5", p.BlockedRatio)
+	}
+	// 100 - 30*2/4 - 30*1/4 - 40*2/4 = 57.5
+	if p.Score != 58 || p.Health != AtRisk {
+		t.Errorf("score = %d (%s), want 58 (%s)", p.Score, p.Health, AtRisk)
+	}
+	if p.LastActivity == nil || !p.LastActivity.Equal(now) {
+		t.Errorf("LastActivity = %v, want %v (tombstones ignored)", p.LastActivity, now)
+	}
+}
+
+func TestAssessEmptyProject(t *testing.T) {
+	p := Assess("empty", ".", nil, time.Now(), Options{})
+	if p.Score != 100 || p.Health != Healthy || p.LastActivity != nil {
+		t.Errorf("got %+v, want a healthy project with no activity", p)
+	}
+}
+
+func TestSort(t *testing.T) {
+	projects := []Project{
+		{Name: "b", Score: 90},
+		Failed("broken", "x", errTest("no such file")),
+		{Name: "c", Score: 40},
+		{Name: "a", Score: 90},
+	}
+	Sort(projects)
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if got := names[0] + names[1] + names[2] + names[3]; got != "cabbroken" {
+		t.Errorf("order = %v, want [c a b broken]", names)
+	}
+}
+
+type errTest string
+
+func (e errTest) Error() string { return string(e) }
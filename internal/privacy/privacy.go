@@ -0,0 +1,295 @@
+// Package privacy collects and erases the records attributed to a person,
+// for data subject access and erasure requests (bd user export and bd user
+// forget).
+//
+// A person is identified by one or more names - an email, an actor name, a
+// display name - matched case-insensitively against every field that
+// attributes a record: issue assignee, owner, creator, deleter and sender,
+// comment authors, audit trail events, dependency creators, and the actor
+// of .beads/interactions.jsonl entries. Mentions inside free text are not
+// attribution and are left alone.
+package privacy
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/audit"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ErrUnsupported is returned by Forget for storage backends that can't
+// rewrite attribution.
+var ErrUnsupported = errors.New("bd user forget requires the SQLite backend")
+
+// Identity is one person under all their names.
+type Identity []string
+
+// Matches reports whether s names the person.
+func (id Identity) Matches(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false
+	}
+	for _, name := range id {
+		if strings.EqualFold(s, strings.TrimSpace(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesValue reports whether an event value names the person. Values are
+// JSON, such as {"assignee":"alice"}, or plain strings.
+func (id Identity) matchesValue(value *string) bool {
+	if value == nil {
+		return false
+	}
+	var doc interface{}
+	if json.Unmarshal([]byte(*value), &doc) != nil {
+		return id.Matches(*value)
+	}
+	var walk func(v interface{}) bool
+	walk = func(v interface{}) bool {
+		switch v := v.(type) {
+		case string:
+			return id.Matches(v)
+		case map[string]interface{}:
+			for _, e := range v {
+				if walk(e) {
+					return true
+				}
+			}
+		case []interface{}:
+			for _, e := range v {
+				if walk(e) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return walk(doc)
+}
+
+// Pseudonym is the stable name forgotten records are attributed to by
+// default, so they still group together without naming anyone.
+func (id Identity) Pseudonym() string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(id[0]))))
+	return "forgotten-" + hex.EncodeToString(sum[:4])
+}
+
+// IssueRecord is an issue attributed to the person, and how.
+type IssueRecord struct {
+	ID     string   `json:"id"`
+	Title  string   `json:"title"`
+	Status string   `json:"status"`
+	Roles  []string `json:"roles"` // Fields naming the person: assignee, owner, created_by, ...
+}
+
+// CommentRecord is a comment the person wrote.
+type CommentRecord struct {
+	IssueID   string    `json:"issue_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Export is everything attributed to the person.
+type Export struct {
+	Identity     Identity            `json:"identity"`
+	GeneratedAt  time.Time           `json:"generated_at"`
+	Issues       []IssueRecord       `json:"issues"`
+	Comments     []CommentRecord     `json:"comments"`
+	Events       []*types.Event      `json:"events"`
+	Dependencies []*types.Dependency `json:"dependencies"`
+	Interactions []audit.Entry       `json:"interactions"`
+}
+
+// Collect gathers the records attributed to id from s and the interactions
+// log at interactionsPath ("" to skip it).
+func Collect(ctx context.Context, s storage.Storage, interactionsPath string, id Identity) (*Export, error) {
+	out := &Export{
+		Identity:     id,
+		GeneratedAt:  time.Now().UTC(),
+		Issues:       []IssueRecord{},
+		Comments:     []CommentRecord{},
+		Events:       []*types.Event{},
+		Dependencies: []*types.Dependency{},
+		Interactions: []audit.Entry{},
+	}
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{IncludeTombstones: true})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+		var roles []string
+		for _, f := range []struct{ role, value string }{
+			{"assignee", issue.Assignee},
+			{"owner", issue.Owner},
+			{"created_by", issue.CreatedBy},
+			{"deleted_by", issue.DeletedBy},
+			{"sender", issue.Sender},
+		} {
+			if id.Matches(f.value) {
+				roles = append(roles, f.role)
+			}
+		}
+		if len(roles) > 0 {
+			out.Issues = append(out.Issues, IssueRecord{ID: issue.ID, Title: issue.Title, Status: string(issue.Status), Roles: roles})
+		}
+
+		events, err := s.GetEvents(ctx, issue.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			if id.Matches(e.Actor) || id.matchesValue(e.OldValue) || id.matchesValue(e.NewValue) {
+				out.Events = append(out.Events, e)
+			}
+		}
+	}
+
+	comments, err := s.GetCommentsForIssues(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for issueID, list := range comments {
+		for _, c := range list {
+			if id.Matches(c.Author) {
+				out.Comments = append(out.Comments, CommentRecord{IssueID: issueID, Text: c.Text, CreatedAt: c.CreatedAt})
+			}
+		}
+	}
+	sort.Slice(out.Comments, func(i, j int) bool { return out.Comments[i].CreatedAt.Before(out.Comments[j].CreatedAt) })
+
+	deps, err := s.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range deps {
+		for _, d := range list {
+			if id.Matches(d.CreatedBy) {
+				out.Dependencies = append(out.Dependencies, d)
+			}
+		}
+	}
+	sort.Slice(out.Dependencies, func(i, j int) bool {
+		a, b := out.Dependencies[i], out.Dependencies[j]
+		return a.IssueID+" "+a.DependsOnID < b.IssueID+" "+b.DependsOnID
+	})
+
+	if interactionsPath != "" {
+		entries, err := readInteractions(interactionsPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if id.Matches(e.Actor) {
+				out.Interactions = append(out.Interactions, e)
+			}
+		}
+	}
+	return out, nil
+}
+
+// Total is the number of records attributed to the person.
+func (e *Export) Total() int {
+	return len(e.Issues) + len(e.Comments) + len(e.Events) + len(e.Dependencies) + len(e.Interactions)
+}
+
+func readInteractions(path string) ([]audit.Entry, error) {
+	f, err := os.Open(path) // #nosec G304 - the interactions log in .beads
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	var entries []audit.Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e audit.Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// ForgetOptions control erasure.
+type ForgetOptions struct {
+	// Replacement is the name records are attributed to instead; empty
+	// uses the identity's Pseudonym
+	Replacement string
+	// DeleteComments deletes the person's comments instead of only
+	// anonymizing their author
+	DeleteComments bool
+	DryRun         bool
+}
+
+// Report is what Forget changed, or would change with DryRun.
+type Report struct {
+	Identity    Identity       `json:"identity"`
+	Replacement string         `json:"replacement"`
+	DryRun      bool           `json:"dry_run"`
+	Touched     map[string]int `json:"touched"` // Records changed per table.column
+	Issues      []string       `json:"issues"`  // Issues whose exported JSONL changed
+}
+
+// Total is the number of records changed.
+func (r *Report) Total() int {
+	n := 0
+	for _, c := range r.Touched {
+		n += c
+	}
+	return n
+}
+
+// Store is implemented by storage backends that can rewrite attribution
+// (SQLite).
+type Store interface {
+	ReplaceIdentity(ctx context.Context, names []string, replacement string, deleteComments, dryRun bool) (map[string]int, []string, error)
+}
+
+// Forget re-attributes everything attributed to id in s and the
+// interactions log at interactionsPath ("" to skip it).
+func Forget(ctx context.Context, s storage.Storage, interactionsPath string, id Identity, opts ForgetOptions) (*Report, error) {
+	is, ok := s.(Store)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	replacement := opts.Replacement
+	if replacement == "" {
+		replacement = id.Pseudonym()
+	}
+	touched, issues, err := is.ReplaceIdentity(ctx, id, replacement, opts.DeleteComments, opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	if interactionsPath != "" {
+		n, err := audit.ReplaceActor(interactionsPath, id.Matches, replacement, opts.DryRun)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			touched["interactions.actor"] = n
+		}
+	}
+	if issues == nil {
+		issues = []string{}
+	}
+	return &Report{Identity: id, Replacement: replacement, DryRun: opts.DryRun, Touched: touched, Issues: issues}, nil
+}
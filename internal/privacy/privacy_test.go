@@ -0,0 +1,165 @@
+package privacy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestIdentityMatches(t *testing.T) {
+	id := Identity{"alice@example.com", "Alice Smith"}
+	for _, s := range []string{"alice@example.com", "ALICE@example.com", " alice smith "} {
+		if !id.Matches(s) {
+			t.Errorf("Matches(%q) = false, want true", s)
+		}
+	}
+	for _, s := range []string{"", "alice", "bob@example.com", "malice@example.com"} {
+		if id.Matches(s) {
+			t.Errorf("Matches(%q) = true, want false", s)
+		}
+	}
+	value := `{"assignee":"Alice Smith"}`
+	if !id.matchesValue(&value) {
+		t.Errorf("matchesValue(%s) = false, want true", value)
+	}
+	if got := id.Pseudonym(); got != (Identity{"Alice@Example.com"}).Pseudonym() || !strings.HasPrefix(got, "forgotten-") {
+		t.Errorf("Pseudonym() = %q, want a stable forgotten-<hash>", got)
+	}
+}
+
+func TestCollectAndForget(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := sqlite.New(ctx, filepath.Join(dir, "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	mine := &types.Issue{ID: "bd-1", Title: "mine", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, CreatedBy: "alice@example.com"}
+	other := &types.Issue{ID: "bd-2", Title: "other", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, CreatedBy: "bob"}
+	for _, issue := range []*types.Issue{mine, other} {
+		if err := store.CreateIssue(ctx, issue, issue.CreatedBy); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.UpdateIssue(ctx, "bd-2", map[string]interface{}{"assignee": "Alice@Example.com"}, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddIssueComment(ctx, "bd-2", "alice@example.com", "looking at alice's bug"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddIssueComment(ctx, "bd-2", "bob", "thanks"); err != nil {
+		t.Fatal(err)
+	}
+	interactions := filepath.Join(dir, "interactions.jsonl")
+	log := `{"id":"int-1","kind":"llm_call","created_at":"2026-01-01T00:00:00Z","actor":"alice@example.com"}
+{"id":"int-2","kind":"llm_call","created_at":"2026-01-01T00:00:00Z","actor":"bob"}
+`
+	if err := os.WriteFile(interactions, []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	id := Identity{"alice@example.com"}
+	export, err := Collect(ctx, store, interactions, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(export.Issues) != 2 || len(export.Comments) != 1 || len(export.Interactions) != 1 {
+		t.Fatalf("export has %d issues, %d comments, %d interactions, want 2, 1, 1", len(export.Issues), len(export.Comments), len(export.Interactions))
+	}
+	// bd-1's created event is by alice; bd-2's assignment names her
+	if len(export.Events) != 2 {
+		t.Errorf("export has %d events, want 2", len(export.Events))
+	}
+
+	preview, err := Forget(ctx, store, interactions, id, ForgetOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Touched["issues.created_by"] != 1 || preview.Touched["issues.assignee"] != 1 || preview.Touched["comments.author"] != 1 {
+		t.Errorf("preview touched %v", preview.Touched)
+	}
+	if again, _ := Collect(ctx, store, interactions, id); again.Total() != export.Total() {
+		t.Fatal("dry run changed records")
+	}
+
+	report, err := Forget(ctx, store, interactions, id, ForgetOptions{Replacement: "former-member"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Issues) != 2 || report.Touched["interactions.actor"] != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	after, err := Collect(ctx, store, interactions, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Total() != 0 {
+		t.Errorf("%d records still attributed after forget: %+v", after.Total(), after)
+	}
+	got, _ := store.GetIssue(ctx, "bd-2")
+	if got.Assignee != "former-member" {
+		t.Errorf("assignee = %q, want former-member", got.Assignee)
+	}
+	comments, _ := store.GetIssueComments(ctx, "bd-2")
+	if len(comments) != 2 || comments[0].Text != "looking at alice's bug" {
+		t.Errorf("comments = %+v, want both kept with text untouched", comments)
+	}
+	if got.ContentHash != got.ComputeContentHash() {
+		t.Error("content hash not recomputed")
+	}
+	data, _ := os.ReadFile(interactions)
+	if strings.Contains(string(data), "alice") || !strings.Contains(string(data), `"actor":"bob"`) {
+		t.Errorf("interactions log after forget:\n%s", data)
+	}
+
+	dirty, _ := store.GetDirtyIssues(ctx)
+	if len(dirty) < 2 {
+		t.Errorf("dirty issues = %v, want bd-1 and bd-2", dirty)
+	}
+}
+
+func TestForgetDeleteComments(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+	issue := &types.Issue{ID: "bd-1", Title: "t", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	for _, author := range []string{"alice", "bob"} {
+		if _, err := store.AddIssueComment(ctx, "bd-1", author, "hi"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Forget(ctx, store, "", Identity{"alice"}, ForgetOptions{DeleteComments: true}); err != nil {
+		t.Fatal(err)
+	}
+	comments, _ := store.GetIssueComments(ctx, "bd-1")
+	if len(comments) != 1 || comments[0].Author != "bob" {
+		t.Errorf("comments = %+v, want only bob's", comments)
+	}
+}
+
+func TestForgetUnsupportedStore(t *testing.T) {
+	_, err := Forget(context.Background(), memory.New(""), "", Identity{"alice"}, ForgetOptions{})
+	if err != ErrUnsupported {
+		t.Errorf("err = %v, want ErrUnsupported", err)
+	}
+}
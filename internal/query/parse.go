@@ -0,0 +1,356 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/steveyegge/beads/internal/timeparsing"
+	"github.com/steveyegge/beads/internal/validation"
+)
+
+// Options control how expressions are parsed.
+type Options struct {
+	// Now anchors relative dates such as -7d. Defaults to time.Now().
+	Now time.Time
+
+	// Saved resolves @name references to saved filter expressions. If nil,
+	// references are an error.
+	Saved func(name string) (string, error)
+}
+
+// SyntaxError describes a malformed expression.
+type SyntaxError struct {
+	Pos int // 1-based character offset
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos, e.Msg)
+}
+
+// Parse compiles an expression.
+func Parse(src string, opts Options) (Expr, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	return parse(src, opts, nil)
+}
+
+func parse(src string, opts Options, stack []string) (Expr, error) {
+	p := &parser{src: src, opts: opts, stack: stack}
+	p.skipSpace()
+	if p.eof() {
+		return nil, p.errorf("empty expression")
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:p.pos+1])
+	}
+	return e, nil
+}
+
+type parser struct {
+	src   string
+	pos   int
+	opts  Options
+	stack []string // saved filters being expanded, for cycle detection
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Pos: p.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+// keyword consumes the case-insensitive word kw if it appears next as a
+// whole word.
+func (p *parser) keyword(kw string) bool {
+	p.skipSpace()
+	end := p.pos + len(kw)
+	if end > len(p.src) || !strings.EqualFold(p.src[p.pos:end], kw) {
+		return false
+	}
+	if end < len(p.src) && !isDelimiter(p.src[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func isDelimiter(c byte) bool {
+	return c == '(' || c == ')' || unicode.IsSpace(rune(c))
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.eof() || p.src[p.pos] == ')' {
+			return left, nil
+		}
+		save := p.pos
+		if p.keyword("or") {
+			p.pos = save
+			return left, nil
+		}
+		p.keyword("and") // optional: adjacent terms are ANDed
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.keyword("not") {
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{e}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	p.skipSpace()
+	if p.eof() {
+		return nil, p.errorf("expected a condition")
+	}
+
+	switch p.src[p.pos] {
+	case '(':
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.eof() || p.src[p.pos] != ')' {
+			return nil, p.errorf("missing )")
+		}
+		p.pos++
+		return e, nil
+	case ')':
+		return nil, p.errorf("unexpected )")
+	case '@':
+		return p.parseSaved()
+	}
+
+	start := p.pos
+	name := p.ident()
+	if name == "" {
+		return nil, p.errorf("expected a field name")
+	}
+	canonical := strings.ToLower(name)
+	if alias, ok := fieldAliases[canonical]; ok {
+		canonical = alias
+	}
+	f, ok := fields[canonical]
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("unknown field %q (fields: %s)", name, strings.Join(FieldNames(), ", "))
+	}
+
+	o, ok := p.operator(f)
+	if !ok {
+		return nil, p.errorf("expected an operator after %q", name)
+	}
+	if !containsOp(allowedOps[f.kind], o) {
+		return nil, p.errorf("operator %s is not supported for %s", o, f.name)
+	}
+
+	valuePos := p.pos
+	value, quoted, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &compare{field: f, op: o, value: value, raw: quoteValue(value)}
+	if !quoted && strings.EqualFold(value, "none") {
+		if (o != opEq && o != opNe) || f.kind == kindPriority {
+			p.pos = valuePos
+			return nil, p.errorf("none can only be compared with = or != on %s", f.name)
+		}
+		c.none, c.raw = true, "none"
+		return c, nil
+	}
+
+	switch f.kind {
+	case kindPriority:
+		n, err := validation.ValidatePriority(value)
+		if err != nil {
+			p.pos = valuePos
+			return nil, p.errorf("%v", err)
+		}
+		c.num = n
+	case kindTime:
+		if o == opEq || o == opNe {
+			p.pos = valuePos
+			return nil, p.errorf("compare %s with <, <=, > or >= (or =none)", f.name)
+		}
+		t, err := timeparsing.ParseRelativeTime(value, p.opts.Now)
+		if err != nil {
+			p.pos = valuePos
+			return nil, p.errorf("invalid date %q for %s", value, f.name)
+		}
+		c.when = t
+	}
+	return c, nil
+}
+
+func (p *parser) parseSaved() (Expr, error) {
+	start := p.pos
+	p.pos++ // '@'
+	name := p.ident()
+	if name == "" {
+		return nil, p.errorf("expected a saved filter name after @")
+	}
+	if p.opts.Saved == nil {
+		p.pos = start
+		return nil, p.errorf("saved filters are not available here")
+	}
+	for _, s := range p.stack {
+		if s == name {
+			p.pos = start
+			return nil, p.errorf("saved filter @%s refers to itself", name)
+		}
+	}
+	src, err := p.opts.Saved(name)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("%v", err)
+	}
+	e, err := parse(src, p.opts, append(p.stack, name))
+	if err != nil {
+		return nil, fmt.Errorf("in @%s: %w", name, err)
+	}
+	return e, nil
+}
+
+func (p *parser) ident() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.src[p.pos]
+		if c == '_' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.src[start:p.pos]
+}
+
+// operator consumes a comparison operator. ':' is shorthand for '~' on
+// free-text fields and '=' everywhere else.
+func (p *parser) operator(f *field) (op, bool) {
+	p.skipSpace()
+	for _, o := range []op{opNe, opLe, opGe, opNotContains, opEq, opLt, opGt, opContains} {
+		if strings.HasPrefix(p.src[p.pos:], string(o)) {
+			p.pos += len(o)
+			return o, true
+		}
+	}
+	if strings.HasPrefix(p.src[p.pos:], ":") {
+		p.pos++
+		if f.kind == kindText {
+			return opContains, true
+		}
+		return opEq, true
+	}
+	return "", false
+}
+
+// value reads a quoted string or a bare word ending at whitespace or ')'.
+func (p *parser) value() (string, bool, error) {
+	p.skipSpace()
+	if p.eof() {
+		return "", false, p.errorf("expected a value")
+	}
+	if q := p.src[p.pos]; q == '"' || q == '\'' {
+		p.pos++
+		var b strings.Builder
+		for !p.eof() {
+			c := p.src[p.pos]
+			switch {
+			case c == q:
+				p.pos++
+				return b.String(), true, nil
+			case c == '\\' && p.pos+1 < len(p.src):
+				b.WriteByte(p.src[p.pos+1])
+				p.pos += 2
+			default:
+				b.WriteByte(c)
+				p.pos++
+			}
+		}
+		return "", false, p.errorf("unterminated string")
+	}
+	start := p.pos
+	for !p.eof() && !isDelimiter(p.src[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", false, p.errorf("expected a value")
+	}
+	return p.src[start:p.pos], false, nil
+}
+
+func containsOp(ops []op, o op) bool {
+	for _, x := range ops {
+		if x == o {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigPrefix is the config key prefix under which saved filters are
+// stored: @mine is the expression in config key "query.mine".
+const ConfigPrefix = "query."
+
+// ConfigLookup returns an Options.Saved resolver that reads saved filters
+// with get, typically a storage GetConfig call.
+func ConfigLookup(get func(key string) (string, error)) func(string) (string, error) {
+	return func(name string) (string, error) {
+		src, err := get(ConfigPrefix + name)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(src) == "" {
+			return "", fmt.Errorf("no saved filter @%s", name)
+		}
+		return src, nil
+	}
+}
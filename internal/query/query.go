@@ -0,0 +1,432 @@
+// Package query implements bd's filter expression language.
+//
+// An expression combines field comparisons with and, or, not and
+// parentheses:
+//
+//	status=open and (priority<=1 or label:security) and updated>-7d
+//
+// Adjacent terms without an operator are ANDed. Expressions compile to a SQL
+// WHERE fragment over the issues table (Expr.SQL) and can also be evaluated
+// in memory (Expr.Match), so every storage backend can honor them.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Expr is a compiled query expression. It satisfies types.QueryExpr.
+type Expr interface {
+	// SQL returns a WHERE fragment over the issues table and its arguments.
+	SQL() (string, []interface{})
+	// Match reports whether issue satisfies the expression. Label and
+	// parent terms read issue.Labels and issue.Dependencies.
+	Match(issue *types.Issue) bool
+	// String returns the expression in canonical form, with saved filter
+	// references expanded.
+	String() string
+
+	walk(fn func(*compare))
+}
+
+var _ types.QueryExpr = Expr(nil)
+
+// References reports whether e compares the named field (e.g. "status").
+func References(e Expr, field string) bool {
+	found := false
+	e.walk(func(c *compare) {
+		if c.field.name == field {
+			found = true
+		}
+	})
+	return found
+}
+
+type fieldKind int
+
+const (
+	kindText      fieldKind = iota // free text: ':' means contains
+	kindKeyword                    // exact-match strings
+	kindPriority                   // 0-4, accepts P0-P4
+	kindTime                       // absolute or relative dates
+	kindLabel                      // labels table
+	kindParent                     // parent-child dependency
+	kindMilestone                  // milestone:<name> labels
+)
+
+// MilestonePrefix is the label prefix that puts an issue in a milestone;
+// milestone=v1.2 matches issues labeled milestone:v1.2.
+const MilestonePrefix = "milestone:"
+
+type field struct {
+	name   string
+	column string
+	kind   fieldKind
+}
+
+var fields = map[string]*field{
+	"id":          {"id", "id", kindKeyword},
+	"title":       {"title", "title", kindText},
+	"description": {"description", "description", kindText},
+	"notes":       {"notes", "notes", kindText},
+	"status":      {"status", "status", kindKeyword},
+	"type":        {"type", "issue_type", kindKeyword},
+	"assignee":    {"assignee", "assignee", kindKeyword},
+	"owner":       {"owner", "owner", kindKeyword},
+	"priority":    {"priority", "priority", kindPriority},
+	"created":     {"created", "created_at", kindTime},
+	"updated":     {"updated", "updated_at", kindTime},
+	"closed":      {"closed", "closed_at", kindTime},
+	"due":         {"due", "due_at", kindTime},
+	"defer":       {"defer", "defer_until", kindTime},
+	"label":       {"label", "", kindLabel},
+	"parent":      {"parent", "", kindParent},
+	"milestone":   {"milestone", "", kindMilestone},
+}
+
+// fieldAliases maps alternate spellings to canonical field names.
+var fieldAliases = map[string]string{
+	"desc":   "description",
+	"p":      "priority",
+	"pri":    "priority",
+	"labels": "label",
+}
+
+// FieldNames returns the names of all queryable fields.
+func FieldNames() []string {
+	return []string{"id", "title", "description", "notes", "status", "type", "assignee", "owner",
+		"priority", "created", "updated", "closed", "due", "defer", "label", "parent", "milestone"}
+}
+
+type op string
+
+const (
+	opEq          op = "="
+	opNe          op = "!="
+	opLt          op = "<"
+	opLe          op = "<="
+	opGt          op = ">"
+	opGe          op = ">="
+	opContains    op = "~"
+	opNotContains op = "!~"
+)
+
+// allowedOps lists the operators each kind of field supports.
+var allowedOps = map[fieldKind][]op{
+	kindText:      {opEq, opNe, opContains, opNotContains},
+	kindKeyword:   {opEq, opNe, opContains, opNotContains},
+	kindPriority:  {opEq, opNe, opLt, opLe, opGt, opGe},
+	kindTime:      {opLt, opLe, opGt, opGe, opEq, opNe},
+	kindLabel:     {opEq, opNe, opContains, opNotContains},
+	kindParent:    {opEq, opNe},
+	kindMilestone: {opEq, opNe, opContains, opNotContains},
+}
+
+type andExpr struct{ left, right Expr }
+type orExpr struct{ left, right Expr }
+type notExpr struct{ expr Expr }
+
+// compare is a single field comparison. none marks the unquoted value
+// "none", which tests for an empty field.
+type compare struct {
+	field *field
+	op    op
+	value string
+	raw   string // value as written, for String()
+	none  bool
+	num   int
+	when  time.Time
+}
+
+func (e *andExpr) SQL() (string, []interface{}) {
+	ls, la := e.left.SQL()
+	rs, ra := e.right.SQL()
+	return "(" + ls + " AND " + rs + ")", append(la, ra...)
+}
+
+func (e *orExpr) SQL() (string, []interface{}) {
+	ls, la := e.left.SQL()
+	rs, ra := e.right.SQL()
+	return "(" + ls + " OR " + rs + ")", append(la, ra...)
+}
+
+func (e *notExpr) SQL() (string, []interface{}) {
+	s, a := e.expr.SQL()
+	return "NOT " + s, a
+}
+
+func (e *andExpr) Match(i *types.Issue) bool { return e.left.Match(i) && e.right.Match(i) }
+func (e *orExpr) Match(i *types.Issue) bool  { return e.left.Match(i) || e.right.Match(i) }
+func (e *notExpr) Match(i *types.Issue) bool { return !e.expr.Match(i) }
+
+func (e *andExpr) String() string {
+	return wrapOr(e.left) + " and " + wrapOr(e.right)
+}
+
+func (e *orExpr) String() string {
+	return e.left.String() + " or " + e.right.String()
+}
+
+func (e *notExpr) String() string {
+	if _, ok := e.expr.(*compare); ok {
+		return "not " + e.expr.String()
+	}
+	return "not (" + e.expr.String() + ")"
+}
+
+func wrapOr(e Expr) string {
+	if _, ok := e.(*orExpr); ok {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+func (e *andExpr) walk(fn func(*compare)) { e.left.walk(fn); e.right.walk(fn) }
+func (e *orExpr) walk(fn func(*compare))  { e.left.walk(fn); e.right.walk(fn) }
+func (e *notExpr) walk(fn func(*compare)) { e.expr.walk(fn) }
+func (c *compare) walk(fn func(*compare)) { fn(c) }
+
+func (c *compare) String() string {
+	return c.field.name + string(c.op) + c.raw
+}
+
+// likePattern escapes s for use in a LIKE ... ESCAPE '\' substring match.
+func likePattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
+
+func (c *compare) SQL() (string, []interface{}) {
+	col := c.field.column
+	switch c.field.kind {
+	case kindLabel:
+		const sub = "id IN (SELECT issue_id FROM labels"
+		switch {
+		case c.none && c.op == opEq:
+			return "id NOT IN (SELECT issue_id FROM labels)", nil
+		case c.none:
+			return sub + ")", nil
+		case c.op == opEq:
+			return sub + " WHERE label = ?)", []interface{}{c.value}
+		case c.op == opNe:
+			return "NOT " + sub + " WHERE label = ?)", []interface{}{c.value}
+		case c.op == opContains:
+			return sub + ` WHERE label LIKE ? ESCAPE '\')`, []interface{}{likePattern(c.value)}
+		default:
+			return "NOT " + sub + ` WHERE label LIKE ? ESCAPE '\')`, []interface{}{likePattern(c.value)}
+		}
+
+	case kindMilestone:
+		const sub = "id IN (SELECT issue_id FROM labels WHERE label LIKE 'milestone:%'"
+		switch {
+		case c.none && c.op == opEq:
+			return "NOT " + sub + ")", nil
+		case c.none:
+			return sub + ")", nil
+		case c.op == opEq:
+			return sub + " AND label = ?)", []interface{}{MilestonePrefix + c.value}
+		case c.op == opNe:
+			return "NOT " + sub + " AND label = ?)", []interface{}{MilestonePrefix + c.value}
+		case c.op == opContains:
+			return sub + ` AND substr(label, 11) LIKE ? ESCAPE '\')`, []interface{}{likePattern(c.value)}
+		default:
+			return "NOT " + sub + ` AND substr(label, 11) LIKE ? ESCAPE '\')`, []interface{}{likePattern(c.value)}
+		}
+
+	case kindParent:
+		const sub = "id IN (SELECT issue_id FROM dependencies WHERE type = 'parent-child'"
+		switch {
+		case c.none && c.op == opEq:
+			return "NOT " + sub + ")", nil
+		case c.none:
+			return sub + ")", nil
+		case c.op == opEq:
+			return sub + " AND depends_on_id = ?)", []interface{}{c.value}
+		default:
+			return "NOT " + sub + " AND depends_on_id = ?)", []interface{}{c.value}
+		}
+
+	case kindPriority:
+		return fmt.Sprintf("priority %s ?", c.op), []interface{}{c.num}
+
+	case kindTime:
+		if c.none {
+			if c.op == opEq {
+				return col + " IS NULL", nil
+			}
+			return col + " IS NOT NULL", nil
+		}
+		return fmt.Sprintf("%s %s ?", col, c.op), []interface{}{c.when.UTC().Format(time.RFC3339)}
+	}
+
+	// Text and keyword columns. NULL and '' are the same "empty" value.
+	val := "COALESCE(" + col + ", '')"
+	switch {
+	case c.none && c.op == opEq:
+		return val + " = ''", nil
+	case c.none:
+		return val + " != ''", nil
+	case c.op == opEq:
+		return val + " = ?", []interface{}{c.value}
+	case c.op == opNe:
+		return val + " != ?", []interface{}{c.value}
+	case c.op == opContains:
+		return val + ` LIKE ? ESCAPE '\'`, []interface{}{likePattern(c.value)}
+	default:
+		return val + ` NOT LIKE ? ESCAPE '\'`, []interface{}{likePattern(c.value)}
+	}
+}
+
+func (c *compare) Match(issue *types.Issue) bool {
+	switch c.field.kind {
+	case kindLabel:
+		return c.matchAny(issue.Labels)
+
+	case kindParent:
+		var parents []string
+		for _, dep := range issue.Dependencies {
+			if dep.Type == types.DepParentChild {
+				parents = append(parents, dep.DependsOnID)
+			}
+		}
+		return c.matchAny(parents)
+
+	case kindMilestone:
+		var milestones []string
+		for _, label := range issue.Labels {
+			if strings.HasPrefix(label, MilestonePrefix) {
+				milestones = append(milestones, strings.TrimPrefix(label, MilestonePrefix))
+			}
+		}
+		return c.matchAny(milestones)
+
+	case kindPriority:
+		return compareOrdered(issue.Priority, c.num, c.op)
+
+	case kindTime:
+		t := timeField(issue, c.field.name)
+		if c.none {
+			return (t == nil) == (c.op == opEq)
+		}
+		if t == nil {
+			return false
+		}
+		switch c.op {
+		case opEq:
+			return t.Equal(c.when)
+		case opNe:
+			return !t.Equal(c.when)
+		}
+		return compareOrdered(t.Unix(), c.when.Unix(), c.op)
+	}
+
+	v := stringField(issue, c.field.name)
+	if c.none {
+		return (v == "") == (c.op == opEq)
+	}
+	return c.matchString(v)
+}
+
+// matchAny applies a label-style comparison to a set of values: positive
+// operators need one match, negated operators need none.
+func (c *compare) matchAny(values []string) bool {
+	if c.none {
+		return (len(values) == 0) == (c.op == opEq)
+	}
+	negated := c.op == opNe || c.op == opNotContains
+	positive := *c
+	switch c.op {
+	case opNe:
+		positive.op = opEq
+	case opNotContains:
+		positive.op = opContains
+	}
+	for _, v := range values {
+		if positive.matchString(v) {
+			return !negated
+		}
+	}
+	return negated
+}
+
+func (c *compare) matchString(v string) bool {
+	switch c.op {
+	case opEq:
+		return v == c.value
+	case opNe:
+		return v != c.value
+	case opContains:
+		return strings.Contains(strings.ToLower(v), strings.ToLower(c.value))
+	case opNotContains:
+		return !strings.Contains(strings.ToLower(v), strings.ToLower(c.value))
+	}
+	return false
+}
+
+func compareOrdered[T int | int64](a, b T, o op) bool {
+	switch o {
+	case opEq:
+		return a == b
+	case opNe:
+		return a != b
+	case opLt:
+		return a < b
+	case opLe:
+		return a <= b
+	case opGt:
+		return a > b
+	case opGe:
+		return a >= b
+	}
+	return false
+}
+
+func stringField(issue *types.Issue, name string) string {
+	switch name {
+	case "id":
+		return issue.ID
+	case "title":
+		return issue.Title
+	case "description":
+		return issue.Description
+	case "notes":
+		return issue.Notes
+	case "status":
+		return string(issue.Status)
+	case "type":
+		return string(issue.IssueType)
+	case "assignee":
+		return issue.Assignee
+	case "owner":
+		return issue.Owner
+	}
+	return ""
+}
+
+func timeField(issue *types.Issue, name string) *time.Time {
+	switch name {
+	case "created":
+		return &issue.CreatedAt
+	case "updated":
+		return &issue.UpdatedAt
+	case "closed":
+		return issue.ClosedAt
+	case "due":
+		return issue.DueAt
+	case "defer":
+		return issue.DeferUntil
+	}
+	return nil
+}
+
+// quoteValue returns v as it must be written in an expression.
+func quoteValue(v string) string {
+	if v == "" || v == "none" || strings.ContainsAny(v, " \t\n()\"'") {
+		return strconv.Quote(v)
+	}
+	return v
+}
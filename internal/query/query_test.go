@@ -0,0 +1,258 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/query"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var testNow = time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func TestParseString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"status=open", "status=open"},
+		{"status:open", "status=open"},
+		{"title:login", "title~login"},
+		{"p<=1", "priority<=1"},
+		{"priority=P0", "priority=P0"},
+		{"a=b", ""},
+		{"status=open priority<2", "status=open and priority<2"},
+		{"status=open AND (p<=1 OR label:security)", "status=open and (priority<=1 or label=security)"},
+		{"not label:wip", "not label=wip"},
+		{"not (status=closed or status=deferred)", "not (status=closed or status=deferred)"},
+		{`title~"sign in"`, `title~"sign in"`},
+		{"assignee=none", "assignee=none"},
+		{`assignee="none"`, `assignee="none"`},
+		{"desc!~todo", "description!~todo"},
+	}
+	for _, tt := range tests {
+		e, err := query.Parse(tt.in, query.Options{Now: testNow})
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Parse(%q) = %q, want error", tt.in, e.String())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := e.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+		// Canonical form parses back to itself.
+		again, err := query.Parse(e.String(), query.Options{Now: testNow})
+		if err != nil || again.String() != tt.want {
+			t.Errorf("reparse of %q: %v, %v", tt.want, again, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "empty expression"},
+		{"color=red", `unknown field "color"`},
+		{"status", "expected an operator"},
+		{"status=", "expected a value"},
+		{"(status=open", "missing )"},
+		{"status=open)", `unexpected ")"`},
+		{`title~"abc`, "unterminated string"},
+		{"priority~1", "operator ~ is not supported for priority"},
+		{"priority=9", "priority"},
+		{"updated=2025-01-01", "compare updated with"},
+		{"updated>yesterdayish", "invalid date"},
+		{"priority=none", "none can only be compared"},
+		{"@mine", "saved filters are not available"},
+		{"status=open or", "expected a condition"},
+	}
+	for _, tt := range tests {
+		_, err := query.Parse(tt.in, query.Options{Now: testNow})
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want error containing %q", tt.in, tt.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.in, err, tt.want)
+		}
+	}
+
+	var syntaxErr *query.SyntaxError
+	_, err := query.Parse("status=open color=red", query.Options{})
+	if !errors.As(err, &syntaxErr) || syntaxErr.Pos != 13 {
+		t.Errorf("error = %v, want SyntaxError at position 13", err)
+	}
+}
+
+func TestSavedFilters(t *testing.T) {
+	saved := map[string]string{
+		"query.mine":   "assignee=alice status!=closed",
+		"query.urgent": "@mine and priority<=1",
+		"query.loop1":  "@loop2",
+		"query.loop2":  "label=x or @loop1",
+	}
+	opts := query.Options{Now: testNow, Saved: query.ConfigLookup(func(key string) (string, error) {
+		return saved[key], nil
+	})}
+
+	e, err := query.Parse("@urgent or label=fire", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "assignee=alice and status!=closed and priority<=1 or label=fire"
+	if e.String() != want {
+		t.Errorf("String() = %q, want %q", e.String(), want)
+	}
+
+	if _, err := query.Parse("@missing", opts); err == nil || !strings.Contains(err.Error(), "no saved filter @missing") {
+		t.Errorf("missing filter error = %v", err)
+	}
+	if _, err := query.Parse("@loop1", opts); err == nil || !strings.Contains(err.Error(), "refers to itself") {
+		t.Errorf("cycle error = %v", err)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	e, err := query.Parse("not (status=closed) or label=x", query.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !query.References(e, "status") || !query.References(e, "label") {
+		t.Error("References missed a field")
+	}
+	if query.References(e, "closed") {
+		t.Error("References found a field that is not in the expression")
+	}
+}
+
+func TestSQL(t *testing.T) {
+	tests := []struct {
+		in   string
+		sql  string
+		args []interface{}
+	}{
+		{"status=open", "COALESCE(status, '') = ?", []interface{}{"open"}},
+		{"title:100%_done", `COALESCE(title, '') LIKE ? ESCAPE '\'`, []interface{}{`%100\%\_done%`}},
+		{"assignee=none", "COALESCE(assignee, '') = ''", nil},
+		{"p<2 or label=x", "(priority < ? OR id IN (SELECT issue_id FROM labels WHERE label = ?))", []interface{}{2, "x"}},
+		{"not due=none", "NOT due_at IS NULL", nil},
+		{"updated>-1d", "updated_at > ?", []interface{}{"2025-06-14T12:00:00Z"}},
+	}
+	for _, tt := range tests {
+		e, err := query.Parse(tt.in, query.Options{Now: testNow})
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.in, err)
+		}
+		sql, args := e.SQL()
+		if sql != tt.sql {
+			t.Errorf("%q: SQL = %q, want %q", tt.in, sql, tt.sql)
+		}
+		if fmt.Sprint(args) != fmt.Sprint(tt.args) {
+			t.Errorf("%q: args = %v, want %v", tt.in, args, tt.args)
+		}
+	}
+}
+
+// TestBackendsAgree checks that the SQL compilation and in-memory matching
+// select the same issues.
+func TestBackendsAgree(t *testing.T) {
+	ctx := context.Background()
+
+	sqlStore, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlStore.Close()
+	memStore := memory.New("")
+
+	now := time.Now()
+	due := now.Add(48 * time.Hour)
+	seed := []struct {
+		issue  types.Issue
+		labels []string
+	}{
+		{types.Issue{ID: "tq-1", Title: "Login page broken", Priority: 0, IssueType: types.TypeBug, Status: types.StatusOpen, Assignee: "alice"}, []string{"security", "frontend"}},
+		{types.Issue{ID: "tq-2", Title: "Sign in with SSO", Priority: 1, IssueType: types.TypeFeature, Status: types.StatusInProgress, DueAt: &due}, []string{"frontend", "milestone:v1.2"}},
+		{types.Issue{ID: "tq-3", Title: "Write docs", Description: "TODO: 100% coverage", Priority: 3, IssueType: types.TypeTask, Status: types.StatusOpen, Assignee: "bob"}, nil},
+		{types.Issue{ID: "tq-4", Title: "Old cleanup", Priority: 2, IssueType: types.TypeChore, Status: types.StatusClosed, ClosedAt: &now}, []string{"wip", "milestone:v2.0"}},
+		{types.Issue{ID: "tq-5", Title: "Login child", Priority: 2, IssueType: types.TypeTask, Status: types.StatusOpen}, nil},
+	}
+	for _, s := range []storage.Storage{sqlStore, memStore} {
+		if err := s.SetConfig(ctx, "issue_prefix", "tq"); err != nil {
+			t.Fatal(err)
+		}
+		for _, seedIssue := range seed {
+			issue := seedIssue.issue
+			if err := s.CreateIssue(ctx, &issue, "test"); err != nil {
+				t.Fatal(err)
+			}
+			for _, l := range seedIssue.labels {
+				if err := s.AddLabel(ctx, issue.ID, l, "test"); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		dep := &types.Dependency{IssueID: "tq-5", DependsOnID: "tq-1", Type: types.DepParentChild}
+		if err := s.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exprs := map[string][]string{
+		"status=open":                             {"tq-1", "tq-3", "tq-5"},
+		"title:login":                             {"tq-1", "tq-5"},
+		`title~"SIGN IN"`:                         {"tq-2"},
+		"desc:100%":                               {"tq-3"},
+		"assignee=none":                           {"tq-2", "tq-4", "tq-5"},
+		"priority<=1 or label:wip":                {"tq-1", "tq-2", "tq-4"},
+		"label=frontend and not label=security":   {"tq-2"},
+		"label!=frontend":                         {"tq-3", "tq-4", "tq-5"},
+		"label=none":                              {"tq-3", "tq-5"},
+		"label~front":                             {"tq-1", "tq-2"},
+		"parent=tq-1":                             {"tq-5"},
+		"parent=none status!=closed":              {"tq-1", "tq-2", "tq-3"},
+		"milestone=v1.2":                          {"tq-2"},
+		"milestone!=v1.2":                         {"tq-1", "tq-3", "tq-4", "tq-5"},
+		"milestone=none":                          {"tq-1", "tq-3", "tq-5"},
+		"milestone~V2":                            {"tq-4"},
+		"due<+3d":                                 {"tq-2"},
+		"due!=none or closed>-1d":                 {"tq-2", "tq-4"},
+		"created>-1h and type=bug":                {"tq-1"},
+		"(type=task or type=chore) and p>=3":      {"tq-3"},
+		"id=tq-2 or (assignee~o and status=open)": {"tq-2", "tq-3"},
+	}
+	for src, want := range exprs {
+		e, err := query.Parse(src, query.Options{})
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", src, err)
+		}
+		for name, s := range map[string]storage.Storage{"sqlite": sqlStore, "memory": memStore} {
+			issues, err := s.SearchIssues(ctx, "", types.IssueFilter{Query: e})
+			if err != nil {
+				t.Fatalf("%s: SearchIssues(%q): %v", name, src, err)
+			}
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.ID)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("%s: %q matched %v, want %v", name, src, got, want)
+			}
+		}
+	}
+}
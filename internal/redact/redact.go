@@ -0,0 +1,216 @@
+// Package redact scrubs personal data and secrets from issues before they
+// leave the machine, so a public export can be committed while the full
+// data stays in the local database.
+//
+// Rules are regular expressions: the built-in ones named in redact.rules
+// (emails and tokens by default) plus custom patterns from
+// redact.patterns.<name>. Every match is replaced with a [redacted:<rule>]
+// marker, and a redacted issue gets the Label label so readers of the
+// export know it isn't the full text.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Label marks an issue whose exported text was redacted.
+const Label = "redacted"
+
+// EmbargoLabel marks an issue withheld from an export because its security
+// advisory is under embargo (bd security set --embargo-until).
+const EmbargoLabel = "embargoed"
+
+// Config keys
+const (
+	KeyRules    = "redact.rules"
+	KeyPatterns = "redact.patterns"
+)
+
+// Builtin are the rules redact.rules can enable, by name.
+var Builtin = map[string][]string{
+	"emails": {`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`},
+	"tokens": {
+		`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+		`\b(?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{20,}`,
+		`\bgithub_pat_[A-Za-z0-9_]{20,}`,
+		`\bglpat-[A-Za-z0-9_-]{20,}`,
+		`\bxox[abprs]-[A-Za-z0-9-]{10,}`,
+		`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+		`\b(?:sk|pk|rk)_(?:live|test)_[A-Za-z0-9]{16,}`,
+		`\bsk-[A-Za-z0-9_-]{20,}`,
+		`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`,
+		`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{16,}=*`,
+	},
+	"ips": {`\b(?:\d{1,3}\.){3}\d{1,3}\b`},
+}
+
+type rule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// Redactor applies a set of rules and counts what it replaced.
+type Redactor struct {
+	rules []rule
+	// Matches counts replacements per rule name
+	Matches map[string]int
+}
+
+// New builds a Redactor from built-in rule names and named custom patterns.
+func New(builtin []string, patterns map[string]string) (*Redactor, error) {
+	r := &Redactor{Matches: make(map[string]int)}
+	for _, name := range builtin {
+		exprs, ok := Builtin[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown redaction rule %q (built-in: %s)", name, strings.Join(RuleNames(), ", "))
+		}
+		for _, expr := range exprs {
+			r.rules = append(r.rules, rule{name: name, re: regexp.MustCompile(expr)})
+		}
+	}
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %s: %w", name, err)
+		}
+		r.rules = append(r.rules, rule{name: name, re: re})
+	}
+	return r, nil
+}
+
+// FromConfig builds a Redactor from redact.rules and redact.patterns, plus
+// any extra patterns given on the command line.
+func FromConfig(extra []string) (*Redactor, error) {
+	var builtin []string
+	for _, v := range config.GetStringSlice(KeyRules) {
+		// A value set from an env var or flag arrives comma-separated
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				builtin = append(builtin, name)
+			}
+		}
+	}
+	patterns := config.GetStringMapByPrefix(KeyPatterns)
+	for i, expr := range extra {
+		patterns[fmt.Sprintf("pattern%d", i+1)] = expr
+	}
+	return New(builtin, patterns)
+}
+
+// RuleNames lists the built-in rule names.
+func RuleNames() []string {
+	names := make([]string, 0, len(Builtin))
+	for name := range Builtin {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// String returns s with every match replaced by its rule's marker.
+func (r *Redactor) String(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, rl := range r.rules {
+		marker := "[redacted:" + rl.name + "]"
+		s = rl.re.ReplaceAllStringFunc(s, func(match string) string {
+			if match == marker {
+				return match
+			}
+			r.Matches[rl.name]++
+			return marker
+		})
+	}
+	return s
+}
+
+// Issue returns a redacted copy of issue, and whether anything was
+// replaced. The original is left untouched.
+func (r *Redactor) Issue(issue *types.Issue) (*types.Issue, bool) {
+	before := r.total()
+	out := *issue
+	for _, field := range []*string{
+		&out.Title, &out.Description, &out.Design, &out.AcceptanceCriteria, &out.Notes,
+		&out.CloseReason, &out.Assignee, &out.Owner, &out.CreatedBy, &out.DeletedBy,
+		&out.DeleteReason, &out.Sender,
+	} {
+		*field = r.String(*field)
+	}
+	if issue.ExternalRef != nil {
+		ref := r.String(*issue.ExternalRef)
+		out.ExternalRef = &ref
+	}
+	if issue.Waiters != nil {
+		out.Waiters = make([]string, len(issue.Waiters))
+		for i, w := range issue.Waiters {
+			out.Waiters[i] = r.String(w)
+		}
+	}
+	if issue.Comments != nil {
+		out.Comments = make([]*types.Comment, len(issue.Comments))
+		for i, c := range issue.Comments {
+			comment := *c
+			comment.Author = r.String(c.Author)
+			comment.Text = r.String(c.Text)
+			out.Comments[i] = &comment
+		}
+	}
+
+	if r.total() == before {
+		return issue, false
+	}
+	hasLabel := false
+	for _, l := range issue.Labels {
+		hasLabel = hasLabel || l == Label
+	}
+	if !hasLabel {
+		out.Labels = append(append([]string(nil), issue.Labels...), Label)
+		sort.Strings(out.Labels)
+	}
+	return &out, true
+}
+
+// Embargo returns a stand-in for issue if its security advisory is under
+// embargo at now, and whether it did. The stand-in keeps only what places
+// the issue in the graph (ID, status, priority, type, timestamps and
+// dependencies) plus the embargo date, so nothing about the vulnerability
+// leaves before disclosure.
+func Embargo(issue *types.Issue, now time.Time) (*types.Issue, bool) {
+	if !issue.Security.Embargoed(now) {
+		return issue, false
+	}
+	return &types.Issue{
+		ID:           issue.ID,
+		Title:        "Embargoed security issue",
+		Status:       issue.Status,
+		Priority:     issue.Priority,
+		IssueType:    issue.IssueType,
+		CreatedAt:    issue.CreatedAt,
+		UpdatedAt:    issue.UpdatedAt,
+		ClosedAt:     issue.ClosedAt,
+		Labels:       []string{EmbargoLabel},
+		Dependencies: issue.Dependencies,
+		Security:     &types.Security{EmbargoUntil: issue.Security.EmbargoUntil},
+	}, true
+}
+
+func (r *Redactor) total() int {
+	n := 0
+	for _, c := range r.Matches {
+		n += c
+	}
+	return n
+}
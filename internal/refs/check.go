@@ -0,0 +1,238 @@
+package refs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/httpx"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Check statuses
+const (
+	StatusAlive   = "alive"
+	StatusDead    = "dead"
+	StatusUnknown = "unknown" // couldn't tell: not configured, auth, network or server error
+)
+
+// DeadLabel marks issues with at least one dead ref, following the
+// <dimension>:<value> label convention so list and query filters see it.
+const DeadLabel = "ref:dead"
+
+var (
+	githubRepoIssuePattern = regexp.MustCompile(`^([\w.-]+)/([\w.-]+)#(\d+)$`)
+	githubURLPattern       = regexp.MustCompile(`(?i)^https?://github\.com/([\w.-]+)/([\w.-]+)/(?:issues|pull)/(\d+)`)
+	githubShortIDPattern   = regexp.MustCompile(`^gh-(\d+)$`)
+	jiraBareKeyPattern     = regexp.MustCompile(`^[A-Z][A-Z0-9]+-\d+$`)
+)
+
+// Checker verifies that external references still resolve.
+type Checker struct {
+	Client      *http.Client
+	GitHubAPI   string // API base, https://api.github.com by default
+	GitHubRepo  string // owner/repo that gh-<n> refs point into
+	GitHubToken string // optional, raises the API rate limit
+	JiraURL     string // Jira base URL that bare issue keys point into
+	DocRoot     string // directory doc paths are relative to
+}
+
+// NewChecker returns a checker configured from the store's github.org,
+// github.repo and jira.url settings and the GITHUB_TOKEN environment
+// variable. Doc paths resolve against docRoot.
+func NewChecker(ctx context.Context, s storage.Storage, docRoot string) *Checker {
+	c := &Checker{
+		Client:      httpx.NewClient(15 * time.Second),
+		GitHubAPI:   "https://api.github.com",
+		GitHubToken: os.Getenv("GITHUB_TOKEN"),
+		DocRoot:     docRoot,
+	}
+	if s == nil {
+		return c
+	}
+	org, _ := s.GetConfig(ctx, "github.org")
+	repo, _ := s.GetConfig(ctx, "github.repo")
+	if org != "" && repo != "" && !strings.Contains(repo, "/") {
+		repo = org + "/" + repo
+	}
+	c.GitHubRepo = repo
+	c.JiraURL, _ = s.GetConfig(ctx, "jira.url")
+	return c
+}
+
+// Check verifies one reference.
+func (c *Checker) Check(ctx context.Context, ref types.Ref) types.RefCheck {
+	status, detail := c.check(ctx, ref)
+	return types.RefCheck{Value: ref.Value, Status: status, Detail: detail, CheckedAt: time.Now().UTC()}
+}
+
+func (c *Checker) check(ctx context.Context, ref types.Ref) (string, string) {
+	value := ref.Value
+	if m := githubURLPattern.FindStringSubmatch(value); m != nil {
+		return c.checkGitHubIssue(ctx, m[1]+"/"+m[2], m[3])
+	}
+	if m := githubRepoIssuePattern.FindStringSubmatch(value); m != nil {
+		return c.checkGitHubIssue(ctx, m[1]+"/"+m[2], m[3])
+	}
+	if m := githubShortIDPattern.FindStringSubmatch(value); m != nil {
+		if c.GitHubRepo == "" {
+			return StatusUnknown, "set github.org and github.repo to check gh-<n> refs"
+		}
+		return c.checkGitHubIssue(ctx, c.GitHubRepo, m[1])
+	}
+	if isURL(value) {
+		return c.checkURL(ctx, value, nil)
+	}
+	if jiraBareKeyPattern.MatchString(value) || ref.Type == types.RefJira {
+		if c.JiraURL == "" {
+			return StatusUnknown, "set jira.url to check Jira keys"
+		}
+		key := strings.TrimPrefix(value, "jira-")
+		return c.checkURL(ctx, strings.TrimSuffix(c.JiraURL, "/")+"/browse/"+key, nil)
+	}
+	if ref.Type == types.RefDoc {
+		path := value
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(c.DocRoot, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return StatusDead, "file not found"
+		}
+		return StatusAlive, ""
+	}
+	return StatusUnknown, "don't know how to check this ref"
+}
+
+// checkGitHubIssue asks the GitHub API whether an issue or pull request
+// exists (pull requests are issues to the API).
+func (c *Checker) checkGitHubIssue(ctx context.Context, repo, number string) (string, string) {
+	header := http.Header{"Accept": {"application/vnd.github+json"}}
+	if c.GitHubToken != "" {
+		header.Set("Authorization", "Bearer "+c.GitHubToken)
+	}
+	return c.checkURL(ctx, strings.TrimSuffix(c.GitHubAPI, "/")+"/repos/"+repo+"/issues/"+number, header)
+}
+
+// checkURL requests url with HEAD, retrying with GET for servers that
+// don't allow HEAD. 2xx is alive, 404 and 410 are dead, anything else is
+// unknown.
+func (c *Checker) checkURL(ctx context.Context, url string, header http.Header) (string, string) {
+	code, err := c.request(ctx, http.MethodHead, url, header)
+	if err == nil && (code == http.StatusMethodNotAllowed || code == http.StatusNotImplemented || code == http.StatusForbidden) {
+		code, err = c.request(ctx, http.MethodGet, url, header)
+	}
+	if err != nil {
+		return StatusUnknown, err.Error()
+	}
+	detail := fmt.Sprintf("HTTP %d", code)
+	switch {
+	case code >= 200 && code < 300:
+		return StatusAlive, ""
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return StatusDead, detail
+	}
+	return StatusUnknown, detail
+}
+
+func (c *Checker) request(ctx context.Context, method, url string, header http.Header) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("User-Agent", "beads-ref-check")
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func isURL(value string) bool {
+	lower := strings.ToLower(value)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// checkStore is implemented by stores that cache check results (SQLite).
+type checkStore interface {
+	SaveRefCheck(ctx context.Context, check types.RefCheck) error
+	GetRefChecks(ctx context.Context) (map[string]*types.RefCheck, error)
+}
+
+// Checks returns the cached check results by ref value, or nil for stores
+// that don't cache them.
+func Checks(ctx context.Context, s storage.Storage) (map[string]*types.RefCheck, error) {
+	cs, ok := s.(checkStore)
+	if !ok {
+		return nil, nil
+	}
+	return cs.GetRefChecks(ctx)
+}
+
+// IssueCheck is the outcome of checking one issue's refs.
+type IssueCheck struct {
+	IssueID string            `json:"issue_id"`
+	Checks  []*types.RefCheck `json:"checks"`
+	Dead    bool              `json:"dead"`
+}
+
+// CheckIssues checks every ref of issues (Refs must be populated), caches
+// the results and keeps the DeadLabel on exactly the issues with a dead
+// ref. Unknown results never add or remove the label. Returns the issues
+// that had refs to check.
+func CheckIssues(ctx context.Context, s storage.Storage, c *Checker, issues []*types.Issue, actor string) ([]IssueCheck, error) {
+	cs, _ := s.(checkStore)
+	var results []IssueCheck
+	for _, issue := range issues {
+		all := All(issue)
+		if len(all) == 0 {
+			continue
+		}
+		result := IssueCheck{IssueID: issue.ID}
+		anyUnknown := false
+		for _, ref := range all {
+			check := c.Check(ctx, *ref)
+			if cs != nil {
+				if err := cs.SaveRefCheck(ctx, check); err != nil {
+					return results, err
+				}
+			}
+			result.Checks = append(result.Checks, &check)
+			switch check.Status {
+			case StatusDead:
+				result.Dead = true
+			case StatusUnknown:
+				anyUnknown = true
+			}
+		}
+
+		labels, err := s.GetLabels(ctx, issue.ID)
+		if err != nil {
+			return results, fmt.Errorf("failed to get labels for %s: %w", issue.ID, err)
+		}
+		hasLabel := false
+		for _, l := range labels {
+			hasLabel = hasLabel || l == DeadLabel
+		}
+		switch {
+		case result.Dead && !hasLabel:
+			err = s.AddLabel(ctx, issue.ID, DeadLabel, actor)
+		case !result.Dead && !anyUnknown && hasLabel:
+			err = s.RemoveLabel(ctx, issue.ID, DeadLabel, actor)
+		}
+		if err != nil {
+			return results, fmt.Errorf("failed to update %s on %s: %w", DeadLabel, issue.ID, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,105 @@
+package refs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func newTestChecker(t *testing.T) (*Checker, string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/issues/1", "/ok", "/browse/PROJ-1":
+			w.WriteHeader(http.StatusOK)
+		case "/head-not-allowed":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "design.md"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return &Checker{Client: srv.Client(), GitHubAPI: srv.URL, GitHubRepo: "o/r", JiraURL: srv.URL, DocRoot: root}, srv.URL
+}
+
+func TestCheckerStatuses(t *testing.T) {
+	ctx := context.Background()
+	c, base := newTestChecker(t)
+	tests := []struct {
+		ref  types.Ref
+		want string
+	}{
+		{types.Ref{Type: types.RefGitHub, Value: "gh-1"}, StatusAlive},
+		{types.Ref{Type: types.RefGitHub, Value: "o/r#2"}, StatusDead},
+		{types.Ref{Type: types.RefJira, Value: "PROJ-1"}, StatusAlive},
+		{types.Ref{Type: types.RefJira, Value: "PROJ-2"}, StatusDead},
+		{types.Ref{Type: types.RefURL, Value: base + "/ok"}, StatusAlive},
+		{types.Ref{Type: types.RefURL, Value: base + "/head-not-allowed"}, StatusAlive},
+		{types.Ref{Type: types.RefURL, Value: base + "/gone"}, StatusDead},
+		{types.Ref{Type: types.RefURL, Value: base + "/broken"}, StatusUnknown},
+		{types.Ref{Type: types.RefDoc, Value: "design.md"}, StatusAlive},
+		{types.Ref{Type: types.RefDoc, Value: "missing.md"}, StatusDead},
+	}
+	for _, tt := range tests {
+		if got := c.Check(ctx, tt.ref); got.Status != tt.want {
+			t.Errorf("Check(%s) = %s (%s), want %s", tt.ref.Value, got.Status, got.Detail, tt.want)
+		}
+	}
+
+	c.GitHubRepo = ""
+	if got := c.Check(ctx, types.Ref{Type: types.RefGitHub, Value: "gh-1"}); got.Status != StatusUnknown {
+		t.Errorf("gh-<n> without github.repo = %s, want unknown", got.Status)
+	}
+}
+
+func TestCheckIssuesMaintainsDeadLabel(t *testing.T) {
+	ctx := context.Background()
+	c, base := newTestChecker(t)
+	s := memory.New("")
+	issue := &types.Issue{ID: "bd-1", Title: "Links", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask,
+		Refs: []*types.Ref{{Type: types.RefURL, Value: base + "/gone"}}}
+	if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := CheckIssues(ctx, s, c, []*types.Issue{issue}, "tester")
+	if err != nil || len(results) != 1 || !results[0].Dead {
+		t.Fatalf("CheckIssues = %+v, %v; want one dead issue", results, err)
+	}
+	if labels, _ := s.GetLabels(ctx, "bd-1"); len(labels) != 1 || labels[0] != DeadLabel {
+		t.Errorf("labels = %v, want [%s]", labels, DeadLabel)
+	}
+
+	// An unknown result leaves the label alone; an all-alive one removes it
+	issue.Refs = []*types.Ref{{Type: types.RefURL, Value: base + "/broken"}}
+	if _, err := CheckIssues(ctx, s, c, []*types.Issue{issue}, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if labels, _ := s.GetLabels(ctx, "bd-1"); len(labels) != 1 {
+		t.Errorf("unknown check changed labels to %v", labels)
+	}
+	issue.Refs = []*types.Ref{{Type: types.RefURL, Value: base + "/ok"}}
+	if _, err := CheckIssues(ctx, s, c, []*types.Issue{issue}, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if labels, _ := s.GetLabels(ctx, "bd-1"); len(labels) != 0 {
+		t.Errorf("labels = %v, want none after refs are alive again", labels)
+	}
+}
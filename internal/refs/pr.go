@@ -0,0 +1,281 @@
+package refs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var (
+	githubPRPattern      = regexp.MustCompile(`(?i)^(?:https?://)?(?:www\.)?github\.com/([\w.-]+)/([\w.-]+)(?:/pull/|#)(\d+)(?:[/?#].*)?$`)
+	githubPRShortPattern = regexp.MustCompile(`^([\w.-]+)/([\w.-]+)#(\d+)$`)
+	githubPRNumber       = regexp.MustCompile(`^#?(\d+)$`)
+	githubPRURLPattern   = regexp.MustCompile(`^https://github\.com/([\w.-]+)/([\w.-]+)/pull/(\d+)$`)
+	githubRemotePattern  = regexp.MustCompile(`(?i)github\.com[:/]([\w.-]+)/([\w.-]+?)(?:\.git)?/?$`)
+)
+
+// PRURL is the canonical URL of pull request number n in repo (owner/repo).
+// Linked pull requests are stored as github refs with this value.
+func PRURL(repo string, n string) string {
+	return "https://github.com/" + repo + "/pull/" + n
+}
+
+// NormalizePR turns a pull request reference into its canonical URL. It
+// accepts https://github.com/owner/repo/pull/12, github.com/owner/repo#12,
+// owner/repo#12, and #12 or 12 in defaultRepo.
+func NormalizePR(value, defaultRepo string) (string, error) {
+	value = strings.TrimSpace(value)
+	if m := githubPRPattern.FindStringSubmatch(value); m != nil {
+		return PRURL(m[1]+"/"+m[2], m[3]), nil
+	}
+	if m := githubPRShortPattern.FindStringSubmatch(value); m != nil {
+		return PRURL(m[1]+"/"+m[2], m[3]), nil
+	}
+	if m := githubPRNumber.FindStringSubmatch(value); m != nil {
+		if defaultRepo == "" {
+			return "", fmt.Errorf("%q needs a repository: use owner/repo#%s, or set github.org and github.repo", value, m[1])
+		}
+		return PRURL(defaultRepo, m[1]), nil
+	}
+	return "", fmt.Errorf("not a GitHub pull request: %q (use owner/repo#12 or a pull request URL)", value)
+}
+
+// RepoFromRemote returns owner/repo for a GitHub remote URL (https, ssh or
+// scp-style), or "" for other remotes.
+func RepoFromRemote(remote string) string {
+	if m := githubRemotePattern.FindStringSubmatch(strings.TrimSpace(remote)); m != nil {
+		return m[1] + "/" + m[2]
+	}
+	return ""
+}
+
+// PullRequests returns the canonical URLs of the pull requests linked to
+// issue, in ref order. issue.Refs must already be populated.
+func PullRequests(issue *types.Issue) []string {
+	var prs []string
+	for _, ref := range All(issue) {
+		if ref.Type == types.RefGitHub && githubPRURLPattern.MatchString(ref.Value) {
+			prs = append(prs, ref.Value)
+		}
+	}
+	return prs
+}
+
+// FetchPR fetches a pull request's state, review status and CI status from
+// the GitHub API. Failures are reported as PRStateUnknown with a detail.
+func (c *Checker) FetchPR(ctx context.Context, prURL string) types.PRStatus {
+	status := types.PRStatus{URL: prURL, State: types.PRStateUnknown, CheckedAt: time.Now().UTC()}
+	m := githubPRURLPattern.FindStringSubmatch(prURL)
+	if m == nil {
+		status.Detail = "not a pull request URL"
+		return status
+	}
+	api := strings.TrimSuffix(c.GitHubAPI, "/") + "/repos/" + m[1] + "/" + m[2]
+
+	var pr struct {
+		Title  string `json:"title"`
+		State  string `json:"state"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		Head   struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := c.getJSON(ctx, api+"/pulls/"+m[3], &pr); err != nil {
+		status.Detail = err.Error()
+		return status
+	}
+	status.Title = pr.Title
+	switch {
+	case pr.Merged:
+		status.State = types.PRStateMerged
+	case pr.State == "closed":
+		status.State = types.PRStateClosed
+	case pr.Draft:
+		status.State = types.PRStateDraft
+	default:
+		status.State = types.PRStateOpen
+	}
+
+	var reviews []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State string `json:"state"`
+	}
+	if err := c.getJSON(ctx, api+"/pulls/"+m[3]+"/reviews?per_page=100", &reviews); err == nil {
+		latest := make(map[string]string) // Reviewer -> last deciding review
+		for _, r := range reviews {
+			switch r.State {
+			case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+				latest[r.User.Login] = r.State
+			}
+		}
+		status.Review = reviewStatus(latest)
+	}
+
+	if pr.Head.SHA != "" {
+		status.CI = c.fetchCI(ctx, api+"/commits/"+pr.Head.SHA)
+	}
+	return status
+}
+
+// reviewStatus summarizes each reviewer's last deciding review: any
+// outstanding change request wins over approvals.
+func reviewStatus(latest map[string]string) string {
+	approved := false
+	for _, state := range latest {
+		switch state {
+		case "CHANGES_REQUESTED":
+			return types.PRReviewChangesRequested
+		case "APPROVED":
+			approved = true
+		}
+	}
+	if approved {
+		return types.PRReviewApproved
+	}
+	return types.PRReviewPending
+}
+
+// fetchCI combines the check runs and commit statuses of a commit.
+func (c *Checker) fetchCI(ctx context.Context, commitAPI string) string {
+	var results []string
+	var runs struct {
+		CheckRuns []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := c.getJSON(ctx, commitAPI+"/check-runs?per_page=100", &runs); err == nil {
+		for _, run := range runs.CheckRuns {
+			switch {
+			case run.Status != "completed":
+				results = append(results, types.PRCIPending)
+			case run.Conclusion == "failure" || run.Conclusion == "timed_out" || run.Conclusion == "cancelled" || run.Conclusion == "action_required":
+				results = append(results, types.PRCIFailure)
+			default:
+				results = append(results, types.PRCISuccess)
+			}
+		}
+	}
+	var combined struct {
+		State      string `json:"state"`
+		TotalCount int    `json:"total_count"`
+	}
+	if err := c.getJSON(ctx, commitAPI+"/status", &combined); err == nil && combined.TotalCount > 0 {
+		switch combined.State {
+		case "failure", "error":
+			results = append(results, types.PRCIFailure)
+		case "pending":
+			results = append(results, types.PRCIPending)
+		default:
+			results = append(results, types.PRCISuccess)
+		}
+	}
+	return ciStatus(results)
+}
+
+// ciStatus reduces individual check results: any failure fails, then any
+// pending check keeps the whole pending.
+func ciStatus(results []string) string {
+	if len(results) == 0 {
+		return types.PRCINone
+	}
+	status := types.PRCISuccess
+	for _, r := range results {
+		switch r {
+		case types.PRCIFailure:
+			return types.PRCIFailure
+		case types.PRCIPending:
+			status = types.PRCIPending
+		}
+	}
+	return status
+}
+
+// FindPR returns the URL of the newest pull request from branch in repo
+// (owner/repo), or "" if there is none.
+func (c *Checker) FindPR(ctx context.Context, repo, branch string) (string, error) {
+	owner, _, _ := strings.Cut(repo, "/")
+	query := url.Values{"head": {owner + ":" + branch}, "state": {"all"}, "per_page": {"1"}}
+	var prs []struct {
+		Number int `json:"number"`
+	}
+	if err := c.getJSON(ctx, strings.TrimSuffix(c.GitHubAPI, "/")+"/repos/"+repo+"/pulls?"+query.Encode(), &prs); err != nil {
+		return "", err
+	}
+	if len(prs) == 0 {
+		return "", nil
+	}
+	return PRURL(repo, fmt.Sprint(prs[0].Number)), nil
+}
+
+// getJSON GETs a GitHub API URL and decodes the response into v.
+func (c *Checker) getJSON(ctx context.Context, apiURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "beads-pr-status")
+	if c.GitHubToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.GitHubToken)
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// prStore is implemented by stores that cache pull request statuses (SQLite).
+type prStore interface {
+	SavePRStatus(ctx context.Context, pr types.PRStatus) error
+	GetPRStatuses(ctx context.Context) (map[string]*types.PRStatus, error)
+}
+
+// PRStatuses returns the cached pull request statuses by URL, or nil for
+// stores that don't cache them.
+func PRStatuses(ctx context.Context, s storage.Storage) (map[string]*types.PRStatus, error) {
+	ps, ok := s.(prStore)
+	if !ok {
+		return nil, nil
+	}
+	return ps.GetPRStatuses(ctx)
+}
+
+// RefreshPRs fetches and caches the status of each pull request URL, in
+// sorted order.
+func RefreshPRs(ctx context.Context, s storage.Storage, c *Checker, prURLs []string) ([]types.PRStatus, error) {
+	ps, _ := s.(prStore)
+	sorted := append([]string(nil), prURLs...)
+	sort.Strings(sorted)
+	var statuses []types.PRStatus
+	for i, u := range sorted {
+		if i > 0 && u == sorted[i-1] {
+			continue
+		}
+		status := c.FetchPR(ctx, u)
+		if ps != nil {
+			if err := ps.SavePRStatus(ctx, status); err != nil {
+				return statuses, err
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
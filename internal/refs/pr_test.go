@@ -0,0 +1,111 @@
+package refs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestNormalizePR(t *testing.T) {
+	tests := []struct {
+		value, repo, want string
+	}{
+		{"https://github.com/o/r/pull/12", "", "https://github.com/o/r/pull/12"},
+		{"https://github.com/o/r/pull/12/files", "", "https://github.com/o/r/pull/12"},
+		{"github.com/org/repo#456", "", "https://github.com/org/repo/pull/456"},
+		{"org/repo#456", "", "https://github.com/org/repo/pull/456"},
+		{"#7", "o/r", "https://github.com/o/r/pull/7"},
+		{"7", "o/r", "https://github.com/o/r/pull/7"},
+		{"7", "", ""},
+		{"PROJ-1", "o/r", ""},
+	}
+	for _, tt := range tests {
+		got, err := NormalizePR(tt.value, tt.repo)
+		if got != tt.want || (err != nil) != (tt.want == "") {
+			t.Errorf("NormalizePR(%q, %q) = %q, %v; want %q", tt.value, tt.repo, got, err, tt.want)
+		}
+	}
+}
+
+func TestRepoFromRemote(t *testing.T) {
+	for remote, want := range map[string]string{
+		"git@github.com:o/r.git":          "o/r",
+		"https://github.com/o/r.git":      "o/r",
+		"https://github.com/o/r":          "o/r",
+		"ssh://git@github.com/o/my.repo/": "o/my.repo",
+		"https://gitlab.com/o/r.git":      "",
+		"/srv/git/r.git":                  "",
+	} {
+		if got := RepoFromRemote(remote); got != want {
+			t.Errorf("RepoFromRemote(%q) = %q, want %q", remote, got, want)
+		}
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	primary := "https://github.com/o/r/pull/1"
+	issue := &types.Issue{ExternalRef: &primary, Refs: []*types.Ref{
+		{Type: types.RefGitHub, Value: primary},
+		{Type: types.RefGitHub, Value: "o/r#2"},
+		{Type: types.RefGitHub, Value: "https://github.com/o/r/pull/3"},
+		{Type: types.RefURL, Value: "https://example.com/pull/4"},
+	}}
+	got := PullRequests(issue)
+	if len(got) != 2 || got[0] != primary || got[1] != "https://github.com/o/r/pull/3" {
+		t.Errorf("PullRequests = %v", got)
+	}
+}
+
+func TestFetchPR(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/pulls/1":
+			fmt.Fprint(w, `{"title":"Fix it","state":"open","draft":false,"merged":false,"head":{"sha":"abc"}}`)
+		case "/repos/o/r/pulls/1/reviews":
+			fmt.Fprint(w, `[{"user":{"login":"a"},"state":"CHANGES_REQUESTED"},{"user":{"login":"b"},"state":"APPROVED"},{"user":{"login":"a"},"state":"APPROVED"},{"user":{"login":"c"},"state":"COMMENTED"}]`)
+		case "/repos/o/r/commits/abc/check-runs":
+			fmt.Fprint(w, `{"check_runs":[{"status":"completed","conclusion":"success"},{"status":"in_progress"}]}`)
+		case "/repos/o/r/commits/abc/status":
+			fmt.Fprint(w, `{"state":"pending","total_count":0}`)
+		case "/repos/o/r/pulls/2":
+			fmt.Fprint(w, `{"title":"Done","state":"closed","merged":true,"head":{"sha":"def"}}`)
+		case "/repos/o/r/commits/def/status":
+			fmt.Fprint(w, `{"state":"failure","total_count":1}`)
+		case "/repos/o/r/pulls":
+			if r.URL.Query().Get("head") == "o:feature" {
+				fmt.Fprint(w, `[{"number":9}]`)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := &Checker{Client: srv.Client(), GitHubAPI: srv.URL}
+	ctx := context.Background()
+
+	pr := c.FetchPR(ctx, "https://github.com/o/r/pull/1")
+	if pr.Title != "Fix it" || pr.State != types.PRStateOpen || pr.Review != types.PRReviewApproved || pr.CI != types.PRCIPending {
+		t.Errorf("pull 1 = %+v", pr)
+	}
+	pr = c.FetchPR(ctx, "https://github.com/o/r/pull/2")
+	if pr.State != types.PRStateMerged || pr.Review != "" || pr.CI != types.PRCIFailure {
+		t.Errorf("pull 2 = %+v", pr)
+	}
+	pr = c.FetchPR(ctx, "https://github.com/o/r/pull/3")
+	if pr.State != types.PRStateUnknown || pr.Detail != "HTTP 404" {
+		t.Errorf("pull 3 = %+v", pr)
+	}
+
+	if got, err := c.FindPR(ctx, "o/r", "feature"); err != nil || got != "https://github.com/o/r/pull/9" {
+		t.Errorf("FindPR(feature) = %q, %v", got, err)
+	}
+	if got, err := c.FindPR(ctx, "o/r", "other"); err != nil || got != "" {
+		t.Errorf("FindPR(other) = %q, %v", got, err)
+	}
+}
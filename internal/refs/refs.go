@@ -0,0 +1,111 @@
+// Package refs manages an issue's typed external references: links into
+// GitHub, Jira, the web or documents, kept alongside the primary
+// external_ref and synced through the JSONL "refs" field.
+package refs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ErrUnsupported is returned for stores that don't keep refs.
+var ErrUnsupported = errors.New("external refs are not supported by this storage backend")
+
+// Store is implemented by stores that keep refs (SQLite).
+type Store interface {
+	AddRef(ctx context.Context, issueID string, ref types.Ref, actor string) error
+	RemoveRef(ctx context.Context, issueID, value, actor string) (bool, error)
+	SetRefs(ctx context.Context, issueID string, refs []*types.Ref, actor string) error
+	GetRefs(ctx context.Context, issueID string) ([]*types.Ref, error)
+	GetRefsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Ref, error)
+}
+
+// For returns s as a ref store.
+func For(s storage.Storage) (Store, error) {
+	rs, ok := s.(Store)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return rs, nil
+}
+
+var (
+	githubShortPattern = regexp.MustCompile(`^(gh-\d+|[\w.-]+/[\w.-]+#\d+)$`)
+	jiraKeyPattern     = regexp.MustCompile(`^([A-Z][A-Z0-9]+-\d+|jira-[\w-]+)$`)
+)
+
+// Infer guesses a ref's type from its value: gh-12, owner/repo#12 and
+// github.com links are github; PROJ-123 keys and /browse/ links are jira;
+// other links are url; anything else (a path, a doc name) is doc.
+func Infer(value string) types.RefType {
+	lower := strings.ToLower(value)
+	isURL := strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+	switch {
+	case githubShortPattern.MatchString(value), isURL && strings.Contains(lower, "github.com/"):
+		return types.RefGitHub
+	case jiraKeyPattern.MatchString(value), isURL && (strings.Contains(lower, "/browse/") || strings.Contains(lower, "atlassian.net/")):
+		return types.RefJira
+	case isURL:
+		return types.RefURL
+	}
+	return types.RefDoc
+}
+
+// New builds a ref, inferring the type when refType is empty.
+func New(refType, value string) (types.Ref, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return types.Ref{}, fmt.Errorf("ref value cannot be empty")
+	}
+	if refType == "" {
+		return types.Ref{Type: Infer(value), Value: value}, nil
+	}
+	t := types.RefType(strings.ToLower(refType))
+	if !t.IsValid() {
+		return types.Ref{}, fmt.Errorf("invalid ref type %q (use github, jira, url or doc)", refType)
+	}
+	return types.Ref{Type: t, Value: value}, nil
+}
+
+// All returns every ref on issue, its primary external_ref first. issue.Refs
+// must already be populated.
+func All(issue *types.Issue) []*types.Ref {
+	var all []*types.Ref
+	if issue.ExternalRef != nil && *issue.ExternalRef != "" {
+		all = append(all, &types.Ref{Type: Infer(*issue.ExternalRef), Value: *issue.ExternalRef})
+	}
+	for _, ref := range issue.Refs {
+		if len(all) > 0 && ref.Value == all[0].Value {
+			continue
+		}
+		all = append(all, ref)
+	}
+	return all
+}
+
+// Populate fills in the Refs of issues, as exports and show do. Stores that
+// don't keep refs leave issues unchanged.
+func Populate(ctx context.Context, s storage.Storage, issues []*types.Issue) error {
+	rs, err := For(s)
+	if err != nil || len(issues) == 0 {
+		return nil
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	byIssue, err := rs.GetRefsForIssues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get refs: %w", err)
+	}
+	for _, issue := range issues {
+		issue.Refs = byIssue[issue.ID]
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package refs
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestInfer(t *testing.T) {
+	tests := map[string]types.RefType{
+		"gh-12":                           types.RefGitHub,
+		"steveyegge/beads#12":             types.RefGitHub,
+		"https://github.com/o/r/issues/1": types.RefGitHub,
+		"PROJ-123":                        types.RefJira,
+		"jira-ABC":                        types.RefJira,
+		"https://acme.atlassian.net/browse/PROJ-1": types.RefJira,
+		"https://example.com/rfc":                  types.RefURL,
+		"docs/design.md":                           types.RefDoc,
+	}
+	for value, want := range tests {
+		if got := Infer(value); got != want {
+			t.Errorf("Infer(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestNewValidatesType(t *testing.T) {
+	if _, err := New("wiki", "x"); err == nil {
+		t.Error("expected error for unknown type")
+	}
+	if _, err := New("", "  "); err == nil {
+		t.Error("expected error for empty value")
+	}
+	ref, err := New("DOC", "https://example.com/spec")
+	if err != nil || ref.Type != types.RefDoc {
+		t.Errorf("New(DOC) = %v, %v; want an explicit doc ref", ref, err)
+	}
+}
+
+func TestAllListsPrimaryFirstWithoutDuplicates(t *testing.T) {
+	primary := "gh-9"
+	issue := &types.Issue{
+		ExternalRef: &primary,
+		Refs:        []*types.Ref{{Type: types.RefGitHub, Value: "gh-9"}, {Type: types.RefJira, Value: "PROJ-1"}},
+	}
+	all := All(issue)
+	if len(all) != 2 || all[0].Value != "gh-9" || all[1].Value != "PROJ-1" {
+		t.Errorf("All = %v, want gh-9 then PROJ-1", all)
+	}
+}
@@ -0,0 +1,214 @@
+// Package repro holds the environment and reproduction metadata of bug
+// reports (OS, version, steps, expected and actual behavior) set with
+// bd repro. The metadata travels with issues through the JSONL like
+// labels and refs.
+package repro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ErrUnsupported is returned for storage backends without repro metadata.
+var ErrUnsupported = errors.New("repro metadata requires the SQLite backend")
+
+// Store is the storage interface for repro metadata.
+type Store interface {
+	SetRepro(ctx context.Context, issueID string, repro *types.Repro, actor string) error
+	GetRepro(ctx context.Context, issueID string) (*types.Repro, error)
+	GetReproForIssues(ctx context.Context, issueIDs []string) (map[string]*types.Repro, error)
+	SearchReproIssueIDs(ctx context.Context, text string) ([]string, error)
+}
+
+// For returns s as a repro store.
+func For(s storage.Storage) (Store, error) {
+	rs, ok := s.(Store)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return rs, nil
+}
+
+// Populate fills in the Repro of issues, as exports and show do. Stores
+// that don't keep repros leave issues unchanged.
+func Populate(ctx context.Context, s storage.Storage, issues []*types.Issue) error {
+	rs, err := For(s)
+	if err != nil || len(issues) == 0 {
+		return nil
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	byIssue, err := rs.GetReproForIssues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get repro metadata: %w", err)
+	}
+	for _, issue := range issues {
+		issue.Repro = byIssue[issue.ID]
+	}
+	return nil
+}
+
+// Field limits
+const (
+	MaxLineField = 200   // OS and version
+	MaxTextField = 10000 // Steps, expected and actual
+)
+
+// Validate checks a repro: OS and version are single short lines, and
+// expected or actual behavior needs the steps that lead to it.
+func Validate(r *types.Repro) error {
+	if r.IsEmpty() {
+		return nil
+	}
+	for _, f := range []struct{ name, value string }{{"os", r.OS}, {"version", r.Version}} {
+		if strings.ContainsAny(f.value, "\r\n") {
+			return fmt.Errorf("%s must be a single line", f.name)
+		}
+		if len(f.value) > MaxLineField {
+			return fmt.Errorf("%s is longer than %d characters", f.name, MaxLineField)
+		}
+	}
+	for _, f := range []struct{ name, value string }{{"steps", r.Steps}, {"expected", r.Expected}, {"actual", r.Actual}} {
+		if len(f.value) > MaxTextField {
+			return fmt.Errorf("%s is longer than %d characters", f.name, MaxTextField)
+		}
+	}
+	if r.Steps == "" && (r.Expected != "" || r.Actual != "") {
+		return errors.New("steps are required with expected or actual behavior")
+	}
+	return nil
+}
+
+// Normalize trims every field.
+func Normalize(r *types.Repro) {
+	r.OS = strings.TrimSpace(r.OS)
+	r.Version = strings.TrimSpace(r.Version)
+	r.Steps = strings.TrimSpace(r.Steps)
+	r.Expected = strings.TrimSpace(r.Expected)
+	r.Actual = strings.TrimSpace(r.Actual)
+}
+
+// Template headings, in order, and the field each fills
+var sections = []struct {
+	heading string
+	field   func(*types.Repro) *string
+}{
+	{"OS", func(r *types.Repro) *string { return &r.OS }},
+	{"Version", func(r *types.Repro) *string { return &r.Version }},
+	{"Steps to Reproduce", func(r *types.Repro) *string { return &r.Steps }},
+	{"Expected", func(r *types.Repro) *string { return &r.Expected }},
+	{"Actual", func(r *types.Repro) *string { return &r.Actual }},
+}
+
+// aliases maps other heading names people write to template headings.
+var aliases = map[string]string{
+	"environment":        "os",
+	"platform":           "os",
+	"steps":              "steps to reproduce",
+	"repro":              "steps to reproduce",
+	"expected behavior":  "expected",
+	"expected behaviour": "expected",
+	"actual behavior":    "actual",
+	"actual behaviour":   "actual",
+}
+
+var htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// Template renders a repro as the Markdown document bd repro edit opens in
+// the editor: one "## " section per field.
+func Template(issueID string, r *types.Repro) string {
+	if r == nil {
+		r = &types.Repro{}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!-- Repro for %s. Fill in any sections; leave the rest empty.\n", issueID)
+	b.WriteString("     OS and Version are one line each. Expected and Actual need Steps. -->\n")
+	for _, s := range sections {
+		fmt.Fprintf(&b, "\n## %s\n", s.heading)
+		if v := *s.field(r); v != "" {
+			b.WriteString(v + "\n")
+		}
+	}
+	return b.String()
+}
+
+// ParseTemplate reads a document in the Template format back into a repro.
+// Common variants of the headings (Environment, Expected behavior, ...) are
+// accepted; any other heading is an error.
+func ParseTemplate(doc string) (*types.Repro, error) {
+	doc = htmlComment.ReplaceAllString(doc, "")
+	r := &types.Repro{}
+	var current *string
+	var text []string
+	flush := func() {
+		if current != nil {
+			*current = strings.TrimSpace(strings.Join(text, "\n"))
+		}
+		text = nil
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		if heading, ok := strings.CutPrefix(line, "## "); ok {
+			flush()
+			current = nil
+			name := strings.ToLower(strings.TrimSpace(heading))
+			if alias, ok := aliases[name]; ok {
+				name = alias
+			}
+			for _, s := range sections {
+				if strings.ToLower(s.heading) == name {
+					current = s.field(r)
+				}
+			}
+			if current == nil {
+				return nil, fmt.Errorf("unknown section %q (use OS, Version, Steps to Reproduce, Expected or Actual)", strings.TrimSpace(heading))
+			}
+			continue
+		}
+		if current == nil {
+			if strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("text before the first section: %q", strings.TrimSpace(line))
+			}
+			continue
+		}
+		text = append(text, line)
+	}
+	flush()
+	return r, nil
+}
+
+// Markdown renders a repro for bd show: the environment on one line, then
+// the steps and behavior.
+func Markdown(r *types.Repro) string {
+	if r.IsEmpty() {
+		return ""
+	}
+	var parts []string
+	var env []string
+	if r.OS != "" {
+		env = append(env, "**OS:** "+r.OS)
+	}
+	if r.Version != "" {
+		env = append(env, "**Version:** "+r.Version)
+	}
+	if len(env) > 0 {
+		parts = append(parts, strings.Join(env, " · "))
+	}
+	if r.Steps != "" {
+		parts = append(parts, "**Steps to reproduce**\n\n"+r.Steps)
+	}
+	if r.Expected != "" {
+		parts = append(parts, "**Expected:** "+r.Expected)
+	}
+	if r.Actual != "" {
+		parts = append(parts, "**Actual:** "+r.Actual)
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,68 @@
+package repro
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		repro   types.Repro
+		wantErr string
+	}{
+		{types.Repro{}, ""},
+		{types.Repro{OS: "Linux", Version: "1.2.3"}, ""},
+		{types.Repro{Steps: "1. Run it", Actual: "Crash"}, ""},
+		{types.Repro{OS: "Linux\nUbuntu"}, "os must be a single line"},
+		{types.Repro{Version: strings.Repeat("1", MaxLineField+1)}, "version is longer"},
+		{types.Repro{Expected: "Works"}, "steps are required"},
+	}
+	for _, tt := range tests {
+		err := Validate(&tt.repro)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("Validate(%+v) = %v", tt.repro, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Validate(%+v) = %v, want %q", tt.repro, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTemplateRoundTrip(t *testing.T) {
+	want := &types.Repro{OS: "macOS 15.1", Steps: "1. Open\n\n2. Save", Actual: "Segfault"}
+	doc := Template("bd-1", want)
+	if !strings.Contains(doc, "## Steps to Reproduce\n1. Open") {
+		t.Errorf("Template = %q", doc)
+	}
+	got, err := ParseTemplate(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("ParseTemplate(Template) = %+v, want %+v", got, want)
+	}
+
+	got, err = ParseTemplate("## Environment\nWindows 11\n\n## Expected behavior\nSaved\n")
+	if err != nil || got.OS != "Windows 11" || got.Expected != "Saved" {
+		t.Errorf("ParseTemplate with aliases = %+v, %v", got, err)
+	}
+	if _, err := ParseTemplate("## Workaround\nnone\n"); err == nil {
+		t.Errorf("ParseTemplate accepted an unknown section")
+	}
+	if _, err := ParseTemplate("stray text\n## OS\nLinux\n"); err == nil {
+		t.Errorf("ParseTemplate accepted text before the first section")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	if Markdown(nil) != "" {
+		t.Errorf("Markdown(nil) not empty")
+	}
+	md := Markdown(&types.Repro{OS: "Linux", Version: "2.0", Steps: "1. Run", Actual: "Hangs"})
+	want := "**OS:** Linux · **Version:** 2.0\n\n**Steps to reproduce**\n\n1. Run\n\n**Actual:** Hangs"
+	if md != want {
+		t.Errorf("Markdown = %q, want %q", md, want)
+	}
+}
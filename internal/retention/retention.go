@@ -0,0 +1,159 @@
+// Package retention purges old data according to the project's retention
+// policy, set in .beads/config.yaml:
+//
+//	retention:
+//	  closed-comments: 1y   # comments of issues closed longer ago
+//	  events: 2y            # audit trail events (bd history)
+//	  interactions: 2y      # .beads/interactions.jsonl entries
+//
+// Ages use the compact duration units h, d, w, m (months) and y. The daemon
+// applies the policy on the retention.schedule cron (daily by default);
+// bd retention preview shows what it would purge.
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/audit"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/timeparsing"
+)
+
+// Config keys
+const (
+	KeyClosedComments = "retention.closed-comments"
+	KeyEvents         = "retention.events"
+	KeyInteractions   = "retention.interactions"
+	KeySchedule       = "retention.schedule"
+)
+
+// JobID names the scheduled job that applies the policy.
+const JobID = "retention"
+
+// ErrUnsupported is returned for storage backends that can't purge.
+var ErrUnsupported = errors.New("retention requires the SQLite backend")
+
+// Policy is how long each kind of data is kept. Empty keeps it forever.
+type Policy struct {
+	ClosedComments string `json:"closed_comments,omitempty"`
+	Events         string `json:"events,omitempty"`
+	Interactions   string `json:"interactions,omitempty"`
+}
+
+// PolicyFromConfig returns the configured policy.
+func PolicyFromConfig() (Policy, error) {
+	p := Policy{
+		ClosedComments: strings.TrimSpace(config.GetString(KeyClosedComments)),
+		Events:         strings.TrimSpace(config.GetString(KeyEvents)),
+		Interactions:   strings.TrimSpace(config.GetString(KeyInteractions)),
+	}
+	return p, p.Validate()
+}
+
+// Validate checks that every age is a compact duration.
+func (p Policy) Validate() error {
+	for key, age := range map[string]string{
+		KeyClosedComments: p.ClosedComments,
+		KeyEvents:         p.Events,
+		KeyInteractions:   p.Interactions,
+	} {
+		if err := ValidateAge(age); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Empty reports whether the policy keeps everything.
+func (p Policy) Empty() bool {
+	return p.ClosedComments == "" && p.Events == "" && p.Interactions == ""
+}
+
+// ValidateAge checks an age such as "90d" or "1y". Empty is valid.
+func ValidateAge(age string) error {
+	if age == "" {
+		return nil
+	}
+	if strings.HasPrefix(age, "+") || strings.HasPrefix(age, "-") || !timeparsing.IsCompactDuration(age) {
+		return fmt.Errorf("want an age such as 90d, 6m or 1y, got %q", age)
+	}
+	return nil
+}
+
+// Cutoff returns the time before which data of the given age is purged.
+func Cutoff(age string, now time.Time) (time.Time, error) {
+	return timeparsing.ParseCompactDuration("-"+age, now)
+}
+
+// Store is implemented by storage backends that can purge (SQLite).
+type Store interface {
+	PurgeClosedComments(ctx context.Context, closedBefore time.Time, dryRun bool) (map[string]int, error)
+	PurgeEvents(ctx context.Context, before time.Time, dryRun bool) (int, error)
+}
+
+// Result is what a run purged, or would purge with DryRun.
+type Result struct {
+	DryRun        bool      `json:"dry_run"`
+	Policy        Policy    `json:"policy"`
+	Comments      int       `json:"comments"`
+	CommentIssues []string  `json:"comment_issues,omitempty"`
+	Events        int       `json:"events"`
+	Interactions  int       `json:"interactions"`
+	RanAt         time.Time `json:"ran_at"`
+}
+
+// Total is the number of records purged.
+func (r *Result) Total() int {
+	return r.Comments + r.Events + r.Interactions
+}
+
+// Run applies policy as of now: s holds comments and events, and
+// interactionsPath is the interactions log ("" to skip it). With dryRun
+// nothing is deleted.
+func Run(ctx context.Context, s storage.Storage, interactionsPath string, policy Policy, now time.Time, dryRun bool) (*Result, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	res := &Result{DryRun: dryRun, Policy: policy, RanAt: now}
+	if policy.ClosedComments != "" || policy.Events != "" {
+		ps, ok := s.(Store)
+		if !ok {
+			return nil, ErrUnsupported
+		}
+		if policy.ClosedComments != "" {
+			cutoff, _ := Cutoff(policy.ClosedComments, now)
+			counts, err := ps.PurgeClosedComments(ctx, cutoff, dryRun)
+			if err != nil {
+				return nil, err
+			}
+			for id, n := range counts {
+				res.Comments += n
+				res.CommentIssues = append(res.CommentIssues, id)
+			}
+			sort.Strings(res.CommentIssues)
+		}
+		if policy.Events != "" {
+			cutoff, _ := Cutoff(policy.Events, now)
+			n, err := ps.PurgeEvents(ctx, cutoff, dryRun)
+			if err != nil {
+				return nil, err
+			}
+			res.Events = n
+		}
+	}
+	if policy.Interactions != "" && interactionsPath != "" {
+		cutoff, _ := Cutoff(policy.Interactions, now)
+		n, err := audit.Prune(interactionsPath, cutoff, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		res.Interactions = n
+	}
+	return res, nil
+}
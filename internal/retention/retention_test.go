@@ -0,0 +1,126 @@
+package retention
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestValidateAge(t *testing.T) {
+	for _, age := range []string{"", "90d", "6m", "1y", "12h", "2w"} {
+		if err := ValidateAge(age); err != nil {
+			t.Errorf("ValidateAge(%q) = %v, want nil", age, err)
+		}
+	}
+	for _, age := range []string{"2", "-1y", "+1y", "1 year", "1x"} {
+		if err := ValidateAge(age); err == nil {
+			t.Errorf("ValidateAge(%q) = nil, want an error", age)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := sqlite.New(ctx, filepath.Join(dir, "beads.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for _, id := range []string{"bd-old", "bd-recent", "bd-open"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := store.AddIssueComment(ctx, id, "tester", "note"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for _, id := range []string{"bd-old", "bd-recent"} {
+		if err := store.CloseIssue(ctx, id, "done", "tester", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db := store.UnderlyingDB()
+	if _, err := db.Exec(`UPDATE issues SET closed_at = ? WHERE id = 'bd-old'`, now.AddDate(-2, 0, 0).Format(time.RFC3339)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE events SET created_at = ? WHERE issue_id = 'bd-old' AND event_type = 'created'`, now.AddDate(-3, 0, 0).Format(time.RFC3339)); err != nil {
+		t.Fatal(err)
+	}
+
+	interactions := filepath.Join(dir, "interactions.jsonl")
+	log := `{"id":"int-1","kind":"llm_call","created_at":"` + now.AddDate(-3, 0, 0).UTC().Format(time.RFC3339) + `"}
+{"id":"int-2","kind":"llm_call","created_at":"` + now.UTC().Format(time.RFC3339) + `"}
+not json
+`
+	if err := os.WriteFile(interactions, []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := Policy{ClosedComments: "1y", Events: "2y", Interactions: "2y"}
+	preview, err := Run(ctx, store, interactions, policy, now, true)
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+	if preview.Comments != 2 || preview.Events != 1 || preview.Interactions != 1 {
+		t.Fatalf("preview = %+v, want 2 comments, 1 event, 1 interaction", preview)
+	}
+	if comments, _ := store.GetIssueComments(ctx, "bd-old"); len(comments) != 2 {
+		t.Fatal("preview deleted comments")
+	}
+
+	res, err := Run(ctx, store, interactions, policy, now, false)
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if res.Comments != 2 || len(res.CommentIssues) != 1 || res.CommentIssues[0] != "bd-old" {
+		t.Errorf("result = %+v, want bd-old's 2 comments", res)
+	}
+	for id, want := range map[string]int{"bd-old": 0, "bd-recent": 2, "bd-open": 2} {
+		if comments, _ := store.GetIssueComments(ctx, id); len(comments) != want {
+			t.Errorf("%s has %d comments, want %d", id, len(comments), want)
+		}
+	}
+	dirty, _ := store.GetDirtyIssues(ctx)
+	found := false
+	for _, id := range dirty {
+		found = found || id == "bd-old"
+	}
+	if !found {
+		t.Error("bd-old should be marked dirty so the export drops its comments")
+	}
+	data, _ := os.ReadFile(interactions)
+	if strings.Contains(string(data), "int-1") || !strings.Contains(string(data), "int-2") || !strings.Contains(string(data), "not json") {
+		t.Errorf("interactions log after prune:\n%s", data)
+	}
+
+	again, err := Run(ctx, store, interactions, policy, now, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Total() != 0 {
+		t.Errorf("second run purged %d, want 0", again.Total())
+	}
+}
+
+func TestRunUnsupportedStore(t *testing.T) {
+	_, err := Run(context.Background(), memory.New(""), "", Policy{Events: "1y"}, time.Now(), true)
+	if err != ErrUnsupported {
+		t.Errorf("err = %v, want ErrUnsupported", err)
+	}
+}
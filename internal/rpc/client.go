@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -36,6 +37,7 @@ type Client struct {
 	conn       net.Conn
 	socketPath string
 	timeout    time.Duration
+	ctx        context.Context // cancels in-flight requests (Ctrl-C, --timeout)
 	dbPath     string // Expected database path for validation
 	actor      string // Actor for audit trail (who is performing operations)
 }
@@ -153,6 +155,13 @@ func (c *Client) SetTimeout(timeout time.Duration) {
 	c.timeout = timeout
 }
 
+// SetContext ties requests to ctx: a request fails as soon as ctx is done,
+// and the daemon is told the deadline so it stops work the client has
+// given up on.
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
 // SetDatabasePath sets the expected database path for validation
 func (c *Client) SetDatabasePath(dbPath string) {
 	c.dbPath = dbPath
@@ -181,12 +190,32 @@ func (c *Client) ExecuteWithCwd(operation string, args interface{}, cwd string)
 	}
 
 	req := Request{
-		Operation:     operation,
-		Args:          argsJSON,
-		Actor:         c.actor, // Who is performing this operation
-		ClientVersion: ClientVersion,
-		Cwd:           cwd,
-		ExpectedDB:    c.dbPath, // Send expected database path for validation
+		Operation:       operation,
+		Args:            argsJSON,
+		Actor:           c.actor, // Who is performing this operation
+		ClientVersion:   ClientVersion,
+		ProtocolVersion: ProtocolVersion,
+		Cwd:             cwd,
+		ExpectedDB:      c.dbPath, // Send expected database path for validation
+	}
+
+	var deadline time.Time
+	if c.timeout > 0 {
+		deadline = time.Now().Add(c.timeout)
+	}
+	if c.ctx != nil {
+		if err := c.ctx.Err(); err != nil {
+			return nil, context.Cause(c.ctx)
+		}
+		if d, ok := c.ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+		// Unblock the read below when ctx is cancelled
+		stop := context.AfterFunc(c.ctx, func() { _ = c.conn.SetDeadline(time.Now()) })
+		defer stop()
+	}
+	if !deadline.IsZero() {
+		req.TimeoutMs = time.Until(deadline).Milliseconds()
 	}
 
 	reqJSON, err := json.Marshal(req)
@@ -194,8 +223,7 @@ func (c *Client) ExecuteWithCwd(operation string, args interface{}, cwd string)
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	if c.timeout > 0 {
-		deadline := time.Now().Add(c.timeout)
+	if !deadline.IsZero() {
 		if err := c.conn.SetDeadline(deadline); err != nil {
 			return nil, fmt.Errorf("failed to set deadline: %w", err)
 		}
@@ -215,6 +243,9 @@ func (c *Client) ExecuteWithCwd(operation string, args interface{}, cwd string)
 	reader := bufio.NewReader(c.conn)
 	respLine, err := reader.ReadBytes('\n')
 	if err != nil {
+		if c.ctx != nil && c.ctx.Err() != nil {
+			return nil, fmt.Errorf("%s: %w", operation, context.Cause(c.ctx))
+		}
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
@@ -355,6 +386,11 @@ func (c *Client) Stats() (*Response, error) {
 	return c.Execute(OpStats, nil)
 }
 
+// Prompt retrieves the cached 'bd prompt' summary via the daemon
+func (c *Client) Prompt(args *PromptArgs) (*Response, error) {
+	return c.Execute(OpPrompt, args)
+}
+
 // GetMutations retrieves recent mutations from the daemon
 func (c *Client) GetMutations(args *GetMutationsArgs) (*Response, error) {
 	return c.Execute(OpGetMutations, args)
@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClientContextCancelsRequests(t *testing.T) {
+	_, client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	client.SetContext(ctx)
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping before cancel: %v", err)
+	}
+
+	cause := errors.New("interrupted")
+	cancel(cause)
+	start := time.Now()
+	if err := client.Ping(); !errors.Is(err, cause) {
+		t.Errorf("Ping after cancel = %v, want %v", err, cause)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("cancelled request did not fail fast")
+	}
+}
+
+func TestReqCtxHonorsClientDeadline(t *testing.T) {
+	s := &Server{requestTimeout: time.Minute}
+
+	deadline, _ := s.reqCtx(&Request{}).Deadline()
+	if d := time.Until(deadline); d < 50*time.Second {
+		t.Errorf("default deadline in %v, want about a minute", d)
+	}
+	deadline, _ = s.reqCtx(&Request{TimeoutMs: 2000}).Deadline()
+	if d := time.Until(deadline); d > 2*time.Second {
+		t.Errorf("client deadline ignored: %v", d)
+	}
+	deadline, _ = s.reqCtx(&Request{TimeoutMs: int64(time.Hour / time.Millisecond)}).Deadline()
+	if d := time.Until(deadline); d > time.Minute {
+		t.Errorf("client deadline extended the server timeout: %v", d)
+	}
+}
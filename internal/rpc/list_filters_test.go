@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -255,6 +256,37 @@ func TestListFiltersParity(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "pattern matching - title regex",
+			listArgs: &ListArgs{
+				TitleRegex: "^Auth",
+				Limit:      10,
+			},
+			directCount: 1,
+			validator: func(t *testing.T, issues []*types.Issue) {
+				for _, issue := range issues {
+					if !strings.HasPrefix(issue.Title, "Auth") {
+						t.Errorf("Issue %s title does not start with 'Auth': %s", issue.ID, issue.Title)
+					}
+				}
+			},
+		},
+		{
+			name: "label glob and exclusion",
+			listArgs: &ListArgs{
+				LabelGlobs: []string{"b*"},
+				NotLabels:  []string{"security"},
+				Limit:      10,
+			},
+			directCount: 1, // "bug" matches; both "backend" issues are security
+			validator: func(t *testing.T, issues []*types.Issue) {
+				for _, issue := range issues {
+					if issue.Title != "Fix login bug" {
+						t.Errorf("Unexpected issue %s: %s", issue.ID, issue.Title)
+					}
+				}
+			},
+		},
 		{
 			name: "empty description check",
 			listArgs: &ListArgs{
@@ -689,12 +721,15 @@ func listArgsToFilter(args *ListArgs, t *testing.T) *types.IssueFilter {
 		TitleContains:       args.TitleContains,
 		DescriptionContains: args.DescriptionContains,
 		NotesContains:       args.NotesContains,
+		TitleRegex:          args.TitleRegex,
 		EmptyDescription:    args.EmptyDescription,
 		NoAssignee:          args.NoAssignee,
 		NoLabels:            args.NoLabels,
 		PriorityMin:         args.PriorityMin,
 		PriorityMax:         args.PriorityMax,
 		Labels:              args.Labels,
+		LabelGlobs:          args.LabelGlobs,
+		NotLabels:           args.NotLabels,
 	}
 
 	if args.Status != "" && args.Status != "all" {
@@ -738,3 +773,167 @@ func listArgsToFilter(args *ListArgs, t *testing.T) *types.IssueFilter {
 
 	return filter
 }
+
+// TestListExpr verifies the daemon evaluates query expressions, including
+// saved filter references, and reports malformed expressions as errors.
+func TestListExpr(t *testing.T) {
+	_, client, store, cleanup := setupTestServerWithStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	ids := make(map[string]string)
+	for _, c := range []CreateArgs{
+		{Title: "Urgent fix", IssueType: "bug", Priority: 0, Labels: []string{"api"}},
+		{Title: "API docs", IssueType: "task", Priority: 3, Labels: []string{"api", "docs"}},
+		{Title: "Cleanup", IssueType: "chore", Priority: 2},
+	} {
+		resp, err := client.Create(&c)
+		if err != nil {
+			t.Fatalf("Create(%q) failed: %v", c.Title, err)
+		}
+		var issue types.Issue
+		if err := json.Unmarshal(resp.Data, &issue); err != nil {
+			t.Fatalf("Failed to unmarshal created issue: %v", err)
+		}
+		ids[c.Title] = issue.ID
+	}
+	if err := store.SetConfig(ctx, "query.api", "label=api not label=docs"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"priority<=1 or label:docs", []string{ids["Urgent fix"], ids["API docs"]}},
+		{"@api", []string{ids["Urgent fix"]}},
+		{"title:clean", []string{ids["Cleanup"]}},
+	}
+	for _, tt := range tests {
+		resp, err := client.List(&ListArgs{Expr: tt.expr})
+		if err != nil {
+			t.Fatalf("List(%q) failed: %v", tt.expr, err)
+		}
+		var issues []*types.IssueWithCounts
+		if err := json.Unmarshal(resp.Data, &issues); err != nil {
+			t.Fatalf("Failed to unmarshal issues: %v", err)
+		}
+		got := make(map[string]bool)
+		for _, issue := range issues {
+			got[issue.ID] = true
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("List(%q) returned %d issues, want %d", tt.expr, len(got), len(tt.want))
+		}
+		for _, id := range tt.want {
+			if !got[id] {
+				t.Errorf("List(%q) missing %s", tt.expr, id)
+			}
+		}
+	}
+
+	if _, err := client.List(&ListArgs{Expr: "priority=high"}); err == nil || !strings.Contains(err.Error(), "invalid query") {
+		t.Errorf("expected invalid query error, got %v", err)
+	}
+}
+
+func TestListAndReadyPaginate(t *testing.T) {
+	_, client, _, cleanup := setupTestServerWithStore(t)
+	defer cleanup()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Create(&CreateArgs{Title: fmt.Sprintf("Paged %d", i), IssueType: "task", Priority: 2}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	fetch := func(call func(cursor string) (*Response, error)) []string {
+		var ids []string
+		cursor := ""
+		for {
+			resp, err := call(cursor)
+			if err != nil {
+				t.Fatalf("paged request failed: %v", err)
+			}
+			var page types.IssuePage[*types.Issue]
+			if err := json.Unmarshal(resp.Data, &page); err != nil {
+				t.Fatalf("Failed to unmarshal page: %v", err)
+			}
+			if len(page.Issues) > 2 {
+				t.Fatalf("page has %d issues, want at most 2", len(page.Issues))
+			}
+			for _, issue := range page.Issues {
+				ids = append(ids, issue.ID)
+			}
+			if page.NextCursor == "" {
+				return ids
+			}
+			cursor = page.NextCursor
+		}
+	}
+
+	listed := fetch(func(cursor string) (*Response, error) {
+		return client.List(&ListArgs{Limit: 2, Paginate: true, Cursor: cursor})
+	})
+	ready := fetch(func(cursor string) (*Response, error) {
+		return client.Ready(&ReadyArgs{Limit: 2, Paginate: true, Cursor: cursor})
+	})
+	for name, ids := range map[string][]string{"list": listed, "ready": ready} {
+		seen := make(map[string]bool)
+		for _, id := range ids {
+			if seen[id] {
+				t.Errorf("%s: %s returned twice", name, id)
+			}
+			seen[id] = true
+		}
+		if len(seen) != 3 {
+			t.Errorf("%s: got %d issues across pages, want 3", name, len(seen))
+		}
+	}
+
+	if _, err := client.List(&ListArgs{Paginate: true, Cursor: "bogus"}); err == nil || !strings.Contains(err.Error(), "invalid cursor") {
+		t.Errorf("expected invalid cursor error, got %v", err)
+	}
+}
+
+func TestListCountOnly(t *testing.T) {
+	_, client, _, cleanup := setupTestServerWithStore(t)
+	defer cleanup()
+
+	for _, c := range []CreateArgs{
+		{Title: "API bug", IssueType: "bug", Priority: 1, Labels: []string{"api"}},
+		{Title: "UI task", IssueType: "task", Priority: 2, Labels: []string{"ui"}, Assignee: "bob"},
+		{Title: "Docs", IssueType: "task", Priority: 2},
+	} {
+		if _, err := client.Create(&c); err != nil {
+			t.Fatalf("Create(%q) failed: %v", c.Title, err)
+		}
+	}
+
+	count := func(args *ListArgs) types.IssueCounts {
+		t.Helper()
+		args.CountOnly = true
+		resp, err := client.List(args)
+		if err != nil {
+			t.Fatalf("List(count) failed: %v", err)
+		}
+		var counts types.IssueCounts
+		if err := json.Unmarshal(resp.Data, &counts); err != nil {
+			t.Fatalf("Failed to unmarshal counts: %v", err)
+		}
+		return counts
+	}
+
+	if got := count(&ListArgs{IssueType: "task", Limit: 1}); got.Total != 2 || len(got.Groups) != 0 {
+		t.Errorf("task count = %+v, want total 2 without groups", got)
+	}
+	got := count(&ListArgs{GroupBy: "label"})
+	want := []types.GroupCount{{Group: "(no labels)", Count: 1}, {Group: "api", Count: 1}, {Group: "ui", Count: 1}}
+	if got.Total != 3 || fmt.Sprint(got.Groups) != fmt.Sprint(want) {
+		t.Errorf("label counts = %+v, want total 3 and %v", got, want)
+	}
+
+	if _, err := client.List(&ListArgs{CountOnly: true, GroupBy: "color"}); err == nil || !strings.Contains(err.Error(), "invalid group by") {
+		t.Errorf("expected invalid group by error, got %v", err)
+	}
+}
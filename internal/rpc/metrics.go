@@ -173,6 +173,9 @@ type MetricsSnapshot struct {
 	MemoryAllocMB  uint64             `json:"memory_alloc_mb"`
 	MemorySysMB    uint64             `json:"memory_sys_mb"`
 	GoroutineCount int                `json:"goroutine_count"`
+	CacheHits      int64              `json:"cache_hits"`
+	CacheMisses    int64              `json:"cache_misses"`
+	CacheEntries   int                `json:"cache_entries"`
 }
 
 // OperationMetrics holds metrics for a single operation type
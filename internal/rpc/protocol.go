@@ -45,6 +45,7 @@ const (
 	OpGetWorkerStatus     = "get_worker_status"
 	OpGetConfig           = "get_config"
 	OpMolStale            = "mol_stale"
+	OpPrompt              = "prompt"
 
 	// Gate operations
 	OpGateCreate = "gate_create"
@@ -54,15 +55,30 @@ const (
 	OpGateWait   = "gate_wait"
 )
 
+// ProtocolVersion is the RPC protocol spoken by this build. Bump it when a
+// request or response changes in a way an older peer can't handle.
+const ProtocolVersion = 1
+
+// MinProtocolVersion is the oldest client protocol the daemon still serves.
+const MinProtocolVersion = 1
+
+// Which side of an incompatible connection needs upgrading
+const (
+	UpgradeDaemon = "daemon"
+	UpgradeClient = "client"
+)
+
 // Request represents an RPC request from client to daemon
 type Request struct {
-	Operation     string          `json:"operation"`
-	Args          json.RawMessage `json:"args"`
-	Actor         string          `json:"actor,omitempty"`
-	RequestID     string          `json:"request_id,omitempty"`
-	Cwd           string          `json:"cwd,omitempty"`            // Working directory for database discovery
-	ClientVersion string          `json:"client_version,omitempty"` // Client version for compatibility checks
-	ExpectedDB    string          `json:"expected_db,omitempty"`    // Expected database path for validation (absolute)
+	Operation       string          `json:"operation"`
+	Args            json.RawMessage `json:"args"`
+	Actor           string          `json:"actor,omitempty"`
+	RequestID       string          `json:"request_id,omitempty"`
+	Cwd             string          `json:"cwd,omitempty"`              // Working directory for database discovery
+	ClientVersion   string          `json:"client_version,omitempty"`   // Client version for compatibility checks
+	ProtocolVersion int             `json:"protocol_version,omitempty"` // Client RPC protocol (0 = before negotiation)
+	ExpectedDB      string          `json:"expected_db,omitempty"`      // Expected database path for validation (absolute)
+	TimeoutMs       int64           `json:"timeout_ms,omitempty"`       // Time the client will wait; caps the server's request timeout
 }
 
 // Response represents an RPC response from daemon to client
@@ -112,6 +128,8 @@ type CreateArgs struct {
 	// Time-based scheduling fields (GH#820)
 	DueAt      string `json:"due_at,omitempty"`      // Relative or ISO format due date
 	DeferUntil string `json:"defer_until,omitempty"` // Relative or ISO format defer date
+	// Force lets the issue break the validation rules (bd create --force)
+	Force bool `json:"force,omitempty"`
 }
 
 // UpdateArgs represents arguments for the update operation
@@ -167,6 +185,8 @@ type UpdateArgs struct {
 	Waiters []string `json:"waiters,omitempty"`  // Mail addresses to notify when gate clears
 	// Slot fields
 	Holder *string `json:"holder,omitempty"` // Who currently holds the slot (for type=slot beads)
+	// Force lets the change break the validation rules (bd update --force)
+	Force bool `json:"force,omitempty"`
 }
 
 // CloseArgs represents arguments for the close operation
@@ -206,11 +226,16 @@ type ListArgs struct {
 	LabelsAny []string `json:"labels_any,omitempty"` // OR semantics
 	IDs       []string `json:"ids,omitempty"`        // Filter by specific issue IDs
 	Limit     int      `json:"limit,omitempty"`
+
+	// Label patterns and exclusion
+	LabelGlobs []string `json:"label_globs,omitempty"` // AND semantics
+	NotLabels  []string `json:"not_labels,omitempty"`  // Issue must have none of these
 	
 	// Pattern matching
 	TitleContains       string `json:"title_contains,omitempty"`
 	DescriptionContains string `json:"description_contains,omitempty"`
 	NotesContains       string `json:"notes_contains,omitempty"`
+	TitleRegex          string `json:"title_regex,omitempty"` // Go regexp syntax
 	
 	// Date ranges (ISO 8601 format)
 	CreatedAfter  string `json:"created_after,omitempty"`
@@ -258,6 +283,19 @@ type ListArgs struct {
 	DueBefore   string `json:"due_before,omitempty"`   // ISO 8601 format
 	Overdue     bool   `json:"overdue,omitempty"`      // Filter issues where due_at < now
 
+	// Query expression (bd query), ANDed with the filters above
+	Expr string `json:"expr,omitempty"`
+
+	// Pagination: with Paginate set, the response is a types.IssuePage
+	// whose NextCursor can be passed back as Cursor for the next page
+	Paginate bool   `json:"paginate,omitempty"`
+	Cursor   string `json:"cursor,omitempty"`
+
+	// Aggregation: with CountOnly set, the response is a types.IssueCounts
+	// (optionally grouped by GroupBy) instead of issues
+	CountOnly bool   `json:"count_only,omitempty"`
+	GroupBy   string `json:"group_by,omitempty"`
+
 	// Staleness control (bd-dpkdm)
 	AllowStale bool `json:"allow_stale,omitempty"` // Skip staleness check, return potentially stale data
 }
@@ -323,6 +361,8 @@ type ReadyArgs struct {
 	ParentID        string   `json:"parent_id,omitempty"`        // Filter to descendants of this bead/epic
 	MolType         string   `json:"mol_type,omitempty"`         // Filter by molecule type: swarm, patrol, or work
 	IncludeDeferred bool     `json:"include_deferred,omitempty"` // Include issues with future defer_until (GH#820)
+	Paginate        bool     `json:"paginate,omitempty"`         // Respond with a types.IssuePage
+	Cursor          string   `json:"cursor,omitempty"`           // NextCursor from the previous page
 }
 
 // BlockedArgs represents arguments for the blocked operation
@@ -414,10 +454,13 @@ type StatusResponse struct {
 
 // HealthResponse is the response for a health check operation
 type HealthResponse struct {
-	Status         string  `json:"status"`                   // "healthy", "degraded", "unhealthy"
-	Version        string  `json:"version"`                  // Server/daemon version
-	ClientVersion  string  `json:"client_version,omitempty"` // Client version from request
-	Compatible     bool    `json:"compatible"`               // Whether versions are compatible
+	Status         string  `json:"status"`                         // "healthy", "degraded", "unhealthy"
+	Version        string  `json:"version"`                        // Server/daemon version
+	ClientVersion  string  `json:"client_version,omitempty"`       // Client version from request
+	Compatible     bool    `json:"compatible"`                     // Whether versions are compatible
+	Upgrade        string  `json:"upgrade,omitempty"`              // Side to upgrade when incompatible: "daemon" or "client"
+	Protocol       int     `json:"protocol_version,omitempty"`     // Daemon RPC protocol
+	MinProtocol    int     `json:"min_protocol_version,omitempty"` // Oldest client protocol the daemon serves
 	Uptime         float64 `json:"uptime_seconds"`
 	DBResponseTime float64 `json:"db_response_ms"`
 	ActiveConns    int32   `json:"active_connections"`
@@ -603,6 +646,11 @@ type MoleculeProgress struct {
 	Steps      []MoleculeStep `json:"steps"`
 }
 
+// PromptArgs represents arguments for the prompt operation
+type PromptArgs struct {
+	Assignee string `json:"assignee,omitempty"` // Limit in-progress issues to this assignee
+}
+
 // GetConfigArgs represents arguments for getting daemon config
 type GetConfigArgs struct {
 	Key string `json:"key"` // Config key to retrieve (e.g., "issue_prefix")
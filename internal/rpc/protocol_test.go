@@ -170,3 +170,36 @@ func TestUpdateArgsWithNilValues(t *testing.T) {
 		t.Errorf("Expected status to be nil, got %v", *decodedArgs.Status)
 	}
 }
+
+func TestCheckCompatibilityNegotiatesProtocol(t *testing.T) {
+	originalServerVersion := ServerVersion
+	defer func() { ServerVersion = originalServerVersion }()
+	s := &Server{}
+
+	tests := []struct {
+		name          string
+		serverVersion string
+		req           Request
+		wantUpgrade   string // "" = compatible
+	}{
+		{"same protocol", "dev", Request{ClientVersion: "dev", ProtocolVersion: ProtocolVersion}, ""},
+		{"client before negotiation", "dev", Request{ClientVersion: "dev"}, ""},
+		{"client speaks newer protocol", "dev", Request{ClientVersion: "dev", ProtocolVersion: ProtocolVersion + 1}, UpgradeDaemon},
+		{"daemon older", "1.2.0", Request{ClientVersion: "1.3.0", ProtocolVersion: ProtocolVersion}, UpgradeDaemon},
+		{"daemon newer major", "1.2.0", Request{ClientVersion: "0.9.0", ProtocolVersion: ProtocolVersion}, UpgradeClient},
+	}
+	for _, tt := range tests {
+		ServerVersion = tt.serverVersion
+		err := s.checkCompatibility(&tt.req)
+		if tt.wantUpgrade == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		verr, ok := err.(*VersionError)
+		if !ok || verr.Upgrade != tt.wantUpgrade {
+			t.Errorf("%s: got %v, want a VersionError to upgrade the %s", tt.name, err, tt.wantUpgrade)
+		}
+	}
+}
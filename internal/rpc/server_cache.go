@@ -0,0 +1,173 @@
+package rpc
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Agents poll 'bd ready' and 'bd list' every few seconds, and shell prompts
+// call 'bd prompt' on every render. Those responses are cached until the next
+// write through the daemon, so repeated polls don't hit SQLite at all.
+//
+// Writes that bypass the daemon (direct mode, git pulls, another daemon) are
+// caught by comparing the database and WAL file stats recorded with each
+// entry. The TTL bounds staleness for everything else, such as deferred
+// issues becoming ready as time passes.
+
+// defaultReadCacheTTL is how long a cached response may be served.
+const defaultReadCacheTTL = 10 * time.Second
+
+// maxReadCacheEntries bounds memory when clients page through results with
+// many distinct cursors. The whole cache is dropped when it fills up.
+const maxReadCacheEntries = 256
+
+// cacheableOps are read operations whose responses depend only on their args
+// and the database contents.
+var cacheableOps = map[string]bool{
+	OpReady:   true,
+	OpList:    true,
+	OpCount:   true,
+	OpBlocked: true,
+	OpStats:   true,
+	OpPrompt:  true,
+}
+
+// readOnlyOps never modify the database, so they leave the cache intact.
+// Any operation not listed here (including unknown ones) invalidates it.
+var readOnlyOps = map[string]bool{
+	OpPing:                true,
+	OpStatus:              true,
+	OpHealth:              true,
+	OpMetrics:             true,
+	OpShow:                true,
+	OpResolveID:           true,
+	OpStale:               true,
+	OpCommentList:         true,
+	OpCompactStats:        true,
+	OpExport:              true,
+	OpEpicStatus:          true,
+	OpGetMutations:        true,
+	OpGetMoleculeProgress: true,
+	OpGetWorkerStatus:     true,
+	OpGetConfig:           true,
+	OpMolStale:            true,
+	OpGateList:            true,
+	OpGateShow:            true,
+}
+
+// readCache holds successful responses keyed by operation and raw args.
+type readCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]readCacheEntry
+	// gen increases on every invalidation so a response computed before a
+	// write is never stored after it.
+	gen    uint64
+	hits   int64
+	misses int64
+}
+
+type readCacheEntry struct {
+	data []byte
+	at   time.Time
+	fp   dbFingerprint
+}
+
+// dbFingerprint identifies the on-disk state of the database.
+type dbFingerprint struct {
+	dbMod, walMod   time.Time
+	dbSize, walSize int64
+}
+
+// readCacheTTLFromEnv parses BEADS_DAEMON_CACHE_TTL; 0 disables the cache.
+func readCacheTTLFromEnv() time.Duration {
+	env := os.Getenv("BEADS_DAEMON_CACHE_TTL")
+	if env == "" {
+		return defaultReadCacheTTL
+	}
+	ttl, err := time.ParseDuration(env)
+	if err != nil || ttl < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid BEADS_DAEMON_CACHE_TTL %q, using %s\n", env, defaultReadCacheTTL)
+		return defaultReadCacheTTL
+	}
+	return ttl
+}
+
+func cacheKey(req *Request) string {
+	return req.Operation + "\x00" + string(req.Args)
+}
+
+// fingerprint stats the database and its WAL. Writes from other processes
+// always touch one of them.
+func (s *Server) fingerprint() dbFingerprint {
+	var fp dbFingerprint
+	if s.dbPath == "" {
+		return fp
+	}
+	if fi, err := os.Stat(s.dbPath); err == nil {
+		fp.dbMod, fp.dbSize = fi.ModTime(), fi.Size()
+	}
+	if fi, err := os.Stat(s.dbPath + "-wal"); err == nil {
+		fp.walMod, fp.walSize = fi.ModTime(), fi.Size()
+	}
+	return fp
+}
+
+// cachedResponse returns a fresh cached response for req, along with the
+// cache generation to pass to storeResponse on a miss.
+func (s *Server) cachedResponse(req *Request) (Response, uint64, bool) {
+	c := &s.readCache
+	if c.ttl <= 0 {
+		return Response{}, 0, false
+	}
+	fp := s.fingerprint()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[cacheKey(req)]
+	if ok && time.Since(entry.at) < c.ttl && entry.fp == fp {
+		c.hits++
+		return Response{Success: true, Data: entry.data}, c.gen, true
+	}
+	c.misses++
+	return Response{}, c.gen, false
+}
+
+// storeResponse caches a successful response unless the cache was
+// invalidated after gen was read.
+func (s *Server) storeResponse(req *Request, gen uint64, resp Response) {
+	c := &s.readCache
+	if c.ttl <= 0 || !resp.Success {
+		return
+	}
+	fp := s.fingerprint()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	if c.entries == nil || len(c.entries) >= maxReadCacheEntries {
+		c.entries = make(map[string]readCacheEntry)
+	}
+	c.entries[cacheKey(req)] = readCacheEntry{data: resp.Data, at: time.Now(), fp: fp}
+}
+
+// invalidateReadCache drops all cached responses after a write.
+func (s *Server) invalidateReadCache() {
+	c := &s.readCache
+	c.mu.Lock()
+	c.entries = nil
+	c.gen++
+	c.mu.Unlock()
+}
+
+// readCacheStats returns the hit and miss counts for metrics.
+func (s *Server) readCacheStats() (hits, misses int64, size int) {
+	c := &s.readCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses, len(c.entries)
+}
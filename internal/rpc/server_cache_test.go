@@ -0,0 +1,121 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func readyIDs(t *testing.T, client *Client) []string {
+	t.Helper()
+	resp, err := client.Ready(&ReadyArgs{})
+	if err != nil {
+		t.Fatalf("Ready failed: %v", err)
+	}
+	var issues []*types.Issue
+	if err := json.Unmarshal(resp.Data, &issues); err != nil {
+		t.Fatalf("Failed to unmarshal ready issues: %v", err)
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	return ids
+}
+
+func TestReadCacheServesRepeatedReads(t *testing.T) {
+	server, client, _, cleanup := setupTestServerWithStore(t)
+	defer cleanup()
+
+	if _, err := client.Create(&CreateArgs{Title: "First", IssueType: "task", Priority: 2}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if ids := readyIDs(t, client); len(ids) != 1 {
+			t.Fatalf("ready = %v, want 1 issue", ids)
+		}
+	}
+	hits, misses, size := server.readCacheStats()
+	if hits != 2 || misses != 1 || size != 1 {
+		t.Errorf("hits=%d misses=%d size=%d, want 2/1/1", hits, misses, size)
+	}
+
+	// Different args are cached separately
+	if _, err := client.Ready(&ReadyArgs{Priority: intPtr(0)}); err != nil {
+		t.Fatalf("Ready failed: %v", err)
+	}
+	if _, _, size := server.readCacheStats(); size != 2 {
+		t.Errorf("size = %d, want 2", size)
+	}
+}
+
+func TestReadCacheInvalidatedByDaemonWrite(t *testing.T) {
+	server, client, _, cleanup := setupTestServerWithStore(t)
+	defer cleanup()
+
+	if _, err := client.Create(&CreateArgs{Title: "First", IssueType: "task", Priority: 2}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if ids := readyIDs(t, client); len(ids) != 1 {
+		t.Fatalf("ready = %v, want 1 issue", ids)
+	}
+
+	if _, err := client.Create(&CreateArgs{Title: "Second", IssueType: "task", Priority: 2}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, _, size := server.readCacheStats(); size != 0 {
+		t.Errorf("cache has %d entries after create, want 0", size)
+	}
+	if ids := readyIDs(t, client); len(ids) != 2 {
+		t.Errorf("ready after create = %v, want 2 issues", ids)
+	}
+}
+
+func TestReadCacheDetectsExternalWrite(t *testing.T) {
+	_, client, store, cleanup := setupTestServerWithStore(t)
+	defer cleanup()
+
+	if ids := readyIDs(t, client); len(ids) != 0 {
+		t.Fatalf("ready = %v, want none", ids)
+	}
+
+	// Write behind the daemon's back, as a direct-mode bd would
+	issue := &types.Issue{Title: "External", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(context.Background(), issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if ids := readyIDs(t, client); len(ids) != 1 {
+		t.Errorf("ready after external write = %v, want 1 issue", ids)
+	}
+}
+
+func TestReadCacheStaleGenerationNotStored(t *testing.T) {
+	server := NewServer("", nil, "", "")
+	req := &Request{Operation: OpReady, Args: json.RawMessage(`{}`)}
+
+	_, gen, ok := server.cachedResponse(req)
+	if ok {
+		t.Fatal("unexpected hit on empty cache")
+	}
+	// A write lands while the read is being computed
+	server.invalidateReadCache()
+	server.storeResponse(req, gen, Response{Success: true, Data: json.RawMessage(`[]`)})
+	if _, _, ok := server.cachedResponse(req); ok {
+		t.Error("response computed before the write was cached")
+	}
+}
+
+func TestReadCacheDisabled(t *testing.T) {
+	t.Setenv("BEADS_DAEMON_CACHE_TTL", "0")
+	server := NewServer("", nil, "", "")
+	req := &Request{Operation: OpStats}
+
+	_, gen, _ := server.cachedResponse(req)
+	server.storeResponse(req, gen, Response{Success: true, Data: json.RawMessage(`{}`)})
+	if _, _, ok := server.cachedResponse(req); ok {
+		t.Error("cache served a response with BEADS_DAEMON_CACHE_TTL=0")
+	}
+}
@@ -56,6 +56,11 @@ type Server struct {
 	recentMutations   []MutationEvent
 	recentMutationsMu sync.RWMutex
 	maxMutationBuffer int
+	// Live mutation subscribers (the HTTP API's event stream)
+	subscribers   map[chan MutationEvent]struct{}
+	subscribersMu sync.Mutex
+	// Cached responses for hot read operations, dropped on every write
+	readCache readCache
 	// Daemon configuration (set via SetConfig after creation)
 	autoCommit   bool
 	autoPush     bool
@@ -135,7 +140,9 @@ func NewServer(socketPath string, store storage.Storage, workspacePath string, d
 		mutationChan:      make(chan MutationEvent, mutationBufferSize), // Configurable buffer
 		recentMutations:   make([]MutationEvent, 0, 100),
 		maxMutationBuffer: 100,
+		subscribers:       make(map[chan MutationEvent]struct{}),
 	}
+	s.readCache.ttl = readCacheTTLFromEnv()
 	s.lastActivityTime.Store(time.Now())
 	return s
 }
@@ -171,6 +178,8 @@ func (s *Server) emitRichMutation(event MutationEvent) {
 		s.droppedEvents.Add(1)
 	}
 
+	s.invalidateReadCache()
+
 	// Store in recent mutations buffer for polling
 	s.recentMutationsMu.Lock()
 	s.recentMutations = append(s.recentMutations, event)
@@ -179,6 +188,49 @@ func (s *Server) emitRichMutation(event MutationEvent) {
 		s.recentMutations = s.recentMutations[1:]
 	}
 	s.recentMutationsMu.Unlock()
+
+	// Fan out to live subscribers; a subscriber too slow to keep up misses
+	// events rather than holding up writes
+	s.subscribersMu.Lock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	s.subscribersMu.Unlock()
+}
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// it misses some.
+const subscriberBuffer = 256
+
+// Subscribe returns a channel receiving every mutation from now on, and a
+// function that ends the subscription and closes the channel.
+func (s *Server) Subscribe() (<-chan MutationEvent, func()) {
+	ch := make(chan MutationEvent, subscriberBuffer)
+	s.subscribersMu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.subscribersMu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.subscribersMu.Lock()
+			delete(s.subscribers, ch)
+			s.subscribersMu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Execute handles req in-process, as if a client had sent it over the
+// socket. The HTTP API sends its writes here, so they get the same lock,
+// validation, event and export handling as bd commands.
+func (s *Server) Execute(req *Request) Response {
+	if req.ExpectedDB == "" {
+		req.ExpectedDB = s.storage.Path()
+	}
+	return s.handleRequest(req)
 }
 
 // MutationChan returns the mutation event channel for the daemon to consume
@@ -11,14 +11,24 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/beads/internal/advisory"
 	"github.com/steveyegge/beads/internal/autoimport"
+	"github.com/steveyegge/beads/internal/cc"
+	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/debug"
 	"github.com/steveyegge/beads/internal/export"
+	"github.com/steveyegge/beads/internal/goals"
 	"github.com/steveyegge/beads/internal/importer"
+	"github.com/steveyegge/beads/internal/oplog"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/repro"
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/subset"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/utils"
+	"github.com/steveyegge/beads/internal/visibility"
+	"github.com/steveyegge/beads/internal/votes"
 )
 
 // handleExport handles the export operation
@@ -137,6 +147,65 @@ func (s *Server) handleExport(req *Request) Response {
 		issue.Comments = allComments[issue.ID]
 	}
 
+	// Populate typed external refs
+	if err := refs.Populate(ctx, store, issues); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get refs: %v", err),
+		}
+	}
+
+	// Populate security advisories
+	if err := advisory.Populate(ctx, store, issues); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get security advisories: %v", err),
+		}
+	}
+
+	// Populate repro metadata
+	if err := repro.Populate(ctx, store, issues); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get repro metadata: %v", err),
+		}
+	}
+	if err := votes.Populate(ctx, store, issues); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get votes: %v", err),
+		}
+	}
+	if err := cc.Populate(ctx, store, issues); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get cc lists: %v", err),
+		}
+	}
+	if err := goals.Populate(ctx, store, issues); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get key results: %v", err),
+		}
+	}
+	// Private issues never reach the committed JSONL
+	issues, err = visibility.WithholdPrivate(ctx, store, issues)
+	if err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to withhold private issues: %v", err),
+		}
+	}
+
+	// Write subset stubs back in full
+	issues, err = subset.PreserveStubs(ctx, store, exportArgs.JSONLPath, issues)
+	if err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to restore subset stubs: %v", err),
+		}
+	}
+
 	// Create temp file for atomic write
 	dir := filepath.Dir(exportArgs.JSONLPath)
 	base := filepath.Base(exportArgs.JSONLPath)
@@ -328,7 +397,21 @@ func (s *Server) checkAndAutoImportIfStale(req *Request) error {
 	// Perform actual import with timeout protection
 	notify := autoimport.NewStderrNotifier(debug.Enabled())
 
+	// With sync.oplog, unexported local edits go into the op log before the
+	// import and the merged log is replayed after it
+	useOpLog := config.GetBool("sync.oplog")
+	jsonlPath := utils.FindJSONLInDir(dbDir)
+
 	importFunc := func(ctx context.Context, issues []*types.Issue) (created, updated int, idMapping map[string]string, err error) {
+		if useOpLog {
+			signer, err := oplog.ConfiguredSigner()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: op log not signed: %v\n", err)
+			}
+			if _, err := oplog.RecordPending(ctx, store, jsonlPath, "daemon", signer); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to record op log: %v\n", err)
+			}
+		}
 		// Use the importer package to perform the actual import
 		result, err := importer.ImportIssues(ctx, dbPath, store, issues, importer.Options{
 			RenameOnImport: true, // Auto-rename prefix mismatches
@@ -337,6 +420,12 @@ func (s *Server) checkAndAutoImportIfStale(req *Request) error {
 		if err != nil {
 			return 0, 0, nil, err
 		}
+		if useOpLog {
+			if _, err := oplog.Apply(ctx, store, jsonlPath, "oplog"); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to apply op log: %v\n", err)
+			}
+		}
+		s.invalidateReadCache()
 		return result.Created, result.Updated, result.IDMapping, nil
 	}
 
@@ -533,6 +622,39 @@ func (s *Server) triggerExport(ctx context.Context, store storage.Storage, dbPat
 		issue.Comments = allComments[issue.ID]
 	}
 
+	// Populate typed external refs
+	if err := refs.Populate(ctx, store, allIssues); err != nil {
+		return err
+	}
+
+	// Populate security advisories
+	if err := advisory.Populate(ctx, store, allIssues); err != nil {
+		return err
+	}
+	if err := repro.Populate(ctx, store, allIssues); err != nil {
+		return err
+	}
+	if err := votes.Populate(ctx, store, allIssues); err != nil {
+		return err
+	}
+	if err := cc.Populate(ctx, store, allIssues); err != nil {
+		return err
+	}
+	if err := goals.Populate(ctx, store, allIssues); err != nil {
+		return err
+	}
+	// Private issues never reach the committed JSONL
+	allIssues, err = visibility.WithholdPrivate(ctx, store, allIssues)
+	if err != nil {
+		return err
+	}
+
+	// Write subset stubs back in full
+	allIssues, err = subset.PreserveStubs(ctx, store, jsonlPath, allIssues)
+	if err != nil {
+		return fmt.Errorf("failed to restore subset stubs: %w", err)
+	}
+
 	// Write to JSONL file with atomic replace (temp file + rename)
 	dir := filepath.Dir(jsonlPath)
 	base := filepath.Base(jsonlPath)
@@ -8,10 +8,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/beads/internal/advisory"
+	"github.com/steveyegge/beads/internal/cc"
+	"github.com/steveyegge/beads/internal/goals"
+	"github.com/steveyegge/beads/internal/query"
+	"github.com/steveyegge/beads/internal/refs"
+	"github.com/steveyegge/beads/internal/repro"
+	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/util"
 	"github.com/steveyegge/beads/internal/utils"
+	"github.com/steveyegge/beads/internal/validation"
+	"github.com/steveyegge/beads/internal/visibility"
+	"github.com/steveyegge/beads/internal/votes"
 )
 
 // containsLabel checks if a label exists in the list
@@ -221,6 +231,9 @@ func (s *Server) handleCreate(req *Request) Response {
 		}
 	}
 	ctx := s.reqCtx(req)
+	if createArgs.Force {
+		ctx = validation.WithForce(ctx, s.reqActor(req))
+	}
 
 	// If parent is specified, generate child ID
 	issueID := createArgs.ID
@@ -296,6 +309,7 @@ func (s *Server) handleCreate(req *Request) Response {
 		AcceptanceCriteria: strValue(acceptance),
 		Notes:              strValue(notes),
 		Assignee:           strValue(assignee),
+		Labels:             createArgs.Labels, // Added below; set so the validation rules see them
 		ExternalRef:        externalRef,
 		EstimatedMinutes:   createArgs.EstimatedMinutes,
 		Status:             types.StatusOpen,
@@ -565,6 +579,38 @@ func (s *Server) handleUpdate(req *Request) Response {
 
 	actor := s.reqActor(req)
 
+	updates, err := updatesFromArgs(updateArgs)
+	if err != nil {
+		return Response{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	// Check the validation rules against the whole change, then make it in
+	// steps the rules don't check one by one
+	change := make(map[string]interface{}, len(updates)+5)
+	for k, v := range updates {
+		change[k] = v
+	}
+	if updateArgs.Claim {
+		change["assignee"], change["status"] = actor, string(types.StatusInProgress)
+	}
+	if len(updateArgs.SetLabels) > 0 {
+		change["set_labels"] = updateArgs.SetLabels
+	}
+	change["add_labels"], change["remove_labels"] = updateArgs.AddLabels, updateArgs.RemoveLabels
+	if updateArgs.Force {
+		ctx = validation.WithForce(ctx, actor)
+	}
+	if err := validation.CheckUpdate(ctx, store, issue, change); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to update issue: %v", err),
+		}
+	}
+	ctx = validation.WithBypass(ctx)
+
 	// Handle claim operation atomically
 	if updateArgs.Claim {
 		// Check if already claimed (has non-empty assignee)
@@ -587,14 +633,6 @@ func (s *Server) handleUpdate(req *Request) Response {
 		}
 	}
 
-	updates, err := updatesFromArgs(updateArgs)
-	if err != nil {
-		return Response{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-
 	// Apply regular field updates if any
 	if len(updates) > 0 {
 		if err := store.UpdateIssue(ctx, updateArgs.ID, updates, actor); err != nil {
@@ -934,7 +972,7 @@ func (s *Server) handleDelete(req *Request) Response {
 		// Use batch delete if: cascade enabled, force enabled, multiple IDs, or dry-run
 		useBatchDelete := deleteArgs.Cascade || deleteArgs.Force || len(deleteArgs.IDs) > 1 || deleteArgs.DryRun
 		if useBatchDelete {
-			result, err := sqlStore.DeleteIssues(ctx, deleteArgs.IDs, deleteArgs.Cascade, deleteArgs.Force, deleteArgs.DryRun)
+			result, err := sqlStore.DeleteIssues(ctx, deleteArgs.IDs, deleteArgs.Cascade, deleteArgs.Force, deleteArgs.DryRun, s.reqActor(req))
 			if err != nil {
 				return Response{
 					Success: false,
@@ -1025,13 +1063,13 @@ func (s *Server) handleDelete(req *Request) Response {
 			if reason == "" {
 				reason = "deleted via daemon"
 			}
-			if err := t.CreateTombstone(ctx, issueID, "daemon", reason); err != nil {
+			if err := t.CreateTombstone(ctx, issueID, s.reqActor(req), reason); err != nil {
 				errors = append(errors, fmt.Sprintf("%s: %v", issueID, err))
 				continue
 			}
 		} else {
 			// Fallback to hard delete if CreateTombstone not available
-			if err := store.DeleteIssue(ctx, issueID); err != nil {
+			if err := store.DeleteIssue(ctx, issueID, s.reqActor(req)); err != nil {
 				errors = append(errors, fmt.Sprintf("%s: %v", issueID, err))
 				continue
 			}
@@ -1088,6 +1126,19 @@ func (s *Server) handleList(req *Request) Response {
 	filter := types.IssueFilter{
 		Limit: listArgs.Limit,
 	}
+	if listArgs.Paginate {
+		after, err := types.DecodeCursor(listArgs.Cursor)
+		if err != nil {
+			return Response{
+				Success: false,
+				Error:   err.Error(),
+			}
+		}
+		filter.After = after
+		if filter.Limit > 0 {
+			filter.Limit++ // one extra row tells us whether there is a next page
+		}
+	}
 	
 	// Normalize status: treat "" or "all" as unset (no filter)
 	if listArgs.Status != "" && listArgs.Status != "all" {
@@ -1118,6 +1169,8 @@ func (s *Server) handleList(req *Request) Response {
 	if len(labelsAny) > 0 {
 		filter.LabelsAny = labelsAny
 	}
+	filter.LabelGlobs = util.NormalizeLabels(listArgs.LabelGlobs)
+	filter.NotLabels = util.NormalizeLabels(listArgs.NotLabels)
 	if len(listArgs.IDs) > 0 {
 		ids := util.NormalizeLabels(listArgs.IDs)
 		if len(ids) > 0 {
@@ -1129,6 +1182,7 @@ func (s *Server) handleList(req *Request) Response {
 	filter.TitleContains = listArgs.TitleContains
 	filter.DescriptionContains = listArgs.DescriptionContains
 	filter.NotesContains = listArgs.NotesContains
+	filter.TitleRegex = listArgs.TitleRegex
 	
 	// Date ranges - use parseTimeRPC helper for flexible formats
 	if listArgs.CreatedAfter != "" {
@@ -1292,6 +1346,36 @@ func (s *Server) handleList(req *Request) Response {
 	}
 
 	ctx := s.reqCtx(req)
+
+	// Query expression (bd query)
+	if listArgs.Expr != "" {
+		expr, err := query.Parse(listArgs.Expr, query.Options{
+			Saved: query.ConfigLookup(func(key string) (string, error) {
+				return store.GetConfig(ctx, key)
+			}),
+		})
+		if err != nil {
+			return Response{
+				Success: false,
+				Error:   err.Error(),
+			}
+		}
+		filter.Query = expr
+	}
+	if listArgs.CountOnly {
+		counts, err := store.CountIssues(ctx, listArgs.Query, filter, listArgs.GroupBy)
+		if err != nil {
+			return Response{
+				Success: false,
+				Error:   fmt.Sprintf("failed to count issues: %v", err),
+			}
+		}
+		data, _ := json.Marshal(counts)
+		return Response{
+			Success: true,
+			Data:    data,
+		}
+	}
 	issues, err := store.SearchIssues(ctx, listArgs.Query, filter)
 	if err != nil {
 		return Response{
@@ -1300,6 +1384,11 @@ func (s *Server) handleList(req *Request) Response {
 		}
 	}
 
+	var nextCursor string
+	if listArgs.Paginate {
+		issues, nextCursor = types.Paginate(issues, listArgs.Limit, types.CursorFor)
+	}
+
 	// Populate labels for each issue
 	for _, issue := range issues {
 		labels, _ := store.GetLabels(ctx, issue.ID)
@@ -1327,7 +1416,12 @@ func (s *Server) handleList(req *Request) Response {
 		}
 	}
 
-	data, _ := json.Marshal(issuesWithCounts)
+	var data []byte
+	if listArgs.Paginate {
+		data, _ = json.Marshal(types.IssuePage[*types.IssueWithCounts]{Issues: issuesWithCounts, NextCursor: nextCursor})
+	} else {
+		data, _ = json.Marshal(issuesWithCounts)
+	}
 	return Response{
 		Success: true,
 		Data:    data,
@@ -1463,7 +1557,7 @@ func (s *Server) handleCount(req *Request) Response {
 	filter.PriorityMax = countArgs.PriorityMax
 
 	ctx := s.reqCtx(req)
-	issues, err := store.SearchIssues(ctx, countArgs.Query, filter)
+	counts, err := store.CountIssues(ctx, countArgs.Query, filter, countArgs.GroupBy)
 	if err != nil {
 		return Response{
 			Success: false,
@@ -1476,90 +1570,14 @@ func (s *Server) handleCount(req *Request) Response {
 		type CountResult struct {
 			Count int `json:"count"`
 		}
-		data, _ := json.Marshal(CountResult{Count: len(issues)})
+		data, _ := json.Marshal(CountResult{Count: counts.Total})
 		return Response{
 			Success: true,
 			Data:    data,
 		}
 	}
 
-	// Group by the specified field
-	type GroupCount struct {
-		Group string `json:"group"`
-		Count int    `json:"count"`
-	}
-
-	counts := make(map[string]int)
-
-	// For label grouping, fetch all labels in one query to avoid N+1
-	var labelsMap map[string][]string
-	if countArgs.GroupBy == "label" {
-		issueIDs := make([]string, len(issues))
-		for i, issue := range issues {
-			issueIDs[i] = issue.ID
-		}
-		var err error
-		labelsMap, err = store.GetLabelsForIssues(ctx, issueIDs)
-		if err != nil {
-			return Response{
-				Success: false,
-				Error:   fmt.Sprintf("failed to get labels: %v", err),
-			}
-		}
-	}
-
-	for _, issue := range issues {
-		var groupKey string
-		switch countArgs.GroupBy {
-		case "status":
-			groupKey = string(issue.Status)
-		case "priority":
-			groupKey = fmt.Sprintf("P%d", issue.Priority)
-		case "type":
-			groupKey = string(issue.IssueType)
-		case "assignee":
-			if issue.Assignee == "" {
-				groupKey = "(unassigned)"
-			} else {
-				groupKey = issue.Assignee
-			}
-		case "label":
-			// For labels, count each label separately
-			labels := labelsMap[issue.ID]
-			if len(labels) > 0 {
-				for _, label := range labels {
-					counts[label]++
-				}
-				continue
-			} else {
-				groupKey = "(no labels)"
-			}
-		default:
-			return Response{
-				Success: false,
-				Error:   fmt.Sprintf("invalid group_by value: %s (must be one of: status, priority, type, assignee, label)", countArgs.GroupBy),
-			}
-		}
-		counts[groupKey]++
-	}
-
-	// Convert map to sorted slice
-	groups := make([]GroupCount, 0, len(counts))
-	for group, count := range counts {
-		groups = append(groups, GroupCount{Group: group, Count: count})
-	}
-
-	type GroupedCountResult struct {
-		Total  int          `json:"total"`
-		Groups []GroupCount `json:"groups"`
-	}
-
-	result := GroupedCountResult{
-		Total:  len(issues),
-		Groups: groups,
-	}
-
-	data, _ := json.Marshal(result)
+	data, _ := json.Marshal(counts)
 	return Response{
 		Success: true,
 		Data:    data,
@@ -1657,8 +1675,15 @@ func (s *Server) handleShow(req *Request) Response {
 		}
 	}
 
-	// Fetch comments
+	// Fetch comments, typed external refs and security advisory
 	comments, _ := store.GetIssueComments(ctx, issue.ID)
+	_ = refs.Populate(ctx, store, []*types.Issue{issue})
+	_ = advisory.Populate(ctx, store, []*types.Issue{issue})
+	_ = repro.Populate(ctx, store, []*types.Issue{issue})
+	_ = votes.Populate(ctx, store, []*types.Issue{issue})
+	_ = cc.Populate(ctx, store, []*types.Issue{issue})
+	_ = visibility.Populate(ctx, store, []*types.Issue{issue})
+	_ = goals.Populate(ctx, store, []*types.Issue{issue})
 
 	// Create detailed response with related data
 	details := &types.IssueDetails{
@@ -1715,6 +1740,20 @@ func (s *Server) handleReady(req *Request) Response {
 		wf.MolType = &molType
 	}
 
+	if readyArgs.Paginate {
+		after, err := types.DecodeCursor(readyArgs.Cursor)
+		if err != nil {
+			return Response{
+				Success: false,
+				Error:   err.Error(),
+			}
+		}
+		wf.ResumeAfter(after, time.Now())
+		if wf.Limit > 0 {
+			wf.Limit++ // one extra row tells us whether there is a next page
+		}
+	}
+
 	ctx := s.reqCtx(req)
 	issues, err := store.GetReadyWork(ctx, wf)
 	if err != nil {
@@ -1724,7 +1763,17 @@ func (s *Server) handleReady(req *Request) Response {
 		}
 	}
 
-	data, _ := json.Marshal(issues)
+	var data []byte
+	if readyArgs.Paginate {
+		page := types.IssuePage[*types.Issue]{}
+		page.Issues, page.NextCursor = types.Paginate(issues, readyArgs.Limit, wf.PageKey)
+		if page.Issues == nil {
+			page.Issues = []*types.Issue{}
+		}
+		data, _ = json.Marshal(page)
+	} else {
+		data, _ = json.Marshal(issues)
+	}
 	return Response{
 		Success: true,
 		Data:    data,
@@ -1833,6 +1882,43 @@ func (s *Server) handleStats(req *Request) Response {
 	}
 }
 
+// handlePrompt serves the 'bd prompt' summary. Shell prompts call it on
+// every render, so it is served from the read cache (see server_cache.go).
+func (s *Server) handlePrompt(req *Request) Response {
+	var promptArgs PromptArgs
+	if len(req.Args) > 0 {
+		if err := json.Unmarshal(req.Args, &promptArgs); err != nil {
+			return Response{
+				Success: false,
+				Error:   fmt.Sprintf("invalid prompt args: %v", err),
+			}
+		}
+	}
+
+	store := s.storage
+	if store == nil {
+		return Response{
+			Success: false,
+			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'bd daemon' in your project)",
+		}
+	}
+
+	ctx := s.reqCtx(req)
+	summary, err := storage.Summarize(ctx, store, promptArgs.Assignee)
+	if err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to summarize: %v", err),
+		}
+	}
+
+	data, _ := json.Marshal(summary)
+	return Response{
+		Success: true,
+		Data:    data,
+	}
+}
+
 func (s *Server) handleEpicStatus(req *Request) Response {
 	var epicArgs EpicStatusArgs
 	if err := json.Unmarshal(req.Args, &epicArgs); err != nil {
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/types"
 )
 
@@ -697,3 +698,89 @@ func compareTimePtr(t *testing.T, name string, direct, daemon *time.Time) bool {
 	}
 	return true
 }
+
+// TestPromptCacheInvalidatedByMutation verifies that the cached prompt
+// summary is dropped when an issue changes through the daemon.
+func TestPromptCacheInvalidatedByMutation(t *testing.T) {
+	_, client, _, cleanup := setupTestServerWithStore(t)
+	defer cleanup()
+
+	prompt := func() types.PromptSummary {
+		t.Helper()
+		resp, err := client.Prompt(&PromptArgs{})
+		if err != nil {
+			t.Fatalf("Prompt failed: %v", err)
+		}
+		var summary types.PromptSummary
+		if err := json.Unmarshal(resp.Data, &summary); err != nil {
+			t.Fatalf("Failed to unmarshal summary: %v", err)
+		}
+		return summary
+	}
+
+	resp, err := client.Create(&CreateArgs{Title: "First", IssueType: "task", Priority: 2})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var first types.Issue
+	if err := json.Unmarshal(resp.Data, &first); err != nil {
+		t.Fatalf("Failed to unmarshal issue: %v", err)
+	}
+	if got := prompt(); got.Ready != 1 || len(got.InProgress) != 0 {
+		t.Fatalf("summary = %+v, want 1 ready", got)
+	}
+
+	status := string(types.StatusInProgress)
+	if _, err := client.Update(&UpdateArgs{ID: first.ID, Status: &status}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got := prompt(); len(got.InProgress) != 1 || got.InProgress[0] != first.ID {
+		t.Errorf("summary after update = %+v, want %s in progress", got, first.ID)
+	}
+}
+
+// TestValidationRulesThroughDaemon verifies that creates and updates sent to
+// the daemon are held to the validation rules, and that an update is
+// checked as a whole rather than field by field.
+func TestValidationRulesThroughDaemon(t *testing.T) {
+	_, client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	if err := config.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	config.Set("validation.title-min-length", 10)
+	config.Set("validation.required-fields.bug", []string{"labels"})
+	t.Cleanup(func() {
+		config.Set("validation.title-min-length", 0)
+		config.Set("validation.required-fields.bug", []string{})
+	})
+
+	if _, err := client.Create(&CreateArgs{Title: "Short", IssueType: "task", Priority: 2}); err == nil {
+		t.Error("create with a short title succeeded, want it refused")
+	}
+	if _, err := client.Create(&CreateArgs{Title: "Short", IssueType: "task", Priority: 2, Force: true}); err != nil {
+		t.Errorf("forced create failed: %v", err)
+	}
+
+	resp, err := client.Create(&CreateArgs{Title: "Login redirects forever", IssueType: "task", Priority: 2})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(resp.Data, &issue); err != nil {
+		t.Fatal(err)
+	}
+
+	bug := "bug"
+	if _, err := client.Update(&UpdateArgs{ID: issue.ID, IssueType: &bug}); err == nil {
+		t.Error("retyping to bug without labels succeeded, want it refused")
+	}
+	// The label arrives in the same update as the type change
+	if _, err := client.Update(&UpdateArgs{ID: issue.ID, IssueType: &bug, SetLabels: []string{"auth"}}); err != nil {
+		t.Errorf("retyping to bug with a label failed: %v", err)
+	}
+	if _, err := client.Update(&UpdateArgs{ID: issue.ID, RemoveLabels: []string{"auth"}}); err == nil {
+		t.Error("removing the bug's only label succeeded, want it refused")
+	}
+}
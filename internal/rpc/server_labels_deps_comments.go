@@ -230,12 +230,13 @@ func (s *Server) handleBatch(req *Request) Response {
 
 	for _, op := range batchArgs.Operations {
 		subReq := &Request{
-			Operation:     op.Operation,
-			Args:          op.Args,
-			Actor:         req.Actor,
-			RequestID:     req.RequestID,
-			Cwd:           req.Cwd,           // Pass through context
-			ClientVersion: req.ClientVersion, // Pass through version for compatibility checks
+			Operation:       op.Operation,
+			Args:            op.Args,
+			Actor:           req.Actor,
+			RequestID:       req.RequestID,
+			Cwd:             req.Cwd,           // Pass through context
+			ClientVersion:   req.ClientVersion, // Pass through version for compatibility checks
+			ProtocolVersion: req.ProtocolVersion,
 		}
 
 		resp := s.handleRequest(subReq)
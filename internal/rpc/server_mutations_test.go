@@ -1457,3 +1457,49 @@ func TestHandleClose_BlockerCheck_ClosedBlocker(t *testing.T) {
 		t.Errorf("expected close to succeed after blocker was closed, got error: %s", closeBlockedResp.Error)
 	}
 }
+
+func TestSubscribe(t *testing.T) {
+	store := memory.New("/tmp/test.jsonl")
+	server := NewServer("/tmp/test.sock", store, "/tmp", "/tmp/test.db")
+
+	events, cancel := server.Subscribe()
+	server.emitMutation(MutationCreate, "bd-1", "First", "")
+	server.emitMutation(MutationUpdate, "bd-1", "First", "alice")
+
+	for _, want := range []string{MutationCreate, MutationUpdate} {
+		select {
+		case e := <-events:
+			if e.Type != want || e.IssueID != "bd-1" {
+				t.Errorf("got %s %s, want %s bd-1", e.Type, e.IssueID, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no %s event", want)
+		}
+	}
+
+	cancel()
+	cancel() // Idempotent
+	server.emitMutation(MutationDelete, "bd-1", "", "")
+	if _, ok := <-events; ok {
+		t.Error("channel still open after cancel")
+	}
+}
+
+func TestExecute(t *testing.T) {
+	store := memory.New("/tmp/test.jsonl")
+	server := NewServer("/tmp/test.sock", store, "/tmp", "/tmp/test.db")
+
+	args, _ := json.Marshal(CreateArgs{Title: "From the API", IssueType: "task", Priority: 2})
+	resp := server.Execute(&Request{Operation: OpCreate, Args: args, Actor: "triage-bot"})
+	if !resp.Success {
+		t.Fatalf("Execute failed: %s", resp.Error)
+	}
+	select {
+	case event := <-server.MutationChan():
+		if event.Type != MutationCreate || event.Title != "From the API" {
+			t.Errorf("event = %+v, want a create event", event)
+		}
+	default:
+		t.Error("Execute emitted no mutation event")
+	}
+}
@@ -15,8 +15,35 @@ import (
 	"golang.org/x/mod/semver"
 )
 
+// VersionError reports an incompatible client and daemon, and which of them
+// needs upgrading.
+type VersionError struct {
+	Upgrade string // UpgradeDaemon or UpgradeClient
+	Message string
+}
+
+func (e *VersionError) Error() string { return e.Message }
+
+// checkCompatibility validates the client's protocol and version against the
+// daemon's. The protocol is negotiated first: the daemon serves clients
+// speaking MinProtocolVersion through ProtocolVersion. Returns a
+// *VersionError if they are incompatible.
+func (s *Server) checkCompatibility(req *Request) error {
+	switch {
+	case req.ProtocolVersion > ProtocolVersion:
+		return &VersionError{Upgrade: UpgradeDaemon, Message: fmt.Sprintf(
+			"protocol mismatch: client v%s speaks RPC protocol %d but daemon v%s only supports up to %d. Restart the daemon with the new binary: bd daemons killall",
+			req.ClientVersion, req.ProtocolVersion, ServerVersion, ProtocolVersion)}
+	case req.ProtocolVersion != 0 && req.ProtocolVersion < MinProtocolVersion:
+		return &VersionError{Upgrade: UpgradeClient, Message: fmt.Sprintf(
+			"protocol mismatch: client v%s speaks RPC protocol %d but daemon v%s requires at least %d. Upgrade the bd CLI",
+			req.ClientVersion, req.ProtocolVersion, ServerVersion, MinProtocolVersion)}
+	}
+	return s.checkVersionCompatibility(req.ClientVersion)
+}
+
 // checkVersionCompatibility validates client version against server version
-// Returns error if versions are incompatible
+// Returns a *VersionError if versions are incompatible
 func (s *Server) checkVersionCompatibility(clientVersion string) error {
 	// Allow empty client version (old clients before this feature)
 	if clientVersion == "" {
@@ -48,12 +75,12 @@ func (s *Server) checkVersionCompatibility(clientVersion string) error {
 		cmp := semver.Compare(serverVer, clientVer)
 		if cmp < 0 {
 			// Daemon is older - needs upgrade
-			return fmt.Errorf("incompatible major versions: client %s, daemon %s. Daemon is older; upgrade and restart daemon: 'bd daemon stop && bd daemon start'",
-				clientVersion, ServerVersion)
+			return &VersionError{Upgrade: UpgradeDaemon, Message: fmt.Sprintf("incompatible major versions: client %s, daemon %s. Daemon is older; upgrade and restart daemon: 'bd daemon stop && bd daemon start'",
+				clientVersion, ServerVersion)}
 		}
 		// Daemon is newer - client needs upgrade
-		return fmt.Errorf("incompatible major versions: client %s, daemon %s. Client is older; upgrade the bd CLI to match the daemon's major version",
-			clientVersion, ServerVersion)
+		return &VersionError{Upgrade: UpgradeClient, Message: fmt.Sprintf("incompatible major versions: client %s, daemon %s. Client is older; upgrade the bd CLI to match the daemon's major version",
+			clientVersion, ServerVersion)}
 	}
 
 	// Compare full versions - daemon must be >= client (strict minor version gating)
@@ -67,13 +94,13 @@ func (s *Server) checkVersionCompatibility(clientVersion string) error {
 		
 		if serverMinor != clientMinor {
 			// Minor version mismatch - schema may be incompatible
-			return fmt.Errorf("version mismatch: client v%s requires daemon upgrade (daemon is v%s). The client may expect schema changes not present in this daemon version. Run: bd daemons killall",
-				clientVersion, ServerVersion)
+			return &VersionError{Upgrade: UpgradeDaemon, Message: fmt.Sprintf("version mismatch: client v%s requires daemon upgrade (daemon is v%s). The client may expect schema changes not present in this daemon version. Run: bd daemons killall",
+				clientVersion, ServerVersion)}
 		}
 		
 		// Patch version difference - usually safe but warn
-		return fmt.Errorf("version mismatch: daemon v%s is older than client v%s. Upgrade and restart daemon: bd daemons killall",
-			ServerVersion, clientVersion)
+		return &VersionError{Upgrade: UpgradeDaemon, Message: fmt.Sprintf("version mismatch: daemon v%s is older than client v%s. Upgrade and restart daemon: bd daemons killall",
+			ServerVersion, clientVersion)}
 	}
 
 	// Client is same version or older - OK (daemon supports backward compat within major version)
@@ -137,7 +164,7 @@ func (s *Server) handleRequest(req *Request) Response {
 
 	// Check version compatibility (skip for ping/health to allow version checks)
 	if req.Operation != OpPing && req.Operation != OpHealth {
-		if err := s.checkVersionCompatibility(req.ClientVersion); err != nil {
+		if err := s.checkCompatibility(req); err != nil {
 			s.metrics.RecordError(req.Operation)
 			return Response{
 				Success: false,
@@ -160,6 +187,18 @@ func (s *Server) handleRequest(req *Request) Response {
 	// Update last activity timestamp
 	s.lastActivityTime.Store(time.Now())
 
+	// Serve hot reads from the cache; any other write drops it afterwards
+	var cacheGen uint64
+	if cacheableOps[req.Operation] {
+		cached, gen, ok := s.cachedResponse(req)
+		if ok {
+			return cached
+		}
+		cacheGen = gen
+	} else if !readOnlyOps[req.Operation] {
+		defer s.invalidateReadCache()
+	}
+
 	var resp Response
 	switch req.Operation {
 	case OpPing:
@@ -227,6 +266,8 @@ func (s *Server) handleRequest(req *Request) Response {
 		resp = s.handleGetWorkerStatus(req)
 	case OpGetConfig:
 		resp = s.handleGetConfig(req)
+	case OpPrompt:
+		resp = s.handlePrompt(req)
 	case OpMolStale:
 		resp = s.handleMolStale(req)
 	case OpShutdown:
@@ -253,6 +294,8 @@ func (s *Server) handleRequest(req *Request) Response {
 	// Record error if request failed
 	if !resp.Success {
 		s.metrics.RecordError(req.Operation)
+	} else if cacheableOps[req.Operation] {
+		s.storeResponse(req, cacheGen, resp)
 	}
 
 	return resp
@@ -262,9 +305,17 @@ func (s *Server) handleRequest(req *Request) Response {
 
 // reqCtx returns a context with the server's request timeout applied.
 // This prevents request handlers from hanging indefinitely if database
-// operations or other internal calls stall (GH#bd-p76kv).
-func (s *Server) reqCtx(_ *Request) context.Context {
-	ctx, _ := context.WithTimeout(context.Background(), s.requestTimeout)
+// operations or other internal calls stall (GH#bd-p76kv). A shorter client
+// deadline (bd --timeout) takes precedence, so work the client has given up
+// on is abandoned rather than finished for nobody.
+func (s *Server) reqCtx(req *Request) context.Context {
+	timeout := s.requestTimeout
+	if req != nil && req.TimeoutMs > 0 {
+		if client := time.Duration(req.TimeoutMs) * time.Millisecond; timeout <= 0 || client < timeout {
+			timeout = client
+		}
+	}
+	ctx, _ := context.WithTimeout(context.Background(), timeout)
 	return ctx
 }
 
@@ -362,11 +413,13 @@ func (s *Server) handleHealth(req *Request) Response {
 		status = "degraded"
 	}
 
-	// Check version compatibility
+	// Check protocol and version compatibility
 	compatible := true
-	if req.ClientVersion != "" {
-		if err := s.checkVersionCompatibility(req.ClientVersion); err != nil {
-			compatible = false
+	upgrade := ""
+	if err := s.checkCompatibility(req); err != nil {
+		compatible = false
+		if verr, ok := err.(*VersionError); ok {
+			upgrade = verr.Upgrade
 		}
 	}
 
@@ -375,6 +428,9 @@ func (s *Server) handleHealth(req *Request) Response {
 		Version:        ServerVersion,
 		ClientVersion:  req.ClientVersion,
 		Compatible:     compatible,
+		Upgrade:        upgrade,
+		Protocol:       ProtocolVersion,
+		MinProtocol:    MinProtocolVersion,
 		Uptime:         time.Since(s.startTime).Seconds(),
 		DBResponseTime: dbResponseMs,
 		ActiveConns:    atomic.LoadInt32(&s.activeConns),
@@ -398,6 +454,7 @@ func (s *Server) handleMetrics(_ *Request) Response {
 	snapshot := s.metrics.Snapshot(
 		int(atomic.LoadInt32(&s.activeConns)),
 	)
+	snapshot.CacheHits, snapshot.CacheMisses, snapshot.CacheEntries = s.readCacheStats()
 
 	data, _ := json.Marshal(snapshot)
 	return Response{
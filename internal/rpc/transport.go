@@ -0,0 +1,240 @@
+package rpc
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TransportEnv forces a single RPC transport instead of automatic selection.
+const TransportEnv = "BD_RPC_TRANSPORT"
+
+// RPC transports. The daemon tries the platform's transports in order
+// (unix then tcp, or pipe then tcp on Windows) and records the one it picked
+// at the socket path, so clients never need to be told.
+const (
+	TransportUnix = "unix"
+	TransportPipe = "pipe"
+	TransportTCP  = "tcp"
+)
+
+// tokenPrefix starts the first line a client sends over a TCP transport.
+// Loopback TCP is reachable by every local user, so the daemon only serves
+// connections that present the token from the owner-only endpoint file.
+const tokenPrefix = "BEADS-TOKEN "
+
+// handshakeTimeout bounds how long an accepted TCP connection may take to
+// present its token.
+const handshakeTimeout = 5 * time.Second
+
+// endpointInfo is written to the socket path by transports that cannot live
+// there themselves (named pipes and TCP).
+type endpointInfo struct {
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Token   string `json:"token,omitempty"`
+}
+
+// rpcTransports returns the transports to try, in order.
+func rpcTransports() []string {
+	if t := strings.ToLower(strings.TrimSpace(os.Getenv(TransportEnv))); t != "" {
+		return []string{t}
+	}
+	return defaultTransports
+}
+
+// listenRPC starts the first transport in rpcTransports that works.
+func listenRPC(socketPath string) (net.Listener, error) {
+	var errs []error
+	for _, transport := range rpcTransports() {
+		listener, err := listenTransport(socketPath, transport)
+		if err == nil {
+			return listener, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", transport, err))
+		_ = os.Remove(socketPath)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func listenTransport(socketPath, transport string) (net.Listener, error) {
+	switch transport {
+	case TransportUnix:
+		return listenUnix(socketPath)
+	case TransportPipe:
+		return listenPipe(socketPath)
+	case TransportTCP:
+		return listenTCP(socketPath)
+	default:
+		return nil, fmt.Errorf("unknown RPC transport %q (valid: unix, pipe, tcp)", transport)
+	}
+}
+
+// dialRPC connects to whichever transport the daemon recorded at socketPath.
+func dialRPC(socketPath string, timeout time.Duration) (net.Conn, error) {
+	if fi, err := os.Stat(socketPath); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		return net.DialTimeout("unix", socketPath, timeout)
+	}
+	info, err := readEndpoint(socketPath)
+	if err != nil {
+		return nil, err
+	}
+	return dialEndpoint(info, timeout)
+}
+
+func endpointExists(socketPath string) bool {
+	_, err := os.Stat(socketPath)
+	return err == nil
+}
+
+func dialEndpoint(info endpointInfo, timeout time.Duration) (net.Conn, error) {
+	switch info.Network {
+	case TransportPipe:
+		return dialPipe(info.Address, timeout)
+	case TransportTCP, "":
+		conn, err := net.DialTimeout("tcp", info.Address, timeout)
+		if err != nil {
+			return nil, err
+		}
+		// Endpoint files from older daemons carry no token
+		if info.Token != "" {
+			_ = conn.SetWriteDeadline(time.Now().Add(handshakeTimeout))
+			if _, err := conn.Write([]byte(tokenPrefix + info.Token + "\n")); err != nil {
+				_ = conn.Close()
+				return nil, err
+			}
+			_ = conn.SetWriteDeadline(time.Time{})
+		}
+		return conn, nil
+	case TransportUnix:
+		return net.DialTimeout("unix", info.Address, timeout)
+	default:
+		return nil, fmt.Errorf("invalid RPC endpoint: unknown network %q", info.Network)
+	}
+}
+
+func writeEndpoint(socketPath string, info endpointInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(socketPath, data, 0o600)
+}
+
+func readEndpoint(socketPath string) (endpointInfo, error) {
+	var info endpointInfo
+	data, err := os.ReadFile(socketPath) // #nosec G304 - daemon-owned endpoint file
+	if err != nil {
+		return info, err
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return info, fmt.Errorf("invalid RPC endpoint %s: %w", socketPath, err)
+	}
+	if info.Address == "" {
+		return info, errors.New("invalid RPC endpoint: missing address")
+	}
+	return info, nil
+}
+
+// listenTCP listens on a random loopback port and records the address and a
+// fresh token at socketPath.
+func listenTCP(socketPath string) (net.Listener, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(raw)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	info := endpointInfo{Network: TransportTCP, Address: listener.Addr().String(), Token: token}
+	if err := writeEndpoint(socketPath, info); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return &tokenListener{Listener: listener, token: token}, nil
+}
+
+// tokenListener hands out connections that must present the token before any
+// request is read. The check happens on the first Read so a slow or silent
+// client cannot stall Accept for everyone else.
+type tokenListener struct {
+	net.Listener
+	token string
+}
+
+func (l *tokenListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &tokenConn{Conn: conn, token: l.token}, nil
+}
+
+type tokenConn struct {
+	net.Conn
+	token  string
+	once   sync.Once
+	reader *bufio.Reader
+	err    error
+
+	mu       sync.Mutex
+	deadline time.Time // caller's read deadline, restored after the handshake
+}
+
+func (c *tokenConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.deadline = t
+	c.mu.Unlock()
+	return c.Conn.SetReadDeadline(t)
+}
+
+func (c *tokenConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.deadline = t
+	c.mu.Unlock()
+	return c.Conn.SetDeadline(t)
+}
+
+func (c *tokenConn) Read(p []byte) (int, error) {
+	c.once.Do(c.handshake)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(p)
+}
+
+func (c *tokenConn) handshake() {
+	c.mu.Lock()
+	deadline := c.deadline
+	c.mu.Unlock()
+	handshakeDeadline := time.Now().Add(handshakeTimeout)
+	if !deadline.IsZero() && deadline.Before(handshakeDeadline) {
+		handshakeDeadline = deadline
+	}
+	_ = c.Conn.SetReadDeadline(handshakeDeadline)
+	c.reader = bufio.NewReader(c.Conn)
+	line, err := c.reader.ReadString('\n')
+	_ = c.Conn.SetReadDeadline(deadline)
+	if err != nil {
+		c.err = fmt.Errorf("RPC token handshake: %w", err)
+		_ = c.Close()
+		return
+	}
+	got := strings.TrimSuffix(strings.TrimPrefix(line, tokenPrefix), "\n")
+	if !strings.HasPrefix(line, tokenPrefix) || subtle.ConstantTimeCompare([]byte(got), []byte(c.token)) != 1 {
+		c.err = errors.New("RPC token handshake: invalid token")
+		_ = c.Close()
+	}
+}
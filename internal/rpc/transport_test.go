@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTransportMatrix runs a real server over every transport the platform
+// supports and checks a client finds it through the socket path alone.
+func TestTransportMatrix(t *testing.T) {
+	for _, transport := range defaultTransports {
+		t.Run(transport, func(t *testing.T) {
+			t.Setenv(TransportEnv, transport)
+
+			tmpDir := t.TempDir()
+			dbPath := filepath.Join(tmpDir, ".beads", "beads.db")
+			socketPath := newTestSocketPath(t)
+			store := newTestStore(t, dbPath)
+			defer store.Close()
+
+			server := NewServer(socketPath, store, tmpDir, dbPath)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() { _ = server.Start(ctx) }()
+			select {
+			case <-server.WaitReady():
+			case <-time.After(5 * time.Second):
+				t.Fatal("server did not start")
+			}
+			defer func() { _ = server.Stop() }()
+
+			fi, err := os.Stat(socketPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isSocket := fi.Mode()&os.ModeSocket != 0; isSocket != (transport == TransportUnix) {
+				t.Errorf("socket path mode = %v for transport %s", fi.Mode(), transport)
+			}
+			if transport != TransportUnix {
+				info, err := readEndpoint(socketPath)
+				if err != nil || info.Network != transport {
+					t.Fatalf("endpoint = %+v, %v", info, err)
+				}
+			}
+
+			client, err := TryConnect(socketPath)
+			if err != nil || client == nil {
+				t.Fatalf("TryConnect = %v, %v", client, err)
+			}
+			defer client.Close()
+			health, err := client.Health()
+			if err != nil || health.Status == "unhealthy" {
+				t.Fatalf("Health = %+v, %v", health, err)
+			}
+		})
+	}
+}
+
+func TestTCPTransportRequiresToken(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "bd.sock")
+	listener, err := listenTCP(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	info, err := readEndpoint(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Network != TransportTCP || len(info.Token) != 64 || !strings.HasPrefix(info.Address, "127.0.0.1:") {
+		t.Fatalf("endpoint = %+v", info)
+	}
+	if fi, err := os.Stat(socketPath); err == nil && fi.Mode().Perm()&0o077 != 0 && os.PathSeparator == '/' {
+		t.Errorf("endpoint file mode = %v, want owner-only", fi.Mode().Perm())
+	}
+
+	// Echo the first line each accepted connection sends
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err == nil {
+					_, _ = conn.Write([]byte(line))
+				}
+			}(conn)
+		}
+	}()
+
+	roundTrip := func(info endpointInfo) (string, error) {
+		conn, err := dialEndpoint(info, time.Second)
+		if err != nil {
+			return "", err
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+		if _, err := conn.Write([]byte("ping\n")); err != nil {
+			return "", err
+		}
+		return bufio.NewReader(conn).ReadString('\n')
+	}
+
+	if got, err := roundTrip(info); err != nil || got != "ping\n" {
+		t.Errorf("with token: got %q, %v", got, err)
+	}
+	for name, bad := range map[string]endpointInfo{
+		"wrong token": {Network: TransportTCP, Address: info.Address, Token: strings.Repeat("0", 64)},
+		"no token":    {Network: TransportTCP, Address: info.Address},
+	} {
+		if got, err := roundTrip(bad); err == nil && got != "" {
+			t.Errorf("%s: got %q, want the connection refused", name, got)
+		}
+	}
+}
+
+func TestListenRPCFallsBack(t *testing.T) {
+	// A socket path inside a missing directory defeats every transport; the
+	// error should name each one that was tried.
+	t.Setenv(TransportEnv, "")
+	socketPath := filepath.Join(t.TempDir(), "missing", "bd.sock")
+	if _, err := listenRPC(socketPath); err == nil {
+		t.Fatal("listenRPC succeeded without a directory")
+	} else {
+		for _, transport := range defaultTransports {
+			if !strings.Contains(err.Error(), transport+":") {
+				t.Errorf("error %q does not mention %s", err, transport)
+			}
+		}
+	}
+
+	t.Setenv(TransportEnv, "carrier-pigeon")
+	if _, err := listenRPC(filepath.Join(t.TempDir(), "bd.sock")); err == nil || !strings.Contains(err.Error(), "unknown RPC transport") {
+		t.Errorf("listenRPC with unknown transport = %v", err)
+	}
+}
+
+func TestReadEndpointErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"garbage": "not json", "no address": `{"network":"tcp"}`} {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "-"))
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readEndpoint(path); err == nil {
+			t.Errorf("%s: readEndpoint succeeded", name)
+		}
+	}
+	data, _ := json.Marshal(endpointInfo{Network: "smoke-signal", Address: "x"})
+	path := filepath.Join(dir, "unknown")
+	_ = os.WriteFile(path, data, 0o600)
+	if _, err := dialRPC(path, time.Second); err == nil {
+		t.Error("dialRPC succeeded for an unknown network")
+	}
+}
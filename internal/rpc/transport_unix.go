@@ -3,20 +3,23 @@
 package rpc
 
 import (
+	"errors"
 	"net"
-	"os"
 	"time"
 )
 
-func listenRPC(socketPath string) (net.Listener, error) {
+// defaultTransports falls back to loopback TCP when the socket cannot be
+// created, e.g. on filesystems without unix socket support.
+var defaultTransports = []string{TransportUnix, TransportTCP}
+
+func listenUnix(socketPath string) (net.Listener, error) {
 	return net.Listen("unix", socketPath)
 }
 
-func dialRPC(socketPath string, timeout time.Duration) (net.Conn, error) {
-	return net.DialTimeout("unix", socketPath, timeout)
+func listenPipe(string) (net.Listener, error) {
+	return nil, errors.New("named pipes are only available on Windows")
 }
 
-func endpointExists(socketPath string) bool {
-	_, err := os.Stat(socketPath)
-	return err == nil
+func dialPipe(string, time.Duration) (net.Conn, error) {
+	return nil, errors.New("named pipes are only available on Windows")
 }
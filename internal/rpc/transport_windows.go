@@ -3,67 +3,51 @@
 package rpc
 
 import (
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"net"
-	"os"
+	"path/filepath"
+	"strings"
 	"time"
-)
 
-type endpointInfo struct {
-	Network string `json:"network"`
-	Address string `json:"address"`
-}
+	"github.com/Microsoft/go-winio"
+)
 
-func listenRPC(socketPath string) (net.Listener, error) {
-	listener, err := net.Listen("tcp", "127.0.0.1:0")
-	if err != nil {
-		return nil, err
-	}
+// defaultTransports prefers a named pipe and falls back to loopback TCP with
+// a token where pipes are unavailable (some sandboxes and remote shells).
+var defaultTransports = []string{TransportPipe, TransportTCP}
 
-	info := endpointInfo{
-		Network: "tcp",
-		Address: listener.Addr().String(),
-	}
+// pipeSecurity grants access to SYSTEM and the pipe's owner only.
+const pipeSecurity = "D:P(A;;GA;;;SY)(A;;GA;;;OW)"
 
-	data, err := json.Marshal(info)
+// pipeName derives a stable per-workspace pipe name from the socket path.
+func pipeName(socketPath string) string {
+	abs, err := filepath.Abs(socketPath)
 	if err != nil {
-		listener.Close()
-		return nil, err
-	}
-
-	if err := os.WriteFile(socketPath, data, 0o600); err != nil {
-		listener.Close()
-		return nil, err
+		abs = socketPath
 	}
+	sum := sha256.Sum256([]byte(strings.ToLower(abs)))
+	return `\\.\pipe\beads-` + hex.EncodeToString(sum[:8])
+}
 
-	return listener, nil
+func listenUnix(string) (net.Listener, error) {
+	return nil, errors.New("unix sockets are not used on Windows (use pipe or tcp)")
 }
 
-func dialRPC(socketPath string, timeout time.Duration) (net.Conn, error) {
-	data, err := os.ReadFile(socketPath)
+func listenPipe(socketPath string) (net.Listener, error) {
+	name := pipeName(socketPath)
+	listener, err := winio.ListenPipe(name, &winio.PipeConfig{SecurityDescriptor: pipeSecurity})
 	if err != nil {
 		return nil, err
 	}
-
-	var info endpointInfo
-	if err := json.Unmarshal(data, &info); err != nil {
+	if err := writeEndpoint(socketPath, endpointInfo{Network: TransportPipe, Address: name}); err != nil {
+		_ = listener.Close()
 		return nil, err
 	}
-
-	if info.Address == "" {
-		return nil, errors.New("invalid RPC endpoint: missing address")
-	}
-
-	network := info.Network
-	if network == "" {
-		network = "tcp"
-	}
-
-	return net.DialTimeout(network, info.Address, timeout)
+	return listener, nil
 }
 
-func endpointExists(socketPath string) bool {
-	_, err := os.Stat(socketPath)
-	return err == nil
+func dialPipe(name string, timeout time.Duration) (net.Conn, error) {
+	return winio.DialPipe(name, &timeout)
 }
@@ -0,0 +1,233 @@
+package schedule
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ConfigPrefix is the config key prefix jobs are stored under, as
+// schedule.<id>.
+const ConfigPrefix = "schedule."
+
+// MaxRuns bounds the run history file; older runs are dropped.
+const MaxRuns = 500
+
+// MaxOutput bounds how much of a run's output is kept, from the end.
+const MaxOutput = 4096
+
+// DefaultTimeout is how long a job may run before it is killed.
+const DefaultTimeout = 30 * time.Minute
+
+// Job is a bd command run on a cron schedule.
+type Job struct {
+	ID        string    `json:"id"`
+	Cron      string    `json:"cron"`
+	Command   string    `json:"command"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Encode returns the config value a job is stored as.
+func (j Job) Encode() string {
+	data, _ := json.Marshal(struct {
+		Cron      string    `json:"cron"`
+		Command   string    `json:"command"`
+		CreatedAt time.Time `json:"created_at"`
+	}{j.Cron, j.Command, j.CreatedAt})
+	return string(data)
+}
+
+// Decode parses the config value of job id.
+func Decode(id, value string) (Job, error) {
+	job := Job{ID: id}
+	if err := json.Unmarshal([]byte(value), &job); err != nil {
+		return job, fmt.Errorf("job %s: %w", id, err)
+	}
+	job.ID = id
+	return job, nil
+}
+
+// Next returns the job's next run after t, or the zero time if its cron
+// expression is invalid or never matches.
+func (j Job) Next(t time.Time) time.Time {
+	c, err := ParseCron(j.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return c.Next(t)
+}
+
+// Due returns the jobs scheduled at least once in (since, now].
+func Due(jobs []Job, since, now time.Time) []Job {
+	var due []Job
+	for _, j := range jobs {
+		if next := j.Next(since); !next.IsZero() && !next.After(now) {
+			due = append(due, j)
+		}
+	}
+	return due
+}
+
+// Args splits a job's command into bd arguments, honoring single and double
+// quotes and backslash escapes. A leading "bd" is dropped.
+func Args(command string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range command {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", command)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	if len(args) > 0 && args[0] == "bd" {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+	return args, nil
+}
+
+// Run is one execution of a job.
+type Run struct {
+	Job      string    `json:"job"`
+	Command  string    `json:"command"`
+	Started  time.Time `json:"started"`
+	Duration float64   `json:"duration_seconds"`
+	ExitCode int       `json:"exit_code"`
+	Output   string    `json:"output,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// OK reports whether the run succeeded.
+func (r Run) OK() bool {
+	return r.ExitCode == 0 && r.Error == ""
+}
+
+// Execute runs the job as exe (the bd binary) in dir and records the result.
+// The job is killed after timeout.
+func Execute(ctx context.Context, exe, dir string, job Job, timeout time.Duration) Run {
+	run := Run{Job: job.ID, Command: job.Command, Started: time.Now()}
+	args, err := Args(job.Command)
+	if err != nil {
+		run.ExitCode = -1
+		run.Error = err.Error()
+		return run
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, exe, args...) // #nosec G204 -- runs bd itself with the user's scheduled arguments
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err = cmd.Run()
+	run.Duration = time.Since(run.Started).Round(time.Millisecond).Seconds()
+	run.Output = tail(out.String(), MaxOutput)
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		run.ExitCode = -1
+		run.Error = fmt.Sprintf("timed out after %s", timeout)
+	case errors.As(err, &exitErr):
+		run.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		run.ExitCode = -1
+		run.Error = err.Error()
+	}
+	return run
+}
+
+func tail(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return "…" + s[len(s)-n:]
+}
+
+// AppendRun adds a run to the history file at path, keeping the last
+// MaxRuns entries.
+func AppendRun(path string, run Run) error {
+	runs, err := ReadRuns(path)
+	if err != nil {
+		return err
+	}
+	runs = append(runs, run)
+	if len(runs) > MaxRuns {
+		runs = runs[len(runs)-MaxRuns:]
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range runs {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadRuns returns the run history at path, oldest first. A missing file is
+// an empty history; lines that don't parse are skipped.
+func ReadRuns(path string) ([]Run, error) {
+	f, err := os.Open(path) // #nosec G304 -- run history in the .beads directory
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var runs []Run
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Run
+		if json.Unmarshal(scanner.Bytes(), &r) == nil {
+			runs = append(runs, r)
+		}
+	}
+	return runs, scanner.Err()
+}
@@ -0,0 +1,131 @@
+package schedule
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// Thursday
+	from := time.Date(2026, 3, 12, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 12, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 12, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * 1", time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2026, 3, 13, 9, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2026, 3, 13, 10, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 jun *", time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)},
+		// Day of month and day of week are alternatives when both are set
+		{"0 0 20 * 5", time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 12, 11, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.spec)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := c.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "*/0 * * * *", "5-1 * * * *", "* * * * funday"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	jobs := []Job{{ID: "hourly", Cron: "0 * * * *"}, {ID: "daily", Cron: "0 9 * * *"}, {ID: "bad", Cron: "nope"}}
+	since := time.Date(2026, 3, 12, 8, 59, 30, 0, time.UTC)
+	due := Due(jobs, since, since.Add(time.Minute))
+	if len(due) != 2 {
+		t.Fatalf("Due = %v, want hourly and daily", due)
+	}
+	if due := Due(jobs, since.Add(time.Minute), since.Add(2*time.Minute)); len(due) != 0 {
+		t.Errorf("Due = %v, want none", due)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"digest --since 1w", []string{"digest", "--since", "1w"}},
+		{"bd stale --days 30", []string{"stale", "--days", "30"}},
+		{`list --title "needs review"`, []string{"list", "--title", "needs review"}},
+		{`comment x 'it''s'`, []string{"comment", "x", "its"}},
+		{`export -o a\ b.jsonl`, []string{"export", "-o", "a b.jsonl"}},
+	}
+	for _, tt := range tests {
+		got, err := Args(tt.in)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Args(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "bd", `list "open`} {
+		if _, err := Args(in); err == nil {
+			t.Errorf("Args(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestJobEncodeDecode(t *testing.T) {
+	job := Job{ID: "weekly", Cron: "0 9 * * 1", Command: "digest", CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	got, err := Decode("weekly", job.Encode())
+	if err != nil || !reflect.DeepEqual(got, job) {
+		t.Errorf("Decode(Encode) = %+v, %v; want %+v", got, err, job)
+	}
+}
+
+func TestRunHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule-runs.log")
+	if runs, err := ReadRuns(path); err != nil || len(runs) != 0 {
+		t.Fatalf("ReadRuns(missing) = %v, %v", runs, err)
+	}
+	for i := 0; i < MaxRuns+3; i++ {
+		if err := AppendRun(path, Run{Job: "j", ExitCode: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runs, err := ReadRuns(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != MaxRuns || runs[0].ExitCode != 3 || runs[len(runs)-1].ExitCode != MaxRuns+2 {
+		t.Errorf("got %d runs from %d to %d", len(runs), runs[0].ExitCode, runs[len(runs)-1].ExitCode)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	// The test binary itself stands in for bd; an unknown flag makes it fail
+	run := Execute(context.Background(), exe, t.TempDir(), Job{ID: "x", Command: "-test.bogus"}, time.Minute)
+	if run.OK() || run.ExitCode == 0 || run.Output == "" {
+		t.Errorf("run = %+v, want a failure with output", run)
+	}
+	run = Execute(context.Background(), exe, t.TempDir(), Job{ID: "x", Command: "-test.run=^$"}, time.Minute)
+	if !run.OK() {
+		t.Errorf("run = %+v, want success", run)
+	}
+}
@@ -0,0 +1,238 @@
+// Package signing makes and checks detached signatures with the same keys
+// git signs commits with: SSH keys through ssh-keygen -Y, or OpenPGP keys
+// through gpg.
+//
+// Keys are configured with sign.format and sign.key, falling back to git's
+// gpg.format and user.signingkey. SSH signatures are checked against an
+// allowed signers file (sign.allowed-signers, else git's
+// gpg.ssh.allowedSignersFile); without one only the signature's integrity is
+// checked, not who made it.
+package signing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/config"
+)
+
+// Signature formats
+const (
+	FormatSSH = "ssh"
+	FormatGPG = "gpg"
+)
+
+// Namespace scopes SSH signatures so a signature made for beads can't be
+// passed off as a git commit or file signature, and vice versa.
+const Namespace = "beads"
+
+// ErrNoKey is returned by FromConfig when no signing key is configured.
+var ErrNoKey = errors.New("no signing key configured (set sign.key or git's user.signingkey)")
+
+// Key signs with one SSH or GPG key.
+type Key struct {
+	Format string // FormatSSH or FormatGPG
+	// ID is the SSH key file (private, or public with the key in ssh-agent)
+	// or the GPG key ID
+	ID string
+	// Signer names who signs: the principal (usually an email) an allowed
+	// signers file lists for an SSH key, or the GPG key ID
+	Signer string
+}
+
+// FromConfig returns the configured signing key.
+func FromConfig() (*Key, error) {
+	format := config.GetString("sign.format")
+	if format == "" {
+		format = gitConfig("gpg.format")
+	}
+	switch format {
+	case "", "openpgp", FormatGPG:
+		format = FormatGPG
+	case FormatSSH:
+	default:
+		return nil, fmt.Errorf("unsupported signature format %q (use ssh or gpg)", format)
+	}
+
+	id := config.GetString("sign.key")
+	if id == "" {
+		id = gitConfig("user.signingkey")
+	}
+	if id == "" {
+		return nil, ErrNoKey
+	}
+	if strings.HasPrefix(id, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			id = home + id[1:]
+		}
+	}
+
+	signer := id
+	if format == FormatSSH {
+		if signer = gitConfig("user.email"); signer == "" {
+			signer = config.GetString("actor")
+		}
+	}
+	return &Key{Format: format, ID: id, Signer: signer}, nil
+}
+
+// Name returns the signer recorded with signatures.
+func (k *Key) Name() string { return k.Signer }
+
+// Sign returns an armored detached signature of data.
+func (k *Key) Sign(data []byte) (string, error) {
+	var cmd *exec.Cmd
+	switch k.Format {
+	case FormatSSH:
+		// #nosec G204 - key path comes from the user's own config
+		cmd = exec.Command("ssh-keygen", "-q", "-Y", "sign", "-f", k.ID, "-n", Namespace)
+	case FormatGPG:
+		// #nosec G204 - key ID comes from the user's own config
+		cmd = exec.Command("gpg", "--batch", "--yes", "--detach-sign", "--armor", "--local-user", k.ID)
+	default:
+		return "", fmt.Errorf("unsupported signature format %q", k.Format)
+	}
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s signing failed: %v: %s", k.Format, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Status is the outcome of checking a signature.
+type Status string
+
+const (
+	// StatusGood: the signature is valid and made by the named signer
+	StatusGood Status = "good"
+	// StatusUntrusted: the signature is valid, but nothing vouches for who
+	// made it (no allowed signers file)
+	StatusUntrusted Status = "untrusted"
+	// StatusBad: the data was changed or the signature is not the signer's
+	StatusBad Status = "bad"
+)
+
+// Verifier checks signatures.
+type Verifier struct {
+	// AllowedSigners is the ssh-keygen allowed signers file; empty only
+	// checks SSH signatures' integrity
+	AllowedSigners string
+}
+
+// VerifierFromConfig returns a Verifier using the configured allowed
+// signers file.
+func VerifierFromConfig() *Verifier {
+	path := config.GetString("sign.allowed-signers")
+	if path == "" {
+		path = gitConfig("gpg.ssh.allowedSignersFile")
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + path[1:]
+		}
+	}
+	return &Verifier{AllowedSigners: path}
+}
+
+// FormatOf tells an SSH signature from a GPG one by its armor.
+func FormatOf(sig string) string {
+	if strings.Contains(sig, "BEGIN SSH SIGNATURE") {
+		return FormatSSH
+	}
+	return FormatGPG
+}
+
+var gpgGoodSig = regexp.MustCompile(`(?m)^\[GNUPG:\] GOODSIG (\S+) (.*)$`)
+
+// Verify checks that sig is signer's signature of data. An empty signer
+// accepts any signer the allowed signers file lists. An error means the
+// check itself couldn't run (e.g. ssh-keygen is missing).
+func (v *Verifier) Verify(data []byte, sig, signer string) (Status, error) {
+	sigFile, err := os.CreateTemp("", "bd-sig-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to write signature: %w", err)
+	}
+	defer func() { _ = os.Remove(sigFile.Name()) }()
+	if _, err := sigFile.WriteString(sig); err != nil {
+		_ = sigFile.Close()
+		return "", fmt.Errorf("failed to write signature: %w", err)
+	}
+	if err := sigFile.Close(); err != nil {
+		return "", fmt.Errorf("failed to write signature: %w", err)
+	}
+
+	status := StatusGood
+	var cmd *exec.Cmd
+	switch FormatOf(sig) {
+	case FormatSSH:
+		if v.AllowedSigners != "" && signer == "" {
+			// Unnamed signer: whoever the allowed signers file lists for the key
+			if signer = v.findPrincipal(sigFile.Name()); signer == "" {
+				return StatusBad, nil
+			}
+		}
+		if v.AllowedSigners != "" {
+			// #nosec G204 - arguments are file paths and the recorded signer
+			cmd = exec.Command("ssh-keygen", "-Y", "verify", "-f", v.AllowedSigners, "-I", signer, "-n", Namespace, "-s", sigFile.Name())
+		} else {
+			status = StatusUntrusted
+			// #nosec G204 - argument is a temp file path
+			cmd = exec.Command("ssh-keygen", "-Y", "check-novalidate", "-n", Namespace, "-s", sigFile.Name())
+		}
+	default:
+		// #nosec G204 - argument is a temp file path
+		cmd = exec.Command("gpg", "--batch", "--status-fd", "1", "--verify", sigFile.Name(), "-")
+	}
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return "", fmt.Errorf("failed to run %s: %w", cmd.Path, err)
+	}
+	if err != nil {
+		return StatusBad, nil
+	}
+
+	if FormatOf(sig) == FormatGPG {
+		m := gpgGoodSig.FindStringSubmatch(stdout.String())
+		if m == nil {
+			return StatusBad, nil
+		}
+		// The recorded signer is the key ID or a user ID the key carries
+		if signer != "" && !strings.HasSuffix(strings.ToUpper(m[1]), strings.ToUpper(strings.TrimPrefix(signer, "0x"))) &&
+			!strings.Contains(strings.ToLower(m[2]), strings.ToLower(signer)) {
+			return StatusBad, nil
+		}
+	}
+	return status, nil
+}
+
+// findPrincipal returns the first principal the allowed signers file lists
+// for the key that made the SSH signature in sigPath, or "" if none.
+func (v *Verifier) findPrincipal(sigPath string) string {
+	// #nosec G204 - arguments are file paths
+	out, err := exec.Command("ssh-keygen", "-Y", "find-principals", "-f", v.AllowedSigners, "-s", sigPath).Output()
+	if err != nil {
+		return ""
+	}
+	principal, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return principal
+}
+
+// gitConfig returns a git config value, or "" when unset.
+func gitConfig(key string) string {
+	out, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,88 @@
+package signing
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newSSHKey generates an ed25519 key and an allowed signers file listing it
+// for principal.
+func newSSHKey(t *testing.T, principal string) (keyPath, allowedSigners string) {
+	t.Helper()
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+	dir := t.TempDir()
+	keyPath = filepath.Join(dir, "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", principal, "-f", keyPath).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen failed: %v: %s", err, out)
+	}
+	pub, err := os.ReadFile(keyPath + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowedSigners = filepath.Join(dir, "allowed_signers")
+	if err := os.WriteFile(allowedSigners, []byte(principal+" "+strings.TrimSpace(string(pub))+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return keyPath, allowedSigners
+}
+
+func TestSSHSignAndVerify(t *testing.T) {
+	keyPath, allowed := newSSHKey(t, "alice@example.com")
+	key := &Key{Format: FormatSSH, ID: keyPath, Signer: "alice@example.com"}
+	data := []byte("issues.jsonl contents\n")
+	sig, err := key.Sign(data)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if FormatOf(sig) != FormatSSH {
+		t.Fatalf("FormatOf = %q, want ssh", FormatOf(sig))
+	}
+
+	trusted := &Verifier{AllowedSigners: allowed}
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		data     []byte
+		signer   string
+		want     Status
+	}{
+		{"good", trusted, data, "alice@example.com", StatusGood},
+		{"signer looked up", trusted, data, "", StatusGood},
+		{"tampered", trusted, []byte("issues.jsonl contents, edited\n"), "alice@example.com", StatusBad},
+		{"wrong signer", trusted, data, "mallory@example.com", StatusBad},
+		{"no allowed signers", &Verifier{}, data, "alice@example.com", StatusUntrusted},
+		{"no allowed signers, tampered", &Verifier{}, []byte("edited\n"), "", StatusBad},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verifier.Verify(tt.data, sig, tt.signer)
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Verify = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsUnlistedKey(t *testing.T) {
+	keyPath, _ := newSSHKey(t, "mallory@example.com")
+	_, allowed := newSSHKey(t, "alice@example.com")
+	sig, err := (&Key{Format: FormatSSH, ID: keyPath}).Sign([]byte("data"))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	got, err := (&Verifier{AllowedSigners: allowed}).Verify([]byte("data"), sig, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != StatusBad {
+		t.Errorf("Verify = %q, want bad for a key not in allowed signers", got)
+	}
+}
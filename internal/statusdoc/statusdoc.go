@@ -0,0 +1,230 @@
+// Package statusdoc writes a project status document: a Markdown summary of
+// top priorities, work in progress, blocked issues and recent closes, meant
+// to be committed next to the code (bd summary write STATUS.md).
+//
+// The summary sits between begin and end markers, so the rest of the file
+// can be written by hand and a summary can live inside a README.
+package statusdoc
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/visibility"
+)
+
+// Markers around the generated summary.
+const (
+	BeginMarker = "<!-- bd summary:begin (generated by bd summary write; edits here are overwritten) -->"
+	EndMarker   = "<!-- bd summary:end -->"
+)
+
+// Defaults for Options.
+const (
+	DefaultLimit = 10
+	DefaultDays  = 14
+)
+
+// Options shapes a summary.
+type Options struct {
+	Limit int // entries per section, 0 for DefaultLimit
+	Days  int // how far back closes count as recent, 0 for DefaultDays
+}
+
+// Entry is one issue listed in a summary.
+type Entry struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Priority  int        `json:"priority"`
+	Status    string     `json:"status"`
+	Assignee  string     `json:"assignee,omitempty"`
+	BlockedBy []string   `json:"blocked_by,omitempty"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+}
+
+// Summary is the content of a status document.
+type Summary struct {
+	Updated    time.Time `json:"updated"` // latest issue update, so the document only changes with the issues
+	Open       int       `json:"open"`
+	InProgress int       `json:"in_progress"`
+	Blocked    int       `json:"blocked"`
+	Closed     int       `json:"closed"`
+	Days       int       `json:"days"`
+
+	TopPriorities  []Entry `json:"top_priorities"`
+	Working        []Entry `json:"in_progress_issues"`
+	BlockedIssues  []Entry `json:"blocked_issues"`
+	RecentlyClosed []Entry `json:"recently_closed"`
+}
+
+// Build collects the summary of the issues in s as of now. Private issues
+// are left out: the document is meant to be committed.
+func Build(ctx context.Context, s storage.Storage, now time.Time, opts Options) (*Summary, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLimit
+	}
+	if opts.Days <= 0 {
+		opts.Days = DefaultDays
+	}
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	if err := visibility.Populate(ctx, s, issues); err != nil {
+		return nil, err
+	}
+	issues = visibility.Filter(issues, visibility.Internal)
+	blocked, err := s.GetBlockedIssues(ctx, types.WorkFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked issues: %w", err)
+	}
+	blockers := make(map[string][]string, len(blocked))
+	for _, b := range blocked {
+		blockers[b.ID] = b.BlockedBy
+	}
+
+	sum := &Summary{Days: opts.Days}
+	since := now.AddDate(0, 0, -opts.Days)
+	for _, issue := range issues {
+		if issue.Status == types.StatusTombstone {
+			continue
+		}
+		if issue.UpdatedAt.After(sum.Updated) {
+			sum.Updated = issue.UpdatedAt
+		}
+		e := Entry{
+			ID:       issue.ID,
+			Title:    issue.Title,
+			Priority: issue.Priority,
+			Status:   string(issue.Status),
+			Assignee: issue.Assignee,
+			ClosedAt: issue.ClosedAt,
+		}
+		switch {
+		case issue.Status == types.StatusClosed:
+			sum.Closed++
+			if issue.ClosedAt != nil && !issue.ClosedAt.Before(since) {
+				sum.RecentlyClosed = append(sum.RecentlyClosed, e)
+			}
+		case blockers[issue.ID] != nil || issue.Status == types.StatusBlocked:
+			sum.Blocked++
+			e.BlockedBy = blockers[issue.ID]
+			sum.BlockedIssues = append(sum.BlockedIssues, e)
+		case issue.Status == types.StatusInProgress:
+			sum.InProgress++
+			sum.Working = append(sum.Working, e)
+			sum.TopPriorities = append(sum.TopPriorities, e)
+		case issue.Status == types.StatusOpen:
+			sum.Open++
+			sum.TopPriorities = append(sum.TopPriorities, e)
+		default:
+			// Deferred, pinned and custom statuses count as open work
+			// without being a priority right now
+			sum.Open++
+		}
+	}
+
+	byPriority := func(a, b Entry) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), strings.Compare(a.ID, b.ID))
+	}
+	slices.SortFunc(sum.TopPriorities, byPriority)
+	slices.SortFunc(sum.Working, byPriority)
+	slices.SortFunc(sum.BlockedIssues, byPriority)
+	slices.SortFunc(sum.RecentlyClosed, func(a, b Entry) int {
+		return cmp.Or(b.ClosedAt.Compare(*a.ClosedAt), strings.Compare(a.ID, b.ID))
+	})
+	sum.TopPriorities = truncate(sum.TopPriorities, opts.Limit)
+	sum.Working = truncate(sum.Working, opts.Limit)
+	sum.BlockedIssues = truncate(sum.BlockedIssues, opts.Limit)
+	sum.RecentlyClosed = truncate(sum.RecentlyClosed, opts.Limit)
+	return sum, nil
+}
+
+func truncate(entries []Entry, n int) []Entry {
+	if len(entries) > n {
+		return entries[:n]
+	}
+	return entries
+}
+
+// Render writes the summary as Markdown, markers included.
+func Render(sum *Summary) string {
+	var b strings.Builder
+	b.WriteString(BeginMarker + "\n")
+	b.WriteString("# Project Status\n\n")
+	if sum.Updated.IsZero() {
+		b.WriteString("_No issues yet._\n")
+	} else {
+		fmt.Fprintf(&b, "_As of %s: %d open, %d in progress, %d blocked, %d closed._\n",
+			sum.Updated.Format("2006-01-02"), sum.Open, sum.InProgress, sum.Blocked, sum.Closed)
+	}
+
+	section := func(title string, entries []Entry, line func(Entry) string) {
+		fmt.Fprintf(&b, "\n## %s\n\n", title)
+		if len(entries) == 0 {
+			b.WriteString("_None._\n")
+			return
+		}
+		for _, e := range entries {
+			b.WriteString("- " + line(e) + "\n")
+		}
+	}
+	section("Top Priorities", sum.TopPriorities, func(e Entry) string {
+		line := fmt.Sprintf("**P%d** %s (`%s`", e.Priority, oneLine(e.Title), e.ID)
+		if e.Status == string(types.StatusInProgress) {
+			line += ", in progress"
+		}
+		return line + assignee(e) + ")"
+	})
+	section("In Progress", sum.Working, func(e Entry) string {
+		return fmt.Sprintf("%s (`%s`%s)", oneLine(e.Title), e.ID, assignee(e))
+	})
+	section("Blocked", sum.BlockedIssues, func(e Entry) string {
+		line := fmt.Sprintf("%s (`%s`%s)", oneLine(e.Title), e.ID, assignee(e))
+		if len(e.BlockedBy) > 0 {
+			line += " — blocked by `" + strings.Join(e.BlockedBy, "`, `") + "`"
+		}
+		return line
+	})
+	section(fmt.Sprintf("Recently Closed (last %d days)", sum.Days), sum.RecentlyClosed, func(e Entry) string {
+		return fmt.Sprintf("%s %s (`%s`)", e.ClosedAt.Format("2006-01-02"), oneLine(e.Title), e.ID)
+	})
+	b.WriteString(EndMarker + "\n")
+	return b.String()
+}
+
+func assignee(e Entry) string {
+	if e.Assignee == "" {
+		return ""
+	}
+	return ", @" + e.Assignee
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Splice puts a rendered summary into content: in place of the previous
+// one between the markers, else at the end.
+func Splice(content, rendered string) string {
+	begin := strings.Index(content, BeginMarker)
+	if begin >= 0 {
+		if n := strings.Index(content[begin:], EndMarker); n >= 0 {
+			end := begin + n + len(EndMarker)
+			if end < len(content) && content[end] == '\n' {
+				end++
+			}
+			return content[:begin] + rendered + content[end:]
+		}
+	}
+	if strings.TrimSpace(content) == "" {
+		return rendered
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + rendered
+}
@@ -0,0 +1,71 @@
+package statusdoc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New("")
+	for _, issue := range []*types.Issue{
+		{ID: "bd-1", Title: "Login page", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "bd-2", Title: "Outage", Status: types.StatusInProgress, Priority: 0, IssueType: types.TypeBug, Assignee: "ana"},
+		{ID: "bd-3", Title: "Billing", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+		{ID: "bd-4", Title: "Docs", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeTask},
+	} {
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: "bd-3", DependsOnID: "bd-2", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseIssue(ctx, "bd-4", "done", "tester", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := Build(ctx, s, time.Now(), Options{})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if sum.Open != 1 || sum.InProgress != 1 || sum.Blocked != 1 || sum.Closed != 1 {
+		t.Errorf("counts = %d open, %d in progress, %d blocked, %d closed", sum.Open, sum.InProgress, sum.Blocked, sum.Closed)
+	}
+	if len(sum.TopPriorities) != 2 || sum.TopPriorities[0].ID != "bd-2" || sum.TopPriorities[1].ID != "bd-1" {
+		t.Errorf("top priorities = %+v", sum.TopPriorities)
+	}
+	if len(sum.BlockedIssues) != 1 || len(sum.BlockedIssues[0].BlockedBy) != 1 || sum.BlockedIssues[0].BlockedBy[0] != "bd-2" {
+		t.Errorf("blocked = %+v", sum.BlockedIssues)
+	}
+	if len(sum.RecentlyClosed) != 1 || sum.RecentlyClosed[0].ID != "bd-4" {
+		t.Errorf("recently closed = %+v", sum.RecentlyClosed)
+	}
+
+	doc := Render(sum)
+	for _, want := range []string{"# Project Status", "**P0** Outage (`bd-2`, in progress, @ana)", "Billing (`bd-3`) — blocked by `bd-2`"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document lacks %q:\n%s", want, doc)
+		}
+	}
+}
+
+func TestSplice(t *testing.T) {
+	first := BeginMarker + "\nold\n" + EndMarker + "\n"
+	content := "# Readme\n\nIntro.\n\n" + first + "\nFooter.\n"
+	got := Splice(content, BeginMarker+"\nnew\n"+EndMarker+"\n")
+	if want := "# Readme\n\nIntro.\n\n" + BeginMarker + "\nnew\n" + EndMarker + "\n\nFooter.\n"; got != want {
+		t.Errorf("Splice replaced to:\n%s", got)
+	}
+	if got := Splice("# Readme\n", first); got != "# Readme\n\n"+first {
+		t.Errorf("Splice appended to:\n%s", got)
+	}
+	if got := Splice("", first); got != first {
+		t.Errorf("Splice into empty content = %q", got)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+
+	"github.com/steveyegge/beads/internal/config"
 )
 
 // SetConfig sets a configuration value
@@ -21,6 +23,11 @@ func (s *DoltStore) SetConfig(ctx context.Context, key, value string) error {
 
 // GetConfig retrieves a configuration value
 func (s *DoltStore) GetConfig(ctx context.Context, key string) (string, error) {
+	// Flags and BD_/BEADS_ environment variables override stored config
+	if value, _, ok := config.Override(key); ok {
+		return value, nil
+	}
+
 	var value string
 	err := s.db.QueryRowContext(ctx, "SELECT value FROM config WHERE `key` = ?", key).Scan(&value)
 	if err == sql.ErrNoRows {
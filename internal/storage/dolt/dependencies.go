@@ -12,6 +12,10 @@ import (
 
 // AddDependency adds a dependency between two issues
 func (s *DoltStore) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	if err := s.checkLock(ctx, dep.IssueID, actor); err != nil {
+		return err
+	}
+
 	metadata := dep.Metadata
 	if metadata == "" {
 		metadata = "{}"
@@ -30,6 +34,10 @@ func (s *DoltStore) AddDependency(ctx context.Context, dep *types.Dependency, ac
 
 // RemoveDependency removes a dependency between two issues
 func (s *DoltStore) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	if err := s.checkLock(ctx, issueID, actor); err != nil {
+		return err
+	}
+
 	_, err := s.db.ExecContext(ctx, `
 		DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ?
 	`, issueID, dependsOnID)
@@ -642,7 +642,7 @@ func TestDoltStoreDeleteIssue(t *testing.T) {
 	}
 
 	// Delete the issue
-	if err := store.DeleteIssue(ctx, issue.ID); err != nil {
+	if err := store.DeleteIssue(ctx, issue.ID, "test-user"); err != nil {
 		t.Fatalf("failed to delete issue: %v", err)
 	}
 
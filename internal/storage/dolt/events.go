@@ -11,6 +11,10 @@ import (
 
 // AddComment adds a comment event to an issue
 func (s *DoltStore) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	if err := s.checkLock(ctx, issueID, actor); err != nil {
+		return err
+	}
+
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO events (issue_id, event_type, actor, comment)
 		VALUES (?, ?, ?, ?)
@@ -65,6 +69,10 @@ func (s *DoltStore) GetEvents(ctx context.Context, issueID string, limit int) ([
 
 // AddIssueComment adds a comment to an issue (structured comment)
 func (s *DoltStore) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
+	if err := s.checkLock(ctx, issueID, author); err != nil {
+		return nil, err
+	}
+
 	result, err := s.db.ExecContext(ctx, `
 		INSERT INTO comments (issue_id, author, text, created_at)
 		VALUES (?, ?, ?, ?)
@@ -9,10 +9,18 @@ import (
 	"time"
 
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/validation"
 )
 
 // CreateIssue creates a new issue
 func (s *DoltStore) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	if err := s.checkLock(ctx, "", actor); err != nil {
+		return err
+	}
+	if err := validation.CheckCreate(ctx, s, issue); err != nil {
+		return err
+	}
+
 	// Fetch custom statuses and types for validation
 	customStatuses, err := s.GetCustomStatuses(ctx)
 	if err != nil {
@@ -115,6 +123,13 @@ func (s *DoltStore) CreateIssue(ctx context.Context, issue *types.Issue, actor s
 
 // CreateIssues creates multiple issues in a single transaction
 func (s *DoltStore) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	if err := s.checkLock(ctx, "", actor); err != nil {
+		return err
+	}
+	if err := validation.CheckCreate(ctx, s, issues...); err != nil {
+		return err
+	}
+
 	if len(issues) == 0 {
 		return nil
 	}
@@ -229,6 +244,10 @@ func (s *DoltStore) GetIssueByExternalRef(ctx context.Context, externalRef strin
 
 // UpdateIssue updates fields on an issue
 func (s *DoltStore) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	if err := s.checkLock(ctx, id, actor); err != nil {
+		return err
+	}
+
 	oldIssue, err := s.GetIssue(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get issue for update: %w", err)
@@ -236,6 +255,9 @@ func (s *DoltStore) UpdateIssue(ctx context.Context, id string, updates map[stri
 	if oldIssue == nil {
 		return fmt.Errorf("issue %s not found", id)
 	}
+	if err := validation.CheckUpdate(ctx, s, oldIssue, updates); err != nil {
+		return err
+	}
 
 	// Build update query
 	setClauses := []string{"updated_at = ?"}
@@ -289,6 +311,10 @@ func (s *DoltStore) UpdateIssue(ctx context.Context, id string, updates map[stri
 
 // CloseIssue closes an issue with a reason
 func (s *DoltStore) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
+	if err := s.checkLock(ctx, id, actor); err != nil {
+		return err
+	}
+
 	now := time.Now().UTC()
 
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -325,7 +351,11 @@ func (s *DoltStore) CloseIssue(ctx context.Context, id string, reason string, ac
 }
 
 // DeleteIssue permanently removes an issue
-func (s *DoltStore) DeleteIssue(ctx context.Context, id string) error {
+func (s *DoltStore) DeleteIssue(ctx context.Context, id, actor string) error {
+	if err := s.checkLock(ctx, id, ""); err != nil {
+		return err
+	}
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
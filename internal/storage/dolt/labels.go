@@ -6,10 +6,15 @@ import (
 	"strings"
 
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/validation"
 )
 
 // AddLabel adds a label to an issue
 func (s *DoltStore) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := s.checkLock(ctx, issueID, actor); err != nil {
+		return err
+	}
+
 	_, err := s.db.ExecContext(ctx, `
 		INSERT IGNORE INTO labels (issue_id, label) VALUES (?, ?)
 	`, issueID, label)
@@ -21,6 +26,13 @@ func (s *DoltStore) AddLabel(ctx context.Context, issueID, label, actor string)
 
 // RemoveLabel removes a label from an issue
 func (s *DoltStore) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := s.checkLock(ctx, issueID, actor); err != nil {
+		return err
+	}
+	if err := validation.CheckRemoveLabel(ctx, s, issueID, label); err != nil {
+		return err
+	}
+
 	_, err := s.db.ExecContext(ctx, `
 		DELETE FROM labels WHERE issue_id = ? AND label = ?
 	`, issueID, label)
@@ -0,0 +1,38 @@
+package dolt
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/lock"
+)
+
+// checkLock refuses a mutation of issueID by actor while the issue is
+// locked or the project frozen (bd lock, bd freeze). An empty issueID
+// checks a create.
+func (s *DoltStore) checkLock(ctx context.Context, issueID, actor string) error {
+	return lock.Check(ctx, issueID, actor, func(id string) (bool, string, error) {
+		var n int
+		if err := s.db.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM labels WHERE issue_id = ? AND label = ?
+		`, id, lock.Label).Scan(&n); err != nil {
+			return false, "", err
+		}
+		if n == 0 {
+			return false, "", nil
+		}
+		var text string
+		err := s.db.QueryRowContext(ctx, `
+			SELECT text FROM comments
+			WHERE issue_id = ? AND text LIKE ?
+			ORDER BY created_at DESC, id DESC LIMIT 1
+		`, id, strings.TrimSpace(lock.CommentPrefix)+"%").Scan(&text)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return true, "", err
+		}
+		reason, _ := lock.ReasonFromComment(text)
+		return true, reason, nil
+	})
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/util"
 )
 
 // SearchIssues finds issues matching query and filters
@@ -15,6 +17,45 @@ func (s *DoltStore) SearchIssues(ctx context.Context, query string, filter types
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	whereSQL, args, err := buildSearchWhere(query, filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.After != nil {
+		clause, afterArgs := searchAfterClause(filter.After)
+		if whereSQL == "" {
+			whereSQL = "WHERE " + clause
+		} else {
+			whereSQL += " AND " + clause
+		}
+		args = append(args, afterArgs...)
+	}
+
+	limitSQL := ""
+	if filter.Limit > 0 {
+		limitSQL = fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	// nolint:gosec // G201: whereSQL contains column comparisons with ?, limitSQL is a safe integer
+	querySQL := fmt.Sprintf(`
+		SELECT id FROM issues
+		%s
+		ORDER BY priority ASC, created_at DESC, id ASC
+		%s
+	`, whereSQL, limitSQL)
+
+	rows, err := s.db.QueryContext(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanIssueIDs(ctx, rows)
+}
+
+// buildSearchWhere builds the WHERE clause and its arguments for
+// SearchIssues and CountIssues.
+func buildSearchWhere(query string, filter types.IssueFilter) (string, []interface{}, error) {
 	whereClauses := []string{}
 	args := []interface{}{}
 
@@ -41,6 +82,10 @@ func (s *DoltStore) SearchIssues(ctx context.Context, query string, filter types
 		whereClauses = append(whereClauses, "notes LIKE ?")
 		args = append(args, "%"+filter.NotesContains+"%")
 	}
+	if filter.TitleRegex != "" {
+		whereClauses = append(whereClauses, "title REGEXP ?")
+		args = append(args, filter.TitleRegex)
+	}
 
 	if filter.Status != nil {
 		whereClauses = append(whereClauses, "status = ?")
@@ -138,6 +183,27 @@ func (s *DoltStore) SearchIssues(ctx context.Context, query string, filter types
 		whereClauses = append(whereClauses, fmt.Sprintf("id IN (SELECT issue_id FROM labels WHERE label IN (%s))", strings.Join(placeholders, ", ")))
 	}
 
+	// Label glob filtering: each pattern must match at least one label.
+	// MySQL has no GLOB operator, so translate to an anchored regexp.
+	for _, pattern := range filter.LabelGlobs {
+		expr, err := util.GlobToRegexp(pattern)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid label glob %q: %w", pattern, err)
+		}
+		whereClauses = append(whereClauses, "id IN (SELECT issue_id FROM labels WHERE REGEXP_LIKE(label, ?, 'c'))")
+		args = append(args, expr)
+	}
+
+	// Label exclusion: issue must have NONE of these labels
+	if len(filter.NotLabels) > 0 {
+		placeholders := make([]string, len(filter.NotLabels))
+		for i, label := range filter.NotLabels {
+			placeholders[i] = "?"
+			args = append(args, label)
+		}
+		whereClauses = append(whereClauses, fmt.Sprintf("id NOT IN (SELECT issue_id FROM labels WHERE label IN (%s))", strings.Join(placeholders, ", ")))
+	}
+
 	// ID filtering
 	if len(filter.IDs) > 0 {
 		placeholders := make([]string, len(filter.IDs))
@@ -201,31 +267,106 @@ func (s *DoltStore) SearchIssues(ctx context.Context, query string, filter types
 		args = append(args, time.Now().UTC().Format(time.RFC3339), types.StatusClosed)
 	}
 
+	// Query expression (bd query)
+	if filter.Query != nil {
+		clause, clauseArgs := filter.Query.SQL()
+		whereClauses = append(whereClauses, clause)
+		args = append(args, clauseArgs...)
+	}
+
 	whereSQL := ""
 	if len(whereClauses) > 0 {
 		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
 	}
+	return whereSQL, args, nil
+}
 
-	limitSQL := ""
-	if filter.Limit > 0 {
-		limitSQL = fmt.Sprintf(" LIMIT %d", filter.Limit)
+// searchAfterClause returns the condition selecting the issues that come
+// after cursor c in SearchIssues order (priority, newest first, then ID).
+func searchAfterClause(c *types.Cursor) (string, []interface{}) {
+	return "(priority > ? OR (priority = ? AND (created_at < ? OR (created_at = ? AND id > ?))))",
+		[]interface{}{c.Priority, c.Priority, c.CreatedAt, c.CreatedAt, c.ID}
+}
+
+// CountIssues counts issues matching query and filters without loading
+// them, optionally grouped by status, priority, type, assignee or label.
+// Limit and After are ignored.
+func (s *DoltStore) CountIssues(ctx context.Context, query string, filter types.IssueFilter, groupBy string) (*types.IssueCounts, error) {
+	if err := types.ValidateGroupBy(groupBy); err != nil {
+		return nil, err
 	}
 
-	// nolint:gosec // G201: whereSQL contains column comparisons with ?, limitSQL is a safe integer
-	querySQL := fmt.Sprintf(`
-		SELECT id FROM issues
-		%s
-		ORDER BY priority ASC, created_at DESC
-		%s
-	`, whereSQL, limitSQL)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	rows, err := s.db.QueryContext(ctx, querySQL, args...)
+	whereSQL, args, err := buildSearchWhere(query, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search issues: %w", err)
+		return nil, err
+	}
+
+	var total int
+	// nolint:gosec // G201: whereSQL contains column comparisons with ?
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM issues "+whereSQL, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count issues: %w", err)
+	}
+	if groupBy == "" {
+		return &types.IssueCounts{Total: total}, nil
+	}
+
+	var groupSQL string
+	switch groupBy {
+	case types.GroupByStatus:
+		groupSQL = "SELECT status, COUNT(*) FROM issues " + whereSQL + " GROUP BY status"
+	case types.GroupByPriority:
+		groupSQL = "SELECT CAST(priority AS CHAR), COUNT(*) FROM issues " + whereSQL + " GROUP BY priority"
+	case types.GroupByType:
+		groupSQL = "SELECT issue_type, COUNT(*) FROM issues " + whereSQL + " GROUP BY issue_type"
+	case types.GroupByAssignee:
+		groupSQL = "SELECT COALESCE(assignee, ''), COUNT(*) FROM issues " + whereSQL + " GROUP BY COALESCE(assignee, '')"
+	case types.GroupByLabel:
+		// Each issue counts once per label
+		groupSQL = "SELECT label, COUNT(*) FROM labels WHERE issue_id IN (SELECT id FROM issues " + whereSQL + ") GROUP BY label"
+	}
+
+	// nolint:gosec // G201: whereSQL contains column comparisons with ?
+	rows, err := s.db.QueryContext(ctx, groupSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count issues: %w", err)
 	}
 	defer rows.Close()
 
-	return s.scanIssueIDs(ctx, rows)
+	groups := make(map[string]int)
+	for rows.Next() {
+		var key sql.NullString
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, fmt.Errorf("failed to count issues: %w", err)
+		}
+		group := key.String
+		switch {
+		case groupBy == types.GroupByPriority:
+			p, _ := strconv.Atoi(group)
+			group = types.PriorityGroup(p)
+		case groupBy == types.GroupByAssignee && group == "":
+			group = types.GroupUnassigned
+		}
+		groups[group] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count issues: %w", err)
+	}
+
+	if groupBy == types.GroupByLabel {
+		var labeled int
+		// nolint:gosec // G201: whereSQL contains column comparisons with ?
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(DISTINCT issue_id) FROM labels WHERE issue_id IN (SELECT id FROM issues "+whereSQL+")", args...).Scan(&labeled); err != nil {
+			return nil, fmt.Errorf("failed to count issues: %w", err)
+		}
+		if unlabeled := total - labeled; unlabeled > 0 {
+			groups[types.GroupNoLabels] = unlabeled
+		}
+	}
+	return types.NewIssueCounts(total, groups), nil
 }
 
 // GetReadyWork returns issues that are ready to work on (not blocked)
@@ -266,6 +407,14 @@ func (s *DoltStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 		)
 	`)
 
+	// Pagination: resume after the cursor's issue (ready work is listed in
+	// search order here)
+	if filter.After != nil {
+		clause, afterArgs := searchAfterClause(filter.After)
+		whereClauses = append(whereClauses, clause)
+		args = append(args, afterArgs...)
+	}
+
 	whereSQL := "WHERE " + strings.Join(whereClauses, " AND ")
 
 	limitSQL := ""
@@ -277,7 +426,7 @@ func (s *DoltStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 	query := fmt.Sprintf(`
 		SELECT id FROM issues
 		%s
-		ORDER BY priority ASC, created_at DESC
+		ORDER BY priority ASC, created_at DESC, id ASC
 		%s
 	`, whereSQL, limitSQL)
 
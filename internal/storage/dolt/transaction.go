@@ -9,6 +9,7 @@ import (
 
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/validation"
 )
 
 // doltTransaction implements storage.Transaction for Dolt
@@ -43,6 +44,9 @@ func (s *DoltStore) RunInTransaction(ctx context.Context, fn func(tx storage.Tra
 
 // CreateIssue creates an issue within the transaction
 func (t *doltTransaction) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	if err := validation.CheckCreate(ctx, t, issue); err != nil {
+		return err
+	}
 	now := time.Now().UTC()
 	if issue.CreatedAt.IsZero() {
 		issue.CreatedAt = now
@@ -69,7 +73,27 @@ func (t *doltTransaction) CreateIssues(ctx context.Context, issues []*types.Issu
 
 // GetIssue retrieves an issue within the transaction
 func (t *doltTransaction) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
-	return scanIssueTx(ctx, t.tx, id)
+	issue, err := scanIssueTx(ctx, t.tx, id)
+	if err != nil || issue == nil {
+		return issue, err
+	}
+
+	// Fetch labels within the transaction, as the validation rules check them
+	rows, err := t.tx.QueryContext(ctx, `
+		SELECT label FROM labels WHERE issue_id = ? ORDER BY label
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			return nil, fmt.Errorf("failed to scan label: %w", err)
+		}
+		issue.Labels = append(issue.Labels, label)
+	}
+	return issue, rows.Err()
 }
 
 // SearchIssues searches for issues within the transaction
@@ -89,6 +113,16 @@ func (t *doltTransaction) SearchIssues(ctx context.Context, query string, filter
 		args = append(args, *filter.Status)
 	}
 
+	if filter.Priority != nil {
+		whereClauses = append(whereClauses, "priority = ?")
+		args = append(args, *filter.Priority)
+	}
+
+	for _, status := range filter.ExcludeStatus {
+		whereClauses = append(whereClauses, "status != ?")
+		args = append(args, status)
+	}
+
 	whereSQL := ""
 	if len(whereClauses) > 0 {
 		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
@@ -121,6 +155,16 @@ func (t *doltTransaction) SearchIssues(ctx context.Context, query string, filter
 
 // UpdateIssue updates an issue within the transaction
 func (t *doltTransaction) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	oldIssue, err := t.GetIssue(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get issue for update: %w", err)
+	}
+	if oldIssue != nil {
+		if err := validation.CheckUpdate(ctx, t, oldIssue, updates); err != nil {
+			return err
+		}
+	}
+
 	setClauses := []string{"updated_at = ?"}
 	args := []interface{}{time.Now().UTC()}
 
@@ -139,7 +183,7 @@ func (t *doltTransaction) UpdateIssue(ctx context.Context, id string, updates ma
 	args = append(args, id)
 	// nolint:gosec // G201: setClauses contains only column names (e.g. "status = ?"), actual values passed via args
 	query := fmt.Sprintf("UPDATE issues SET %s WHERE id = ?", strings.Join(setClauses, ", "))
-	_, err := t.tx.ExecContext(ctx, query, args...)
+	_, err = t.tx.ExecContext(ctx, query, args...)
 	return err
 }
 
@@ -154,7 +198,7 @@ func (t *doltTransaction) CloseIssue(ctx context.Context, id string, reason stri
 }
 
 // DeleteIssue deletes an issue within the transaction
-func (t *doltTransaction) DeleteIssue(ctx context.Context, id string) error {
+func (t *doltTransaction) DeleteIssue(ctx context.Context, id, actor string) error {
 	_, err := t.tx.ExecContext(ctx, "DELETE FROM issues WHERE id = ?", id)
 	return err
 }
@@ -187,6 +231,9 @@ func (t *doltTransaction) AddLabel(ctx context.Context, issueID, label, actor st
 
 // RemoveLabel removes a label within the transaction
 func (t *doltTransaction) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := validation.CheckRemoveLabel(ctx, t, issueID, label); err != nil {
+		return err
+	}
 	_, err := t.tx.ExecContext(ctx, `
 		DELETE FROM labels WHERE issue_id = ? AND label = ?
 	`, issueID, label)
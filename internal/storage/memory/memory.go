@@ -7,14 +7,18 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/lock"
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/util"
+	"github.com/steveyegge/beads/internal/validation"
 )
 
 // MemoryStorage implements the Storage interface using in-memory data structures
@@ -187,9 +191,18 @@ func extractParentAndChildNumber(id string) (string, int, bool) {
 
 // CreateIssue creates a new issue
 func (m *MemoryStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	// Checked before locking: the rules read the store
+	if err := validation.CheckCreate(ctx, m, issue); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.checkLock(ctx, "", actor); err != nil {
+		return err
+	}
+
 	// Get custom types and statuses for validation
 	var customTypes, customStatuses []string
 	if typeStr := m.config["types.custom"]; typeStr != "" {
@@ -249,9 +262,17 @@ func (m *MemoryStorage) CreateIssue(ctx context.Context, issue *types.Issue, act
 
 // CreateIssues creates multiple issues atomically
 func (m *MemoryStorage) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	if err := validation.CheckCreate(ctx, m, issues...); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.checkLock(ctx, "", actor); err != nil {
+		return err
+	}
+
 	// Get custom types and statuses for validation
 	var customTypes, customStatuses []string
 	if typeStr := m.config["types.custom"]; typeStr != "" {
@@ -382,9 +403,23 @@ func (m *MemoryStorage) GetIssueByExternalRef(ctx context.Context, externalRef s
 
 // UpdateIssue updates fields on an issue
 func (m *MemoryStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	before, err := m.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+	if before != nil {
+		if err := validation.CheckUpdate(ctx, m, before, updates); err != nil {
+			return err
+		}
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.checkLock(ctx, id, actor); err != nil {
+		return err
+	}
+
 	issue, exists := m.issues[id]
 	if !exists {
 		return fmt.Errorf("issue %s not found", id)
@@ -543,10 +578,14 @@ func (m *MemoryStorage) CreateTombstone(ctx context.Context, id string, actor st
 }
 
 // DeleteIssue permanently deletes an issue and all associated data
-func (m *MemoryStorage) DeleteIssue(ctx context.Context, id string) error {
+func (m *MemoryStorage) DeleteIssue(ctx context.Context, id, actor string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.checkLock(ctx, id, ""); err != nil {
+		return err
+	}
+
 	// Check if issue exists
 	issue, ok := m.issues[id]
 	if !ok {
@@ -572,10 +611,72 @@ func (m *MemoryStorage) DeleteIssue(ctx context.Context, id string) error {
 }
 
 // SearchIssues finds issues matching query and filters
+// CountIssues counts issues matching query and filters, optionally grouped.
+func (m *MemoryStorage) CountIssues(ctx context.Context, query string, filter types.IssueFilter, groupBy string) (*types.IssueCounts, error) {
+	if err := types.ValidateGroupBy(groupBy); err != nil {
+		return nil, err
+	}
+	filter.Limit, filter.After = 0, nil
+	issues, err := m.SearchIssues(ctx, query, filter)
+	if err != nil {
+		return nil, err
+	}
+	if groupBy == "" {
+		return &types.IssueCounts{Total: len(issues)}, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	groups := make(map[string]int)
+	for _, issue := range issues {
+		switch groupBy {
+		case types.GroupByStatus:
+			groups[string(issue.Status)]++
+		case types.GroupByPriority:
+			groups[types.PriorityGroup(issue.Priority)]++
+		case types.GroupByType:
+			groups[string(issue.IssueType)]++
+		case types.GroupByAssignee:
+			if issue.Assignee == "" {
+				groups[types.GroupUnassigned]++
+			} else {
+				groups[issue.Assignee]++
+			}
+		case types.GroupByLabel:
+			labels := m.labels[issue.ID]
+			if len(labels) == 0 {
+				groups[types.GroupNoLabels]++
+			}
+			for _, label := range labels {
+				groups[label]++
+			}
+		}
+	}
+	return types.NewIssueCounts(len(issues), groups), nil
+}
+
 func (m *MemoryStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	var titleRe *regexp.Regexp
+	if filter.TitleRegex != "" {
+		re, err := regexp.Compile(filter.TitleRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid title regex: %w", err)
+		}
+		titleRe = re
+	}
+	labelGlobs := make([]*regexp.Regexp, 0, len(filter.LabelGlobs))
+	for _, pattern := range filter.LabelGlobs {
+		re, err := util.CompileGlob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label glob %q: %w", pattern, err)
+		}
+		labelGlobs = append(labelGlobs, re)
+	}
+
 	var results []*types.Issue
 
 	for _, issue := range m.issues {
@@ -625,6 +726,13 @@ func (m *MemoryStorage) SearchIssues(ctx context.Context, query string, filter t
 			}
 		}
 
+		if titleRe != nil && !titleRe.MatchString(issue.Title) {
+			continue
+		}
+		if !matchesLabelGlobs(m.labels[issue.ID], labelGlobs) || hasAnyLabel(m.labels[issue.ID], filter.NotLabels) {
+			continue
+		}
+
 		// ID filtering
 		if len(filter.IDs) > 0 {
 			found := false
@@ -669,23 +777,62 @@ func (m *MemoryStorage) SearchIssues(ctx context.Context, query string, filter t
 			issueCopy.Labels = labels
 		}
 
+		// Query expression (bd query), evaluated with labels and deps attached
+		if filter.Query != nil && !filter.Query.Match(&issueCopy) {
+			continue
+		}
+
 		results = append(results, &issueCopy)
 	}
 
-	// Sort by priority, then by created_at
-	sort.Slice(results, func(i, j int) bool {
-		if results[i].Priority != results[j].Priority {
-			return results[i].Priority < results[j].Priority
-		}
-		return results[i].CreatedAt.After(results[j].CreatedAt)
-	})
+	// Sort by priority, then by created_at, then by ID (stable for pagination)
+	sort.Slice(results, func(i, j int) bool { return types.SearchLess(results[i], results[j]) })
+
+	return pageIssues(results, filter.After, filter.Limit, types.SearchLess), nil
+}
+
+// pageIssues drops the results up to and including cursor after (nil for
+// the first page) and applies limit (0 = unlimited). Results must be sorted
+// by less.
+func pageIssues(results []*types.Issue, after *types.Cursor, limit int, less func(a, b *types.Issue) bool) []*types.Issue {
+	if after != nil {
+		last := after.Issue()
+		start := sort.Search(len(results), func(i int) bool { return less(last, results[i]) })
+		results = results[start:]
+	}
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
 
-	// Apply limit
-	if filter.Limit > 0 && len(results) > filter.Limit {
-		results = results[:filter.Limit]
+// matchesLabelGlobs reports whether every glob matches at least one label.
+func matchesLabelGlobs(labels []string, globs []*regexp.Regexp) bool {
+	for _, glob := range globs {
+		found := false
+		for _, label := range labels {
+			if glob.MatchString(label) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
 	}
+	return true
+}
 
-	return results, nil
+// hasAnyLabel reports whether labels contains any of want.
+func hasAnyLabel(labels, want []string) bool {
+	for _, label := range labels {
+		for _, w := range want {
+			if label == w {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // AddDependency adds a dependency between issues
@@ -693,6 +840,10 @@ func (m *MemoryStorage) AddDependency(ctx context.Context, dep *types.Dependency
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.checkLock(ctx, dep.IssueID, actor); err != nil {
+		return err
+	}
+
 	// Check that both issues exist
 	if _, exists := m.issues[dep.IssueID]; !exists {
 		return fmt.Errorf("issue %s not found", dep.IssueID)
@@ -719,6 +870,10 @@ func (m *MemoryStorage) RemoveDependency(ctx context.Context, issueID, dependsOn
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.checkLock(ctx, issueID, actor); err != nil {
+		return err
+	}
+
 	deps := m.dependencies[issueID]
 	newDeps := make([]*types.Dependency, 0)
 
@@ -973,6 +1128,10 @@ func (m *MemoryStorage) AddLabel(ctx context.Context, issueID, label, actor stri
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.checkLock(ctx, issueID, actor); err != nil {
+		return err
+	}
+
 	// Check if issue exists
 	if _, exists := m.issues[issueID]; !exists {
 		return fmt.Errorf("issue %s not found", issueID)
@@ -992,9 +1151,17 @@ func (m *MemoryStorage) AddLabel(ctx context.Context, issueID, label, actor stri
 }
 
 func (m *MemoryStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := validation.CheckRemoveLabel(ctx, m, issueID, label); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.checkLock(ctx, issueID, actor); err != nil {
+		return err
+	}
+
 	labels := m.labels[issueID]
 	newLabels := make([]string, 0)
 
@@ -1169,43 +1336,53 @@ func (m *MemoryStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 		sortPolicy = types.SortPolicyHybrid
 	}
 
-	switch sortPolicy {
+	cutoff := filter.RecentSince
+	if cutoff.IsZero() {
+		cutoff = time.Now().Add(-types.HybridRecentWindow)
+	}
+	less := readyLess(sortPolicy, cutoff)
+	sort.Slice(results, func(i, j int) bool { return less(results[i], results[j]) })
+
+	return pageIssues(results, filter.After, filter.Limit, less), nil
+}
+
+// readyLess returns the GetReadyWork order for policy. Every policy breaks
+// ties by ID so pages stay consistent; the hybrid policy counts issues
+// created since cutoff as recent.
+func readyLess(policy types.SortPolicy, cutoff time.Time) func(a, b *types.Issue) bool {
+	byAge := func(a, b *types.Issue) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	}
+	byPriority := func(a, b *types.Issue) bool {
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return byAge(a, b)
+	}
+
+	switch policy {
 	case types.SortPolicyOldest:
-		sort.Slice(results, func(i, j int) bool {
-			return results[i].CreatedAt.Before(results[j].CreatedAt)
-		})
+		return byAge
 	case types.SortPolicyPriority:
-		sort.Slice(results, func(i, j int) bool {
-			if results[i].Priority != results[j].Priority {
-				return results[i].Priority < results[j].Priority
-			}
-			return results[i].CreatedAt.Before(results[j].CreatedAt)
-		})
+		return byPriority
 	case types.SortPolicyHybrid:
 		fallthrough
 	default:
-		cutoff := time.Now().Add(-48 * time.Hour)
-		sort.Slice(results, func(i, j int) bool {
-			iRecent := results[i].CreatedAt.After(cutoff)
-			jRecent := results[j].CreatedAt.After(cutoff)
-			if iRecent != jRecent {
-				return iRecent // recent first
+		return func(a, b *types.Issue) bool {
+			aRecent := !a.CreatedAt.Before(cutoff)
+			bRecent := !b.CreatedAt.Before(cutoff)
+			if aRecent != bRecent {
+				return aRecent // recent first
 			}
-			if iRecent {
-				if results[i].Priority != results[j].Priority {
-					return results[i].Priority < results[j].Priority
-				}
+			if aRecent {
+				return byPriority(a, b)
 			}
-			return results[i].CreatedAt.Before(results[j].CreatedAt)
-		})
-	}
-
-	// Apply limit
-	if filter.Limit > 0 && len(results) > filter.Limit {
-		results = results[:filter.Limit]
+			return byAge(a, b)
+		}
 	}
-
-	return results, nil
 }
 
 // getOpenBlockers returns the IDs of blockers that are currently open/in_progress/blocked/deferred/hooked.
@@ -1424,7 +1601,9 @@ func (m *MemoryStorage) GetNewlyUnblockedByClose(ctx context.Context, closedIssu
 }
 
 func (m *MemoryStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
-	return nil
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.checkLock(ctx, issueID, actor)
 }
 
 func (m *MemoryStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
@@ -1443,6 +1622,10 @@ func (m *MemoryStorage) AddIssueComment(ctx context.Context, issueID, author, te
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.checkLock(ctx, issueID, author); err != nil {
+		return nil, err
+	}
+
 	comment := &types.Comment{
 		ID:        int64(len(m.comments[issueID]) + 1),
 		IssueID:   issueID,
@@ -1642,6 +1825,11 @@ func (m *MemoryStorage) SetConfig(ctx context.Context, key, value string) error
 }
 
 func (m *MemoryStorage) GetConfig(ctx context.Context, key string) (string, error) {
+	// Flags and BD_/BEADS_ environment variables override stored config
+	if value, _, ok := config.Override(key); ok {
+		return value, nil
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -1830,3 +2018,27 @@ func (m *MemoryStorage) MarkIssueDirty(ctx context.Context, issueID string) erro
 	m.dirty[issueID] = true
 	return nil
 }
+
+// checkLock refuses a mutation of issueID by actor while the issue is
+// locked or the project frozen. Caller must hold m.mu.
+func (m *MemoryStorage) checkLock(ctx context.Context, issueID, actor string) error {
+	return lock.Check(ctx, issueID, actor, func(id string) (bool, string, error) {
+		locked := false
+		for _, l := range m.labels[id] {
+			if l == lock.Label {
+				locked = true
+				break
+			}
+		}
+		if !locked {
+			return false, "", nil
+		}
+		comments := m.comments[id]
+		for i := len(comments) - 1; i >= 0; i-- {
+			if reason, ok := lock.ReasonFromComment(comments[i].Text); ok {
+				return true, reason, nil
+			}
+		}
+		return true, "", nil
+	})
+}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -346,6 +347,61 @@ func TestCloseIssue(t *testing.T) {
 	}
 }
 
+func TestCountIssues(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	issues := []*types.Issue{
+		{Title: "Login bug", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug, Assignee: "alice"},
+		{Title: "Docs", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{Title: "Old bug", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug, Assignee: "alice"},
+	}
+	for _, issue := range issues {
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if err := store.CloseIssue(ctx, issues[2].ID, "Done", "test-user", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	for _, l := range []struct{ id, label string }{{issues[0].ID, "api"}, {issues[0].ID, "urgent"}, {issues[2].ID, "api"}} {
+		if err := store.AddLabel(ctx, l.id, l.label, "test-user"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+
+	bug := types.TypeBug
+	tests := []struct {
+		groupBy string
+		filter  types.IssueFilter
+		want    string
+	}{
+		{"", types.IssueFilter{}, "3 []"},
+		{"", types.IssueFilter{IssueType: &bug, Limit: 1}, "2 []"},
+		{types.GroupByStatus, types.IssueFilter{}, "3 [{closed 1} {open 2}]"},
+		{types.GroupByPriority, types.IssueFilter{}, "3 [{P0 1} {P2 2}]"},
+		{types.GroupByType, types.IssueFilter{}, "3 [{bug 2} {task 1}]"},
+		{types.GroupByAssignee, types.IssueFilter{}, "3 [{(unassigned) 1} {alice 2}]"},
+		{types.GroupByLabel, types.IssueFilter{}, "3 [{(no labels) 1} {api 2} {urgent 1}]"},
+		{types.GroupByLabel, types.IssueFilter{IssueType: &bug}, "2 [{api 2} {urgent 1}]"},
+	}
+	for _, tt := range tests {
+		counts, err := store.CountIssues(ctx, "", tt.filter, tt.groupBy)
+		if err != nil {
+			t.Fatalf("CountIssues(%q) failed: %v", tt.groupBy, err)
+		}
+		if got := fmt.Sprintf("%d %v", counts.Total, counts.Groups); got != tt.want {
+			t.Errorf("CountIssues(%q) = %s, want %s", tt.groupBy, got, tt.want)
+		}
+	}
+
+	if _, err := store.CountIssues(ctx, "", types.IssueFilter{}, "bogus"); err == nil {
+		t.Error("expected error for invalid group by")
+	}
+}
+
 func TestSearchIssues(t *testing.T) {
 	store := setupTestMemory(t)
 	defer store.Close()
@@ -1213,7 +1269,7 @@ func TestGetIssueByExternalRef(t *testing.T) {
 	}
 
 	// Delete issue and verify index is cleaned up
-	if err := store.DeleteIssue(ctx, issue.ID); err != nil {
+	if err := store.DeleteIssue(ctx, issue.ID, "test-user"); err != nil {
 		t.Fatalf("DeleteIssue failed: %v", err)
 	}
 
@@ -1356,3 +1412,59 @@ func TestGetNextChildID_ConfigurableMaxDepth(t *testing.T) {
 		t.Errorf("unexpected error message: %v", err)
 	}
 }
+
+func TestSearchIssuesPatternFilters(t *testing.T) {
+	store := setupTestMemory(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	ui := &types.Issue{Title: "Fix login v2", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+	docs := &types.Issue{Title: "Login docs", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{ui, docs} {
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	for id, labels := range map[string][]string{ui.ID: {"area/ui", "wip"}, docs.ID: {"area/docs"}} {
+		for _, label := range labels {
+			if err := store.AddLabel(ctx, id, label, "test-user"); err != nil {
+				t.Fatalf("AddLabel failed: %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter types.IssueFilter
+		want   []string
+	}{
+		{"title regex", types.IssueFilter{TitleRegex: `v\d$`}, []string{ui.ID}},
+		{"label glob", types.IssueFilter{LabelGlobs: []string{"area/*"}}, []string{ui.ID, docs.ID}},
+		{"not label", types.IssueFilter{LabelGlobs: []string{"area/*"}, NotLabels: []string{"wip"}}, []string{docs.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := store.SearchIssues(ctx, "", tt.filter)
+			if err != nil {
+				t.Fatalf("SearchIssues failed: %v", err)
+			}
+			if len(results) != len(tt.want) {
+				t.Fatalf("Expected %d results, got %d", len(tt.want), len(results))
+			}
+			for _, id := range tt.want {
+				found := false
+				for _, issue := range results {
+					found = found || issue.ID == id
+				}
+				if !found {
+					t.Errorf("Expected %s in results", id)
+				}
+			}
+		})
+	}
+
+	if _, err := store.SearchIssues(ctx, "", types.IssueFilter{TitleRegex: "("}); err == nil {
+		t.Error("Expected error for invalid title regex")
+	}
+}
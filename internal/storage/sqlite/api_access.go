@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// RecordAPIAccess appends requests to the API access log in one
+// transaction.
+func (s *SQLiteStorage) RecordAPIAccess(ctx context.Context, entries []*types.APIAccess) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO api_access_log (time, method, path, actor, token_id, remote, status, latency_ms)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`)
+		if err != nil {
+			return wrapDBError("prepare API access insert", err)
+		}
+		defer func() { _ = stmt.Close() }()
+		for _, a := range entries {
+			if _, err := stmt.ExecContext(ctx, a.Time, a.Method, a.Path, a.Actor, a.TokenID, a.Remote, a.Status, a.LatencyMS); err != nil {
+				return wrapDBError("record API access", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetAPIAccess returns the access log entries matching filter, newest
+// first.
+func (s *SQLiteStorage) GetAPIAccess(ctx context.Context, filter types.APIAccessFilter) ([]*types.APIAccess, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	var where []string
+	var args []interface{}
+	if !filter.Since.IsZero() {
+		where = append(where, "time >= ?")
+		args = append(args, filter.Since)
+	}
+	if filter.Actor != "" {
+		where = append(where, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.TokenID != "" {
+		where = append(where, "token_id = ?")
+		args = append(args, filter.TokenID)
+	}
+	if filter.MinStatus > 0 {
+		where = append(where, "status >= ?")
+		args = append(args, filter.MinStatus)
+	}
+	query := `SELECT id, time, method, path, actor, token_id, remote, status, latency_ms FROM api_access_log`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY time DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, wrapDBError("get API access log", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []*types.APIAccess
+	for rows.Next() {
+		var a types.APIAccess
+		if err := rows.Scan(&a.ID, &a.Time, &a.Method, &a.Path, &a.Actor, &a.TokenID, &a.Remote, &a.Status, &a.LatencyMS); err != nil {
+			return nil, wrapDBError("scan API access", err)
+		}
+		entries = append(entries, &a)
+	}
+	return entries, wrapDBError("iterate API access log", rows.Err())
+}
+
+// PruneAPIAccess deletes access log entries older than before, returning
+// how many were deleted.
+func (s *SQLiteStorage) PruneAPIAccess(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM api_access_log WHERE time < ?`, before)
+	if err != nil {
+		return 0, wrapDBError("prune API access log", err)
+	}
+	n, _ := result.RowsAffected()
+	return n, nil
+}
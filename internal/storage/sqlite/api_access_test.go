@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestAPIAccessLog(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	entries := []*types.APIAccess{
+		{Method: "POST", Path: "/graphql", Actor: "ci", TokenID: "tok-1", Status: 200, LatencyMS: 12},
+		{Method: "POST", Path: "/graphql", Actor: "ci", TokenID: "tok-1", Status: 429},
+		{Method: "GET", Path: "/events", Actor: "anonymous", Remote: "10.0.0.7", Status: 101, LatencyMS: 60000},
+	}
+	for i, a := range entries {
+		a.Time = base.Add(time.Duration(i) * time.Hour)
+	}
+	if err := store.RecordAPIAccess(ctx, entries); err != nil {
+		t.Fatalf("RecordAPIAccess: %v", err)
+	}
+
+	all, err := store.GetAPIAccess(ctx, types.APIAccessFilter{})
+	if err != nil {
+		t.Fatalf("GetAPIAccess: %v", err)
+	}
+	if len(all) != 3 || all[0].Path != "/events" || all[0].Remote != "10.0.0.7" {
+		t.Fatalf("GetAPIAccess = %v, want 3 entries newest first", all)
+	}
+	for _, tt := range []struct {
+		name   string
+		filter types.APIAccessFilter
+		want   int
+	}{
+		{"since", types.APIAccessFilter{Since: base.Add(30 * time.Minute)}, 2},
+		{"actor", types.APIAccessFilter{Actor: "ci"}, 2},
+		{"token", types.APIAccessFilter{TokenID: "tok-1", MinStatus: 400}, 1},
+		{"limit", types.APIAccessFilter{Limit: 1}, 1},
+	} {
+		got, err := store.GetAPIAccess(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("%s: got %d entries, want %d", tt.name, len(got), tt.want)
+		}
+	}
+
+	n, err := store.PruneAPIAccess(ctx, base.Add(90*time.Minute))
+	if err != nil {
+		t.Fatalf("PruneAPIAccess: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("pruned %d entries, want 2", n)
+	}
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+const apiTokenColumns = `id, name, scopes, created_by, created_at, expires_at, last_used_at, revoked_at`
+
+// CreateAPIToken stores a new API token under the hash of its secret. Names
+// are unique among tokens that haven't been revoked, so a name picks out a
+// single token; creating a second one fails with ErrConflict.
+func (s *SQLiteStorage) CreateAPIToken(ctx context.Context, token *types.APIToken, hash string) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO api_tokens (id, name, token_hash, scopes, created_by, created_at, expires_at)
+		SELECT ?, ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM api_tokens WHERE name = ? AND revoked_at IS NULL)
+	`, token.ID, token.Name, hash, strings.Join(token.Scopes, ","), token.CreatedBy, token.CreatedAt, token.ExpiresAt, token.Name)
+	if err != nil {
+		return wrapDBErrorf(err, "create API token %s", token.Name)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("create API token %s: a token with that name exists (revoke it first): %w", token.Name, ErrConflict)
+	}
+	return nil
+}
+
+// GetAPITokenByHash returns the token whose secret has the given hash,
+// including revoked and expired ones.
+func (s *SQLiteStorage) GetAPITokenByHash(ctx context.Context, hash string) (*types.APIToken, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	row := s.db.QueryRowContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = ?`, hash)
+	token, err := scanAPIToken(row)
+	if err != nil {
+		return nil, wrapDBError("get API token", err)
+	}
+	return token, nil
+}
+
+// ListAPITokens returns every API token, oldest first.
+func (s *SQLiteStorage) ListAPITokens(ctx context.Context) ([]*types.APIToken, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens ORDER BY created_at, id`)
+	if err != nil {
+		return nil, wrapDBError("list API tokens", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tokens []*types.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, wrapDBError("scan API token", err)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, wrapDBError("iterate API tokens", rows.Err())
+}
+
+// RevokeAPIToken revokes a token by ID, or by name the one unrevoked token
+// with that name. Revoking a revoked token by ID keeps its original
+// revocation time.
+func (s *SQLiteStorage) RevokeAPIToken(ctx context.Context, idOrName string, at time.Time) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, ?)
+		WHERE id = ? OR (name = ? AND revoked_at IS NULL)
+	`, at, idOrName, idOrName)
+	if err != nil {
+		return wrapDBErrorf(err, "revoke API token %s", idOrName)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return wrapDBErrorf(sql.ErrNoRows, "revoke API token %s", idOrName)
+	}
+	return nil
+}
+
+// TouchAPIToken records that a token was used at the given time.
+func (s *SQLiteStorage) TouchAPIToken(ctx context.Context, id string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = ? WHERE id = ?`, at, id)
+	return wrapDBErrorf(err, "touch API token %s", id)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIToken(row rowScanner) (*types.APIToken, error) {
+	var token types.APIToken
+	var scopes string
+	var expires, used, revoked sql.NullTime
+	if err := row.Scan(&token.ID, &token.Name, &scopes, &token.CreatedBy, &token.CreatedAt, &expires, &used, &revoked); err != nil {
+		return nil, err
+	}
+	if scopes != "" {
+		token.Scopes = strings.Split(scopes, ",")
+	}
+	for _, t := range []struct {
+		src sql.NullTime
+		dst **time.Time
+	}{{expires, &token.ExpiresAt}, {used, &token.LastUsedAt}, {revoked, &token.RevokedAt}} {
+		if t.src.Valid {
+			v := t.src.Time
+			*t.dst = &v
+		}
+	}
+	return &token, nil
+}
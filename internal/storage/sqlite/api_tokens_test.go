@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestAPITokens(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	expires := created.Add(30 * 24 * time.Hour)
+	for _, tok := range []*types.APIToken{
+		{ID: "tok-1", Name: "ci", Scopes: []string{"read", "admin"}, CreatedBy: "alice", CreatedAt: created, ExpiresAt: &expires},
+		{ID: "tok-2", Name: "dashboard", Scopes: []string{"read"}, CreatedAt: created.Add(time.Hour)},
+	} {
+		if err := store.CreateAPIToken(ctx, tok, "hash-"+tok.ID); err != nil {
+			t.Fatalf("CreateAPIToken: %v", err)
+		}
+	}
+
+	got, err := store.GetAPITokenByHash(ctx, "hash-tok-1")
+	if err != nil {
+		t.Fatalf("GetAPITokenByHash: %v", err)
+	}
+	if got.Name != "ci" || len(got.Scopes) != 2 || got.ExpiresAt == nil || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("GetAPITokenByHash = %+v", got)
+	}
+	if _, err := store.GetAPITokenByHash(ctx, "nope"); !IsNotFound(err) {
+		t.Errorf("unknown hash: err = %v, want not found", err)
+	}
+
+	if err := store.TouchAPIToken(ctx, "tok-2", created.Add(2*time.Hour)); err != nil {
+		t.Fatalf("TouchAPIToken: %v", err)
+	}
+	if err := store.RevokeAPIToken(ctx, "dashboard", created.Add(3*time.Hour)); err != nil {
+		t.Fatalf("RevokeAPIToken: %v", err)
+	}
+	if err := store.RevokeAPIToken(ctx, "nope", created); !IsNotFound(err) {
+		t.Errorf("revoking unknown token: err = %v, want not found", err)
+	}
+
+	// An unrevoked token's name can't be reused; a revoked one's can, and
+	// revoking by name then leaves the old token alone
+	dup := &types.APIToken{ID: "tok-3", Name: "ci", Scopes: []string{"read"}, CreatedAt: created}
+	if err := store.CreateAPIToken(ctx, dup, "hash-tok-3"); !IsConflict(err) {
+		t.Errorf("duplicate name: err = %v, want conflict", err)
+	}
+	rotated := &types.APIToken{ID: "tok-4", Name: "dashboard", Scopes: []string{"read"}, CreatedAt: created.Add(5 * time.Hour)}
+	if err := store.CreateAPIToken(ctx, rotated, "hash-tok-4"); err != nil {
+		t.Fatalf("reusing a revoked token's name: %v", err)
+	}
+	if err := store.RevokeAPIToken(ctx, "dashboard", created.Add(6*time.Hour)); err != nil {
+		t.Fatalf("RevokeAPIToken: %v", err)
+	}
+	if old, _ := store.GetAPITokenByHash(ctx, "hash-tok-2"); old == nil || !old.RevokedAt.Equal(created.Add(3*time.Hour)) {
+		t.Errorf("revoking by name changed the old token: %+v", old)
+	}
+	if err := store.RevokeAPIToken(ctx, "dashboard", created); !IsNotFound(err) {
+		t.Errorf("revoking by name with no unrevoked token: err = %v, want not found", err)
+	}
+
+	list, err := store.ListAPITokens(ctx)
+	if err != nil {
+		t.Fatalf("ListAPITokens: %v", err)
+	}
+	if len(list) != 3 || list[0].ID != "tok-1" || list[1].ID != "tok-2" || list[2].RevokedAt == nil {
+		t.Fatalf("ListAPITokens = %v", list)
+	}
+	if list[1].LastUsedAt == nil || list[1].RevokedAt == nil || list[1].Active(created.Add(4*time.Hour)) {
+		t.Errorf("dashboard token = %+v, want used and revoked", list[1])
+	}
+	if !list[0].Active(created) || list[0].Active(expires) {
+		t.Errorf("ci token should be active until it expires")
+	}
+}
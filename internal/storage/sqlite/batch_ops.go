@@ -8,8 +8,13 @@ import (
 	"time"
 
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/validation"
 )
 
+// maxSQLVariables caps the number of bound parameters in one IN (...) list.
+// SQLite rejects statements with more than 32766 variables.
+const maxSQLVariables = 1000
+
 // validateBatchIssues validates all issues in a batch and sets timestamps if not provided
 // Uses built-in statuses and types only for backward compatibility.
 func validateBatchIssues(issues []*types.Issue) error {
@@ -155,24 +160,28 @@ func checkForExistingIDs(ctx context.Context, conn *sql.Conn, issues []*types.Is
 		return nil
 	}
 
-	// Check for existing IDs in database using a single query with IN clause
-	placeholders := make([]string, len(ids))
-	args := make([]interface{}, len(ids))
-	for i, id := range ids {
-		placeholders[i] = "?"
-		args[i] = id
-	}
+	// Check for existing IDs in database using IN clauses, chunked to stay
+	// under SQLite's bound-variable limit for very large batches
+	for start := 0; start < len(ids); start += maxSQLVariables {
+		chunk := ids[start:min(start+maxSQLVariables, len(ids))]
+		placeholders := make([]string, len(chunk))
+		args := make([]interface{}, len(chunk))
+		for i, id := range chunk {
+			placeholders[i] = "?"
+			args[i] = id
+		}
 
-	query := fmt.Sprintf("SELECT id FROM issues WHERE id IN (%s) LIMIT 1", strings.Join(placeholders, ","))
-	var existingID string
-	err := conn.QueryRowContext(ctx, query, args...).Scan(&existingID)
-	if err == nil {
-		// Found an existing ID
-		return fmt.Errorf("issue ID %s already exists", existingID)
-	}
-	if err != sql.ErrNoRows {
-		// Unexpected error
-		return fmt.Errorf("failed to check for existing IDs: %w", err)
+		query := fmt.Sprintf("SELECT id FROM issues WHERE id IN (%s) LIMIT 1", strings.Join(placeholders, ","))
+		var existingID string
+		err := conn.QueryRowContext(ctx, query, args...).Scan(&existingID)
+		if err == nil {
+			// Found an existing ID
+			return fmt.Errorf("issue ID %s already exists", existingID)
+		}
+		if err != sql.ErrNoRows {
+			// Unexpected error
+			return fmt.Errorf("failed to check for existing IDs: %w", err)
+		}
 	}
 
 	return nil
@@ -251,6 +260,13 @@ func (s *SQLiteStorage) CreateIssuesWithFullOptions(ctx context.Context, issues
 		return nil
 	}
 
+	if err := checkLock(ctx, s.db, "", actor); err != nil {
+		return err
+	}
+	if err := validation.CheckCreate(ctx, s, issues...); err != nil {
+		return err
+	}
+
 	// Fetch custom statuses and types for validation
 	customStatuses, err := s.GetCustomStatuses(ctx)
 	if err != nil {
@@ -0,0 +1,170 @@
+//go:build bench
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Bulk write benchmarks measure the import path: one large batch per
+// iteration into a fresh database, reported per item so results are
+// comparable across batch sizes.
+
+const bulkBenchSize = 10000
+
+// newBulkBenchStore opens an empty file-backed store (WAL, real fsync
+// behavior) outside the timed region.
+func newBulkBenchStore(b *testing.B) *SQLiteStorage {
+	b.Helper()
+	ctx := context.Background()
+	store, err := New(ctx, filepath.Join(b.TempDir(), "bulk.db"))
+	if err != nil {
+		b.Fatalf("Failed to create storage: %v", err)
+	}
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		b.Fatalf("Failed to set issue_prefix: %v", err)
+	}
+	b.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func bulkBenchIssues(n int) []*types.Issue {
+	issues := make([]*types.Issue, n)
+	for i := range issues {
+		issues[i] = &types.Issue{
+			ID:          fmt.Sprintf("bd-%d", i+1),
+			Title:       fmt.Sprintf("Bulk issue %d", i+1),
+			Description: "Imported from a large JSONL file",
+			Status:      types.StatusOpen,
+			Priority:    i % 5,
+			IssueType:   types.TypeTask,
+		}
+	}
+	return issues
+}
+
+// BenchmarkCreateIssues_Bulk10K benchmarks a single 10K-issue batch insert
+func BenchmarkCreateIssues_Bulk10K(b *testing.B) {
+	startBenchmarkProfiling(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		store := newBulkBenchStore(b)
+		issues := bulkBenchIssues(bulkBenchSize)
+		b.StartTimer()
+
+		if err := store.CreateIssues(ctx, issues, "bench"); err != nil {
+			b.Fatalf("CreateIssues failed: %v", err)
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N*bulkBenchSize), "µs/issue")
+}
+
+// BenchmarkAddDependencies_Bulk10K benchmarks batching 10K blocking
+// dependencies with a single blocked-cache rebuild
+func BenchmarkAddDependencies_Bulk10K(b *testing.B) {
+	startBenchmarkProfiling(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		store := newBulkBenchStore(b)
+		if err := store.CreateIssues(ctx, bulkBenchIssues(bulkBenchSize+1), "bench"); err != nil {
+			b.Fatalf("CreateIssues failed: %v", err)
+		}
+		deps := make([]*types.Dependency, bulkBenchSize)
+		for j := range deps {
+			deps[j] = &types.Dependency{
+				IssueID:     fmt.Sprintf("bd-%d", j+2),
+				DependsOnID: fmt.Sprintf("bd-%d", j+1),
+				Type:        types.DepBlocks,
+			}
+		}
+		b.StartTimer()
+
+		if err := store.AddDependencies(ctx, deps, "bench", nil); err != nil {
+			b.Fatalf("AddDependencies failed: %v", err)
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N*bulkBenchSize), "µs/dep")
+}
+
+// BenchmarkAddDependency_Loop1K is the per-call baseline for
+// BenchmarkAddDependencies_Bulk10K (smaller because it is quadratic)
+func BenchmarkAddDependency_Loop1K(b *testing.B) {
+	const n = 1000
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		store := newBulkBenchStore(b)
+		if err := store.CreateIssues(ctx, bulkBenchIssues(n+1), "bench"); err != nil {
+			b.Fatalf("CreateIssues failed: %v", err)
+		}
+		b.StartTimer()
+
+		for j := 0; j < n; j++ {
+			dep := &types.Dependency{
+				IssueID:     fmt.Sprintf("bd-%d", j+2),
+				DependsOnID: fmt.Sprintf("bd-%d", j+1),
+				Type:        types.DepBlocks,
+			}
+			if err := store.AddDependency(ctx, dep, "bench"); err != nil {
+				b.Fatalf("AddDependency failed: %v", err)
+			}
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N*n), "µs/dep")
+}
+
+// BenchmarkAddLabels_Bulk10K benchmarks batching two labels on each of 5K issues
+func BenchmarkAddLabels_Bulk10K(b *testing.B) {
+	startBenchmarkProfiling(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		store := newBulkBenchStore(b)
+		if err := store.CreateIssues(ctx, bulkBenchIssues(bulkBenchSize/2), "bench"); err != nil {
+			b.Fatalf("CreateIssues failed: %v", err)
+		}
+		labels := make([]types.Label, 0, bulkBenchSize)
+		for j := 1; j <= bulkBenchSize/2; j++ {
+			id := fmt.Sprintf("bd-%d", j)
+			labels = append(labels, types.Label{IssueID: id, Label: "team"}, types.Label{IssueID: id, Label: fmt.Sprintf("area:%d", j%7)})
+		}
+		b.StartTimer()
+
+		if err := store.AddLabels(ctx, labels, "bench", nil); err != nil {
+			b.Fatalf("AddLabels failed: %v", err)
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N*bulkBenchSize), "µs/label")
+}
+
+// BenchmarkGetIssue_Large benchmarks point lookups, which export and
+// auto-flush run once per dirty issue
+func BenchmarkGetIssue_Large(b *testing.B) {
+	store, cleanup := setupLargeBenchDB(b)
+	defer cleanup()
+	ctx := context.Background()
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{Limit: 1000})
+	if err != nil || len(issues) == 0 {
+		b.Fatalf("Failed to get issues: %v", err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := store.GetIssue(ctx, issues[i%len(issues)].ID); err != nil {
+			b.Fatalf("GetIssue failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func createBulkTestIssues(t *testing.T, store *SQLiteStorage, n int) []*types.Issue {
+	t.Helper()
+	issues := make([]*types.Issue, n)
+	for i := range issues {
+		issues[i] = &types.Issue{
+			ID:        fmt.Sprintf("bd-%d", i+1),
+			Title:     fmt.Sprintf("Issue %d", i+1),
+			Status:    types.StatusOpen,
+			Priority:  2,
+			IssueType: types.TypeTask,
+		}
+	}
+	if err := store.CreateIssues(context.Background(), issues, "test"); err != nil {
+		t.Fatalf("CreateIssues failed: %v", err)
+	}
+	return issues
+}
+
+func TestAddDependencies(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	createBulkTestIssues(t, store, 4)
+
+	deps := []*types.Dependency{
+		{IssueID: "bd-2", DependsOnID: "bd-1", Type: types.DepBlocks},
+		{IssueID: "bd-3", DependsOnID: "bd-2", Type: types.DepBlocks},
+		{IssueID: "bd-1", DependsOnID: "bd-3", Type: types.DepBlocks},  // cycle
+		{IssueID: "bd-4", DependsOnID: "bd-99", Type: types.DepBlocks}, // missing target
+		{IssueID: "bd-4", DependsOnID: "bd-4", Type: types.DepRelated}, // self
+		{IssueID: "bd-4", DependsOnID: "bd-1", Type: types.DepRelated},
+	}
+
+	var skipped []string
+	err := store.AddDependencies(ctx, deps, "test", func(dep *types.Dependency, err error) error {
+		skipped = append(skipped, fmt.Sprintf("%s→%s: %v", dep.IssueID, dep.DependsOnID, err))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("AddDependencies failed: %v", err)
+	}
+	if len(skipped) != 3 {
+		t.Fatalf("expected 3 skipped dependencies, got %d: %v", len(skipped), skipped)
+	}
+	for i, want := range []string{"would create a cycle", "dependency target bd-99 not found", "cannot depend on itself"} {
+		if !strings.Contains(skipped[i], want) {
+			t.Errorf("skipped[%d] = %q, want it to mention %q", i, skipped[i], want)
+		}
+	}
+
+	records, err := store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		t.Fatalf("GetAllDependencyRecords failed: %v", err)
+	}
+	if len(records["bd-2"]) != 1 || len(records["bd-3"]) != 1 || len(records["bd-4"]) != 1 || len(records["bd-1"]) != 0 {
+		t.Errorf("unexpected dependency records: %v", records)
+	}
+
+	// The blocked cache is rebuilt once for the batch
+	blocked, err := store.GetBlockedIssueIDs(ctx)
+	if err != nil {
+		t.Fatalf("GetBlockedIssueIDs failed: %v", err)
+	}
+	slices.Sort(blocked)
+	if !slices.Equal(blocked, []string{"bd-2", "bd-3"}) {
+		t.Errorf("blocked = %v, want [bd-2 bd-3]", blocked)
+	}
+
+	events, err := store.GetEvents(ctx, "bd-2", 10)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	found := false
+	for _, e := range events {
+		if e.EventType == types.EventDependencyAdded {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a dependency_added event for bd-2")
+	}
+}
+
+func TestAddDependenciesAbortRollsBack(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	createBulkTestIssues(t, store, 3)
+
+	deps := []*types.Dependency{
+		{IssueID: "bd-2", DependsOnID: "bd-1", Type: types.DepBlocks},
+		{IssueID: "bd-3", DependsOnID: "bd-99", Type: types.DepBlocks},
+	}
+	if err := store.AddDependencies(ctx, deps, "test", nil); err == nil {
+		t.Fatal("expected error for missing target with nil onError")
+	}
+
+	records, err := store.GetDependencyRecords(ctx, "bd-2")
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected batch to roll back, found %d dependencies on bd-2", len(records))
+	}
+}
+
+func TestAddLabels(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	createBulkTestIssues(t, store, 2)
+
+	if err := store.AddLabel(ctx, "bd-1", "existing", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := store.ClearDirtyIssuesByID(ctx, []string{"bd-1", "bd-2"}); err != nil {
+		t.Fatalf("ClearDirtyIssuesByID failed: %v", err)
+	}
+
+	labels := []types.Label{
+		{IssueID: "bd-1", Label: "existing"},
+		{IssueID: "bd-1", Label: "new"},
+		{IssueID: "bd-2", Label: "new"},
+		{IssueID: "bd-99", Label: "orphan"},
+	}
+	var failed []types.Label
+	err := store.AddLabels(ctx, labels, "test", func(l types.Label, err error) error {
+		failed = append(failed, l)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("AddLabels failed: %v", err)
+	}
+	if len(failed) != 1 || failed[0].IssueID != "bd-99" {
+		t.Errorf("expected only bd-99 to fail, got %v", failed)
+	}
+
+	got, err := store.GetLabels(ctx, "bd-1")
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if !slices.Equal(got, []string{"existing", "new"}) {
+		t.Errorf("bd-1 labels = %v", got)
+	}
+
+	// Re-adding an existing label records no event
+	events, err := store.GetEvents(ctx, "bd-1", 20)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	added := 0
+	for _, e := range events {
+		if e.EventType == types.EventLabelAdded {
+			added++
+		}
+	}
+	if added != 2 {
+		t.Errorf("expected 2 label_added events on bd-1, got %d", added)
+	}
+
+	dirty, err := store.GetDirtyIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetDirtyIssues failed: %v", err)
+	}
+	slices.Sort(dirty)
+	if !slices.Equal(dirty, []string{"bd-1", "bd-2"}) {
+		t.Errorf("dirty = %v, want [bd-1 bd-2]", dirty)
+	}
+}
+
+func TestCreateIssuesBeyondVariableLimit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping large batch test in short mode")
+	}
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// More IDs than fit in one IN (...) list
+	n := maxSQLVariables*2 + 10
+	createBulkTestIssues(t, store, n)
+
+	// A second batch colliding with the last chunk must still be rejected
+	dup := []*types.Issue{{ID: fmt.Sprintf("bd-%d", n), Title: "dup", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}}
+	err := store.CreateIssues(context.Background(), dup, "test")
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected already exists error, got %v", err)
+	}
+}
+
+func TestPreparedStatementsSurviveReconnect(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	createBulkTestIssues(t, store, 1)
+
+	if issue, err := store.GetIssue(ctx, "bd-1"); err != nil || issue == nil {
+		t.Fatalf("GetIssue before reconnect: %v, %v", issue, err)
+	}
+	if err := store.reconnect(); err != nil {
+		t.Fatalf("reconnect failed: %v", err)
+	}
+	if issue, err := store.GetIssue(ctx, "bd-1"); err != nil || issue == nil {
+		t.Fatalf("GetIssue after reconnect: %v, %v", issue, err)
+	}
+}
+
+func TestWALSynchronousNormal(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var mode string
+	if err := store.db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("journal_mode: %v", err)
+	}
+	if !strings.EqualFold(mode, "wal") {
+		t.Skipf("journal mode is %s", mode)
+	}
+	var sync int
+	if err := store.db.QueryRow("PRAGMA synchronous").Scan(&sync); err != nil {
+		t.Fatalf("synchronous: %v", err)
+	}
+	if sync != 1 {
+		t.Errorf("synchronous = %d, want 1 (NORMAL)", sync)
+	}
+}
@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SetCC replaces the stakeholders cc'd on an issue. An empty list removes
+// them all.
+func (s *SQLiteStorage) SetCC(ctx context.Context, issueID string, cc []string, actor string) error {
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return err
+	}
+	current, err := s.GetCC(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	want := normalizeCC(cc)
+	if slices.Equal(current, want) {
+		return nil
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return replaceCC(ctx, tx, issueID, want, actor)
+	})
+}
+
+// normalizeCC sorts a cc list and drops duplicates, as GetCC returns it.
+func normalizeCC(cc []string) []string {
+	want := slices.Clone(cc)
+	slices.Sort(want)
+	return slices.Compact(want)
+}
+
+// replaceCC writes an issue's normalized cc list in tx.
+func replaceCC(ctx context.Context, tx *sql.Tx, issueID string, want []string, actor string) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM issue_cc WHERE issue_id = ?`, issueID)
+	if err != nil {
+		return wrapDBErrorf(err, "clear cc of %s", issueID)
+	}
+	for _, person := range want {
+		if result, err = tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO issue_cc (issue_id, person) VALUES (?, ?)
+		`, issueID, person); err != nil {
+			return wrapDBErrorf(err, "cc %s on %s", person, issueID)
+		}
+	}
+	comment := "Cleared cc"
+	if len(want) > 0 {
+		comment = "Set cc: " + strings.Join(want, ", ")
+	}
+	return recordRefChange(ctx, tx, result, issueID, actor, comment)
+}
+
+// GetCC returns the stakeholders cc'd on an issue, sorted.
+func (s *SQLiteStorage) GetCC(ctx context.Context, issueID string) ([]string, error) {
+	cc, err := s.GetCCForIssues(ctx, []string{issueID})
+	if err != nil {
+		return nil, err
+	}
+	return cc[issueID], nil
+}
+
+// GetCCForIssues returns the cc lists of many issues in one query. Issues
+// without stakeholders are absent from the map.
+func (s *SQLiteStorage) GetCCForIssues(ctx context.Context, issueIDs []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	args := make([]interface{}, len(issueIDs))
+	for i, id := range issueIDs {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`
+		SELECT issue_id, person FROM issue_cc
+		WHERE issue_id IN (%s)
+		ORDER BY issue_id, person
+	`, buildPlaceholders(len(issueIDs))) // #nosec G201 -- placeholders are generated internally
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, wrapDBError("get cc", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var issueID, person string
+		if err := rows.Scan(&issueID, &person); err != nil {
+			return nil, wrapDBError("scan cc", err)
+		}
+		result[issueID] = append(result[issueID], person)
+	}
+	return result, wrapDBError("iterate cc", rows.Err())
+}
+
+// GetCCIssueIDs returns the IDs of the issues a person is cc'd on.
+func (s *SQLiteStorage) GetCCIssueIDs(ctx context.Context, person string) ([]string, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT issue_id FROM issue_cc WHERE person = ? ORDER BY issue_id`, person)
+	if err != nil {
+		return nil, wrapDBError("get cc issues", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, wrapDBError("scan cc issue", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, wrapDBError("iterate cc issues", rows.Err())
+}
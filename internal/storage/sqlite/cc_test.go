@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestCC(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var ids []string
+	for _, title := range []string{"Billing export", "Audit log"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeFeature}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+
+	if err := store.SetCC(ctx, ids[0], []string{"pm@example.com", "alice", "pm@example.com"}, "test-user"); err != nil {
+		t.Fatalf("SetCC failed: %v", err)
+	}
+	if err := store.SetCC(ctx, ids[1], []string{"alice"}, "test-user"); err != nil {
+		t.Fatalf("SetCC failed: %v", err)
+	}
+
+	cc, err := store.GetCC(ctx, ids[0])
+	if err != nil || !reflect.DeepEqual(cc, []string{"alice", "pm@example.com"}) {
+		t.Errorf("GetCC = %v, %v", cc, err)
+	}
+	if got, _ := store.GetCCIssueIDs(ctx, "alice"); len(got) != 2 {
+		t.Errorf("GetCCIssueIDs(alice) = %v", got)
+	}
+
+	if err := store.SetCC(ctx, ids[0], nil, "test-user"); err != nil {
+		t.Fatalf("clearing SetCC failed: %v", err)
+	}
+	if cc, _ := store.GetCC(ctx, ids[0]); len(cc) != 0 {
+		t.Errorf("after clearing got %v", cc)
+	}
+	events, err := store.GetEvents(ctx, ids[0], 10)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	var comments []string
+	for _, e := range events {
+		if e.Comment != nil {
+			comments = append(comments, *e.Comment)
+		}
+	}
+	if len(comments) < 2 {
+		t.Errorf("events = %v, want set and clear", comments)
+	}
+}
@@ -144,7 +144,7 @@ func TestMigrateChildCountersTable(t *testing.T) {
 		}
 
 		// Delete the parent issue
-		err = s.DeleteIssue(ctx, "bd-parent")
+		err = s.DeleteIssue(ctx, "bd-parent", "test-user")
 		if err != nil {
 			t.Fatalf("failed to delete parent issue: %v", err)
 		}
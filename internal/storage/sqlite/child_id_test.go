@@ -304,7 +304,7 @@ func TestGetNextChildID_ResurrectParent(t *testing.T) {
 	}
 
 	// Delete the parent from database (simulating deletion)
-	if err := store.DeleteIssue(ctx, parent.ID); err != nil {
+	if err := store.DeleteIssue(ctx, parent.ID, "test-user"); err != nil {
 		t.Fatalf("failed to delete parent: %v", err)
 	}
 
@@ -9,6 +9,10 @@ import (
 
 // AddIssueComment adds a comment to an issue
 func (s *SQLiteStorage) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
+	if err := checkLock(ctx, s.db, issueID, author); err != nil {
+		return nil, err
+	}
+
 	// Verify issue exists
 	var exists bool
 	err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM issues WHERE id = ?)`, issueID).Scan(&exists)
@@ -57,6 +61,10 @@ func (s *SQLiteStorage) AddIssueComment(ctx context.Context, issueID, author, te
 // createdAt time from the JSONL file. This prevents timestamp drift during sync cycles.
 // GH#735: Comment created_at timestamps were being overwritten with current time during import.
 func (s *SQLiteStorage) ImportIssueComment(ctx context.Context, issueID, author, text string, createdAt string) (*types.Comment, error) {
+	if err := checkLock(ctx, s.db, issueID, author); err != nil {
+		return nil, err
+	}
+
 	// Verify issue exists
 	var exists bool
 	err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM issues WHERE id = ?)`, issueID).Scan(&exists)
@@ -107,12 +115,16 @@ func (s *SQLiteStorage) GetIssueComments(ctx context.Context, issueID string) ([
 	s.reconnectMu.RLock()
 	defer s.reconnectMu.RUnlock()
 
-	rows, err := s.db.QueryContext(ctx, `
+	stmt, err := s.prepared(ctx, `
 		SELECT id, issue_id, author, text, created_at
 		FROM comments
 		WHERE issue_id = ?
 		ORDER BY created_at ASC
-	`, issueID)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	rows, err := stmt.QueryContext(ctx, issueID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query comments: %w", err)
 	}
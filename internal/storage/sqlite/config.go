@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"strings"
+
+	"github.com/steveyegge/beads/internal/config"
 )
 
 // SetConfig sets a configuration value
@@ -22,6 +24,11 @@ func (s *SQLiteStorage) SetConfig(ctx context.Context, key, value string) error
 
 // GetConfig gets a configuration value
 func (s *SQLiteStorage) GetConfig(ctx context.Context, key string) (string, error) {
+	// Flags and BD_/BEADS_ environment variables override stored config
+	if value, _, ok := config.Override(key); ok {
+		return value, nil
+	}
+
 	// Hold read lock during database operations to prevent reconnect() from
 	// closing the connection mid-query (GH#607 race condition fix)
 	s.reconnectMu.RLock()
@@ -12,7 +12,7 @@ func TestDeleteIssues(t *testing.T) {
 
 	t.Run("delete non-existent issue", func(t *testing.T) {
 		store := newTestStore(t, "file::memory:?mode=memory&cache=private")
-		result, err := store.DeleteIssues(ctx, []string{"bd-999"}, false, false, false)
+		result, err := store.DeleteIssues(ctx, []string{"bd-999"}, false, false, false, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues failed: %v", err)
 		}
@@ -38,7 +38,7 @@ func TestDeleteIssues(t *testing.T) {
 			t.Fatalf("Failed to add dependency: %v", err)
 		}
 		
-		_, err := store.DeleteIssues(ctx, []string{"bd-1"}, false, false, false)
+		_, err := store.DeleteIssues(ctx, []string{"bd-1"}, false, false, false, "test-user")
 		if err == nil {
 			t.Fatal("Expected error when deleting issue with dependents")
 		}
@@ -71,7 +71,7 @@ func TestDeleteIssues(t *testing.T) {
 			t.Fatalf("Failed to add dependency: %v", err)
 		}
 
-		result, err := store.DeleteIssues(ctx, []string{"bd-1"}, true, false, false)
+		result, err := store.DeleteIssues(ctx, []string{"bd-1"}, true, false, false, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues with cascade failed: %v", err)
 		}
@@ -118,7 +118,7 @@ func TestDeleteIssues(t *testing.T) {
 			t.Fatalf("Failed to add dependency: %v", err)
 		}
 
-		result, err := store.DeleteIssues(ctx, []string{"bd-1"}, false, true, false)
+		result, err := store.DeleteIssues(ctx, []string{"bd-1"}, false, true, false, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues with force failed: %v", err)
 		}
@@ -154,7 +154,7 @@ func TestDeleteIssues(t *testing.T) {
 			t.Fatalf("Failed to create issue2: %v", err)
 		}
 
-		result, err := store.DeleteIssues(ctx, []string{"bd-1", "bd-2"}, false, true, true)
+		result, err := store.DeleteIssues(ctx, []string{"bd-1", "bd-2"}, false, true, true, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues dry run failed: %v", err)
 		}
@@ -186,7 +186,7 @@ func TestDeleteIssues(t *testing.T) {
 			t.Fatalf("Failed to create independent2: %v", err)
 		}
 
-		result, err := store.DeleteIssues(ctx, []string{"bd-10", "bd-11"}, false, false, false)
+		result, err := store.DeleteIssues(ctx, []string{"bd-10", "bd-11"}, false, false, false, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues failed: %v", err)
 		}
@@ -221,7 +221,7 @@ func TestDeleteIssue(t *testing.T) {
 	}
 
 	// Delete it
-	if err := store.DeleteIssue(ctx, "bd-1"); err != nil {
+	if err := store.DeleteIssue(ctx, "bd-1", "test-user"); err != nil {
 		t.Fatalf("DeleteIssue failed: %v", err)
 	}
 
@@ -231,7 +231,7 @@ func TestDeleteIssue(t *testing.T) {
 	}
 
 	// Delete non-existent - should error
-	if err := store.DeleteIssue(ctx, "bd-999"); err == nil {
+	if err := store.DeleteIssue(ctx, "bd-999", "test-user"); err == nil {
 		t.Error("DeleteIssue of non-existent should error")
 	}
 }
@@ -268,7 +268,7 @@ func TestDeleteIssueWithComments(t *testing.T) {
 	}
 
 	// Delete the issue
-	if err := store.DeleteIssue(ctx, "bd-1"); err != nil {
+	if err := store.DeleteIssue(ctx, "bd-1", "test-user"); err != nil {
 		t.Fatalf("DeleteIssue failed: %v", err)
 	}
 
@@ -386,7 +386,7 @@ func TestDeleteIssueMarksDependentsDirty(t *testing.T) {
 	}
 
 	// Delete the wisp
-	if err := store.DeleteIssue(ctx, "bd-wisp-1"); err != nil {
+	if err := store.DeleteIssue(ctx, "bd-wisp-1", "test-user"); err != nil {
 		t.Fatalf("Failed to delete wisp: %v", err)
 	}
 
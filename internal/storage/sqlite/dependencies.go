@@ -19,6 +19,10 @@ const (
 
 // AddDependency adds a dependency between issues with cycle prevention
 func (s *SQLiteStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	if err := checkLock(ctx, s.db, dep.IssueID, actor); err != nil {
+		return err
+	}
+
 	// Validate dependency type
 	if !dep.Type.IsValid() {
 		return fmt.Errorf("invalid dependency type: %q (must be non-empty string, max 50 chars)", dep.Type)
@@ -105,30 +109,7 @@ func (s *SQLiteStorage) AddDependency(ctx context.Context, dep *types.Dependency
 		// Skip cycle detection for relates-to (inherently bidirectional)
 		if dep.Type != types.DepRelatesTo {
 			var cycleExists bool
-			err = tx.QueryRowContext(ctx, `
-				WITH RECURSIVE paths AS (
-					SELECT
-						issue_id,
-						depends_on_id,
-						1 as depth
-					FROM dependencies
-					WHERE issue_id = ?
-
-					UNION ALL
-
-					SELECT
-						d.issue_id,
-						d.depends_on_id,
-						p.depth + 1
-					FROM dependencies d
-					JOIN paths p ON d.issue_id = p.depends_on_id
-					WHERE p.depth < ?
-				)
-				SELECT EXISTS(
-					SELECT 1 FROM paths
-					WHERE depends_on_id = ?
-				)
-			`, dep.DependsOnID, maxDependencyDepth, dep.IssueID).Scan(&cycleExists)
+			err = tx.QueryRowContext(ctx, cycleCheckQuery, dep.DependsOnID, maxDependencyDepth, dep.IssueID).Scan(&cycleExists)
 
 			if err != nil {
 				return fmt.Errorf("failed to check for cycles: %w", err)
@@ -181,8 +162,194 @@ func (s *SQLiteStorage) AddDependency(ctx context.Context, dep *types.Dependency
 	})
 }
 
+// cycleCheckQuery reports whether issue_id (third argument) is reachable from
+// depends_on_id (first argument), i.e. whether adding the edge would close a cycle.
+const cycleCheckQuery = `
+	WITH RECURSIVE paths AS (
+		SELECT
+			issue_id,
+			depends_on_id,
+			1 as depth
+		FROM dependencies
+		WHERE issue_id = ?
+
+		UNION ALL
+
+		SELECT
+			d.issue_id,
+			d.depends_on_id,
+			p.depth + 1
+		FROM dependencies d
+		JOIN paths p ON d.issue_id = p.depends_on_id
+		WHERE p.depth < ?
+	)
+	SELECT EXISTS(
+		SELECT 1 FROM paths
+		WHERE depends_on_id = ?
+	)
+`
+
+// AddDependencies adds many dependencies in one transaction using prepared
+// statements. It applies the same lock check, validation and cycle
+// detection as AddDependency, but marks issues dirty and rebuilds the blocked cache once
+// for the whole batch instead of once per dependency, which dominates the
+// cost of importing large dependency graphs.
+//
+// When a dependency fails, onError decides what happens: returning nil skips
+// it and continues, returning an error aborts and rolls back the whole batch.
+// A nil onError aborts on the first failure.
+func (s *SQLiteStorage) AddDependencies(ctx context.Context, deps []*types.Dependency, actor string, onError func(dep *types.Dependency, err error) error) error {
+	if len(deps) == 0 {
+		return nil
+	}
+	if onError == nil {
+		onError = func(_ *types.Dependency, err error) error { return err }
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		lookupStmt, err := tx.PrepareContext(ctx, `SELECT issue_type FROM issues WHERE id = ?`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare issue lookup: %w", err)
+		}
+		defer func() { _ = lookupStmt.Close() }()
+
+		cycleStmt, err := tx.PrepareContext(ctx, cycleCheckQuery)
+		if err != nil {
+			return fmt.Errorf("failed to prepare cycle check: %w", err)
+		}
+		defer func() { _ = cycleStmt.Close() }()
+
+		insertStmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by, metadata, thread_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare dependency insert: %w", err)
+		}
+		defer func() { _ = insertStmt.Close() }()
+
+		eventStmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, comment)
+			VALUES (?, ?, ?, ?)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare event insert: %w", err)
+		}
+		defer func() { _ = eventStmt.Close() }()
+
+		// issueType returns the type of an issue, or "" if it doesn't exist
+		issueType := func(id string) (types.IssueType, error) {
+			var t types.IssueType
+			err := lookupStmt.QueryRowContext(ctx, id).Scan(&t)
+			if err == sql.ErrNoRows {
+				return "", nil
+			}
+			return t, err
+		}
+
+		addOne := func(dep *types.Dependency) error {
+			if err := checkLock(ctx, tx, dep.IssueID, actor); err != nil {
+				return err
+			}
+			if !dep.Type.IsValid() {
+				return fmt.Errorf("invalid dependency type: %q (must be non-empty string, max 50 chars)", dep.Type)
+			}
+			sourceType, err := issueType(dep.IssueID)
+			if err != nil {
+				return fmt.Errorf("failed to check issue %s: %w", dep.IssueID, err)
+			}
+			if sourceType == "" {
+				return fmt.Errorf("issue %s not found", dep.IssueID)
+			}
+
+			if !strings.HasPrefix(dep.DependsOnID, "external:") {
+				targetType, err := issueType(dep.DependsOnID)
+				if err != nil {
+					return fmt.Errorf("failed to check dependency %s: %w", dep.DependsOnID, err)
+				}
+				if targetType == "" {
+					return fmt.Errorf("dependency target %s not found", dep.DependsOnID)
+				}
+				if dep.IssueID == dep.DependsOnID {
+					return fmt.Errorf("issue cannot depend on itself")
+				}
+				if dep.Type == types.DepParentChild && sourceType == types.TypeEpic && targetType != types.TypeEpic {
+					return fmt.Errorf("invalid parent-child dependency: parent (%s) cannot depend on child (%s). Use: bd dep add %s %s --type parent-child",
+						dep.IssueID, dep.DependsOnID, dep.DependsOnID, dep.IssueID)
+				}
+			}
+
+			if dep.Type != types.DepRelatesTo {
+				var cycleExists bool
+				if err := cycleStmt.QueryRowContext(ctx, dep.DependsOnID, maxDependencyDepth, dep.IssueID).Scan(&cycleExists); err != nil {
+					return fmt.Errorf("failed to check for cycles: %w", err)
+				}
+				if cycleExists {
+					return fmt.Errorf("cannot add dependency: would create a cycle (%s → %s → ... → %s)",
+						dep.IssueID, dep.DependsOnID, dep.IssueID)
+				}
+			}
+
+			if dep.CreatedAt.IsZero() {
+				dep.CreatedAt = time.Now()
+			}
+			if dep.CreatedBy == "" {
+				dep.CreatedBy = actor
+			}
+			if _, err := insertStmt.ExecContext(ctx, dep.IssueID, dep.DependsOnID, dep.Type, dep.CreatedAt, dep.CreatedBy, dep.Metadata, dep.ThreadID); err != nil {
+				return fmt.Errorf("failed to add dependency: %w", err)
+			}
+			if _, err := eventStmt.ExecContext(ctx, dep.IssueID, types.EventDependencyAdded, actor,
+				fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID)); err != nil {
+				return fmt.Errorf("failed to record event: %w", err)
+			}
+			return nil
+		}
+
+		var dirtyIDs []string
+		seenDirty := make(map[string]bool)
+		markDirty := func(id string) {
+			if !seenDirty[id] {
+				seenDirty[id] = true
+				dirtyIDs = append(dirtyIDs, id)
+			}
+		}
+		affectsReadyWork := false
+
+		for _, dep := range deps {
+			if err := addOne(dep); err != nil {
+				if abort := onError(dep, err); abort != nil {
+					return abort
+				}
+				continue
+			}
+			markDirty(dep.IssueID)
+			if !strings.HasPrefix(dep.DependsOnID, "external:") {
+				markDirty(dep.DependsOnID)
+			}
+			if dep.Type.AffectsReadyWork() {
+				affectsReadyWork = true
+			}
+		}
+
+		if err := markIssuesDirtyTx(ctx, tx, dirtyIDs); err != nil {
+			return wrapDBError("mark issues dirty after adding dependencies", err)
+		}
+		if affectsReadyWork {
+			if err := s.invalidateBlockedCache(ctx, tx); err != nil {
+				return fmt.Errorf("failed to invalidate blocked cache: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
 // RemoveDependency removes a dependency
 func (s *SQLiteStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return err
+	}
+
 	return s.withTx(ctx, func(tx *sql.Tx) error {
 		// First, check what type of dependency is being removed
 		var depType types.DependencyType
@@ -404,13 +571,17 @@ func (s *SQLiteStorage) GetDependencyRecords(ctx context.Context, issueID string
 	s.reconnectMu.RLock()
 	defer s.reconnectMu.RUnlock()
 
-	rows, err := s.db.QueryContext(ctx, `
+	stmt, err := s.prepared(ctx, `
 		SELECT issue_id, depends_on_id, type, created_at, created_by,
 		       COALESCE(metadata, '{}') as metadata, COALESCE(thread_id, '') as thread_id
 		FROM dependencies
 		WHERE issue_id = ?
 		ORDER BY created_at ASC
-	`, issueID)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependency records: %w", err)
+	}
+	rows, err := stmt.QueryContext(ctx, issueID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get dependency records: %w", err)
 	}
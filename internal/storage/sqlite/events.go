@@ -13,6 +13,10 @@ const limitClause = " LIMIT ?"
 
 // AddComment adds a comment to an issue
 func (s *SQLiteStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return err
+	}
+
 	return s.withTx(ctx, func(tx *sql.Tx) error {
 		// Update issue updated_at timestamp first to verify issue exists
 		now := time.Now()
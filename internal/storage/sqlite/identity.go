@@ -0,0 +1,233 @@
+// Package sqlite implements identity erasure (bd user forget).
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// identityColumns are the columns that attribute a record to a person, by
+// table. Event old and new values are handled by rewriteEventValues.
+var identityColumns = []struct{ table, column string }{
+	{"issues", "assignee"},
+	{"issues", "owner"},
+	{"issues", "created_by"},
+	{"issues", "deleted_by"},
+	{"issues", "sender"},
+	{"issues", "actor"},
+	{"comments", "author"},
+	{"events", "actor"},
+	{"dependencies", "created_by"},
+}
+
+// ReplaceIdentity replaces every attribution to any of names (matched
+// case-insensitively) with replacement, or deletes the person's comments
+// when deleteComments is set. It returns the number of records changed per
+// "table.column" and the IDs of the issues whose exported form changed,
+// which are marked dirty. With dryRun nothing is changed.
+func (s *SQLiteStorage) ReplaceIdentity(ctx context.Context, names []string, replacement string, deleteComments, dryRun bool) (map[string]int, []string, error) {
+	if len(names) == 0 {
+		return map[string]int{}, nil, nil
+	}
+	lowered := make([]interface{}, len(names))
+	for i, n := range names {
+		lowered[i] = strings.ToLower(strings.TrimSpace(n))
+	}
+	in := "(" + strings.TrimSuffix(strings.Repeat("?,", len(names)), ",") + ")"
+
+	counts := make(map[string]int)
+	touched := make(map[string]bool)
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		for _, c := range identityColumns {
+			where := "LOWER(TRIM(" + c.column + ")) IN " + in
+			idColumn := "issue_id"
+			if c.table == "issues" {
+				idColumn = "id"
+			}
+			// #nosec G201 - table and column names come from identityColumns
+			rows, err := tx.QueryContext(ctx, "SELECT "+idColumn+", COUNT(*) FROM "+c.table+" WHERE "+where+" GROUP BY "+idColumn, lowered...)
+			if err != nil {
+				return wrapDBErrorf(err, "find %s.%s", c.table, c.column)
+			}
+			n := 0
+			for rows.Next() {
+				var id string
+				var count int
+				if err := rows.Scan(&id, &count); err != nil {
+					_ = rows.Close()
+					return wrapDBErrorf(err, "scan %s.%s", c.table, c.column)
+				}
+				n += count
+				// Events aren't exported, so they don't change the issue's JSONL
+				if c.table != "events" {
+					touched[id] = true
+				}
+			}
+			_ = rows.Close()
+			if err := rows.Err(); err != nil {
+				return wrapDBErrorf(err, "iterate %s.%s", c.table, c.column)
+			}
+			if n == 0 {
+				continue
+			}
+			counts[c.table+"."+c.column] = n
+			if dryRun {
+				continue
+			}
+
+			var stmt string
+			args := append([]interface{}{}, lowered...)
+			if c.table == "comments" && deleteComments {
+				stmt = "DELETE FROM comments WHERE " + where
+			} else {
+				stmt = "UPDATE " + c.table + " SET " + c.column + " = ? WHERE " + where
+				args = append([]interface{}{replacement}, lowered...)
+			}
+			// #nosec G202 - table and column names come from identityColumns
+			if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+				return wrapDBErrorf(err, "rewrite %s.%s", c.table, c.column)
+			}
+		}
+		for _, column := range []string{"old_value", "new_value"} {
+			n, err := rewriteEventValues(ctx, tx, column, lowered, replacement, dryRun)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				counts["events."+column] = n
+			}
+		}
+		if dryRun {
+			return nil
+		}
+
+		// Bump updated_at so other clones take the anonymized issue on import
+		now := time.Now()
+		for id := range touched {
+			if _, err := tx.ExecContext(ctx, `UPDATE issues SET updated_at = ? WHERE id = ?`, now, id); err != nil {
+				return wrapDBErrorf(err, "touch %s", id)
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO dirty_issues (issue_id, marked_at)
+				VALUES (?, ?)
+				ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+			`, id, now); err != nil {
+				return wrapDBErrorf(err, "mark %s dirty", id)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]string, 0, len(touched))
+	for id := range touched {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if dryRun {
+		return counts, ids, nil
+	}
+
+	// The content hash covers the assignee and creator
+	for _, id := range ids {
+		issue, err := s.GetIssue(ctx, id)
+		if err != nil || issue == nil {
+			continue
+		}
+		if _, err := s.db.ExecContext(ctx, `UPDATE issues SET content_hash = ? WHERE id = ?`, issue.ComputeContentHash(), id); err != nil {
+			return nil, nil, wrapDBErrorf(err, "rehash %s", id)
+		}
+	}
+	return counts, ids, nil
+}
+
+// rewriteEventValues replaces the names in an events value column. Values
+// are JSON (e.g. {"assignee":"alice"} for an update) or plain strings, so
+// each candidate row is decoded and only whole string values are replaced.
+func rewriteEventValues(ctx context.Context, tx *sql.Tx, column string, lowered []interface{}, replacement string, dryRun bool) (int, error) {
+	names := make(map[string]bool, len(lowered))
+	like := make([]string, len(lowered))
+	args := make([]interface{}, len(lowered))
+	for i, n := range lowered {
+		names[n.(string)] = true
+		like[i] = "LOWER(" + column + ") LIKE ?"
+		args[i] = "%" + n.(string) + "%"
+	}
+	// #nosec G202 - column is old_value or new_value
+	rows, err := tx.QueryContext(ctx, "SELECT id, "+column+" FROM events WHERE "+strings.Join(like, " OR "), args...)
+	if err != nil {
+		return 0, wrapDBErrorf(err, "find events.%s", column)
+	}
+	updates := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var value string
+		if err := rows.Scan(&id, &value); err != nil {
+			_ = rows.Close()
+			return 0, wrapDBErrorf(err, "scan events.%s", column)
+		}
+		if rewritten, ok := replaceIdentityValue(value, names, replacement); ok {
+			updates[id] = rewritten
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, wrapDBErrorf(err, "iterate events.%s", column)
+	}
+	if dryRun {
+		return len(updates), nil
+	}
+	for id, value := range updates {
+		// #nosec G202 - column is old_value or new_value
+		if _, err := tx.ExecContext(ctx, "UPDATE events SET "+column+" = ? WHERE id = ?", value, id); err != nil {
+			return 0, wrapDBErrorf(err, "rewrite events.%s", column)
+		}
+	}
+	return len(updates), nil
+}
+
+// replaceIdentityValue replaces the string values of a JSON document (or a
+// plain string) that name the person. It reports whether anything changed.
+func replaceIdentityValue(value string, names map[string]bool, replacement string) (string, bool) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(value), &doc); err != nil {
+		if names[strings.ToLower(strings.TrimSpace(value))] {
+			return replacement, true
+		}
+		return value, false
+	}
+	changed := false
+	var walk func(v interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case string:
+			if names[strings.ToLower(strings.TrimSpace(v))] {
+				changed = true
+				return replacement
+			}
+		case map[string]interface{}:
+			for k, e := range v {
+				v[k] = walk(e)
+			}
+		case []interface{}:
+			for i, e := range v {
+				v[i] = walk(e)
+			}
+		}
+		return v
+	}
+	doc = walk(doc)
+	if !changed {
+		return value, false
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return value, false
+	}
+	return string(data), true
+}
@@ -0,0 +1,130 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ImportIssueExtras replaces the metadata imported issues carry outside the
+// issues table (refs, security advisory, repro, votes, cc list, visibility
+// and key results) with the values on each issue, in one transaction. The
+// current values are read for the whole batch up front, and only issues
+// whose values differ are lock-checked and written, so re-importing an
+// unchanged JSONL costs seven queries rather than several per issue.
+//
+// Private issues stay private: the committed JSONL never carries them, so a
+// copy arriving without a level is a stale one.
+//
+// When an issue fails, onError decides what happens, as in AddDependencies:
+// returning nil undoes that issue's writes and continues, returning an error
+// aborts and rolls back the whole batch. A nil onError aborts on the first
+// failure.
+func (s *SQLiteStorage) ImportIssueExtras(ctx context.Context, issues []*types.Issue, actor string, onError func(issue *types.Issue, err error) error) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	if onError == nil {
+		onError = func(_ *types.Issue, err error) error { return err }
+	}
+
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	refs, err := s.GetRefsForIssues(ctx, ids)
+	if err != nil {
+		return err
+	}
+	security, err := s.GetSecurityForIssues(ctx, ids)
+	if err != nil {
+		return err
+	}
+	repro, err := s.GetReproForIssues(ctx, ids)
+	if err != nil {
+		return err
+	}
+	votes, err := s.GetVotesForIssues(ctx, ids)
+	if err != nil {
+		return err
+	}
+	cc, err := s.GetCCForIssues(ctx, ids)
+	if err != nil {
+		return err
+	}
+	visibility, err := s.GetVisibilityForIssues(ctx, ids)
+	if err != nil {
+		return err
+	}
+	keyResults, err := s.GetKeyResultsForIssues(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		importOne := func(issue *types.Issue) error {
+			id := issue.ID
+			var writes []func() error
+			if !refsEqual(refs[id], issue.Refs) {
+				writes = append(writes, func() error { return replaceRefs(ctx, tx, id, issue.Refs) })
+			}
+			if !security[id].Equal(issue.Security) {
+				writes = append(writes, func() error { return replaceSecurity(ctx, tx, id, issue.Security, actor) })
+			}
+			if !repro[id].Equal(issue.Repro) {
+				writes = append(writes, func() error { return replaceRepro(ctx, tx, id, issue.Repro, actor) })
+			}
+			if !votesEqual(votes[id], issue.Votes) {
+				writes = append(writes, func() error { return replaceVotes(ctx, tx, id, issue.Votes) })
+			}
+			if want := normalizeCC(issue.CC); !slices.Equal(cc[id], want) {
+				writes = append(writes, func() error { return replaceCC(ctx, tx, id, want, actor) })
+			}
+			level, err := normalizeVisibility(issue.Visibility)
+			if err != nil {
+				return err
+			}
+			if current := visibility[id]; level != current && (current != "private" || level == "public") {
+				writes = append(writes, func() error { return replaceVisibility(ctx, tx, id, level, actor) })
+			}
+			if !types.KeyResultsEqual(keyResults[id], issue.KeyResults) {
+				if err := validateKeyResults(issue.KeyResults); err != nil {
+					return err
+				}
+				writes = append(writes, func() error { return replaceKeyResults(ctx, tx, id, issue.KeyResults, actor) })
+			}
+			if len(writes) == 0 {
+				return nil
+			}
+
+			if err := checkLock(ctx, tx, id, actor); err != nil {
+				return err
+			}
+			// A savepoint per issue lets a failure skip just that issue
+			if _, err := tx.ExecContext(ctx, `SAVEPOINT import_extras`); err != nil {
+				return wrapDBError("begin issue savepoint", err)
+			}
+			for _, write := range writes {
+				if err := write(); err != nil {
+					_, _ = tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT import_extras`)
+					_, _ = tx.ExecContext(ctx, `RELEASE SAVEPOINT import_extras`)
+					return err
+				}
+			}
+			_, err = tx.ExecContext(ctx, `RELEASE SAVEPOINT import_extras`)
+			return wrapDBError("release issue savepoint", err)
+		}
+
+		for _, issue := range issues {
+			if err := importOne(issue); err != nil {
+				if abort := onError(issue, fmt.Errorf("import metadata of %s: %w", issue.ID, err)); abort != nil {
+					return abort
+				}
+			}
+		}
+		return nil
+	})
+}
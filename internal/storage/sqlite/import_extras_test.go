@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestImportIssueExtras(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var ids []string
+	for _, title := range []string{"Roadmap", "Salary bands", "Q3 goal"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	if err := store.SetVisibility(ctx, ids[1], "private", "test-user"); err != nil {
+		t.Fatalf("SetVisibility failed: %v", err)
+	}
+
+	incoming := []*types.Issue{
+		{ID: ids[0], Refs: []*types.Ref{{Type: types.RefGitHub, Value: "gh-12"}}, CC: []string{"bob", "alice", "bob"},
+			Votes: []*types.Vote{{Voter: "carol", Value: 1}}, Visibility: "public"},
+		{ID: ids[1], Repro: &types.Repro{OS: "linux", Steps: "run it"}},
+		{ID: ids[2], KeyResults: []*types.KeyResult{{Title: ""}}, CC: []string{"dave"}},
+	}
+	var failed []string
+	skip := func(issue *types.Issue, err error) error {
+		failed = append(failed, issue.ID)
+		return nil
+	}
+	if err := store.ImportIssueExtras(ctx, incoming, "import", skip); err != nil {
+		t.Fatalf("ImportIssueExtras failed: %v", err)
+	}
+	if len(failed) != 1 || failed[0] != ids[2] {
+		t.Errorf("failed issues = %v, want only %s", failed, ids[2])
+	}
+
+	if refs, _ := store.GetRefsForIssues(ctx, ids); len(refs[ids[0]]) != 1 {
+		t.Errorf("refs = %v", refs)
+	}
+	cc, _ := store.GetCCForIssues(ctx, ids)
+	if len(cc[ids[0]]) != 2 || cc[ids[0]][0] != "alice" || len(cc[ids[2]]) != 0 {
+		t.Errorf("cc = %v, want the failed issue left alone", cc)
+	}
+	if repro, _ := store.GetReproForIssues(ctx, ids); repro[ids[1]] == nil || repro[ids[1]].OS != "linux" {
+		t.Errorf("repro = %v", repro)
+	}
+	levels, _ := store.GetVisibilityForIssues(ctx, ids)
+	if levels[ids[0]] != "public" || levels[ids[1]] != "private" {
+		t.Errorf("visibility = %v, want private issue to stay private", levels)
+	}
+
+	// Re-importing the same values writes nothing
+	events, err := store.GetEvents(ctx, ids[0], 100)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if err := store.ImportIssueExtras(ctx, incoming[:2], "import", nil); err != nil {
+		t.Fatalf("second ImportIssueExtras failed: %v", err)
+	}
+	again, _ := store.GetEvents(ctx, ids[0], 100)
+	if len(again) != len(events) {
+		t.Errorf("unchanged import recorded %d new events", len(again)-len(events))
+	}
+
+	if err := store.ImportIssueExtras(ctx, incoming[2:], "import", nil); err == nil {
+		t.Error("nil onError should abort on an invalid key result")
+	}
+}
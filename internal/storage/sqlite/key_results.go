@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// SetKeyResults replaces the key results of a goal. An empty list removes
+// them.
+func (s *SQLiteStorage) SetKeyResults(ctx context.Context, issueID string, krs []*types.KeyResult, actor string) error {
+	if err := validateKeyResults(krs); err != nil {
+		return err
+	}
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return err
+	}
+	current, err := s.GetKeyResults(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if types.KeyResultsEqual(current, krs) {
+		return nil
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return replaceKeyResults(ctx, tx, issueID, krs, actor)
+	})
+}
+
+func validateKeyResults(krs []*types.KeyResult) error {
+	for _, kr := range krs {
+		if strings.TrimSpace(kr.Title) == "" {
+			return fmt.Errorf("key result title cannot be empty")
+		}
+	}
+	return nil
+}
+
+// replaceKeyResults writes a goal's key results in tx.
+func replaceKeyResults(ctx context.Context, tx *sql.Tx, issueID string, krs []*types.KeyResult, actor string) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM issue_key_results WHERE issue_id = ?`, issueID)
+	if err != nil {
+		return wrapDBErrorf(err, "clear key results of %s", issueID)
+	}
+	for i, kr := range krs {
+		if result, err = tx.ExecContext(ctx, `
+			INSERT INTO issue_key_results (issue_id, position, title, issues, start, target, current, unit)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, issueID, i+1, kr.Title, strings.Join(kr.Issues, ","), kr.Start, kr.Target, kr.Current, kr.Unit); err != nil {
+			return wrapDBErrorf(err, "set key results of %s", issueID)
+		}
+	}
+	comment := "Cleared key results"
+	if len(krs) > 0 {
+		comment = fmt.Sprintf("Set %d key result(s)", len(krs))
+	}
+	return recordRefChange(ctx, tx, result, issueID, actor, comment)
+}
+
+// GetKeyResults returns a goal's key results, in order.
+func (s *SQLiteStorage) GetKeyResults(ctx context.Context, issueID string) ([]*types.KeyResult, error) {
+	byIssue, err := s.GetKeyResultsForIssues(ctx, []string{issueID})
+	if err != nil {
+		return nil, err
+	}
+	return byIssue[issueID], nil
+}
+
+// GetKeyResultsForIssues returns the key results of many issues in one
+// query. Issues without key results are absent from the map.
+func (s *SQLiteStorage) GetKeyResultsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.KeyResult, error) {
+	result := make(map[string][]*types.KeyResult)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	args := make([]interface{}, len(issueIDs))
+	for i, id := range issueIDs {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`
+		SELECT issue_id, title, issues, start, target, current, unit FROM issue_key_results
+		WHERE issue_id IN (%s)
+		ORDER BY issue_id, position
+	`, buildPlaceholders(len(issueIDs))) // #nosec G201 -- placeholders are generated internally
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, wrapDBError("get key results", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var issueID, linked string
+		var start, target, current sql.NullFloat64
+		kr := &types.KeyResult{}
+		if err := rows.Scan(&issueID, &kr.Title, &linked, &start, &target, &current, &kr.Unit); err != nil {
+			return nil, wrapDBError("scan key result", err)
+		}
+		if linked != "" {
+			kr.Issues = strings.Split(linked, ",")
+		}
+		kr.Start = nullFloat(start)
+		kr.Target = nullFloat(target)
+		kr.Current = nullFloat(current)
+		result[issueID] = append(result[issueID], kr)
+	}
+	return result, wrapDBError("iterate key results", rows.Err())
+}
+
+func nullFloat(f sql.NullFloat64) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
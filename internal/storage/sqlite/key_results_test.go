@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestKeyResults(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	goal := &types.Issue{Title: "Reduce p95 latency 30%", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	if err := store.CreateIssue(ctx, goal, "test-user"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	target, current := 140.0, 180.0
+	krs := []*types.KeyResult{
+		{Title: "p95 under 140ms", Target: &target, Current: &current, Unit: "ms"},
+		{Title: "Ship the cache", Issues: []string{"bd-1", "bd-2"}},
+	}
+	if err := store.SetKeyResults(ctx, goal.ID, krs, "test-user"); err != nil {
+		t.Fatalf("SetKeyResults failed: %v", err)
+	}
+	got, err := store.GetKeyResults(ctx, goal.ID)
+	if err != nil {
+		t.Fatalf("GetKeyResults failed: %v", err)
+	}
+	if !types.KeyResultsEqual(got, krs) {
+		t.Errorf("GetKeyResults = %+v, want %+v", got, krs)
+	}
+	if got[0].Start != nil || !got[0].IsMetric() || got[1].IsMetric() {
+		t.Errorf("metric fields not kept: %+v", got)
+	}
+
+	if err := store.SetKeyResults(ctx, goal.ID, []*types.KeyResult{{Title: " "}}, "test-user"); err == nil {
+		t.Error("SetKeyResults accepted an empty title")
+	}
+	if err := store.SetKeyResults(ctx, goal.ID, nil, "test-user"); err != nil {
+		t.Fatalf("SetKeyResults(nil) failed: %v", err)
+	}
+	if got, _ := store.GetKeyResults(ctx, goal.ID); len(got) != 0 {
+		t.Errorf("key results not cleared: %+v", got)
+	}
+}
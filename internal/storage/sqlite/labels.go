@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/validation"
 )
 
 // executeLabelOperation executes a label operation (add or remove) within a transaction
@@ -58,6 +59,10 @@ func (s *SQLiteStorage) executeLabelOperation(
 
 // AddLabel adds a label to an issue
 func (s *SQLiteStorage) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return err
+	}
+
 	return s.executeLabelOperation(
 		ctx, issueID, actor,
 		`INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)`,
@@ -68,8 +73,81 @@ func (s *SQLiteStorage) AddLabel(ctx context.Context, issueID, label, actor stri
 	)
 }
 
+// AddLabels adds many labels in one transaction using prepared statements.
+// Labels an issue already has are left alone and record no event, matching
+// AddLabel. When a label fails to insert (e.g. the issue doesn't exist),
+// onError decides what happens: returning nil skips it, returning an error
+// aborts and rolls back the whole batch. A nil onError aborts on the first
+// failure.
+func (s *SQLiteStorage) AddLabels(ctx context.Context, labels []types.Label, actor string, onError func(label types.Label, err error) error) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	if onError == nil {
+		onError = func(_ types.Label, err error) error { return err }
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		insertStmt, err := tx.PrepareContext(ctx, `INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare label insert: %w", err)
+		}
+		defer func() { _ = insertStmt.Close() }()
+
+		eventStmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, comment)
+			VALUES (?, ?, ?, ?)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare event insert: %w", err)
+		}
+		defer func() { _ = eventStmt.Close() }()
+
+		var dirtyIDs []string
+		seenDirty := make(map[string]bool)
+		for _, l := range labels {
+			if err := checkLock(ctx, tx, l.IssueID, actor); err != nil {
+				if abort := onError(l, err); abort != nil {
+					return abort
+				}
+				continue
+			}
+			result, err := insertStmt.ExecContext(ctx, l.IssueID, l.Label)
+			if err != nil {
+				if abort := onError(l, fmt.Errorf("failed to add label: %w", err)); abort != nil {
+					return abort
+				}
+				continue
+			}
+			rows, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to check rows affected: %w", err)
+			}
+			if rows == 0 {
+				continue // Already had the label
+			}
+			if _, err := eventStmt.ExecContext(ctx, l.IssueID, types.EventLabelAdded, actor, fmt.Sprintf("Added label: %s", l.Label)); err != nil {
+				return fmt.Errorf("failed to record event: %w", err)
+			}
+			if !seenDirty[l.IssueID] {
+				seenDirty[l.IssueID] = true
+				dirtyIDs = append(dirtyIDs, l.IssueID)
+			}
+		}
+
+		return markIssuesDirtyTx(ctx, tx, dirtyIDs)
+	})
+}
+
 // RemoveLabel removes a label from an issue
 func (s *SQLiteStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return err
+	}
+	if err := validation.CheckRemoveLabel(ctx, s, issueID, label); err != nil {
+		return err
+	}
+
 	return s.executeLabelOperation(
 		ctx, issueID, actor,
 		`DELETE FROM labels WHERE issue_id = ? AND label = ?`,
@@ -85,9 +163,11 @@ func (s *SQLiteStorage) RemoveLabel(ctx context.Context, issueID, label, actor s
 // so we don't acquire the lock here to avoid deadlock. Callers must ensure
 // appropriate locking when calling directly.
 func (s *SQLiteStorage) GetLabels(ctx context.Context, issueID string) ([]string, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT label FROM labels WHERE issue_id = ? ORDER BY label
-	`, issueID)
+	stmt, err := s.prepared(ctx, `SELECT label FROM labels WHERE issue_id = ? ORDER BY label`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	rows, err := stmt.QueryContext(ctx, issueID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get labels: %w", err)
 	}
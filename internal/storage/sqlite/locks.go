@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/lock"
+)
+
+// rowQueryer is satisfied by *sql.DB, *sql.Conn and *sql.Tx.
+type rowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// checkLock refuses a mutation of issueID by actor while the issue is
+// locked or the project frozen (bd lock, bd freeze). An empty issueID
+// checks a create.
+func checkLock(ctx context.Context, q rowQueryer, issueID, actor string) error {
+	return lock.Check(ctx, issueID, actor, func(id string) (bool, string, error) {
+		return isLocked(ctx, q, id)
+	})
+}
+
+// checkLocks is checkLock for several issues.
+func checkLocks(ctx context.Context, q rowQueryer, issueIDs []string, actor string) error {
+	for _, id := range issueIDs {
+		if err := checkLock(ctx, q, id, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isLocked reports whether issueID carries the lock label, and the reason
+// recorded by its latest lock comment.
+func isLocked(ctx context.Context, q rowQueryer, issueID string) (bool, string, error) {
+	var n int
+	if err := q.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM labels WHERE issue_id = ? AND label = ?
+	`, issueID, lock.Label).Scan(&n); err != nil {
+		return false, "", err
+	}
+	if n == 0 {
+		return false, "", nil
+	}
+
+	var text string
+	err := q.QueryRowContext(ctx, `
+		SELECT text FROM comments
+		WHERE issue_id = ? AND text LIKE ?
+		ORDER BY created_at DESC, id DESC LIMIT 1
+	`, issueID, strings.TrimSpace(lock.CommentPrefix)+"%").Scan(&text)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return true, "", err
+	}
+	reason, _ := lock.ReasonFromComment(text)
+	return true, reason, nil
+}
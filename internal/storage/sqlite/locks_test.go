@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/lock"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestLockedIssueRefusesChanges(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	if err := config.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	config.Set(lock.KeyAdmins, "boss")
+	defer config.Set(lock.KeyAdmins, []string{})
+
+	issue := &types.Issue{Title: "Release notes", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	other := &types.Issue{Title: "Changelog", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, other, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	bypass := lock.WithBypass(ctx)
+	if _, err := store.AddIssueComment(bypass, issue.ID, "boss", "Locked: release freeze"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddLabel(bypass, issue.ID, lock.Label, "boss"); err != nil {
+		t.Fatal(err)
+	}
+
+	var lockErr *lock.Error
+	mutations := map[string]func() error{
+		"update": func() error {
+			return store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 0}, "alice")
+		},
+		"close": func() error { return store.CloseIssue(ctx, issue.ID, "done", "alice", "") },
+		"comment": func() error {
+			_, err := store.AddIssueComment(ctx, issue.ID, "alice", "hi")
+			return err
+		},
+		"unlabel": func() error { return store.RemoveLabel(ctx, issue.ID, lock.Label, "alice") },
+		"delete":  func() error { return store.DeleteIssue(ctx, issue.ID, "alice") },
+		"bulk delete": func() error {
+			_, err := store.DeleteIssues(ctx, []string{issue.ID}, false, true, false, "alice")
+			return err
+		},
+		"bulk deps": func() error {
+			return store.AddDependencies(ctx, []*types.Dependency{
+				{IssueID: issue.ID, DependsOnID: other.ID, Type: types.DepBlocks},
+			}, "alice", nil)
+		},
+		"tx update": func() error {
+			return store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+				return tx.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 0}, "alice")
+			})
+		},
+	}
+	for name, mutate := range mutations {
+		err := mutate()
+		if !errors.As(err, &lockErr) || lockErr.Reason != "release freeze" {
+			t.Errorf("%s: got %v, want a lock error with the reason", name, err)
+		}
+	}
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 0}, "boss"); err != nil {
+		t.Errorf("admin update refused: %v", err)
+	}
+	if _, err := store.DeleteIssues(ctx, []string{issue.ID}, false, true, false, "boss"); err != nil {
+		t.Errorf("admin delete refused: %v", err)
+	}
+}
+
+func TestFreezeRefusesCreates(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	if err := config.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	config.Set(lock.KeyFreezeEnabled, true)
+	defer config.Set(lock.KeyFreezeEnabled, false)
+
+	issue := &types.Issue{Title: "New work", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	var lockErr *lock.Error
+	if err := store.CreateIssue(ctx, issue, "alice"); !errors.As(err, &lockErr) || !lockErr.Frozen {
+		t.Errorf("create during freeze: got %v", err)
+	}
+	if err := store.CreateIssues(ctx, []*types.Issue{issue}, "alice"); !errors.As(err, &lockErr) {
+		t.Errorf("batch create during freeze: got %v", err)
+	}
+	if err := store.CreateIssue(lock.WithBypass(ctx), issue, "import"); err != nil {
+		t.Errorf("bypassed create refused: %v", err)
+	}
+}
@@ -57,6 +57,19 @@ var migrationsList = []Migration{
 	{"work_type_column", migrations.MigrateWorkTypeColumn},
 	{"source_system_column", migrations.MigrateSourceSystemColumn},
 	{"quality_score_column", migrations.MigrateQualityScoreColumn},
+	{"subset_stubs_table", migrations.MigrateSubsetStubsTable},
+	{"issue_refs_table", migrations.MigrateIssueRefsTable},
+	{"ref_checks_table", migrations.MigrateRefChecksTable},
+	{"pr_status_table", migrations.MigratePRStatusTable},
+	{"issue_security_table", migrations.MigrateIssueSecurityTable},
+	{"issue_repro_table", migrations.MigrateIssueReproTable},
+	{"issue_votes_table", migrations.MigrateIssueVotesTable},
+	{"issue_cc_table", migrations.MigrateIssueCCTable},
+	{"issue_visibility_table", migrations.MigrateIssueVisibilityTable},
+	{"issue_key_results_table", migrations.MigrateIssueKeyResultsTable},
+	{"status_snapshots_table", migrations.MigrateStatusSnapshotsTable},
+	{"api_tokens_table", migrations.MigrateAPITokensTable},
+	{"api_access_log_table", migrations.MigrateAPIAccessLogTable},
 }
 
 // MigrationInfo contains metadata about a migration for inspection
@@ -121,6 +134,19 @@ func getMigrationDescription(name string) string {
 		"work_type_column":             "Adds work_type column for work assignment model (mutex vs open_competition per Decision 006)",
 		"source_system_column":         "Adds source_system column for federation adapter tracking",
 		"quality_score_column":         "Adds quality_score column for aggregate quality (0.0-1.0) set by Refineries",
+		"subset_stubs_table":           "Adds subset_stubs table listing issues a subset clone keeps as stubs",
+		"issue_refs_table":             "Adds issue_refs table for typed external references (github, jira, url, doc)",
+		"ref_checks_table":             "Adds ref_checks table caching external reference liveness checks",
+		"pr_status_table":              "Adds pr_status table caching the state, reviews and CI of linked pull requests",
+		"issue_security_table":         "Adds issue_security table for advisory metadata (CVE, CVSS, affected versions, embargo)",
+		"issue_repro_table":            "Adds issue_repro table for environment and reproduction metadata (OS, version, steps, expected, actual)",
+		"issue_votes_table":            "Adds issue_votes table for stakeholder votes (+1/-1 per voter)",
+		"issue_cc_table":               "Adds issue_cc table for stakeholders kept informed about issues (--cc)",
+		"issue_visibility_table":       "Adds issue_visibility table for public and private issues (internal is the default)",
+		"issue_key_results_table":      "Adds issue_key_results table for the key results of goals (bd goal)",
+		"status_snapshots_table":       "Adds status_snapshots table for daily per-status issue counts (bd stats cfd)",
+		"api_tokens_table":             "Adds api_tokens table for hashed daemon API credentials (bd token)",
+		"api_access_log_table":         "Adds api_access_log table recording daemon API requests (bd server audit)",
 	}
 
 	if desc, ok := descriptions[name]; ok {
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateSubsetStubsTable adds the subset_stubs table. A clone initialized
+// with bd init --subset keeps issues outside its subset as stubs (no long
+// text or comments); this table lists them so exports can restore their full
+// content from the JSONL instead of overwriting it.
+func MigrateSubsetStubsTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS subset_stubs (
+			issue_id TEXT PRIMARY KEY,
+			FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create subset_stubs table: %w", err)
+	}
+	return nil
+}
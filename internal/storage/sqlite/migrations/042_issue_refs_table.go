@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateIssueRefsTable adds the issue_refs table holding an issue's typed
+// external references (github, jira, url, doc) beyond its primary
+// external_ref. Refs are looked up by value when sync integrations match
+// remote items to issues, hence the value index.
+func MigrateIssueRefsTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS issue_refs (
+			issue_id TEXT NOT NULL,
+			type TEXT NOT NULL,
+			value TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (issue_id, value),
+			FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_issue_refs_value ON issue_refs(value);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create issue_refs table: %w", err)
+	}
+	return nil
+}
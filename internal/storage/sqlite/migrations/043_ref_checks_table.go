@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateRefChecksTable adds the ref_checks table caching the last liveness
+// check (bd ref check) of each external reference value. It is keyed by
+// value so the primary external_ref and typed refs share one cache.
+func MigrateRefChecksTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS ref_checks (
+			value TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			detail TEXT NOT NULL DEFAULT '',
+			checked_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create ref_checks table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigratePRStatusTable adds the pr_status table caching the last known
+// state, review status and CI status of each linked pull request (bd pr),
+// keyed by the pull request's URL.
+func MigratePRStatusTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS pr_status (
+			url TEXT PRIMARY KEY,
+			title TEXT NOT NULL DEFAULT '',
+			state TEXT NOT NULL,
+			review TEXT NOT NULL DEFAULT '',
+			ci TEXT NOT NULL DEFAULT '',
+			detail TEXT NOT NULL DEFAULT '',
+			checked_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create pr_status table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateIssueSecurityTable adds the issue_security table holding the
+// advisory metadata of security issues (CVE, CVSS score, affected versions,
+// embargo date). Issues without metadata have no row; the cvss index serves
+// bd list --min-cvss.
+func MigrateIssueSecurityTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS issue_security (
+			issue_id TEXT PRIMARY KEY,
+			cve TEXT NOT NULL DEFAULT '',
+			cvss REAL,
+			affected_versions TEXT NOT NULL DEFAULT '',
+			embargo_until DATETIME,
+			FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_issue_security_cvss ON issue_security(cvss);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create issue_security table: %w", err)
+	}
+	return nil
+}
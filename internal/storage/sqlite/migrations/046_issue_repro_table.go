@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateIssueReproTable adds the issue_repro table holding the environment
+// and reproduction metadata of bug reports (OS, version, steps, expected and
+// actual behavior). Issues without metadata have no row.
+func MigrateIssueReproTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS issue_repro (
+			issue_id TEXT PRIMARY KEY,
+			os TEXT NOT NULL DEFAULT '',
+			version TEXT NOT NULL DEFAULT '',
+			steps TEXT NOT NULL DEFAULT '',
+			expected TEXT NOT NULL DEFAULT '',
+			actual TEXT NOT NULL DEFAULT '',
+			FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create issue_repro table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateIssueVotesTable adds the issue_votes table holding each voter's +1
+// or -1 on an issue (bd vote). A voter has at most one vote per issue.
+func MigrateIssueVotesTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS issue_votes (
+			issue_id TEXT NOT NULL,
+			voter TEXT NOT NULL,
+			value INTEGER NOT NULL CHECK (value IN (-1, 1)),
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (issue_id, voter),
+			FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create issue_votes table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateIssueCCTable adds the issue_cc table listing the stakeholders kept
+// informed about an issue (--cc): people or addresses, besides the assignee.
+func MigrateIssueCCTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS issue_cc (
+			issue_id TEXT NOT NULL,
+			person TEXT NOT NULL,
+			PRIMARY KEY (issue_id, person),
+			FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_issue_cc_person ON issue_cc(person);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create issue_cc table: %w", err)
+	}
+	return nil
+}
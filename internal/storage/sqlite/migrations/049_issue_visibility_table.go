@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateIssueVisibilityTable adds the issue_visibility table holding the
+// visibility of issues that aren't internal, the default: public issues
+// may be shared outside the team, private ones never leave this database.
+func MigrateIssueVisibilityTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS issue_visibility (
+			issue_id TEXT PRIMARY KEY,
+			level TEXT NOT NULL CHECK (level IN ('public', 'private')),
+			FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create issue_visibility table: %w", err)
+	}
+	return nil
+}
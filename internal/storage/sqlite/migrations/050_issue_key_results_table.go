@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateIssueKeyResultsTable adds the issue_key_results table holding the
+// key results of goals (bd goal), in order. Linked issues are stored as a
+// comma-separated list of IDs.
+func MigrateIssueKeyResultsTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS issue_key_results (
+			issue_id TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			title TEXT NOT NULL,
+			issues TEXT NOT NULL DEFAULT '',
+			start REAL,
+			target REAL,
+			current REAL,
+			unit TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (issue_id, position),
+			FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create issue_key_results table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateStatusSnapshotsTable adds the status_snapshots table holding the
+// number of issues in each status at the end of each day (bd stats cfd).
+// Days are local dates (YYYY-MM-DD).
+func MigrateStatusSnapshotsTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS status_snapshots (
+			day TEXT NOT NULL,
+			status TEXT NOT NULL,
+			count INTEGER NOT NULL,
+			PRIMARY KEY (day, status)
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create status_snapshots table: %w", err)
+	}
+	return nil
+}
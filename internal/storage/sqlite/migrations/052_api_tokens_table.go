@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateAPITokensTable adds the api_tokens table holding the daemon API's
+// credentials (bd token). Only the SHA-256 of each secret is stored.
+func MigrateAPITokensTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS api_tokens (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			scopes TEXT NOT NULL DEFAULT '',
+			created_by TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			expires_at DATETIME,
+			last_used_at DATETIME,
+			revoked_at DATETIME
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create api_tokens table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateAPIAccessLogTable adds the api_access_log table, one row per
+// request to the daemon's HTTP API (bd server audit).
+func MigrateAPIAccessLogTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS api_access_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			time DATETIME NOT NULL,
+			method TEXT NOT NULL,
+			path TEXT NOT NULL,
+			actor TEXT NOT NULL,
+			token_id TEXT NOT NULL DEFAULT '',
+			remote TEXT NOT NULL DEFAULT '',
+			status INTEGER NOT NULL,
+			latency_ms INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_api_access_log_time ON api_access_log(time);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create api_access_log table: %w", err)
+	}
+	return nil
+}
@@ -423,7 +423,7 @@ func (s *SQLiteStorage) upsertIssueInTx(ctx context.Context, tx *sql.Tx, issue *
 // It also cleans up related data: dependencies, labels, comments, events, and dirty markers.
 // Returns the number of issues deleted.
 func (s *SQLiteStorage) DeleteIssuesBySourceRepo(ctx context.Context, sourceRepo string) (int, error) {
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.beginTxWithRetry(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -5,10 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 
+	"github.com/steveyegge/beads/internal/atomicfile"
 	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/debug"
 	"github.com/steveyegge/beads/internal/types"
@@ -151,39 +153,19 @@ func (s *SQLiteStorage) exportToRepo(ctx context.Context, repoPath string, issue
 		return issues[i].ID < issues[j].ID
 	})
 
-	// Write atomically using temp file + rename
-	tempPath := fmt.Sprintf("%s.tmp.%d", jsonlPath, os.Getpid())
-	f, err := os.Create(tempPath) // #nosec G304 -- tempPath derived from trusted jsonlPath
-	if err != nil {
-		return 0, fmt.Errorf("failed to create temp file: %w", err)
-	}
-
-	// Ensure cleanup on failure
-	defer func() {
-		if f != nil {
-			_ = f.Close()
-			_ = os.Remove(tempPath)
-		}
-	}()
-
-	// Write JSONL
-	encoder := json.NewEncoder(f)
-	for _, issue := range issues {
-		if err := encoder.Encode(issue); err != nil {
-			return 0, fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
+	// Write atomically; atomicfile fsyncs and journals the swap so a crash
+	// can't leave a truncated JSONL behind
+	_, err = atomicfile.Write(jsonlPath, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		for _, issue := range issues {
+			if err := encoder.Encode(issue); err != nil {
+				return fmt.Errorf("failed to encode issue %s: %w", issue.ID, err)
+			}
 		}
-	}
-
-	// Close before rename
-	if err := f.Close(); err != nil {
-		return 0, fmt.Errorf("failed to close temp file: %w", err)
-	}
-	f = nil // Prevent defer cleanup
-
-	// Atomic rename
-	if err := os.Rename(tempPath, jsonlPath); err != nil {
-		_ = os.Remove(tempPath)
-		return 0, fmt.Errorf("failed to rename temp file: %w", err)
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	// Set file permissions
@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// TestKeysetPagination walks listings two issues at a time while issues
+// are created and closed between pages: every issue that exists
+// throughout must be returned exactly once.
+func TestKeysetPagination(t *testing.T) {
+	now := time.Now()
+	created := []struct {
+		priority int
+		age      time.Duration
+	}{
+		{2, time.Hour}, {2, time.Hour}, // same sort key: ordered by ID
+		{1, 2 * time.Hour}, {3, 30 * time.Minute},
+		{1, 72 * time.Hour}, {0, 96*time.Hour + 123456789}, {2, 100 * time.Hour},
+	}
+
+	list := func(env *testEnv, name string) func(after *types.Cursor) []*types.Issue {
+		return func(after *types.Cursor) []*types.Issue {
+			var issues []*types.Issue
+			var err error
+			if name == "search" {
+				issues, err = env.Store.SearchIssues(env.Ctx, "", types.IssueFilter{Limit: 2, After: after})
+			} else {
+				filter := types.WorkFilter{Limit: 2, SortPolicy: types.SortPolicy(name)}
+				filter.ResumeAfter(after, now)
+				issues, err = env.Store.GetReadyWork(env.Ctx, filter)
+			}
+			if err != nil {
+				t.Fatalf("listing failed: %v", err)
+			}
+			return issues
+		}
+	}
+
+	for _, name := range []string{"search", string(types.SortPolicyPriority), string(types.SortPolicyOldest), string(types.SortPolicyHybrid)} {
+		t.Run(name, func(t *testing.T) {
+			env := newTestEnv(t)
+			want := map[string]bool{}
+			sameTime := now.Add(-time.Hour)
+			for i, c := range created {
+				issue := &types.Issue{
+					Title:     fmt.Sprintf("Issue %d", i),
+					Status:    types.StatusOpen,
+					Priority:  c.priority,
+					IssueType: types.TypeTask,
+					CreatedAt: now.Add(-c.age),
+				}
+				if c.age == time.Hour {
+					issue.CreatedAt = sameTime
+				}
+				if err := env.Store.CreateIssue(env.Ctx, issue, "test-user"); err != nil {
+					t.Fatalf("CreateIssue failed: %v", err)
+				}
+				want[issue.ID] = true
+			}
+			next := list(env, name)
+
+			seen := map[string]bool{}
+			var after *types.Cursor
+			for pages := 1; ; pages++ {
+				if pages > len(created) {
+					t.Fatal("pagination did not terminate")
+				}
+				issues := next(after)
+				if len(issues) == 0 {
+					break
+				}
+				for _, issue := range issues {
+					if seen[issue.ID] {
+						t.Errorf("page %d: %s returned again", pages, issue.ID)
+					}
+					seen[issue.ID] = true
+				}
+
+				if pages == 1 {
+					// An issue that sorts first, and a listed issue closed:
+					// offsets would repeat and skip issues after this
+					env.CreateIssueWith("New and urgent", types.StatusOpen, 0, types.TypeTask)
+					env.Close(issues[0], "Done")
+				}
+
+				// Round-trip the cursor as callers do
+				var filter types.WorkFilter
+				filter.ResumeAfter(after, now)
+				c := filter.PageKey(issues[len(issues)-1])
+				if after, _ = types.DecodeCursor(types.EncodeCursor(c)); after == nil {
+					t.Fatal("cursor did not round-trip")
+				}
+			}
+			for id := range want {
+				if !seen[id] {
+					t.Errorf("%s never returned", id)
+				}
+			}
+		})
+	}
+}
@@ -1,14 +1,17 @@
 package sqlite
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/validation"
 )
 
 // NOTE: createGraphEdgesFromIssueFields and createGraphEdgesFromUpdates removed
@@ -75,6 +78,13 @@ func formatJSONStringArray(arr []string) string {
 
 // CreateIssue creates a new issue
 func (s *SQLiteStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	if err := checkLock(ctx, s.db, "", actor); err != nil {
+		return err
+	}
+	if err := validation.CheckCreate(ctx, s, issue); err != nil {
+		return err
+	}
+
 	// Fetch custom statuses and types for validation
 	customStatuses, err := s.GetCustomStatuses(ctx)
 	if err != nil {
@@ -281,6 +291,23 @@ func (s *SQLiteStorage) CreateIssue(ctx context.Context, issue *types.Issue, act
 // validateBatchIssues validates all issues in a batch and sets timestamps
 // Batch operation functions moved to batch_ops.go
 
+// getIssueQuery is prepared once and reused; GetIssue runs per issue in
+// export and auto-flush loops.
+const getIssueQuery = `
+	SELECT id, content_hash, title, description, design, acceptance_criteria, notes,
+	       status, priority, issue_type, assignee, estimated_minutes,
+	       created_at, created_by, owner, updated_at, closed_at, external_ref,
+	       compaction_level, compacted_at, compacted_at_commit, original_size, source_repo, close_reason,
+	       deleted_at, deleted_by, delete_reason, original_type,
+	       sender, ephemeral, pinned, is_template, crystallizes,
+	       await_type, await_id, timeout_ns, waiters,
+	       hook_bead, role_bead, agent_state, last_activity, role_type, rig, mol_type,
+	       event_kind, actor, target, payload,
+	       due_at, defer_until
+	FROM issues
+	WHERE id = ?
+`
+
 // GetIssue retrieves an issue by ID
 func (s *SQLiteStorage) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
 	// Check for external database file modifications (daemon mode)
@@ -339,20 +366,11 @@ func (s *SQLiteStorage) GetIssue(ctx context.Context, id string) (*types.Issue,
 	var contentHash sql.NullString
 	var compactedAtCommit sql.NullString
 	var owner sql.NullString
-	err := s.db.QueryRowContext(ctx, `
-		SELECT id, content_hash, title, description, design, acceptance_criteria, notes,
-		       status, priority, issue_type, assignee, estimated_minutes,
-		       created_at, created_by, owner, updated_at, closed_at, external_ref,
-		       compaction_level, compacted_at, compacted_at_commit, original_size, source_repo, close_reason,
-		       deleted_at, deleted_by, delete_reason, original_type,
-		       sender, ephemeral, pinned, is_template, crystallizes,
-		       await_type, await_id, timeout_ns, waiters,
-		       hook_bead, role_bead, agent_state, last_activity, role_type, rig, mol_type,
-		       event_kind, actor, target, payload,
-		       due_at, defer_until
-		FROM issues
-		WHERE id = ?
-	`, id).Scan(
+	stmt, err := s.prepared(ctx, getIssueQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+	err = stmt.QueryRowContext(ctx, id).Scan(
 		&issue.ID, &contentHash, &issue.Title, &issue.Description, &issue.Design,
 		&issue.AcceptanceCriteria, &issue.Notes, &issue.Status,
 		&issue.Priority, &issue.IssueType, &assignee, &estimatedMinutes,
@@ -578,7 +596,8 @@ func (s *SQLiteStorage) GetCloseReasonsForIssues(ctx context.Context, issueIDs [
 	return result, nil
 }
 
-// GetIssueByExternalRef retrieves an issue by external reference
+// GetIssueByExternalRef retrieves an issue by external reference, matching
+// its primary external_ref first and its typed refs second
 func (s *SQLiteStorage) GetIssueByExternalRef(ctx context.Context, externalRef string) (*types.Issue, error) {
 	var issue types.Issue
 	var closedAt sql.NullTime
@@ -633,7 +652,13 @@ func (s *SQLiteStorage) GetIssueByExternalRef(ctx context.Context, externalRef s
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, nil
+		// Fall back to the issue's other refs (bd ref add), which sync
+		// integrations use as join keys too
+		id, err := s.getIssueIDByRef(ctx, externalRef)
+		if err != nil || id == "" {
+			return nil, err
+		}
+		return s.GetIssue(ctx, id)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get issue by external_ref: %w", err)
@@ -841,6 +866,10 @@ func manageClosedAt(oldIssue *types.Issue, updates map[string]interface{}, setCl
 
 // UpdateIssue updates fields on an issue
 func (s *SQLiteStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	if err := checkLock(ctx, s.db, id, actor); err != nil {
+		return err
+	}
+
 	// Get old issue for event
 	oldIssue, err := s.GetIssue(ctx, id)
 	if err != nil {
@@ -849,6 +878,9 @@ func (s *SQLiteStorage) UpdateIssue(ctx context.Context, id string, updates map[
 	if oldIssue == nil {
 		return fmt.Errorf("issue %s not found", id)
 	}
+	if err := validation.CheckUpdate(ctx, s, oldIssue, updates); err != nil {
+		return err
+	}
 
 	// Fetch custom statuses for validation
 	customStatuses, err := s.GetCustomStatuses(ctx)
@@ -953,7 +985,7 @@ func (s *SQLiteStorage) UpdateIssue(ctx context.Context, id string, updates map[
 	args = append(args, id)
 
 	// Start transaction
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.beginTxWithRetry(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -1015,6 +1047,10 @@ func (s *SQLiteStorage) UpdateIssue(ctx context.Context, id string, updates map[
 
 // UpdateIssueID updates an issue ID and all its text fields in a single transaction
 func (s *SQLiteStorage) UpdateIssueID(ctx context.Context, oldID, newID string, issue *types.Issue, actor string) error {
+	if err := checkLock(ctx, s.db, oldID, actor); err != nil {
+		return err
+	}
+
 	// Get exclusive connection to ensure PRAGMA applies
 	conn, err := s.db.Conn(ctx)
 	if err != nil {
@@ -1162,10 +1198,14 @@ func (s *SQLiteStorage) ResetCounter(ctx context.Context, prefix string) error {
 // CloseIssue closes an issue with a reason.
 // The session parameter tracks which Claude Code session closed the issue (can be empty).
 func (s *SQLiteStorage) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
+	if err := checkLock(ctx, s.db, id, actor); err != nil {
+		return err
+	}
+
 	now := time.Now()
 
 	// Update with special event handling
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.beginTxWithRetry(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -1310,7 +1350,7 @@ func (s *SQLiteStorage) CreateTombstone(ctx context.Context, id string, actor st
 		return fmt.Errorf("issue not found: %s", id)
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.beginTxWithRetry(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -1369,9 +1409,112 @@ func (s *SQLiteStorage) CreateTombstone(ctx context.Context, id string, actor st
 	return nil
 }
 
+// ApplyTombstone converts an existing issue into the tombstone another clone
+// exported for it. Unlike CreateTombstone it keeps the deletion time, actor
+// and reason from the incoming record, so every clone converges on the same
+// tombstone instead of re-stamping it. Dependencies in both directions are
+// removed, as they are for a local delete.
+func (s *SQLiteStorage) ApplyTombstone(ctx context.Context, tombstone *types.Issue, actor string) error {
+	existing, err := s.GetIssue(ctx, tombstone.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("issue not found: %s", tombstone.ID)
+	}
+
+	deletedAt := time.Now()
+	if tombstone.DeletedAt != nil {
+		deletedAt = *tombstone.DeletedAt
+	}
+	updatedAt := tombstone.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = deletedAt
+	}
+	deletedBy := cmp.Or(tombstone.DeletedBy, actor)
+	originalType := cmp.Or(tombstone.OriginalType, string(existing.IssueType))
+
+	tx, err := s.beginTxWithRetry(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Dependents lose their edge to the deleted issue and must be re-exported
+	rows, err := tx.QueryContext(ctx, `SELECT issue_id FROM dependencies WHERE depends_on_id = ?`, tombstone.ID)
+	if err != nil {
+		return fmt.Errorf("failed to query dependent issues: %w", err)
+	}
+	var dependentIDs []string
+	for rows.Next() {
+		var depID string
+		if err := rows.Scan(&depID); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan dependent issue ID: %w", err)
+		}
+		dependentIDs = append(dependentIDs, depID)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate dependent issues: %w", err)
+	}
+	if len(dependentIDs) > 0 {
+		if err := markIssuesDirtyTx(ctx, tx, dependentIDs); err != nil {
+			return fmt.Errorf("failed to mark dependent issues dirty: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM dependencies WHERE issue_id = ? OR depends_on_id = ?`, tombstone.ID, tombstone.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete dependencies: %w", err)
+	}
+
+	// closed_at must be NULL because of the CHECK constraint
+	// (status = 'closed') = (closed_at IS NOT NULL)
+	_, err = tx.ExecContext(ctx, `
+		UPDATE issues
+		SET status = ?,
+		    closed_at = NULL,
+		    deleted_at = ?,
+		    deleted_by = ?,
+		    delete_reason = ?,
+		    original_type = ?,
+		    updated_at = ?
+		WHERE id = ?
+	`, types.StatusTombstone, deletedAt, deletedBy, tombstone.DeleteReason, originalType, updatedAt, tombstone.ID)
+	if err != nil {
+		return fmt.Errorf("failed to apply tombstone: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, comment)
+		VALUES (?, ?, ?, ?)
+	`, tombstone.ID, "deleted", deletedBy, tombstone.DeleteReason)
+	if err != nil {
+		return fmt.Errorf("failed to record tombstone event: %w", err)
+	}
+
+	if err := markIssuesDirtyTx(ctx, tx, []string{tombstone.ID}); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+
+	if err := s.invalidateBlockedCache(ctx, tx); err != nil {
+		return fmt.Errorf("failed to invalidate blocked cache: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return wrapDBError("commit tombstone transaction", err)
+	}
+	return nil
+}
+
 // DeleteIssue permanently removes an issue from the database
-func (s *SQLiteStorage) DeleteIssue(ctx context.Context, id string) error {
-	tx, err := s.db.BeginTx(ctx, nil)
+func (s *SQLiteStorage) DeleteIssue(ctx context.Context, id, actor string) error {
+	if err := checkLock(ctx, s.db, id, actor); err != nil {
+		return err
+	}
+
+	tx, err := s.beginTxWithRetry(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -1463,12 +1606,17 @@ type DeleteIssuesResult struct {
 // If cascade is false but force is true, deletes issues and orphans their dependents
 // If cascade and force are both false, returns an error if any issue has dependents
 // If dryRun is true, only computes statistics without deleting
-func (s *SQLiteStorage) DeleteIssues(ctx context.Context, ids []string, cascade bool, force bool, dryRun bool) (*DeleteIssuesResult, error) {
+func (s *SQLiteStorage) DeleteIssues(ctx context.Context, ids []string, cascade bool, force bool, dryRun bool, actor string) (*DeleteIssuesResult, error) {
 	if len(ids) == 0 {
 		return &DeleteIssuesResult{}, nil
 	}
+	if !dryRun {
+		if err := checkLocks(ctx, s.db, ids, actor); err != nil {
+			return nil, err
+		}
+	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.beginTxWithRetry(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -1785,6 +1933,49 @@ func (s *SQLiteStorage) SearchIssues(ctx context.Context, query string, filter t
 	s.reconnectMu.RLock()
 	defer s.reconnectMu.RUnlock()
 
+	whereSQL, args := buildSearchWhere(query, filter)
+	if filter.After != nil {
+		clause, afterArgs := searchAfterClause(filter.After)
+		if whereSQL == "" {
+			whereSQL = "WHERE " + clause
+		} else {
+			whereSQL += " AND " + clause
+		}
+		args = append(args, afterArgs...)
+	}
+
+	limitSQL := ""
+	if filter.Limit > 0 {
+		limitSQL = " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	// #nosec G201 - safe SQL with controlled formatting
+	querySQL := fmt.Sprintf(`
+		SELECT id, content_hash, title, description, design, acceptance_criteria, notes,
+		       status, priority, issue_type, assignee, estimated_minutes,
+		       created_at, created_by, owner, updated_at, closed_at, external_ref, source_repo, close_reason,
+		       deleted_at, deleted_by, delete_reason, original_type,
+		       sender, ephemeral, pinned, is_template, crystallizes,
+		       await_type, await_id, timeout_ns, waiters
+		FROM issues
+		%s
+		ORDER BY priority ASC, created_at DESC, id ASC
+		%s
+	`, whereSQL, limitSQL)
+
+	rows, err := s.db.QueryContext(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return s.scanIssues(ctx, rows)
+}
+
+// buildSearchWhere builds the WHERE clause and its arguments for
+// SearchIssues and CountIssues.
+func buildSearchWhere(query string, filter types.IssueFilter) (string, []interface{}) {
 	whereClauses := []string{}
 	args := []interface{}{}
 
@@ -1813,6 +2004,10 @@ func (s *SQLiteStorage) SearchIssues(ctx context.Context, query string, filter t
 		whereClauses = append(whereClauses, "notes LIKE ?")
 		args = append(args, "%"+filter.NotesContains+"%")
 	}
+	if filter.TitleRegex != "" {
+		whereClauses = append(whereClauses, "title REGEXP ?")
+		args = append(args, filter.TitleRegex)
+	}
 
 	if filter.Status != nil {
 		whereClauses = append(whereClauses, "status = ?")
@@ -1923,6 +2118,22 @@ func (s *SQLiteStorage) SearchIssues(ctx context.Context, query string, filter t
 		whereClauses = append(whereClauses, fmt.Sprintf("id IN (SELECT issue_id FROM labels WHERE label IN (%s))", strings.Join(placeholders, ", ")))
 	}
 
+	// Label glob filtering: each pattern must match at least one label
+	for _, pattern := range filter.LabelGlobs {
+		whereClauses = append(whereClauses, "id IN (SELECT issue_id FROM labels WHERE label GLOB ?)")
+		args = append(args, pattern)
+	}
+
+	// Label exclusion: issue must have NONE of these labels
+	if len(filter.NotLabels) > 0 {
+		placeholders := make([]string, len(filter.NotLabels))
+		for i, label := range filter.NotLabels {
+			placeholders[i] = "?"
+			args = append(args, label)
+		}
+		whereClauses = append(whereClauses, fmt.Sprintf("id NOT IN (SELECT issue_id FROM labels WHERE label IN (%s))", strings.Join(placeholders, ", ")))
+	}
+
 	// ID filtering: match specific issue IDs
 	if len(filter.IDs) > 0 {
 		placeholders := make([]string, len(filter.IDs))
@@ -2003,36 +2214,102 @@ func (s *SQLiteStorage) SearchIssues(ctx context.Context, query string, filter t
 		args = append(args, time.Now().Format(time.RFC3339), types.StatusClosed)
 	}
 
+	// Query expression (bd query)
+	if filter.Query != nil {
+		clause, clauseArgs := filter.Query.SQL()
+		whereClauses = append(whereClauses, clause)
+		args = append(args, clauseArgs...)
+	}
+
 	whereSQL := ""
 	if len(whereClauses) > 0 {
 		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
 	}
+	return whereSQL, args
+}
 
-	limitSQL := ""
-	if filter.Limit > 0 {
-		limitSQL = " LIMIT ?"
-		args = append(args, filter.Limit)
+// searchAfterClause returns the condition selecting the issues that come
+// after cursor c in SearchIssues order (priority, newest first, then ID).
+func searchAfterClause(c *types.Cursor) (string, []interface{}) {
+	return "(priority > ? OR (priority = ? AND (created_at < ? OR (created_at = ? AND id > ?))))",
+		[]interface{}{c.Priority, c.Priority, c.CreatedAt, c.CreatedAt, c.ID}
+}
+
+// CountIssues counts issues matching query and filters without loading
+// them, optionally grouped by status, priority, type, assignee or label.
+// Limit and After are ignored.
+func (s *SQLiteStorage) CountIssues(ctx context.Context, query string, filter types.IssueFilter, groupBy string) (*types.IssueCounts, error) {
+	if err := types.ValidateGroupBy(groupBy); err != nil {
+		return nil, err
 	}
 
+	s.checkFreshness()
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	whereSQL, args := buildSearchWhere(query, filter)
+
+	var total int
 	// #nosec G201 - safe SQL with controlled formatting
-	querySQL := fmt.Sprintf(`
-		SELECT id, content_hash, title, description, design, acceptance_criteria, notes,
-		       status, priority, issue_type, assignee, estimated_minutes,
-		       created_at, created_by, owner, updated_at, closed_at, external_ref, source_repo, close_reason,
-		       deleted_at, deleted_by, delete_reason, original_type,
-		       sender, ephemeral, pinned, is_template, crystallizes,
-		       await_type, await_id, timeout_ns, waiters
-		FROM issues
-		%s
-		ORDER BY priority ASC, created_at DESC
-		%s
-	`, whereSQL, limitSQL)
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM issues "+whereSQL, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count issues: %w", err)
+	}
+	if groupBy == "" {
+		return &types.IssueCounts{Total: total}, nil
+	}
 
-	rows, err := s.db.QueryContext(ctx, querySQL, args...)
+	var groupSQL string
+	switch groupBy {
+	case types.GroupByStatus:
+		groupSQL = "SELECT status, COUNT(*) FROM issues " + whereSQL + " GROUP BY status"
+	case types.GroupByPriority:
+		groupSQL = "SELECT priority, COUNT(*) FROM issues " + whereSQL + " GROUP BY priority"
+	case types.GroupByType:
+		groupSQL = "SELECT issue_type, COUNT(*) FROM issues " + whereSQL + " GROUP BY issue_type"
+	case types.GroupByAssignee:
+		groupSQL = "SELECT COALESCE(assignee, ''), COUNT(*) FROM issues " + whereSQL + " GROUP BY COALESCE(assignee, '')"
+	case types.GroupByLabel:
+		// Each issue counts once per label
+		groupSQL = "SELECT label, COUNT(*) FROM labels WHERE issue_id IN (SELECT id FROM issues " + whereSQL + ") GROUP BY label"
+	}
+
+	// #nosec G201 - safe SQL with controlled formatting
+	rows, err := s.db.QueryContext(ctx, groupSQL, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search issues: %w", err)
+		return nil, fmt.Errorf("failed to count issues: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
-	return s.scanIssues(ctx, rows)
+	groups := make(map[string]int)
+	for rows.Next() {
+		var key sql.NullString
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, fmt.Errorf("failed to count issues: %w", err)
+		}
+		group := key.String
+		switch {
+		case groupBy == types.GroupByPriority:
+			p, _ := strconv.Atoi(group)
+			group = types.PriorityGroup(p)
+		case groupBy == types.GroupByAssignee && group == "":
+			group = types.GroupUnassigned
+		}
+		groups[group] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count issues: %w", err)
+	}
+
+	if groupBy == types.GroupByLabel {
+		var labeled int
+		// #nosec G201 - safe SQL with controlled formatting
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(DISTINCT issue_id) FROM labels WHERE issue_id IN (SELECT id FROM issues "+whereSQL+")", args...).Scan(&labeled); err != nil {
+			return nil, fmt.Errorf("failed to count issues: %w", err)
+		}
+		if unlabeled := total - labeled; unlabeled > 0 {
+			groups[types.GroupNoLabels] = unlabeled
+		}
+	}
+	return types.NewIssueCounts(total, groups), nil
 }
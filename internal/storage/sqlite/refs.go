@@ -0,0 +1,252 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// AddRef adds a typed external reference to an issue. Adding a value the
+// issue already has updates its type.
+func (s *SQLiteStorage) AddRef(ctx context.Context, issueID string, ref types.Ref, actor string) error {
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return err
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO issue_refs (issue_id, type, value) VALUES (?, ?, ?)
+			ON CONFLICT (issue_id, value) DO UPDATE SET type = excluded.type
+			WHERE type != excluded.type
+		`, issueID, string(ref.Type), ref.Value)
+		if err != nil {
+			return wrapDBErrorf(err, "add ref to %s", issueID)
+		}
+		return recordRefChange(ctx, tx, result, issueID, actor, fmt.Sprintf("Added ref: %s %s", ref.Type, ref.Value))
+	})
+}
+
+// RemoveRef removes the reference with the given value from an issue.
+// Returns false if the issue had no such reference.
+func (s *SQLiteStorage) RemoveRef(ctx context.Context, issueID, value, actor string) (bool, error) {
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return false, err
+	}
+
+	removed := false
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM issue_refs WHERE issue_id = ? AND value = ?`, issueID, value)
+		if err != nil {
+			return wrapDBErrorf(err, "remove ref from %s", issueID)
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			removed = true
+		}
+		return recordRefChange(ctx, tx, result, issueID, actor, "Removed ref: "+value)
+	})
+	return removed, err
+}
+
+// SetRefs replaces an issue's references, as an import does.
+func (s *SQLiteStorage) SetRefs(ctx context.Context, issueID string, refs []*types.Ref, actor string) error {
+	current, err := s.GetRefs(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if refsEqual(current, refs) {
+		return nil
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return replaceRefs(ctx, tx, issueID, refs)
+	})
+}
+
+// replaceRefs writes an issue's references in tx.
+func replaceRefs(ctx context.Context, tx *sql.Tx, issueID string, refs []*types.Ref) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_refs WHERE issue_id = ?`, issueID); err != nil {
+		return wrapDBErrorf(err, "clear refs of %s", issueID)
+	}
+	for _, ref := range refs {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT OR REPLACE INTO issue_refs (issue_id, type, value) VALUES (?, ?, ?)
+		`, issueID, string(ref.Type), ref.Value); err != nil {
+			return wrapDBErrorf(err, "set ref on %s", issueID)
+		}
+	}
+	return nil
+}
+
+// GetRefs returns an issue's references in the order they were added.
+func (s *SQLiteStorage) GetRefs(ctx context.Context, issueID string) ([]*types.Ref, error) {
+	refs, err := s.GetRefsForIssues(ctx, []string{issueID})
+	if err != nil {
+		return nil, err
+	}
+	return refs[issueID], nil
+}
+
+// GetRefsForIssues returns the references of many issues in one query.
+func (s *SQLiteStorage) GetRefsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Ref, error) {
+	result := make(map[string][]*types.Ref)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	args := make([]interface{}, len(issueIDs))
+	for i, id := range issueIDs {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`
+		SELECT issue_id, type, value FROM issue_refs
+		WHERE issue_id IN (%s)
+		ORDER BY issue_id, created_at, value
+	`, buildPlaceholders(len(issueIDs))) // #nosec G201 -- placeholders are generated internally
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, wrapDBError("get refs", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var issueID, refType, value string
+		if err := rows.Scan(&issueID, &refType, &value); err != nil {
+			return nil, wrapDBError("scan ref", err)
+		}
+		result[issueID] = append(result[issueID], &types.Ref{Type: types.RefType(refType), Value: value})
+	}
+	return result, wrapDBError("iterate refs", rows.Err())
+}
+
+// getIssueIDByRef returns the issue holding a reference with the given
+// value, or "" if none does.
+func (s *SQLiteStorage) getIssueIDByRef(ctx context.Context, value string) (string, error) {
+	var id string
+	err := s.db.QueryRowContext(ctx, `SELECT issue_id FROM issue_refs WHERE value = ? ORDER BY created_at LIMIT 1`, value).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", wrapDBError("look up ref", err)
+	}
+	return id, nil
+}
+
+// recordRefChange records an event and marks the issue dirty when a ref
+// statement changed something.
+func recordRefChange(ctx context.Context, tx *sql.Tx, result sql.Result, issueID, actor, comment string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rows == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, comment)
+		VALUES (?, ?, ?, ?)
+	`, issueID, types.EventUpdated, actor, comment); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO dirty_issues (issue_id, marked_at)
+		VALUES (?, ?)
+		ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+	`, issueID, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	return nil
+}
+
+func refsEqual(a, b []*types.Ref) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[types.Ref]bool, len(a))
+	for _, ref := range a {
+		seen[*ref] = true
+	}
+	for _, ref := range b {
+		if !seen[*ref] {
+			return false
+		}
+	}
+	return true
+}
+
+// SaveRefCheck records the result of checking a reference value.
+func (s *SQLiteStorage) SaveRefCheck(ctx context.Context, check types.RefCheck) error {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO ref_checks (value, status, detail, checked_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (value) DO UPDATE SET
+			status = excluded.status, detail = excluded.detail, checked_at = excluded.checked_at
+	`, check.Value, check.Status, check.Detail, check.CheckedAt)
+	return wrapDBError("save ref check", err)
+}
+
+// GetRefChecks returns the last check of every reference value checked.
+func (s *SQLiteStorage) GetRefChecks(ctx context.Context) (map[string]*types.RefCheck, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT value, status, detail, checked_at FROM ref_checks`)
+	if err != nil {
+		return nil, wrapDBError("get ref checks", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	checks := make(map[string]*types.RefCheck)
+	for rows.Next() {
+		var check types.RefCheck
+		if err := rows.Scan(&check.Value, &check.Status, &check.Detail, &check.CheckedAt); err != nil {
+			return nil, wrapDBError("scan ref check", err)
+		}
+		checks[check.Value] = &check
+	}
+	return checks, wrapDBError("iterate ref checks", rows.Err())
+}
+
+// SavePRStatus records the last fetched state of a pull request.
+func (s *SQLiteStorage) SavePRStatus(ctx context.Context, pr types.PRStatus) error {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO pr_status (url, title, state, review, ci, detail, checked_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (url) DO UPDATE SET
+			title = excluded.title, state = excluded.state, review = excluded.review,
+			ci = excluded.ci, detail = excluded.detail, checked_at = excluded.checked_at
+	`, pr.URL, pr.Title, pr.State, pr.Review, pr.CI, pr.Detail, pr.CheckedAt)
+	return wrapDBError("save pr status", err)
+}
+
+// GetPRStatuses returns the last fetched state of every pull request, by URL.
+func (s *SQLiteStorage) GetPRStatuses(ctx context.Context) (map[string]*types.PRStatus, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT url, title, state, review, ci, detail, checked_at FROM pr_status`)
+	if err != nil {
+		return nil, wrapDBError("get pr statuses", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	statuses := make(map[string]*types.PRStatus)
+	for rows.Next() {
+		var pr types.PRStatus
+		if err := rows.Scan(&pr.URL, &pr.Title, &pr.State, &pr.Review, &pr.CI, &pr.Detail, &pr.CheckedAt); err != nil {
+			return nil, wrapDBError("scan pr status", err)
+		}
+		statuses[pr.URL] = &pr
+	}
+	return statuses, wrapDBError("iterate pr statuses", rows.Err())
+}
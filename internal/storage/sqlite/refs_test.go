@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestRefsAddRemoveAndLookup(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Test issue", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	for _, ref := range []types.Ref{
+		{Type: types.RefJira, Value: "PROJ-1"},
+		{Type: types.RefDoc, Value: "docs/design.md"},
+		{Type: types.RefJira, Value: "PROJ-1"}, // duplicate is ignored
+	} {
+		if err := store.AddRef(ctx, issue.ID, ref, "test-user"); err != nil {
+			t.Fatalf("AddRef failed: %v", err)
+		}
+	}
+	got, err := store.GetRefs(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetRefs failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("GetRefs = %v, want 2 refs", got)
+	}
+
+	// Sync integrations find the issue by any of its refs
+	found, err := store.GetIssueByExternalRef(ctx, "PROJ-1")
+	if err != nil || found == nil || found.ID != issue.ID {
+		t.Errorf("GetIssueByExternalRef(PROJ-1) = %v, %v; want %s", found, err, issue.ID)
+	}
+
+	removed, err := store.RemoveRef(ctx, issue.ID, "PROJ-1", "test-user")
+	if err != nil || !removed {
+		t.Fatalf("RemoveRef = %v, %v", removed, err)
+	}
+	if removed, _ := store.RemoveRef(ctx, issue.ID, "PROJ-1", "test-user"); removed {
+		t.Error("removing a missing ref should report false")
+	}
+	if found, _ := store.GetIssueByExternalRef(ctx, "PROJ-1"); found != nil {
+		t.Errorf("removed ref still matches %s", found.ID)
+	}
+
+	if err := store.SetRefs(ctx, issue.ID, []*types.Ref{{Type: types.RefURL, Value: "https://example.com"}}, "import"); err != nil {
+		t.Fatalf("SetRefs failed: %v", err)
+	}
+	got, _ = store.GetRefs(ctx, issue.ID)
+	if len(got) != 1 || got[0].Value != "https://example.com" {
+		t.Errorf("after SetRefs got %v, want only https://example.com", got)
+	}
+}
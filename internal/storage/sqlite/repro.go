@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// SetRepro replaces an issue's repro metadata. An empty repro removes it.
+func (s *SQLiteStorage) SetRepro(ctx context.Context, issueID string, repro *types.Repro, actor string) error {
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return err
+	}
+	current, err := s.GetRepro(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if current.Equal(repro) {
+		return nil
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return replaceRepro(ctx, tx, issueID, repro, actor)
+	})
+}
+
+// replaceRepro writes an issue's repro metadata in tx.
+func replaceRepro(ctx context.Context, tx *sql.Tx, issueID string, repro *types.Repro, actor string) error {
+	if repro.IsEmpty() {
+		result, err := tx.ExecContext(ctx, `DELETE FROM issue_repro WHERE issue_id = ?`, issueID)
+		if err != nil {
+			return wrapDBErrorf(err, "clear repro of %s", issueID)
+		}
+		return recordRefChange(ctx, tx, result, issueID, actor, "Cleared repro")
+	}
+	result, err := tx.ExecContext(ctx, `
+		INSERT OR REPLACE INTO issue_repro (issue_id, os, version, steps, expected, actual)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, issueID, repro.OS, repro.Version, repro.Steps, repro.Expected, repro.Actual)
+	if err != nil {
+		return wrapDBErrorf(err, "set repro on %s", issueID)
+	}
+	return recordRefChange(ctx, tx, result, issueID, actor, "Set repro: "+describeRepro(repro))
+}
+
+// GetRepro returns an issue's repro metadata, or nil if it has none.
+func (s *SQLiteStorage) GetRepro(ctx context.Context, issueID string) (*types.Repro, error) {
+	byIssue, err := s.GetReproForIssues(ctx, []string{issueID})
+	if err != nil {
+		return nil, err
+	}
+	return byIssue[issueID], nil
+}
+
+// GetReproForIssues returns the repro metadata of many issues in one query.
+// Issues without metadata are absent from the map.
+func (s *SQLiteStorage) GetReproForIssues(ctx context.Context, issueIDs []string) (map[string]*types.Repro, error) {
+	result := make(map[string]*types.Repro)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	args := make([]interface{}, len(issueIDs))
+	for i, id := range issueIDs {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`
+		SELECT issue_id, os, version, steps, expected, actual FROM issue_repro
+		WHERE issue_id IN (%s)
+	`, buildPlaceholders(len(issueIDs))) // #nosec G201 -- placeholders are generated internally
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, wrapDBError("get repro", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var issueID string
+		var repro types.Repro
+		if err := rows.Scan(&issueID, &repro.OS, &repro.Version, &repro.Steps, &repro.Expected, &repro.Actual); err != nil {
+			return nil, wrapDBError("scan repro", err)
+		}
+		result[issueID] = &repro
+	}
+	return result, wrapDBError("iterate repro", rows.Err())
+}
+
+// SearchReproIssueIDs returns the IDs of issues with repro metadata and,
+// when text isn't empty, a repro field containing it (case-insensitive).
+func (s *SQLiteStorage) SearchReproIssueIDs(ctx context.Context, text string) ([]string, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	query := `SELECT issue_id FROM issue_repro ORDER BY issue_id`
+	var args []interface{}
+	if text != "" {
+		query = `
+			SELECT issue_id FROM issue_repro
+			WHERE os LIKE ? OR version LIKE ? OR steps LIKE ?
+			   OR expected LIKE ? OR actual LIKE ?
+			ORDER BY issue_id`
+		pattern := "%" + text + "%"
+		args = []interface{}{pattern, pattern, pattern, pattern, pattern}
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, wrapDBError("search repro", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, wrapDBError("scan repro issue", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, wrapDBError("iterate repro issues", rows.Err())
+}
+
+// describeRepro summarizes a repro for its event comment.
+func describeRepro(repro *types.Repro) string {
+	var parts []string
+	if repro.OS != "" {
+		parts = append(parts, "OS "+repro.OS)
+	}
+	if repro.Version != "" {
+		parts = append(parts, "version "+repro.Version)
+	}
+	for _, field := range []struct{ name, value string }{
+		{"steps", repro.Steps}, {"expected", repro.Expected}, {"actual", repro.Actual},
+	} {
+		if field.value != "" {
+			parts = append(parts, field.name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestReproSetAndSearch(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var ids []string
+	for _, title := range []string{"Crash on save", "Slow startup", "Plain"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	repros := map[string]*types.Repro{
+		ids[0]: {OS: "macOS 15.1", Version: "1.4.2", Steps: "1. Open a file\n2. Press save", Expected: "Saved", Actual: "Segfault"},
+		ids[1]: {OS: "Windows 11"},
+	}
+	for id, repro := range repros {
+		if err := store.SetRepro(ctx, id, repro, "test-user"); err != nil {
+			t.Fatalf("SetRepro failed: %v", err)
+		}
+	}
+
+	got, err := store.GetRepro(ctx, ids[0])
+	if err != nil || !got.Equal(repros[ids[0]]) {
+		t.Errorf("GetRepro = %+v, %v", got, err)
+	}
+	if got, _ := store.GetRepro(ctx, ids[2]); got != nil {
+		t.Errorf("issue without repro got %+v", got)
+	}
+
+	all, _ := store.SearchReproIssueIDs(ctx, "")
+	segfault, _ := store.SearchReproIssueIDs(ctx, "SEGFAULT")
+	windows, _ := store.SearchReproIssueIDs(ctx, "windows")
+	if len(all) != 2 || len(segfault) != 1 || segfault[0] != ids[0] || len(windows) != 1 || windows[0] != ids[1] {
+		t.Errorf("SearchReproIssueIDs = %v (all), %v (segfault), %v (windows)", all, segfault, windows)
+	}
+
+	if err := store.SetRepro(ctx, ids[1], &types.Repro{}, "test-user"); err != nil {
+		t.Fatalf("clearing SetRepro failed: %v", err)
+	}
+	if all, _ := store.SearchReproIssueIDs(ctx, ""); len(all) != 1 {
+		t.Errorf("after clearing got %v", all)
+	}
+}
@@ -0,0 +1,90 @@
+// Package sqlite implements data retention purges (bd retention).
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// PurgeClosedComments deletes the comments of issues closed before
+// closedBefore and marks those issues dirty so the next export drops the
+// comments from JSONL too. It returns the number of comments per issue;
+// with dryRun nothing is deleted.
+func (s *SQLiteStorage) PurgeClosedComments(ctx context.Context, closedBefore time.Time, dryRun bool) (map[string]int, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.issue_id, COUNT(*)
+		FROM comments c
+		JOIN issues i ON i.id = c.issue_id
+		WHERE i.status = 'closed' AND i.closed_at IS NOT NULL AND i.closed_at < ?
+		GROUP BY c.issue_id
+	`, closedBefore.Format(time.RFC3339))
+	if err != nil {
+		return nil, wrapDBError("find comments to purge", err)
+	}
+	counts := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			_ = rows.Close()
+			return nil, wrapDBError("scan comments to purge", err)
+		}
+		counts[id] = n
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, wrapDBError("iterate comments to purge", err)
+	}
+	if dryRun || len(counts) == 0 {
+		return counts, nil
+	}
+
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now()
+		for id := range counts {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM comments WHERE issue_id = ?`, id); err != nil {
+				return wrapDBErrorf(err, "purge comments of %s", id)
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO dirty_issues (issue_id, marked_at)
+				VALUES (?, ?)
+				ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+			`, id, now); err != nil {
+				return wrapDBErrorf(err, "mark %s dirty", id)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// PurgeEvents deletes audit trail events recorded before the cutoff and
+// returns how many there were; with dryRun nothing is deleted.
+func (s *SQLiteStorage) PurgeEvents(ctx context.Context, before time.Time, dryRun bool) (int, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	cutoff := before.Format(time.RFC3339)
+	if dryRun {
+		var n int
+		err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events WHERE created_at < ?`, cutoff).Scan(&n)
+		return n, wrapDBError("count events to purge", err)
+	}
+	var n int64
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM events WHERE created_at < ?`, cutoff)
+		if err != nil {
+			return wrapDBError("purge events", err)
+		}
+		n, err = res.RowsAffected()
+		return err
+	})
+	return int(n), err
+}
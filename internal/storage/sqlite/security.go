@@ -0,0 +1,157 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// SetSecurity replaces an issue's advisory metadata. An empty advisory
+// removes it.
+func (s *SQLiteStorage) SetSecurity(ctx context.Context, issueID string, sec *types.Security, actor string) error {
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return err
+	}
+	current, err := s.GetSecurity(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if current.Equal(sec) {
+		return nil
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return replaceSecurity(ctx, tx, issueID, sec, actor)
+	})
+}
+
+// replaceSecurity writes an issue's advisory metadata in tx.
+func replaceSecurity(ctx context.Context, tx *sql.Tx, issueID string, sec *types.Security, actor string) error {
+	if sec.IsEmpty() {
+		result, err := tx.ExecContext(ctx, `DELETE FROM issue_security WHERE issue_id = ?`, issueID)
+		if err != nil {
+			return wrapDBErrorf(err, "clear security of %s", issueID)
+		}
+		return recordRefChange(ctx, tx, result, issueID, actor, "Cleared security advisory")
+	}
+	var cvss interface{}
+	if sec.CVSS != nil {
+		cvss = *sec.CVSS
+	}
+	var embargo interface{}
+	if sec.EmbargoUntil != nil {
+		embargo = sec.EmbargoUntil.UTC()
+	}
+	result, err := tx.ExecContext(ctx, `
+		INSERT OR REPLACE INTO issue_security (issue_id, cve, cvss, affected_versions, embargo_until)
+		VALUES (?, ?, ?, ?, ?)
+	`, issueID, sec.CVE, cvss, sec.AffectedVersions, embargo)
+	if err != nil {
+		return wrapDBErrorf(err, "set security on %s", issueID)
+	}
+	return recordRefChange(ctx, tx, result, issueID, actor, "Set security advisory: "+describeSecurity(sec))
+}
+
+// GetSecurity returns an issue's advisory metadata, or nil if it has none.
+func (s *SQLiteStorage) GetSecurity(ctx context.Context, issueID string) (*types.Security, error) {
+	byIssue, err := s.GetSecurityForIssues(ctx, []string{issueID})
+	if err != nil {
+		return nil, err
+	}
+	return byIssue[issueID], nil
+}
+
+// GetSecurityForIssues returns the advisory metadata of many issues in one
+// query. Issues without metadata are absent from the map.
+func (s *SQLiteStorage) GetSecurityForIssues(ctx context.Context, issueIDs []string) (map[string]*types.Security, error) {
+	result := make(map[string]*types.Security)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	args := make([]interface{}, len(issueIDs))
+	for i, id := range issueIDs {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`
+		SELECT issue_id, cve, cvss, affected_versions, embargo_until FROM issue_security
+		WHERE issue_id IN (%s)
+	`, buildPlaceholders(len(issueIDs))) // #nosec G201 -- placeholders are generated internally
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, wrapDBError("get security", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var issueID string
+		var sec types.Security
+		var cvss sql.NullFloat64
+		var embargo sql.NullTime
+		if err := rows.Scan(&issueID, &sec.CVE, &cvss, &sec.AffectedVersions, &embargo); err != nil {
+			return nil, wrapDBError("scan security", err)
+		}
+		if cvss.Valid {
+			sec.CVSS = &cvss.Float64
+		}
+		if embargo.Valid {
+			sec.EmbargoUntil = &embargo.Time
+		}
+		result[issueID] = &sec
+	}
+	return result, wrapDBError("iterate security", rows.Err())
+}
+
+// GetSecurityIssueIDs returns the IDs of issues with advisory metadata and,
+// when minCVSS is above zero, a CVSS score of at least minCVSS.
+func (s *SQLiteStorage) GetSecurityIssueIDs(ctx context.Context, minCVSS float64) ([]string, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	query := `SELECT issue_id FROM issue_security ORDER BY issue_id`
+	var args []interface{}
+	if minCVSS > 0 {
+		query = `SELECT issue_id FROM issue_security WHERE cvss >= ? ORDER BY issue_id`
+		args = append(args, minCVSS)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, wrapDBError("get security issues", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, wrapDBError("scan security issue", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, wrapDBError("iterate security issues", rows.Err())
+}
+
+// describeSecurity summarizes an advisory for its event comment.
+func describeSecurity(sec *types.Security) string {
+	var parts []string
+	if sec.CVE != "" {
+		parts = append(parts, sec.CVE)
+	}
+	if sec.CVSS != nil {
+		parts = append(parts, fmt.Sprintf("CVSS %.1f", *sec.CVSS))
+	}
+	if sec.AffectedVersions != "" {
+		parts = append(parts, "affects "+sec.AffectedVersions)
+	}
+	if sec.EmbargoUntil != nil {
+		parts = append(parts, "embargoed until "+sec.EmbargoUntil.UTC().Format("2006-01-02"))
+	}
+	return strings.Join(parts, ", ")
+}
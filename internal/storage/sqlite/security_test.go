@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestSecuritySetAndQuery(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var ids []string
+	for _, title := range []string{"Critical", "Medium", "Unscored", "Plain"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	score := func(f float64) *float64 { return &f }
+	embargo := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	advisories := map[string]*types.Security{
+		ids[0]: {CVE: "CVE-2026-0001", CVSS: score(9.8), AffectedVersions: "<1.4.3", EmbargoUntil: &embargo},
+		ids[1]: {CVSS: score(5.0)},
+		ids[2]: {CVE: "CVE-2026-0002"},
+	}
+	for id, sec := range advisories {
+		if err := store.SetSecurity(ctx, id, sec, "test-user"); err != nil {
+			t.Fatalf("SetSecurity failed: %v", err)
+		}
+	}
+
+	got, err := store.GetSecurity(ctx, ids[0])
+	if err != nil || !got.Equal(advisories[ids[0]]) {
+		t.Errorf("GetSecurity = %+v, %v", got, err)
+	}
+	if got, _ := store.GetSecurity(ctx, ids[3]); got != nil {
+		t.Errorf("issue without advisory got %+v", got)
+	}
+
+	all, _ := store.GetSecurityIssueIDs(ctx, 0)
+	high, _ := store.GetSecurityIssueIDs(ctx, 7)
+	if len(all) != 3 || len(high) != 1 || high[0] != ids[0] {
+		t.Errorf("GetSecurityIssueIDs = %v (all), %v (>= 7)", all, high)
+	}
+
+	if err := store.SetSecurity(ctx, ids[1], nil, "test-user"); err != nil {
+		t.Fatalf("clearing SetSecurity failed: %v", err)
+	}
+	if all, _ := store.GetSecurityIssueIDs(ctx, 0); len(all) != 2 {
+		t.Errorf("after clearing got %v", all)
+	}
+}
@@ -2,6 +2,7 @@ package sqlite
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -843,6 +844,61 @@ func TestClosedAtInvariant(t *testing.T) {
 	})
 }
 
+func TestCountIssues(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	issues := []*types.Issue{
+		{Title: "Login bug", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug, Assignee: "alice"},
+		{Title: "Docs", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{Title: "Old bug", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug, Assignee: "alice"},
+	}
+	for _, issue := range issues {
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if err := store.CloseIssue(ctx, issues[2].ID, "Done", "test-user", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	for _, l := range []struct{ id, label string }{{issues[0].ID, "api"}, {issues[0].ID, "urgent"}, {issues[2].ID, "api"}} {
+		if err := store.AddLabel(ctx, l.id, l.label, "test-user"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+
+	bug := types.TypeBug
+	tests := []struct {
+		groupBy string
+		filter  types.IssueFilter
+		want    string
+	}{
+		{"", types.IssueFilter{}, "3 []"},
+		{"", types.IssueFilter{IssueType: &bug, Limit: 1}, "2 []"},
+		{types.GroupByStatus, types.IssueFilter{}, "3 [{closed 1} {open 2}]"},
+		{types.GroupByPriority, types.IssueFilter{}, "3 [{P0 1} {P2 2}]"},
+		{types.GroupByType, types.IssueFilter{}, "3 [{bug 2} {task 1}]"},
+		{types.GroupByAssignee, types.IssueFilter{}, "3 [{(unassigned) 1} {alice 2}]"},
+		{types.GroupByLabel, types.IssueFilter{}, "3 [{(no labels) 1} {api 2} {urgent 1}]"},
+		{types.GroupByLabel, types.IssueFilter{IssueType: &bug}, "2 [{api 2} {urgent 1}]"},
+	}
+	for _, tt := range tests {
+		counts, err := store.CountIssues(ctx, "", tt.filter, tt.groupBy)
+		if err != nil {
+			t.Fatalf("CountIssues(%q) failed: %v", tt.groupBy, err)
+		}
+		if got := fmt.Sprintf("%d %v", counts.Total, counts.Groups); got != tt.want {
+			t.Errorf("CountIssues(%q) = %s, want %s", tt.groupBy, got, tt.want)
+		}
+	}
+
+	if _, err := store.CountIssues(ctx, "", types.IssueFilter{}, "bogus"); err == nil {
+		t.Error("expected error for invalid group by")
+	}
+}
+
 func TestSearchIssues(t *testing.T) {
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
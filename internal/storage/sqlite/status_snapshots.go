@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+)
+
+// RecordStatusSnapshot stores the number of issues in each status on day
+// (YYYY-MM-DD), replacing any earlier snapshot of the same day.
+func (s *SQLiteStorage) RecordStatusSnapshot(ctx context.Context, day string, counts map[string]int) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM status_snapshots WHERE day = ?`, day); err != nil {
+			return wrapDBErrorf(err, "clear status snapshot of %s", day)
+		}
+		for status, count := range counts {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO status_snapshots (day, status, count) VALUES (?, ?, ?)
+			`, day, status, count); err != nil {
+				return wrapDBErrorf(err, "record status snapshot of %s", day)
+			}
+		}
+		return nil
+	})
+}
+
+// GetStatusSnapshots returns the snapshots of the days from first to last
+// (YYYY-MM-DD, inclusive), keyed by day and then status. Days without a
+// snapshot are absent from the map.
+func (s *SQLiteStorage) GetStatusSnapshots(ctx context.Context, first, last string) (map[string]map[string]int, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT day, status, count FROM status_snapshots
+		WHERE day >= ? AND day <= ?
+	`, first, last)
+	if err != nil {
+		return nil, wrapDBError("get status snapshots", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	result := make(map[string]map[string]int)
+	for rows.Next() {
+		var day, status string
+		var count int
+		if err := rows.Scan(&day, &status, &count); err != nil {
+			return nil, wrapDBError("scan status snapshot", err)
+		}
+		if result[day] == nil {
+			result[day] = make(map[string]int)
+		}
+		result[day][status] = count
+	}
+	return result, wrapDBError("iterate status snapshots", rows.Err())
+}
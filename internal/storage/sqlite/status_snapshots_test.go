@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStatusSnapshots(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := store.RecordStatusSnapshot(ctx, "2026-03-01", map[string]int{"open": 3, "closed": 1}); err != nil {
+		t.Fatalf("RecordStatusSnapshot: %v", err)
+	}
+	if err := store.RecordStatusSnapshot(ctx, "2026-03-02", map[string]int{"open": 4}); err != nil {
+		t.Fatalf("RecordStatusSnapshot: %v", err)
+	}
+	// A later snapshot of the same day replaces the earlier one
+	if err := store.RecordStatusSnapshot(ctx, "2026-03-02", map[string]int{"open": 2, "in_progress": 2}); err != nil {
+		t.Fatalf("RecordStatusSnapshot: %v", err)
+	}
+
+	got, err := store.GetStatusSnapshots(ctx, "2026-03-02", "2026-03-31")
+	if err != nil {
+		t.Fatalf("GetStatusSnapshots: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d days, want 1: %v", len(got), got)
+	}
+	day := got["2026-03-02"]
+	if len(day) != 2 || day["open"] != 2 || day["in_progress"] != 2 {
+		t.Errorf("2026-03-02 = %v, want open 2, in_progress 2", day)
+	}
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// stmtCache holds prepared statements for hot per-issue queries, keyed by SQL
+// text. Parsing and planning is most of the cost of a point lookup in SQLite,
+// so loops that fetch one issue at a time (export, auto-flush, import) spend
+// far less time when the statement is prepared once and reused.
+//
+// *sql.Stmt is safe for concurrent use and re-prepares itself on whichever
+// pooled connection runs it. The cache must be cleared whenever s.db is
+// replaced or closed, which reconnect() and Close() do under reconnectMu.
+type stmtCache struct {
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+// prepared returns a cached prepared statement for query, preparing it on
+// first use. Callers must hold reconnectMu (read or write) while using it.
+func (s *SQLiteStorage) prepared(ctx context.Context, query string) (*sql.Stmt, error) {
+	s.stmts.mu.Lock()
+	defer s.stmts.mu.Unlock()
+
+	if stmt, ok := s.stmts.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	if s.stmts.stmts == nil {
+		s.stmts.stmts = make(map[string]*sql.Stmt)
+	}
+	s.stmts.stmts[query] = stmt
+	return stmt, nil
+}
+
+// closeStmts closes and forgets all cached statements. Called with
+// reconnectMu held for writing, before the underlying *sql.DB goes away.
+func (s *SQLiteStorage) closeStmts() {
+	s.stmts.mu.Lock()
+	defer s.stmts.mu.Unlock()
+
+	for _, stmt := range s.stmts.stmts {
+		_ = stmt.Close()
+	}
+	s.stmts.stmts = nil
+}
@@ -18,6 +18,7 @@ import (
 	sqlite3 "github.com/ncruces/go-sqlite3"
 	_ "github.com/ncruces/go-sqlite3/driver"
 	_ "github.com/ncruces/go-sqlite3/embed"
+	sqliteregexp "github.com/ncruces/go-sqlite3/ext/regexp"
 	"github.com/tetratelabs/wazero"
 )
 
@@ -52,6 +53,7 @@ type SQLiteStorage struct {
 	readOnly    bool              // True if opened in read-only mode (GH#804)
 	freshness   *FreshnessChecker // Optional freshness checker for daemon mode
 	reconnectMu sync.RWMutex      // Protects reconnection and db access (GH#607)
+	stmts       stmtCache         // Prepared statements for hot queries
 }
 
 // setupWASMCache configures WASM compilation caching to reduce SQLite startup time.
@@ -99,6 +101,9 @@ func setupWASMCache() string {
 func init() {
 	// Setup WASM compilation cache to avoid 220ms JIT compilation overhead on every process start
 	_ = setupWASMCache()
+
+	// Provide the REGEXP operator (Go regexp syntax) for title regex filters
+	sqlite3.AutoExtension(sqliteregexp.Register)
 }
 
 // New creates a new SQLite storage backend with default 30s busy timeout
@@ -134,6 +139,15 @@ func NewWithTimeout(ctx context.Context, path string, busyTimeout time.Duration)
 		}
 		// Use file URI with pragmas
 		connStr = fmt.Sprintf("file:%s?_pragma=foreign_keys(ON)&_pragma=busy_timeout(%d)&_time_format=sqlite", path, timeoutMs)
+		// In WAL mode, synchronous=NORMAL only syncs at checkpoints instead of on
+		// every commit. The database cannot be corrupted by a crash; at worst the
+		// last few commits before a power loss are rolled back, and JSONL remains
+		// the source of truth. This makes many small write transactions (bulk
+		// imports, auto-flush bookkeeping) several times faster.
+		// Not applied with the DELETE journal fallback, where NORMAL is unsafe.
+		if !isWSL2WindowsPath(path) {
+			connStr += "&_pragma=synchronous(NORMAL)"
+		}
 	}
 
 	db, err := sql.Open("sqlite3", connStr)
@@ -314,6 +328,7 @@ func (s *SQLiteStorage) Close() error {
 		// Without this, writes may be stranded in the WAL and lost between CLI invocations.
 		_, _ = s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
 	}
+	s.closeStmts()
 	return s.db.Close()
 }
 
@@ -490,6 +505,7 @@ func (s *SQLiteStorage) reconnect() error {
 	}
 
 	// Close the old connection - log but continue since connection may be stale/invalid
+	s.closeStmts()
 	if err := s.db.Close(); err != nil {
 		// Old connection might already be broken after file replacement - this is expected
 		debugPrintf("reconnect: close old connection: %v (continuing)\n", err)
@@ -0,0 +1,54 @@
+// Package sqlite implements subset stub tracking for bd init --subset clones.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SetStubs marks issues as subset stubs (stub=true) or as fully materialized
+// (stub=false).
+func (s *SQLiteStorage) SetStubs(ctx context.Context, issueIDs []string, stub bool) error {
+	if len(issueIDs) == 0 {
+		return nil
+	}
+	query := `INSERT OR IGNORE INTO subset_stubs (issue_id) VALUES (?)`
+	if !stub {
+		query = `DELETE FROM subset_stubs WHERE issue_id = ?`
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return wrapDBError("prepare subset stub statement", err)
+		}
+		defer func() { _ = stmt.Close() }()
+		for _, id := range issueIDs {
+			if _, err := stmt.ExecContext(ctx, id); err != nil {
+				return wrapDBErrorf(err, "update subset stub %s", id)
+			}
+		}
+		return nil
+	})
+}
+
+// GetStubIDs returns the IDs of the issues held as subset stubs.
+func (s *SQLiteStorage) GetStubIDs(ctx context.Context) (map[string]bool, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT issue_id FROM subset_stubs`)
+	if err != nil {
+		return nil, wrapDBError("get subset stubs", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, wrapDBError("scan subset stub", err)
+		}
+		ids[id] = true
+	}
+	return ids, wrapDBError("iterate subset stubs", rows.Err())
+}
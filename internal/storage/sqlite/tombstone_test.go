@@ -237,7 +237,7 @@ func TestDeleteIssuesCreatesTombstones(t *testing.T) {
 			t.Fatalf("Failed to create issue: %v", err)
 		}
 
-		result, err := store.DeleteIssues(ctx, []string{"bd-1"}, false, true, false)
+		result, err := store.DeleteIssues(ctx, []string{"bd-1"}, false, true, false, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues failed: %v", err)
 		}
@@ -274,7 +274,7 @@ func TestDeleteIssuesCreatesTombstones(t *testing.T) {
 			t.Fatalf("Failed to create issue2: %v", err)
 		}
 
-		result, err := store.DeleteIssues(ctx, []string{"bd-10", "bd-11"}, false, true, false)
+		result, err := store.DeleteIssues(ctx, []string{"bd-10", "bd-11"}, false, true, false, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues failed: %v", err)
 		}
@@ -335,7 +335,7 @@ func TestDeleteIssuesCreatesTombstones(t *testing.T) {
 		}
 
 		// Batch delete closed issues - this was failing before the fix
-		result, err := store.DeleteIssues(ctx, []string{"bd-closed-10", "bd-closed-11"}, false, true, false)
+		result, err := store.DeleteIssues(ctx, []string{"bd-closed-10", "bd-closed-11"}, false, true, false, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues on closed issues failed: %v", err)
 		}
@@ -388,7 +388,7 @@ func TestDeleteIssuesCreatesTombstones(t *testing.T) {
 			t.Fatalf("Failed to add dependency: %v", err)
 		}
 
-		result, err := store.DeleteIssues(ctx, []string{"bd-1"}, true, false, false)
+		result, err := store.DeleteIssues(ctx, []string{"bd-1"}, true, false, false, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues with cascade failed: %v", err)
 		}
@@ -428,7 +428,7 @@ func TestDeleteIssuesCreatesTombstones(t *testing.T) {
 		}
 
 		// Delete it (creates tombstone)
-		result, err := store.DeleteIssues(ctx, []string{"bd-respawn-1"}, false, true, false)
+		result, err := store.DeleteIssues(ctx, []string{"bd-respawn-1"}, false, true, false, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues failed: %v", err)
 		}
@@ -497,7 +497,7 @@ func TestDeleteIssuesCreatesTombstones(t *testing.T) {
 		}
 
 		// Delete parent
-		_, err := store.DeleteIssues(ctx, []string{"bd-100"}, false, true, false)
+		_, err := store.DeleteIssues(ctx, []string{"bd-100"}, false, true, false, "test-user")
 		if err != nil {
 			t.Fatalf("DeleteIssues failed: %v", err)
 		}
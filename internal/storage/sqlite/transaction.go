@@ -12,6 +12,7 @@ import (
 
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/validation"
 )
 
 // Verify sqliteTxStorage implements storage.Transaction at compile time
@@ -91,6 +92,13 @@ func (s *SQLiteStorage) RunInTransaction(ctx context.Context, fn func(tx storage
 
 // CreateIssue creates a new issue within the transaction.
 func (t *sqliteTxStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	if err := checkLock(ctx, t.conn, "", actor); err != nil {
+		return err
+	}
+	if err := validation.CheckCreate(ctx, t, issue); err != nil {
+		return err
+	}
+
 	// Fetch custom statuses and types for validation
 	customStatuses, err := t.GetCustomStatuses(ctx)
 	if err != nil {
@@ -209,6 +217,13 @@ func (t *sqliteTxStorage) CreateIssue(ctx context.Context, issue *types.Issue, a
 
 // CreateIssues creates multiple issues within the transaction.
 func (t *sqliteTxStorage) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	if err := checkLock(ctx, t.conn, "", actor); err != nil {
+		return err
+	}
+	if err := validation.CheckCreate(ctx, t, issues...); err != nil {
+		return err
+	}
+
 	if len(issues) == 0 {
 		return nil
 	}
@@ -379,6 +394,10 @@ func (t *sqliteTxStorage) getLabels(ctx context.Context, issueID string) ([]stri
 
 // UpdateIssue updates an issue within the transaction.
 func (t *sqliteTxStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	if err := checkLock(ctx, t.conn, id, actor); err != nil {
+		return err
+	}
+
 	// Get old issue for event
 	oldIssue, err := t.GetIssue(ctx, id)
 	if err != nil {
@@ -387,6 +406,9 @@ func (t *sqliteTxStorage) UpdateIssue(ctx context.Context, id string, updates ma
 	if oldIssue == nil {
 		return fmt.Errorf("issue %s not found", id)
 	}
+	if err := validation.CheckUpdate(ctx, t, oldIssue, updates); err != nil {
+		return err
+	}
 
 	// Fetch custom statuses for validation
 	customStatuses, err := t.GetCustomStatuses(ctx)
@@ -543,6 +565,10 @@ func applyUpdatesToIssue(issue *types.Issue, updates map[string]interface{}) {
 // NOTE: close_reason is stored in both issues table and events table - see SQLiteStorage.CloseIssue.
 // The session parameter tracks which Claude Code session closed the issue (can be empty).
 func (t *sqliteTxStorage) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
+	if err := checkLock(ctx, t.conn, id, actor); err != nil {
+		return err
+	}
+
 	now := time.Now()
 
 	result, err := t.conn.ExecContext(ctx, `
@@ -657,7 +683,11 @@ func (t *sqliteTxStorage) CloseIssue(ctx context.Context, id string, reason stri
 }
 
 // DeleteIssue deletes an issue within the transaction.
-func (t *sqliteTxStorage) DeleteIssue(ctx context.Context, id string) error {
+func (t *sqliteTxStorage) DeleteIssue(ctx context.Context, id, actor string) error {
+	if err := checkLock(ctx, t.conn, id, actor); err != nil {
+		return err
+	}
+
 	// Delete dependencies (both directions)
 	_, err := t.conn.ExecContext(ctx, `DELETE FROM dependencies WHERE issue_id = ? OR depends_on_id = ?`, id, id)
 	if err != nil {
@@ -695,6 +725,10 @@ func (t *sqliteTxStorage) DeleteIssue(ctx context.Context, id string) error {
 
 // AddDependency adds a dependency between issues within the transaction.
 func (t *sqliteTxStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	if err := checkLock(ctx, t.conn, dep.IssueID, actor); err != nil {
+		return err
+	}
+
 	// Validate dependency type
 	if !dep.Type.IsValid() {
 		return fmt.Errorf("invalid dependency type: %q (must be non-empty string, max 50 chars)", dep.Type)
@@ -824,6 +858,10 @@ func (t *sqliteTxStorage) AddDependency(ctx context.Context, dep *types.Dependen
 
 // RemoveDependency removes a dependency within the transaction.
 func (t *sqliteTxStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	if err := checkLock(ctx, t.conn, issueID, actor); err != nil {
+		return err
+	}
+
 	// First, check what type of dependency is being removed
 	var depType types.DependencyType
 	err := t.conn.QueryRowContext(ctx, `
@@ -881,6 +919,10 @@ func (t *sqliteTxStorage) RemoveDependency(ctx context.Context, issueID, depends
 
 // AddLabel adds a label to an issue within the transaction.
 func (t *sqliteTxStorage) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := checkLock(ctx, t.conn, issueID, actor); err != nil {
+		return err
+	}
+
 	result, err := t.conn.ExecContext(ctx, `
 		INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)
 	`, issueID, label)
@@ -916,6 +958,13 @@ func (t *sqliteTxStorage) AddLabel(ctx context.Context, issueID, label, actor st
 
 // RemoveLabel removes a label from an issue within the transaction.
 func (t *sqliteTxStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := checkLock(ctx, t.conn, issueID, actor); err != nil {
+		return err
+	}
+	if err := validation.CheckRemoveLabel(ctx, t, issueID, label); err != nil {
+		return err
+	}
+
 	result, err := t.conn.ExecContext(ctx, `
 		DELETE FROM labels WHERE issue_id = ? AND label = ?
 	`, issueID, label)
@@ -1027,6 +1076,10 @@ func (t *sqliteTxStorage) GetMetadata(ctx context.Context, key string) (string,
 
 // AddComment adds a comment to an issue within the transaction.
 func (t *sqliteTxStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	if err := checkLock(ctx, t.conn, issueID, actor); err != nil {
+		return err
+	}
+
 	// Update issue updated_at timestamp first to verify issue exists
 	now := time.Now()
 	res, err := t.conn.ExecContext(ctx, `
@@ -1061,6 +1114,32 @@ func (t *sqliteTxStorage) AddComment(ctx context.Context, issueID, actor, commen
 	return nil
 }
 
+// UpdateCommentText replaces the text of a comment on issueID (bd sed).
+// It is not part of storage.Transaction; callers type-assert for it.
+func (t *sqliteTxStorage) UpdateCommentText(ctx context.Context, issueID string, commentID int64, text, actor string) error {
+	if err := checkLock(ctx, t.conn, issueID, actor); err != nil {
+		return err
+	}
+	res, err := t.conn.ExecContext(ctx, `
+		UPDATE comments SET text = ? WHERE id = ? AND issue_id = ?
+	`, text, commentID, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("comment %d not found on %s", commentID, issueID)
+	}
+	if _, err := t.conn.ExecContext(ctx, `UPDATE issues SET updated_at = ? WHERE id = ?`, time.Now(), issueID); err != nil {
+		return fmt.Errorf("failed to update timestamp: %w", err)
+	}
+	if err := markDirty(ctx, t.conn, issueID); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	return nil
+}
+
 // SearchIssues finds issues matching query and filters within the transaction.
 // This enables read-your-writes semantics for searching within a transaction.
 func (t *sqliteTxStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
@@ -1092,6 +1171,10 @@ func (t *sqliteTxStorage) SearchIssues(ctx context.Context, query string, filter
 		whereClauses = append(whereClauses, "notes LIKE ?")
 		args = append(args, "%"+filter.NotesContains+"%")
 	}
+	if filter.TitleRegex != "" {
+		whereClauses = append(whereClauses, "title REGEXP ?")
+		args = append(args, filter.TitleRegex)
+	}
 
 	if filter.Status != nil {
 		whereClauses = append(whereClauses, "status = ?")
@@ -1202,6 +1285,22 @@ func (t *sqliteTxStorage) SearchIssues(ctx context.Context, query string, filter
 		whereClauses = append(whereClauses, fmt.Sprintf("id IN (SELECT issue_id FROM labels WHERE label IN (%s))", strings.Join(placeholders, ", ")))
 	}
 
+	// Label glob filtering: each pattern must match at least one label
+	for _, pattern := range filter.LabelGlobs {
+		whereClauses = append(whereClauses, "id IN (SELECT issue_id FROM labels WHERE label GLOB ?)")
+		args = append(args, pattern)
+	}
+
+	// Label exclusion: issue must have NONE of these labels
+	if len(filter.NotLabels) > 0 {
+		placeholders := make([]string, len(filter.NotLabels))
+		for i, label := range filter.NotLabels {
+			placeholders[i] = "?"
+			args = append(args, label)
+		}
+		whereClauses = append(whereClauses, fmt.Sprintf("id NOT IN (SELECT issue_id FROM labels WHERE label IN (%s))", strings.Join(placeholders, ", ")))
+	}
+
 	// ID filtering: match specific issue IDs
 	if len(filter.IDs) > 0 {
 		placeholders := make([]string, len(filter.IDs))
@@ -1242,6 +1341,20 @@ func (t *sqliteTxStorage) SearchIssues(ctx context.Context, query string, filter
 		args = append(args, *filter.ParentID)
 	}
 
+	// Query expression (bd query)
+	if filter.Query != nil {
+		clause, clauseArgs := filter.Query.SQL()
+		whereClauses = append(whereClauses, clause)
+		args = append(args, clauseArgs...)
+	}
+
+	// Pagination: resume after the cursor's issue
+	if filter.After != nil {
+		clause, afterArgs := searchAfterClause(filter.After)
+		whereClauses = append(whereClauses, clause)
+		args = append(args, afterArgs...)
+	}
+
 	whereSQL := ""
 	if len(whereClauses) > 0 {
 		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
@@ -1264,7 +1377,7 @@ func (t *sqliteTxStorage) SearchIssues(ctx context.Context, query string, filter
 		       await_type, await_id, timeout_ns, waiters
 		FROM issues
 		%s
-		ORDER BY priority ASC, created_at DESC
+		ORDER BY priority ASC, created_at DESC, id ASC
 		%s
 	`, whereSQL, limitSQL)
 
@@ -235,6 +235,48 @@ func TestTransactionUpdateIssue(t *testing.T) {
 	}
 }
 
+// TestTransactionUpdateCommentText tests editing a comment within a transaction.
+func TestTransactionUpdateCommentText(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	issue := &types.Issue{
+		Title:     "Commented",
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeTask,
+	}
+	if err := store.CreateIssue(ctx, issue, "test-actor"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	comment, err := store.AddIssueComment(ctx, issue.ID, "alice", "uses old-service")
+	if err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+
+	err = store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		return tx.(*sqliteTxStorage).UpdateCommentText(ctx, issue.ID, comment.ID, "uses new-service", "test-actor")
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction failed: %v", err)
+	}
+	comments, err := store.GetIssueComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssueComments failed: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Text != "uses new-service" || comments[0].Author != "alice" {
+		t.Errorf("comments = %+v", comments)
+	}
+
+	err = store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		return tx.(*sqliteTxStorage).UpdateCommentText(ctx, issue.ID, comment.ID+100, "x", "test-actor")
+	})
+	if err == nil {
+		t.Error("expected an error for a missing comment")
+	}
+}
+
 // TestTransactionCloseIssue tests closing an issue within a transaction.
 func TestTransactionCloseIssue(t *testing.T) {
 	ctx := context.Background()
@@ -293,7 +335,7 @@ func TestTransactionDeleteIssue(t *testing.T) {
 
 	// Delete in transaction
 	err := store.RunInTransaction(ctx, func(tx storage.Transaction) error {
-		return tx.DeleteIssue(ctx, issue.ID)
+		return tx.DeleteIssue(ctx, issue.ID, "test-user")
 	})
 
 	if err != nil {
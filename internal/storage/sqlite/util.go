@@ -15,14 +15,32 @@ func (s *SQLiteStorage) QueryContext(ctx context.Context, query string, args ...
 // BeginTx starts a new database transaction
 // This is used by commands that need to perform multiple operations atomically
 func (s *SQLiteStorage) BeginTx(ctx context.Context) (*sql.Tx, error) {
-	return s.db.BeginTx(ctx, nil)
+	return s.beginTxWithRetry(ctx)
+}
+
+// writeTxOptions makes the driver start transactions with BEGIN IMMEDIATE.
+// A DEFERRED transaction that reads and then writes fails with SQLITE_BUSY,
+// without waiting out busy_timeout, when another process committed in
+// between; taking the write lock up front makes it wait instead.
+var writeTxOptions = &sql.TxOptions{Isolation: sql.LevelSerializable}
+
+// beginTxWithRetry starts a write transaction, retrying with backoff while
+// another process holds the write lock.
+func (s *SQLiteStorage) beginTxWithRetry(ctx context.Context) (*sql.Tx, error) {
+	var tx *sql.Tx
+	err := retryOnBusy(ctx, 5, 10*time.Millisecond, func() error {
+		var err error
+		tx, err = s.db.BeginTx(ctx, writeTxOptions)
+		return err
+	})
+	return tx, err
 }
 
 // withTx executes a function within a database transaction.
 // If the function returns an error, the transaction is rolled back.
 // Otherwise, the transaction is committed.
 func (s *SQLiteStorage) withTx(ctx context.Context, fn func(*sql.Tx) error) error {
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.beginTxWithRetry(ctx)
 	if err != nil {
 		return wrapDBError("begin transaction", err)
 	}
@@ -88,6 +106,16 @@ func IsBusyError(err error) bool {
 //   - BEGIN IMMEDIATE fails with non-busy error
 //   - All retries exhausted with SQLITE_BUSY
 func beginImmediateWithRetry(ctx context.Context, conn *sql.Conn, maxRetries int, initialDelay time.Duration) error {
+	return retryOnBusy(ctx, maxRetries, initialDelay, func() error {
+		_, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+		return err
+	})
+}
+
+// retryOnBusy runs fn, retrying with exponential backoff while it fails with
+// SQLITE_BUSY. Other errors and context cancellation are returned at once.
+// Non-positive maxRetries and initialDelay default to 5 and 10ms.
+func retryOnBusy(ctx context.Context, maxRetries int, initialDelay time.Duration, fn func() error) error {
 	if maxRetries <= 0 {
 		maxRetries = 5
 	}
@@ -104,8 +132,7 @@ func beginImmediateWithRetry(ctx context.Context, conn *sql.Conn, maxRetries int
 			return err
 		}
 
-		// Attempt to begin transaction
-		_, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+		err := fn()
 		if err == nil {
 			return nil // Success
 		}
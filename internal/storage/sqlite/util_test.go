@@ -4,7 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
 )
 
 func TestIsUniqueConstraintError(t *testing.T) {
@@ -315,3 +319,61 @@ func TestBeginImmediateWithRetry(t *testing.T) {
 		_, _ = conn.ExecContext(context.Background(), "ROLLBACK")
 	})
 }
+
+func TestTransactionsRetryWhileAnotherProcessWrites(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	holder := newTestStore(t, dbPath)
+	defer holder.Close()
+
+	// A second store with busy_timeout 0 stands in for another bd process
+	// run with --lock-timeout 0: without retries it fails immediately.
+	other, err := NewWithTimeout(ctx, dbPath, 0)
+	if err != nil {
+		t.Fatalf("NewWithTimeout failed: %v", err)
+	}
+	defer other.Close()
+
+	issue := &types.Issue{Title: "Contended", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := holder.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	conn, err := holder.db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Failed to acquire connection: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		t.Fatalf("BEGIN IMMEDIATE failed: %v", err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = conn.ExecContext(context.Background(), "COMMIT")
+	}()
+
+	if err := other.CloseIssue(ctx, issue.ID, "done", "test", ""); err != nil {
+		t.Fatalf("CloseIssue should succeed once the lock is released: %v", err)
+	}
+}
+
+func TestRetryOnBusyStopsOnOtherErrors(t *testing.T) {
+	calls := 0
+	boom := errors.New("boom")
+	err := retryOnBusy(context.Background(), 5, time.Millisecond, func() error {
+		calls++
+		return boom
+	})
+	if !errors.Is(err, boom) || calls != 1 {
+		t.Errorf("retryOnBusy = %v after %d calls, want boom after 1", err, calls)
+	}
+
+	calls = 0
+	err = retryOnBusy(context.Background(), 2, time.Millisecond, func() error {
+		calls++
+		return errors.New("sqlite3: database is locked")
+	})
+	if !IsBusyError(err) || calls != 3 {
+		t.Errorf("retryOnBusy = %v after %d calls, want busy error after 3", err, calls)
+	}
+}
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SetVisibility sets an issue's visibility: "public" or "private". An empty
+// level or "internal", the default, removes the setting.
+func (s *SQLiteStorage) SetVisibility(ctx context.Context, issueID, level, actor string) error {
+	level, err := normalizeVisibility(level)
+	if err != nil {
+		return err
+	}
+	if err := checkLock(ctx, s.db, issueID, actor); err != nil {
+		return err
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return replaceVisibility(ctx, tx, issueID, level, actor)
+	})
+}
+
+// normalizeVisibility validates a level, returning "" for internal.
+func normalizeVisibility(level string) (string, error) {
+	if level == "internal" {
+		level = ""
+	}
+	if level != "" && level != "public" && level != "private" {
+		return "", fmt.Errorf("invalid visibility %q (must be public, internal or private)", level)
+	}
+	return level, nil
+}
+
+// replaceVisibility writes an issue's normalized visibility in tx.
+func replaceVisibility(ctx context.Context, tx *sql.Tx, issueID, level, actor string) error {
+	if level == "" {
+		result, err := tx.ExecContext(ctx, `DELETE FROM issue_visibility WHERE issue_id = ?`, issueID)
+		if err != nil {
+			return wrapDBErrorf(err, "clear visibility of %s", issueID)
+		}
+		return recordRefChange(ctx, tx, result, issueID, actor, "Set visibility: internal")
+	}
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO issue_visibility (issue_id, level) VALUES (?, ?)
+		ON CONFLICT (issue_id) DO UPDATE SET level = excluded.level
+		WHERE level != excluded.level
+	`, issueID, level)
+	if err != nil {
+		return wrapDBErrorf(err, "set visibility of %s", issueID)
+	}
+	return recordRefChange(ctx, tx, result, issueID, actor, "Set visibility: "+level)
+}
+
+// GetVisibilityForIssues returns the visibility of many issues in one
+// query. Internal issues are absent from the map.
+func (s *SQLiteStorage) GetVisibilityForIssues(ctx context.Context, issueIDs []string) (map[string]string, error) {
+	result := make(map[string]string)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	args := make([]interface{}, len(issueIDs))
+	for i, id := range issueIDs {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`
+		SELECT issue_id, level FROM issue_visibility WHERE issue_id IN (%s)
+	`, buildPlaceholders(len(issueIDs))) // #nosec G201 -- placeholders are generated internally
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, wrapDBError("get visibility", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var issueID, level string
+		if err := rows.Scan(&issueID, &level); err != nil {
+			return nil, wrapDBError("scan visibility", err)
+		}
+		result[issueID] = level
+	}
+	return result, wrapDBError("iterate visibility", rows.Err())
+}
+
+// GetVisibilityIssueIDs returns the IDs of the issues with a visibility
+// level other than internal.
+func (s *SQLiteStorage) GetVisibilityIssueIDs(ctx context.Context, level string) ([]string, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT issue_id FROM issue_visibility WHERE level = ? ORDER BY issue_id`, level)
+	if err != nil {
+		return nil, wrapDBError("get issues by visibility", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, wrapDBError("scan issue", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, wrapDBError("iterate issues", rows.Err())
+}
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestVisibility(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var ids []string
+	for _, title := range []string{"Roadmap", "Salary bands"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+
+	if err := store.SetVisibility(ctx, ids[0], "public", "test-user"); err != nil {
+		t.Fatalf("SetVisibility failed: %v", err)
+	}
+	if err := store.SetVisibility(ctx, ids[1], "private", "test-user"); err != nil {
+		t.Fatalf("SetVisibility failed: %v", err)
+	}
+	if err := store.SetVisibility(ctx, ids[1], "secret", "test-user"); err == nil {
+		t.Error("SetVisibility accepted an invalid level")
+	}
+
+	levels, err := store.GetVisibilityForIssues(ctx, ids)
+	if err != nil {
+		t.Fatalf("GetVisibilityForIssues failed: %v", err)
+	}
+	if levels[ids[0]] != "public" || levels[ids[1]] != "private" {
+		t.Errorf("GetVisibilityForIssues = %v", levels)
+	}
+
+	if err := store.SetVisibility(ctx, ids[0], "internal", "test-user"); err != nil {
+		t.Fatalf("SetVisibility failed: %v", err)
+	}
+	private, err := store.GetVisibilityIssueIDs(ctx, "private")
+	if err != nil {
+		t.Fatalf("GetVisibilityIssueIDs failed: %v", err)
+	}
+	if len(private) != 1 || private[0] != ids[1] {
+		t.Errorf("GetVisibilityIssueIDs = %v", private)
+	}
+	if levels, _ := store.GetVisibilityForIssues(ctx, ids[:1]); len(levels) != 0 {
+		t.Errorf("internal issue still has visibility %v", levels)
+	}
+}
@@ -0,0 +1,130 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// SetVote records a voter's +1 or -1 on an issue, replacing any earlier
+// vote of theirs. A value of 0 retracts their vote.
+func (s *SQLiteStorage) SetVote(ctx context.Context, issueID, voter string, value int, actor string) error {
+	if value < -1 || value > 1 {
+		return fmt.Errorf("invalid vote %d (must be +1 or -1)", value)
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if value == 0 {
+			result, err := tx.ExecContext(ctx, `DELETE FROM issue_votes WHERE issue_id = ? AND voter = ?`, issueID, voter)
+			if err != nil {
+				return wrapDBErrorf(err, "retract vote on %s", issueID)
+			}
+			return recordRefChange(ctx, tx, result, issueID, actor, "Retracted vote of "+voter)
+		}
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO issue_votes (issue_id, voter, value) VALUES (?, ?, ?)
+			ON CONFLICT (issue_id, voter) DO UPDATE SET value = excluded.value, created_at = CURRENT_TIMESTAMP
+			WHERE value != excluded.value
+		`, issueID, voter, value)
+		if err != nil {
+			return wrapDBErrorf(err, "vote on %s", issueID)
+		}
+		return recordRefChange(ctx, tx, result, issueID, actor, fmt.Sprintf("Vote %+d by %s", value, voter))
+	})
+}
+
+// SetVotes replaces an issue's votes, as an import does.
+func (s *SQLiteStorage) SetVotes(ctx context.Context, issueID string, votes []*types.Vote, actor string) error {
+	current, err := s.GetVotes(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if votesEqual(current, votes) {
+		return nil
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return replaceVotes(ctx, tx, issueID, votes)
+	})
+}
+
+// replaceVotes writes an issue's votes in tx, ignoring invalid values.
+func replaceVotes(ctx context.Context, tx *sql.Tx, issueID string, votes []*types.Vote) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_votes WHERE issue_id = ?`, issueID); err != nil {
+		return wrapDBErrorf(err, "clear votes of %s", issueID)
+	}
+	for _, v := range votes {
+		if v.Value != 1 && v.Value != -1 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT OR REPLACE INTO issue_votes (issue_id, voter, value) VALUES (?, ?, ?)
+		`, issueID, v.Voter, v.Value); err != nil {
+			return wrapDBErrorf(err, "set vote on %s", issueID)
+		}
+	}
+	return nil
+}
+
+// GetVotes returns an issue's votes, ordered by voter.
+func (s *SQLiteStorage) GetVotes(ctx context.Context, issueID string) ([]*types.Vote, error) {
+	votes, err := s.GetVotesForIssues(ctx, []string{issueID})
+	if err != nil {
+		return nil, err
+	}
+	return votes[issueID], nil
+}
+
+// GetVotesForIssues returns the votes of many issues in one query. Issues
+// without votes are absent from the map.
+func (s *SQLiteStorage) GetVotesForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Vote, error) {
+	result := make(map[string][]*types.Vote)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	args := make([]interface{}, len(issueIDs))
+	for i, id := range issueIDs {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`
+		SELECT issue_id, voter, value FROM issue_votes
+		WHERE issue_id IN (%s)
+		ORDER BY issue_id, voter
+	`, buildPlaceholders(len(issueIDs))) // #nosec G201 -- placeholders are generated internally
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, wrapDBError("get votes", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var issueID string
+		var v types.Vote
+		if err := rows.Scan(&issueID, &v.Voter, &v.Value); err != nil {
+			return nil, wrapDBError("scan vote", err)
+		}
+		result[issueID] = append(result[issueID], &v)
+	}
+	return result, wrapDBError("iterate votes", rows.Err())
+}
+
+func votesEqual(a, b []*types.Vote) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[types.Vote]bool, len(a))
+	for _, v := range a {
+		seen[*v] = true
+	}
+	for _, v := range b {
+		if !seen[*v] {
+			return false
+		}
+	}
+	return true
+}
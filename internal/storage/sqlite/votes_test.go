@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestVotes(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	issue := &types.Issue{Title: "Dark mode", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeFeature}
+	if err := store.CreateIssue(ctx, issue, "test-user"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	for _, v := range []struct {
+		voter string
+		value int
+	}{{"alice", 1}, {"bob", 1}, {"carol", -1}, {"bob", -1}, {"alice", 0}} {
+		if err := store.SetVote(ctx, issue.ID, v.voter, v.value, v.voter); err != nil {
+			t.Fatalf("SetVote(%s, %d) failed: %v", v.voter, v.value, err)
+		}
+	}
+	if err := store.SetVote(ctx, issue.ID, "dave", 2, "dave"); err == nil {
+		t.Error("SetVote accepted a vote of 2")
+	}
+
+	votes, err := store.GetVotes(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetVotes failed: %v", err)
+	}
+	if len(votes) != 2 || votes[0].Voter != "bob" || votes[0].Value != -1 || votes[1].Voter != "carol" {
+		t.Errorf("GetVotes = %+v", votes)
+	}
+	if score := types.VoteScore(votes); score != -2 {
+		t.Errorf("VoteScore = %d, want -2", score)
+	}
+
+	replaced := []*types.Vote{{Voter: "erin", Value: 1}}
+	if err := store.SetVotes(ctx, issue.ID, replaced, "import"); err != nil {
+		t.Fatalf("SetVotes failed: %v", err)
+	}
+	if votes, _ := store.GetVotes(ctx, issue.ID); !votesEqual(votes, replaced) {
+		t.Errorf("after SetVotes got %+v", votes)
+	}
+}
@@ -51,7 +51,7 @@ type Transaction interface {
 	CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error
 	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
 	CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error
-	DeleteIssue(ctx context.Context, id string) error
+	DeleteIssue(ctx context.Context, id, actor string) error
 	GetIssue(ctx context.Context, id string) (*types.Issue, error)                                  // For read-your-writes within transaction
 	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) // For read-your-writes within transaction
 
@@ -84,8 +84,9 @@ type Storage interface {
 	GetIssueByExternalRef(ctx context.Context, externalRef string) (*types.Issue, error)
 	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
 	CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error
-	DeleteIssue(ctx context.Context, id string) error
+	DeleteIssue(ctx context.Context, id, actor string) error
 	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	CountIssues(ctx context.Context, query string, filter types.IssueFilter, groupBy string) (*types.IssueCounts, error) // Aggregate counts without loading rows
 
 	// Dependencies
 	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
@@ -38,12 +38,15 @@ func (m *mockStorage) UpdateIssue(ctx context.Context, id string, updates map[st
 func (m *mockStorage) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
 	return nil
 }
-func (m *mockStorage) DeleteIssue(ctx context.Context, id string) error {
+func (m *mockStorage) DeleteIssue(ctx context.Context, id, actor string) error {
 	return nil
 }
 func (m *mockStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
 	return nil, nil
 }
+func (m *mockStorage) CountIssues(ctx context.Context, query string, filter types.IssueFilter, groupBy string) (*types.IssueCounts, error) {
+	return nil, nil
+}
 func (m *mockStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
 	return nil
 }
@@ -222,7 +225,7 @@ func (m *mockTransaction) UpdateIssue(ctx context.Context, id string, updates ma
 func (m *mockTransaction) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
 	return nil
 }
-func (m *mockTransaction) DeleteIssue(ctx context.Context, id string) error {
+func (m *mockTransaction) DeleteIssue(ctx context.Context, id, actor string) error {
 	return nil
 }
 func (m *mockTransaction) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
@@ -308,6 +311,7 @@ func TestInterfaceDocumentation(t *testing.T) {
 		_ = s.CloseIssue
 		_ = s.DeleteIssue
 		_ = s.SearchIssues
+		_ = s.CountIssues
 
 		// Verify dependency operations
 		_ = s.AddDependency
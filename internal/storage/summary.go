@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Summarize computes the 'bd prompt' summary: ready and blocked counts plus
+// the in-progress issues, limited to assignee when it is non-empty.
+func Summarize(ctx context.Context, s Storage, assignee string) (*types.PromptSummary, error) {
+	ready, err := s.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := s.GetBlockedIssues(ctx, types.WorkFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	status := types.StatusInProgress
+	filter := types.IssueFilter{Status: &status}
+	if assignee != "" {
+		filter.Assignee = &assignee
+	}
+	active, err := s.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].UpdatedAt.Before(active[j].UpdatedAt)
+	})
+
+	summary := &types.PromptSummary{Ready: len(ready), Blocked: len(blocked)}
+	for _, issue := range active {
+		summary.InProgress = append(summary.InProgress, issue.ID)
+	}
+	return summary, nil
+}
@@ -0,0 +1,239 @@
+// Package subset implements sparse clones for large trackers.
+//
+// A clone initialized with bd init --subset "label:frontend" materializes
+// only the issues its filter matches. Every other issue is imported as a
+// stub: the same ID, title, status, labels and dependencies, but no long
+// text or comments. So references to those issues still resolve and the
+// dependency graph stays whole.
+//
+// The JSONL stays the full project. Exports write stubs back with the long
+// text and comments taken from the existing JSONL, so a subset clone never
+// truncates issues it doesn't hold in full.
+package subset
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ConfigKey is the per-clone database config key holding the filter.
+const ConfigKey = "subset"
+
+// Keys are the fields a filter term can match on.
+var Keys = []string{"label", "type", "status", "assignee", "priority", "prefix"}
+
+// Filter selects the issues a subset clone holds in full. Terms are ANDed;
+// the comma-separated values within a term are ORed.
+type Filter struct {
+	expr  string
+	terms []term
+}
+
+type term struct {
+	key    string
+	values []string
+}
+
+// Parse parses a filter such as "label:frontend,ui type:bug".
+func Parse(expr string) (*Filter, error) {
+	f := &Filter{expr: strings.TrimSpace(expr)}
+	for _, field := range strings.Fields(expr) {
+		key, value, ok := strings.Cut(field, ":")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid subset term %q (expected key:value)", field)
+		}
+		if !validKey(key) {
+			return nil, fmt.Errorf("unknown subset key %q (valid: %s)", key, strings.Join(Keys, ", "))
+		}
+		values := strings.Split(value, ",")
+		if key == "priority" {
+			for _, v := range values {
+				if _, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(v), "P")); err != nil {
+					return nil, fmt.Errorf("invalid priority %q in subset", v)
+				}
+			}
+		}
+		f.terms = append(f.terms, term{key: key, values: values})
+	}
+	if len(f.terms) == 0 {
+		return nil, fmt.Errorf("empty subset filter")
+	}
+	return f, nil
+}
+
+func validKey(key string) bool {
+	for _, k := range Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the filter as it was written.
+func (f *Filter) String() string {
+	return f.expr
+}
+
+// Match reports whether issue belongs to the subset.
+func (f *Filter) Match(issue *types.Issue) bool {
+	for _, t := range f.terms {
+		if !t.match(issue) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t term) match(issue *types.Issue) bool {
+	for _, v := range t.values {
+		switch t.key {
+		case "label":
+			for _, label := range issue.Labels {
+				if label == v {
+					return true
+				}
+			}
+		case "type":
+			if string(issue.IssueType) == v {
+				return true
+			}
+		case "status":
+			if string(issue.Status) == v {
+				return true
+			}
+		case "assignee":
+			if issue.Assignee == v {
+				return true
+			}
+		case "priority":
+			p, _ := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(v), "P"))
+			if issue.Priority == p {
+				return true
+			}
+		case "prefix":
+			if strings.HasPrefix(issue.ID, strings.TrimSuffix(v, "-")+"-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Load returns the clone's subset filter, or nil for a full clone.
+func Load(ctx context.Context, s storage.Storage) (*Filter, error) {
+	expr, err := s.GetConfig(ctx, ConfigKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subset config: %w", err)
+	}
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	return Parse(expr)
+}
+
+// Stub returns a copy of issue without its long text and comments.
+func Stub(issue *types.Issue) *types.Issue {
+	stub := *issue
+	stub.Description = ""
+	stub.Design = ""
+	stub.AcceptanceCriteria = ""
+	stub.Notes = ""
+	stub.Comments = nil
+	return &stub
+}
+
+// Restore returns the full issue to export for a stub: the stub's fields
+// where it was edited since full was written, with full's long text and
+// comments.
+func Restore(full, stub *types.Issue) *types.Issue {
+	if full.UpdatedAt.After(stub.UpdatedAt) {
+		return full
+	}
+	restored := *stub
+	restored.Description = full.Description
+	restored.Design = full.Design
+	restored.AcceptanceCriteria = full.AcceptanceCriteria
+	restored.Notes = full.Notes
+	restored.Comments = full.Comments
+	return &restored
+}
+
+// stubStore is implemented by stores that track subset stubs (SQLite).
+type stubStore interface {
+	GetStubIDs(ctx context.Context) (map[string]bool, error)
+}
+
+// StubIDs returns the IDs of the issues s holds as stubs.
+func StubIDs(ctx context.Context, s storage.Storage) (map[string]bool, error) {
+	ss, ok := s.(stubStore)
+	if !ok {
+		return nil, nil
+	}
+	return ss.GetStubIDs(ctx)
+}
+
+// PreserveStubs replaces the stubs among issues with their restored full
+// form, read from the JSONL at jsonlPath. Call it on the issues an export is
+// about to write. Stores that don't track stubs return issues unchanged.
+func PreserveStubs(ctx context.Context, s storage.Storage, jsonlPath string, issues []*types.Issue) ([]*types.Issue, error) {
+	stubs, err := StubIDs(ctx, s)
+	if err != nil || len(stubs) == 0 {
+		return issues, err
+	}
+	full, err := readIssues(jsonlPath, stubs)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*types.Issue, len(issues))
+	for i, issue := range issues {
+		out[i] = issue
+		if f, ok := full[issue.ID]; ok && stubs[issue.ID] {
+			out[i] = Restore(f, issue)
+		}
+	}
+	return out, nil
+}
+
+// readIssues returns the issues in the JSONL at path whose IDs are in ids.
+func readIssues(path string, ids map[string]bool) (map[string]*types.Issue, error) {
+	// #nosec G304 - path is the beads JSONL
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	issues := make(map[string]*types.Issue)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var head struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(line, &head) != nil || !ids[head.ID] {
+			continue
+		}
+		var issue types.Issue
+		if err := json.Unmarshal(line, &issue); err != nil {
+			return nil, fmt.Errorf("failed to parse %s in %s: %w", head.ID, path, err)
+		}
+		issues[issue.ID] = &issue
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return issues, nil
+}
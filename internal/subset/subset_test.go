@@ -0,0 +1,117 @@
+package subset
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParseAndMatch(t *testing.T) {
+	f, err := Parse("label:frontend,ui type:bug")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	tests := []struct {
+		name  string
+		issue types.Issue
+		want  bool
+	}{
+		{"label and type", types.Issue{Labels: []string{"ui"}, IssueType: types.TypeBug}, true},
+		{"label only", types.Issue{Labels: []string{"frontend"}, IssueType: types.TypeTask}, false},
+		{"type only", types.Issue{IssueType: types.TypeBug}, false},
+	}
+	for _, tt := range tests {
+		if got := f.Match(&tt.issue); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	p, err := Parse("priority:P0,1 prefix:web")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !p.Match(&types.Issue{ID: "web-1", Priority: 1}) || p.Match(&types.Issue{ID: "webx-1", Priority: 0}) {
+		t.Error("priority/prefix terms matched wrongly")
+	}
+
+	for _, bad := range []string{"", "frontend", "color:red", "priority:high"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRestoreKeepsLocalEditsAndFullText(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	full := &types.Issue{ID: "bd-1", Title: "Old", Description: "Long text", Status: types.StatusOpen, UpdatedAt: t0,
+		Comments: []*types.Comment{{Text: "hi"}}}
+
+	stub := Stub(full)
+	if stub.Description != "" || stub.Comments != nil || full.Description == "" {
+		t.Fatalf("Stub should strip a copy, got stub=%+v full=%+v", stub, full)
+	}
+
+	stub.Status = types.StatusClosed
+	stub.UpdatedAt = t0.Add(time.Hour)
+	got := Restore(full, stub)
+	if got.Status != types.StatusClosed || got.Description != "Long text" || len(got.Comments) != 1 {
+		t.Errorf("Restore = %+v, want the local close with the full text", got)
+	}
+
+	// A newer JSONL entry (not yet imported) wins over the stale stub
+	full.UpdatedAt = t0.Add(2 * time.Hour)
+	if Restore(full, stub) != full {
+		t.Error("Restore should keep the newer JSONL issue")
+	}
+}
+
+func TestPreserveStubs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := sqlite.New(ctx, filepath.Join(dir, "beads.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "bd"); err != nil {
+		t.Fatal(err)
+	}
+
+	full := &types.Issue{ID: "bd-1", Title: "Backend", Description: "Full text", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, Stub(full), "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetStubs(ctx, []string{"bd-1"}, true); err != nil {
+		t.Fatalf("SetStubs failed: %v", err)
+	}
+	stub, _ := store.GetIssue(ctx, "bd-1")
+	full.UpdatedAt = stub.UpdatedAt
+
+	jsonlPath := filepath.Join(dir, "issues.jsonl")
+	data, _ := json.Marshal(full)
+	if err := os.WriteFile(jsonlPath, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := PreserveStubs(ctx, store, jsonlPath, []*types.Issue{stub})
+	if err != nil {
+		t.Fatalf("PreserveStubs failed: %v", err)
+	}
+	if got[0].Description != "Full text" {
+		t.Errorf("exported stub description = %q, want the JSONL text", got[0].Description)
+	}
+
+	if err := store.SetStubs(ctx, []string{"bd-1"}, false); err != nil {
+		t.Fatalf("SetStubs failed: %v", err)
+	}
+	got, _ = PreserveStubs(ctx, store, jsonlPath, []*types.Issue{stub})
+	if got[0] != stub {
+		t.Error("a fully materialized issue should export as is")
+	}
+}
@@ -0,0 +1,303 @@
+// Package taskwarrior mirrors beads issues into Taskwarrior through the
+// task command's JSON export and import.
+//
+// Synced tasks carry the issue ID in a "beadsid" user-defined attribute
+// (UDA) and live under one project, so a filter on that project finds
+// every task bd manages.
+package taskwarrior
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1" // #nosec G505 - name-based UUIDs (RFC 4122 v5), not security
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// UDA is the user-defined attribute holding the beads issue ID.
+const UDA = "beadsid"
+
+// TimeLayout is Taskwarrior's JSON date format.
+const TimeLayout = "20060102T150405Z"
+
+// Time is a Taskwarrior timestamp.
+type Time struct{ time.Time }
+
+// MarshalJSON writes the timestamp in Taskwarrior's format.
+func (t Time) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.UTC().Format(TimeLayout))
+}
+
+// UnmarshalJSON reads a Taskwarrior timestamp.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(TimeLayout, s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// Task is a Taskwarrior task.
+type Task struct {
+	UUID        string   `json:"uuid"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"` // pending, completed, deleted, waiting, recurring
+	Project     string   `json:"project,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Priority    string   `json:"priority,omitempty"` // H, M, L or ""
+	Entry       *Time    `json:"entry,omitempty"`
+	Modified    *Time    `json:"modified,omitempty"`
+	Start       *Time    `json:"start,omitempty"`
+	End         *Time    `json:"end,omitempty"`
+	Due         *Time    `json:"due,omitempty"`
+	Wait        *Time    `json:"wait,omitempty"`
+	Depends     []string `json:"depends,omitempty"`
+	BeadsID     string   `json:"beadsid,omitempty"`
+
+	// Raw is the task as exported, so that attributes bd doesn't manage
+	// (annotations, other UDAs) survive being imported back.
+	Raw map[string]json.RawMessage `json:"-"`
+}
+
+// managed lists the attributes bd writes; the others are kept from Raw.
+var managed = []string{"uuid", "description", "status", "project", "tags", "priority",
+	"start", "end", "due", "wait", UDA}
+
+// Runner runs the task command with args and stdin, returning stdout.
+type Runner func(ctx context.Context, args []string, stdin []byte) ([]byte, error)
+
+// Client talks to Taskwarrior through the task command.
+type Client struct {
+	Bin string
+	Run Runner
+}
+
+// NewClient returns a client running bin (usually "task").
+func NewClient(bin string) *Client {
+	c := &Client{Bin: bin}
+	c.Run = c.exec
+	return c
+}
+
+func (c *Client) exec(ctx context.Context, args []string, stdin []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, c.Bin, args...) // #nosec G204 - bin is user configuration
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s %s: %w: %s", c.Bin, args[len(args)-1], err, msg)
+		}
+		return nil, fmt.Errorf("%s %s: %w", c.Bin, args[len(args)-1], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// rcArgs declares the beadsid UDA for this invocation and turns off
+// prompts, hooks and chatter.
+func rcArgs() []string {
+	return []string{
+		"rc.confirmation=off",
+		"rc.hooks=off",
+		"rc.verbose=nothing",
+		"rc.json.array=on",
+		"rc.uda." + UDA + ".type=string",
+		"rc.uda." + UDA + ".label=Beads",
+	}
+}
+
+// Export returns the tasks in project (including subprojects), completed
+// and deleted ones too.
+func (c *Client) Export(ctx context.Context, project string) ([]Task, error) {
+	args := append(rcArgs(), "project:"+project, "export")
+	out, err := c.Run(ctx, args, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+	var raws []map[string]json.RawMessage
+	if err := json.Unmarshal(out, &raws); err != nil {
+		return nil, fmt.Errorf("parsing task export: %w", err)
+	}
+	tasks := make([]Task, len(raws))
+	for i, raw := range raws {
+		data, _ := json.Marshal(raw)
+		if err := json.Unmarshal(data, &tasks[i]); err != nil {
+			return nil, fmt.Errorf("parsing task export: %w", err)
+		}
+		tasks[i].Raw = raw
+	}
+	return tasks, nil
+}
+
+// Import adds or updates tasks, matching existing ones by UUID.
+func (c *Client) Import(ctx context.Context, tasks []Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	merged := make([]map[string]json.RawMessage, len(tasks))
+	for i := range tasks {
+		m, err := tasks[i].merge()
+		if err != nil {
+			return fmt.Errorf("encoding tasks: %w", err)
+		}
+		merged[i] = m
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("encoding tasks: %w", err)
+	}
+	_, err = c.Run(ctx, append(rcArgs(), "import"), data)
+	return err
+}
+
+// merge overlays the attributes bd manages onto the exported task.
+func (t *Task) merge() (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	out := make(map[string]json.RawMessage, len(t.Raw)+len(fields))
+	for k, v := range t.Raw {
+		out[k] = v
+	}
+	for _, k := range managed {
+		delete(out, k)
+	}
+	for k, v := range fields {
+		out[k] = v
+	}
+	delete(out, "modified") // Taskwarrior stamps the import time
+	return out, nil
+}
+
+// UUIDFor returns the UUID used for a task created from issueID: a name
+// based (version 5) UUID, so the same issue always maps to the same task.
+func UUIDFor(issueID string) string {
+	sum := sha1.Sum([]byte("beads:" + issueID)) // #nosec G401 - not security
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// Priority maps a beads priority to H/M/L: P0-P1 are H, P2 is M, P3 is L
+// and P4 has no priority.
+func Priority(p int) string {
+	switch {
+	case p <= 1:
+		return "H"
+	case p == 2:
+		return "M"
+	case p == 3:
+		return "L"
+	}
+	return ""
+}
+
+// BeadsPriority maps a Taskwarrior priority back to beads.
+func BeadsPriority(p string) int {
+	switch strings.ToUpper(p) {
+	case "H":
+		return 1
+	case "M":
+		return 2
+	case "L":
+		return 3
+	}
+	return 4
+}
+
+// Tag converts a label to a tag, which can't contain spaces.
+func Tag(label string) string {
+	return strings.ReplaceAll(label, " ", "_")
+}
+
+// Project returns the project for an issue: base, or base.<component> when
+// the issue has a component:<name> label.
+func Project(base string, labels []string) string {
+	for _, label := range labels {
+		if component, ok := strings.CutPrefix(label, "component:"); ok && component != "" {
+			return base + "." + component
+		}
+	}
+	return base
+}
+
+// FromIssue builds the task mirroring issue. uuid is the existing task's
+// UUID, or "" to use UUIDFor.
+func FromIssue(issue *types.Issue, uuid, project string) Task {
+	if uuid == "" {
+		uuid = UUIDFor(issue.ID)
+	}
+	task := Task{
+		UUID:        uuid,
+		Description: issue.Title,
+		Status:      "pending",
+		Project:     Project(project, issue.Labels),
+		Priority:    Priority(issue.Priority),
+		BeadsID:     issue.ID,
+	}
+	if !issue.CreatedAt.IsZero() {
+		task.Entry = &Time{issue.CreatedAt}
+	}
+	for _, label := range issue.Labels {
+		if !strings.HasPrefix(label, "component:") {
+			task.Tags = append(task.Tags, Tag(label))
+		}
+	}
+	switch issue.Status {
+	case types.StatusClosed:
+		task.Status = "completed"
+		end := issue.UpdatedAt
+		if issue.ClosedAt != nil {
+			end = *issue.ClosedAt
+		}
+		task.End = &Time{end}
+	case types.StatusInProgress, types.StatusHooked:
+		task.Start = &Time{issue.UpdatedAt}
+	case types.StatusBlocked:
+		task.Tags = append(task.Tags, "blocked")
+	}
+	if issue.DueAt != nil {
+		task.Due = &Time{*issue.DueAt}
+	}
+	if issue.DeferUntil != nil && issue.DeferUntil.After(time.Now()) && task.Status == "pending" {
+		task.Wait = &Time{*issue.DeferUntil}
+	}
+	return task
+}
+
+// Status maps a task's state to a beads status. Blocked issues carry the
+// +blocked tag; other pending tasks are in progress once started.
+func Status(task *Task) types.Status {
+	switch task.Status {
+	case "completed", "deleted":
+		return types.StatusClosed
+	}
+	for _, tag := range task.Tags {
+		if tag == "blocked" {
+			return types.StatusBlocked
+		}
+	}
+	if task.Start != nil {
+		return types.StatusInProgress
+	}
+	return types.StatusOpen
+}
@@ -0,0 +1,93 @@
+package taskwarrior
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestExportImportKeepsUnmanagedAttributes(t *testing.T) {
+	var gotArgs []string
+	var imported []map[string]any
+	client := &Client{Bin: "task", Run: func(ctx context.Context, args []string, stdin []byte) ([]byte, error) {
+		gotArgs = args
+		switch args[len(args)-1] {
+		case "export":
+			return []byte(`[{"uuid":"u1","description":"Write docs","status":"pending","project":"beads",
+				"beadsid":"bd-1","modified":"20250102T030405Z","annotations":[{"description":"keep me"}],"estimate":"2h"}]`), nil
+		case "import":
+			if err := json.Unmarshal(stdin, &imported); err != nil {
+				t.Fatalf("import payload: %v", err)
+			}
+		}
+		return nil, nil
+	}}
+
+	tasks, err := client.Export(context.Background(), "beads")
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if !slices.Contains(gotArgs, "project:beads") || !slices.Contains(gotArgs, "rc.uda.beadsid.type=string") {
+		t.Errorf("export args = %v", gotArgs)
+	}
+	if len(tasks) != 1 || tasks[0].BeadsID != "bd-1" || !tasks[0].Modified.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("tasks = %+v", tasks)
+	}
+
+	task := tasks[0]
+	task.Description = "Write better docs"
+	task.Tags = nil
+	if err := client.Import(context.Background(), []Task{task}); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if len(imported) != 1 {
+		t.Fatalf("imported %d tasks", len(imported))
+	}
+	got := imported[0]
+	if got["description"] != "Write better docs" || got["estimate"] != "2h" || got["annotations"] == nil {
+		t.Errorf("imported = %v", got)
+	}
+	if _, ok := got["modified"]; ok {
+		t.Error("modified should be left to Taskwarrior")
+	}
+}
+
+func TestFromIssueAndStatus(t *testing.T) {
+	now := time.Now()
+	issue := &types.Issue{ID: "bd-7", Title: "Ship it", Status: types.StatusInProgress, Priority: 0,
+		Labels: []string{"component:api", "needs review"}, UpdatedAt: now}
+	task := FromIssue(issue, "", "work")
+	if task.UUID != UUIDFor("bd-7") || task.Project != "work.api" || task.Priority != "H" || task.Start == nil {
+		t.Errorf("task = %+v", task)
+	}
+	if !slices.Equal(task.Tags, []string{"needs_review"}) {
+		t.Errorf("tags = %v", task.Tags)
+	}
+	if Status(&task) != types.StatusInProgress {
+		t.Errorf("Status = %s", Status(&task))
+	}
+
+	issue.Status = types.StatusBlocked
+	blocked := FromIssue(issue, "u", "work")
+	if Status(&blocked) != types.StatusBlocked {
+		t.Errorf("blocked Status = %s", Status(&blocked))
+	}
+	issue.Status = types.StatusClosed
+	if closed := FromIssue(issue, "u", "work"); closed.Status != "completed" || closed.End == nil {
+		t.Errorf("closed task = %+v", closed)
+	}
+
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(UUIDFor("bd-7")) {
+		t.Errorf("UUIDFor = %s, want a v5 UUID", UUIDFor("bd-7"))
+	}
+	for p := 0; p <= 4; p++ {
+		if back := BeadsPriority(Priority(p)); Priority(back) != Priority(p) {
+			t.Errorf("priority %d round-trips to %d", p, back)
+		}
+	}
+}
@@ -365,7 +365,7 @@ func generateFromJSONL(ctx context.Context, store storage.Storage, tempDir strin
 	}
 
 	for _, issue := range allIssues {
-		if err := store.DeleteIssue(ctx, issue.ID); err != nil {
+		if err := store.DeleteIssue(ctx, issue.ID, "fixture"); err != nil {
 			return fmt.Errorf("failed to delete issue %s: %w", issue.ID, err)
 		}
 	}
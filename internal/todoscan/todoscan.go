@@ -0,0 +1,218 @@
+// Package todoscan finds TODO, FIXME and HACK comments in source files and
+// gives each a fingerprint that survives the comment moving within its
+// file, for bd scan todos.
+package todoscan
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxFileSize bounds the files read; bigger ones are generated or data.
+const maxFileSize = 1 << 20
+
+// skipDirs are never descended into: dependencies and fixtures carry
+// comments that aren't the project's work.
+var skipDirs = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+	"testdata":     true,
+}
+
+// markerPattern matches a marker at the start of a comment: after //, #,
+// /*, *, --, ; or <!--, optionally with an (author) and a colon.
+var markerPattern = regexp.MustCompile(`(?:^|\s)(?://+|#+|/\*+|\*|--|;+|<!--)\s*(TODO|FIXME|HACK)(?:\(([^)]*)\))?(?::|\s|$)\s*(.*)$`)
+
+// Todo is one marker comment.
+type Todo struct {
+	Kind        string `json:"kind"` // TODO, FIXME or HACK
+	Author      string `json:"author,omitempty"`
+	Text        string `json:"text"`
+	File        string `json:"file"` // Slash-separated, relative to the scan root
+	Line        int    `json:"line"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Location is the comment's file:line.
+func (t *Todo) Location() string {
+	return t.File + ":" + strconv.Itoa(t.Line)
+}
+
+// ParseLine returns the marker comment on a line, if any.
+func ParseLine(line string) (kind, author, text string, ok bool) {
+	m := markerPattern.FindStringSubmatch(line)
+	if m == nil {
+		return "", "", "", false
+	}
+	text = strings.TrimSpace(m[3])
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(text, "-->"), "*/"))
+	return m[1], strings.TrimSpace(m[2]), text, true
+}
+
+// ScanFile returns the marker comments in a file's contents. Binary files
+// have none.
+func ScanFile(file string, data []byte) []Todo {
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil
+	}
+	var todos []Todo
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 64*1024), maxFileSize)
+	for n := 1; sc.Scan(); n++ {
+		if kind, author, text, ok := ParseLine(sc.Text()); ok {
+			todos = append(todos, Todo{Kind: kind, Author: author, Text: text, File: file, Line: n})
+		}
+	}
+	return todos
+}
+
+// Fingerprint sets the fingerprint of each comment: a hash of its file,
+// marker and normalized text, so it survives lines moving above it. Equal
+// comments in one file are told apart by their order.
+func Fingerprint(todos []Todo) {
+	seen := make(map[string]int)
+	for i := range todos {
+		t := &todos[i]
+		key := t.File + "\x00" + t.Kind + "\x00" + strings.Join(strings.Fields(strings.ToLower(t.Text)), " ")
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key += "\x00" + strconv.Itoa(n)
+		}
+		sum := sha256.Sum256([]byte(key))
+		t.Fingerprint = hex.EncodeToString(sum[:])[:12]
+	}
+}
+
+// Scan finds the marker comments in files under root matching patterns
+// (see Files), fingerprinted and in file order.
+func Scan(root string, patterns []string) ([]Todo, error) {
+	files, err := Files(root, patterns)
+	if err != nil {
+		return nil, err
+	}
+	var todos []Todo
+	for _, file := range files {
+		full := filepath.Join(root, filepath.FromSlash(file))
+		info, err := os.Stat(full)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxFileSize {
+			continue
+		}
+		data, err := os.ReadFile(full) // #nosec G304 -- files under the scan root
+		if err != nil {
+			continue
+		}
+		todos = append(todos, ScanFile(file, data)...)
+	}
+	Fingerprint(todos)
+	return todos, nil
+}
+
+// Files lists the files under root that patterns select, slash-separated
+// and relative to root. A pattern is a file or directory relative to root;
+// directories are scanned recursively, and a Go-style "/..." suffix is
+// accepted. In a git work tree, ignored files are left out. Hidden,
+// vendor, node_modules and testdata directories are skipped.
+func Files(root string, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	var prefixes []string
+	for _, p := range patterns {
+		p = strings.TrimSuffix(filepath.ToSlash(p), "...")
+		p = path.Clean(strings.TrimSuffix(p, "/"))
+		if p == "" {
+			p = "."
+		}
+		prefixes = append(prefixes, p)
+	}
+
+	all, err := gitFiles(root)
+	if err != nil {
+		if all, err = walkFiles(root); err != nil {
+			return nil, err
+		}
+	}
+	var files []string
+	for _, f := range all {
+		if skipped(f) {
+			continue
+		}
+		for _, p := range prefixes {
+			if Within(f, p) {
+				files = append(files, f)
+				break
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Within reports whether a slash-separated file is dir or inside it.
+func Within(file, dir string) bool {
+	return dir == "." || file == dir || strings.HasPrefix(file, dir+"/")
+}
+
+// skipped reports whether a file is under a skipped directory.
+func skipped(file string) bool {
+	parts := strings.Split(file, "/")
+	for _, dir := range parts[:len(parts)-1] {
+		if skipDirs[dir] || strings.HasPrefix(dir, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// gitFiles lists tracked and untracked, non-ignored files of the work tree
+// at root.
+func gitFiles(root string) ([]string, error) {
+	out, err := exec.Command("git", "-C", root, "ls-files", "-z", "--cached", "--others", "--exclude-standard").Output() // #nosec G204 -- fixed arguments
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// walkFiles lists every file under root.
+func walkFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && (skipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
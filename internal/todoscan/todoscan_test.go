@@ -0,0 +1,92 @@
+package todoscan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line               string
+		kind, author, text string
+		ok                 bool
+	}{
+		{"\t// TODO: handle timeouts", "TODO", "", "handle timeouts", true},
+		{"x := 1 // FIXME(alice): off by one", "FIXME", "alice", "off by one", true},
+		{"# HACK work around pip bug", "HACK", "", "work around pip bug", true},
+		{"/* TODO remove after 2.0 */", "TODO", "", "remove after 2.0", true},
+		{"<!-- TODO: screenshot -->", "TODO", "", "screenshot", true},
+		{"-- TODO", "TODO", "", "", true},
+		{`msg := "TODO: not a comment"`, "", "", "", false},
+		{"// TODOS are tracked elsewhere", "", "", "", false},
+		{"// todo lowercase is prose", "", "", "", false},
+	}
+	for _, tt := range tests {
+		kind, author, text, ok := ParseLine(tt.line)
+		if kind != tt.kind || author != tt.author || text != tt.text || ok != tt.ok {
+			t.Errorf("ParseLine(%q) = %q, %q, %q, %v", tt.line, kind, author, text, ok)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	todos := ScanFile("a.go", []byte("// TODO: fix\n\n// TODO: fix\n// FIXME: fix\n"))
+	if len(todos) != 3 || todos[1].Line != 3 {
+		t.Fatalf("ScanFile = %+v", todos)
+	}
+	Fingerprint(todos)
+	if todos[0].Fingerprint == todos[1].Fingerprint || todos[0].Fingerprint == todos[2].Fingerprint {
+		t.Errorf("fingerprints not distinct: %+v", todos)
+	}
+
+	// Moving a comment down doesn't change its fingerprint; reformatting
+	// its whitespace doesn't either
+	moved := ScanFile("a.go", []byte("package a\n\n//   TODO:   Fix\n"))
+	Fingerprint(moved)
+	if moved[0].Fingerprint != todos[0].Fingerprint {
+		t.Errorf("fingerprint changed when the comment moved")
+	}
+
+	if ScanFile("bin", []byte("\x00\x01// TODO: x")) != nil {
+		t.Errorf("binary file scanned")
+	}
+}
+
+func TestFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"main.go", "pkg/a.go", "pkg/sub/b.go", "vendor/x/c.go", ".git/config", "other/d.go"} {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("// TODO: "+f+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := walkFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 4 {
+		t.Errorf("walkFiles = %v", files)
+	}
+
+	files, err = Files(root, []string{"./pkg/...", "main.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"main.go", "pkg/a.go", "pkg/sub/b.go"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("Files = %v, want %v", files, want)
+	}
+
+	todos, err := Scan(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todos) != 4 || todos[0].Text != "main.go" || todos[0].Location() != "main.go:1" {
+		t.Errorf("Scan = %+v", todos)
+	}
+}
@@ -0,0 +1,167 @@
+// Package trello reads Trello board JSON exports (Board menu > Print,
+// export and share > Export as JSON) for import into beads.
+package trello
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Board is the part of a Trello board export that beads imports.
+type Board struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	URL        string      `json:"url"`
+	Lists      []List      `json:"lists"`
+	Cards      []Card      `json:"cards"`
+	Checklists []Checklist `json:"checklists"`
+	Members    []Member    `json:"members"`
+}
+
+// List is a board column.
+type List struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"` // archived
+}
+
+// Card is a Trello card.
+type Card struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Desc             string    `json:"desc"`
+	IDList           string    `json:"idList"`
+	Closed           bool      `json:"closed"` // archived
+	DueComplete      bool      `json:"dueComplete"`
+	Labels           []Label   `json:"labels"`
+	IDMembers        []string  `json:"idMembers"`
+	ShortURL         string    `json:"shortUrl"`
+	URL              string    `json:"url"`
+	DateLastActivity time.Time `json:"dateLastActivity"`
+	Due              *string   `json:"due"`
+}
+
+// Label is a card label. Unnamed labels are identified only by color.
+type Label struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Checklist is a named checklist on a card.
+type Checklist struct {
+	ID         string      `json:"id"`
+	IDCard     string      `json:"idCard"`
+	Name       string      `json:"name"`
+	Pos        float64     `json:"pos"`
+	CheckItems []CheckItem `json:"checkItems"`
+}
+
+// CheckItem is one checklist item.
+type CheckItem struct {
+	Name  string  `json:"name"`
+	State string  `json:"state"` // "complete" or "incomplete"
+	Pos   float64 `json:"pos"`
+}
+
+// Member is a board member.
+type Member struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	FullName string `json:"fullName"`
+}
+
+// Parse reads a board export.
+func Parse(r io.Reader) (*Board, error) {
+	var board Board
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, fmt.Errorf("not a Trello board export: %w", err)
+	}
+	if board.ID == "" && len(board.Cards) == 0 {
+		return nil, fmt.Errorf("not a Trello board export: no board id or cards")
+	}
+	return &board, nil
+}
+
+// ListName returns the name of the list a card is in.
+func (b *Board) ListName(card *Card) string {
+	for _, l := range b.Lists {
+		if l.ID == card.IDList {
+			return l.Name
+		}
+	}
+	return ""
+}
+
+// Username returns a member's username, or "" if unknown.
+func (b *Board) Username(memberID string) string {
+	for _, m := range b.Members {
+		if m.ID == memberID {
+			return m.Username
+		}
+	}
+	return ""
+}
+
+// CreatedAt returns when a card was created, which Trello encodes in the
+// first 8 hex digits of its ID (a Unix timestamp).
+func (c *Card) CreatedAt() time.Time {
+	var secs int64
+	if len(c.ID) >= 8 {
+		if _, err := fmt.Sscanf(c.ID[:8], "%x", &secs); err == nil {
+			return time.Unix(secs, 0).UTC()
+		}
+	}
+	return c.DateLastActivity
+}
+
+// ChecklistMarkdown returns the card's checklists as a Markdown task list, one
+// section per checklist when there are several.
+func (b *Board) ChecklistMarkdown(card *Card) string {
+	var lists []Checklist
+	for _, cl := range b.Checklists {
+		if cl.IDCard == card.ID && len(cl.CheckItems) > 0 {
+			lists = append(lists, cl)
+		}
+	}
+	sort.SliceStable(lists, func(i, j int) bool { return lists[i].Pos < lists[j].Pos })
+
+	var sb strings.Builder
+	for i, cl := range lists {
+		if len(lists) > 1 {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(cl.Name + ":\n")
+		}
+		items := append([]CheckItem{}, cl.CheckItems...)
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+		for _, item := range items {
+			box := "[ ]"
+			if item.State == "complete" {
+				box = "[x]"
+			}
+			sb.WriteString("- " + box + " " + item.Name + "\n")
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// LabelNames returns a card's label names, using the color for unnamed
+// labels.
+func LabelNames(card *Card) []string {
+	var names []string
+	for _, l := range card.Labels {
+		name := strings.TrimSpace(l.Name)
+		if name == "" {
+			name = l.Color
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,67 @@
+package trello
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const boardJSON = `{
+  "id": "5f1a2b3c4d5e6f7a8b9c0d1e",
+  "name": "Roadmap",
+  "lists": [
+    {"id": "l1", "name": "To Do"},
+    {"id": "l2", "name": "Done"},
+    {"id": "l3", "name": "Old", "closed": true}
+  ],
+  "members": [{"id": "m1", "username": "ana", "fullName": "Ana Li"}],
+  "cards": [
+    {"id": "5f1a2b3c0000000000000001", "name": "Write docs", "idList": "l1",
+     "labels": [{"name": "docs", "color": "blue"}, {"name": "", "color": "red"}],
+     "idMembers": ["m1"], "shortUrl": "https://trello.com/c/abc",
+     "dateLastActivity": "2024-03-01T10:00:00.000Z"}
+  ],
+  "checklists": [
+    {"id": "c2", "idCard": "5f1a2b3c0000000000000001", "name": "Review", "pos": 2,
+     "checkItems": [{"name": "Proofread", "state": "incomplete", "pos": 1}]},
+    {"id": "c1", "idCard": "5f1a2b3c0000000000000001", "name": "Draft", "pos": 1,
+     "checkItems": [
+       {"name": "Examples", "state": "incomplete", "pos": 2},
+       {"name": "Outline", "state": "complete", "pos": 1}
+     ]}
+  ]
+}`
+
+func TestParseBoard(t *testing.T) {
+	board, err := Parse(strings.NewReader(boardJSON))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	card := &board.Cards[0]
+	if got := board.ListName(card); got != "To Do" {
+		t.Errorf("ListName = %q, want To Do", got)
+	}
+	if got := board.Username("m1"); got != "ana" {
+		t.Errorf("Username = %q, want ana", got)
+	}
+	if got, want := card.CreatedAt(), time.Unix(0x5f1a2b3c, 0).UTC(); !got.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", got, want)
+	}
+	if got := LabelNames(card); strings.Join(got, ",") != "docs,red" {
+		t.Errorf("LabelNames = %v, want [docs red]", got)
+	}
+
+	want := "Draft:\n- [x] Outline\n- [ ] Examples\n\nReview:\n- [ ] Proofread"
+	if got := board.ChecklistMarkdown(card); got != want {
+		t.Errorf("ChecklistMarkdown =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseRejectsOtherJSON(t *testing.T) {
+	if _, err := Parse(strings.NewReader(`{"foo": 1}`)); err == nil {
+		t.Error("expected an error for JSON that isn't a board export")
+	}
+	if _, err := Parse(strings.NewReader(`not json`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
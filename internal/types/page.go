@@ -0,0 +1,117 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// IssuePage is one page of a cursor-paginated listing (bd list/ready/search
+// with --cursor). Pass NextCursor back to fetch the following page; it is
+// empty on the last page.
+type IssuePage[T any] struct {
+	Issues     []T    `json:"issues"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Cursor is the sort key of the last issue on a page. The next page holds
+// the issues that sort after it, so pages stay consistent when issues are
+// created, closed or reprioritized in between.
+type Cursor struct {
+	Priority  int       `json:"p"`
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+	// RecentSince is the recent/old boundary of the hybrid ready sort,
+	// fixed by the first page so later pages are sorted the same way.
+	RecentSince *time.Time `json:"r,omitempty"`
+}
+
+// CursorFor returns the position just after issue.
+func CursorFor(issue *Issue) Cursor {
+	return Cursor{Priority: issue.Priority, CreatedAt: issue.CreatedAt, ID: issue.ID}
+}
+
+// Issue returns an issue with the cursor's sort key, for comparing against
+// listed issues.
+func (c *Cursor) Issue() *Issue {
+	return &Issue{Priority: c.Priority, CreatedAt: c.CreatedAt, ID: c.ID}
+}
+
+// SearchLess reports whether a comes before b in SearchIssues order: by
+// priority, newest first, then by ID.
+func SearchLess(a, b *Issue) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// cursorPrefix versions the cursor encoding so it can change later without
+// misreading old cursors.
+const cursorPrefix = "k1:"
+
+// EncodeCursor returns the opaque form of c. Cursors are only meaningful
+// for the query that produced them.
+func EncodeCursor(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(append([]byte(cursorPrefix), raw...))
+}
+
+// DecodeCursor parses a cursor returned by EncodeCursor. An empty cursor
+// means the first page and decodes to nil.
+func DecodeCursor(cursor string) (*Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return nil, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw[len(cursorPrefix):], &c); err != nil || c.ID == "" {
+		return nil, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return &c, nil
+}
+
+// Paginate trims a result fetched with limit+1 rows to limit rows, and
+// returns the cursor for the next page (empty if there is none), built from
+// the key of the last row kept. A limit of 0 means the rest of the results
+// fit on this page.
+func Paginate[T any](items []T, limit int, key func(T) Cursor) ([]T, string) {
+	if limit <= 0 || len(items) <= limit {
+		return items, ""
+	}
+	return items[:limit], EncodeCursor(key(items[limit-1]))
+}
+
+// HybridRecentWindow is how new an issue must be for the hybrid ready sort
+// to order it by priority rather than by age.
+const HybridRecentWindow = 48 * time.Hour
+
+// ResumeAfter makes f select the ready work after cursor c (nil for the
+// first page), pinning the hybrid sort's recent/old boundary so every page
+// of the listing uses the same one.
+func (f *WorkFilter) ResumeAfter(c *Cursor, now time.Time) {
+	f.After = c
+	if c != nil && c.RecentSince != nil {
+		f.RecentSince = *c.RecentSince
+	} else {
+		f.RecentSince = now.Add(-HybridRecentWindow).UTC().Truncate(time.Second)
+	}
+}
+
+// PageKey returns the cursor that resumes f's listing after issue.
+func (f *WorkFilter) PageKey(issue *Issue) Cursor {
+	c := CursorFor(issue)
+	if !f.RecentSince.IsZero() {
+		since := f.RecentSince
+		c.RecentSince = &since
+	}
+	return c
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, c := range []Cursor{
+		{Priority: 1, CreatedAt: time.Date(2026, 1, 3, 4, 5, 6, 789, time.FixedZone("", 2*3600)), ID: "bd-1"},
+		{Priority: 0, CreatedAt: since, ID: "bd-x.2", RecentSince: &since},
+	} {
+		got, err := DecodeCursor(EncodeCursor(c))
+		if err != nil || !reflect.DeepEqual(*got, c) {
+			t.Errorf("DecodeCursor(EncodeCursor(%+v)) = %+v, %v", c, got, err)
+		}
+	}
+	if got, err := DecodeCursor(""); err != nil || got != nil {
+		t.Errorf("empty cursor = %+v, %v; want first page", got, err)
+	}
+	for _, bad := range []string{"garbage!", "MTA", "bzE6Mg", EncodeCursor(Cursor{Priority: 1})} {
+		if _, err := DecodeCursor(bad); err == nil {
+			t.Errorf("DecodeCursor(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	issues := []*Issue{{ID: "bd-1"}, {ID: "bd-2"}, {ID: "bd-3"}}
+	tests := []struct {
+		name     string
+		limit    int
+		want     int
+		wantNext string
+	}{
+		{"more pages", 2, 2, EncodeCursor(Cursor{ID: "bd-2"})},
+		{"exact fit", 3, 3, ""},
+		{"no limit", 0, 3, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next := Paginate(issues, tt.limit, CursorFor)
+			if len(got) != tt.want || next != tt.wantNext {
+				t.Errorf("Paginate = %d issues, %q; want %d, %q", len(got), next, tt.want, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestWorkFilterResumeAfter(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 500, time.UTC)
+	var first WorkFilter
+	first.ResumeAfter(nil, now)
+	if want := now.Add(-HybridRecentWindow).Truncate(time.Second); !first.RecentSince.Equal(want) {
+		t.Fatalf("RecentSince = %v, want %v", first.RecentSince, want)
+	}
+
+	// Later pages keep the first page's boundary, however much time passes
+	c := first.PageKey(&Issue{ID: "bd-1", Priority: 2, CreatedAt: now})
+	var next WorkFilter
+	next.ResumeAfter(&c, now.Add(72*time.Hour))
+	if !next.RecentSince.Equal(first.RecentSince) || next.After.ID != "bd-1" {
+		t.Errorf("next page filter = %+v, want the first page's boundary after bd-1", next)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"hash"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 )
@@ -46,7 +48,7 @@ type Issue struct {
 	DeferUntil *time.Time `json:"defer_until,omitempty"` // Hide from bd ready until this time
 
 	// ===== External Integration =====
-	ExternalRef  *string `json:"external_ref,omitempty"`  // e.g., "gh-9", "jira-ABC"
+	ExternalRef  *string `json:"external_ref,omitempty"`  // Primary ref, e.g., "gh-9", "jira-ABC"
 	SourceSystem string  `json:"source_system,omitempty"` // Adapter/system that created this issue (federation)
 
 	// ===== Compaction Metadata =====
@@ -64,6 +66,13 @@ type Issue struct {
 	Labels       []string      `json:"labels,omitempty"`
 	Dependencies []*Dependency `json:"dependencies,omitempty"`
 	Comments     []*Comment    `json:"comments,omitempty"`
+	Refs         []*Ref        `json:"refs,omitempty"`     // External refs beyond ExternalRef
+	Security     *Security     `json:"security,omitempty"` // Advisory metadata (bd security)
+	Repro        *Repro        `json:"repro,omitempty"`    // Environment and reproduction steps (bd repro)
+	Votes        []*Vote       `json:"votes,omitempty"`    // Stakeholder votes (bd vote)
+	CC           []string      `json:"cc,omitempty"`       // Stakeholders kept informed (--cc)
+	Visibility   string        `json:"visibility,omitempty"` // public or private; empty is internal (bd visibility)
+	KeyResults   []*KeyResult  `json:"key_results,omitempty"` // Key results of a goal (bd goal)
 
 	// ===== Tombstone Fields (soft-delete support) =====
 	DeletedAt    *time.Time `json:"deleted_at,omitempty"`    // When deleted
@@ -127,6 +136,12 @@ type Issue struct {
 // ComputeContentHash creates a deterministic hash of the issue's content.
 // Uses all substantive fields (excluding ID, timestamps, and compaction metadata)
 // to ensure that identical content produces identical hashes across all clones.
+//
+// Only the issues row is hashed. Data kept in side tables (labels,
+// dependencies, comments, refs, security, repro, votes, cc, visibility and
+// key results) is left out because import doesn't use the hash to decide
+// whether to apply it: it replaces each issue's side data with the JSONL's
+// on every import, skipping only issues with unexported local changes.
 func (i *Issue) ComputeContentHash() string {
 	h := sha256.New()
 	w := hashFieldWriter{h}
@@ -500,6 +515,7 @@ const (
 	TypeConvoy       IssueType = "convoy"        // Cross-project tracking with reactive completion
 	TypeEvent        IssueType = "event"         // Operational state change record
 	TypeSlot         IssueType = "slot"          // Exclusive access slot (merge-slot gate)
+	TypeGoal         IssueType = "goal"          // Objective with key results (bd goal)
 )
 
 // IsValid checks if the issue type is a core work type.
@@ -679,6 +695,61 @@ type IssueWithCounts struct {
 	DependentCount  int `json:"dependent_count"`
 }
 
+// IssueCounts is the result of an aggregate count over issues matching a
+// filter (bd list --count, bd count). Groups is set only for grouped counts
+// and is sorted by group name.
+type IssueCounts struct {
+	Total  int          `json:"total"`
+	Groups []GroupCount `json:"groups,omitempty"`
+}
+
+// GroupCount is the number of issues in one group of a grouped count. When
+// grouping by label an issue is counted once per label, so group counts can
+// add up to more than the total.
+type GroupCount struct {
+	Group string `json:"group"`
+	Count int    `json:"count"`
+}
+
+// Count grouping keys and the placeholder groups for missing values.
+const (
+	GroupByStatus   = "status"
+	GroupByPriority = "priority"
+	GroupByType     = "type"
+	GroupByAssignee = "assignee"
+	GroupByLabel    = "label"
+
+	GroupUnassigned = "(unassigned)"
+	GroupNoLabels   = "(no labels)"
+)
+
+// ValidateGroupBy checks a count grouping key.
+func ValidateGroupBy(groupBy string) error {
+	switch groupBy {
+	case "", GroupByStatus, GroupByPriority, GroupByType, GroupByAssignee, GroupByLabel:
+		return nil
+	}
+	return fmt.Errorf("invalid group by %q (must be one of: status, priority, type, assignee, label)", groupBy)
+}
+
+// NewIssueCounts builds an IssueCounts from per-group counts, sorting the
+// groups by name.
+func NewIssueCounts(total int, groups map[string]int) *IssueCounts {
+	result := &IssueCounts{Total: total}
+	for group, count := range groups {
+		result.Groups = append(result.Groups, GroupCount{Group: group, Count: count})
+	}
+	sort.Slice(result.Groups, func(i, j int) bool {
+		return result.Groups[i].Group < result.Groups[j].Group
+	})
+	return result
+}
+
+// PriorityGroup is the group name for a priority in grouped counts.
+func PriorityGroup(priority int) string {
+	return fmt.Sprintf("P%d", priority)
+}
+
 // IssueDetails extends Issue with labels, dependencies, dependents, and comments.
 // Used for JSON serialization in bd show and RPC responses.
 type IssueDetails struct {
@@ -825,6 +896,241 @@ type Label struct {
 	Label   string `json:"label"`
 }
 
+// RefType is the kind of system an external reference points into
+type RefType string
+
+// Ref types
+const (
+	RefGitHub RefType = "github" // GitHub issue or pull request (gh-12, owner/repo#12, URL)
+	RefJira   RefType = "jira"   // Jira issue key or URL (PROJ-123)
+	RefURL    RefType = "url"    // Any other web page
+	RefDoc    RefType = "doc"    // Document or file path (docs/design.md)
+)
+
+// IsValid checks if the ref type is one bd knows
+func (t RefType) IsValid() bool {
+	switch t {
+	case RefGitHub, RefJira, RefURL, RefDoc:
+		return true
+	}
+	return false
+}
+
+// Ref is one typed external reference on an issue. Sync integrations use
+// refs as join keys in addition to the issue's primary ExternalRef.
+type Ref struct {
+	Type  RefType `json:"type"`
+	Value string  `json:"value"`
+}
+
+// RefCheck is the last liveness check of an external reference (bd ref
+// check). Checks are local to a clone and are not exported.
+type RefCheck struct {
+	Value     string    `json:"value"`
+	Status    string    `json:"status"`           // alive, dead or unknown
+	Detail    string    `json:"detail,omitempty"` // e.g. "HTTP 404"
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// PR states, review statuses and CI statuses (bd pr)
+const (
+	PRStateOpen    = "open"
+	PRStateDraft   = "draft"
+	PRStateMerged  = "merged"
+	PRStateClosed  = "closed"
+	PRStateUnknown = "unknown" // couldn't fetch: not found, auth, network or server error
+
+	PRReviewApproved         = "approved"
+	PRReviewChangesRequested = "changes_requested"
+	PRReviewPending          = "pending" // no approving or blocking review yet
+
+	PRCISuccess = "success"
+	PRCIFailure = "failure"
+	PRCIPending = "pending"
+	PRCINone    = "none" // no checks reported
+)
+
+// PRStatus is the last fetched state of a pull request linked to an issue
+// (bd pr link). Like ref checks, statuses are local to a clone and are not
+// exported.
+type PRStatus struct {
+	URL       string    `json:"url"` // https://github.com/<owner>/<repo>/pull/<n>
+	Title     string    `json:"title,omitempty"`
+	State     string    `json:"state"`
+	Review    string    `json:"review,omitempty"`
+	CI        string    `json:"ci,omitempty"`
+	Detail    string    `json:"detail,omitempty"` // Why the state is unknown
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Security is the advisory metadata of a security issue (bd security set).
+// Every field is optional.
+type Security struct {
+	CVE              string     `json:"cve,omitempty"`               // e.g. CVE-2026-1234
+	CVSS             *float64   `json:"cvss,omitempty"`              // Base score, 0.0-10.0
+	AffectedVersions string     `json:"affected_versions,omitempty"` // Free-form range, e.g. ">=1.2, <1.4.3"
+	EmbargoUntil     *time.Time `json:"embargo_until,omitempty"`     // Details stay out of exports until then
+}
+
+// IsEmpty reports whether no advisory field is set
+func (s *Security) IsEmpty() bool {
+	return s == nil || (s.CVE == "" && s.CVSS == nil && s.AffectedVersions == "" && s.EmbargoUntil == nil)
+}
+
+// Embargoed reports whether the advisory is still under embargo at now
+func (s *Security) Embargoed(now time.Time) bool {
+	return s != nil && s.EmbargoUntil != nil && now.Before(*s.EmbargoUntil)
+}
+
+// Severity is the CVSS v3 qualitative rating of the score: none, low,
+// medium, high or critical, or "" without a score
+func (s *Security) Severity() string {
+	if s == nil || s.CVSS == nil {
+		return ""
+	}
+	switch score := *s.CVSS; {
+	case score >= 9.0:
+		return "critical"
+	case score >= 7.0:
+		return "high"
+	case score >= 4.0:
+		return "medium"
+	case score > 0:
+		return "low"
+	}
+	return "none"
+}
+
+// Equal reports whether two advisories hold the same values
+func (s *Security) Equal(o *Security) bool {
+	if s.IsEmpty() || o.IsEmpty() {
+		return s.IsEmpty() == o.IsEmpty()
+	}
+	scoreEqual := (s.CVSS == nil) == (o.CVSS == nil) && (s.CVSS == nil || *s.CVSS == *o.CVSS)
+	embargoEqual := (s.EmbargoUntil == nil) == (o.EmbargoUntil == nil) && (s.EmbargoUntil == nil || s.EmbargoUntil.Equal(*o.EmbargoUntil))
+	return s.CVE == o.CVE && s.AffectedVersions == o.AffectedVersions && scoreEqual && embargoEqual
+}
+
+// Repro is the environment and reproduction metadata of a bug report
+// (bd repro set). Every field is optional.
+type Repro struct {
+	OS       string `json:"os,omitempty"`       // e.g. "macOS 15.1 arm64"
+	Version  string `json:"version,omitempty"`  // Version of the software that misbehaved
+	Steps    string `json:"steps,omitempty"`    // Steps to reproduce, one per line
+	Expected string `json:"expected,omitempty"` // What should have happened
+	Actual   string `json:"actual,omitempty"`   // What happened instead
+}
+
+// IsEmpty reports whether no repro field is set
+func (r *Repro) IsEmpty() bool {
+	return r == nil || (r.OS == "" && r.Version == "" && r.Steps == "" && r.Expected == "" && r.Actual == "")
+}
+
+// Equal reports whether two repros hold the same values
+func (r *Repro) Equal(o *Repro) bool {
+	if r.IsEmpty() || o.IsEmpty() {
+		return r.IsEmpty() == o.IsEmpty()
+	}
+	return *r == *o
+}
+
+// KeyResult is a measurable outcome of a goal (bd goal kr). Its progress
+// comes from a metric when Target is set, else from the completion of the
+// linked issues and their children.
+type KeyResult struct {
+	Title   string   `json:"title"`
+	Issues  []string `json:"issues,omitempty"`  // Linked epics and issues
+	Start   *float64 `json:"start,omitempty"`   // Metric baseline (default 0)
+	Target  *float64 `json:"target,omitempty"`  // Metric goal; set for metric key results
+	Current *float64 `json:"current,omitempty"` // Last reported metric value
+	Unit    string   `json:"unit,omitempty"`    // e.g. "ms" or "%"
+}
+
+// IsMetric reports whether progress comes from reported metric values
+func (k *KeyResult) IsMetric() bool {
+	return k.Target != nil
+}
+
+// Equal reports whether two key results hold the same values
+func (k *KeyResult) Equal(o *KeyResult) bool {
+	floatEqual := func(a, b *float64) bool { return (a == nil) == (b == nil) && (a == nil || *a == *b) }
+	return k.Title == o.Title && k.Unit == o.Unit && slices.Equal(k.Issues, o.Issues) &&
+		floatEqual(k.Start, o.Start) && floatEqual(k.Target, o.Target) && floatEqual(k.Current, o.Current)
+}
+
+// KeyResultsEqual reports whether two lists hold the same key results in
+// the same order
+func KeyResultsEqual(a, b []*KeyResult) bool {
+	return slices.EqualFunc(a, b, func(x, y *KeyResult) bool { return x.Equal(y) })
+}
+
+// Vote is one voter's +1 or -1 on an issue (bd vote)
+type Vote struct {
+	Voter string `json:"voter"`
+	Value int    `json:"value"` // +1 or -1
+}
+
+// VoteTally counts the up and down votes of an issue
+func VoteTally(votes []*Vote) (up, down int) {
+	for _, v := range votes {
+		switch {
+		case v.Value > 0:
+			up++
+		case v.Value < 0:
+			down++
+		}
+	}
+	return up, down
+}
+
+// VoteScore is the up votes minus the down votes of an issue
+func VoteScore(votes []*Vote) int {
+	up, down := VoteTally(votes)
+	return up - down
+}
+
+// APIToken is a credential for the daemon's HTTP API (bd token). Only a
+// hash of the secret is stored; like ref checks, tokens are local to a
+// clone and are not exported.
+type APIToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`   // What the token is for, e.g. "ci-agent"
+	Scopes     []string   `json:"scopes"` // e.g. read, write:issues
+	CreatedBy  string     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the token may be used at now
+func (t *APIToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// APIAccess is one request to the daemon's HTTP API, as kept in its
+// access log (bd server audit)
+type APIAccess struct {
+	ID        int64     `json:"id"`
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Actor     string    `json:"actor"`              // Token name, signed-in user, "token" or "anonymous"
+	TokenID   string    `json:"token_id,omitempty"` // API token the request carried
+	Remote    string    `json:"remote,omitempty"`   // Client address
+	Status    int       `json:"status"`
+	LatencyMS int64     `json:"latency_ms"`
+}
+
+// APIAccessFilter selects access log entries. Zero fields match everything.
+type APIAccessFilter struct {
+	Since     time.Time
+	Actor     string
+	TokenID   string
+	MinStatus int // e.g. 400 for failed requests
+	Limit     int // Newest first
+}
+
 // Comment represents a comment on an issue
 type Comment struct {
 	ID        int64     `json:"id"`
@@ -907,6 +1213,13 @@ type Statistics struct {
 	AverageLeadTime          float64 `json:"average_lead_time_hours"`
 }
 
+// PromptSummary is the compact work summary shown by 'bd prompt'.
+type PromptSummary struct {
+	Ready      int      `json:"ready"`
+	Blocked    int      `json:"blocked"`
+	InProgress []string `json:"in_progress,omitempty"` // IDs of in-progress issues, oldest update first
+}
+
 // IssueFilter is used to filter issue queries
 type IssueFilter struct {
 	Status      *Status
@@ -915,15 +1228,19 @@ type IssueFilter struct {
 	Assignee    *string
 	Labels      []string  // AND semantics: issue must have ALL these labels
 	LabelsAny   []string  // OR semantics: issue must have AT LEAST ONE of these labels
+	LabelGlobs  []string  // AND semantics: each glob (path.Match syntax) must match at least one label
+	NotLabels   []string  // issue must have NONE of these labels
 	TitleSearch string
 	IDs         []string  // Filter by specific issue IDs
 	IDPrefix    string    // Filter by ID prefix (e.g., "bd-" to match "bd-abc123")
 	Limit       int
+	After       *Cursor   // Resume after this issue in search order (pagination; see DecodeCursor)
 
 	// Pattern matching
 	TitleContains       string
 	DescriptionContains string
 	NotesContains       string
+	TitleRegex          string // Go regexp syntax; SQL backends evaluate it with REGEXP
 	
 	// Date ranges
 	CreatedAfter  *time.Time
@@ -973,6 +1290,17 @@ type IssueFilter struct {
 	DueAfter    *time.Time // Filter issues with due_at > this time
 	DueBefore   *time.Time // Filter issues with due_at < this time
 	Overdue     bool       // Filter issues where due_at < now AND status != closed
+
+	// Query expression filtering (bd query): ANDed with the filters above
+	Query QueryExpr
+}
+
+// QueryExpr is a compiled filter expression (see internal/query). SQL
+// backends push SQL down into their WHERE clause; others call Match on
+// issues with Labels and Dependencies populated.
+type QueryExpr interface {
+	SQL() (string, []interface{})
+	Match(issue *Issue) bool
 }
 
 // SortPolicy determines how ready work is ordered
@@ -1013,6 +1341,9 @@ type WorkFilter struct {
 	Labels     []string   // AND semantics: issue must have ALL these labels
 	LabelsAny  []string   // OR semantics: issue must have AT LEAST ONE of these labels
 	Limit      int
+	After      *Cursor    // Resume after this issue in the sort order (pagination; see ResumeAfter)
+	// RecentSince pins the hybrid sort's recent/old boundary (zero: 48 hours ago)
+	RecentSince time.Time
 	SortPolicy SortPolicy
 
 	// Parent filtering: filter to descendants of a bead/epic (recursive)
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// accessible is set by SetAccessible (bd --accessible).
+var accessible bool
+
+// SetAccessible switches to screen-reader-friendly output: no color, no
+// decorative symbols, and status, priority and warnings spelled out as
+// words. Commands check IsAccessible to print one line per item instead of
+// tables and trees.
+func SetAccessible(on bool) {
+	accessible = on
+	if on {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// IsAccessible reports whether accessible output is on.
+func IsAccessible() bool {
+	return accessible
+}
+
+// accessibleSymbol replaces a decorative symbol with the word it signals,
+// or nothing when it is only decoration. Text is returned unchanged.
+func accessibleSymbol(s string) string {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return s
+		}
+	}
+	switch strings.TrimSpace(s) {
+	case IconWarn, "⚠️":
+		return "Warning:"
+	case IconFail, "✗", "❌":
+		return "Error:"
+	}
+	return ""
+}
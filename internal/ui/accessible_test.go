@@ -0,0 +1,33 @@
+package ui
+
+import "testing"
+
+func TestAccessibleRendering(t *testing.T) {
+	SetAccessible(true)
+	defer SetAccessible(false)
+
+	for in, want := range map[string]string{
+		"✓":        "",
+		"📋":        "",
+		"⚠":        "Warning:",
+		"✖":        "Error:",
+		"3":        "3",
+		"overload": "overload",
+	} {
+		if got := RenderPass(in); got != want {
+			t.Errorf("RenderPass(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := RenderPriority(1); got != "P1" {
+		t.Errorf("RenderPriority(1) = %q, want P1", got)
+	}
+	if got := RenderStatusIcon("in_progress"); got != "in_progress" {
+		t.Errorf("RenderStatusIcon = %q, want the status word", got)
+	}
+	if RenderSeparator() != "" {
+		t.Error("RenderSeparator should draw nothing")
+	}
+	if ShouldUseColor() || ShouldUseEmoji() {
+		t.Error("accessible mode must not use color or emoji")
+	}
+}
@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressRedraw limits how often a progress line is redrawn.
+const progressRedraw = 100 * time.Millisecond
+
+// Progress draws a one-line progress bar for long operations, normally on
+// stderr so it never mixes with command output. A disabled Progress (or a
+// nil one) ignores every call, so callers can report unconditionally.
+type Progress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	label   string
+	total   int
+	done    int
+	started time.Time
+	drawn   time.Time
+	enabled bool
+	now     func() time.Time
+}
+
+// NewProgress returns a progress line for label out of total steps. When
+// enabled is false, nothing is ever written.
+func NewProgress(w io.Writer, label string, total int, enabled bool) *Progress {
+	return &Progress{w: w, label: label, total: total, enabled: enabled, now: time.Now, started: time.Now()}
+}
+
+// SetTotal changes the number of steps, for operations that only learn it
+// part way through.
+func (p *Progress) SetTotal(total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.enabled {
+		return
+	}
+	p.total = total
+	p.draw()
+}
+
+// Set records that done steps have completed.
+func (p *Progress) Set(done int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.enabled {
+		return
+	}
+	p.done = done
+	p.draw()
+}
+
+// Add records n more completed steps.
+func (p *Progress) Add(n int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.enabled {
+		return
+	}
+	p.done += n
+	p.draw()
+}
+
+// Finish erases the progress line so the command's summary starts on a
+// clean line.
+func (p *Progress) Finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.enabled {
+		return
+	}
+	if !p.drawn.IsZero() {
+		_, _ = fmt.Fprint(p.w, "\r\033[K")
+	}
+	p.enabled = false
+}
+
+func (p *Progress) draw() {
+	now := p.now()
+	if p.done < p.total && now.Sub(p.drawn) < progressRedraw {
+		return
+	}
+	p.drawn = now
+	_, _ = fmt.Fprintf(p.w, "\r\033[K%s", p.line(now))
+}
+
+// line renders "label [████    ] 12/40 30% ~3s left".
+func (p *Progress) line(now time.Time) string {
+	if p.total <= 0 {
+		return fmt.Sprintf("%s %d", p.label, p.done)
+	}
+	done := min(p.done, p.total)
+	s := fmt.Sprintf("%s %s %d/%d %d%%", p.label, ProgressBar(done, p.total, 24), done, p.total, done*100/p.total)
+	if done > 0 && done < p.total {
+		elapsed := now.Sub(p.started)
+		left := time.Duration(float64(elapsed) / float64(done) * float64(p.total-done))
+		if left >= time.Second {
+			s += fmt.Sprintf(" ~%s left", left.Round(time.Second))
+		}
+	}
+	return s
+}
+
+// ProgressBar renders current/total as a bar of width cells, e.g.
+// "[██████      ]".
+func ProgressBar(current, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = min(max(current, 0), total) * width / total
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat(" ", width-filled) + "]"
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		current, total int
+		want           string
+	}{
+		{0, 100, "[          ]"},
+		{50, 100, "[█████     ]"},
+		{100, 100, "[██████████]"},
+		{150, 100, "[██████████]"},
+		{3, 0, "[          ]"},
+	}
+	for _, tt := range tests {
+		if got := ProgressBar(tt.current, tt.total, 10); got != tt.want {
+			t.Errorf("ProgressBar(%d, %d) = %q, want %q", tt.current, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewProgress(&buf, "Importing", 4, true)
+	clock := p.started
+	p.now = func() time.Time { return clock }
+
+	p.Set(1)
+	clock = clock.Add(10 * time.Second)
+	p.Set(2)
+	if !strings.HasSuffix(buf.String(), "Importing [████████████            ] 2/4 50% ~10s left") {
+		t.Errorf("output = %q", buf.String())
+	}
+
+	// Redraws are throttled, except for the final step
+	buf.Reset()
+	p.Add(1)
+	if buf.Len() != 0 {
+		t.Errorf("redraw within %v: %q", progressRedraw, buf.String())
+	}
+	p.Add(1)
+	if !strings.Contains(buf.String(), "4/4 100%") {
+		t.Errorf("final step not drawn: %q", buf.String())
+	}
+
+	buf.Reset()
+	p.Finish()
+	p.Add(1)
+	if buf.String() != "\r\033[K" {
+		t.Errorf("Finish wrote %q", buf.String())
+	}
+}
+
+func TestProgressDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewProgress(&buf, "Exporting", 10, false)
+	p.Set(5)
+	p.Finish()
+	var nilProgress *Progress
+	nilProgress.Add(1)
+	nilProgress.Finish()
+	if buf.Len() != 0 {
+		t.Errorf("disabled progress wrote %q", buf.String())
+	}
+}
@@ -223,6 +223,9 @@ const PriorityIcon = "●"
 // RenderStatusIcon returns the appropriate icon for a status with semantic coloring
 // This is the canonical source for status icon rendering - use this everywhere
 func RenderStatusIcon(status string) string {
+	if accessible {
+		return status
+	}
 	switch status {
 	case "open":
 		return StatusIconOpen // no color - available but not urgent
@@ -244,6 +247,9 @@ func RenderStatusIcon(status string) string {
 // GetStatusIcon returns just the icon character without styling
 // Useful when you need to apply custom styling or for non-TTY output
 func GetStatusIcon(status string) string {
+	if accessible {
+		return status
+	}
 	switch status {
 	case "open":
 		return StatusIconOpen
@@ -299,26 +305,41 @@ const (
 
 // RenderPass renders text with pass (green) styling
 func RenderPass(s string) string {
+	if accessible {
+		return accessibleSymbol(s)
+	}
 	return PassStyle.Render(s)
 }
 
 // RenderWarn renders text with warning (yellow) styling
 func RenderWarn(s string) string {
+	if accessible {
+		return accessibleSymbol(s)
+	}
 	return WarnStyle.Render(s)
 }
 
 // RenderFail renders text with fail (red) styling
 func RenderFail(s string) string {
+	if accessible {
+		return accessibleSymbol(s)
+	}
 	return FailStyle.Render(s)
 }
 
 // RenderMuted renders text with muted (gray) styling
 func RenderMuted(s string) string {
+	if accessible {
+		return accessibleSymbol(s)
+	}
 	return MutedStyle.Render(s)
 }
 
 // RenderAccent renders text with accent (blue) styling
 func RenderAccent(s string) string {
+	if accessible {
+		return accessibleSymbol(s)
+	}
 	return AccentStyle.Render(s)
 }
 
@@ -329,32 +350,35 @@ func RenderCategory(s string) string {
 
 // RenderSeparator renders the light separator line in muted color
 func RenderSeparator() string {
+	if accessible {
+		return ""
+	}
 	return MutedStyle.Render(SeparatorLight)
 }
 
 // RenderPassIcon renders the pass icon with styling
 func RenderPassIcon() string {
-	return PassStyle.Render(IconPass)
+	return RenderPass(IconPass)
 }
 
 // RenderWarnIcon renders the warning icon with styling
 func RenderWarnIcon() string {
-	return WarnStyle.Render(IconWarn)
+	return RenderWarn(IconWarn)
 }
 
 // RenderFailIcon renders the fail icon with styling
 func RenderFailIcon() string {
-	return FailStyle.Render(IconFail)
+	return RenderFail(IconFail)
 }
 
 // RenderSkipIcon renders the skip icon with styling
 func RenderSkipIcon() string {
-	return MutedStyle.Render(IconSkip)
+	return RenderMuted(IconSkip)
 }
 
 // RenderInfoIcon renders the info icon with styling
 func RenderInfoIcon() string {
-	return AccentStyle.Render(IconInfo)
+	return RenderAccent(IconInfo)
 }
 
 // === Issue Component Renderers ===
@@ -387,6 +411,9 @@ func RenderStatus(status string) string {
 // Format: ● P0 (icon + label)
 // P0/P1 get color; P2/P3/P4 use standard text
 func RenderPriority(priority int) string {
+	if accessible {
+		return fmt.Sprintf("P%d", priority)
+	}
 	label := fmt.Sprintf("%s P%d", PriorityIcon, priority)
 	switch priority {
 	case 0:
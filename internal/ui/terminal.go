@@ -19,6 +19,11 @@ func IsTerminal() bool {
 //   - CLICOLOR_FORCE: forces color even in non-TTY
 //   - Falls back to TTY detection
 func ShouldUseColor() bool {
+	// Accessible mode never relies on color
+	if accessible {
+		return false
+	}
+
 	// NO_COLOR standard - any value disables color
 	if os.Getenv("NO_COLOR") != "" {
 		return false
@@ -42,6 +47,10 @@ func ShouldUseColor() bool {
 // Disabled in non-TTY mode to keep output machine-readable.
 // Can be controlled with BD_NO_EMOJI environment variable.
 func ShouldUseEmoji() bool {
+	if accessible {
+		return false
+	}
+
 	// Explicit disable
 	if os.Getenv("BD_NO_EMOJI") != "" {
 		return false
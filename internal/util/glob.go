@@ -0,0 +1,63 @@
+package util
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrBadGlob is returned for malformed glob patterns.
+var ErrBadGlob = errors.New("syntax error in glob pattern")
+
+// GlobToRegexp translates a shell-style glob into an anchored regular
+// expression with the same semantics as SQLite's GLOB operator: * matches
+// any run of characters (including /), ? matches one character, and [...]
+// matches a character class ([^...] or [!...] negates it). Matching is
+// case-sensitive.
+func GlobToRegexp(pattern string) (string, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			j := i + 1
+			if j < len(pattern) && (pattern[j] == '^' || pattern[j] == '!') {
+				j++
+			}
+			if j < len(pattern) && pattern[j] == ']' {
+				j++ // a leading ] is a literal member
+			}
+			end := strings.IndexByte(pattern[j:], ']')
+			if end < 0 {
+				return "", ErrBadGlob
+			}
+			class := pattern[i+1 : j+end]
+			i = j + end
+			b.WriteByte('[')
+			if class[0] == '^' || class[0] == '!' {
+				b.WriteByte('^')
+				class = class[1:]
+			}
+			b.WriteString(strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`).Replace(class))
+			b.WriteByte(']')
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return b.String(), nil
+}
+
+// CompileGlob compiles a glob pattern (see GlobToRegexp) into a matcher.
+func CompileGlob(pattern string) (*regexp.Regexp, error) {
+	expr, err := GlobToRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(expr)
+}
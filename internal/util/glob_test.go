@@ -0,0 +1,42 @@
+package util
+
+import "testing"
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		input   string
+		want    bool
+	}{
+		{"area/*", "area/ui", true},
+		{"area/*", "area/ui/forms", true},
+		{"area/*", "areas", false},
+		{"*", "", true},
+		{"p?", "p1", true},
+		{"p?", "p12", false},
+		{"v[0-9]", "v3", true},
+		{"v[!0-9]", "v3", false},
+		{"v[^0-9]", "vx", true},
+		{"[]]x", "]x", true},
+		{"a.b", "axb", false},
+		{"a+b", "a+b", true},
+		{"Bug", "bug", false},
+		{"ü*", "über", true},
+	}
+	for _, tt := range tests {
+		re, err := CompileGlob(tt.pattern)
+		if err != nil {
+			t.Errorf("CompileGlob(%q): %v", tt.pattern, err)
+			continue
+		}
+		if got := re.MatchString(tt.input); got != tt.want {
+			t.Errorf("glob %q on %q = %v, want %v", tt.pattern, tt.input, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"a[b", "[]", "[!"} {
+		if _, err := CompileGlob(bad); err == nil {
+			t.Errorf("CompileGlob(%q) succeeded, want error", bad)
+		}
+	}
+}
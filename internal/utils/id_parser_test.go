@@ -1,4 +1,4 @@
-package utils
+package utils_test
 
 import (
 	"context"
@@ -6,6 +6,7 @@ import (
 
 	"github.com/steveyegge/beads/internal/storage/memory"
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/utils"
 )
 
 func TestParseIssueID(t *testing.T) {
@@ -55,7 +56,7 @@ func TestParseIssueID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ParseIssueID(tt.input, tt.prefix)
+			result := utils.ParseIssueID(tt.input, tt.prefix)
 			if result != tt.expected {
 				t.Errorf("ParseIssueID(%q, %q) = %q; want %q", tt.input, tt.prefix, result, tt.expected)
 			}
@@ -184,7 +185,7 @@ func TestResolvePartialID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := ResolvePartialID(ctx, store, tt.input)
+			result, err := utils.ResolvePartialID(ctx, store, tt.input)
 			
 			if tt.shouldError {
 				if err == nil {
@@ -265,7 +266,7 @@ func TestResolvePartialIDs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := ResolvePartialIDs(ctx, store, tt.inputs)
+			result, err := utils.ResolvePartialIDs(ctx, store, tt.inputs)
 			
 			if tt.shouldError {
 				if err == nil {
@@ -306,7 +307,7 @@ func TestResolvePartialID_NoConfig(t *testing.T) {
 	}
 	
 	// Don't set config - should use default "bd" prefix
-	result, err := ResolvePartialID(ctx, store, "1")
+	result, err := utils.ResolvePartialID(ctx, store, "1")
 	if err != nil {
 		t.Fatalf("ResolvePartialID failed with default config: %v", err)
 	}
@@ -320,7 +321,7 @@ func TestResolvePartialID_NilStorage(t *testing.T) {
 	ctx := context.Background()
 
 	// Test that nil storage returns an error instead of panicking
-	_, err := ResolvePartialID(ctx, nil, "bd-123")
+	_, err := utils.ResolvePartialID(ctx, nil, "bd-123")
 	if err == nil {
 		t.Fatal("ResolvePartialID with nil storage should return error, got nil")
 	}
@@ -436,7 +437,7 @@ func TestExtractIssuePrefix(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ExtractIssuePrefix(tt.issueID)
+			result := utils.ExtractIssuePrefix(tt.issueID)
 			if result != tt.expected {
 				t.Errorf("ExtractIssuePrefix(%q) = %q; want %q", tt.issueID, result, tt.expected)
 			}
@@ -499,7 +500,7 @@ func TestExtractIssueNumber(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ExtractIssueNumber(tt.issueID)
+			result := utils.ExtractIssueNumber(tt.issueID)
 			if result != tt.expected {
 				t.Errorf("ExtractIssueNumber(%q) = %d; want %d", tt.issueID, result, tt.expected)
 			}
@@ -0,0 +1,379 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/lock"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Configured reads the rules under validation.* in config.yaml. Unknown
+// field names under validation.required-fields are ignored and returned as
+// "<type>.<field>" so commands can report them.
+func Configured() (Rules, []string) {
+	rules := Rules{
+		TitleMinLength: config.GetInt("validation.title-min-length"),
+		TitleMaxLength: config.GetInt("validation.title-max-length"),
+		ForbiddenWords: splitList(config.GetStringSlice("validation.forbidden-words")),
+		MaxOpenP0:      config.GetInt("validation.max-open-p0"),
+
+		DescriptionMaxBytes: config.GetSize("validation.description-max-size"),
+	}
+	var unknown []string
+	byType := config.GetListMapByPrefix("validation.required-fields")
+	typeNames := make([]string, 0, len(byType))
+	for t := range byType {
+		typeNames = append(typeNames, t)
+	}
+	sort.Strings(typeNames)
+	for _, t := range typeNames {
+		for _, field := range splitList(byType[t]) {
+			field = strings.ToLower(field)
+			if !IsRequirableField(field) {
+				unknown = append(unknown, t+"."+field)
+				continue
+			}
+			if rules.RequiredFields == nil {
+				rules.RequiredFields = make(map[types.IssueType][]string)
+			}
+			rules.RequiredFields[types.IssueType(t)] = append(rules.RequiredFields[types.IssueType(t)], field)
+		}
+	}
+	return rules, unknown
+}
+
+// splitList flattens a list value, splitting entries that arrive
+// comma-separated from an env var or bd config set.
+func splitList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
+
+type bypassKey struct{}
+
+type forceKey struct{}
+
+// WithBypass returns a context whose writes skip the rules, for changes
+// already checked where they were made (sync, auto-import).
+func WithBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// WithForce returns a context whose writes may break the rules on behalf
+// of actor (--force). Anyone may force until lock.admins is set; then only
+// the admins may.
+func WithForce(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, forceKey{}, actor)
+}
+
+func bypassed(ctx context.Context) bool {
+	b, _ := ctx.Value(bypassKey{}).(bool)
+	return b
+}
+
+// Reader looks up stored issues. Every storage backend and transaction is
+// one.
+type Reader interface {
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+}
+
+// CheckCreate returns an error if issues, created together, break the
+// configured rules. Labels set on the issues count, so callers that add
+// labels after creating must set them first. Wisps are exempt: they stay
+// local and are never exported.
+func CheckCreate(ctx context.Context, r Reader, issues ...*types.Issue) error {
+	rules, _ := Configured()
+	if bypassed(ctx) || !rules.Enabled() {
+		return nil
+	}
+	return resolve(ctx, checkCreate(ctx, rules, r, issues))
+}
+
+func checkCreate(ctx context.Context, rules Rules, r Reader, issues []*types.Issue) []error {
+	var errs []error
+	p0 := openP0Counter{r: r}
+	for _, issue := range issues {
+		if issue.Ephemeral {
+			continue
+		}
+		others, err := p0.others(ctx, rules, nil, issue)
+		if err != nil {
+			return []error{err}
+		}
+		if violations := rules.Check(issue, others); len(violations) > 0 {
+			errs = append(errs, &RuleError{ID: issue.ID, Violations: violations})
+		} else {
+			p0.accepted(rules, nil, issue)
+		}
+	}
+	return errs
+}
+
+// CheckUpdate returns an error if applying updates (as passed to
+// UpdateIssue) to before breaks a rule that before did not.
+func CheckUpdate(ctx context.Context, r Reader, before *types.Issue, updates map[string]interface{}) error {
+	rules, _ := Configured()
+	if bypassed(ctx) || !rules.Enabled() || before.Ephemeral {
+		return nil
+	}
+	return resolve(ctx, checkChange(ctx, rules, r, before, ApplyUpdates(before, updates)))
+}
+
+// CheckRemoveLabel returns an error if removing label from the issue id
+// breaks a rule (labels required for its type).
+func CheckRemoveLabel(ctx context.Context, r Reader, id, label string) error {
+	rules, _ := Configured()
+	if bypassed(ctx) || !rules.Enabled() {
+		return nil
+	}
+	before, err := r.GetIssue(ctx, id)
+	if err != nil || before == nil || before.Ephemeral {
+		return err
+	}
+	after := *before
+	after.Labels = nil
+	for _, l := range before.Labels {
+		if l != label {
+			after.Labels = append(after.Labels, l)
+		}
+	}
+	return resolve(ctx, checkChange(ctx, rules, r, before, &after))
+}
+
+func checkChange(ctx context.Context, rules Rules, r Reader, before, after *types.Issue) []error {
+	others, err := (&openP0Counter{r: r}).others(ctx, rules, before, after)
+	if err != nil {
+		return []error{err}
+	}
+	if violations := rules.CheckChange(before, after, others); len(violations) > 0 {
+		return []error{&RuleError{ID: after.ID, Violations: violations}}
+	}
+	return nil
+}
+
+// CheckImport returns an error listing every issue being imported that
+// breaks the configured rules: new issues in full, existing ones for what
+// the import changes. Each open P0 it accepts counts toward the limit for
+// the issues after it.
+func CheckImport(ctx context.Context, r Reader, issues []*types.Issue) error {
+	rules, _ := Configured()
+	if bypassed(ctx) || !rules.Enabled() {
+		return nil
+	}
+	errs, err := checkImport(ctx, rules, r, issues)
+	if err != nil {
+		return err
+	}
+	return resolve(ctx, errs)
+}
+
+func checkImport(ctx context.Context, rules Rules, r Reader, issues []*types.Issue) ([]error, error) {
+	var errs []error
+	p0 := openP0Counter{r: r}
+	for _, issue := range issues {
+		if issue.Ephemeral {
+			continue
+		}
+		before, err := r.GetIssue(ctx, issue.ID)
+		if err != nil {
+			return nil, err
+		}
+		others, err := p0.others(ctx, rules, before, issue)
+		if err != nil {
+			return nil, err
+		}
+		var violations []string
+		if before == nil {
+			violations = rules.Check(issue, others)
+		} else {
+			violations = rules.CheckChange(before, issue, others)
+		}
+		if len(violations) > 0 {
+			errs = append(errs, &RuleError{ID: issue.ID, Violations: violations})
+		} else {
+			p0.accepted(rules, before, issue)
+		}
+	}
+	return errs, nil
+}
+
+// openP0Counter tracks the open P0 issues for a run of checks: those
+// stored, counted once, plus those the run has accepted so far.
+type openP0Counter struct {
+	r       Reader
+	counted bool
+	n       int
+}
+
+// others returns how many issues besides after are open at P0. before is
+// the stored issue being changed, or nil.
+func (c *openP0Counter) others(ctx context.Context, rules Rules, before, after *types.Issue) (int, error) {
+	if !rules.CountsOpenP0(after) {
+		return 0, nil
+	}
+	if !c.counted {
+		p0 := 0
+		issues, err := c.r.SearchIssues(ctx, "", types.IssueFilter{
+			Priority:      &p0,
+			ExcludeStatus: []types.Status{types.StatusClosed, types.StatusTombstone},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("counting open P0 issues: %w", err)
+		}
+		c.counted = true
+		for _, issue := range issues {
+			if isOpenP0(issue) { // Not every backend applies the whole filter
+				c.n++
+			}
+		}
+	}
+	if before != nil && isOpenP0(before) {
+		return c.n - 1, nil
+	}
+	return c.n, nil
+}
+
+// accepted counts after toward later checks once it passed.
+func (c *openP0Counter) accepted(rules Rules, before, after *types.Issue) {
+	if c.counted && rules.CountsOpenP0(after) && (before == nil || !isOpenP0(before)) {
+		c.n++
+	}
+}
+
+// resolve combines the rule errors of a write, letting them through if
+// ctx forces them and its actor may.
+func resolve(ctx context.Context, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	err := errors.Join(errs...)
+	var ruleErr *RuleError
+	if !errors.As(err, &ruleErr) {
+		return err // Failed to check, not a broken rule
+	}
+	actor, forced := ctx.Value(forceKey{}).(string)
+	if !forced {
+		return err
+	}
+	if admins := lock.Admins(); len(admins) > 0 && !lock.IsAdmin(actor) {
+		return fmt.Errorf("%w\n--force past validation rules is restricted to lock.admins and %q is not listed", err, actor)
+	}
+	return nil
+}
+
+// ApplyUpdates returns a copy of issue with the field changes of an
+// UpdateIssue updates map applied, for checking them before writing. The
+// label changes of bd update (set_labels, add_labels, remove_labels) apply
+// too, so a command making several writes can check them as one change.
+func ApplyUpdates(issue *types.Issue, updates map[string]interface{}) *types.Issue {
+	after := *issue
+	after.Labels = append([]string(nil), issue.Labels...)
+	for key, value := range updates {
+		switch key {
+		case "title":
+			after.Title = stringValue(value)
+		case "description":
+			after.Description = stringValue(value)
+		case "design":
+			after.Design = stringValue(value)
+		case "acceptance_criteria":
+			after.AcceptanceCriteria = stringValue(value)
+		case "notes":
+			after.Notes = stringValue(value)
+		case "assignee":
+			after.Assignee = stringValue(value)
+		case "status":
+			after.Status = types.Status(stringValue(value))
+		case "priority":
+			if n, ok := intValue(value); ok {
+				after.Priority = n
+			}
+		case "issue_type":
+			after.IssueType = types.IssueType(stringValue(value))
+		case "external_ref":
+			if s := stringValue(value); s != "" {
+				after.ExternalRef = &s
+			} else {
+				after.ExternalRef = nil
+			}
+		case "estimated_minutes":
+			if n, ok := intValue(value); ok {
+				after.EstimatedMinutes = &n
+			} else {
+				after.EstimatedMinutes = nil
+			}
+		case "due_at":
+			switch t := value.(type) {
+			case time.Time:
+				after.DueAt = &t
+			case *time.Time:
+				after.DueAt = t
+			default:
+				after.DueAt = nil
+			}
+		case "set_labels":
+			set, _ := value.([]string)
+			after.Labels = append([]string(nil), set...)
+		}
+	}
+	if add, ok := updates["add_labels"].([]string); ok {
+		after.Labels = append(after.Labels, add...)
+	}
+	if remove, ok := updates["remove_labels"].([]string); ok {
+		kept := after.Labels[:0]
+		for _, l := range after.Labels {
+			if !slices.Contains(remove, l) {
+				kept = append(kept, l)
+			}
+		}
+		after.Labels = kept
+	}
+	return &after
+}
+
+func stringValue(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case *string:
+		if s != nil {
+			return *s
+		}
+	case fmt.Stringer:
+		return s.String()
+	case types.Status:
+		return string(s)
+	case types.IssueType:
+		return string(s)
+	}
+	return ""
+}
+
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case *int:
+		if n != nil {
+			return *n, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// fakeReader serves issues from a map.
+type fakeReader map[string]*types.Issue
+
+func (f fakeReader) GetIssue(_ context.Context, id string) (*types.Issue, error) {
+	return f[id], nil
+}
+
+func (f fakeReader) SearchIssues(_ context.Context, _ string, filter types.IssueFilter) ([]*types.Issue, error) {
+	var out []*types.Issue
+	for _, issue := range f {
+		if filter.Priority != nil && issue.Priority == *filter.Priority && isOpenP0(issue) {
+			out = append(out, issue)
+		}
+	}
+	return out, nil
+}
+
+// setConfig initializes config and applies settings, restoring the
+// defaults when the test ends.
+func setConfig(t *testing.T, settings map[string]interface{}) {
+	t.Helper()
+	if err := config.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range settings {
+		config.Set(k, v)
+	}
+	t.Cleanup(func() {
+		config.Set("validation.title-min-length", 0)
+		config.Set("lock.admins", []string{})
+	})
+}
+
+func TestApplyUpdates(t *testing.T) {
+	before := &types.Issue{ID: "bd-1", Title: "Old title", Status: types.StatusOpen, Priority: 2, Labels: []string{"a", "b"}}
+	due := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	set := []string{"x", "y"}
+	after := ApplyUpdates(before, map[string]interface{}{
+		"title":         "New title",
+		"priority":      0,
+		"status":        types.StatusInProgress,
+		"due_at":        due,
+		"set_labels":    set,
+		"add_labels":    []string{"z"},
+		"remove_labels": []string{"x"},
+	})
+
+	if after.Title != "New title" || after.Priority != 0 || after.DueAt == nil || !after.DueAt.Equal(due) {
+		t.Errorf("fields not applied: %+v", after)
+	}
+	if after.Status != types.StatusInProgress {
+		t.Errorf("status = %q, want in_progress", after.Status)
+	}
+	if len(after.Labels) != 2 || after.Labels[0] != "y" || after.Labels[1] != "z" {
+		t.Errorf("labels = %v, want [y z]", after.Labels)
+	}
+	if before.Title != "Old title" || len(before.Labels) != 2 || set[0] != "x" {
+		t.Error("ApplyUpdates changed its inputs")
+	}
+}
+
+func TestCheckImport(t *testing.T) {
+	ctx := context.Background()
+	existing := &types.Issue{ID: "test-old", Title: "Old", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	r := fakeReader{existing.ID: existing}
+
+	rules := Rules{TitleMinLength: 5, MaxOpenP0: 1}
+	unchanged := *existing
+	errs, err := checkImport(ctx, rules, r, []*types.Issue{
+		&unchanged, // Already broke the title rule; not changed by the import
+		{ID: "test-a", Title: "First outage", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug},
+		{ID: "test-b", Title: "Second outage", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug},
+		{ID: "test-c", Title: "Bad", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "test-wisp-d", Title: "Bad", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Ephemeral: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, e := range errs {
+		ids = append(ids, e.(*RuleError).ID)
+	}
+	if len(ids) != 2 || ids[0] != "test-b" || ids[1] != "test-c" {
+		t.Errorf("issues breaking rules = %v, want [test-b test-c]", ids)
+	}
+}
+
+func TestCheckCreateForce(t *testing.T) {
+	r := fakeReader{}
+	short := &types.Issue{ID: "bd-1", Title: "Bad", Status: types.StatusOpen, IssueType: types.TypeTask}
+	setConfig(t, map[string]interface{}{"validation.title-min-length": 5})
+
+	ctx := context.Background()
+	var ruleErr *RuleError
+	if err := CheckCreate(ctx, r, short); !errors.As(err, &ruleErr) {
+		t.Fatalf("CheckCreate = %v, want a rule error", err)
+	}
+	if err := CheckCreate(WithBypass(ctx), r, short); err != nil {
+		t.Errorf("bypassed CheckCreate = %v, want nil", err)
+	}
+	if err := CheckCreate(WithForce(ctx, "carol"), r, short); err != nil {
+		t.Errorf("forced CheckCreate without admins = %v, want nil", err)
+	}
+
+	config.Set("lock.admins", "alice")
+	if err := CheckCreate(WithForce(ctx, "alice"), r, short); err != nil {
+		t.Errorf("CheckCreate forced by an admin = %v, want nil", err)
+	}
+	err := CheckCreate(WithForce(ctx, "carol"), r, short)
+	if !errors.As(err, &ruleErr) || !strings.Contains(err.Error(), "restricted to lock.admins") {
+		t.Errorf("CheckCreate forced by a non-admin = %v, want it refused", err)
+	}
+}
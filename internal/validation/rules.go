@@ -0,0 +1,160 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dustin/go-humanize"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Rules are project-wide checks on issue content, configured under
+// validation.* in config.yaml. The zero value checks nothing.
+type Rules struct {
+	TitleMinLength int                          // In characters; 0 = no minimum
+	TitleMaxLength int                          // In characters; 0 = no maximum
+	RequiredFields map[types.IssueType][]string // Fields that must be set, by issue type (see RequirableFields)
+	ForbiddenWords []string                     // Case-insensitive whole words or phrases, in title or description
+	MaxOpenP0      int                          // Most issues open at priority 0; 0 = unlimited
+
+	DescriptionMaxBytes int64 // 0 = no maximum
+}
+
+// RequirableFields are the field names validation.required-fields accepts.
+var RequirableFields = []string{
+	"description", "design", "acceptance_criteria", "notes",
+	"assignee", "estimate", "labels", "external_ref", "due",
+}
+
+// IsRequirableField reports whether name is one of RequirableFields.
+func IsRequirableField(name string) bool {
+	for _, f := range RequirableFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled reports whether any rule is set.
+func (r Rules) Enabled() bool {
+	return r.TitleMinLength > 0 || r.TitleMaxLength > 0 || len(r.RequiredFields) > 0 ||
+		len(r.ForbiddenWords) > 0 || r.MaxOpenP0 > 0 || r.DescriptionMaxBytes > 0
+}
+
+// CountsOpenP0 reports whether checking issue needs the number of other
+// open P0 issues.
+func (r Rules) CountsOpenP0(issue *types.Issue) bool {
+	return r.MaxOpenP0 > 0 && isOpenP0(issue)
+}
+
+func isOpenP0(issue *types.Issue) bool {
+	return issue.Priority == 0 && issue.Status != types.StatusClosed && issue.Status != types.StatusTombstone
+}
+
+// Check returns a description of each rule issue breaks, or nil. otherOpenP0
+// is how many issues besides this one are open at priority 0; it is only
+// used when CountsOpenP0 is true.
+func (r Rules) Check(issue *types.Issue, otherOpenP0 int) []string {
+	var violations []string
+
+	n := utf8.RuneCountInString(issue.Title)
+	if r.TitleMinLength > 0 && n < r.TitleMinLength {
+		violations = append(violations, fmt.Sprintf("title is %d characters (minimum %d)", n, r.TitleMinLength))
+	}
+	if r.TitleMaxLength > 0 && n > r.TitleMaxLength {
+		violations = append(violations, fmt.Sprintf("title is %d characters (maximum %d)", n, r.TitleMaxLength))
+	}
+
+	if size := int64(len(issue.Description)); r.DescriptionMaxBytes > 0 && size > r.DescriptionMaxBytes {
+		violations = append(violations, fmt.Sprintf("description is %s (maximum %s)",
+			humanize.Bytes(uint64(size)), humanize.Bytes(uint64(r.DescriptionMaxBytes))))
+	}
+
+	for _, field := range r.RequiredFields[issue.IssueType] {
+		if !fieldSet(issue, field) {
+			violations = append(violations, fmt.Sprintf("%s is required for %s issues", field, issue.IssueType))
+		}
+	}
+
+	for _, word := range r.ForbiddenWords {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		re := regexp.MustCompile(`(?i)(^|\W)` + regexp.QuoteMeta(word) + `($|\W)`)
+		for _, f := range []struct{ name, text string }{{"title", issue.Title}, {"description", issue.Description}} {
+			if re.MatchString(f.text) {
+				violations = append(violations, fmt.Sprintf("%s contains forbidden word %q", f.name, word))
+			}
+		}
+	}
+
+	if r.CountsOpenP0(issue) && otherOpenP0 >= r.MaxOpenP0 {
+		violations = append(violations, fmt.Sprintf("%d P0 issues are already open (maximum %d)", otherOpenP0, r.MaxOpenP0))
+	}
+	return violations
+}
+
+// CheckChange returns the rules after breaks that before did not, so that
+// editing an issue which already broke a rule is refused only for what the
+// edit itself breaks.
+func (r Rules) CheckChange(before, after *types.Issue, otherOpenP0 int) []string {
+	existing := make(map[string]bool)
+	for _, v := range r.Check(before, otherOpenP0) {
+		existing[v] = true
+	}
+	var introduced []string
+	for _, v := range r.Check(after, otherOpenP0) {
+		if !existing[v] {
+			introduced = append(introduced, v)
+		}
+	}
+	return introduced
+}
+
+func fieldSet(issue *types.Issue, field string) bool {
+	switch field {
+	case "description":
+		return strings.TrimSpace(issue.Description) != ""
+	case "design":
+		return strings.TrimSpace(issue.Design) != ""
+	case "acceptance_criteria":
+		return strings.TrimSpace(issue.AcceptanceCriteria) != ""
+	case "notes":
+		return strings.TrimSpace(issue.Notes) != ""
+	case "assignee":
+		return issue.Assignee != ""
+	case "estimate":
+		return issue.EstimatedMinutes != nil
+	case "labels":
+		return len(issue.Labels) > 0
+	case "external_ref":
+		return issue.ExternalRef != nil && *issue.ExternalRef != ""
+	case "due":
+		return issue.DueAt != nil
+	}
+	return true
+}
+
+// RuleError is returned when an issue breaks validation rules. It lists
+// every broken rule at once.
+type RuleError struct {
+	ID         string // Empty for an issue not yet created
+	Violations []string
+}
+
+func (e *RuleError) Error() string {
+	var b strings.Builder
+	if e.ID != "" {
+		fmt.Fprintf(&b, "%s breaks validation rules:", e.ID)
+	} else {
+		b.WriteString("issue breaks validation rules:")
+	}
+	for _, v := range e.Violations {
+		fmt.Fprintf(&b, "\n  - %s", v)
+	}
+	return b.String()
+}
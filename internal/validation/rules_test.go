@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestRulesCheck(t *testing.T) {
+	rules := Rules{
+		TitleMinLength: 10,
+		TitleMaxLength: 40,
+		RequiredFields: map[types.IssueType][]string{types.TypeBug: {"description", "labels"}},
+		ForbiddenWords: []string{"asap", "quick fix"},
+		MaxOpenP0:      2,
+
+		DescriptionMaxBytes: 1000,
+	}
+
+	tests := []struct {
+		name        string
+		issue       types.Issue
+		otherOpenP0 int
+		want        []string
+	}{
+		{
+			name:  "passes",
+			issue: types.Issue{Title: "Crash on startup", IssueType: types.TypeBug, Description: "Steps", Labels: []string{"crash"}, Priority: 1},
+		},
+		{
+			name:  "title too short",
+			issue: types.Issue{Title: "Fix it", IssueType: types.TypeTask, Priority: 2},
+			want:  []string{"title is 6 characters (minimum 10)"},
+		},
+		{
+			name:  "title too long counts characters",
+			issue: types.Issue{Title: strings.Repeat("é", 41), IssueType: types.TypeTask, Priority: 2},
+			want:  []string{"title is 41 characters (maximum 40)"},
+		},
+		{
+			name:  "required fields by type",
+			issue: types.Issue{Title: "Crash on startup", IssueType: types.TypeBug, Description: "  ", Priority: 2},
+			want:  []string{"description is required for bug issues", "labels is required for bug issues"},
+		},
+		{
+			name:  "forbidden words are whole words",
+			issue: types.Issue{Title: "Need this ASAP please", IssueType: types.TypeTask, Description: "A quick fix. Also quick fixes.", Priority: 2},
+			want:  []string{`title contains forbidden word "asap"`, `description contains forbidden word "quick fix"`},
+		},
+		{
+			name:  "description too large",
+			issue: types.Issue{Title: "Paste of a log", IssueType: types.TypeTask, Description: strings.Repeat("x", 1500), Priority: 2},
+			want:  []string{"description is 1.5 kB (maximum 1.0 kB)"},
+		},
+		{
+			name:  "no forbidden word inside another word",
+			issue: types.Issue{Title: "Update gasapp config", IssueType: types.TypeTask, Priority: 2},
+		},
+		{
+			name:        "too many open P0s",
+			issue:       types.Issue{Title: "Outage in prod", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 0},
+			otherOpenP0: 2,
+			want:        []string{"2 P0 issues are already open (maximum 2)"},
+		},
+		{
+			name:        "closed P0 does not count",
+			issue:       types.Issue{Title: "Outage in prod", IssueType: types.TypeTask, Status: types.StatusClosed, Priority: 0},
+			otherOpenP0: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules.Check(&tt.issue, tt.otherOpenP0)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRulesCheckChange(t *testing.T) {
+	rules := Rules{TitleMinLength: 10, MaxOpenP0: 1}
+	before := &types.Issue{Title: "Short", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2}
+
+	// Closing an issue whose title already broke a rule is allowed
+	after := *before
+	after.Status = types.StatusClosed
+	if got := rules.CheckChange(before, &after, 1); len(got) != 0 {
+		t.Errorf("CheckChange(close) = %q, want none", got)
+	}
+
+	// Raising it to P0 past the limit is not
+	after = *before
+	after.Priority = 0
+	if got := rules.CheckChange(before, &after, 1); len(got) != 1 || !strings.Contains(got[0], "P0") {
+		t.Errorf("CheckChange(P0) = %q, want the open P0 limit", got)
+	}
+}
+
+func TestRuleErrorMessage(t *testing.T) {
+	err := &RuleError{ID: "bd-1", Violations: []string{"title is 3 characters (minimum 10)", "assignee is required for task issues"}}
+	want := "bd-1 breaks validation rules:\n  - title is 3 characters (minimum 10)\n  - assignee is required for task issues"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if (Rules{}).Enabled() {
+		t.Error("zero Rules should not be enabled")
+	}
+}
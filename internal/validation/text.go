@@ -0,0 +1,204 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Text lint rules, as reported in TextFinding.Rule.
+const (
+	RuleBrokenMarkdown   = "broken-markdown"
+	RuleDeadReference    = "dead-reference"
+	RuleEmptyDescription = "empty-description"
+)
+
+// TextFinding is one problem LintText found in an issue's text.
+type TextFinding struct {
+	ID      string `json:"id"`
+	Field   string `json:"field"`          // title, description, design, acceptance_criteria or notes
+	Line    int    `json:"line,omitempty"` // 1-based line in the field, when the problem has one
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Fix     string `json:"fix"`      // What to do about it
+	AutoFix bool   `json:"auto_fix"` // FixMarkdown repairs it
+}
+
+// NonTrivialTypes are the issue types LintText expects a description on.
+var NonTrivialTypes = []types.IssueType{types.TypeBug, types.TypeFeature, types.TypeTask, types.TypeEpic}
+
+// textFields returns an issue's free-text fields by name, title first.
+func textFields(issue *types.Issue) []struct{ name, text string } {
+	return []struct{ name, text string }{
+		{"title", issue.Title},
+		{"description", issue.Description},
+		{"design", issue.Design},
+		{"acceptance_criteria", issue.AcceptanceCriteria},
+		{"notes", issue.Notes},
+	}
+}
+
+// LintText checks an issue's title and text fields for broken markdown,
+// references to issues that don't exist, and a missing description on a
+// non-trivial type. References are IDs with prefix that contain a digit;
+// exists reports whether one names an issue.
+func LintText(issue *types.Issue, prefix string, exists func(id string) bool) []TextFinding {
+	var findings []TextFinding
+	add := func(f TextFinding) {
+		f.ID = issue.ID
+		findings = append(findings, f)
+	}
+
+	for _, t := range NonTrivialTypes {
+		if issue.IssueType == t && strings.TrimSpace(issue.Description) == "" {
+			add(TextFinding{
+				Field:   "description",
+				Rule:    RuleEmptyDescription,
+				Message: fmt.Sprintf("%s has no description", issue.IssueType),
+				Fix:     fmt.Sprintf("bd update %s --description \"...\"", issue.ID),
+			})
+		}
+	}
+
+	for _, field := range textFields(issue) {
+		for _, f := range lintMarkdown(field.text, field.name == "title") {
+			f.Field = field.name
+			add(f)
+		}
+		if prefix == "" || exists == nil {
+			continue
+		}
+		for _, ref := range mentionedIDs(field.text, prefix) {
+			if ref != strings.ToLower(issue.ID) && !exists(ref) {
+				add(TextFinding{
+					Field:   field.name,
+					Rule:    RuleDeadReference,
+					Message: fmt.Sprintf("%s does not exist", ref),
+					Fix:     fmt.Sprintf("correct or remove the reference (bd edit %s)", issue.ID),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+var (
+	fencePattern       = regexp.MustCompile("^ {0,3}(```|~~~)")
+	headingNoSpace     = regexp.MustCompile(`^(#{2,6})([^#\s])`)
+	emptyLinkPattern   = regexp.MustCompile(`\[[^\]]*\]\(\s*\)`)
+	unclosedLinkTarget = regexp.MustCompile(`\]\([^)\s]*$`)
+	inlineCodePattern  = regexp.MustCompile("`[^`]*`")
+)
+
+// lintMarkdown finds unclosed code fences, headings missing their space,
+// empty or unclosed link targets and unclosed inline code. A title is a
+// single line, so only the inline checks apply to it.
+func lintMarkdown(text string, inline bool) []TextFinding {
+	var findings []TextFinding
+	openFence, fenceLine := "", 0
+	for i, line := range strings.Split(text, "\n") {
+		n := i + 1
+		if m := fencePattern.FindStringSubmatch(line); m != nil && !inline {
+			switch {
+			case openFence == "":
+				openFence, fenceLine = m[1], n
+			case m[1] == openFence:
+				openFence = ""
+			}
+			continue
+		}
+		if openFence != "" {
+			continue
+		}
+		if !inline && headingNoSpace.MatchString(line) {
+			findings = append(findings, TextFinding{
+				Line: n, Rule: RuleBrokenMarkdown, Message: "heading needs a space after the #s",
+				Fix: "add a space after the #s", AutoFix: true,
+			})
+		}
+		if strings.Count(line, "`")%2 != 0 {
+			findings = append(findings, TextFinding{
+				Line: n, Rule: RuleBrokenMarkdown, Message: "unclosed inline code (odd number of backticks)",
+				Fix: "add the missing backtick",
+			})
+		}
+		prose := inlineCodePattern.ReplaceAllString(line, "")
+		if emptyLinkPattern.MatchString(prose) {
+			findings = append(findings, TextFinding{
+				Line: n, Rule: RuleBrokenMarkdown, Message: "link has no target",
+				Fix: "fill in the link's URL or drop the link",
+			})
+		}
+		if unclosedLinkTarget.MatchString(prose) {
+			findings = append(findings, TextFinding{
+				Line: n, Rule: RuleBrokenMarkdown, Message: "link target is missing its closing )",
+				Fix: "add the closing )", AutoFix: true,
+			})
+		}
+	}
+	if openFence != "" {
+		findings = append(findings, TextFinding{
+			Line: fenceLine, Rule: RuleBrokenMarkdown, Message: "code block is never closed",
+			Fix: "add a closing " + openFence, AutoFix: true,
+		})
+	}
+	if inline {
+		for i := range findings {
+			findings[i].Line = 0
+		}
+	}
+	return findings
+}
+
+// FixMarkdown repairs the problems lintMarkdown marks AutoFix: it closes an
+// unclosed code block, adds the space a heading is missing and closes a
+// link target left open at the end of a line.
+func FixMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	openFence := ""
+	for i, line := range lines {
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			switch {
+			case openFence == "":
+				openFence = m[1]
+			case m[1] == openFence:
+				openFence = ""
+			}
+			continue
+		}
+		if openFence != "" {
+			continue
+		}
+		line = headingNoSpace.ReplaceAllString(line, "$1 $2")
+		if unclosedLinkTarget.MatchString(inlineCodePattern.ReplaceAllString(line, "")) {
+			line += ")"
+		}
+		lines[i] = line
+	}
+	fixed := strings.Join(lines, "\n")
+	if openFence != "" {
+		fixed = strings.TrimRight(fixed, "\n") + "\n" + openFence
+	}
+	return fixed
+}
+
+// mentionedIDs returns the IDs with prefix mentioned in text, lowercased,
+// in order of first mention. Words like "bd-style" are not IDs: the part
+// after the prefix must contain a digit.
+func mentionedIDs(text, prefix string) []string {
+	pattern := regexp.MustCompile(`(?i)(?:^|[^\w-])(` + regexp.QuoteMeta(prefix) + `-[0-9a-z]+(?:\.\d+)*)\b`)
+	seen := make(map[string]bool)
+	var ids []string
+	for _, m := range pattern.FindAllStringSubmatch(text, -1) {
+		id := strings.ToLower(m[1])
+		hash := strings.SplitN(strings.TrimPrefix(id, strings.ToLower(prefix)+"-"), ".", 2)[0]
+		if seen[id] || !strings.ContainsAny(hash, "0123456789") {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
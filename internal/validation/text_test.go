@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestLintText(t *testing.T) {
+	exists := func(id string) bool { return id == "bd-a1" || id == "bd-a1.2" }
+	issue := &types.Issue{
+		ID:        "bd-x9",
+		Title:     "Fix `parser",
+		IssueType: types.TypeBug,
+		Design: "See bd-a1, bd-a1.2 and bd-zz9.\n" +
+			"Not IDs: bd-style, bd-x9 (itself).\n" +
+			"##Plan\n" +
+			"[docs]() and [spec](https://example.com/spec\n" +
+			"```go\n" +
+			"##not a heading in code, nor bd-dead1\n",
+	}
+
+	type key struct{ field, rule, message string }
+	want := map[key]int{
+		{"description", RuleEmptyDescription, "bug has no description"}:                 0,
+		{"title", RuleBrokenMarkdown, "unclosed inline code (odd number of backticks)"}: 0,
+		{"design", RuleBrokenMarkdown, "heading needs a space after the #s"}:            3,
+		{"design", RuleBrokenMarkdown, "link has no target"}:                            4,
+		{"design", RuleBrokenMarkdown, "link target is missing its closing )"}:          4,
+		{"design", RuleBrokenMarkdown, "code block is never closed"}:                    5,
+		{"design", RuleDeadReference, "bd-zz9 does not exist"}:                          0,
+		{"design", RuleDeadReference, "bd-dead1 does not exist"}:                        0,
+	}
+	got := LintText(issue, "bd", exists)
+	for _, f := range got {
+		k := key{f.Field, f.Rule, f.Message}
+		line, ok := want[k]
+		if !ok {
+			t.Errorf("unexpected finding %+v", f)
+			continue
+		}
+		if f.Line != line || f.ID != "bd-x9" {
+			t.Errorf("finding %+v: want line %d", f, line)
+		}
+		delete(want, k)
+	}
+	for k := range want {
+		t.Errorf("missing finding %+v", k)
+	}
+}
+
+func TestLintTextTrivialType(t *testing.T) {
+	issue := &types.Issue{ID: "bd-1", Title: "Bump deps", IssueType: types.TypeChore}
+	if got := LintText(issue, "bd", func(string) bool { return true }); len(got) != 0 {
+		t.Errorf("LintText() = %+v, want none", got)
+	}
+}
+
+func TestFixMarkdown(t *testing.T) {
+	in := "##Plan\nSee [spec](https://example.com/spec\n```\n##code\n"
+	want := "## Plan\nSee [spec](https://example.com/spec)\n```\n##code\n```"
+	got := FixMarkdown(in)
+	if got != want {
+		t.Errorf("FixMarkdown() = %q, want %q", got, want)
+	}
+	for _, f := range lintMarkdown(got, false) {
+		t.Errorf("finding left after fix: %+v", f)
+	}
+	if again := FixMarkdown(got); again != got {
+		t.Errorf("FixMarkdown is not idempotent: %q", again)
+	}
+}
@@ -0,0 +1,141 @@
+// Package visibility holds issue visibility levels, so one database can
+// hold both shareable and confidential issues. Internal, the default, is
+// for the team; public issues may be shown outside it; private issues
+// never leave the local database: they're withheld from the committed
+// JSONL and from every audience but the local user.
+package visibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Visibility levels, from the widest audience to the narrowest.
+const (
+	Public   = "public"
+	Internal = "internal"
+	Private  = "private"
+)
+
+// ErrUnsupported is returned for storage backends without visibility levels.
+var ErrUnsupported = errors.New("visibility levels require the SQLite backend")
+
+// Store is the storage interface for visibility levels.
+type Store interface {
+	SetVisibility(ctx context.Context, issueID, level, actor string) error
+	GetVisibilityForIssues(ctx context.Context, issueIDs []string) (map[string]string, error)
+	GetVisibilityIssueIDs(ctx context.Context, level string) ([]string, error)
+}
+
+// For returns s as a visibility store.
+func For(s storage.Storage) (Store, error) {
+	vs, ok := s.(Store)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return vs, nil
+}
+
+// Parse validates a visibility level, case-insensitively. An empty level
+// is internal.
+func Parse(s string) (string, error) {
+	switch level := strings.ToLower(strings.TrimSpace(s)); level {
+	case "":
+		return Internal, nil
+	case Public, Internal, Private:
+		return level, nil
+	default:
+		return "", fmt.Errorf("invalid visibility %q (must be public, internal or private)", s)
+	}
+}
+
+// Of returns an issue's visibility level. issue.Visibility must already be
+// populated.
+func Of(issue *types.Issue) string {
+	if issue.Visibility == "" {
+		return Internal
+	}
+	return issue.Visibility
+}
+
+func rank(level string) int {
+	switch level {
+	case Public:
+		return 0
+	case Private:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Allows reports whether an audience may see issues of the given level:
+// the public sees public issues, the team (internal) sees public and
+// internal issues, and the local user (private) sees everything.
+func Allows(audience, level string) bool {
+	if level == "" {
+		level = Internal
+	}
+	return rank(level) <= rank(audience)
+}
+
+// Filter returns the issues an audience may see. Issue visibility must
+// already be populated.
+func Filter(issues []*types.Issue, audience string) []*types.Issue {
+	out := make([]*types.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if Allows(audience, Of(issue)) {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+// Populate fills in the Visibility of issues, as exports and show do.
+// Stores without visibility levels leave issues unchanged.
+func Populate(ctx context.Context, s storage.Storage, issues []*types.Issue) error {
+	vs, err := For(s)
+	if err != nil || len(issues) == 0 {
+		return nil
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	levels, err := vs.GetVisibilityForIssues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get visibility: %w", err)
+	}
+	for _, issue := range issues {
+		issue.Visibility = levels[issue.ID]
+	}
+	return nil
+}
+
+// WithholdPrivate populates issue visibility and drops private issues, for
+// exports to the committed JSONL. Withheld issues are no longer dirty:
+// there is nothing to export for them.
+func WithholdPrivate(ctx context.Context, s storage.Storage, issues []*types.Issue) ([]*types.Issue, error) {
+	if err := Populate(ctx, s, issues); err != nil {
+		return nil, err
+	}
+	kept := Filter(issues, Internal)
+	if len(kept) == len(issues) {
+		return issues, nil
+	}
+	var withheld []string
+	for _, issue := range issues {
+		if Of(issue) == Private {
+			withheld = append(withheld, issue.ID)
+		}
+	}
+	if err := s.ClearDirtyIssuesByID(ctx, withheld); err != nil {
+		return nil, fmt.Errorf("failed to clear dirty private issues: %w", err)
+	}
+	return kept, nil
+}
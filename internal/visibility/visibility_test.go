@@ -0,0 +1,31 @@
+package visibility
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParse(t *testing.T) {
+	for in, want := range map[string]string{"": Internal, "Public": Public, " private ": Private, "internal": Internal} {
+		if got, err := Parse(in); err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := Parse("secret"); err == nil {
+		t.Error("Parse(\"secret\") succeeded")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	issues := []*types.Issue{
+		{ID: "bd-1", Visibility: Public},
+		{ID: "bd-2"},
+		{ID: "bd-3", Visibility: Private},
+	}
+	for audience, want := range map[string]int{Public: 1, Internal: 2, Private: 3} {
+		if got := Filter(issues, audience); len(got) != want {
+			t.Errorf("Filter(%s) kept %d issues, want %d", audience, len(got), want)
+		}
+	}
+}
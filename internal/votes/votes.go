@@ -0,0 +1,73 @@
+// Package votes holds stakeholders' +1 and -1 votes on issues, cast with
+// bd vote, so demand can inform prioritization in shared databases. Votes
+// travel with issues through the JSONL like labels and refs.
+package votes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ErrUnsupported is returned for storage backends without votes.
+var ErrUnsupported = errors.New("votes require the SQLite backend")
+
+// Store is the storage interface for votes.
+type Store interface {
+	SetVote(ctx context.Context, issueID, voter string, value int, actor string) error
+	GetVotes(ctx context.Context, issueID string) ([]*types.Vote, error)
+	GetVotesForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Vote, error)
+}
+
+// For returns s as a vote store.
+func For(s storage.Storage) (Store, error) {
+	vs, ok := s.(Store)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return vs, nil
+}
+
+// Populate fills in the Votes of issues, as exports, show and list --sort
+// votes do. Stores that don't keep votes leave issues unchanged.
+func Populate(ctx context.Context, s storage.Storage, issues []*types.Issue) error {
+	vs, err := For(s)
+	if err != nil || len(issues) == 0 {
+		return nil
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	byIssue, err := vs.GetVotesForIssues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get votes: %w", err)
+	}
+	for _, issue := range issues {
+		issue.Votes = byIssue[issue.ID]
+	}
+	return nil
+}
+
+// ParseValue parses a vote argument: +1, -1, up, down, or 0 (retract).
+func ParseValue(s string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "+1", "1", "up", "+":
+		return 1, nil
+	case "-1", "down", "-":
+		return -1, nil
+	case "0", "none", "retract":
+		return 0, nil
+	}
+	return 0, fmt.Errorf("invalid vote %q (use +1, -1 or 0 to retract)", s)
+}
+
+// Format renders a tally like "+3 -1".
+func Format(votes []*types.Vote) string {
+	up, down := types.VoteTally(votes)
+	return fmt.Sprintf("+%d -%d", up, down)
+}
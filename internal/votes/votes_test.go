@@ -0,0 +1,32 @@
+package votes
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := map[string]int{"+1": 1, "up": 1, "-1": -1, "DOWN": -1, "0": 0, "retract": 0}
+	for in, want := range tests {
+		got, err := ParseValue(in)
+		if err != nil || got != want {
+			t.Errorf("ParseValue(%q) = %d, %v, want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "2", "+2", "yes"} {
+		if _, err := ParseValue(in); err == nil {
+			t.Errorf("ParseValue(%q) succeeded", in)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	votes := []*types.Vote{{Voter: "a", Value: 1}, {Voter: "b", Value: 1}, {Voter: "c", Value: -1}}
+	if got := Format(votes); got != "+2 -1" {
+		t.Errorf("Format = %q", got)
+	}
+	if got := Format(nil); got != "+0 -0" {
+		t.Errorf("Format(nil) = %q", got)
+	}
+}
@@ -0,0 +1,438 @@
+// bd web: board, list, issue and dependency graph views over the daemon's
+// GraphQL API, refreshed live from the /events WebSocket.
+
+const PAGE_SIZE = 500;
+const BOARD_COLUMNS = ['open', 'in_progress', 'blocked', 'deferred', 'closed'];
+const BOARD_CLOSED_LIMIT = 25;
+const GRAPH_DEPTH = 3;
+const GRAPH_MAX_NODES = 80;
+
+const STATUS_COLORS = {
+    open: 'var(--open-color)',
+    in_progress: 'var(--in-progress-color)',
+    blocked: 'var(--blocked-color)',
+    closed: 'var(--closed-color)',
+    deferred: 'var(--deferred-color)',
+};
+
+// h builds an element. Strings become text nodes, so issue content is never
+// parsed as HTML.
+function h(tag, attrs, ...children) {
+    const el = document.createElement(tag);
+    for (const [key, value] of Object.entries(attrs || {})) {
+        if (key.startsWith('on')) {
+            el.addEventListener(key.slice(2), value);
+        } else if (value !== undefined && value !== null && value !== false) {
+            el.setAttribute(key, value);
+        }
+    }
+    for (const child of children.flat()) {
+        if (child !== undefined && child !== null && child !== false) {
+            el.append(child instanceof Node ? child : document.createTextNode(String(child)));
+        }
+    }
+    return el;
+}
+
+function svg(tag, attrs, ...children) {
+    const el = document.createElementNS('http://www.w3.org/2000/svg', tag);
+    for (const [key, value] of Object.entries(attrs || {})) {
+        el.setAttribute(key, value);
+    }
+    for (const child of children) {
+        el.append(child instanceof Node ? child : document.createTextNode(String(child)));
+    }
+    return el;
+}
+
+async function gql(query, variables) {
+    const resp = await fetch('graphql', {
+        method: 'POST',
+        headers: {'Content-Type': 'application/json'},
+        body: JSON.stringify({query, variables}),
+    });
+    if (!resp.ok) {
+        throw new Error(`API request failed: ${resp.status} ${await resp.text()}`);
+    }
+    const result = await resp.json();
+    if (result.errors && result.errors.length) {
+        throw new Error(result.errors.map(e => e.message).join('; '));
+    }
+    return result.data;
+}
+
+// fetchAll pages through issues(...) until the last page.
+async function fetchAll(fields, filters) {
+    const query = `query($first: Int, $after: String, $status: String) {
+        issues(first: $first, after: $after, status: $status) {
+            nodes { ${fields} }
+            pageInfo { hasNextPage endCursor }
+        }
+    }`;
+    let issues = [];
+    let after = null;
+    for (;;) {
+        const data = await gql(query, {first: PAGE_SIZE, after, ...filters});
+        issues = issues.concat(data.issues.nodes);
+        if (!data.issues.pageInfo.hasNextPage) {
+            return issues;
+        }
+        after = data.issues.pageInfo.endCursor;
+    }
+}
+
+function statusBadge(status) {
+    return h('span', {class: `badge status-${status}`}, status.replace('_', ' '));
+}
+
+function priorityBadge(priority) {
+    return h('span', {class: `badge priority-${priority}`}, `P${priority}`);
+}
+
+function issueLink(issue) {
+    return h('a', {href: `#/issue/${encodeURIComponent(issue.id)}`}, issue.id);
+}
+
+function formatTime(t) {
+    return t ? new Date(t).toLocaleString() : '';
+}
+
+// parseHash splits "#/list?status=open" into ["list"] and its parameters.
+function parseHash() {
+    const [path, query] = location.hash.replace(/^#\/?/, '').split('?');
+    return {parts: path.split('/').filter(Boolean).map(decodeURIComponent), params: new URLSearchParams(query || '')};
+}
+
+// Views
+
+async function renderBoard() {
+    const issues = await fetchAll('id title status priority type assignee labels updatedAt closedAt');
+    const columns = new Map(BOARD_COLUMNS.map(status => [status, []]));
+    for (const issue of issues) {
+        if (!columns.has(issue.status)) {
+            columns.set(issue.status, []);
+        }
+        columns.get(issue.status).push(issue);
+    }
+    const closed = columns.get('closed');
+    closed.sort((a, b) => (b.closedAt || '').localeCompare(a.closedAt || ''));
+    const hidden = closed.length - BOARD_CLOSED_LIMIT;
+    columns.set('closed', closed.slice(0, BOARD_CLOSED_LIMIT));
+
+    return h('div', {class: 'board'}, [...columns].map(([status, list]) =>
+        h('div', {class: 'column'},
+            h('h2', null, `${status.replace('_', ' ')} (${status === 'closed' ? closed.length : list.length})`),
+            list.map(issue => h('a', {class: 'card', href: `#/issue/${encodeURIComponent(issue.id)}`},
+                h('div', null, issue.title),
+                h('div', {class: 'meta'},
+                    priorityBadge(issue.priority),
+                    h('span', null, issue.id),
+                    h('span', null, issue.type),
+                    issue.assignee && h('span', null, `@${issue.assignee}`)))),
+            status === 'closed' && hidden > 0 && h('div', {class: 'muted'}, `${hidden} older not shown`))));
+}
+
+async function renderList() {
+    const {params} = parseHash();
+    const filters = {};
+    for (const key of ['status', 'type', 'assignee', 'search']) {
+        if (params.get(key)) {
+            filters[key] = params.get(key);
+        }
+    }
+    if (params.get('priority')) {
+        filters.priority = Number(params.get('priority'));
+    }
+
+    const update = (key, value) => {
+        const next = new URLSearchParams(params);
+        if (value === '') {
+            next.delete(key);
+        } else {
+            next.set(key, value);
+        }
+        location.hash = `#/list?${next}`;
+    };
+    const select = (key, options) => h('select', {onchange: e => update(key, e.target.value)},
+        options.map(([value, label]) => h('option', {value, selected: (params.get(key) || '') === value}, label)));
+
+    const controls = h('div', {class: 'filters'},
+        h('input', {type: 'search', placeholder: 'Search', value: params.get('search') || '',
+            onchange: e => update('search', e.target.value.trim())}),
+        select('status', [['', 'Any status'], ...BOARD_COLUMNS.map(s => [s, s.replace('_', ' ')])]),
+        select('type', [['', 'Any type'], ...['bug', 'feature', 'task', 'epic', 'chore'].map(t => [t, t])]),
+        select('priority', [['', 'Any priority'], ...[0, 1, 2, 3, 4].map(p => [String(p), `P${p}`])]),
+        h('input', {type: 'text', placeholder: 'Assignee', value: params.get('assignee') || '',
+            onchange: e => update('assignee', e.target.value.trim())}));
+
+    const body = h('tbody');
+    const table = h('table', null,
+        h('thead', null, h('tr', null, ['ID', 'P', 'Type', 'Status', 'Title', 'Assignee', 'Updated'].map(c => h('th', null, c)))),
+        body);
+    const count = h('p', {class: 'muted'});
+    const more = h('button', {hidden: true});
+    const query = `query($first: Int, $after: String, $status: String, $type: String, $priority: Int, $assignee: String, $search: String) {
+        issues(first: $first, after: $after, status: $status, type: $type, priority: $priority, assignee: $assignee, search: $search) {
+            totalCount
+            nodes { id title status priority type assignee updatedAt }
+            pageInfo { hasNextPage endCursor }
+        }
+    }`;
+    let after = null;
+    const loadPage = async () => {
+        const data = await gql(query, {first: 100, after, ...filters});
+        for (const issue of data.issues.nodes) {
+            body.append(h('tr', null,
+                h('td', null, issueLink(issue)),
+                h('td', null, priorityBadge(issue.priority)),
+                h('td', null, issue.type),
+                h('td', null, statusBadge(issue.status)),
+                h('td', null, issue.title),
+                h('td', null, issue.assignee || ''),
+                h('td', {class: 'muted'}, formatTime(issue.updatedAt))));
+        }
+        count.textContent = `${data.issues.totalCount} issue(s)`;
+        after = data.issues.pageInfo.endCursor;
+        more.hidden = !data.issues.pageInfo.hasNextPage;
+    };
+    more.textContent = 'Load more';
+    more.addEventListener('click', () => loadPage().catch(showError));
+    await loadPage();
+    return h('div', null, controls, count, table, more);
+}
+
+async function renderIssue(id) {
+    const data = await gql(`query($id: ID!) {
+        issue(id: $id) {
+            id title description design acceptanceCriteria notes status priority type assignee createdBy
+            createdAt updatedAt closedAt closeReason dueAt labels
+            parent { id title status }
+            children(first: 200) { nodes { id title status } }
+            dependencies { type issue { id title status } }
+            dependents { type issue { id title status } }
+            comments(first: 200) { nodes { author text createdAt } }
+        }
+    }`, {id});
+    const issue = data.issue;
+    if (!issue) {
+        return h('p', null, `Issue ${id} not found.`);
+    }
+
+    const textSection = (title, text) => text && h('section', null, h('h3', null, title), h('div', {class: 'text'}, text));
+    const related = (title, list) => list.length > 0 && h('section', null, h('h3', null, title),
+        h('ul', {class: 'links'}, list.map(({issue: other, type}) => h('li', null,
+            statusBadge(other.status), ' ', issueLink(other), ' ', other.title, type ? h('span', {class: 'muted'}, ` (${type})`) : ''))));
+    const field = (label, value) => value && [h('dt', null, label), h('dd', null, value)];
+
+    return h('div', {class: 'detail'},
+        h('div', null,
+            h('h2', null, `${issue.id}: ${issue.title}`),
+            textSection('Description', issue.description),
+            textSection('Design', issue.design),
+            textSection('Acceptance criteria', issue.acceptanceCriteria),
+            textSection('Notes', issue.notes),
+            related('Children', issue.children.nodes.map(c => ({issue: c}))),
+            related('Depends on', issue.dependencies),
+            related('Needed by', issue.dependents),
+            h('section', null, h('h3', null, `Comments (${issue.comments.nodes.length})`),
+                issue.comments.nodes.map(c => h('div', {class: 'comment'},
+                    h('div', {class: 'muted'}, `${c.author} · ${formatTime(c.createdAt)}`),
+                    h('div', {class: 'text'}, c.text))))),
+        h('div', null,
+            h('section', null,
+                h('dl', {class: 'fields'},
+                    field('Status', statusBadge(issue.status)),
+                    field('Priority', priorityBadge(issue.priority)),
+                    field('Type', issue.type),
+                    field('Assignee', issue.assignee),
+                    field('Parent', issue.parent && h('span', null, issueLink(issue.parent), ' ', issue.parent.title)),
+                    field('Labels', issue.labels.join(', ')),
+                    field('Due', formatTime(issue.dueAt)),
+                    field('Created', `${formatTime(issue.createdAt)} by ${issue.createdBy}`),
+                    field('Updated', formatTime(issue.updatedAt)),
+                    field('Closed', issue.closedAt && `${formatTime(issue.closedAt)}${issue.closeReason ? ` (${issue.closeReason})` : ''}`)),
+                h('a', {href: `#/graph/${encodeURIComponent(issue.id)}`}, 'Dependency graph'))));
+}
+
+// graphAround collects the issues within GRAPH_DEPTH dependency hops of id.
+async function graphAround(id) {
+    const query = `query($id: ID!) {
+        issue(id: $id) { id title status dependencies { type issue { id } } dependents { type issue { id } } }
+    }`;
+    const nodes = new Map();
+    let frontier = [id];
+    for (let depth = 0; depth <= GRAPH_DEPTH && frontier.length > 0 && nodes.size < GRAPH_MAX_NODES; depth++) {
+        const fetched = await Promise.all(frontier.map(next => gql(query, {id: next})));
+        frontier = [];
+        for (const {issue} of fetched) {
+            if (!issue || nodes.has(issue.id)) {
+                continue;
+            }
+            nodes.set(issue.id, issue);
+            for (const dep of [...issue.dependencies, ...issue.dependents]) {
+                if (!nodes.has(dep.issue.id) && !frontier.includes(dep.issue.id)) {
+                    frontier.push(dep.issue.id);
+                }
+            }
+        }
+    }
+    return [...nodes.values()];
+}
+
+async function renderGraph(id) {
+    const issues = id
+        ? await graphAround(id)
+        : (await fetchAll('id title status dependencies { type issue { id } }')).filter(i => i.status !== 'closed');
+    const byID = new Map(issues.map(i => [i.id, i]));
+    const edges = [];
+    for (const issue of issues) {
+        for (const dep of issue.dependencies) {
+            if (byID.has(dep.issue.id)) {
+                edges.push({from: dep.issue.id, to: issue.id, type: dep.type});
+            }
+        }
+    }
+    const linked = new Set(edges.flatMap(e => [e.from, e.to]));
+    const shown = id ? issues : issues.filter(i => linked.has(i.id));
+    if (shown.length === 0) {
+        return h('p', {class: 'muted'}, 'No open issues have dependencies.');
+    }
+
+    // Layer each issue after everything it depends on (longest path)
+    const layer = new Map();
+    const visiting = new Set();
+    const layerOf = issueID => {
+        if (layer.has(issueID)) {
+            return layer.get(issueID);
+        }
+        if (visiting.has(issueID)) {
+            return 0; // Dependency cycle
+        }
+        visiting.add(issueID);
+        let l = 0;
+        for (const e of edges) {
+            if (e.to === issueID) {
+                l = Math.max(l, layerOf(e.from) + 1);
+            }
+        }
+        visiting.delete(issueID);
+        layer.set(issueID, l);
+        return l;
+    };
+    const columns = [];
+    for (const issue of [...shown].sort((a, b) => a.id.localeCompare(b.id))) {
+        const l = layerOf(issue.id);
+        (columns[l] = columns[l] || []).push(issue);
+    }
+
+    const nodeW = 200, nodeH = 44, gapX = 70, gapY = 14, pad = 20;
+    const pos = new Map();
+    columns.forEach((col, x) => (col || []).forEach((issue, y) => {
+        pos.set(issue.id, {x: pad + x * (nodeW + gapX), y: pad + y * (nodeH + gapY)});
+    }));
+    const width = pad * 2 + columns.length * (nodeW + gapX) - gapX;
+    const height = pad * 2 + Math.max(...columns.map(c => (c || []).length)) * (nodeH + gapY) - gapY;
+
+    const root = svg('svg', {width, height, viewBox: `0 0 ${width} ${height}`});
+    root.append(svg('defs', null, svg('marker', {id: 'arrow', viewBox: '0 0 10 10', refX: 10, refY: 5, markerWidth: 6, markerHeight: 6, orient: 'auto'},
+        svg('path', {d: 'M0,0 L10,5 L0,10 z', fill: '#97a0af'}))));
+    for (const e of edges) {
+        const a = pos.get(e.from), b = pos.get(e.to);
+        if (!a || !b) {
+            continue;
+        }
+        const x1 = a.x + nodeW, y1 = a.y + nodeH / 2, x2 = b.x, y2 = b.y + nodeH / 2;
+        const mid = (x1 + x2) / 2;
+        root.append(svg('path', {class: `edge ${e.type}`, d: `M${x1},${y1} C${mid},${y1} ${mid},${y2} ${x2},${y2}`, 'marker-end': 'url(#arrow)'},
+            svg('title', null, e.type)));
+    }
+    for (const issue of shown) {
+        const p = pos.get(issue.id);
+        const title = issue.title.length > 28 ? issue.title.slice(0, 27) + '…' : issue.title;
+        const node = svg('g', {class: `node${issue.id === id ? ' focus' : ''}`, transform: `translate(${p.x},${p.y})`},
+            svg('rect', {width: nodeW, height: nodeH, rx: 4, stroke: STATUS_COLORS[issue.status] || 'var(--border-color)'}),
+            svg('text', {x: 8, y: 17}, issue.id),
+            svg('text', {x: 8, y: 34}, title),
+            svg('title', null, `${issue.id}: ${issue.title} (${issue.status})`));
+        node.addEventListener('click', () => { location.hash = `#/issue/${encodeURIComponent(issue.id)}`; });
+        root.append(node);
+    }
+    return h('div', null,
+        h('p', {class: 'muted'}, id
+            ? `Issues within ${GRAPH_DEPTH} dependency hops of ${id}; arrows point from a dependency to the issue needing it.`
+            : 'Open issues with dependencies; arrows point from a dependency to the issue needing it. Dashed lines are parent-child links.'),
+        h('div', {class: 'graph'}, root));
+}
+
+// Rendering and live updates
+
+function showError(err) {
+    const el = document.getElementById('error');
+    el.textContent = err ? String(err.message || err) : '';
+    el.hidden = !err;
+}
+
+let rendering = 0;
+
+async function render() {
+    const {parts} = parseHash();
+    const view = parts[0] || 'board';
+    for (const link of document.querySelectorAll('nav a')) {
+        link.classList.toggle('active', link.dataset.view === view);
+    }
+    const token = ++rendering;
+    try {
+        let content;
+        switch (view) {
+        case 'list':
+            content = await renderList();
+            break;
+        case 'issue':
+            content = await renderIssue(parts[1]);
+            break;
+        case 'graph':
+            content = await renderGraph(parts[1]);
+            break;
+        default:
+            content = await renderBoard();
+        }
+        if (token === rendering) {
+            document.getElementById('view').replaceChildren(content);
+            showError(null);
+        }
+    } catch (err) {
+        if (token === rendering) {
+            showError(err);
+        }
+    }
+}
+
+function setLive(live) {
+    document.getElementById('connection').classList.toggle('live', live);
+    document.getElementById('connection-text').textContent = live ? 'Live' : 'Reconnecting…';
+}
+
+function connectEvents() {
+    const protocol = location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const ws = new WebSocket(`${protocol}//${location.host}${location.pathname.replace(/[^/]*$/, '')}events`);
+    let pending = null;
+    ws.onopen = () => setLive(true);
+    ws.onmessage = () => {
+        // Coalesce bursts of mutations into one refresh; inputs being edited
+        // in the list view would lose focus, so skip it while one has focus
+        clearTimeout(pending);
+        pending = setTimeout(() => {
+            if (!document.activeElement || !['INPUT', 'SELECT'].includes(document.activeElement.tagName)) {
+                render();
+            }
+        }, 300);
+    };
+    ws.onclose = () => {
+        setLive(false);
+        setTimeout(connectEvents, 5000);
+    };
+}
+
+window.addEventListener('hashchange', render);
+render();
+connectEvents();
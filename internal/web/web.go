@@ -0,0 +1,51 @@
+// Package web is the browser UI served by bd web: board, list, issue and
+// dependency graph views over the daemon's HTTP API (internal/api). The
+// API is proxied under the UI's own origin, so the pages need no
+// cross-origin access and the API token never reaches the browser.
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+//go:embed static
+var static embed.FS
+
+// Options configures the UI handler.
+type Options struct {
+	API   *url.URL // Base URL of the daemon's HTTP API
+	Token string   // Sent to the API as a bearer token; empty for none
+}
+
+// Handler serves the UI, proxying /graphql and /events to the API.
+func Handler(opts Options) http.Handler {
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // The embedded directory always exists
+	}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(opts.API)
+			// Keep the browser's host, which the API's WebSocket origin
+			// check compares against
+			r.Out.Host = r.In.Host
+			q := r.Out.URL.Query()
+			q.Del("token")
+			r.Out.URL.RawQuery = q.Encode()
+			r.Out.Header.Del("Authorization")
+			if opts.Token != "" {
+				r.Out.Header.Set("Authorization", "Bearer "+opts.Token)
+			}
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/graphql", proxy)
+	mux.Handle("/events", proxy)
+	mux.Handle("/", http.FileServerFS(assets))
+	return mux
+}
@@ -0,0 +1,72 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHandlerAssets(t *testing.T) {
+	api, _ := url.Parse("http://127.0.0.1:1")
+	srv := httptest.NewServer(Handler(Options{API: api}))
+	defer srv.Close()
+
+	for path, want := range map[string]string{
+		"/":          "<title>bd</title>",
+		"/app.js":    "function gql(",
+		"/style.css": ".board",
+	} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), want) {
+			t.Errorf("GET %s = %d, want a body containing %q", path, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestHandlerProxy(t *testing.T) {
+	var got *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		_, _ = io.WriteString(w, `{"data":{}}`)
+	}))
+	defer backend.Close()
+	api, _ := url.Parse(backend.URL)
+
+	for _, tt := range []struct {
+		token, wantAuth string
+	}{
+		{"secret", "Bearer secret"},
+		{"", ""}, // --public: the client's own credentials are dropped too
+	} {
+		srv := httptest.NewServer(Handler(Options{API: api, Token: tt.token}))
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/graphql?token=guess", strings.NewReader(`{"query":"{ stats { total } }"}`))
+		req.Header.Set("Authorization", "Bearer guess")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		srv.Close()
+
+		if got == nil || got.URL.Path != "/graphql" {
+			t.Fatalf("request not proxied: %v", got)
+		}
+		if auth := got.Header.Get("Authorization"); auth != tt.wantAuth {
+			t.Errorf("token %q: Authorization = %q, want %q", tt.token, auth, tt.wantAuth)
+		}
+		if got.URL.Query().Has("token") {
+			t.Errorf("token query parameter passed through")
+		}
+		if got.Host != strings.TrimPrefix(srv.URL, "http://") {
+			t.Errorf("Host = %q, want the UI's host", got.Host)
+		}
+	}
+}